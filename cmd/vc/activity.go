@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"math"
 	"os"
 
 	"github.com/fatih/color"
@@ -25,7 +26,7 @@ Shows events from the agent_events table including:
 - Errors and warnings
 - Watchdog alerts and interventions
 
-Use filters to narrow down events by issue, type, or severity.
+Use filters to narrow down events by issue, type, severity, executor, or agent.
 
 Examples:
   vc activity                              # Show last 20 events
@@ -34,12 +35,21 @@ Examples:
   vc activity --type error                 # Show only error events
   vc activity --type context_usage         # Show context usage events
   vc activity --severity warning           # Show warnings and above
-  vc activity --type git_operation -n 10   # Show last 10 git operations`,
+  vc activity --executor <instance-id>     # Show events from one executor
+  vc activity --agent <agent-id>           # Show events from one agent
+  vc activity --type git_operation -n 10   # Show last 10 git operations
+  vc activity --type sandbox_cleanup_failed # Show failed sandbox cleanups
+  vc activity --page-size 50               # Page back through history...
+  vc activity --page-size 50 --cursor 1234 # ...using the cursor printed at the end`,
 	Run: func(cmd *cobra.Command, args []string) {
 		limit, _ := cmd.Flags().GetInt("limit")
 		issueID, _ := cmd.Flags().GetString("issue")
 		eventType, _ := cmd.Flags().GetString("type")
 		severity, _ := cmd.Flags().GetString("severity")
+		executorID, _ := cmd.Flags().GetString("executor")
+		agentID, _ := cmd.Flags().GetString("agent")
+		cursor, _ := cmd.Flags().GetInt64("cursor")
+		pageSize, _ := cmd.Flags().GetInt("page-size")
 
 		ctx := context.Background()
 
@@ -56,15 +66,34 @@ Examples:
 		if severity != "" {
 			filter.Severity = events.EventSeverity(severity)
 		}
+		filter.ExecutorID = executorID
+		filter.AgentID = agentID
 
 		// Fetch events
 		var eventList []*events.AgentEvent
 		var err error
 
+		// Paging walks back by storage ID so pages never overlap or skip
+		// events, even while new ones arrive
+		paginate := pageSize > 0 || cursor > 0
+		if paginate {
+			if pageSize <= 0 {
+				pageSize = limit
+			}
+			filter.Limit = pageSize
+			filter.BeforeID = cursor
+			if cursor <= 0 {
+				filter.BeforeID = math.MaxInt64
+			}
+		}
+
 		// Use optimized queries when possible
-		if issueID != "" && eventType == "" && severity == "" {
+		noOtherFilters := eventType == "" && severity == "" && executorID == "" && agentID == ""
+		if paginate {
+			eventList, err = store.GetAgentEvents(ctx, filter)
+		} else if issueID != "" && noOtherFilters {
 			eventList, err = store.GetAgentEventsByIssue(ctx, issueID)
-		} else if issueID == "" && eventType == "" && severity == "" {
+		} else if issueID == "" && noOtherFilters {
 			eventList, err = store.GetRecentAgentEvents(ctx, limit)
 		} else {
 			eventList, err = store.GetAgentEvents(ctx, filter)
@@ -90,6 +119,14 @@ Examples:
 			displayActivityEvent(eventList[i])
 		}
 
+		if paginate {
+			if next := nextActivityCursor(eventList, pageSize); next > 0 {
+				fmt.Printf("\nNext cursor: %d (use --cursor %d for older events)\n", next, next)
+			} else {
+				fmt.Printf("\nNo older events\n")
+			}
+		}
+
 		fmt.Println()
 	},
 }
@@ -99,9 +136,22 @@ func init() {
 	activityCmd.Flags().StringP("issue", "i", "", "Filter events by issue ID")
 	activityCmd.Flags().StringP("type", "t", "", "Filter by event type (e.g., error, git_operation, test_run)")
 	activityCmd.Flags().StringP("severity", "s", "", "Filter by severity (info, warning, error, critical)")
+	activityCmd.Flags().String("executor", "", "Filter by executor instance ID")
+	activityCmd.Flags().String("agent", "", "Filter by agent ID")
+	activityCmd.Flags().Int64("cursor", 0, "Show events older than this cursor (printed at the end of each page)")
+	activityCmd.Flags().Int("page-size", 0, "Page through events this many at a time (default: --limit when --cursor is set)")
 	rootCmd.AddCommand(activityCmd)
 }
 
+// nextActivityCursor returns the cursor for the page after eventList (ordered
+// newest first), or 0 if eventList was the last page.
+func nextActivityCursor(eventList []*events.AgentEvent, pageSize int) int64 {
+	if len(eventList) == 0 || len(eventList) < pageSize {
+		return 0
+	}
+	return eventList[len(eventList)-1].Cursor()
+}
+
 // displayActivityEvent formats and prints a single event with color
 func displayActivityEvent(event *events.AgentEvent) {
 	// Color coding by severity
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/steveyegge/vc/internal/events"
+)
+
+func TestNextActivityCursor(t *testing.T) {
+	page := []*events.AgentEvent{{ID: "42"}, {ID: "17"}, {ID: "9"}}
+
+	if got := nextActivityCursor(page, 3); got != 9 {
+		t.Errorf("nextActivityCursor(full page) = %d, want 9", got)
+	}
+	if got := nextActivityCursor(page, 5); got != 0 {
+		t.Errorf("nextActivityCursor(short page) = %d, want 0", got)
+	}
+	if got := nextActivityCursor(nil, 5); got != 0 {
+		t.Errorf("nextActivityCursor(empty) = %d, want 0", got)
+	}
+}
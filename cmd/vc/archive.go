@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+var archiveCmd = &cobra.Command{
+	Use:   "archive [id...]",
+	Short: "Archive issues (hide them from queries, keeping their history)",
+	Long: `Archive one or more issues.
+
+Archived issues are not deleted: their history, comments and dependencies are
+kept, and 'vc show' still displays them. They are left out of 'vc list',
+'vc ready', 'vc stats' and the dependency views. Use 'vc list --archived' to
+see them and 'vc unarchive' to bring them back.
+
+Archiving an issue that an open issue depends on prints a warning: the
+dependent stays blocked, but its blocker is no longer visible.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+		green := color.New(color.FgGreen).SprintFunc()
+		yellow := color.New(color.FgYellow).SprintFunc()
+
+		failed := false
+		for _, id := range args {
+			dependents, err := openDependents(ctx, store, id)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error checking dependents of %s: %v\n", id, err)
+				failed = true
+				continue
+			}
+
+			if err := store.ArchiveIssue(ctx, id, actor); err != nil {
+				fmt.Fprintf(os.Stderr, "Error archiving %s: %v\n", id, err)
+				failed = true
+				continue
+			}
+			fmt.Printf("%s Archived %s\n", green("✓"), id)
+
+			for _, dependent := range dependents {
+				fmt.Printf("  %s %s (%s) depends on %s and will stay blocked on an archived issue\n",
+					yellow("⚠"), dependent.ID, dependent.Status, id)
+			}
+		}
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+var unarchiveCmd = &cobra.Command{
+	Use:   "unarchive [id...]",
+	Short: "Restore archived issues",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+		green := color.New(color.FgGreen).SprintFunc()
+
+		failed := false
+		for _, id := range args {
+			if err := store.UnarchiveIssue(ctx, id, actor); err != nil {
+				fmt.Fprintf(os.Stderr, "Error unarchiving %s: %v\n", id, err)
+				failed = true
+				continue
+			}
+			fmt.Printf("%s Unarchived %s\n", green("✓"), id)
+		}
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+// openDependents returns the visible, not yet closed issues that id blocks
+// (related and discovered-from links don't hold anything up)
+func openDependents(ctx context.Context, s storage.Storage, id string) ([]*types.Issue, error) {
+	dependents, err := s.GetDependents(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var open []*types.Issue
+	for _, dependent := range dependents {
+		if dependent.Status == types.StatusClosed {
+			continue
+		}
+		records, err := s.GetDependencyRecords(ctx, dependent.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range records {
+			if record.DependsOnID == id && record.Type.IsBlocking() {
+				open = append(open, dependent)
+				break
+			}
+		}
+	}
+	return open, nil
+}
+
+func init() {
+	rootCmd.AddCommand(archiveCmd)
+	rootCmd.AddCommand(unarchiveCmd)
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// printAssessment prints an issue's latest AI assessment for
+// 'vc show --assessment', checking off the steps agents reported done
+func printAssessment(w io.Writer, record *types.AssessmentRecord) {
+	if record == nil {
+		fmt.Fprintf(w, "\nAssessment: none\n")
+		return
+	}
+	a := record.Assessment
+	attempt := "unrecorded attempt"
+	if record.Attempt > 0 {
+		attempt = fmt.Sprintf("attempt #%d", record.Attempt)
+	}
+	fmt.Fprintf(w, "\nAssessment (%s, %s):\n", attempt, record.CreatedAt.Format("2006-01-02 15:04"))
+	fmt.Fprintf(w, "  Strategy: %s\n", a.Strategy)
+	fmt.Fprintf(w, "  Confidence: %.0f%%\n", a.Confidence*100)
+	if len(a.Steps) > 0 {
+		fmt.Fprintf(w, "  Steps (%d of %d done):\n", len(record.CompletedSteps), len(a.Steps))
+		for i, step := range a.Steps {
+			mark := " "
+			if record.StepCompleted(i) {
+				mark = "x"
+			}
+			fmt.Fprintf(w, "    [%s] %d. %s\n", mark, i+1, step)
+		}
+	}
+	if len(a.Risks) > 0 {
+		fmt.Fprintf(w, "  Risks:\n")
+		for _, risk := range a.Risks {
+			fmt.Fprintf(w, "    - %s\n", risk)
+		}
+	}
+	if a.Reasoning != "" {
+		fmt.Fprintf(w, "  Reasoning: %s\n", a.Reasoning)
+	}
+}
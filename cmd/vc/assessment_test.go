@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestPrintAssessment(t *testing.T) {
+	var out bytes.Buffer
+	printAssessment(&out, &types.AssessmentRecord{
+		IssueID: "vc-1",
+		Attempt: 2,
+		Assessment: types.Assessment{
+			Strategy:   "Fix the parser",
+			Steps:      []string{"Add a failing test", "Fix the bug"},
+			Risks:      []string{"Breaks callers"},
+			Confidence: 0.75,
+		},
+		CompletedSteps: []int{0},
+		CreatedAt:      time.Date(2025, 1, 2, 3, 4, 0, 0, time.UTC),
+	})
+	for _, want := range []string{
+		"Assessment (attempt #2, 2025-01-02 03:04)",
+		"Strategy: Fix the parser",
+		"Confidence: 75%",
+		"Steps (1 of 2 done)",
+		"[x] 1. Add a failing test",
+		"[ ] 2. Fix the bug",
+		"- Breaks callers",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q in:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	printAssessment(&out, nil)
+	if !strings.Contains(out.String(), "Assessment: none") {
+		t.Errorf("Unexpected output without an assessment: %q", out.String())
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/storage/beads"
+)
+
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Back up the database (safe while executors are running)",
+	Long: `Write a consistent copy of the database using SQLite's online backup API.
+
+The backup is taken while executors keep running and writing. Each backup is
+a timestamped file plus a manifest (<file>.manifest.json) recording the
+schema version and issue and event counts, which 'vc restore' checks.
+
+By default backups go to a backups directory next to the database
+(.beads/backups). --out names the backup file, or a directory to put the
+timestamped file in.
+
+Examples:
+  vc backup                          # .beads/backups/vc-backup-<time>.db
+  vc backup --out /mnt/backups       # /mnt/backups/vc-backup-<time>.db
+  vc backup --out before-upgrade.db  # exactly this file`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		out, _ := cmd.Flags().GetString("out")
+
+		vcStore, ok := store.(*beads.VCStorage)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: storage backend does not support backups\n")
+			os.Exit(1)
+		}
+
+		backupPath := backupDestination(out, dbPath, time.Now())
+		manifest, err := vcStore.Backup(context.Background(), backupPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Backed up database to %s\n", green("✓"), backupPath)
+		printBackupManifest(os.Stdout, manifest)
+	},
+}
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore <file>",
+	Short: "Replace the database with a backup",
+	Long: `Replace the database with a backup made by 'vc backup'.
+
+The backup is checked against its manifest (integrity, schema version and
+counts) first. Restore refuses to run while an executor is heartbeating
+against the database; stop executors and other vc processes before
+restoring. The backup is swapped in atomically and the replaced database is
+kept as <db>.pre-restore.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		// The root command doesn't open the database for restore
+		choice, err := resolveDatabase()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		target := choice.Path
+
+		manifest, err := beads.RestoreBackup(context.Background(), args[0], target)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: restore failed: %v\n", err)
+			os.Exit(1)
+		}
+
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Restored %s from %s\n", green("✓"), target, args[0])
+		printBackupManifest(os.Stdout, manifest)
+		fmt.Printf("  Previous database kept as %s.pre-restore\n", target)
+	},
+}
+
+// backupDestination resolves --out: empty means the default backup
+// directory, an existing directory gets a timestamped file name, anything
+// else is the backup file itself
+func backupDestination(out, dbPath string, now time.Time) string {
+	if out == "" {
+		return filepath.Join(beads.DefaultBackupDir(dbPath), beads.BackupFileName(now))
+	}
+	if info, err := os.Stat(out); err == nil && info.IsDir() {
+		return filepath.Join(out, beads.BackupFileName(now))
+	}
+	return out
+}
+
+// printBackupManifest prints what a backup contains
+func printBackupManifest(w io.Writer, manifest *beads.BackupManifest) {
+	fmt.Fprintf(w, "  Taken:          %s\n", manifest.CreatedAt.Local().Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(w, "  Schema version: %d\n", manifest.SchemaVersion)
+	fmt.Fprintf(w, "  Issues:         %d\n", manifest.Issues)
+	fmt.Fprintf(w, "  Agent events:   %d\n", manifest.AgentEvents)
+	fmt.Fprintf(w, "  Size:           %d bytes\n", manifest.SizeBytes)
+}
+
+func init() {
+	backupCmd.Flags().StringP("out", "o", "", "Backup file or directory (default: backups/ next to the database)")
+	rootCmd.AddCommand(backupCmd)
+	rootCmd.AddCommand(restoreCmd)
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBackupDestination(t *testing.T) {
+	now := time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC)
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, ".beads", "vc.db")
+
+	tests := []struct {
+		name string
+		out  string
+		want string
+	}{
+		{"default directory", "", filepath.Join(dir, ".beads", "backups", "vc-backup-20250304T050607Z.db")},
+		{"existing directory", dir, filepath.Join(dir, "vc-backup-20250304T050607Z.db")},
+		{"file", filepath.Join(dir, "before-upgrade.db"), filepath.Join(dir, "before-upgrade.db")},
+	}
+	for _, tt := range tests {
+		if got := backupDestination(tt.out, dbPath, now); got != tt.want {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,306 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+var blameCmd = &cobra.Command{
+	Use:   "blame <path>",
+	Short: "List the issues whose work touched a file",
+	Long: `List the issues whose work recently changed a file, newest first.
+
+Issues are found by the commits that touched the path (git log -- <path>):
+an issue is listed for each commit recorded as its closing or merge commit
+(see 'vc show') or naming it in a Closes: trailer. Each entry shows the
+commit, and the executor, attempt and agent that made it when the
+execution history has them.
+
+Executions from before closing commits were recorded don't have one. For
+those, the agent events are searched for edits of the path, and the issues
+found are listed as approximate (marked ~) with the time of their latest
+edit.`,
+	Example: `  vc blame internal/executor/agent.go
+  vc blame internal/executor/agent.go --since 30d
+  vc blame cmd/vc/main.go --json`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		sinceStr, _ := cmd.Flags().GetString("since")
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		var since time.Time
+		if sinceStr != "" {
+			window, err := parseSince(sinceStr)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: invalid --since value: %v\n", err)
+				os.Exit(1)
+			}
+			since = time.Now().Add(-window)
+		}
+		root, err := storage.GetProjectRoot(dbPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		path, err := projectPath(root, args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		entries, err := blamePath(context.Background(), store, root, path, since)
+		if err == nil {
+			if asJSON {
+				err = writeJSON(os.Stdout, entries)
+			} else if len(entries) == 0 {
+				fmt.Printf("No issues touched %s\n", path)
+			} else {
+				err = writeBlame(os.Stdout, entries)
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// Where a blame entry was found
+const (
+	blameSourceCommit = "commit" // A commit that touched the path
+	blameSourceEvents = "events" // Agent events editing the path (approximate)
+)
+
+// blameEntry is one issue that touched a path, for vc blame
+type blameEntry struct {
+	IssueID  string       `json:"issue_id"`
+	Title    string       `json:"title"`
+	Status   types.Status `json:"status"`
+	Date     time.Time    `json:"date"`               // The commit's author date, or the latest edit in the agent events
+	Source   string       `json:"source"`             // blameSourceCommit or blameSourceEvents
+	Commit   string       `json:"commit,omitempty"`   // Only for blameSourceCommit
+	Executor string       `json:"executor,omitempty"` // Executor instance ID, if known
+	Attempt  int          `json:"attempt,omitempty"`  // Execution attempt that made the commit, if known
+	Agent    string       `json:"agent,omitempty"`    // Agent type, e.g. amp, if known
+	AgentID  string       `json:"agent_id,omitempty"` // Only for blameSourceEvents
+}
+
+// projectPath returns path (absolute or relative to the working directory)
+// relative to the project root, in git's slash-separated form
+func projectPath(root, path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is outside the project (%s)", path, root)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// blamePath lists the issues that touched path (relative to the project
+// root) since the given time (zero for all history), newest first: those
+// closed by commits touching it and, of the others, those whose agent
+// events edited it
+func blamePath(ctx context.Context, s storage.Storage, root, path string, since time.Time) ([]*blameEntry, error) {
+	var args []string
+	if !since.IsZero() {
+		args = append(args, "--since="+since.Format(time.RFC3339))
+	}
+	commits, err := gitLog(ctx, root, append(args, "--", path)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*blameEntry
+	found := make(map[string]bool)
+	for _, commit := range commits {
+		if commit.Date.Before(since) {
+			continue // git log --since goes by the commit date, blame by the author date
+		}
+		ids, err := issuesClosedBy(ctx, s, commit)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			entry, err := commitBlame(ctx, s, id, commit)
+			if err != nil {
+				return nil, err
+			}
+			if entry != nil {
+				entries = append(entries, entry)
+				found[id] = true
+			}
+		}
+	}
+
+	approximate, err := eventBlame(ctx, s, path, since, found)
+	if err != nil {
+		return nil, err
+	}
+	entries = append(entries, approximate...)
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].Date.Equal(entries[j].Date) {
+			return entries[i].Date.After(entries[j].Date)
+		}
+		return entries[i].IssueID < entries[j].IssueID
+	})
+	return entries, nil
+}
+
+// commitBlame describes the issue closed by a commit, nil if there is no
+// such issue (a Closes: trailer of another tracker)
+func commitBlame(ctx context.Context, s storage.Storage, issueID string, commit loggedCommit) (*blameEntry, error) {
+	issue, err := s.GetIssue(ctx, issueID)
+	if err != nil || issue == nil {
+		return nil, err
+	}
+	entry := &blameEntry{
+		IssueID: issue.ID,
+		Title:   issue.Title,
+		Status:  issue.Status,
+		Date:    commit.Date,
+		Source:  blameSourceCommit,
+		Commit:  commit.Hash,
+	}
+	history, err := s.GetExecutionHistory(ctx, issue.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get execution history of %s: %w", issue.ID, err)
+	}
+	for _, attempt := range history {
+		if attempt.CommitHash != commit.Hash && attempt.MergeCommit != commit.Hash {
+			continue
+		}
+		entry.Executor = attempt.ExecutorInstanceID
+		entry.Attempt = attempt.AttemptNumber
+		var before time.Time
+		if attempt.CompletedAt != nil {
+			before = *attempt.CompletedAt
+		}
+		entry.Agent = agentTypeAt(ctx, s, issue.ID, attempt.ExecutorInstanceID, before)
+		break
+	}
+	return entry, nil
+}
+
+// eventBlame lists the issues, other than those in skip, whose agent events
+// since the given time edited path: file_modified events and edit or write
+// tool uses, whose paths may be absolute (in a sandbox) or relative. Each
+// issue is dated by its latest edit.
+func eventBlame(ctx context.Context, s storage.Storage, path string, since time.Time, skip map[string]bool) ([]*blameEntry, error) {
+	matches, err := s.GetAgentEvents(ctx, events.EventFilter{AfterTime: since, MessageContains: path})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search agent events: %w", err)
+	}
+	var entries []*blameEntry
+	seen := make(map[string]bool)
+	for _, event := range matches { // Newest first
+		if event.IssueID == "" || skip[event.IssueID] || seen[event.IssueID] || !editsPath(event, path) {
+			continue
+		}
+		seen[event.IssueID] = true
+		issue, err := s.GetIssue(ctx, event.IssueID)
+		if err != nil {
+			return nil, err
+		}
+		if issue == nil {
+			continue
+		}
+		entries = append(entries, &blameEntry{
+			IssueID:  issue.ID,
+			Title:    issue.Title,
+			Status:   issue.Status,
+			Date:     event.Timestamp,
+			Source:   blameSourceEvents,
+			Executor: event.ExecutorID,
+			Agent:    agentTypeAt(ctx, s, issue.ID, event.ExecutorID, event.Timestamp),
+			AgentID:  event.AgentID,
+		})
+	}
+	return entries, nil
+}
+
+// editsPath reports whether an agent event edited path
+func editsPath(event *events.AgentEvent, path string) bool {
+	var edited string
+	switch event.Type {
+	case events.EventTypeFileModified:
+		if data, err := event.GetFileModifiedData(); err == nil {
+			edited = data.FilePath
+		}
+	case events.EventTypeAgentToolUse:
+		if data, err := event.GetAgentToolUseData(); err == nil && (data.ToolName == "edit" || data.ToolName == "write") {
+			edited = data.TargetFile
+		}
+	}
+	edited = filepath.ToSlash(edited)
+	return edited != "" && (edited == path || strings.HasSuffix(edited, "/"+path))
+}
+
+// agentTypeAt returns the type of the last agent the executor spawned for
+// the issue before the given time (zero for now), "" if unknown
+func agentTypeAt(ctx context.Context, s storage.Storage, issueID, executorID string, before time.Time) string {
+	spawned, err := s.GetAgentEvents(ctx, events.EventFilter{
+		IssueID:    issueID,
+		ExecutorID: executorID,
+		Type:       events.EventTypeAgentSpawned,
+		BeforeTime: before,
+	})
+	if err != nil {
+		return ""
+	}
+	for _, event := range spawned { // Newest first
+		if agentType, ok := event.Data["agent_type"]; ok && event.Data["success"] == true {
+			return fmt.Sprint(agentType)
+		}
+	}
+	return ""
+}
+
+// writeBlame prints vc blame entries, two lines each
+func writeBlame(w io.Writer, entries []*blameEntry) error {
+	for _, entry := range entries {
+		if _, err := fmt.Fprintf(w, "%s  %s  [%s] %s\n", entry.Date.Local().Format("2006-01-02 15:04"),
+			entry.IssueID, entry.Status, entry.Title); err != nil {
+			return err
+		}
+		var by []string
+		if entry.Source == blameSourceCommit {
+			by = append(by, "commit "+shortCommit(entry.Commit))
+		} else {
+			by = append(by, "~ agent events")
+		}
+		if entry.Executor != "" {
+			executor := "executor " + types.ExecutorShortID(entry.Executor)
+			if entry.Attempt > 0 {
+				executor += fmt.Sprintf(" attempt #%d", entry.Attempt)
+			}
+			by = append(by, executor)
+		}
+		if entry.Agent != "" {
+			by = append(by, "agent "+entry.Agent)
+		}
+		if _, err := fmt.Fprintf(w, "    %s\n", strings.Join(by, ", ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func init() {
+	blameCmd.Flags().String("since", "", "Only consider changes newer than this (e.g. 30d, 72h; default: all history)")
+	blameCmd.Flags().Bool("json", false, "Print the entries as JSON")
+	rootCmd.AddCommand(blameCmd)
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage/storagetest"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestBlamePath(t *testing.T) {
+	ctx := context.Background()
+	repo := t.TempDir()
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=Test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repo
+		output, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("git %v: %v", args, err)
+		}
+		return strings.TrimSpace(string(output))
+	}
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(repo, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := storagetest.NewFakeStorage()
+	create := func(title string) *types.Issue {
+		t.Helper()
+		issue := &types.Issue{Title: title, Status: types.StatusClosed, Priority: 2, IssueType: types.TypeTask}
+		if err := s.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue: %v", err)
+		}
+		return issue
+	}
+	recorded := create("Fix the parser")
+	trailer := create("Tidy the parser")
+	other := create("Touch another file")
+	edited := create("Edited before commits were recorded")
+	create("Never touched it")
+
+	git("init", "-q")
+	write("internal/parser.go", "package parser\n")
+	git("add", "-A")
+	git("commit", "-q", "-m", "Add parser", "--date=2026-10-01T10:00:00Z")
+	fix := git("rev-parse", "HEAD")
+	if err := s.SetIssueMeta(ctx, recorded.ID, types.MetaClosingCommit, types.MetaString(fix)); err != nil {
+		t.Fatal(err)
+	}
+	started := time.Now().Add(-time.Hour)
+	completed := time.Now()
+	if err := s.RecordExecutionAttempt(ctx, &types.ExecutionAttempt{IssueID: recorded.ID, ExecutorInstanceID: "exec-1234567890",
+		AttemptNumber: 1, StartedAt: started, CompletedAt: &completed, CommitHash: fix}); err != nil {
+		t.Fatal(err)
+	}
+	spawned := &events.AgentEvent{Type: events.EventTypeAgentSpawned, Timestamp: started.Add(time.Minute), IssueID: recorded.ID,
+		ExecutorID: "exec-1234567890", Severity: events.SeverityInfo, Data: map[string]interface{}{"success": true, "agent_type": "amp"}}
+	if err := s.StoreAgentEvent(ctx, spawned); err != nil {
+		t.Fatal(err)
+	}
+
+	write("internal/parser.go", "package parser\n\n// Tidied\n")
+	git("commit", "-q", "-am", "Tidy parser\n\nCloses: "+trailer.ID)
+	write("README.md", "readme\n")
+	git("add", "-A")
+	git("commit", "-q", "-m", "Readme\n\nCloses: "+other.ID)
+
+	// An older execution only left agent events, with sandbox paths
+	toolUse := &events.AgentEvent{Type: events.EventTypeAgentToolUse, Timestamp: time.Now().Add(-48 * time.Hour), IssueID: edited.ID,
+		ExecutorID: "exec-old", AgentID: "agent-old", Severity: events.SeverityInfo, Message: "edit_file",
+		Data: map[string]interface{}{"tool_name": "edit", "target_file": "/work/.sandboxes/mission-1/internal/parser.go"}}
+	read := &events.AgentEvent{Type: events.EventTypeAgentToolUse, Timestamp: time.Now(), IssueID: other.ID,
+		Severity: events.SeverityInfo, Message: "read",
+		Data: map[string]interface{}{"tool_name": "read", "target_file": "internal/parser.go"}}
+	for _, event := range []*events.AgentEvent{toolUse, read} {
+		if err := s.StoreAgentEvent(ctx, event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := blamePath(ctx, s, repo, "internal/parser.go", time.Time{})
+	if err != nil {
+		t.Fatalf("blamePath: %v", err)
+	}
+	var got []string
+	for _, entry := range entries {
+		got = append(got, entry.IssueID+" "+entry.Source)
+	}
+	want := []string{trailer.ID + " commit", edited.ID + " events", recorded.ID + " commit"}
+	if strings.Join(got, ", ") != strings.Join(want, ", ") {
+		t.Fatalf("blamePath() = %v, want %v (newest first)", got, want)
+	}
+	if e := entries[2]; e.Commit != fix || e.Executor != "exec-1234567890" || e.Attempt != 1 || e.Agent != "amp" {
+		t.Errorf("Expected the recorded commit attributed to its attempt, got %+v", e)
+	}
+	if e := entries[1]; e.Executor != "exec-old" || e.AgentID != "agent-old" || e.Commit != "" {
+		t.Errorf("Expected the edit attributed to its agent, got %+v", e)
+	}
+
+	var out strings.Builder
+	if err := writeBlame(&out, entries[2:]); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), recorded.ID+"  [closed] Fix the parser\n    commit "+fix[:8]+", executor exec-123 attempt #1, agent amp\n") {
+		t.Errorf("writeBlame() = %q", out.String())
+	}
+
+	// --since drops older commits and events
+	entries, err = blamePath(ctx, s, repo, "internal/parser.go", time.Now().Add(-time.Hour))
+	if err != nil || len(entries) != 1 || entries[0].IssueID != trailer.ID {
+		t.Errorf("blamePath(since) = %v, %v, want only %s", entries, err, trailer.ID)
+	}
+}
+
+func TestProjectPath(t *testing.T) {
+	root := t.TempDir()
+	if got, err := projectPath(root, filepath.Join(root, "internal", "parser.go")); err != nil || got != "internal/parser.go" {
+		t.Errorf("projectPath() = %q, %v", got, err)
+	}
+	if _, err := projectPath(root, filepath.Dir(root)); err == nil {
+		t.Error("Expected an error for a path outside the project")
+	}
+}
@@ -227,11 +227,18 @@ Examples:
 		// Run VACUUM if requested
 		if vacuum {
 			fmt.Printf("\nRunning VACUUM to reclaim disk space...\n")
+			before, statsErr := store.GetDatabaseStats(ctx)
 			if err := store.VacuumDatabase(ctx); err != nil {
 				fmt.Fprintf(os.Stderr, "Error: VACUUM failed: %v\n", err)
 				os.Exit(1)
 			}
-			fmt.Printf("%s VACUUM complete\n", green("✓"))
+			after, afterErr := store.GetDatabaseStats(ctx)
+			if statsErr == nil && afterErr == nil {
+				fmt.Printf("%s VACUUM complete (%s -> %s)\n", green("✓"),
+					formatBytes(before.SizeBytes), formatBytes(after.SizeBytes))
+			} else {
+				fmt.Printf("%s VACUUM complete\n", green("✓"))
+			}
 		} else {
 			fmt.Printf("\nNote: Use --vacuum to reclaim disk space\n")
 		}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/git"
+	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// loggedCommit is one commit listed by gitLog
+type loggedCommit struct {
+	Hash   string
+	Date   time.Time // Author date
+	Closes []string  // Issue IDs of the commit's Closes: trailers
+}
+
+// loggedCommitFormat prints a commit per line: its hash, its author date
+// and the values of its Closes: trailers (comma-separated), tab-separated
+var loggedCommitFormat = "--format=%H%x09%aI%x09%(trailers:key=" + git.ClosesTrailerKey + ",valueonly,separator=%x2C)"
+
+// gitLog runs git log with args (revisions, options, -- and paths) in the
+// repository at dir and lists its commits, newest first
+func gitLog(ctx context.Context, dir string, args ...string) ([]loggedCommit, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"log", loggedCommitFormat}, args...)...)
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("git log failed: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("git log failed: %w", err)
+	}
+	return parseLoggedCommits(string(output)), nil
+}
+
+// rangeCommits lists the commits of a git revision range (as for git log)
+// in the repository at dir
+func rangeCommits(ctx context.Context, dir, revRange string) ([]loggedCommit, error) {
+	if strings.HasPrefix(revRange, "-") {
+		return nil, fmt.Errorf("invalid revision range %q", revRange)
+	}
+	return gitLog(ctx, dir, revRange, "--")
+}
+
+// parseLoggedCommits parses the output of git log with loggedCommitFormat
+func parseLoggedCommits(output string) []loggedCommit {
+	var commits []loggedCommit
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), "\t", 3)
+		hash := strings.TrimSpace(fields[0])
+		if hash == "" {
+			continue
+		}
+		commit := loggedCommit{Hash: hash}
+		if len(fields) > 1 {
+			commit.Date, _ = time.Parse(time.RFC3339, fields[1])
+		}
+		if len(fields) > 2 {
+			for _, id := range strings.Split(fields[2], ",") {
+				if id = strings.TrimSpace(id); id != "" {
+					commit.Closes = append(commit.Closes, id)
+				}
+			}
+		}
+		commits = append(commits, commit)
+	}
+	return commits
+}
+
+// issuesClosedIn returns the IDs of the issues closed by the commits: those
+// whose recorded closing or merge commit is one of them (see
+// types.ClosingCommitFromMeta) and those named by their Closes: trailers
+func issuesClosedIn(ctx context.Context, s storage.Storage, commits []loggedCommit) (map[string]bool, error) {
+	ids := make(map[string]bool)
+	for _, commit := range commits {
+		closed, err := issuesClosedBy(ctx, s, commit)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range closed {
+			ids[id] = true
+		}
+	}
+	return ids, nil
+}
+
+// issuesClosedBy returns the IDs of the issues one commit closed, in the
+// order of its Closes: trailers and then of the recorded commits, once each
+func issuesClosedBy(ctx context.Context, s storage.Storage, commit loggedCommit) ([]string, error) {
+	ids := slices.Clone(commit.Closes)
+	for _, key := range []string{types.MetaClosingCommit, types.MetaMergeCommit} {
+		issues, err := s.SearchIssues(ctx, "", types.IssueFilter{
+			MetaEquals:      map[string]string{key: string(types.MetaString(commit.Hash))},
+			IncludeArchived: true,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to find issues closed in %s: %w", commit.Hash, err)
+		}
+		for _, issue := range issues {
+			ids = append(ids, issue.ID)
+		}
+	}
+	seen := make(map[string]bool, len(ids))
+	return slices.DeleteFunc(ids, func(id string) bool {
+		duplicate := seen[id]
+		seen[id] = true
+		return duplicate
+	}), nil
+}
+
+// listClosedIn lists the issues matching filter that were closed in the
+// commits of revRange, applying filter's Offset and Limit to them. It also
+// returns how many matched before paging.
+func listClosedIn(ctx context.Context, s storage.Storage, dir, revRange string, filter types.IssueFilter) ([]*types.Issue, int, error) {
+	commits, err := rangeCommits(ctx, dir, revRange)
+	if err != nil {
+		return nil, 0, err
+	}
+	ids, err := issuesClosedIn(ctx, s, commits)
+	if err != nil {
+		return nil, 0, err
+	}
+	limit, offset := filter.Limit, filter.Offset
+	filter.Limit, filter.Offset = 0, 0
+	all, err := s.SearchIssues(ctx, "", filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	var issues []*types.Issue
+	for _, issue := range all {
+		if ids[issue.ID] {
+			issues = append(issues, issue)
+		}
+	}
+	total := len(issues)
+	issues = issues[min(offset, total):]
+	if limit > 0 && limit < len(issues) {
+		issues = issues[:limit]
+	}
+	return issues, total, nil
+}
+
+// formatClosingCommit describes the commit that closed an issue, for vc show
+func formatClosingCommit(c *types.ClosingCommit) string {
+	line := shortCommit(c.Commit)
+	if c.Branch != "" {
+		line += " on " + c.Branch
+	}
+	if c.MergeCommit != "" {
+		line += fmt.Sprintf(" (merged as %s)", shortCommit(c.MergeCommit))
+	}
+	return line
+}
+
+// shortCommit abbreviates a commit hash to 8 characters
+func shortCommit(hash string) string {
+	if len(hash) > 8 {
+		return hash[:8]
+	}
+	return hash
+}
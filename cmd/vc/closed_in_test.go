@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/storage/storagetest"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestParseLoggedCommits(t *testing.T) {
+	commits := parseLoggedCommits("aaa\t2026-10-01T12:00:00+02:00\tvc-1,vc-2\nbbb\t2026-09-30T08:00:00Z\t\n\nccc\n")
+	if len(commits) != 3 {
+		t.Fatalf("Expected 3 commits, got %+v", commits)
+	}
+	if commits[0].Hash != "aaa" || strings.Join(commits[0].Closes, " ") != "vc-1 vc-2" {
+		t.Errorf("Got %+v, want aaa closing vc-1 and vc-2", commits[0])
+	}
+	if !commits[0].Date.Equal(time.Date(2026, 10, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("Got date %v for aaa", commits[0].Date)
+	}
+	if commits[1].Hash != "bbb" || commits[1].Closes != nil || commits[2].Hash != "ccc" {
+		t.Errorf("Got %+v, want commits without trailers", commits[1:])
+	}
+}
+
+func TestListClosedIn(t *testing.T) {
+	ctx := context.Background()
+	repo := t.TempDir()
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=Test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = repo
+		output, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("git %v: %v", args, err)
+		}
+		return strings.TrimSpace(string(output))
+	}
+	git("init", "-q")
+	git("commit", "-q", "--allow-empty", "-m", "Initial commit")
+	base := git("rev-parse", "HEAD")
+
+	s := storagetest.NewFakeStorage()
+	create := func(title string) *types.Issue {
+		t.Helper()
+		issue := &types.Issue{Title: title, Status: types.StatusClosed, Priority: 2, IssueType: types.TypeTask}
+		if err := s.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue: %v", err)
+		}
+		return issue
+	}
+	recorded := create("Closed by a recorded commit")
+	trailer := create("Closed by a trailer")
+	merged := create("Closed on a merged branch")
+	create("Closed before the range")
+
+	git("commit", "-q", "--allow-empty", "-m", "Fix recorded")
+	if err := s.SetIssueMeta(ctx, recorded.ID, types.MetaClosingCommit, types.MetaString(git("rev-parse", "HEAD"))); err != nil {
+		t.Fatal(err)
+	}
+	git("commit", "-q", "--allow-empty", "-m", "Fix trailer\n\nCloses: "+trailer.ID)
+	git("commit", "-q", "--allow-empty", "-m", "Merge branch")
+	if err := s.SetIssueMeta(ctx, merged.ID, types.MetaClosingCommit, types.MetaString("0123456789")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SetIssueMeta(ctx, merged.ID, types.MetaMergeCommit, types.MetaString(git("rev-parse", "HEAD"))); err != nil {
+		t.Fatal(err)
+	}
+
+	issues, total, err := listClosedIn(ctx, s, repo, base+"..HEAD", types.IssueFilter{OrderBy: "id"})
+	if err != nil {
+		t.Fatalf("listClosedIn: %v", err)
+	}
+	if total != 3 || strings.Join(ids(issues), " ") != strings.Join([]string{recorded.ID, trailer.ID, merged.ID}, " ") {
+		t.Errorf("listClosedIn() = %v (total %d), want the 3 issues closed in the range", ids(issues), total)
+	}
+
+	issues, total, err = listClosedIn(ctx, s, repo, base+"..HEAD", types.IssueFilter{OrderBy: "id", Offset: 1, Limit: 1})
+	if err != nil || total != 3 || len(issues) != 1 || issues[0].ID != trailer.ID {
+		t.Errorf("listClosedIn() with paging = %v (total %d, err %v), want %s of 3", ids(issues), total, err, trailer.ID)
+	}
+
+	if _, _, err := listClosedIn(ctx, s, repo, "no-such-rev..HEAD", types.IssueFilter{}); err == nil {
+		t.Error("Expected an error for an unknown revision")
+	}
+}
+
+func TestFormatClosingCommit(t *testing.T) {
+	closing := &types.ClosingCommit{Commit: "0123456789abcdef", Branch: "vc/vc-87", MergeCommit: "fedcba9876543210"}
+	if got := formatClosingCommit(closing); got != "01234567 on vc/vc-87 (merged as fedcba98)" {
+		t.Errorf("formatClosingCommit() = %q", got)
+	}
+}
+
+func ids(issues []*types.Issue) []string {
+	out := make([]string, len(issues))
+	for i, issue := range issues {
+		out[i] = issue.ID
+	}
+	return out
+}
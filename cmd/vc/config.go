@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/config"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "List, get and set runtime settings",
+	Long: `Runtime settings are stored in the database and read by vc at startup.
+'vc config list' shows the known settings with their defaults, and changing
+one the executor reads (executor.*) is recorded as a config_changed event.
+
+Config files set CLI defaults, the database path and executor settings:
+.vc/config.yaml in the directory vc runs in, merged over the user's
+~/.config/vc/config.yaml. Flags win over config files, which win over the
+database settings and built-in defaults. 'vc config effective' shows the
+merged result.`,
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list [prefix]",
+	Short: "List settings, defaults and overridden values",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		prefix := ""
+		if len(args) == 1 {
+			prefix = args[0]
+		}
+		stored, err := store.ListConfig(context.Background(), prefix)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := writeConfigTable(os.Stdout, prefix, stored); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get [key]",
+	Short: "Print a setting's value (its default when unset)",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		value, err := store.GetConfig(context.Background(), args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if setting, known := config.LookupSetting(args[0]); known && value == "" {
+			value = setting.Default
+		}
+		fmt.Println(value)
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set [key] [value]",
+	Short: "Set a setting",
+	Long: `Set a setting. Values of known settings are validated; executors pick
+up changes when they next start.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		key, value := args[0], args[1]
+		if err := store.SetConfig(context.Background(), key, value); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Set %s = %s\n", green("✓"), key, value)
+		if _, known := config.LookupSetting(key); !known {
+			yellow := color.New(color.FgYellow).SprintFunc()
+			fmt.Printf("%s %s is not a known setting\n", yellow("⚠"), key)
+		}
+	},
+}
+
+// writeConfigTable renders the known settings under prefix, then any
+// other stored keys, marking which values override the default
+func writeConfigTable(w io.Writer, prefix string, stored map[string]string) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tVALUE\tSOURCE\tTYPE\tCONSUMED BY")
+
+	seen := make(map[string]bool)
+	for _, setting := range config.Settings {
+		if !strings.HasPrefix(setting.Key, prefix) {
+			continue
+		}
+		seen[setting.Key] = true
+		value, source := setting.Default, "default"
+		if v, ok := stored[setting.Key]; ok && v != "" {
+			value, source = v, "overridden"
+			if v == setting.Default {
+				source = "set (= default)"
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", setting.Key, value, source, setting.Type, setting.ConsumedBy)
+	}
+
+	var unknown []string
+	for key := range stored {
+		if !seen[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	for _, key := range unknown {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", key, stored[key], "stored", "-", "-")
+	}
+	return tw.Flush()
+}
+
+var configEffectiveCmd = &cobra.Command{
+	Use:   "effective",
+	Short: "Show the merged configuration and where each value comes from",
+	Long: `Show the keys config files can set with the value in effect and its
+source: a flag, a config file, the database ('vc config set') or the
+built-in default.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		stored, err := store.ListConfig(context.Background(), config.ExecutorNamespace)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		settings := effectiveSettings(fileConfig, stored)
+		for _, s := range settings {
+			switch s.Key {
+			case "actor":
+				s.Value, s.Source = actor, sourceOf(cmd, "actor", s.Source, "$USER")
+			case "db":
+				s.Value, s.Source = dbPath, "auto-discovered"
+				if dbChoice != nil && dbChoice.Source != "" {
+					s.Source = dbChoice.Source
+				}
+			}
+		}
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		if jsonOutput {
+			err = writeJSON(os.Stdout, settings)
+		} else {
+			err = writeEffectiveTable(os.Stdout, settings)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// effectiveSetting is a config file key's value in effect
+type effectiveSetting struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Source string `json:"source"` // Config file path, "database" or "default"
+}
+
+// effectiveSettings merges the config files over the stored executor
+// settings and the defaults
+func effectiveSettings(file *config.File, stored map[string]string) []*effectiveSetting {
+	settings := make([]*effectiveSetting, 0, len(config.FileSettings))
+	for _, setting := range config.FileSettings {
+		s := &effectiveSetting{Key: setting.Key, Value: setting.Default, Source: "default"}
+		if v, ok := file.Get(setting.Key); ok {
+			s.Value, s.Source = v.Value, v.Source
+		} else if v := stored[setting.Key]; v != "" && config.IsExecutorSetting(setting.Key) {
+			s.Value, s.Source = v, "database"
+		}
+		settings = append(settings, s)
+	}
+	return settings
+}
+
+// sourceOf names where a global flag's value came from: the flag, the
+// config file or fallback
+func sourceOf(cmd *cobra.Command, flag, source, fallback string) string {
+	if cmd.Flags().Changed(flag) {
+		return "--" + flag
+	}
+	if source != "default" {
+		return source
+	}
+	return fallback
+}
+
+// writeEffectiveTable renders 'vc config effective'
+func writeEffectiveTable(w io.Writer, settings []*effectiveSetting) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tVALUE\tSOURCE")
+	for _, s := range settings {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", s.Key, s.Value, s.Source)
+	}
+	return tw.Flush()
+}
+
+// loadConfigFiles reads the user's config file and, over it, the
+// project's .vc/config.yaml, or the file given with --config on commands
+// that have it (which must exist)
+func loadConfigFiles(cmd *cobra.Command) (*config.File, error) {
+	project := config.ProjectConfigFile
+	if flag := cmd.Flags().Lookup("config"); flag != nil && flag.Value.String() != "" {
+		project = flag.Value.String()
+		if _, err := os.Stat(project); err != nil {
+			return nil, fmt.Errorf("config file: %w", err)
+		}
+	}
+	return config.LoadFiles(config.UserConfigPath(), project)
+}
+
+// applyConfigDefaults sets the flags not given on the command line to the
+// values the config files set for their keys (flag name -> key)
+func applyConfigDefaults(cmd *cobra.Command, keys map[string]string) error {
+	for flag, key := range keys {
+		v, ok := fileConfig.Get(key)
+		if !ok || cmd.Flags().Changed(flag) {
+			continue
+		}
+		if err := cmd.Flags().Set(flag, v.Value); err != nil {
+			return fmt.Errorf("%s (from %s): %w", key, v.Source, err)
+		}
+	}
+	return nil
+}
+
+func init() {
+	configEffectiveCmd.Flags().Bool("json", false, "Output as JSON")
+	configCmd.AddCommand(configEffectiveCmd)
+	configCmd.AddCommand(configListCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	rootCmd.AddCommand(configCmd)
+}
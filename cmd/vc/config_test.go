@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/config"
+)
+
+func TestWriteConfigTable(t *testing.T) {
+	stored := map[string]string{
+		"executor.poll_interval": "10s",
+		"executor.keep_branches": "false",
+		"executor.custom":        "x",
+	}
+	var buf bytes.Buffer
+	if err := writeConfigTable(&buf, "executor.", stored); err != nil {
+		t.Fatalf("writeConfigTable failed: %v", err)
+	}
+	rows := make(map[string]string)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if !strings.HasPrefix(lines[0], "KEY") {
+		t.Fatalf("Missing header:\n%s", buf.String())
+	}
+	for _, line := range lines[1:] {
+		fields := strings.Fields(line)
+		rows[fields[0]] = strings.Join(fields[1:], " ")
+		if !strings.HasPrefix(fields[0], "executor.") {
+			t.Errorf("Row outside the prefix: %q", line)
+		}
+	}
+
+	for key, want := range map[string]string{
+		"executor.poll_interval":    "10s overridden duration vc execute (event loop)",
+		"executor.keep_branches":    "false set (= default) bool vc execute (sandbox manager)",
+		"executor.heartbeat_period": "30s default duration vc execute (heartbeat)",
+		"executor.custom":           "x stored - -",
+	} {
+		if rows[key] != want {
+			t.Errorf("%s: got %q, want %q", key, rows[key], want)
+		}
+	}
+	if last := lines[len(lines)-1]; !strings.HasPrefix(last, "executor.custom") {
+		t.Errorf("Expected unknown keys after the known settings, last row %q", last)
+	}
+}
+
+func TestEffectiveSettings(t *testing.T) {
+	file := &config.File{Values: map[string]config.FileValue{}}
+	if err := file.Parse(".vc/config.yaml", []byte("create:\n  type: bug\nexecutor:\n  poll_interval: 10s\n")); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	stored := map[string]string{
+		"executor.poll_interval": "2s",
+		"executor.keep_branches": "true",
+		"issue_prefix":           "xy",
+	}
+	got := make(map[string]string)
+	for _, s := range effectiveSettings(file, stored) {
+		got[s.Key] = s.Value + " " + s.Source
+	}
+	for key, want := range map[string]string{
+		"create.type":               "bug .vc/config.yaml",
+		"create.priority":           "2 default",
+		"executor.poll_interval":    "10s .vc/config.yaml",
+		"executor.keep_branches":    "true database",
+		"executor.sandbox_root":     ".sandboxes default",
+		"executor.heartbeat_period": "30s default",
+	} {
+		if got[key] != want {
+			t.Errorf("%s: got %q, want %q", key, got[key], want)
+		}
+	}
+	if _, ok := got["issue_prefix"]; ok {
+		t.Error("Database-only settings should not be listed")
+	}
+}
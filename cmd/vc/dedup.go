@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/deduplication"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+var dedupCmd = &cobra.Command{
+	Use:   "dedup",
+	Short: "Inspect duplicate detection of discovered issues",
+	Long: `The executor drops discovered issues the AI judges duplicates of existing
+ones. Tune it with the dedup.* settings (see 'vc config list dedup.').`,
+}
+
+var dedupPreviewCmd = &cobra.Command{
+	Use:   "preview [title]",
+	Short: "Rank existing issues against a hypothetical one",
+	Long: `Run the deduplicator against a hypothetical issue, as if an agent had just
+discovered it, and print every compared issue with its score. Nothing is
+filed. Use it to calibrate dedup.confidence_threshold against your backlog.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		description, _ := cmd.Flags().GetString("description")
+		issueType, _ := cmd.Flags().GetString("type")
+		priority, _ := cmd.Flags().GetInt("priority")
+		within, _ := cmd.Flags().GetString("within")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		ctx := context.Background()
+		dedupConfig, err := deduplication.LoadConfig(ctx, store)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		supervisor, err := newAISupervisor(ctx, store)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: AI supervisor unavailable: %v\n", err)
+			os.Exit(1)
+		}
+		dedup, err := deduplication.NewAIDeduplicator(supervisor, store, dedupConfig)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		candidate := &types.Issue{
+			Title:       args[0],
+			Description: description,
+			Status:      types.StatusOpen,
+			Priority:    priority,
+			IssueType:   types.IssueType(issueType),
+		}
+		if err := candidate.Validate(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if within != "" {
+			ctx = deduplication.WithScopeIssue(ctx, within)
+		}
+		matches, err := dedup.RankMatches(ctx, candidate)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		preview := newDedupPreview(dedupConfig, within, matches)
+		if jsonOutput {
+			err = writeDedupPreviewJSON(os.Stdout, preview)
+		} else {
+			err = writeDedupPreviewTable(os.Stdout, preview)
+			if err == nil && len(candidate.Title) < dedupConfig.MinTitleLength {
+				fmt.Printf("\nNote: titles shorter than %d characters skip deduplication when filed\n", dedupConfig.MinTitleLength)
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// dedupPreviewMatch is one ranked issue of 'vc dedup preview'
+type dedupPreviewMatch struct {
+	IssueID     string       `json:"issue_id"`
+	Title       string       `json:"title"`
+	Status      types.Status `json:"status"`
+	Confidence  float64      `json:"confidence"`
+	AIDuplicate bool         `json:"ai_duplicate"` // The AI's judgment alone
+	Duplicate   bool         `json:"duplicate"`    // AI judgment and confidence at or above the threshold
+	Reasoning   string       `json:"reasoning"`
+}
+
+// dedupPreview is the result of 'vc dedup preview'
+type dedupPreview struct {
+	Threshold   float64             `json:"threshold"`
+	Scope       string              `json:"scope"`
+	ScopeIssue  string              `json:"scope_issue,omitempty"`
+	Statuses    []types.Status      `json:"statuses"`
+	DuplicateOf string              `json:"duplicate_of,omitempty"` // The issue the executor would drop it for
+	Matches     []dedupPreviewMatch `json:"matches"`
+}
+
+// newDedupPreview applies the threshold to ranked matches the way
+// CheckDuplicate does: the best AI-confirmed match at or above it wins
+func newDedupPreview(cfg deduplication.Config, scopeIssue string, matches []deduplication.RankedMatch) *dedupPreview {
+	preview := &dedupPreview{
+		Threshold:  cfg.ConfidenceThreshold,
+		Scope:      string(cfg.Scope),
+		ScopeIssue: scopeIssue,
+		Statuses:   cfg.Statuses,
+		Matches:    []dedupPreviewMatch{},
+	}
+	if preview.Scope == "" {
+		preview.Scope = string(deduplication.ScopeAll)
+	}
+	for _, m := range matches {
+		duplicate := m.IsDuplicate && m.Confidence >= cfg.ConfidenceThreshold
+		if duplicate && preview.DuplicateOf == "" {
+			preview.DuplicateOf = m.Issue.ID
+		}
+		preview.Matches = append(preview.Matches, dedupPreviewMatch{
+			IssueID:     m.Issue.ID,
+			Title:       m.Issue.Title,
+			Status:      m.Issue.Status,
+			Confidence:  m.Confidence,
+			AIDuplicate: m.IsDuplicate,
+			Duplicate:   duplicate,
+			Reasoning:   m.Reasoning,
+		})
+	}
+	return preview
+}
+
+func writeDedupPreviewJSON(w io.Writer, preview *dedupPreview) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(preview)
+}
+
+// writeDedupPreviewTable renders 'vc dedup preview': the ranked matches,
+// then what the executor would do with the issue
+func writeDedupPreviewTable(w io.Writer, preview *dedupPreview) error {
+	scope := preview.Scope
+	if preview.ScopeIssue != "" {
+		scope += " within " + preview.ScopeIssue
+	}
+	statuses := make([]string, len(preview.Statuses))
+	for i, status := range preview.Statuses {
+		statuses[i] = string(status)
+	}
+	fmt.Fprintf(w, "Threshold %.2f, scope %s, statuses %s: %d issues compared\n\n",
+		preview.Threshold, scope, strings.Join(statuses, ","), len(preview.Matches))
+
+	if len(preview.Matches) > 0 {
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "RANK\tISSUE\tSTATUS\tSCORE\tAI SAYS\tTITLE")
+		for i, m := range preview.Matches {
+			verdict := "distinct"
+			if m.AIDuplicate {
+				verdict = "duplicate"
+			}
+			marker := ""
+			if m.Duplicate {
+				marker = " *"
+			}
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%.2f%s\t%s\t%s\n", i+1, m.IssueID, m.Status, m.Confidence, marker, verdict, truncateTitle(m.Title, 60))
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+		fmt.Fprintln(w)
+	}
+
+	switch {
+	case preview.DuplicateOf != "":
+		fmt.Fprintf(w, "Would be dropped as a duplicate of %s (* = at or above the threshold)\n", preview.DuplicateOf)
+	case len(preview.Matches) > 0:
+		fmt.Fprintf(w, "Would be filed: best match %s scored %.2f\n", preview.Matches[0].IssueID, preview.Matches[0].Confidence)
+	default:
+		fmt.Fprintln(w, "Would be filed: no issues to compare against")
+	}
+	return nil
+}
+
+// truncateTitle shortens a title for table output
+func truncateTitle(title string, max int) string {
+	if len(title) <= max {
+		return title
+	}
+	return title[:max-3] + "..."
+}
+
+func init() {
+	dedupPreviewCmd.Flags().StringP("description", "d", "", "Description of the hypothetical issue")
+	dedupPreviewCmd.Flags().StringP("type", "t", "task", "Issue type (bug|feature|task|epic|chore)")
+	dedupPreviewCmd.Flags().IntP("priority", "p", 2, "Priority (0-4, 0=highest)")
+	dedupPreviewCmd.Flags().String("within", "", "Issue it was discovered in, for the epic and label scopes")
+	dedupPreviewCmd.Flags().Bool("json", false, "Output as JSON")
+	dedupCmd.AddCommand(dedupPreviewCmd)
+	rootCmd.AddCommand(dedupCmd)
+}
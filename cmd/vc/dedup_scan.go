@@ -0,0 +1,373 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/deduplication"
+	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// dedupScanStateFile holds the progress of 'vc dedup scan', next to the
+// database
+const dedupScanStateFile = "dedup-scan.json"
+
+var dedupScanCmd = &cobra.Command{
+	Use:   "scan",
+	Short: "Find duplicates among existing open issues",
+	Long: `Compare open issues pairwise with the AI deduplicator, oldest first, and
+print groups of duplicates with their confidence scores. Each issue is
+compared with the newer ones in batches of VC_DEDUP_BATCH_SIZE.
+
+Progress is saved to .beads/dedup-scan.json after every issue: an
+interrupted scan resumes where it stopped when run again with the same
+filters (--restart starts over). Once a scan is complete, running it again
+reports the saved results without AI calls, e.g. with another --threshold.
+
+--link adds "related" dependencies from each duplicate to the survivor,
+the oldest issue of its group. --merge asks, group by group, to close the
+duplicates with a comment referencing the survivor.`,
+	Example: `  vc dedup scan --label parser --since 90d   # Scan part of the backlog
+  vc dedup scan --limit 20                    # Spread a long scan over runs
+  vc dedup scan --threshold 0.9 --merge       # Review and close duplicates`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		label, _ := cmd.Flags().GetString("label")
+		issueType, _ := cmd.Flags().GetString("type")
+		since, _ := cmd.Flags().GetString("since")
+		threshold, _ := cmd.Flags().GetFloat64("threshold")
+		limit, _ := cmd.Flags().GetInt("limit")
+		restart, _ := cmd.Flags().GetBool("restart")
+		link, _ := cmd.Flags().GetBool("link")
+		merge, _ := cmd.Flags().GetBool("merge")
+
+		// Ctrl-C stops after the current batch; progress so far is kept
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		dedupConfig, err := deduplication.LoadConfig(ctx, store)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if !cmd.Flags().Changed("threshold") {
+			threshold = dedupConfig.ConfidenceThreshold
+		}
+
+		statePath := filepath.Join(filepath.Dir(dbPath), dedupScanStateFile)
+		filter := deduplication.ScanFilter{Label: label, Type: issueType, Since: since}
+		state, err := loadScanStateFor(statePath, filter, restart)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if state == nil {
+			issues, err := selectScanIssues(ctx, store, filter, time.Now())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			state = deduplication.NewScanState(filter, issues)
+			fmt.Fprintf(os.Stderr, "Scanning %d open issues\n", len(issues))
+		} else if !state.Done() {
+			fmt.Fprintf(os.Stderr, "Resuming scan at issue %d of %d\n", state.Next+1, len(state.IssueIDs))
+		}
+
+		issues, err := loadScanIssues(ctx, store, state.IssueIDs)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if !state.Done() {
+			supervisor, err := newAISupervisor(ctx, store)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: AI supervisor unavailable: %v\n", err)
+				os.Exit(1)
+			}
+			dedup, err := deduplication.NewAIDeduplicator(supervisor, store, dedupConfig)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			if err := runDedupScan(ctx, dedup, state, issues, statePath, limit, os.Stderr); err != nil {
+				if ctx.Err() != nil {
+					fmt.Fprintf(os.Stderr, "\nInterrupted at issue %d of %d; run again to resume\n", state.Next+1, len(state.IssueIDs))
+					os.Exit(130)
+				}
+				fmt.Fprintf(os.Stderr, "Error: %v (progress saved; run again to resume)\n", err)
+				os.Exit(1)
+			}
+		}
+
+		groups := state.Groups(threshold)
+		writeDuplicateGroups(os.Stdout, state, groups, issues, threshold)
+
+		if link {
+			for _, group := range groups {
+				added, err := linkDuplicateGroup(ctx, store, group, actor)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					os.Exit(1)
+				}
+				if added > 0 {
+					fmt.Printf("Linked %d issues to %s\n", added, group.Survivor)
+				}
+			}
+		}
+		if merge {
+			if err := mergeDuplicateGroups(ctx, store, groups, issues, actor, os.Stdin, os.Stdout); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+	},
+}
+
+// loadScanStateFor returns the saved scan to continue, or nil to start a
+// new one: when there is none, restart is set or its filter differs
+func loadScanStateFor(path string, filter deduplication.ScanFilter, restart bool) (*deduplication.ScanState, error) {
+	if restart {
+		return nil, nil
+	}
+	state, err := deduplication.LoadScanState(path)
+	if err != nil || state == nil {
+		return nil, err
+	}
+	if state.Filter != filter {
+		fmt.Fprintf(os.Stderr, "Starting a new scan: the saved one used other filters\n")
+		return nil, nil
+	}
+	return state, nil
+}
+
+// selectScanIssues returns the open issues matching the scan filter
+func selectScanIssues(ctx context.Context, s storage.Storage, filter deduplication.ScanFilter, now time.Time) ([]*types.Issue, error) {
+	open := types.StatusOpen
+	issueFilter := types.IssueFilter{Status: &open}
+	if filter.Label != "" {
+		issueFilter.Labels = []string{filter.Label}
+	}
+	if filter.Type != "" {
+		t := types.IssueType(filter.Type)
+		if !t.IsValid() {
+			return nil, fmt.Errorf("invalid issue type: %s", filter.Type)
+		}
+		issueFilter.IssueType = &t
+	}
+	var createdAfter time.Time
+	if filter.Since != "" {
+		window, err := parseSince(filter.Since)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --since value: %w", err)
+		}
+		createdAfter = now.Add(-window)
+	}
+
+	issues, err := s.SearchIssues(ctx, "", issueFilter)
+	if err != nil {
+		return nil, err
+	}
+	var selected []*types.Issue
+	for _, issue := range issues {
+		if issue.CreatedAt.Before(createdAfter) {
+			continue
+		}
+		selected = append(selected, issue)
+	}
+	return selected, nil
+}
+
+// loadScanIssues fetches the scanned issues by ID. Issues deleted or
+// closed since the scan started are left out.
+func loadScanIssues(ctx context.Context, s storage.Storage, ids []string) (map[string]*types.Issue, error) {
+	issues := make(map[string]*types.Issue, len(ids))
+	for _, id := range ids {
+		issue, err := s.GetIssue(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if issue != nil && issue.Status != types.StatusClosed {
+			issues[id] = issue
+		}
+	}
+	return issues, nil
+}
+
+// issueScanner compares an issue with newer ones (AIDeduplicator.ScanIssue)
+type issueScanner interface {
+	ScanIssue(ctx context.Context, issue *types.Issue, newer []*types.Issue) ([]deduplication.ScanPair, int, error)
+}
+
+// runDedupScan compares the scan's issues from its cursor on, saving the
+// state after each issue. limit > 0 stops after that many issues.
+func runDedupScan(ctx context.Context, scanner issueScanner, state *deduplication.ScanState, issues map[string]*types.Issue, statePath string, limit int, progress io.Writer) error {
+	if err := state.Save(statePath); err != nil {
+		return fmt.Errorf("failed to save scan state: %w", err)
+	}
+	total := len(state.IssueIDs)
+	for scanned := 0; !state.Done() && (limit <= 0 || scanned < limit); scanned++ {
+		issue := issues[state.IssueIDs[state.Next]]
+		var newer []*types.Issue
+		for _, id := range state.IssueIDs[state.Next+1:] {
+			if other, ok := issues[id]; ok {
+				newer = append(newer, other)
+			}
+		}
+
+		if issue != nil && len(newer) > 0 {
+			pairs, calls, err := scanner.ScanIssue(ctx, issue, newer)
+			state.AICalls += calls
+			if err != nil {
+				_ = state.Save(statePath)
+				return err
+			}
+			state.Pairs = append(state.Pairs, pairs...)
+			fmt.Fprintf(progress, "[%d/%d] %s: %d compared, %d duplicate pairs\n",
+				state.Next+1, total, issue.ID, len(newer), len(pairs))
+		}
+		state.Next++
+		if err := state.Save(statePath); err != nil {
+			return fmt.Errorf("failed to save scan state: %w", err)
+		}
+	}
+	if !state.Done() {
+		fmt.Fprintf(progress, "Stopped after %d issues (--limit); run again to continue\n", limit)
+	}
+	return nil
+}
+
+// writeDuplicateGroups prints the duplicate groups of a scan
+func writeDuplicateGroups(w io.Writer, state *deduplication.ScanState, groups []deduplication.DuplicateGroup, issues map[string]*types.Issue, threshold float64) {
+	progress := "complete"
+	if !state.Done() {
+		progress = fmt.Sprintf("%d of %d issues compared", state.Next, len(state.IssueIDs))
+	}
+	fmt.Fprintf(w, "\nScan %s, %d AI calls. %d duplicate groups at threshold %.2f\n",
+		progress, state.AICalls, len(groups), threshold)
+
+	title := func(id string) string {
+		if issue, ok := issues[id]; ok {
+			return issue.Title
+		}
+		return "(closed or deleted)"
+	}
+	for i, group := range groups {
+		fmt.Fprintf(w, "\nGroup %d (weakest link %.2f):\n", i+1, group.MinConfidence)
+		fmt.Fprintf(w, "  keep   %s  %s\n", group.Survivor, title(group.Survivor))
+		for _, id := range group.Duplicates {
+			best := 0.0
+			for _, pair := range group.Pairs {
+				if (pair.Newer == id || pair.Older == id) && pair.Confidence > best {
+					best = pair.Confidence
+				}
+			}
+			fmt.Fprintf(w, "  dup    %s  %s  (%.2f)\n", id, title(id), best)
+		}
+	}
+}
+
+// linkDuplicateGroup adds a related dependency from each duplicate to the
+// survivor unless they are already linked; returns how many were added
+func linkDuplicateGroup(ctx context.Context, s storage.Storage, group deduplication.DuplicateGroup, actor string) (int, error) {
+	added := 0
+	for _, id := range group.Duplicates {
+		records, err := s.GetDependencyRecords(ctx, id)
+		if err != nil {
+			return added, err
+		}
+		linked := false
+		for _, dep := range records {
+			if dep.DependsOnID == group.Survivor {
+				linked = true
+				break
+			}
+		}
+		if linked {
+			continue
+		}
+		dep := &types.Dependency{IssueID: id, DependsOnID: group.Survivor, Type: types.DepRelated}
+		if err := s.AddDependency(ctx, dep, actor); err != nil {
+			return added, fmt.Errorf("failed to link %s to %s: %w", id, group.Survivor, err)
+		}
+		added++
+	}
+	return added, nil
+}
+
+// mergeDuplicateGroups asks, group by group, whether to close the
+// duplicates (y), skip the group (n) or stop (q)
+func mergeDuplicateGroups(ctx context.Context, s storage.Storage, groups []deduplication.DuplicateGroup, issues map[string]*types.Issue, actor string, in io.Reader, out io.Writer) error {
+	reader := bufio.NewReader(in)
+	for _, group := range groups {
+		var open []string
+		for _, id := range group.Duplicates {
+			if _, ok := issues[id]; ok {
+				open = append(open, id)
+			}
+		}
+		if len(open) == 0 {
+			continue
+		}
+		fmt.Fprintf(out, "\nClose %s as duplicates of %s? [y/N/q] ", strings.Join(open, ", "), group.Survivor)
+		answer, err := reader.ReadString('\n')
+		if err != nil && answer == "" {
+			return nil // End of input
+		}
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "y", "yes":
+		case "q", "quit":
+			return nil
+		default:
+			continue
+		}
+		for _, id := range open {
+			if err := closeAsDuplicate(ctx, s, id, group, actor); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "Closed %s\n", id)
+		}
+	}
+	return nil
+}
+
+// closeAsDuplicate comments on a duplicate which issue it duplicates, then
+// closes it
+func closeAsDuplicate(ctx context.Context, s storage.Storage, id string, group deduplication.DuplicateGroup, actor string) error {
+	confidence := 0.0
+	for _, pair := range group.Pairs {
+		if (pair.Newer == id || pair.Older == id) && pair.Confidence > confidence {
+			confidence = pair.Confidence
+		}
+	}
+	comment := fmt.Sprintf("Duplicate of %s (found by vc dedup scan, confidence %.2f). Closing in favor of %s.",
+		group.Survivor, confidence, group.Survivor)
+	if err := s.AddComment(ctx, id, actor, comment); err != nil {
+		return fmt.Errorf("failed to comment on %s: %w", id, err)
+	}
+	if err := s.CloseIssue(ctx, id, "duplicate of "+group.Survivor, actor); err != nil {
+		return fmt.Errorf("failed to close %s: %w", id, err)
+	}
+	return nil
+}
+
+func init() {
+	dedupScanCmd.Flags().String("label", "", "Only scan issues with this label")
+	dedupScanCmd.Flags().StringP("type", "t", "", "Only scan issues of this type (bug|feature|task|epic|chore)")
+	dedupScanCmd.Flags().String("since", "", "Only scan issues created within this window (e.g. 90d, 720h)")
+	dedupScanCmd.Flags().Float64("threshold", 0, "Minimum confidence to group issues (default: dedup.confidence_threshold)")
+	dedupScanCmd.Flags().Int("limit", 0, "Compare at most this many issues in this run (0 = all)")
+	dedupScanCmd.Flags().Bool("restart", false, "Discard saved progress and start a new scan")
+	dedupScanCmd.Flags().Bool("link", false, "Add related dependencies from duplicates to the survivor")
+	dedupScanCmd.Flags().Bool("merge", false, "Interactively close duplicates with a comment referencing the survivor")
+	dedupCmd.AddCommand(dedupScanCmd)
+}
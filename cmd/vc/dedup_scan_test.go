@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/deduplication"
+	"github.com/steveyegge/vc/internal/storage/storagetest"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// fakeScanner judges issues with equal titles duplicates
+type fakeScanner struct {
+	calls int
+}
+
+func (f *fakeScanner) ScanIssue(ctx context.Context, issue *types.Issue, newer []*types.Issue) ([]deduplication.ScanPair, int, error) {
+	f.calls++
+	var pairs []deduplication.ScanPair
+	for _, other := range newer {
+		if other.Title == issue.Title {
+			pairs = append(pairs, deduplication.ScanPair{Older: issue.ID, Newer: other.ID, Confidence: 0.9})
+		}
+	}
+	return pairs, 1, nil
+}
+
+// TestDedupScan verifies a scan resumes from its saved cursor and that
+// merging closes the newer duplicates with a comment
+func TestDedupScan(t *testing.T) {
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+	for _, title := range []string{"Parser crash", "Slow build", "Parser crash", "Docs"} {
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeBug}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue: %v", err)
+		}
+	}
+
+	filter := deduplication.ScanFilter{Type: "bug"}
+	selected, err := selectScanIssues(ctx, store, filter, time.Now())
+	if err != nil || len(selected) != 4 {
+		t.Fatalf("selectScanIssues: %d issues, %v", len(selected), err)
+	}
+	state := deduplication.NewScanState(filter, selected)
+	issues, err := loadScanIssues(ctx, store, state.IssueIDs)
+	if err != nil {
+		t.Fatalf("loadScanIssues: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), dedupScanStateFile)
+	scanner := &fakeScanner{}
+	var progress bytes.Buffer
+	if err := runDedupScan(ctx, scanner, state, issues, path, 1, &progress); err != nil {
+		t.Fatalf("runDedupScan: %v", err)
+	}
+	if state.Done() || state.Next != 1 || scanner.calls != 1 {
+		t.Fatalf("Expected the scan to stop after 1 issue, at %d with %d calls", state.Next, scanner.calls)
+	}
+
+	resumed, err := loadScanStateFor(path, filter, false)
+	if err != nil || resumed == nil || resumed.Next != 1 {
+		t.Fatalf("Expected to resume at 1, got %+v, %v", resumed, err)
+	}
+	if other, _ := loadScanStateFor(path, deduplication.ScanFilter{Label: "x"}, false); other != nil {
+		t.Error("A scan with other filters must not resume")
+	}
+	if err := runDedupScan(ctx, scanner, resumed, issues, path, 0, &progress); err != nil {
+		t.Fatalf("runDedupScan: %v", err)
+	}
+	if !resumed.Done() || scanner.calls != 3 {
+		t.Errorf("Expected the scan done with 3 calls, got next %d, %d calls", resumed.Next, scanner.calls)
+	}
+
+	groups := resumed.Groups(0.85)
+	if len(groups) != 1 || len(groups[0].Duplicates) != 1 {
+		t.Fatalf("Expected one pair of duplicates, got %+v", groups)
+	}
+	survivor, duplicate := groups[0].Survivor, groups[0].Duplicates[0]
+	if issues[survivor].CreatedAt.After(issues[duplicate].CreatedAt) {
+		t.Error("The older issue should survive")
+	}
+
+	var out bytes.Buffer
+	writeDuplicateGroups(&out, resumed, groups, issues, 0.85)
+	if !strings.Contains(out.String(), "keep   "+survivor) || !strings.Contains(out.String(), "dup    "+duplicate) {
+		t.Errorf("Unexpected groups output:\n%s", out.String())
+	}
+
+	if added, err := linkDuplicateGroup(ctx, store, groups[0], "test"); err != nil || added != 1 {
+		t.Fatalf("linkDuplicateGroup: %d, %v", added, err)
+	}
+	if added, _ := linkDuplicateGroup(ctx, store, groups[0], "test"); added != 0 {
+		t.Error("Linking twice should add nothing")
+	}
+
+	out.Reset()
+	if err := mergeDuplicateGroups(ctx, store, groups, issues, "test", strings.NewReader("y\n"), &out); err != nil {
+		t.Fatalf("mergeDuplicateGroups: %v", err)
+	}
+	closed, _ := store.GetIssue(ctx, duplicate)
+	if closed.Status != types.StatusClosed {
+		t.Errorf("Expected %s closed, got %s", duplicate, closed.Status)
+	}
+	eventList, _ := store.GetEvents(ctx, duplicate, 0)
+	commented := false
+	for _, event := range eventList {
+		if event.EventType == types.EventCommented && event.Comment != nil && strings.Contains(*event.Comment, "Duplicate of "+survivor) {
+			commented = true
+		}
+	}
+	if !commented {
+		t.Errorf("Expected a comment referencing %s", survivor)
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/deduplication"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestDedupPreview(t *testing.T) {
+	cfg := deduplication.DefaultConfig()
+	matches := []deduplication.RankedMatch{
+		{Issue: &types.Issue{ID: "vc-2", Title: "Fix parser crash", Status: types.StatusOpen}, Confidence: 0.9, IsDuplicate: false},
+		{Issue: &types.Issue{ID: "vc-1", Title: "Parser crashes on empty input", Status: types.StatusOpen}, Confidence: 0.88, IsDuplicate: true},
+		{Issue: &types.Issue{ID: "vc-3", Title: "Add docs", Status: types.StatusOpen}, Confidence: 0.1},
+	}
+	preview := newDedupPreview(cfg, "", matches)
+	// The AI's judgment is honored: a distinct high score doesn't count
+	if preview.DuplicateOf != "vc-1" {
+		t.Errorf("Expected vc-1 as the duplicate, got %q", preview.DuplicateOf)
+	}
+
+	var out bytes.Buffer
+	if err := writeDedupPreviewTable(&out, preview); err != nil {
+		t.Fatalf("writeDedupPreviewTable: %v", err)
+	}
+	for _, want := range []string{"Threshold 0.85, scope all, statuses open: 3 issues compared", "0.88 *", "Would be dropped as a duplicate of vc-1"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q in:\n%s", want, out.String())
+		}
+	}
+
+	cfg.ConfidenceThreshold = 0.95
+	out.Reset()
+	if err := writeDedupPreviewTable(&out, newDedupPreview(cfg, "vc-9", matches)); err != nil {
+		t.Fatalf("writeDedupPreviewTable: %v", err)
+	}
+	if !strings.Contains(out.String(), "Would be filed: best match vc-2 scored 0.90") {
+		t.Errorf("Expected the issue to be filed above the threshold:\n%s", out.String())
+	}
+}
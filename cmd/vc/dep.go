@@ -3,10 +3,14 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/ai"
+	"github.com/steveyegge/vc/internal/storage"
 	"github.com/steveyegge/vc/internal/types"
 )
 
@@ -18,9 +22,20 @@ var depCmd = &cobra.Command{
 var depAddCmd = &cobra.Command{
 	Use:   "add [issue-id] [depends-on-id]",
 	Short: "Add a dependency",
-	Args:  cobra.ExactArgs(2),
+	Long: `Add a dependency: issue-id depends on depends-on-id.
+
+Only 'blocks' dependencies keep issue-id out of 'vc ready' until
+depends-on-id is closed. The other types record a relationship:
+  parent-child     issue-id is a child of depends-on-id (e.g. an epic's task)
+  discovered-from  issue-id was found while working on depends-on-id
+  related          the issues are related`,
+	Args: cobra.ExactArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
 		depType, _ := cmd.Flags().GetString("type")
+		if !types.DependencyType(depType).IsValid() {
+			fmt.Fprintf(os.Stderr, "Error: invalid dependency type %q (must be blocks, related, parent-child, or discovered-from)\n", depType)
+			os.Exit(1)
+		}
 
 		dep := &types.Dependency{
 			IssueID:     args[0],
@@ -79,16 +94,11 @@ var depTreeCmd = &cobra.Command{
 
 		hasTruncation := false
 		for _, node := range tree {
-			indent := ""
-			for i := 0; i < node.Depth; i++ {
-				indent += "  "
-			}
-			fmt.Printf("%s→ %s: %s [P%d] (%s)\n",
-				indent, node.ID, node.Title, node.Priority, node.Status)
 			if node.Truncated {
 				hasTruncation = true
 			}
 		}
+		printDependencyTree(os.Stdout, tree)
 
 		if hasTruncation {
 			yellow := color.New(color.FgYellow).SprintFunc()
@@ -128,8 +138,147 @@ var depCyclesCmd = &cobra.Command{
 	},
 }
 
+// printDependencyTree prints each node under the issue that depends on it.
+// Blocking links are drawn with →; other links with ⋯ and their type, since
+// they don't hold anything up. Nodes whose parent isn't in the tree (it was
+// archived) are listed at the end, and nodes of another project than the
+// root are flagged.
+func printDependencyTree(w io.Writer, tree []*types.TreeNode) {
+	faint := color.New(color.Faint).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	children := make(map[string][]*types.TreeNode)
+	inTree := make(map[string]bool, len(tree))
+	for _, node := range tree {
+		inTree[node.ID] = true
+	}
+	var orphans []*types.TreeNode
+	rootProject := ""
+	for _, node := range tree {
+		switch {
+		case node.Depth == 0:
+			rootProject = node.Project
+		case inTree[node.ParentID]:
+			children[node.ParentID] = append(children[node.ParentID], node)
+		default:
+			orphans = append(orphans, node)
+		}
+	}
+
+	var printNode func(node *types.TreeNode, level int)
+	printNode = func(node *types.TreeNode, level int) {
+		indent := strings.Repeat("  ", level)
+		line := fmt.Sprintf("%s: %s [P%d] (%s)", node.ID, node.Title, node.Priority, node.Status)
+		if node.Project != rootProject {
+			line += " " + yellow(fmt.Sprintf("[cross-project: %s]", node.Project))
+		}
+		if node.Depth == 0 || node.DependencyType.IsBlocking() {
+			fmt.Fprintf(w, "%s→ %s\n", indent, line)
+		} else {
+			fmt.Fprintf(w, "%s%s\n", indent, faint(fmt.Sprintf("⋯ %s [%s]", line, node.DependencyType)))
+		}
+		for _, child := range children[node.ID] {
+			printNode(child, level+1)
+		}
+	}
+	for _, node := range tree {
+		if node.Depth == 0 {
+			printNode(node, 0)
+		}
+	}
+	for _, node := range orphans {
+		printNode(node, node.Depth)
+	}
+}
+
+// dependencyLinkSections names the sections of 'vc show' for each link type,
+// as seen from the issue that depends (outgoing) and the one depended on
+// (incoming)
+var dependencyLinkSections = []struct {
+	depType  types.DependencyType
+	outgoing string
+	incoming string
+}{
+	{types.DepBlocks, "Depends on", "Blocks"},
+	{types.DepParentChild, "Parent", "Children"},
+	{types.DepDiscoveredFrom, "Discovered from", "Discovered"},
+	{types.DepRelated, "Related to", "Related from"},
+}
+
+// printIssueLinks prints an issue's dependencies and dependents for 'vc show',
+// grouped by dependency type
+func printIssueLinks(ctx context.Context, w io.Writer, s storage.Storage, issueID string) {
+	outgoing := make(map[types.DependencyType][]*types.Issue)
+	if records, err := s.GetDependencyRecords(ctx, issueID); err == nil {
+		typeOf := make(map[string]types.DependencyType, len(records))
+		for _, record := range records {
+			typeOf[record.DependsOnID] = record.Type
+		}
+		deps, _ := s.GetDependencies(ctx, issueID)
+		for _, dep := range deps {
+			outgoing[typeOf[dep.ID]] = append(outgoing[typeOf[dep.ID]], dep)
+		}
+	}
+
+	incoming := make(map[types.DependencyType][]*types.Issue)
+	dependents, _ := s.GetDependents(ctx, issueID)
+	for _, dependent := range dependents {
+		depType := types.DepBlocks
+		if records, err := s.GetDependencyRecords(ctx, dependent.ID); err == nil {
+			for _, record := range records {
+				if record.DependsOnID == issueID {
+					depType = record.Type
+				}
+			}
+		}
+		incoming[depType] = append(incoming[depType], dependent)
+	}
+
+	for _, section := range dependencyLinkSections {
+		if issues := outgoing[section.depType]; len(issues) > 0 {
+			fmt.Fprintf(w, "\n%s (%d):\n", section.outgoing, len(issues))
+			for _, dep := range issues {
+				fmt.Fprintf(w, "  → %s: %s [P%d]\n", dep.ID, dep.Title, dep.Priority)
+			}
+		}
+	}
+	for _, section := range dependencyLinkSections {
+		if issues := incoming[section.depType]; len(issues) > 0 {
+			fmt.Fprintf(w, "\n%s (%d):\n", section.incoming, len(issues))
+			for _, dep := range issues {
+				fmt.Fprintf(w, "  ← %s: %s [P%d]\n", dep.ID, dep.Title, dep.Priority)
+			}
+		}
+	}
+}
+
+// discoveryOrigin describes where 'vc show' says an issue was discovered,
+// e.g. "vc-123 (attempt 4)": its discovered-from issue and that issue's
+// execution attempt running when it was created. Empty if it has no origin.
+func discoveryOrigin(ctx context.Context, s storage.Storage, issue *types.Issue) string {
+	records, err := s.GetDependencyRecords(ctx, issue.ID)
+	if err != nil {
+		return ""
+	}
+	for _, record := range records {
+		if record.Type != types.DepDiscoveredFrom {
+			continue
+		}
+		origin := ai.DiscoveryOrigin{}
+		history, _ := s.GetExecutionHistory(ctx, record.DependsOnID)
+		for _, attempt := range history {
+			if attempt.StartedAt.After(issue.CreatedAt) {
+				break
+			}
+			origin.Attempt = attempt.AttemptNumber
+		}
+		return origin.Describe(record.DependsOnID)
+	}
+	return ""
+}
+
 func init() {
-	depAddCmd.Flags().StringP("type", "t", "blocks", "Dependency type (blocks|related|parent-child)")
+	depAddCmd.Flags().StringP("type", "t", "blocks", "Dependency type (blocks|related|parent-child|discovered-from)")
 	depCmd.AddCommand(depAddCmd)
 	depCmd.AddCommand(depRemoveCmd)
 	depCmd.AddCommand(depTreeCmd)
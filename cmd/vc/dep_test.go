@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/storage/storagetest"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestPrintDependencyTree(t *testing.T) {
+	node := func(id string, depth int, parentID string, depType types.DependencyType) *types.TreeNode {
+		return &types.TreeNode{
+			Issue:          types.Issue{ID: id, Title: "Issue " + id, Priority: 2, Status: types.StatusOpen},
+			Depth:          depth,
+			ParentID:       parentID,
+			DependencyType: depType,
+		}
+	}
+	// Beads returns nodes by depth; the output follows the links
+	tree := []*types.TreeNode{
+		node("vc-1", 0, "", ""),
+		node("vc-2", 1, "vc-1", types.DepBlocks),
+		node("vc-3", 1, "vc-1", types.DepDiscoveredFrom),
+		node("vc-4", 2, "vc-3", types.DepBlocks),
+		node("vc-5", 2, "vc-9", types.DepBlocks), // parent was archived
+	}
+	tree[3].Project = "web"
+
+	var buf bytes.Buffer
+	printDependencyTree(&buf, tree)
+
+	want := []string{
+		"→ vc-1: Issue vc-1 [P2] (open)",
+		"  → vc-2: Issue vc-2 [P2] (open)",
+		"  ⋯ vc-3: Issue vc-3 [P2] (open) [discovered-from]",
+		"    → vc-4: Issue vc-4 [P2] (open) [cross-project: web]",
+		"    → vc-5: Issue vc-5 [P2] (open)",
+	}
+	if got := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n"); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected tree:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestDiscoveryOrigin(t *testing.T) {
+	ctx := context.Background()
+	s := storagetest.NewFakeStorage()
+	origin := &types.Issue{Title: "Origin", Status: types.StatusInProgress, Priority: 2, IssueType: types.TypeTask}
+	if err := s.CreateIssue(ctx, origin, "test"); err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+	// Attempts 1 and 2 started before the discovered issue, 3 after it
+	now := time.Now()
+	for i, started := range []time.Time{now.Add(-2 * time.Hour), now.Add(-time.Hour), now.Add(time.Hour)} {
+		attempt := &types.ExecutionAttempt{IssueID: origin.ID, ExecutorInstanceID: "exec-1", AttemptNumber: i + 1, StartedAt: started}
+		if err := s.RecordExecutionAttempt(ctx, attempt); err != nil {
+			t.Fatalf("RecordExecutionAttempt: %v", err)
+		}
+	}
+
+	discovered := &types.Issue{Title: "Discovered", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeBug}
+	dep := &types.Dependency{DependsOnID: origin.ID, Type: types.DepDiscoveredFrom}
+	if err := s.CreateIssueWithMetadata(ctx, discovered, nil, []*types.Dependency{dep}, "test"); err != nil {
+		t.Fatalf("CreateIssueWithMetadata: %v", err)
+	}
+
+	if got, want := discoveryOrigin(ctx, s, discovered), origin.ID+" (attempt 2)"; got != want {
+		t.Errorf("discoveryOrigin() = %q, want %q", got, want)
+	}
+	if got := discoveryOrigin(ctx, s, origin); got != "" {
+		t.Errorf("Expected no origin for an issue that wasn't discovered, got %q", got)
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -12,6 +13,7 @@ import (
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/storage/beads"
 	"github.com/steveyegge/vc/internal/types"
 )
 
@@ -22,10 +24,13 @@ var doctorCmd = &cobra.Command{
 
 This command checks for:
 - Database existence and accessibility
+- Database schema version (refuses databases from a newer vc)
+- Issues with invalid fields (written before storage validated them)
+- Beads library compatibility (refuses databases vc's Beads can't handle)
 - Database staleness (sync with issues.jsonl)
 - WAL mode timestamp sync issues
 - Beads daemon conflicts
-- Required environment variables
+- AI provider credentials and model access
 - Git repository status
 - Sandbox directory permissions
 
@@ -50,19 +55,14 @@ Exit codes:
 
 		// Check 1: Database discovery
 		fmt.Printf("%s Database discovery\n", cyan("→"))
-		if dbPath == "" {
-			if discoveredPath, err := storage.DiscoverDatabase(); err != nil {
-				criticalFailures = append(criticalFailures, fmt.Sprintf("No database found: %v", err))
-				fmt.Printf("  %s No database found\n", red("✗"))
-				if verbose {
-					fmt.Printf("    Error: %v\n", err)
-				}
-			} else {
-				dbPath = discoveredPath
-				fmt.Printf("  %s Found database: %s\n", green("✓"), dbPath)
+		if choice, err := resolveDatabase(); err != nil {
+			criticalFailures = append(criticalFailures, fmt.Sprintf("No database found: %v", err))
+			fmt.Printf("  %s No database found\n", red("✗"))
+			if verbose {
+				fmt.Printf("    Error: %v\n", err)
 			}
 		} else {
-			fmt.Printf("  %s Using explicit database: %s\n", green("✓"), dbPath)
+			fmt.Printf("  %s Using database: %s (%s)\n", green("✓"), choice.Path, choice.Reason())
 		}
 
 		if dbPath == "" {
@@ -170,20 +170,7 @@ Exit codes:
 			fmt.Printf("  %s No beads daemon detected\n", green("✓"))
 		}
 
-		// Check 7: Required environment variables
-		fmt.Printf("%s Environment variables\n", cyan("→"))
-		if apiKey := os.Getenv("ANTHROPIC_API_KEY"); apiKey == "" {
-			failures = append(failures, "ANTHROPIC_API_KEY not set")
-			fmt.Printf("  %s ANTHROPIC_API_KEY not set\n", red("✗"))
-			fmt.Printf("    AI supervision features will not work\n")
-		} else {
-			fmt.Printf("  %s ANTHROPIC_API_KEY is set\n", green("✓"))
-			if verbose {
-				fmt.Printf("    Key: %s...%s\n", apiKey[:10], apiKey[len(apiKey)-4:])
-			}
-		}
-
-		// Check 8: Git repository status
+		// Check 7: Git repository status
 		fmt.Printf("%s Git repository\n", cyan("→"))
 		if projectRoot != "" {
 			gitDir := filepath.Join(projectRoot, ".git")
@@ -216,7 +203,7 @@ Exit codes:
 			}
 		}
 
-		// Check 9: Sandbox directory
+		// Check 8: Sandbox directory
 		fmt.Printf("%s Sandbox directory\n", cyan("→"))
 		if projectRoot != "" {
 			sandboxRoot := filepath.Join(projectRoot, ".sandboxes")
@@ -251,41 +238,123 @@ Exit codes:
 			}
 		}
 
-		// Check 10: Database issue count
-		fmt.Printf("%s Database statistics\n", cyan("→"))
+		// Check 9: Beads library compatibility
+		fmt.Printf("%s Beads compatibility\n", cyan("→"))
+		if projectRoot != "" {
+			if compat, err := beads.CheckBeadsCompat(context.Background(), dbPath); err != nil {
+				warnings = append(warnings, fmt.Sprintf("Cannot check Beads compatibility: %v", err))
+				fmt.Printf("  %s Cannot check Beads compatibility\n", yellow("⚠"))
+			} else if !compat.Compatible() {
+				criticalFailures = append(criticalFailures, fmt.Sprintf(
+					"Database is incompatible with this vc's Beads library (%s); see \"Beads Compatibility\" in docs/CONFIGURATION.md",
+					beads.BeadsLibraryVersion()))
+				fmt.Printf("  %s Database is incompatible with Beads %s:\n", red("✗"), beads.BeadsLibraryVersion())
+				for _, problem := range compat.Problems {
+					fmt.Printf("    %s\n", problem)
+				}
+			} else {
+				fmt.Printf("  %s Compatible with Beads %s\n", green("✓"), beads.BeadsLibraryVersion())
+				if verbose && compat.Version != "" {
+					fmt.Printf("    Last opened with Beads %s (compatibility level %d, this vc: %d)\n",
+						compat.Version, compat.Level, beads.BeadsCompatLevel)
+				}
+			}
+		}
+
+		// Check 10: Schema version, and opening the database
+		fmt.Printf("%s Schema version\n", cyan("→"))
+		var dbStore storage.Storage
 		if projectRoot != "" {
-			// Try to connect and get basic stats
 			cfg := storage.DefaultConfig()
 			cfg.Path = dbPath
 			ctx := context.Background()
-			if store, err := storage.NewStorage(ctx, cfg); err == nil {
-				// Get issue count using SearchIssues with empty query
-				issues, err := store.SearchIssues(ctx, "", types.IssueFilter{})
-				if err != nil {
-					warnings = append(warnings, fmt.Sprintf("Cannot query issues: %v", err))
-					fmt.Printf("  %s Cannot query database\n", yellow("⚠"))
-				} else {
-					fmt.Printf("  %s Database contains %d issue(s)\n", green("✓"), len(issues))
-
-					// Count by status
-					statusCounts := make(map[string]int)
-					for _, issue := range issues {
-						statusCounts[string(issue.Status)]++
-					}
-					if verbose && len(issues) > 0 {
-						for status, count := range statusCounts {
-							fmt.Printf("    %s: %d\n", status, count)
-						}
-					}
-				}
-				store.Close()
-			} else {
+			var versionErr *beads.SchemaVersionError
+			opened, err := storage.NewStorage(ctx, cfg)
+			switch {
+			case errors.As(err, &versionErr):
+				criticalFailures = append(criticalFailures, fmt.Sprintf(
+					"Database schema version %d is newer than this vc supports (%d); upgrade vc",
+					versionErr.DatabaseVersion, versionErr.SupportedVersion))
+				fmt.Printf("  %s Database is at schema version %d, this vc supports up to %d\n",
+					red("✗"), versionErr.DatabaseVersion, versionErr.SupportedVersion)
+			case errors.Is(err, beads.ErrBeadsIncompatible):
+				fmt.Printf("  %s Not checked: the database is incompatible (see above)\n", red("✗"))
+			case err != nil:
 				failures = append(failures, fmt.Sprintf("Cannot connect to database: %v", err))
 				fmt.Printf("  %s Cannot connect to database\n", red("✗"))
 				if verbose {
 					fmt.Printf("    Error: %v\n", err)
 				}
+			default:
+				dbStore = opened
+				if vcStore, ok := dbStore.(*beads.VCStorage); ok {
+					if version, err := vcStore.SchemaVersion(ctx); err != nil {
+						warnings = append(warnings, fmt.Sprintf("Cannot read schema version: %v", err))
+						fmt.Printf("  %s Cannot read schema version\n", yellow("⚠"))
+					} else {
+						fmt.Printf("  %s Schema version %d (this vc expects %d)\n", green("✓"), version, beads.LatestSchemaVersion())
+					}
+				}
+			}
+		}
+
+		// Check 11: Database issue count
+		fmt.Printf("%s Database statistics\n", cyan("→"))
+		if dbStore != nil {
+			ctx := context.Background()
+			// Get issue count using SearchIssues with empty query
+			issues, err := dbStore.SearchIssues(ctx, "", types.IssueFilter{})
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("Cannot query issues: %v", err))
+				fmt.Printf("  %s Cannot query database\n", yellow("⚠"))
+			} else {
+				fmt.Printf("  %s Database contains %d issue(s)\n", green("✓"), len(issues))
+
+				// Count by status
+				statusCounts := make(map[string]int)
+				for _, issue := range issues {
+					statusCounts[string(issue.Status)]++
+				}
+				if verbose && len(issues) > 0 {
+					for status, count := range statusCounts {
+						fmt.Printf("    %s: %d\n", status, count)
+					}
+				}
+
+				// Rows written before storage validated issues still
+				// read fine; report them so they can be fixed
+				if invalid := invalidIssues(issues); len(invalid) > 0 {
+					warnings = append(warnings, fmt.Sprintf("%d issue(s) have invalid fields (update them to fix)", len(invalid)))
+					fmt.Printf("  %s %d issue(s) have invalid fields\n", yellow("⚠"), len(invalid))
+					for _, line := range invalid {
+						fmt.Printf("    %s\n", line)
+					}
+				} else {
+					fmt.Printf("  %s All issues have valid fields\n", green("✓"))
+				}
+			}
+		}
+
+		// Check 12: AI provider credentials and model access, with the
+		// settings the executor uses
+		fmt.Printf("%s AI provider\n", cyan("→"))
+		if dbStore == nil {
+			warnings = append(warnings, "AI provider not checked (no database to read its settings from)")
+			fmt.Printf("  %s Skipped: no database\n", yellow("⚠"))
+		} else {
+			ctx := context.Background()
+			supervisor, err := newAISupervisor(ctx, dbStore)
+			if err == nil {
+				err = supervisor.Healthcheck(ctx)
 			}
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("AI provider unavailable: %v", err))
+				fmt.Printf("  %s %v\n", red("✗"), err)
+				fmt.Printf("    AI supervision features will not work\n")
+			} else {
+				fmt.Printf("  %s %s model %s is reachable\n", green("✓"), supervisor.Provider(), supervisor.Model())
+			}
+			dbStore.Close()
 		}
 
 		// Summary
@@ -340,6 +409,21 @@ func init() {
 }
 
 // isBeadsDaemonRunning checks if any bd daemon processes are running
+// invalidIssues returns one "id: field: message" line per invalid field of
+// the issues that fail types.Issue.Validate
+func invalidIssues(issues []*types.Issue) []string {
+	var lines []string
+	for _, issue := range issues {
+		var invalid *types.ValidationError
+		if err := issue.Validate(); errors.As(err, &invalid) {
+			for _, field := range invalid.Fields {
+				lines = append(lines, fmt.Sprintf("%s: %s", issue.ID, field))
+			}
+		}
+	}
+	return lines
+}
+
 func isBeadsDaemonRunning() bool {
 	cmd := exec.Command("pgrep", "-f", "bd daemon")
 	err := cmd.Run()
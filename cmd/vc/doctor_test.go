@@ -0,0 +1,33 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestInvalidIssues(t *testing.T) {
+	issues := []*types.Issue{
+		{ID: "vc-1", Title: "Fine", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask},
+		{ID: "vc-2", Title: "Legacy", Status: types.StatusOpen, Priority: 7, IssueType: "chore-ish"},
+	}
+	lines := invalidIssues(issues)
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 invalid fields, got %v", lines)
+	}
+	if lines[0] != "vc-2: priority: must be between 0 and 4 (got 7)" || !strings.HasPrefix(lines[1], "vc-2: issue_type: ") {
+		t.Errorf("Unexpected lines: %v", lines)
+	}
+}
+
+func TestFormatIssueError(t *testing.T) {
+	err := (&types.Issue{Title: "x", Status: types.StatusOpen, Priority: 9, IssueType: types.TypeTask}).Validate()
+	if got := formatIssueError(err); got != "Error: invalid issue:\n  priority: must be between 0 and 4 (got 9)\n" {
+		t.Errorf("Unexpected validation output: %q", got)
+	}
+	if got := formatIssueError(errors.New("database is locked")); got != "Error: database is locked\n" {
+		t.Errorf("Unexpected output: %q", got)
+	}
+}
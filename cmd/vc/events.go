@@ -0,0 +1,222 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage"
+)
+
+var eventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "Search and export stored agent events",
+	Long: `Search the agent events recorded by the executor, or export them with
+"vc events export".
+
+--grep matches text anywhere in an event's message or data, ignoring case.
+Searches use the full-text index on agent events unless it has been turned
+off with "bd config set event_search_index false", in which case (and for
+terms shorter than three characters) the events are scanned instead.
+
+Examples:
+  vc events --grep "connection refused"      # Last 20 events mentioning it
+  vc events --grep timeout --issue vc-123    # Within one issue
+  vc events --grep panic --since 7d -n 100   # Up to 100 from the last week`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		filter, err := exportFilterFromFlags(cmd)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		eventList, err := store.GetAgentEvents(context.Background(), filter)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error fetching events: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(eventList) == 0 {
+			yellow := color.New(color.FgYellow).SprintFunc()
+			fmt.Printf("\n%s No events found matching the criteria\n\n", yellow("✨"))
+			return
+		}
+
+		cyan := color.New(color.FgCyan).SprintFunc()
+		if filter.MessageContains != "" {
+			fmt.Printf("\n%s Events matching %q (%d events):\n\n", cyan("🔍"), filter.MessageContains, len(eventList))
+		} else {
+			fmt.Printf("\n%s Recent events (%d events):\n\n", cyan("📋"), len(eventList))
+		}
+
+		// Newest last, so the list reads top to bottom
+		for i := len(eventList) - 1; i >= 0; i-- {
+			displayActivityEvent(eventList[i])
+		}
+		fmt.Println()
+	},
+}
+
+var eventsExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export agent events as JSON Lines",
+	Long: `Write agent events as JSON Lines (one JSON object per line), oldest first,
+for offline analysis in tools such as DuckDB or pandas.
+
+Each line holds the event's id, timestamp, issue_id, executor_id, agent_id,
+type, severity, message, data (as a JSON object) and source_line. Events are
+streamed straight from the database, so exports of any size run in constant
+memory. The number of exported events is printed to stderr.
+
+Examples:
+  vc events export > events.jsonl                    # Everything to stdout
+  vc events export --out events.jsonl.gz --gzip      # Compressed file
+  vc events export --issue vc-123 --type error       # Errors for one issue
+  vc events export --since 7d --executor <instance>  # One executor's week
+  vc events export --after 120000 -o new.jsonl       # Events after a cursor
+  vc events export --grep "rate limit" > hits.jsonl  # Events mentioning text`,
+	Run: func(cmd *cobra.Command, args []string) {
+		outPath, _ := cmd.Flags().GetString("out")
+		compress, _ := cmd.Flags().GetBool("gzip")
+
+		filter, err := exportFilterFromFlags(cmd)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		var out io.Writer = os.Stdout
+		var file *os.File
+		if outPath != "" && outPath != "-" {
+			file, err = os.Create(outPath)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error creating %s: %v\n", outPath, err)
+				os.Exit(1)
+			}
+			out = file
+		}
+
+		count, err := exportEvents(context.Background(), store, out, filter, compress)
+		if file != nil {
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				// Don't leave a truncated export behind
+				_ = os.Remove(outPath)
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error exporting events: %v\n", err)
+			os.Exit(1)
+		}
+
+		destination := "stdout"
+		if file != nil {
+			destination = outPath
+		}
+		fmt.Fprintf(os.Stderr, "Exported %d events to %s\n", count, destination)
+	},
+}
+
+func init() {
+	eventsCmd.Flags().StringP("grep", "g", "", "Only show events whose message or data contains this text (case-insensitive)")
+	eventsCmd.Flags().StringP("issue", "i", "", "Filter by issue ID")
+	eventsCmd.Flags().StringP("type", "t", "", "Filter by event type")
+	eventsCmd.Flags().StringP("severity", "s", "", "Filter by severity (info, warning, error, critical)")
+	eventsCmd.Flags().String("executor", "", "Filter by executor instance ID")
+	eventsCmd.Flags().String("agent", "", "Filter by agent ID")
+	eventsCmd.Flags().String("since", "", "Only show events newer than this (e.g., 2h, 24h, 7d)")
+	eventsCmd.Flags().IntP("limit", "n", 20, "Maximum number of events to show (0 = all)")
+
+	eventsExportCmd.Flags().StringP("out", "o", "", "Output file (default: stdout)")
+	eventsExportCmd.Flags().Bool("gzip", false, "Gzip-compress the output")
+	eventsExportCmd.Flags().StringP("issue", "i", "", "Filter by issue ID")
+	eventsExportCmd.Flags().StringP("type", "t", "", "Filter by event type")
+	eventsExportCmd.Flags().StringP("severity", "s", "", "Filter by severity (info, warning, error, critical)")
+	eventsExportCmd.Flags().String("executor", "", "Filter by executor instance ID")
+	eventsExportCmd.Flags().String("agent", "", "Filter by agent ID")
+	eventsExportCmd.Flags().String("since", "", "Only export events newer than this (e.g., 2h, 24h, 7d)")
+	eventsExportCmd.Flags().Int64("after", 0, "Only export events after this cursor (event id)")
+	eventsExportCmd.Flags().StringP("grep", "g", "", "Only export events whose message or data contains this text (case-insensitive)")
+	eventsExportCmd.Flags().IntP("limit", "n", 0, "Maximum number of events to export (0 = all)")
+
+	eventsCmd.AddCommand(eventsExportCmd)
+	rootCmd.AddCommand(eventsCmd)
+}
+
+// exportFilterFromFlags builds the event filter from the flags shared by the
+// events and export commands
+func exportFilterFromFlags(cmd *cobra.Command) (events.EventFilter, error) {
+	issueID, _ := cmd.Flags().GetString("issue")
+	eventType, _ := cmd.Flags().GetString("type")
+	severity, _ := cmd.Flags().GetString("severity")
+	executorID, _ := cmd.Flags().GetString("executor")
+	agentID, _ := cmd.Flags().GetString("agent")
+	sinceStr, _ := cmd.Flags().GetString("since")
+	afterID, _ := cmd.Flags().GetInt64("after")
+	grep, _ := cmd.Flags().GetString("grep")
+	limit, _ := cmd.Flags().GetInt("limit")
+
+	filter := events.EventFilter{
+		IssueID:         issueID,
+		Type:            events.EventType(eventType),
+		Severity:        events.EventSeverity(severity),
+		ExecutorID:      executorID,
+		AgentID:         agentID,
+		MessageContains: grep,
+		AfterID:         afterID,
+		Limit:           limit,
+	}
+	if sinceStr != "" {
+		since, err := parseSince(sinceStr)
+		if err != nil {
+			return filter, fmt.Errorf("invalid --since value: %w", err)
+		}
+		filter.AfterTime = time.Now().Add(-since)
+	}
+	return filter, nil
+}
+
+// exportEvents streams events matching filter to w as JSON Lines and returns
+// how many were written.
+func exportEvents(ctx context.Context, s storage.Storage, w io.Writer, filter events.EventFilter, compress bool) (int, error) {
+	var gz *gzip.Writer
+	if compress {
+		gz = gzip.NewWriter(w)
+		w = gz
+	}
+	buf := bufio.NewWriter(w)
+	encoder := json.NewEncoder(buf)
+
+	count := 0
+	err := s.StreamAgentEvents(ctx, filter, func(event *events.AgentEvent) error {
+		if err := encoder.Encode(event); err != nil {
+			return fmt.Errorf("failed to write event %s: %w", event.ID, err)
+		}
+		count++
+		return nil
+	})
+	if err != nil {
+		return count, err
+	}
+
+	if err := buf.Flush(); err != nil {
+		return count, fmt.Errorf("failed to write output: %w", err)
+	}
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			return count, fmt.Errorf("failed to finish gzip stream: %w", err)
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage"
+)
+
+func TestExportEvents(t *testing.T) {
+	ctx := context.Background()
+	testStore, err := storage.NewStorage(ctx, &storage.Config{Path: t.TempDir() + "/test.db"})
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer func() { _ = testStore.Close() }()
+
+	now := time.Now()
+	for i, e := range []*events.AgentEvent{
+		{Timestamp: now.Add(-2 * time.Minute), ExecutorID: "exec-1", AgentID: "agent-1", Type: events.EventTypeFileModified, Severity: events.SeverityInfo, Message: "edited", SourceLine: 12,
+			Data: map[string]interface{}{"file_path": "main.go", "operation": "modified"}},
+		{Timestamp: now.Add(-1 * time.Minute), ExecutorID: "exec-2", Type: events.EventTypeError, Severity: events.SeverityError, Message: "failed"},
+	} {
+		if err := testStore.StoreAgentEvent(ctx, e); err != nil {
+			t.Fatalf("Failed to store event %d: %v", i, err)
+		}
+	}
+
+	var out bytes.Buffer
+	count, err := exportEvents(ctx, testStore, &out, events.EventFilter{}, false)
+	if err != nil {
+		t.Fatalf("exportEvents failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if count != 2 || len(lines) != 2 {
+		t.Fatalf("Expected 2 exported lines, got count=%d lines=%d", count, len(lines))
+	}
+
+	// Oldest first, with data inlined as an object rather than a string
+	var first map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatalf("Invalid JSON line %q: %v", lines[0], err)
+	}
+	if first["message"] != "edited" || first["executor_id"] != "exec-1" || first["agent_id"] != "agent-1" || first["source_line"] != float64(12) {
+		t.Errorf("Unexpected first line: %v", first)
+	}
+	if data, ok := first["data"].(map[string]interface{}); !ok || data["file_path"] != "main.go" {
+		t.Errorf("Expected data as a JSON object, got %#v", first["data"])
+	}
+
+	// Filters and gzip output
+	var compressed bytes.Buffer
+	count, err = exportEvents(ctx, testStore, &compressed, events.EventFilter{ExecutorID: "exec-2"}, true)
+	if err != nil {
+		t.Fatalf("exportEvents failed: %v", err)
+	}
+	gz, err := gzip.NewReader(&compressed)
+	if err != nil {
+		t.Fatalf("Output is not gzip: %v", err)
+	}
+	plain, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatalf("Failed to read gzip output: %v", err)
+	}
+	if count != 1 || !strings.Contains(string(plain), `"message":"failed"`) {
+		t.Errorf("Expected only the exec-2 event, got count=%d output=%s", count, plain)
+	}
+}
+
+func TestEventsFilterFromFlags(t *testing.T) {
+	if err := eventsCmd.Flags().Parse([]string{"--grep", "connection refused", "--issue", "vc-1", "--since", "2h"}); err != nil {
+		t.Fatalf("Failed to parse flags: %v", err)
+	}
+	defer func() {
+		for _, name := range []string{"grep", "issue", "since"} {
+			_ = eventsCmd.Flags().Set(name, "")
+		}
+	}()
+
+	filter, err := exportFilterFromFlags(eventsCmd)
+	if err != nil {
+		t.Fatalf("exportFilterFromFlags failed: %v", err)
+	}
+	if filter.MessageContains != "connection refused" || filter.IssueID != "vc-1" || filter.Limit != 20 {
+		t.Errorf("Unexpected filter: %+v", filter)
+	}
+	if age := time.Since(filter.AfterTime); age < 119*time.Minute || age > 121*time.Minute {
+		t.Errorf("Expected --since 2h to start two hours ago, got %v", filter.AfterTime)
+	}
+}
@@ -5,15 +5,19 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/artifacts"
 	"github.com/steveyegge/vc/internal/config"
 	"github.com/steveyegge/vc/internal/deduplication"
 	"github.com/steveyegge/vc/internal/executor"
 	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/storage/beads"
 )
 
 var executeCmd = &cobra.Command{
@@ -27,7 +31,11 @@ The executor will:
 3. Atomically claim available issues
 4. Spawn coding agents (Claude Code) to execute the work
 5. Update issue status based on agent results
-6. Continue until stopped with Ctrl+C`,
+6. Continue until stopped with Ctrl+C
+
+The first Ctrl+C (or SIGTERM) stops claiming work and lets a running agent
+finish, for up to its remaining timeout plus executor.shutdown_grace. A
+second one stops the agent right away and releases its issue for a retry.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if err := runExecutor(cmd, args); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -40,12 +48,19 @@ The executor will:
 // This function returns errors instead of calling os.Exit(), which ensures that defer
 // statements (like lock cleanup) run properly on all error paths.
 func runExecutor(cmd *cobra.Command, args []string) error {
+	if err := applyConfigDefaults(cmd, executeConfigKeys); err != nil {
+		return fmt.Errorf("invalid config file: %w", err)
+	}
 	version, _ := cmd.Flags().GetString("version")
 	pollSeconds, _ := cmd.Flags().GetInt("poll-interval")
 	disableSandboxes, _ := cmd.Flags().GetBool("disable-sandboxes")
 	sandboxRoot, _ := cmd.Flags().GetString("sandbox-root")
 	parentRepo, _ := cmd.Flags().GetString("parent-repo")
 	enableAutoCommit, _ := cmd.Flags().GetBool("enable-auto-commit")
+	requireAI, _ := cmd.Flags().GetBool("require-ai")
+	offline, _ := cmd.Flags().GetBool("offline")
+	backupInterval, _ := cmd.Flags().GetDuration("backup-interval")
+	backupKeep, _ := cmd.Flags().GetInt("backup-keep")
 
 	// Check environment variable as fallback for auto-commit (vc-142)
 	if !enableAutoCommit {
@@ -87,8 +102,8 @@ func runExecutor(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Load deduplication configuration from environment
-	dedupConfig, err := deduplication.ConfigFromEnv()
+	// Load deduplication configuration from dedup.* settings and the environment
+	dedupConfig, err := deduplication.LoadConfig(context.Background(), store)
 	if err != nil {
 		return fmt.Errorf("invalid deduplication configuration: %w", err)
 	}
@@ -111,9 +126,39 @@ func runExecutor(cmd *cobra.Command, args []string) error {
 	cfg.InstanceCleanupAge = instanceCleanupConfig.CleanupAge() // vc-33: from environment
 	cfg.InstanceCleanupKeep = instanceCleanupConfig.CleanupKeep  // vc-33: from environment
 	cfg.EnableAutoCommit = enableAutoCommit // vc-142: expose auto-commit configuration
-	if pollSeconds > 0 {
+	cfg.RequireAI = requireAI
+	// Settings from `vc config set`; flags given explicitly below still win
+	applied, err := cfg.LoadSettings(context.Background(), store)
+	if err != nil {
+		return fmt.Errorf("invalid executor settings: %w", err)
+	}
+	if len(applied) > 0 {
+		fmt.Printf("Executor settings from config: %s\n", strings.Join(applied, ", "))
+	}
+	// Then the config files' executor settings, which win over the database
+	applied, err = cfg.LoadFileSettings(context.Background(), fileConfig)
+	if err != nil {
+		return fmt.Errorf("invalid executor settings: %w", err)
+	}
+	if len(applied) > 0 {
+		fmt.Printf("Executor settings from config files: %s\n", strings.Join(applied, ", "))
+	}
+	cfg.ArtifactsDir = resolveArtifactsDir(cfg.ArtifactsDir, projectRoot)
+	if backupInterval > 0 {
+		cfg.BackupDir = beads.DefaultBackupDir(dbPath)
+		cfg.BackupInterval = backupInterval
+		cfg.BackupRetention = backupKeep
+	}
+	if cmd.Flags().Changed("offline") {
+		cfg.Offline = offline
+	}
+	if pollSeconds > 0 && cmd.Flags().Changed("poll-interval") {
 		cfg.PollInterval = time.Duration(pollSeconds) * time.Second
 	}
+	cfg.Project, err = currentProject(context.Background(), store)
+	if err != nil {
+		return err
+	}
 
 	// Warn if sandboxes are disabled (vc-144)
 	if disableSandboxes {
@@ -143,8 +188,8 @@ func runExecutor(cmd *cobra.Command, args []string) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	// Handle signals for graceful shutdown
-	sigCh := make(chan os.Signal, 1)
+	// Handle signals: the first stops gracefully, a second immediately
+	sigCh := make(chan os.Signal, 2)
 	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
 
 	// Start executor in background
@@ -153,26 +198,32 @@ func runExecutor(cmd *cobra.Command, args []string) error {
 	}
 
 	cyan := color.New(color.FgCyan).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
 	fmt.Printf("%s Executor started (version %s)\n", green("✓"), cyan(version))
 	fmt.Printf("  Polling for ready work every %v\n", cfg.PollInterval)
+	if cfg.Project != "" {
+		fmt.Printf("  Project: %s\n", cfg.Project)
+	}
 	if cfg.EnableSandboxes {
 		fmt.Printf("  Sandboxes: %s (root: %s)\n", green("enabled"), cfg.SandboxRoot)
 	} else {
 		fmt.Printf("  Sandboxes: disabled\n")
 	}
+	if offline, reason := exec.Offline(); offline {
+		fmt.Printf("  Mode: %s (%s)\n", yellow("offline"), reason)
+	}
+	fmt.Printf("  Features: %s\n", executor.FormatFeatures(exec.Features()))
+	if cfg.ArtifactsDir != "" {
+		fmt.Printf("  Artifacts: %s\n", cfg.ArtifactsDir)
+	}
+	if cfg.BackupDir != "" {
+		fmt.Printf("  Backups: every %v to %s (keeping %d)\n", cfg.BackupInterval, cfg.BackupDir, cfg.BackupRetention)
+	}
 	fmt.Printf("  Press Ctrl+C to stop\n\n")
 
-	// Wait for shutdown signal
-	<-sigCh
-	fmt.Println("\n\nShutting down executor...")
-
-	// Stop the executor gracefully
-	// Use a fresh context for shutdown since main context is being canceled
-	cancel()
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer shutdownCancel()
-
-	if err := exec.Stop(shutdownCtx); err != nil {
+	// Wait for a shutdown signal and stop, waiting for a running agent up to
+	// its timeout plus executor.shutdown_grace
+	if err := exec.ShutdownOnSignal(sigCh, cancel); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: error during shutdown: %v\n", err)
 	}
 
@@ -180,12 +231,44 @@ func runExecutor(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// executeConfigKeys are the config file keys of vc execute's flag defaults
+var executeConfigKeys = map[string]string{
+	"backup-interval":    "executor.backup_interval",
+	"backup-keep":        "executor.backup_keep",
+	"disable-sandboxes":  "executor.disable_sandboxes",
+	"enable-auto-commit": "executor.enable_auto_commit",
+	"parent-repo":        "executor.parent_repo",
+	"require-ai":         "executor.require_ai",
+	"sandbox-root":       "executor.sandbox_root",
+}
+
+// resolveArtifactsDir turns the executor.artifacts_dir setting into the
+// directory attempt artifacts go to: empty is .beads/artifacts, "off" saves
+// none, and relative paths are under the project root
+func resolveArtifactsDir(setting, projectRoot string) string {
+	switch {
+	case setting == "":
+		return artifacts.DefaultDir(dbPath)
+	case setting == "off":
+		return ""
+	case filepath.IsAbs(setting):
+		return setting
+	default:
+		return filepath.Join(projectRoot, setting)
+	}
+}
+
 func init() {
 	executeCmd.Flags().String("version", "0.1.0", "Executor version")
 	executeCmd.Flags().IntP("poll-interval", "i", 5, "Poll interval in seconds")
 	executeCmd.Flags().Bool("disable-sandboxes", false, "Disable sandbox isolation (DANGEROUS: for development/testing only)")
 	executeCmd.Flags().String("sandbox-root", ".sandboxes", "Root directory for sandboxes")
 	executeCmd.Flags().String("parent-repo", ".", "Parent repository path")
+	executeCmd.Flags().Duration("backup-interval", 0, "Back up the database to .beads/backups this often, e.g. 6h (default: no backups)")
+	executeCmd.Flags().Int("backup-keep", 7, "Number of periodic backups to keep")
 	executeCmd.Flags().Bool("enable-auto-commit", false, "Enable automatic git commits after successful execution (can also use VC_ENABLE_AUTO_COMMIT=true)")
+	executeCmd.Flags().Bool("offline", false, "Run without AI supervision, dedup, watchdog AI analysis or health monitors (also executor.offline)")
+	executeCmd.Flags().String("config", "", "Config file to read instead of .vc/config.yaml (~/.config/vc/config.yaml is still merged underneath)")
+	executeCmd.Flags().Bool("require-ai", false, "Refuse to start if the AI provider fails its startup healthcheck (default: continue without AI supervision)")
 	rootCmd.AddCommand(executeCmd)
 }
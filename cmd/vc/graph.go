@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/graph"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+var graphCmd = &cobra.Command{
+	Use:   "graph [issue-id]",
+	Short: "Export the dependency graph as Graphviz DOT or Mermaid",
+	Long: `Render issues and their dependencies as a graph for planning reviews.
+
+Edges point from the issue depended on to the issue that depends on it
+(blocker to blocked, parent to child). Nodes are colored by status and
+shaped by type; edges are styled by dependency type:
+
+  blocks           bold (DOT), ==> (Mermaid)
+  parent-child     dashed, -->
+  discovered-from  dotted gray, -.->
+  related          dotted gray without arrow, -.-
+
+With an issue ID only its neighborhood is drawn: the issues within --radius
+links of it in either direction. --epic draws an epic and everything below
+it. --label and --status then narrow the graph further.
+
+Output is deterministic (nodes and edges sorted by ID), so renderings of the
+same backlog are identical and diffs between runs show real changes.`,
+	Example: `  vc graph | dot -Tsvg > backlog.svg
+  vc graph vc-123 --radius 2 --format mermaid
+  vc graph --epic vc-40 --collapse-closed --out epic.dot
+  vc graph --status open --status blocked --label frontend`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		format, _ := cmd.Flags().GetString("format")
+		outPath, _ := cmd.Flags().GetString("out")
+		opts, err := graphOptionsFromFlags(cmd, args)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		ctx := context.Background()
+		opts.Project = mustCurrentProject(ctx)
+		g, err := graph.Load(ctx, store, opts)
+		var tooLarge *graph.TooLargeError
+		if errors.As(err, &tooLarge) {
+			fmt.Fprintf(os.Stderr, "Error: %v\nNarrow it with an issue ID and --radius, --epic, --label or --status, or raise --max-nodes.\n", err)
+			os.Exit(1)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		var buf bytes.Buffer
+		if err := g.Write(&buf, format); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if outPath == "" || outPath == "-" {
+			_, _ = os.Stdout.Write(buf.Bytes())
+			return
+		}
+		if err := os.WriteFile(outPath, buf.Bytes(), 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", outPath, err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "Wrote %d issues and %d dependencies to %s\n", len(g.Nodes), len(g.Edges), outPath)
+	},
+}
+
+// graphOptionsFromFlags reads vc graph's scope flags
+func graphOptionsFromFlags(cmd *cobra.Command, args []string) (graph.Options, error) {
+	opts := graph.Options{}
+	opts.Labels, _ = cmd.Flags().GetStringSlice("label")
+	opts.Epic, _ = cmd.Flags().GetString("epic")
+	opts.Radius, _ = cmd.Flags().GetInt("radius")
+	opts.CollapseClosed, _ = cmd.Flags().GetBool("collapse-closed")
+	opts.MaxNodes, _ = cmd.Flags().GetInt("max-nodes")
+	statuses, _ := cmd.Flags().GetStringSlice("status")
+	for _, s := range statuses {
+		status := types.Status(s)
+		if !status.IsValid() {
+			return opts, fmt.Errorf("invalid status %q (must be open, in_progress, blocked or closed)", s)
+		}
+		opts.Statuses = append(opts.Statuses, status)
+	}
+	if len(args) > 0 {
+		opts.Focus = args[0]
+	} else if cmd.Flags().Changed("radius") {
+		return opts, fmt.Errorf("--radius needs an issue ID to focus on")
+	}
+	if opts.Radius < 1 {
+		return opts, fmt.Errorf("--radius must be at least 1")
+	}
+	return opts, nil
+}
+
+func init() {
+	graphCmd.Flags().StringP("format", "f", graph.FormatDOT, "Output format (dot|mermaid)")
+	graphCmd.Flags().StringP("out", "o", "", "Output file (default: stdout)")
+	graphCmd.Flags().StringSlice("label", nil, "Only issues with this label (repeatable; all must match)")
+	graphCmd.Flags().StringSlice("status", nil, "Only issues with this status (repeatable)")
+	graphCmd.Flags().String("epic", "", "Only this epic and the issues below it")
+	graphCmd.Flags().Int("radius", graph.DefaultRadius, "Links to follow from the focus issue")
+	graphCmd.Flags().Bool("collapse-closed", false, "Draw closed issues as one node")
+	graphCmd.Flags().Int("max-nodes", graph.DefaultMaxNodes, "Refuse to draw more nodes than this (0 = no limit)")
+	rootCmd.AddCommand(graphCmd)
+}
@@ -4,12 +4,16 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/vc/internal/ai"
+	"github.com/steveyegge/vc/internal/executor"
 	"github.com/steveyegge/vc/internal/health"
+	"github.com/steveyegge/vc/internal/ignore"
 	"github.com/steveyegge/vc/internal/storage"
 	"github.com/steveyegge/vc/internal/types"
 )
@@ -22,10 +26,12 @@ var healthCmd = &cobra.Command{
 Health monitors use AI to detect issues like:
 - Oversized files that should be split
 - Cruft files (backups, temp files, etc.)
+- Growing TODO/FIXME/HACK markers
 - ZFC violations (hardcoded thresholds, regex for semantic parsing, etc.)
 - Code duplication
 - High complexity
 - Missing tests
+- Declining test coverage
 
 All monitors are ZFC-compliant: they collect facts and defer judgment to AI.`,
 }
@@ -42,7 +48,9 @@ Examples:
   # Run specific monitor
   vc health check --monitor file-size
   vc health check --monitor cruft
+  vc health check --monitor todo
   vc health check --monitor zfc
+  vc health check --monitor coverage
 
   # Dry run (show issues without filing)
   vc health check --dry-run
@@ -64,12 +72,10 @@ Examples:
 		}
 
 		// Create AI supervisor for health monitors
-		supervisor, err := ai.NewSupervisor(&ai.Config{
-			Store: store,
-		})
+		supervisor, err := newAISupervisor(ctx, store)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: failed to create AI supervisor: %v\n", err)
-			fmt.Fprintf(os.Stderr, "Make sure ANTHROPIC_API_KEY is set in your environment\n")
+			fmt.Fprintf(os.Stderr, "Check the AI provider settings ('vc config list executor.ai_') and its API key\n")
 			os.Exit(1)
 		}
 
@@ -179,8 +185,201 @@ Examples:
 	},
 }
 
+var healthRunCmd = &cobra.Command{
+	Use:   "run [monitor-name]",
+	Short: "Run registered monitors now and show their findings",
+	Long: `Run the monitors the executor schedules, using the same health_monitors.yaml
+and health_state.json, and print their findings.
+
+Findings are only printed unless --create-issues is passed. Each run is recorded
+in health_state.json, so it shows up in 'vc health status'.
+
+Examples:
+  # Run every registered monitor
+  vc health run
+
+  # Run one monitor and file issues for its findings
+  vc health run todo_density_monitor --create-issues`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		createIssues, _ := cmd.Flags().GetBool("create-issues")
+
+		ctx := context.Background()
+
+		projectRoot, err := storage.GetProjectRoot(dbPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Without an AI supervisor, run the monitors that don't need one
+		opts := health.RegistryOptions{ProjectRoot: projectRoot}
+		if supervisor, err := newAISupervisor(ctx, store); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: no AI supervisor (%v); skipping the AI-backed monitors\n", err)
+			fmt.Fprintf(os.Stderr, "Check the AI provider settings ('vc config list executor.ai_') and its API key\n")
+			opts.WithoutAI = true
+		} else {
+			opts.Supervisor = supervisor
+		}
+		registry := loadHealthRegistry(opts)
+
+		// Without a name, run every monitor that isn't disabled in health_monitors.yaml
+		var names []string
+		for _, name := range registry.SortedMonitorNames() {
+			if registry.IsEnabled(name) {
+				names = append(names, name)
+			}
+		}
+		if len(args) == 1 {
+			if _, ok := registry.GetMonitor(args[0]); !ok {
+				fmt.Fprintf(os.Stderr, "Error: unknown monitor %q. Registered monitors: %s\n",
+					args[0], strings.Join(registry.SortedMonitorNames(), ", "))
+				if opts.WithoutAI {
+					fmt.Fprintf(os.Stderr, "AI-backed monitors are only registered with an AI supervisor\n")
+				}
+				os.Exit(1)
+			}
+			names = []string{args[0]}
+		}
+
+		green := color.New(color.FgGreen).SprintFunc()
+		yellow := color.New(color.FgYellow).SprintFunc()
+		cyan := color.New(color.FgCyan).SprintFunc()
+		red := color.New(color.FgRed).SprintFunc()
+
+		failed := 0
+		for _, name := range names {
+			monitor, _ := registry.GetMonitor(name)
+			fmt.Printf("%s %s\n", cyan("▶"), name)
+
+			result, err := registry.RunMonitor(ctx, name, health.CodebaseContext{})
+			if err != nil {
+				failed++
+				fmt.Printf("  %s Error: %v\n\n", red("✗"), err)
+				if recordErr := registry.RecordFailure(name, time.Now(), err); recordErr != nil {
+					fmt.Fprintf(os.Stderr, "Warning: failed to record run: %v\n", recordErr)
+				}
+				continue
+			}
+
+			for _, warning := range result.Warnings {
+				fmt.Printf("  %s %s\n", yellow("⚠"), warning)
+			}
+
+			var filed []string
+			if len(result.IssuesFound) == 0 {
+				fmt.Printf("  %s No issues found\n", green("✓"))
+			} else {
+				fmt.Printf("  %s Found %d issue(s)\n", yellow("!"), len(result.IssuesFound))
+				for _, discovered := range result.IssuesFound {
+					fmt.Printf("  - [%s] %s\n", discovered.Severity, buildIssueTitle(monitor, discovered))
+					if !createIssues {
+						continue
+					}
+					issueID, err := fileHealthIssue(ctx, store, monitor, discovered)
+					if err != nil {
+						fmt.Printf("    %s Failed to file issue: %v\n", red("✗"), err)
+						continue
+					}
+					filed = append(filed, issueID)
+					fmt.Printf("    %s Filed %s\n", green("✓"), issueID)
+				}
+			}
+
+			if err := registry.RecordRun(name, result, filed); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to record run: %v\n", err)
+			}
+			fmt.Println()
+		}
+
+		if !createIssues {
+			fmt.Printf("%s Findings were not filed (use --create-issues to file them)\n", yellow("ⓘ"))
+		}
+		if failed > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+var healthStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show when each monitor last ran and its open issues",
+	Long: `Render health_state.json: each registered monitor's schedule, last run,
+findings from that run, the last error if it failed, and the open issues it
+has filed.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		projectRoot, err := storage.GetProjectRoot(dbPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Inspecting state needs no AI calls
+		registry := loadHealthRegistry(health.RegistryOptions{ProjectRoot: projectRoot})
+
+		openIssues, err := openHealthIssues(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to load open health issues: %v\n", err)
+		}
+
+		cyan := color.New(color.FgCyan).SprintFunc()
+		yellow := color.New(color.FgYellow).SprintFunc()
+		red := color.New(color.FgRed).SprintFunc()
+		gray := color.New(color.FgHiBlack).SprintFunc()
+
+		fmt.Printf("\n%s Health Monitors:\n\n", cyan("⚕"))
+		now := time.Now()
+		for _, name := range registry.SortedMonitorNames() {
+			schedule, _ := registry.Schedule(name)
+			state, _ := registry.GetMonitorState(name)
+
+			scheduleDesc := describeSchedule(schedule)
+			if !registry.IsEnabled(name) {
+				scheduleDesc = "disabled"
+			}
+			fmt.Printf("%s  %s\n", cyan(name), gray(scheduleDesc))
+			if state == nil || state.LastRun.IsZero() {
+				fmt.Printf("  Last run: never\n")
+			} else {
+				fmt.Printf("  Last run: %s (%s ago)\n", state.LastRun.Format("2006-01-02 15:04"), now.Sub(state.LastRun).Round(time.Minute))
+				if state.LastError != "" {
+					fmt.Printf("  %s %s\n", red("Failed:"), state.LastError)
+				} else {
+					fmt.Printf("  Findings: %d (%d filed)\n", state.LastFindings, state.LastIssueCount)
+				}
+			}
+
+			issues := filterIssuesByMonitor(openIssues, name)
+			if len(issues) > 0 {
+				fmt.Printf("  %s\n", yellow(fmt.Sprintf("Open issues (%d):", len(issues))))
+				for _, issue := range issues {
+					fmt.Printf("    %s [P%d] %s\n", issue.ID, issue.Priority, issue.Title)
+				}
+			}
+			fmt.Println()
+		}
+	},
+}
+
+// newAISupervisor creates an AI supervisor with the executor's AI provider
+// settings, so health monitors use the same provider and model
+func newAISupervisor(ctx context.Context, store storage.Storage) (*ai.Supervisor, error) {
+	cfg := executor.DefaultConfig()
+	cfg.Store = store
+	if _, err := cfg.LoadSettings(ctx, store); err != nil {
+		return nil, err
+	}
+	return ai.NewSupervisor(cfg.AIConfig())
+}
+
 func init() {
-	healthCheckCmd.Flags().StringP("monitor", "m", "", "Run specific monitor (file-size, cruft, zfc)")
+	healthRunCmd.Flags().Bool("create-issues", false, "File issues for findings (default: only print them)")
+	healthCmd.AddCommand(healthRunCmd)
+	healthCmd.AddCommand(healthStatusCmd)
+
+	healthCheckCmd.Flags().StringP("monitor", "m", "", "Run specific monitor (file-size, cruft, todo, zfc, coverage)")
 	healthCheckCmd.Flags().Bool("dry-run", false, "Show issues without filing")
 	healthCheckCmd.Flags().BoolP("verbose", "v", false, "Verbose output")
 
@@ -192,16 +391,64 @@ func init() {
 func createMonitors(projectRoot string, supervisor *ai.Supervisor, monitorName string) ([]health.HealthMonitor, error) {
 	var monitors []health.HealthMonitor
 
+	// The .vcignore is parsed once and shared by the monitors that scan files
+	matcher, err := ignore.Load(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
 	// Available monitors
 	allMonitors := map[string]func() (health.HealthMonitor, error){
 		"file-size": func() (health.HealthMonitor, error) {
-			return health.NewFileSizeMonitor(projectRoot, supervisor)
+			monitor, err := health.NewFileSizeMonitor(projectRoot, supervisor)
+			if err != nil {
+				return nil, err
+			}
+			monitor.Ignore = matcher
+			return monitor, nil
 		},
 		"cruft": func() (health.HealthMonitor, error) {
-			return health.NewCruftDetector(projectRoot, supervisor)
+			detector, err := health.NewCruftDetector(projectRoot, supervisor)
+			if err != nil {
+				return nil, err
+			}
+			detector.Ignore = matcher
+			return detector, nil
 		},
 		"zfc": func() (health.HealthMonitor, error) {
-			return health.NewZFCDetector(projectRoot, supervisor)
+			detector, err := health.NewZFCDetector(projectRoot, supervisor)
+			if err != nil {
+				return nil, err
+			}
+			detector.Ignore = matcher
+			return detector, nil
+		},
+		"todo": func() (health.HealthMonitor, error) {
+			registry, err := health.NewMonitorRegistry(filepath.Join(projectRoot, ".beads", "health_state.json"))
+			if err != nil {
+				return nil, err
+			}
+			monitor, err := health.NewTodoDensityMonitor(projectRoot, registry)
+			if err != nil {
+				return nil, err
+			}
+			monitor.Ignore = matcher
+			config, err := health.LoadConfig(filepath.Join(projectRoot, ".beads", "health_monitors.yaml"))
+			if err == nil {
+				if monitorConfig, ok := config.Monitors[monitor.Name()]; ok {
+					if err := monitor.ApplyConfig(monitorConfig); err != nil {
+						return nil, err
+					}
+				}
+			}
+			return monitor, nil
+		},
+		"coverage": func() (health.HealthMonitor, error) {
+			registry, err := health.NewMonitorRegistry(filepath.Join(projectRoot, ".beads", "health_state.json"))
+			if err != nil {
+				return nil, err
+			}
+			return health.NewCoverageMonitor(projectRoot, supervisor, registry)
 		},
 	}
 
@@ -225,7 +472,7 @@ func createMonitors(projectRoot string, supervisor *ai.Supervisor, monitorName s
 	} else {
 		// Create all monitors
 		// Order matters: run cheaper checks first
-		monitorOrder := []string{"file-size", "cruft", "zfc"}
+		monitorOrder := []string{"file-size", "cruft", "todo", "zfc", "coverage"}
 
 		for _, name := range monitorOrder {
 			createFn := allMonitors[name]
@@ -240,6 +487,72 @@ func createMonitors(projectRoot string, supervisor *ai.Supervisor, monitorName s
 	return monitors, nil
 }
 
+// loadHealthRegistry builds the same registry the executor uses, printing any
+// non-fatal setup warnings. Exits on failure.
+func loadHealthRegistry(opts health.RegistryOptions) *health.MonitorRegistry {
+	registry, warnings, err := health.NewDefaultRegistry(opts)
+	for _, warning := range warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", warning)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to load health registry: %v\n", err)
+		os.Exit(1)
+	}
+	return registry
+}
+
+// openHealthIssues returns non-closed issues filed by health monitors.
+func openHealthIssues(ctx context.Context) ([]*types.Issue, error) {
+	issues, err := store.SearchIssues(ctx, "", types.IssueFilter{Labels: []string{"health"}})
+	if err != nil {
+		return nil, err
+	}
+	var open []*types.Issue
+	for _, issue := range issues {
+		if issue.Status != types.StatusClosed {
+			open = append(open, issue)
+		}
+	}
+	return open, nil
+}
+
+// filterIssuesByMonitor returns the issues whose description names the monitor.
+// Health issues record their monitor as "**Monitor:** <name>".
+func filterIssuesByMonitor(issues []*types.Issue, monitorName string) []*types.Issue {
+	marker := fmt.Sprintf("**Monitor:** %s\n", monitorName)
+	var matched []*types.Issue
+	for _, issue := range issues {
+		if strings.Contains(issue.Description, marker) {
+			matched = append(matched, issue)
+		}
+	}
+	return matched
+}
+
+// describeSchedule renders a monitor schedule for status output.
+func describeSchedule(schedule health.ScheduleConfig) string {
+	var desc string
+	switch schedule.Type {
+	case health.ScheduleTimeBased:
+		desc = fmt.Sprintf("every %v", schedule.Interval)
+	case health.ScheduleEventBased:
+		desc = strings.ReplaceAll(schedule.EventTrigger, "_", " ")
+	case health.ScheduleHybrid:
+		desc = fmt.Sprintf("every %v-%v", schedule.MinInterval, schedule.MaxInterval)
+		if schedule.EventTrigger != "" {
+			desc += ", " + strings.ReplaceAll(schedule.EventTrigger, "_", " ")
+		}
+	case health.ScheduleManual:
+		desc = "manual"
+	default:
+		desc = string(schedule.Type)
+	}
+	if schedule.Window != nil {
+		desc += fmt.Sprintf(" (%s)", schedule.Window)
+	}
+	return desc
+}
+
 // fileHealthIssue creates an issue in the tracker for a discovered health problem.
 func fileHealthIssue(ctx context.Context, store storage.Storage, monitor health.HealthMonitor, discovered health.DiscoveredIssue) (string, error) {
 	// Build issue title and description
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/health"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestDescribeSchedule(t *testing.T) {
+	window, err := health.ParseTimeWindow("01:00-05:00")
+	if err != nil {
+		t.Fatalf("ParseTimeWindow failed: %v", err)
+	}
+
+	tests := []struct {
+		schedule health.ScheduleConfig
+		want     string
+	}{
+		{health.ScheduleConfig{Type: health.ScheduleTimeBased, Interval: 24 * time.Hour}, "every 24h0m0s"},
+		{health.ScheduleConfig{Type: health.ScheduleTimeBased, Interval: time.Hour, Window: window}, "every 1h0m0s (01:00-05:00)"},
+		{health.ScheduleConfig{Type: health.ScheduleEventBased, EventTrigger: "every_10_issues"}, "every 10 issues"},
+		{health.ScheduleConfig{Type: health.ScheduleManual}, "manual"},
+	}
+
+	for _, tt := range tests {
+		if got := describeSchedule(tt.schedule); got != tt.want {
+			t.Errorf("describeSchedule(%+v) = %q, want %q", tt.schedule, got, tt.want)
+		}
+	}
+}
+
+func TestFilterIssuesByMonitor(t *testing.T) {
+	issues := []*types.Issue{
+		{ID: "vc-1", Description: "## Health Monitor Finding\n\n**Monitor:** cruft_detector\n"},
+		{ID: "vc-2", Description: "## Health Monitor Finding\n\n**Monitor:** coverage_monitor\n"},
+		{ID: "vc-3", Description: "## Health Monitor Finding\n\n**Monitor:** cruft_detector_v2\n"},
+	}
+
+	got := filterIssuesByMonitor(issues, "cruft_detector")
+	if len(got) != 1 || got[0].ID != "vc-1" {
+		t.Errorf("Expected only vc-1, got %v", got)
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history [issue-id]",
+	Short: "Show an issue's execution attempts",
+	Long: `Show each attempt the executor made at an issue: when it ran, how it ended
+and its summary, followed by how long each attempt spent in each execution
+phase: assessment, sandbox, agent, analysis, gates and merge. Phases an
+attempt didn't reach show as "-".
+
+With --artifacts, list what each attempt left behind: the diff of its
+sandbox against the base branch (diff.patch), the full output of each
+quality gate (gate-<name>.log) and the agent's summary (summary.md). They
+are saved under .beads/artifacts/<issue>/<attempt>/ unless the
+executor.artifacts_dir setting says otherwise, and files the retention
+sweep (executor.artifacts_max_age, executor.artifacts_max_total_mb) has
+removed are listed as such.
+
+With --files, list the files each attempt changed, relative to the
+repository root. The executor uses them to predict which files a retry
+will touch, and defers claiming work that overlaps another executor's
+work in flight.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		history, err := store.GetExecutionHistory(context.Background(), args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			if history == nil {
+				history = []*types.ExecutionAttempt{}
+			}
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(history); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+		if len(history) == 0 {
+			fmt.Printf("%s has no execution attempts\n", args[0])
+			return
+		}
+		write := writeHistoryTable
+		if showArtifacts, _ := cmd.Flags().GetBool("artifacts"); showArtifacts {
+			write = writeArtifactList
+		} else if showFiles, _ := cmd.Flags().GetBool("files"); showFiles {
+			write = writeFileList
+		}
+		if err := write(os.Stdout, history); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// writeHistoryTable lists the attempts, oldest first, then their phase
+// timings
+func writeHistoryTable(w io.Writer, history []*types.ExecutionAttempt) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ATTEMPT\tSTARTED\tDURATION\tRESULT\tSUMMARY")
+	for _, attempt := range history {
+		duration := "-"
+		if attempt.CompletedAt != nil {
+			duration = attempt.CompletedAt.Sub(attempt.StartedAt).Round(time.Second).String()
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", attempt.AttemptNumber,
+			attempt.StartedAt.Local().Format("2006-01-02 15:04"), duration, attemptResult(attempt), attempt.Summary)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if slices.ContainsFunc(history, func(a *types.ExecutionAttempt) bool { return len(a.Phases) > 0 }) {
+		fmt.Fprintln(w)
+		return writePhaseBreakdown(w, history)
+	}
+	return nil
+}
+
+// attemptResult describes how an attempt ended
+func attemptResult(attempt *types.ExecutionAttempt) string {
+	switch {
+	case attempt.Success == nil:
+		return "running"
+	case *attempt.Success:
+		return "succeeded"
+	case attempt.ExitCode != nil:
+		return fmt.Sprintf("failed (exit %d)", *attempt.ExitCode)
+	default:
+		return "failed"
+	}
+}
+
+// writeArtifactList lists each attempt's artifacts with their sizes
+func writeArtifactList(w io.Writer, history []*types.ExecutionAttempt) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ATTEMPT\tSIZE\tPATH")
+	for _, attempt := range history {
+		if len(attempt.Artifacts) == 0 {
+			fmt.Fprintf(tw, "%d\t-\t(none saved)\n", attempt.AttemptNumber)
+			continue
+		}
+		for _, path := range attempt.Artifacts {
+			size := "removed"
+			if info, err := os.Stat(path); err == nil {
+				size = formatBytes(info.Size())
+			}
+			fmt.Fprintf(tw, "%d\t%s\t%s\n", attempt.AttemptNumber, size, path)
+		}
+	}
+	return tw.Flush()
+}
+
+// writeFileList lists the files each attempt changed
+func writeFileList(w io.Writer, history []*types.ExecutionAttempt) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ATTEMPT\tFILE")
+	for _, attempt := range history {
+		if len(attempt.Files) == 0 {
+			fmt.Fprintf(tw, "%d\t(none recorded)\n", attempt.AttemptNumber)
+			continue
+		}
+		for _, file := range attempt.Files {
+			fmt.Fprintf(tw, "%d\t%s\n", attempt.AttemptNumber, file)
+		}
+	}
+	return tw.Flush()
+}
+
+func init() {
+	historyCmd.Flags().Bool("artifacts", false, "List the artifacts saved for each attempt")
+	historyCmd.Flags().Bool("files", false, "List the files each attempt changed")
+	historyCmd.Flags().Bool("json", false, "Print the attempts as JSON")
+	rootCmd.AddCommand(historyCmd)
+}
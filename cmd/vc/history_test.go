@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestWriteHistoryTable(t *testing.T) {
+	start := time.Date(2025, 3, 1, 9, 0, 0, 0, time.Local)
+	done := start.Add(90 * time.Second)
+	failed, exitCode := false, 2
+	history := []*types.ExecutionAttempt{
+		{AttemptNumber: 1, StartedAt: start, CompletedAt: &done, Success: &failed, ExitCode: &exitCode, Summary: "quality gates failed"},
+		{AttemptNumber: 2, StartedAt: done},
+	}
+	var out bytes.Buffer
+	if err := writeHistoryTable(&out, history); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected a header and 2 attempts, got:\n%s", out.String())
+	}
+	if !strings.Contains(lines[1], "1m30s") || !strings.Contains(lines[1], "failed (exit 2)") || !strings.Contains(lines[1], "quality gates failed") {
+		t.Errorf("Unexpected first attempt: %q", lines[1])
+	}
+	if !strings.Contains(lines[2], "running") {
+		t.Errorf("Expected the second attempt running: %q", lines[2])
+	}
+}
+
+func TestWriteArtifactList(t *testing.T) {
+	dir := t.TempDir()
+	kept := filepath.Join(dir, "diff.patch")
+	if err := os.WriteFile(kept, []byte("diff"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	history := []*types.ExecutionAttempt{
+		{AttemptNumber: 1},
+		{AttemptNumber: 2, Artifacts: []string{kept, filepath.Join(dir, "gate-test.log")}},
+	}
+	var out bytes.Buffer
+	if err := writeArtifactList(&out, history); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 || !strings.Contains(lines[1], "(none saved)") ||
+		!strings.Contains(lines[2], "4 B") || !strings.HasSuffix(lines[2], kept) ||
+		!strings.Contains(lines[3], "removed") || !strings.HasSuffix(lines[3], "gate-test.log") {
+		t.Errorf("Unexpected artifact list:\n%s", out.String())
+	}
+}
+
+func TestWriteFileList(t *testing.T) {
+	history := []*types.ExecutionAttempt{
+		{AttemptNumber: 1},
+		{AttemptNumber: 2, Files: []string{"cmd/vc/main.go", "internal/parser/parse.go"}},
+	}
+	var out bytes.Buffer
+	if err := writeFileList(&out, history); err != nil {
+		t.Fatal(err)
+	}
+	want := "ATTEMPT  FILE\n1        (none recorded)\n2        cmd/vc/main.go\n2        internal/parser/parse.go\n"
+	if out.String() != want {
+		t.Errorf("writeFileList() = %q, want %q", out.String(), want)
+	}
+}
+
+func TestResolveArtifactsDir(t *testing.T) {
+	oldPath := dbPath
+	dbPath = filepath.Join("/work", ".beads", "vc.db")
+	defer func() { dbPath = oldPath }()
+	for setting, want := range map[string]string{
+		"":               filepath.Join("/work", ".beads", "artifacts"),
+		"off":            "",
+		"/var/artifacts": "/var/artifacts",
+		"out/artifacts":  filepath.Join("/work", "out", "artifacts"),
+	} {
+		if got := resolveArtifactsDir(setting, "/work"); got != want {
+			t.Errorf("resolveArtifactsDir(%q) = %q, want %q", setting, got, want)
+		}
+	}
+}
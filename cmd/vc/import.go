@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/github"
+	"github.com/steveyegge/vc/internal/jira"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import issues from other trackers",
+}
+
+var importGitHubCmd = &cobra.Command{
+	Use:   "github",
+	Short: "Import issues from a GitHub repository",
+	Long: `Import a GitHub repository's issues (not pull requests) as vc issues.
+
+Titles, bodies, labels and assignees carry over; bug, enhancement/feature and
+chore labels set the issue type and P0-P4 labels the priority. Each issue is
+labeled github:owner/name#N, and numbers already imported are skipped, so an
+interrupted or repeated import picks up where it left off. "Blocked by #N" in
+a body becomes a blocking dependency when both issues are imported.
+
+The token is read from GITHUB_TOKEN or GH_TOKEN.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		repo, _ := cmd.Flags().GetString("repo")
+		state, _ := cmd.Flags().GetString("state")
+		labels, _ := cmd.Flags().GetStringSlice("label")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		ctx := context.Background()
+
+		if state != "open" && state != "closed" && state != "all" {
+			fmt.Fprintf(os.Stderr, "Error: --state must be open, closed or all\n")
+			os.Exit(1)
+		}
+		importer, err := github.NewImporter(store, repo, actor)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		token := github.TokenFromEnv()
+		if token == "" {
+			fmt.Fprintf(os.Stderr, "Warning: no GITHUB_TOKEN or GH_TOKEN set; unauthenticated requests are limited to 60 per hour\n")
+		}
+
+		issues, err := github.NewClient("", token).ListIssues(ctx, repo, github.ListOptions{State: state, Labels: labels})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		plan, err := importer.Plan(ctx, issues)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if dryRun {
+			if err := writeImportPlan(os.Stdout, plan); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+		result, err := importer.Apply(ctx, plan)
+		if err != nil {
+			// Created issues are labeled, so a rerun resumes from here
+			fmt.Fprintf(os.Stderr, "Error: %v (imported %d issues before failing; rerun to resume)\n", err, len(result.Created))
+			os.Exit(1)
+		}
+		fmt.Printf("Imported %d issues from %s (%d already imported), added %d dependencies\n",
+			len(result.Created), repo, len(plan.Issues)-len(plan.New()), result.Dependencies)
+	},
+}
+
+var importJiraCmd = &cobra.Command{
+	Use:   "jira <file>",
+	Short: "Import issues from a Jira CSV or JSON export",
+	Long: `Import the issues of a Jira CSV or JSON export as vc issues.
+
+Summaries, descriptions, assignees and labels carry over. Issue types map
+Story to feature, Bug to bug, Task to task and Epic to epic, priority names
+map to 0-4, and done statuses close the issue. Sprints become sprint:<name>
+labels, and epic links, parents and "blocks" links become dependencies.
+Fields with no vc equivalent go into the issue's notes verbatim.
+
+Each issue is labeled jira:<key>; keys imported before are skipped, so an
+interrupted import can be rerun. The export is streamed and created in
+batches. Every mapping decision is written to the report file.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		path := args[0]
+		format, _ := cmd.Flags().GetString("format")
+		reportPath, _ := cmd.Flags().GetString("report")
+		batchSize, _ := cmd.Flags().GetInt("batch-size")
+
+		if format == "" {
+			var err error
+			if format, err = jira.DetectFormat(path); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if reportPath == "" {
+			reportPath = path + ".report.txt"
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer file.Close()
+		reader, err := jira.NewReader(file, format)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		report, err := os.Create(reportPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to create report: %v\n", err)
+			os.Exit(1)
+		}
+		defer report.Close()
+
+		result, err := jira.NewImporter(store, actor, report, batchSize).Import(context.Background(), reader)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v (created %d issues before failing; rerun to resume)\n", err, result.Created)
+			os.Exit(1)
+		}
+		fmt.Printf("Imported %d issues from %s (%d already imported, %d failed), added %d dependencies (%d links to issues not imported)\n",
+			result.Created, path, result.Skipped, result.Failed, result.Dependencies, result.Unresolved)
+		fmt.Printf("Mapping report: %s\n", reportPath)
+	},
+}
+
+// writeImportPlan renders what 'vc import github --dry-run' would do
+func writeImportPlan(w io.Writer, plan *github.ImportPlan) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "GITHUB\tACTION\tTYPE\tPRIORITY\tSTATUS\tASSIGNEE\tTITLE")
+	for _, planned := range plan.Issues {
+		action := "create"
+		if planned.ExistingID != "" {
+			action = "skip (" + planned.ExistingID + ")"
+		}
+		issue := planned.Issue
+		assignee := issue.Assignee
+		if assignee == "" {
+			assignee = "-"
+		}
+		fmt.Fprintf(tw, "#%d\t%s\t%s\tP%d\t%s\t%s\t%s\n",
+			planned.Number, action, issue.IssueType, issue.Priority, issue.Status, assignee, issue.Title)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	for _, dep := range plan.Dependencies {
+		fmt.Fprintf(w, "#%d blocked by #%d\n", dep.Number, dep.BlockedByNumber)
+	}
+	for _, dep := range plan.Unresolved {
+		fmt.Fprintf(w, "#%d blocked by #%d: not imported, skipped\n", dep.Number, dep.BlockedByNumber)
+	}
+	fmt.Fprintf(w, "\n%d to create, %d already imported, %d dependencies (dry run, nothing written)\n",
+		len(plan.New()), len(plan.Issues)-len(plan.New()), len(plan.Dependencies))
+	return nil
+}
+
+func init() {
+	importGitHubCmd.Flags().String("repo", "", "GitHub repository as owner/name (required)")
+	importGitHubCmd.Flags().String("state", "open", "Issues to import: open, closed or all")
+	importGitHubCmd.Flags().StringSlice("label", nil, "Only import issues with this label (repeatable)")
+	importGitHubCmd.Flags().Bool("dry-run", false, "Print the mapping without importing")
+	_ = importGitHubCmd.MarkFlagRequired("repo")
+	importCmd.AddCommand(importGitHubCmd)
+
+	importJiraCmd.Flags().String("format", "", "Export format: csv or json (default: from the file extension)")
+	importJiraCmd.Flags().String("report", "", "Mapping report file (default: <file>.report.txt)")
+	importJiraCmd.Flags().Int("batch-size", jira.DefaultBatchSize, "Issues created per transaction")
+	importCmd.AddCommand(importJiraCmd)
+	rootCmd.AddCommand(importCmd)
+}
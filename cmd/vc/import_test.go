@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/github"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestWriteImportPlan(t *testing.T) {
+	plan := &github.ImportPlan{
+		Repo: "acme/widgets",
+		Issues: []*github.PlannedIssue{
+			{Number: 1, Issue: &types.Issue{Title: "Crash", IssueType: types.TypeBug, Priority: 1, Status: types.StatusOpen, Assignee: "ada"}},
+			{Number: 2, ExistingID: "vc-7", Issue: &types.Issue{Title: "Cleanup", IssueType: types.TypeChore, Priority: 2, Status: types.StatusClosed}},
+		},
+		Dependencies: []github.PlannedDependency{{Number: 2, BlockedByNumber: 1}},
+		Unresolved:   []github.PlannedDependency{{Number: 1, BlockedByNumber: 40}},
+	}
+
+	var buf bytes.Buffer
+	if err := writeImportPlan(&buf, plan); err != nil {
+		t.Fatalf("writeImportPlan failed: %v", err)
+	}
+	lines := strings.Split(buf.String(), "\n")
+	if fields := strings.Join(strings.Fields(lines[1]), " "); fields != "#1 create bug P1 open ada Crash" {
+		t.Errorf("Unexpected #1 row: %q", lines[1])
+	}
+	if fields := strings.Join(strings.Fields(lines[2]), " "); fields != "#2 skip (vc-7) chore P2 closed - Cleanup" {
+		t.Errorf("Unexpected #2 row: %q", lines[2])
+	}
+	for _, want := range []string{"#2 blocked by #1\n", "#1 blocked by #40: not imported", "1 to create, 1 already imported, 1 dependencies"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Expected %q in:\n%s", want, buf.String())
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+var instancesCmd = &cobra.Command{
+	Use:   "instances",
+	Short: "List running executor instances",
+	Long: `List the executor instances registered as running, with their host, PID,
+heartbeat and mode. Offline executors (vc execute --offline, or started while
+the AI provider was unreachable) show why they run without AI; restart them
+to go back online.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		instances, err := store.GetActiveInstances(context.Background())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if len(instances) == 0 {
+			fmt.Println("No running executor instances")
+			return
+		}
+		if err := writeInstancesTable(os.Stdout, instances, time.Now()); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// writeInstancesTable renders one row per executor instance for 'vc instances'
+func writeInstancesTable(w io.Writer, instances []*types.ExecutorInstance, now time.Time) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "INSTANCE\tHOST\tPID\tVERSION\tUPTIME\tHEARTBEAT\tMODE")
+	for _, instance := range instances {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\t%s ago\t%s\n",
+			instance.InstanceID, instance.Hostname, instance.PID, instance.Version,
+			formatSeconds(now.Sub(instance.StartedAt).Seconds()),
+			formatSeconds(now.Sub(instance.LastHeartbeat).Seconds()),
+			instanceMode(instance.ParseMetadata()))
+	}
+	return tw.Flush()
+}
+
+// instanceMode describes whether an executor runs with AI, and if not, why
+func instanceMode(metadata types.InstanceMetadata) string {
+	if !metadata.Offline {
+		return "online"
+	}
+	if metadata.OfflineReason == "" {
+		return "offline"
+	}
+	return fmt.Sprintf("offline (%s)", metadata.OfflineReason)
+}
+
+func init() {
+	rootCmd.AddCommand(instancesCmd)
+}
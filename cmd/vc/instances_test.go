@@ -0,0 +1,35 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestWriteInstancesTable(t *testing.T) {
+	now := time.Now()
+	instances := []*types.ExecutorInstance{
+		{InstanceID: "exec-1", Hostname: "build-01", PID: 42, Version: "0.1.0",
+			StartedAt: now.Add(-2 * time.Hour), LastHeartbeat: now.Add(-10 * time.Second), Metadata: "{}"},
+		{InstanceID: "exec-2", Hostname: "laptop", PID: 7, Version: "0.1.0",
+			StartedAt: now.Add(-90 * time.Second), LastHeartbeat: now, Metadata: `{"offline":true,"offline_reason":"ai_unreachable"}`},
+	}
+
+	var buf bytes.Buffer
+	if err := writeInstancesTable(&buf, instances, now); err != nil {
+		t.Fatalf("writeInstancesTable failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "INSTANCE") {
+		t.Fatalf("Unexpected instances table:\n%s", buf.String())
+	}
+	if fields := strings.Join(strings.Fields(lines[1]), " "); fields != "exec-1 build-01 42 0.1.0 2h0m 10s ago online" {
+		t.Errorf("Unexpected exec-1 row: %q", lines[1])
+	}
+	if fields := strings.Join(strings.Fields(lines[2]), " "); fields != "exec-2 laptop 7 0.1.0 1m30s 0s ago offline (ai_unreachable)" {
+		t.Errorf("Unexpected exec-2 row: %q", lines[2])
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// issueGroup is one section of 'vc list --group-by'
+type issueGroup struct {
+	Key    string         `json:"key"`    // The group's value ("" for issues without one)
+	Count  int            `json:"count"`  // Matching issues in the group
+	Issues []*types.Issue `json:"issues"` // The first of them, in list order
+	More   int            `json:"more"`   // Matching issues not listed
+}
+
+// listGroups groups the issues matching query and filter by groupBy,
+// listing at most perGroup issues per group (0 = all). Counts come from
+// one GROUP BY, so they include issues past filter's Limit and Offset.
+func listGroups(ctx context.Context, s storage.Storage, query string, filter types.IssueFilter, groupBy string, perGroup int) ([]*issueGroup, error) {
+	counts, err := s.CountIssuesByGroup(ctx, query, filter, groupBy)
+	if err != nil {
+		return nil, err
+	}
+	issues, err := s.SearchIssues(ctx, query, filter)
+	if err != nil {
+		return nil, err
+	}
+	var labels map[string][]string
+	if groupBy == types.GroupByLabelPrefix {
+		labels = make(map[string][]string, len(issues))
+		for _, issue := range issues {
+			if labels[issue.ID], err = s.GetLabels(ctx, issue.ID); err != nil {
+				return nil, fmt.Errorf("failed to get labels of %s: %w", issue.ID, err)
+			}
+		}
+	}
+	return groupIssues(issues, groupBy, labels, counts, perGroup), nil
+}
+
+// groupIssues sorts issues into one group per key of counts. With
+// types.GroupByLabelPrefix an issue goes into the group of each of its label
+// prefixes, or into "" if it has none.
+func groupIssues(issues []*types.Issue, groupBy string, labels map[string][]string, counts map[string]int, perGroup int) []*issueGroup {
+	byKey := make(map[string]*issueGroup, len(counts))
+	var groups []*issueGroup
+	for key, count := range counts {
+		group := &issueGroup{Key: key, Count: count, Issues: []*types.Issue{}}
+		byKey[key] = group
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groupKeyLess(groupBy, groups[i].Key, groups[j].Key) })
+
+	for _, issue := range issues {
+		for _, key := range issueGroupKeys(issue, groupBy, labels[issue.ID]) {
+			group := byKey[key]
+			if group != nil && (perGroup <= 0 || len(group.Issues) < perGroup) {
+				group.Issues = append(group.Issues, issue)
+			}
+		}
+	}
+	for _, group := range groups {
+		group.More = max(group.Count-len(group.Issues), 0)
+	}
+	return groups
+}
+
+// issueGroupKeys returns the keys of the groups the issue belongs to
+func issueGroupKeys(issue *types.Issue, groupBy string, labels []string) []string {
+	switch groupBy {
+	case types.GroupByStatus:
+		return []string{string(issue.Status)}
+	case types.GroupByPriority:
+		return []string{strconv.Itoa(issue.Priority)}
+	case types.GroupByType:
+		return []string{string(issue.IssueType)}
+	case types.GroupByAssignee:
+		return []string{issue.Assignee}
+	}
+	var prefixes []string
+	for _, label := range labels {
+		if prefix := types.LabelPrefix(label); prefix != "" && !slices.Contains(prefixes, prefix) {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	if len(prefixes) == 0 {
+		return []string{""}
+	}
+	return prefixes
+}
+
+// statusOrder is the order of status groups, following an issue's life
+var statusOrder = []types.Status{types.StatusOpen, types.StatusInProgress, types.StatusBlocked, types.StatusClosed}
+
+// groupKeyLess orders groups: statuses in lifecycle order, priorities from
+// P0, other keys alphabetically with the group without a value last
+func groupKeyLess(groupBy, a, b string) bool {
+	if (a == "") != (b == "") {
+		return b == ""
+	}
+	switch groupBy {
+	case types.GroupByStatus:
+		ia, ib := slices.Index(statusOrder, types.Status(a)), slices.Index(statusOrder, types.Status(b))
+		if ia != ib {
+			return uint(ia) < uint(ib) // Unknown statuses (-1) last
+		}
+	case types.GroupByPriority:
+		pa, errA := strconv.Atoi(a)
+		pb, errB := strconv.Atoi(b)
+		if errA == nil && errB == nil {
+			return pa < pb
+		}
+	}
+	return a < b
+}
+
+// groupTitle renders a group's key for 'vc list'
+func groupTitle(groupBy, key string) string {
+	switch {
+	case key == "" && groupBy == types.GroupByAssignee:
+		return "(unassigned)"
+	case key == "" && groupBy == types.GroupByLabelPrefix:
+		return "(no prefixed label)"
+	case key == "":
+		return "(none)"
+	case groupBy == types.GroupByPriority:
+		return "P" + key
+	case groupBy == types.GroupByLabelPrefix:
+		return key + ":"
+	}
+	return key
+}
+
+// writeIssueGroups renders 'vc list --group-by', one line per issue
+func writeIssueGroups(w io.Writer, groupBy string, groups []*issueGroup) error {
+	var b strings.Builder
+	for i, group := range groups {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		fmt.Fprintf(&b, "%s (%d)\n", groupTitle(groupBy, group.Key), group.Count)
+		for _, issue := range group.Issues {
+			fmt.Fprintf(&b, "  %s [P%d] %s  %s\n", issue.ID, issue.Priority, issue.Status, issue.Title)
+		}
+		if group.More > 0 {
+			fmt.Fprintf(&b, "  ... and %d more\n", group.More)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeJSON writes v as indented JSON
+func writeJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
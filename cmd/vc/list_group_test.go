@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/storage/storagetest"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestListGroups(t *testing.T) {
+	ctx := context.Background()
+	s := storagetest.NewFakeStorage()
+	create := func(title string, priority int, labels ...string) *types.Issue {
+		t.Helper()
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: priority, IssueType: types.TypeTask}
+		if err := s.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue: %v", err)
+		}
+		for _, label := range labels {
+			if err := s.AddLabel(ctx, issue.ID, label, "test"); err != nil {
+				t.Fatalf("AddLabel: %v", err)
+			}
+		}
+		return issue
+	}
+	forms := create("Form validation", 1, "area:ui")
+	dark := create("Dark mode", 2, "area:ui")
+	create("Button colors", 3, "area:ui")
+	api := create("Rate limits", 1, "area:api", "component:gateway")
+	plain := create("Tidy up", 2, "cleanup")
+
+	groups, err := listGroups(ctx, s, "", types.IssueFilter{OrderBy: "id"}, types.GroupByLabelPrefix, 2)
+	if err != nil {
+		t.Fatalf("listGroups: %v", err)
+	}
+	var out bytes.Buffer
+	if err := writeIssueGroups(&out, types.GroupByLabelPrefix, groups); err != nil {
+		t.Fatal(err)
+	}
+	want := "area: (4)\n" +
+		"  " + forms.ID + " [P1] open  Form validation\n" +
+		"  " + dark.ID + " [P2] open  Dark mode\n" +
+		"  ... and 2 more\n" +
+		"\ncomponent: (1)\n" +
+		"  " + api.ID + " [P1] open  Rate limits\n" +
+		"\n(no prefixed label) (1)\n" +
+		"  " + plain.ID + " [P2] open  Tidy up\n"
+	if out.String() != want {
+		t.Errorf("writeIssueGroups:\n%s\nwant:\n%s", out.String(), want)
+	}
+
+	// Counts ignore Limit, so the rest of each group is still reported
+	groups, err = listGroups(ctx, s, "", types.IssueFilter{OrderBy: "id", Limit: 1}, types.GroupByPriority, 0)
+	if err != nil {
+		t.Fatalf("listGroups: %v", err)
+	}
+	if len(groups) != 3 || groups[0].Key != "1" || groups[0].Count != 2 || len(groups[0].Issues) != 1 || groups[0].More != 1 ||
+		groups[2].Key != "3" || len(groups[2].Issues) != 0 || groups[2].More != 1 {
+		t.Errorf("listGroups(priority, limit 1): got %+v", groups)
+	}
+
+	encoded, err := json.Marshal(groups)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded []struct {
+		Key    string `json:"key"`
+		Issues []struct {
+			ID string `json:"id"`
+		} `json:"issues"`
+	}
+	if err := json.Unmarshal(encoded, &decoded); err != nil || len(decoded) != 3 || decoded[0].Issues[0].ID != forms.ID {
+		t.Errorf("Expected groups to nest their issues in JSON, got %s (%v)", encoded, err)
+	}
+}
+
+func TestGroupKeyLess(t *testing.T) {
+	tests := []struct {
+		groupBy, a, b string
+		want          bool
+	}{
+		{types.GroupByStatus, "open", "closed", true},
+		{types.GroupByStatus, "blocked", "in_progress", false},
+		{types.GroupByPriority, "2", "10", true},
+		{types.GroupByAssignee, "", "alice", false},
+		{types.GroupByLabelPrefix, "area", "component", true},
+	}
+	for _, tt := range tests {
+		if got := groupKeyLess(tt.groupBy, tt.a, tt.b); got != tt.want {
+			t.Errorf("groupKeyLess(%s, %q, %q) = %v, want %v", tt.groupBy, tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -2,21 +2,34 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
-	"path/filepath"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
 
+	"github.com/chzyer/readline"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/artifacts"
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/estimates"
 	"github.com/steveyegge/vc/internal/storage"
 	"github.com/steveyegge/vc/internal/storage/beads"
 	"github.com/steveyegge/vc/internal/types"
 )
 
 var (
-	dbPath string
-	actor  string
-	store  storage.Storage
+	dbPath     string
+	dbChoice   *storage.DatabaseChoice // How dbPath was picked
+	noDiscover bool
+	actor      string
+	store      storage.Storage
+	fileConfig *config.File // Merged config files, loaded before each command
 )
 
 var rootCmd = &cobra.Command{
@@ -24,27 +37,32 @@ var rootCmd = &cobra.Command{
 	Short: "VC - AI-orchestrated coding agent colony",
 	Long:  `VibeCoder v2: Orchestrate coding agents to work on small, well-defined tasks with AI supervision.`,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
-		// Skip database initialization for init command
-		if cmd.Name() == "init" {
+		// Skip database initialization for init, for doctor, which opens
+		// the database itself to diagnose why it can't be opened, and for
+		// restore, which replaces the database file
+		if cmd.Name() == "init" || cmd.Name() == "doctor" || cmd.Name() == "restore" {
 			return
 		}
 
-		// Initialize storage
+		// Config files set the defaults of flags not given
 		var err error
-		if dbPath == "" {
-			// Auto-discover database by walking up directory tree
-			dbPath, err = storage.DiscoverDatabase()
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-				os.Exit(1)
-			}
-		} else {
-			// Make path absolute if relative was provided
-			dbPath, err = filepath.Abs(dbPath)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error: invalid database path: %v\n", err)
-				os.Exit(1)
-			}
+		fileConfig, err = loadConfigFiles(cmd)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		for _, warning := range fileConfig.Warnings {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+		}
+
+		// Initialize storage
+		choice, err := resolveDatabase()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if verbose, _ := cmd.Flags().GetBool("verbose"); verbose {
+			fmt.Fprintf(os.Stderr, "Using database %s (%s)\n", choice.Path, choice.Reason())
 		}
 
 		ctx := context.Background()
@@ -54,7 +72,10 @@ var rootCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		// Set actor from env or default
+		// Set actor from the config files, env or default
+		if v, ok := fileConfig.Get("actor"); ok && actor == "" {
+			actor = v.Value
+		}
 		if actor == "" {
 			actor = os.Getenv("USER")
 			if actor == "" {
@@ -69,16 +90,45 @@ var rootCmd = &cobra.Command{
 	},
 }
 
+// resolveDatabase picks the database (see storage.ResolveDatabase) from the
+// --db and --no-discover flags and the db setting of the config files, if
+// loaded, and makes it dbPath
+func resolveDatabase() (*storage.DatabaseChoice, error) {
+	var fromConfig storage.DatabaseSetting
+	if fileConfig != nil {
+		if v, ok := fileConfig.Get("db"); ok {
+			fromConfig = storage.DatabaseSetting{Path: v.Value, Source: v.Source}
+		}
+	}
+	choice, err := storage.ResolveDatabase(storage.DatabaseSetting{Path: dbPath, Source: "--db"}, fromConfig, noDiscover)
+	if err != nil {
+		return nil, err
+	}
+	dbPath, dbChoice = choice.Path, choice
+	return choice, nil
+}
+
 func init() {
-	rootCmd.PersistentFlags().StringVar(&dbPath, "db", "", "Database path (default: auto-discover .beads/vc.db)")
+	rootCmd.PersistentFlags().StringVar(&dbPath, "db", "", "Database path (default: $VC_DB_PATH, the db setting or .beads/*.db in the current directory)")
+	rootCmd.PersistentFlags().BoolVar(&noDiscover, "no-discover", false, "Fail instead of looking for .beads/*.db when no database is given")
+	rootCmd.PersistentFlags().Bool("verbose", false, "Print which database is used and why")
 	rootCmd.PersistentFlags().StringVar(&actor, "actor", "", "Actor name for audit trail (default: $USER)")
 }
 
 var createCmd = &cobra.Command{
 	Use:   "create [title]",
 	Short: "Create a new issue",
-	Args:  cobra.MinimumNArgs(1),
+	Long: `Create a new issue.
+
+Without --description, the description, design and acceptance criteria not
+given come from the type's template (see 'vc template'). --edit opens them
+in $EDITOR to fill in first.`,
+	Args: cobra.MinimumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
+		if err := applyConfigDefaults(cmd, createConfigKeys); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
 		title := args[0]
 		description, _ := cmd.Flags().GetString("description")
 		design, _ := cmd.Flags().GetString("design")
@@ -87,6 +137,8 @@ var createCmd = &cobra.Command{
 		issueType, _ := cmd.Flags().GetString("type")
 		assignee, _ := cmd.Flags().GetString("assignee")
 		labels, _ := cmd.Flags().GetStringSlice("labels")
+		noTemplate, _ := cmd.Flags().GetBool("no-template")
+		edit, _ := cmd.Flags().GetBool("edit")
 
 		issue := &types.Issue{
 			Title:              title,
@@ -98,20 +150,27 @@ var createCmd = &cobra.Command{
 			IssueType:          types.IssueType(issueType),
 			Assignee:           assignee,
 		}
+		if !noTemplate {
+			if err := applyTemplate(issue); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if edit {
+			if err := editSections(issue); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
 
+		// The issue and its labels are created together or not at all
 		ctx := context.Background()
-		if err := store.CreateIssue(ctx, issue, actor); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		issue.Project = mustCurrentProject(ctx)
+		if err := store.CreateIssueWithMetadata(ctx, issue, labels, nil, actor); err != nil {
+			fmt.Fprint(os.Stderr, formatIssueError(err))
 			os.Exit(1)
 		}
 
-		// Add labels if specified
-		for _, label := range labels {
-			if err := store.AddLabel(ctx, issue.ID, label, actor); err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to add label %s: %v\n", label, err)
-			}
-		}
-
 		green := color.New(color.FgGreen).SprintFunc()
 		fmt.Printf("%s Created issue: %s\n", green("✓"), issue.ID)
 		fmt.Printf("  Title: %s\n", issue.Title)
@@ -120,6 +179,14 @@ var createCmd = &cobra.Command{
 	},
 }
 
+// createConfigKeys are the config file keys of vc create's flag defaults
+var createConfigKeys = map[string]string{
+	"priority": "create.priority",
+	"type":     "create.type",
+	"assignee": "create.assignee",
+	"labels":   "create.labels",
+}
+
 func init() {
 	createCmd.Flags().StringP("description", "d", "", "Issue description")
 	createCmd.Flags().String("design", "", "Design notes")
@@ -128,14 +195,49 @@ func init() {
 	createCmd.Flags().StringP("type", "t", "task", "Issue type (bug|feature|task|epic|chore)")
 	createCmd.Flags().StringP("assignee", "a", "", "Assignee")
 	createCmd.Flags().StringSliceP("labels", "l", []string{}, "Labels (comma-separated)")
+	createCmd.Flags().Bool("no-template", false, "Don't pre-fill empty sections from the type's template")
+	createCmd.Flags().Bool("edit", false, "Fill in the description, design and acceptance criteria in $EDITOR")
 	rootCmd.AddCommand(createCmd)
 }
 
 var showCmd = &cobra.Command{
 	Use:   "show [id]",
 	Short: "Show issue details",
-	Args:  cobra.ExactArgs(1),
+	Long: `Show issue details.
+
+With --watch, show a panel of the issue while an executor works on it: its
+status, execution state and elapsed time, its latest agent events and the
+quality gates' progress. The panel is redrawn on every agent event of the
+issue and every --interval, and the watch ends with a summary once the issue
+is closed or blocked. When stdout isn't a terminal, changes are appended as
+plain lines instead.`,
+	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
+		if watch, _ := cmd.Flags().GetBool("watch"); watch {
+			interval, _ := cmd.Flags().GetDuration("interval")
+			if interval <= 0 {
+				fmt.Fprintf(os.Stderr, "Error: --interval must be positive\n")
+				os.Exit(1)
+			}
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			opts := watchOptions{Interval: interval}
+			if fd := int(os.Stdout.Fd()); readline.IsTerminal(fd) {
+				opts.Size = func() (int, int) {
+					width, height, err := readline.GetSize(fd)
+					if err != nil || width <= 0 || height <= 0 {
+						return 80, 24
+					}
+					return width, height
+				}
+			}
+			if err := watchIssue(ctx, store, args[0], os.Stdout, opts); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
 		ctx := context.Background()
 		issue, err := store.GetIssue(ctx, args[0])
 		if err != nil {
@@ -150,16 +252,39 @@ var showCmd = &cobra.Command{
 		cyan := color.New(color.FgCyan).SprintFunc()
 		fmt.Printf("\n%s: %s\n", cyan(issue.ID), issue.Title)
 		fmt.Printf("Status: %s\n", issue.Status)
+		if issue.Status == types.StatusClosed && issue.Resolution != "" {
+			fmt.Printf("Resolution: %s\n", issue.Resolution)
+		}
+		if issue.Status == types.StatusClosed {
+			if meta, err := store.GetIssueMeta(ctx, issue.ID); err == nil {
+				if closing := types.ClosingCommitFromMeta(meta); closing != nil {
+					fmt.Printf("Closed in: %s\n", formatClosingCommit(closing))
+				}
+			}
+		}
 		fmt.Printf("Priority: P%d\n", issue.Priority)
 		fmt.Printf("Type: %s\n", issue.IssueType)
-		if issue.Assignee != "" {
-			fmt.Printf("Assignee: %s\n", issue.Assignee)
+		assignee, claim := issueAssignment(ctx, store, issue)
+		if assignee != "" {
+			fmt.Printf("Assignee: %s\n", assignee)
+		}
+		if claim != nil {
+			fmt.Printf("Claimed by: executor %s (%s since %s)\n", types.ExecutorShortID(claim.ExecutorInstanceID),
+				claim.State, claim.ClaimedAt.Format("2006-01-02 15:04"))
 		}
 		if issue.EstimatedMinutes != nil {
 			fmt.Printf("Estimated: %d minutes\n", *issue.EstimatedMinutes)
 		}
+		if actual, err := estimates.ActualTime(ctx, store, issue.ID); err == nil {
+			if line := formatActual(issue.EstimatedMinutes, actual); line != "" {
+				fmt.Printf("Actual: %s\n", line)
+			}
+		}
 		fmt.Printf("Created: %s\n", issue.CreatedAt.Format("2006-01-02 15:04"))
 		fmt.Printf("Updated: %s\n", issue.UpdatedAt.Format("2006-01-02 15:04"))
+		if origin := discoveryOrigin(ctx, store, issue); origin != "" {
+			fmt.Printf("Discovered during %s\n", origin)
+		}
 
 		if issue.Description != "" {
 			fmt.Printf("\nDescription:\n%s\n", issue.Description)
@@ -177,21 +302,30 @@ var showCmd = &cobra.Command{
 			fmt.Printf("\nLabels: %v\n", labels)
 		}
 
-		// Show dependencies
-		deps, _ := store.GetDependencies(ctx, issue.ID)
-		if len(deps) > 0 {
-			fmt.Printf("\nDepends on (%d):\n", len(deps))
-			for _, dep := range deps {
-				fmt.Printf("  → %s: %s [P%d]\n", dep.ID, dep.Title, dep.Priority)
+		// Show metadata
+		if meta, err := store.GetIssueMeta(ctx, issue.ID); err == nil && len(meta) > 0 {
+			fmt.Printf("\nMetadata:\n")
+			_ = writeMetaTable(os.Stdout, meta)
+		}
+
+		// Show the mission state of missions and phases
+		if issue.IssueSubtype != types.SubtypeNormal {
+			if mission, err := store.GetMission(ctx, issue.ID); err == nil {
+				printMissionState(os.Stdout, mission)
 			}
 		}
 
-		// Show dependents
-		dependents, _ := store.GetDependents(ctx, issue.ID)
-		if len(dependents) > 0 {
-			fmt.Printf("\nBlocks (%d):\n", len(dependents))
-			for _, dep := range dependents {
-				fmt.Printf("  ← %s: %s [P%d]\n", dep.ID, dep.Title, dep.Priority)
+		// Show dependencies and dependents by link type
+		printIssueLinks(ctx, os.Stdout, store, issue.ID)
+
+		printComments(ctx, os.Stdout, store, issue.ID)
+
+		if showAssessment, _ := cmd.Flags().GetBool("assessment"); showAssessment {
+			record, err := store.GetLatestAssessment(ctx, issue.ID)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to load assessment: %v\n", err)
+			} else {
+				printAssessment(os.Stdout, record)
 			}
 		}
 
@@ -199,21 +333,132 @@ var showCmd = &cobra.Command{
 	},
 }
 
+// issueAssignment returns who an issue is assigned to and, while an executor
+// holds it, the claim. A claim puts the executor's short ID in the assignee
+// and keeps the real one in the claim until the issue is released.
+func issueAssignment(ctx context.Context, s storage.Storage, issue *types.Issue) (string, *types.IssueExecutionState) {
+	claim, err := s.GetExecutionState(ctx, issue.ID)
+	if err != nil || claim == nil || claim.ExecutorInstanceID == "" {
+		return issue.Assignee, nil
+	}
+	switch claim.State {
+	case types.ExecutionStatePending, types.ExecutionStateCompleted, types.ExecutionStateFailed:
+		return issue.Assignee, nil // Released
+	}
+	if issue.Assignee == types.ExecutorShortID(claim.ExecutorInstanceID) {
+		return claim.PreviousAssignee, claim
+	}
+	return issue.Assignee, claim // Reassigned during the claim
+}
+
+// printComments prints an issue's comments for 'vc show', oldest first. A
+// comment capped by artifacts.CommentLimit ends with a hint naming the file
+// holding its full text.
+func printComments(ctx context.Context, w io.Writer, s storage.Storage, issueID string) {
+	eventList, err := s.GetEvents(ctx, issueID, 0)
+	if err != nil {
+		return
+	}
+	var comments []*types.Event
+	for i := len(eventList) - 1; i >= 0; i-- {
+		if eventList[i].EventType == types.EventCommented && eventList[i].Comment != nil {
+			comments = append(comments, eventList[i])
+		}
+	}
+	if len(comments) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\nComments:\n")
+	for _, event := range comments {
+		fmt.Fprintf(w, "  [%s] %s:\n", event.CreatedAt.Format("2006-01-02 15:04"), event.Actor)
+		text, path, truncated := artifacts.TruncatedComment(*event.Comment)
+		for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+			fmt.Fprintf(w, "    %s\n", line)
+		}
+		switch {
+		case truncated && path != "":
+			fmt.Fprintf(w, "    (truncated, full text at %s)\n", path)
+		case truncated:
+			fmt.Fprintf(w, "    (truncated)\n")
+		}
+	}
+}
+
+// formatIssueError renders an error from creating or updating an issue,
+// with one line per invalid field if the storage rejected its values
+func formatIssueError(err error) string {
+	var invalid *types.ValidationError
+	if !errors.As(err, &invalid) {
+		return fmt.Sprintf("Error: %v\n", err)
+	}
+	var b strings.Builder
+	b.WriteString("Error: invalid issue:\n")
+	for _, field := range invalid.Fields {
+		fmt.Fprintf(&b, "  %s\n", field)
+	}
+	return b.String()
+}
+
 func init() {
+	showCmd.Flags().Bool("assessment", false, "Show the latest AI assessment and the steps done so far")
+	showCmd.Flags().BoolP("watch", "w", false, "Keep showing the issue, live, until it is closed or blocked (Ctrl+C to stop)")
+	showCmd.Flags().Duration("interval", 2*time.Second, "How often --watch re-reads the issue")
 	rootCmd.AddCommand(showCmd)
 }
 
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List issues",
+	Long: `List issues matching the filters.
+
+With --group-by, list the issues in sections by status, priority, type,
+assignee or label prefix, each with its count and its first --per-group
+issues. label-prefix groups by the part of labels before the first colon
+(area:ui and area:api both go under area:), so an issue with labels of
+several prefixes shows up in each of their groups.
+
+With --closed-in, list the issues closed by the commits of a git revision
+range (as for git log): those whose recorded closing commit or merge commit
+is in the range (see 'vc show'), and those named by a commit's Closes:
+trailer.
+
+Examples:
+  vc list --group-by label-prefix          # Sections per area:, component:, ...
+  vc list --group-by status --per-group 10
+  vc list --group-by assignee --json       # Groups nest their issues
+  vc list --closed-in v1.2.0..v1.3.0       # What a release closed`,
 	Run: func(cmd *cobra.Command, args []string) {
 		status, _ := cmd.Flags().GetString("status")
 		assignee, _ := cmd.Flags().GetString("assignee")
 		issueType, _ := cmd.Flags().GetString("type")
 		limit, _ := cmd.Flags().GetInt("limit")
-
+		offset, _ := cmd.Flags().GetInt("offset")
+		orderBy, _ := cmd.Flags().GetString("sort")
+		descending, _ := cmd.Flags().GetBool("desc")
+		includeArchived, _ := cmd.Flags().GetBool("archived")
+		includeSystem, _ := cmd.Flags().GetBool("system")
+		metaFlags, _ := cmd.Flags().GetStringArray("meta")
+		labels, _ := cmd.Flags().GetStringArray("label")
+		groupBy, _ := cmd.Flags().GetString("group-by")
+		perGroup, _ := cmd.Flags().GetInt("per-group")
+		asJSON, _ := cmd.Flags().GetBool("json")
+		closedIn, _ := cmd.Flags().GetString("closed-in")
+
+		metaEquals, err := parseMetaFilters(metaFlags)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
 		filter := types.IssueFilter{
-			Limit: limit,
+			Limit:           limit,
+			Offset:          offset,
+			OrderBy:         orderBy,
+			Descending:      descending,
+			IncludeArchived: includeArchived,
+			IncludeSystem:   includeSystem,
+			MetaEquals:      metaEquals,
+			Labels:          labels,
 		}
 		if status != "" {
 			s := types.Status(status)
@@ -233,15 +478,80 @@ var listCmd = &cobra.Command{
 		}
 
 		ctx := context.Background()
-		issues, err := store.SearchIssues(ctx, "", filter)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		filter.Project = mustCurrentProject(ctx)
+
+		if groupBy != "" && closedIn != "" {
+			fmt.Fprintf(os.Stderr, "Error: --closed-in can't be combined with --group-by\n")
 			os.Exit(1)
 		}
+		if groupBy != "" {
+			if !slices.Contains(types.IssueGroupings, groupBy) {
+				fmt.Fprintf(os.Stderr, "Error: invalid --group-by %q (valid: %s)\n", groupBy, strings.Join(types.IssueGroupings, ", "))
+				os.Exit(1)
+			}
+			groups, err := listGroups(ctx, store, "", filter, groupBy, perGroup)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			if asJSON {
+				err = writeJSON(os.Stdout, map[string]interface{}{"group_by": groupBy, "groups": groups})
+			} else if len(groups) == 0 {
+				fmt.Println("No issues found")
+			} else {
+				err = writeIssueGroups(os.Stdout, groupBy, groups)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		var issues []*types.Issue
+		total := -1 // Counted below, if paging
+		if closedIn != "" {
+			root, err := storage.GetProjectRoot(dbPath)
+			if err == nil {
+				issues, total, err = listClosedIn(ctx, store, root, closedIn, filter)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		} else {
+			issues, err = store.SearchIssues(ctx, "", filter)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if asJSON {
+			if err := writeJSON(os.Stdout, issues); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
 
-		fmt.Printf("\nFound %d issues:\n\n", len(issues))
+		if limit > 0 || offset > 0 {
+			if total < 0 {
+				total, err = store.CountIssues(ctx, "", filter)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					os.Exit(1)
+				}
+			}
+			fmt.Printf("\nShowing %d of %d issues (offset %d):\n\n", len(issues), total, offset)
+		} else {
+			fmt.Printf("\nFound %d issues:\n\n", len(issues))
+		}
 		for _, issue := range issues {
-			fmt.Printf("%s [P%d] %s\n", issue.ID, issue.Priority, issue.Status)
+			archived := ""
+			if issue.Archived {
+				archived = " [archived]"
+			}
+			fmt.Printf("%s [P%d] %s%s\n", issue.ID, issue.Priority, issue.Status, archived)
 			fmt.Printf("  %s\n", issue.Title)
 			if issue.Assignee != "" {
 				fmt.Printf("  Assignee: %s\n", issue.Assignee)
@@ -257,6 +567,17 @@ func init() {
 	listCmd.Flags().StringP("assignee", "a", "", "Filter by assignee")
 	listCmd.Flags().StringP("type", "t", "", "Filter by type")
 	listCmd.Flags().IntP("limit", "n", 0, "Limit results")
+	listCmd.Flags().Int("offset", 0, "Skip the first N results (for paging)")
+	listCmd.Flags().String("sort", "", "Sort by column (id, title, status, priority, issue_type, assignee, created_at, updated_at, closed_at)")
+	listCmd.Flags().Bool("desc", false, "Sort in descending order")
+	listCmd.Flags().Bool("archived", false, "Include archived issues")
+	listCmd.Flags().Bool("system", false, "Include the SYSTEM pseudo-issue that system-level events are filed under")
+	listCmd.Flags().StringArray("meta", nil, "Filter by metadata value, key=value (repeatable; see 'vc meta')")
+	listCmd.Flags().StringArray("label", nil, "Filter by label (repeatable; issues must have every label)")
+	listCmd.Flags().String("group-by", "", "Group issues into sections: "+strings.Join(types.IssueGroupings, ", "))
+	listCmd.Flags().Int("per-group", 5, "Issues to show per group with --group-by (0 = all)")
+	listCmd.Flags().Bool("json", false, "Print the issues (or with --group-by, the groups) as JSON")
+	listCmd.Flags().String("closed-in", "", "Only list issues closed by the commits of a git revision range (e.g. v1.2.0..HEAD)")
 	rootCmd.AddCommand(listCmd)
 }
 
@@ -290,8 +611,40 @@ var updateCmd = &cobra.Command{
 		}
 
 		ctx := context.Background()
-		if err := store.UpdateIssue(ctx, args[0], updates, actor); err != nil {
+
+		// --force sets the status outside the legal status graph (e.g. to
+		// repair an issue stuck in_progress). The override is audited.
+		force, _ := cmd.Flags().GetBool("force")
+		if status, ok := updates["status"].(string); ok && force {
+			reason, _ := cmd.Flags().GetString("reason")
+			if err := store.OverrideIssueStatus(ctx, args[0], types.Status(status), reason, actor); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			delete(updates, "status")
+		}
+
+		// Apply the update to the version we read. If someone else writes the
+		// issue meanwhile, retry unless they changed a field we are setting:
+		// then the user has to decide which value wins.
+		var original *types.Issue
+		err := storage.ModifyIssue(ctx, store, args[0], actor, func(current *types.Issue) (map[string]interface{}, error) {
+			if original == nil {
+				original = current
+				return updates, nil
+			}
+			for field := range updates {
+				if issueField(original, field) != issueField(current, field) {
+					return nil, fmt.Errorf("%s was changed concurrently (now %q); re-run to overwrite it", field, issueField(current, field))
+				}
+			}
+			return updates, nil
+		})
+		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			if errors.Is(err, types.ErrIllegalTransition) {
+				fmt.Fprintf(os.Stderr, "Use 'vc close' to close with a reason, or --force --reason to override\n")
+			}
 			os.Exit(1)
 		}
 
@@ -300,38 +653,72 @@ var updateCmd = &cobra.Command{
 	},
 }
 
+// issueField returns the value of a field settable by vc update, for
+// detecting concurrent changes
+func issueField(issue *types.Issue, field string) string {
+	switch field {
+	case "status":
+		return string(issue.Status)
+	case "priority":
+		return fmt.Sprint(issue.Priority)
+	case "title":
+		return issue.Title
+	case "assignee":
+		return issue.Assignee
+	}
+	return ""
+}
+
 func init() {
 	updateCmd.Flags().StringP("status", "s", "", "New status")
 	updateCmd.Flags().IntP("priority", "p", 0, "New priority")
 	updateCmd.Flags().String("title", "", "New title")
 	updateCmd.Flags().StringP("assignee", "a", "", "New assignee")
+	updateCmd.Flags().Bool("force", false, "Set the status even if the transition is illegal (admin override, audited)")
+	updateCmd.Flags().String("reason", "", "Reason for a forced status override")
 	rootCmd.AddCommand(updateCmd)
 }
 
 var closeCmd = &cobra.Command{
 	Use:   "close [id...]",
 	Short: "Close one or more issues",
-	Args:  cobra.MinimumNArgs(1),
+	Long: `Close one or more issues.
+
+The resolution says why: fixed (the default), wontfix, duplicate or obsolete.
+Executors won't work on an issue that depends on one closed as wontfix or
+obsolete; they block it and label it needs-triage instead.`,
+	Args: cobra.MinimumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		reason, _ := cmd.Flags().GetString("reason")
 		if reason == "" {
 			reason = "Closed"
 		}
+		resolutionFlag, _ := cmd.Flags().GetString("resolution")
+		resolution := types.Resolution(resolutionFlag)
+		if !resolution.IsValid() {
+			fmt.Fprintf(os.Stderr, "Error: invalid resolution %q (valid: %v)\n", resolution, types.Resolutions)
+			os.Exit(1)
+		}
 
 		ctx := context.Background()
 		for _, id := range args {
-			if err := store.CloseIssue(ctx, id, reason, actor); err != nil {
+			if err := store.CloseIssueWithResolution(ctx, id, reason, resolution, actor); err != nil {
 				fmt.Fprintf(os.Stderr, "Error closing %s: %v\n", id, err)
 				continue
 			}
 			green := color.New(color.FgGreen).SprintFunc()
-			fmt.Printf("%s Closed %s: %s\n", green("✓"), id, reason)
+			if resolution == types.ResolutionFixed {
+				fmt.Printf("%s Closed %s: %s\n", green("✓"), id, reason)
+			} else {
+				fmt.Printf("%s Closed %s (%s): %s\n", green("✓"), id, resolution, reason)
+			}
 		}
 	},
 }
 
 func init() {
 	closeCmd.Flags().StringP("reason", "r", "", "Reason for closing")
+	closeCmd.Flags().String("resolution", string(types.ResolutionFixed), "Why the issue is closed: fixed, wontfix, duplicate or obsolete")
 	rootCmd.AddCommand(closeCmd)
 }
 
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/artifacts"
+	"github.com/steveyegge/vc/internal/storage/storagetest"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestIssueAssignment(t *testing.T) {
+	ctx := context.Background()
+	s := storagetest.NewFakeStorage()
+	issue := &types.Issue{Title: "Assigned", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, Assignee: "alice"}
+	if err := s.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+	get := func() *types.Issue {
+		t.Helper()
+		got, err := s.GetIssue(ctx, issue.ID)
+		if err != nil {
+			t.Fatalf("GetIssue: %v", err)
+		}
+		return got
+	}
+
+	if assignee, claim := issueAssignment(ctx, s, get()); assignee != "alice" || claim != nil {
+		t.Errorf("Unclaimed: got (%q, %+v), want (alice, nil)", assignee, claim)
+	}
+
+	// While claimed, the assignee is the one from before the claim
+	if err := s.ClaimIssue(ctx, issue.ID, "0123456789abcdef"); err != nil {
+		t.Fatalf("ClaimIssue: %v", err)
+	}
+	assignee, claim := issueAssignment(ctx, s, get())
+	if assignee != "alice" || claim == nil || claim.ExecutorInstanceID != "0123456789abcdef" {
+		t.Errorf("Claimed: got (%q, %+v), want alice and the claim", assignee, claim)
+	}
+
+	if err := s.UpdateIssue(ctx, issue.ID, map[string]interface{}{"assignee": "bob"}, "test"); err != nil {
+		t.Fatalf("UpdateIssue: %v", err)
+	}
+	if assignee, claim := issueAssignment(ctx, s, get()); assignee != "bob" || claim == nil {
+		t.Errorf("Reassigned while claimed: got (%q, %+v), want bob and the claim", assignee, claim)
+	}
+
+	if err := s.ReleaseIssueAndReopen(ctx, issue.ID, "test", "failed"); err != nil {
+		t.Fatalf("ReleaseIssueAndReopen: %v", err)
+	}
+	if assignee, claim := issueAssignment(ctx, s, get()); assignee != "bob" || claim != nil {
+		t.Errorf("Released: got (%q, %+v), want (bob, nil)", assignee, claim)
+	}
+}
+
+func TestPrintComments(t *testing.T) {
+	ctx := context.Background()
+	s := storagetest.NewFakeStorage()
+	issue := &types.Issue{Title: "Commented", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := s.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+
+	var out bytes.Buffer
+	printComments(ctx, &out, s, issue.ID)
+	if out.Len() != 0 {
+		t.Errorf("Expected nothing without comments, got:\n%s", out.String())
+	}
+
+	limit := artifacts.CommentLimit{Root: t.TempDir(), MaxSize: 20}
+	for _, comment := range []string{"First look", limit.Cap(issue.ID, "Assessment: "+strings.Repeat("long ", 20))} {
+		if err := s.AddComment(ctx, issue.ID, "ai-supervisor", comment); err != nil {
+			t.Fatalf("AddComment: %v", err)
+		}
+	}
+	printComments(ctx, &out, s, issue.ID)
+	got := out.String()
+	if !strings.Contains(got, "    First look\n") || strings.Index(got, "First look") > strings.Index(got, "Assessment") {
+		t.Errorf("Expected the comments oldest first, got:\n%s", got)
+	}
+	if !strings.Contains(got, "    (truncated, full text at "+limit.Root) || strings.Contains(got, "bytes shown") {
+		t.Errorf("Expected the truncation hint in place of the marker, got:\n%s", got)
+	}
+}
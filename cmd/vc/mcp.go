@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/mcp"
+)
+
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Serve the tracker as MCP tools over stdio",
+	Long: `Speak the Model Context Protocol on stdin and stdout, so an agent session
+(Claude Desktop, an IDE, another vc agent) can work with issues through tools
+instead of parsing CLI output.
+
+Tools:
+  create_issue       Create an issue
+  get_issue          An issue with its labels and dependencies
+  search_issues      Search issues by text and filters
+  update_issue       Update fields, change status or close
+  add_comment        Comment on an issue
+  add_dependency     Record a dependency between issues
+  list_ready_work    Open issues with nothing blocking them
+  get_agent_events   Recent agent events
+
+Writes are recorded under the client's name from the MCP handshake, falling
+back to --actor. Nothing but protocol messages is written to stdout.`,
+	Example: `  # In an MCP client's server list:
+  {"command": "vc", "args": ["mcp", "--db", "/path/to/repo/.beads/vc.db"]}`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		// The version matches vc execute's default --version
+		server := mcp.NewServer(store, "0.1.0", actor)
+		if err := server.Serve(ctx, os.Stdin, os.Stdout); err != nil && ctx.Err() == nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mcpCmd)
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+var metaCmd = &cobra.Command{
+	Use:   "meta",
+	Short: "Manage key-value metadata on issues",
+	Long: `Issues carry JSON metadata for integrations: external ticket IDs, service
+names, review URLs. Keys are namespaced by convention, with the integration
+first: github.issue, jira.key, review.url. They use lowercase letters,
+digits, '_' and '-', with dots between segments.
+
+Values are JSON of at most 4 KB. 'vc meta set' takes valid JSON as is and
+anything else as a string: 42 is a number, PROJ-7 and '"42"' are strings.
+'vc list --meta key=value' reads values the same way.`,
+}
+
+var metaSetCmd = &cobra.Command{
+	Use:   "set [issue-id] [key] [value]",
+	Short: "Set a metadata value",
+	Example: `  vc meta set vc-12 jira.key PROJ-7
+  vc meta set vc-12 review.urls '["https://example.com/r/1"]'`,
+	Args: cobra.ExactArgs(3),
+	Run: func(cmd *cobra.Command, args []string) {
+		value, err := types.ParseMetaValue(args[2])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := store.SetIssueMeta(context.Background(), args[0], args[1], value); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Set %s of %s to %s\n", green("✓"), args[1], args[0], value)
+	},
+}
+
+var metaGetCmd = &cobra.Command{
+	Use:   "get [issue-id] [key]",
+	Short: "Print a metadata value as JSON",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		meta, err := store.GetIssueMeta(context.Background(), args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		value, ok := meta[args[1]]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: issue %s has no metadata %s\n", args[0], args[1])
+			os.Exit(1)
+		}
+		fmt.Println(string(value))
+	},
+}
+
+var metaListCmd = &cobra.Command{
+	Use:   "list [issue-id]",
+	Short: "List an issue's metadata",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		meta, err := store.GetIssueMeta(context.Background(), args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(meta); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+		if len(meta) == 0 {
+			fmt.Printf("%s has no metadata\n", args[0])
+			return
+		}
+		if err := writeMetaTable(os.Stdout, meta); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+var metaUnsetCmd = &cobra.Command{
+	Use:   "unset [issue-id] [key]",
+	Short: "Remove a metadata value",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := store.DeleteIssueMeta(context.Background(), args[0], args[1]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Removed %s from %s\n", green("✓"), args[1], args[0])
+	},
+}
+
+// writeMetaTable lists metadata by key
+func writeMetaTable(w io.Writer, meta map[string]json.RawMessage) error {
+	keys := make([]string, 0, len(meta))
+	for key := range meta {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, key := range keys {
+		fmt.Fprintf(tw, "%s\t%s\n", key, meta[key])
+	}
+	return tw.Flush()
+}
+
+// parseMetaFilters reads --meta key=value flags into IssueFilter.MetaEquals
+func parseMetaFilters(flags []string) (map[string]string, error) {
+	if len(flags) == 0 {
+		return nil, nil
+	}
+	filters := make(map[string]string, len(flags))
+	for _, flag := range flags {
+		key, value, ok := strings.Cut(flag, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid --meta %q (want key=value)", flag)
+		}
+		if err := types.ValidateMetaKey(key); err != nil {
+			return nil, err
+		}
+		filters[key] = value
+	}
+	return filters, nil
+}
+
+func init() {
+	metaListCmd.Flags().Bool("json", false, "Print the metadata as a JSON object")
+	metaCmd.AddCommand(metaSetCmd)
+	metaCmd.AddCommand(metaGetCmd)
+	metaCmd.AddCommand(metaListCmd)
+	metaCmd.AddCommand(metaUnsetCmd)
+	rootCmd.AddCommand(metaCmd)
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParseMetaFilters(t *testing.T) {
+	filters, err := parseMetaFilters([]string{"jira.key=PROJ-7", "review.url=https://example.com/?a=b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"jira.key": "PROJ-7", "review.url": "https://example.com/?a=b"}
+	if !reflect.DeepEqual(filters, want) {
+		t.Errorf("Expected %v, got %v", want, filters)
+	}
+	for _, bad := range []string{"jira.key", "flat=1", "Jira.Key=x"} {
+		if _, err := parseMetaFilters([]string{bad}); err == nil {
+			t.Errorf("Expected --meta %q rejected", bad)
+		}
+	}
+	if filters, err := parseMetaFilters(nil); err != nil || filters != nil {
+		t.Errorf("Expected no filters, got %v (%v)", filters, err)
+	}
+}
+
+func TestWriteMetaTable(t *testing.T) {
+	var out bytes.Buffer
+	err := writeMetaTable(&out, map[string]json.RawMessage{
+		"review.urls": json.RawMessage(`["a"]`),
+		"jira.key":    json.RawMessage(`"PROJ-7"`),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "jira.key     \"PROJ-7\"\nreview.urls  [\"a\"]\n"
+	if out.String() != want {
+		t.Errorf("Expected\n%s\ngot\n%s", want, out.String())
+	}
+}
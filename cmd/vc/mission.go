@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+var missionCmd = &cobra.Command{
+	Use:   "mission",
+	Short: "Review, inspect and resume missions",
+}
+
+var missionApproveCmd = &cobra.Command{
+	Use:   "approve [mission-id]",
+	Short: "Approve a mission's plan",
+	Long: `Approve a mission's plan so executors start on its work.
+
+While a mission that requires approval is unapproved, its phases and tasks
+are not ready work. Approving a mission blocked by an earlier rejection
+reopens it.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		comment, _ := cmd.Flags().GetString("comment")
+		ctx := context.Background()
+		if err := store.ApproveMission(ctx, args[0], actor, comment); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Approved plan of mission %s\n", green("✓"), args[0])
+	},
+}
+
+var missionRejectCmd = &cobra.Command{
+	Use:   "reject [mission-id]",
+	Short: "Reject a mission's plan",
+	Long: `Reject a mission's plan. The mission is blocked with your comment
+until the plan is revised and approved.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		comment, _ := cmd.Flags().GetString("comment")
+		ctx := context.Background()
+		if err := store.RejectMission(ctx, args[0], actor, comment); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		yellow := color.New(color.FgYellow).SprintFunc()
+		fmt.Printf("%s Rejected plan of mission %s (mission blocked)\n", yellow("✗"), args[0])
+	},
+}
+
+var missionShowCmd = &cobra.Command{
+	Use:   "show [mission-id]",
+	Short: "Show a mission's state and phases",
+	Long: `Show a mission's state and its phases in execution order, with each
+phase's status. The arrow marks the mission's current phase.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+		mission, err := store.GetMission(ctx, args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		phases, err := store.GetMissionPhases(ctx, mission.ID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		cyan := color.New(color.FgCyan).SprintFunc()
+		fmt.Printf("\n%s: %s\n", cyan(mission.ID), mission.Title)
+		fmt.Printf("Status: %s\n", mission.Status)
+		printMissionState(os.Stdout, mission)
+		printMissionPhases(os.Stdout, mission, phases)
+	},
+}
+
+var missionResumeCmd = &cobra.Command{
+	Use:   "resume [mission-id]",
+	Short: "Resume a mission stopped by a failed phase",
+	Long: `Resume a mission that stopped because a task of its current phase was
+blocked. The blocked tasks of that phase, the phase and the mission are
+reopened, and executors retry the phase. Fix what blocked the task first.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+		reopened, err := resumeMission(ctx, store, args[0], actor)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Resumed mission %s (reopened %s)\n", green("✓"), args[0], strings.Join(reopened, ", "))
+	},
+}
+
+func init() {
+	missionApproveCmd.Flags().StringP("comment", "c", "", "Review comment")
+	missionRejectCmd.Flags().StringP("comment", "c", "", "Why the plan is rejected (required)")
+	_ = missionRejectCmd.MarkFlagRequired("comment")
+	missionCmd.AddCommand(missionApproveCmd)
+	missionCmd.AddCommand(missionRejectCmd)
+	missionCmd.AddCommand(missionShowCmd)
+	missionCmd.AddCommand(missionResumeCmd)
+	rootCmd.AddCommand(missionCmd)
+}
+
+// printMissionState prints the mission state block of a mission or phase
+// for 'vc show'
+func printMissionState(w io.Writer, mission *types.Mission) {
+	fmt.Fprintf(w, "\nMission State:\n")
+	if mission.Goal != "" {
+		fmt.Fprintf(w, "  Goal: %s\n", mission.Goal)
+	}
+	if mission.PhaseCount > 0 {
+		fmt.Fprintf(w, "  Phase: %d of %d\n", mission.CurrentPhase, mission.PhaseCount)
+	}
+	if mission.ApprovalRequired {
+		if mission.ApprovedAt != nil {
+			fmt.Fprintf(w, "  Approved: %s by %s\n", mission.ApprovedAt.Format("2006-01-02 15:04"), mission.ApprovedBy)
+		} else {
+			fmt.Fprintf(w, "  Approved: no (approval required)\n")
+		}
+	}
+	if mission.SandboxPath != "" {
+		fmt.Fprintf(w, "  Sandbox: %s\n", mission.SandboxPath)
+	}
+	if mission.BranchName != "" {
+		fmt.Fprintf(w, "  Branch: %s\n", mission.BranchName)
+	}
+	fmt.Fprintf(w, "  Iterations: %d\n", mission.IterationCount)
+	gates := mission.GatesStatus
+	if gates == "" {
+		gates = "not run"
+	}
+	if mission.LastGatesRun != nil {
+		gates += fmt.Sprintf(" (last run %s)", mission.LastGatesRun.Format("2006-01-02 15:04"))
+	}
+	fmt.Fprintf(w, "  Quality Gates: %s\n", gates)
+}
+
+// printMissionPhases prints a mission's phases in execution order, marking
+// the current one, for 'vc mission show'
+func printMissionPhases(w io.Writer, mission *types.Mission, phases []*types.Issue) {
+	if len(phases) == 0 {
+		fmt.Fprintf(w, "\nPhases: none\n")
+		return
+	}
+	fmt.Fprintf(w, "\nPhases:\n")
+	for i, phase := range phases {
+		marker := "  "
+		if i == mission.CurrentPhase {
+			marker = "→ "
+		}
+		fmt.Fprintf(w, "  %s%d. [%s] %s: %s\n", marker, i+1, phase.Status, phase.ID, phase.Title)
+	}
+	if mission.CurrentPhase >= len(phases) {
+		fmt.Fprintf(w, "  All %d phases completed\n", len(phases))
+	}
+}
+
+// resumeMission reopens a mission stopped by a failed phase: the blocked
+// tasks of its executing phase (the first one that isn't closed), the
+// phase, then the mission. It returns the reopened IDs in that order.
+func resumeMission(ctx context.Context, s storage.Storage, missionID, actor string) ([]string, error) {
+	mission, err := s.GetMission(ctx, missionID)
+	if err != nil {
+		return nil, err
+	}
+	if mission.IssueSubtype != types.SubtypeMission {
+		return nil, fmt.Errorf("issue %s is a %s, not a mission", missionID, mission.IssueSubtype)
+	}
+	if mission.Status != types.StatusBlocked {
+		return nil, fmt.Errorf("mission %s is %s, only a blocked mission can be resumed", missionID, mission.Status)
+	}
+	phases, err := s.GetMissionPhases(ctx, missionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var toReopen []string
+	for _, phase := range phases {
+		if phase.Status == types.StatusClosed {
+			continue
+		}
+		tasks, err := s.GetDependents(ctx, phase.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get tasks of phase %s: %w", phase.ID, err)
+		}
+		for _, task := range tasks {
+			if task.Status == types.StatusBlocked {
+				toReopen = append(toReopen, task.ID)
+			}
+		}
+		if phase.Status == types.StatusBlocked {
+			toReopen = append(toReopen, phase.ID)
+		}
+		break
+	}
+	toReopen = append(toReopen, missionID)
+
+	for _, id := range toReopen {
+		if err := s.UpdateIssue(ctx, id, map[string]interface{}{"status": string(types.StatusOpen)}, actor); err != nil {
+			return nil, fmt.Errorf("failed to reopen %s: %w", id, err)
+		}
+	}
+	comment := fmt.Sprintf("**Resumed** by %s (reopened %s)", actor, strings.Join(toReopen, ", "))
+	if err := s.AddComment(ctx, missionID, actor, comment); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to add comment to %s: %v\n", missionID, err)
+	}
+	return toReopen, nil
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/storage/storagetest"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestPrintMissionState(t *testing.T) {
+	lastRun := time.Date(2025, 3, 1, 14, 30, 0, 0, time.UTC)
+	mission := &types.Mission{
+		Goal:             "Ship it",
+		PhaseCount:       3,
+		CurrentPhase:     1,
+		ApprovalRequired: true,
+		SandboxPath:      "/tmp/sandbox",
+		BranchName:       "mission/vc-1",
+		IterationCount:   2,
+		GatesStatus:      types.GatesStatusFailed,
+		LastGatesRun:     &lastRun,
+	}
+
+	var buf bytes.Buffer
+	printMissionState(&buf, mission)
+	out := buf.String()
+	for _, want := range []string{
+		"Goal: Ship it",
+		"Phase: 1 of 3",
+		"Approved: no (approval required)",
+		"Sandbox: /tmp/sandbox",
+		"Branch: mission/vc-1",
+		"Iterations: 2",
+		"Quality Gates: failed (last run 2025-03-01 14:30)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in output:\n%s", want, out)
+		}
+	}
+
+	// A fresh mission shows only what it has
+	buf.Reset()
+	printMissionState(&buf, &types.Mission{})
+	out = buf.String()
+	if strings.Contains(out, "Sandbox:") || strings.Contains(out, "Approved:") {
+		t.Errorf("Expected no sandbox or approval lines for a fresh mission:\n%s", out)
+	}
+	if !strings.Contains(out, "Quality Gates: not run") {
+		t.Errorf("Expected gates not run for a fresh mission:\n%s", out)
+	}
+}
+
+func TestPrintMissionPhases(t *testing.T) {
+	phases := []*types.Issue{
+		{ID: "vc-2", Title: "Design", Status: types.StatusClosed},
+		{ID: "vc-3", Title: "Build", Status: types.StatusBlocked},
+		{ID: "vc-4", Title: "Ship", Status: types.StatusOpen},
+	}
+
+	var buf bytes.Buffer
+	printMissionPhases(&buf, &types.Mission{CurrentPhase: 1, PhaseCount: 3}, phases)
+	out := buf.String()
+	for _, want := range []string{
+		"    1. [closed] vc-2: Design",
+		"  → 2. [blocked] vc-3: Build",
+		"    3. [open] vc-4: Ship",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in output:\n%s", want, out)
+		}
+	}
+
+	buf.Reset()
+	printMissionPhases(&buf, &types.Mission{CurrentPhase: 3, PhaseCount: 3}, phases)
+	if out := buf.String(); strings.Contains(out, "→") || !strings.Contains(out, "All 3 phases completed") {
+		t.Errorf("Expected a completed mission without a current phase:\n%s", out)
+	}
+
+	buf.Reset()
+	printMissionPhases(&buf, &types.Mission{}, nil)
+	if out := buf.String(); !strings.Contains(out, "Phases: none") {
+		t.Errorf("Expected no phases:\n%s", out)
+	}
+}
+
+func TestResumeMission(t *testing.T) {
+	ctx := context.Background()
+	s := storagetest.NewFakeStorage()
+	mission := &types.Mission{
+		Issue: types.Issue{
+			Title:        "Mission",
+			Status:       types.StatusOpen,
+			Priority:     1,
+			IssueType:    types.TypeEpic,
+			IssueSubtype: types.SubtypeMission,
+		},
+		Goal: "Ship it",
+	}
+	if err := s.CreateMission(ctx, mission, "test"); err != nil {
+		t.Fatalf("Failed to create mission: %v", err)
+	}
+	create := func(title string, issueType types.IssueType, subtype types.IssueSubtype, parentID string) *types.Issue {
+		t.Helper()
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 1, IssueType: issueType, IssueSubtype: subtype}
+		if err := s.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("Failed to create %s: %v", title, err)
+		}
+		if err := s.AddDependency(ctx, &types.Dependency{IssueID: issue.ID, DependsOnID: parentID, Type: types.DepParentChild}, "test"); err != nil {
+			t.Fatalf("Failed to add dependency: %v", err)
+		}
+		return issue
+	}
+	design := create("Design", types.TypeEpic, types.SubtypePhase, mission.ID)
+	build := create("Build", types.TypeEpic, types.SubtypePhase, mission.ID)
+	failed := create("Failed task", types.TypeTask, types.SubtypeNormal, build.ID)
+	pending := create("Pending task", types.TypeTask, types.SubtypeNormal, build.ID)
+	if err := s.CloseIssue(ctx, design.ID, "done", "test"); err != nil {
+		t.Fatalf("Failed to close phase: %v", err)
+	}
+
+	// Only a stopped mission can be resumed
+	if _, err := resumeMission(ctx, s, mission.ID, "tester"); err == nil {
+		t.Error("Expected an error resuming a mission that isn't blocked")
+	}
+
+	for _, id := range []string{failed.ID, build.ID, mission.ID} {
+		if err := s.UpdateIssue(ctx, id, map[string]interface{}{"status": string(types.StatusBlocked)}, "executor"); err != nil {
+			t.Fatalf("Failed to block %s: %v", id, err)
+		}
+	}
+	reopened, err := resumeMission(ctx, s, mission.ID, "tester")
+	if err != nil {
+		t.Fatalf("resumeMission: %v", err)
+	}
+	want := []string{failed.ID, build.ID, mission.ID}
+	if strings.Join(reopened, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected %v reopened, got %v", want, reopened)
+	}
+	for _, id := range append(want, pending.ID) {
+		issue, err := s.GetIssue(ctx, id)
+		if err != nil {
+			t.Fatalf("Failed to get %s: %v", id, err)
+		}
+		if issue.Status != types.StatusOpen {
+			t.Errorf("Expected %s open after resuming, got %s", id, issue.Status)
+		}
+	}
+	if got, _ := s.GetIssue(ctx, design.ID); got.Status != types.StatusClosed {
+		t.Errorf("Expected the completed phase to stay closed, got %s", got.Status)
+	}
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// projectFlag is the --project flag; see currentProject
+var projectFlag string
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&projectFlag, "project", "", "Project to work in (default: the project whose root contains the working directory, then the project setting)")
+}
+
+// projectSource is the part of the store projects are resolved from
+type projectSource interface {
+	ListProjects(ctx context.Context) ([]*types.Project, error)
+	GetConfig(ctx context.Context, key string) (string, error)
+}
+
+// currentProject returns the project commands work in: --project, else the
+// project whose root contains the working directory (the deepest one), else
+// the project setting. Empty means every project.
+func currentProject(ctx context.Context, s projectSource) (string, error) {
+	projects, err := s.ListProjects(ctx)
+	if err != nil {
+		return "", err
+	}
+	if projectFlag != "" {
+		for _, p := range projects {
+			if p.Name == projectFlag {
+				return projectFlag, nil
+			}
+		}
+		return "", fmt.Errorf("unknown project %s (see 'vc project list')", projectFlag)
+	}
+	if cwd, err := os.Getwd(); err == nil {
+		if name := projectForDir(projects, cwd); name != "" {
+			return name, nil
+		}
+	}
+	return s.GetConfig(ctx, "project")
+}
+
+// projectForDir returns the project with the deepest root containing dir,
+// or "" if no root does
+func projectForDir(projects []*types.Project, dir string) string {
+	best, bestLen := "", -1
+	for _, p := range projects {
+		if p.Root == "" {
+			continue
+		}
+		rel, err := filepath.Rel(p.Root, dir)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if len(p.Root) > bestLen {
+			best, bestLen = p.Name, len(p.Root)
+		}
+	}
+	return best
+}
+
+// mustCurrentProject is currentProject for command handlers: it exits on error
+func mustCurrentProject(ctx context.Context) string {
+	project, err := currentProject(ctx, store)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	return project
+}
+
+var projectCmd = &cobra.Command{
+	Use:   "project",
+	Short: "Manage projects and their issue ID prefixes",
+	Long: `A database can hold the issues of several projects, each with its own ID
+prefix (vc-, web-, api-). Issues created before projects existed belong to
+the default project, whose prefix is the issue_prefix setting.
+
+Commands work in the project given by --project, else the project whose root
+contains the working directory, else the project setting. Without any of
+them they see every project. Dependencies may cross projects; 'vc dep tree'
+flags them.`,
+}
+
+var projectAddCmd = &cobra.Command{
+	Use:   "add [name]",
+	Short: "Add a project with its own ID prefix",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		prefix, _ := cmd.Flags().GetString("prefix")
+		root, _ := cmd.Flags().GetString("root")
+		description, _ := cmd.Flags().GetString("description")
+		if prefix == "" {
+			prefix = args[0]
+		}
+		if root != "" {
+			abs, err := filepath.Abs(root)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: invalid root: %v\n", err)
+				os.Exit(1)
+			}
+			root = abs
+		}
+
+		project := &types.Project{Name: args[0], Prefix: prefix, Root: root, Description: description}
+		if err := store.CreateProject(context.Background(), project); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Added project %s (IDs %s-N)\n", green("✓"), project.Name, project.Prefix)
+	},
+}
+
+var projectListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List projects",
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+		projects, err := store.ListProjects(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		current, err := currentProject(ctx, store)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := writeProjectTable(os.Stdout, projects, current); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// writeProjectTable lists the projects, marking the current one with *
+func writeProjectTable(w io.Writer, projects []*types.Project, current string) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "\tNAME\tPREFIX\tROOT\tDESCRIPTION")
+	for _, p := range projects {
+		mark := ""
+		if p.Name == current {
+			mark = "*"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s-\t%s\t%s\n", mark, p.Name, p.Prefix, p.Root, p.Description)
+	}
+	return tw.Flush()
+}
+
+func init() {
+	projectAddCmd.Flags().String("prefix", "", "ID prefix of the project's issues (default: the name)")
+	projectAddCmd.Flags().String("root", "", "Directory whose commands default to the project")
+	projectAddCmd.Flags().StringP("description", "d", "", "Project description")
+	projectCmd.AddCommand(projectAddCmd)
+	projectCmd.AddCommand(projectListCmd)
+	rootCmd.AddCommand(projectCmd)
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/storage/storagetest"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestProjectForDir(t *testing.T) {
+	root := filepath.Join(string(filepath.Separator), "src")
+	projects := []*types.Project{
+		{Name: types.DefaultProject},
+		{Name: "mono", Root: root},
+		{Name: "web", Root: filepath.Join(root, "web")},
+		{Name: "webapp", Root: filepath.Join(root, "webapp")},
+	}
+	tests := []struct {
+		dir  string
+		want string
+	}{
+		{filepath.Join(root, "web", "ui"), "web"},
+		{filepath.Join(root, "web"), "web"},
+		{filepath.Join(root, "webapp"), "webapp"},
+		{filepath.Join(root, "api"), "mono"},
+		{filepath.Join(string(filepath.Separator), "elsewhere"), ""},
+	}
+	for _, tt := range tests {
+		if got := projectForDir(projects, tt.dir); got != tt.want {
+			t.Errorf("projectForDir(%s) = %q, want %q", tt.dir, got, tt.want)
+		}
+	}
+}
+
+func TestCurrentProject(t *testing.T) {
+	ctx := context.Background()
+	s := storagetest.NewFakeStorage()
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CreateProject(ctx, &types.Project{Name: "web", Prefix: "web"}); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { projectFlag = "" }()
+
+	if got, err := currentProject(ctx, s); err != nil || got != "" {
+		t.Errorf("Expected every project without flag, root or setting, got %q, %v", got, err)
+	}
+	if err := s.SetConfig(ctx, "project", "web"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := currentProject(ctx, s); got != "web" {
+		t.Errorf("Expected the project setting, got %q", got)
+	}
+	if err := s.CreateProject(ctx, &types.Project{Name: "here", Prefix: "here", Root: cwd}); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := currentProject(ctx, s); got != "here" {
+		t.Errorf("Expected the project rooted at the working directory, got %q", got)
+	}
+	projectFlag = types.DefaultProject
+	if got, _ := currentProject(ctx, s); got != types.DefaultProject {
+		t.Errorf("Expected --project to win, got %q", got)
+	}
+	projectFlag = "nowhere"
+	if _, err := currentProject(ctx, s); err == nil || !strings.Contains(err.Error(), "unknown project") {
+		t.Errorf("Expected an unknown project error, got %v", err)
+	}
+}
+
+func TestWriteProjectTable(t *testing.T) {
+	projects := []*types.Project{
+		{Name: types.DefaultProject, Prefix: "vc"},
+		{Name: "web", Prefix: "web", Root: "/src/web", Description: "Frontend"},
+	}
+	var buf bytes.Buffer
+	if err := writeProjectTable(&buf, projects, "web"); err != nil {
+		t.Fatalf("writeProjectTable failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || !strings.Contains(lines[0], "PREFIX") {
+		t.Fatalf("Unexpected table:\n%s", buf.String())
+	}
+	if got := strings.Fields(lines[2]); strings.Join(got, " ") != "* web web- /src/web Frontend" {
+		t.Errorf("Unexpected row %q", lines[2])
+	}
+	if strings.HasPrefix(strings.TrimSpace(lines[1]), "*") {
+		t.Errorf("Only the current project should be marked: %q", lines[1])
+	}
+}
@@ -4,22 +4,38 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/estimates"
 	"github.com/steveyegge/vc/internal/types"
 )
 
 var readyCmd = &cobra.Command{
 	Use:   "ready",
 	Short: "Show ready work (no blockers)",
+	Long: `Show ready work: open issues without open blockers.
+
+Work is listed most urgent first, oldest first within a priority, then by
+ID, the order executors claim it in by default. --order age lists the
+oldest first and --order estimate the smallest estimate first (shortest
+job first), with unestimated issues last.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		limit, _ := cmd.Flags().GetInt("limit")
 		assignee, _ := cmd.Flags().GetString("assignee")
+		order, _ := cmd.Flags().GetString("order")
 
 		filter := types.WorkFilter{
-			Status: types.StatusOpen,
-			Limit:  limit,
+			Status:  types.StatusOpen,
+			Limit:   limit,
+			OrderBy: types.WorkOrder(order),
+		}
+		if !filter.OrderBy.IsValid() {
+			fmt.Fprintf(os.Stderr, "Error: invalid --order %q (valid: %v)\n", order, types.WorkOrders)
+			os.Exit(1)
 		}
 		// Use Changed() to properly handle P0 (priority=0)
 		if cmd.Flags().Changed("priority") {
@@ -31,6 +47,7 @@ var readyCmd = &cobra.Command{
 		}
 
 		ctx := context.Background()
+		filter.Project = mustCurrentProject(ctx)
 		issues, err := store.GetReadyWork(ctx, filter)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -63,8 +80,19 @@ var readyCmd = &cobra.Command{
 var blockedCmd = &cobra.Command{
 	Use:   "blocked",
 	Short: "Show blocked issues",
+	Long: `Show issues blocked by open dependencies.
+
+Each issue lists its direct blockers and, when they differ, its root
+blockers: the open issues at the end of its blocking chains, which have no
+open blockers themselves.
+
+With --roots, show the root blockers across the whole backlog instead,
+ranked by how many blocked issues are waiting on each one.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		ctx := context.Background()
+		showRoots, _ := cmd.Flags().GetBool("roots")
+		limit, _ := cmd.Flags().GetInt("limit")
+
 		blocked, err := store.GetBlockedIssues(ctx)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -77,6 +105,11 @@ var blockedCmd = &cobra.Command{
 			return
 		}
 
+		if showRoots {
+			printRootBlockers(ctx, blocked, limit)
+			return
+		}
+
 		red := color.New(color.FgRed).SprintFunc()
 		fmt.Printf("\n%s Blocked issues (%d):\n\n", red("🚫"), len(blocked))
 
@@ -84,16 +117,287 @@ var blockedCmd = &cobra.Command{
 			fmt.Printf("[P%d] %s: %s\n", issue.Priority, issue.ID, issue.Title)
 			fmt.Printf("  Blocked by %d open dependencies: %v\n",
 				issue.BlockedByCount, issue.BlockedBy)
+			if len(issue.RootBlockers) > 0 && !sameIDs(issue.RootBlockers, issue.BlockedBy) {
+				fmt.Printf("  Root blockers: %v\n", issue.RootBlockers)
+			}
+			if issue.RootsTruncated {
+				fmt.Printf("  (blocking chain too deep or cyclic to follow to the end)\n")
+			}
 			fmt.Println()
 		}
 	},
 }
 
+// rootBlocker is a root blocker with the number of blocked issues waiting on it
+type rootBlocker struct {
+	ID     string
+	Blocks int
+}
+
+// rankRootBlockers counts, for every root blocker, the blocked issues whose
+// chains end at it, most blocking first (ties by ID)
+func rankRootBlockers(blocked []*types.BlockedIssue) []rootBlocker {
+	counts := make(map[string]int)
+	for _, issue := range blocked {
+		for _, root := range issue.RootBlockers {
+			counts[root]++
+		}
+	}
+
+	ranked := make([]rootBlocker, 0, len(counts))
+	for id, n := range counts {
+		ranked = append(ranked, rootBlocker{ID: id, Blocks: n})
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Blocks != ranked[j].Blocks {
+			return ranked[i].Blocks > ranked[j].Blocks
+		}
+		return ranked[i].ID < ranked[j].ID
+	})
+	return ranked
+}
+
+// printRootBlockers prints the top root blockers for 'vc blocked --roots'
+func printRootBlockers(ctx context.Context, blocked []*types.BlockedIssue, limit int) {
+	ranked := rankRootBlockers(blocked)
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	if len(ranked) == 0 {
+		fmt.Printf("\n%s %d blocked issues, but every chain ends in a dependency cycle\n\n", yellow("⚠"), len(blocked))
+		return
+	}
+	if limit > 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+
+	fmt.Printf("\n%s Root blockers (%d blocked issues):\n\n", yellow("🔑"), len(blocked))
+	for _, root := range ranked {
+		issue, err := store.GetIssue(ctx, root.ID)
+		if err != nil || issue == nil {
+			fmt.Printf("%s: blocks %d issues\n", root.ID, root.Blocks)
+			continue
+		}
+		fmt.Printf("[P%d] %s: %s (%s)\n", issue.Priority, issue.ID, issue.Title, issue.Status)
+		fmt.Printf("  Blocks %d issues\n", root.Blocks)
+	}
+	fmt.Println()
+}
+
+// sameIDs reports whether two ID lists hold the same IDs
+func sameIDs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[string]bool, len(a))
+	for _, id := range a {
+		seen[id] = true
+	}
+	for _, id := range b {
+		if !seen[id] {
+			return false
+		}
+	}
+	return true
+}
+
 var statsCmd = &cobra.Command{
 	Use:   "stats",
 	Short: "Show statistics",
+	Long: `Show issue counts, sandbox metrics, AI call retries and database size.
+Sandbox metrics cover the --since window; sizes are measured before cleanup.
+
+With --flow, also show flow metrics for the last 8 weeks: lead time (created
+to closed), cycle time (first execution attempt to closed), issues closed per
+week and current work in progress. Weeks start on Monday 00:00 UTC.
+
+With --by-actor or --by-executor, show activity per actor (humans, the AI
+supervisor, executor instances) or per executor instance instead: issues
+created and closed, comments, execution attempts, success rate, average
+attempt duration and quality gate pass rate over the --since window.
+
+With --ai, show AI calls over the --since window by purpose (assessment,
+analysis, dedup, watchdog, health, ...) and by UTC day: calls, input and
+output tokens, estimated cost and average latency. Costs use the price table
+extended by the executor.ai_prices setting; "+" marks totals missing calls
+to unpriced models.
+
+With --phases, show where execution time goes: the median, p90, max and
+average time attempts started in the --since window spent in each phase
+(assessment, sandbox, agent, analysis, gates, merge). 'vc history <id>'
+breaks down a single issue's attempts.
+
+With --estimates, compare estimates with actual time for the issues closed in
+the --since window: the median and middle half of actual/estimate ratios by
+issue type and by estimate size (up to 30m, 30m-2h, 2h-8h, over 8h). Actual
+time is the duration of completed execution attempts plus the time logged
+with 'vc time'; issues without an estimate or without any time are left out.
+
+With --storage, show the database queries that took slow_query_ms or more
+(VC_SLOW_QUERY_MS overrides it; off by default) in the --since window, by
+statement. Each process with slow query logging on records its slow queries
+when it closes the database. Beads calls are listed as beads.<Method>.
+
+Examples:
+  vc stats --flow                     # Counts plus flow metrics
+  vc stats --by-actor --since 30d     # Humans vs. the colony this month
+  vc stats --by-executor --json       # Per-host performance as JSON
+  vc stats --ai --since 30d           # AI tokens and cost this month
+  vc stats --phases                   # Median sandbox creation and gate time
+  vc stats --estimates --since 90d    # How far off estimates were this quarter
+  vc stats --storage --since 24h      # Slow database queries today`,
 	Run: func(cmd *cobra.Command, args []string) {
 		ctx := context.Background()
+
+		if showAI, _ := cmd.Flags().GetBool("ai"); showAI {
+			sinceStr, _ := cmd.Flags().GetString("since")
+			asJSON, _ := cmd.Flags().GetBool("json")
+			window, err := parseSince(sinceStr)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: invalid --since value: %v\n", err)
+				os.Exit(1)
+			}
+			since := time.Now().Add(-window)
+			callEvents, err := loadAICallEvents(ctx, store, since)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			usage := summarizeAICalls(callEvents, since)
+			if asJSON {
+				err = writeAIUsageJSON(os.Stdout, usage)
+			} else if usage.Total.Calls == 0 {
+				fmt.Printf("No AI calls in the last %s\n", sinceStr)
+			} else {
+				err = writeAIUsageTable(os.Stdout, usage)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		if showPhases, _ := cmd.Flags().GetBool("phases"); showPhases {
+			sinceStr, _ := cmd.Flags().GetString("since")
+			asJSON, _ := cmd.Flags().GetBool("json")
+			window, err := parseSince(sinceStr)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: invalid --since value: %v\n", err)
+				os.Exit(1)
+			}
+			attempts, err := store.GetExecutionPhases(ctx, time.Now().Add(-window))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			rows := summarizePhases(attempts)
+			if asJSON {
+				err = writePhaseStatsJSON(os.Stdout, rows)
+			} else if len(rows) == 0 {
+				fmt.Printf("No timed execution attempts in the last %s\n", sinceStr)
+			} else {
+				fmt.Printf("%d attempts in the last %s\n\n", len(attempts), sinceStr)
+				err = writePhaseStatsTable(os.Stdout, rows)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		if showEstimates, _ := cmd.Flags().GetBool("estimates"); showEstimates {
+			sinceStr, _ := cmd.Flags().GetString("since")
+			asJSON, _ := cmd.Flags().GetBool("json")
+			window, err := parseSince(sinceStr)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: invalid --since value: %v\n", err)
+				os.Exit(1)
+			}
+			samples, err := estimates.Samples(ctx, store, types.IssueFilter{Project: mustCurrentProject(ctx)}, time.Now().Add(-window))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			report := estimates.NewReport(samples)
+			if asJSON {
+				err = writeEstimateStatsJSON(os.Stdout, report)
+			} else if len(samples) == 0 {
+				fmt.Printf("No issues closed in the last %s with both an estimate and recorded time\n", sinceStr)
+			} else {
+				err = writeEstimateStatsTable(os.Stdout, report)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		if showStorage, _ := cmd.Flags().GetBool("storage"); showStorage {
+			sinceStr, _ := cmd.Flags().GetString("since")
+			asJSON, _ := cmd.Flags().GetBool("json")
+			window, err := parseSince(sinceStr)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: invalid --since value: %v\n", err)
+				os.Exit(1)
+			}
+			slowEvents, err := loadSlowQueryEvents(ctx, store, time.Now().Add(-window))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+
+			slow := summarizeSlowQueries(slowEvents)
+			if asJSON {
+				err = writeSlowQueryStatsJSON(os.Stdout, slow)
+			} else if slow.Slow == 0 {
+				fmt.Printf("No slow queries recorded in the last %s (set slow_query_ms or VC_SLOW_QUERY_MS to record them)\n", sinceStr)
+			} else {
+				err = writeSlowQueryStatsTable(os.Stdout, slow)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		byActor, _ := cmd.Flags().GetBool("by-actor")
+		byExecutor, _ := cmd.Flags().GetBool("by-executor")
+		if byActor || byExecutor {
+			sinceStr, _ := cmd.Flags().GetString("since")
+			asJSON, _ := cmd.Flags().GetBool("json")
+			window, err := parseSince(sinceStr)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: invalid --since value: %v\n", err)
+				os.Exit(1)
+			}
+
+			actorStats, err := store.GetActorStatistics(ctx, time.Now().Add(-window))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			actorStats = selectActorStats(actorStats, byExecutor)
+
+			if asJSON {
+				err = writeActorStatsJSON(os.Stdout, actorStats)
+			} else if len(actorStats) == 0 {
+				fmt.Printf("No activity in the last %s\n", sinceStr)
+			} else {
+				err = writeActorStatsTable(os.Stdout, actorStats, byExecutor)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
 		stats, err := store.GetStatistics(ctx)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -110,20 +414,146 @@ var statsCmd = &cobra.Command{
 		fmt.Printf("In Progress:       %s\n", yellow(fmt.Sprintf("%d", stats.InProgressIssues)))
 		fmt.Printf("Closed:            %d\n", stats.ClosedIssues)
 		fmt.Printf("Blocked:           %d\n", stats.BlockedIssues)
+		if stats.AutoBlockedIssues > 0 {
+			red := color.New(color.FgRed).SprintFunc()
+			fmt.Printf("Auto-blocked:      %s (vc list --label %s)\n", red(fmt.Sprintf("%d", stats.AutoBlockedIssues)), types.AutoBlockedLabel)
+		}
 		fmt.Printf("Ready:             %s\n", green(fmt.Sprintf("%d", stats.ReadyIssues)))
 		if stats.AverageLeadTime > 0 {
 			fmt.Printf("Avg Lead Time:     %.1f hours\n", stats.AverageLeadTime)
 		}
+
+		// Sandbox lifecycle metrics (aggregated from sandbox_* events in the --since window)
+		sinceStr, _ := cmd.Flags().GetString("since")
+		window, err := parseSince(sinceStr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: invalid --since value: %v\n", err)
+			os.Exit(1)
+		}
+		sandboxEvents, err := loadSandboxEvents(ctx, store, time.Now().Add(-window))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to load sandbox metrics: %v\n", err)
+		} else if len(sandboxEvents) > 0 {
+			m := summarizeSandboxEvents(sandboxEvents)
+			red := color.New(color.FgRed).SprintFunc()
+
+			fmt.Printf("\n%s Sandbox Metrics (last %s):\n\n", cyan("📦"), sinceStr)
+			fmt.Printf("Created:           %d\n", m.Created)
+			if m.CreateFailures > 0 {
+				fmt.Printf("Create Failures:   %s\n", red(fmt.Sprintf("%d", m.CreateFailures)))
+			}
+			if m.Created > 0 {
+				fmt.Printf("Avg Create Time:   %dms (max %dms)\n", m.AvgCreateMs, m.MaxCreateMs)
+			}
+			if m.AvgDiskBytes > 0 {
+				fmt.Printf("Avg Size:          %s (max %s)\n", formatBytes(m.AvgDiskBytes), formatBytes(m.MaxDiskBytes))
+			}
+			fmt.Printf("Cleaned:           %d\n", m.Cleaned)
+			if m.Cleaned > 0 {
+				fmt.Printf("Avg Cleanup Time:  %dms (%s reclaimed)\n", m.AvgCleanupMs, formatBytes(m.ReclaimedBytes))
+			}
+			if m.CleanupFailures > 0 {
+				fmt.Printf("Cleanup Failures:  %s\n", red(fmt.Sprintf("%d", m.CleanupFailures)))
+			}
+		}
+
+		// AI call retries (aggregated from ai_retried/ai_retries_exhausted events)
+		retryEvents, err := loadAIRetryEvents(ctx, store)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to load AI retry metrics: %v\n", err)
+		} else if len(retryEvents) > 0 {
+			m := summarizeAIRetryEvents(retryEvents)
+			fmt.Printf("\n%s AI Retries:\n\n", cyan("🔁"))
+			fmt.Printf("Retried Calls:     %d (%d retries)\n", m.RetriedCalls+m.ExhaustedCalls, m.Retries)
+			if m.ExhaustedCalls > 0 {
+				red := color.New(color.FgRed).SprintFunc()
+				fmt.Printf("Gave Up:           %s\n", red(fmt.Sprintf("%d", m.ExhaustedCalls)))
+			}
+		}
+
+		// Database file size and reclaimable space
+		if dbStats, err := store.GetDatabaseStats(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to load database stats: %v\n", err)
+		} else {
+			printDatabaseStats(dbStats)
+		}
+
+		if showFlow, _ := cmd.Flags().GetBool("flow"); showFlow && stats.Flow != nil {
+			printFlowMetrics(stats.Flow)
+		}
 		fmt.Println()
 	},
 }
 
+// printDatabaseStats renders the database section of 'vc stats'
+func printDatabaseStats(db *types.DatabaseStats) {
+	cyan := color.New(color.FgCyan).SprintFunc()
+
+	fmt.Printf("\n%s Database:\n\n", cyan("💾"))
+	fmt.Printf("Size:              %s (%d pages of %d bytes)\n", formatBytes(db.SizeBytes), db.PageCount, db.PageSize)
+	fmt.Printf("Free Pages:        %d (%.1f%%)\n", db.FreelistCount, db.FreeFraction()*100)
+	if db.WALSizeBytes > 0 {
+		fmt.Printf("WAL Size:          %s\n", formatBytes(db.WALSizeBytes))
+	}
+	fmt.Printf("Auto Vacuum:       %s\n", db.AutoVacuum)
+}
+
+// printFlowMetrics renders the flow section of 'vc stats --flow'
+func printFlowMetrics(flow *types.FlowMetrics) {
+	cyan := color.New(color.FgCyan).SprintFunc()
+
+	fmt.Printf("\n%s Flow Metrics (last %d weeks, since %s UTC):\n\n",
+		cyan("⏱"), flow.Weeks, flow.Since.Format("2006-01-02"))
+	fmt.Printf("WIP:               %d in progress\n", flow.WIP)
+	printDurationStats("Lead Time:", flow.LeadTime)
+	printDurationStats("Cycle Time:", flow.CycleTime)
+
+	fmt.Printf("\nThroughput (closed per week, weeks start Monday UTC):\n")
+	maxClosed := 0
+	for _, week := range flow.Throughput {
+		if week.Closed > maxClosed {
+			maxClosed = week.Closed
+		}
+	}
+	for _, week := range flow.Throughput {
+		bar := ""
+		if maxClosed > 0 {
+			bar = strings.Repeat("█", week.Closed*30/maxClosed)
+		}
+		fmt.Printf("  %s  %4d  %s\n", week.WeekStart.Format("2006-01-02"), week.Closed, bar)
+	}
+}
+
+// printDurationStats prints one duration row, or a placeholder without data
+func printDurationStats(label string, d types.DurationStats) {
+	if d.Count == 0 {
+		fmt.Printf("%-18s no closed issues\n", label)
+		return
+	}
+	fmt.Printf("%-18s avg %.1fh, p50 %.1fh, p90 %.1fh (%d issues)\n",
+		label, d.Average, d.P50, d.P90, d.Count)
+}
+
 func init() {
 	readyCmd.Flags().IntP("limit", "n", 10, "Maximum issues to show")
 	readyCmd.Flags().IntP("priority", "p", 0, "Filter by priority")
 	readyCmd.Flags().StringP("assignee", "a", "", "Filter by assignee")
+	readyCmd.Flags().String("order", string(types.WorkOrderPriority), "Order: priority, age or estimate")
 
 	rootCmd.AddCommand(readyCmd)
+	blockedCmd.Flags().Bool("roots", false, "Rank the root blockers of the whole backlog")
+	blockedCmd.Flags().IntP("limit", "n", 10, "Maximum root blockers to show with --roots (0 = all)")
+
 	rootCmd.AddCommand(blockedCmd)
+	statsCmd.Flags().Bool("flow", false, "Show lead time, cycle time, throughput and WIP")
+	statsCmd.Flags().Bool("by-actor", false, "Show activity per actor")
+	statsCmd.Flags().Bool("by-executor", false, "Show activity per executor instance")
+	statsCmd.Flags().Bool("ai", false, "Show AI token usage and estimated cost by purpose and day")
+	statsCmd.Flags().Bool("phases", false, "Show the time attempts spend in each execution phase")
+	statsCmd.Flags().Bool("storage", false, "Show the slow database queries recorded with slow_query_ms")
+	statsCmd.Flags().Bool("estimates", false, "Compare estimates with actual time by issue type and estimate size")
+	statsCmd.Flags().String("since", "7d", "Window for sandbox metrics, --by-actor, --by-executor, --ai, --phases, --estimates and --storage (e.g., 24h, 7d)")
+	statsCmd.Flags().Bool("json", false, "Print --by-actor, --by-executor, --ai, --phases, --estimates or --storage rows as JSON")
+
 	rootCmd.AddCommand(statsCmd)
 }
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestRankRootBlockers(t *testing.T) {
+	blocked := []*types.BlockedIssue{
+		{Issue: types.Issue{ID: "vc-2"}, RootBlockers: []string{"vc-1"}},
+		{Issue: types.Issue{ID: "vc-3"}, RootBlockers: []string{"vc-1"}},
+		{Issue: types.Issue{ID: "vc-4"}, RootBlockers: []string{"vc-1", "vc-9"}},
+		{Issue: types.Issue{ID: "vc-5"}, RootBlockers: []string{"vc-8"}},
+		{Issue: types.Issue{ID: "vc-6"}, RootBlockers: []string{"vc-9"}},
+		// Cyclic chain: no roots
+		{Issue: types.Issue{ID: "vc-7"}, RootsTruncated: true},
+	}
+
+	ranked := rankRootBlockers(blocked)
+	want := []rootBlocker{{"vc-1", 3}, {"vc-9", 2}, {"vc-8", 1}}
+	if len(ranked) != len(want) {
+		t.Fatalf("Expected %d root blockers, got %v", len(want), ranked)
+	}
+	for i := range want {
+		if ranked[i] != want[i] {
+			t.Errorf("ranked[%d] = %v, want %v", i, ranked[i], want[i])
+		}
+	}
+
+	if got := rankRootBlockers(nil); len(got) != 0 {
+		t.Errorf("Expected no root blockers, got %v", got)
+	}
+}
+
+func TestSameIDs(t *testing.T) {
+	if !sameIDs([]string{"a", "b"}, []string{"b", "a"}) {
+		t.Error("Expected order not to matter")
+	}
+	if sameIDs([]string{"a"}, []string{"a", "b"}) || sameIDs([]string{"a", "c"}, []string{"a", "b"}) {
+		t.Error("Expected different ID lists to differ")
+	}
+}
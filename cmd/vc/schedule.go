@@ -0,0 +1,235 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/schedule"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Manage recurring issues",
+	Long: `A schedule files an issue from a template on a cron schedule or at a fixed
+interval: weekly dependency reviews, monthly key rotations, hourly queue
+checks. Running executors check schedules on every cleanup pass (every 5
+minutes by default) and file the issues that are due.
+
+Each filed issue gets the labels "scheduled" and "schedule:<id>" besides the
+template's, and its description ends with a link back to the schedule.
+
+If the issue a schedule filed last is still open, the schedule's policy
+decides: "skip" (the default) lets the window pass, "file" files another
+issue anyway. A window is filed at most once even with several executors,
+and an executor that was down over many windows files one catch-up issue,
+not one per missed window.
+
+Cron expressions have five fields (minute hour day-of-month month
+day-of-week) in local time, with lists, ranges and steps, or one of @hourly,
+@daily, @weekly, @monthly and @yearly.`,
+}
+
+var scheduleAddCmd = &cobra.Command{
+	Use:   "add [title]",
+	Short: "Add a schedule",
+	Example: `  vc schedule add "Review dependency updates" --cron "0 9 * * 1" --type chore
+  vc schedule add "Check the deploy queue" --every 4h --policy file
+  vc schedule add "Rotate API keys" --cron @monthly -p 1 --label security`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+		s, err := scheduleFromFlags(cmd, args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		s.Project = mustCurrentProject(ctx)
+		if err := store.CreateSchedule(ctx, s); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Added schedule %d (%s)\n", green("✓"), s.ID, schedule.Describe(s))
+		if next, err := schedule.NextRun(s); err == nil && !next.IsZero() && s.Enabled {
+			fmt.Printf("  Next issue: %s\n", next.Local().Format("2006-01-02 15:04"))
+		}
+	},
+}
+
+// scheduleFromFlags builds the schedule vc schedule add describes
+func scheduleFromFlags(cmd *cobra.Command, title string) (*types.Schedule, error) {
+	cronExpr, _ := cmd.Flags().GetString("cron")
+	every, _ := cmd.Flags().GetDuration("every")
+	issueType, _ := cmd.Flags().GetString("type")
+	priority, _ := cmd.Flags().GetInt("priority")
+	description, _ := cmd.Flags().GetString("description")
+	labels, _ := cmd.Flags().GetStringSlice("label")
+	policy, _ := cmd.Flags().GetString("policy")
+	disabled, _ := cmd.Flags().GetBool("disabled")
+
+	if (cronExpr == "") == (every == 0) {
+		return nil, fmt.Errorf("specify exactly one of --cron and --every")
+	}
+	if cronExpr != "" {
+		if _, err := schedule.ParseCron(cronExpr); err != nil {
+			return nil, err
+		}
+	}
+	s := &types.Schedule{
+		Title:       title,
+		Description: description,
+		IssueType:   types.IssueType(issueType),
+		Priority:    priority,
+		Labels:      labels,
+		Cron:        cronExpr,
+		Interval:    every,
+		Policy:      types.SchedulePolicy(policy),
+		Enabled:     !disabled,
+	}
+	return s, s.Validate()
+}
+
+var scheduleListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List schedules",
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+		project := mustCurrentProject(ctx)
+		schedules, err := store.ListSchedules(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if project != "" {
+			var inProject []*types.Schedule
+			for _, s := range schedules {
+				if s.Project == project || (s.Project == "" && project == types.DefaultProject) {
+					inProject = append(inProject, s)
+				}
+			}
+			schedules = inProject
+		}
+		if len(schedules) == 0 {
+			fmt.Println("No schedules (add one with 'vc schedule add')")
+			return
+		}
+		if err := writeScheduleTable(os.Stdout, schedules); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// writeScheduleTable lists the schedules with when each files next
+func writeScheduleTable(w io.Writer, schedules []*types.Schedule) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tSCHEDULE\tPOLICY\tNEXT\tLAST ISSUE\tTITLE")
+	for _, s := range schedules {
+		next := "disabled"
+		if s.Enabled {
+			at, err := schedule.NextRun(s)
+			switch {
+			case err != nil:
+				next = "invalid"
+			case at.IsZero():
+				next = "never"
+			default:
+				next = at.Local().Format("2006-01-02 15:04")
+			}
+		}
+		last := s.LastIssueID
+		if last == "" {
+			last = "-"
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\n", s.ID, schedule.Describe(s), s.Policy, next, last, s.Title)
+	}
+	return tw.Flush()
+}
+
+var scheduleRemoveCmd = &cobra.Command{
+	Use:   "remove [id]",
+	Short: "Remove a schedule (the issues it filed stay)",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		id := mustScheduleID(args[0])
+		if err := store.DeleteSchedule(context.Background(), id); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Removed schedule %d\n", green("✓"), id)
+	},
+}
+
+var scheduleEnableCmd = &cobra.Command{
+	Use:   "enable [id]",
+	Short: "Enable a schedule",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		setScheduleEnabled(args[0], true)
+	},
+}
+
+var scheduleDisableCmd = &cobra.Command{
+	Use:   "disable [id]",
+	Short: "Disable a schedule, keeping it for later",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		setScheduleEnabled(args[0], false)
+	},
+}
+
+// setScheduleEnabled runs vc schedule enable or disable
+func setScheduleEnabled(arg string, enabled bool) {
+	id := mustScheduleID(arg)
+	if err := store.SetScheduleEnabled(context.Background(), id, enabled); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	done := "Enabled"
+	if !enabled {
+		done = "Disabled"
+	}
+	green := color.New(color.FgGreen).SprintFunc()
+	fmt.Printf("%s %s schedule %d\n", green("✓"), done, id)
+}
+
+// mustScheduleID parses a schedule ID argument, exiting if it isn't one
+func mustScheduleID(arg string) int64 {
+	id, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil || id <= 0 {
+		fmt.Fprintf(os.Stderr, "Error: invalid schedule ID %q (see 'vc schedule list')\n", arg)
+		os.Exit(1)
+	}
+	return id
+}
+
+// addScheduleFlags defines vc schedule add's template and timing flags
+func addScheduleFlags(cmd *cobra.Command) {
+	flags := cmd.Flags()
+	flags.String("cron", "", "Cron expression (e.g. \"0 9 * * 1\" or @weekly)")
+	flags.Duration("every", 0, "Fixed interval from the schedule's creation (e.g. 4h, at least 1m)")
+	flags.StringP("type", "t", "task", "Issue type (bug|feature|task|epic|chore)")
+	flags.IntP("priority", "p", 2, "Priority (0-4, 0=highest)")
+	flags.StringP("description", "d", "", "Issue description")
+	flags.StringSliceP("label", "l", nil, "Labels of the filed issues (repeatable)")
+	flags.String("policy", string(types.ScheduleSkipOpen), "When the last filed issue is still open: skip or file")
+	flags.Bool("disabled", false, "Add the schedule disabled")
+}
+
+func init() {
+	addScheduleFlags(scheduleAddCmd)
+	scheduleCmd.AddCommand(scheduleAddCmd)
+	scheduleCmd.AddCommand(scheduleListCmd)
+	scheduleCmd.AddCommand(scheduleRemoveCmd)
+	scheduleCmd.AddCommand(scheduleEnableCmd)
+	scheduleCmd.AddCommand(scheduleDisableCmd)
+	rootCmd.AddCommand(scheduleCmd)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// newScheduleAddCmd returns a fresh command with vc schedule add's flags
+func newScheduleAddCmd(t *testing.T, args ...string) *cobra.Command {
+	t.Helper()
+	cmd := &cobra.Command{}
+	addScheduleFlags(cmd)
+	if err := cmd.ParseFlags(args); err != nil {
+		t.Fatal(err)
+	}
+	return cmd
+}
+
+func TestScheduleFromFlags(t *testing.T) {
+	cmd := newScheduleAddCmd(t, "--cron", "0 9 * * 1", "-t", "chore", "-l", "deps", "--policy", "file")
+	s, err := scheduleFromFlags(cmd, "Review dependencies")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Cron != "0 9 * * 1" || s.IssueType != types.TypeChore || s.Policy != types.ScheduleFileAnyway ||
+		len(s.Labels) != 1 || !s.Enabled || s.Priority != 2 {
+		t.Errorf("Unexpected schedule: %+v", s)
+	}
+
+	for _, args := range [][]string{
+		{},
+		{"--cron", "@daily", "--every", "1h"},
+		{"--cron", "0 25 * * *"},
+		{"--every", "30s"},
+		{"--every", "1h", "--policy", "sometimes"},
+	} {
+		if _, err := scheduleFromFlags(newScheduleAddCmd(t, args...), "Title"); err == nil {
+			t.Errorf("scheduleFromFlags(%v): expected an error", args)
+		}
+	}
+}
+
+func TestWriteScheduleTable(t *testing.T) {
+	last := time.Now()
+	schedules := []*types.Schedule{
+		{ID: 1, Title: "Weekly review", Cron: "@weekly", Policy: types.ScheduleSkipOpen, Enabled: true, LastRunAt: &last, LastIssueID: "vc-9"},
+		{ID: 2, Title: "Paused", Interval: 4 * time.Hour, Policy: types.ScheduleFileAnyway, CreatedAt: last},
+	}
+	var buf bytes.Buffer
+	if err := writeScheduleTable(&buf, schedules); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{"@weekly", "vc-9", "every 4h0m0s", "disabled", "Paused"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Table lacks %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/api"
+)
+
+// serveTokenEnvVar holds the API token when --token isn't given
+const serveTokenEnvVar = "VC_SERVE_TOKEN"
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve a read-only JSON API over the tracker",
+	Long: `Serve issues, events, executor instances and statistics as JSON over HTTP,
+so teammates can browse the colony without the CLI or the database file.
+
+Endpoints (all GET; /openapi.json describes them):
+  /issues                 Search issues (q, status, priority, type, assignee,
+                          label, project, sort, desc, archived, limit, offset)
+  /issues/{id}            An issue with labels, dependencies and execution state
+  /issues/{id}/events     The issue's audit trail
+  /events                 Recent agent events (issue, executor, type, severity,
+                          q, since, limit)
+  /instances              Running executors
+  /stats                  Statistics
+
+The database is opened query-only, so the server can't write, and shares it
+with running executors through WAL. Set a token (--token or VC_SERVE_TOKEN)
+before listening beyond localhost; clients send it as
+"Authorization: Bearer <token>".`,
+	Example: `  vc serve --addr localhost:8080
+  VC_SERVE_TOKEN=s3cret vc serve --addr :8080 --cors-origin https://dash.example.com`,
+	Run: func(cmd *cobra.Command, args []string) {
+		addr, _ := cmd.Flags().GetString("addr")
+		token, _ := cmd.Flags().GetString("token")
+		origins, _ := cmd.Flags().GetStringSlice("cors-origin")
+		if token == "" {
+			token = os.Getenv(serveTokenEnvVar)
+		}
+		if err := runServe(addr, token, origins); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	serveCmd.Flags().String("addr", "localhost:8080", "Address to listen on")
+	serveCmd.Flags().String("token", "", "Token clients must send (default: $"+serveTokenEnvVar+", none if unset)")
+	serveCmd.Flags().StringSlice("cors-origin", nil, "Origins browsers may call the API from (comma-separated, * for any)")
+	rootCmd.AddCommand(serveCmd)
+}
+
+// runServe serves the API until interrupted
+func runServe(addr, token string, origins []string) error {
+	// Writes fail from here on; reads still see what executors write
+	if ro, ok := store.(interface{ SetReadOnly() }); ok {
+		ro.SetReadOnly()
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	server := &http.Server{
+		Handler:           api.NewHandler(store, api.Options{Token: token, CORSOrigins: origins}),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	green := color.New(color.FgGreen).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	fmt.Printf("%s Serving the read-only API on http://%s (spec at /openapi.json)\n", green("✓"), listener.Addr())
+	if token == "" && !isLoopback(listener.Addr()) {
+		fmt.Printf("%s No token set and listening beyond localhost: anyone who can reach %s can read the tracker\n", yellow("⚠"), listener.Addr())
+	}
+	fmt.Printf("  Press Ctrl+C to stop\n")
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+	errCh := make(chan error, 1)
+	go func() { errCh <- server.Serve(listener) }()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-sigCh:
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(ctx); err != nil {
+			return fmt.Errorf("failed to shut down: %w", err)
+		}
+		if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	}
+}
+
+// isLoopback reports whether the listener accepts local connections only
+func isLoopback(addr net.Addr) bool {
+	tcp, ok := addr.(*net.TCPAddr)
+	return ok && tcp.IP.IsLoopback()
+}
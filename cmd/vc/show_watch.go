@@ -0,0 +1,365 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/steveyegge/vc/internal/estimates"
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// watchEventLimit is how many of the issue's agent events the watch panel
+// shows (fewer when the terminal is short)
+const watchEventLimit = 15
+
+// watchResizeCheck is how often a terminal's size is checked, so the panel
+// is redrawn soon after a resize
+const watchResizeCheck = 250 * time.Millisecond
+
+// watchOptions configure 'vc show --watch'
+type watchOptions struct {
+	// Interval is how often the issue and its execution state are re-read
+	Interval time.Duration
+	// Size returns the terminal's width and height; nil when the output
+	// isn't a terminal, which appends changes as plain lines instead of
+	// redrawing the panel
+	Size func() (int, int)
+}
+
+// watchPanel is what 'vc show --watch' knows about the issue
+type watchPanel struct {
+	issue  *types.Issue
+	state  *types.IssueExecutionState
+	events []*events.AgentEvent // The latest, oldest first
+	gates  string               // Quality gate progress, "" before any
+}
+
+// watchIssue shows the issue until it is closed or blocked or ctx is done,
+// re-reading it every opts.Interval and on each of its agent events, then
+// prints a summary of how it ended
+func watchIssue(ctx context.Context, s storage.Storage, issueID string, out io.Writer, opts watchOptions) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Subscribe before loading the latest events so none falls in between
+	feed, err := s.WatchAgentEvents(ctx, events.EventFilter{IssueID: issueID})
+	if err != nil {
+		return fmt.Errorf("failed to watch agent events: %w", err)
+	}
+	recent, err := s.GetAgentEvents(ctx, events.EventFilter{IssueID: issueID, Limit: watchEventLimit})
+	if err != nil {
+		return fmt.Errorf("failed to get agent events: %w", err)
+	}
+	p := &watchPanel{}
+	for i := len(recent) - 1; i >= 0; i-- { // Newest first
+		p.addEvent(recent[i])
+	}
+	if err := p.reload(ctx, s, issueID); err != nil {
+		return err
+	}
+
+	tty := opts.Size != nil
+	var width, height int
+	if tty {
+		width, height = opts.Size()
+		fmt.Fprint(out, "\x1b[H\x1b[2J")
+		drawPanel(out, p.render(width, height, time.Now()))
+	} else {
+		p.writeHeader(out)
+		for _, event := range p.events {
+			fmt.Fprintln(out, formatWatchEvent(event))
+		}
+	}
+
+	reload := time.NewTicker(opts.Interval)
+	defer reload.Stop()
+	resize := time.NewTicker(watchResizeCheck)
+	defer resize.Stop()
+
+	for !p.done() {
+		before := *p.issue
+		var beforeState types.IssueExecutionState
+		if p.state != nil {
+			beforeState = *p.state
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-feed:
+			if !ok {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return fmt.Errorf("agent event feed closed")
+			}
+			if !p.addEvent(event) {
+				continue
+			}
+			if !tty {
+				fmt.Fprintln(out, formatWatchEvent(event))
+			}
+			if err := p.reload(ctx, s, issueID); err != nil {
+				return err
+			}
+		case <-reload.C:
+			if err := p.reload(ctx, s, issueID); err != nil {
+				return err
+			}
+		case <-resize.C:
+			if !tty {
+				continue
+			}
+			if w, h := opts.Size(); w == width && h == height {
+				continue
+			}
+		}
+
+		if tty {
+			width, height = opts.Size()
+			drawPanel(out, p.render(width, height, time.Now()))
+		} else {
+			p.writeChanges(out, &before, &beforeState)
+		}
+	}
+
+	summary, err := finalSummary(ctx, s, p.issue)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "\n%s\n", summary)
+	return nil
+}
+
+// reload re-reads the issue and its execution state
+func (p *watchPanel) reload(ctx context.Context, s storage.Storage, issueID string) error {
+	issue, err := s.GetIssue(ctx, issueID)
+	if err != nil {
+		return err
+	}
+	if issue == nil {
+		return fmt.Errorf("issue %s not found", issueID)
+	}
+	state, err := s.GetExecutionState(ctx, issueID)
+	if err != nil {
+		return fmt.Errorf("failed to get execution state: %w", err)
+	}
+	p.issue, p.state = issue, state
+	return nil
+}
+
+// addEvent keeps the latest watchEventLimit events and follows the quality
+// gates. Returns false for an event already shown.
+func (p *watchPanel) addEvent(event *events.AgentEvent) bool {
+	for _, e := range p.events {
+		if e.ID == event.ID && event.ID != "" {
+			return false
+		}
+	}
+	p.events = append(p.events, event)
+	if len(p.events) > watchEventLimit {
+		p.events = p.events[len(p.events)-watchEventLimit:]
+	}
+	if gates := gateProgress(event); gates != "" {
+		p.gates = gates
+	}
+	return true
+}
+
+// done reports whether the issue reached a state the watch ends in
+func (p *watchPanel) done() bool {
+	return p.issue.Status == types.StatusClosed || p.issue.Status == types.StatusBlocked
+}
+
+// gateProgress renders a quality gate event, or "" for other events
+func gateProgress(event *events.AgentEvent) string {
+	switch event.Type {
+	case events.EventTypeQualityGatesStarted:
+		return "running"
+	case events.EventTypeQualityGatesProgress:
+		done, total := dataInt(event.Data, "gates_completed"), dataInt(event.Data, "total_gates")
+		progress := fmt.Sprintf("%d/%d done", done, total)
+		if gate, _ := event.Data["current_gate"].(string); gate != "" {
+			progress += ", running " + gate
+		}
+		return progress
+	case events.EventTypeQualityGatesCompleted, events.EventTypeQualityGatesSkipped, events.EventTypeQualityGatesDeferred:
+		return oneLine(event.Message)
+	}
+	return ""
+}
+
+// dataInt reads a number from event data, stored as an int in process and
+// as a float64 once decoded from JSON
+func dataInt(data map[string]interface{}, key string) int {
+	switch v := data[key].(type) {
+	case int:
+		return v
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	}
+	return 0
+}
+
+// executionLine renders the execution state with the executor and the time
+// since the execution started (or the issue was claimed)
+func executionLine(state *types.IssueExecutionState, now time.Time) string {
+	if state == nil || state.ExecutorInstanceID == "" {
+		return "not claimed"
+	}
+	line := fmt.Sprintf("%s (executor %s", state.State, types.ExecutorShortID(state.ExecutorInstanceID))
+	since := state.StartedAt
+	if since.IsZero() {
+		since = state.ClaimedAt
+	}
+	if !since.IsZero() {
+		line += ", " + formatElapsedTime(now.Sub(since))
+	}
+	return line + ")"
+}
+
+// formatElapsedTime renders a duration as 45s, 3m12s or 2h05m
+func formatElapsedTime(d time.Duration) string {
+	d = max(d, 0).Truncate(time.Second)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+	}
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// render draws the panel in less than height lines of width columns,
+// showing fewer events on a short terminal. The last line stays free so the
+// newline after the panel doesn't scroll it.
+func (p *watchPanel) render(width, height int, now time.Time) []string {
+	height--
+	lines := []string{
+		fmt.Sprintf("%s: %s", p.issue.ID, p.issue.Title),
+		fmt.Sprintf("Status: %s   Priority: P%d   Type: %s", p.issue.Status, p.issue.Priority, p.issue.IssueType),
+		"Execution: " + executionLine(p.state, now),
+	}
+	if p.gates != "" {
+		lines = append(lines, "Quality gates: "+p.gates)
+	}
+	lines = append(lines, "", "Recent events:")
+	shown := p.events
+	if room := height - len(lines) - 1; height > 0 && len(shown) > room {
+		shown = shown[len(shown)-max(room, 0):]
+	}
+	if len(p.events) == 0 {
+		lines = append(lines, "  (none yet)")
+	}
+	for _, event := range shown {
+		lines = append(lines, "  "+formatWatchEvent(event))
+	}
+	lines = append(lines, fmt.Sprintf("Watching %s, updated %s (Ctrl+C to stop)", p.issue.ID, now.Format("15:04:05")))
+	for i, line := range lines {
+		lines[i] = truncateWidth(line, width)
+	}
+	if height > 0 && len(lines) > height {
+		lines = lines[:height]
+	}
+	return lines
+}
+
+// drawPanel writes a frame over the previous one
+func drawPanel(out io.Writer, lines []string) {
+	var b strings.Builder
+	b.WriteString("\x1b[H")
+	for _, line := range lines {
+		b.WriteString(line)
+		b.WriteString("\x1b[K\n")
+	}
+	b.WriteString("\x1b[J")
+	_, _ = io.WriteString(out, b.String())
+}
+
+// writeHeader starts the plain output of a watch
+func (p *watchPanel) writeHeader(out io.Writer) {
+	fmt.Fprintf(out, "%s: %s\n", p.issue.ID, p.issue.Title)
+	fmt.Fprintf(out, "Status: %s\n", p.issue.Status)
+	fmt.Fprintf(out, "Execution: %s\n", executionLine(p.state, time.Now()))
+}
+
+// writeChanges appends a line for each change of the status and execution
+// state since the last read
+func (p *watchPanel) writeChanges(out io.Writer, before *types.Issue, beforeState *types.IssueExecutionState) {
+	if p.issue.Status != before.Status {
+		fmt.Fprintf(out, "Status: %s -> %s\n", before.Status, p.issue.Status)
+	}
+	var state types.IssueExecutionState
+	if p.state != nil {
+		state = *p.state
+	}
+	if state.State != beforeState.State || state.ExecutorInstanceID != beforeState.ExecutorInstanceID {
+		fmt.Fprintf(out, "Execution: %s\n", executionLine(p.state, time.Now()))
+	}
+}
+
+// formatWatchEvent renders an agent event on one line
+func formatWatchEvent(event *events.AgentEvent) string {
+	return fmt.Sprintf("%s %s: %s", event.Timestamp.Local().Format("15:04:05"), event.Type, oneLine(event.Message))
+}
+
+// oneLine collapses whitespace, newlines included
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// truncateWidth cuts a line to width runes, marking the cut with "…"
+func truncateWidth(s string, width int) string {
+	if width <= 0 || utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:width-1]) + "…"
+}
+
+// finalSummary tells how a watched issue ended: its status and resolution,
+// the last attempt's outcome and summary, and the time spent on it
+func finalSummary(ctx context.Context, s storage.Storage, issue *types.Issue) (string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s is %s", issue.ID, issue.Status)
+	if issue.Status == types.StatusClosed && issue.Resolution != "" {
+		fmt.Fprintf(&b, ": %s", issue.Resolution)
+	}
+	b.WriteByte('\n')
+
+	history, err := s.GetExecutionHistory(ctx, issue.ID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get execution history: %w", err)
+	}
+	if len(history) > 0 {
+		last := history[len(history)-1]
+		outcome := "did not complete"
+		if last.Success != nil && *last.Success {
+			outcome = "succeeded"
+		} else if last.Success != nil {
+			outcome = "failed"
+		}
+		fmt.Fprintf(&b, "Last attempt (#%d) %s", last.AttemptNumber, outcome)
+		if last.CompletedAt != nil {
+			fmt.Fprintf(&b, " after %s", formatElapsedTime(last.CompletedAt.Sub(last.StartedAt)))
+		}
+		b.WriteByte('\n')
+		if summary := oneLine(last.Summary); summary != "" {
+			fmt.Fprintf(&b, "Summary: %s\n", summary)
+		}
+	}
+	if actual, err := estimates.ActualTime(ctx, s, issue.ID); err == nil {
+		if line := formatActual(issue.EstimatedMinutes, actual); line != "" {
+			fmt.Fprintf(&b, "Actual: %s\n", line)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n"), nil
+}
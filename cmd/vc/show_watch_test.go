@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage/storagetest"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// lockedBuffer is a bytes.Buffer safe to read while a watch writes it
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestWatchIssuePlain(t *testing.T) {
+	ctx := context.Background()
+	s := storagetest.NewFakeStorage()
+	issue := &types.Issue{Title: "Fix login", Status: types.StatusInProgress, Priority: 1, IssueType: types.TypeBug}
+	if err := s.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+	event := func(typ events.EventType, message string, data map[string]interface{}) *events.AgentEvent {
+		return &events.AgentEvent{Type: typ, Timestamp: time.Now(), IssueID: issue.ID, Severity: events.SeverityInfo, Message: message, Data: data}
+	}
+	if err := s.StoreAgentEvent(ctx, event(events.EventTypeIssueClaimed, "Issue claimed", nil)); err != nil {
+		t.Fatalf("StoreAgentEvent: %v", err)
+	}
+
+	var out lockedBuffer
+	done := make(chan error, 1)
+	go func() {
+		done <- watchIssue(ctx, s, issue.ID, &out, watchOptions{Interval: 10 * time.Millisecond})
+	}()
+	waitFor := func(want string) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for !strings.Contains(out.String(), want) {
+			if time.Now().After(deadline) {
+				t.Fatalf("Timed out waiting for %q in:\n%s", want, out.String())
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+	waitFor("issue_claimed: Issue claimed")
+
+	progress := event(events.EventTypeQualityGatesProgress, "Running lint", map[string]interface{}{"gates_completed": 1, "total_gates": 3, "current_gate": "lint"})
+	if err := s.StoreAgentEvent(ctx, progress); err != nil {
+		t.Fatalf("StoreAgentEvent: %v", err)
+	}
+	waitFor("quality_gates_progress: Running lint")
+	if err := s.CloseIssue(ctx, issue.ID, "fixed", "test"); err != nil {
+		t.Fatalf("CloseIssue: %v", err)
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("watchIssue: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("watchIssue did not return after the issue closed")
+	}
+	got := out.String()
+	for _, want := range []string{
+		issue.ID + ": Fix login\nStatus: in_progress\nExecution: not claimed\n",
+		"Status: in_progress -> closed\n",
+		"\n" + issue.ID + " is closed: fixed\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Output lacks %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "\x1b[") {
+		t.Errorf("Plain output has escape sequences:\n%q", got)
+	}
+}
+
+func TestWatchPanelRender(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.Local)
+	p := &watchPanel{
+		issue: &types.Issue{ID: "vc-7", Title: "Add a retry to the importer", Status: types.StatusInProgress, Priority: 2, IssueType: types.TypeTask},
+		state: &types.IssueExecutionState{ExecutorInstanceID: "a1b2c3d4-0000", State: types.ExecutionStateExecuting, StartedAt: now.Add(-192 * time.Second)},
+	}
+	for i := 1; i <= 20; i++ {
+		p.addEvent(&events.AgentEvent{ID: fmt.Sprint(i), Type: events.EventTypeProgress, Timestamp: now, Message: fmt.Sprintf("step %d", i)})
+	}
+	p.addEvent(&events.AgentEvent{ID: "21", Type: events.EventTypeQualityGatesProgress, Timestamp: now,
+		Data: map[string]interface{}{"gates_completed": float64(2), "total_gates": float64(3), "current_gate": "build"}})
+	if p.addEvent(&events.AgentEvent{ID: "21"}) {
+		t.Error("An event seen before should not be added again")
+	}
+
+	full := p.render(200, 50, now)
+	if len(full) != 6+watchEventLimit+1 {
+		t.Errorf("Got %d lines, want the header, %d events and the footer:\n%s", len(full), watchEventLimit, strings.Join(full, "\n"))
+	}
+	if !strings.Contains(full[2], "executing (executor a1b2c3d4, 3m12s)") || full[3] != "Quality gates: 2/3 done, running build" {
+		t.Errorf("Unexpected execution and gate lines: %q, %q", full[2], full[3])
+	}
+
+	short := p.render(20, 10, now)
+	if len(short) != 9 {
+		t.Errorf("Got %d lines on a 10 line terminal, want 9", len(short))
+	}
+	for _, line := range short {
+		if n := len([]rune(line)); n > 20 {
+			t.Errorf("Line wider than the terminal (%d): %q", n, line)
+		}
+	}
+	if !strings.Contains(short[len(short)-2], "quality_") {
+		t.Errorf("A short terminal should keep the newest events, got %q", short)
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// selectActorStats keeps the executor instances when executors is set, and
+// every actor otherwise
+func selectActorStats(stats []*types.ActorStatistics, executors bool) []*types.ActorStatistics {
+	if !executors {
+		return stats
+	}
+	var selected []*types.ActorStatistics
+	for _, s := range stats {
+		if s.Executor {
+			selected = append(selected, s)
+		}
+	}
+	return selected
+}
+
+// writeActorStatsJSON writes the rows as an indented JSON array
+func writeActorStatsJSON(w io.Writer, stats []*types.ActorStatistics) error {
+	if stats == nil {
+		stats = []*types.ActorStatistics{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(stats)
+}
+
+// writeActorStatsTable renders one row per actor for 'vc stats --by-actor'
+// and 'vc stats --by-executor'. Executor tables add the host and drop the
+// issue columns, which executors rarely populate.
+func writeActorStatsTable(w io.Writer, stats []*types.ActorStatistics, executors bool) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if executors {
+		fmt.Fprintln(tw, "EXECUTOR\tHOST\tATTEMPTS\tSUCCESS\tAVG DURATION\tGATE RUNS\tGATE PASS")
+	} else {
+		fmt.Fprintln(tw, "ACTOR\tCREATED\tCLOSED\tCOMMENTS\tATTEMPTS\tSUCCESS\tAVG DURATION\tGATE PASS")
+	}
+
+	for _, s := range stats {
+		success := formatRate(s.SuccessRate, s.ExecutionAttempts)
+		gatePass := formatRate(s.GatePassRate, s.GateRuns)
+		duration := "-"
+		if s.AverageDurationSeconds > 0 {
+			duration = formatSeconds(s.AverageDurationSeconds)
+		}
+
+		if executors {
+			host := s.Hostname
+			if host == "" {
+				host = "?"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\t%d\t%s\n",
+				s.Actor, host, s.ExecutionAttempts, success, duration, s.GateRuns, gatePass)
+		} else {
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%s\t%s\t%s\n",
+				s.Actor, s.IssuesCreated, s.IssuesClosed, s.Comments, s.ExecutionAttempts, success, duration, gatePass)
+		}
+	}
+	return tw.Flush()
+}
+
+// formatRate renders a fraction as a percentage, or "-" when nothing was counted
+func formatRate(rate float64, count int) string {
+	if count == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f%%", rate*100)
+}
+
+// formatSeconds renders a duration in seconds as e.g. "45s", "12m30s" or "2h5m"
+func formatSeconds(seconds float64) string {
+	total := int(seconds + 0.5)
+	switch {
+	case total < 60:
+		return fmt.Sprintf("%ds", total)
+	case total < 3600:
+		return fmt.Sprintf("%dm%ds", total/60, total%60)
+	default:
+		return fmt.Sprintf("%dh%dm", total/3600, (total%3600)/60)
+	}
+}
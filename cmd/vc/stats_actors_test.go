@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestActorStatsOutput(t *testing.T) {
+	stats := []*types.ActorStatistics{
+		{Actor: "alice", IssuesCreated: 4, IssuesClosed: 1, Comments: 2},
+		{Actor: "exec-1", Executor: true, Hostname: "build-01", IssuesClosed: 3,
+			ExecutionAttempts: 4, SuccessfulAttempts: 3, SuccessRate: 0.75, AverageDurationSeconds: 750,
+			GateRuns: 3, GatesPassed: 3, GatePassRate: 1},
+	}
+
+	executors := selectActorStats(stats, true)
+	if len(executors) != 1 || executors[0].Actor != "exec-1" {
+		t.Fatalf("Expected only exec-1, got %v", executors)
+	}
+	if got := selectActorStats(stats, false); len(got) != 2 {
+		t.Errorf("Expected every actor without --by-executor, got %d", len(got))
+	}
+
+	var buf bytes.Buffer
+	if err := writeActorStatsTable(&buf, stats, false); err != nil {
+		t.Fatalf("writeActorStatsTable failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "ACTOR") {
+		t.Fatalf("Unexpected actor table:\n%s", buf.String())
+	}
+	if fields := strings.Fields(lines[1]); strings.Join(fields, " ") != "alice 4 1 2 0 - - -" {
+		t.Errorf("Unexpected alice row: %q", lines[1])
+	}
+	if fields := strings.Fields(lines[2]); strings.Join(fields, " ") != "exec-1 0 3 0 4 75% 12m30s 100%" {
+		t.Errorf("Unexpected exec-1 row: %q", lines[2])
+	}
+
+	buf.Reset()
+	if err := writeActorStatsTable(&buf, executors, true); err != nil {
+		t.Fatalf("writeActorStatsTable failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "HOST") || !strings.Contains(buf.String(), "build-01") {
+		t.Errorf("Executor table is missing the host:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := writeActorStatsJSON(&buf, nil); err != nil {
+		t.Fatalf("writeActorStatsJSON failed: %v", err)
+	}
+	if strings.TrimSpace(buf.String()) != "[]" {
+		t.Errorf("Expected an empty JSON array, got %q", buf.String())
+	}
+	buf.Reset()
+	if err := writeActorStatsJSON(&buf, executors); err != nil {
+		t.Fatalf("writeActorStatsJSON failed: %v", err)
+	}
+	var decoded []types.ActorStatistics
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if len(decoded) != 1 || decoded[0].Hostname != "build-01" || decoded[0].GatesPassed != 3 {
+		t.Errorf("Unexpected decoded rows: %+v", decoded)
+	}
+}
+
+func TestFormatSeconds(t *testing.T) {
+	tests := map[float64]string{0.4: "0s", 45: "45s", 750: "12m30s", 7500: "2h5m"}
+	for seconds, want := range tests {
+		if got := formatSeconds(seconds); got != want {
+			t.Errorf("formatSeconds(%v) = %q, want %q", seconds, got, want)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage"
+)
+
+// aiRetryMetrics aggregates AI retry events (ai_retried,
+// ai_retries_exhausted) for display in `vc stats`.
+type aiRetryMetrics struct {
+	RetriedCalls   int // Calls that succeeded after retrying
+	ExhaustedCalls int // Calls that failed after using up their retries
+	Retries        int // Retries across all calls
+}
+
+// summarizeAIRetryEvents aggregates AI retry events into aiRetryMetrics
+func summarizeAIRetryEvents(eventList []*events.AgentEvent) aiRetryMetrics {
+	var m aiRetryMetrics
+	for _, event := range eventList {
+		switch event.Type {
+		case events.EventTypeAIRetried:
+			m.RetriedCalls++
+		case events.EventTypeAIRetriesExhausted:
+			m.ExhaustedCalls++
+		default:
+			continue
+		}
+		// Stored data round-trips through JSON, so numbers come back as float64
+		switch retries := event.Data["retries"].(type) {
+		case int:
+			m.Retries += retries
+		case float64:
+			m.Retries += int(retries)
+		}
+	}
+	return m
+}
+
+// loadAIRetryEvents fetches the AI retry events from storage
+func loadAIRetryEvents(ctx context.Context, s storage.Storage) ([]*events.AgentEvent, error) {
+	var result []*events.AgentEvent
+	for _, eventType := range []events.EventType{
+		events.EventTypeAIRetried,
+		events.EventTypeAIRetriesExhausted,
+	} {
+		eventList, err := s.GetAgentEvents(ctx, events.EventFilter{Type: eventType})
+		if err != nil {
+			return nil, fmt.Errorf("failed to query %s events: %w", eventType, err)
+		}
+		result = append(result, eventList...)
+	}
+	return result, nil
+}
+
+// aiUsageRow totals the ai_call_completed events of one purpose or day
+type aiUsageRow struct {
+	Key          string  `json:"key"`
+	Calls        int     `json:"calls"`
+	InputTokens  int64   `json:"input_tokens"`
+	OutputTokens int64   `json:"output_tokens"`
+	CostUSD      float64 `json:"estimated_cost_usd"`
+	Unpriced     int     `json:"unpriced_calls"` // Calls to models without a price, not in CostUSD
+	LatencyMs    int64   `json:"total_latency_ms"`
+}
+
+func (r *aiUsageRow) add(data *events.AICallData) {
+	r.Calls++
+	r.InputTokens += data.InputTokens
+	r.OutputTokens += data.OutputTokens
+	r.LatencyMs += data.LatencyMs
+	if data.Priced {
+		r.CostUSD += data.EstimatedCostUSD
+	} else {
+		r.Unpriced++
+	}
+}
+
+// aiMalformedRow counts the structured responses of one operation that
+// needed repair (ai_response_repaired) or a retry (ai_response_retried)
+type aiMalformedRow struct {
+	Operation   string `json:"operation"`
+	Calls       int    `json:"calls"`
+	Repaired    int    `json:"repaired"`
+	Retried     int    `json:"retried"`
+	RetryFailed int    `json:"retry_failed"`
+}
+
+// aiUsage is the `vc stats --ai` report
+type aiUsage struct {
+	Since     time.Time         `json:"since"`
+	Total     aiUsageRow        `json:"total"`
+	ByPurpose []*aiUsageRow     `json:"by_purpose"`          // Most expensive first
+	ByDay     []*aiUsageRow     `json:"by_day"`              // Oldest first, UTC days
+	Malformed []*aiMalformedRow `json:"malformed_responses"` // Most fragile first
+}
+
+// summarizeAICalls aggregates ai_call_completed events by purpose and by
+// UTC day, and repair/retry events by operation. Events with unparseable
+// data are skipped.
+func summarizeAICalls(eventList []*events.AgentEvent, since time.Time) *aiUsage {
+	usage := &aiUsage{Since: since, Total: aiUsageRow{Key: "total"}}
+	byPurpose := make(map[string]*aiUsageRow)
+	byDay := make(map[string]*aiUsageRow)
+	row := func(rows map[string]*aiUsageRow, key string) *aiUsageRow {
+		if rows[key] == nil {
+			rows[key] = &aiUsageRow{Key: key}
+		}
+		return rows[key]
+	}
+
+	calls := make(map[string]int)
+	malformed := make(map[string]*aiMalformedRow)
+	for _, event := range eventList {
+		switch event.Type {
+		case events.EventTypeAICallCompleted:
+			data, err := event.GetAICallData()
+			if err != nil {
+				continue
+			}
+			usage.Total.add(data)
+			row(byPurpose, data.Purpose).add(data)
+			row(byDay, event.Timestamp.UTC().Format("2006-01-02")).add(data)
+			calls[data.Operation]++
+		case events.EventTypeAIResponseRepaired, events.EventTypeAIResponseRetried:
+			data, err := event.GetAIResponseRepairData()
+			if err != nil {
+				continue
+			}
+			r := malformed[data.Operation]
+			if r == nil {
+				r = &aiMalformedRow{Operation: data.Operation}
+				malformed[data.Operation] = r
+			}
+			if event.Type == events.EventTypeAIResponseRepaired {
+				r.Repaired++
+			} else {
+				r.Retried++
+				if !data.Success {
+					r.RetryFailed++
+				}
+			}
+		}
+	}
+
+	for _, r := range byPurpose {
+		usage.ByPurpose = append(usage.ByPurpose, r)
+	}
+	sort.Slice(usage.ByPurpose, func(i, j int) bool {
+		a, b := usage.ByPurpose[i], usage.ByPurpose[j]
+		if a.CostUSD != b.CostUSD {
+			return a.CostUSD > b.CostUSD
+		}
+		if a.InputTokens+a.OutputTokens != b.InputTokens+b.OutputTokens {
+			return a.InputTokens+a.OutputTokens > b.InputTokens+b.OutputTokens
+		}
+		return a.Key < b.Key
+	})
+	for _, r := range byDay {
+		usage.ByDay = append(usage.ByDay, r)
+	}
+	sort.Slice(usage.ByDay, func(i, j int) bool { return usage.ByDay[i].Key < usage.ByDay[j].Key })
+	for op, r := range malformed {
+		r.Calls = calls[op]
+		usage.Malformed = append(usage.Malformed, r)
+	}
+	sort.Slice(usage.Malformed, func(i, j int) bool {
+		a, b := usage.Malformed[i], usage.Malformed[j]
+		if a.Retried != b.Retried {
+			return a.Retried > b.Retried
+		}
+		if a.Repaired != b.Repaired {
+			return a.Repaired > b.Repaired
+		}
+		return a.Operation < b.Operation
+	})
+	return usage
+}
+
+// loadAICallEvents fetches the ai_call_completed events, and the repair and
+// retry events of malformed responses, since the given time
+func loadAICallEvents(ctx context.Context, s storage.Storage, since time.Time) ([]*events.AgentEvent, error) {
+	var result []*events.AgentEvent
+	for _, eventType := range []events.EventType{
+		events.EventTypeAICallCompleted,
+		events.EventTypeAIResponseRepaired,
+		events.EventTypeAIResponseRetried,
+	} {
+		eventList, err := s.GetAgentEvents(ctx, events.EventFilter{Type: eventType, AfterTime: since})
+		if err != nil {
+			return nil, fmt.Errorf("failed to query %s events: %w", eventType, err)
+		}
+		result = append(result, eventList...)
+	}
+	return result, nil
+}
+
+// writeAIUsageJSON writes the report as indented JSON
+func writeAIUsageJSON(w io.Writer, usage *aiUsage) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(usage)
+}
+
+// writeAIUsageTable renders 'vc stats --ai': usage by purpose, then by day
+func writeAIUsageTable(w io.Writer, usage *aiUsage) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	writeRows := func(header string, rows []*aiUsageRow) {
+		fmt.Fprintf(tw, "%s\tCALLS\tINPUT TOKENS\tOUTPUT TOKENS\tEST. COST\tAVG LATENCY\n", header)
+		for _, r := range rows {
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%s\t%s\n",
+				r.Key, r.Calls, r.InputTokens, r.OutputTokens, formatCost(r), formatLatency(r))
+		}
+	}
+	writeRows("PURPOSE", usage.ByPurpose)
+	fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%s\t%s\n", "TOTAL", usage.Total.Calls,
+		usage.Total.InputTokens, usage.Total.OutputTokens, formatCost(&usage.Total), formatLatency(&usage.Total))
+	fmt.Fprintln(tw, "\t\t\t\t\t")
+	writeRows("DAY (UTC)", usage.ByDay)
+	if len(usage.Malformed) > 0 {
+		fmt.Fprintln(tw, "\t\t\t\t\t")
+		fmt.Fprintf(tw, "MALFORMED RESPONSES\tCALLS\tREPAIRED\tRETRIED\tRETRY FAILED\t\n")
+		for _, r := range usage.Malformed {
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t\n", r.Operation, r.Calls, r.Repaired, r.Retried, r.RetryFailed)
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if usage.Total.Unpriced > 0 {
+		fmt.Fprintf(w, "\n%d calls used models without a price (not in EST. COST); set executor.ai_prices\n", usage.Total.Unpriced)
+	}
+	return nil
+}
+
+// formatCost renders a row's estimated cost, marking rows with unpriced calls
+func formatCost(r *aiUsageRow) string {
+	cost := fmt.Sprintf("$%.4f", r.CostUSD)
+	if r.Unpriced > 0 {
+		cost += "+"
+	}
+	return cost
+}
+
+// formatLatency renders a row's average call latency
+func formatLatency(r *aiUsageRow) string {
+	if r.Calls == 0 {
+		return "-"
+	}
+	return (time.Duration(r.LatencyMs/int64(r.Calls)) * time.Millisecond).String()
+}
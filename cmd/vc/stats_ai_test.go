@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+)
+
+func TestSummarizeAIRetryEvents(t *testing.T) {
+	eventList := []*events.AgentEvent{
+		{Type: events.EventTypeAIRetried, Data: map[string]interface{}{"retries": 2}},
+		// As read back from the database
+		{Type: events.EventTypeAIRetried, Data: map[string]interface{}{"retries": float64(1)}},
+		{Type: events.EventTypeAIRetriesExhausted, Data: map[string]interface{}{"retries": float64(3)}},
+		{Type: events.EventTypeSandboxCreated, Data: map[string]interface{}{"retries": 7}},
+	}
+
+	m := summarizeAIRetryEvents(eventList)
+	if m.RetriedCalls != 2 || m.ExhaustedCalls != 1 || m.Retries != 6 {
+		t.Errorf("Unexpected metrics: %+v", m)
+	}
+}
+
+func TestSummarizeAICalls(t *testing.T) {
+	day1 := time.Date(2025, 3, 1, 23, 0, 0, 0, time.UTC)
+	day2 := day1.Add(2 * time.Hour)
+	newEvent := func(at time.Time, data events.AICallData) *events.AgentEvent {
+		event, err := events.NewAICallCompletedEvent("vc-1", "test", data)
+		if err != nil {
+			t.Fatalf("Failed to create event: %v", err)
+		}
+		event.Timestamp = at
+		return event
+	}
+	eventList := []*events.AgentEvent{
+		newEvent(day1, events.AICallData{Purpose: "assessment", InputTokens: 100, OutputTokens: 10, LatencyMs: 100, EstimatedCostUSD: 0.5, Priced: true}),
+		newEvent(day2, events.AICallData{Purpose: "assessment", InputTokens: 200, OutputTokens: 20, LatencyMs: 300, EstimatedCostUSD: 1, Priced: true}),
+		newEvent(day2, events.AICallData{Purpose: "dedup", InputTokens: 50, OutputTokens: 5, LatencyMs: 50}),
+		{Type: events.EventTypeAIRetried, Timestamp: day2},
+	}
+
+	usage := summarizeAICalls(eventList, day1)
+	if usage.Total.Calls != 3 || usage.Total.InputTokens != 350 || usage.Total.CostUSD != 1.5 || usage.Total.Unpriced != 1 {
+		t.Errorf("Unexpected total: %+v", usage.Total)
+	}
+	if len(usage.ByPurpose) != 2 || usage.ByPurpose[0].Key != "assessment" || usage.ByPurpose[0].Calls != 2 {
+		t.Errorf("Unexpected purposes: %+v", usage.ByPurpose)
+	}
+	if len(usage.ByDay) != 2 || usage.ByDay[0].Key != "2025-03-01" || usage.ByDay[1].Calls != 2 {
+		t.Errorf("Unexpected days: %+v", usage.ByDay)
+	}
+
+	var out bytes.Buffer
+	if err := writeAIUsageTable(&out, usage); err != nil {
+		t.Fatalf("writeAIUsageTable: %v", err)
+	}
+	for _, want := range []string{"PURPOSE", "assessment", "$1.5000", "$0.0000+", "200ms", "2025-03-02", "1 calls used models without a price"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q in:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestSummarizeAICalls_MalformedResponses(t *testing.T) {
+	now := time.Now()
+	call, err := events.NewAICallCompletedEvent("vc-1", "test", events.AICallData{Purpose: "dedup", Operation: "duplicate_check"})
+	if err != nil {
+		t.Fatalf("Failed to create event: %v", err)
+	}
+	repaired, err := events.NewAIResponseRepairedEvent("vc-1", "test", events.AIResponseRepairData{Operation: "duplicate_check", Repair: "extract", Success: true})
+	if err != nil {
+		t.Fatalf("Failed to create event: %v", err)
+	}
+	retried, err := events.NewAIResponseRetriedEvent("vc-1", "test", events.AIResponseRepairData{Operation: "assessment", Error: "bad"})
+	if err != nil {
+		t.Fatalf("Failed to create event: %v", err)
+	}
+
+	usage := summarizeAICalls([]*events.AgentEvent{call, call, repaired, retried}, now)
+	if len(usage.Malformed) != 2 {
+		t.Fatalf("Expected 2 malformed rows, got %+v", usage.Malformed)
+	}
+	if r := usage.Malformed[0]; r.Operation != "assessment" || r.Retried != 1 || r.RetryFailed != 1 {
+		t.Errorf("Unexpected first row: %+v", r)
+	}
+	if r := usage.Malformed[1]; r.Operation != "duplicate_check" || r.Calls != 2 || r.Repaired != 1 {
+		t.Errorf("Unexpected second row: %+v", r)
+	}
+
+	var out bytes.Buffer
+	if err := writeAIUsageTable(&out, usage); err != nil {
+		t.Fatalf("writeAIUsageTable: %v", err)
+	}
+	if !strings.Contains(out.String(), "MALFORMED RESPONSES") {
+		t.Errorf("Expected malformed section in:\n%s", out.String())
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/steveyegge/vc/internal/estimates"
+)
+
+// writeEstimateStatsJSON writes the report as indented JSON
+func writeEstimateStatsJSON(w io.Writer, report *estimates.Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// writeEstimateStatsTable renders 'vc stats --estimates': the ratios by
+// issue type, then by estimate bucket, then overall
+func writeEstimateStatsTable(w io.Writer, report *estimates.Report) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	row := func(group *estimates.Group) {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s-%s\n", group.Name, group.Issues, estimates.FormatRatio(group.MedianRatio),
+			estimates.FormatRatio(group.P25Ratio), estimates.FormatRatio(group.P75Ratio))
+	}
+	fmt.Fprintln(tw, "TYPE\tISSUES\tMEDIAN\tMIDDLE HALF")
+	for _, group := range report.ByType {
+		row(group)
+	}
+	fmt.Fprintln(tw, "\t\t\t")
+	fmt.Fprintln(tw, "ESTIMATE\tISSUES\tMEDIAN\tMIDDLE HALF")
+	for _, group := range report.ByBucket {
+		row(group)
+	}
+	fmt.Fprintln(tw, "\t\t\t")
+	row(report.Overall)
+	return tw.Flush()
+}
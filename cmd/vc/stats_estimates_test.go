@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/estimates"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestWriteEstimateStatsTable(t *testing.T) {
+	report := estimates.NewReport([]estimates.Sample{
+		{IssueID: "vc-1", IssueType: types.TypeBug, Estimate: 30, Actual: 60},
+		{IssueID: "vc-2", IssueType: types.TypeTask, Estimate: 240, Actual: 720},
+	})
+	var out bytes.Buffer
+	if err := writeEstimateStatsTable(&out, report); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	want := [][]string{
+		{"TYPE", "ISSUES", "MEDIAN", "MIDDLE", "HALF"},
+		{"bug", "1", "2.0x", "2.0x-2.0x"},
+		{"task", "1", "3.0x", "3.0x-3.0x"},
+		nil,
+		{"ESTIMATE", "ISSUES", "MEDIAN", "MIDDLE", "HALF"},
+		{"up", "to", "30m", "1", "2.0x", "2.0x-2.0x"},
+		{"2h-8h", "1", "3.0x", "3.0x-3.0x"},
+		nil,
+		{"all", "2", "2.0x", "2.0x-3.0x"},
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("Expected %d lines, got:\n%s", len(want), out.String())
+	}
+	for i, fields := range want {
+		if got := strings.Fields(lines[i]); strings.Join(got, " ") != strings.Join(fields, " ") {
+			t.Errorf("Line %d = %q, want %q", i, got, fields)
+		}
+	}
+}
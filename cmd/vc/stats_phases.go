@@ -0,0 +1,139 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// phaseStatsRow summarizes the time attempts spent in one execution phase.
+// Percentiles use the nearest-rank method, like the flow metrics.
+type phaseStatsRow struct {
+	Phase     types.ExecutionPhase `json:"phase"`
+	Attempts  int                  `json:"attempts"`
+	MedianMs  int64                `json:"median_ms"`
+	P90Ms     int64                `json:"p90_ms"`
+	MaxMs     int64                `json:"max_ms"`
+	AverageMs int64                `json:"average_ms"`
+}
+
+// summarizePhases aggregates the phase timings of attempts, one row per
+// phase in the order phases run (unknown phases last, by name)
+func summarizePhases(attempts []*types.ExecutionAttempt) []*phaseStatsRow {
+	durations := make(map[types.ExecutionPhase][]int64)
+	for _, attempt := range attempts {
+		for _, p := range attempt.Phases {
+			durations[p.Phase] = append(durations[p.Phase], p.DurationMs)
+		}
+	}
+
+	phases := make([]types.ExecutionPhase, 0, len(durations))
+	for phase := range durations {
+		phases = append(phases, phase)
+	}
+	order := func(phase types.ExecutionPhase) int {
+		if i := slices.Index(types.ExecutionPhases, phase); i >= 0 {
+			return i
+		}
+		return len(types.ExecutionPhases)
+	}
+	sort.Slice(phases, func(i, j int) bool {
+		if oi, oj := order(phases[i]), order(phases[j]); oi != oj {
+			return oi < oj
+		}
+		return phases[i] < phases[j]
+	})
+
+	rows := make([]*phaseStatsRow, 0, len(phases))
+	for _, phase := range phases {
+		sorted := slices.Clone(durations[phase])
+		slices.Sort(sorted)
+		var sum int64
+		for _, ms := range sorted {
+			sum += ms
+		}
+		percentile := func(p float64) int64 {
+			rank := max(int(math.Ceil(p*float64(len(sorted)))), 1)
+			return sorted[rank-1]
+		}
+		rows = append(rows, &phaseStatsRow{
+			Phase:     phase,
+			Attempts:  len(sorted),
+			MedianMs:  percentile(0.5),
+			P90Ms:     percentile(0.9),
+			MaxMs:     sorted[len(sorted)-1],
+			AverageMs: sum / int64(len(sorted)),
+		})
+	}
+	return rows
+}
+
+// writePhaseStatsJSON writes the rows as indented JSON
+func writePhaseStatsJSON(w io.Writer, rows []*phaseStatsRow) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(rows)
+}
+
+// writePhaseStatsTable renders 'vc stats --phases'
+func writePhaseStatsTable(w io.Writer, rows []*phaseStatsRow) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PHASE\tATTEMPTS\tMEDIAN\tP90\tMAX\tAVG")
+	for _, r := range rows {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\t%s\n", r.Phase, r.Attempts,
+			formatPhaseMs(r.MedianMs), formatPhaseMs(r.P90Ms), formatPhaseMs(r.MaxMs), formatPhaseMs(r.AverageMs))
+	}
+	return tw.Flush()
+}
+
+// writePhaseBreakdown renders the phases of each attempt that recorded
+// them, one column per phase, for 'vc history'
+func writePhaseBreakdown(w io.Writer, history []*types.ExecutionAttempt) error {
+	var phases []types.ExecutionPhase
+	for _, phase := range summarizePhases(history) {
+		phases = append(phases, phase.Phase)
+	}
+	if len(phases) == 0 {
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	header := []string{"ATTEMPT"}
+	for _, phase := range phases {
+		header = append(header, strings.ToUpper(string(phase)))
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, attempt := range history {
+		if len(attempt.Phases) == 0 {
+			continue
+		}
+		row := []string{fmt.Sprintf("%d", attempt.AttemptNumber)}
+		for _, phase := range phases {
+			cell := "-"
+			if i := slices.IndexFunc(attempt.Phases, func(p types.PhaseTiming) bool { return p.Phase == phase }); i >= 0 {
+				cell = formatPhaseMs(attempt.Phases[i].DurationMs)
+			}
+			row = append(row, cell)
+		}
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// formatPhaseMs renders a phase duration, to the tenth of a second below a
+// minute and to the second above
+func formatPhaseMs(ms int64) string {
+	d := time.Duration(ms) * time.Millisecond
+	if d < time.Minute {
+		return d.Round(100 * time.Millisecond).String()
+	}
+	return d.Round(time.Second).String()
+}
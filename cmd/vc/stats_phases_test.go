@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestSummarizePhases(t *testing.T) {
+	timed := func(n int, phases ...types.PhaseTiming) *types.ExecutionAttempt {
+		return &types.ExecutionAttempt{AttemptNumber: n, Phases: phases}
+	}
+	sandbox := func(ms int64) types.PhaseTiming {
+		return types.PhaseTiming{Phase: types.ExecutionPhaseSandbox, DurationMs: ms}
+	}
+	gates := func(ms int64) types.PhaseTiming {
+		return types.PhaseTiming{Phase: types.ExecutionPhaseGates, DurationMs: ms}
+	}
+	attempts := []*types.ExecutionAttempt{
+		timed(1, gates(60000), sandbox(1000)),
+		timed(2, sandbox(3000)),
+		timed(3, sandbox(2000), gates(120000)),
+		timed(4),
+	}
+
+	rows := summarizePhases(attempts)
+	if len(rows) != 2 || rows[0].Phase != types.ExecutionPhaseSandbox || rows[1].Phase != types.ExecutionPhaseGates {
+		t.Fatalf("Expected sandbox then gates, got %+v", rows)
+	}
+	if r := rows[0]; r.Attempts != 3 || r.MedianMs != 2000 || r.P90Ms != 3000 || r.MaxMs != 3000 || r.AverageMs != 2000 {
+		t.Errorf("Unexpected sandbox row: %+v", r)
+	}
+	if r := rows[1]; r.Attempts != 2 || r.MedianMs != 60000 || r.MaxMs != 120000 || r.AverageMs != 90000 {
+		t.Errorf("Unexpected gates row: %+v", r)
+	}
+
+	var out bytes.Buffer
+	if err := writePhaseStatsTable(&out, rows); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"PHASE", "sandbox", "2s", "1m30s"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q in:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	if err := writePhaseBreakdown(&out, attempts); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 || !strings.Contains(lines[0], "SANDBOX") || !strings.Contains(lines[0], "GATES") {
+		t.Fatalf("Expected a header and 3 timed attempts, got:\n%s", out.String())
+	}
+	if fields := strings.Fields(lines[2]); len(fields) != 3 || fields[1] != "3s" || fields[2] != "-" {
+		t.Errorf("Expected attempt 2 without gates, got %q", lines[2])
+	}
+}
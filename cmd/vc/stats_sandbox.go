@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage"
+)
+
+// sandboxMetrics aggregates sandbox lifecycle events (sandbox_created,
+// sandbox_cleaned, sandbox_cleanup_failed) for display in `vc stats`.
+type sandboxMetrics struct {
+	Created         int
+	CreateFailures  int
+	AvgCreateMs     int64
+	MaxCreateMs     int64
+	AvgDiskBytes    int64
+	MaxDiskBytes    int64
+	Cleaned         int
+	CleanupFailures int
+	AvgCleanupMs    int64
+	ReclaimedBytes  int64
+}
+
+// summarizeSandboxEvents aggregates sandbox lifecycle events into sandboxMetrics.
+// Events with unparseable data are skipped.
+func summarizeSandboxEvents(eventList []*events.AgentEvent) sandboxMetrics {
+	var m sandboxMetrics
+	var createMsTotal, diskTotal, cleanupMsTotal int64
+	var diskSamples, cleanupSamples int
+
+	for _, event := range eventList {
+		data, err := event.GetSandboxLifecycleData()
+		if err != nil {
+			continue
+		}
+
+		switch event.Type {
+		case events.EventTypeSandboxCreated:
+			if !data.Success {
+				m.CreateFailures++
+				continue
+			}
+			m.Created++
+			createMsTotal += data.DurationMs
+			if data.DurationMs > m.MaxCreateMs {
+				m.MaxCreateMs = data.DurationMs
+			}
+		case events.EventTypeSandboxCleaned:
+			m.Cleaned++
+			cleanupSamples++
+			cleanupMsTotal += data.DurationMs
+			m.ReclaimedBytes += data.DiskBytes
+			// Sizes are measured before cleanup; zero means not measured
+			if data.DiskBytes > 0 {
+				diskSamples++
+				diskTotal += data.DiskBytes
+				if data.DiskBytes > m.MaxDiskBytes {
+					m.MaxDiskBytes = data.DiskBytes
+				}
+			}
+		case events.EventTypeSandboxCleanupFailed:
+			m.CleanupFailures++
+		}
+	}
+
+	if m.Created > 0 {
+		m.AvgCreateMs = createMsTotal / int64(m.Created)
+	}
+	if diskSamples > 0 {
+		m.AvgDiskBytes = diskTotal / int64(diskSamples)
+	}
+	if cleanupSamples > 0 {
+		m.AvgCleanupMs = cleanupMsTotal / int64(cleanupSamples)
+	}
+
+	return m
+}
+
+// loadSandboxEvents fetches the sandbox lifecycle events since the given time
+func loadSandboxEvents(ctx context.Context, s storage.Storage, since time.Time) ([]*events.AgentEvent, error) {
+	var result []*events.AgentEvent
+	for _, eventType := range []events.EventType{
+		events.EventTypeSandboxCreated,
+		events.EventTypeSandboxCleaned,
+		events.EventTypeSandboxCleanupFailed,
+	} {
+		eventList, err := s.GetAgentEvents(ctx, events.EventFilter{Type: eventType, AfterTime: since})
+		if err != nil {
+			return nil, fmt.Errorf("failed to query %s events: %w", eventType, err)
+		}
+		result = append(result, eventList...)
+	}
+	return result, nil
+}
+
+// formatBytes formats a byte count using binary units (KiB, MiB, GiB)
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
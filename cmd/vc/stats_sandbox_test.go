@@ -0,0 +1,82 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/steveyegge/vc/internal/events"
+)
+
+func TestSummarizeSandboxEvents(t *testing.T) {
+	newEvent := func(eventType events.EventType, data events.SandboxLifecycleData) *events.AgentEvent {
+		event, err := events.NewSandboxLifecycleEvent(eventType, "vc-1", "exec-1", "", events.SeverityInfo, "test", data)
+		if err != nil {
+			t.Fatalf("Failed to create event: %v", err)
+		}
+		return event
+	}
+
+	eventList := []*events.AgentEvent{
+		newEvent(events.EventTypeSandboxCreated, events.SandboxLifecycleData{DurationMs: 100, Success: true}),
+		newEvent(events.EventTypeSandboxCreated, events.SandboxLifecycleData{DurationMs: 300, Success: true}),
+		newEvent(events.EventTypeSandboxCreated, events.SandboxLifecycleData{DurationMs: 200, Success: true}),
+		newEvent(events.EventTypeSandboxCreated, events.SandboxLifecycleData{DurationMs: 5, Success: false, Error: "worktree exists"}),
+		newEvent(events.EventTypeSandboxCleaned, events.SandboxLifecycleData{DurationMs: 40, DiskBytes: 1000, Success: true}),
+		newEvent(events.EventTypeSandboxCleaned, events.SandboxLifecycleData{DurationMs: 60, DiskBytes: 3000, Success: true}),
+		// Disk usage unknown - counted for timing but not for size
+		newEvent(events.EventTypeSandboxCleaned, events.SandboxLifecycleData{DurationMs: 50, Success: true}),
+		newEvent(events.EventTypeSandboxCleanupFailed, events.SandboxLifecycleData{DurationMs: 10, Error: "busy"}),
+	}
+
+	m := summarizeSandboxEvents(eventList)
+
+	if m.Created != 3 {
+		t.Errorf("Created = %d, want 3", m.Created)
+	}
+	if m.CreateFailures != 1 {
+		t.Errorf("CreateFailures = %d, want 1", m.CreateFailures)
+	}
+	if m.AvgCreateMs != 200 {
+		t.Errorf("AvgCreateMs = %d, want 200", m.AvgCreateMs)
+	}
+	if m.MaxCreateMs != 300 {
+		t.Errorf("MaxCreateMs = %d, want 300", m.MaxCreateMs)
+	}
+	if m.AvgDiskBytes != 2000 {
+		t.Errorf("AvgDiskBytes = %d, want 2000", m.AvgDiskBytes)
+	}
+	if m.MaxDiskBytes != 3000 {
+		t.Errorf("MaxDiskBytes = %d, want 3000", m.MaxDiskBytes)
+	}
+	if m.Cleaned != 3 {
+		t.Errorf("Cleaned = %d, want 3", m.Cleaned)
+	}
+	if m.AvgCleanupMs != 50 {
+		t.Errorf("AvgCleanupMs = %d, want 50", m.AvgCleanupMs)
+	}
+	if m.ReclaimedBytes != 4000 {
+		t.Errorf("ReclaimedBytes = %d, want 4000", m.ReclaimedBytes)
+	}
+	if m.CleanupFailures != 1 {
+		t.Errorf("CleanupFailures = %d, want 1", m.CleanupFailures)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		input    int64
+		expected string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KiB"},
+		{1536, "1.5 KiB"},
+		{1048576, "1.0 MiB"},
+		{5 * 1073741824, "5.0 GiB"},
+	}
+
+	for _, tt := range tests {
+		if got := formatBytes(tt.input); got != tt.expected {
+			t.Errorf("formatBytes(%d) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
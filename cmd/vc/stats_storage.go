@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage"
+)
+
+// slowQueryStats merges the storage_slow_queries events of several
+// processes for 'vc stats --storage'
+type slowQueryStats struct {
+	// Processes is the number of processes that recorded slow queries
+	Processes int `json:"processes"`
+	// Queries and Slow total the queries those processes timed and the
+	// ones over their threshold
+	Queries int64 `json:"queries"`
+	Slow    int64 `json:"slow"`
+	// Statements are merged by statement, slowest total first
+	Statements []*events.SlowStatement `json:"statements"`
+}
+
+// summarizeSlowQueries merges storage_slow_queries events. Events with
+// unparseable data are skipped.
+func summarizeSlowQueries(eventList []*events.AgentEvent) *slowQueryStats {
+	stats := &slowQueryStats{Statements: []*events.SlowStatement{}}
+	byStatement := make(map[string]*events.SlowStatement)
+	for _, event := range eventList {
+		if event.Type != events.EventTypeStorageSlowQueries {
+			continue
+		}
+		data, err := event.GetStorageSlowQueriesData()
+		if err != nil {
+			continue
+		}
+		stats.Processes++
+		stats.Queries += data.Queries
+		stats.Slow += data.Slow
+		for _, s := range data.Statements {
+			merged := byStatement[s.Statement]
+			if merged == nil {
+				merged = &events.SlowStatement{Statement: s.Statement}
+				byStatement[s.Statement] = merged
+				stats.Statements = append(stats.Statements, merged)
+			}
+			merged.Slow += s.Slow
+			merged.TotalMs += s.TotalMs
+			merged.MaxMs = max(merged.MaxMs, s.MaxMs)
+		}
+	}
+	sort.Slice(stats.Statements, func(i, j int) bool {
+		a, b := stats.Statements[i], stats.Statements[j]
+		if a.TotalMs != b.TotalMs {
+			return a.TotalMs > b.TotalMs
+		}
+		return a.Statement < b.Statement
+	})
+	return stats
+}
+
+// loadSlowQueryEvents fetches the storage_slow_queries events since the
+// given time
+func loadSlowQueryEvents(ctx context.Context, s storage.Storage, since time.Time) ([]*events.AgentEvent, error) {
+	eventList, err := s.GetAgentEvents(ctx, events.EventFilter{Type: events.EventTypeStorageSlowQueries, AfterTime: since})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s events: %w", events.EventTypeStorageSlowQueries, err)
+	}
+	return eventList, nil
+}
+
+// writeSlowQueryStatsJSON writes the report as indented JSON
+func writeSlowQueryStatsJSON(w io.Writer, stats *slowQueryStats) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(stats)
+}
+
+// writeSlowQueryStatsTable renders 'vc stats --storage'
+func writeSlowQueryStatsTable(w io.Writer, stats *slowQueryStats) error {
+	fmt.Fprintf(w, "%d of %d timed queries were slow, in %d processes\n\n", stats.Slow, stats.Queries, stats.Processes)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SLOW\tTOTAL\tAVG\tMAX\tSTATEMENT")
+	for _, s := range stats.Statements {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", s.Slow, formatQueryMs(s.TotalMs),
+			formatQueryMs(s.TotalMs/max(s.Slow, 1)), formatQueryMs(s.MaxMs), s.Statement)
+	}
+	return tw.Flush()
+}
+
+// formatQueryMs renders a query duration to the millisecond
+func formatQueryMs(ms int64) string {
+	return (time.Duration(ms) * time.Millisecond).String()
+}
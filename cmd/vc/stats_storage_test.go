@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/events"
+)
+
+func TestSummarizeSlowQueries(t *testing.T) {
+	newEvent := func(data events.StorageSlowQueriesData) *events.AgentEvent {
+		event, err := events.NewStorageSlowQueriesEvent("slow queries", data)
+		if err != nil {
+			t.Fatalf("Failed to create event: %v", err)
+		}
+		return event
+	}
+	eventList := []*events.AgentEvent{
+		newEvent(events.StorageSlowQueriesData{ThresholdMs: 100, Queries: 500, Slow: 3, Statements: []events.SlowStatement{
+			{Statement: "beads.GetReadyWork", Slow: 2, TotalMs: 400, MaxMs: 250},
+			{Statement: "SELECT * FROM vc_agent_events WHERE issue_id = ?", Slow: 1, TotalMs: 120, MaxMs: 120},
+		}}),
+		newEvent(events.StorageSlowQueriesData{ThresholdMs: 100, Queries: 100, Slow: 2, Statements: []events.SlowStatement{
+			{Statement: "SELECT * FROM vc_agent_events WHERE issue_id = ?", Slow: 2, TotalMs: 900, MaxMs: 600},
+		}}),
+		{Type: events.EventTypeAIRetried},
+	}
+
+	stats := summarizeSlowQueries(eventList)
+	if stats.Processes != 2 || stats.Queries != 600 || stats.Slow != 5 || len(stats.Statements) != 2 {
+		t.Fatalf("Unexpected stats: %+v", stats)
+	}
+	if s := stats.Statements[0]; !strings.HasPrefix(s.Statement, "SELECT") || s.Slow != 3 || s.TotalMs != 1020 || s.MaxMs != 600 {
+		t.Errorf("Expected the event query merged and first, got %+v", s)
+	}
+
+	var out bytes.Buffer
+	if err := writeSlowQueryStatsTable(&out, stats); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"5 of 600 timed queries were slow, in 2 processes", "STATEMENT", "1.02s", "340ms", "beads.GetReadyWork"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q in:\n%s", want, out.String())
+		}
+	}
+}
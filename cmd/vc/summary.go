@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/digest"
+)
+
+var summaryCmd = &cobra.Command{
+	Use:   "summary",
+	Short: "Show a digest of what the colony did recently",
+	Long: `Show one screen for standup: the issues completed in the period, the
+issues whose attempts failed, what got blocked and what was discovered, the
+AI calls, tokens and estimated cost, and the top of the ready queue.
+
+Sections and rows always come in the same order, so the digests of two days
+can be diffed. --markdown renders it for pasting into chat, and --post-to
+sends that markdown to a chat webhook (Slack, Mattermost and others accept
+its {"text": ...} body).
+
+Executors record the same digest once a day as a daily_summary event when
+executor.daily_summary is on.
+
+Examples:
+  vc summary                           # The last 24 hours
+  vc summary --since 7d --markdown     # The last week, for chat
+  vc summary --post-to https://hooks.slack.com/services/...`,
+	Run: func(cmd *cobra.Command, args []string) {
+		sinceFlag, _ := cmd.Flags().GetString("since")
+		markdown, _ := cmd.Flags().GetBool("markdown")
+		postTo, _ := cmd.Flags().GetString("post-to")
+
+		window, err := parseSince(sinceFlag)
+		if err != nil || window <= 0 {
+			fmt.Fprintf(os.Stderr, "Error: invalid --since value %q (e.g., 24h, 7d)\n", sinceFlag)
+			os.Exit(1)
+		}
+
+		ctx := context.Background()
+		now := time.Now()
+		d, err := digest.Build(ctx, store, now.Add(-window), now, digest.Options{Project: mustCurrentProject(ctx)})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if postTo != "" {
+			if err := digest.Post(ctx, postTo, d.Markdown()); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("Posted summary: %s\n", d.Headline())
+			return
+		}
+
+		if markdown {
+			err = d.WriteMarkdown(os.Stdout)
+		} else {
+			err = d.WriteText(os.Stdout)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	summaryCmd.Flags().String("since", "24h", "Period to summarize (e.g., 24h, 7d)")
+	summaryCmd.Flags().Bool("markdown", false, "Render the digest as markdown")
+	summaryCmd.Flags().String("post-to", "", "Post the markdown digest to this chat webhook URL instead of printing it")
+	rootCmd.AddCommand(summaryCmd)
+}
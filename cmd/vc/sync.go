@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/github"
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Sync issues with other trackers",
+}
+
+var syncGitHubCmd = &cobra.Command{
+	Use:   "github",
+	Short: "Sync linked issues with a GitHub repository",
+	Long: `Sync the titles and open/closed state of issues imported with
+'vc import github' with their GitHub issues, both ways.
+
+A change made on one side since the last sync is copied to the other. When
+both sides changed, the most recent write wins and a comment on the vc issue
+records the value it replaced. Closing a vc issue closes the GitHub issue with
+a comment giving the close reason, execution summary and commit.
+
+Each sync reads only GitHub issues updated since the last one. --full rereads
+all of them and checks linked issues GitHub no longer lists: deleted and
+transferred issues are unlinked (with a comment) and no longer synced.
+
+The token is read from GITHUB_TOKEN or GH_TOKEN and needs write access to
+issues.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		repo, _ := cmd.Flags().GetString("repo")
+		full, _ := cmd.Flags().GetBool("full")
+
+		token := github.TokenFromEnv()
+		if token == "" {
+			fmt.Fprintf(os.Stderr, "Error: set GITHUB_TOKEN or GH_TOKEN; syncing writes to GitHub\n")
+			os.Exit(1)
+		}
+		syncer, err := github.NewSyncer(store, github.NewClient("", token), repo, actor)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		result, err := syncer.Sync(context.Background(), full)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		writeSyncResult(os.Stdout, repo, result)
+		if len(result.Errors) > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+// writeSyncResult reports what 'vc sync github' changed
+func writeSyncResult(w io.Writer, repo string, result *github.SyncResult) {
+	sections := []struct {
+		title string
+		lines []string
+	}{
+		{"Updated in vc", result.Pulled},
+		{"Updated on GitHub", result.Pushed},
+		{"Conflicts (most recent write won)", result.Overwrites},
+		{"Unlinked", result.Unlinked},
+	}
+	changes := 0
+	for _, section := range sections {
+		if len(section.lines) == 0 {
+			continue
+		}
+		changes += len(section.lines)
+		fmt.Fprintf(w, "%s:\n", section.title)
+		for _, line := range section.lines {
+			fmt.Fprintf(w, "  %s\n", line)
+		}
+	}
+	if len(result.Errors) > 0 {
+		fmt.Fprintf(w, "Failed (retried on the next sync):\n")
+		for _, err := range result.Errors {
+			fmt.Fprintf(w, "  %v\n", err)
+		}
+		fmt.Fprintf(w, "Synced %s with %d changes and %d errors\n", repo, changes, len(result.Errors))
+		return
+	}
+	if changes == 0 {
+		fmt.Fprintf(w, "%s is in sync\n", repo)
+		return
+	}
+	fmt.Fprintf(w, "Synced %s with %d changes\n", repo, changes)
+}
+
+func init() {
+	syncGitHubCmd.Flags().String("repo", "", "GitHub repository as owner/name (required)")
+	syncGitHubCmd.Flags().Bool("full", false, "Reread every issue and detect deleted or transferred ones")
+	_ = syncGitHubCmd.MarkFlagRequired("repo")
+	syncCmd.AddCommand(syncGitHubCmd)
+	rootCmd.AddCommand(syncCmd)
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/github"
+)
+
+func TestWriteSyncResult(t *testing.T) {
+	var buf bytes.Buffer
+	writeSyncResult(&buf, "acme/widgets", &github.SyncResult{})
+	if got := buf.String(); got != "acme/widgets is in sync\n" {
+		t.Errorf("Unexpected output for an empty sync: %q", got)
+	}
+
+	buf.Reset()
+	writeSyncResult(&buf, "acme/widgets", &github.SyncResult{
+		Pulled:   []string{`vc-1: title from acme/widgets#1: "New"`},
+		Pushed:   []string{"vc-2: closed acme/widgets#2"},
+		Unlinked: []string{"vc-3: acme/widgets#3 was deleted"},
+		Errors:   []error{errors.New("failed to update acme/widgets#4")},
+	})
+	for _, want := range []string{"Updated in vc:\n  vc-1", "Updated on GitHub:\n  vc-2", "Unlinked:\n  vc-3",
+		"Failed (retried on the next sync):\n  failed to update", "with 3 changes and 1 errors"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Expected %q in:\n%s", want, buf.String())
+		}
+	}
+}
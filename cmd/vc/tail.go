@@ -93,10 +93,13 @@ func runTailFollow(ctx context.Context, issueID string, initialLimit int) {
 		displayEvent(events[i])
 	}
 
-	// Track the most recent event timestamp
-	var lastTimestamp time.Time
-	if len(events) > 0 {
-		lastTimestamp = events[0].Timestamp
+	// Track the newest event seen. Following by storage ID rather than
+	// timestamp never repeats or skips events that share a timestamp.
+	var lastID int64
+	for _, event := range events {
+		if cursor := event.Cursor(); cursor > lastID {
+			lastID = cursor
+		}
 	}
 
 	// Poll for new events
@@ -109,18 +112,17 @@ func runTailFollow(ctx context.Context, issueID string, initialLimit int) {
 			fmt.Println("\n\nStopped following")
 			return
 		case <-ticker.C:
-			// Fetch events newer than the last one we saw
-			newEvents, err := fetchEventsAfter(ctx, issueID, lastTimestamp)
+			// Fetch events stored after the last one we saw
+			newEvents, err := fetchEventsAfter(ctx, issueID, lastID)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "\nError fetching new events: %v\n", err)
 				continue
 			}
 
-			// Display new events in chronological order
-			for i := len(newEvents) - 1; i >= 0; i-- {
-				displayEvent(newEvents[i])
-				if newEvents[i].Timestamp.After(lastTimestamp) {
-					lastTimestamp = newEvents[i].Timestamp
+			for _, event := range newEvents {
+				displayEvent(event)
+				if cursor := event.Cursor(); cursor > lastID {
+					lastID = cursor
 				}
 			}
 		}
@@ -135,16 +137,26 @@ func fetchEvents(ctx context.Context, issueID string, limit int) ([]*events.Agen
 	return store.GetRecentAgentEvents(ctx, limit)
 }
 
-// fetchEventsAfter retrieves events that occurred after the given timestamp
-func fetchEventsAfter(ctx context.Context, issueID string, afterTime time.Time) ([]*events.AgentEvent, error) {
+// fetchEventsAfter retrieves events stored after the given cursor, oldest first.
+// A zero cursor (nothing seen yet) returns the most recent events.
+func fetchEventsAfter(ctx context.Context, issueID string, afterID int64) ([]*events.AgentEvent, error) {
 	filter := events.EventFilter{
-		AfterTime: afterTime,
-		Limit:     100, // Fetch up to 100 new events at a time
+		AfterID: afterID,
+		Limit:   100, // Fetch up to 100 new events at a time
 	}
 	if issueID != "" {
 		filter.IssueID = issueID
 	}
-	return store.GetAgentEvents(ctx, filter)
+	newEvents, err := store.GetAgentEvents(ctx, filter)
+	if err != nil || afterID > 0 {
+		return newEvents, err
+	}
+
+	// Without a cursor, events come back newest first
+	for i, j := 0, len(newEvents)-1; i < j; i, j = i+1, j-1 {
+		newEvents[i], newEvents[j] = newEvents[j], newEvents[i]
+	}
+	return newEvents, nil
 }
 
 // displayEvent formats and prints a single event with color
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"sort"
 	"testing"
 	"time"
 
@@ -166,3 +167,55 @@ func TestTailCommand(t *testing.T) {
 		}
 	})
 }
+
+func TestFetchEventsAfterCursor(t *testing.T) {
+	ctx := context.Background()
+	testStore, err := storage.NewStorage(ctx, &storage.Config{Path: t.TempDir() + "/test.db"})
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	defer func() { _ = testStore.Close() }()
+
+	oldStore := store
+	store = testStore
+	defer func() { store = oldStore }()
+
+	// Events sharing a timestamp used to be repeated or skipped by follow mode
+	now := time.Now()
+	for _, msg := range []string{"first", "second", "third"} {
+		event := &events.AgentEvent{Type: events.EventTypeProgress, Timestamp: now, Severity: events.SeverityInfo, Message: msg}
+		if err := testStore.StoreAgentEvent(ctx, event); err != nil {
+			t.Fatalf("Failed to store event: %v", err)
+		}
+	}
+
+	initial, err := fetchEventsAfter(ctx, "", 0)
+	if err != nil {
+		t.Fatalf("fetchEventsAfter failed: %v", err)
+	}
+	if len(initial) != 3 {
+		t.Fatalf("Expected 3 events without a cursor, got %d", len(initial))
+	}
+
+	var cursors []int64
+	for _, event := range initial {
+		cursors = append(cursors, event.Cursor())
+	}
+	sort.Slice(cursors, func(i, j int) bool { return cursors[i] < cursors[j] })
+
+	after, err := fetchEventsAfter(ctx, "", cursors[0])
+	if err != nil {
+		t.Fatalf("fetchEventsAfter failed: %v", err)
+	}
+	if len(after) != 2 || after[0].Cursor() != cursors[1] || after[1].Cursor() != cursors[2] {
+		t.Errorf("Expected the 2 later events oldest first, got %+v", after)
+	}
+
+	latest, err := fetchEventsAfter(ctx, "", cursors[2])
+	if err != nil {
+		t.Fatalf("fetchEventsAfter failed: %v", err)
+	}
+	if len(latest) != 0 {
+		t.Errorf("Expected no events after the newest cursor, got %d", len(latest))
+	}
+}
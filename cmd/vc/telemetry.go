@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/watchdog"
+)
+
+// staleTelemetryAge is how old a snapshot can get before the executor is
+// assumed to have moved on (snapshots are written about once a minute)
+const staleTelemetryAge = 3 * time.Minute
+
+var telemetryCmd = &cobra.Command{
+	Use:   "telemetry [issue-id]",
+	Short: "Show the watchdog's view of an execution",
+	Long: `Display the latest execution telemetry snapshot recorded by the executor.
+
+While an issue executes, the executor periodically snapshots what the
+watchdog monitor sees: the current state and how long it has been in it,
+the event rate, the last activity, and the state-transition history. This
+is the same signal the AI anomaly analyzer uses, and it answers "is it
+stuck or just slow?" from another terminal.
+
+Without an issue ID, the most recent snapshot from any execution is shown.
+
+Examples:
+  vc telemetry           # Latest snapshot from any execution
+  vc telemetry vc-123    # Latest snapshot for one issue`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		filter := events.EventFilter{Type: events.EventTypeExecutionTelemetry, Limit: 1}
+		if len(args) == 1 {
+			filter.IssueID = args[0]
+		}
+
+		snapshots, err := store.GetAgentEvents(ctx, filter)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error fetching telemetry: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(snapshots) == 0 {
+			yellow := color.New(color.FgYellow).SprintFunc()
+			if filter.IssueID != "" {
+				fmt.Printf("\n%s No telemetry recorded for issue %s\n\n", yellow("✨"), filter.IssueID)
+			} else {
+				fmt.Printf("\n%s No telemetry recorded yet\n\n", yellow("✨"))
+			}
+			return
+		}
+
+		event := snapshots[0]
+		data, err := event.GetExecutionTelemetryData()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		displayTelemetry(event, data, time.Now())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(telemetryCmd)
+}
+
+// displayTelemetry prints a telemetry snapshot. Durations that keep growing
+// while the execution runs (time in state, silence) are measured against now.
+func displayTelemetry(event *events.AgentEvent, data *events.ExecutionTelemetryData, now time.Time) {
+	cyan := color.New(color.FgCyan).SprintFunc()
+	gray := color.New(color.FgHiBlack).SprintFunc()
+
+	fmt.Printf("\n%s Execution telemetry for %s %s\n\n",
+		cyan("📡"), color.GreenString(event.IssueID),
+		gray(fmt.Sprintf("(snapshot %s ago, executor %s)", formatTelemetryAge(now.Sub(event.Timestamp)), event.ExecutorID)))
+
+	fmt.Printf("  State:          %s for %s\n",
+		color.MagentaString(data.State), formatTelemetryAge(now.Sub(data.StateSince)))
+	fmt.Printf("  Running for:    %s\n", formatTelemetryAge(now.Sub(data.StartedAt)))
+	fmt.Printf("  Events/minute:  %.1f (%d events)\n", data.EventsPerMinute, data.TotalEvents)
+	fmt.Printf("  Last activity:  %s ago\n", formatTelemetryAge(now.Sub(data.LastActivity)))
+	if data.ProtectionMode != "" && data.ProtectionMode != string(watchdog.ProtectionNormal) {
+		fmt.Printf("  Protection:     %s\n", color.YellowString(data.ProtectionMode))
+	}
+	if data.SilenceThresholdMs > 0 {
+		fmt.Printf("  Stall after:    %s of silence\n", formatTelemetryAge(time.Duration(data.SilenceThresholdMs)*time.Millisecond))
+	}
+
+	if len(data.EventCounts) > 0 {
+		fmt.Printf("\n  Events by type:\n")
+		for _, eventType := range sortedEventTypes(data.EventCounts) {
+			fmt.Printf("    %-28s %d\n", eventType, data.EventCounts[eventType])
+		}
+	}
+
+	if len(data.Transitions) > 0 {
+		fmt.Printf("\n  State transitions:\n")
+		for _, tr := range data.Transitions {
+			fmt.Printf("    %s  %s → %s\n", gray(tr.Timestamp.Format("15:04:05")), tr.From, tr.To)
+		}
+	}
+
+	if age := now.Sub(event.Timestamp); age > staleTelemetryAge {
+		fmt.Printf("\n  %s\n", color.YellowString("Snapshot is %s old; the execution may have finished or the executor stopped.",
+			formatTelemetryAge(age)))
+	}
+	fmt.Println()
+}
+
+// sortedEventTypes returns event types by count (highest first), then by name
+func sortedEventTypes(counts map[string]int) []string {
+	eventTypes := make([]string, 0, len(counts))
+	for eventType := range counts {
+		eventTypes = append(eventTypes, eventType)
+	}
+	sort.Slice(eventTypes, func(i, j int) bool {
+		if counts[eventTypes[i]] != counts[eventTypes[j]] {
+			return counts[eventTypes[i]] > counts[eventTypes[j]]
+		}
+		return eventTypes[i] < eventTypes[j]
+	})
+	return eventTypes
+}
+
+// formatTelemetryAge rounds a duration to whole seconds for display
+func formatTelemetryAge(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	return d.Round(time.Second).String()
+}
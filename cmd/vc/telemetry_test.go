@@ -0,0 +1,27 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSortedEventTypes(t *testing.T) {
+	counts := map[string]int{"progress": 2, "agent_tool_use": 9, "error": 2}
+
+	got := sortedEventTypes(counts)
+	want := []string{"agent_tool_use", "error", "progress"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sortedEventTypes() = %v, want %v", got, want)
+	}
+}
+
+func TestFormatTelemetryAge(t *testing.T) {
+	if got := formatTelemetryAge(90*time.Second + 400*time.Millisecond); got != "1m30s" {
+		t.Errorf("formatTelemetryAge() = %q, want 1m30s", got)
+	}
+	// Clock skew between executor and CLI host must not show negative ages
+	if got := formatTelemetryAge(-time.Second); got != "0s" {
+		t.Errorf("formatTelemetryAge(negative) = %q, want 0s", got)
+	}
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/templates"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+var templateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "List and show issue templates",
+	Long: `Issue templates pre-fill the description, design and acceptance criteria
+of new issues of a type. 'vc create' applies the type's template when no
+description is given, and 'vc create --edit' opens it in $EDITOR to fill in.
+
+Templates are Markdown files at .vc/templates/<type>.md under the project
+root. Level-1 headings split a template into sections:
+
+  # Description          (also the text before the first heading)
+  # Design
+  # Acceptance Criteria
+
+Any other level-1 heading is an error; use ## headings inside a section.
+Bugs and features have built-in templates, which a file replaces.`,
+}
+
+var templateListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List templates and check them",
+	Run: func(cmd *cobra.Command, args []string) {
+		entries, err := templates.List(templateRoot())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := writeTemplateTable(os.Stdout, entries); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		for _, entry := range entries {
+			if entry.Err != nil {
+				os.Exit(1)
+			}
+		}
+	},
+}
+
+// writeTemplateTable lists the templates with where each comes from
+func writeTemplateTable(w io.Writer, entries []templates.Entry) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TYPE\tSOURCE\tSTATUS")
+	for _, entry := range entries {
+		source := entry.Path
+		if source == "" {
+			source = "built-in"
+		}
+		status := "ok"
+		if entry.Err != nil {
+			status = entry.Err.Error()
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", entry.Type, source, status)
+	}
+	return tw.Flush()
+}
+
+var templateShowCmd = &cobra.Command{
+	Use:   "show [type]",
+	Short: "Show the template of an issue type",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		issueType := types.IssueType(args[0])
+		if !issueType.IsValid() {
+			fmt.Fprintf(os.Stderr, "Error: invalid issue type %q (bug|feature|task|epic|chore)\n", args[0])
+			os.Exit(1)
+		}
+		tmpl, err := templates.Load(templateRoot(), issueType)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if tmpl == nil {
+			fmt.Printf("No template for %s issues (add %s/%s.md)\n", issueType, templates.Dir, issueType)
+			return
+		}
+		source := tmpl.Path
+		if source == "" {
+			source = "built-in"
+		}
+		cyan := color.New(color.FgCyan).SprintFunc()
+		fmt.Printf("%s\n\n", cyan(fmt.Sprintf("%s template (%s)", issueType, source)))
+		issue := &types.Issue{}
+		tmpl.Apply(issue)
+		fmt.Print(templates.Render(issue))
+	},
+}
+
+// templateRoot returns the project root templates are read from, or "" if
+// it can't be found (only built-in templates apply then)
+func templateRoot() string {
+	root, err := storage.GetProjectRoot(dbPath)
+	if err != nil {
+		return ""
+	}
+	return root
+}
+
+// applyTemplate fills the sections of a new issue from its type's template
+// when no description was given
+func applyTemplate(issue *types.Issue) error {
+	if issue.Description != "" {
+		return nil
+	}
+	tmpl, err := templates.Load(templateRoot(), issue.IssueType)
+	if err != nil || tmpl == nil {
+		return err
+	}
+	tmpl.Apply(issue)
+	return nil
+}
+
+// editSections opens the issue's sections in $VISUAL or $EDITOR (default
+// vi) and reads back what was written
+func editSections(issue *types.Issue) error {
+	f, err := os.CreateTemp("", "vc-issue-*.md")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(templates.Render(issue)); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	argv := append(strings.Fields(editor), f.Name())
+	cmd := exec.Command(argv[0], argv[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor %s failed: %w", editor, err)
+	}
+
+	edited, err := os.Open(f.Name())
+	if err != nil {
+		return err
+	}
+	defer edited.Close()
+	sections, err := templates.Parse(edited)
+	if err != nil {
+		return fmt.Errorf("invalid issue text: %w", err)
+	}
+	issue.Description = sections.Description
+	issue.Design = sections.Design
+	issue.AcceptanceCriteria = sections.AcceptanceCriteria
+	return nil
+}
+
+func init() {
+	templateCmd.AddCommand(templateListCmd)
+	templateCmd.AddCommand(templateShowCmd)
+	rootCmd.AddCommand(templateCmd)
+}
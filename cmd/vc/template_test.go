@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/templates"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestApplyTemplate(t *testing.T) {
+	root := t.TempDir()
+	oldPath := dbPath
+	dbPath = filepath.Join(root, ".beads", "vc.db")
+	defer func() { dbPath = oldPath }()
+	if err := os.MkdirAll(filepath.Join(root, templates.Dir), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, templates.Dir, "task.md"), []byte("## Context\n\n# Acceptance Criteria\n\n- [ ] Done\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	task := &types.Issue{IssueType: types.TypeTask}
+	if err := applyTemplate(task); err != nil {
+		t.Fatal(err)
+	}
+	if task.Description != "## Context" || task.AcceptanceCriteria != "- [ ] Done" {
+		t.Errorf("Expected the task template applied, got %+v", task)
+	}
+	bug := &types.Issue{IssueType: types.TypeBug}
+	if err := applyTemplate(bug); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(bug.Description, "## Steps to reproduce") {
+		t.Errorf("Expected the built-in bug template, got %q", bug.Description)
+	}
+	given := &types.Issue{IssueType: types.TypeBug, Description: "It crashed"}
+	if err := applyTemplate(given); err != nil || given.Description != "It crashed" {
+		t.Errorf("An explicit description was replaced: %q, %v", given.Description, err)
+	}
+}
+
+func TestEditSections(t *testing.T) {
+	script := filepath.Join(t.TempDir(), "editor.sh")
+	body := "#!/bin/sh\ngrep -q 'Steps to reproduce' \"$1\" || exit 1\nprintf '# Description\\nIt crashed\\n# Design\\nGuard it\\n' > \"$1\"\n"
+	if err := os.WriteFile(script, []byte(body), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", script)
+
+	issue := &types.Issue{IssueType: types.TypeBug}
+	templates.Builtin(types.TypeBug).Apply(issue)
+	if err := editSections(issue); err != nil {
+		t.Fatal(err)
+	}
+	if issue.Description != "It crashed" || issue.Design != "Guard it" || issue.AcceptanceCriteria != "" {
+		t.Errorf("Edited sections not read back: %+v", issue)
+	}
+}
+
+func TestWriteTemplateTable(t *testing.T) {
+	entries := []templates.Entry{
+		{Type: types.TypeBug},
+		{Type: "story", Path: ".vc/templates/story.md", Err: errors.New("story is not an issue type")},
+	}
+	var buf bytes.Buffer
+	if err := writeTemplateTable(&buf, entries); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"built-in", "ok", "story.md", "not an issue type"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Table lacks %q:\n%s", want, buf.String())
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/estimates"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+var timeCmd = &cobra.Command{
+	Use:   "time",
+	Short: "Log working time on issues",
+	Long: `Log time spent on an issue outside of execution attempts: reviewing,
+pairing, fixing up what an agent left behind.
+
+An issue's actual time is the duration of its completed execution attempts
+plus the time logged here. 'vc show' prints it next to the estimate, and
+'vc stats --estimates' compares the two across closed issues.
+
+Durations are minutes (45) or Go durations (45m, 1h30m), rounded to the
+minute. Entries record who logged or last edited them (--actor).`,
+}
+
+var timeAddCmd = &cobra.Command{
+	Use:   "add [issue-id] [duration]",
+	Short: "Log time on an issue",
+	Example: `  vc time add vc-42 45m --note "Reviewed the agent's migration"
+  vc time add vc-42 1h30m`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		minutes, err := parseMinutes(args[1])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		note, _ := cmd.Flags().GetString("note")
+		entry := &types.TimeEntry{IssueID: args[0], Minutes: minutes, Note: note, Actor: actor}
+		if err := store.AddTimeEntry(context.Background(), entry); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Logged %s on %s (entry %d)\n", green("✓"), formatMinutes(minutes), entry.IssueID, entry.ID)
+	},
+}
+
+var timeListCmd = &cobra.Command{
+	Use:   "list [issue-id]",
+	Short: "List the time logged on an issue (or on every issue)",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		issueID := ""
+		if len(args) == 1 {
+			issueID = args[0]
+		}
+		entries, err := store.ListTimeEntries(context.Background(), issueID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if len(entries) == 0 {
+			fmt.Println("No time logged (add some with 'vc time add')")
+			return
+		}
+		if err := writeTimeEntryTable(os.Stdout, entries); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// writeTimeEntryTable lists time entries with their total
+func writeTimeEntryTable(w io.Writer, entries []*types.TimeEntry) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tISSUE\tTIME\tACTOR\tLOGGED\tNOTE")
+	total := 0
+	for _, e := range entries {
+		total += e.Minutes
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\n", e.ID, e.IssueID, formatMinutes(e.Minutes), e.Actor,
+			e.CreatedAt.Local().Format("2006-01-02 15:04"), e.Note)
+	}
+	fmt.Fprintf(tw, "\t\t%s\t\t\t\n", formatMinutes(total))
+	return tw.Flush()
+}
+
+var timeEditCmd = &cobra.Command{
+	Use:   "edit [entry-id] [duration]",
+	Short: "Change the time of an entry (and its note with --note)",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+		id := mustTimeEntryID(args[0])
+		minutes, err := parseMinutes(args[1])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		entry, err := store.GetTimeEntry(ctx, id)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if entry == nil {
+			fmt.Fprintf(os.Stderr, "Error: time entry %d not found\n", id)
+			os.Exit(1)
+		}
+		note := entry.Note
+		if cmd.Flags().Changed("note") {
+			note, _ = cmd.Flags().GetString("note")
+		}
+		if err := store.UpdateTimeEntry(ctx, id, minutes, note, actor); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Updated entry %d on %s: %s\n", green("✓"), id, entry.IssueID, formatMinutes(minutes))
+	},
+}
+
+var timeRemoveCmd = &cobra.Command{
+	Use:     "remove [entry-id]",
+	Aliases: []string{"rm"},
+	Short:   "Remove a time entry",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		id := mustTimeEntryID(args[0])
+		if err := store.DeleteTimeEntry(context.Background(), id); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Printf("%s Removed time entry %d\n", green("✓"), id)
+	},
+}
+
+// mustTimeEntryID parses a time entry ID argument, exiting if it isn't one
+func mustTimeEntryID(arg string) int64 {
+	id, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil || id <= 0 {
+		fmt.Fprintf(os.Stderr, "Error: invalid time entry ID %q (see 'vc time list')\n", arg)
+		os.Exit(1)
+	}
+	return id
+}
+
+// parseMinutes reads a duration given as minutes ("45") or as a Go
+// duration ("45m", "1h30m"), rounded to the minute
+func parseMinutes(s string) (int, error) {
+	if minutes, err := strconv.Atoi(s); err == nil {
+		if minutes < 1 {
+			return 0, fmt.Errorf("invalid duration %q: must be at least 1 minute", s)
+		}
+		return minutes, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q (e.g., 45, 45m, 1h30m)", s)
+	}
+	minutes := int(math.Round(d.Minutes()))
+	if minutes < 1 {
+		return 0, fmt.Errorf("invalid duration %q: must be at least 1 minute", s)
+	}
+	return minutes, nil
+}
+
+// formatMinutes renders minutes as e.g. "45m", "2h" or "1h30m"
+func formatMinutes(minutes int) string {
+	switch {
+	case minutes < 60:
+		return fmt.Sprintf("%dm", minutes)
+	case minutes%60 == 0:
+		return fmt.Sprintf("%dh", minutes/60)
+	}
+	return fmt.Sprintf("%dh%dm", minutes/60, minutes%60)
+}
+
+// formatActual renders 'vc show's line on an issue's actual time, e.g.
+// "2h (1 attempt, 30m logged; 2.0x the estimate)", or "" if no time was
+// spent on it
+func formatActual(estimate *int, actual *estimates.Actual) string {
+	if actual.Total() <= 0 {
+		return ""
+	}
+	var sources []string
+	if actual.Attempts > 0 {
+		attempts := fmt.Sprintf("%d attempt", actual.Attempts)
+		if actual.Attempts != 1 {
+			attempts += "s"
+		}
+		sources = append(sources, attempts)
+	}
+	if actual.Entries > 0 {
+		sources = append(sources, formatMinutes(actual.ManualMinutes)+" logged")
+	}
+	line := fmt.Sprintf("%s (%s", formatMinutes(actual.Minutes()), strings.Join(sources, ", "))
+	if estimate != nil && *estimate > 0 {
+		ratio := actual.Total().Minutes() / float64(*estimate)
+		line += fmt.Sprintf("; %s the estimate", estimates.FormatRatio(ratio))
+	}
+	return line + ")"
+}
+
+func init() {
+	timeAddCmd.Flags().String("note", "", "What the time was spent on")
+	timeEditCmd.Flags().String("note", "", "Replace the entry's note")
+	timeCmd.AddCommand(timeAddCmd)
+	timeCmd.AddCommand(timeListCmd)
+	timeCmd.AddCommand(timeEditCmd)
+	timeCmd.AddCommand(timeRemoveCmd)
+	rootCmd.AddCommand(timeCmd)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/estimates"
+)
+
+func TestFormatActual(t *testing.T) {
+	hour := 60
+	tests := []struct {
+		name     string
+		estimate *int
+		actual   estimates.Actual
+		want     string
+	}{
+		{"nothing spent", &hour, estimates.Actual{}, ""},
+		{"attempts and logged time", &hour, estimates.Actual{Attempts: 2, AttemptTime: 90 * time.Minute, Entries: 1, ManualMinutes: 30},
+			"2h (2 attempts, 30m logged; 2.0x the estimate)"},
+		{"no estimate", nil, estimates.Actual{Entries: 2, ManualMinutes: 45}, "45m (45m logged)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatActual(tt.estimate, &tt.actual); got != tt.want {
+				t.Errorf("formatActual() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMinutes(t *testing.T) {
+	for input, want := range map[string]int{"45": 45, "45m": 45, "1h30m": 90, "90s": 2} {
+		if got, err := parseMinutes(input); err != nil || got != want {
+			t.Errorf("parseMinutes(%q) = (%d, %v), want %d", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"0", "-5", "20s", "soon"} {
+		if _, err := parseMinutes(input); err == nil {
+			t.Errorf("parseMinutes(%q): expected an error", input)
+		}
+	}
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/chzyer/readline"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/artifacts"
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// transcriptPoll is how often --follow checks for new output and for the
+// end of the attempt
+const transcriptPoll = 500 * time.Millisecond
+
+var transcriptCmd = &cobra.Command{
+	Use:   "transcript [issue-id]",
+	Short: "Show the full output of an agent run",
+	Long: `Show everything the agent printed during an execution attempt, line by
+line as the executor saw it. Lines the agent wrote to stderr start with
+"[stderr] ".
+
+The executor writes each attempt's transcript next to its other artifacts
+(executor.artifacts_dir) and compresses it once the agent exits if it is
+over 256 KB. Transcripts are kept and swept like the other artifacts.
+
+Without --follow the transcript of the latest attempt (or --attempt N) is
+paged with $PAGER (default less) on a terminal. --follow prints the
+transcript of a running attempt as the agent writes it, until the attempt
+ends.`,
+	Example: `  vc transcript vc-42
+  vc transcript vc-42 --attempt 2 --tail 50
+  vc transcript vc-42 --follow`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		attemptNumber, _ := cmd.Flags().GetInt("attempt")
+		follow, _ := cmd.Flags().GetBool("follow")
+		tail, _ := cmd.Flags().GetInt("tail")
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		if err := runTranscript(ctx, args[0], attemptNumber, follow, tail); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// runTranscript shows, pages or follows the transcript of an attempt
+func runTranscript(ctx context.Context, issueID string, attemptNumber int, follow bool, tail int) error {
+	attempt, err := findAttempt(ctx, store, issueID, attemptNumber)
+	if err != nil {
+		return err
+	}
+	root, err := artifactsRoot(ctx)
+	if err != nil {
+		return err
+	}
+	path := transcriptOf(attempt, root)
+
+	if follow {
+		if path == "" && attempt.CompletedAt != nil {
+			return fmt.Errorf("attempt #%d of %s has no transcript", attempt.AttemptNumber, issueID)
+		}
+		finished := func() (bool, error) {
+			current, err := findAttempt(ctx, store, issueID, attempt.AttemptNumber)
+			return err != nil || current.CompletedAt != nil, err
+		}
+		if path == "" {
+			// The agent hasn't started writing yet
+			path = artifacts.TranscriptPath(root, issueID, attempt.AttemptNumber)
+		}
+		return followTranscript(ctx, os.Stdout, path, tail, finished, transcriptPoll)
+	}
+
+	if path == "" {
+		return fmt.Errorf("attempt #%d of %s has no transcript (artifacts off, swept, or the agent never ran)", attempt.AttemptNumber, issueID)
+	}
+	r, err := artifacts.OpenTranscript(path)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	if tail > 0 {
+		return writeLastLines(os.Stdout, r, tail)
+	}
+	if readline.IsTerminal(int(os.Stdout.Fd())) {
+		return page(r)
+	}
+	_, err = io.Copy(os.Stdout, r)
+	return err
+}
+
+// findAttempt returns the issue's attempt with the number, or its latest
+// with 0
+func findAttempt(ctx context.Context, s storage.Storage, issueID string, number int) (*types.ExecutionAttempt, error) {
+	history, err := s.GetExecutionHistory(ctx, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get execution history: %w", err)
+	}
+	if len(history) == 0 {
+		return nil, fmt.Errorf("%s has no execution attempts", issueID)
+	}
+	if number == 0 {
+		return history[len(history)-1], nil
+	}
+	for _, attempt := range history {
+		if attempt.AttemptNumber == number {
+			return attempt, nil
+		}
+	}
+	return nil, fmt.Errorf("%s has no attempt #%d (it has %d)", issueID, number, len(history))
+}
+
+// artifactsRoot returns the artifacts directory the executor writes to,
+// from the config files or the executor.artifacts_dir setting ("" = off)
+func artifactsRoot(ctx context.Context) (string, error) {
+	setting, err := config.GetConfigString(ctx, store, "executor.artifacts_dir")
+	if err != nil {
+		return "", err
+	}
+	if v, ok := fileConfig.Get("executor.artifacts_dir"); ok {
+		setting = v.Value
+	}
+	projectRoot, err := storage.GetProjectRoot(dbPath)
+	if err != nil {
+		return "", err
+	}
+	return resolveArtifactsDir(setting, projectRoot), nil
+}
+
+// transcriptOf returns the path of an attempt's transcript: the one
+// recorded with its artifacts, else the one a running attempt writes under
+// root. Returns "" if there is none.
+func transcriptOf(attempt *types.ExecutionAttempt, root string) string {
+	for _, path := range attempt.Artifacts {
+		if artifacts.IsTranscript(path) {
+			if _, err := os.Stat(path); err == nil {
+				return path
+			}
+		}
+	}
+	if root == "" {
+		return ""
+	}
+	return artifacts.FindTranscript(root, attempt.IssueID, attempt.AttemptNumber)
+}
+
+// writeLastLines writes the last n lines of r
+func writeLastLines(w io.Writer, r io.Reader, n int) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64<<10), 16<<20)
+	lines := make([]string, 0, n)
+	for scanner.Scan() {
+		if len(lines) == n {
+			lines = lines[1:]
+		}
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// page shows r in $PAGER (default less)
+func page(r io.Reader) error {
+	pager := os.Getenv("PAGER")
+	if pager == "" {
+		pager = "less"
+	}
+	argv := strings.Fields(pager)
+	cmd := exec.Command(argv[0], argv[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = r, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil // Quitting the pager early is fine
+		}
+		return fmt.Errorf("pager %s failed: %w", pager, err)
+	}
+	return nil
+}
+
+// followTranscript writes the transcript at path (its last tail lines with
+// tail > 0), then what the agent appends to it, until finished reports the
+// attempt ended and everything written is shown, or ctx is done. A missing
+// file is waited for.
+func followTranscript(ctx context.Context, w io.Writer, path string, tail int, finished func() (bool, error), poll time.Duration) error {
+	var file *os.File
+	for file == nil {
+		f, err := os.Open(path)
+		switch {
+		case err == nil:
+			file = f
+		case !errors.Is(err, os.ErrNotExist):
+			return err
+		default:
+			// A finished transcript may have been compressed meanwhile
+			if gz, err := os.Stat(path + ".gz"); err == nil && !gz.IsDir() {
+				r, err := artifacts.OpenTranscript(path + ".gz")
+				if err != nil {
+					return err
+				}
+				defer r.Close()
+				if tail > 0 {
+					return writeLastLines(w, r, tail)
+				}
+				_, err = io.Copy(w, r)
+				return err
+			}
+			if done, err := finished(); err != nil || done {
+				return err
+			}
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(poll):
+			}
+		}
+	}
+	defer file.Close()
+
+	if tail > 0 {
+		if err := writeLastLines(w, file, tail); err != nil {
+			return err
+		}
+	}
+	for {
+		// Check before reading, so the output written before the end is shown
+		done, err := finished()
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(w, file); err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(poll):
+		}
+	}
+}
+
+func init() {
+	transcriptCmd.Flags().Int("attempt", 0, "Attempt number (default: the latest)")
+	transcriptCmd.Flags().BoolP("follow", "f", false, "Print the output of a running attempt as it is written, until the attempt ends")
+	transcriptCmd.Flags().IntP("tail", "n", 0, "Only the last N lines")
+	rootCmd.AddCommand(transcriptCmd)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/artifacts"
+)
+
+func TestWriteLastLines(t *testing.T) {
+	var out strings.Builder
+	if err := writeLastLines(&out, strings.NewReader("a\nb\nc\nd\n"), 2); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "c\nd\n" {
+		t.Errorf("Got %q, want the last 2 lines", out.String())
+	}
+}
+
+func TestFollowTranscript(t *testing.T) {
+	path := artifacts.TranscriptPath(t.TempDir(), "vc-7", 1)
+	var finished atomic.Bool
+	var out lockedBuffer
+	done := make(chan error, 1)
+	go func() {
+		done <- followTranscript(context.Background(), &out, path, 0, func() (bool, error) {
+			return finished.Load(), nil
+		}, 5*time.Millisecond)
+	}()
+
+	// The transcript appears after the follow started
+	time.Sleep(20 * time.Millisecond)
+	tr, err := artifacts.CreateTranscript(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr.WriteLine("first")
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(out.String(), "first\n") {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for the first line, got %q", out.String())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	tr.WriteLine(artifacts.StderrPrefix + "last")
+	if _, err := tr.Close(); err != nil {
+		t.Fatal(err)
+	}
+	finished.Store(true)
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("followTranscript: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("followTranscript did not return after the attempt finished")
+	}
+	if out.String() != "first\n[stderr] last\n" {
+		t.Errorf("Got %q, want every line once", out.String())
+	}
+}
+
+func TestFollowTranscriptCompressed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), artifacts.TranscriptName)
+	tr, err := artifacts.CreateTranscript(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 400; i++ {
+		tr.WriteLine(strings.Repeat("y", 1000))
+	}
+	tr.WriteLine("end")
+	if final, err := tr.Close(); err != nil || final != path+".gz" {
+		t.Fatalf("Close() = %q, %v", final, err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("Expected only the compressed transcript, got %v", err)
+	}
+
+	var out strings.Builder
+	err = followTranscript(context.Background(), &out, path, 1, func() (bool, error) { return true, nil }, time.Millisecond)
+	if err != nil || out.String() != "end\n" {
+		t.Errorf("followTranscript() = %q, %v, want the last line of the compressed transcript", out.String(), err)
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/tui"
+)
+
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Interactive dashboard of ready work, executions and live events",
+	Long: `Show a terminal dashboard instead of running list, show and tail in loops:
+
+  Ready         The ready queue, by priority
+  In progress   Issues being executed, with their execution state and elapsed time
+  Events        Live agent events, as vc tail -f shows them
+
+Select a row and press Enter for the issue's details and history.
+
+Keys:
+  tab / shift-tab   Switch pane
+  ↑↓ / j k          Move (scroll in the detail view)
+  enter / esc       Open / close the detail view
+  c                 Close the selected issue (asks for a reason)
+  p                 Set its priority (0-4)
+  m                 Comment on it
+  P                 Pause or resume executors (executor.paused): a paused
+                    executor finishes what it is running and claims nothing new
+  r                 Refresh now
+  q / ctrl-c        Quit
+
+The dashboard reads the database while executors write it. The queues are
+re-read when an event arrives (at most once a second) and every 15 seconds
+otherwise. Needs a terminal; use vc tail, vc ready or vc serve elsewhere.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		opts := tui.Options{
+			Actor:   actor,
+			Project: mustCurrentProject(ctx),
+		}
+		if err := tui.Run(ctx, store, os.Stdin, os.Stdout, opts); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tuiCmd)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var unblockCmd = &cobra.Command{
+	Use:   "unblock [id...]",
+	Short: "Reopen blocked issues",
+	Long: `Reopen one or more blocked issues so executors pick them up again.
+
+The executor blocks an issue after three consecutive failed executions and
+labels it auto-blocked; 'vc list --label auto-blocked' lists them. Unblocking
+removes the label and records an issue_unblocked event. Fix whatever made the
+executions fail first, or the issue is blocked again after three more.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+		green := color.New(color.FgGreen).SprintFunc()
+
+		failed := false
+		for _, id := range args {
+			if err := store.UnblockIssue(ctx, id, actor); err != nil {
+				fmt.Fprintf(os.Stderr, "Error unblocking %s: %v\n", id, err)
+				failed = true
+				continue
+			}
+			fmt.Printf("%s Unblocked %s\n", green("✓"), id)
+		}
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(unblockCmd)
+}
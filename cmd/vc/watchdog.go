@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+var watchdogCmd = &cobra.Command{
+	Use:   "watchdog",
+	Short: "Inspect watchdog activity",
+	Long:  `Commands for inspecting what the watchdog has detected and how it intervened.`,
+}
+
+var watchdogHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show persisted watchdog interventions",
+	Long: `Display watchdog interventions recorded across executor restarts.
+
+Every intervention (pausing or killing an agent, requesting a checkpoint,
+escalating to a human) is persisted with the anomaly that triggered it,
+the action taken, and the escalation issue that was filed.
+
+Examples:
+  vc watchdog history                    # Show last 20 interventions
+  vc watchdog history --issue vc-123     # Interventions for one issue
+  vc watchdog history --since 24h        # Interventions in the last day
+  vc watchdog history --since 7d --stats # Per-anomaly summary for the week`,
+	Run: func(cmd *cobra.Command, args []string) {
+		limit, _ := cmd.Flags().GetInt("limit")
+		issueID, _ := cmd.Flags().GetString("issue")
+		sinceStr, _ := cmd.Flags().GetString("since")
+		showStats, _ := cmd.Flags().GetBool("stats")
+
+		ctx := context.Background()
+
+		filter := types.InterventionFilter{IssueID: issueID}
+		if sinceStr != "" {
+			since, err := parseSince(sinceStr)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: invalid --since value: %v\n", err)
+				os.Exit(1)
+			}
+			filter.Since = time.Now().Add(-since)
+		}
+		// Stats summarize the whole window, so don't truncate it
+		if !showStats {
+			filter.Limit = limit
+		}
+
+		records, err := store.GetInterventions(ctx, filter)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error fetching interventions: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(records) == 0 {
+			yellow := color.New(color.FgYellow).SprintFunc()
+			fmt.Printf("\n%s No watchdog interventions found\n\n", yellow("✨"))
+			return
+		}
+
+		if showStats {
+			displayInterventionStats(summarizeInterventions(records), len(records))
+			return
+		}
+
+		cyan := color.New(color.FgCyan).SprintFunc()
+		fmt.Printf("\n%s Watchdog Interventions (%d):\n\n", cyan("🐕"), len(records))
+
+		// Oldest first so the list reads top to bottom
+		for i := len(records) - 1; i >= 0; i-- {
+			displayIntervention(records[i])
+		}
+		fmt.Println()
+	},
+}
+
+func init() {
+	watchdogHistoryCmd.Flags().IntP("limit", "n", 20, "Number of recent interventions to show")
+	watchdogHistoryCmd.Flags().StringP("issue", "i", "", "Filter interventions by issue ID")
+	watchdogHistoryCmd.Flags().String("since", "", "Only show interventions newer than this (e.g., 2h, 24h, 7d)")
+	watchdogHistoryCmd.Flags().Bool("stats", false, "Summarize interventions per anomaly type")
+
+	watchdogCmd.AddCommand(watchdogHistoryCmd)
+	rootCmd.AddCommand(watchdogCmd)
+}
+
+// parseSince parses a lookback duration, accepting a "d" suffix for days
+// in addition to everything time.ParseDuration understands.
+func parseSince(s string) (time.Duration, error) {
+	if strings.HasSuffix(s, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(s, "d"))
+		if err != nil {
+			return 0, fmt.Errorf("invalid day count in %q", s)
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(s)
+}
+
+// interventionStats summarizes interventions for a single anomaly type
+type interventionStats struct {
+	AnomalyType   string
+	Count         int
+	Failures      int
+	Escalations   int
+	AvgConfidence float64
+	Actions       map[string]int
+}
+
+// summarizeInterventions groups interventions by anomaly type, most frequent first
+func summarizeInterventions(records []*types.InterventionRecord) []*interventionStats {
+	byType := make(map[string]*interventionStats)
+	for _, r := range records {
+		stats, ok := byType[r.AnomalyType]
+		if !ok {
+			stats = &interventionStats{AnomalyType: r.AnomalyType, Actions: make(map[string]int)}
+			byType[r.AnomalyType] = stats
+		}
+		stats.Count++
+		if !r.Success {
+			stats.Failures++
+		}
+		if r.EscalationIssueID != "" {
+			stats.Escalations++
+		}
+		// Running mean avoids a second pass
+		stats.AvgConfidence += (r.Confidence - stats.AvgConfidence) / float64(stats.Count)
+		stats.Actions[r.Action]++
+	}
+
+	result := make([]*interventionStats, 0, len(byType))
+	for _, stats := range byType {
+		result = append(result, stats)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].AnomalyType < result[j].AnomalyType
+	})
+	return result
+}
+
+// displayIntervention prints a single intervention record
+func displayIntervention(r *types.InterventionRecord) {
+	timestamp := r.CreatedAt.Format("2006-01-02 15:04:05")
+
+	statusIcon := color.GreenString("✓")
+	if !r.Success {
+		statusIcon = color.RedString("✗")
+	}
+
+	issue := r.IssueID
+	if issue == "" {
+		issue = "-"
+	}
+
+	fmt.Printf("%s %s %s %s %s (severity: %s, confidence: %.0f%%)\n",
+		statusIcon,
+		color.New(color.FgHiBlack).Sprint(timestamp),
+		color.GreenString(issue),
+		color.MagentaString(r.Action),
+		r.AnomalyType,
+		r.Severity,
+		r.Confidence*100)
+	if r.EscalationIssueID != "" {
+		fmt.Printf("    escalation: %s\n", color.CyanString(r.EscalationIssueID))
+	}
+	if r.PolicyEntry != "" {
+		fmt.Printf("    policy: %s\n", r.PolicyEntry)
+	}
+	if r.Outcome != "" {
+		fmt.Printf("    %s\n", r.Outcome)
+	}
+}
+
+// displayInterventionStats prints per-anomaly-type intervention summaries
+func displayInterventionStats(stats []*interventionStats, total int) {
+	cyan := color.New(color.FgCyan).SprintFunc()
+	fmt.Printf("\n%s Watchdog Intervention Summary (%d total):\n\n", cyan("🐕"), total)
+
+	for _, s := range stats {
+		fmt.Printf("  %s: %d intervention(s), %d escalation(s), %d failed, avg confidence %.0f%%\n",
+			color.New(color.Bold).Sprint(s.AnomalyType), s.Count, s.Escalations, s.Failures, s.AvgConfidence*100)
+
+		actions := make([]string, 0, len(s.Actions))
+		for action := range s.Actions {
+			actions = append(actions, action)
+		}
+		sort.Strings(actions)
+		for _, action := range actions {
+			fmt.Printf("    %-20s %d\n", action, s.Actions[action])
+		}
+	}
+	fmt.Println()
+}
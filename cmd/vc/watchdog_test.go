@@ -0,0 +1,66 @@
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestParseSince(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"24h", 24 * time.Hour, false},
+		{"30m", 30 * time.Minute, false},
+		{"7d", 7 * 24 * time.Hour, false},
+		{"xd", 0, true},
+		{"soon", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseSince(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseSince(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseSince(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestSummarizeInterventions(t *testing.T) {
+	records := []*types.InterventionRecord{
+		{AnomalyType: "infinite_loop", Action: "kill_agent", Confidence: 0.9, Success: true, EscalationIssueID: "vc-10"},
+		{AnomalyType: "infinite_loop", Action: "pause_agent", Confidence: 0.7, Success: true, EscalationIssueID: "vc-10"},
+		{AnomalyType: "infinite_loop", Action: "kill_agent", Confidence: 0.8, Success: false},
+		{AnomalyType: "context_exhaustion", Action: "request_checkpoint", Confidence: 0.95, Success: true, EscalationIssueID: "vc-11"},
+	}
+
+	stats := summarizeInterventions(records)
+	if len(stats) != 2 {
+		t.Fatalf("Expected 2 anomaly types, got %d", len(stats))
+	}
+
+	loop := stats[0]
+	if loop.AnomalyType != "infinite_loop" {
+		t.Fatalf("Expected most frequent type first, got %s", loop.AnomalyType)
+	}
+	if loop.Count != 3 || loop.Failures != 1 || loop.Escalations != 2 {
+		t.Errorf("Unexpected counts: %+v", loop)
+	}
+	if math.Abs(loop.AvgConfidence-0.8) > 1e-9 {
+		t.Errorf("AvgConfidence = %v, want 0.8", loop.AvgConfidence)
+	}
+	if loop.Actions["kill_agent"] != 2 || loop.Actions["pause_agent"] != 1 {
+		t.Errorf("Unexpected action breakdown: %v", loop.Actions)
+	}
+
+	if stats[1].AnomalyType != "context_exhaustion" || stats[1].Count != 1 {
+		t.Errorf("Unexpected second entry: %+v", stats[1])
+	}
+}
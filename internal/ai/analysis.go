@@ -6,7 +6,6 @@ import (
 	"os"
 	"time"
 
-	"github.com/anthropics/anthropic-sdk-go"
 	"github.com/steveyegge/vc/internal/types"
 )
 
@@ -44,55 +43,16 @@ func (s *Supervisor) AnalyzeExecutionResult(ctx context.Context, issue *types.Is
 	// Build the prompt for analysis
 	prompt := s.buildAnalysisPrompt(issue, agentOutput, success)
 
-	// Call Anthropic API with retry logic
-	var response *anthropic.Message
-	err := s.retryWithBackoff(ctx, "analysis", func(attemptCtx context.Context) error {
-		resp, apiErr := s.client.Messages.New(attemptCtx, anthropic.MessageNewParams{
-			Model:     anthropic.Model(s.model),
-			MaxTokens: 4096,
-			Messages: []anthropic.MessageParam{
-				anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
-			},
-		})
-		if apiErr != nil {
-			return apiErr
-		}
-		response = resp
-		return nil
-	})
-
+	// Call the AI provider with retry logic
+	analysis, response, err := completeStructured[Analysis](ctx, s, "analysis", CompletionRequest{
+		Model:     s.model,
+		MaxTokens: 4096,
+		Prompt:    prompt,
+		IssueID:   issue.ID,
+	}, "analysis response")
 	if err != nil {
-		return nil, fmt.Errorf("anthropic API call failed: %w", err)
-	}
-
-	// Extract the text content from the response
-	var responseText string
-	for _, block := range response.Content {
-		if block.Type == "text" {
-			responseText += block.Text
-		}
-	}
-
-	// Parse the response as JSON using resilient parser
-	// The parser automatically tries multiple strategies:
-	// 1. Direct JSON parse
-	// 2. Remove markdown code fences (```json, ```, etc.) and retry
-	// 3. Fix common JSON issues (trailing commas, unquoted keys, comments) and retry
-	// 4. Extract JSON from mixed content and retry
-	parseResult := Parse[Analysis](responseText, ParseOptions{
-		Context:   "analysis response",
-		LogErrors: boolPtr(true),
-	})
-	if !parseResult.Success {
-		// Show truncated response to avoid overwhelming logs
-		// Note: Error message shows ORIGINAL text, but parser tried cleaning strategies above
-		truncatedResponse := responseText
-		if len(responseText) > 500 {
-			truncatedResponse = responseText[:500] + "... (truncated)"
-		}
-		return nil, fmt.Errorf("%s\n\nNote: Parser tried all strategies (fence removal, JSON cleanup, extraction). Original response: %s", parseResult.Error, truncatedResponse)
+		return nil, err
 	}
-	analysis := parseResult.Data
 
 	// Log the analysis
 	duration := time.Since(startTime)
@@ -100,7 +60,7 @@ func (s *Supervisor) AnalyzeExecutionResult(ctx context.Context, issue *types.Is
 		issue.ID, analysis.Completed, len(analysis.DiscoveredIssues), len(analysis.QualityIssues), duration)
 
 	// Log AI usage to events
-	if err := s.logAIUsage(ctx, issue.ID, "analysis", response.Usage.InputTokens, response.Usage.OutputTokens, duration); err != nil {
+	if err := s.logAIUsage(ctx, issue.ID, "analysis", response.InputTokens, response.OutputTokens, duration); err != nil {
 		fmt.Fprintf(os.Stderr, "warning: failed to log AI usage: %v\n", err)
 	}
 
@@ -176,7 +136,9 @@ Provide your analysis as a JSON object:
       "description": "Issue description",
       "type": "bug|task|enhancement",
       "priority": "P0|P1|P2|P3",
-      "discovery_type": "blocker|related|background"
+      "discovery_type": "blocker|related|background",
+      "estimated_minutes": 30,
+      "priority_rationale": "Why this priority and type"
     }
   ],
   "quality_issues": ["Quality problem 1", ...],
@@ -189,6 +151,7 @@ RULES:
 2. Set "completed": false if ANY acceptance criterion was not met
 3. Be SPECIFIC in quality_issues - don't say "add tests", say "add unit tests for function X"
 4. If agent output was truncated, note this in the summary
+5. Prioritize each discovered issue by impact: P0 only for security holes, data loss or outages; P1 for bugs users will hit; P2 for ordinary work; P3 for nits and cleanup. Explain the choice in priority_rationale
 
 IMPORTANT: Respond with ONLY raw JSON. Do NOT wrap it in markdown code fences (`+"`"+`). Just the JSON object.`,
 		issue.ID, issue.Title, issue.Description, issue.AcceptanceCriteria,
@@ -7,18 +7,12 @@ import (
 	"strings"
 	"time"
 
-	"github.com/anthropics/anthropic-sdk-go"
 	"github.com/steveyegge/vc/internal/types"
 )
 
-// Assessment represents an AI assessment of an issue before execution
-type Assessment struct {
-	Strategy   string   `json:"strategy"`   // High-level strategy for completing the issue
-	Steps      []string `json:"steps"`      // Specific steps to take
-	Risks      []string `json:"risks"`      // Potential risks or challenges
-	Confidence float64  `json:"confidence"` // Confidence score (0.0-1.0)
-	Reasoning  string   `json:"reasoning"`  // Detailed reasoning
-}
+// Assessment represents an AI assessment of an issue before execution. It
+// lives in types so storage can keep it (types.AssessmentRecord).
+type Assessment = types.Assessment
 
 // CompletionAssessment represents AI assessment of whether an epic/mission is complete
 type CompletionAssessment struct {
@@ -28,52 +22,25 @@ type CompletionAssessment struct {
 	Caveats     []string `json:"caveats"`      // Any caveats or concerns
 }
 
-// AssessIssueState performs AI assessment before executing an issue
-func (s *Supervisor) AssessIssueState(ctx context.Context, issue *types.Issue) (*Assessment, error) {
+// AssessIssueState performs AI assessment before executing an issue.
+// estimateHint says how long similar past issues took against their
+// estimates ("" if unknown).
+func (s *Supervisor) AssessIssueState(ctx context.Context, issue *types.Issue, estimateHint string) (*Assessment, error) {
 	startTime := time.Now()
 
 	// Build the prompt for assessment
-	prompt := s.buildAssessmentPrompt(issue)
-
-	// Call Anthropic API with retry logic
-	var response *anthropic.Message
-	err := s.retryWithBackoff(ctx, "assessment", func(attemptCtx context.Context) error {
-		resp, apiErr := s.client.Messages.New(attemptCtx, anthropic.MessageNewParams{
-			Model:     anthropic.Model(s.model),
-			MaxTokens: 4096,
-			Messages: []anthropic.MessageParam{
-				anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
-			},
-		})
-		if apiErr != nil {
-			return apiErr
-		}
-		response = resp
-		return nil
-	})
-
+	prompt := s.buildAssessmentPrompt(issue, estimateHint)
+
+	// Call the AI provider with retry logic and parse the structured response
+	assessment, response, err := completeStructured[Assessment](ctx, s, "assessment", CompletionRequest{
+		Model:     s.model,
+		MaxTokens: 4096,
+		Prompt:    prompt,
+		IssueID:   issue.ID,
+	}, "assessment response")
 	if err != nil {
-		return nil, fmt.Errorf("anthropic API call failed: %w", err)
-	}
-
-	// Extract the text content from the response
-	var responseText string
-	for _, block := range response.Content {
-		if block.Type == "text" {
-			responseText += block.Text
-		}
-	}
-
-	// Parse the response as JSON using resilient parser
-	parseResult := Parse[Assessment](responseText, ParseOptions{
-		Context:   "assessment response",
-		LogErrors: boolPtr(true),
-	})
-	if !parseResult.Success {
-		// vc-227: Truncate AI response to prevent log spam
-		return nil, fmt.Errorf("failed to parse assessment response: %s (response: %s)", parseResult.Error, truncateString(responseText, 200))
+		return nil, err
 	}
-	assessment := parseResult.Data
 
 	// Log the assessment
 	duration := time.Since(startTime)
@@ -81,7 +48,7 @@ func (s *Supervisor) AssessIssueState(ctx context.Context, issue *types.Issue) (
 		issue.ID, assessment.Confidence, duration)
 
 	// Log AI usage to events
-	if err := s.logAIUsage(ctx, issue.ID, "assessment", response.Usage.InputTokens, response.Usage.OutputTokens, duration); err != nil {
+	if err := s.logAIUsage(ctx, issue.ID, "assessment", response.InputTokens, response.OutputTokens, duration); err != nil {
 		fmt.Fprintf(os.Stderr, "warning: failed to log AI usage: %v\n", err)
 	}
 
@@ -103,53 +70,24 @@ func (s *Supervisor) AssessCompletion(ctx context.Context, issue *types.Issue, c
 	// Build the prompt for completion assessment
 	prompt := s.buildCompletionPrompt(issue, children)
 
-	// Call Anthropic API with retry logic
-	var response *anthropic.Message
-	err := s.retryWithBackoff(ctx, "completion-assessment", func(attemptCtx context.Context) error {
-		resp, apiErr := s.client.Messages.New(attemptCtx, anthropic.MessageNewParams{
-			Model:     anthropic.Model(s.model),
-			MaxTokens: 2048, // Shorter responses for completion decisions
-			Messages: []anthropic.MessageParam{
-				anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
-			},
-		})
-		if apiErr != nil {
-			return apiErr
-		}
-		response = resp
-		return nil
-	})
-
+	// Call the AI provider with retry logic and parse the structured response
+	assessment, response, err := completeStructured[CompletionAssessment](ctx, s, "completion-assessment", CompletionRequest{
+		Model:     s.model,
+		MaxTokens: 2048, // Shorter responses for completion decisions
+		Prompt:    prompt,
+		IssueID:   issue.ID,
+	}, "completion assessment response")
 	if err != nil {
-		return nil, fmt.Errorf("anthropic API call failed: %w", err)
+		return nil, err
 	}
 
-	// Extract the text content from the response
-	var responseText string
-	for _, block := range response.Content {
-		if block.Type == "text" {
-			responseText += block.Text
-		}
-	}
-
-	// Parse the response as JSON using resilient parser
-	parseResult := Parse[CompletionAssessment](responseText, ParseOptions{
-		Context:   "completion assessment response",
-		LogErrors: boolPtr(true),
-	})
-	if !parseResult.Success {
-		// vc-227: Truncate AI response to prevent log spam
-		return nil, fmt.Errorf("failed to parse completion assessment response: %s (response: %s)", parseResult.Error, truncateString(responseText, 200))
-	}
-	assessment := parseResult.Data
-
 	// Log the assessment
 	duration := time.Since(startTime)
 	fmt.Printf("AI Completion Assessment for %s: should_close=%v, confidence=%.2f, duration=%v\n",
 		issue.ID, assessment.ShouldClose, assessment.Confidence, duration)
 
 	// Log AI usage to events
-	if err := s.logAIUsage(ctx, issue.ID, "completion-assessment", response.Usage.InputTokens, response.Usage.OutputTokens, duration); err != nil {
+	if err := s.logAIUsage(ctx, issue.ID, "completion-assessment", response.InputTokens, response.OutputTokens, duration); err != nil {
 		fmt.Fprintf(os.Stderr, "warning: failed to log AI usage for issue %s: %v\n", issue.ID, err)
 	}
 
@@ -157,14 +95,22 @@ func (s *Supervisor) AssessCompletion(ctx context.Context, issue *types.Issue, c
 }
 
 // buildAssessmentPrompt builds the prompt for assessing an issue before execution
-func (s *Supervisor) buildAssessmentPrompt(issue *types.Issue) string {
+func (s *Supervisor) buildAssessmentPrompt(issue *types.Issue, estimateHint string) string {
+	var estimate strings.Builder
+	if issue.EstimatedMinutes != nil {
+		fmt.Fprintf(&estimate, "Estimate: %d minutes\n", *issue.EstimatedMinutes)
+	}
+	if estimateHint != "" {
+		fmt.Fprintf(&estimate, "Estimate history: %s. Weigh this when judging scope and risk.\n", estimateHint)
+	}
+
 	return fmt.Sprintf(`You are an AI supervisor assessing a coding task before execution. Analyze the following issue and provide a structured assessment.
 
 Issue ID: %s
 Title: %s
 Type: %s
 Priority: %d
-
+%s
 Description:
 %s
 
@@ -190,7 +136,7 @@ Focus on:
 4. How confident are you this can be completed successfully?
 
 IMPORTANT: Respond with ONLY raw JSON. Do NOT wrap it in markdown code fences (`+"`"+`). Just the JSON object.`,
-		issue.ID, issue.Title, issue.IssueType, issue.Priority,
+		issue.ID, issue.Title, issue.IssueType, issue.Priority, estimate.String(),
 		issue.Description, issue.Design, issue.AcceptanceCriteria)
 }
 
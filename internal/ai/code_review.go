@@ -6,7 +6,6 @@ import (
 	"os"
 	"time"
 
-	"github.com/anthropics/anthropic-sdk-go"
 	"github.com/steveyegge/vc/internal/types"
 )
 
@@ -51,45 +50,16 @@ func (s *Supervisor) AnalyzeCodeReviewNeed(ctx context.Context, issue *types.Iss
 	// Build the prompt for code review decision
 	prompt := s.buildCodeReviewPrompt(issue, gitDiff)
 
-	// Call Anthropic API with retry logic using Haiku (fast and cheap)
-	var response *anthropic.Message
-	err := s.retryWithBackoff(ctx, "code-review-decision", func(attemptCtx context.Context) error {
-		resp, apiErr := s.client.Messages.New(attemptCtx, anthropic.MessageNewParams{
-			Model:     anthropic.Model("claude-3-5-haiku-20241022"), // Use Haiku for cost efficiency
-			MaxTokens: 1024,                                         // Short decision
-			Messages: []anthropic.MessageParam{
-				anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
-			},
-		})
-		if apiErr != nil {
-			return apiErr
-		}
-		response = resp
-		return nil
-	})
-
+	// Call the AI provider with retry logic using the fast model (cheap)
+	decision, response, err := completeStructured[CodeReviewDecision](ctx, s, "code-review-decision", CompletionRequest{
+		Model:     s.fastModel, // Use the fast model for cost efficiency
+		MaxTokens: 1024,        // Short decision
+		Prompt:    prompt,
+		IssueID:   issue.ID,
+	}, "code review decision response")
 	if err != nil {
-		return nil, fmt.Errorf("anthropic API call failed: %w", err)
-	}
-
-	// Extract the text content from the response
-	var responseText string
-	for _, block := range response.Content {
-		if block.Type == "text" {
-			responseText += block.Text
-		}
-	}
-
-	// Parse the response as JSON using resilient parser
-	parseResult := Parse[CodeReviewDecision](responseText, ParseOptions{
-		Context:   "code review decision response",
-		LogErrors: boolPtr(true),
-	})
-	if !parseResult.Success {
-		// vc-227: Truncate AI response to prevent log spam
-		return nil, fmt.Errorf("failed to parse code review decision response: %s (response: %s)", parseResult.Error, truncateString(responseText, 200))
+		return nil, err
 	}
-	decision := parseResult.Data
 
 	// Log the decision
 	duration := time.Since(startTime)
@@ -97,7 +67,7 @@ func (s *Supervisor) AnalyzeCodeReviewNeed(ctx context.Context, issue *types.Iss
 		issue.ID, decision.NeedsReview, decision.Confidence, duration)
 
 	// Log AI usage to events
-	if err := s.logAIUsage(ctx, issue.ID, "code-review-decision", response.Usage.InputTokens, response.Usage.OutputTokens, duration); err != nil {
+	if err := s.logAIUsage(ctx, issue.ID, "code-review-decision", response.InputTokens, response.OutputTokens, duration); err != nil {
 		fmt.Fprintf(os.Stderr, "warning: failed to log AI usage: %v\n", err)
 	}
 
@@ -120,53 +90,24 @@ func (s *Supervisor) AnalyzeTestCoverage(ctx context.Context, issue *types.Issue
 	// Build the prompt for test coverage analysis
 	prompt := s.buildTestCoveragePrompt(issue, gitDiff, existingTests)
 
-	// Call Anthropic API with retry logic using Sonnet (thorough analysis)
-	var response *anthropic.Message
-	err := s.retryWithBackoff(ctx, "test-coverage-analysis", func(attemptCtx context.Context) error {
-		resp, apiErr := s.client.Messages.New(attemptCtx, anthropic.MessageNewParams{
-			Model:     anthropic.Model(s.model), // Use Sonnet for thorough analysis
-			MaxTokens: 4096,                     // Longer responses for detailed analysis
-			Messages: []anthropic.MessageParam{
-				anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
-			},
-		})
-		if apiErr != nil {
-			return apiErr
-		}
-		response = resp
-		return nil
-	})
-
+	// Call the AI provider with retry logic using the default model (thorough analysis)
+	analysis, response, err := completeStructured[TestSufficiencyAnalysis](ctx, s, "test-coverage-analysis", CompletionRequest{
+		Model:     s.model, // Default model for thorough analysis
+		MaxTokens: 4096,    // Longer responses for detailed analysis
+		Prompt:    prompt,
+		IssueID:   issue.ID,
+	}, "test coverage analysis response")
 	if err != nil {
-		return nil, fmt.Errorf("anthropic API call failed: %w", err)
-	}
-
-	// Extract the text content from the response
-	var responseText string
-	for _, block := range response.Content {
-		if block.Type == "text" {
-			responseText += block.Text
-		}
+		return nil, err
 	}
 
-	// Parse the response as JSON using resilient parser
-	parseResult := Parse[TestSufficiencyAnalysis](responseText, ParseOptions{
-		Context:   "test coverage analysis response",
-		LogErrors: boolPtr(true),
-	})
-	if !parseResult.Success {
-		// vc-227: Truncate AI response to prevent log spam
-		return nil, fmt.Errorf("failed to parse test coverage analysis response: %s (response: %s)", parseResult.Error, truncateString(responseText, 200))
-	}
-	analysis := parseResult.Data
-
 	// Log the analysis
 	duration := time.Since(startTime)
 	fmt.Printf("AI Test Coverage Analysis for %s: sufficient=%v, test_issues=%d, confidence=%.2f, duration=%v\n",
 		issue.ID, analysis.SufficientCoverage, len(analysis.TestIssues), analysis.Confidence, duration)
 
 	// Log AI usage to events
-	if err := s.logAIUsage(ctx, issue.ID, "test-coverage-analysis", response.Usage.InputTokens, response.Usage.OutputTokens, duration); err != nil {
+	if err := s.logAIUsage(ctx, issue.ID, "test-coverage-analysis", response.InputTokens, response.OutputTokens, duration); err != nil {
 		fmt.Fprintf(os.Stderr, "warning: failed to log AI usage: %v\n", err)
 	}
 
@@ -190,45 +131,16 @@ func (s *Supervisor) AnalyzeCodeQuality(ctx context.Context, issue *types.Issue,
 	// Build the prompt for code quality analysis
 	prompt := s.buildCodeQualityPrompt(issue, gitDiff)
 
-	// Call Anthropic API with retry logic using Sonnet (thorough analysis)
-	var response *anthropic.Message
-	err := s.retryWithBackoff(ctx, "code-quality-analysis", func(attemptCtx context.Context) error {
-		resp, apiErr := s.client.Messages.New(attemptCtx, anthropic.MessageNewParams{
-			Model:     anthropic.Model(s.model), // Use Sonnet for thorough analysis
-			MaxTokens: 4096,                     // Longer responses for detailed analysis
-			Messages: []anthropic.MessageParam{
-				anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
-			},
-		})
-		if apiErr != nil {
-			return apiErr
-		}
-		response = resp
-		return nil
-	})
-
+	// Call the AI provider with retry logic using the default model (thorough analysis)
+	analysis, response, err := completeStructured[CodeQualityAnalysis](ctx, s, "code-quality-analysis", CompletionRequest{
+		Model:     s.model, // Default model for thorough analysis
+		MaxTokens: 4096,    // Longer responses for detailed analysis
+		Prompt:    prompt,
+		IssueID:   issue.ID,
+	}, "code quality analysis response")
 	if err != nil {
-		return nil, fmt.Errorf("anthropic API call failed: %w", err)
-	}
-
-	// Extract the text content from the response
-	var responseText string
-	for _, block := range response.Content {
-		if block.Type == "text" {
-			responseText += block.Text
-		}
-	}
-
-	// Parse the response as JSON using resilient parser
-	parseResult := Parse[CodeQualityAnalysis](responseText, ParseOptions{
-		Context:   "code quality analysis response",
-		LogErrors: boolPtr(true),
-	})
-	if !parseResult.Success {
-		// vc-227: Truncate AI response to prevent log spam
-		return nil, fmt.Errorf("failed to parse code quality analysis response: %s (response: %s)", parseResult.Error, truncateString(responseText, 200))
+		return nil, err
 	}
-	analysis := parseResult.Data
 
 	// Log the analysis
 	duration := time.Since(startTime)
@@ -236,7 +148,7 @@ func (s *Supervisor) AnalyzeCodeQuality(ctx context.Context, issue *types.Issue,
 		issue.ID, len(analysis.Issues), analysis.Confidence, duration)
 
 	// Log AI usage to events
-	if err := s.logAIUsage(ctx, issue.ID, "code-quality-analysis", response.Usage.InputTokens, response.Usage.OutputTokens, duration); err != nil {
+	if err := s.logAIUsage(ctx, issue.ID, "code-quality-analysis", response.InputTokens, response.OutputTokens, duration); err != nil {
 		fmt.Fprintf(os.Stderr, "warning: failed to log AI usage: %v\n", err)
 	}
 
@@ -55,30 +55,17 @@ func (s *Supervisor) CheckIssueDuplicate(ctx context.Context, candidate, existin
 	// Build prompt
 	prompt := s.buildDuplicateCheckPrompt(candidate, existing)
 
-	// Call AI with retry
-	var responseText string
-	var err error
-
-	err = s.retryWithBackoff(ctx, "duplicate_check", func(ctx context.Context) error {
-		responseText, err = s.CallAI(ctx, prompt, "duplicate_check", s.model, 1000)
-		return err
-	})
-
+	// Call AI with retry and parse the structured response
+	response, usage, err := completeStructured[DuplicateCheckResponse](ctx, s, "duplicate_check", CompletionRequest{
+		Model:     s.model,
+		MaxTokens: 1000,
+		Prompt:    prompt,
+		IssueID:   candidate.ID,
+	}, "duplicate check response")
 	if err != nil {
 		return nil, fmt.Errorf("AI duplicate check failed: %w", err)
 	}
 
-	// Parse response using resilient parser
-	parseResult := Parse[DuplicateCheckResponse](responseText, ParseOptions{
-		Context:   "duplicate check response",
-		LogErrors: boolPtr(true),
-	})
-	if !parseResult.Success {
-		// vc-227: Truncate AI response to prevent log spam
-		return nil, fmt.Errorf("failed to parse duplicate check response: %s (response: %s)", parseResult.Error, truncateString(responseText, 200))
-	}
-	response := parseResult.Data
-
 	// Validate response
 	if response.Confidence < 0.0 || response.Confidence > 1.0 {
 		return nil, fmt.Errorf("invalid confidence score: %.2f (must be 0.0-1.0)", response.Confidence)
@@ -86,7 +73,7 @@ func (s *Supervisor) CheckIssueDuplicate(ctx context.Context, candidate, existin
 
 	// Log AI usage (don't fail on logging errors)
 	duration := time.Since(startTime)
-	_ = s.logAIUsage(ctx, candidate.ID, fmt.Sprintf("duplicate_check vs %s", existing.ID), 0, 0, duration)
+	_ = s.logAIUsage(ctx, candidate.ID, fmt.Sprintf("duplicate_check vs %s", existing.ID), usage.InputTokens, usage.OutputTokens, duration)
 
 	return &response, nil
 }
@@ -141,30 +128,17 @@ func (s *Supervisor) CheckIssueDuplicateBatch(ctx context.Context, candidate *ty
 		maxTokens = 4000
 	}
 
-	// Call AI with retry
-	var responseText string
-	var err error
-
-	err = s.retryWithBackoff(ctx, "batch_duplicate_check", func(ctx context.Context) error {
-		responseText, err = s.CallAI(ctx, prompt, "batch_duplicate_check", s.model, maxTokens)
-		return err
-	})
-
+	// Call AI with retry and parse the structured response
+	response, usage, err := completeStructured[BatchDuplicateCheckResponse](ctx, s, "batch_duplicate_check", CompletionRequest{
+		Model:     s.model,
+		MaxTokens: maxTokens,
+		Prompt:    prompt,
+		IssueID:   candidate.ID,
+	}, "batch duplicate check response")
 	if err != nil {
 		return nil, fmt.Errorf("AI batch duplicate check failed: %w", err)
 	}
 
-	// Parse response using resilient parser
-	parseResult := Parse[BatchDuplicateCheckResponse](responseText, ParseOptions{
-		Context:   "batch duplicate check response",
-		LogErrors: boolPtr(true),
-	})
-	if !parseResult.Success {
-		// vc-227: Truncate AI response to prevent log spam
-		return nil, fmt.Errorf("failed to parse batch duplicate check response: %s (response: %s)", parseResult.Error, truncateString(responseText, 200))
-	}
-	response := parseResult.Data
-
 	// Validate response: should have one result per existing issue
 	if len(response.Results) != len(existingIssues) {
 		log.Printf("[WARN] Batch duplicate check returned %d results, expected %d", len(response.Results), len(existingIssues))
@@ -203,7 +177,7 @@ func (s *Supervisor) CheckIssueDuplicateBatch(ctx context.Context, candidate *ty
 	for i, issue := range existingIssues {
 		issueIDs[i] = issue.ID
 	}
-	_ = s.logAIUsage(ctx, candidate.ID, fmt.Sprintf("batch_duplicate_check vs [%s]", join(issueIDs, ",")), 0, 0, duration)
+	_ = s.logAIUsage(ctx, candidate.ID, fmt.Sprintf("batch_duplicate_check vs [%s]", join(issueIDs, ",")), usage.InputTokens, usage.OutputTokens, duration)
 
 	return &response, nil
 }
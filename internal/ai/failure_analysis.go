@@ -0,0 +1,119 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// FailureAnalysisOperation is the supervisor operation of AnalyzeFailure,
+// used to total its cost per issue
+const FailureAnalysisOperation = "failure-analysis"
+
+// FailureReport describes a failed attempt for AnalyzeFailure
+type FailureReport struct {
+	Reason     string   // What failed: agent_failed, quality_gates_failed or agent_error
+	Summary    string   // Agent output or error summary
+	GateOutput string   // Output of the failed quality gates, if any
+	Events     []string // Relevant agent events (warnings and errors), oldest first
+}
+
+// FailureDiagnosis is the supervisor's explanation of a failed attempt
+type FailureDiagnosis struct {
+	Diagnosis  string  `json:"diagnosis"`  // Why the attempt failed
+	Suggestion string  `json:"suggestion"` // Concrete change to make on the next attempt
+	Confidence float64 `json:"confidence"` // Confidence in the diagnosis (0.0-1.0)
+}
+
+// AnalyzeFailure asks the supervisor why an attempt failed and what the next
+// attempt should do differently
+func (s *Supervisor) AnalyzeFailure(ctx context.Context, issue *types.Issue, report FailureReport) (*FailureDiagnosis, error) {
+	if issue == nil {
+		return nil, fmt.Errorf("issue cannot be nil")
+	}
+
+	startTime := time.Now()
+	prompt := s.buildFailureAnalysisPrompt(issue, report)
+
+	diagnosis, response, err := completeStructured[FailureDiagnosis](ctx, s, FailureAnalysisOperation, CompletionRequest{
+		Model:     s.model,
+		MaxTokens: 2048,
+		Prompt:    prompt,
+		IssueID:   issue.ID,
+	}, "failure analysis response")
+	if err != nil {
+		return nil, err
+	}
+	if diagnosis.Diagnosis == "" || diagnosis.Suggestion == "" {
+		return nil, fmt.Errorf("failure analysis response is missing a diagnosis or suggestion")
+	}
+
+	duration := time.Since(startTime)
+	fmt.Printf("AI Failure Analysis for %s: reason=%s, confidence=%.2f, duration=%v\n",
+		issue.ID, report.Reason, diagnosis.Confidence, duration)
+
+	if err := s.logAIUsage(ctx, issue.ID, FailureAnalysisOperation, response.InputTokens, response.OutputTokens, duration); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to log AI usage: %v\n", err)
+	}
+
+	return &diagnosis, nil
+}
+
+// buildFailureAnalysisPrompt builds the prompt for diagnosing a failed attempt
+func (s *Supervisor) buildFailureAnalysisPrompt(issue *types.Issue, report FailureReport) string {
+	gateOutput := report.GateOutput
+	if gateOutput == "" {
+		gateOutput = "(no quality gate output)"
+	}
+	agentEvents := "(none)"
+	if len(report.Events) > 0 {
+		agentEvents = "- " + strings.Join(report.Events, "\n- ")
+	}
+
+	return fmt.Sprintf(`You are an AI supervisor diagnosing why a coding agent's attempt at an issue failed. The issue will be retried, and your suggestion will be shown to the next attempt.
+
+Issue ID: %s
+Title: %s
+
+Description:
+%s
+
+Acceptance Criteria:
+%s
+
+Failure: %s
+
+Agent Output (last 6000 chars):
+%s
+
+Quality Gate Output (last 4000 chars):
+%s
+
+Agent Warnings and Errors:
+%s
+
+Give a concise diagnosis of the root cause (not the symptoms) and ONE concrete suggestion for the next attempt: the file, command or change to make. If the failure looks environmental or out of the agent's control, say so.
+
+Provide your analysis as a JSON object:
+{
+  "diagnosis": "One or two sentences on why the attempt failed",
+  "suggestion": "The specific change the next attempt should make",
+  "confidence": 0.8
+}
+
+IMPORTANT: Respond with ONLY raw JSON. Do NOT wrap it in markdown code fences (`+"`"+`). Just the JSON object.`,
+		issue.ID, issue.Title, issue.Description, issue.AcceptanceCriteria, report.Reason,
+		tail(report.Summary, 6000), tail(gateOutput, 4000), agentEvents)
+}
+
+// tail returns the last n bytes of s, where failures usually are
+func tail(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return "... (truncated)\n" + s[len(s)-n:]
+}
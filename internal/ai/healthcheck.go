@@ -0,0 +1,103 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// HealthcheckKind classifies why a healthcheck failed
+type HealthcheckKind string
+
+const (
+	HealthcheckAuth          HealthcheckKind = "auth"            // API key missing, invalid or without access
+	HealthcheckModelNotFound HealthcheckKind = "model_not_found" // Model unknown to the provider or not available to the key
+	HealthcheckNetwork       HealthcheckKind = "network"         // Provider unreachable or timing out
+	HealthcheckOther         HealthcheckKind = "other"
+)
+
+// healthcheckTimeout bounds the probe so a hung endpoint can't stall startup
+const healthcheckTimeout = 30 * time.Second
+
+// HealthcheckError is returned by Healthcheck with the failure classified
+type HealthcheckError struct {
+	Kind     HealthcheckKind
+	Provider string
+	Model    string
+	Err      error
+}
+
+func (e *HealthcheckError) Error() string {
+	switch e.Kind {
+	case HealthcheckAuth:
+		return fmt.Sprintf("AI provider %s rejected the API key (check the key and its access): %v", e.Provider, e.Err)
+	case HealthcheckModelNotFound:
+		return fmt.Sprintf("AI model %s was not found at provider %s (check executor.ai_model / VC_AI_MODEL): %v", e.Model, e.Provider, e.Err)
+	case HealthcheckNetwork:
+		return fmt.Sprintf("cannot reach AI provider %s (check the network and executor.ai_base_url / VC_AI_BASE_URL): %v", e.Provider, e.Err)
+	default:
+		return fmt.Sprintf("AI provider %s healthcheck failed: %v", e.Provider, e.Err)
+	}
+}
+
+func (e *HealthcheckError) Unwrap() error {
+	return e.Err
+}
+
+// Healthcheck verifies the supervisor can reach its provider with valid
+// credentials and that its model is available, using a minimal completion
+// without retries. Failures are *HealthcheckError.
+func (s *Supervisor) Healthcheck(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, healthcheckTimeout)
+	defer cancel()
+
+	_, err := s.provider.Complete(ctx, CompletionRequest{
+		Model:     s.model,
+		MaxTokens: 1,
+		Prompt:    "ping",
+	})
+	if err == nil {
+		return nil
+	}
+	return &HealthcheckError{
+		Kind:     classifyHealthcheckError(err),
+		Provider: s.Provider(),
+		Model:    s.model,
+		Err:      err,
+	}
+}
+
+// classifyHealthcheckError maps a provider error to a HealthcheckKind
+func classifyHealthcheckError(err error) HealthcheckKind {
+	status := 0
+	var anthropicErr *anthropic.Error
+	var statusErr *providerStatusError
+	switch {
+	case errors.As(err, &anthropicErr):
+		status = anthropicErr.StatusCode
+	case errors.As(err, &statusErr):
+		status = statusErr.StatusCode
+	}
+	switch status {
+	case 401, 403:
+		return HealthcheckAuth
+	case 404:
+		return HealthcheckModelNotFound
+	}
+	if status == 400 && strings.Contains(strings.ToLower(err.Error()), "model") {
+		return HealthcheckModelNotFound // Some OpenAI-compatible servers answer 400 for unknown models
+	}
+
+	var netErr net.Error
+	var urlErr *url.Error
+	if errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr) || errors.As(err, &urlErr) {
+		return HealthcheckNetwork
+	}
+	return HealthcheckOther
+}
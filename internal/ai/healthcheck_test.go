@@ -0,0 +1,84 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/storage/storagetest"
+)
+
+func TestHealthcheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Header.Get("Authorization") {
+		case "Bearer good":
+		case "Bearer bad":
+			http.Error(w, `{"error":{"message":"Incorrect API key"}}`, http.StatusUnauthorized)
+			return
+		default:
+			http.Error(w, `{"error":"weird"}`, http.StatusTeapot)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), `"model":"llama3"`) {
+			http.Error(w, `{"error":{"message":"model 'nope' not found"}}`, http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"pong"}}]}`))
+	}))
+	defer server.Close()
+	// A closed port for network failures
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closedURL := closed.URL
+	closed.Close()
+
+	tests := []struct {
+		name     string
+		key      string
+		model    string
+		baseURL  string
+		wantKind HealthcheckKind // "" = healthy
+		wantMsg  string
+	}{
+		{name: "healthy", key: "good", model: "llama3", baseURL: server.URL},
+		{name: "auth", key: "bad", model: "llama3", baseURL: server.URL, wantKind: HealthcheckAuth, wantMsg: "rejected the API key"},
+		{name: "model", key: "good", model: "nope", baseURL: server.URL, wantKind: HealthcheckModelNotFound, wantMsg: "AI model nope was not found"},
+		{name: "network", key: "good", model: "llama3", baseURL: closedURL, wantKind: HealthcheckNetwork, wantMsg: "cannot reach AI provider local"},
+		{name: "other", key: "odd", model: "llama3", baseURL: server.URL, wantKind: HealthcheckOther, wantMsg: "healthcheck failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewSupervisor(&Config{
+				Provider: ProviderLocal,
+				Model:    tt.model,
+				BaseURL:  tt.baseURL,
+				APIKey:   tt.key,
+				Store:    storagetest.NewFakeStorage(),
+			})
+			if err != nil {
+				t.Fatalf("NewSupervisor: %v", err)
+			}
+			err = s.Healthcheck(context.Background())
+			if tt.wantKind == "" {
+				if err != nil {
+					t.Fatalf("Expected a healthy provider, got %v", err)
+				}
+				return
+			}
+			var healthErr *HealthcheckError
+			if !errors.As(err, &healthErr) {
+				t.Fatalf("Expected a *HealthcheckError, got %v", err)
+			}
+			if healthErr.Kind != tt.wantKind {
+				t.Errorf("Kind = %s, want %s (%v)", healthErr.Kind, tt.wantKind, err)
+			}
+			if !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Errorf("Expected %q in %q", tt.wantMsg, err.Error())
+			}
+		})
+	}
+}
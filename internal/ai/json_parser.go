@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"reflect"
 	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -18,12 +20,6 @@ var (
 	codeFenceStartRegex = regexp.MustCompile(`(?s)^` + "`" + `{3}(?:json|javascript|js)?\s*\n?([\s\S]*?)\n?` + "`" + `{3}\s*$`)
 	codeFenceAnyRegex   = regexp.MustCompile(`(?s)` + "`" + `{3}(?:json|javascript|js)?\s*\n?([\s\S]*?)\n?` + "`" + `{3}`)
 
-	// JSON cleanup patterns
-	trailingCommaRegex     = regexp.MustCompile(`,(\s*[}\]])`)
-	unquotedKeyRegex       = regexp.MustCompile(`([{,]\s*)([a-zA-Z_$][a-zA-Z0-9_$]*)\s*:`)
-	singleLineCommentRegex = regexp.MustCompile(`(?m)//.*$`)
-	multiLineCommentRegex  = regexp.MustCompile(`(?s)/\*.*?\*/`)
-
 	// JSON extraction patterns (greedy to capture nested structures)
 	// The first-character check in extractJSON prevents over-matching in most cases
 	objectRegex = regexp.MustCompile(`(?s)\{[\s\S]*\}`)
@@ -37,8 +33,20 @@ type ParseResult[T any] struct {
 	Data         T
 	Error        string
 	OriginalText string
+	Repair       string // Repair needed to parse the text (one of the Repair* constants, "" if it parsed as is)
 }
 
+// Repairs Parse may apply to a malformed response, reported in ParseResult.Repair
+const (
+	RepairCodeFence = "code_fence" // Markdown code fences were stripped
+	RepairCleanup   = "cleanup"    // Comments, trailing commas or unquoted keys were fixed
+	RepairExtract   = "extract"    // The JSON was cut out of surrounding prose
+)
+
+// maxExtractCandidates bounds how many balanced {...}/[...] spans Parse tries
+// when looking for the JSON inside prose
+const maxExtractCandidates = 32
+
 // ParseOptions configures JSON parsing behavior.
 //
 // Optional fields use pointers to distinguish between "not set" (nil) and
@@ -71,7 +79,12 @@ func intPtr(i int) *int    { return IntPtr(i) } // Keep for backward compat
 //  1. Direct JSON parse
 //  2. Remove code fences and retry
 //  3. Fix common JSON issues and retry
-//  4. Extract JSON from mixed content and retry
+//  4. Extract the outermost JSON value from mixed content and retry
+//  5. Greedy extraction as a last resort
+//
+// A parsed object must look like T: when T is a struct, the object has to
+// carry at least one of T's JSON fields, so an unrelated object (or a nested
+// fragment picked out of prose) is rejected rather than silently zero-valued.
 func Parse[T any](text string, opts ...ParseOptions) ParseResult[T] {
 	// Resolve options with proper defaults (vc-248)
 	var options ParseOptions
@@ -119,13 +132,9 @@ func Parse[T any](text string, opts ...ParseOptions) ParseResult[T] {
 	}
 
 	// Strategy 1: Direct JSON parse
-	result, err := tryDirectParse[T](trimmed)
+	result, err := tryParse[T](trimmed)
 	if err == nil {
-		return ParseResult[T]{
-			Success:      true,
-			Data:         result,
-			OriginalText: text,
-		}
+		return parsed(result, text, "")
 	}
 
 	if !enableCleanup {
@@ -138,42 +147,56 @@ func Parse[T any](text string, opts ...ParseOptions) ParseResult[T] {
 			"textPreview", truncate(text, 100),
 			"context", context)
 	}
+	lastErr := err
 
 	// Strategy 2: Remove code fences and try again
 	withoutFences := removeCodeFences(trimmed)
 	if withoutFences != trimmed {
-		if result, err := tryDirectParse[T](withoutFences); err == nil {
-			return ParseResult[T]{
-				Success:      true,
-				Data:         result,
-				OriginalText: text,
-			}
+		result, err := tryParse[T](withoutFences)
+		if err == nil {
+			return parsed(result, text, RepairCodeFence)
 		}
+		lastErr = err
 	}
 
 	// Strategy 3: Fix common JSON issues
 	cleaned := cleanupJSON(withoutFences)
-	if result, err := tryDirectParse[T](cleaned); err == nil {
-		return ParseResult[T]{
-			Success:      true,
-			Data:         result,
-			OriginalText: text,
+	if cleaned != withoutFences {
+		result, err := tryParse[T](cleaned)
+		if err == nil {
+			return parsed(result, text, RepairCleanup)
 		}
+		lastErr = err
 	}
 
-	// Strategy 4: Extract JSON from mixed content
-	// Extract from cleaned version, not original trimmed (which may still have fences)
+	// Strategy 4: Extract the outermost JSON value from mixed content, trying
+	// each balanced span in turn so braces in surrounding prose don't win
+	for i, candidate := range jsonCandidates(withoutFences) {
+		if i == maxExtractCandidates {
+			break
+		}
+		result, err := tryParse[T](candidate)
+		if err != nil {
+			result, err = tryParse[T](cleanupJSON(candidate))
+		}
+		if err == nil {
+			return parsed(result, text, RepairExtract)
+		}
+		lastErr = err
+	}
+
+	// Strategy 5: Greedy extraction for content the balanced scan can't
+	// delimit (e.g. unbalanced quotes in the prose)
 	extracted := extractJSON(cleaned)
 	if extracted != "" {
-		if result, err := tryDirectParse[T](extracted); err == nil {
-			return ParseResult[T]{
-				Success:      true,
-				Data:         result,
-				OriginalText: text,
-			}
+		if result, err := tryParse[T](extracted); err == nil {
+			return parsed(result, text, RepairExtract)
 		}
 	}
 
+	if _, ok := lastErr.(*schemaError); ok {
+		return createError[T](lastErr.Error(), text, context)
+	}
 	return createError[T]("all JSON parsing strategies failed", text, context)
 }
 
@@ -260,6 +283,152 @@ func tryDirectParse[T any](text string) (T, error) {
 	return result, err
 }
 
+// tryParse parses text as T and checks it against T's schema.
+func tryParse[T any](text string) (T, error) {
+	result, err := tryDirectParse[T](text)
+	if err != nil {
+		return result, err
+	}
+	if err := checkSchema[T](text); err != nil {
+		var zero T
+		return zero, err
+	}
+	return result, nil
+}
+
+// parsed builds a successful ParseResult.
+func parsed[T any](data T, text, repair string) ParseResult[T] {
+	return ParseResult[T]{
+		Success:      true,
+		Data:         data,
+		OriginalText: text,
+		Repair:       repair,
+	}
+}
+
+// schemaError reports valid JSON that doesn't match the expected type.
+type schemaError struct {
+	msg string
+}
+
+func (e *schemaError) Error() string { return e.msg }
+
+// checkSchema rejects a JSON object that shares no field with struct type T.
+// Non-struct targets (maps, slices, any) accept whatever decoded.
+func checkSchema[T any](text string) error {
+	fields := jsonFieldNames(reflect.TypeOf((*T)(nil)).Elem())
+	if fields == nil {
+		return nil
+	}
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(text), &object); err != nil {
+		// Not an object (e.g. null); decoding into T already accepted it
+		return nil
+	}
+	for key := range object {
+		if fields[strings.ToLower(key)] {
+			return nil
+		}
+	}
+	want := make([]string, 0, len(fields))
+	for name := range fields {
+		want = append(want, name)
+	}
+	sort.Strings(want)
+	return &schemaError{msg: fmt.Sprintf("JSON object does not match expected schema (want fields: %s)",
+		strings.Join(want, ", "))}
+}
+
+// jsonFieldNames returns the lower-cased JSON names of a struct's fields,
+// or nil if t is not a struct (or pointer to one) with any.
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	var fields map[string]bool
+	for i := 0; i < t.NumField(); i++ {
+		name, ok := jsonFieldName(t.Field(i))
+		if !ok {
+			continue
+		}
+		if fields == nil {
+			fields = make(map[string]bool)
+		}
+		fields[strings.ToLower(name)] = true
+	}
+	return fields
+}
+
+// jsonFieldName returns the name encoding/json uses for an exported field.
+func jsonFieldName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() || field.Anonymous {
+		return "", false
+	}
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name, true
+	}
+	return field.Name, true
+}
+
+// SchemaHint renders T as a JSON skeleton (field names with placeholder
+// types) for prompts asking the model to match the expected shape.
+func SchemaHint[T any]() string {
+	return schemaHint(reflect.TypeOf((*T)(nil)).Elem(), "", 0)
+}
+
+func schemaHint(t reflect.Type, indent string, depth int) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.String() == "time.Time" {
+		return `"<RFC 3339 timestamp>"`
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		if depth > 4 {
+			return "{...}"
+		}
+		var b strings.Builder
+		b.WriteString("{\n")
+		first := true
+		for i := 0; i < t.NumField(); i++ {
+			name, ok := jsonFieldName(t.Field(i))
+			if !ok {
+				continue
+			}
+			if !first {
+				b.WriteString(",\n")
+			}
+			first = false
+			fmt.Fprintf(&b, "%s  %q: %s", indent, name, schemaHint(t.Field(i).Type, indent+"  ", depth+1))
+		}
+		b.WriteString("\n" + indent + "}")
+		return b.String()
+	case reflect.Slice, reflect.Array:
+		return "[" + schemaHint(t.Elem(), indent, depth+1) + "]"
+	case reflect.Map:
+		return `{"<key>": ` + schemaHint(t.Elem(), indent, depth+1) + "}"
+	case reflect.String:
+		return `"<string>"`
+	case reflect.Bool:
+		return "<true|false>"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "<integer>"
+	case reflect.Float32, reflect.Float64:
+		return "<number>"
+	default:
+		return "<any>"
+	}
+}
+
 // removeCodeFences strips markdown code fences from text.
 // Handles both ```json and ``` formats, as well as single backticks.
 func removeCodeFences(text string) string {
@@ -287,27 +456,175 @@ func removeCodeFences(text string) string {
 // - Fixes unquoted object keys (basic cases, JavaScript identifiers only)
 // - Removes // and /* */ comments
 //
+// The fixes only apply outside string literals, so URLs ("https://...") and
+// text like ", }" inside values are left alone.
+//
 // Note: Does NOT convert single quotes to double quotes, as this would break
 // valid JSON containing apostrophes (e.g., {"message": "I'm valid"}).
 // Claude/AI models consistently use double quotes in JSON output.
 func cleanupJSON(text string) string {
-	cleaned := strings.TrimSpace(text)
+	cleaned := stripComments(strings.TrimSpace(text))
+	return strings.TrimSpace(fixCommasAndKeys(cleaned))
+}
+
+// stripComments removes // and /* */ comments outside string literals.
+func stripComments(text string) string {
+	var b strings.Builder
+	inString := false
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if inString {
+			b.WriteByte(c)
+			if c == '\\' && i+1 < len(text) {
+				i++
+				b.WriteByte(text[i])
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+		switch {
+		case c == '"':
+			inString = true
+			b.WriteByte(c)
+		case strings.HasPrefix(text[i:], "//"):
+			end := strings.IndexByte(text[i:], '\n')
+			if end < 0 {
+				return b.String()
+			}
+			i += end - 1
+		case strings.HasPrefix(text[i:], "/*"):
+			end := strings.Index(text[i+2:], "*/")
+			if end < 0 {
+				return b.String()
+			}
+			i += end + 3
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// fixCommasAndKeys drops trailing commas and quotes bare identifier keys,
+// outside string literals.
+func fixCommasAndKeys(text string) string {
+	var b strings.Builder
+	inString := false
+	var prev byte // last non-space byte written outside a string
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if inString {
+			b.WriteByte(c)
+			if c == '\\' && i+1 < len(text) {
+				i++
+				b.WriteByte(text[i])
+			} else if c == '"' {
+				inString = false
+				prev = c
+			}
+			continue
+		}
+		switch {
+		case c == '"':
+			inString = true
+			b.WriteByte(c)
+		case c == ',':
+			next := skipSpace(text, i+1)
+			if next < len(text) && (text[next] == '}' || text[next] == ']') {
+				continue
+			}
+			b.WriteByte(c)
+			prev = c
+		case (prev == '{' || prev == ',') && isIdentStart(c):
+			end := i + 1
+			for end < len(text) && isIdentPart(text[end]) {
+				end++
+			}
+			if colon := skipSpace(text, end); colon < len(text) && text[colon] == ':' {
+				b.WriteString(`"` + text[i:end] + `"`)
+			} else {
+				b.WriteString(text[i:end])
+			}
+			i = end - 1
+			prev = 'a'
+		default:
+			b.WriteByte(c)
+			if !isSpace(c) {
+				prev = c
+			}
+		}
+	}
+	return b.String()
+}
 
-	// Remove trailing commas before closing braces/brackets
-	cleaned = trailingCommaRegex.ReplaceAllString(cleaned, "$1")
+// jsonCandidates returns the balanced {...} and [...] spans of text in the
+// order they start, so the outermost value comes before its children.
+// Brackets inside string literals don't count.
+func jsonCandidates(text string) []string {
+	var candidates []string
+	for start := 0; start < len(text) && len(candidates) < maxExtractCandidates; start++ {
+		if text[start] != '{' && text[start] != '[' {
+			continue
+		}
+		if end := matchingClose(text, start); end > start {
+			candidates = append(candidates, text[start:end+1])
+		}
+	}
+	return candidates
+}
 
-	// Fix unquoted object keys (basic cases)
-	// Match: { or , followed by whitespace, then JavaScript identifier, then :
-	// Limitation: Only handles [a-zA-Z_$][a-zA-Z0-9_$]* pattern
-	cleaned = unquotedKeyRegex.ReplaceAllString(cleaned, `$1"$2":`)
+// matchingClose returns the index closing the bracket at start, or -1.
+func matchingClose(text string, start int) int {
+	var stack []byte
+	inString := false
+	for i := start; i < len(text); i++ {
+		c := text[i]
+		if inString {
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+		case '{':
+			stack = append(stack, '}')
+		case '[':
+			stack = append(stack, ']')
+		case '}', ']':
+			if len(stack) == 0 || stack[len(stack)-1] != c {
+				return -1
+			}
+			stack = stack[:len(stack)-1]
+			if len(stack) == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
 
-	// Remove single-line comments (multiline mode: $ matches end of line)
-	cleaned = singleLineCommentRegex.ReplaceAllString(cleaned, "")
+func skipSpace(text string, i int) int {
+	for i < len(text) && isSpace(text[i]) {
+		i++
+	}
+	return i
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
 
-	// Remove multi-line comments
-	cleaned = multiLineCommentRegex.ReplaceAllString(cleaned, "")
+func isIdentStart(c byte) bool {
+	return c == '_' || c == '$' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
 
-	return strings.TrimSpace(cleaned)
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || ('0' <= c && c <= '9')
 }
 
 // extractJSON tries to extract JSON objects or arrays from mixed content.
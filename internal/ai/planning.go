@@ -7,7 +7,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/anthropics/anthropic-sdk-go"
 	"github.com/steveyegge/vc/internal/types"
 )
 
@@ -27,46 +26,16 @@ func (s *Supervisor) GeneratePlan(ctx context.Context, planningCtx *types.Planni
 	// Build the planning prompt
 	prompt := s.buildPlanningPrompt(planningCtx)
 
-	// Call Anthropic API with retry logic
-	var response *anthropic.Message
-	err := s.retryWithBackoff(ctx, "planning", func(attemptCtx context.Context) error {
-		resp, apiErr := s.client.Messages.New(attemptCtx, anthropic.MessageNewParams{
-			Model:     anthropic.Model(s.model),
-			MaxTokens: 8192, // Larger token limit for complex plans
-			Messages: []anthropic.MessageParam{
-				anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
-			},
-		})
-		if apiErr != nil {
-			return apiErr
-		}
-		response = resp
-		return nil
-	})
-
+	// Call the AI provider with retry logic
+	plan, response, err := completeStructured[types.MissionPlan](ctx, s, "planning", CompletionRequest{
+		Model:     s.model,
+		MaxTokens: 8192, // Larger token limit for complex plans
+		Prompt:    prompt,
+		IssueID:   planningCtx.Mission.ID,
+	}, "mission plan response")
 	if err != nil {
-		return nil, fmt.Errorf("anthropic API call failed: %w", err)
-	}
-
-	// Extract the text content from the response
-	var responseText string
-	for _, block := range response.Content {
-		if block.Type == "text" {
-			responseText += block.Text
-		}
-	}
-
-	// Parse the response as JSON using resilient parser
-	parseResult := Parse[types.MissionPlan](responseText, ParseOptions{
-		Context:   "mission plan response",
-		LogErrors: boolPtr(true),
-	})
-	if !parseResult.Success {
-		// Log full response for debugging, but truncate in error message
-		fmt.Fprintf(os.Stderr, "Full AI planning response: %s\n", responseText)
-		return nil, fmt.Errorf("failed to parse mission plan response: %s (response: %s)", parseResult.Error, truncateString(responseText, 500))
+		return nil, err
 	}
-	plan := parseResult.Data
 
 	// Set metadata
 	plan.MissionID = planningCtx.Mission.ID
@@ -84,7 +53,7 @@ func (s *Supervisor) GeneratePlan(ctx context.Context, planningCtx *types.Planni
 		planningCtx.Mission.ID, len(plan.Phases), plan.Confidence, plan.EstimatedEffort, duration)
 
 	// Log AI usage to events
-	if err := s.logAIUsage(ctx, planningCtx.Mission.ID, "planning", response.Usage.InputTokens, response.Usage.OutputTokens, duration); err != nil {
+	if err := s.logAIUsage(ctx, planningCtx.Mission.ID, "planning", response.InputTokens, response.OutputTokens, duration); err != nil {
 		fmt.Fprintf(os.Stderr, "warning: failed to log AI usage: %v\n", err)
 	}
 
@@ -116,49 +85,22 @@ func (s *Supervisor) RefinePhase(ctx context.Context, phase *types.Phase, missio
 	// Build the refinement prompt
 	prompt := s.buildRefinementPrompt(phase, missionCtx)
 
-	// Call Anthropic API with retry logic
-	var response *anthropic.Message
-	err := s.retryWithBackoff(ctx, "refinement", func(attemptCtx context.Context) error {
-		resp, apiErr := s.client.Messages.New(attemptCtx, anthropic.MessageNewParams{
-			Model:     anthropic.Model(s.model),
-			MaxTokens: 8192,
-			Messages: []anthropic.MessageParam{
-				anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
-			},
-		})
-		if apiErr != nil {
-			return apiErr
-		}
-		response = resp
-		return nil
-	})
-
-	if err != nil {
-		return nil, fmt.Errorf("anthropic API call failed: %w", err)
-	}
-
-	// Extract the text content from the response
-	var responseText string
-	for _, block := range response.Content {
-		if block.Type == "text" {
-			responseText += block.Text
-		}
-	}
-
-	// Parse the response - expecting {"tasks": [...]}
+	// The response is expected as {"tasks": [...]}
 	type refinementResponse struct {
 		Tasks []types.PlannedTask `json:"tasks"`
 	}
-	parseResult := Parse[refinementResponse](responseText, ParseOptions{
-		Context:   "phase refinement response",
-		LogErrors: boolPtr(true),
-	})
-	if !parseResult.Success {
-		// Log full response for debugging, but truncate in error message
-		fmt.Fprintf(os.Stderr, "Full AI refinement response: %s\n", responseText)
-		return nil, fmt.Errorf("failed to parse refinement response: %s (response: %s)", parseResult.Error, truncateString(responseText, 500))
+
+	// Call the AI provider with retry logic
+	parsed, response, err := completeStructured[refinementResponse](ctx, s, "refinement", CompletionRequest{
+		Model:     s.model,
+		MaxTokens: 8192,
+		Prompt:    prompt,
+		IssueID:   phase.ID,
+	}, "phase refinement response")
+	if err != nil {
+		return nil, err
 	}
-	tasks := parseResult.Data.Tasks
+	tasks := parsed.Tasks
 
 	// Validate tasks
 	if len(tasks) == 0 {
@@ -176,7 +118,7 @@ func (s *Supervisor) RefinePhase(ctx context.Context, phase *types.Phase, missio
 		phase.ID, len(tasks), duration)
 
 	// Log AI usage
-	if err := s.logAIUsage(ctx, phase.ID, "refinement", response.Usage.InputTokens, response.Usage.OutputTokens, duration); err != nil {
+	if err := s.logAIUsage(ctx, phase.ID, "refinement", response.InputTokens, response.OutputTokens, duration); err != nil {
 		fmt.Fprintf(os.Stderr, "warning: failed to log AI usage: %v\n", err)
 	}
 
@@ -232,36 +174,6 @@ func (s *Supervisor) ValidatePhaseStructure(ctx context.Context, phases []types.
 	// Build validation prompt
 	prompt := s.buildPhaseValidationPrompt(phases)
 
-	// Call Anthropic API with retry logic
-	var response *anthropic.Message
-	err := s.retryWithBackoff(ctx, "phase-validation", func(attemptCtx context.Context) error {
-		resp, apiErr := s.client.Messages.New(attemptCtx, anthropic.MessageNewParams{
-			Model:     anthropic.Model(s.model),
-			MaxTokens: 2048,
-			Messages: []anthropic.MessageParam{
-				anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
-			},
-		})
-		if apiErr != nil {
-			return apiErr
-		}
-		response = resp
-		return nil
-	})
-
-	if err != nil {
-		return fmt.Errorf("anthropic API call failed: %w", err)
-	}
-
-	// Extract the text content from the response
-	var responseText string
-	for _, block := range response.Content {
-		if block.Type == "text" {
-			responseText += block.Text
-		}
-	}
-
-	// Parse the response
 	type validationResult struct {
 		Valid     bool     `json:"valid"`
 		Errors    []string `json:"errors"`
@@ -269,15 +181,15 @@ func (s *Supervisor) ValidatePhaseStructure(ctx context.Context, phases []types.
 		Reasoning string   `json:"reasoning"`
 	}
 
-	parseResult := Parse[validationResult](responseText, ParseOptions{
-		Context:   "phase validation response",
-		LogErrors: boolPtr(true),
-	})
-	if !parseResult.Success {
-		// vc-227: Truncate AI response to prevent log spam
-		return fmt.Errorf("failed to parse phase validation response: %s (response: %s)", parseResult.Error, truncateString(responseText, 200))
+	// Call the AI provider with retry logic
+	result, response, err := completeStructured[validationResult](ctx, s, "phase-validation", CompletionRequest{
+		Model:     s.model,
+		MaxTokens: 2048,
+		Prompt:    prompt,
+	}, "phase validation response")
+	if err != nil {
+		return err
 	}
-	result := parseResult.Data
 
 	// Log the validation
 	duration := time.Since(startTime)
@@ -285,7 +197,7 @@ func (s *Supervisor) ValidatePhaseStructure(ctx context.Context, phases []types.
 		result.Valid, len(result.Errors), len(result.Warnings), duration)
 
 	// Log AI usage (use a dummy issue ID for now since we don't have one in this context)
-	if err := s.logAIUsage(ctx, "phase-validation", "phase-validation", response.Usage.InputTokens, response.Usage.OutputTokens, duration); err != nil {
+	if err := s.logAIUsage(ctx, "phase-validation", "phase-validation", response.InputTokens, response.OutputTokens, duration); err != nil {
 		fmt.Fprintf(os.Stderr, "warning: failed to log AI usage: %v\n", err)
 	}
 
@@ -0,0 +1,292 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/anthropics/anthropic-sdk-go/option"
+)
+
+// Provider names accepted in Config.Provider (and VC_AI_PROVIDER)
+const (
+	ProviderAnthropic = "anthropic"
+	ProviderOpenAI    = "openai"
+	ProviderLocal     = "local" // OpenAI-compatible endpoint, e.g. Ollama or llama.cpp
+)
+
+// CompletionRequest is a single-turn prompt sent to a provider
+type CompletionRequest struct {
+	Model     string
+	MaxTokens int
+	Prompt    string
+	IssueID   string // Issue the call is about, for usage events; not sent
+}
+
+// CompletionResponse is a provider's answer to a CompletionRequest
+type CompletionResponse struct {
+	Text         string
+	Model        string // Model that answered, as reported by the provider
+	InputTokens  int64
+	OutputTokens int64
+}
+
+// Provider is an AI API the supervisor sends prompts to
+type Provider interface {
+	// Name returns the provider name (ProviderAnthropic, ...)
+	Name() string
+	// Complete returns the provider's free-form text answer
+	Complete(ctx context.Context, req CompletionRequest) (*CompletionResponse, error)
+	// StructuredComplete asks for a single JSON object as the answer, using
+	// the provider's JSON mode where it has one. The text still goes
+	// through Parse, since not every provider can enforce it.
+	StructuredComplete(ctx context.Context, req CompletionRequest) (*CompletionResponse, error)
+}
+
+// providerDefaults holds what a provider uses when Config leaves it unset
+type providerDefaults struct {
+	apiKeyEnv string // "" = no API key needed
+	baseURL   string
+	model     string
+	fastModel string // Cheaper model for short decisions
+}
+
+var knownProviders = map[string]providerDefaults{
+	ProviderAnthropic: {
+		apiKeyEnv: "ANTHROPIC_API_KEY",
+		model:     "claude-sonnet-4-5-20250929",
+		fastModel: "claude-3-5-haiku-20241022",
+	},
+	ProviderOpenAI: {
+		apiKeyEnv: "OPENAI_API_KEY",
+		baseURL:   "https://api.openai.com/v1",
+		model:     "gpt-4o",
+		fastModel: "gpt-4o-mini",
+	},
+	ProviderLocal: {
+		baseURL: "http://localhost:11434/v1",
+	},
+}
+
+// newProvider builds the provider cfg selects and returns it with the
+// default and fast models to use. requestTimeout bounds each HTTP request
+// (RetryConfig.Timeout), so a hung endpoint fails even when the caller's
+// context has no deadline.
+func newProvider(cfg *Config, requestTimeout time.Duration) (Provider, string, string, error) {
+	name := firstNonEmpty(cfg.Provider, os.Getenv("VC_AI_PROVIDER"), ProviderAnthropic)
+	defaults, ok := knownProviders[name]
+	if !ok {
+		return nil, "", "", fmt.Errorf("unknown AI provider %q (want %s, %s or %s)", name, ProviderAnthropic, ProviderOpenAI, ProviderLocal)
+	}
+
+	model := firstNonEmpty(cfg.Model, os.Getenv("VC_AI_MODEL"), defaults.model)
+	if model == "" {
+		return nil, "", "", fmt.Errorf("AI provider %s needs a model (set VC_AI_MODEL or executor.ai_model)", name)
+	}
+	fastModel := defaults.fastModel
+	if fastModel == "" || model != defaults.model {
+		// A chosen model is used throughout rather than mixed with a default
+		fastModel = model
+	}
+	baseURL := firstNonEmpty(cfg.BaseURL, os.Getenv("VC_AI_BASE_URL"), defaults.baseURL)
+
+	apiKey := cfg.APIKey
+	if apiKey == "" {
+		keyEnv := firstNonEmpty(cfg.APIKeyEnv, os.Getenv("VC_AI_API_KEY_ENV"), defaults.apiKeyEnv)
+		if keyEnv != "" {
+			apiKey = os.Getenv(keyEnv)
+			if apiKey == "" && defaults.apiKeyEnv != "" {
+				return nil, "", "", fmt.Errorf("%s not set", keyEnv)
+			}
+		}
+	}
+
+	if name == ProviderAnthropic {
+		// The supervisor retries (retryWithBackoff), so the SDK must not as well
+		opts := []option.RequestOption{option.WithAPIKey(apiKey), option.WithMaxRetries(0), option.WithRequestTimeout(requestTimeout)}
+		if baseURL != "" {
+			opts = append(opts, option.WithBaseURL(baseURL))
+		}
+		client := anthropic.NewClient(opts...)
+		return &anthropicProvider{client: &client}, model, fastModel, nil
+	}
+	return &openAIProvider{
+		name:    name,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		apiKey:  apiKey,
+		client:  &http.Client{Timeout: requestTimeout},
+	}, model, fastModel, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// anthropicProvider sends prompts to the Anthropic Messages API
+type anthropicProvider struct {
+	client *anthropic.Client
+}
+
+func (p *anthropicProvider) Name() string { return ProviderAnthropic }
+
+func (p *anthropicProvider) Complete(ctx context.Context, req CompletionRequest) (*CompletionResponse, error) {
+	resp, err := p.client.Messages.New(ctx, anthropic.MessageNewParams{
+		Model:     anthropic.Model(req.Model),
+		MaxTokens: int64(req.MaxTokens),
+		Messages: []anthropic.MessageParam{
+			anthropic.NewUserMessage(anthropic.NewTextBlock(req.Prompt)),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Extract the text content from the response
+	var text string
+	for _, block := range resp.Content {
+		if block.Type == "text" {
+			text += block.Text
+		}
+	}
+	return &CompletionResponse{
+		Text:         text,
+		Model:        string(resp.Model),
+		InputTokens:  resp.Usage.InputTokens,
+		OutputTokens: resp.Usage.OutputTokens,
+	}, nil
+}
+
+// StructuredComplete is Complete: the Messages API has no JSON mode, and
+// the prompts already ask for JSON
+func (p *anthropicProvider) StructuredComplete(ctx context.Context, req CompletionRequest) (*CompletionResponse, error) {
+	return p.Complete(ctx, req)
+}
+
+// openAIProvider sends prompts to an OpenAI-compatible chat completions
+// endpoint: OpenAI itself or a local server
+type openAIProvider struct {
+	name    string
+	baseURL string
+	apiKey  string // Optional for local servers
+	client  *http.Client
+}
+
+// providerStatusError is a non-200 answer from an OpenAI-compatible endpoint
+type providerStatusError struct {
+	Provider   string
+	StatusCode int
+	Status     string
+	Body       string
+	Header     http.Header // For Retry-After
+}
+
+// Error keeps the status code in the message for isRetriableError
+func (e *providerStatusError) Error() string {
+	return fmt.Sprintf("%s API returned %s: %s", e.Provider, e.Status, e.Body)
+}
+
+type openAIChatRequest struct {
+	Model          string              `json:"model"`
+	MaxTokens      int                 `json:"max_tokens,omitempty"`
+	Messages       []openAIChatMessage `json:"messages"`
+	ResponseFormat *openAIFormat       `json:"response_format,omitempty"`
+}
+
+type openAIChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIFormat struct {
+	Type string `json:"type"`
+}
+
+type openAIChatResponse struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message openAIChatMessage `json:"message"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int64 `json:"prompt_tokens"`
+		CompletionTokens int64 `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+func (p *openAIProvider) Name() string { return p.name }
+
+func (p *openAIProvider) Complete(ctx context.Context, req CompletionRequest) (*CompletionResponse, error) {
+	return p.chat(ctx, req, nil)
+}
+
+func (p *openAIProvider) StructuredComplete(ctx context.Context, req CompletionRequest) (*CompletionResponse, error) {
+	return p.chat(ctx, req, &openAIFormat{Type: "json_object"})
+}
+
+func (p *openAIProvider) chat(ctx context.Context, req CompletionRequest, format *openAIFormat) (*CompletionResponse, error) {
+	body, err := json.Marshal(openAIChatRequest{
+		Model:          req.Model,
+		MaxTokens:      req.MaxTokens,
+		Messages:       []openAIChatMessage{{Role: "user", Content: req.Prompt}},
+		ResponseFormat: format,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &providerStatusError{
+			Provider:   p.name,
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			Body:       truncateString(string(respBody), 500),
+			Header:     resp.Header,
+		}
+	}
+
+	var chat openAIChatResponse
+	if err := json.Unmarshal(respBody, &chat); err != nil {
+		return nil, fmt.Errorf("failed to decode %s response: %w", p.name, err)
+	}
+	if len(chat.Choices) == 0 {
+		return nil, fmt.Errorf("%s response has no choices", p.name)
+	}
+	model := chat.Model
+	if model == "" {
+		model = req.Model
+	}
+	return &CompletionResponse{
+		Text:         chat.Choices[0].Message.Content,
+		Model:        model,
+		InputTokens:  chat.Usage.PromptTokens,
+		OutputTokens: chat.Usage.CompletionTokens,
+	}, nil
+}
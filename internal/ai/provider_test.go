@@ -0,0 +1,173 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/storage/storagetest"
+)
+
+func TestOpenAIProvider(t *testing.T) {
+	var got openAIChatRequest
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			http.NotFound(w, r)
+			return
+		}
+		auth = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		if got.Model == "overloaded" {
+			http.Error(w, `{"error":"busy"}`, http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"model":"m-2024","choices":[{"message":{"role":"assistant","content":"{\"ok\":true}"}}],"usage":{"prompt_tokens":12,"completion_tokens":3}}`))
+	}))
+	defer server.Close()
+
+	p := &openAIProvider{name: ProviderLocal, baseURL: server.URL + "/v1", apiKey: "secret", client: server.Client()}
+	ctx := context.Background()
+
+	resp, err := p.StructuredComplete(ctx, CompletionRequest{Model: "m", MaxTokens: 100, Prompt: "Answer in JSON"})
+	if err != nil {
+		t.Fatalf("StructuredComplete: %v", err)
+	}
+	if resp.Text != `{"ok":true}` || resp.Model != "m-2024" || resp.InputTokens != 12 || resp.OutputTokens != 3 {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+	if got.ResponseFormat == nil || got.ResponseFormat.Type != "json_object" {
+		t.Errorf("Expected JSON mode, got %+v", got.ResponseFormat)
+	}
+	if len(got.Messages) != 1 || got.Messages[0].Role != "user" || got.Messages[0].Content != "Answer in JSON" || got.MaxTokens != 100 {
+		t.Errorf("Unexpected request: %+v", got)
+	}
+	if auth != "Bearer secret" {
+		t.Errorf("Expected bearer auth, got %q", auth)
+	}
+
+	got = openAIChatRequest{}
+	if _, err := p.Complete(ctx, CompletionRequest{Model: "m", Prompt: "hi"}); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if got.ResponseFormat != nil {
+		t.Errorf("Expected no JSON mode for Complete, got %+v", got.ResponseFormat)
+	}
+
+	// Server errors keep the status code, so they are retried
+	_, err = p.Complete(ctx, CompletionRequest{Model: "overloaded", Prompt: "hi"})
+	if err == nil || !strings.Contains(err.Error(), "503") || !isRetriableError(err) {
+		t.Errorf("Expected a retriable 503 error, got %v", err)
+	}
+}
+
+func TestNewSupervisorProviders(t *testing.T) {
+	for _, env := range []string{"VC_AI_PROVIDER", "VC_AI_MODEL", "VC_AI_BASE_URL", "VC_AI_API_KEY_ENV", "ANTHROPIC_API_KEY", "OPENAI_API_KEY"} {
+		t.Setenv(env, "")
+	}
+	store := storagetest.NewFakeStorage()
+
+	tests := []struct {
+		name         string
+		cfg          Config
+		env          map[string]string
+		wantProvider string
+		wantModel    string
+		wantErr      string
+	}{
+		{name: "anthropic without key", cfg: Config{}, wantErr: "ANTHROPIC_API_KEY not set"},
+		{name: "anthropic default", env: map[string]string{"ANTHROPIC_API_KEY": "k"},
+			wantProvider: ProviderAnthropic, wantModel: "claude-sonnet-4-5-20250929"},
+		{name: "openai from env", env: map[string]string{"VC_AI_PROVIDER": "openai", "OPENAI_API_KEY": "k"},
+			wantProvider: ProviderOpenAI, wantModel: "gpt-4o"},
+		{name: "openai custom key env", cfg: Config{Provider: ProviderOpenAI, APIKeyEnv: "MY_KEY"},
+			wantErr: "MY_KEY not set"},
+		{name: "local needs a model", cfg: Config{Provider: ProviderLocal}, wantErr: "needs a model"},
+		{name: "local without key", cfg: Config{Provider: ProviderLocal, Model: "llama3"},
+			wantProvider: ProviderLocal, wantModel: "llama3"},
+		{name: "config beats env", cfg: Config{Provider: ProviderLocal, Model: "llama3"}, env: map[string]string{"VC_AI_PROVIDER": "openai"},
+			wantProvider: ProviderLocal, wantModel: "llama3"},
+		{name: "unknown provider", cfg: Config{Provider: "gemini"}, wantErr: "unknown AI provider"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			cfg := tt.cfg
+			cfg.Store = store
+			s, err := NewSupervisor(&cfg)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewSupervisor: %v", err)
+			}
+			if s.Provider() != tt.wantProvider || s.Model() != tt.wantModel {
+				t.Errorf("Got %s/%s, want %s/%s", s.Provider(), s.Model(), tt.wantProvider, tt.wantModel)
+			}
+		})
+	}
+}
+
+func TestSupervisorFastModel(t *testing.T) {
+	t.Setenv("ANTHROPIC_API_KEY", "k")
+	t.Setenv("VC_AI_PROVIDER", "")
+	t.Setenv("VC_AI_MODEL", "")
+	store := storagetest.NewFakeStorage()
+
+	s, err := NewSupervisor(&Config{Store: store})
+	if err != nil {
+		t.Fatalf("NewSupervisor: %v", err)
+	}
+	if s.fastModel != "claude-3-5-haiku-20241022" {
+		t.Errorf("Expected the provider's fast model by default, got %s", s.fastModel)
+	}
+
+	// A chosen model is used for everything
+	s, err = NewSupervisor(&Config{Store: store, Model: "claude-opus-4-1"})
+	if err != nil {
+		t.Fatalf("NewSupervisor: %v", err)
+	}
+	if s.fastModel != "claude-opus-4-1" {
+		t.Errorf("Expected the chosen model as fast model, got %s", s.fastModel)
+	}
+}
+
+func TestOpenAIProviderRequestTimeout(t *testing.T) {
+	for _, env := range []string{"VC_AI_PROVIDER", "VC_AI_MODEL", "VC_AI_BASE_URL", "VC_AI_API_KEY_ENV"} {
+		t.Setenv(env, "")
+	}
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	p, _, _, err := newProvider(&Config{Provider: ProviderLocal, Model: "llama3", BaseURL: server.URL + "/v1"}, 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("newProvider: %v", err)
+	}
+
+	// A hung endpoint fails even though the context has no deadline
+	start := time.Now()
+	if _, err := p.Complete(context.Background(), CompletionRequest{Model: "llama3", Prompt: "hi"}); err == nil {
+		t.Fatal("Expected a timeout error from a hung endpoint")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Request took %v, expected it to stop at the 100ms timeout", elapsed)
+	}
+}
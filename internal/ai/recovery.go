@@ -7,7 +7,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/anthropics/anthropic-sdk-go"
 	"github.com/steveyegge/vc/internal/types"
 )
 
@@ -46,45 +45,16 @@ func (s *Supervisor) GenerateRecoveryStrategy(ctx context.Context, issue *types.
 	// Build the prompt for recovery strategy
 	prompt := s.buildRecoveryPrompt(issue, gateResults)
 
-	// Call Anthropic API with retry logic
-	var response *anthropic.Message
-	err := s.retryWithBackoff(ctx, "recovery-strategy", func(attemptCtx context.Context) error {
-		resp, apiErr := s.client.Messages.New(attemptCtx, anthropic.MessageNewParams{
-			Model:     anthropic.Model(s.model),
-			MaxTokens: 3072, // Medium-length responses for strategy
-			Messages: []anthropic.MessageParam{
-				anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
-			},
-		})
-		if apiErr != nil {
-			return apiErr
-		}
-		response = resp
-		return nil
-	})
-
+	// Call the AI provider with retry logic
+	strategy, response, err := completeStructured[RecoveryStrategy](ctx, s, "recovery-strategy", CompletionRequest{
+		Model:     s.model,
+		MaxTokens: 3072, // Medium-length responses for strategy
+		Prompt:    prompt,
+		IssueID:   issue.ID,
+	}, "recovery strategy response")
 	if err != nil {
-		return nil, fmt.Errorf("anthropic API call failed: %w", err)
-	}
-
-	// Extract the text content from the response
-	var responseText string
-	for _, block := range response.Content {
-		if block.Type == "text" {
-			responseText += block.Text
-		}
-	}
-
-	// Parse the response as JSON using resilient parser
-	parseResult := Parse[RecoveryStrategy](responseText, ParseOptions{
-		Context:   "recovery strategy response",
-		LogErrors: boolPtr(true),
-	})
-	if !parseResult.Success {
-		// vc-227: Truncate AI response to prevent log spam
-		return nil, fmt.Errorf("failed to parse recovery strategy response: %s (response: %s)", parseResult.Error, truncateString(responseText, 200))
+		return nil, err
 	}
-	strategy := parseResult.Data
 
 	// Log the strategy
 	duration := time.Since(startTime)
@@ -92,7 +62,7 @@ func (s *Supervisor) GenerateRecoveryStrategy(ctx context.Context, issue *types.
 		issue.ID, strategy.Action, strategy.Confidence, duration)
 
 	// Log AI usage to events
-	if err := s.logAIUsage(ctx, issue.ID, "recovery-strategy", response.Usage.InputTokens, response.Usage.OutputTokens, duration); err != nil {
+	if err := s.logAIUsage(ctx, issue.ID, "recovery-strategy", response.InputTokens, response.OutputTokens, duration); err != nil {
 		fmt.Fprintf(os.Stderr, "warning: failed to log AI usage: %v\n", err)
 	}
 
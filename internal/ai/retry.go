@@ -4,10 +4,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/google/uuid"
+	"github.com/steveyegge/vc/internal/events"
 )
 
 // RetryConfig holds retry configuration for API calls
@@ -17,6 +26,7 @@ type RetryConfig struct {
 	MaxBackoff        time.Duration // Maximum backoff duration (default: 30s)
 	BackoffMultiplier float64       // Backoff multiplier (default: 2.0)
 	Timeout           time.Duration // Per-request timeout (default: 60s)
+	RetryBudget       time.Duration // Maximum total wait between attempts of one call (default: 2m, 0 = no limit)
 
 	// Circuit breaker settings
 	CircuitBreakerEnabled bool          // Enable circuit breaker (default: true)
@@ -75,6 +85,7 @@ func DefaultRetryConfig() RetryConfig {
 		MaxBackoff:            30 * time.Second,
 		BackoffMultiplier:     2.0,
 		Timeout:               60 * time.Second,
+		RetryBudget:           2 * time.Minute,
 		CircuitBreakerEnabled: true,
 		FailureThreshold:      5,
 		SuccessThreshold:      2,
@@ -207,7 +218,10 @@ func (cb *CircuitBreaker) transitionToHalfOpen() {
 	fmt.Printf("Circuit breaker state transition: %s → %s (probing for recovery)\n", oldState, cb.state)
 }
 
-// retryWithBackoff executes an operation with retry and exponential backoff
+// retryWithBackoff executes an operation with retry and exponential backoff.
+// Waits are jittered, a Retry-After from the provider takes precedence, and
+// the total wait of one call is bounded by the retry budget. Canceling ctx
+// aborts at once, without further attempts.
 func (s *Supervisor) retryWithBackoff(ctx context.Context, operation string, fn func(context.Context) error) error {
 	// Acquire concurrency slot if limiter is enabled (vc-220)
 	if s.concurrencySem != nil {
@@ -218,6 +232,7 @@ func (s *Supervisor) retryWithBackoff(ctx context.Context, operation string, fn
 	}
 
 	var lastErr error
+	var waited time.Duration
 	backoff := s.retry.InitialBackoff
 
 	for attempt := 0; attempt <= s.retry.MaxRetries; attempt++ {
@@ -248,10 +263,16 @@ func (s *Supervisor) retryWithBackoff(ctx context.Context, operation string, fn
 
 			if attempt > 0 {
 				fmt.Printf("AI API %s succeeded after %d retries\n", operation, attempt)
+				s.logRetryEvent(ctx, events.EventTypeAIRetried, events.SeverityInfo, operation, attempt, waited, nil)
 			}
 			return nil
 		}
 
+		// A canceled call (shutdown) is neither retried nor a provider failure
+		if ctx.Err() != nil {
+			return fmt.Errorf("%s failed: context canceled: %w", operation, ctx.Err())
+		}
+
 		lastErr = err
 
 		// Record failure with circuit breaker if it's a retriable error
@@ -271,18 +292,24 @@ func (s *Supervisor) retryWithBackoff(ctx context.Context, operation string, fn
 			break
 		}
 
-		// Check if context is already canceled
-		if ctx.Err() != nil {
-			return fmt.Errorf("%s failed: context canceled: %w", operation, ctx.Err())
+		wait := jitter(backoff)
+		if retryAfter, ok := retryAfterFromError(err); ok {
+			wait = retryAfter
+		}
+		if s.retry.RetryBudget > 0 && waited+wait > s.retry.RetryBudget {
+			err := fmt.Errorf("%s failed after %d attempts: retry budget of %v exhausted: %w",
+				operation, attempt+1, s.retry.RetryBudget, lastErr)
+			s.logRetryEvent(ctx, events.EventTypeAIRetriesExhausted, events.SeverityWarning, operation, attempt, waited, err)
+			return err
 		}
 
 		// Log the retry
 		fmt.Printf("AI API %s failed (attempt %d/%d), retrying in %v: %v\n",
-			operation, attempt+1, s.retry.MaxRetries+1, backoff, err)
+			operation, attempt+1, s.retry.MaxRetries+1, wait, err)
 
-		// Sleep with exponential backoff
 		select {
-		case <-time.After(backoff):
+		case <-time.After(wait):
+			waited += wait
 			// Calculate next backoff with exponential growth
 			backoff = time.Duration(float64(backoff) * s.retry.BackoffMultiplier)
 			if backoff > s.retry.MaxBackoff {
@@ -293,23 +320,122 @@ func (s *Supervisor) retryWithBackoff(ctx context.Context, operation string, fn
 		}
 	}
 
-	return fmt.Errorf("%s failed after %d attempts: %w", operation, s.retry.MaxRetries+1, lastErr)
+	err := fmt.Errorf("%s failed after %d attempts: %w", operation, s.retry.MaxRetries+1, lastErr)
+	s.logRetryEvent(ctx, events.EventTypeAIRetriesExhausted, events.SeverityWarning, operation, s.retry.MaxRetries, waited, err)
+	return err
+}
+
+// jitter spreads a backoff over [d/2, d] so concurrent callers hitting the
+// same rate limit don't retry in lockstep
+func jitter(d time.Duration) time.Duration {
+	if d <= 1 {
+		return d
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(d-half)+1))
+}
+
+// retryAfterFromError returns the wait a provider asked for in a
+// Retry-After (or retry-after-ms) header, if the error carries one
+func retryAfterFromError(err error) (time.Duration, bool) {
+	var anthropicErr *anthropic.Error
+	var statusErr *providerStatusError
+	var header http.Header
+	switch {
+	case errors.As(err, &anthropicErr) && anthropicErr.Response != nil:
+		header = anthropicErr.Response.Header
+	case errors.As(err, &statusErr):
+		header = statusErr.Header
+	}
+	return parseRetryAfter(header, time.Now())
+}
+
+// parseRetryAfter reads retry-after-ms, then Retry-After as seconds or an
+// HTTP date
+func parseRetryAfter(header http.Header, now time.Time) (time.Duration, bool) {
+	if header == nil {
+		return 0, false
+	}
+	if ms, err := strconv.ParseFloat(header.Get("Retry-After-Ms"), 64); err == nil && ms >= 0 {
+		return time.Duration(ms * float64(time.Millisecond)), true
+	}
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds >= 0 {
+		return time.Duration(seconds * float64(time.Second)), true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if wait := at.Sub(now); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// logRetryEvent records a call that needed retries as a SYSTEM event; the
+// counts add up to the AI retry totals in 'vc stats'
+func (s *Supervisor) logRetryEvent(ctx context.Context, eventType events.EventType, severity events.EventSeverity,
+	operation string, retries int, waited time.Duration, callErr error) {
+	data := map[string]interface{}{
+		"operation": operation,
+		"retries":   retries,
+		"waited_ms": waited.Milliseconds(),
+	}
+	message := fmt.Sprintf("AI %s succeeded after %d retries", operation, retries)
+	if callErr != nil {
+		data["error"] = callErr.Error()
+		message = fmt.Sprintf("AI %s gave up after %d retries: %v", operation, retries, callErr)
+	}
+	event := &events.AgentEvent{
+		ID:        uuid.New().String(),
+		Type:      eventType,
+		Timestamp: time.Now(),
+		IssueID:   "SYSTEM",
+		Severity:  severity,
+		Message:   message,
+		Data:      data,
+	}
+	if s.provider != nil {
+		event.SetAIModel(s.Provider(), s.model)
+	}
+	s.storeEvent(ctx, event)
 }
 
-// isRetriableError determines if an error is retriable (transient)
+// isRetriableError determines if an error is retriable (transient): rate
+// limits, overload and 5xx answers, timeouts and network errors. Other 4xx
+// answers and unknown errors are terminal.
 func isRetriableError(err error) bool {
 	if err == nil {
 		return false
 	}
+	if errors.Is(err, ErrCircuitOpen) || errors.Is(err, context.Canceled) {
+		return false
+	}
 
 	// Network errors and timeouts are retriable
-	if errors.Is(err, context.DeadlineExceeded) {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, io.ErrUnexpectedEOF) {
 		return true
 	}
 
-	// Check for HTTP status codes indicating transient errors
-	// Anthropic SDK should wrap these, but we check the error string
-	errStr := err.Error()
+	// Provider answers carry their status code
+	var anthropicErr *anthropic.Error
+	var statusErr *providerStatusError
+	switch {
+	case errors.As(err, &anthropicErr):
+		return isRetriableStatus(anthropicErr.StatusCode)
+	case errors.As(err, &statusErr):
+		return isRetriableStatus(statusErr.StatusCode)
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	// Fall back to the message for errors that lost their type
+	errStr := strings.ToLower(err.Error())
 
 	// Rate limits (429) are retriable
 	if strings.Contains(errStr, "429") || strings.Contains(errStr, "rate limit") {
@@ -319,10 +445,12 @@ func isRetriableError(err error) bool {
 	// Server errors (5xx) are retriable
 	if strings.Contains(errStr, "500") || strings.Contains(errStr, "502") ||
 		strings.Contains(errStr, "503") || strings.Contains(errStr, "504") ||
+		strings.Contains(errStr, "529") ||
 		strings.Contains(errStr, "internal server error") ||
 		strings.Contains(errStr, "bad gateway") ||
 		strings.Contains(errStr, "service unavailable") ||
-		strings.Contains(errStr, "gateway timeout") {
+		strings.Contains(errStr, "gateway timeout") ||
+		strings.Contains(errStr, "overloaded") {
 		return true
 	}
 
@@ -335,13 +463,19 @@ func isRetriableError(err error) bool {
 		return true
 	}
 
-	// 4xx client errors (except rate limits) are NOT retriable
-	// These indicate bad requests that won't succeed on retry
-	if strings.Contains(errStr, "400") || strings.Contains(errStr, "401") ||
-		strings.Contains(errStr, "403") || strings.Contains(errStr, "404") {
-		return false
-	}
+	// Default to not retrying unknown errors, including 4xx client errors
+	// other than rate limits: they won't succeed on retry
+	return false
+}
 
-	// Default to not retrying unknown errors
+// isRetriableStatus reports whether an HTTP status is worth retrying: 408,
+// 409 (Anthropic's lock conflict), 429, 5xx and 529 (overloaded)
+func isRetriableStatus(code int) bool {
+	switch {
+	case code == http.StatusRequestTimeout, code == http.StatusConflict, code == http.StatusTooManyRequests:
+		return true
+	case code >= 500:
+		return true
+	}
 	return false
 }
@@ -0,0 +1,196 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage/storagetest"
+)
+
+func newRetryTestSupervisor(t *testing.T, budget time.Duration) (*Supervisor, *storagetest.FakeStorage) {
+	t.Helper()
+	store := storagetest.NewFakeStorage()
+	return &Supervisor{
+		store: store,
+		retry: RetryConfig{
+			MaxRetries:        3,
+			InitialBackoff:    time.Millisecond,
+			MaxBackoff:        10 * time.Millisecond,
+			BackoffMultiplier: 2.0,
+			Timeout:           time.Second,
+			RetryBudget:       budget,
+		},
+	}, store
+}
+
+func rateLimited(retryAfter string) error {
+	header := http.Header{}
+	if retryAfter != "" {
+		header.Set("Retry-After", retryAfter)
+	}
+	return &providerStatusError{Provider: ProviderOpenAI, StatusCode: 429, Status: "429 Too Many Requests", Header: header}
+}
+
+func systemEvents(t *testing.T, store *storagetest.FakeStorage, eventType events.EventType) []*events.AgentEvent {
+	t.Helper()
+	stored, err := store.GetAgentEvents(context.Background(), events.EventFilter{Type: eventType})
+	if err != nil {
+		t.Fatalf("Failed to get events: %v", err)
+	}
+	return stored
+}
+
+// TestRetryRecordsRetriedCall verifies a call that succeeds after retrying
+// is recorded with its retry count
+func TestRetryRecordsRetriedCall(t *testing.T) {
+	s, store := newRetryTestSupervisor(t, time.Minute)
+	calls := 0
+	err := s.retryWithBackoff(context.Background(), "assessment", func(ctx context.Context) error {
+		calls++
+		if calls < 3 {
+			return rateLimited("0")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	retried := systemEvents(t, store, events.EventTypeAIRetried)
+	if len(retried) != 1 {
+		t.Fatalf("Expected 1 ai_retried event, got %d", len(retried))
+	}
+	if fmt.Sprint(retried[0].Data["retries"]) != "2" || retried[0].Data["operation"] != "assessment" || retried[0].IssueID != "SYSTEM" {
+		t.Errorf("Unexpected event: %+v", retried[0])
+	}
+	if len(systemEvents(t, store, events.EventTypeAIRetriesExhausted)) != 0 {
+		t.Error("Expected no ai_retries_exhausted event")
+	}
+}
+
+// TestRetryBudget verifies a Retry-After beyond the retry budget gives up
+// at once with a warning event
+func TestRetryBudget(t *testing.T) {
+	s, store := newRetryTestSupervisor(t, time.Second)
+	calls := 0
+	start := time.Now()
+	err := s.retryWithBackoff(context.Background(), "analysis", func(ctx context.Context) error {
+		calls++
+		return rateLimited("60")
+	})
+	if err == nil || !strings.Contains(err.Error(), "retry budget") {
+		t.Fatalf("Expected a retry budget error, got %v", err)
+	}
+	if calls != 1 || time.Since(start) > 500*time.Millisecond {
+		t.Errorf("Expected to give up after 1 call without waiting, got %d calls in %v", calls, time.Since(start))
+	}
+
+	exhausted := systemEvents(t, store, events.EventTypeAIRetriesExhausted)
+	if len(exhausted) != 1 || exhausted[0].Severity != events.SeverityWarning {
+		t.Fatalf("Expected 1 warning ai_retries_exhausted event, got %+v", exhausted)
+	}
+}
+
+// TestRetryExhausted verifies running out of retries is recorded
+func TestRetryExhausted(t *testing.T) {
+	s, store := newRetryTestSupervisor(t, 0)
+	calls := 0
+	err := s.retryWithBackoff(context.Background(), "analysis", func(ctx context.Context) error {
+		calls++
+		return errors.New("503 service unavailable")
+	})
+	if err == nil || calls != 4 {
+		t.Fatalf("Expected failure after 4 calls, got %d calls, err %v", calls, err)
+	}
+	exhausted := systemEvents(t, store, events.EventTypeAIRetriesExhausted)
+	if len(exhausted) != 1 || fmt.Sprint(exhausted[0].Data["retries"]) != "3" {
+		t.Fatalf("Expected an ai_retries_exhausted event with 3 retries, got %+v", exhausted)
+	}
+}
+
+// TestRetryAbortsOnCancel verifies canceling the context stops retries at
+// once, even during a long Retry-After wait
+func TestRetryAbortsOnCancel(t *testing.T) {
+	s, store := newRetryTestSupervisor(t, time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+	start := time.Now()
+	err := s.retryWithBackoff(ctx, "analysis", func(ctx context.Context) error {
+		calls++
+		return rateLimited("30")
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if calls != 1 || time.Since(start) > 5*time.Second {
+		t.Errorf("Expected 1 call and a prompt return, got %d calls in %v", calls, time.Since(start))
+	}
+	if len(systemEvents(t, store, events.EventTypeAIRetriesExhausted)) != 0 {
+		t.Error("A canceled call should not be recorded as exhausted")
+	}
+}
+
+func TestIsRetriableErrorStatus(t *testing.T) {
+	tests := []struct {
+		status int
+		want   bool
+	}{
+		{429, true},
+		{500, true},
+		{503, true},
+		{529, true},
+		{408, true},
+		{400, false},
+		{401, false},
+		{404, false},
+	}
+	for _, tt := range tests {
+		err := &providerStatusError{Provider: ProviderOpenAI, StatusCode: tt.status, Status: http.StatusText(tt.status)}
+		if got := isRetriableError(err); got != tt.want {
+			t.Errorf("isRetriableError(%d) = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+	if isRetriableError(context.Canceled) || isRetriableError(ErrCircuitOpen) {
+		t.Error("Cancellation and an open circuit must not be retried")
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		header http.Header
+		want   time.Duration
+		ok     bool
+	}{
+		{"none", http.Header{}, 0, false},
+		{"seconds", http.Header{"Retry-After": {"3"}}, 3 * time.Second, true},
+		{"milliseconds", http.Header{"Retry-After-Ms": {"250"}, "Retry-After": {"3"}}, 250 * time.Millisecond, true},
+		{"date", http.Header{"Retry-After": {now.Add(5 * time.Second).Format(http.TimeFormat)}}, 5 * time.Second, true},
+		{"garbage", http.Header{"Retry-After": {"soon"}}, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.header, now)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("%s: got %v, %v; want %v, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestJitter(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		if d := jitter(time.Second); d < 500*time.Millisecond || d > time.Second {
+			t.Fatalf("jitter(1s) = %v, want within [500ms, 1s]", d)
+		}
+	}
+}
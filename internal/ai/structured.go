@@ -0,0 +1,117 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/steveyegge/vc/internal/events"
+)
+
+// completeStructured asks the model for JSON and parses it as T. A response
+// that only parses after repair is recorded as an ai_response_repaired event;
+// one that can't be parsed at all gets a single retry asking for only valid
+// JSON matching T's schema, recorded as an ai_response_retried event. The
+// returned response carries the tokens of both calls.
+func completeStructured[T any](ctx context.Context, s *Supervisor, operation string, req CompletionRequest, parseContext string) (T, *CompletionResponse, error) {
+	var zero T
+	response, err := s.completeJSON(ctx, operation, req)
+	if err != nil {
+		return zero, nil, err
+	}
+
+	opts := ParseOptions{
+		Context:   parseContext,
+		LogErrors: boolPtr(true),
+	}
+	parseResult := Parse[T](response.Text, opts)
+	if parseResult.Success {
+		s.RecordResponseRepair(ctx, operation, req.IssueID, parseResult.Repair)
+		return parseResult.Data, response, nil
+	}
+
+	retryReq := req
+	retryReq.Prompt = StructuredRetryPrompt(req.Prompt, response.Text, parseResult.Error, SchemaHint[T]())
+	retry, err := s.completeJSON(ctx, operation, retryReq)
+	if err != nil {
+		s.RecordResponseRetry(ctx, operation, req.IssueID, parseResult.Error, false)
+		return zero, nil, fmt.Errorf("failed to parse %s: %s (response: %s); retry failed: %w",
+			parseContext, parseResult.Error, truncateString(response.Text, 200), err)
+	}
+	total := *retry
+	total.InputTokens += response.InputTokens
+	total.OutputTokens += response.OutputTokens
+
+	retryResult := Parse[T](retry.Text, opts)
+	s.RecordResponseRetry(ctx, operation, req.IssueID, parseResult.Error, retryResult.Success)
+	if !retryResult.Success {
+		// vc-227: Truncate AI response to prevent log spam
+		return zero, &total, fmt.Errorf("failed to parse %s: %s (response: %s)",
+			parseContext, retryResult.Error, truncateString(retry.Text, 200))
+	}
+	return retryResult.Data, &total, nil
+}
+
+// StructuredRetryPrompt asks again for a response that could not be parsed,
+// quoting the bad output and the schema it should have matched.
+func StructuredRetryPrompt(prompt, response, parseErr, schema string) string {
+	var b strings.Builder
+	b.WriteString(prompt)
+	b.WriteString("\n\n---\n\nYour previous response to this request could not be parsed as the expected JSON.\n\n")
+	fmt.Fprintf(&b, "Parse error: %s\n\n", parseErr)
+	fmt.Fprintf(&b, "Previous response (truncated):\n%s\n\n", truncateString(response, 1000))
+	fmt.Fprintf(&b, "Return ONLY valid JSON matching this schema, with no prose, comments or code fences:\n%s\n", schema)
+	return b.String()
+}
+
+// RecordResponseRepair records that a structured response for operation only
+// parsed after repair. It does nothing for responses that parsed as is.
+func (s *Supervisor) RecordResponseRepair(ctx context.Context, operation, issueID, repair string) {
+	if repair == "" {
+		return
+	}
+	event, err := events.NewAIResponseRepairedEvent(systemIssue(issueID),
+		fmt.Sprintf("AI %s response needed repair (%s)", operation, repair),
+		events.AIResponseRepairData{
+			Purpose:   OperationPurpose(operation),
+			Operation: operation,
+			Repair:    repair,
+			Success:   true,
+		})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to create ai_response_repaired event: %v\n", err)
+		return
+	}
+	s.storeEvent(ctx, event)
+}
+
+// RecordResponseRetry records that an unparseable structured response for
+// operation was asked for again, and whether the retry parsed.
+func (s *Supervisor) RecordResponseRetry(ctx context.Context, operation, issueID, parseErr string, success bool) {
+	outcome := "succeeded"
+	if !success {
+		outcome = "failed"
+	}
+	event, err := events.NewAIResponseRetriedEvent(systemIssue(issueID),
+		fmt.Sprintf("AI %s response was unparseable; retry %s", operation, outcome),
+		events.AIResponseRepairData{
+			Purpose:   OperationPurpose(operation),
+			Operation: operation,
+			Error:     truncateString(parseErr, 500),
+			Success:   success,
+		})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to create ai_response_retried event: %v\n", err)
+		return
+	}
+	s.storeEvent(ctx, event)
+}
+
+// systemIssue returns issueID, or SYSTEM for events not about an issue
+func systemIssue(issueID string) string {
+	if issueID == "" {
+		return "SYSTEM"
+	}
+	return issueID
+}
@@ -0,0 +1,251 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage/storagetest"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// malformedCorpus holds structured responses as models have actually sent
+// them, each paired with the repair Parse should report
+var malformedCorpus = []struct {
+	name   string
+	input  string
+	repair string
+}{
+	{
+		name:   "clean",
+		input:  `{"is_duplicate": true, "confidence": 0.92, "reasoning": "Same nil check in parser.go"}`,
+		repair: "",
+	},
+	{
+		name:   "json fence",
+		input:  "```json\n{\"is_duplicate\": false, \"confidence\": 0.2, \"reasoning\": \"Different files\"}\n```",
+		repair: RepairCodeFence,
+	},
+	{
+		name: "trailing commas",
+		input: `{
+  "is_duplicate": true,
+  "confidence": 0.9,
+  "reasoning": "Both fix the retry loop",
+}`,
+		repair: RepairCleanup,
+	},
+	{
+		name: "comments and a URL",
+		input: `{
+  // the model explaining itself
+  "is_duplicate": false,
+  "confidence": 0.4, /* unsure */
+  "reasoning": "See https://example.com/issues/12 // not the same"
+}`,
+		repair: RepairCleanup,
+	},
+	{
+		name: "prose before and after",
+		input: `Looking at both issues, I believe they describe the same problem.
+
+{"is_duplicate": true, "confidence": 0.88, "reasoning": "Both report the {panic} in Stop()"}
+
+Let me know if you need anything else {or more detail}.`,
+		repair: RepairExtract,
+	},
+	{
+		name:   "fence inside prose with trailing comma",
+		input:  "Here is my analysis:\n\n```json\n{\"is_duplicate\": true, \"confidence\": 0.95, \"reasoning\": \"identical titles\",}\n```\n\nHope this helps!",
+		repair: RepairExtract,
+	},
+	{
+		name:   "braces in prose before the object",
+		input:  `The set {a, b} overlaps. Result: {"is_duplicate": false, "confidence": 0.1, "reasoning": "no overlap"}`,
+		repair: RepairExtract,
+	},
+	{
+		name:   "unquoted keys",
+		input:  `{is_duplicate: true, confidence: 0.9, reasoning: "same stack trace"}`,
+		repair: RepairCleanup,
+	},
+}
+
+func TestParse_MalformedCorpus(t *testing.T) {
+	for _, tt := range malformedCorpus {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Parse[DuplicateCheckResponse](tt.input, ParseOptions{LogErrors: BoolPtr(false)})
+			if !result.Success {
+				t.Fatalf("Expected parse to succeed, got: %s", result.Error)
+			}
+			if result.Repair != tt.repair {
+				t.Errorf("Expected repair %q, got %q", tt.repair, result.Repair)
+			}
+			if result.Data.Confidence == 0 || result.Data.Reasoning == "" {
+				t.Errorf("Expected populated response, got %+v", result.Data)
+			}
+		})
+	}
+}
+
+func TestParse_UnrepairableResponses(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{
+			name:    "truncated",
+			input:   `{"is_duplicate": true, "confidence": 0.9, "reasoning": "Both issues descr`,
+			wantErr: "all JSON parsing strategies failed",
+		},
+		{
+			name:    "refusal",
+			input:   `I'm sorry, I can't compare these issues without more context.`,
+			wantErr: "all JSON parsing strategies failed",
+		},
+		{
+			name:    "wrong schema",
+			input:   `{"summary": "These look similar", "score": 7}`,
+			wantErr: "does not match expected schema",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Parse[DuplicateCheckResponse](tt.input, ParseOptions{LogErrors: BoolPtr(false)})
+			if result.Success {
+				t.Fatalf("Expected parse to fail, got %+v", result.Data)
+			}
+			if !strings.Contains(result.Error, tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, result.Error)
+			}
+		})
+	}
+}
+
+func TestParse_SkipsNestedFragments(t *testing.T) {
+	// The balanced scan must pick the outer object, not the first result
+	input := `Result: {"results": [{"existing_issue_id": "vc-1", "is_duplicate": true, "confidence": 0.9, "reasoning": "same"}]} done`
+	result := Parse[BatchDuplicateCheckResponse](input)
+	if !result.Success {
+		t.Fatalf("Expected parse to succeed, got: %s", result.Error)
+	}
+	if len(result.Data.Results) != 1 || result.Data.Results[0].ExistingIssueID != "vc-1" {
+		t.Errorf("Unexpected results: %+v", result.Data.Results)
+	}
+}
+
+func TestSchemaHint(t *testing.T) {
+	hint := SchemaHint[BatchDuplicateCheckResponse]()
+	for _, want := range []string{`"results": [{`, `"existing_issue_id": "<string>"`, `"is_duplicate": <true|false>`, `"confidence": <number>`} {
+		if !strings.Contains(hint, want) {
+			t.Errorf("Expected %q in hint:\n%s", want, hint)
+		}
+	}
+}
+
+// TestCompleteStructured_RetriesOnce verifies an unparseable response is
+// asked for again with the schema, and both outcomes are recorded as events
+func TestCompleteStructured_RetriesOnce(t *testing.T) {
+	replies := []string{
+		`I think these are duplicates, confidence is high.`,
+		`{"is_duplicate": true, "confidence": 0.9, "reasoning": "Same bug"}`,
+	}
+	var calls atomic.Int32
+	var retryPrompt string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1)) - 1
+		var body struct {
+			Messages []struct {
+				Content string `json:"content"`
+			} `json:"messages"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if n == 1 && len(body.Messages) > 0 {
+			retryPrompt = body.Messages[len(body.Messages)-1].Content
+		}
+		reply := replies[len(replies)-1]
+		if n < len(replies) {
+			reply = replies[n]
+		}
+		content, _ := json.Marshal(reply)
+		w.Write([]byte(`{"model":"llama3","choices":[{"message":{"role":"assistant","content":` + string(content) + `}}],
+			"usage":{"prompt_tokens":100,"completion_tokens":10}}`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+	s, err := NewSupervisor(&Config{Provider: ProviderLocal, Model: "llama3", BaseURL: server.URL, Store: store})
+	if err != nil {
+		t.Fatalf("NewSupervisor: %v", err)
+	}
+
+	candidate := &types.Issue{ID: "vc-2", Title: "Nil pointer in parser"}
+	existing := &types.Issue{ID: "vc-1", Title: "Parser panics on nil"}
+	response, err := s.CheckIssueDuplicate(ctx, candidate, existing)
+	if err != nil {
+		t.Fatalf("CheckIssueDuplicate: %v", err)
+	}
+	if !response.IsDuplicate || response.Reasoning != "Same bug" {
+		t.Errorf("Unexpected response: %+v", response)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Expected 2 calls, got %d", calls.Load())
+	}
+	if !strings.Contains(retryPrompt, "Return ONLY valid JSON") || !strings.Contains(retryPrompt, `"is_duplicate"`) {
+		t.Errorf("Retry prompt lacks the schema:\n%s", retryPrompt)
+	}
+
+	retried, err := store.GetAgentEvents(ctx, events.EventFilter{Type: events.EventTypeAIResponseRetried})
+	if err != nil {
+		t.Fatalf("Failed to get events: %v", err)
+	}
+	if len(retried) != 1 || retried[0].IssueID != "vc-2" {
+		t.Fatalf("Expected one ai_response_retried event for vc-2, got %+v", retried)
+	}
+	data, err := retried[0].GetAIResponseRepairData()
+	if err != nil {
+		t.Fatalf("GetAIResponseRepairData: %v", err)
+	}
+	if !data.Success || data.Operation != "duplicate_check" || data.Purpose != PurposeDedup || data.Error == "" {
+		t.Errorf("Unexpected retry data: %+v", data)
+	}
+}
+
+func TestCompleteStructured_RecordsRepair(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, _ := json.Marshal("```json\n{\"is_duplicate\": false, \"confidence\": 0.3, \"reasoning\": \"different\",}\n```")
+		w.Write([]byte(`{"model":"llama3","choices":[{"message":{"role":"assistant","content":` + string(content) + `}}]}`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+	s, err := NewSupervisor(&Config{Provider: ProviderLocal, Model: "llama3", BaseURL: server.URL, Store: store})
+	if err != nil {
+		t.Fatalf("NewSupervisor: %v", err)
+	}
+
+	if _, err := s.CheckIssueDuplicate(ctx, &types.Issue{Title: "a"}, &types.Issue{ID: "vc-1", Title: "b"}); err != nil {
+		t.Fatalf("CheckIssueDuplicate: %v", err)
+	}
+	repaired, err := store.GetAgentEvents(ctx, events.EventFilter{Type: events.EventTypeAIResponseRepaired})
+	if err != nil {
+		t.Fatalf("Failed to get events: %v", err)
+	}
+	if len(repaired) != 1 || repaired[0].IssueID != "SYSTEM" {
+		t.Fatalf("Expected one SYSTEM ai_response_repaired event, got %+v", repaired)
+	}
+	if data, err := repaired[0].GetAIResponseRepairData(); err != nil || data.Repair != RepairCleanup {
+		t.Errorf("Unexpected repair data: %+v (%v)", data, err)
+	}
+	retried, _ := store.GetAgentEvents(ctx, events.EventFilter{Type: events.EventTypeAIResponseRetried})
+	if len(retried) != 0 {
+		t.Errorf("Expected no retry, got %d", len(retried))
+	}
+}
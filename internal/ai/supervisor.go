@@ -1,11 +1,11 @@
 package ai
 
 import (
+	"context"
 	"fmt"
-	"os"
+	"time"
 
-	"github.com/anthropics/anthropic-sdk-go"
-	"github.com/anthropics/anthropic-sdk-go/option"
+	"github.com/steveyegge/vc/internal/config"
 	"github.com/steveyegge/vc/internal/storage"
 	"github.com/steveyegge/vc/internal/types"
 	"golang.org/x/sync/semaphore"
@@ -16,6 +16,7 @@ import (
 //
 // The Supervisor's responsibilities are distributed across multiple files:
 // - supervisor.go: Core struct and constructor (this file)
+// - provider.go: AI providers (Anthropic, OpenAI, local OpenAI-compatible)
 // - retry.go: Circuit breaker and retry logic
 // - assessment.go: Pre-execution assessment and completion assessment
 // - analysis.go: Post-execution analysis
@@ -26,23 +27,32 @@ import (
 // - planning.go: Mission planning and phase refinement
 // - utils.go: Shared utilities (logging, summarization, truncation)
 type Supervisor struct {
-	client         *anthropic.Client
-	store          storage.Storage
+	provider       Provider
+	store          storage.IssueStore
 	model          string
+	fastModel      string // Cheaper model for short decisions
 	retry          RetryConfig
 	circuitBreaker *CircuitBreaker
 	concurrencySem *semaphore.Weighted // Limits concurrent AI API calls (vc-220)
+	prices         map[string]config.ModelPrice
 }
 
 // Compile-time check that Supervisor implements MissionPlanner
 var _ types.MissionPlanner = (*Supervisor)(nil)
 
 // Config holds supervisor configuration
+// Provider, Model, BaseURL and APIKeyEnv fall back to the VC_AI_PROVIDER,
+// VC_AI_MODEL, VC_AI_BASE_URL and VC_AI_API_KEY_ENV env vars, then to the
+// provider's defaults.
 type Config struct {
-	APIKey string // Anthropic API key (if empty, reads from ANTHROPIC_API_KEY env var)
-	Model  string // Model to use (default: claude-sonnet-4-5-20250929)
-	Store  storage.Storage
-	Retry  RetryConfig // Retry configuration (uses defaults if not specified)
+	Provider  string // anthropic (default), openai or local
+	Model     string // Model to use (default: claude-sonnet-4-5-20250929 for anthropic, gpt-4o for openai; required for local)
+	BaseURL   string // API endpoint (default: the provider's; http://localhost:11434/v1 for local)
+	APIKeyEnv string // Env var holding the API key (default: ANTHROPIC_API_KEY or OPENAI_API_KEY; optional for local)
+	APIKey    string // API key (if empty, read from APIKeyEnv)
+	Store     storage.IssueStore
+	Retry     RetryConfig                  // Retry configuration (uses defaults if not specified); Retry.Timeout also bounds each HTTP request
+	Prices    map[string]config.ModelPrice // Model prices for cost estimates, added to config.DefaultModelPrices
 }
 
 // NewSupervisor creates a new AI supervisor
@@ -51,26 +61,16 @@ func NewSupervisor(cfg *Config) (*Supervisor, error) {
 		return nil, fmt.Errorf("storage is required")
 	}
 
-	apiKey := cfg.APIKey
-	if apiKey == "" {
-		apiKey = os.Getenv("ANTHROPIC_API_KEY")
-		if apiKey == "" {
-			return nil, fmt.Errorf("ANTHROPIC_API_KEY not set")
-		}
-	}
-
-	model := cfg.Model
-	if model == "" {
-		model = "claude-sonnet-4-5-20250929"
-	}
-
 	// Use default retry config if not specified
 	retry := cfg.Retry
 	if retry.MaxRetries == 0 {
 		retry = DefaultRetryConfig()
 	}
 
-	client := anthropic.NewClient(option.WithAPIKey(apiKey))
+	provider, model, fastModel, err := newProvider(cfg, retry.Timeout)
+	if err != nil {
+		return nil, err
+	}
 
 	// Initialize circuit breaker if enabled
 	var circuitBreaker *CircuitBreaker
@@ -92,11 +92,69 @@ func NewSupervisor(cfg *Config) (*Supervisor, error) {
 	}
 
 	return &Supervisor{
-		client:         &client,
+		provider:       provider,
 		store:          cfg.Store,
 		model:          model,
+		fastModel:      fastModel,
 		retry:          retry,
 		circuitBreaker: circuitBreaker,
 		concurrencySem: concurrencySem,
+		prices:         mergePrices(cfg.Prices),
 	}, nil
 }
+
+// Provider returns the name of the AI provider the supervisor calls
+func (s *Supervisor) Provider() string {
+	if s.provider == nil {
+		return ""
+	}
+	return s.provider.Name()
+}
+
+// Model returns the supervisor's default model
+func (s *Supervisor) Model() string {
+	return s.model
+}
+
+// Client returns the provider the supervisor sends prompts to, for callers
+// that build their own prompts (e.g. commit messages)
+func (s *Supervisor) Client() Provider {
+	return s.provider
+}
+
+// complete sends a prompt to the provider with retries and the circuit
+// breaker. A request without a model uses the supervisor's default.
+func (s *Supervisor) complete(ctx context.Context, operation string, req CompletionRequest) (*CompletionResponse, error) {
+	return s.call(ctx, operation, req, s.provider.Complete)
+}
+
+// completeJSON is complete for prompts whose answer is a JSON object
+func (s *Supervisor) completeJSON(ctx context.Context, operation string, req CompletionRequest) (*CompletionResponse, error) {
+	return s.call(ctx, operation, req, s.provider.StructuredComplete)
+}
+
+func (s *Supervisor) call(ctx context.Context, operation string, req CompletionRequest,
+	fn func(context.Context, CompletionRequest) (*CompletionResponse, error)) (*CompletionResponse, error) {
+	if req.Model == "" {
+		req.Model = s.model
+	}
+	if req.MaxTokens == 0 {
+		req.MaxTokens = 4096
+	}
+
+	start := time.Now()
+	var response *CompletionResponse
+	err := s.retryWithBackoff(ctx, operation, func(attemptCtx context.Context) error {
+		resp, apiErr := fn(attemptCtx, req)
+		if apiErr != nil {
+			return apiErr
+		}
+		response = resp
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s API call failed: %w", s.provider.Name(), err)
+	}
+	s.logAICall(ctx, operation, req, response, time.Since(start))
+	return response, nil
+}
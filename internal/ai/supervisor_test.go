@@ -9,249 +9,13 @@ import (
 	"testing"
 	"time"
 
-	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage/storagetest"
 	"github.com/steveyegge/vc/internal/types"
 )
 
-// mockStorage implements a minimal storage.Storage for testing
-type mockStorage struct {
-	issues       map[string]*types.Issue
-	comments     []string
-	dependencies []types.Dependency
-	labels       map[string][]string                                                // issueID -> labels (vc-151)
-	createError  error                                                              // Inject errors for testing
-	depError     error
-	createFunc   func(ctx context.Context, issue *types.Issue, actor string) error // Allow overriding
-}
-
-func newMockStorage() *mockStorage {
-	return &mockStorage{
-		issues:       make(map[string]*types.Issue),
-		comments:     []string{},
-		dependencies: []types.Dependency{},
-		labels:       make(map[string][]string), // vc-151
-	}
-}
-
-func (m *mockStorage) CreateIssue(ctx context.Context, issue *types.Issue, actor string) error {
-	// Allow overriding for testing
-	if m.createFunc != nil {
-		return m.createFunc(ctx, issue, actor)
-	}
-	if m.createError != nil {
-		return m.createError
-	}
-	// Generate a simple ID
-	issue.ID = "test-" + issue.Title[:min(5, len(issue.Title))]
-	m.issues[issue.ID] = issue
-	return nil
-}
-
-func (m *mockStorage) CreateMission(ctx context.Context, mission *types.Mission, actor string) error {
-	// Generate a simple ID for mission-based issues
-	return m.CreateIssue(ctx, &mission.Issue, actor)
-}
-
-func (m *mockStorage) AddComment(ctx context.Context, issueID, actor, comment string) error {
-	m.comments = append(m.comments, comment)
-	return nil
-}
-
-func (m *mockStorage) AddDependency(ctx context.Context, dep *types.Dependency, actor string) error {
-	if m.depError != nil {
-		return m.depError
-	}
-	m.dependencies = append(m.dependencies, *dep)
-	return nil
-}
-
-// Stub implementations for other required methods
-func (m *mockStorage) GetIssue(ctx context.Context, id string) (*types.Issue, error) {
-	return m.issues[id], nil
-}
-func (m *mockStorage) GetMission(ctx context.Context, id string) (*types.Mission, error) {
-	issue, err := m.GetIssue(ctx, id)
-	if err != nil {
-		return nil, err
-	}
-	if issue == nil {
-		return nil, nil
-	}
-	return &types.Mission{Issue: *issue}, nil
-}
-func (m *mockStorage) UpdateMission(ctx context.Context, id string, updates map[string]interface{}, actor string) error {
-	return nil
-}
-func (m *mockStorage) UpdateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string) error {
-	return nil
-}
-func (m *mockStorage) CloseIssue(ctx context.Context, id string, reason string, actor string) error {
-	return nil
-}
-func (m *mockStorage) SearchIssues(ctx context.Context, query string, filter types.IssueFilter) ([]*types.Issue, error) {
-	return nil, nil
-}
-func (m *mockStorage) RemoveDependency(ctx context.Context, issueID, dependsOnID string, actor string) error {
-	return nil
-}
-func (m *mockStorage) GetDependencies(ctx context.Context, issueID string) ([]*types.Issue, error) {
-	return nil, nil
-}
-func (m *mockStorage) GetDependencyRecords(ctx context.Context, issueID string) ([]*types.Dependency, error) {
-	return nil, nil
-}
-func (m *mockStorage) GetDependents(ctx context.Context, issueID string) ([]*types.Issue, error) {
-	return nil, nil
-}
-func (m *mockStorage) GetDependencyTree(ctx context.Context, issueID string, maxDepth int) ([]*types.TreeNode, error) {
-	return nil, nil
-}
-func (m *mockStorage) DetectCycles(ctx context.Context) ([][]*types.Issue, error) {
-	return nil, nil
-}
-func (m *mockStorage) AddLabel(ctx context.Context, issueID, label, actor string) error {
-	// vc-151: Actually store labels for testing
-	if m.labels[issueID] == nil {
-		m.labels[issueID] = []string{}
-	}
-	m.labels[issueID] = append(m.labels[issueID], label)
-	return nil
-}
-func (m *mockStorage) RemoveLabel(ctx context.Context, issueID, label, actor string) error {
-	return nil
-}
-func (m *mockStorage) GetLabels(ctx context.Context, issueID string) ([]string, error) {
-	// vc-151: Return stored labels
-	return m.labels[issueID], nil
-}
-func (m *mockStorage) GetIssuesByLabel(ctx context.Context, label string) ([]*types.Issue, error) {
-	return nil, nil
-}
-func (m *mockStorage) GetReadyWork(ctx context.Context, filter types.WorkFilter) ([]*types.Issue, error) {
-	return nil, nil
-}
-func (m *mockStorage) GetBlockedIssues(ctx context.Context) ([]*types.BlockedIssue, error) {
-	return nil, nil
-}
-func (m *mockStorage) GetReadyBlockers(ctx context.Context, limit int) ([]*types.Issue, error) {
-	return nil, nil
-}
-func (m *mockStorage) IsEpicComplete(ctx context.Context, epicID string) (bool, error) {
-	return false, nil
-}
-func (m *mockStorage) GetMissionForTask(ctx context.Context, taskID string) (*types.MissionContext, error) {
-	return nil, errors.New("not implemented in mock")
-}
-func (m *mockStorage) GetMissionsNeedingGates(ctx context.Context) ([]*types.Issue, error) {
-	return nil, nil
-}
-func (m *mockStorage) GetEvents(ctx context.Context, issueID string, limit int) ([]*types.Event, error) {
-	return nil, nil
-}
-func (m *mockStorage) GetStatistics(ctx context.Context) (*types.Statistics, error) {
-	return nil, nil
-}
-func (m *mockStorage) RegisterInstance(ctx context.Context, instance *types.ExecutorInstance) error {
-	return nil
-}
-func (m *mockStorage) MarkInstanceStopped(ctx context.Context, instanceID string) error {
-	return nil
-}
-func (m *mockStorage) UpdateHeartbeat(ctx context.Context, instanceID string) error {
-	return nil
-}
-func (m *mockStorage) GetActiveInstances(ctx context.Context) ([]*types.ExecutorInstance, error) {
-	return nil, nil
-}
-func (m *mockStorage) CleanupStaleInstances(ctx context.Context, staleThreshold int) (int, error) {
-	return 0, nil
-}
-func (m *mockStorage) DeleteOldStoppedInstances(ctx context.Context, olderThanSeconds int, maxToKeep int) (int, error) {
-	return 0, nil
-}
-func (m *mockStorage) ClaimIssue(ctx context.Context, issueID, executorInstanceID string) error {
-	return nil
-}
-func (m *mockStorage) GetExecutionState(ctx context.Context, issueID string) (*types.IssueExecutionState, error) {
-	return nil, nil
-}
-func (m *mockStorage) UpdateExecutionState(ctx context.Context, issueID string, state types.ExecutionState) error {
-	return nil
-}
-func (m *mockStorage) SaveCheckpoint(ctx context.Context, issueID string, checkpointData interface{}) error {
-	return nil
-}
-func (m *mockStorage) GetCheckpoint(ctx context.Context, issueID string) (string, error) {
-	return "", nil
-}
-func (m *mockStorage) ReleaseIssue(ctx context.Context, issueID string) error {
-	return nil
-}
-func (m *mockStorage) ReleaseIssueAndReopen(ctx context.Context, issueID, actor, errorComment string) error {
-	return nil
-}
-func (m *mockStorage) Close() error {
-	return nil
-}
-
-// Execution History methods
-func (m *mockStorage) GetExecutionHistory(ctx context.Context, issueID string) ([]*types.ExecutionAttempt, error) {
-	return nil, nil
-}
-func (m *mockStorage) RecordExecutionAttempt(ctx context.Context, attempt *types.ExecutionAttempt) error {
-	return nil
-}
-
-// Agent Events methods
-func (m *mockStorage) StoreAgentEvent(ctx context.Context, event *events.AgentEvent) error {
-	return nil
-}
-func (m *mockStorage) GetAgentEvents(ctx context.Context, filter events.EventFilter) ([]*events.AgentEvent, error) {
-	return nil, nil
-}
-func (m *mockStorage) GetAgentEventsByIssue(ctx context.Context, issueID string) ([]*events.AgentEvent, error) {
-	return nil, nil
-}
-func (m *mockStorage) GetRecentAgentEvents(ctx context.Context, limit int) ([]*events.AgentEvent, error) {
-	return nil, nil
-}
-func (m *mockStorage) GetConfig(ctx context.Context, key string) (string, error) {
-	return "", nil
-}
-func (m *mockStorage) SetConfig(ctx context.Context, key, value string) error {
-	return nil
-}
-
-func (m *mockStorage) CleanupEventsByAge(ctx context.Context, retentionDays, criticalRetentionDays, batchSize int) (int, error) {
-	return 0, nil
-}
-
-func (m *mockStorage) CleanupEventsByGlobalLimit(ctx context.Context, globalLimit int, batchSize int) (int, error) {
-	return 0, nil
-}
-
-func (m *mockStorage) CleanupEventsByIssueLimit(ctx context.Context, perIssueLimit int, batchSize int) (int, error) {
-	return 0, nil
-}
-
-func (m *mockStorage) GetEventCounts(ctx context.Context) (*types.EventCounts, error) {
-	return &types.EventCounts{}, nil
-}
-
-func (m *mockStorage) VacuumDatabase(ctx context.Context) error {
-	return nil
-}
-
-func min(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
-}
-
 // TestBuildAssessmentPrompt tests prompt construction
 func TestBuildAssessmentPrompt(t *testing.T) {
-	store := newMockStorage()
+	store := storagetest.NewFakeStorage()
 	supervisor := &Supervisor{
 		store: store,
 		model: "test-model",
@@ -267,7 +31,7 @@ func TestBuildAssessmentPrompt(t *testing.T) {
 		Priority:           1,
 	}
 
-	prompt := supervisor.buildAssessmentPrompt(issue)
+	prompt := supervisor.buildAssessmentPrompt(issue, "")
 
 	// Verify prompt contains key elements
 	if !strings.Contains(prompt, "test-1") {
@@ -291,11 +55,31 @@ func TestBuildAssessmentPrompt(t *testing.T) {
 	if !strings.Contains(prompt, "confidence") {
 		t.Error("Prompt should request confidence")
 	}
+	if strings.Contains(prompt, "Estimate") {
+		t.Error("Prompt should not mention an estimate the issue doesn't have")
+	}
+}
+
+// TestBuildAssessmentPromptEstimateHint tests that the estimate and its
+// history reach the assessment
+func TestBuildAssessmentPromptEstimateHint(t *testing.T) {
+	supervisor := &Supervisor{store: storagetest.NewFakeStorage(), model: "test-model"}
+	minutes := 60
+	issue := &types.Issue{ID: "test-1", Title: "Add feature X", IssueType: types.TypeTask, EstimatedMinutes: &minutes}
+	hint := "Similar past issues (3 closed tasks) took 2.0x their estimate (median; middle half 1.5x-3.0x)"
+
+	prompt := supervisor.buildAssessmentPrompt(issue, hint)
+	if !strings.Contains(prompt, "Estimate: 60 minutes") {
+		t.Error("Prompt should contain the estimate")
+	}
+	if !strings.Contains(prompt, "Estimate history: "+hint) {
+		t.Error("Prompt should contain the estimate history")
+	}
 }
 
 // TestBuildAnalysisPrompt tests analysis prompt construction
 func TestBuildAnalysisPrompt(t *testing.T) {
-	store := newMockStorage()
+	store := storagetest.NewFakeStorage()
 	supervisor := &Supervisor{
 		store: store,
 		model: "test-model",
@@ -344,12 +128,6 @@ func TestBuildAnalysisPrompt(t *testing.T) {
 
 // TestCreateDiscoveredIssues tests issue creation from AI analysis
 func TestCreateDiscoveredIssues(t *testing.T) {
-	store := newMockStorage()
-	supervisor := &Supervisor{
-		store: store,
-		model: "test-model",
-	}
-
 	parentIssue := &types.Issue{
 		ID:       "parent-1",
 		Title:    "Parent task",
@@ -450,10 +228,12 @@ func TestCreateDiscoveredIssues(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Reset store
-			store.issues = make(map[string]*types.Issue)
-			store.dependencies = []types.Dependency{}
-			store.labels = make(map[string][]string) // vc-151
+			store := storagetest.NewFakeStorage()
+			store.AddIssue(parentIssue)
+			supervisor := &Supervisor{
+				store: store,
+				model: "test-model",
+			}
 
 			ctx := context.Background()
 			createdIDs, err := supervisor.CreateDiscoveredIssues(ctx, parentIssue, tt.discovered)
@@ -466,10 +246,15 @@ func TestCreateDiscoveredIssues(t *testing.T) {
 				t.Errorf("Created %d issues, want %d", len(createdIDs), tt.wantCount)
 			}
 
+			// All issues go to storage as one batch
+			if n := store.CallCount("CreateIssues"); n != 1 {
+				t.Errorf("Expected 1 batch create, got %d", n)
+			}
+
 			// Verify created issues have correct types and priorities
 			for i, id := range createdIDs {
-				issue := store.issues[id]
-				if issue == nil {
+				issue, err := store.GetIssue(ctx, id)
+				if err != nil || issue == nil {
 					t.Fatalf("Issue %s not found in store", id)
 				}
 
@@ -491,11 +276,19 @@ func TestCreateDiscoveredIssues(t *testing.T) {
 			}
 
 			// Verify dependencies were created
-			if len(store.dependencies) != tt.wantCount {
-				t.Errorf("Created %d dependencies, want %d", len(store.dependencies), tt.wantCount)
+			var deps []*types.Dependency
+			for _, id := range createdIDs {
+				records, err := store.GetDependencyRecords(ctx, id)
+				if err != nil {
+					t.Fatalf("GetDependencyRecords(%s) failed: %v", id, err)
+				}
+				deps = append(deps, records...)
+			}
+			if len(deps) != tt.wantCount {
+				t.Errorf("Created %d dependencies, want %d", len(deps), tt.wantCount)
 			}
 
-			for _, dep := range store.dependencies {
+			for _, dep := range deps {
 				if dep.DependsOnID != parentIssue.ID {
 					t.Errorf("Dependency should reference parent issue %s, got %s", parentIssue.ID, dep.DependsOnID)
 				}
@@ -531,7 +324,7 @@ func TestCreateDiscoveredIssues(t *testing.T) {
 
 // TestCreateDiscoveredIssues_PartialFailure tests behavior when issue creation fails mid-way
 func TestCreateDiscoveredIssues_PartialFailure(t *testing.T) {
-	store := newMockStorage()
+	store := storagetest.NewFakeStorage()
 	supervisor := &Supervisor{
 		store: store,
 		model: "test-model",
@@ -548,18 +341,17 @@ func TestCreateDiscoveredIssues_PartialFailure(t *testing.T) {
 		{Title: "Issue 3", Description: "Third", Type: "task", Priority: "P2"},
 	}
 
+	store.AddIssue(parentIssue)
+
 	// First two succeed, third fails
 	callCount := 0
-	store.createFunc = func(ctx context.Context, issue *types.Issue, actor string) error {
+	store.FailWhen("CreateIssue", func(args []interface{}) error {
 		callCount++
 		if callCount > 2 {
 			return fmt.Errorf("simulated creation failure")
 		}
-		// Default creation logic with unique ID
-		issue.ID = fmt.Sprintf("test-%d", callCount)
-		store.issues[issue.ID] = issue
 		return nil
-	}
+	})
 
 	ctx := context.Background()
 	createdIDs, err := supervisor.CreateDiscoveredIssues(ctx, parentIssue, discovered)
@@ -573,9 +365,135 @@ func TestCreateDiscoveredIssues_PartialFailure(t *testing.T) {
 		t.Errorf("Should have created 2 issues before failing, got %d", len(createdIDs))
 	}
 
-	// Verify the two successful issues exist
-	if len(store.issues) != 2 {
-		t.Errorf("Store should have 2 issues, got %d", len(store.issues))
+	// Verify the two successful issues exist alongside the parent
+	all, err := store.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		t.Fatalf("SearchIssues failed: %v", err)
+	}
+	if len(all) != 3 {
+		t.Errorf("Store should have the parent and 2 discovered issues, got %d", len(all))
+	}
+}
+
+// TestCreateDiscoveredIssuesFrom_Origin verifies discovered issues name the
+// execution they came from and inherit the parent's area and component labels
+func TestCreateDiscoveredIssuesFrom_Origin(t *testing.T) {
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+	supervisor := &Supervisor{store: store, model: "test-model"}
+
+	parentIssue := &types.Issue{Title: "Parent task", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, parentIssue, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	for _, label := range []string{"area:storage", "component:sqlite", "needs-review"} {
+		if err := store.AddLabel(ctx, parentIssue.ID, label, "test"); err != nil {
+			t.Fatalf("AddLabel failed: %v", err)
+		}
+	}
+
+	createdIDs, err := supervisor.CreateDiscoveredIssuesFrom(ctx, parentIssue, []DiscoveredIssue{
+		{Title: "Index is missing", Description: "Queries scan the table", Type: "bug", DiscoveryType: "related"},
+	}, DiscoveryOrigin{Attempt: 4, CommitHash: "0123456789abcdef"}, TriagePolicy{})
+	if err != nil || len(createdIDs) != 1 {
+		t.Fatalf("CreateDiscoveredIssuesFrom = %v, %v", createdIDs, err)
+	}
+
+	issue, err := store.GetIssue(ctx, createdIDs[0])
+	if err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	want := "_Discovered during execution of " + parentIssue.ID + " (attempt 4, commit 01234567)_"
+	if !strings.HasSuffix(issue.Description, want) {
+		t.Errorf("Expected description to end with %q, got %q", want, issue.Description)
+	}
+	labels, err := store.GetLabels(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetLabels failed: %v", err)
+	}
+	got := strings.Join(labels, ",")
+	for _, label := range []string{"discovered:related", "area:storage", "component:sqlite"} {
+		if !strings.Contains(got, label) {
+			t.Errorf("Expected label %s, got %v", label, labels)
+		}
+	}
+	if strings.Contains(got, "needs-review") {
+		t.Errorf("State labels must not be inherited, got %v", labels)
+	}
+}
+
+// TestCreateDiscoveredIssuesFrom_InferredTriage verifies inferred priorities,
+// types and estimates are applied, and P0 is capped for human triage
+func TestCreateDiscoveredIssuesFrom_InferredTriage(t *testing.T) {
+	discovered := []DiscoveredIssue{
+		{Title: "SQL injection in search", Type: "bug", Priority: "P0", DiscoveryType: "related",
+			EstimatedMinutes: 60, PriorityRationale: "User input reaches the query unescaped"},
+		{Title: "Typo in help text", Type: "chore", Priority: "P3", DiscoveryType: "blocker"},
+		{Title: "Flaky lint step", Type: "task", Priority: "urgent", DiscoveryType: "blocker"},
+		{Title: "Add a metric", Type: "enhancement", Priority: "P2"},
+	}
+	tests := []struct {
+		name         string
+		policy       TriagePolicy
+		wantPriority []int
+		wantTriage   []bool
+	}{
+		{"inferred", TriagePolicy{UseInferred: true}, []int{1, 3, 1, 2}, []bool{true, false, true, false}},
+		{"forced triage", TriagePolicy{UseInferred: true, ForceTriage: true}, []int{1, 3, 1, 2}, []bool{true, true, true, true}},
+		{"calculated", TriagePolicy{}, []int{2, 0, 0, 1}, []bool{false, false, false, false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := storagetest.NewFakeStorage()
+			parentIssue := &types.Issue{ID: "parent-1", Title: "Parent task", Priority: 1}
+			store.AddIssue(parentIssue)
+			supervisor := &Supervisor{store: store, model: "test-model"}
+
+			createdIDs, err := supervisor.CreateDiscoveredIssuesFrom(ctx, parentIssue, discovered, DiscoveryOrigin{}, tt.policy)
+			if err != nil || len(createdIDs) != len(discovered) {
+				t.Fatalf("CreateDiscoveredIssuesFrom = %v, %v", createdIDs, err)
+			}
+			for i, id := range createdIDs {
+				issue, _ := store.GetIssue(ctx, id)
+				if issue.Priority != tt.wantPriority[i] {
+					t.Errorf("%s: got P%d, want P%d", issue.Title, issue.Priority, tt.wantPriority[i])
+				}
+				labels, _ := store.GetLabels(ctx, id)
+				if got := strings.Contains(strings.Join(labels, ","), LabelNeedsTriage); got != tt.wantTriage[i] {
+					t.Errorf("%s: needs-triage = %v, want %v (labels %v)", issue.Title, got, tt.wantTriage[i], labels)
+				}
+			}
+
+			first, _ := store.GetIssue(ctx, createdIDs[0])
+			if tt.policy.UseInferred {
+				if first.EstimatedMinutes == nil || *first.EstimatedMinutes != 60 {
+					t.Errorf("Expected the 60 minute estimate, got %v", first.EstimatedMinutes)
+				}
+				if !strings.Contains(first.Description, "**Triage (inferred):** P1 bug (suggested P0), ~60 min — User input reaches the query unescaped") {
+					t.Errorf("Expected the triage rationale in the description, got %q", first.Description)
+				}
+			} else if first.EstimatedMinutes != nil || strings.Contains(first.Description, "Triage") {
+				t.Errorf("Expected no inferred triage, got %+v", first)
+			}
+		})
+	}
+}
+
+func TestDiscoveryOrigin_Describe(t *testing.T) {
+	tests := []struct {
+		origin DiscoveryOrigin
+		want   string
+	}{
+		{DiscoveryOrigin{}, "vc-1"},
+		{DiscoveryOrigin{Attempt: 3}, "vc-1 (attempt 3)"},
+		{DiscoveryOrigin{CommitHash: "abc"}, "vc-1 (commit abc)"},
+		{DiscoveryOrigin{Attempt: 3, CommitHash: "0123456789"}, "vc-1 (attempt 3, commit 01234567)"},
+	}
+	for _, tt := range tests {
+		if got := tt.origin.Describe("vc-1"); got != tt.want {
+			t.Errorf("Describe(%+v) = %q, want %q", tt.origin, got, tt.want)
+		}
 	}
 }
 
@@ -642,15 +560,13 @@ func TestPriorityMapping(t *testing.T) {
 		{"", 2, 2},   // Empty AI priority, inherits parent P2
 	}
 
-	store := newMockStorage()
-	supervisor := &Supervisor{
-		store: store,
-		model: "test-model",
-	}
-
 	for _, tt := range tests {
 		t.Run(tt.input, func(t *testing.T) {
-			store.issues = make(map[string]*types.Issue)
+			store := storagetest.NewFakeStorage()
+			supervisor := &Supervisor{
+				store: store,
+				model: "test-model",
+			}
 
 			// Create parent with specific priority for this test (vc-152)
 			parentIssue := &types.Issue{
@@ -658,6 +574,7 @@ func TestPriorityMapping(t *testing.T) {
 				Title:    "Parent",
 				Priority: tt.parentPriority,
 			}
+			store.AddIssue(parentIssue)
 
 			discovered := []DiscoveredIssue{
 				{
@@ -680,7 +597,10 @@ func TestPriorityMapping(t *testing.T) {
 				t.Fatal("Should create 1 issue")
 			}
 
-			issue := store.issues[createdIDs[0]]
+			issue, err := store.GetIssue(ctx, createdIDs[0])
+			if err != nil || issue == nil {
+				t.Fatalf("Issue %s not found in store: %v", createdIDs[0], err)
+			}
 			if issue.Priority != tt.want {
 				t.Errorf("Priority %s mapped to %d, want %d", tt.input, issue.Priority, tt.want)
 			}
@@ -704,17 +624,15 @@ func TestTypeMapping(t *testing.T) {
 		{"", types.TypeTask},        // empty, defaults to task
 	}
 
-	store := newMockStorage()
-	supervisor := &Supervisor{
-		store: store,
-		model: "test-model",
-	}
-
-	parentIssue := &types.Issue{ID: "parent", Title: "Parent"}
-
 	for _, tt := range tests {
 		t.Run(tt.input, func(t *testing.T) {
-			store.issues = make(map[string]*types.Issue)
+			store := storagetest.NewFakeStorage()
+			supervisor := &Supervisor{
+				store: store,
+				model: "test-model",
+			}
+			parentIssue := &types.Issue{ID: "parent", Title: "Parent"}
+			store.AddIssue(parentIssue)
 
 			discovered := []DiscoveredIssue{
 				{
@@ -736,7 +654,10 @@ func TestTypeMapping(t *testing.T) {
 				t.Fatal("Should create 1 issue")
 			}
 
-			issue := store.issues[createdIDs[0]]
+			issue, err := store.GetIssue(ctx, createdIDs[0])
+			if err != nil || issue == nil {
+				t.Fatalf("Issue %s not found in store: %v", createdIDs[0], err)
+			}
 			if issue.IssueType != tt.want {
 				t.Errorf("Type %s mapped to %s, want %s", tt.input, issue.IssueType, tt.want)
 			}
@@ -1002,7 +923,7 @@ func TestCircuitBreakerThreadSafety(t *testing.T) {
 // TestCircuitBreakerWithRetry tests integration with retryWithBackoff
 func TestCircuitBreakerWithRetry(t *testing.T) {
 	t.Run("circuit breaker blocks retries when open", func(t *testing.T) {
-		store := newMockStorage()
+		store := storagetest.NewFakeStorage()
 		cfg := &Config{
 			Store: store,
 			Retry: RetryConfig{
@@ -1061,7 +982,7 @@ func TestCircuitBreakerWithRetry(t *testing.T) {
 	})
 
 	t.Run("successful request records success with circuit breaker", func(t *testing.T) {
-		store := newMockStorage()
+		store := storagetest.NewFakeStorage()
 		cfg := &Config{
 			Store: store,
 			Retry: RetryConfig{
@@ -1107,7 +1028,7 @@ func TestCircuitBreakerWithRetry(t *testing.T) {
 	})
 
 	t.Run("non-retriable errors don't affect circuit breaker", func(t *testing.T) {
-		store := newMockStorage()
+		store := storagetest.NewFakeStorage()
 		cfg := &Config{
 			Store: store,
 			Retry: RetryConfig{
@@ -1187,7 +1108,7 @@ func TestCircuitBreakerStateTransitions(t *testing.T) {
 
 // TestCircuitBreakerDisabled tests behavior when circuit breaker is disabled
 func TestCircuitBreakerDisabled(t *testing.T) {
-	store := newMockStorage()
+	store := storagetest.NewFakeStorage()
 	cfg := &Config{
 		Store: store,
 		Retry: RetryConfig{
@@ -6,7 +6,6 @@ import (
 	"os"
 	"time"
 
-	"github.com/anthropics/anthropic-sdk-go"
 	"github.com/steveyegge/vc/internal/types"
 )
 
@@ -52,45 +51,16 @@ func (s *Supervisor) DiagnoseTestFailure(ctx context.Context, issue *types.Issue
 	// Build the diagnosis prompt
 	prompt := s.buildTestFailureDiagnosisPrompt(issue, testOutput)
 
-	// Call Anthropic API with retry logic
-	var response *anthropic.Message
-	err := s.retryWithBackoff(ctx, "test-failure-diagnosis", func(attemptCtx context.Context) error {
-		resp, apiErr := s.client.Messages.New(attemptCtx, anthropic.MessageNewParams{
-			Model:     anthropic.Model(s.model),
-			MaxTokens: 4096,
-			Messages: []anthropic.MessageParam{
-				anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
-			},
-		})
-		if apiErr != nil {
-			return apiErr
-		}
-		response = resp
-		return nil
-	})
-
+	// Call the AI provider with retry logic
+	diagnosis, response, err := completeStructured[TestFailureDiagnosis](ctx, s, "test-failure-diagnosis", CompletionRequest{
+		Model:     s.model,
+		MaxTokens: 4096,
+		Prompt:    prompt,
+		IssueID:   issue.ID,
+	}, "test failure diagnosis response")
 	if err != nil {
-		return nil, fmt.Errorf("anthropic API call failed: %w", err)
-	}
-
-	// Extract the text content from the response
-	var responseText string
-	for _, block := range response.Content {
-		if block.Type == "text" {
-			responseText += block.Text
-		}
-	}
-
-	// Parse the response as JSON using resilient parser
-	parseResult := Parse[TestFailureDiagnosis](responseText, ParseOptions{
-		Context:   "test failure diagnosis response",
-		LogErrors: boolPtr(true),
-	})
-	if !parseResult.Success {
-		// vc-227: Truncate AI response to prevent log spam
-		return nil, fmt.Errorf("failed to parse test failure diagnosis: %s (response: %s)", parseResult.Error, truncateString(responseText, 200))
+		return nil, err
 	}
-	diagnosis := parseResult.Data
 
 	// Log the diagnosis
 	duration := time.Since(startTime)
@@ -98,7 +68,7 @@ func (s *Supervisor) DiagnoseTestFailure(ctx context.Context, issue *types.Issue
 		issue.ID, diagnosis.FailureType, diagnosis.Confidence, duration)
 
 	// Log AI usage to events
-	if err := s.logAIUsage(ctx, issue.ID, "test-failure-diagnosis", response.Usage.InputTokens, response.Usage.OutputTokens, duration); err != nil {
+	if err := s.logAIUsage(ctx, issue.ID, "test-failure-diagnosis", response.InputTokens, response.OutputTokens, duration); err != nil {
 		fmt.Fprintf(os.Stderr, "warning: failed to log AI usage: %v\n", err)
 	}
 
@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/steveyegge/vc/internal/labels"
 	"github.com/steveyegge/vc/internal/priorities"
 	"github.com/steveyegge/vc/internal/types"
 )
@@ -16,17 +18,101 @@ type DiscoveredIssue struct {
 	Type         string `json:"type"`          // bug, task, enhancement, etc.
 	Priority     string `json:"priority"`      // P0, P1, P2, P3
 	DiscoveryType string `json:"discovery_type"` // blocker, related, background (vc-151)
+
+	EstimatedMinutes  int    `json:"estimated_minutes,omitempty"`  // Suggested effort estimate
+	PriorityRationale string `json:"priority_rationale,omitempty"` // Why this priority and type
+}
+
+// LabelNeedsTriage marks discovered issues whose inferred priority a person
+// should review before they are worked on
+const LabelNeedsTriage = types.NeedsTriageLabel
+
+// TriagePolicy is how CreateDiscoveredIssuesFrom applies the priority, type
+// and effort the supervisor inferred for each discovered issue
+type TriagePolicy struct {
+	// UseInferred applies the inferred priority instead of the one calculated
+	// from the discovery type and the parent's priority, which blockers no
+	// longer get. Issues are never created at P0 this way: they are capped
+	// at P1 and labeled needs-triage.
+	UseInferred bool
+	// ForceTriage labels every discovered issue needs-triage
+	ForceTriage bool
+}
+
+// DiscoveryOrigin identifies the execution of the parent issue that an issue
+// was discovered in
+type DiscoveryOrigin struct {
+	Attempt    int    // Attempt number of the parent's execution (0 if unknown)
+	CommitHash string // Commit the execution made (empty if none)
 }
 
-// CreateDiscoveredIssues creates issues from the AI analysis
+// Describe names the origin for people, e.g. "vc-123 (attempt 4, commit 1a2b3c4d)"
+func (o DiscoveryOrigin) Describe(parentID string) string {
+	var details []string
+	if o.Attempt > 0 {
+		details = append(details, fmt.Sprintf("attempt %d", o.Attempt))
+	}
+	if o.CommitHash != "" {
+		hash := o.CommitHash
+		if len(hash) > 8 {
+			hash = hash[:8]
+		}
+		details = append(details, "commit "+hash)
+	}
+	if len(details) == 0 {
+		return parentID
+	}
+	return fmt.Sprintf("%s (%s)", parentID, strings.Join(details, ", "))
+}
+
+// CreateDiscoveredIssues creates issues from the AI analysis in one batch,
+// each with its discovery label and discovered-from dependency on the parent.
+// An issue that fails is skipped (along with its label and dependency); the
+// IDs of the issues that were created are returned with the error.
 func (s *Supervisor) CreateDiscoveredIssues(ctx context.Context, parentIssue *types.Issue, discovered []DiscoveredIssue) ([]string, error) {
-	var createdIDs []string
+	return s.CreateDiscoveredIssuesFrom(ctx, parentIssue, discovered, DiscoveryOrigin{}, TriagePolicy{})
+}
+
+// CreateDiscoveredIssuesFrom is CreateDiscoveredIssues for issues discovered
+// in a known execution of the parent, which each description names. The new
+// issues also inherit the parent's area:* and component:* labels, and their
+// priority, type and effort follow policy.
+func (s *Supervisor) CreateDiscoveredIssuesFrom(ctx context.Context, parentIssue *types.Issue, discovered []DiscoveredIssue, origin DiscoveryOrigin, policy TriagePolicy) ([]string, error) {
+	if len(discovered) == 0 {
+		return nil, nil
+	}
 
-	for _, disc := range discovered {
-		// Calculate priority based on discovery type and parent priority (vc-152)
-		// This overrides the AI-suggested priority string (disc.Priority) for blockers/related/background
-		// The AI's priority suggestion is stored but not used (may be useful for future enhancements)
+	var inherited []string
+	if parentLabels, err := s.store.GetLabels(ctx, parentIssue.ID); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to get labels of %s to inherit: %v\n", parentIssue.ID, err)
+	} else {
+		inherited = labels.Inheritable(parentLabels)
+	}
+
+	newIssues := make([]*types.Issue, len(discovered))
+	opts := types.CreateIssuesOptions{
+		Labels:       make(map[int][]string),
+		Dependencies: make(map[int][]*types.Dependency),
+	}
+
+	for i, disc := range discovered {
+		// Calculate priority based on discovery type and parent priority (vc-152).
+		// With policy.UseInferred (the executor's default) the AI-suggested
+		// priority (disc.Priority) replaces it whenever it parses, so blockers
+		// no longer get P0 from the discovery type, nor related issues their
+		// parent's priority + 1; the calculated priority is only the fallback.
 		priority := priorities.CalculateDiscoveredPriority(parentIssue.Priority, disc.DiscoveryType)
+		needsTriage := policy.ForceTriage
+		if policy.UseInferred {
+			if inferred, ok := parsePriority(disc.Priority); ok {
+				priority = inferred
+			}
+			// Never auto-create P0: a person confirms it
+			if priority == 0 {
+				priority = 1
+				needsTriage = true
+			}
+		}
 
 		// Map string type to types.IssueType
 		issueType := types.TypeTask // default
@@ -43,48 +129,96 @@ func (s *Supervisor) CreateDiscoveredIssues(ctx context.Context, parentIssue *ty
 			issueType = types.TypeChore
 		}
 
+		description := disc.Description
+		if policy.UseInferred {
+			description += triageNote(disc, priority, issueType)
+		}
+
 		// Create the issue
 		newIssue := &types.Issue{
 			Title:       disc.Title,
-			Description: disc.Description + fmt.Sprintf("\n\n_Discovered during execution of %s_", parentIssue.ID),
+			Description: description + fmt.Sprintf("\n\n_Discovered during execution of %s_", origin.Describe(parentIssue.ID)),
 			IssueType:   issueType,
 			Status:      types.StatusOpen,
-			Priority:    priority, // Use calculated priority (vc-152)
+			Priority:    priority, // Inferred, else calculated (vc-152)
 			Assignee:    "ai-supervisor",
 		}
 
-		err := s.store.CreateIssue(ctx, newIssue, "ai-supervisor")
-		if err != nil {
-			return createdIDs, fmt.Errorf("failed to create discovered issue: %w", err)
+		if policy.UseInferred && disc.EstimatedMinutes > 0 {
+			estimate := disc.EstimatedMinutes
+			newIssue.EstimatedMinutes = &estimate
 		}
 
-		// The ID is set on the issue by CreateIssue
-		id := newIssue.ID
-
-		createdIDs = append(createdIDs, id)
-		fmt.Printf("Created discovered issue %s: %s\n", id, disc.Title)
+		newIssues[i] = newIssue
 
-		// Add discovery type label (vc-151)
+		// Add discovery type label (vc-151) and the parent's inheritable labels
 		if disc.DiscoveryType != "" {
-			label := fmt.Sprintf("discovered:%s", disc.DiscoveryType)
-			if err := s.store.AddLabel(ctx, id, label, "ai-supervisor"); err != nil {
-				fmt.Fprintf(os.Stderr, "warning: failed to add label %s to %s: %v\n", label, id, err)
-			} else {
-				fmt.Printf("  Added label: %s\n", label)
-			}
+			opts.Labels[i] = []string{fmt.Sprintf("discovered:%s", disc.DiscoveryType)}
+		}
+		opts.Labels[i] = append(opts.Labels[i], inherited...)
+		if needsTriage {
+			opts.Labels[i] = append(opts.Labels[i], LabelNeedsTriage)
 		}
 
 		// Add a dependency: new issue was discovered from parent
 		// This ensures discovered work doesn't get lost and is tracked properly
-		dep := &types.Dependency{
-			IssueID:     id,
+		// (an empty IssueID refers to the new issue)
+		opts.Dependencies[i] = []*types.Dependency{{
 			DependsOnID: parentIssue.ID,
 			Type:        types.DepDiscoveredFrom,
+		}}
+	}
+
+	// One transaction for the whole batch: consecutive IDs, and each issue is
+	// created with its label and dependency or not at all, so a failure can't
+	// leave an untracked, unlabeled issue behind
+	batchErr := s.store.CreateIssues(ctx, newIssues, "ai-supervisor", opts)
+
+	var createdIDs []string
+	for i, newIssue := range newIssues {
+		// The ID is set on each issue that was created
+		if newIssue.ID == "" {
+			continue
 		}
-		if err := s.store.AddDependency(ctx, dep, "ai-supervisor"); err != nil {
-			fmt.Fprintf(os.Stderr, "warning: failed to add dependency %s -> %s: %v\n", id, parentIssue.ID, err)
+		createdIDs = append(createdIDs, newIssue.ID)
+		fmt.Printf("Created discovered issue %s: %s\n", newIssue.ID, newIssue.Title)
+		for _, label := range opts.Labels[i] {
+			fmt.Printf("  Added label: %s\n", label)
 		}
 	}
 
+	if batchErr != nil {
+		return createdIDs, fmt.Errorf("failed to create discovered issues: %w", batchErr)
+	}
 	return createdIDs, nil
 }
+
+// parsePriority parses a suggested priority, "P0" to "P3"
+func parsePriority(value string) (int, bool) {
+	switch strings.ToUpper(strings.TrimSpace(value)) {
+	case "P0":
+		return 0, true
+	case "P1":
+		return 1, true
+	case "P2":
+		return 2, true
+	case "P3":
+		return 3, true
+	}
+	return 0, false
+}
+
+// triageNote records how an issue's priority was inferred in its description
+func triageNote(disc DiscoveredIssue, priority int, issueType types.IssueType) string {
+	note := fmt.Sprintf("\n\n**Triage (inferred):** P%d %s", priority, issueType)
+	if suggested, ok := parsePriority(disc.Priority); ok && suggested != priority {
+		note += fmt.Sprintf(" (suggested P%d)", suggested)
+	}
+	if disc.EstimatedMinutes > 0 {
+		note += fmt.Sprintf(", ~%d min", disc.EstimatedMinutes)
+	}
+	if disc.PriorityRationale != "" {
+		note += " — " + disc.PriorityRationale
+	}
+	return note
+}
@@ -0,0 +1,114 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage"
+)
+
+// Purposes group AI calls in ai_call_completed events and `vc stats --ai`
+const (
+	PurposeAssessment    = "assessment"
+	PurposeAnalysis      = "analysis"
+	PurposeDedup         = "dedup"
+	PurposeWatchdog      = "watchdog"
+	PurposeHealth        = "health"
+	PurposePlanning      = "planning"
+	PurposeRecovery      = "recovery"
+	PurposeCodeReview    = "code_review"
+	PurposeSummarization = "summarization"
+	PurposeOther         = "other"
+)
+
+// operationPurposes maps supervisor operations (including those CallAI
+// callers pass) to their purpose
+var operationPurposes = map[string]string{
+	"assessment":             PurposeAssessment,
+	"completion-assessment":  PurposeAssessment,
+	"analysis":               PurposeAnalysis,
+	"duplicate_check":        PurposeDedup,
+	"batch_duplicate_check":  PurposeDedup,
+	"anomaly-detection":      PurposeWatchdog,
+	"file_size_evaluation":   PurposeHealth,
+	"cruft_evaluation":       PurposeHealth,
+	"zfc_evaluation":         PurposeHealth,
+	"coverage_description":   PurposeHealth,
+	"planning":               PurposePlanning,
+	"refinement":             PurposePlanning,
+	"phase-validation":       PurposePlanning,
+	"recovery-strategy":      PurposeRecovery,
+	"test-failure-diagnosis": PurposeRecovery,
+	"failure-analysis":       PurposeRecovery,
+	"code-review-decision":   PurposeCodeReview,
+	"test-coverage-analysis": PurposeCodeReview,
+	"code-quality-analysis":  PurposeCodeReview,
+	"summarization":          PurposeSummarization,
+}
+
+// OperationPurpose returns the purpose of a supervisor operation
+func OperationPurpose(operation string) string {
+	if purpose, ok := operationPurposes[operation]; ok {
+		return purpose
+	}
+	return PurposeOther
+}
+
+// logAICall records a completed call's usage as an ai_call_completed event
+// on its issue, or SYSTEM for calls not about one
+func (s *Supervisor) logAICall(ctx context.Context, operation string, req CompletionRequest, resp *CompletionResponse, latency time.Duration) {
+	model := resp.Model
+	if model == "" {
+		model = req.Model
+	}
+	data := events.AICallData{
+		Purpose:      OperationPurpose(operation),
+		Operation:    operation,
+		Provider:     s.Provider(),
+		Model:        model,
+		InputTokens:  resp.InputTokens,
+		OutputTokens: resp.OutputTokens,
+		LatencyMs:    latency.Milliseconds(),
+	}
+	if price, ok := config.LookupModelPrice(s.prices, model); ok {
+		data.EstimatedCostUSD = price.Cost(resp.InputTokens, resp.OutputTokens)
+		data.Priced = true
+	}
+
+	event, err := events.NewAICallCompletedEvent(systemIssue(req.IssueID),
+		fmt.Sprintf("AI %s call: %d input, %d output tokens in %v", operation, resp.InputTokens, resp.OutputTokens, latency.Round(time.Millisecond)),
+		data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to create ai_call_completed event: %v\n", err)
+		return
+	}
+	s.storeEvent(ctx, event)
+}
+
+// storeEvent stores an agent event if the supervisor's store keeps them.
+// It uses a context without the call's deadline, which may be about to expire.
+func (s *Supervisor) storeEvent(ctx context.Context, event *events.AgentEvent) {
+	eventStore, ok := s.store.(storage.EventStore)
+	if !ok {
+		return
+	}
+	if err := eventStore.StoreAgentEvent(context.WithoutCancel(ctx), event); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to store %s event: %v\n", event.Type, err)
+	}
+}
+
+// mergePrices returns the default price table extended by overrides
+func mergePrices(overrides map[string]config.ModelPrice) map[string]config.ModelPrice {
+	prices := make(map[string]config.ModelPrice, len(config.DefaultModelPrices)+len(overrides))
+	for model, price := range config.DefaultModelPrices {
+		prices[model] = price
+	}
+	for model, price := range overrides {
+		prices[model] = price
+	}
+	return prices
+}
@@ -0,0 +1,87 @@
+package ai
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage/storagetest"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// TestCallRecordsUsage verifies each completed call stores an
+// ai_call_completed event with tokens, purpose and estimated cost
+func TestCallRecordsUsage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"model":"llama3-8b","choices":[{"message":{"role":"assistant","content":"A short summary"}}],
+			"usage":{"prompt_tokens":1000,"completion_tokens":200}}`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+	s, err := NewSupervisor(&Config{
+		Provider: ProviderLocal,
+		Model:    "llama3",
+		BaseURL:  server.URL,
+		Store:    store,
+		Prices:   map[string]config.ModelPrice{"llama3": {InputPerMTok: 1, OutputPerMTok: 2}},
+	})
+	if err != nil {
+		t.Fatalf("NewSupervisor: %v", err)
+	}
+
+	issue := &types.Issue{ID: "vc-7", Title: "Summarize"}
+	if _, err := s.SummarizeAgentOutput(ctx, issue, string(make([]byte, 5000)), 500); err != nil {
+		t.Fatalf("SummarizeAgentOutput: %v", err)
+	}
+	if _, err := s.CallAI(ctx, "ping", "anomaly-detection", "", 100); err != nil {
+		t.Fatalf("CallAI: %v", err)
+	}
+
+	calls, err := store.GetAgentEvents(ctx, events.EventFilter{Type: events.EventTypeAICallCompleted})
+	if err != nil {
+		t.Fatalf("Failed to get events: %v", err)
+	}
+	if len(calls) != 2 {
+		t.Fatalf("Expected 2 ai_call_completed events, got %d", len(calls))
+	}
+	byIssue := make(map[string]*events.AICallData)
+	for _, event := range calls {
+		data, err := event.GetAICallData()
+		if err != nil {
+			t.Fatalf("GetAICallData: %v", err)
+		}
+		byIssue[event.IssueID] = data
+	}
+
+	summary := byIssue["vc-7"]
+	if summary == nil || summary.Purpose != PurposeSummarization || summary.Model != "llama3-8b" ||
+		summary.Provider != ProviderLocal || summary.InputTokens != 1000 || summary.OutputTokens != 200 {
+		t.Fatalf("Unexpected summarization usage: %+v", summary)
+	}
+	// llama3-8b is priced by its llama3 prefix: 1000*1/1e6 + 200*2/1e6
+	if !summary.Priced || math.Abs(summary.EstimatedCostUSD-0.0014) > 1e-12 {
+		t.Errorf("Unexpected cost: priced %v, $%v", summary.Priced, summary.EstimatedCostUSD)
+	}
+	if watchdog := byIssue["SYSTEM"]; watchdog == nil || watchdog.Purpose != PurposeWatchdog {
+		t.Errorf("Expected a SYSTEM watchdog call, got %+v", watchdog)
+	}
+}
+
+func TestOperationPurpose(t *testing.T) {
+	for operation, want := range map[string]string{
+		"completion-assessment": PurposeAssessment,
+		"batch_duplicate_check": PurposeDedup,
+		"zfc_evaluation":        PurposeHealth,
+		"something-new":         PurposeOther,
+	} {
+		if got := OperationPurpose(operation); got != want {
+			t.Errorf("OperationPurpose(%s) = %s, want %s", operation, got, want)
+		}
+	}
+}
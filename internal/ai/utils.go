@@ -4,11 +4,9 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"strings"
 	"time"
 	"unicode/utf8"
 
-	"github.com/anthropics/anthropic-sdk-go"
 	"github.com/steveyegge/vc/internal/types"
 )
 
@@ -24,8 +22,8 @@ func (s *Supervisor) logAIUsage(ctx context.Context, issueID, activity string, i
 		return nil
 	}
 
-	comment := fmt.Sprintf("AI Usage (%s): input=%d tokens, output=%d tokens, duration=%v, model=%s",
-		activity, inputTokens, outputTokens, duration, s.model)
+	comment := fmt.Sprintf("AI Usage (%s): input=%d tokens, output=%d tokens, duration=%v, provider=%s, model=%s",
+		activity, inputTokens, outputTokens, duration, s.Provider(), s.model)
 	return s.store.AddComment(ctx, issueID, "ai-supervisor", comment)
 }
 
@@ -34,7 +32,6 @@ func (s *Supervisor) logAIUsage(ctx context.Context, issueID, activity string, i
 // without duplicating retry logic and circuit breaker code
 func (s *Supervisor) CallAI(ctx context.Context, prompt string, operation string, model string, maxTokens int) (string, error) {
 	startTime := time.Now()
-	var responseText string
 
 	// Use default model if not specified
 	if model == "" {
@@ -46,40 +43,22 @@ func (s *Supervisor) CallAI(ctx context.Context, prompt string, operation string
 		maxTokens = 4096
 	}
 
-	// Call Anthropic API with retry logic
-	var response *anthropic.Message
-	err := s.retryWithBackoff(ctx, operation, func(attemptCtx context.Context) error {
-		resp, apiErr := s.client.Messages.New(attemptCtx, anthropic.MessageNewParams{
-			Model:     anthropic.Model(model),
-			MaxTokens: int64(maxTokens),
-			Messages: []anthropic.MessageParam{
-				anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
-			},
-		})
-		if apiErr != nil {
-			return apiErr
-		}
-		response = resp
-		return nil
+	// Call the AI provider with retry logic
+	response, err := s.complete(ctx, operation, CompletionRequest{
+		Model:     model,
+		MaxTokens: maxTokens,
+		Prompt:    prompt,
 	})
-
 	if err != nil {
-		return "", fmt.Errorf("anthropic API call failed: %w", err)
-	}
-
-	// Extract the text content from the response
-	for _, block := range response.Content {
-		if block.Type == "text" {
-			responseText += block.Text
-		}
+		return "", err
 	}
 
 	// Log the call
 	duration := time.Since(startTime)
 	fmt.Printf("AI %s call: input=%d tokens, output=%d tokens, duration=%v\n",
-		operation, response.Usage.InputTokens, response.Usage.OutputTokens, duration)
+		operation, response.InputTokens, response.OutputTokens, duration)
 
-	return responseText, nil
+	return response.Text, nil
 }
 
 // SummarizeAgentOutput uses AI to create an intelligent summary of agent output
@@ -106,37 +85,19 @@ func (s *Supervisor) SummarizeAgentOutput(ctx context.Context, issue *types.Issu
 	// Build the summarization prompt
 	prompt := s.buildSummarizationPrompt(issue, fullOutput, maxLength)
 
-	// Call Anthropic API with retry logic
-	var response *anthropic.Message
-	err := s.retryWithBackoff(ctx, "summarization", func(attemptCtx context.Context) error {
-		resp, apiErr := s.client.Messages.New(attemptCtx, anthropic.MessageNewParams{
-			Model:     anthropic.Model(s.model),
-			MaxTokens: 2048, // Summaries should be concise
-			Messages: []anthropic.MessageParam{
-				anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
-			},
-		})
-		if apiErr != nil {
-			return apiErr
-		}
-		response = resp
-		return nil
+	// Call the AI provider with retry logic
+	response, err := s.complete(ctx, "summarization", CompletionRequest{
+		Model:     s.model,
+		MaxTokens: 2048, // Summaries should be concise
+		Prompt:    prompt,
+		IssueID:   issue.ID,
 	})
-
 	if err != nil {
 		// Don't fall back to heuristics - return the error (ZFC compliance)
 		return "", fmt.Errorf("AI summarization failed after %d retry attempts: %w", s.retry.MaxRetries+1, err)
 	}
 
-	// Extract the text content from the response
-	var summary strings.Builder
-	for _, block := range response.Content {
-		if block.Type == "text" {
-			summary.WriteString(block.Text)
-		}
-	}
-
-	summaryText := summary.String()
+	summaryText := response.Text
 
 	// Log the summarization
 	duration := time.Since(startTime)
@@ -144,7 +105,7 @@ func (s *Supervisor) SummarizeAgentOutput(ctx context.Context, issue *types.Issu
 		len(fullOutput), len(summaryText), duration)
 
 	// Log AI usage to events
-	if err := s.logAIUsage(ctx, issue.ID, "summarization", response.Usage.InputTokens, response.Usage.OutputTokens, duration); err != nil {
+	if err := s.logAIUsage(ctx, issue.ID, "summarization", response.InputTokens, response.OutputTokens, duration); err != nil {
 		fmt.Fprintf(os.Stderr, "warning: failed to log AI usage: %v\n", err)
 	}
 
@@ -0,0 +1,452 @@
+// Package api serves a read-only JSON view of the tracker over HTTP (vc
+// serve): issues with their labels, dependencies, execution state and audit
+// events, agent events, executor instances and statistics. Nothing in it
+// writes, and GET (with HEAD and CORS preflights) is the only method.
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// Page sizes of the list endpoints: the default when limit is absent, and
+// the most one request may ask for
+const (
+	DefaultLimit = 100
+	MaxLimit     = 1000
+)
+
+// Store is the part of the storage the API reads
+type Store interface {
+	SearchIssues(ctx context.Context, query string, filter types.IssueFilter) ([]*types.Issue, error)
+	CountIssues(ctx context.Context, query string, filter types.IssueFilter) (int, error)
+	GetIssue(ctx context.Context, id string) (*types.Issue, error)
+	GetLabels(ctx context.Context, issueID string) ([]string, error)
+	GetIssueMeta(ctx context.Context, issueID string) (map[string]json.RawMessage, error)
+	GetDependencyRecords(ctx context.Context, issueID string) ([]*types.Dependency, error)
+	GetDependents(ctx context.Context, issueID string) ([]*types.Issue, error)
+	GetExecutionState(ctx context.Context, issueID string) (*types.IssueExecutionState, error)
+	GetEvents(ctx context.Context, issueID string, limit int) ([]*types.Event, error)
+	GetAgentEvents(ctx context.Context, filter events.EventFilter) ([]*events.AgentEvent, error)
+	GetActiveInstances(ctx context.Context) ([]*types.ExecutorInstance, error)
+	GetStatistics(ctx context.Context) (*types.Statistics, error)
+}
+
+// Options configure the handler
+type Options struct {
+	// Token, when set, must be sent as "Authorization: Bearer <token>" (or
+	// in an X-VC-Token header) on every request but /openapi.json
+	Token string
+	// CORSOrigins are the origins browsers may call the API from; "*"
+	// allows any. Empty sends no CORS headers.
+	CORSOrigins []string
+}
+
+// IssueList is the /issues response
+type IssueList struct {
+	Issues []*types.Issue `json:"issues"`
+	Total  int            `json:"total"` // Matches ignoring limit and offset
+	Limit  int            `json:"limit"`
+	Offset int            `json:"offset"`
+}
+
+// IssueDetail is the /issues/{id} response
+type IssueDetail struct {
+	Issue *types.Issue `json:"issue"`
+	// Labels of the issue, sorted
+	Labels []string `json:"labels"`
+	// Metadata of the issue by key (see vc meta)
+	Metadata map[string]json.RawMessage `json:"metadata"`
+	// Dependencies are the issue's links to the issues it depends on
+	Dependencies []*types.Dependency `json:"dependencies"`
+	// Dependents are the IDs of the issues that depend on it
+	Dependents []string `json:"dependents"`
+	// ExecutionState is null unless an executor claimed the issue
+	ExecutionState *types.IssueExecutionState `json:"execution_state"`
+}
+
+// errorResponse is the body of every error
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// errBadRequest marks errors caused by the request's parameters
+var errBadRequest = errors.New("bad request")
+
+// NewHandler returns the API handler over store
+func NewHandler(store Store, opts Options) http.Handler {
+	s := &server{store: store, opts: opts}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /openapi.json", s.openAPI)
+	mux.Handle("GET /issues", s.guard(s.listIssues))
+	mux.Handle("GET /issues/{id}", s.guard(s.getIssue))
+	mux.Handle("GET /issues/{id}/events", s.guard(s.issueEvents))
+	mux.Handle("GET /events", s.guard(s.agentEvents))
+	mux.Handle("GET /instances", s.guard(s.instances))
+	mux.Handle("GET /stats", s.guard(s.stats))
+	return s.cors(mux)
+}
+
+// server holds the handler's state
+type server struct {
+	store Store
+	opts  Options
+}
+
+// guard checks the token and turns a handler's error into a JSON error
+// response: 400 for bad parameters, 500 otherwise
+func (s *server) guard(fn func(w http.ResponseWriter, r *http.Request) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.opts.Token != "" && !validToken(r, s.opts.Token) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="vc"`)
+			writeError(w, http.StatusUnauthorized, "missing or invalid token")
+			return
+		}
+		if err := fn(w, r); err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, errBadRequest) {
+				status = http.StatusBadRequest
+			}
+			writeError(w, status, err.Error())
+		}
+	})
+}
+
+// validToken reports whether the request carries the token
+func validToken(r *http.Request, token string) bool {
+	got := r.Header.Get("X-VC-Token")
+	if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		got = bearer
+	}
+	return subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1
+}
+
+// cors adds the CORS headers for allowed origins and answers preflights
+func (s *server) cors(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin != "" && s.allowedOrigin(origin) {
+			h := w.Header()
+			if slices.Contains(s.opts.CORSOrigins, "*") {
+				h.Set("Access-Control-Allow-Origin", "*")
+			} else {
+				h.Set("Access-Control-Allow-Origin", origin)
+				h.Add("Vary", "Origin")
+			}
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				h.Set("Access-Control-Allow-Methods", "GET, HEAD, OPTIONS")
+				h.Set("Access-Control-Allow-Headers", "Authorization, X-VC-Token")
+				h.Set("Access-Control-Max-Age", "600")
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// allowedOrigin reports whether browsers may call the API from origin
+func (s *server) allowedOrigin(origin string) bool {
+	for _, allowed := range s.opts.CORSOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// GET /issues
+func (s *server) listIssues(w http.ResponseWriter, r *http.Request) error {
+	q := r.URL.Query()
+	filter, err := issueFilter(q.Get)
+	if err != nil {
+		return err
+	}
+	if filter.MetaEquals, err = metaParams(q["meta"]); err != nil {
+		return err
+	}
+	issues, err := s.store.SearchIssues(r.Context(), q.Get("q"), filter)
+	if err != nil {
+		return err
+	}
+	total, err := s.store.CountIssues(r.Context(), q.Get("q"), filter)
+	if err != nil {
+		return err
+	}
+	if issues == nil {
+		issues = []*types.Issue{}
+	}
+	return writeJSON(w, IssueList{Issues: issues, Total: total, Limit: filter.Limit, Offset: filter.Offset})
+}
+
+// issueFilter reads the /issues query parameters
+func issueFilter(get func(string) string) (types.IssueFilter, error) {
+	var filter types.IssueFilter
+	var err error
+	if filter.Limit, err = limitParam(get("limit")); err != nil {
+		return filter, err
+	}
+	if v := get("offset"); v != "" {
+		if filter.Offset, err = strconv.Atoi(v); err != nil || filter.Offset < 0 {
+			return filter, fmt.Errorf("%w: invalid offset %q", errBadRequest, v)
+		}
+	}
+	if v := get("status"); v != "" {
+		status := types.Status(v)
+		if !status.IsValid() {
+			return filter, fmt.Errorf("%w: invalid status %q", errBadRequest, v)
+		}
+		filter.Status = &status
+	}
+	if v := get("priority"); v != "" {
+		priority, err := strconv.Atoi(v)
+		if err != nil || priority < 0 || priority > 4 {
+			return filter, fmt.Errorf("%w: invalid priority %q (0-4)", errBadRequest, v)
+		}
+		filter.Priority = &priority
+	}
+	if v := get("type"); v != "" {
+		issueType := types.IssueType(v)
+		if !issueType.IsValid() {
+			return filter, fmt.Errorf("%w: invalid type %q", errBadRequest, v)
+		}
+		filter.IssueType = &issueType
+	}
+	if v := get("assignee"); v != "" {
+		filter.Assignee = &v
+	}
+	if v := get("label"); v != "" {
+		filter.Labels = strings.Split(v, ",")
+	}
+	filter.Project = get("project")
+	if v := get("sort"); v != "" {
+		if !slices.Contains(types.SortableIssueColumns, v) {
+			return filter, fmt.Errorf("%w: invalid sort %q (valid: %s)", errBadRequest, v, strings.Join(types.SortableIssueColumns, ", "))
+		}
+		filter.OrderBy = v
+	}
+	if filter.Descending, err = boolParam("desc", get("desc")); err != nil {
+		return filter, err
+	}
+	if filter.IncludeArchived, err = boolParam("archived", get("archived")); err != nil {
+		return filter, err
+	}
+	if filter.IncludeSystem, err = boolParam("system", get("system")); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
+// metaParams reads the repeatable meta=key=value parameter
+func metaParams(values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	meta := make(map[string]string, len(values))
+	for _, v := range values {
+		key, value, ok := strings.Cut(v, "=")
+		if !ok {
+			return nil, fmt.Errorf("%w: invalid meta %q (want key=value)", errBadRequest, v)
+		}
+		if err := types.ValidateMetaKey(key); err != nil {
+			return nil, fmt.Errorf("%w: %v", errBadRequest, err)
+		}
+		meta[key] = value
+	}
+	return meta, nil
+}
+
+// GET /issues/{id}
+func (s *server) getIssue(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	id := r.PathValue("id")
+	issue, err := s.store.GetIssue(ctx, id)
+	if err != nil {
+		return err
+	}
+	if issue == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("issue %s not found", id))
+		return nil
+	}
+	detail := IssueDetail{Issue: issue, Labels: []string{}, Dependencies: []*types.Dependency{}, Dependents: []string{}}
+	if labels, err := s.store.GetLabels(ctx, id); err != nil {
+		return err
+	} else if labels != nil {
+		detail.Labels = labels
+	}
+	if detail.Metadata, err = s.store.GetIssueMeta(ctx, id); err != nil {
+		return err
+	}
+	if deps, err := s.store.GetDependencyRecords(ctx, id); err != nil {
+		return err
+	} else if deps != nil {
+		detail.Dependencies = deps
+	}
+	dependents, err := s.store.GetDependents(ctx, id)
+	if err != nil {
+		return err
+	}
+	for _, dependent := range dependents {
+		detail.Dependents = append(detail.Dependents, dependent.ID)
+	}
+	if detail.ExecutionState, err = s.store.GetExecutionState(ctx, id); err != nil {
+		return err
+	}
+	return writeJSON(w, detail)
+}
+
+// GET /issues/{id}/events
+func (s *server) issueEvents(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	id := r.PathValue("id")
+	limit, err := limitParam(r.URL.Query().Get("limit"))
+	if err != nil {
+		return err
+	}
+	issue, err := s.store.GetIssue(ctx, id)
+	if err != nil {
+		return err
+	}
+	if issue == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("issue %s not found", id))
+		return nil
+	}
+	list, err := s.store.GetEvents(ctx, id, limit)
+	if err != nil {
+		return err
+	}
+	if list == nil {
+		list = []*types.Event{}
+	}
+	return writeJSON(w, list)
+}
+
+// GET /events: agent events, newest first
+func (s *server) agentEvents(w http.ResponseWriter, r *http.Request) error {
+	q := r.URL.Query()
+	filter := events.EventFilter{
+		IssueID:         q.Get("issue"),
+		ExecutorID:      q.Get("executor"),
+		Type:            events.EventType(q.Get("type")),
+		Severity:        events.EventSeverity(q.Get("severity")),
+		MessageContains: q.Get("q"),
+	}
+	var err error
+	if filter.Limit, err = limitParam(q.Get("limit")); err != nil {
+		return err
+	}
+	if v := q.Get("since"); v != "" {
+		if filter.AfterTime, err = sinceParam(v, time.Now()); err != nil {
+			return err
+		}
+	}
+	list, err := s.store.GetAgentEvents(r.Context(), filter)
+	if err != nil {
+		return err
+	}
+	if list == nil {
+		list = []*events.AgentEvent{}
+	}
+	return writeJSON(w, list)
+}
+
+// GET /instances: the running executors
+func (s *server) instances(w http.ResponseWriter, r *http.Request) error {
+	list, err := s.store.GetActiveInstances(r.Context())
+	if err != nil {
+		return err
+	}
+	if list == nil {
+		list = []*types.ExecutorInstance{}
+	}
+	return writeJSON(w, list)
+}
+
+// GET /stats
+func (s *server) stats(w http.ResponseWriter, r *http.Request) error {
+	stats, err := s.store.GetStatistics(r.Context())
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, stats)
+}
+
+// GET /openapi.json
+func (s *server) openAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(OpenAPISpec))
+}
+
+// limitParam parses a page size, DefaultLimit when empty
+func limitParam(v string) (int, error) {
+	if v == "" {
+		return DefaultLimit, nil
+	}
+	limit, err := strconv.Atoi(v)
+	if err != nil || limit < 1 || limit > MaxLimit {
+		return 0, fmt.Errorf("%w: invalid limit %q (1-%d)", errBadRequest, v, MaxLimit)
+	}
+	return limit, nil
+}
+
+// boolParam parses an optional boolean parameter
+func boolParam(name, v string) (bool, error) {
+	if v == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("%w: invalid %s %q", errBadRequest, name, v)
+	}
+	return b, nil
+}
+
+// sinceParam parses a lookback (a Go duration, or days as 7d) or an
+// RFC 3339 time
+func sinceParam(v string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	var d time.Duration
+	var err error
+	if days, ok := strings.CutSuffix(v, "d"); ok {
+		var n int
+		n, err = strconv.Atoi(days)
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		d, err = time.ParseDuration(v)
+	}
+	if err != nil || d < 0 {
+		return time.Time{}, fmt.Errorf("%w: invalid since %q (e.g. 2h, 7d or an RFC 3339 time)", errBadRequest, v)
+	}
+	return now.Add(-d), nil
+}
+
+// writeJSON writes v as the response body
+func writeJSON(w http.ResponseWriter, v interface{}) error {
+	body, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode response: %w", err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(append(body, '\n'))
+	return nil
+}
+
+// writeError writes a JSON error response
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(errorResponse{Error: message})
+}
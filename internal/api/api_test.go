@@ -0,0 +1,195 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage/storagetest"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// get requests path from the handler and decodes the JSON body into out
+func get(t *testing.T, h http.Handler, path string, header http.Header, out interface{}) int {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if out != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+			t.Fatalf("GET %s: invalid JSON %q: %v", path, rec.Body.String(), err)
+		}
+	}
+	return rec.Code
+}
+
+// seed creates two issues, vc-2 blocking vc-1, and an agent event
+func seed(t *testing.T) *storagetest.FakeStorage {
+	t.Helper()
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+	for _, title := range []string{"Parser crash", "Lexer cleanup"} {
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 1, IssueType: types.TypeBug}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.AddLabel(ctx, "vc-1", "parser", "test"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SetIssueMeta(ctx, "vc-1", "jira.key", json.RawMessage(`"PROJ-1"`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.AddDependency(ctx, &types.Dependency{IssueID: "vc-1", DependsOnID: "vc-2", Type: types.DepBlocks}, "test"); err != nil {
+		t.Fatal(err)
+	}
+	event := &events.AgentEvent{ID: "e1", Type: events.EventTypeError, Timestamp: time.Now(), IssueID: "vc-1", Severity: events.SeverityError, Message: "boom"}
+	if err := store.StoreAgentEvent(ctx, event); err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func TestIssueEndpoints(t *testing.T) {
+	h := NewHandler(seed(t), Options{})
+
+	var list IssueList
+	if code := get(t, h, "/issues?q=parser&limit=1", nil, &list); code != http.StatusOK {
+		t.Fatalf("GET /issues: status %d", code)
+	}
+	if list.Total != 1 || len(list.Issues) != 1 || list.Issues[0].ID != "vc-1" || list.Limit != 1 {
+		t.Errorf("GET /issues?q=parser: got %+v", list)
+	}
+
+	if code := get(t, h, "/issues?meta=jira.key=PROJ-1", nil, &list); code != http.StatusOK || list.Total != 1 || list.Issues[0].ID != "vc-1" {
+		t.Errorf("GET /issues?meta=jira.key=PROJ-1: status %d, got %+v", code, list)
+	}
+	if code := get(t, h, "/issues?status=closed", nil, &list); code != http.StatusOK || list.Total != 0 {
+		t.Errorf("GET /issues?status=closed: status %d, expected SYSTEM left out, got %+v", code, list)
+	}
+	if code := get(t, h, "/issues?status=closed&system=true", nil, &list); code != http.StatusOK || list.Total != 1 || list.Issues[0].ID != types.SystemIssueID {
+		t.Errorf("GET /issues?system=true: status %d, got %+v", code, list)
+	}
+
+	var detail IssueDetail
+	if code := get(t, h, "/issues/vc-1", nil, &detail); code != http.StatusOK {
+		t.Fatalf("GET /issues/vc-1: status %d", code)
+	}
+	if detail.Issue.ID != "vc-1" || len(detail.Labels) != 1 || len(detail.Dependencies) != 1 ||
+		string(detail.Metadata["jira.key"]) != `"PROJ-1"` ||
+		detail.Dependencies[0].DependsOnID != "vc-2" || detail.ExecutionState != nil {
+		t.Errorf("GET /issues/vc-1: got %+v", detail)
+	}
+	if code := get(t, h, "/issues/vc-2", nil, &detail); code != http.StatusOK || len(detail.Dependents) != 1 || detail.Dependents[0] != "vc-1" {
+		t.Errorf("GET /issues/vc-2: status %d, dependents %v", code, detail.Dependents)
+	}
+
+	var audit []*types.Event
+	if code := get(t, h, "/issues/vc-1/events", nil, &audit); code != http.StatusOK || len(audit) == 0 {
+		t.Errorf("GET /issues/vc-1/events: status %d, %d events", code, len(audit))
+	}
+
+	var apiErr errorResponse
+	if code := get(t, h, "/issues/vc-99", nil, &apiErr); code != http.StatusNotFound || !strings.Contains(apiErr.Error, "not found") {
+		t.Errorf("GET /issues/vc-99: status %d, %+v", code, apiErr)
+	}
+	for _, path := range []string{"/issues?status=done", "/issues?limit=0", "/issues?priority=9", "/issues?sort=color", "/issues?meta=flat=1", "/events?since=yesterday"} {
+		if code := get(t, h, path, nil, &apiErr); code != http.StatusBadRequest {
+			t.Errorf("GET %s: status %d, want 400", path, code)
+		}
+	}
+}
+
+func TestEventInstanceAndStatsEndpoints(t *testing.T) {
+	h := NewHandler(seed(t), Options{})
+
+	var agentEvents []*events.AgentEvent
+	if code := get(t, h, "/events?severity=error&since=1h", nil, &agentEvents); code != http.StatusOK || len(agentEvents) != 1 {
+		t.Errorf("GET /events: status %d, %d events", code, len(agentEvents))
+	}
+	var instances []*types.ExecutorInstance
+	if code := get(t, h, "/instances", nil, &instances); code != http.StatusOK || instances == nil {
+		t.Errorf("GET /instances: status %d, %v", code, instances)
+	}
+	var stats types.Statistics
+	if code := get(t, h, "/stats", nil, &stats); code != http.StatusOK || stats.TotalIssues != 2 {
+		t.Errorf("GET /stats: status %d, %+v", code, stats)
+	}
+}
+
+func TestReadOnlyMethods(t *testing.T) {
+	h := NewHandler(seed(t), Options{})
+	for _, method := range []string{http.MethodPost, http.MethodPut, http.MethodDelete} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(method, "/issues", nil))
+		if rec.Code != http.StatusMethodNotAllowed {
+			t.Errorf("%s /issues: status %d, want 405", method, rec.Code)
+		}
+	}
+}
+
+func TestToken(t *testing.T) {
+	h := NewHandler(seed(t), Options{Token: "s3cret"})
+	tests := []struct {
+		header http.Header
+		want   int
+	}{
+		{nil, http.StatusUnauthorized},
+		{http.Header{"Authorization": {"Bearer wrong"}}, http.StatusUnauthorized},
+		{http.Header{"Authorization": {"Bearer s3cret"}}, http.StatusOK},
+		{http.Header{"X-Vc-Token": {"s3cret"}}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		if code := get(t, h, "/stats", tt.header, nil); code != tt.want {
+			t.Errorf("GET /stats with %v: status %d, want %d", tt.header, code, tt.want)
+		}
+	}
+	if code := get(t, h, "/openapi.json", nil, nil); code != http.StatusOK {
+		t.Errorf("GET /openapi.json: status %d, want it open", code)
+	}
+}
+
+func TestCORS(t *testing.T) {
+	h := NewHandler(seed(t), Options{CORSOrigins: []string{"https://dash.example.com"}})
+
+	req := httptest.NewRequest(http.MethodOptions, "/issues", nil)
+	req.Header.Set("Origin", "https://dash.example.com")
+	req.Header.Set("Access-Control-Request-Method", "GET")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNoContent || rec.Header().Get("Access-Control-Allow-Origin") != "https://dash.example.com" ||
+		!strings.Contains(rec.Header().Get("Access-Control-Allow-Headers"), "Authorization") {
+		t.Errorf("Preflight: status %d, headers %v", rec.Code, rec.Header())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/stats", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("Expected no CORS headers for another origin, got %v", rec.Header())
+	}
+}
+
+func TestOpenAPISpecCoversRoutes(t *testing.T) {
+	var spec struct {
+		OpenAPI string                 `json:"openapi"`
+		Paths   map[string]interface{} `json:"paths"`
+	}
+	if err := json.Unmarshal([]byte(OpenAPISpec), &spec); err != nil {
+		t.Fatalf("OpenAPISpec is not valid JSON: %v", err)
+	}
+	for _, path := range []string{"/issues", "/issues/{id}", "/issues/{id}/events", "/events", "/instances", "/stats", "/openapi.json"} {
+		if spec.Paths[path] == nil {
+			t.Errorf("OpenAPISpec lacks %s", path)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package api
+
+// OpenAPISpec describes the API (OpenAPI 3.0), served at /openapi.json.
+// Keep it in step with NewHandler; TestOpenAPISpecCoversRoutes checks the
+// paths.
+const OpenAPISpec = `{
+  "openapi": "3.0.3",
+  "info": {
+    "title": "vc",
+    "version": "1",
+    "description": "Read-only view of a vc tracker: issues, their events, agent events, executor instances and statistics. Served by vc serve."
+  },
+  "security": [{"bearer": []}, {"header": []}, {}],
+  "components": {
+    "securitySchemes": {
+      "bearer": {"type": "http", "scheme": "bearer", "description": "The token given to vc serve"},
+      "header": {"type": "apiKey", "in": "header", "name": "X-VC-Token"}
+    },
+    "parameters": {
+      "limit": {"name": "limit", "in": "query", "schema": {"type": "integer", "minimum": 1, "maximum": 1000, "default": 100}},
+      "id": {"name": "id", "in": "path", "required": true, "schema": {"type": "string"}, "example": "vc-42"}
+    },
+    "responses": {
+      "Error": {
+        "description": "Bad parameters (400), missing token (401), unknown issue (404) or storage failure (500)",
+        "content": {"application/json": {"schema": {"type": "object", "properties": {"error": {"type": "string"}}}}}
+      }
+    },
+    "schemas": {
+      "Issue": {
+        "type": "object",
+        "properties": {
+          "id": {"type": "string"},
+          "title": {"type": "string"},
+          "description": {"type": "string"},
+          "design": {"type": "string"},
+          "acceptance_criteria": {"type": "string"},
+          "notes": {"type": "string"},
+          "status": {"type": "string", "enum": ["open", "in_progress", "blocked", "closed"]},
+          "priority": {"type": "integer", "minimum": 0, "maximum": 4},
+          "issue_type": {"type": "string", "enum": ["bug", "feature", "task", "epic", "chore"]},
+          "assignee": {"type": "string"},
+          "estimated_minutes": {"type": "integer", "nullable": true},
+          "created_at": {"type": "string", "format": "date-time"},
+          "updated_at": {"type": "string", "format": "date-time"},
+          "closed_at": {"type": "string", "format": "date-time", "nullable": true},
+          "project": {"type": "string"}
+        },
+        "additionalProperties": true
+      },
+      "Dependency": {
+        "type": "object",
+        "properties": {
+          "issue_id": {"type": "string"},
+          "depends_on_id": {"type": "string"},
+          "type": {"type": "string", "enum": ["blocks", "related", "parent-child", "discovered-from"]},
+          "created_at": {"type": "string", "format": "date-time"},
+          "created_by": {"type": "string"}
+        }
+      },
+      "ExecutionState": {
+        "type": "object",
+        "nullable": true,
+        "properties": {
+          "issue_id": {"type": "string"},
+          "executor_instance_id": {"type": "string"},
+          "state": {"type": "string"},
+          "checkpoint_data": {"type": "string"},
+          "claimed_at": {"type": "string", "format": "date-time"},
+          "started_at": {"type": "string", "format": "date-time"},
+          "updated_at": {"type": "string", "format": "date-time"},
+          "error_message": {"type": "string"}
+        }
+      },
+      "Event": {
+        "type": "object",
+        "properties": {
+          "id": {"type": "integer"},
+          "issue_id": {"type": "string"},
+          "event_type": {"type": "string"},
+          "actor": {"type": "string"},
+          "old_value": {"type": "string"},
+          "new_value": {"type": "string"},
+          "comment": {"type": "string"},
+          "created_at": {"type": "string", "format": "date-time"}
+        }
+      },
+      "AgentEvent": {
+        "type": "object",
+        "properties": {
+          "id": {"type": "string"},
+          "type": {"type": "string"},
+          "timestamp": {"type": "string", "format": "date-time"},
+          "issue_id": {"type": "string"},
+          "executor_id": {"type": "string"},
+          "agent_id": {"type": "string"},
+          "severity": {"type": "string", "enum": ["info", "warning", "error", "critical"]},
+          "message": {"type": "string"},
+          "data": {"type": "object", "additionalProperties": true}
+        }
+      },
+      "Instance": {
+        "type": "object",
+        "properties": {
+          "instance_id": {"type": "string"},
+          "hostname": {"type": "string"},
+          "pid": {"type": "integer"},
+          "status": {"type": "string"},
+          "started_at": {"type": "string", "format": "date-time"},
+          "last_heartbeat": {"type": "string", "format": "date-time"},
+          "version": {"type": "string"},
+          "metadata": {"type": "string"}
+        }
+      }
+    }
+  },
+  "paths": {
+    "/issues": {
+      "get": {
+        "summary": "Search issues",
+        "parameters": [
+          {"name": "q", "in": "query", "description": "Text in the title, description or ID", "schema": {"type": "string"}},
+          {"name": "status", "in": "query", "schema": {"type": "string"}},
+          {"name": "priority", "in": "query", "schema": {"type": "integer", "minimum": 0, "maximum": 4}},
+          {"name": "type", "in": "query", "schema": {"type": "string"}},
+          {"name": "assignee", "in": "query", "schema": {"type": "string"}},
+          {"name": "label", "in": "query", "description": "Comma-separated; issues must have every label", "schema": {"type": "string"}},
+          {"name": "project", "in": "query", "schema": {"type": "string"}},
+          {"name": "meta", "in": "query", "description": "key=value; issues must have the metadata value (JSON, or else a string). Repeatable.", "schema": {"type": "array", "items": {"type": "string"}}, "style": "form", "explode": true},
+          {"name": "sort", "in": "query", "schema": {"type": "string", "enum": ["id", "title", "status", "priority", "issue_type", "assignee", "created_at", "updated_at", "closed_at"]}},
+          {"name": "desc", "in": "query", "schema": {"type": "boolean"}},
+          {"name": "archived", "in": "query", "description": "Include archived issues", "schema": {"type": "boolean"}},
+          {"name": "system", "in": "query", "description": "Include the SYSTEM pseudo-issue", "schema": {"type": "boolean"}},
+          {"$ref": "#/components/parameters/limit"},
+          {"name": "offset", "in": "query", "schema": {"type": "integer", "minimum": 0}}
+        ],
+        "responses": {
+          "200": {
+            "description": "A page of matching issues",
+            "content": {"application/json": {"schema": {
+              "type": "object",
+              "properties": {
+                "issues": {"type": "array", "items": {"$ref": "#/components/schemas/Issue"}},
+                "total": {"type": "integer", "description": "Matches ignoring limit and offset"},
+                "limit": {"type": "integer"},
+                "offset": {"type": "integer"}
+              }
+            }}}
+          },
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/issues/{id}": {
+      "get": {
+        "summary": "Get an issue with its labels, metadata, dependencies and execution state",
+        "parameters": [{"$ref": "#/components/parameters/id"}],
+        "responses": {
+          "200": {
+            "description": "The issue",
+            "content": {"application/json": {"schema": {
+              "type": "object",
+              "properties": {
+                "issue": {"$ref": "#/components/schemas/Issue"},
+                "labels": {"type": "array", "items": {"type": "string"}},
+                "metadata": {"type": "object", "additionalProperties": {}, "description": "JSON values by namespaced key"},
+                "dependencies": {"type": "array", "items": {"$ref": "#/components/schemas/Dependency"}},
+                "dependents": {"type": "array", "items": {"type": "string"}, "description": "IDs of the issues depending on this one"},
+                "execution_state": {"$ref": "#/components/schemas/ExecutionState"}
+              }
+            }}}
+          },
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/issues/{id}/events": {
+      "get": {
+        "summary": "The issue's audit trail, newest first",
+        "parameters": [{"$ref": "#/components/parameters/id"}, {"$ref": "#/components/parameters/limit"}],
+        "responses": {
+          "200": {"description": "Events", "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Event"}}}}},
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/events": {
+      "get": {
+        "summary": "Recent agent events, newest first",
+        "parameters": [
+          {"name": "issue", "in": "query", "schema": {"type": "string"}},
+          {"name": "executor", "in": "query", "schema": {"type": "string"}},
+          {"name": "type", "in": "query", "schema": {"type": "string"}},
+          {"name": "severity", "in": "query", "schema": {"type": "string"}},
+          {"name": "q", "in": "query", "description": "Text in the message or data", "schema": {"type": "string"}},
+          {"name": "since", "in": "query", "description": "A lookback such as 2h or 7d, or an RFC 3339 time", "schema": {"type": "string"}},
+          {"$ref": "#/components/parameters/limit"}
+        ],
+        "responses": {
+          "200": {"description": "Agent events", "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/AgentEvent"}}}}},
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/instances": {
+      "get": {
+        "summary": "Running executor instances",
+        "responses": {
+          "200": {"description": "Instances", "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Instance"}}}}},
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/stats": {
+      "get": {
+        "summary": "Issue counts and lead time",
+        "responses": {
+          "200": {"description": "Statistics", "content": {"application/json": {"schema": {"type": "object", "additionalProperties": true}}}},
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/openapi.json": {
+      "get": {
+        "summary": "This description",
+        "security": [{}],
+        "responses": {"200": {"description": "OpenAPI 3.0 document", "content": {"application/json": {}}}}
+      }
+    }
+  }
+}
+`
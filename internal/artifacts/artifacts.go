@@ -0,0 +1,211 @@
+// Package artifacts keeps what an execution attempt produced after its
+// sandbox is gone: the diff of the sandbox branch against its base, the full
+// output of each quality gate and the agent's summary of its work, and the
+// transcript of everything the agent printed (see Transcript).
+//
+// Artifacts live under <root>/<issue>/<attempt>/, one file each, capped in
+// size (transcripts are compressed instead). The executor records their paths in the attempt's execution history
+// row, and its cleanup loop sweeps old ones away (see Sweep). The full text
+// of AI comments capped by CommentLimit lives under <root>/<issue>/comments/.
+package artifacts
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Defaults for the executor's artifact settings
+const (
+	DefaultMaxFileSize  int64 = 1 << 20   // 1 MiB per file
+	DefaultMaxTotalSize int64 = 500 << 20 // 500 MiB under the root
+	DefaultMaxAge             = 30 * 24 * time.Hour
+)
+
+// DefaultDir is where artifacts of the database at dbPath go: an
+// artifacts directory next to it (.beads/artifacts)
+func DefaultDir(dbPath string) string {
+	return filepath.Join(filepath.Dir(dbPath), "artifacts")
+}
+
+// File is one artifact to save
+type File struct {
+	Name    string
+	Content string
+	// KeepTail keeps the end of content over the size cap rather than its
+	// start; logs put their failures last
+	KeepTail bool
+}
+
+// Dir returns the directory of an attempt's artifacts
+func Dir(root, issueID string, attempt int) string {
+	return filepath.Join(root, issueID, strconv.Itoa(attempt))
+}
+
+// Save writes files to the attempt's directory and returns their paths.
+// Files over maxFileSize bytes (0 = no cap) are truncated with a note
+// saying so, and empty files are skipped.
+func Save(root, issueID string, attempt int, files []File, maxFileSize int64) ([]string, error) {
+	if issueID == "" || filepath.Base(issueID) != issueID || issueID == "." || issueID == ".." {
+		return nil, fmt.Errorf("invalid issue ID %q for an artifact directory", issueID)
+	}
+	if attempt < 1 {
+		return nil, fmt.Errorf("attempt must be positive (got %d)", attempt)
+	}
+	dir := Dir(root, issueID, attempt)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create artifact directory: %w", err)
+	}
+	var paths []string
+	for _, file := range files {
+		if file.Content == "" {
+			continue
+		}
+		if file.Name == "" || filepath.Base(file.Name) != file.Name {
+			return paths, fmt.Errorf("invalid artifact name %q", file.Name)
+		}
+		path := filepath.Join(dir, file.Name)
+		if err := os.WriteFile(path, []byte(capContent(file, maxFileSize)), 0644); err != nil {
+			return paths, fmt.Errorf("failed to write artifact %s: %w", file.Name, err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// capContent truncates the file's content to max bytes, noting what it cut
+func capContent(file File, max int64) string {
+	size := int64(len(file.Content))
+	if max <= 0 || size <= max {
+		return file.Content
+	}
+	if file.KeepTail {
+		note := fmt.Sprintf("[truncated: the first %d of %d bytes were dropped]\n", size-max, size)
+		return note + file.Content[size-max:]
+	}
+	note := fmt.Sprintf("\n[truncated: the last %d of %d bytes were dropped]\n", size-max, size)
+	return file.Content[:max] + note
+}
+
+// Remove deletes the artifact files at paths, then their attempt and issue
+// directories if that left them empty. Files already gone are skipped. It
+// returns how many files it removed.
+func Remove(paths []string) (int, error) {
+	removed := 0
+	for _, path := range paths {
+		err := os.Remove(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return removed, fmt.Errorf("failed to remove artifact %s: %w", path, err)
+		}
+		removed++
+		// Only succeed once the directories are empty
+		attemptDir := filepath.Dir(path)
+		if os.Remove(attemptDir) == nil {
+			_ = os.Remove(filepath.Dir(attemptDir))
+		}
+	}
+	return removed, nil
+}
+
+// Policy bounds how much Sweep keeps
+type Policy struct {
+	MaxAge       time.Duration // Remove attempts older than this (0 = no age limit)
+	MaxTotalSize int64         // Then remove the oldest until the rest fit (0 = no size limit)
+}
+
+// attemptDir is one <issue>/<attempt> directory found by Sweep
+type attemptDir struct {
+	path    string
+	modTime time.Time
+	size    int64
+}
+
+// Sweep removes attempt directories under root that the policy no longer
+// keeps, oldest first, along with issue directories left empty. It returns
+// how many attempts it removed; a missing root has none.
+func Sweep(root string, policy Policy, now time.Time) (int, error) {
+	attempts, err := scan(root)
+	if err != nil {
+		return 0, err
+	}
+	sort.Slice(attempts, func(i, j int) bool { return attempts[i].modTime.Before(attempts[j].modTime) })
+
+	var total int64
+	for _, a := range attempts {
+		total += a.size
+	}
+	removed := 0
+	for _, a := range attempts {
+		expired := policy.MaxAge > 0 && now.Sub(a.modTime) > policy.MaxAge
+		oversize := policy.MaxTotalSize > 0 && total > policy.MaxTotalSize
+		if !expired && !oversize {
+			continue
+		}
+		if err := os.RemoveAll(a.path); err != nil {
+			return removed, fmt.Errorf("failed to remove artifacts %s: %w", a.path, err)
+		}
+		total -= a.size
+		removed++
+		// Drops the issue directory once its last attempt is gone
+		_ = os.Remove(filepath.Dir(a.path))
+	}
+	return removed, nil
+}
+
+// scan lists the attempt directories under root with their sizes. An
+// attempt's age is that of its newest file.
+func scan(root string) ([]attemptDir, error) {
+	issues, err := os.ReadDir(root)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read artifact directory: %w", err)
+	}
+	var attempts []attemptDir
+	for _, issue := range issues {
+		if !issue.IsDir() {
+			continue
+		}
+		issueDir := filepath.Join(root, issue.Name())
+		entries, err := os.ReadDir(issueDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read artifact directory: %w", err)
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+			a := attemptDir{path: filepath.Join(issueDir, entry.Name())}
+			if info, err := entry.Info(); err == nil {
+				a.modTime = info.ModTime()
+			}
+			err := filepath.WalkDir(a.path, func(_ string, d os.DirEntry, err error) error {
+				if err != nil || d.IsDir() {
+					return err
+				}
+				info, err := d.Info()
+				if err != nil {
+					return err
+				}
+				a.size += info.Size()
+				if info.ModTime().After(a.modTime) {
+					a.modTime = info.ModTime()
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to size artifacts %s: %w", a.path, err)
+			}
+			attempts = append(attempts, a)
+		}
+	}
+	return attempts, nil
+}
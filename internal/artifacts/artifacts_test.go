@@ -0,0 +1,122 @@
+package artifacts
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSave(t *testing.T) {
+	root := t.TempDir()
+	paths, err := Save(root, "vc-7", 2, []File{
+		{Name: "diff.patch", Content: "0123456789"},
+		{Name: "gate-test.log", Content: "ok\nFAIL: TestX\n", KeepTail: true},
+		{Name: "summary.md", Content: ""},
+	}, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 2 || paths[0] != filepath.Join(root, "vc-7", "2", "diff.patch") {
+		t.Fatalf("Expected the diff and the log saved, got %v", paths)
+	}
+	diff, _ := os.ReadFile(paths[0])
+	if !strings.HasPrefix(string(diff), "01234567\n[truncated: the last 2 of 10 bytes") {
+		t.Errorf("Expected the diff's start kept, got %q", diff)
+	}
+	log, _ := os.ReadFile(paths[1])
+	if !strings.HasSuffix(string(log), "\n: TestX\n") || !strings.HasPrefix(string(log), "[truncated: the first 7 of 15 bytes") {
+		t.Errorf("Expected the log's end kept, got %q", log)
+	}
+
+	for _, id := range []string{"", "..", "a/b"} {
+		if _, err := Save(root, id, 1, nil, 0); err == nil {
+			t.Errorf("Expected issue ID %q rejected", id)
+		}
+	}
+	if _, err := Save(root, "vc-7", 0, nil, 0); err == nil {
+		t.Error("Expected attempt 0 rejected")
+	}
+	if _, err := Save(root, "vc-7", 1, []File{{Name: "../x", Content: "x"}}, 0); err == nil {
+		t.Error("Expected a name outside the attempt directory rejected")
+	}
+}
+
+func TestSweep(t *testing.T) {
+	root := t.TempDir()
+	now := time.Now()
+	save := func(issue string, attempt int, size int, age time.Duration) {
+		t.Helper()
+		paths, err := Save(root, issue, attempt, []File{{Name: "diff.patch", Content: strings.Repeat("x", size)}}, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		at := now.Add(-age)
+		for _, path := range append(paths, filepath.Dir(paths[0])) {
+			if err := os.Chtimes(path, at, at); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	save("vc-1", 1, 100, 40*24*time.Hour)
+	save("vc-2", 1, 100, 3*time.Hour)
+	save("vc-2", 2, 100, 2*time.Hour)
+	save("vc-3", 1, 100, time.Hour)
+
+	removed, err := Sweep(root, Policy{MaxAge: 30 * 24 * time.Hour, MaxTotalSize: 250}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 2 {
+		t.Errorf("Expected the expired and the oldest attempt removed, got %d", removed)
+	}
+	if _, err := os.Stat(filepath.Join(root, "vc-1")); !os.IsNotExist(err) {
+		t.Errorf("Expected the emptied issue directory removed (%v)", err)
+	}
+	if _, err := os.Stat(Dir(root, "vc-2", 1)); !os.IsNotExist(err) {
+		t.Errorf("Expected the oldest attempt over the size cap removed (%v)", err)
+	}
+	for _, dir := range []string{Dir(root, "vc-2", 2), Dir(root, "vc-3", 1)} {
+		if _, err := os.Stat(dir); err != nil {
+			t.Errorf("Expected %s kept: %v", dir, err)
+		}
+	}
+
+	if removed, err := Sweep(filepath.Join(root, "missing"), Policy{MaxAge: time.Hour}, now); err != nil || removed != 0 {
+		t.Errorf("Expected nothing to sweep without a root, got %d (%v)", removed, err)
+	}
+}
+
+func TestRemove(t *testing.T) {
+	root := t.TempDir()
+	first, err := Save(root, "vc-1", 1, []File{{Name: "diff.patch", Content: "x"}, {Name: "summary.md", Content: "y"}}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := Save(root, "vc-1", 2, []File{{Name: "diff.patch", Content: "z"}}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	removed, err := Remove(append(first, filepath.Join(root, "vc-1", "1", "gone.log")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 2 {
+		t.Errorf("Expected 2 files removed, got %d", removed)
+	}
+	if _, err := os.Stat(Dir(root, "vc-1", 1)); !os.IsNotExist(err) {
+		t.Errorf("Expected the emptied attempt directory removed (%v)", err)
+	}
+	if _, err := os.Stat(second[0]); err != nil {
+		t.Errorf("Expected the other attempt kept: %v", err)
+	}
+
+	if _, err := Remove(second); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "vc-1")); !os.IsNotExist(err) {
+		t.Errorf("Expected the emptied issue directory removed (%v)", err)
+	}
+}
@@ -0,0 +1,85 @@
+package artifacts
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// DefaultMaxCommentSize is the default cap of an AI-authored comment
+const DefaultMaxCommentSize = 16 << 10 // 16 KiB
+
+// commentsDir holds an issue's full comment texts next to its attempts;
+// Sweep treats it like an attempt directory
+const commentsDir = "comments"
+
+// truncatedMarker ends a capped comment. The path is missing when the full
+// text couldn't be saved.
+var truncatedMarker = regexp.MustCompile(`\n\[truncated: \d+ of \d+ bytes shown(?:, full text at (.+))?\]$`)
+
+// CommentLimit caps the size of AI-authored comments (assessments, analyses,
+// watchdog escalations), so one huge response can't make 'vc show'
+// unreadable or bloat the events table. The zero value caps nothing.
+type CommentLimit struct {
+	Root    string // Artifacts root the full text of capped comments is saved under ("" = don't save it)
+	MaxSize int    // Size cap in bytes (0 = no cap)
+}
+
+// Cap returns comment as is if it fits, else its start with a marker
+// saying how much was kept and where the full text was saved. A failure to
+// save is logged and leaves the path out of the marker.
+func (l CommentLimit) Cap(issueID, comment string) string {
+	if l.MaxSize <= 0 || len(comment) <= l.MaxSize {
+		return comment
+	}
+	kept := comment[:l.MaxSize]
+	for len(kept) > 0 && !utf8.ValidString(kept) {
+		kept = kept[:len(kept)-1]
+	}
+
+	location := ""
+	if l.Root != "" {
+		path, err := saveComment(l.Root, issueID, comment, time.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to save the full comment on %s: %v\n", issueID, err)
+		} else {
+			location = ", full text at " + path
+		}
+	}
+	return fmt.Sprintf("%s\n[truncated: %d of %d bytes shown%s]", kept, len(kept), len(comment), location)
+}
+
+// saveComment writes the full text of a comment to the issue's comments
+// directory and returns its path
+func saveComment(root, issueID, comment string, now time.Time) (string, error) {
+	if issueID == "" || filepath.Base(issueID) != issueID || issueID == "." || issueID == ".." {
+		return "", fmt.Errorf("invalid issue ID %q for an artifact directory", issueID)
+	}
+	dir := filepath.Join(root, issueID, commentsDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create artifact directory: %w", err)
+	}
+	path := filepath.Join(dir, strconv.FormatInt(now.UnixNano(), 10)+".md")
+	if err := os.WriteFile(path, []byte(comment), 0644); err != nil {
+		return "", fmt.Errorf("failed to write comment artifact: %w", err)
+	}
+	return path, nil
+}
+
+// TruncatedComment reports whether comment was capped by CommentLimit.Cap,
+// and returns the comment without its marker and the path of the full text
+// ("" if it wasn't saved).
+func TruncatedComment(comment string) (text, path string, truncated bool) {
+	match := truncatedMarker.FindStringSubmatchIndex(comment)
+	if match == nil {
+		return comment, "", false
+	}
+	if match[2] >= 0 {
+		path = comment[match[2]:match[3]]
+	}
+	return comment[:match[0]], path, true
+}
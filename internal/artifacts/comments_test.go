@@ -0,0 +1,49 @@
+package artifacts
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCommentLimitCap(t *testing.T) {
+	root := t.TempDir()
+	limit := CommentLimit{Root: root, MaxSize: 100}
+
+	if got := limit.Cap("vc-1", "short"); got != "short" {
+		t.Errorf("Expected a short comment kept as is, got %q", got)
+	}
+	if got := (CommentLimit{}).Cap("vc-1", strings.Repeat("x", 1<<20)); len(got) != 1<<20 {
+		t.Errorf("Expected the zero limit to cap nothing, got %d bytes", len(got))
+	}
+
+	full := strings.Repeat("é", 80) // 160 bytes; the cap falls mid-rune
+	capped := limit.Cap("vc-1", full)
+	text, path, truncated := TruncatedComment(capped)
+	if !truncated || text != strings.Repeat("é", 50) {
+		t.Fatalf("Expected the first 50 runes kept, got %q", capped)
+	}
+	if !strings.Contains(capped, "[truncated: 100 of 160 bytes shown, full text at ") {
+		t.Errorf("Unexpected marker: %q", capped)
+	}
+	if dir := filepath.Join(root, "vc-1", "comments"); filepath.Dir(path) != dir {
+		t.Errorf("Expected the full text under %s, got %s", dir, path)
+	}
+	if saved, err := os.ReadFile(path); err != nil || string(saved) != full {
+		t.Errorf("Expected the full text saved, got %q (%v)", saved, err)
+	}
+
+	// Without a root the comment is only truncated
+	capped = CommentLimit{MaxSize: 10}.Cap("vc-1", strings.Repeat("y", 20))
+	if text, path, truncated := TruncatedComment(capped); !truncated || path != "" || text != strings.Repeat("y", 10) {
+		t.Errorf("Expected a truncated comment without a path, got %q", capped)
+	}
+}
+
+func TestTruncatedCommentPlain(t *testing.T) {
+	comment := "Mentions [truncated: 1 of 2 bytes shown] mid-text\nand more"
+	if text, path, truncated := TruncatedComment(comment); truncated || path != "" || text != comment {
+		t.Errorf("Expected an uncapped comment, got (%q, %q, %v)", text, path, truncated)
+	}
+}
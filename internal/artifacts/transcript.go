@@ -0,0 +1,161 @@
+package artifacts
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// TranscriptName is the file an attempt's agent output is written to, line
+// by line as the agent prints it. Once the agent exits, transcripts over
+// TranscriptCompressSize are gzipped to TranscriptName + ".gz".
+const TranscriptName = "transcript.log"
+
+// TranscriptCompressSize is the size above which a finished transcript is
+// compressed
+const TranscriptCompressSize int64 = 256 << 10
+
+// StderrPrefix marks the lines the agent wrote to stderr
+const StderrPrefix = "[stderr] "
+
+// TranscriptPath returns where an attempt's transcript is written
+func TranscriptPath(root, issueID string, attempt int) string {
+	return filepath.Join(Dir(root, issueID, attempt), TranscriptName)
+}
+
+// IsTranscript reports whether an artifact path is a transcript, compressed
+// or not
+func IsTranscript(path string) bool {
+	name := filepath.Base(path)
+	return name == TranscriptName || name == TranscriptName+".gz"
+}
+
+// Transcript tees an agent's output to a file. Unlike the other artifacts
+// it isn't capped; it is compressed instead when closed. It is safe for
+// concurrent use.
+type Transcript struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+	w    *bufio.Writer
+	err  error // First write error; later writes are dropped
+}
+
+// CreateTranscript creates the transcript file at path, with its directory
+func CreateTranscript(path string) (*Transcript, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create transcript directory: %w", err)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transcript: %w", err)
+	}
+	return &Transcript{path: path, file: file, w: bufio.NewWriter(file)}, nil
+}
+
+// WriteLine appends a line and flushes it, so 'vc transcript --follow'
+// sees it right away
+func (t *Transcript) WriteLine(line string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.err != nil || t.file == nil {
+		return
+	}
+	if _, err := t.w.WriteString(line + "\n"); err != nil {
+		t.err = err
+		return
+	}
+	t.err = t.w.Flush()
+}
+
+// Close closes the file, compresses it if it's over TranscriptCompressSize
+// and returns its final path. Closing twice returns the same path.
+func (t *Transcript) Close() (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.file == nil {
+		return t.path, t.err
+	}
+	err := errors.Join(t.err, t.w.Flush(), t.file.Close())
+	t.file = nil
+	if err != nil {
+		t.err = fmt.Errorf("failed to write transcript %s: %w", t.path, err)
+		return t.path, t.err
+	}
+	info, err := os.Stat(t.path)
+	if err != nil || info.Size() <= TranscriptCompressSize {
+		return t.path, nil
+	}
+	compressed, err := compressFile(t.path)
+	if err != nil {
+		// The uncompressed transcript is still there
+		return t.path, err
+	}
+	t.path = compressed
+	return t.path, nil
+}
+
+// compressFile gzips path to path.gz and removes path
+func compressFile(path string) (string, error) {
+	in, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer in.Close()
+	gzPath := path + ".gz"
+	out, err := os.Create(gzPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to compress transcript: %w", err)
+	}
+	zw := gzip.NewWriter(out)
+	_, err = io.Copy(zw, in)
+	if err = errors.Join(err, zw.Close(), out.Close()); err != nil {
+		_ = os.Remove(gzPath)
+		return "", fmt.Errorf("failed to compress transcript: %w", err)
+	}
+	return gzPath, os.Remove(path)
+}
+
+// OpenTranscript opens a transcript for reading, decompressing gzipped ones
+func OpenTranscript(path string) (io.ReadCloser, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if filepath.Ext(path) != ".gz" {
+		return file, nil
+	}
+	zr, err := gzip.NewReader(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to read compressed transcript %s: %w", path, err)
+	}
+	return &gzipFile{Reader: zr, file: file}, nil
+}
+
+// gzipFile closes a gzip reader and the file under it
+type gzipFile struct {
+	*gzip.Reader
+	file *os.File
+}
+
+func (g *gzipFile) Close() error {
+	return errors.Join(g.Reader.Close(), g.file.Close())
+}
+
+// FindTranscript returns the transcript of an attempt under root,
+// compressed or not, or "" if it has none
+func FindTranscript(root, issueID string, attempt int) string {
+	path := TranscriptPath(root, issueID, attempt)
+	for _, candidate := range []string{path, path + ".gz"} {
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate
+		}
+	}
+	return ""
+}
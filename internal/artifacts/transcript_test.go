@@ -0,0 +1,77 @@
+package artifacts
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTranscript(t *testing.T) {
+	root := t.TempDir()
+	path := TranscriptPath(root, "vc-7", 1)
+	tr, err := CreateTranscript(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr.WriteLine("hello")
+	tr.WriteLine(StderrPrefix + "warning")
+	if got, _ := os.ReadFile(path); string(got) != "hello\n[stderr] warning\n" {
+		t.Errorf("Expected lines on disk before Close, got %q", got)
+	}
+	final, err := tr.Close()
+	if err != nil || final != path {
+		t.Fatalf("Close() = %q, %v, want the uncompressed path", final, err)
+	}
+	if again, err := tr.Close(); again != path || err != nil {
+		t.Errorf("A second Close() = %q, %v", again, err)
+	}
+	tr.WriteLine("after close") // Dropped
+	if found := FindTranscript(root, "vc-7", 1); found != path {
+		t.Errorf("FindTranscript() = %q, want %q", found, path)
+	}
+	if found := FindTranscript(root, "vc-7", 2); found != "" {
+		t.Errorf("Expected no transcript for attempt 2, got %q", found)
+	}
+}
+
+func TestTranscriptCompressed(t *testing.T) {
+	root := t.TempDir()
+	path := TranscriptPath(root, "vc-7", 3)
+	tr, err := CreateTranscript(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	line := strings.Repeat("x", 1023)
+	n := int(TranscriptCompressSize/1024) + 1
+	for i := 0; i < n; i++ {
+		tr.WriteLine(line)
+	}
+	final, err := tr.Close()
+	if err != nil || final != path+".gz" || !IsTranscript(final) {
+		t.Fatalf("Close() = %q, %v, want the compressed path", final, err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Expected the uncompressed transcript removed, got %v", err)
+	}
+	if found := FindTranscript(root, "vc-7", 3); found != final {
+		t.Errorf("FindTranscript() = %q, want %q", found, final)
+	}
+
+	r, err := OpenTranscript(final)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	content, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(content) != n*1024 || !strings.HasPrefix(string(content), line+"\n") {
+		t.Errorf("Expected %d bytes of the original lines back, got %d", n*1024, len(content))
+	}
+	if IsTranscript(filepath.Join(root, "vc-7", "3", "diff.patch")) {
+		t.Error("A diff is not a transcript")
+	}
+}
@@ -49,10 +49,32 @@ type EventRetentionConfig struct {
 	// Default: "oldest_non_critical"
 	CleanupStrategy string
 
-	// CleanupVacuum controls whether to run VACUUM after cleanup
-	// VACUUM reclaims disk space but can lock the database
-	// Default: false
-	CleanupVacuum bool
+	// VacuumFreeFraction is the fraction of the database that must be free
+	// pages (PRAGMA freelist_count / page_count) before cleanup vacuums it.
+	// VACUUM rewrites the whole file and blocks writers while it runs, so it
+	// is only worth it once a good share of the file is reclaimable.
+	// Databases with auto_vacuum=INCREMENTAL use the cheap incremental_vacuum instead.
+	// Set to 0 to never vacuum automatically
+	// Default: 0.25, Range: 0 or 0.01-0.9
+	VacuumFreeFraction float64
+
+	// VacuumMinIntervalHours is the minimum time between two full VACUUMs
+	// Set to 0 for no minimum
+	// Default: 168 (1 week), Range: 0-8760 (1 year)
+	VacuumMinIntervalHours int
+
+	// HistoryRetentionDays is how long execution attempts (and their
+	// artifacts) are kept at least (in days). Older attempts beyond the
+	// newest HistoryKeepPerIssue of their issue are deleted, except those of
+	// blocked or escalated issues
+	// Set to 0 to keep execution history forever
+	// Default: 90, Range: 0-3650
+	HistoryRetentionDays int
+
+	// HistoryKeepPerIssue is how many of each issue's newest attempts are
+	// kept regardless of their age
+	// Default: 10, Range: 0-1000
+	HistoryKeepPerIssue int
 }
 
 // DefaultEventRetentionConfig returns the default event retention configuration
@@ -63,18 +85,22 @@ type EventRetentionConfig struct {
 // - Prevent runaway issues (1000 events per issue max)
 // - Cap total database size (100k events = ~50 MB)
 // - Run cleanup daily during off-hours
-// - Use non-blocking cleanup (no VACUUM by default)
+// - VACUUM only when a quarter of the file is free, at most weekly
+// - Keep 90 days of execution history, and each issue's last 10 attempts
 func DefaultEventRetentionConfig() EventRetentionConfig {
 	return EventRetentionConfig{
-		RetentionDays:         30,
-		RetentionCriticalDays: 90,
-		PerIssueLimitEvents:   1000,
-		GlobalLimitEvents:     100000,
-		CleanupIntervalHours:  24,
-		CleanupBatchSize:      1000,
-		CleanupEnabled:        true,
-		CleanupStrategy:       "oldest_non_critical",
-		CleanupVacuum:         false,
+		RetentionDays:          30,
+		RetentionCriticalDays:  90,
+		PerIssueLimitEvents:    1000,
+		GlobalLimitEvents:      100000,
+		CleanupIntervalHours:   24,
+		CleanupBatchSize:       1000,
+		CleanupEnabled:         true,
+		CleanupStrategy:        "oldest_non_critical",
+		VacuumFreeFraction:     0.25,
+		VacuumMinIntervalHours: 168,
+		HistoryRetentionDays:   90,
+		HistoryKeepPerIssue:    10,
 	}
 }
 
@@ -145,6 +171,26 @@ func (c EventRetentionConfig) Validate() error {
 			c.CleanupStrategy)
 	}
 
+	// Validate vacuum policy
+	if c.VacuumFreeFraction != 0 && (c.VacuumFreeFraction < 0.01 || c.VacuumFreeFraction > 0.9) {
+		return fmt.Errorf("vacuum_free_fraction must be 0 (never) or between 0.01 and 0.9 (got %g)",
+			c.VacuumFreeFraction)
+	}
+	if c.VacuumMinIntervalHours < 0 || c.VacuumMinIntervalHours > 8760 {
+		return fmt.Errorf("vacuum_min_interval_hours must be between 0 and 8760 (got %d)",
+			c.VacuumMinIntervalHours)
+	}
+
+	// Validate execution history retention (0 days = keep forever)
+	if c.HistoryRetentionDays < 0 || c.HistoryRetentionDays > 3650 {
+		return fmt.Errorf("history_retention_days must be between 0 and 3650 (got %d)",
+			c.HistoryRetentionDays)
+	}
+	if c.HistoryKeepPerIssue < 0 || c.HistoryKeepPerIssue > 1000 {
+		return fmt.Errorf("history_keep_per_issue must be between 0 and 1000 (got %d)",
+			c.HistoryKeepPerIssue)
+	}
+
 	return nil
 }
 
@@ -153,10 +199,12 @@ func (c EventRetentionConfig) String() string {
 	return fmt.Sprintf(
 		"EventRetentionConfig{RetentionDays: %d, RetentionCriticalDays: %d, "+
 			"PerIssueLimit: %d, GlobalLimit: %d, CleanupInterval: %dh, "+
-			"BatchSize: %d, Enabled: %t, Strategy: %s, Vacuum: %t}",
+			"BatchSize: %d, Enabled: %t, Strategy: %s, VacuumFreeFraction: %g, VacuumMinInterval: %dh, "+
+			"HistoryRetentionDays: %d, HistoryKeepPerIssue: %d}",
 		c.RetentionDays, c.RetentionCriticalDays, c.PerIssueLimitEvents,
 		c.GlobalLimitEvents, c.CleanupIntervalHours, c.CleanupBatchSize,
-		c.CleanupEnabled, c.CleanupStrategy, c.CleanupVacuum,
+		c.CleanupEnabled, c.CleanupStrategy, c.VacuumFreeFraction, c.VacuumMinIntervalHours,
+		c.HistoryRetentionDays, c.HistoryKeepPerIssue,
 	)
 }
 
@@ -172,7 +220,12 @@ func (c EventRetentionConfig) String() string {
 //   - VC_EVENT_CLEANUP_BATCH_SIZE: Events to delete per transaction (default: 1000)
 //   - VC_EVENT_CLEANUP_ENABLED: Enable automatic cleanup (default: true)
 //   - VC_EVENT_CLEANUP_STRATEGY: Which events to delete first (default: oldest_non_critical)
-//   - VC_EVENT_CLEANUP_VACUUM: Run VACUUM after cleanup (default: false)
+//   - VC_EVENT_VACUUM_FREE_FRACTION: Free-page fraction that triggers VACUUM, 0 for never (default: 0.25)
+//   - VC_EVENT_VACUUM_MIN_INTERVAL_HOURS: Minimum hours between VACUUMs (default: 168)
+//   - VC_EVENT_CLEANUP_VACUUM: Legacy switch; false disables automatic VACUUM
+//     (same as VC_EVENT_VACUUM_FREE_FRACTION=0)
+//   - VC_HISTORY_RETENTION_DAYS: Days of execution history to keep, 0 for forever (default: 90)
+//   - VC_HISTORY_KEEP_PER_ISSUE: Newest attempts kept per issue regardless of age (default: 10)
 //
 // Returns an error if any environment variable has an invalid value.
 func EventRetentionConfigFromEnv() (EventRetentionConfig, error) {
@@ -201,7 +254,23 @@ func EventRetentionConfigFromEnv() (EventRetentionConfig, error) {
 		return cfg, err
 	}
 	parseEnvString("VC_EVENT_CLEANUP_STRATEGY", &cfg.CleanupStrategy)
-	if err := parseEnvBool("VC_EVENT_CLEANUP_VACUUM", &cfg.CleanupVacuum); err != nil {
+	vacuum := true
+	if err := parseEnvBool("VC_EVENT_CLEANUP_VACUUM", &vacuum); err != nil {
+		return cfg, err
+	}
+	if !vacuum {
+		cfg.VacuumFreeFraction = 0
+	}
+	if err := parseEnvFloat("VC_EVENT_VACUUM_FREE_FRACTION", &cfg.VacuumFreeFraction); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvInt("VC_EVENT_VACUUM_MIN_INTERVAL_HOURS", &cfg.VacuumMinIntervalHours); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvInt("VC_HISTORY_RETENTION_DAYS", &cfg.HistoryRetentionDays); err != nil {
+		return cfg, err
+	}
+	if err := parseEnvInt("VC_HISTORY_KEEP_PER_ISSUE", &cfg.HistoryKeepPerIssue); err != nil {
 		return cfg, err
 	}
 
@@ -227,6 +296,20 @@ func parseEnvInt(key string, dest *int) error {
 	return nil
 }
 
+// parseEnvFloat parses a float64 from an environment variable
+func parseEnvFloat(key string, dest *float64) error {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil // Use default
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+	*dest = parsed
+	return nil
+}
+
 // parseEnvBool parses a bool from an environment variable
 func parseEnvBool(key string, dest *bool) error {
 	value := os.Getenv(key)
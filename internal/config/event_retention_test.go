@@ -42,23 +42,35 @@ func TestEventRetentionConfigFromEnv(t *testing.T) {
 				if cfg.CleanupStrategy != defaults.CleanupStrategy {
 					t.Errorf("CleanupStrategy = %v, want %v", cfg.CleanupStrategy, defaults.CleanupStrategy)
 				}
-				if cfg.CleanupVacuum != defaults.CleanupVacuum {
-					t.Errorf("CleanupVacuum = %v, want %v", cfg.CleanupVacuum, defaults.CleanupVacuum)
+				if cfg.VacuumFreeFraction != defaults.VacuumFreeFraction {
+					t.Errorf("VacuumFreeFraction = %v, want %v", cfg.VacuumFreeFraction, defaults.VacuumFreeFraction)
+				}
+				if cfg.VacuumMinIntervalHours != defaults.VacuumMinIntervalHours {
+					t.Errorf("VacuumMinIntervalHours = %v, want %v", cfg.VacuumMinIntervalHours, defaults.VacuumMinIntervalHours)
+				}
+				if cfg.HistoryRetentionDays != defaults.HistoryRetentionDays {
+					t.Errorf("HistoryRetentionDays = %v, want %v", cfg.HistoryRetentionDays, defaults.HistoryRetentionDays)
+				}
+				if cfg.HistoryKeepPerIssue != defaults.HistoryKeepPerIssue {
+					t.Errorf("HistoryKeepPerIssue = %v, want %v", cfg.HistoryKeepPerIssue, defaults.HistoryKeepPerIssue)
 				}
 			},
 		},
 		{
 			name: "valid custom configuration",
 			envVars: map[string]string{
-				"VC_EVENT_RETENTION_DAYS":          "60",
-				"VC_EVENT_RETENTION_CRITICAL_DAYS": "180",
-				"VC_EVENT_PER_ISSUE_LIMIT":         "2000",
-				"VC_EVENT_GLOBAL_LIMIT":            "200000",
-				"VC_EVENT_CLEANUP_INTERVAL_HOURS":  "12",
-				"VC_EVENT_CLEANUP_BATCH_SIZE":      "500",
-				"VC_EVENT_CLEANUP_ENABLED":         "false",
-				"VC_EVENT_CLEANUP_STRATEGY":        "oldest_first",
-				"VC_EVENT_CLEANUP_VACUUM":          "true",
+				"VC_EVENT_RETENTION_DAYS":            "60",
+				"VC_EVENT_RETENTION_CRITICAL_DAYS":   "180",
+				"VC_EVENT_PER_ISSUE_LIMIT":           "2000",
+				"VC_EVENT_GLOBAL_LIMIT":              "200000",
+				"VC_EVENT_CLEANUP_INTERVAL_HOURS":    "12",
+				"VC_EVENT_CLEANUP_BATCH_SIZE":        "500",
+				"VC_EVENT_CLEANUP_ENABLED":           "false",
+				"VC_EVENT_CLEANUP_STRATEGY":          "oldest_first",
+				"VC_EVENT_VACUUM_FREE_FRACTION":      "0.5",
+				"VC_EVENT_VACUUM_MIN_INTERVAL_HOURS": "24",
+				"VC_HISTORY_RETENTION_DAYS":          "0",
+				"VC_HISTORY_KEEP_PER_ISSUE":          "3",
 			},
 			wantErr: false,
 			check: func(t *testing.T, cfg EventRetentionConfig) {
@@ -86,10 +98,45 @@ func TestEventRetentionConfigFromEnv(t *testing.T) {
 				if cfg.CleanupStrategy != "oldest_first" {
 					t.Errorf("CleanupStrategy = %v, want oldest_first", cfg.CleanupStrategy)
 				}
-				if cfg.CleanupVacuum != true {
-					t.Errorf("CleanupVacuum = %v, want true", cfg.CleanupVacuum)
+				if cfg.VacuumFreeFraction != 0.5 {
+					t.Errorf("VacuumFreeFraction = %v, want 0.5", cfg.VacuumFreeFraction)
+				}
+				if cfg.VacuumMinIntervalHours != 24 {
+					t.Errorf("VacuumMinIntervalHours = %v, want 24", cfg.VacuumMinIntervalHours)
 				}
+				if cfg.HistoryRetentionDays != 0 {
+					t.Errorf("HistoryRetentionDays = %v, want 0 (forever)", cfg.HistoryRetentionDays)
+				}
+				if cfg.HistoryKeepPerIssue != 3 {
+					t.Errorf("HistoryKeepPerIssue = %v, want 3", cfg.HistoryKeepPerIssue)
+				}
+			},
+		},
+		{
+			name: "legacy vacuum switch off disables automatic vacuum",
+			envVars: map[string]string{
+				"VC_EVENT_CLEANUP_VACUUM": "false",
+			},
+			wantErr: false,
+			check: func(t *testing.T, cfg EventRetentionConfig) {
+				if cfg.VacuumFreeFraction != 0 {
+					t.Errorf("VacuumFreeFraction = %v, want 0", cfg.VacuumFreeFraction)
+				}
+			},
+		},
+		{
+			name: "invalid history keep per issue",
+			envVars: map[string]string{
+				"VC_HISTORY_KEEP_PER_ISSUE": "-1",
 			},
+			wantErr: true,
+		},
+		{
+			name: "invalid vacuum free fraction",
+			envVars: map[string]string{
+				"VC_EVENT_VACUUM_FREE_FRACTION": "1.5",
+			},
+			wantErr: true,
 		},
 		{
 			name: "unlimited per-issue events (zero value)",
@@ -252,6 +299,10 @@ func TestEventRetentionConfigFromEnv(t *testing.T) {
 				"VC_EVENT_CLEANUP_ENABLED",
 				"VC_EVENT_CLEANUP_STRATEGY",
 				"VC_EVENT_CLEANUP_VACUUM",
+				"VC_EVENT_VACUUM_FREE_FRACTION",
+				"VC_EVENT_VACUUM_MIN_INTERVAL_HOURS",
+				"VC_HISTORY_RETENTION_DAYS",
+				"VC_HISTORY_KEEP_PER_ISSUE",
 			}
 			for _, key := range clearEnv {
 				_ = os.Unsetenv(key) // Intentionally ignore error in test cleanup
@@ -309,7 +360,7 @@ func TestEventRetentionConfigValidate(t *testing.T) {
 				CleanupBatchSize:      100,
 				CleanupEnabled:        true,
 				CleanupStrategy:       "oldest_first",
-				CleanupVacuum:         false,
+				VacuumFreeFraction:    0,
 			},
 			wantErr: false,
 		},
@@ -324,7 +375,7 @@ func TestEventRetentionConfigValidate(t *testing.T) {
 				CleanupBatchSize:      10000,
 				CleanupEnabled:        false,
 				CleanupStrategy:       "oldest_non_critical",
-				CleanupVacuum:         true,
+				VacuumFreeFraction:    0.9,
 			},
 			wantErr: false,
 		},
@@ -339,7 +390,7 @@ func TestEventRetentionConfigValidate(t *testing.T) {
 				CleanupBatchSize:      1000,
 				CleanupEnabled:        true,
 				CleanupStrategy:       "oldest_non_critical",
-				CleanupVacuum:         false,
+				VacuumFreeFraction:    0,
 			},
 			wantErr: true,
 			errMsg:  "retention_days must be between 1 and 365",
@@ -355,7 +406,7 @@ func TestEventRetentionConfigValidate(t *testing.T) {
 				CleanupBatchSize:      1000,
 				CleanupEnabled:        true,
 				CleanupStrategy:       "oldest_non_critical",
-				CleanupVacuum:         false,
+				VacuumFreeFraction:    0,
 			},
 			wantErr: true,
 			errMsg:  "retention_days must be between 1 and 365",
@@ -371,7 +422,7 @@ func TestEventRetentionConfigValidate(t *testing.T) {
 				CleanupBatchSize:      1000,
 				CleanupEnabled:        true,
 				CleanupStrategy:       "oldest_non_critical",
-				CleanupVacuum:         false,
+				VacuumFreeFraction:    0,
 			},
 			wantErr: true,
 			errMsg:  "retention_critical_days (30) must be >= retention_days (60)",
@@ -387,7 +438,7 @@ func TestEventRetentionConfigValidate(t *testing.T) {
 				CleanupBatchSize:      1000,
 				CleanupEnabled:        true,
 				CleanupStrategy:       "oldest_non_critical",
-				CleanupVacuum:         false,
+				VacuumFreeFraction:    0,
 			},
 			wantErr: true,
 			errMsg:  "per_issue_limit_events cannot be negative",
@@ -403,7 +454,7 @@ func TestEventRetentionConfigValidate(t *testing.T) {
 				CleanupBatchSize:      1000,
 				CleanupEnabled:        true,
 				CleanupStrategy:       "oldest_non_critical",
-				CleanupVacuum:         false,
+				VacuumFreeFraction:    0,
 			},
 			wantErr: true,
 			errMsg:  "per_issue_limit_events must be 0 (unlimited) or >= 100",
@@ -419,7 +470,7 @@ func TestEventRetentionConfigValidate(t *testing.T) {
 				CleanupBatchSize:      1000,
 				CleanupEnabled:        true,
 				CleanupStrategy:       "oldest_non_critical",
-				CleanupVacuum:         false,
+				VacuumFreeFraction:    0,
 			},
 			wantErr: false,
 		},
@@ -434,11 +485,27 @@ func TestEventRetentionConfigValidate(t *testing.T) {
 				CleanupBatchSize:      1000,
 				CleanupEnabled:        true,
 				CleanupStrategy:       "random_order",
-				CleanupVacuum:         false,
+				VacuumFreeFraction:    0,
 			},
 			wantErr: true,
 			errMsg:  "cleanup_strategy must be 'oldest_first' or 'oldest_non_critical'",
 		},
+		{
+			name: "history retention too long",
+			config: EventRetentionConfig{
+				RetentionDays:         30,
+				RetentionCriticalDays: 90,
+				PerIssueLimitEvents:   1000,
+				GlobalLimitEvents:     100000,
+				CleanupIntervalHours:  24,
+				CleanupBatchSize:      1000,
+				CleanupEnabled:        true,
+				CleanupStrategy:       "oldest_non_critical",
+				HistoryRetentionDays:  4000,
+			},
+			wantErr: true,
+			errMsg:  "history_retention_days must be between 0 and 3650",
+		},
 	}
 
 	for _, tt := range tests {
@@ -472,7 +539,10 @@ func TestEventRetentionConfigString(t *testing.T) {
 		"BatchSize: 1000",
 		"Enabled: true",
 		"Strategy: oldest_non_critical",
-		"Vacuum: false",
+		"VacuumFreeFraction: 0.25",
+		"VacuumMinInterval: 168h",
+		"HistoryRetentionDays: 90",
+		"HistoryKeepPerIssue: 10",
 	}
 
 	for _, exp := range expected {
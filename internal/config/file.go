@@ -0,0 +1,352 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SettingList is the type of config file keys holding a YAML list of
+// strings. Only config files have them.
+const SettingList SettingType = "list"
+
+// ProjectConfigFile is the project's config file, relative to the
+// directory vc runs in (like .beads, it isn't looked for further up)
+const ProjectConfigFile = ".vc/config.yaml"
+
+// executorFlagSettings are the executor keys of config files that set
+// `vc execute` flags rather than database settings
+var executorFlagSettings = []Setting{
+	{
+		Key:         "executor.backup_interval",
+		Type:        SettingDuration,
+		Default:     "0s",
+		Description: "Back up the database to .beads/backups this often (0 = no backups)",
+		ConsumedBy:  "vc execute (--backup-interval)",
+		Validate:    minDuration(0),
+	},
+	{
+		Key:         "executor.backup_keep",
+		Type:        SettingInt,
+		Default:     "7",
+		Description: "Number of periodic backups to keep",
+		ConsumedBy:  "vc execute (--backup-keep)",
+		Validate:    intRange(1, 1000),
+	},
+	{
+		Key:         "executor.disable_sandboxes",
+		Type:        SettingBool,
+		Default:     "false",
+		Description: "Run agents in the main workspace instead of sandboxes (development and testing only)",
+		ConsumedBy:  "vc execute (--disable-sandboxes)",
+	},
+	{
+		Key:         "executor.enable_auto_commit",
+		Type:        SettingBool,
+		Default:     "false",
+		Description: "Commit automatically after successful executions",
+		ConsumedBy:  "vc execute (--enable-auto-commit)",
+	},
+	{
+		Key:         "executor.parent_repo",
+		Type:        SettingString,
+		Default:     ".",
+		Description: "Parent repository path",
+		ConsumedBy:  "vc execute (--parent-repo)",
+	},
+	{
+		Key:         "executor.require_ai",
+		Type:        SettingBool,
+		Default:     "false",
+		Description: "Refuse to start if the AI provider fails its startup healthcheck",
+		ConsumedBy:  "vc execute (--require-ai)",
+	},
+	{
+		Key:         "executor.sandbox_root",
+		Type:        SettingString,
+		Default:     ".sandboxes",
+		Description: "Root directory for sandboxes",
+		ConsumedBy:  "vc execute (--sandbox-root)",
+	},
+}
+
+// FileSettings is the table of keys config files may set, sorted by key:
+// CLI defaults, the storage path, every executor.* setting and the `vc
+// execute` flags
+var FileSettings = fileSettings()
+
+func fileSettings() []Setting {
+	settings := []Setting{
+		{
+			Key:         "actor",
+			Type:        SettingString,
+			Default:     "",
+			Description: "Actor name for the audit trail (empty = $USER)",
+			ConsumedBy:  "every command (--actor)",
+		},
+		{
+			Key:         "create.assignee",
+			Type:        SettingString,
+			Default:     "",
+			Description: "Assignee of new issues",
+			ConsumedBy:  "vc create (--assignee)",
+		},
+		{
+			Key:         "create.labels",
+			Type:        SettingList,
+			Default:     "",
+			Description: "Labels of new issues",
+			ConsumedBy:  "vc create (--labels)",
+		},
+		{
+			Key:         "create.priority",
+			Type:        SettingInt,
+			Default:     "2",
+			Description: "Priority of new issues (0-4, 0=highest)",
+			ConsumedBy:  "vc create (--priority)",
+			Validate:    intRange(0, 4),
+		},
+		{
+			Key:         "create.type",
+			Type:        SettingString,
+			Default:     "task",
+			Description: "Type of new issues",
+			ConsumedBy:  "vc create (--type)",
+			Validate:    oneOf("bug", "feature", "task", "epic", "chore"),
+		},
+		{
+			Key:         "db",
+			Type:        SettingString,
+			Default:     "",
+			Description: "Database path (empty = auto-discover .beads/*.db)",
+			ConsumedBy:  "every command (--db)",
+		},
+	}
+	for _, setting := range Settings {
+		if IsExecutorSetting(setting.Key) {
+			settings = append(settings, setting)
+		}
+	}
+	settings = append(settings, executorFlagSettings...)
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Key < settings[j].Key })
+	return settings
+}
+
+// LookupFileSetting returns the config file key with the key
+func LookupFileSetting(key string) (Setting, bool) {
+	i := sort.Search(len(FileSettings), func(i int) bool { return FileSettings[i].Key >= key })
+	if i < len(FileSettings) && FileSettings[i].Key == key {
+		return FileSettings[i], true
+	}
+	return Setting{}, false
+}
+
+// isFileSection reports whether key is a mapping of config file keys, like
+// executor
+func isFileSection(key string) bool {
+	for _, setting := range FileSettings {
+		if strings.HasPrefix(setting.Key, key+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// FileValue is a value set in a config file
+type FileValue struct {
+	Value  string   // Normalized, e.g. "true" or "30s"; lists comma-separated
+	List   []string // The items of a SettingList
+	Source string   // Path of the file that set it
+}
+
+// File is the merged content of config files (.vc/config.yaml)
+type File struct {
+	Values map[string]FileValue
+	// Warnings name the unknown keys, which are ignored
+	Warnings []string
+}
+
+// UserConfigPath returns the user's config file, merged under the
+// project's: $XDG_CONFIG_HOME/vc/config.yaml, else ~/.config/vc/config.yaml.
+// Returns "" without a home directory.
+func UserConfigPath() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "vc", "config.yaml")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "vc", "config.yaml")
+}
+
+// LoadFiles reads and merges config files, each overriding the keys set
+// by the ones before it. Missing files and empty paths are skipped.
+func LoadFiles(paths ...string) (*File, error) {
+	f := &File{Values: make(map[string]FileValue)}
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading config file: %w", err)
+		}
+		if err := f.Parse(path, data); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// Parse merges the YAML content of the config file at path into f.
+// Unknown keys add warnings; values of the wrong type are errors naming
+// the file, line and key.
+func (f *File) Parse(path string, data []byte) error {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+	if len(doc.Content) == 0 {
+		return nil // Empty file
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("%s:%d: expected a mapping of keys to values", path, root.Line)
+	}
+	return f.parseMapping(path, "", root)
+}
+
+// parseMapping reads the keys of a mapping node under prefix
+func (f *File) parseMapping(path, prefix string, node *yaml.Node) error {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		name, value := node.Content[i], node.Content[i+1]
+		key := prefix + name.Value
+		if setting, known := LookupFileSetting(key); known {
+			v, set, err := decodeFileValue(setting, value)
+			if err != nil {
+				return fmt.Errorf("%s:%d: %s: %w", path, value.Line, key, err)
+			}
+			if set {
+				v.Source = path
+				f.Values[key] = v
+			}
+			continue
+		}
+		if isFileSection(key) {
+			if value.Kind != yaml.MappingNode {
+				return fmt.Errorf("%s:%d: %s: expected a mapping, got %s", path, value.Line, key, describeNode(value))
+			}
+			if err := f.parseMapping(path, key+".", value); err != nil {
+				return err
+			}
+			continue
+		}
+		f.Warnings = append(f.Warnings, fmt.Sprintf("%s:%d: unknown key %s (ignored)", path, name.Line, key))
+	}
+	return nil
+}
+
+// decodeFileValue checks a node against the setting's type. A null value
+// (key: ~ or key:) leaves the key unset.
+func decodeFileValue(setting Setting, node *yaml.Node) (FileValue, bool, error) {
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return FileValue{}, false, nil
+	}
+	if setting.Type == SettingList {
+		if node.Kind != yaml.SequenceNode {
+			return FileValue{}, false, fmt.Errorf("expected a list of strings, got %s", describeNode(node))
+		}
+		list := []string{}
+		for _, item := range node.Content {
+			if item.Kind != yaml.ScalarNode {
+				return FileValue{}, false, fmt.Errorf("expected a list of strings, got an item that is %s", describeNode(item))
+			}
+			list = append(list, item.Value)
+		}
+		return FileValue{Value: strings.Join(list, ","), List: list}, true, nil
+	}
+	if node.Kind != yaml.ScalarNode {
+		return FileValue{}, false, fmt.Errorf("expected %s, got %s", article(setting.Type), describeNode(node))
+	}
+
+	value := node.Value
+	var err error
+	switch setting.Type {
+	case SettingInt:
+		var n int
+		if err = node.Decode(&n); err == nil {
+			value = strconv.Itoa(n)
+		}
+	case SettingBool:
+		var b bool
+		if err = node.Decode(&b); err == nil {
+			value = strconv.FormatBool(b)
+		}
+	case SettingFloat:
+		var x float64
+		if err = node.Decode(&x); err == nil {
+			value = strconv.FormatFloat(x, 'g', -1, 64)
+		}
+	case SettingDuration:
+		_, err = time.ParseDuration(value)
+	}
+	if err != nil {
+		return FileValue{}, false, fmt.Errorf("expected %s, got %q", article(setting.Type), node.Value)
+	}
+	if setting.Validate != nil {
+		if err := setting.Validate(value); err != nil {
+			return FileValue{}, false, fmt.Errorf("invalid value %q: %w", node.Value, err)
+		}
+	}
+	return FileValue{Value: value}, true, nil
+}
+
+// article names a setting type with its article, e.g. "an int" or "a
+// duration like 30s or 5m"
+func article(typ SettingType) string {
+	switch typ {
+	case SettingInt:
+		return "an int"
+	case SettingDuration:
+		return "a duration like 30s or 5m"
+	}
+	return "a " + string(typ)
+}
+
+// describeNode names the kind of a YAML node for type mismatch errors
+func describeNode(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "a mapping"
+	case yaml.SequenceNode:
+		return "a list"
+	}
+	return fmt.Sprintf("%q", node.Value)
+}
+
+// Get returns the value of a key the files set. A nil File sets none.
+func (f *File) Get(key string) (FileValue, bool) {
+	if f == nil {
+		return FileValue{}, false
+	}
+	v, ok := f.Values[key]
+	return v, ok
+}
+
+// GetConfig makes the files a ConfigReader: unset keys read as "", so
+// GetConfigString and friends fall back to the setting's default
+func (f *File) GetConfig(ctx context.Context, key string) (string, error) {
+	return f.Values[key].Value, nil
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestFileSettingsTable(t *testing.T) {
+	for i, setting := range FileSettings {
+		if i > 0 && FileSettings[i-1].Key >= setting.Key {
+			t.Errorf("FileSettings must be sorted without duplicates: %s after %s", setting.Key, FileSettings[i-1].Key)
+		}
+		if setting.Type != SettingList && setting.Default != "" {
+			if err := setting.Check(setting.Default); err != nil {
+				t.Errorf("Default of %s is invalid: %v", setting.Key, err)
+			}
+		}
+	}
+	if _, known := LookupFileSetting("executor.poll_interval"); !known {
+		t.Error("Config files should set every executor setting")
+	}
+	if _, known := LookupFileSetting("issue_prefix"); known {
+		t.Error("Config files should not set database-only settings")
+	}
+}
+
+func TestLoadFiles(t *testing.T) {
+	dir := t.TempDir()
+	user := filepath.Join(dir, "user.yaml")
+	project := filepath.Join(dir, "project.yaml")
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(user, `actor: alice
+create:
+  priority: 1
+  labels: [team:core]
+executor:
+  poll_interval: 10s
+`)
+	write(project, `create:
+  priority: 0
+  labels: [area:cli, team:core]
+  color: blue
+executor:
+  keep_branches: true
+  sandbox_root: /tmp/sandboxes
+`)
+
+	f, err := LoadFiles(user, project, filepath.Join(dir, "missing.yaml"))
+	if err != nil {
+		t.Fatalf("LoadFiles: %v", err)
+	}
+	want := map[string]string{
+		"actor":                  "alice " + user,
+		"create.priority":        "0 " + project,
+		"create.labels":          "area:cli,team:core " + project,
+		"executor.poll_interval": "10s " + user,
+		"executor.keep_branches": "true " + project,
+		"executor.sandbox_root":  "/tmp/sandboxes " + project,
+	}
+	for key, v := range want {
+		got, ok := f.Get(key)
+		if !ok || got.Value+" "+got.Source != v {
+			t.Errorf("%s = %+v, want %s", key, got, v)
+		}
+	}
+	if len(f.Values) != len(want) {
+		keys := make([]string, 0, len(f.Values))
+		for key := range f.Values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		t.Errorf("Unexpected keys set: %s", strings.Join(keys, ", "))
+	}
+	if len(f.Warnings) != 1 || !strings.Contains(f.Warnings[0], project+":4: unknown key create.color") {
+		t.Errorf("Warnings = %q, want one for create.color", f.Warnings)
+	}
+}
+
+func TestParseFileErrors(t *testing.T) {
+	tests := []struct {
+		name, content, want string
+	}{
+		{"int", "create:\n  priority: high\n", `config.yaml:2: create.priority: expected an int, got "high"`},
+		{"range", "create:\n  priority: 7\n", `config.yaml:2: create.priority: invalid value "7": must be between 0 and 4`},
+		{"duration", "executor:\n  poll_interval: 10\n", `config.yaml:2: executor.poll_interval: expected a duration like 30s or 5m, got "10"`},
+		{"bool", "executor:\n  offline: maybe\n", `config.yaml:2: executor.offline: expected a bool, got "maybe"`},
+		{"list", "create:\n  labels: area:cli\n", `config.yaml:2: create.labels: expected a list of strings, got "area:cli"`},
+		{"section", "executor: fast\n", `config.yaml:1: executor: expected a mapping, got "fast"`},
+		{"nested value", "actor:\n  name: alice\n", `config.yaml:2: actor: expected a string, got a mapping`},
+		{"top level", "- actor\n", `config.yaml:1: expected a mapping of keys to values`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &File{Values: map[string]FileValue{}}
+			err := f.Parse("config.yaml", []byte(tt.content))
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("Parse() error = %v, want %s", err, tt.want)
+			}
+		})
+	}
+
+	// Null values leave keys unset, and unknown sections only warn
+	f := &File{Values: map[string]FileValue{}}
+	if err := f.Parse("config.yaml", []byte("actor: ~\nhealth:\n  enabled: true\n")); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if _, ok := f.Get("actor"); ok || len(f.Warnings) != 1 {
+		t.Errorf("Got values %+v and warnings %q, want no value and one warning", f.Values, f.Warnings)
+	}
+}
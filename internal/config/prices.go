@@ -0,0 +1,85 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ModelPrice is what a model costs in USD per million tokens
+type ModelPrice struct {
+	InputPerMTok  float64
+	OutputPerMTok float64
+}
+
+// Cost estimates the USD cost of a call
+func (p ModelPrice) Cost(inputTokens, outputTokens int64) float64 {
+	return (float64(inputTokens)*p.InputPerMTok + float64(outputTokens)*p.OutputPerMTok) / 1e6
+}
+
+// DefaultModelPrices are list prices of the providers' default models.
+// The executor.ai_prices setting overrides and extends them.
+var DefaultModelPrices = map[string]ModelPrice{
+	"claude-sonnet-4-5": {InputPerMTok: 3, OutputPerMTok: 15},
+	"claude-haiku-4-5":  {InputPerMTok: 1, OutputPerMTok: 5},
+	"claude-3-5-haiku":  {InputPerMTok: 0.8, OutputPerMTok: 4},
+	"claude-opus-4-1":   {InputPerMTok: 15, OutputPerMTok: 75},
+	"gpt-4o":            {InputPerMTok: 2.5, OutputPerMTok: 10},
+	"gpt-4o-mini":       {InputPerMTok: 0.15, OutputPerMTok: 0.6},
+}
+
+// ParseModelPrices parses a price table of comma-separated
+// model=input/output entries, in USD per million tokens, e.g.
+// "llama3=0/0,gpt-4o=2.5/10". An empty string is an empty table.
+func ParseModelPrices(value string) (map[string]ModelPrice, error) {
+	prices := make(map[string]ModelPrice)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		model, rates, ok := strings.Cut(entry, "=")
+		input, output, ok2 := strings.Cut(rates, "/")
+		model = strings.TrimSpace(model)
+		if !ok || !ok2 || model == "" {
+			return nil, fmt.Errorf("price %q is not model=input/output", entry)
+		}
+		in, err := strconv.ParseFloat(strings.TrimSpace(input), 64)
+		if err != nil || in < 0 {
+			return nil, fmt.Errorf("invalid input price in %q", entry)
+		}
+		out, err := strconv.ParseFloat(strings.TrimSpace(output), 64)
+		if err != nil || out < 0 {
+			return nil, fmt.Errorf("invalid output price in %q", entry)
+		}
+		prices[model] = ModelPrice{InputPerMTok: in, OutputPerMTok: out}
+	}
+	return prices, nil
+}
+
+// LookupModelPrice finds a model's price: an exact entry, else the longest
+// entry the model starts with, so dated snapshots (claude-sonnet-4-5-20250929)
+// match their family
+func LookupModelPrice(prices map[string]ModelPrice, model string) (ModelPrice, bool) {
+	if price, ok := prices[model]; ok {
+		return price, true
+	}
+	keys := make([]string, 0, len(prices))
+	for key := range prices {
+		keys = append(keys, key)
+	}
+	// Longest first; ties broken by name for determinism
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) > len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+	for _, key := range keys {
+		if strings.HasPrefix(model, key) {
+			return prices[key], true
+		}
+	}
+	return ModelPrice{}, false
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseModelPrices(t *testing.T) {
+	prices, err := ParseModelPrices(" llama3=0/0, gpt-4o=2.5/10 ,")
+	if err != nil {
+		t.Fatalf("ParseModelPrices: %v", err)
+	}
+	if len(prices) != 2 || prices["gpt-4o"] != (ModelPrice{InputPerMTok: 2.5, OutputPerMTok: 10}) {
+		t.Errorf("Unexpected prices: %v", prices)
+	}
+
+	for _, bad := range []string{"gpt-4o", "gpt-4o=2.5", "=1/2", "gpt-4o=x/1", "gpt-4o=1/-2"} {
+		if _, err := ParseModelPrices(bad); err == nil {
+			t.Errorf("Expected an error for %q", bad)
+		}
+	}
+	if err := CheckSetting("executor.ai_prices", "gpt-4o=oops"); err == nil {
+		t.Error("Expected executor.ai_prices to reject a malformed table")
+	}
+}
+
+func TestLookupModelPrice(t *testing.T) {
+	prices := map[string]ModelPrice{
+		"gpt-4o":      {InputPerMTok: 2.5, OutputPerMTok: 10},
+		"gpt-4o-mini": {InputPerMTok: 0.15, OutputPerMTok: 0.6},
+	}
+	tests := []struct {
+		model string
+		want  float64
+		ok    bool
+	}{
+		{"gpt-4o", 2.5, true},
+		{"gpt-4o-2024-08-06", 2.5, true},
+		{"gpt-4o-mini-2024-07-18", 0.15, true},
+		{"llama3", 0, false},
+	}
+	for _, tt := range tests {
+		price, ok := LookupModelPrice(prices, tt.model)
+		if ok != tt.ok || price.InputPerMTok != tt.want {
+			t.Errorf("LookupModelPrice(%s) = %v, %v; want input %v, %v", tt.model, price, ok, tt.want, tt.ok)
+		}
+	}
+
+	if _, ok := LookupModelPrice(DefaultModelPrices, "claude-sonnet-4-5-20250929"); !ok {
+		t.Error("Expected a default price for the default Anthropic model")
+	}
+}
+
+func TestModelPriceCost(t *testing.T) {
+	price := ModelPrice{InputPerMTok: 3, OutputPerMTok: 15}
+	if got := price.Cost(1000, 200); math.Abs(got-0.006) > 1e-12 {
+		t.Errorf("Cost = %v, want 0.006", got)
+	}
+}
@@ -0,0 +1,31 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParsePromptBudgets parses comma-separated agent=tokens entries giving the
+// prompt token budget of each agent type, e.g. "amp=150000,claude-code=180000".
+// An empty string is an empty table.
+func ParsePromptBudgets(value string) (map[string]int, error) {
+	budgets := make(map[string]int)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		agent, tokens, ok := strings.Cut(entry, "=")
+		agent = strings.TrimSpace(agent)
+		if !ok || agent == "" {
+			return nil, fmt.Errorf("prompt budget %q is not agent=tokens", entry)
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(tokens))
+		if err != nil || n < 1000 {
+			return nil, fmt.Errorf("invalid token budget in %q (want an integer of at least 1000)", entry)
+		}
+		budgets[agent] = n
+	}
+	return budgets, nil
+}
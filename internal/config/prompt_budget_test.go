@@ -0,0 +1,22 @@
+package config
+
+import "testing"
+
+func TestParsePromptBudgets(t *testing.T) {
+	budgets, err := ParsePromptBudgets(" amp=150000, claude-code=180000 ,")
+	if err != nil {
+		t.Fatalf("ParsePromptBudgets: %v", err)
+	}
+	if len(budgets) != 2 || budgets["claude-code"] != 180000 {
+		t.Errorf("Unexpected budgets: %v", budgets)
+	}
+
+	for _, bad := range []string{"amp", "=5000", "amp=lots", "amp=10"} {
+		if _, err := ParsePromptBudgets(bad); err == nil {
+			t.Errorf("Expected an error for %q", bad)
+		}
+	}
+	if err := CheckSetting("executor.prompt_token_budgets", "amp=x"); err == nil {
+		t.Error("Expected executor.prompt_token_budgets to reject a malformed table")
+	}
+}
@@ -0,0 +1,615 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SettingType is the type of a runtime setting's value
+type SettingType string
+
+const (
+	SettingString   SettingType = "string"
+	SettingInt      SettingType = "int"
+	SettingBool     SettingType = "bool"
+	SettingDuration SettingType = "duration" // Go duration syntax, e.g. 30s or 5m
+	SettingFloat    SettingType = "float"
+)
+
+// Setting describes a runtime setting stored in the database config table
+// (`vc config set`). Keys are namespaced by the component that reads them,
+// e.g. executor.poll_interval.
+type Setting struct {
+	Key         string
+	Type        SettingType
+	Default     string
+	Description string
+	// ConsumedBy names where the value is read, for `vc config list`
+	ConsumedBy string
+	// Validate checks a value that already parses as Type (nil = any)
+	Validate func(value string) error
+}
+
+// ExecutorNamespace prefixes the settings the executor reads at startup.
+// Changing one of them is recorded as a SYSTEM config_changed event.
+const ExecutorNamespace = "executor."
+
+// DedupNamespace prefixes the deduplication settings, read by vc execute
+// and vc dedup when they set up the deduplicator
+const DedupNamespace = "dedup."
+
+// Settings is the table of known runtime settings, sorted by key
+var Settings = []Setting{
+	{
+		Key:         "dedup.confidence_threshold",
+		Type:        SettingFloat,
+		Default:     "0.85",
+		Description: "Minimum AI confidence (0-1) to treat a new issue as a duplicate; lower catches more duplicates but risks dropping distinct issues",
+		ConsumedBy:  "vc execute, vc dedup (deduplicator)",
+		Validate:    floatRange(0, 1),
+	},
+	{
+		Key:         "dedup.lookback_window",
+		Type:        SettingDuration,
+		Default:     "168h",
+		Description: "How recently a closed issue must have closed to be compared (only with closed in dedup.statuses)",
+		ConsumedBy:  "vc execute, vc dedup (deduplicator)",
+		Validate:    durationRange(time.Hour, 90*24*time.Hour),
+	},
+	{
+		Key:         "dedup.max_candidates",
+		Type:        SettingInt,
+		Default:     "25",
+		Description: "Existing issues compared per new issue, most recently updated first; more finds older duplicates at higher AI cost",
+		ConsumedBy:  "vc execute, vc dedup (deduplicator)",
+		Validate:    intRange(1, 500),
+	},
+	{
+		Key:         "dedup.scope",
+		Type:        SettingString,
+		Default:     "all",
+		Description: "Which issues a discovered issue is compared with: all, epic (the same epic as the issue it was found in) or label (sharing one of its labels)",
+		ConsumedBy:  "vc execute, vc dedup (deduplicator)",
+		Validate:    oneOf("all", "epic", "label"),
+	},
+	{
+		Key:         "dedup.statuses",
+		Type:        SettingString,
+		Default:     "open",
+		Description: "Comma-separated statuses of the issues compared: open, in_progress, blocked, closed",
+		ConsumedBy:  "vc execute, vc dedup (deduplicator)",
+		Validate:    listOf("open", "in_progress", "blocked", "closed"),
+	},
+	{
+		Key:         "event_search_index",
+		Type:        SettingString,
+		Default:     "on",
+		Description: "Keep the full-text index of agent events (off, false, no or 0 drop it)",
+		ConsumedBy:  "storage, when the database is opened",
+		Validate:    oneOf("on", "off", "true", "false", "yes", "no", "1", "0"),
+	},
+	{
+		Key:         "executor.ai_api_key_env",
+		Type:        SettingString,
+		Default:     "",
+		Description: "Env var holding the AI API key (empty = the provider's: ANTHROPIC_API_KEY, OPENAI_API_KEY)",
+		ConsumedBy:  "vc execute, vc health (AI supervisor)",
+	},
+	{
+		Key:         "executor.ai_base_url",
+		Type:        SettingString,
+		Default:     "",
+		Description: "AI API endpoint (empty = the provider's; http://localhost:11434/v1 for local)",
+		ConsumedBy:  "vc execute, vc health (AI supervisor)",
+	},
+	{
+		Key:         "executor.ai_comment_max_kb",
+		Type:        SettingInt,
+		Default:     "16",
+		Description: "Size cap of AI-authored comments in KB; longer ones are truncated and their full text saved as an artifact (0 = no cap)",
+		ConsumedBy:  "vc execute (assessment, analysis and watchdog comments)",
+		Validate:    intRange(0, 1<<20),
+	},
+	{
+		Key:         "executor.ai_model",
+		Type:        SettingString,
+		Default:     "",
+		Description: "AI model (empty = the provider's default; required for local)",
+		ConsumedBy:  "vc execute, vc health (AI supervisor)",
+	},
+	{
+		Key:         "executor.ai_prices",
+		Type:        SettingString,
+		Default:     "",
+		Description: "AI prices in USD per million tokens as model=input/output,... (extends the built-in table; a model prefix matches dated versions)",
+		ConsumedBy:  "vc execute, vc health (ai_call_completed cost estimates)",
+		Validate: func(value string) error {
+			_, err := ParseModelPrices(value)
+			return err
+		},
+	},
+	{
+		Key:         "executor.ai_provider",
+		Type:        SettingString,
+		Default:     "anthropic",
+		Description: "AI provider: anthropic, openai or local (OpenAI-compatible endpoint)",
+		ConsumedBy:  "vc execute, vc health (AI supervisor)",
+		Validate:    oneOf("anthropic", "openai", "local"),
+	},
+	{
+		Key:         "executor.artifacts_dir",
+		Type:        SettingString,
+		Default:     "",
+		Description: "Where each attempt's diff, gate logs and agent summary are saved, relative to the project root (empty = .beads/artifacts, off = don't save)",
+		ConsumedBy:  "vc execute (results, cleanup loop)",
+	},
+	{
+		Key:         "executor.artifacts_max_age",
+		Type:        SettingDuration,
+		Default:     "720h",
+		Description: "Age after which the cleanup loop removes an attempt's artifacts (0 = no age limit)",
+		ConsumedBy:  "vc execute (cleanup loop)",
+		Validate:    minDuration(0),
+	},
+	{
+		Key:         "executor.artifacts_max_file_kb",
+		Type:        SettingInt,
+		Default:     "1024",
+		Description: "Size cap of each artifact file in KB; larger diffs and logs are truncated",
+		ConsumedBy:  "vc execute (results)",
+		Validate:    intRange(1, 1<<20),
+	},
+	{
+		Key:         "executor.artifacts_max_total_mb",
+		Type:        SettingInt,
+		Default:     "500",
+		Description: "Size the artifacts directory is trimmed to by the cleanup loop, oldest attempts first (0 = no size limit)",
+		ConsumedBy:  "vc execute (cleanup loop)",
+		Validate:    intRange(0, 1<<20),
+	},
+	{
+		Key:         "executor.auto_merge_threshold",
+		Type:        SettingFloat,
+		Default:     "0",
+		Description: "Merge sandbox changes scoring at least this (0-1) without human approval and hold the rest for review (0 = always ask)",
+		ConsumedBy:  "vc execute (results processor)",
+		Validate:    floatRange(0, 1),
+	},
+	{
+		Key:         "executor.cleanup_interval",
+		Type:        SettingDuration,
+		Default:     "5m",
+		Description: "How often to look for stale executor instances",
+		ConsumedBy:  "vc execute (cleanup loop)",
+		Validate:    minDuration(time.Second),
+	},
+	{
+		Key:         "executor.daily_summary",
+		Type:        SettingBool,
+		Default:     "false",
+		Description: "Record a daily_summary event once a day with the digest 'vc summary' prints",
+		ConsumedBy:  "vc execute (cleanup loop)",
+	},
+	{
+		Key:         "executor.daily_summary_webhook",
+		Type:        SettingString,
+		Default:     "",
+		Description: "Chat webhook URL the daily summary is also posted to, as markdown (empty = event only)",
+		ConsumedBy:  "vc execute (cleanup loop)",
+	},
+	{
+		Key:         "executor.discovered_force_triage",
+		Type:        SettingBool,
+		Default:     "false",
+		Description: "Label every discovered issue needs-triage instead of trusting the AI's inferred priority",
+		ConsumedBy:  "vc execute (results processor)",
+	},
+	{
+		Key:         "executor.enable_failure_analysis",
+		Type:        SettingBool,
+		Default:     "false",
+		Description: "Ask the AI supervisor to diagnose failed attempts and suggest a fix for the next one",
+		ConsumedBy:  "vc execute (results processor)",
+	},
+	{
+		Key:         "executor.enable_quality_gate_worker",
+		Type:        SettingBool,
+		Default:     "true",
+		Description: "Run quality gates for missions in a QA worker",
+		ConsumedBy:  "vc execute (QA worker)",
+	},
+	{
+		Key:         "executor.enable_quality_gates",
+		Type:        SettingBool,
+		Default:     "true",
+		Description: "Run quality gates after each execution",
+		ConsumedBy:  "vc execute (results processor)",
+	},
+	{
+		Key:         "executor.epic_auto_close",
+		Type:        SettingString,
+		Default:     "off",
+		Description: "When every issue blocking an epic is closed: off, label (ready-to-close and a comment) or close (with a comment listing the children); reopening a child undoes it",
+		ConsumedBy:  "vc execute (event loop)",
+		Validate:    oneOf("off", "label", "close"),
+	},
+	{
+		Key:         "executor.event_coalesce_exempt",
+		Type:        SettingString,
+		Default:     "",
+		Description: "Comma-separated warning event types stored every time instead of coalesced, e.g. storage_timeout",
+		ConsumedBy:  "vc execute (event logging)",
+		Validate: func(value string) error {
+			if strings.ContainsAny(value, " \t") {
+				return fmt.Errorf("must be event types separated by commas, without spaces")
+			}
+			return nil
+		},
+	},
+	{
+		Key:         "executor.event_coalesce_window",
+		Type:        SettingDuration,
+		Default:     "10m",
+		Description: "How long identical executor warnings (same type, issue and message) are counted before one summary event is stored (0 = store each)",
+		ConsumedBy:  "vc execute (event logging)",
+		Validate:    durationRange(0, 24*time.Hour),
+	},
+	{
+		Key:         "executor.failure_analysis_cost_cap",
+		Type:        SettingFloat,
+		Default:     "0.5",
+		Description: "Maximum estimated USD spent on failure analysis per issue (0 = no cap)",
+		ConsumedBy:  "vc execute (results processor)",
+		Validate:    floatRange(0, 100),
+	},
+	{
+		Key:         "executor.heartbeat_period",
+		Type:        SettingDuration,
+		Default:     "30s",
+		Description: "How often the executor instance sends a heartbeat",
+		ConsumedBy:  "vc execute (heartbeat)",
+		Validate:    minDuration(time.Second),
+	},
+	{
+		Key:         "executor.keep_branches",
+		Type:        SettingBool,
+		Default:     "false",
+		Description: "Keep mission branches after their sandbox is cleaned up",
+		ConsumedBy:  "vc execute (sandbox manager)",
+	},
+	{
+		Key:         "executor.keep_sandbox_on_failure",
+		Type:        SettingBool,
+		Default:     "false",
+		Description: "Keep the sandboxes of failed executions for debugging",
+		ConsumedBy:  "vc execute (sandbox manager)",
+	},
+	{
+		Key:         "executor.offline",
+		Type:        SettingBool,
+		Default:     "false",
+		Description: "Run without AI: no supervision, dedup, watchdog AI analysis or health monitors",
+		ConsumedBy:  "vc execute",
+	},
+	{
+		Key:         "executor.paused",
+		Type:        SettingBool,
+		Default:     "false",
+		Description: "Claim no new work; the issue in progress still finishes (re-read every poll, so a running executor drains)",
+		ConsumedBy:  "vc execute (event loop), vc tui",
+	},
+	{
+		Key:         "executor.poll_interval",
+		Type:        SettingDuration,
+		Default:     "5s",
+		Description: "How often to poll for ready work (--poll-interval overrides)",
+		ConsumedBy:  "vc execute (event loop)",
+		Validate:    minDuration(100 * time.Millisecond),
+	},
+	{
+		Key:         "executor.prompt_token_budgets",
+		Type:        SettingString,
+		Default:     "",
+		Description: "Agent prompt token budgets as agent=tokens,... (agents: amp, claude-code; unset agents get 150000)",
+		ConsumedBy:  "vc execute (prompt truncation)",
+		Validate: func(value string) error {
+			_, err := ParsePromptBudgets(value)
+			return err
+		},
+	},
+	{
+		Key:         "executor.respect_assignees",
+		Type:        SettingBool,
+		Default:     "true",
+		Description: "Leave work assigned to a human alone unless it is labeled agent-ok",
+		ConsumedBy:  "vc execute (event loop)",
+	},
+	{
+		Key:         "executor.sandbox_retention_count",
+		Type:        SettingInt,
+		Default:     "3",
+		Description: "Failed sandboxes to keep (0 = keep all)",
+		ConsumedBy:  "vc execute (sandbox manager)",
+		Validate:    intRange(0, 1000),
+	},
+	{
+		Key:         "executor.shutdown_grace",
+		Type:        SettingDuration,
+		Default:     "30s",
+		Description: "How long a graceful shutdown waits beyond the running agent's remaining timeout before stopping it",
+		ConsumedBy:  "vc execute (shutdown)",
+		Validate:    durationRange(time.Second, time.Hour),
+	},
+	{
+		Key:         "executor.stale_threshold",
+		Type:        SettingDuration,
+		Default:     "5m",
+		Description: "Heartbeat age after which an executor instance is stale",
+		ConsumedBy:  "vc execute (cleanup loop)",
+		Validate:    minDuration(time.Second),
+	},
+	{
+		Key:         "executor.storage_call_timeout",
+		Type:        SettingDuration,
+		Default:     "5s",
+		Description: "Timeout of storage calls in the executor's hot paths (heartbeat, claims, state updates, events)",
+		ConsumedBy:  "vc execute (event loop, heartbeat)",
+		Validate:    durationRange(100*time.Millisecond, 5*time.Minute),
+	},
+	{
+		Key:         "executor.storage_failure_threshold",
+		Type:        SettingInt,
+		Default:     "3",
+		Description: "Consecutive failed heartbeat, ready-work and claim calls after which the database is reopened",
+		ConsumedBy:  "vc execute (heartbeat)",
+		Validate:    intRange(1, 100),
+	},
+	{
+		Key:         "executor.work_order",
+		Type:        SettingString,
+		Default:     "priority",
+		Description: "Order ready work is claimed in: priority (most urgent, then oldest), age (oldest first) or estimate (shortest job first)",
+		ConsumedBy:  "vc execute (event loop)",
+		Validate:    oneOf("priority", "age", "estimate"),
+	},
+	{
+		Key:         "issue_prefix",
+		Type:        SettingString,
+		Default:     "vc",
+		Description: "Prefix of new issue IDs",
+		ConsumedBy:  "storage, when creating issues",
+		Validate: func(value string) error {
+			if value == "" || strings.ContainsAny(value, " \t") {
+				return fmt.Errorf("must be non-empty without spaces")
+			}
+			return nil
+		},
+	},
+	{
+		Key:         "project",
+		Type:        SettingString,
+		Default:     "",
+		Description: "Project to work in when --project isn't given and no project root contains the working directory (empty = every project)",
+		ConsumedBy:  "vc create, list, ready and execute",
+		Validate: func(value string) error {
+			if strings.ContainsAny(value, " \t") {
+				return fmt.Errorf("must not contain spaces")
+			}
+			return nil
+		},
+	},
+	{
+		Key:         "slow_query_ms",
+		Type:        SettingInt,
+		Default:     "0",
+		Description: "Log database queries taking at least this many milliseconds and count them for vc stats --storage (0 = off; VC_SLOW_QUERY_MS overrides)",
+		ConsumedBy:  "storage, when the database is opened",
+		Validate:    intRange(0, 3600000),
+	},
+	{
+		Key:         "unblock_dependents",
+		Type:        SettingString,
+		Default:     "reopen",
+		Description: "When closing an issue leaves a blocked issue it blocks with no open blocker: reopen (with a comment), label (ready-to-unblock and a comment) or off",
+		ConsumedBy:  "storage, when closing issues",
+		Validate:    oneOf("reopen", "label", "off"),
+	},
+}
+
+// LookupSetting returns the known setting with the key
+func LookupSetting(key string) (Setting, bool) {
+	i := sort.Search(len(Settings), func(i int) bool { return Settings[i].Key >= key })
+	if i < len(Settings) && Settings[i].Key == key {
+		return Settings[i], true
+	}
+	return Setting{}, false
+}
+
+// IsExecutorSetting reports whether the executor reads the key
+func IsExecutorSetting(key string) bool {
+	_, known := LookupSetting(key)
+	return known && strings.HasPrefix(key, ExecutorNamespace)
+}
+
+// Check reports whether value is valid for the setting
+func (s Setting) Check(value string) error {
+	var err error
+	switch s.Type {
+	case SettingInt:
+		_, err = strconv.Atoi(value)
+	case SettingBool:
+		_, err = strconv.ParseBool(value)
+	case SettingDuration:
+		_, err = time.ParseDuration(value)
+	case SettingFloat:
+		_, err = strconv.ParseFloat(value, 64)
+	}
+	if err == nil && s.Validate != nil {
+		err = s.Validate(value)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid %s value %q for %s: %w", s.Type, value, s.Key, err)
+	}
+	return nil
+}
+
+// CheckSetting validates a value for a key; unknown keys are accepted as is
+func CheckSetting(key, value string) error {
+	setting, known := LookupSetting(key)
+	if !known {
+		return nil
+	}
+	return setting.Check(value)
+}
+
+// ConfigReader is the part of the store settings are read from
+type ConfigReader interface {
+	GetConfig(ctx context.Context, key string) (string, error)
+}
+
+// settingValue returns the stored value of a known setting of the given
+// type, or its default when unset
+func settingValue(ctx context.Context, r ConfigReader, key string, typ SettingType) (string, error) {
+	setting, known := LookupSetting(key)
+	if !known {
+		return "", fmt.Errorf("unknown setting: %s", key)
+	}
+	if setting.Type != typ {
+		return "", fmt.Errorf("setting %s is a %s, not a %s", key, setting.Type, typ)
+	}
+	value, err := r.GetConfig(ctx, key)
+	if err != nil {
+		return "", fmt.Errorf("failed to read setting %s: %w", key, err)
+	}
+	if value == "" {
+		return setting.Default, nil
+	}
+	if err := setting.Check(value); err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+// GetConfigString returns a string setting, or its default when unset
+func GetConfigString(ctx context.Context, r ConfigReader, key string) (string, error) {
+	return settingValue(ctx, r, key, SettingString)
+}
+
+// GetConfigInt returns an int setting, or its default when unset
+func GetConfigInt(ctx context.Context, r ConfigReader, key string) (int, error) {
+	value, err := settingValue(ctx, r, key, SettingInt)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(value)
+}
+
+// GetConfigBool returns a bool setting, or its default when unset
+func GetConfigBool(ctx context.Context, r ConfigReader, key string) (bool, error) {
+	value, err := settingValue(ctx, r, key, SettingBool)
+	if err != nil {
+		return false, err
+	}
+	return strconv.ParseBool(value)
+}
+
+// GetConfigFloat returns a float setting, or its default when unset
+func GetConfigFloat(ctx context.Context, r ConfigReader, key string) (float64, error) {
+	value, err := settingValue(ctx, r, key, SettingFloat)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+// GetConfigDuration returns a duration setting, or its default when unset
+func GetConfigDuration(ctx context.Context, r ConfigReader, key string) (time.Duration, error) {
+	value, err := settingValue(ctx, r, key, SettingDuration)
+	if err != nil {
+		return 0, err
+	}
+	return time.ParseDuration(value)
+}
+
+// minDuration validates durations of at least min
+func minDuration(min time.Duration) func(string) error {
+	return func(value string) error {
+		if d, _ := time.ParseDuration(value); d < min {
+			return fmt.Errorf("must be at least %v", min)
+		}
+		return nil
+	}
+}
+
+// durationRange validates durations within [min, max]
+func durationRange(min, max time.Duration) func(string) error {
+	return func(value string) error {
+		if d, _ := time.ParseDuration(value); d < min || d > max {
+			return fmt.Errorf("must be between %v and %v", min, max)
+		}
+		return nil
+	}
+}
+
+// floatRange validates floats within [min, max]
+func floatRange(min, max float64) func(string) error {
+	return func(value string) error {
+		if f, _ := strconv.ParseFloat(value, 64); f < min || f > max {
+			return fmt.Errorf("must be between %g and %g", min, max)
+		}
+		return nil
+	}
+}
+
+// intRange validates ints within [min, max]
+func intRange(min, max int) func(string) error {
+	return func(value string) error {
+		if n, _ := strconv.Atoi(value); n < min || n > max {
+			return fmt.Errorf("must be between %d and %d", min, max)
+		}
+		return nil
+	}
+}
+
+// oneOf validates values from a fixed list
+func oneOf(allowed ...string) func(string) error {
+	return func(value string) error {
+		for _, a := range allowed {
+			if value == a {
+				return nil
+			}
+		}
+		return fmt.Errorf("must be one of %s", strings.Join(allowed, ", "))
+	}
+}
+
+// listOf validates non-empty comma-separated lists of values from a fixed list
+func listOf(allowed ...string) func(string) error {
+	single := oneOf(allowed...)
+	return func(value string) error {
+		for _, item := range SplitList(value) {
+			if err := single(item); err != nil {
+				return fmt.Errorf("%q: %w", item, err)
+			}
+		}
+		if len(SplitList(value)) == 0 {
+			return fmt.Errorf("must list at least one of %s", strings.Join(allowed, ", "))
+		}
+		return nil
+	}
+}
+
+// SplitList splits a comma-separated setting value, dropping blanks
+func SplitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
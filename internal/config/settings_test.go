@@ -0,0 +1,104 @@
+package config
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+)
+
+// mapReader is a ConfigReader over a map
+type mapReader map[string]string
+
+func (m mapReader) GetConfig(ctx context.Context, key string) (string, error) {
+	return m[key], nil
+}
+
+func TestSettingsTable(t *testing.T) {
+	if !sort.SliceIsSorted(Settings, func(i, j int) bool { return Settings[i].Key < Settings[j].Key }) {
+		t.Error("Settings must be sorted by key for LookupSetting")
+	}
+	for _, setting := range Settings {
+		if err := setting.Check(setting.Default); err != nil {
+			t.Errorf("Default of %s is invalid: %v", setting.Key, err)
+		}
+		if setting.Description == "" || setting.ConsumedBy == "" {
+			t.Errorf("%s needs a description and where it's consumed", setting.Key)
+		}
+	}
+	if !IsExecutorSetting("executor.poll_interval") || IsExecutorSetting("issue_prefix") || IsExecutorSetting("executor.unknown") {
+		t.Error("IsExecutorSetting should match only known executor.* keys")
+	}
+}
+
+func TestCheckSetting(t *testing.T) {
+	tests := []struct {
+		key, value string
+		wantErr    bool
+	}{
+		{"executor.poll_interval", "2s", false},
+		{"executor.poll_interval", "soon", true},
+		{"executor.poll_interval", "10ms", true},
+		{"executor.sandbox_retention_count", "0", false},
+		{"executor.sandbox_retention_count", "-1", true},
+		{"executor.sandbox_retention_count", "three", true},
+		{"executor.keep_branches", "yes", true},
+		{"executor.keep_branches", "true", false},
+		{"event_search_index", "off", false},
+		{"event_search_index", "maybe", true},
+		{"issue_prefix", "my proj", true},
+		{"project", "web", false},
+		{"project", "my web", true},
+		{"dedup.confidence_threshold", "0.9", false},
+		{"dedup.confidence_threshold", "1.5", true},
+		{"dedup.confidence_threshold", "high", true},
+		{"dedup.statuses", "open, in_progress", false},
+		{"dedup.statuses", "open,done", true},
+		{"dedup.statuses", " , ", true},
+		{"dedup.scope", "epic", false},
+		{"dedup.scope", "team", true},
+		{"some.unknown.key", "anything", false},
+	}
+	for _, tt := range tests {
+		err := CheckSetting(tt.key, tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("CheckSetting(%s, %q) = %v, wantErr %v", tt.key, tt.value, err, tt.wantErr)
+		}
+	}
+}
+
+func TestTypedAccessors(t *testing.T) {
+	ctx := context.Background()
+	r := mapReader{
+		"executor.poll_interval":           "2s",
+		"executor.sandbox_retention_count": "7",
+		"executor.stale_threshold":         "bogus",
+	}
+
+	if d, err := GetConfigDuration(ctx, r, "executor.poll_interval"); err != nil || d != 2*time.Second {
+		t.Errorf("GetConfigDuration: got (%v, %v), want 2s", d, err)
+	}
+	if d, err := GetConfigDuration(ctx, r, "executor.heartbeat_period"); err != nil || d != 30*time.Second {
+		t.Errorf("GetConfigDuration: expected the 30s default, got (%v, %v)", d, err)
+	}
+	if n, err := GetConfigInt(ctx, r, "executor.sandbox_retention_count"); err != nil || n != 7 {
+		t.Errorf("GetConfigInt: got (%d, %v), want 7", n, err)
+	}
+	if b, err := GetConfigBool(ctx, r, "executor.enable_quality_gates"); err != nil || !b {
+		t.Errorf("GetConfigBool: expected the true default, got (%v, %v)", b, err)
+	}
+
+	if f, err := GetConfigFloat(ctx, r, "dedup.confidence_threshold"); err != nil || f != 0.85 {
+		t.Errorf("GetConfigFloat: expected the 0.85 default, got (%v, %v)", f, err)
+	}
+
+	if _, err := GetConfigDuration(ctx, r, "executor.stale_threshold"); err == nil {
+		t.Error("Expected an error for an invalid stored value")
+	}
+	if _, err := GetConfigInt(ctx, r, "executor.poll_interval"); err == nil {
+		t.Error("Expected an error reading a duration as an int")
+	}
+	if _, err := GetConfigBool(ctx, r, "executor.nope"); err == nil {
+		t.Error("Expected an error for an unknown setting")
+	}
+}
@@ -14,12 +14,13 @@ import (
 // AIDeduplicator implements the Deduplicator interface using AI-powered semantic analysis
 type AIDeduplicator struct {
 	supervisor *ai.Supervisor
-	store      storage.Storage
+	store      storage.IssueStore
 	config     Config
 }
 
 // Compile-time check that AIDeduplicator implements Deduplicator
 var _ Deduplicator = (*AIDeduplicator)(nil)
+var _ ModelReporter = (*AIDeduplicator)(nil)
 
 // NewAIDeduplicator creates a new AI-powered deduplicator
 //
@@ -37,7 +38,7 @@ var _ Deduplicator = (*AIDeduplicator)(nil)
 //	if err != nil {
 //	    return fmt.Errorf("failed to create deduplicator: %w", err)
 //	}
-func NewAIDeduplicator(supervisor *ai.Supervisor, store storage.Storage, config Config) (*AIDeduplicator, error) {
+func NewAIDeduplicator(supervisor *ai.Supervisor, store storage.IssueStore, config Config) (*AIDeduplicator, error) {
 	// Validate dependencies
 	if supervisor == nil {
 		return nil, fmt.Errorf("supervisor cannot be nil")
@@ -58,6 +59,11 @@ func NewAIDeduplicator(supervisor *ai.Supervisor, store storage.Storage, config
 	}, nil
 }
 
+// AIModel returns the AI provider and model the deduplicator calls
+func (d *AIDeduplicator) AIModel() (provider, model string) {
+	return d.supervisor.Provider(), d.supervisor.Model()
+}
+
 // CheckDuplicate checks if a candidate issue is a duplicate of any recent open issues
 func (d *AIDeduplicator) CheckDuplicate(ctx context.Context, candidate *types.Issue) (*DuplicateDecision, error) {
 	// Validate candidate issue (config is already validated in constructor)
@@ -80,15 +86,7 @@ func (d *AIDeduplicator) CheckDuplicate(ctx context.Context, candidate *types.Is
 		}, nil
 	}
 
-	// Query recent open issues
-	// Note: GetReadyWork returns open issues with no blockers
-	// For deduplication, we actually want ALL open issues, but this is a reasonable approximation
-	filter := types.WorkFilter{
-		Status: types.StatusOpen,
-		Limit:  d.config.MaxCandidates,
-	}
-
-	existingIssues, err := d.store.GetReadyWork(ctx, filter)
+	filteredIssues, err := d.existingIssues(ctx, candidate)
 	if err != nil {
 		// Fail-safe: if we can't query existing issues, assume not duplicate
 		log.Printf("[DEDUP] Failed to query existing issues: %v (assuming not duplicate)", err)
@@ -100,14 +98,6 @@ func (d *AIDeduplicator) CheckDuplicate(ctx context.Context, candidate *types.Is
 		}, nil
 	}
 
-	// Filter out self-comparison
-	filteredIssues := []*types.Issue{}
-	for _, existing := range existingIssues {
-		if existing.ID != candidate.ID {
-			filteredIssues = append(filteredIssues, existing)
-		}
-	}
-
 	if len(filteredIssues) == 0 {
 		return &DuplicateDecision{
 			IsDuplicate:   false,
@@ -0,0 +1,250 @@
+package deduplication
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// maxScopeDepth bounds the parent-child walks of the epic scope
+const maxScopeDepth = 10
+
+// existingIssues returns the issues a candidate is compared with: those
+// with a configured status (closed ones only within LookbackWindow) in the
+// configured scope, most recently updated first, at most MaxCandidates
+func (d *AIDeduplicator) existingIssues(ctx context.Context, candidate *types.Issue) ([]*types.Issue, error) {
+	statuses := d.config.statuses()
+
+	var pool []*types.Issue
+	scope, scopeID := d.config.scope(), scopeIssue(ctx)
+	if scope != ScopeAll && scopeID != "" {
+		var err error
+		if pool, err = d.scopedIssues(ctx, scope, scopeID); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, status := range statuses {
+			status := status
+			issues, err := d.store.SearchIssues(ctx, "", types.IssueFilter{
+				Status:     &status,
+				Limit:      d.config.MaxCandidates + 1, // The candidate may be among them
+				OrderBy:    "updated_at",
+				Descending: true,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to query %s issues: %w", status, err)
+			}
+			pool = append(pool, issues...)
+		}
+	}
+
+	wanted := make(map[types.Status]bool, len(statuses))
+	for _, status := range statuses {
+		wanted[status] = true
+	}
+	closedSince := time.Now().Add(-d.config.LookbackWindow)
+	seen := make(map[string]bool)
+	var existing []*types.Issue
+	for _, issue := range pool {
+		if issue.ID == candidate.ID || seen[issue.ID] || !wanted[issue.Status] {
+			continue
+		}
+		if issue.Status == types.StatusClosed && issue.ClosedAt != nil && issue.ClosedAt.Before(closedSince) {
+			continue
+		}
+		seen[issue.ID] = true
+		existing = append(existing, issue)
+	}
+	sort.SliceStable(existing, func(i, j int) bool {
+		return existing[i].UpdatedAt.After(existing[j].UpdatedAt)
+	})
+	if len(existing) > d.config.MaxCandidates {
+		existing = existing[:d.config.MaxCandidates]
+	}
+	return existing, nil
+}
+
+// scopedIssues returns the issues in the scope of the issue the candidates
+// were found in: its epic's tree, or the issues sharing one of its labels.
+// An issue without labels falls back to every issue.
+func (d *AIDeduplicator) scopedIssues(ctx context.Context, scope Scope, scopeID string) ([]*types.Issue, error) {
+	switch scope {
+	case ScopeEpic:
+		root, err := d.epicOf(ctx, scopeID)
+		if err != nil {
+			return nil, err
+		}
+		return d.epicTree(ctx, root)
+	case ScopeLabel:
+		labels, err := d.store.GetLabels(ctx, scopeID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get labels of %s: %w", scopeID, err)
+		}
+		if len(labels) == 0 {
+			log.Printf("[DEDUP] %s has no labels, comparing against all issues", scopeID)
+			return d.allIssues(ctx)
+		}
+		var issues []*types.Issue
+		for _, label := range labels {
+			labeled, err := d.store.GetIssuesByLabel(ctx, label)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get issues labeled %s: %w", label, err)
+			}
+			issues = append(issues, labeled...)
+		}
+		return issues, nil
+	}
+	return d.allIssues(ctx)
+}
+
+// allIssues returns every non-archived issue
+func (d *AIDeduplicator) allIssues(ctx context.Context) ([]*types.Issue, error) {
+	issues, err := d.store.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query issues: %w", err)
+	}
+	return issues, nil
+}
+
+// epicOf walks up the parent-child links from an issue to the nearest epic,
+// or to the topmost parent when there is no epic above it
+func (d *AIDeduplicator) epicOf(ctx context.Context, issueID string) (*types.Issue, error) {
+	var issue *types.Issue
+	id := issueID
+	for depth := 0; depth < maxScopeDepth && id != ""; depth++ {
+		next, err := d.store.GetIssue(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get issue %s: %w", id, err)
+		}
+		if next == nil {
+			break
+		}
+		issue = next
+		if issue.IssueType == types.TypeEpic {
+			break
+		}
+		deps, err := d.store.GetDependencyRecords(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get dependencies of %s: %w", id, err)
+		}
+		id = ""
+		for _, dep := range deps {
+			if dep.Type == types.DepParentChild {
+				id = dep.DependsOnID
+				break
+			}
+		}
+	}
+	if issue == nil {
+		return nil, fmt.Errorf("scope issue %s not found", issueID)
+	}
+	return issue, nil
+}
+
+// epicTree returns an epic and its parent-child descendants
+func (d *AIDeduplicator) epicTree(ctx context.Context, root *types.Issue) ([]*types.Issue, error) {
+	tree := []*types.Issue{root}
+	level := []string{root.ID}
+	seen := map[string]bool{root.ID: true}
+	for depth := 0; depth < maxScopeDepth && len(level) > 0; depth++ {
+		var next []string
+		for _, parentID := range level {
+			dependents, err := d.store.GetDependents(ctx, parentID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get dependents of %s: %w", parentID, err)
+			}
+			for _, dependent := range dependents {
+				if seen[dependent.ID] {
+					continue
+				}
+				child, err := d.isChildOf(ctx, dependent.ID, parentID)
+				if err != nil {
+					return nil, err
+				}
+				if child {
+					seen[dependent.ID] = true
+					tree = append(tree, dependent)
+					next = append(next, dependent.ID)
+				}
+			}
+		}
+		level = next
+	}
+	return tree, nil
+}
+
+// isChildOf reports whether issueID has a parent-child link to parentID
+func (d *AIDeduplicator) isChildOf(ctx context.Context, issueID, parentID string) (bool, error) {
+	deps, err := d.store.GetDependencyRecords(ctx, issueID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get dependencies of %s: %w", issueID, err)
+	}
+	for _, dep := range deps {
+		if dep.DependsOnID == parentID && dep.Type == types.DepParentChild {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// RankedMatch is an existing issue scored against a candidate
+type RankedMatch struct {
+	Issue      *types.Issue
+	Confidence float64
+	// IsDuplicate is the AI's judgment; the issue only counts as a
+	// duplicate when Confidence also reaches the threshold
+	IsDuplicate bool
+	Reasoning   string
+}
+
+// RankMatches compares a candidate against every existing issue it would be
+// checked against and returns the scores, highest confidence first. Unlike
+// CheckDuplicate it doesn't stop at the first duplicate, so the threshold
+// can be calibrated against the whole list.
+func (d *AIDeduplicator) RankMatches(ctx context.Context, candidate *types.Issue) ([]RankedMatch, error) {
+	existing, err := d.existingIssues(ctx, candidate)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*types.Issue, len(existing))
+	for _, issue := range existing {
+		byID[issue.ID] = issue
+	}
+
+	var matches []RankedMatch
+	for i := 0; i < len(existing); i += d.config.BatchSize {
+		end := i + d.config.BatchSize
+		if end > len(existing) {
+			end = len(existing)
+		}
+		resp, err := d.supervisor.CheckIssueDuplicateBatch(ctx, candidate, existing[i:end])
+		if err != nil {
+			return nil, err
+		}
+		for _, result := range resp.Results {
+			issue, ok := byID[result.ExistingIssueID]
+			if !ok {
+				continue
+			}
+			matches = append(matches, RankedMatch{
+				Issue:       issue,
+				Confidence:  result.Confidence,
+				IsDuplicate: result.IsDuplicate,
+				Reasoning:   result.Reasoning,
+			})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Confidence > matches[j].Confidence
+	})
+	return matches, nil
+}
+
+// Threshold returns the confidence a match needs to count as a duplicate
+func (d *AIDeduplicator) Threshold() float64 {
+	return d.config.ConfidenceThreshold
+}
@@ -0,0 +1,129 @@
+package deduplication
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/ai"
+	"github.com/steveyegge/vc/internal/storage/storagetest"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestLoadConfig(t *testing.T) {
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+	for key, value := range map[string]string{
+		"dedup.confidence_threshold": "0.7",
+		"dedup.max_candidates":       "40",
+		"dedup.statuses":             "open, blocked",
+		"dedup.scope":                "epic",
+	} {
+		if err := store.SetConfig(ctx, key, value); err != nil {
+			t.Fatalf("SetConfig %s: %v", key, err)
+		}
+	}
+	t.Setenv("VC_DEDUP_MAX_CANDIDATES", "60")
+
+	cfg, err := LoadConfig(ctx, store)
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if cfg.ConfidenceThreshold != 0.7 || cfg.Scope != ScopeEpic {
+		t.Errorf("Settings not applied: threshold %.2f, scope %s", cfg.ConfidenceThreshold, cfg.Scope)
+	}
+	if cfg.MaxCandidates != 60 {
+		t.Errorf("Expected the environment to override max candidates, got %d", cfg.MaxCandidates)
+	}
+	if len(cfg.Statuses) != 2 || cfg.Statuses[0] != types.StatusOpen || cfg.Statuses[1] != types.StatusBlocked {
+		t.Errorf("Unexpected statuses: %v", cfg.Statuses)
+	}
+	if cfg.LookbackWindow != DefaultConfig().LookbackWindow {
+		t.Errorf("Unset setting changed the lookback window to %v", cfg.LookbackWindow)
+	}
+}
+
+// TestExistingIssues verifies which issues a candidate is compared with
+// under the status and scope settings
+func TestExistingIssues(t *testing.T) {
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+	create := func(title string, issueType types.IssueType, labels ...string) *types.Issue {
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 2, IssueType: issueType}
+		if err := store.CreateIssueWithMetadata(ctx, issue, labels, nil, "test"); err != nil {
+			t.Fatalf("CreateIssue %s: %v", title, err)
+		}
+		return issue
+	}
+	link := func(child, parent *types.Issue) {
+		dep := &types.Dependency{IssueID: child.ID, DependsOnID: parent.ID, Type: types.DepParentChild}
+		if err := store.AddDependency(ctx, dep, "test"); err != nil {
+			t.Fatalf("AddDependency: %v", err)
+		}
+	}
+
+	epic := create("Parser epic", types.TypeEpic)
+	parent := create("Parser work", types.TypeTask, "parser")
+	sibling := create("Parser sibling", types.TypeTask)
+	create("Labeled elsewhere", types.TypeTask, "parser")
+	other := create("Unrelated", types.TypeTask)
+	blocked := create("Blocked one", types.TypeTask)
+	link(parent, epic)
+	link(sibling, epic)
+	if err := store.UpdateIssue(ctx, blocked.ID, map[string]interface{}{"status": types.StatusBlocked}, "test"); err != nil {
+		t.Fatalf("UpdateIssue: %v", err)
+	}
+
+	ids := func(cfg Config, scopeID string) string {
+		t.Helper()
+		d, err := NewAIDeduplicator(&ai.Supervisor{}, store, cfg)
+		if err != nil {
+			t.Fatalf("NewAIDeduplicator: %v", err)
+		}
+		if scopeID != "" {
+			ctx = WithScopeIssue(context.Background(), scopeID)
+		} else {
+			ctx = context.Background()
+		}
+		issues, err := d.existingIssues(ctx, &types.Issue{ID: other.ID})
+		if err != nil {
+			t.Fatalf("existingIssues: %v", err)
+		}
+		var got []string
+		for _, issue := range issues {
+			got = append(got, issue.Title)
+		}
+		sort.Strings(got)
+		return strings.Join(got, ",")
+	}
+
+	cfg := DefaultConfig()
+	if got := ids(cfg, ""); got != "Labeled elsewhere,Parser epic,Parser sibling,Parser work" {
+		t.Errorf("Default (open, all): %s", got)
+	}
+	cfg.Statuses = []types.Status{types.StatusBlocked}
+	if got := ids(cfg, ""); got != "Blocked one" {
+		t.Errorf("Blocked only: %s", got)
+	}
+
+	cfg = DefaultConfig()
+	cfg.Scope = ScopeEpic
+	if got := ids(cfg, parent.ID); got != "Parser epic,Parser sibling,Parser work" {
+		t.Errorf("Epic scope: %s", got)
+	}
+	if got := ids(cfg, ""); got != "Labeled elsewhere,Parser epic,Parser sibling,Parser work" {
+		t.Errorf("Epic scope without a scope issue should compare all: %s", got)
+	}
+
+	cfg.Scope = ScopeLabel
+	if got := ids(cfg, parent.ID); got != "Labeled elsewhere,Parser work" {
+		t.Errorf("Label scope: %s", got)
+	}
+
+	cfg = DefaultConfig()
+	cfg.MaxCandidates = 2
+	if got := ids(cfg, ""); strings.Count(got, ",") != 1 {
+		t.Errorf("Expected 2 candidates, got %s", got)
+	}
+}
@@ -5,6 +5,17 @@ import (
 	"os"
 	"strconv"
 	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// Scope limits which existing issues a candidate is compared with
+type Scope string
+
+const (
+	ScopeAll   Scope = "all"   // Every issue with a compared status
+	ScopeEpic  Scope = "epic"  // Issues in the same epic as the scope issue
+	ScopeLabel Scope = "label" // Issues sharing a label with the scope issue
 )
 
 // Config holds configuration for the deduplication engine
@@ -59,6 +70,17 @@ type Config struct {
 	// RequestTimeout is the timeout for individual AI API calls
 	// Default: 30 seconds
 	RequestTimeout time.Duration
+
+	// Statuses are the statuses of the existing issues compared against.
+	// Closed issues only count if they closed within LookbackWindow.
+	// Default: open (empty also means open)
+	Statuses []types.Status
+
+	// Scope limits comparisons to the epic or labels of the issue the
+	// candidates were found in (see WithScopeIssue). Without a scope issue
+	// every candidate is compared as with ScopeAll.
+	// Default: ScopeAll (empty also means all)
+	Scope Scope
 }
 
 // DefaultConfig returns the default deduplication configuration
@@ -86,6 +108,8 @@ func DefaultConfig() Config {
 		MinTitleLength:         10,                // Minimum title length
 		MaxRetries:             2,                 // Retry twice on failure
 		RequestTimeout:         30 * time.Second,  // 30 second timeout
+		Statuses:               []types.Status{types.StatusOpen},
+		Scope:                  ScopeAll,
 	}
 }
 
@@ -131,6 +155,16 @@ func (c Config) Validate() error {
 	if c.RequestTimeout > 5*time.Minute {
 		return fmt.Errorf("request_timeout too large (got %v, max 5 minutes)", c.RequestTimeout)
 	}
+	for _, status := range c.Statuses {
+		if !status.IsValid() {
+			return fmt.Errorf("invalid status in statuses: %q", status)
+		}
+	}
+	switch c.Scope {
+	case "", ScopeAll, ScopeEpic, ScopeLabel:
+	default:
+		return fmt.Errorf("scope must be all, epic or label (got %q)", c.Scope)
+	}
 	return nil
 }
 
@@ -139,10 +173,10 @@ func (c Config) String() string {
 	return fmt.Sprintf(
 		"Config{Threshold: %.2f, Lookback: %v, MaxCandidates: %d, BatchSize: %d, "+
 			"WithinBatch: %t, FailOpen: %t, IncludeClosed: %t, MinTitleLen: %d, "+
-			"MaxRetries: %d, Timeout: %v}",
+			"MaxRetries: %d, Timeout: %v, Statuses: %v, Scope: %s}",
 		c.ConfidenceThreshold, c.LookbackWindow, c.MaxCandidates, c.BatchSize,
 		c.EnableWithinBatchDedup, c.FailOpen, c.IncludeClosedIssues, c.MinTitleLength,
-		c.MaxRetries, c.RequestTimeout,
+		c.MaxRetries, c.RequestTimeout, c.Statuses, c.scope(),
 	)
 }
 
@@ -163,45 +197,49 @@ func (c Config) String() string {
 // Returns an error if any environment variable has an invalid value.
 func ConfigFromEnv() (Config, error) {
 	cfg := DefaultConfig()
-
-	// Parse environment variables with validation
-	if err := parseEnvFloat("VC_DEDUP_CONFIDENCE_THRESHOLD", &cfg.ConfidenceThreshold); err != nil {
+	if err := cfg.applyEnv(); err != nil {
 		return cfg, err
 	}
-	if err := parseEnvDuration("VC_DEDUP_LOOKBACK_DAYS", &cfg.LookbackWindow, 24*time.Hour); err != nil {
-		return cfg, err
+
+	// Validate the final configuration
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid configuration from environment: %w", err)
 	}
-	if err := parseEnvInt("VC_DEDUP_MAX_CANDIDATES", &cfg.MaxCandidates); err != nil {
-		return cfg, err
+
+	return cfg, nil
+}
+
+// applyEnv overrides the config with the VC_DEDUP_* variables that are set
+func (c *Config) applyEnv() error {
+	// Parse environment variables with validation
+	if err := parseEnvFloat("VC_DEDUP_CONFIDENCE_THRESHOLD", &c.ConfidenceThreshold); err != nil {
+		return err
 	}
-	if err := parseEnvInt("VC_DEDUP_BATCH_SIZE", &cfg.BatchSize); err != nil {
-		return cfg, err
+	if err := parseEnvDuration("VC_DEDUP_LOOKBACK_DAYS", &c.LookbackWindow, 24*time.Hour); err != nil {
+		return err
 	}
-	if err := parseEnvBool("VC_DEDUP_WITHIN_BATCH", &cfg.EnableWithinBatchDedup); err != nil {
-		return cfg, err
+	if err := parseEnvInt("VC_DEDUP_MAX_CANDIDATES", &c.MaxCandidates); err != nil {
+		return err
 	}
-	if err := parseEnvBool("VC_DEDUP_FAIL_OPEN", &cfg.FailOpen); err != nil {
-		return cfg, err
+	if err := parseEnvInt("VC_DEDUP_BATCH_SIZE", &c.BatchSize); err != nil {
+		return err
 	}
-	if err := parseEnvBool("VC_DEDUP_INCLUDE_CLOSED", &cfg.IncludeClosedIssues); err != nil {
-		return cfg, err
+	if err := parseEnvBool("VC_DEDUP_WITHIN_BATCH", &c.EnableWithinBatchDedup); err != nil {
+		return err
 	}
-	if err := parseEnvInt("VC_DEDUP_MIN_TITLE_LENGTH", &cfg.MinTitleLength); err != nil {
-		return cfg, err
+	if err := parseEnvBool("VC_DEDUP_FAIL_OPEN", &c.FailOpen); err != nil {
+		return err
 	}
-	if err := parseEnvInt("VC_DEDUP_MAX_RETRIES", &cfg.MaxRetries); err != nil {
-		return cfg, err
+	if err := parseEnvBool("VC_DEDUP_INCLUDE_CLOSED", &c.IncludeClosedIssues); err != nil {
+		return err
 	}
-	if err := parseEnvDuration("VC_DEDUP_TIMEOUT_SECS", &cfg.RequestTimeout, time.Second); err != nil {
-		return cfg, err
+	if err := parseEnvInt("VC_DEDUP_MIN_TITLE_LENGTH", &c.MinTitleLength); err != nil {
+		return err
 	}
-
-	// Validate the final configuration
-	if err := cfg.Validate(); err != nil {
-		return cfg, fmt.Errorf("invalid configuration from environment: %w", err)
+	if err := parseEnvInt("VC_DEDUP_MAX_RETRIES", &c.MaxRetries); err != nil {
+		return err
 	}
-
-	return cfg, nil
+	return parseEnvDuration("VC_DEDUP_TIMEOUT_SECS", &c.RequestTimeout, time.Second)
 }
 
 // parseEnvFloat parses a float64 from an environment variable
@@ -63,6 +63,12 @@ type Deduplicator interface {
 	DeduplicateBatch(ctx context.Context, candidates []*types.Issue) (*DeduplicationResult, error)
 }
 
+// ModelReporter is implemented by deduplicators backed by an AI model, so
+// their events can record the provider and model used
+type ModelReporter interface {
+	AIModel() (provider, model string)
+}
+
 // DuplicateDecision represents the result of checking a single issue for duplicates
 type DuplicateDecision struct {
 	// IsDuplicate is true if the candidate is a duplicate with high confidence
@@ -0,0 +1,219 @@
+package deduplication
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ScanFilter selects the issues a scan compares, as given on the command
+// line; a saved scan only resumes with the same filter
+type ScanFilter struct {
+	Label string `json:"label,omitempty"`
+	Type  string `json:"type,omitempty"`
+	Since string `json:"since,omitempty"` // Window as given, e.g. 90d
+}
+
+// ScanPair is a pair of existing issues the AI judged duplicates
+type ScanPair struct {
+	Older      string  `json:"older"`
+	Newer      string  `json:"newer"`
+	Confidence float64 `json:"confidence"`
+	Reasoning  string  `json:"reasoning"`
+}
+
+// ScanState is the progress of a scan of existing issues for duplicates,
+// saved after every issue so an interrupted scan resumes where it stopped
+type ScanState struct {
+	Filter ScanFilter `json:"filter"`
+	// IssueIDs are the scanned issues, oldest first, fixed when the scan starts
+	IssueIDs []string `json:"issue_ids"`
+	// Next indexes the next issue of IssueIDs to compare with the newer ones
+	Next      int        `json:"next"`
+	Pairs     []ScanPair `json:"pairs"`
+	AICalls   int        `json:"ai_calls"`
+	StartedAt time.Time  `json:"started_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// NewScanState starts a scan of the issues, which it orders oldest first
+func NewScanState(filter ScanFilter, issues []*types.Issue) *ScanState {
+	sorted := append([]*types.Issue{}, issues...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+	})
+	state := &ScanState{Filter: filter, Pairs: []ScanPair{}, StartedAt: time.Now()}
+	for _, issue := range sorted {
+		state.IssueIDs = append(state.IssueIDs, issue.ID)
+	}
+	return state
+}
+
+// Done reports whether every issue has been compared
+func (s *ScanState) Done() bool {
+	// The newest issue has nothing newer to compare with
+	return s.Next >= len(s.IssueIDs)-1
+}
+
+// LoadScanState reads a saved scan; nil, nil if there is none
+func LoadScanState(path string) (*ScanState, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var state ScanState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse scan state %s: %w", path, err)
+	}
+	return &state, nil
+}
+
+// Save writes the scan state, replacing the file atomically so an
+// interruption can't leave it half written
+func (s *ScanState) Save(path string) error {
+	s.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".dedup-scan-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// ScanIssue compares an issue with newer ones in batches of BatchSize and
+// returns the pairs the AI judged duplicates, whatever their confidence,
+// so groups can be formed at any threshold later. Also returns the number
+// of AI calls made.
+func (d *AIDeduplicator) ScanIssue(ctx context.Context, issue *types.Issue, newer []*types.Issue) ([]ScanPair, int, error) {
+	var pairs []ScanPair
+	calls := 0
+	for i := 0; i < len(newer); i += d.config.BatchSize {
+		end := i + d.config.BatchSize
+		if end > len(newer) {
+			end = len(newer)
+		}
+		resp, err := d.supervisor.CheckIssueDuplicateBatch(ctx, issue, newer[i:end])
+		calls++
+		if err != nil {
+			return pairs, calls, err
+		}
+		for _, result := range resp.Results {
+			if !result.IsDuplicate || result.ExistingIssueID == issue.ID {
+				continue
+			}
+			pairs = append(pairs, ScanPair{
+				Older:      issue.ID,
+				Newer:      result.ExistingIssueID,
+				Confidence: result.Confidence,
+				Reasoning:  result.Reasoning,
+			})
+		}
+	}
+	return pairs, calls, nil
+}
+
+// DuplicateGroup is a set of issues connected by duplicate pairs; the
+// oldest survives and the others are its duplicates
+type DuplicateGroup struct {
+	Survivor   string     `json:"survivor"`
+	Duplicates []string   `json:"duplicates"` // Oldest first
+	Pairs      []ScanPair `json:"pairs"`
+	// MinConfidence is the weakest pair holding the group together
+	MinConfidence float64 `json:"min_confidence"`
+}
+
+// Groups joins the scan's pairs at or above the threshold into duplicate
+// groups, largest first
+func (s *ScanState) Groups(threshold float64) []DuplicateGroup {
+	order := make(map[string]int, len(s.IssueIDs))
+	for i, id := range s.IssueIDs {
+		order[id] = i
+	}
+	parent := make(map[string]string)
+	var find func(id string) string
+	find = func(id string) string {
+		if p, ok := parent[id]; ok && p != id {
+			root := find(p)
+			parent[id] = root
+			return root
+		}
+		parent[id] = id
+		return id
+	}
+
+	var kept []ScanPair
+	for _, pair := range s.Pairs {
+		if pair.Confidence < threshold {
+			continue
+		}
+		kept = append(kept, pair)
+		a, b := find(pair.Older), find(pair.Newer)
+		if a == b {
+			continue
+		}
+		// The older root survives
+		if order[b] < order[a] {
+			a, b = b, a
+		}
+		parent[b] = a
+	}
+
+	byRoot := make(map[string]*DuplicateGroup)
+	var roots []string
+	for _, pair := range kept {
+		root := find(pair.Older)
+		group, ok := byRoot[root]
+		if !ok {
+			group = &DuplicateGroup{Survivor: root, MinConfidence: pair.Confidence}
+			byRoot[root] = group
+			roots = append(roots, root)
+		}
+		group.Pairs = append(group.Pairs, pair)
+		if pair.Confidence < group.MinConfidence {
+			group.MinConfidence = pair.Confidence
+		}
+	}
+	for id := range parent {
+		root := find(id)
+		if group, ok := byRoot[root]; ok && id != root {
+			group.Duplicates = append(group.Duplicates, id)
+		}
+	}
+
+	groups := make([]DuplicateGroup, 0, len(roots))
+	for _, root := range roots {
+		group := byRoot[root]
+		sort.Slice(group.Duplicates, func(i, j int) bool {
+			return order[group.Duplicates[i]] < order[group.Duplicates[j]]
+		})
+		groups = append(groups, *group)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		if len(groups[i].Duplicates) != len(groups[j].Duplicates) {
+			return len(groups[i].Duplicates) > len(groups[j].Duplicates)
+		}
+		return order[groups[i].Survivor] < order[groups[j].Survivor]
+	})
+	return groups
+}
@@ -0,0 +1,67 @@
+package deduplication
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestScanStateGroups(t *testing.T) {
+	now := time.Now()
+	var issues []*types.Issue
+	for i, id := range []string{"vc-5", "vc-1", "vc-2", "vc-3", "vc-4"} {
+		// Created in list order, so vc-5 is the oldest
+		issues = append(issues, &types.Issue{ID: id, CreatedAt: now.Add(time.Duration(i) * time.Minute)})
+	}
+	state := NewScanState(ScanFilter{}, issues)
+	state.Pairs = []ScanPair{
+		{Older: "vc-1", Newer: "vc-3", Confidence: 0.9},
+		{Older: "vc-5", Newer: "vc-3", Confidence: 0.87},
+		{Older: "vc-2", Newer: "vc-4", Confidence: 0.95},
+		{Older: "vc-1", Newer: "vc-4", Confidence: 0.6},
+	}
+
+	groups := state.Groups(0.85)
+	if len(groups) != 2 {
+		t.Fatalf("Expected 2 groups, got %+v", groups)
+	}
+	if groups[0].Survivor != "vc-5" || !reflect.DeepEqual(groups[0].Duplicates, []string{"vc-1", "vc-3"}) || groups[0].MinConfidence != 0.87 {
+		t.Errorf("Unexpected first group: %+v", groups[0])
+	}
+	if groups[1].Survivor != "vc-2" || !reflect.DeepEqual(groups[1].Duplicates, []string{"vc-4"}) {
+		t.Errorf("Unexpected second group: %+v", groups[1])
+	}
+
+	// A lower threshold joins everything through vc-1 - vc-4
+	if groups := state.Groups(0.5); len(groups) != 1 || len(groups[0].Duplicates) != 4 {
+		t.Errorf("Expected one group of 5 at 0.5, got %+v", groups)
+	}
+}
+
+func TestScanStateSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dedup-scan.json")
+	if state, err := LoadScanState(path); state != nil || err != nil {
+		t.Fatalf("Expected no saved scan, got %v, %v", state, err)
+	}
+
+	state := NewScanState(ScanFilter{Label: "parser"}, []*types.Issue{{ID: "vc-1"}, {ID: "vc-2"}, {ID: "vc-3"}})
+	state.Next = 1
+	state.Pairs = append(state.Pairs, ScanPair{Older: "vc-1", Newer: "vc-2", Confidence: 0.9})
+	if err := state.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	loaded, err := LoadScanState(path)
+	if err != nil {
+		t.Fatalf("LoadScanState: %v", err)
+	}
+	if loaded.Filter.Label != "parser" || loaded.Next != 1 || len(loaded.Pairs) != 1 || loaded.Done() {
+		t.Errorf("Unexpected loaded state: %+v", loaded)
+	}
+	loaded.Next = 2
+	if !loaded.Done() {
+		t.Error("Expected the scan done once only the newest issue is left")
+	}
+}
@@ -0,0 +1,110 @@
+package deduplication
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// LoadConfig returns the deduplication config: the defaults, overridden by
+// the dedup.* settings stored in the database (`vc config set`), then by
+// any VC_DEDUP_* environment variables
+func LoadConfig(ctx context.Context, r config.ConfigReader) (Config, error) {
+	cfg := DefaultConfig()
+	if err := cfg.applySettings(ctx, r); err != nil {
+		return cfg, err
+	}
+	if err := cfg.applyEnv(); err != nil {
+		return cfg, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid deduplication configuration: %w", err)
+	}
+	return cfg, nil
+}
+
+// applySettings overrides the config with the dedup.* settings; unset ones
+// leave it alone
+func (c *Config) applySettings(ctx context.Context, r config.ConfigReader) error {
+	stored := func(key string) (bool, error) {
+		value, err := r.GetConfig(ctx, key)
+		return value != "", err
+	}
+	for _, key := range []string{
+		"dedup.confidence_threshold", "dedup.lookback_window", "dedup.max_candidates",
+		"dedup.scope", "dedup.statuses",
+	} {
+		set, err := stored(key)
+		if err != nil {
+			return fmt.Errorf("failed to read setting %s: %w", key, err)
+		}
+		if !set {
+			continue
+		}
+		switch key {
+		case "dedup.confidence_threshold":
+			c.ConfidenceThreshold, err = config.GetConfigFloat(ctx, r, key)
+		case "dedup.lookback_window":
+			c.LookbackWindow, err = config.GetConfigDuration(ctx, r, key)
+		case "dedup.max_candidates":
+			c.MaxCandidates, err = config.GetConfigInt(ctx, r, key)
+		case "dedup.scope":
+			var value string
+			value, err = config.GetConfigString(ctx, r, key)
+			c.Scope = Scope(value)
+		case "dedup.statuses":
+			var value string
+			value, err = config.GetConfigString(ctx, r, key)
+			c.Statuses = nil
+			for _, status := range config.SplitList(value) {
+				c.Statuses = append(c.Statuses, types.Status(status))
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scope returns the configured scope, ScopeAll when unset
+func (c Config) scope() Scope {
+	if c.Scope == "" {
+		return ScopeAll
+	}
+	return c.Scope
+}
+
+// statuses returns the statuses compared against, including closed when
+// IncludeClosedIssues is set
+func (c Config) statuses() []types.Status {
+	statuses := c.Statuses
+	if len(statuses) == 0 {
+		statuses = []types.Status{types.StatusOpen}
+	}
+	if c.IncludeClosedIssues {
+		for _, status := range statuses {
+			if status == types.StatusClosed {
+				return statuses
+			}
+		}
+		statuses = append(append([]types.Status{}, statuses...), types.StatusClosed)
+	}
+	return statuses
+}
+
+type scopeIssueKey struct{}
+
+// WithScopeIssue records the issue the candidates were found in, which the
+// epic and label scopes compare within
+func WithScopeIssue(ctx context.Context, issueID string) context.Context {
+	return context.WithValue(ctx, scopeIssueKey{}, issueID)
+}
+
+// scopeIssue returns the issue set by WithScopeIssue, "" if none
+func scopeIssue(ctx context.Context) string {
+	id, _ := ctx.Value(scopeIssueKey{}).(string)
+	return id
+}
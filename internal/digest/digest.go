@@ -0,0 +1,324 @@
+// Package digest builds the colony's digest for a period: the issues it
+// completed, the attempts that failed, what got blocked and what was
+// discovered, the AI spend and the top of the ready queue. It is what 'vc
+// summary' prints and what the executor records as a daily_summary event.
+//
+// Sections and their rows are always in the same order, so the digests of
+// two days can be diffed.
+package digest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// DefaultReadyLimit is how many issues of the ready queue a digest lists
+const DefaultReadyLimit = 5
+
+// Digest is the report of one period
+type Digest struct {
+	Since time.Time `json:"since"`
+	Until time.Time `json:"until"`
+
+	Completed  []*Item `json:"completed"`  // Closed in the period, oldest first
+	Failed     []*Item `json:"failed"`     // Issues with failed attempts in the period, by ID
+	Blocked    []*Item `json:"blocked"`    // Blocked now and updated in the period, by ID
+	Discovered []*Item `json:"discovered"` // Created in the period from other work, by ID
+	Ready      []*Item `json:"ready"`      // The top of the ready queue, in claim order
+
+	AI AIUsage `json:"ai"`
+}
+
+// Item is an issue in a section of the digest
+type Item struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Priority int    `json:"priority"`
+	// Detail is what the section says about the issue: the failed attempts,
+	// or the issue a discovered one came from
+	Detail string `json:"detail,omitempty"`
+}
+
+// AIUsage totals the period's ai_call_completed events
+type AIUsage struct {
+	Calls        int     `json:"calls"`
+	InputTokens  int64   `json:"input_tokens"`
+	OutputTokens int64   `json:"output_tokens"`
+	CostUSD      float64 `json:"estimated_cost_usd"`
+	Unpriced     int     `json:"unpriced_calls"` // Calls to models without a price, not in CostUSD
+}
+
+// Options scopes a digest
+type Options struct {
+	Project    string // Only this project's issues ("" for every project)
+	ReadyLimit int    // Ready issues to list (default: DefaultReadyLimit)
+}
+
+// Build assembles the digest of the period from since to until
+func Build(ctx context.Context, s storage.Storage, since, until time.Time, opts Options) (*Digest, error) {
+	if opts.ReadyLimit <= 0 {
+		opts.ReadyLimit = DefaultReadyLimit
+	}
+	d := &Digest{Since: since, Until: until}
+	inPeriod := func(t time.Time) bool { return !t.Before(since) && t.Before(until) }
+
+	issues, err := s.SearchIssues(ctx, "", types.IssueFilter{Project: opts.Project})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issues: %w", err)
+	}
+	byID := make(map[string]*types.Issue, len(issues))
+	var closed []*types.Issue
+	for _, issue := range issues {
+		byID[issue.ID] = issue
+		switch {
+		case issue.Status == types.StatusClosed && issue.ClosedAt != nil && inPeriod(*issue.ClosedAt):
+			closed = append(closed, issue)
+		case issue.Status == types.StatusBlocked && inPeriod(issue.UpdatedAt):
+			d.Blocked = append(d.Blocked, newItem(issue, ""))
+		}
+		if inPeriod(issue.CreatedAt) {
+			if origin := discoveredFrom(ctx, s, issue.ID); origin != "" {
+				d.Discovered = append(d.Discovered, newItem(issue, "from "+origin))
+			}
+		}
+	}
+	sort.Slice(closed, func(i, j int) bool {
+		if !closed[i].ClosedAt.Equal(*closed[j].ClosedAt) {
+			return closed[i].ClosedAt.Before(*closed[j].ClosedAt)
+		}
+		return closed[i].ID < closed[j].ID
+	})
+	for _, issue := range closed {
+		d.Completed = append(d.Completed, newItem(issue, ""))
+	}
+
+	attempts, err := s.GetExecutionPhases(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attempts: %w", err)
+	}
+	failures := make(map[string]int)
+	for _, attempt := range attempts {
+		if attempt.Success != nil && !*attempt.Success && inPeriod(attempt.StartedAt) && byID[attempt.IssueID] != nil {
+			failures[attempt.IssueID]++
+		}
+	}
+	for id, n := range failures {
+		d.Failed = append(d.Failed, newItem(byID[id], plural(n, "failed attempt")))
+	}
+
+	if d.AI, err = aiUsage(ctx, s, since, until); err != nil {
+		return nil, err
+	}
+
+	ready, err := s.GetReadyWork(ctx, types.WorkFilter{Status: types.StatusOpen, Limit: opts.ReadyLimit, Project: opts.Project})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ready work: %w", err)
+	}
+	for _, issue := range ready {
+		d.Ready = append(d.Ready, newItem(issue, ""))
+	}
+
+	for _, items := range [][]*Item{d.Failed, d.Blocked, d.Discovered} {
+		sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+	}
+	return d, nil
+}
+
+func newItem(issue *types.Issue, detail string) *Item {
+	return &Item{ID: issue.ID, Title: issue.Title, Priority: issue.Priority, Detail: detail}
+}
+
+// discoveredFrom returns the issue issueID was discovered from, if any
+func discoveredFrom(ctx context.Context, s storage.Storage, issueID string) string {
+	records, err := s.GetDependencyRecords(ctx, issueID)
+	if err != nil {
+		return ""
+	}
+	for _, record := range records {
+		if record.Type == types.DepDiscoveredFrom {
+			return record.DependsOnID
+		}
+	}
+	return ""
+}
+
+// aiUsage totals the ai_call_completed events of the period. Events with
+// unparseable data are skipped.
+func aiUsage(ctx context.Context, s storage.Storage, since, until time.Time) (AIUsage, error) {
+	var usage AIUsage
+	eventList, err := s.GetAgentEvents(ctx, events.EventFilter{Type: events.EventTypeAICallCompleted, AfterTime: since, BeforeTime: until})
+	if err != nil {
+		return usage, fmt.Errorf("failed to query %s events: %w", events.EventTypeAICallCompleted, err)
+	}
+	for _, event := range eventList {
+		data, err := event.GetAICallData()
+		if err != nil {
+			continue
+		}
+		usage.Calls++
+		usage.InputTokens += data.InputTokens
+		usage.OutputTokens += data.OutputTokens
+		if data.Priced {
+			usage.CostUSD += data.EstimatedCostUSD
+		} else {
+			usage.Unpriced++
+		}
+	}
+	return usage, nil
+}
+
+// section is one list of the digest, in the order they're written
+type section struct {
+	title string
+	items []*Item
+}
+
+func (d *Digest) sections() []section {
+	return []section{
+		{"Completed", d.Completed},
+		{"Failed", d.Failed},
+		{"Blocked", d.Blocked},
+		{"Discovered", d.Discovered},
+	}
+}
+
+// Headline is the digest in one line, e.g. "3 completed, 1 failed, 0
+// blocked, 2 discovered, $1.20 AI spend"
+func (d *Digest) Headline() string {
+	return fmt.Sprintf("%d completed, %d failed, %d blocked, %d discovered, %s AI spend",
+		len(d.Completed), len(d.Failed), len(d.Blocked), len(d.Discovered), formatCost(d.AI.CostUSD))
+}
+
+// period renders the digest's period in UTC
+func (d *Digest) period() string {
+	const layout = "2006-01-02 15:04"
+	return fmt.Sprintf("%s to %s UTC", d.Since.UTC().Format(layout), d.Until.UTC().Format(layout))
+}
+
+// aiLine renders the AI usage
+func (d *Digest) aiLine() string {
+	line := fmt.Sprintf("%s, %s tokens in, %s out, %s", plural(d.AI.Calls, "call"),
+		formatTokens(d.AI.InputTokens), formatTokens(d.AI.OutputTokens), formatCost(d.AI.CostUSD))
+	if d.AI.Unpriced > 0 {
+		line += fmt.Sprintf(" (%s to unpriced models)", plural(d.AI.Unpriced, "call"))
+	}
+	return line
+}
+
+// WriteText renders the digest for a terminal
+func (d *Digest) WriteText(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "VC summary, %s\n%s\n", d.period(), d.Headline())
+	for _, s := range d.sections() {
+		writeTextSection(&b, fmt.Sprintf("%s (%d)", s.title, len(s.items)), s.items)
+	}
+	fmt.Fprintf(&b, "\nAI usage\n  %s\n", d.aiLine())
+	writeTextSection(&b, fmt.Sprintf("Ready queue (top %d)", len(d.Ready)), d.Ready)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeTextSection(b *strings.Builder, title string, items []*Item) {
+	fmt.Fprintf(b, "\n%s\n", title)
+	if len(items) == 0 {
+		b.WriteString("  none\n")
+	}
+	for _, item := range items {
+		fmt.Fprintf(b, "  %s  P%d  %s", item.ID, item.Priority, item.Title)
+		if item.Detail != "" {
+			fmt.Fprintf(b, " (%s)", item.Detail)
+		}
+		b.WriteByte('\n')
+	}
+}
+
+// WriteMarkdown renders the digest for pasting into chat
+func (d *Digest) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## VC summary, %s\n\n%s\n", d.period(), d.Headline())
+	for _, s := range d.sections() {
+		writeMarkdownSection(&b, fmt.Sprintf("%s (%d)", s.title, len(s.items)), s.items)
+	}
+	fmt.Fprintf(&b, "\n### AI usage\n\n%s\n", d.aiLine())
+	writeMarkdownSection(&b, fmt.Sprintf("Ready queue (top %d)", len(d.Ready)), d.Ready)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeMarkdownSection(b *strings.Builder, title string, items []*Item) {
+	fmt.Fprintf(b, "\n### %s\n\n", title)
+	if len(items) == 0 {
+		b.WriteString("None\n")
+		return
+	}
+	for _, item := range items {
+		fmt.Fprintf(b, "- **%s** P%d %s", item.ID, item.Priority, item.Title)
+		if item.Detail != "" {
+			fmt.Fprintf(b, " (%s)", item.Detail)
+		}
+		b.WriteByte('\n')
+	}
+}
+
+// Markdown returns the markdown rendering of the digest
+func (d *Digest) Markdown() string {
+	var b strings.Builder
+	_ = d.WriteMarkdown(&b)
+	return b.String()
+}
+
+// Post sends text to a chat webhook as a JSON {"text": ...} body, the
+// payload Slack, Mattermost and most chat incoming webhooks accept
+func Post(ctx context.Context, url, text string) error {
+	body, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post the summary: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("failed to post the summary: webhook answered %s", resp.Status)
+	}
+	return nil
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// formatTokens renders a token count compactly, e.g. 1.2M or 340k
+func formatTokens(n int64) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1e6)
+	case n >= 1_000:
+		return fmt.Sprintf("%.0fk", float64(n)/1e3)
+	}
+	return fmt.Sprintf("%d", n)
+}
+
+func formatCost(usd float64) string {
+	return fmt.Sprintf("$%.2f", usd)
+}
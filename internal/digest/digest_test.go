@@ -0,0 +1,123 @@
+package digest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage/storagetest"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestBuild(t *testing.T) {
+	ctx := context.Background()
+	s := storagetest.NewFakeStorage()
+	create := func(title string, priority int) *types.Issue {
+		t.Helper()
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: priority, IssueType: types.TypeTask}
+		if err := s.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue: %v", err)
+		}
+		return issue
+	}
+
+	done := create("Fix login", 1)
+	if err := s.CloseIssue(ctx, done.ID, "fixed", "test"); err != nil {
+		t.Fatalf("CloseIssue: %v", err)
+	}
+	stuck := create("Migrate the schema", 2)
+	if err := s.UpdateIssue(ctx, stuck.ID, map[string]interface{}{"status": string(types.StatusBlocked)}, "test"); err != nil {
+		t.Fatalf("UpdateIssue: %v", err)
+	}
+	found := create("Login page leaks the session", 0)
+	if err := s.AddDependency(ctx, &types.Dependency{IssueID: found.ID, DependsOnID: done.ID, Type: types.DepDiscoveredFrom}, "test"); err != nil {
+		t.Fatalf("AddDependency: %v", err)
+	}
+	flaky := create("Flaky test", 3)
+	failed := false
+	for i := 1; i <= 2; i++ {
+		attempt := &types.ExecutionAttempt{IssueID: flaky.ID, ExecutorInstanceID: "exec-1", AttemptNumber: i, StartedAt: time.Now(), Success: &failed,
+			Phases: []types.PhaseTiming{{Phase: types.ExecutionPhaseAgent, DurationMs: 1000}}}
+		if err := s.RecordExecutionAttempt(ctx, attempt); err != nil {
+			t.Fatalf("RecordExecutionAttempt: %v", err)
+		}
+	}
+	call, err := events.NewAICallCompletedEvent(flaky.ID, "call", events.AICallData{InputTokens: 12000, OutputTokens: 800, EstimatedCostUSD: 0.42, Priced: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.StoreAgentEvent(ctx, call); err != nil {
+		t.Fatalf("StoreAgentEvent: %v", err)
+	}
+
+	since, until := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+	d, err := Build(ctx, s, since, until, Options{})
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if got, want := d.Headline(), "1 completed, 1 failed, 1 blocked, 1 discovered, $0.42 AI spend"; got != want {
+		t.Errorf("Headline() = %q, want %q", got, want)
+	}
+	if d.Failed[0].Detail != "2 failed attempts" || d.Discovered[0].Detail != "from "+done.ID {
+		t.Errorf("Unexpected details: %+v, %+v", d.Failed[0], d.Discovered[0])
+	}
+	if len(d.Ready) == 0 || d.Ready[0].ID != found.ID {
+		t.Errorf("Expected the P0 issue at the top of the ready queue, got %+v", d.Ready)
+	}
+
+	markdown := d.Markdown()
+	for _, want := range []string{
+		"### Completed (1)\n\n- **" + done.ID + "** P1 Fix login\n",
+		"### Blocked (1)\n\n- **" + stuck.ID + "** P2 Migrate the schema\n",
+		"### AI usage\n\n1 call, 12k tokens in, 800 out, $0.42\n",
+	} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("Expected %q in:\n%s", want, markdown)
+		}
+	}
+
+	// The same data renders the same digest
+	again, err := Build(ctx, s, since, until, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Markdown() != markdown {
+		t.Error("Expected the digest to render identically twice")
+	}
+
+	var text bytes.Buffer
+	if err := (&Digest{Since: since, Until: until}).WriteText(&text); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(text.String(), "Completed (0)\n  none\n") {
+		t.Errorf("Expected empty sections spelled out, got:\n%s", text.String())
+	}
+}
+
+func TestPost(t *testing.T) {
+	var got map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer server.Close()
+	if err := Post(context.Background(), server.URL, "## VC summary"); err != nil {
+		t.Fatalf("Post: %v", err)
+	}
+	if got["text"] != "## VC summary" {
+		t.Errorf("Expected the text posted, got %v", got)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer failing.Close()
+	if err := Post(context.Background(), failing.URL, "x"); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("Expected the webhook's status in the error, got %v", err)
+	}
+}
@@ -0,0 +1,229 @@
+// Package estimates compares the time issues actually took with their
+// estimates. An issue's actual time is the duration of its completed
+// execution attempts plus the time logged on it by hand (vc time).
+//
+// 'vc show' prints an issue's actual time next to its estimate, 'vc stats
+// --estimates' reports the median actual/estimate ratio by issue type and by
+// estimate size, and the executor tells the assessment how long similar past
+// issues took against their estimates.
+package estimates
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// MinSamples is how many closed issues a group needs for its ratio to be
+// reported as a hint
+const MinSamples = 3
+
+// Actual is the working time spent on an issue
+type Actual struct {
+	Attempts      int           `json:"attempts"`       // Completed execution attempts
+	AttemptTime   time.Duration `json:"attempt_time"`   // Their total duration
+	Entries       int           `json:"entries"`        // Time entries logged by hand
+	ManualMinutes int           `json:"manual_minutes"` // Their total
+}
+
+// Total is the attempt time plus the time logged by hand
+func (a *Actual) Total() time.Duration {
+	return a.AttemptTime + time.Duration(a.ManualMinutes)*time.Minute
+}
+
+// Minutes is Total rounded to whole minutes
+func (a *Actual) Minutes() int {
+	return int(math.Round(a.Total().Minutes()))
+}
+
+// ActualTime sums the issue's completed execution attempts and time entries
+func ActualTime(ctx context.Context, s storage.Storage, issueID string) (*Actual, error) {
+	attempts, err := s.GetExecutionHistory(ctx, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get execution history of %s: %w", issueID, err)
+	}
+	entries, err := s.ListTimeEntries(ctx, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get time entries of %s: %w", issueID, err)
+	}
+	actual := &Actual{Entries: len(entries)}
+	for _, attempt := range attempts {
+		if attempt.CompletedAt == nil || attempt.CompletedAt.Before(attempt.StartedAt) {
+			continue
+		}
+		actual.Attempts++
+		actual.AttemptTime += attempt.CompletedAt.Sub(attempt.StartedAt)
+	}
+	for _, entry := range entries {
+		actual.ManualMinutes += entry.Minutes
+	}
+	return actual, nil
+}
+
+// Sample is a closed issue with both an estimate and some actual time
+type Sample struct {
+	IssueID   string          `json:"issue_id"`
+	IssueType types.IssueType `json:"issue_type"`
+	Estimate  int             `json:"estimated_minutes"`
+	Actual    float64         `json:"actual_minutes"`
+}
+
+// Ratio is the actual time over the estimate (2 = took twice as long)
+func (s Sample) Ratio() float64 {
+	return s.Actual / float64(s.Estimate)
+}
+
+// Samples returns the issues matching filter closed at or after since that
+// have an estimate and some actual time. The filter's status is overridden.
+func Samples(ctx context.Context, s storage.Storage, filter types.IssueFilter, since time.Time) ([]Sample, error) {
+	closed := types.StatusClosed
+	filter.Status = &closed
+	issues, err := s.SearchIssues(ctx, "", filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list closed issues: %w", err)
+	}
+	var samples []Sample
+	for _, issue := range issues {
+		if issue.EstimatedMinutes == nil || *issue.EstimatedMinutes <= 0 || issue.ClosedAt == nil || issue.ClosedAt.Before(since) {
+			continue
+		}
+		actual, err := ActualTime(ctx, s, issue.ID)
+		if err != nil {
+			return nil, err
+		}
+		if actual.Total() <= 0 {
+			continue
+		}
+		samples = append(samples, Sample{
+			IssueID:   issue.ID,
+			IssueType: issue.IssueType,
+			Estimate:  *issue.EstimatedMinutes,
+			Actual:    actual.Total().Minutes(),
+		})
+	}
+	return samples, nil
+}
+
+// Buckets of estimate sizes, smallest first
+var Buckets = []string{"up to 30m", "30m-2h", "2h-8h", "over 8h"}
+
+// Bucket returns the bucket of an estimate in minutes
+func Bucket(minutes int) string {
+	switch {
+	case minutes <= 30:
+		return Buckets[0]
+	case minutes <= 120:
+		return Buckets[1]
+	case minutes <= 480:
+		return Buckets[2]
+	}
+	return Buckets[3]
+}
+
+// Group summarizes the ratios of a set of samples. Percentiles use the
+// nearest-rank method, like the flow and phase statistics.
+type Group struct {
+	Name        string  `json:"name"`
+	Issues      int     `json:"issues"`
+	MedianRatio float64 `json:"median_ratio"`
+	P25Ratio    float64 `json:"p25_ratio"`
+	P75Ratio    float64 `json:"p75_ratio"`
+}
+
+// Summarize returns the group of samples under name, or nil for none
+func Summarize(name string, samples []Sample) *Group {
+	if len(samples) == 0 {
+		return nil
+	}
+	ratios := make([]float64, len(samples))
+	for i, sample := range samples {
+		ratios[i] = sample.Ratio()
+	}
+	slices.Sort(ratios)
+	percentile := func(p float64) float64 {
+		rank := max(int(math.Ceil(p*float64(len(ratios)))), 1)
+		return ratios[rank-1]
+	}
+	return &Group{
+		Name:        name,
+		Issues:      len(ratios),
+		MedianRatio: percentile(0.5),
+		P25Ratio:    percentile(0.25),
+		P75Ratio:    percentile(0.75),
+	}
+}
+
+// Report is the estimate accuracy of a set of closed issues
+type Report struct {
+	Overall  *Group   `json:"overall"`
+	ByType   []*Group `json:"by_type"`   // By issue type name
+	ByBucket []*Group `json:"by_bucket"` // In the order of Buckets
+}
+
+// NewReport groups samples by issue type and by estimate bucket. Empty
+// groups are left out.
+func NewReport(samples []Sample) *Report {
+	r := &Report{Overall: Summarize("all", samples)}
+	byType := make(map[types.IssueType][]Sample)
+	byBucket := make(map[string][]Sample)
+	for _, sample := range samples {
+		byType[sample.IssueType] = append(byType[sample.IssueType], sample)
+		byBucket[Bucket(sample.Estimate)] = append(byBucket[Bucket(sample.Estimate)], sample)
+	}
+	issueTypes := make([]string, 0, len(byType))
+	for issueType := range byType {
+		issueTypes = append(issueTypes, string(issueType))
+	}
+	slices.Sort(issueTypes)
+	for _, issueType := range issueTypes {
+		r.ByType = append(r.ByType, Summarize(issueType, byType[types.IssueType(issueType)]))
+	}
+	for _, bucket := range Buckets {
+		if group := Summarize(bucket, byBucket[bucket]); group != nil {
+			r.ByBucket = append(r.ByBucket, group)
+		}
+	}
+	return r
+}
+
+// Hint tells how long closed issues like issue took against their estimates,
+// e.g. "Similar past issues (4 closed tasks estimated 30m-2h) took 2.1x their
+// estimate (median; middle half 1.6x-2.8x)". It prefers issues of the same
+// type and estimate bucket, then of the same type, and returns "" if neither
+// has MinSamples samples.
+func Hint(issue *types.Issue, samples []Sample) string {
+	var sameType, sameBucket []Sample
+	for _, sample := range samples {
+		if sample.IssueID == issue.ID || sample.IssueType != issue.IssueType {
+			continue
+		}
+		sameType = append(sameType, sample)
+		if issue.EstimatedMinutes != nil && Bucket(sample.Estimate) == Bucket(*issue.EstimatedMinutes) {
+			sameBucket = append(sameBucket, sample)
+		}
+	}
+	var group *Group
+	var what string
+	switch {
+	case len(sameBucket) >= MinSamples:
+		group = Summarize("", sameBucket)
+		what = fmt.Sprintf("%d closed %ss estimated %s", group.Issues, issue.IssueType, Bucket(*issue.EstimatedMinutes))
+	case len(sameType) >= MinSamples:
+		group = Summarize("", sameType)
+		what = fmt.Sprintf("%d closed %ss", group.Issues, issue.IssueType)
+	default:
+		return ""
+	}
+	return fmt.Sprintf("Similar past issues (%s) took %s their estimate (median; middle half %s-%s)",
+		what, FormatRatio(group.MedianRatio), FormatRatio(group.P25Ratio), FormatRatio(group.P75Ratio))
+}
+
+// FormatRatio renders a ratio like 2.1x
+func FormatRatio(ratio float64) string {
+	return fmt.Sprintf("%.1fx", ratio)
+}
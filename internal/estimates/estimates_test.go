@@ -0,0 +1,117 @@
+package estimates
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/storage/storagetest"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestActualTimeAndSamples(t *testing.T) {
+	ctx := context.Background()
+	s := storagetest.NewFakeStorage()
+	create := func(title string, issueType types.IssueType, estimate int) *types.Issue {
+		t.Helper()
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 2, IssueType: issueType, EstimatedMinutes: &estimate}
+		if err := s.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue: %v", err)
+		}
+		return issue
+	}
+
+	done := create("Fix login", types.TypeBug, 60)
+	start := time.Now().Add(-3 * time.Hour)
+	end := start.Add(90 * time.Minute)
+	for i, completedAt := range []*time.Time{&end, nil} {
+		attempt := &types.ExecutionAttempt{IssueID: done.ID, ExecutorInstanceID: "exec-1", AttemptNumber: i + 1,
+			StartedAt: start, CompletedAt: completedAt}
+		if err := s.RecordExecutionAttempt(ctx, attempt); err != nil {
+			t.Fatalf("RecordExecutionAttempt: %v", err)
+		}
+	}
+	if err := s.AddTimeEntry(ctx, &types.TimeEntry{IssueID: done.ID, Minutes: 30, Actor: "alice"}); err != nil {
+		t.Fatalf("AddTimeEntry: %v", err)
+	}
+	if err := s.CloseIssue(ctx, done.ID, "fixed", "test"); err != nil {
+		t.Fatalf("CloseIssue: %v", err)
+	}
+	open := create("Still open", types.TypeBug, 60)
+	untracked := create("Closed without any time", types.TypeBug, 60)
+	if err := s.CloseIssue(ctx, untracked.ID, "fixed", "test"); err != nil {
+		t.Fatalf("CloseIssue: %v", err)
+	}
+
+	actual, err := ActualTime(ctx, s, done.ID)
+	if err != nil {
+		t.Fatalf("ActualTime: %v", err)
+	}
+	// The attempt that never completed doesn't count
+	if actual.Attempts != 1 || actual.AttemptTime != 90*time.Minute || actual.Entries != 1 || actual.Minutes() != 120 {
+		t.Errorf("ActualTime = %+v, want one 90 minute attempt and 30 minutes logged", actual)
+	}
+
+	samples, err := Samples(ctx, s, types.IssueFilter{}, time.Time{})
+	if err != nil {
+		t.Fatalf("Samples: %v", err)
+	}
+	if len(samples) != 1 || samples[0].IssueID != done.ID || samples[0].Ratio() != 2 {
+		t.Errorf("Samples = %+v, want only %s with a ratio of 2 (not %s or %s)", samples, done.ID, open.ID, untracked.ID)
+	}
+	if samples, err := Samples(ctx, s, types.IssueFilter{}, time.Now().Add(time.Hour)); err != nil || len(samples) != 0 {
+		t.Errorf("Samples after the closes: got (%+v, %v), want none", samples, err)
+	}
+}
+
+func TestNewReport(t *testing.T) {
+	samples := []Sample{
+		{IssueID: "vc-1", IssueType: types.TypeBug, Estimate: 30, Actual: 60},
+		{IssueID: "vc-2", IssueType: types.TypeBug, Estimate: 60, Actual: 180},
+		{IssueID: "vc-3", IssueType: types.TypeTask, Estimate: 600, Actual: 300},
+	}
+	r := NewReport(samples)
+	if r.Overall.Issues != 3 || r.Overall.MedianRatio != 2 {
+		t.Errorf("Overall = %+v, want 3 issues with a median of 2", r.Overall)
+	}
+	if len(r.ByType) != 2 || r.ByType[0].Name != "bug" || r.ByType[0].Issues != 2 || r.ByType[1].MedianRatio != 0.5 {
+		t.Errorf("ByType = %+v", r.ByType)
+	}
+	var buckets []string
+	for _, group := range r.ByBucket {
+		buckets = append(buckets, group.Name)
+	}
+	if got := strings.Join(buckets, ","); got != "up to 30m,30m-2h,over 8h" {
+		t.Errorf("ByBucket = %s, want the non-empty buckets smallest first", got)
+	}
+}
+
+func TestHint(t *testing.T) {
+	samples := []Sample{
+		{IssueID: "vc-1", IssueType: types.TypeTask, Estimate: 60, Actual: 120},
+		{IssueID: "vc-2", IssueType: types.TypeTask, Estimate: 60, Actual: 180},
+		{IssueID: "vc-3", IssueType: types.TypeTask, Estimate: 90, Actual: 225},
+		{IssueID: "vc-4", IssueType: types.TypeTask, Estimate: 600, Actual: 600},
+		{IssueID: "vc-5", IssueType: types.TypeBug, Estimate: 60, Actual: 60},
+	}
+	hour := 60
+	tests := []struct {
+		name  string
+		issue *types.Issue
+		want  string
+	}{
+		{"same bucket", &types.Issue{ID: "vc-9", IssueType: types.TypeTask, EstimatedMinutes: &hour},
+			"Similar past issues (3 closed tasks estimated 30m-2h) took 2.5x their estimate (median; middle half 2.0x-3.0x)"},
+		{"same type without an estimate", &types.Issue{ID: "vc-9", IssueType: types.TypeTask},
+			"Similar past issues (4 closed tasks) took 2.0x their estimate (median; middle half 1.0x-2.5x)"},
+		{"too little history", &types.Issue{ID: "vc-9", IssueType: types.TypeBug, EstimatedMinutes: &hour}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Hint(tt.issue, samples); got != tt.want {
+				t.Errorf("Hint() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
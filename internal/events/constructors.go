@@ -230,3 +230,265 @@ func NewEpicCleanupCompletedEvent(issueID, executorID, agentID string, severity
 	}
 	return event, nil
 }
+
+// NewSandboxLifecycleEvent creates a new AgentEvent for a sandbox lifecycle metric with type-safe data.
+// eventType must be one of EventTypeSandboxCreated, EventTypeSandboxCleaned, or EventTypeSandboxCleanupFailed.
+func NewSandboxLifecycleEvent(eventType EventType, issueID, executorID, agentID string, severity EventSeverity, message string, data SandboxLifecycleData) (*AgentEvent, error) {
+	event := &AgentEvent{
+		ID:         uuid.New().String(),
+		Type:       eventType,
+		Timestamp:  time.Now(),
+		IssueID:    issueID,
+		ExecutorID: executorID,
+		AgentID:    agentID,
+		Severity:   severity,
+		Message:    message,
+		SourceLine: 0,
+	}
+	if err := event.SetSandboxLifecycleData(data); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// NewStorageSlowQueriesEvent creates a new SYSTEM AgentEvent recording a process's slow queries with type-safe data.
+func NewStorageSlowQueriesEvent(message string, data StorageSlowQueriesData) (*AgentEvent, error) {
+	event := &AgentEvent{
+		ID:         uuid.New().String(),
+		Type:       EventTypeStorageSlowQueries,
+		Timestamp:  time.Now(),
+		IssueID:    "SYSTEM",
+		Severity:   SeverityWarning,
+		Message:    message,
+		SourceLine: 0,
+	}
+	if err := event.SetStorageSlowQueriesData(data); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// NewDailySummaryEvent creates a new SYSTEM AgentEvent carrying the daily digest with type-safe data.
+func NewDailySummaryEvent(executorID string, message string, data DailySummaryData) (*AgentEvent, error) {
+	event := &AgentEvent{
+		ID:         uuid.New().String(),
+		Type:       EventTypeDailySummary,
+		Timestamp:  time.Now(),
+		IssueID:    "SYSTEM",
+		ExecutorID: executorID,
+		Severity:   SeverityInfo,
+		Message:    message,
+		SourceLine: 0,
+	}
+	if err := event.SetDailySummaryData(data); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// NewAICallCompletedEvent creates a new AgentEvent recording the usage of an AI call with type-safe data.
+// issueID is the issue the call was about, or "SYSTEM".
+func NewAICallCompletedEvent(issueID string, message string, data AICallData) (*AgentEvent, error) {
+	event := &AgentEvent{
+		ID:         uuid.New().String(),
+		Type:       EventTypeAICallCompleted,
+		Timestamp:  time.Now(),
+		IssueID:    issueID,
+		Severity:   SeverityInfo,
+		Message:    message,
+		SourceLine: 0,
+	}
+	if err := event.SetAICallData(data); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// NewFailureAnalyzedEvent creates a new AgentEvent for the AI supervisor's diagnosis of a failed attempt.
+func NewFailureAnalyzedEvent(issueID string, message string, data FailureAnalysisData) (*AgentEvent, error) {
+	event := &AgentEvent{
+		ID:         uuid.New().String(),
+		Type:       EventTypeFailureAnalyzed,
+		Timestamp:  time.Now(),
+		IssueID:    issueID,
+		Severity:   SeverityInfo,
+		Message:    message,
+		SourceLine: 0,
+	}
+	if err := event.SetFailureAnalysisData(data); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// NewMergeDecisionEvent creates a new AgentEvent for an auto-merge decision.
+// Changes held for review are a warning.
+func NewMergeDecisionEvent(issueID string, message string, data MergeDecisionData) (*AgentEvent, error) {
+	severity := SeverityInfo
+	if !data.AutoMerged {
+		severity = SeverityWarning
+	}
+	event := &AgentEvent{
+		ID:         uuid.New().String(),
+		Type:       EventTypeMergeDecision,
+		Timestamp:  time.Now(),
+		IssueID:    issueID,
+		Severity:   severity,
+		Message:    message,
+		SourceLine: 0,
+	}
+	if err := event.SetMergeDecisionData(data); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// NewAIResponseRepairedEvent creates a new AgentEvent for a structured AI response that parsed only after repair.
+func NewAIResponseRepairedEvent(issueID string, message string, data AIResponseRepairData) (*AgentEvent, error) {
+	return newAIResponseRepairEvent(EventTypeAIResponseRepaired, SeverityInfo, issueID, message, data)
+}
+
+// NewAIResponseRetriedEvent creates a new AgentEvent for an unusable structured AI response asked for again.
+// A retry that failed as well is a warning.
+func NewAIResponseRetriedEvent(issueID string, message string, data AIResponseRepairData) (*AgentEvent, error) {
+	severity := SeverityInfo
+	if !data.Success {
+		severity = SeverityWarning
+	}
+	return newAIResponseRepairEvent(EventTypeAIResponseRetried, severity, issueID, message, data)
+}
+
+func newAIResponseRepairEvent(eventType EventType, severity EventSeverity, issueID string, message string, data AIResponseRepairData) (*AgentEvent, error) {
+	event := &AgentEvent{
+		ID:         uuid.New().String(),
+		Type:       eventType,
+		Timestamp:  time.Now(),
+		IssueID:    issueID,
+		Severity:   severity,
+		Message:    message,
+		SourceLine: 0,
+	}
+	if err := event.SetAIResponseRepairData(data); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// NewExecutionTelemetryEvent creates a new AgentEvent for an execution telemetry snapshot with type-safe data.
+func NewExecutionTelemetryEvent(issueID, executorID, agentID string, severity EventSeverity, message string, data ExecutionTelemetryData) (*AgentEvent, error) {
+	event := &AgentEvent{
+		ID:         uuid.New().String(),
+		Type:       EventTypeExecutionTelemetry,
+		Timestamp:  time.Now(),
+		IssueID:    issueID,
+		ExecutorID: executorID,
+		AgentID:    agentID,
+		Severity:   severity,
+		Message:    message,
+		SourceLine: 0,
+	}
+	if err := event.SetExecutionTelemetryData(data); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// NewVacuumEvent creates a new AgentEvent for a vacuum_started or vacuum_completed event with type-safe data.
+func NewVacuumEvent(eventType EventType, issueID, executorID, agentID string, severity EventSeverity, message string, data VacuumData) (*AgentEvent, error) {
+	event := &AgentEvent{
+		ID:         uuid.New().String(),
+		Type:       eventType,
+		Timestamp:  time.Now(),
+		IssueID:    issueID,
+		ExecutorID: executorID,
+		AgentID:    agentID,
+		Severity:   severity,
+		Message:    message,
+		SourceLine: 0,
+	}
+	if err := event.SetVacuumData(data); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// NewMissionApprovalEvent creates a new AgentEvent for a mission plan approval or rejection with type-safe data.
+// The event type follows data.Approved: EventTypeMissionApproved or EventTypeMissionRejected.
+func NewMissionApprovalEvent(issueID, executorID, agentID string, severity EventSeverity, message string, data MissionApprovalData) (*AgentEvent, error) {
+	eventType := EventTypeMissionRejected
+	if data.Approved {
+		eventType = EventTypeMissionApproved
+	}
+	event := &AgentEvent{
+		ID:         uuid.New().String(),
+		Type:       eventType,
+		Timestamp:  time.Now(),
+		IssueID:    issueID,
+		ExecutorID: executorID,
+		AgentID:    agentID,
+		Severity:   severity,
+		Message:    message,
+		SourceLine: 0,
+	}
+	if err := event.SetMissionApprovalData(data); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// NewConfigChangedEvent creates a new AgentEvent for a config_changed event with type-safe data.
+func NewConfigChangedEvent(issueID, executorID, agentID string, severity EventSeverity, message string, data ConfigChangedData) (*AgentEvent, error) {
+	event := &AgentEvent{
+		ID:         uuid.New().String(),
+		Type:       EventTypeConfigChanged,
+		Timestamp:  time.Now(),
+		IssueID:    issueID,
+		ExecutorID: executorID,
+		AgentID:    agentID,
+		Severity:   severity,
+		Message:    message,
+		SourceLine: 0,
+	}
+	if err := event.SetConfigChangedData(data); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// NewIssueAutoBlockedEvent creates a new AgentEvent for an issue_auto_blocked event with type-safe data.
+func NewIssueAutoBlockedEvent(issueID, executorID, agentID string, severity EventSeverity, message string, data IssueAutoBlockedData) (*AgentEvent, error) {
+	event := &AgentEvent{
+		ID:         uuid.New().String(),
+		Type:       EventTypeIssueAutoBlocked,
+		Timestamp:  time.Now(),
+		IssueID:    issueID,
+		ExecutorID: executorID,
+		AgentID:    agentID,
+		Severity:   severity,
+		Message:    message,
+		SourceLine: 0,
+	}
+	if err := event.SetIssueAutoBlockedData(data); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// NewIssueUnblockedEvent creates a new AgentEvent for an issue_unblocked event with type-safe data.
+func NewIssueUnblockedEvent(issueID, executorID, agentID string, severity EventSeverity, message string, data IssueUnblockedData) (*AgentEvent, error) {
+	event := &AgentEvent{
+		ID:         uuid.New().String(),
+		Type:       EventTypeIssueUnblocked,
+		Timestamp:  time.Now(),
+		IssueID:    issueID,
+		ExecutorID: executorID,
+		AgentID:    agentID,
+		Severity:   severity,
+		Message:    message,
+		SourceLine: 0,
+	}
+	if err := event.SetIssueUnblockedData(data); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
@@ -3,6 +3,7 @@ package events
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
 )
 
 // SetFileModifiedData sets the Data field with FileModifiedData in a type-safe way.
@@ -273,3 +274,288 @@ func (e *AgentEvent) GetEpicCleanupCompletedData() (*EpicCleanupCompletedData, e
 	}
 	return &data, nil
 }
+
+// SetSandboxLifecycleData sets the Data field with SandboxLifecycleData in a type-safe way.
+func (e *AgentEvent) SetSandboxLifecycleData(data SandboxLifecycleData) error {
+	dataMap, err := structToMap(data)
+	if err != nil {
+		return fmt.Errorf("failed to convert SandboxLifecycleData: %w", err)
+	}
+	e.Data = dataMap
+	return nil
+}
+
+// GetSandboxLifecycleData retrieves SandboxLifecycleData from the Data field.
+func (e *AgentEvent) GetSandboxLifecycleData() (*SandboxLifecycleData, error) {
+	var data SandboxLifecycleData
+	if err := mapToStruct(e.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse SandboxLifecycleData: %w", err)
+	}
+	return &data, nil
+}
+
+// SetAICallData sets the Data field with AICallData in a type-safe way.
+func (e *AgentEvent) SetAICallData(data AICallData) error {
+	dataMap, err := structToMap(data)
+	if err != nil {
+		return fmt.Errorf("failed to convert AICallData: %w", err)
+	}
+	e.Data = dataMap
+	return nil
+}
+
+// GetAICallData retrieves AICallData from the Data field.
+func (e *AgentEvent) GetAICallData() (*AICallData, error) {
+	var data AICallData
+	if err := mapToStruct(e.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse AICallData: %w", err)
+	}
+	return &data, nil
+}
+
+// SetStorageSlowQueriesData sets the Data field with StorageSlowQueriesData in a type-safe way.
+func (e *AgentEvent) SetStorageSlowQueriesData(data StorageSlowQueriesData) error {
+	dataMap, err := structToMap(data)
+	if err != nil {
+		return fmt.Errorf("failed to convert StorageSlowQueriesData: %w", err)
+	}
+	e.Data = dataMap
+	return nil
+}
+
+// GetStorageSlowQueriesData retrieves StorageSlowQueriesData from the Data field.
+func (e *AgentEvent) GetStorageSlowQueriesData() (*StorageSlowQueriesData, error) {
+	var data StorageSlowQueriesData
+	if err := mapToStruct(e.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse StorageSlowQueriesData: %w", err)
+	}
+	return &data, nil
+}
+
+// SetDailySummaryData sets the Data field with DailySummaryData in a type-safe way.
+func (e *AgentEvent) SetDailySummaryData(data DailySummaryData) error {
+	dataMap, err := structToMap(data)
+	if err != nil {
+		return fmt.Errorf("failed to convert DailySummaryData: %w", err)
+	}
+	e.Data = dataMap
+	return nil
+}
+
+// GetDailySummaryData retrieves DailySummaryData from the Data field.
+func (e *AgentEvent) GetDailySummaryData() (*DailySummaryData, error) {
+	var data DailySummaryData
+	if err := mapToStruct(e.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse DailySummaryData: %w", err)
+	}
+	return &data, nil
+}
+
+// SetFailureAnalysisData sets the Data field with FailureAnalysisData in a type-safe way.
+func (e *AgentEvent) SetFailureAnalysisData(data FailureAnalysisData) error {
+	dataMap, err := structToMap(data)
+	if err != nil {
+		return fmt.Errorf("failed to convert FailureAnalysisData: %w", err)
+	}
+	e.Data = dataMap
+	return nil
+}
+
+// GetFailureAnalysisData retrieves FailureAnalysisData from the Data field.
+func (e *AgentEvent) GetFailureAnalysisData() (*FailureAnalysisData, error) {
+	var data FailureAnalysisData
+	if err := mapToStruct(e.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse FailureAnalysisData: %w", err)
+	}
+	return &data, nil
+}
+
+// SetMergeDecisionData sets the Data field with MergeDecisionData in a type-safe way.
+func (e *AgentEvent) SetMergeDecisionData(data MergeDecisionData) error {
+	dataMap, err := structToMap(data)
+	if err != nil {
+		return fmt.Errorf("failed to convert MergeDecisionData: %w", err)
+	}
+	e.Data = dataMap
+	return nil
+}
+
+// GetMergeDecisionData retrieves MergeDecisionData from the Data field.
+func (e *AgentEvent) GetMergeDecisionData() (*MergeDecisionData, error) {
+	var data MergeDecisionData
+	if err := mapToStruct(e.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse MergeDecisionData: %w", err)
+	}
+	return &data, nil
+}
+
+// SetAIResponseRepairData sets the Data field with AIResponseRepairData in a type-safe way.
+func (e *AgentEvent) SetAIResponseRepairData(data AIResponseRepairData) error {
+	dataMap, err := structToMap(data)
+	if err != nil {
+		return fmt.Errorf("failed to convert AIResponseRepairData: %w", err)
+	}
+	e.Data = dataMap
+	return nil
+}
+
+// GetAIResponseRepairData retrieves AIResponseRepairData from the Data field.
+func (e *AgentEvent) GetAIResponseRepairData() (*AIResponseRepairData, error) {
+	var data AIResponseRepairData
+	if err := mapToStruct(e.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse AIResponseRepairData: %w", err)
+	}
+	return &data, nil
+}
+
+// SetVacuumData sets the Data field with VacuumData in a type-safe way.
+func (e *AgentEvent) SetVacuumData(data VacuumData) error {
+	dataMap, err := structToMap(data)
+	if err != nil {
+		return fmt.Errorf("failed to convert VacuumData: %w", err)
+	}
+	e.Data = dataMap
+	return nil
+}
+
+// GetVacuumData retrieves VacuumData from the Data field.
+func (e *AgentEvent) GetVacuumData() (*VacuumData, error) {
+	var data VacuumData
+	if err := mapToStruct(e.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse VacuumData: %w", err)
+	}
+	return &data, nil
+}
+
+// SetExecutionTelemetryData sets the Data field with ExecutionTelemetryData in a type-safe way.
+func (e *AgentEvent) SetExecutionTelemetryData(data ExecutionTelemetryData) error {
+	dataMap, err := structToMap(data)
+	if err != nil {
+		return fmt.Errorf("failed to convert ExecutionTelemetryData: %w", err)
+	}
+	e.Data = dataMap
+	return nil
+}
+
+// GetExecutionTelemetryData retrieves ExecutionTelemetryData from the Data field.
+func (e *AgentEvent) GetExecutionTelemetryData() (*ExecutionTelemetryData, error) {
+	var data ExecutionTelemetryData
+	if err := mapToStruct(e.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse ExecutionTelemetryData: %w", err)
+	}
+	return &data, nil
+}
+
+// Cursor returns the storage ID of an event loaded from storage, for use as an
+// EventFilter AfterID/BeforeID cursor. It returns 0 for events that were not
+// loaded from storage (their IDs are UUIDs).
+func (e *AgentEvent) Cursor() int64 {
+	id, err := strconv.ParseInt(e.ID, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+// SetMissionApprovalData sets the Data field with MissionApprovalData in a type-safe way.
+func (e *AgentEvent) SetMissionApprovalData(data MissionApprovalData) error {
+	dataMap, err := structToMap(data)
+	if err != nil {
+		return fmt.Errorf("failed to convert MissionApprovalData: %w", err)
+	}
+	e.Data = dataMap
+	return nil
+}
+
+// GetMissionApprovalData retrieves MissionApprovalData from the Data field.
+func (e *AgentEvent) GetMissionApprovalData() (*MissionApprovalData, error) {
+	var data MissionApprovalData
+	if err := mapToStruct(e.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse MissionApprovalData: %w", err)
+	}
+	return &data, nil
+}
+
+// SetConfigChangedData sets the Data field with ConfigChangedData in a type-safe way.
+func (e *AgentEvent) SetConfigChangedData(data ConfigChangedData) error {
+	dataMap, err := structToMap(data)
+	if err != nil {
+		return fmt.Errorf("failed to convert ConfigChangedData: %w", err)
+	}
+	e.Data = dataMap
+	return nil
+}
+
+// GetConfigChangedData retrieves ConfigChangedData from the Data field.
+func (e *AgentEvent) GetConfigChangedData() (*ConfigChangedData, error) {
+	var data ConfigChangedData
+	if err := mapToStruct(e.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse ConfigChangedData: %w", err)
+	}
+	return &data, nil
+}
+
+// SetIssueAutoBlockedData sets the Data field with IssueAutoBlockedData in a type-safe way.
+func (e *AgentEvent) SetIssueAutoBlockedData(data IssueAutoBlockedData) error {
+	dataMap, err := structToMap(data)
+	if err != nil {
+		return fmt.Errorf("failed to convert IssueAutoBlockedData: %w", err)
+	}
+	e.Data = dataMap
+	return nil
+}
+
+// GetIssueAutoBlockedData retrieves IssueAutoBlockedData from the Data field.
+func (e *AgentEvent) GetIssueAutoBlockedData() (*IssueAutoBlockedData, error) {
+	var data IssueAutoBlockedData
+	if err := mapToStruct(e.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse IssueAutoBlockedData: %w", err)
+	}
+	return &data, nil
+}
+
+// SetIssueUnblockedData sets the Data field with IssueUnblockedData in a type-safe way.
+func (e *AgentEvent) SetIssueUnblockedData(data IssueUnblockedData) error {
+	dataMap, err := structToMap(data)
+	if err != nil {
+		return fmt.Errorf("failed to convert IssueUnblockedData: %w", err)
+	}
+	e.Data = dataMap
+	return nil
+}
+
+// GetIssueUnblockedData retrieves IssueUnblockedData from the Data field.
+func (e *AgentEvent) GetIssueUnblockedData() (*IssueUnblockedData, error) {
+	var data IssueUnblockedData
+	if err := mapToStruct(e.Data, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse IssueUnblockedData: %w", err)
+	}
+	return &data, nil
+}
+
+// Data keys recording the AI provider and model behind an AI-related event
+const (
+	DataKeyAIProvider = "ai_provider"
+	DataKeyAIModel    = "ai_model"
+)
+
+// SetAIModel records the AI provider and model that produced the event in
+// its Data, alongside the event's own fields. The map is copied, so the
+// caller's map is left as is.
+func (e *AgentEvent) SetAIModel(provider, model string) {
+	data := make(map[string]interface{}, len(e.Data)+2)
+	for k, v := range e.Data {
+		data[k] = v
+	}
+	data[DataKeyAIProvider] = provider
+	data[DataKeyAIModel] = model
+	e.Data = data
+}
+
+// AIModel returns the AI provider and model recorded by SetAIModel, or
+// empty strings
+func (e *AgentEvent) AIModel() (provider, model string) {
+	provider, _ = e.Data[DataKeyAIProvider].(string)
+	model, _ = e.Data[DataKeyAIModel].(string)
+	return provider, model
+}
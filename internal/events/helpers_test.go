@@ -505,3 +505,196 @@ func TestEpicCleanupCompletedEventWithError(t *testing.T) {
 		t.Errorf("Success should be false for failed cleanup")
 	}
 }
+
+func TestNewSandboxLifecycleEvent(t *testing.T) {
+	data := SandboxLifecycleData{
+		SandboxID:  "sandbox-vc-7-1700000000",
+		Path:       "/tmp/sandboxes/mission-vc-7",
+		Branch:     "mission/vc-7/1700000000",
+		DurationMs: 1234,
+		DiskBytes:  4096,
+		Success:    true,
+	}
+
+	event, err := NewSandboxLifecycleEvent(EventTypeSandboxCreated, "vc-7", "exec-1", "", SeverityInfo, "Sandbox created", data)
+	if err != nil {
+		t.Fatalf("NewSandboxLifecycleEvent failed: %v", err)
+	}
+
+	if event.Type != EventTypeSandboxCreated {
+		t.Errorf("Wrong event type: got %s, want %s", event.Type, EventTypeSandboxCreated)
+	}
+
+	// Keys must match the documented field names so the CLI can aggregate them
+	for _, key := range []string{"duration_ms", "path", "branch", "disk_bytes"} {
+		if _, ok := event.Data[key]; !ok {
+			t.Errorf("Expected key %q in event data, got %v", key, event.Data)
+		}
+	}
+
+	retrieved, err := event.GetSandboxLifecycleData()
+	if err != nil {
+		t.Fatalf("GetSandboxLifecycleData failed: %v", err)
+	}
+	if *retrieved != data {
+		t.Errorf("Data mismatch: got %+v, want %+v", *retrieved, data)
+	}
+}
+
+func TestSandboxCleanupFailedEventWithError(t *testing.T) {
+	data := SandboxLifecycleData{
+		Path:       "/tmp/sandboxes/mission-vc-7",
+		DurationMs: 50,
+		Success:    false,
+		Error:      "failed to remove worktree",
+	}
+
+	event, err := NewSandboxLifecycleEvent(EventTypeSandboxCleanupFailed, "vc-7", "exec-1", "", SeverityError, "Sandbox cleanup failed", data)
+	if err != nil {
+		t.Fatalf("NewSandboxLifecycleEvent failed: %v", err)
+	}
+
+	retrieved, err := event.GetSandboxLifecycleData()
+	if err != nil {
+		t.Fatalf("GetSandboxLifecycleData failed: %v", err)
+	}
+	if retrieved.Error != data.Error {
+		t.Errorf("Error mismatch: got %s, want %s", retrieved.Error, data.Error)
+	}
+	if retrieved.Success {
+		t.Errorf("Success should be false for failed cleanup")
+	}
+}
+
+func TestNewExecutionTelemetryEvent(t *testing.T) {
+	since := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	data := ExecutionTelemetryData{
+		State:           "executing",
+		StateSince:      since,
+		StartedAt:       since.Add(-time.Minute),
+		LastActivity:    since.Add(30 * time.Second),
+		TotalEvents:     12,
+		EventsPerMinute: 4,
+		EventCounts:     map[string]int{"agent_tool_use": 12},
+		Transitions: []TelemetryTransition{
+			{From: "assessing", To: "executing", Timestamp: since},
+		},
+		ProtectionMode: "normal",
+	}
+
+	event, err := NewExecutionTelemetryEvent("vc-7", "exec-1", "", SeverityInfo, "vc-7: executing", data)
+	if err != nil {
+		t.Fatalf("NewExecutionTelemetryEvent failed: %v", err)
+	}
+	if event.Type != EventTypeExecutionTelemetry {
+		t.Errorf("Wrong event type: got %s, want %s", event.Type, EventTypeExecutionTelemetry)
+	}
+
+	retrieved, err := event.GetExecutionTelemetryData()
+	if err != nil {
+		t.Fatalf("GetExecutionTelemetryData failed: %v", err)
+	}
+	if retrieved.State != data.State || !retrieved.StateSince.Equal(data.StateSince) || retrieved.TotalEvents != data.TotalEvents {
+		t.Errorf("Data mismatch: got %+v, want %+v", *retrieved, data)
+	}
+	if len(retrieved.Transitions) != 1 || retrieved.Transitions[0] != data.Transitions[0] {
+		t.Errorf("Transitions mismatch: got %+v", retrieved.Transitions)
+	}
+	if retrieved.EventCounts["agent_tool_use"] != 12 {
+		t.Errorf("EventCounts mismatch: got %v", retrieved.EventCounts)
+	}
+}
+
+func TestAgentEventCursor(t *testing.T) {
+	stored := &AgentEvent{ID: "1234"}
+	if got := stored.Cursor(); got != 1234 {
+		t.Errorf("Cursor() = %d, want 1234", got)
+	}
+
+	// Events that were never stored carry a UUID
+	unsaved := &AgentEvent{ID: "0f8fad5b-d9cb-469f-a165-70867728950e"}
+	if got := unsaved.Cursor(); got != 0 {
+		t.Errorf("Cursor() = %d, want 0", got)
+	}
+}
+
+func TestNewMissionApprovalEvent(t *testing.T) {
+	data := MissionApprovalData{
+		MissionID: "vc-9",
+		Approved:  false,
+		Comment:   "Phase 2 is missing a migration",
+		Actor:     "alice",
+	}
+
+	event, err := NewMissionApprovalEvent("vc-9", "", "", SeverityWarning, "Plan rejected", data)
+	if err != nil {
+		t.Fatalf("NewMissionApprovalEvent failed: %v", err)
+	}
+	if event.Type != EventTypeMissionRejected {
+		t.Errorf("Wrong event type: got %s, want %s", event.Type, EventTypeMissionRejected)
+	}
+
+	retrieved, err := event.GetMissionApprovalData()
+	if err != nil {
+		t.Fatalf("GetMissionApprovalData failed: %v", err)
+	}
+	if *retrieved != data {
+		t.Errorf("Data mismatch: got %+v, want %+v", *retrieved, data)
+	}
+
+	data.Approved = true
+	event, err = NewMissionApprovalEvent("vc-9", "", "", SeverityInfo, "Plan approved", data)
+	if err != nil {
+		t.Fatalf("NewMissionApprovalEvent failed: %v", err)
+	}
+	if event.Type != EventTypeMissionApproved {
+		t.Errorf("Wrong event type: got %s, want %s", event.Type, EventTypeMissionApproved)
+	}
+}
+
+func TestNewConfigChangedEvent(t *testing.T) {
+	data := ConfigChangedData{
+		Key:      "executor.poll_interval",
+		OldValue: "",
+		NewValue: "10s",
+	}
+
+	event, err := NewConfigChangedEvent("SYSTEM", "", "", SeverityInfo, "Config changed", data)
+	if err != nil {
+		t.Fatalf("NewConfigChangedEvent failed: %v", err)
+	}
+	if event.Type != EventTypeConfigChanged || event.IssueID != "SYSTEM" {
+		t.Errorf("Wrong event: got type %s issue %s", event.Type, event.IssueID)
+	}
+
+	retrieved, err := event.GetConfigChangedData()
+	if err != nil {
+		t.Fatalf("GetConfigChangedData failed: %v", err)
+	}
+	if *retrieved != data {
+		t.Errorf("Data mismatch: got %+v, want %+v", *retrieved, data)
+	}
+}
+
+func TestSetAIModel(t *testing.T) {
+	data := map[string]interface{}{"confidence": 0.9}
+	event := &AgentEvent{Type: EventTypeAssessmentCompleted, Data: data}
+	event.SetAIModel("openai", "gpt-4o")
+
+	if provider, model := event.AIModel(); provider != "openai" || model != "gpt-4o" {
+		t.Errorf("AIModel() = %s/%s, want openai/gpt-4o", provider, model)
+	}
+	if event.Data["confidence"] != 0.9 {
+		t.Errorf("Expected the event's own data to be kept, got %v", event.Data)
+	}
+	if _, ok := data[DataKeyAIModel]; ok {
+		t.Error("SetAIModel modified the caller's map")
+	}
+
+	if provider, model := (&AgentEvent{}).AIModel(); provider != "" || model != "" {
+		t.Errorf("Expected no AI model on an event without one, got %s/%s", provider, model)
+	}
+	if !IsAIEvent(EventTypeDeduplicationDecision) || IsAIEvent(EventTypeFileModified) {
+		t.Error("IsAIEvent should cover AI work only")
+	}
+}
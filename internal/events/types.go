@@ -2,6 +2,8 @@ package events
 
 import (
 	"context"
+	"encoding/json"
+	"strings"
 	"time"
 )
 
@@ -25,8 +27,16 @@ const (
 	EventTypeError EventType = "error"
 	// EventTypeWatchdog indicates a watchdog alert was triggered
 	EventTypeWatchdog EventType = "watchdog_alert"
+	// EventTypeWatchdogProtectionReduced indicates watchdog protection was deliberately
+	// reduced for an execution via a watchdog:off or watchdog:relaxed label
+	EventTypeWatchdogProtectionReduced EventType = "watchdog_protection_reduced"
 	// EventTypeContextUsage indicates context usage measurement from agent output
 	EventTypeContextUsage EventType = "context_usage"
+	// EventTypeExecutionTelemetry is a periodic snapshot of the watchdog's view of the active execution
+	EventTypeExecutionTelemetry EventType = "execution_telemetry"
+	// EventTypeWatchEventsDropped tells a WatchAgentEvents subscriber that it fell
+	// behind and older events were dropped (delivered to subscribers, never stored)
+	EventTypeWatchEventsDropped EventType = "watch_events_dropped"
 
 	// Executor-level events
 	// EventTypeIssueClaimed indicates an executor claimed an issue for processing
@@ -43,10 +53,18 @@ const (
 	EventTypeResultsProcessingStarted EventType = "results_processing_started"
 	// EventTypeResultsProcessingCompleted indicates results processing phase completed
 	EventTypeResultsProcessingCompleted EventType = "results_processing_completed"
+	// EventTypeIssueAutoBlocked indicates the executor blocked an issue after repeated execution failures
+	EventTypeIssueAutoBlocked EventType = "issue_auto_blocked"
+	// EventTypeIssueUnblocked indicates a blocked issue was reopened by hand (vc unblock)
+	EventTypeIssueUnblocked EventType = "issue_unblocked"
 	// EventTypeAnalysisStarted indicates AI analysis phase started
 	EventTypeAnalysisStarted EventType = "analysis_started"
 	// EventTypeAnalysisCompleted indicates AI analysis phase completed
 	EventTypeAnalysisCompleted EventType = "analysis_completed"
+	// EventTypeFailureAnalyzed records the AI supervisor's diagnosis of a failed attempt and its suggested fix
+	EventTypeFailureAnalyzed EventType = "failure_analyzed"
+	// EventTypeMergeDecision records whether changes that passed the gates merge automatically or wait for review, with the risk signals scored
+	EventTypeMergeDecision EventType = "merge_decision"
 	// EventTypeQualityGatesStarted indicates quality gates evaluation started
 	EventTypeQualityGatesStarted EventType = "quality_gates_started"
 	// EventTypeQualityGatesProgress indicates progress during quality gates evaluation
@@ -70,6 +88,36 @@ const (
 	// EventTypeEventCleanupCompleted indicates event cleanup cycle completed
 	EventTypeEventCleanupCompleted EventType = "event_cleanup_completed"
 
+	// EventTypeVacuumStarted indicates cleanup started reclaiming free database pages
+	EventTypeVacuumStarted EventType = "vacuum_started"
+	// EventTypeVacuumCompleted indicates a vacuum finished (or failed), with sizes before and after
+	EventTypeVacuumCompleted EventType = "vacuum_completed"
+
+	// EventTypeConfigChanged indicates a runtime setting the executor reads was changed (SYSTEM event)
+	EventTypeConfigChanged EventType = "config_changed"
+	// EventTypeStorageTimeout indicates a hot-path storage call (heartbeat, claim, state update, event) timed out (SYSTEM event)
+	EventTypeStorageTimeout EventType = "storage_timeout"
+	// EventTypeStorageDegraded indicates the database kept failing and couldn't be reopened, so the executor stopped claiming work (SYSTEM event)
+	EventTypeStorageDegraded EventType = "storage_degraded"
+	// EventTypeStorageRecovered indicates the database was reopened after repeated failures and work resumed (SYSTEM event)
+	EventTypeStorageRecovered EventType = "storage_recovered"
+	// EventTypeStorageSlowQueries records the database queries of one process that exceeded slow_query_ms, when it closed the database (SYSTEM event)
+	EventTypeStorageSlowQueries EventType = "storage_slow_queries"
+	// EventTypeDailySummary carries the executor's daily digest of completed, failed, blocked and discovered work (SYSTEM event)
+	EventTypeDailySummary EventType = "daily_summary"
+	// EventTypeAIUnavailable indicates the executor started without AI supervision because the AI healthcheck failed (SYSTEM event)
+	EventTypeAIUnavailable EventType = "ai_unavailable"
+	// EventTypeAIRetried indicates an AI call succeeded after retrying rate limits or transient errors (SYSTEM event)
+	EventTypeAIRetried EventType = "ai_retried"
+	// EventTypeAIRetriesExhausted indicates an AI call failed after using up its retries or retry budget (SYSTEM event)
+	EventTypeAIRetriesExhausted EventType = "ai_retries_exhausted"
+	// EventTypeAICallCompleted records the token usage, latency and estimated cost of one AI call
+	EventTypeAICallCompleted EventType = "ai_call_completed"
+	// EventTypeAIResponseRepaired indicates a structured AI response only parsed after repair (fences, prose, trailing commas, ...)
+	EventTypeAIResponseRepaired EventType = "ai_response_repaired"
+	// EventTypeAIResponseRetried indicates an unusable structured AI response was asked for again with the expected schema
+	EventTypeAIResponseRetried EventType = "ai_response_retried"
+
 	// Instance cleanup events (vc-32)
 	// EventTypeInstanceCleanupCompleted indicates executor instance cleanup cycle completed
 	EventTypeInstanceCleanupCompleted EventType = "instance_cleanup_completed"
@@ -79,6 +127,9 @@ const (
 	EventTypeHealthCheckCompleted EventType = "health_check_completed"
 	// EventTypeHealthCheckFailed indicates a health monitor failed to execute
 	EventTypeHealthCheckFailed EventType = "health_check_failed"
+	// EventTypeHealthCheckWarning indicates a health monitor ran but reported a problem
+	// with its own output (e.g. malformed JSON from a custom monitor command)
+	EventTypeHealthCheckWarning EventType = "health_check_warning"
 
 	// Agent progress events (vc-129)
 	// EventTypeAgentToolUse indicates an agent invoked a tool (Read, Edit, Write, Bash, etc.)
@@ -136,12 +187,38 @@ const (
 	// EventTypeSandboxCleanupCompleted indicates sandbox cleanup completed
 	EventTypeSandboxCleanupCompleted EventType = "sandbox_cleanup_completed"
 
+	// Sandbox lifecycle metrics events (emitted by the executor around sandbox creation and cleanup)
+	// EventTypeSandboxCreated records the duration and disk footprint of a sandbox creation
+	EventTypeSandboxCreated EventType = "sandbox_created"
+	// EventTypeSandboxCleaned records the duration and reclaimed disk space of a sandbox cleanup
+	EventTypeSandboxCleaned EventType = "sandbox_cleaned"
+	// EventTypeSandboxCleanupFailed indicates a sandbox cleanup attempt failed
+	EventTypeSandboxCleanupFailed EventType = "sandbox_cleanup_failed"
+
 	// Mission phase transition events (vc-266)
 	// EventTypeMissionCreated indicates a new mission was created
 	EventTypeMissionCreated EventType = "mission_created"
 	// EventTypeMissionMetadataUpdated indicates mission metadata was updated
 	EventTypeMissionMetadataUpdated EventType = "mission_metadata_updated"
 
+	// Mission plan approval events
+	// EventTypeMissionApproved indicates a human approved a mission's plan
+	EventTypeMissionApproved EventType = "mission_approved"
+	// EventTypeMissionRejected indicates a human rejected a mission's plan (the mission is blocked)
+	EventTypeMissionRejected EventType = "mission_rejected"
+	// EventTypeMissionAwaitingApproval indicates the executor is holding back a mission's work until its plan is approved
+	EventTypeMissionAwaitingApproval EventType = "mission_awaiting_approval"
+	// EventTypeClaimDeferred indicates the executor held off claiming an issue whose files overlap another executor's work in flight
+	EventTypeClaimDeferred EventType = "claim_deferred"
+
+	// Phased mission execution events
+	// EventTypeMissionPhaseCompleted indicates a mission finished a phase and moved on to the next
+	EventTypeMissionPhaseCompleted EventType = "mission_phase_completed"
+	// EventTypeMissionPhaseFailed indicates a phase stopped on a blocked task (the phase and mission are blocked)
+	EventTypeMissionPhaseFailed EventType = "mission_phase_failed"
+	// EventTypeMissionCompleted indicates a mission was closed after its last phase completed
+	EventTypeMissionCompleted EventType = "mission_completed"
+
 	// Epic lifecycle events (vc-268)
 	// EventTypeEpicCompleted indicates an epic was completed (all children done)
 	EventTypeEpicCompleted EventType = "epic_completed"
@@ -284,12 +361,39 @@ type EventCleanupCompletedData struct {
 	VacuumRan bool `json:"vacuum_ran"`
 	// EventsRemaining is the total number of events remaining after cleanup
 	EventsRemaining int `json:"events_remaining"`
+	// HistoryDeleted is the number of execution attempts deleted by
+	// history retention
+	HistoryDeleted int `json:"history_deleted"`
+	// ArtifactsDeleted is the number of artifact files deleted with them
+	ArtifactsDeleted int `json:"artifacts_deleted"`
 	// Success indicates whether cleanup succeeded
 	Success bool `json:"success"`
 	// Error contains the error message if cleanup failed
 	Error string `json:"error,omitempty"`
 }
 
+// VacuumData contains structured data for vacuum_started and vacuum_completed events.
+type VacuumData struct {
+	// Mode is "full" (VACUUM) or "incremental" (PRAGMA incremental_vacuum)
+	Mode string `json:"mode"`
+	// Reason explains why the vacuum ran
+	Reason string `json:"reason"`
+	// SizeBeforeBytes is the database size before the vacuum
+	SizeBeforeBytes int64 `json:"size_before_bytes"`
+	// FreePagesBefore is the freelist page count before the vacuum
+	FreePagesBefore int64 `json:"free_pages_before"`
+	// SizeAfterBytes is the database size after the vacuum (completed events only)
+	SizeAfterBytes int64 `json:"size_after_bytes,omitempty"`
+	// FreePagesAfter is the freelist page count after the vacuum (completed events only)
+	FreePagesAfter int64 `json:"free_pages_after,omitempty"`
+	// DurationMs is how long the vacuum took (completed events only)
+	DurationMs int64 `json:"duration_ms,omitempty"`
+	// Success indicates whether the vacuum succeeded (completed events only)
+	Success bool `json:"success,omitempty"`
+	// Error contains the error message if the vacuum failed
+	Error string `json:"error,omitempty"`
+}
+
 // InstanceCleanupCompletedData contains structured data for instance cleanup events (vc-32).
 // This struct follows the same pattern as EventCleanupCompletedData for consistency.
 type InstanceCleanupCompletedData struct {
@@ -501,6 +605,175 @@ type SandboxCleanupCompletedData struct {
 	Error string `json:"error,omitempty"`
 }
 
+// SandboxLifecycleData contains structured metrics for sandbox lifecycle events
+// (sandbox_created, sandbox_cleaned, sandbox_cleanup_failed).
+type SandboxLifecycleData struct {
+	// SandboxID is the unique ID for this sandbox
+	SandboxID string `json:"sandbox_id,omitempty"`
+	// Path is the path to the sandbox directory
+	Path string `json:"path,omitempty"`
+	// Branch is the git branch used by the sandbox
+	Branch string `json:"branch,omitempty"`
+	// DurationMs is the time taken by the operation in milliseconds
+	DurationMs int64 `json:"duration_ms"`
+	// DiskBytes is the on-disk size of the sandbox before cleanup, leaving out
+	// .vcignore'd paths. Zero on sandbox_created events, and if disk usage
+	// could not be determined within its time budget.
+	DiskBytes int64 `json:"disk_bytes"`
+	// Success indicates whether the operation succeeded
+	Success bool `json:"success"`
+	// Error contains the error message if the operation failed
+	Error string `json:"error,omitempty"`
+}
+
+// FailureAnalysisData contains the AI supervisor's diagnosis of a failed
+// attempt (failure_analyzed events). The next attempt's prompt includes it.
+type FailureAnalysisData struct {
+	// Reason is what failed: agent_failed, quality_gates_failed or agent_error
+	Reason string `json:"reason"`
+	// Diagnosis explains why the attempt failed
+	Diagnosis string `json:"diagnosis"`
+	// Suggestion is the concrete change to make next time
+	Suggestion string `json:"suggestion"`
+	// Confidence is the supervisor's confidence in the diagnosis (0.0-1.0)
+	Confidence float64 `json:"confidence"`
+}
+
+// MergeDecisionData contains the auto-merge score of changes that passed the
+// quality gates and its inputs (merge_decision events), so that
+// executor.auto_merge_threshold can be tuned from past decisions
+type MergeDecisionData struct {
+	// Score is how safe the change looks to merge unreviewed (0.0-1.0)
+	Score float64 `json:"score"`
+	// Threshold is the executor.auto_merge_threshold the score was compared to
+	Threshold float64 `json:"threshold"`
+	// AutoMerged is true when the score reached the threshold
+	AutoMerged bool `json:"auto_merged"`
+	// Confidence is the assessment's confidence, 0 if the issue wasn't assessed
+	Confidence float64 `json:"confidence"`
+	// FilesChanged and LinesChanged measure the diff of the sandbox
+	FilesChanged int `json:"files_changed"`
+	LinesChanged int `json:"lines_changed"`
+	// TestsModified is true when the diff changes test files
+	TestsModified bool `json:"tests_modified"`
+	// IssueType is the type of the issue the change is for
+	IssueType string `json:"issue_type"`
+}
+
+// AICallData contains the usage of one AI call (ai_call_completed events).
+// Provider and model use the same keys as SetAIModel.
+type AICallData struct {
+	// Purpose groups calls by what they are for: assessment, analysis,
+	// dedup, watchdog, health, planning, recovery, code_review, summarization or other
+	Purpose string `json:"purpose"`
+	// Operation is the supervisor operation, e.g. completion-assessment
+	Operation string `json:"operation"`
+	// Provider is the AI provider called
+	Provider string `json:"ai_provider"`
+	// Model is the model that answered
+	Model string `json:"ai_model"`
+	// InputTokens and OutputTokens are the prompt and completion tokens
+	InputTokens  int64 `json:"input_tokens"`
+	OutputTokens int64 `json:"output_tokens"`
+	// LatencyMs is the time taken by the call, retries included
+	LatencyMs int64 `json:"latency_ms"`
+	// EstimatedCostUSD is the cost at the configured price of the model
+	EstimatedCostUSD float64 `json:"estimated_cost_usd"`
+	// Priced is false when the model has no price, so the cost is unknown
+	Priced bool `json:"priced"`
+}
+
+// AIResponseRepairData describes a structured AI response that needed
+// repair or a retry (ai_response_repaired and ai_response_retried events)
+type AIResponseRepairData struct {
+	// Purpose and Operation are those of the call, as in AICallData
+	Purpose   string `json:"purpose"`
+	Operation string `json:"operation"`
+	// Repair names what made the response parse: code_fence, cleanup or
+	// extract (empty if it never parsed)
+	Repair string `json:"repair,omitempty"`
+	// Error is why the first response was unusable (ai_response_retried)
+	Error string `json:"error,omitempty"`
+	// Success reports whether the retried response was usable (ai_response_retried)
+	Success bool `json:"success"`
+}
+
+// StorageSlowQueriesData contains the slow queries of one process
+// (storage_slow_queries events). Statements never include parameters.
+type StorageSlowQueriesData struct {
+	// ThresholdMs is the slow_query_ms the queries were measured against
+	ThresholdMs int64 `json:"threshold_ms"`
+	// Queries is the number of queries timed, Slow those over the threshold
+	Queries int64 `json:"queries"`
+	Slow    int64 `json:"slow"`
+	// Statements are the slow statements, slowest total first
+	Statements []SlowStatement `json:"statements"`
+}
+
+// DailySummaryData contains the executor's daily digest (daily_summary
+// events), see internal/digest
+type DailySummaryData struct {
+	// Since and Until bound the period summarized
+	Since time.Time `json:"since"`
+	Until time.Time `json:"until"`
+	// The number of issues in each section of the digest
+	Completed  int `json:"completed"`
+	Failed     int `json:"failed"`
+	Blocked    int `json:"blocked"`
+	Discovered int `json:"discovered"`
+	// CostUSD is the estimated AI spend of the period
+	CostUSD float64 `json:"estimated_cost_usd"`
+	// Markdown is the full digest as 'vc summary --markdown' prints it
+	Markdown string `json:"markdown"`
+}
+
+// SlowStatement is one statement's share of StorageSlowQueriesData
+type SlowStatement struct {
+	// Statement is the SQL with its literals masked and whitespace
+	// collapsed, truncated; Beads calls are named beads.<Method>
+	Statement string `json:"statement"`
+	// Slow is how often it exceeded the threshold, TotalMs and MaxMs the
+	// time those executions took
+	Slow    int64 `json:"slow"`
+	TotalMs int64 `json:"total_ms"`
+	MaxMs   int64 `json:"max_ms"`
+}
+
+// ExecutionTelemetryData contains a snapshot of the watchdog monitor's telemetry
+// for the active execution (execution_telemetry events).
+type ExecutionTelemetryData struct {
+	// State is the current execution state (the target of the last transition)
+	State string `json:"state"`
+	// StateSince is when the execution entered State
+	StateSince time.Time `json:"state_since"`
+	// StartedAt is when the executor claimed the issue
+	StartedAt time.Time `json:"started_at"`
+	// LastActivity is when the most recent agent or executor event was recorded
+	LastActivity time.Time `json:"last_activity"`
+	// TotalEvents is the number of events recorded so far
+	TotalEvents int `json:"total_events"`
+	// EventsPerMinute is TotalEvents averaged over the execution so far
+	EventsPerMinute float64 `json:"events_per_minute"`
+	// EventCounts breaks TotalEvents down by event type
+	EventCounts map[string]int `json:"event_counts,omitempty"`
+	// Transitions is the state-transition history, oldest first
+	Transitions []TelemetryTransition `json:"transitions,omitempty"`
+	// ProtectionMode is the watchdog protection mode for this execution
+	ProtectionMode string `json:"protection_mode,omitempty"`
+	// SilenceThresholdMs overrides the stall threshold for this execution (0 = default)
+	SilenceThresholdMs int64 `json:"silence_threshold_ms,omitempty"`
+}
+
+// TelemetryTransition is one state change in an ExecutionTelemetryData history.
+type TelemetryTransition struct {
+	// From is the previous execution state
+	From string `json:"from"`
+	// To is the new execution state
+	To string `json:"to"`
+	// Timestamp is when the transition occurred
+	Timestamp time.Time `json:"timestamp"`
+}
+
 // MissionCreatedData contains structured data for mission creation events (vc-266).
 type MissionCreatedData struct {
 	// MissionID is the ID of the created mission
@@ -529,6 +802,46 @@ type MissionMetadataUpdatedData struct {
 	Actor string `json:"actor"`
 }
 
+// MissionApprovalData contains structured data for mission approval and rejection events.
+type MissionApprovalData struct {
+	// MissionID is the ID of the mission
+	MissionID string `json:"mission_id"`
+	// Approved is true for an approval, false for a rejection
+	Approved bool `json:"approved"`
+	// Comment is the reviewer's comment (required for rejections)
+	Comment string `json:"comment,omitempty"`
+	// Actor is who reviewed the plan
+	Actor string `json:"actor"`
+}
+
+// ConfigChangedData contains structured data for config_changed events.
+type ConfigChangedData struct {
+	// Key is the setting that changed
+	Key string `json:"key"`
+	// OldValue is the previously stored value ("" if it was unset)
+	OldValue string `json:"old_value"`
+	// NewValue is the value now stored
+	NewValue string `json:"new_value"`
+}
+
+// IssueAutoBlockedData contains structured data for issue_auto_blocked events.
+type IssueAutoBlockedData struct {
+	// FailureCount is the number of consecutive failed attempts
+	FailureCount int `json:"failure_count"`
+	// LastError is the error of the attempt that blocked the issue
+	LastError string `json:"last_error"`
+	// AttemptIDs are the failed attempts, newest first
+	AttemptIDs []int64 `json:"attempt_ids"`
+}
+
+// IssueUnblockedData contains structured data for issue_unblocked events.
+type IssueUnblockedData struct {
+	// AutoBlocked is true if the executor had blocked the issue
+	AutoBlocked bool `json:"auto_blocked"`
+	// Actor is who unblocked the issue
+	Actor string `json:"actor"`
+}
+
 // FieldChange represents a change to a field value
 type FieldChange struct {
 	// OldValue is the previous value (may be nil for new fields)
@@ -614,6 +927,10 @@ type EventStore interface {
 type EventFilter struct {
 	// IssueID filters events by issue ID
 	IssueID string
+	// ExecutorID filters events by executor instance ID
+	ExecutorID string
+	// AgentID filters events by the agent that produced them
+	AgentID string
 	// Type filters events by event type
 	Type EventType
 	// Severity filters events by severity level
@@ -622,10 +939,82 @@ type EventFilter struct {
 	AfterTime time.Time
 	// BeforeTime filters events that occurred before this time
 	BeforeTime time.Time
+	// MessageContains keeps events whose message or JSON data contains this
+	// text, ignoring case
+	MessageContains string
+	// AfterID returns only events stored after the event with this ID, oldest
+	// first. Use it to read forward (e.g. follow new events) without gaps or repeats.
+	AfterID int64
+	// BeforeID returns only events stored before the event with this ID, newest
+	// first. Use it to page back through history; math.MaxInt64 starts at the newest event.
+	BeforeID int64
 	// Limit limits the number of events returned
 	Limit int
 }
 
+// Matches reports whether an event satisfies the filter's issue, executor,
+// agent, type, severity, time-range and text criteria. Limit and the ID
+// cursors describe a query rather than an event and are ignored.
+func (f EventFilter) Matches(e *AgentEvent) bool {
+	switch {
+	case f.IssueID != "" && e.IssueID != f.IssueID:
+		return false
+	case f.ExecutorID != "" && e.ExecutorID != f.ExecutorID:
+		return false
+	case f.AgentID != "" && e.AgentID != f.AgentID:
+		return false
+	case f.Type != "" && e.Type != f.Type:
+		return false
+	case f.Severity != "" && e.Severity != f.Severity:
+		return false
+	case !f.AfterTime.IsZero() && e.Timestamp.Before(f.AfterTime):
+		return false
+	case !f.BeforeTime.IsZero() && e.Timestamp.After(f.BeforeTime):
+		return false
+	case f.MessageContains != "" && !e.contains(f.MessageContains):
+		return false
+	}
+	return true
+}
+
+// contains reports whether the event's message or JSON-encoded data contains
+// text, ignoring case
+func (e *AgentEvent) contains(text string) bool {
+	text = strings.ToLower(text)
+	if strings.Contains(strings.ToLower(e.Message), text) {
+		return true
+	}
+	if e.Data == nil {
+		return false
+	}
+	data, err := json.Marshal(e.Data)
+	return err == nil && strings.Contains(strings.ToLower(string(data)), text)
+}
+
+// aiEventTypes are the event types whose outcome comes from an AI call
+var aiEventTypes = map[EventType]bool{
+	EventTypeAssessmentStarted:           true,
+	EventTypeAssessmentCompleted:         true,
+	EventTypeAnalysisStarted:             true,
+	EventTypeAnalysisCompleted:           true,
+	EventTypeFailureAnalyzed:             true,
+	EventTypeDeduplicationBatchStarted:   true,
+	EventTypeDeduplicationBatchCompleted: true,
+	EventTypeDeduplicationDecision:       true,
+	EventTypeWatchdog:                    true,
+	EventTypeAIRetried:                   true,
+	EventTypeAIRetriesExhausted:          true,
+	EventTypeAICallCompleted:             true,
+	EventTypeAIResponseRepaired:          true,
+	EventTypeAIResponseRetried:           true,
+}
+
+// IsAIEvent reports whether events of the type record AI work, and so
+// carry the provider and model used (SetAIModel)
+func IsAIEvent(t EventType) bool {
+	return aiEventTypes[t]
+}
+
 // IsValidFailureType validates if a failure type string is valid (vc-228)
 // Valid values match the FailureType constants in internal/ai/test_failure.go
 func IsValidFailureType(ft string) bool {
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/steveyegge/vc/internal/artifacts"
 	"github.com/steveyegge/vc/internal/events"
 	"github.com/steveyegge/vc/internal/sandbox"
 	"github.com/steveyegge/vc/internal/storage"
@@ -39,9 +40,20 @@ type AgentConfig struct {
 	ExecutorID string
 	AgentID    string
 	// Watchdog monitoring (optional - if nil, events won't be reported to watchdog)
-	Monitor    interface{ RecordEvent(eventType string) }
+	Monitor    AgentMonitor
 	// Sandbox context (optional - if nil, agent runs in main workspace)
 	Sandbox    *sandbox.Sandbox
+	// Transcript is the file all agent output is teed to (optional - if
+	// empty, output is only captured up to maxOutputLines)
+	Transcript string
+}
+
+// AgentMonitor receives agent activity for watchdog anomaly detection
+type AgentMonitor interface {
+	// RecordEvent counts an event by type
+	RecordEvent(eventType string)
+	// RecordOutput feeds an event message to output loop detection
+	RecordOutput(message string)
 }
 
 const (
@@ -69,6 +81,7 @@ type AgentResult struct {
 	ExitCode   int
 	Duration   time.Duration
 	ParsedJSON []AgentMessage  // Parsed JSON messages if StreamJSON=true
+	Transcript string          // Path of the full output, compressed or not ("" if none)
 }
 
 // AgentMessage represents a JSON message from the agent.
@@ -185,6 +198,9 @@ type Agent struct {
 	result AgentResult
 	parser *events.OutputParser // Parser for extracting events from output
 
+	transcript  *artifacts.Transcript // nil without AgentConfig.Transcript
+	captureDone chan struct{}         // Closed once all output is read and the transcript closed
+
 	// Circuit breaker state for detecting infinite loops (vc-117)
 	totalReadCount int            // Total number of Read tool invocations
 	fileReadCounts map[string]int // Number of times each file has been read
@@ -233,8 +249,19 @@ func SpawnAgent(ctx context.Context, cfg AgentConfig, prompt string) (*Agent, er
 		return nil, fmt.Errorf("failed to create stderr pipe: %w", err)
 	}
 
+	// Failing to create the transcript only loses the transcript
+	var transcript *artifacts.Transcript
+	if cfg.Transcript != "" {
+		if transcript, err = artifacts.CreateTranscript(cfg.Transcript); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+		}
+	}
+
 	// Start the process
 	if err := cmd.Start(); err != nil {
+		if transcript != nil {
+			_, _ = transcript.Close()
+		}
 		return nil, fmt.Errorf("failed to start agent: %w", err)
 	}
 
@@ -254,6 +281,8 @@ func SpawnAgent(ctx context.Context, cfg AgentConfig, prompt string) (*Agent, er
 		fileReadCounts: make(map[string]int),
 		loopDetected:   false,
 		loopReason:     "",
+		transcript:     transcript,
+		captureDone:    make(chan struct{}),
 	}
 
 	// Initialize OutputParser if event storage is enabled
@@ -281,9 +310,12 @@ func (a *Agent) Wait(ctx context.Context) (*AgentResult, error) {
 	defer cancel()
 
 	// Wait for process to complete or timeout
+	// Wait closes the output pipes, so the capture finishes right after
 	errCh := make(chan error, 1)
 	go func() {
-		errCh <- a.cmd.Wait()
+		err := a.cmd.Wait()
+		<-a.captureDone
+		errCh <- err
 	}()
 
 	select {
@@ -321,6 +353,7 @@ func (a *Agent) Wait(ctx context.Context) (*AgentResult, error) {
 			a.result.ExitCode = 0
 			a.result.Success = true
 		}
+		a.result.Transcript = a.transcriptPath()
 
 		return &a.result, nil
 	}
@@ -334,9 +367,40 @@ func (a *Agent) Kill() error {
 	return nil
 }
 
+// transcriptWait bounds how long TranscriptPath waits for the output of a
+// killed agent to be read
+const transcriptWait = 5 * time.Second
+
+// TranscriptPath returns the path of the agent's transcript once its output
+// is read and the file closed (and compressed), or "" without one. It waits
+// up to transcriptWait for a killed agent's output.
+func (a *Agent) TranscriptPath() string {
+	if a.transcript == nil {
+		return ""
+	}
+	select {
+	case <-a.captureDone:
+	case <-time.After(transcriptWait):
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.transcriptPath()
+}
+
+// transcriptPath returns the transcript's path. Caller holds mu.
+func (a *Agent) transcriptPath() string {
+	if a.transcript == nil {
+		return ""
+	}
+	path, _ := a.transcript.Close() // A no-op once captureOutput closed it
+	return path
+}
+
 // captureOutput reads stdout/stderr and stores in result
 // If event parsing is enabled, it also parses lines into structured events and stores them
+// Every line also goes to the transcript, if there is one
 func (a *Agent) captureOutput() {
+	defer close(a.captureDone)
 	var wg sync.WaitGroup
 	wg.Add(2)
 
@@ -347,6 +411,9 @@ func (a *Agent) captureOutput() {
 		for scanner.Scan() {
 			line := scanner.Text()
 			a.mu.Lock()
+			if a.transcript != nil {
+				a.transcript.WriteLine(line)
+			}
 
 			// Only append if we haven't reached the limit
 			if len(a.result.Output) < maxOutputLines {
@@ -382,6 +449,9 @@ func (a *Agent) captureOutput() {
 		for scanner.Scan() {
 			line := scanner.Text()
 			a.mu.Lock()
+			if a.transcript != nil {
+				a.transcript.WriteLine(artifacts.StderrPrefix + line)
+			}
 
 			// Only append if we haven't reached the limit
 			if len(a.result.Errors) < maxOutputLines {
@@ -403,6 +473,24 @@ func (a *Agent) captureOutput() {
 	}()
 
 	wg.Wait()
+
+	if a.transcript != nil {
+		if _, err := a.transcript.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+		}
+	}
+}
+
+// loopSignature returns the text compared by the watchdog's loop detector.
+// Tool use messages are raw JSON with per-call IDs, so the tool invocation
+// itself is used instead; repeating the same command is what matters.
+func loopSignature(event *events.AgentEvent) string {
+	if event.Type == events.EventTypeAgentToolUse {
+		if data, err := event.GetAgentToolUseData(); err == nil {
+			return fmt.Sprintf("%s %s %s", data.ToolName, data.Command, data.TargetFile)
+		}
+	}
+	return event.Message
 }
 
 // parseAndStoreEvents parses a line for events and stores them immediately
@@ -435,6 +523,7 @@ func (a *Agent) parseAndStoreEvents(line string) {
 		// Do this synchronously before async storage to ensure monitor sees events in order
 		if a.config.Monitor != nil {
 			a.config.Monitor.RecordEvent(string(event.Type))
+			a.config.Monitor.RecordOutput(loopSignature(event))
 		}
 
 		// Store event asynchronously to avoid blocking output capture
@@ -12,12 +12,12 @@ import (
 
 // AgentReportHandler processes structured agent reports and updates the issue tracker
 type AgentReportHandler struct {
-	store storage.Storage
+	store storage.IssueStore
 	actor string
 }
 
 // NewAgentReportHandler creates a new agent report handler
-func NewAgentReportHandler(store storage.Storage, actor string) *AgentReportHandler {
+func NewAgentReportHandler(store storage.IssueStore, actor string) *AgentReportHandler {
 	return &AgentReportHandler{
 		store: store,
 		actor: actor,
@@ -196,12 +196,13 @@ func (h *AgentReportHandler) handlePartial(ctx context.Context, issue *types.Iss
 	}
 
 	// Keep issue open (partial completion means not done)
-	// But update notes to track progress
-	notes := fmt.Sprintf("Partial completion: %d items done, %d follow-on issues created", len(report.Completed), len(createdIssues))
-	updates := map[string]interface{}{
-		"notes": notes,
-	}
-	if err := h.store.UpdateIssue(ctx, issue.ID, updates, h.actor); err != nil {
+	// But append to the notes to track progress. ModifyIssue re-reads the
+	// notes if someone else writes the issue meanwhile, so no note is lost.
+	note := fmt.Sprintf("Partial completion: %d items done, %d follow-on issues created", len(report.Completed), len(createdIssues))
+	err := storage.ModifyIssue(ctx, h.store, issue.ID, h.actor, func(current *types.Issue) (map[string]interface{}, error) {
+		return map[string]interface{}{"notes": appendNote(current.Notes, note)}, nil
+	})
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "warning: failed to update issue notes: %v\n", err)
 	}
 
@@ -215,14 +216,17 @@ func (h *AgentReportHandler) handleDecomposed(ctx context.Context, issue *types.
 	fmt.Printf("Reasoning: %s\n", report.Reasoning)
 	fmt.Printf("Creating epic with %d children...\n", len(report.Children))
 
-	// Step 1: Convert original issue to epic
-	updates := map[string]interface{}{
-		"issue_type":  types.TypeEpic,
-		"title":       report.Epic.Title,
-		"description": report.Epic.Description,
-		"notes":       fmt.Sprintf("Autonomously decomposed by agent: %s", report.Reasoning),
-	}
-	if err := h.store.UpdateIssue(ctx, issue.ID, updates, h.actor); err != nil {
+	// Step 1: Convert original issue to epic (keeping notes written since we read it)
+	note := fmt.Sprintf("Autonomously decomposed by agent: %s", report.Reasoning)
+	err := storage.ModifyIssue(ctx, h.store, issue.ID, h.actor, func(current *types.Issue) (map[string]interface{}, error) {
+		return map[string]interface{}{
+			"issue_type":  string(types.TypeEpic),
+			"title":       report.Epic.Title,
+			"description": report.Epic.Description,
+			"notes":       appendNote(current.Notes, note),
+		}, nil
+	})
+	if err != nil {
 		return false, fmt.Errorf("failed to convert issue to epic: %w", err)
 	}
 
@@ -355,3 +359,11 @@ func truncateTitle(title string) string {
 	}
 	return title[:maxLen-3] + "..."
 }
+
+// appendNote adds a note on its own paragraph after the existing notes
+func appendNote(notes, note string) string {
+	if strings.TrimSpace(notes) == "" {
+		return note
+	}
+	return strings.TrimRight(notes, "\n") + "\n\n" + note
+}
@@ -0,0 +1,176 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/labels"
+	"github.com/steveyegge/vc/internal/sandbox"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// unassessedConfidence stands in for the assessment confidence of issues
+// that were never assessed (e.g. without AI supervision)
+const unassessedConfidence = 0.5
+
+// mergeSignals are the inputs of the auto-merge score, all known once the
+// quality gates pass
+type mergeSignals struct {
+	Confidence    float64 // Assessment confidence, 0 if the issue wasn't assessed
+	FilesChanged  int
+	LinesChanged  int
+	TestsModified bool // Existing test code was changed or deleted
+	IssueType     types.IssueType
+}
+
+// scoreMerge rates how safe changes look to merge unreviewed, from 0 to 1.
+// It starts from the assessment's confidence and discounts large diffs,
+// changes to existing tests (which may have been loosened to pass), and
+// issue types that carry more design risk.
+func scoreMerge(s mergeSignals) float64 {
+	score := s.Confidence
+	if score <= 0 {
+		score = unassessedConfidence
+	}
+
+	switch {
+	case s.LinesChanged > 500:
+		score *= 0.5
+	case s.LinesChanged > 200:
+		score *= 0.75
+	case s.LinesChanged > 50:
+		score *= 0.9
+	}
+	if s.FilesChanged > 10 {
+		score *= 0.8
+	}
+	if s.TestsModified {
+		score *= 0.8
+	}
+	switch s.IssueType {
+	case types.TypeFeature:
+		score *= 0.9
+	case types.TypeEpic:
+		score *= 0.8
+	}
+	return math.Min(score, 1)
+}
+
+// diffSignals fills in the diff-derived signals. Tests count as modified
+// when a test file lost lines; new tests are not a risk.
+func diffSignals(signals *mergeSignals, diff *sandbox.DiffSummary) {
+	signals.FilesChanged = len(diff.Files)
+	signals.LinesChanged = diff.LinesChanged()
+	for _, file := range diff.Files {
+		if file.Deleted > 0 && isTestFile(file.Path) {
+			signals.TestsModified = true
+		}
+	}
+}
+
+// isTestFile reports whether path looks like test code
+func isTestFile(p string) bool {
+	base := path.Base(p)
+	if strings.HasSuffix(base, "_test.go") || strings.HasPrefix(base, "test_") ||
+		strings.Contains(base, ".test.") || strings.Contains(base, ".spec.") {
+		return true
+	}
+	for _, dir := range strings.Split(path.Dir(p), "/") {
+		if dir == "test" || dir == "tests" || dir == "__tests__" {
+			return true
+		}
+	}
+	return false
+}
+
+// decideMerge scores the sandbox's changes against the auto-merge threshold
+// and records the decision with its inputs as a merge_decision event. Changes
+// that reach the threshold are approved to merge at cleanup. The rest are
+// committed to the mission branch, pushed if there is a remote, and held for
+// review: the issue is blocked with a needs-review label and a comment with
+// the diff stat. It reports whether the changes were held. Changes whose diff
+// can't be measured are held.
+func (rp *ResultsProcessor) decideMerge(ctx context.Context, issue *types.Issue) bool {
+	fmt.Printf("\n=== Auto-merge Decision ===\n")
+
+	signals := mergeSignals{IssueType: issue.IssueType}
+	if record, err := rp.store.GetLatestAssessment(ctx, issue.ID); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to get assessment for auto-merge score: %v\n", err)
+	} else if record != nil {
+		signals.Confidence = record.Assessment.Confidence
+	}
+
+	diff, err := sandbox.Diff(ctx, rp.sandbox.GitWorktree)
+	score := 0.0
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to measure sandbox diff: %v (holding for review)\n", err)
+		diff = &sandbox.DiffSummary{}
+	} else {
+		diffSignals(&signals, diff)
+		score = scoreMerge(signals)
+	}
+	autoMerge := score >= rp.autoMergeThreshold
+
+	decision := "held for review"
+	if autoMerge {
+		decision = "auto-merged"
+	}
+	event, err := events.NewMergeDecisionEvent(issue.ID,
+		fmt.Sprintf("Changes %s: score %.2f, threshold %.2f", decision, score, rp.autoMergeThreshold),
+		events.MergeDecisionData{
+			Score:         score,
+			Threshold:     rp.autoMergeThreshold,
+			AutoMerged:    autoMerge,
+			Confidence:    signals.Confidence,
+			FilesChanged:  signals.FilesChanged,
+			LinesChanged:  signals.LinesChanged,
+			TestsModified: signals.TestsModified,
+			IssueType:     string(signals.IssueType),
+		})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to create merge_decision event: %v\n", err)
+	} else if err := rp.store.StoreAgentEvent(ctx, event); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to store merge_decision event: %v\n", err)
+	}
+
+	if autoMerge {
+		rp.sandbox.ApprovalStatus = "approved"
+		fmt.Printf("✓ Score %.2f reaches %.2f - changes will be merged to main\n", score, rp.autoMergeThreshold)
+		return false
+	}
+
+	fmt.Printf("✗ Score %.2f is below %.2f - holding changes for review\n", score, rp.autoMergeThreshold)
+	rp.sandbox.ApprovalStatus = sandbox.ApprovalStatusReviewRequired
+	where := fmt.Sprintf("kept locally on branch %s", rp.sandbox.GitBranch)
+	pushed, err := sandbox.PrepareForReview(ctx, rp.sandbox, fmt.Sprintf("%s: %s (held for review)", issue.ID, issue.Title))
+	switch {
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "warning: failed to prepare branch %s for review: %v\n", rp.sandbox.GitBranch, err)
+		where = fmt.Sprintf("on branch %s (committing or pushing failed: %v)", rp.sandbox.GitBranch, err)
+	case pushed:
+		where = fmt.Sprintf("pushed to origin as %s", rp.sandbox.GitBranch)
+	}
+
+	stat := diff.Stat
+	if stat == "" {
+		stat = "(diff unavailable)"
+	}
+	comment := fmt.Sprintf("**Review Required**\n\nThe quality gates passed, but the auto-merge score %.2f is below the threshold %.2f "+
+		"(confidence %.2f, %d files, %d lines changed, tests modified: %v). The changes are %s.\n\n```\n%s\n```",
+		score, rp.autoMergeThreshold, signals.Confidence, signals.FilesChanged, signals.LinesChanged, signals.TestsModified, where, stat)
+	if err := rp.store.AddComment(ctx, issue.ID, rp.actor, comment); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to add review comment: %v\n", err)
+	}
+	if err := rp.store.AddLabel(ctx, issue.ID, labels.LabelNeedsReview, rp.actor); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to add needs-review label: %v\n", err)
+	}
+	if err := rp.store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"status": string(types.StatusBlocked)}, rp.actor); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to update issue to blocked: %v\n", err)
+	}
+	return true
+}
@@ -0,0 +1,181 @@
+package executor
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/labels"
+	"github.com/steveyegge/vc/internal/sandbox"
+	"github.com/steveyegge/vc/internal/storage/storagetest"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestScoreMerge(t *testing.T) {
+	tests := []struct {
+		name    string
+		signals mergeSignals
+		want    float64
+	}{
+		{"small confident bug fix", mergeSignals{Confidence: 0.9, FilesChanged: 1, LinesChanged: 12, IssueType: types.TypeBug}, 0.9},
+		{"unassessed", mergeSignals{FilesChanged: 1, LinesChanged: 12, IssueType: types.TypeTask}, 0.5},
+		{"medium diff", mergeSignals{Confidence: 0.9, FilesChanged: 3, LinesChanged: 120, IssueType: types.TypeTask}, 0.81},
+		{"large feature touching tests", mergeSignals{Confidence: 0.9, FilesChanged: 12, LinesChanged: 800, TestsModified: true, IssueType: types.TypeFeature}, 0.2592},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scoreMerge(tt.signals); got < tt.want-1e-9 || got > tt.want+1e-9 {
+				t.Errorf("scoreMerge(%+v) = %.4f, want %.4f", tt.signals, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffSignals(t *testing.T) {
+	var signals mergeSignals
+	diffSignals(&signals, &sandbox.DiffSummary{Files: []sandbox.FileDiff{
+		{Path: "parser.go", Added: 10, Deleted: 2},
+		{Path: "parser_test.go", Added: 30},
+		{Path: "web/__tests__/form.js", Added: 1, Deleted: 1},
+	}})
+	if signals.FilesChanged != 3 || signals.LinesChanged != 44 || !signals.TestsModified {
+		t.Errorf("Unexpected signals %+v", signals)
+	}
+
+	// New tests alone are no risk
+	signals = mergeSignals{}
+	diffSignals(&signals, &sandbox.DiffSummary{Files: []sandbox.FileDiff{{Path: "parser_test.go", Added: 30}}})
+	if signals.TestsModified {
+		t.Error("Expected added tests not to count as modified")
+	}
+
+	for path, want := range map[string]bool{
+		"internal/parser/parser_test.go": true,
+		"tests/test_cli.py":              true,
+		"src/form.spec.ts":               true,
+		"internal/parser/parser.go":      false,
+		"docs/testing.md":                false,
+	} {
+		if got := isTestFile(path); got != want {
+			t.Errorf("isTestFile(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+// newReviewRepo returns a git repository on a mission branch, with one
+// committed file, as a sandbox
+func newReviewRepo(t *testing.T) *sandbox.Sandbox {
+	t.Helper()
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"config", "user.email", "vc@example.com"},
+		{"config", "user.name", "vc"},
+		{"add", "."},
+		{"commit", "-q", "-m", "initial"},
+		{"checkout", "-q", "-b", "mission/vc-1"},
+	} {
+		if args[0] == "add" {
+			if err := os.WriteFile(filepath.Join(dir, "parser.go"), []byte("package parser\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v (%s)", args, err, output)
+		}
+	}
+	return &sandbox.Sandbox{Path: dir, GitWorktree: dir, ParentRepo: dir, GitBranch: "mission/vc-1"}
+}
+
+func TestDecideMerge(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("auto-merge", func(t *testing.T) {
+		store := storagetest.NewFakeStorage()
+		issue := &types.Issue{Title: "Fix parser", Status: types.StatusInProgress, Priority: 1, IssueType: types.TypeBug}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.SaveAssessment(ctx, &types.AssessmentRecord{IssueID: issue.ID, Attempt: 1, Assessment: types.Assessment{Confidence: 0.9}}); err != nil {
+			t.Fatal(err)
+		}
+		sb := newReviewRepo(t)
+		if err := os.WriteFile(filepath.Join(sb.GitWorktree, "parser.go"), []byte("package parser\n\nconst Version = 2\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		rp := &ResultsProcessor{store: store, actor: "test", sandbox: sb, autoMergeThreshold: 0.8}
+		if rp.decideMerge(ctx, issue) {
+			t.Fatal("Expected a small confident fix to merge")
+		}
+		if sb.ApprovalStatus != "approved" {
+			t.Errorf("Expected the sandbox approved, got %q", sb.ApprovalStatus)
+		}
+		decisions, err := store.GetAgentEvents(ctx, events.EventFilter{IssueID: issue.ID, Type: events.EventTypeMergeDecision})
+		if err != nil || len(decisions) != 1 {
+			t.Fatalf("Expected one merge_decision event, got %d (err %v)", len(decisions), err)
+		}
+		data, err := decisions[0].GetMergeDecisionData()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !data.AutoMerged || data.Confidence != 0.9 || data.FilesChanged != 1 || data.LinesChanged != 2 || data.IssueType != "bug" {
+			t.Errorf("Unexpected decision data %+v", data)
+		}
+	})
+
+	t.Run("held for review", func(t *testing.T) {
+		store := storagetest.NewFakeStorage()
+		issue := &types.Issue{Title: "Rewrite parser", Status: types.StatusInProgress, Priority: 1, IssueType: types.TypeFeature}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatal(err)
+		}
+		sb := newReviewRepo(t)
+		if err := os.WriteFile(filepath.Join(sb.GitWorktree, "lexer.go"), []byte("package parser\n"+strings.Repeat("// token\n", 300)), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		rp := &ResultsProcessor{store: store, actor: "test", sandbox: sb, autoMergeThreshold: 0.8}
+		if !rp.decideMerge(ctx, issue) {
+			t.Fatal("Expected a large unassessed feature to be held")
+		}
+		if sb.ApprovalStatus != sandbox.ApprovalStatusReviewRequired {
+			t.Errorf("Expected the sandbox held for review, got %q", sb.ApprovalStatus)
+		}
+
+		updated, err := store.GetIssue(ctx, issue.ID)
+		if err != nil || updated.Status != types.StatusBlocked {
+			t.Errorf("Expected the issue blocked, got %+v (err %v)", updated, err)
+		}
+		issueLabels, _ := store.GetLabels(ctx, issue.ID)
+		if !slices.Contains(issueLabels, labels.LabelNeedsReview) {
+			t.Errorf("Expected the needs-review label, got %v", issueLabels)
+		}
+		history, _ := store.GetEvents(ctx, issue.ID, 0)
+		var commented bool
+		for _, event := range history {
+			if event.EventType == types.EventCommented && strings.Contains(*event.Comment, "**Review Required**") &&
+				strings.Contains(*event.Comment, "kept locally on branch mission/vc-1") && strings.Contains(*event.Comment, "lexer.go") {
+				commented = true
+			}
+		}
+		if !commented {
+			t.Error("Expected a review comment with the diff stat")
+		}
+
+		// The changes are committed to the mission branch for the reviewer
+		cmd := exec.Command("git", "log", "-1", "--format=%s", "mission/vc-1")
+		cmd.Dir = sb.GitWorktree
+		output, err := cmd.Output()
+		if err != nil || !strings.Contains(string(output), "(held for review)") {
+			t.Errorf("Expected the changes committed for review, got %q (err %v)", output, err)
+		}
+	})
+}
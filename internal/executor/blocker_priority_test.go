@@ -205,6 +205,35 @@ func TestGetNextReadyBlocker_PriorityOrdering(t *testing.T) {
 	}
 }
 
+func TestGetNextReadyBlocker_ProjectScope(t *testing.T) {
+	ctx, store, exec := setupExecutorTest(t)
+	defer store.Close()
+
+	if err := store.CreateProject(ctx, &types.Project{Name: "web", Prefix: "web"}); err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+	// The default project's blocker has the higher priority
+	other := &types.Issue{Title: "Core blocker", Status: types.StatusOpen, Priority: 0, IssueType: types.TypeBug}
+	mine := &types.Issue{Title: "Web blocker", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeBug, Project: "web"}
+	for _, issue := range []*types.Issue{other, mine} {
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("Failed to create blocker: %v", err)
+		}
+		if err := store.AddLabel(ctx, issue.ID, "discovered:blocker", "test"); err != nil {
+			t.Fatalf("Failed to add label: %v", err)
+		}
+	}
+
+	exec.project = "web"
+	result, err := exec.getNextReadyBlocker(ctx)
+	if err != nil {
+		t.Fatalf("getNextReadyBlocker failed: %v", err)
+	}
+	if result == nil || result.ID != mine.ID {
+		t.Errorf("Expected the web project's blocker %s, got %v", mine.ID, result)
+	}
+}
+
 func TestCheckMissionConvergence_NotABlocker(t *testing.T) {
 	ctx, store, exec := setupExecutorTest(t)
 	defer store.Close()
@@ -3,6 +3,7 @@ package executor
 import (
 	"context"
 
+	"github.com/steveyegge/vc/internal/events"
 	"github.com/steveyegge/vc/internal/gates"
 	"github.com/steveyegge/vc/internal/types"
 )
@@ -33,9 +34,17 @@ type PromptContext struct {
 	// GitState captures the current git repository state
 	GitState *GitState
 
+	// Assessment is the issue's latest AI assessment, with the steps
+	// previous attempts reported done (nil if never assessed)
+	Assessment *types.AssessmentRecord
+
 	// ResumeHint provides AI with context about where execution left off
 	// Used for resuming after crashes or partial completion
 	ResumeHint string
+
+	// FailureAnalysis is the AI supervisor's diagnosis of the latest failed
+	// attempt and its suggested fix (nil if none was made)
+	FailureAnalysis *events.FailureAnalysisData
 }
 
 // RelatedIssues contains all issues related to the current issue through various
@@ -102,7 +111,19 @@ type ContextGatherer interface {
 	// Returns attempts in chronological order (oldest first)
 	GetPreviousAttempts(ctx context.Context, issueID string) ([]*types.ExecutionAttempt, error)
 
+	// GetFailureAnalysis retrieves the AI supervisor's diagnosis of the
+	// latest failed attempt. Returns nil if none was made.
+	GetFailureAnalysis(ctx context.Context, issueID string) (*events.FailureAnalysisData, error)
+
 	// AnalyzeResumeState examines sandbox state and previous attempts to determine
 	// where execution left off. Returns a human-readable hint for the AI.
 	AnalyzeResumeState(ctx context.Context, sandbox interface{}, attempts []*types.ExecutionAttempt) (string, error)
+
+	// GetEstimateHint tells how long similar closed issues took against
+	// their estimates, for the assessment. Returns "" without enough history.
+	GetEstimateHint(ctx context.Context, issue *types.Issue) (string, error)
+
+	// GetRelevantFiles predicts the files work on an issue will change,
+	// relative to the repository root. Returns nil if it can't tell.
+	GetRelevantFiles(ctx context.Context, issue *types.Issue) ([]string, error)
 }
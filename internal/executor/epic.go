@@ -14,11 +14,18 @@ import (
 	"github.com/steveyegge/vc/internal/types"
 )
 
+// epicStore is the storage epic completion needs: the epic and its children,
+// the events it logs, and (for missions) the sandbox cleanup
+type epicStore interface {
+	storage.IssueStore
+	storage.EventStore
+}
+
 // checkEpicCompletion checks if an issue's parent epic is now complete
 // Uses AI to assess completion based on objectives, not just counting closed children
 // If the epic is closed and is a mission, automatically cleans up the mission sandbox (vc-245)
 // vc-276: Added instanceID parameter to properly attribute events to the executor instance
-func checkEpicCompletion(ctx context.Context, store storage.Storage, supervisor *ai.Supervisor, sandboxMgr sandbox.Manager, instanceID string, issueID string) error {
+func checkEpicCompletion(ctx context.Context, store epicStore, supervisor *ai.Supervisor, sandboxMgr sandbox.Manager, instanceID string, issueID string) error {
 	// Get the issue to check its parent
 	issue, err := store.GetIssue(ctx, issueID)
 	if err != nil {
@@ -62,7 +69,7 @@ func checkEpicCompletion(ctx context.Context, store storage.Storage, supervisor
 // Uses AI assessment instead of hardcoded "all children closed" logic (ZFC compliance)
 // Returns (closed bool, error) indicating whether the epic was closed
 // vc-276: Added instanceID parameter to properly attribute events to the executor instance
-func checkAndCloseEpicIfComplete(ctx context.Context, store storage.Storage, supervisor *ai.Supervisor, instanceID string, epicID string) (bool, error) {
+func checkAndCloseEpicIfComplete(ctx context.Context, store epicStore, supervisor *ai.Supervisor, instanceID string, epicID string) (bool, error) {
 	// Get the epic
 	epic, err := store.GetIssue(ctx, epicID)
 	if err != nil {
@@ -214,7 +221,7 @@ func checkAndCloseEpicIfComplete(ctx context.Context, store storage.Storage, sup
 // cleanupMissionSandboxIfComplete checks if a closed epic is a mission and cleans up its sandbox
 // This is called after checkAndCloseEpicIfComplete successfully closes an epic
 // vc-276: Added instanceID parameter to properly attribute events to the executor instance
-func cleanupMissionSandboxIfComplete(ctx context.Context, store storage.Storage, sandboxMgr sandbox.Manager, instanceID string, epicID string) error {
+func cleanupMissionSandboxIfComplete(ctx context.Context, store epicStore, sandboxMgr sandbox.Manager, instanceID string, epicID string) error {
 	// Check if this epic has mission metadata (is a mission epic)
 	mission, err := store.GetMission(ctx, epicID)
 	if err != nil {
@@ -241,10 +248,26 @@ func cleanupMissionSandboxIfComplete(ctx context.Context, store storage.Storage,
 	logEpicCleanupStartedEvent(ctx, store, epicID, instanceID,
 		fmt.Sprintf("Starting cleanup for mission epic %s", epicID), startEventData)
 
+	diskBytes := sandboxDiskBytes(sandboxMgr, mission.SandboxPath)
 	startTime := time.Now()
 	cleanupErr := sandbox.CleanupMissionSandbox(ctx, sandboxMgr, store, epicID)
 	duration := time.Since(startTime)
 
+	// Record sandbox lifecycle metrics alongside the epic cleanup events
+	if mission.SandboxPath != "" {
+		lifecycleType := events.EventTypeSandboxCleaned
+		if cleanupErr != nil {
+			lifecycleType = events.EventTypeSandboxCleanupFailed
+		}
+		sb := &sandbox.Sandbox{
+			ID:        fmt.Sprintf("mission-%s", epicID),
+			MissionID: epicID,
+			Path:      mission.SandboxPath,
+			GitBranch: mission.BranchName,
+		}
+		logSandboxLifecycleEvent(ctx, store, instanceID, lifecycleType, epicID, sb, duration, diskBytes, cleanupErr)
+	}
+
 	// vc-268: Emit epic_cleanup_completed event (vc-275: using typed constructor)
 	completeEventData := events.EpicCleanupCompletedData{
 		EpicID:      epicID,
@@ -274,7 +297,7 @@ func cleanupMissionSandboxIfComplete(ctx context.Context, store storage.Storage,
 }
 
 // logEpicCompletedEvent creates and stores an epic_completed event using typed constructor (vc-275)
-func logEpicCompletedEvent(ctx context.Context, store storage.Storage, issueID, executorID, message string, data events.EpicCompletedData) {
+func logEpicCompletedEvent(ctx context.Context, store storage.EventStore, issueID, executorID, message string, data events.EpicCompletedData) {
 	// Skip logging if context is canceled (e.g., during shutdown)
 	if ctx.Err() != nil {
 		return
@@ -293,7 +316,7 @@ func logEpicCompletedEvent(ctx context.Context, store storage.Storage, issueID,
 }
 
 // logEpicCleanupStartedEvent creates and stores an epic_cleanup_started event using typed constructor (vc-275)
-func logEpicCleanupStartedEvent(ctx context.Context, store storage.Storage, issueID, executorID, message string, data events.EpicCleanupStartedData) {
+func logEpicCleanupStartedEvent(ctx context.Context, store storage.EventStore, issueID, executorID, message string, data events.EpicCleanupStartedData) {
 	// Skip logging if context is canceled (e.g., during shutdown)
 	if ctx.Err() != nil {
 		return
@@ -312,7 +335,7 @@ func logEpicCleanupStartedEvent(ctx context.Context, store storage.Storage, issu
 }
 
 // logEpicCleanupCompletedEvent creates and stores an epic_cleanup_completed event using typed constructor (vc-275)
-func logEpicCleanupCompletedEvent(ctx context.Context, store storage.Storage, issueID, executorID, message string, severity events.EventSeverity, data events.EpicCleanupCompletedData) {
+func logEpicCleanupCompletedEvent(ctx context.Context, store storage.EventStore, issueID, executorID, message string, severity events.EventSeverity, data events.EpicCleanupCompletedData) {
 	// Skip logging if context is canceled (e.g., during shutdown)
 	if ctx.Err() != nil {
 		return
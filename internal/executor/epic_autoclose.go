@@ -0,0 +1,391 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// Config.EpicAutoClose modes: what the executor does with an epic once every
+// issue blocking it is closed
+const (
+	EpicAutoCloseOff   = "off"   // Nothing (default)
+	EpicAutoCloseLabel = "label" // Label it ready-to-close and comment
+	EpicAutoCloseClose = "close" // Close it with a comment listing the children
+)
+
+// Labels marking what epic auto-close did, so it can be undone when a
+// blocking child is reopened. Epics closed by hand are never reopened.
+const (
+	labelReadyToClose = "ready-to-close"
+	labelAutoClosed   = "auto-closed"
+)
+
+// maxEpicCascade bounds the epics closed or reopened per sweep. Closing an
+// epic can complete the epics it blocks in turn; the bound (and reconciling
+// each epic once) keeps deep or cyclic chains from running away. A sweep
+// that hits it keeps its mark, so the next one carries on.
+const maxEpicCascade = 100
+
+// epicSweepPage is how many recently updated issues a sweep reads at a time
+const epicSweepPage = 100
+
+// epicAutoCloser closes or labels epics whose blocking children are all
+// closed, and undoes that when one is reopened. It notices changes by
+// sweeping the issues updated since its last sweep, so closes from any
+// process (vc close, vc tui, other executors) count. Only touched by the
+// event loop.
+type epicAutoCloser struct {
+	store      epicStore
+	mode       string
+	project    string
+	instanceID string
+
+	started bool
+	// mark is the newest updated_at seen; atMark holds the issues updated
+	// exactly then, already handled
+	mark   time.Time
+	atMark map[string]bool
+}
+
+// newEpicAutoCloser returns nil for EpicAutoCloseOff
+func newEpicAutoCloser(store epicStore, mode, project, instanceID string) (*epicAutoCloser, error) {
+	switch mode {
+	case "", EpicAutoCloseOff:
+		return nil, nil
+	case EpicAutoCloseLabel, EpicAutoCloseClose:
+		return &epicAutoCloser{store: store, mode: mode, project: project, instanceID: instanceID}, nil
+	default:
+		return nil, fmt.Errorf("invalid epic auto-close mode %q (want %s, %s or %s)",
+			mode, EpicAutoCloseOff, EpicAutoCloseLabel, EpicAutoCloseClose)
+	}
+}
+
+// sweep reconciles the epics affected by the issues updated since the last
+// sweep. The first sweep reconciles every epic, catching up on changes made
+// while no executor ran.
+func (a *epicAutoCloser) sweep(ctx context.Context) error {
+	if !a.started {
+		return a.sweepAll(ctx)
+	}
+
+	var changed []*types.Issue
+	newMark, newAtMark := a.mark, make(map[string]bool)
+	for offset := 0; ; offset += epicSweepPage {
+		page, err := a.store.SearchIssues(ctx, "", types.IssueFilter{
+			Project:    a.project,
+			OrderBy:    "updated_at",
+			Descending: true,
+			Limit:      epicSweepPage,
+			Offset:     offset,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to read updated issues: %w", err)
+		}
+		older := false
+		for _, issue := range page {
+			if issue.UpdatedAt.Before(a.mark) {
+				older = true
+				break
+			}
+			if issue.UpdatedAt.After(newMark) {
+				newMark, newAtMark = issue.UpdatedAt, make(map[string]bool)
+			}
+			if issue.UpdatedAt.Equal(newMark) {
+				newAtMark[issue.ID] = true
+			}
+			if issue.UpdatedAt.Equal(a.mark) && a.atMark[issue.ID] {
+				continue
+			}
+			changed = append(changed, issue)
+		}
+		if older || len(page) < epicSweepPage {
+			break
+		}
+	}
+	if newMark.Equal(a.mark) {
+		for id := range a.atMark {
+			newAtMark[id] = true
+		}
+	}
+
+	var epics []string
+	for _, issue := range changed {
+		if issue.IssueType == types.TypeEpic {
+			epics = append(epics, issue.ID)
+		}
+		blocked, err := a.blockedEpics(ctx, issue.ID)
+		if err != nil {
+			return err
+		}
+		epics = append(epics, blocked...)
+	}
+	if a.reconcile(ctx, epics) {
+		a.mark, a.atMark = newMark, newAtMark
+	}
+	return nil
+}
+
+// sweepAll reconciles every epic and sets the mark to the newest update
+func (a *epicAutoCloser) sweepAll(ctx context.Context) error {
+	// Read the mark first, so changes made during the pass are swept again
+	newest, err := a.store.SearchIssues(ctx, "", types.IssueFilter{
+		Project:    a.project,
+		OrderBy:    "updated_at",
+		Descending: true,
+		Limit:      1,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read updated issues: %w", err)
+	}
+	epicType := types.TypeEpic
+	epics, err := a.store.SearchIssues(ctx, "", types.IssueFilter{Project: a.project, IssueType: &epicType})
+	if err != nil {
+		return fmt.Errorf("failed to read epics: %w", err)
+	}
+	ids := make([]string, len(epics))
+	for i, epic := range epics {
+		ids[i] = epic.ID
+	}
+	if !a.reconcile(ctx, ids) {
+		return nil
+	}
+
+	a.started = true
+	a.atMark = make(map[string]bool)
+	if len(newest) > 0 {
+		a.mark = newest[0].UpdatedAt
+		a.atMark[newest[0].ID] = true
+	}
+	return nil
+}
+
+// reconcile works through the epics and then through the epics they block
+// whenever one is closed or reopened. An epic flips at most once per call,
+// so cycles end. Reports false if it stopped at maxEpicCascade.
+func (a *epicAutoCloser) reconcile(ctx context.Context, queue []string) bool {
+	flipped := make(map[string]bool)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if flipped[id] {
+			continue
+		}
+
+		changed, err := a.reconcileEpic(ctx, id)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: epic auto-close failed for %s: %v\n", id, err)
+			continue
+		}
+		if !changed {
+			continue
+		}
+		flipped[id] = true
+		if len(flipped) == maxEpicCascade {
+			fmt.Fprintf(os.Stderr, "warning: epic auto-close stopped after %d epics; the rest follow next poll\n", maxEpicCascade)
+			return false
+		}
+		blocked, err := a.blockedEpics(ctx, id)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: epic auto-close failed for the epics %s blocks: %v\n", id, err)
+			continue
+		}
+		queue = append(queue, blocked...)
+	}
+	return true
+}
+
+// reconcileEpic closes or labels the epic when every issue blocking it is
+// closed, and reopens or unlabels it when one no longer is. Reports whether
+// the epic's status changed.
+func (a *epicAutoCloser) reconcileEpic(ctx context.Context, epicID string) (bool, error) {
+	epic, err := a.store.GetIssue(ctx, epicID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get epic: %w", err)
+	}
+	if epic == nil || epic.Archived || epic.IssueType != types.TypeEpic {
+		return false, nil
+	}
+	children, err := a.blockingChildren(ctx, epicID)
+	if err != nil {
+		return false, err
+	}
+	epicLabels, err := a.store.GetLabels(ctx, epicID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get labels: %w", err)
+	}
+	autoClosed := hasLabel(epicLabels, labelAutoClosed)
+	readyToClose := hasLabel(epicLabels, labelReadyToClose)
+
+	var open []*types.Issue
+	for _, child := range children {
+		if child.Status != types.StatusClosed {
+			open = append(open, child)
+		}
+	}
+	done := len(children) > 0 && len(open) == 0
+	closed := epic.Status == types.StatusClosed
+
+	switch {
+	case closed && autoClosed && !done:
+		return true, a.reopen(ctx, epic, open)
+	case !closed && readyToClose && !done:
+		return false, a.unlabel(ctx, epic, open)
+	case closed || !done:
+		return false, nil
+	case a.mode == EpicAutoCloseClose:
+		return true, a.close(ctx, epic, children, readyToClose)
+	case !readyToClose:
+		return false, a.label(ctx, epic, children)
+	}
+	return false, nil
+}
+
+// close closes a completed epic, leaving the summary comment and the
+// auto-closed label that lets reopen undo it
+func (a *epicAutoCloser) close(ctx context.Context, epic *types.Issue, children []*types.Issue, readyToClose bool) error {
+	if err := a.store.AddComment(ctx, epic.ID, "executor", childSummary(fmt.Sprintf("closed, all %d blocking children are closed:", len(children)), children)); err != nil {
+		return fmt.Errorf("failed to comment: %w", err)
+	}
+	reason := fmt.Sprintf("All %d blocking children closed", len(children))
+	if err := a.store.CloseIssue(ctx, epic.ID, reason, "executor"); err != nil {
+		return fmt.Errorf("failed to close epic: %w", err)
+	}
+	if err := a.store.AddLabel(ctx, epic.ID, labelAutoClosed, "executor"); err != nil {
+		return fmt.Errorf("failed to add %s label: %w", labelAutoClosed, err)
+	}
+	if readyToClose {
+		if err := a.store.RemoveLabel(ctx, epic.ID, labelReadyToClose, "executor"); err != nil {
+			return fmt.Errorf("failed to remove %s label: %w", labelReadyToClose, err)
+		}
+	}
+	fmt.Printf("✓ Auto-closed epic %s: %s (all %d blocking children closed)\n", epic.ID, epic.Title, len(children))
+
+	logEpicCompletedEvent(ctx, a.store, epic.ID, a.instanceID,
+		fmt.Sprintf("Epic %s completed: %s (all %d blocking children closed)", epic.ID, epic.Title, len(children)),
+		events.EpicCompletedData{
+			EpicID:            epic.ID,
+			EpicTitle:         epic.Title,
+			ChildrenCompleted: len(children),
+			CompletionMethod:  "blocking_children_closed",
+			Confidence:        1.0,
+			IsMission:         epic.IssueSubtype == types.SubtypeMission,
+			Actor:             "executor",
+		})
+	return nil
+}
+
+// label marks a completed epic ready-to-close for a human to close
+func (a *epicAutoCloser) label(ctx context.Context, epic *types.Issue, children []*types.Issue) error {
+	if err := a.store.AddLabel(ctx, epic.ID, labelReadyToClose, "executor"); err != nil {
+		return fmt.Errorf("failed to add %s label: %w", labelReadyToClose, err)
+	}
+	if err := a.store.AddComment(ctx, epic.ID, "executor", childSummary(fmt.Sprintf("ready to close, all %d blocking children are closed:", len(children)), children)); err != nil {
+		return fmt.Errorf("failed to comment: %w", err)
+	}
+	fmt.Printf("Epic %s is ready to close: all %d blocking children closed\n", epic.ID, len(children))
+	return nil
+}
+
+// reopen reopens an auto-closed epic whose blocking children are no longer
+// all closed
+func (a *epicAutoCloser) reopen(ctx context.Context, epic *types.Issue, open []*types.Issue) error {
+	if err := a.store.UpdateIssue(ctx, epic.ID, map[string]interface{}{"status": types.StatusOpen}, "executor"); err != nil {
+		return fmt.Errorf("failed to reopen epic: %w", err)
+	}
+	if err := a.store.RemoveLabel(ctx, epic.ID, labelAutoClosed, "executor"); err != nil {
+		return fmt.Errorf("failed to remove %s label: %w", labelAutoClosed, err)
+	}
+	if err := a.store.AddComment(ctx, epic.ID, "executor", childSummary(fmt.Sprintf("reopened, %d blocking children are open again:", len(open)), open)); err != nil {
+		return fmt.Errorf("failed to comment: %w", err)
+	}
+	fmt.Printf("Reopened epic %s: %s (%d blocking children open again)\n", epic.ID, epic.Title, len(open))
+	return nil
+}
+
+// unlabel takes ready-to-close off an epic whose blocking children are no
+// longer all closed
+func (a *epicAutoCloser) unlabel(ctx context.Context, epic *types.Issue, open []*types.Issue) error {
+	if err := a.store.RemoveLabel(ctx, epic.ID, labelReadyToClose, "executor"); err != nil {
+		return fmt.Errorf("failed to remove %s label: %w", labelReadyToClose, err)
+	}
+	if err := a.store.AddComment(ctx, epic.ID, "executor", childSummary(fmt.Sprintf("no longer ready to close, %d blocking children are open again:", len(open)), open)); err != nil {
+		return fmt.Errorf("failed to comment: %w", err)
+	}
+	fmt.Printf("Epic %s is no longer ready to close: %d blocking children open again\n", epic.ID, len(open))
+	return nil
+}
+
+// blockingChildren returns the issues blocking the epic (archived ones are
+// left out)
+func (a *epicAutoCloser) blockingChildren(ctx context.Context, epicID string) ([]*types.Issue, error) {
+	records, err := a.store.GetDependencyRecords(ctx, epicID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dependencies of %s: %w", epicID, err)
+	}
+	var children []*types.Issue
+	for _, dep := range records {
+		if dep.Type != types.DepBlocks {
+			continue
+		}
+		child, err := a.store.GetIssue(ctx, dep.DependsOnID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s: %w", dep.DependsOnID, err)
+		}
+		if child != nil && !child.Archived {
+			children = append(children, child)
+		}
+	}
+	return children, nil
+}
+
+// blockedEpics returns the epics the issue blocks
+func (a *epicAutoCloser) blockedEpics(ctx context.Context, issueID string) ([]string, error) {
+	dependents, err := a.store.GetDependents(ctx, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dependents of %s: %w", issueID, err)
+	}
+	var epics []string
+	for _, dependent := range dependents {
+		if dependent.IssueType != types.TypeEpic || (a.project != "" && dependent.Project != a.project) {
+			continue
+		}
+		records, err := a.store.GetDependencyRecords(ctx, dependent.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get dependencies of %s: %w", dependent.ID, err)
+		}
+		for _, dep := range records {
+			if dep.DependsOnID == issueID && dep.Type == types.DepBlocks {
+				epics = append(epics, dependent.ID)
+				break
+			}
+		}
+	}
+	return epics, nil
+}
+
+// childSummary is the comment epic auto-close leaves: heading, then the
+// children it acted on
+func childSummary(heading string, children []*types.Issue) string {
+	var b strings.Builder
+	b.WriteString("Epic auto-close: " + heading + "\n")
+	for _, child := range children {
+		fmt.Fprintf(&b, "- %s: %s (%s)\n", child.ID, child.Title, child.Status)
+	}
+	return b.String()
+}
+
+// hasLabel reports whether label is in labels
+func hasLabel(labels []string, label string) bool {
+	for _, l := range labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
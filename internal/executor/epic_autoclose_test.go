@@ -0,0 +1,280 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage/storagetest"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// newEpicFixture creates an epic blocked by n tasks and returns their IDs
+func newEpicFixture(t *testing.T, store *storagetest.FakeStorage, n int) (string, []string) {
+	t.Helper()
+	ctx := context.Background()
+	epic := &types.Issue{Title: "Epic", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeEpic}
+	if err := store.CreateIssue(ctx, epic, "test"); err != nil {
+		t.Fatal(err)
+	}
+	var children []string
+	for i := 0; i < n; i++ {
+		child := &types.Issue{Title: fmt.Sprintf("Task %d", i+1), Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, child, "test"); err != nil {
+			t.Fatal(err)
+		}
+		blockEpic(t, store, epic.ID, child.ID)
+		children = append(children, child.ID)
+	}
+	return epic.ID, children
+}
+
+// blockEpic makes child block epic
+func blockEpic(t *testing.T, store *storagetest.FakeStorage, epic, child string) {
+	t.Helper()
+	dep := &types.Dependency{IssueID: epic, DependsOnID: child, Type: types.DepBlocks}
+	if err := store.AddDependency(context.Background(), dep, "test"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// sweepEpics runs one sweep of the auto-closer, failing the test on error
+func sweepEpics(t *testing.T, a *epicAutoCloser) {
+	t.Helper()
+	if err := a.sweep(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// epicState returns the issue's status and whether it has the label
+func epicState(t *testing.T, store *storagetest.FakeStorage, id, label string) (types.Status, bool) {
+	t.Helper()
+	ctx := context.Background()
+	issue, err := store.GetIssue(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	labels, err := store.GetLabels(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return issue.Status, hasLabel(labels, label)
+}
+
+// lastComment returns the newest comment on the issue (events come newest
+// first)
+func lastComment(t *testing.T, store *storagetest.FakeStorage, id string) string {
+	t.Helper()
+	evts, err := store.GetEvents(context.Background(), id, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, event := range evts {
+		if event.EventType == types.EventCommented && event.Comment != nil {
+			return *event.Comment
+		}
+	}
+	return ""
+}
+
+func TestNewEpicAutoCloser(t *testing.T) {
+	store := storagetest.NewFakeStorage()
+	for _, mode := range []string{"", EpicAutoCloseOff} {
+		if a, err := newEpicAutoCloser(store, mode, "", "exec"); a != nil || err != nil {
+			t.Errorf("Mode %q: expected no auto-closer, got %v, %v", mode, a, err)
+		}
+	}
+	if _, err := newEpicAutoCloser(store, "sometimes", "", "exec"); err == nil {
+		t.Error("Expected an invalid mode to fail")
+	}
+}
+
+func TestEpicAutoCloseClose(t *testing.T) {
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+	epic, children := newEpicFixture(t, store, 2)
+	a, err := newEpicAutoCloser(store, EpicAutoCloseClose, "", "exec")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sweepEpics(t, a)
+
+	if err := store.CloseIssue(ctx, children[0], "done", "test"); err != nil {
+		t.Fatal(err)
+	}
+	sweepEpics(t, a)
+	if status, _ := epicState(t, store, epic, labelAutoClosed); status != types.StatusOpen {
+		t.Fatalf("Epic closed with a blocking child open: %s", status)
+	}
+
+	if err := store.CloseIssue(ctx, children[1], "done", "test"); err != nil {
+		t.Fatal(err)
+	}
+	sweepEpics(t, a)
+	status, labeled := epicState(t, store, epic, labelAutoClosed)
+	if status != types.StatusClosed || !labeled {
+		t.Fatalf("Expected the epic closed and labeled %s, got %s (labeled %v)", labelAutoClosed, status, labeled)
+	}
+	comment := lastComment(t, store, epic)
+	for _, want := range []string{"all 2 blocking children are closed", children[0] + ": Task 1", children[1] + ": Task 2"} {
+		if !strings.Contains(comment, want) {
+			t.Errorf("Summary comment lacks %q:\n%s", want, comment)
+		}
+	}
+	agentEvents, err := store.GetAgentEventsByIssue(ctx, epic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(agentEvents) != 1 || agentEvents[0].Type != events.EventTypeEpicCompleted {
+		t.Errorf("Expected one epic_completed event, got %d", len(agentEvents))
+	}
+
+	// Another sweep changes nothing
+	sweepEpics(t, a)
+	if agentEvents, _ := store.GetAgentEventsByIssue(ctx, epic); len(agentEvents) != 1 {
+		t.Errorf("Closed epic completed again: %d events", len(agentEvents))
+	}
+
+	// Reopening a child reopens the epic
+	if err := store.UpdateIssue(ctx, children[1], map[string]interface{}{"status": types.StatusOpen}, "test"); err != nil {
+		t.Fatal(err)
+	}
+	sweepEpics(t, a)
+	status, labeled = epicState(t, store, epic, labelAutoClosed)
+	if status != types.StatusOpen || labeled {
+		t.Fatalf("Expected the epic reopened without %s, got %s (labeled %v)", labelAutoClosed, status, labeled)
+	}
+	if comment := lastComment(t, store, epic); !strings.Contains(comment, "reopened") || !strings.Contains(comment, children[1]) {
+		t.Errorf("Reopen comment should name the reopened child:\n%s", comment)
+	}
+
+	// And closing it again closes the epic again
+	if err := store.CloseIssue(ctx, children[1], "done again", "test"); err != nil {
+		t.Fatal(err)
+	}
+	sweepEpics(t, a)
+	if status, _ := epicState(t, store, epic, labelAutoClosed); status != types.StatusClosed {
+		t.Errorf("Expected the epic closed again, got %s", status)
+	}
+}
+
+func TestEpicAutoCloseLabel(t *testing.T) {
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+	epic, children := newEpicFixture(t, store, 2)
+	a, err := newEpicAutoCloser(store, EpicAutoCloseLabel, "", "exec")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sweepEpics(t, a)
+
+	for _, child := range children {
+		if err := store.CloseIssue(ctx, child, "done", "test"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sweepEpics(t, a)
+	status, labeled := epicState(t, store, epic, labelReadyToClose)
+	if status != types.StatusOpen || !labeled {
+		t.Fatalf("Expected the epic open and labeled %s, got %s (labeled %v)", labelReadyToClose, status, labeled)
+	}
+	if comment := lastComment(t, store, epic); !strings.Contains(comment, "ready to close") {
+		t.Errorf("Expected a ready-to-close comment, got:\n%s", comment)
+	}
+
+	if err := store.UpdateIssue(ctx, children[0], map[string]interface{}{"status": types.StatusOpen}, "test"); err != nil {
+		t.Fatal(err)
+	}
+	sweepEpics(t, a)
+	if _, labeled := epicState(t, store, epic, labelReadyToClose); labeled {
+		t.Errorf("Expected %s removed after a child reopened", labelReadyToClose)
+	}
+}
+
+// TestEpicAutoCloseStartup verifies the first sweep catches epics completed
+// while no executor ran, and leaves epics closed by hand alone
+func TestEpicAutoCloseStartup(t *testing.T) {
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+	done, doneChildren := newEpicFixture(t, store, 1)
+	manual, manualChildren := newEpicFixture(t, store, 1)
+	empty, _ := newEpicFixture(t, store, 0)
+	for _, child := range append(doneChildren, manualChildren...) {
+		if err := store.CloseIssue(ctx, child, "done", "test"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.CloseIssue(ctx, manual, "closed by hand", "test"); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := newEpicAutoCloser(store, EpicAutoCloseClose, "", "exec")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sweepEpics(t, a)
+	if status, _ := epicState(t, store, done, labelAutoClosed); status != types.StatusClosed {
+		t.Errorf("Expected the completed epic closed on startup, got %s", status)
+	}
+	if status, _ := epicState(t, store, empty, labelAutoClosed); status != types.StatusOpen {
+		t.Errorf("An epic without blocking children was closed")
+	}
+
+	// Reopening a child of an epic closed by hand leaves it closed
+	if err := store.UpdateIssue(ctx, manualChildren[0], map[string]interface{}{"status": types.StatusOpen}, "test"); err != nil {
+		t.Fatal(err)
+	}
+	sweepEpics(t, a)
+	if status, _ := epicState(t, store, manual, labelAutoClosed); status != types.StatusClosed {
+		t.Errorf("Epic closed by hand was reopened: %s", status)
+	}
+}
+
+// TestEpicAutoCloseDeepChain verifies a chain of epics, each blocking the
+// next, longer than maxEpicCascade closes over bounded sweeps and reopens
+// the same way
+func TestEpicAutoCloseDeepChain(t *testing.T) {
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+	first, children := newEpicFixture(t, store, 1)
+	chain := []string{first}
+	for len(chain) < maxEpicCascade+20 {
+		next, _ := newEpicFixture(t, store, 0)
+		blockEpic(t, store, next, chain[len(chain)-1])
+		chain = append(chain, next)
+	}
+	a, err := newEpicAutoCloser(store, EpicAutoCloseClose, "", "exec")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sweepEpics(t, a)
+
+	if err := store.CloseIssue(ctx, children[0], "done", "test"); err != nil {
+		t.Fatal(err)
+	}
+	sweepEpics(t, a)
+	if status, _ := epicState(t, store, chain[maxEpicCascade-1], labelAutoClosed); status != types.StatusClosed {
+		t.Fatalf("Expected the first %d epics closed in one sweep, got %s", maxEpicCascade, status)
+	}
+	last := chain[len(chain)-1]
+	if status, _ := epicState(t, store, last, labelAutoClosed); status != types.StatusOpen {
+		t.Fatalf("One sweep went past maxEpicCascade")
+	}
+	sweepEpics(t, a)
+	if status, _ := epicState(t, store, last, labelAutoClosed); status != types.StatusClosed {
+		t.Fatalf("Expected the rest of the chain closed by the next sweep, got %s", status)
+	}
+
+	if err := store.UpdateIssue(ctx, children[0], map[string]interface{}{"status": types.StatusOpen}, "test"); err != nil {
+		t.Fatal(err)
+	}
+	sweepEpics(t, a)
+	sweepEpics(t, a)
+	for _, id := range chain {
+		if status, _ := epicState(t, store, id, labelAutoClosed); status != types.StatusOpen {
+			t.Fatalf("Expected %s reopened with the chain, got %s", id, status)
+		}
+	}
+}
@@ -32,7 +32,7 @@ func TestEventCleanupIntegration(t *testing.T) {
 		CleanupBatchSize:      10,
 		CleanupEnabled:        true,
 		CleanupStrategy:       "oldest_non_critical",
-		CleanupVacuum:         false,
+		VacuumFreeFraction:    0,
 	}
 
 	cfg := DefaultConfig()
@@ -0,0 +1,176 @@
+package executor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/steveyegge/vc/internal/events"
+)
+
+// DefaultEventCoalesceWindow is how long identical executor warnings are
+// counted before a summary of them is written
+const DefaultEventCoalesceWindow = 10 * time.Minute
+
+// eventCoalescer keeps failure loops from flooding vc_agent_events. The
+// first of a run of identical executor warnings (same type, issue and
+// message template) is stored; the repeats within the window are only
+// counted, and one summary event ("Previous message repeated 240 times in
+// 20m0s") is stored when the window closes, the message changes or the
+// executor stops. Errors, agent-produced events and exempt types are never
+// held back. A nil *eventCoalescer passes every event through.
+type eventCoalescer struct {
+	window time.Duration
+	exempt map[events.EventType]bool
+	now    func() time.Time
+
+	mu   sync.Mutex
+	runs map[coalesceKey]*coalesceRun
+}
+
+// coalesceKey identifies a stream of events; a new message template on it
+// ends the run
+type coalesceKey struct {
+	eventType events.EventType
+	issueID   string
+}
+
+// coalesceRun is a stored event and the repeats held back since
+type coalesceRun struct {
+	template string
+	started  time.Time
+	lastAt   time.Time
+	repeats  int
+	last     *events.AgentEvent
+}
+
+// newEventCoalescer returns a coalescer, or nil when window is zero
+func newEventCoalescer(window time.Duration, exempt []events.EventType) *eventCoalescer {
+	if window <= 0 {
+		return nil
+	}
+	c := &eventCoalescer{
+		window: window,
+		exempt: make(map[events.EventType]bool, len(exempt)),
+		now:    time.Now,
+		runs:   make(map[coalesceKey]*coalesceRun),
+	}
+	for _, t := range exempt {
+		c.exempt[t] = true
+	}
+	return c
+}
+
+// coalesces reports whether event may be held back
+func (c *eventCoalescer) coalesces(event *events.AgentEvent) bool {
+	return c != nil && event.Severity == events.SeverityWarning && event.AgentID == "" && !c.exempt[event.Type]
+}
+
+// add returns the events to store for event, oldest first: none while it
+// repeats the open run, else the summary of the run it ends, if any, and
+// event itself
+func (c *eventCoalescer) add(event *events.AgentEvent) []*events.AgentEvent {
+	if !c.coalesces(event) {
+		return []*events.AgentEvent{event}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := coalesceKey{eventType: event.Type, issueID: event.IssueID}
+	template := messageTemplate(event.Message)
+	now := c.now()
+	run := c.runs[key]
+	if run != nil && run.template == template && now.Sub(run.started) < c.window {
+		run.repeats++
+		run.lastAt, run.last = now, event
+		return nil
+	}
+
+	var out []*events.AgentEvent
+	if summary := run.summary(); summary != nil {
+		out = append(out, summary)
+	}
+	c.runs[key] = &coalesceRun{template: template, started: now, lastAt: now, last: event}
+	return append(out, event)
+}
+
+// flush ends the runs whose window has closed, or all of them, and returns
+// the summaries to store, oldest first
+func (c *eventCoalescer) flush(all bool) []*events.AgentEvent {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	var ended []*coalesceRun
+	for key, run := range c.runs {
+		if all || now.Sub(run.started) >= c.window {
+			ended = append(ended, run)
+			delete(c.runs, key)
+		}
+	}
+	sort.Slice(ended, func(i, j int) bool { return ended[i].started.Before(ended[j].started) })
+
+	var out []*events.AgentEvent
+	for _, run := range ended {
+		if summary := run.summary(); summary != nil {
+			out = append(out, summary)
+		}
+	}
+	return out
+}
+
+// summary returns the event standing in for the run's repeats, or nil if
+// there were none. It carries the last repeat's data, plus repeated (the
+// count) and repeated_over_ms (the time from the stored event to the last
+// repeat).
+func (r *coalesceRun) summary() *events.AgentEvent {
+	if r == nil || r.repeats == 0 {
+		return nil
+	}
+	span := r.lastAt.Sub(r.started)
+	data := make(map[string]interface{}, len(r.last.Data)+2)
+	for k, v := range r.last.Data {
+		data[k] = v
+	}
+	data["repeated"] = r.repeats
+	data["repeated_over_ms"] = span.Milliseconds()
+
+	return &events.AgentEvent{
+		ID:         uuid.New().String(),
+		Type:       r.last.Type,
+		Timestamp:  r.last.Timestamp,
+		IssueID:    r.last.IssueID,
+		ExecutorID: r.last.ExecutorID,
+		AgentID:    "",
+		Severity:   r.last.Severity,
+		Message:    fmt.Sprintf("Previous message repeated %d times in %s: %s", r.repeats, span.Round(time.Second), r.last.Message),
+		Data:       data,
+		SourceLine: 0,
+	}
+}
+
+// messageTemplate masks the numbers of a message, so "timed out after 5s
+// (3 timeouts)" and "timed out after 5s (4 timeouts)" repeat each other
+func messageTemplate(message string) string {
+	var b strings.Builder
+	inNumber := false
+	for _, r := range message {
+		if unicode.IsDigit(r) {
+			if !inNumber {
+				b.WriteByte('#')
+			}
+			inNumber = true
+			continue
+		}
+		inNumber = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
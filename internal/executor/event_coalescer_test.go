@@ -0,0 +1,163 @@
+package executor
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage/storagetest"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// newTestCoalescer returns a coalescer with a 20m window on a clock the
+// test moves with advance
+func newTestCoalescer(exempt ...events.EventType) (*eventCoalescer, func(time.Duration)) {
+	c := newEventCoalescer(20*time.Minute, exempt)
+	now := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+	return c, func(d time.Duration) { now = now.Add(d) }
+}
+
+func warning(message string) *events.AgentEvent {
+	return &events.AgentEvent{
+		Type:     events.EventTypeStorageTimeout,
+		IssueID:  types.SystemIssueID,
+		Severity: events.SeverityWarning,
+		Message:  message,
+		Data:     map[string]interface{}{"operation": "UpdateHeartbeat"},
+	}
+}
+
+func TestEventCoalescerBurst(t *testing.T) {
+	c, advance := newTestCoalescer()
+
+	if out := c.add(warning("failed to update heartbeat: database is locked (1)")); len(out) != 1 {
+		t.Fatalf("Expected the first warning stored, got %d events", len(out))
+	}
+	for i := 0; i < 240; i++ {
+		advance(4 * time.Second)
+		if out := c.add(warning("failed to update heartbeat: database is locked (2)")); len(out) != 0 {
+			t.Fatalf("Expected repeat %d held back, got %d events", i, len(out))
+		}
+	}
+
+	// A different message on the same stream ends the run
+	out := c.add(warning("failed to update heartbeat: disk I/O error"))
+	if len(out) != 2 {
+		t.Fatalf("Expected a summary and the new warning, got %d events", len(out))
+	}
+	summary := out[0]
+	if !strings.HasPrefix(summary.Message, "Previous message repeated 240 times in 16m0s: ") {
+		t.Errorf("Unexpected summary message: %q", summary.Message)
+	}
+	if summary.Type != events.EventTypeStorageTimeout || summary.IssueID != types.SystemIssueID || summary.Severity != events.SeverityWarning {
+		t.Errorf("Expected the summary to keep type, issue and severity, got %+v", summary)
+	}
+	if summary.Data["repeated"] != 240 || summary.Data["repeated_over_ms"] != (16*time.Minute).Milliseconds() || summary.Data["operation"] != "UpdateHeartbeat" {
+		t.Errorf("Unexpected summary data: %v", summary.Data)
+	}
+	if out[1].Message != "failed to update heartbeat: disk I/O error" {
+		t.Errorf("Expected the new warning after the summary, got %q", out[1].Message)
+	}
+}
+
+func TestEventCoalescerSteadyState(t *testing.T) {
+	c, advance := newTestCoalescer()
+	stored := 0
+	// One warning every 5s for an hour: one stored warning per 20m window,
+	// and the summary of each window that closed
+	for i := 0; i < 720; i++ {
+		stored += len(c.add(warning("Storage call UpdateHeartbeat timed out after 5s")))
+		stored += len(c.flush(false))
+		advance(5 * time.Second)
+	}
+	if stored != 5 {
+		t.Errorf("Expected 3 warnings and 2 summaries, got %d events", stored)
+	}
+	if out := c.flush(false); len(out) != 1 || out[0].Data["repeated"] != 239 {
+		t.Errorf("Expected the last window's summary once it closed, got %d events", len(out))
+	}
+
+	// Other streams and other events aren't held back
+	other := warning("Storage call UpdateHeartbeat timed out after 5s")
+	other.IssueID = "vc-1"
+	if len(c.add(other)) != 1 {
+		t.Error("Expected a warning about another issue stored")
+	}
+	failed := warning("Storage call UpdateHeartbeat timed out after 5s")
+	failed.Severity = events.SeverityError
+	if len(c.add(failed)) != 1 || len(c.add(failed)) != 1 {
+		t.Error("Expected every error stored")
+	}
+	agent := warning("Storage call UpdateHeartbeat timed out after 5s")
+	agent.AgentID = "agent-1"
+	if len(c.add(agent)) != 1 {
+		t.Error("Expected agent-produced events stored")
+	}
+}
+
+func TestEventCoalescerExemptAndOff(t *testing.T) {
+	c, _ := newTestCoalescer(events.EventTypeStorageTimeout)
+	for i := 0; i < 3; i++ {
+		if len(c.add(warning("locked"))) != 1 {
+			t.Fatal("Expected exempt warnings stored every time")
+		}
+	}
+
+	off := newEventCoalescer(0, nil)
+	if off != nil || len(off.add(warning("locked"))) != 1 || off.flush(true) != nil {
+		t.Error("Expected a zero window to store every event")
+	}
+}
+
+func TestEventCoalescerShutdownFlush(t *testing.T) {
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+	cfg := DefaultConfig()
+	cfg.Store = store
+	cfg.EnableAISupervision = false
+	exec, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create executor: %v", err)
+	}
+
+	for i := 0; i < 5; i++ {
+		exec.logEvent(ctx, events.EventTypeStorageTimeout, events.SeverityWarning, types.SystemIssueID,
+			"failed to update heartbeat: database is locked", nil)
+	}
+	// Repeats within the window aren't flushed before it closes
+	exec.flushCoalescedEvents(ctx, false)
+	stored, err := store.GetAgentEvents(ctx, events.EventFilter{Type: events.EventTypeStorageTimeout})
+	if err != nil || len(stored) != 1 {
+		t.Fatalf("Expected 1 stored warning before shutdown, got %d (%v)", len(stored), err)
+	}
+
+	exec.flushCoalescedEvents(ctx, true)
+	stored, _ = store.GetAgentEvents(ctx, events.EventFilter{Type: events.EventTypeStorageTimeout})
+	if len(stored) != 2 {
+		t.Fatalf("Expected the warning and its summary after shutdown, got %d", len(stored))
+	}
+	var found bool
+	for _, event := range stored {
+		if strings.HasPrefix(event.Message, "Previous message repeated 4 times") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected a summary of the 4 repeats, got %q and %q", stored[0].Message, stored[1].Message)
+	}
+	if exec.coalescer.flush(true) != nil {
+		t.Error("Expected nothing left to flush")
+	}
+}
+
+func TestMessageTemplate(t *testing.T) {
+	if a, b := messageTemplate("timed out after 5s (3 timeouts)"), messageTemplate("timed out after 5s (12 timeouts)"); a != b {
+		t.Errorf("Expected numbers masked, got %q and %q", a, b)
+	}
+	if messageTemplate("database is locked") == messageTemplate("disk I/O error") {
+		t.Error("Expected different messages to differ")
+	}
+}
@@ -3,11 +3,15 @@ package executor
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/steveyegge/vc/internal/events"
 	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/storage/storagetest"
 	"github.com/steveyegge/vc/internal/types"
 )
 
@@ -919,3 +923,99 @@ func TestOutputParserIntegration(t *testing.T) {
 	t.Log("✓ Events stored in real-time as lines are parsed")
 	t.Log("✓ No performance degradation (events stored asynchronously)")
 }
+
+// TestAIEventsRecordModel verifies AI events carry the supervisor's provider
+// and model, and other events don't
+func TestAIEventsRecordModel(t *testing.T) {
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+	cfg := localAIConfig(t, store, http.StatusOK)
+	exec, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create executor: %v", err)
+	}
+	if exec.supervisor == nil {
+		t.Fatal("Expected an AI supervisor for the local provider")
+	}
+
+	exec.logEvent(ctx, events.EventTypeAssessmentCompleted, events.SeverityInfo, "vc-1", "assessed", map[string]interface{}{"confidence": 0.8})
+	exec.logEvent(ctx, events.EventTypeAgentSpawned, events.SeverityInfo, "vc-1", "spawned", map[string]interface{}{})
+
+	stored, err := store.GetAgentEventsByIssue(ctx, "vc-1")
+	if err != nil {
+		t.Fatalf("Failed to get events: %v", err)
+	}
+	for _, event := range stored {
+		provider, model := event.AIModel()
+		switch event.Type {
+		case events.EventTypeAssessmentCompleted:
+			if provider != "local" || model != "llama3" {
+				t.Errorf("Expected local/llama3 on the assessment event, got %s/%s", provider, model)
+			}
+		default:
+			if provider != "" || model != "" {
+				t.Errorf("Expected no AI model on %s, got %s/%s", event.Type, provider, model)
+			}
+		}
+	}
+}
+
+// localAIConfig returns a config using a local AI provider whose endpoint
+// answers every completion with status
+func localAIConfig(t *testing.T, store storage.Storage, status int) *Config {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if status != http.StatusOK {
+			http.Error(w, `{"error":{"message":"denied"}}`, status)
+			return
+		}
+		w.Write([]byte(`{"model":"llama3","choices":[{"message":{"role":"assistant","content":"ok"}}]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	cfg := DefaultConfig()
+	cfg.Store = store
+	cfg.EnableSandboxes = false
+	cfg.AIProvider = "local"
+	cfg.AIModel = "llama3"
+	cfg.AIBaseURL = server.URL + "/v1"
+	return cfg
+}
+
+// TestAIUnavailableAtStartup verifies a failing AI healthcheck disables
+// supervision with a SYSTEM ai_unavailable event, or refuses to start
+// with RequireAI
+func TestAIUnavailableAtStartup(t *testing.T) {
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+	exec, err := New(localAIConfig(t, store, http.StatusUnauthorized))
+	if err != nil {
+		t.Fatalf("Failed to create executor: %v", err)
+	}
+	if exec.supervisor != nil || exec.enableAISupervision {
+		t.Error("Expected AI supervision to be disabled after a failed healthcheck")
+	}
+
+	stored, err := store.GetAgentEventsByIssue(ctx, "SYSTEM")
+	if err != nil {
+		t.Fatalf("Failed to get events: %v", err)
+	}
+	var found *events.AgentEvent
+	for _, event := range stored {
+		if event.Type == events.EventTypeAIUnavailable {
+			found = event
+		}
+	}
+	if found == nil {
+		t.Fatal("Expected an ai_unavailable event")
+	}
+	if found.Data["kind"] != "auth" || found.Data[events.DataKeyAIModel] != "llama3" {
+		t.Errorf("Unexpected event data: %v", found.Data)
+	}
+
+	cfg := localAIConfig(t, storagetest.NewFakeStorage(), http.StatusUnauthorized)
+	cfg.RequireAI = true
+	if _, err := New(cfg); err == nil || !strings.Contains(err.Error(), "rejected the API key") {
+		t.Errorf("Expected New to refuse to start without AI, got %v", err)
+	}
+}
@@ -5,17 +5,19 @@ import (
 	"fmt"
 	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/anthropics/anthropic-sdk-go"
-	"github.com/anthropics/anthropic-sdk-go/option"
 	"github.com/google/uuid"
 	"github.com/steveyegge/vc/internal/ai"
+	"github.com/steveyegge/vc/internal/artifacts"
 	"github.com/steveyegge/vc/internal/config"
 	"github.com/steveyegge/vc/internal/deduplication"
+	"github.com/steveyegge/vc/internal/events"
 	"github.com/steveyegge/vc/internal/gates"
 	"github.com/steveyegge/vc/internal/git"
 	"github.com/steveyegge/vc/internal/health"
+	"github.com/steveyegge/vc/internal/ignore"
 	"github.com/steveyegge/vc/internal/sandbox"
 	"github.com/steveyegge/vc/internal/storage"
 	"github.com/steveyegge/vc/internal/storage/beads"
@@ -23,6 +25,18 @@ import (
 	"github.com/steveyegge/vc/internal/watchdog"
 )
 
+// lifecycleState is where an executor is between New and Stop. An executor
+// runs once: it can't be started again after Stop.
+type lifecycleState int
+
+const (
+	stateCreated  lifecycleState = iota // New returned it, or Start failed
+	stateStarting                       // Start is registering and starting the loops
+	stateRunning                        // The loops are running
+	stateStopping                       // Stop told the loops to stop and waits for them
+	stateStopped                        // Stop finished, or the instance was marked stopped on exit
+)
+
 // Executor manages the issue processing event loop
 type Executor struct {
 	store           storage.Storage
@@ -40,9 +54,14 @@ type Executor struct {
 	qaWorker        *QualityGateWorker             // QA worker for quality gate execution (vc-254)
 	config          *Config
 	instanceID      string
+	offline         bool   // Running without AI (Config.Offline, or the provider was unreachable at startup)
+	offlineReason   string // offlineReasonConfigured or offlineReasonUnreachable
 	hostname        string
 	pid             int
 	version         string
+	project         string // Only this project's work is claimed ("" = every project)
+	respectAssignees bool   // Work assigned to a human is only claimed with the agent-ok label
+	workOrder        types.WorkOrder // Order ready work is claimed in
 
 	// Control channels
 	stopCh             chan struct{}
@@ -53,23 +72,78 @@ type Executor struct {
 	cleanupDoneCh      chan struct{} // Signals when cleanup goroutine finished
 	eventCleanupStopCh chan struct{} // Separate channel for event cleanup shutdown
 	eventCleanupDoneCh chan struct{} // Signals when event cleanup goroutine finished
+	healthStopCh       chan struct{} // Separate channel for health monitor loop shutdown
+	healthDoneCh       chan struct{} // Signals when health monitor loop finished
+	heartbeatDoneCh    chan struct{} // Signals when heartbeat loop finished (stopped by doneCh)
 
 	// Configuration
 	pollInterval            time.Duration
+	heartbeatPeriod         time.Duration
+	storageCallTimeout      time.Duration
+	storageFailureThreshold int
+	shutdownGrace           time.Duration
 	cleanupInterval         time.Duration
 	staleThreshold          time.Duration
 	instanceCleanupAge      time.Duration
 	instanceCleanupKeep     int
+	backupDir               string
+	backupInterval          time.Duration
+	backupRetention         int
+	artifactsDir            string
+	artifactMaxFileSize     int64
+	artifactPolicy          artifacts.Policy
+	commentLimit            artifacts.CommentLimit
 	enableAISupervision     bool
 	enableQualityGates      bool
 	enableSandboxes         bool
 	enableHealthMonitoring  bool
+	healthCheckInterval     time.Duration
 	enableQualityGateWorker bool
 	workingDir              string
 
 	// State
-	mu      sync.RWMutex
-	running bool
+	mu        sync.RWMutex
+	state     lifecycleState
+	startDone chan struct{} // Closed when Start leaves stateStarting
+
+	stopOnce       sync.Once // Closes the loops' stop channels
+	finishStopOnce sync.Once // Runs the rest of Stop once the loops ended
+
+	// The coding agent running now and when it times out (guarded by mu),
+	// so a graceful shutdown knows how long to wait for it
+	agentIssueID  string
+	agentDeadline time.Time
+
+	startedAt             time.Time // Set by Start
+	lastTelemetrySnapshot time.Time // Only touched by the watchdog loop
+	lastBackup            time.Time // Only touched by the cleanup loop
+	paused                bool      // Only touched by the event loop
+
+	// epicCloser closes or labels completed epics (nil when
+	// Config.EpicAutoClose is off); only touched by the event loop
+	epicCloser *epicAutoCloser
+
+	// claimConflicts counts claims lost to another executor
+	claimConflicts atomic.Int64
+
+	// storageTimeouts counts hot-path storage calls that timed out
+	storageTimeouts atomic.Int64
+
+	// coalescer holds back repeated executor warnings (nil when
+	// Config.EventCoalesceWindow is 0; see event_coalescer.go)
+	coalescer *eventCoalescer
+
+	// storageHealth tracks consecutive storage failures and degraded mode
+	// (see executor_storage_health.go)
+	storageHealth storageHealth
+
+	// approvalNoticed holds the missions already reported as awaiting plan
+	// approval, so each is reported once per executor run
+	approvalNoticed sync.Map
+
+	// overlapNoticed holds the "<issue> <issue in flight>" pairs already
+	// reported as deferred due to overlap (see executor_overlap.go)
+	overlapNoticed sync.Map
 }
 
 // Config holds executor configuration
@@ -80,17 +154,37 @@ type Config struct {
 	HeartbeatPeriod         time.Duration
 	CleanupInterval         time.Duration                // How often to check for stale instances (default: 5 minutes)
 	StaleThreshold          time.Duration                // How long before an instance is considered stale (default: 5 minutes)
+	StorageCallTimeout      time.Duration                // Timeout of hot-path storage calls: heartbeat, claims, state updates, events (default: 5s)
+	StorageFailureThreshold int                          // Consecutive failed heartbeat/claim calls before the database is reopened (default: 3)
+	ShutdownGrace           time.Duration                // How long a graceful shutdown waits beyond the running agent's remaining timeout (default: 30s)
 	EnableAISupervision     bool                         // Enable AI assessment and analysis (default: true)
+	Offline                 bool                         // Run without AI: no supervisor, dedup, watchdog AI analysis or health monitors (default: false)
+	Paused                  bool                         // Start without claiming work; a stored executor.paused wins, re-read every poll (default: false)
+	EpicAutoClose           string                       // What to do with epics whose blocking children are all closed: off, label or close (default: off)
+	RequireAI               bool                         // Refuse to start when AI supervision is enabled but unavailable (default: false, continue without it)
+	AIProvider              string                       // AI provider: anthropic, openai or local (default: VC_AI_PROVIDER, then anthropic)
+	AIModel                 string                       // AI model (default: VC_AI_MODEL, then the provider's)
+	AIBaseURL               string                       // AI API endpoint (default: VC_AI_BASE_URL, then the provider's)
+	AIAPIKeyEnv             string                       // Env var holding the AI API key (default: VC_AI_API_KEY_ENV, then the provider's)
+	AIPrices                map[string]config.ModelPrice // AI model prices for cost estimates, added to the built-in table (default: none)
 	EnableQualityGates      bool                         // Enable quality gates enforcement (default: true)
+	EnableFailureAnalysis   bool                         // Ask the AI supervisor to diagnose failed attempts (default: false)
+	FailureAnalysisCostCap  float64                      // Max estimated USD spent on failure analysis per issue (default: 0.50, 0 = no cap)
+	ForceDiscoveredTriage   bool                         // Label every discovered issue needs-triage (default: false, trust inferred priorities)
+	DailySummary            bool                         // Record a daily_summary event once a day from the cleanup loop (default: false)
+	DailySummaryWebhook     string                       // Chat webhook the daily summary is also posted to (default: "", event only)
+	AutoMergeThreshold      float64                      // Auto-merge score (0-1) at which sandbox changes merge without approval (default: 0, always ask)
 	EnableAutoCommit        bool                         // Enable automatic git commits after successful execution (default: false, vc-142)
 	EnableSandboxes         bool                         // Enable sandbox isolation (default: true, vc-144)
 	KeepSandboxOnFailure    bool                         // Keep failed sandboxes for debugging (default: false)
 	KeepBranches            bool                         // Keep mission branches after cleanup (default: false)
 	SandboxRetentionCount   int                          // Number of failed sandboxes to keep (default: 3, 0 = keep all)
+	PromptTokenBudgets      map[string]int               // Prompt token budget per agent type (default: none, DefaultPromptTokenBudget for all)
 	EnableHealthMonitoring  bool                         // Enable health monitoring (default: false, opt-in)
 	EnableQualityGateWorker bool                         // Enable QA worker for quality gate execution (default: true, vc-254)
 	HealthConfigPath        string                       // Path to health_monitors.yaml (default: ".beads/health_monitors.yaml")
 	HealthStatePath         string                       // Path to health_state.json (default: ".beads/health_state.json")
+	HealthCheckInterval     time.Duration                // How often the health loop checks for due monitors (default: 5m)
 	WorkingDir              string                       // Working directory for quality gates (default: ".")
 	SandboxRoot             string                       // Root directory for sandboxes (default: ".sandboxes")
 	ParentRepo              string                       // Parent repository path (default: ".")
@@ -100,6 +194,31 @@ type Config struct {
 	EventRetentionConfig    *config.EventRetentionConfig // Event retention and cleanup configuration (default: sensible defaults, nil = use defaults)
 	InstanceCleanupAge      time.Duration                // How old stopped instances must be before deletion (default: 24h)
 	InstanceCleanupKeep     int                          // Minimum number of stopped instances to keep (default: 10, 0 = keep none)
+	BackupDir               string                       // Directory for periodic database backups from the cleanup loop (default: "", disabled)
+	BackupInterval          time.Duration                // How often to back up when BackupDir is set (default: 24h)
+	BackupRetention         int                          // Number of backups to keep in BackupDir (default: 7)
+	ArtifactsDir            string                       // Directory attempt artifacts (diff, gate logs, agent summary) are saved to (default: "", disabled)
+	ArtifactMaxFileSize     int64                        // Size cap of each artifact file in bytes (default: 1 MiB)
+	ArtifactMaxAge          time.Duration                // Age after which the cleanup loop removes artifacts (default: 720h, 0 = no age limit)
+	ArtifactMaxTotalSize    int64                        // Size ArtifactsDir is trimmed to, oldest attempts first (default: 500 MiB, 0 = no size limit)
+	AICommentMaxSize        int                          // Size cap of AI-authored comments in bytes; the full text goes to ArtifactsDir (default: 16 KiB, 0 = no cap)
+	Project                 string                       // Only claim this project's work (default: "", every project)
+	RespectAssignees        bool                         // Leave work assigned to a human alone unless it is labeled agent-ok (default: true)
+	WorkOrder               types.WorkOrder              // Order ready work is claimed in (default: priority)
+	EventCoalesceWindow     time.Duration                // How long identical executor warnings are counted before one summary is stored (default: 10m, 0 = store each)
+	EventCoalesceExempt     []events.EventType           // Warning types stored every time (default: none)
+}
+
+// AIConfig returns the AI supervisor configuration for the executor
+func (c *Config) AIConfig() *ai.Config {
+	return &ai.Config{
+		Provider:  c.AIProvider,
+		Model:     c.AIModel,
+		BaseURL:   c.AIBaseURL,
+		APIKeyEnv: c.AIAPIKeyEnv,
+		Store:     c.Store,
+		Prices:    c.AIPrices,
+	}
 }
 
 // DefaultConfig returns default executor configuration
@@ -110,10 +229,23 @@ func DefaultConfig() *Config {
 		HeartbeatPeriod:         30 * time.Second,
 		CleanupInterval:         5 * time.Minute,
 		StaleThreshold:          5 * time.Minute,
+		StorageCallTimeout:      5 * time.Second,
+		StorageFailureThreshold: 3,
+		ShutdownGrace:           30 * time.Second,
 		InstanceCleanupAge:      24 * time.Hour,
 		InstanceCleanupKeep:     10,
+		BackupInterval:          24 * time.Hour,
+		BackupRetention:         7,
+		ArtifactMaxFileSize:     artifacts.DefaultMaxFileSize,
+		ArtifactMaxAge:          artifacts.DefaultMaxAge,
+		ArtifactMaxTotalSize:    artifacts.DefaultMaxTotalSize,
+		AICommentMaxSize:        artifacts.DefaultMaxCommentSize,
 		EnableAISupervision:     true,
 		EnableQualityGates:      true,
+		RespectAssignees:        true,
+		WorkOrder:               types.WorkOrderPriority,
+		EventCoalesceWindow:     DefaultEventCoalesceWindow,
+		FailureAnalysisCostCap:  0.50,
 		EnableSandboxes:         true, // Changed to true for safety (vc-144)
 		KeepSandboxOnFailure:    false,
 		KeepBranches:            false,
@@ -122,6 +254,7 @@ func DefaultConfig() *Config {
 		EnableQualityGateWorker: true,  // Enable QA worker by default (vc-254)
 		HealthConfigPath:        ".beads/health_monitors.yaml",
 		HealthStatePath:         ".beads/health_state.json",
+		HealthCheckInterval:     5 * time.Minute,
 		WorkingDir:              ".",
 		SandboxRoot:             ".sandboxes",
 		ParentRepo:              ".",
@@ -158,6 +291,14 @@ func New(cfg *Config) (*Executor, error) {
 		parentRepo = "."
 	}
 
+	// The project's .vcignore is parsed once, for the sandbox disk usage walk
+	// and the health monitors
+	vcignore, err := ignore.Load(parentRepo)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v (using the built-in ignore defaults)\n", err)
+		vcignore = ignore.Defaults()
+	}
+
 	// Set default cleanup interval if not specified
 	cleanupInterval := cfg.CleanupInterval
 	if cleanupInterval == 0 {
@@ -176,12 +317,61 @@ func New(cfg *Config) (*Executor, error) {
 		instanceCleanupAge = 24 * time.Hour
 	}
 
+	// Set default heartbeat period and storage call timeout if not specified
+	heartbeatPeriod := cfg.HeartbeatPeriod
+	if heartbeatPeriod == 0 {
+		heartbeatPeriod = cfg.PollInterval
+	}
+	storageCallTimeout := cfg.StorageCallTimeout
+	if storageCallTimeout == 0 {
+		storageCallTimeout = 5 * time.Second
+	}
+	storageFailureThreshold := cfg.StorageFailureThreshold
+	if storageFailureThreshold == 0 {
+		storageFailureThreshold = 3
+	}
+	shutdownGrace := cfg.ShutdownGrace
+	if shutdownGrace == 0 {
+		shutdownGrace = 30 * time.Second
+	}
+
+	// Set default health check interval if not specified
+	healthCheckInterval := cfg.HealthCheckInterval
+	if healthCheckInterval == 0 {
+		healthCheckInterval = 5 * time.Minute
+	}
+
 	// Set default instance cleanup keep count if not specified
 	instanceCleanupKeep := cfg.InstanceCleanupKeep
 	if instanceCleanupKeep == 0 {
 		instanceCleanupKeep = 10
 	}
 
+	// Set default backup interval and retention if not specified
+	backupInterval := cfg.BackupInterval
+	if backupInterval == 0 {
+		backupInterval = 24 * time.Hour
+	}
+	backupRetention := cfg.BackupRetention
+	if backupRetention == 0 {
+		backupRetention = 7
+	}
+
+	// Set default artifact size cap if not specified
+	artifactMaxFileSize := cfg.ArtifactMaxFileSize
+	if artifactMaxFileSize == 0 {
+		artifactMaxFileSize = artifacts.DefaultMaxFileSize
+	}
+
+	// Set default work order if not specified
+	workOrder := cfg.WorkOrder
+	if workOrder == "" {
+		workOrder = types.WorkOrderPriority
+	}
+	if !workOrder.IsValid() {
+		return nil, fmt.Errorf("invalid work order %q (valid: %v)", workOrder, types.WorkOrders)
+	}
+
 	e := &Executor{
 		store:                   cfg.Store,
 		config:                  cfg,
@@ -189,15 +379,33 @@ func New(cfg *Config) (*Executor, error) {
 		hostname:                hostname,
 		pid:                     os.Getpid(),
 		version:                 cfg.Version,
+		project:                 cfg.Project,
+		respectAssignees:        cfg.RespectAssignees,
+		workOrder:               workOrder,
+		coalescer:               newEventCoalescer(cfg.EventCoalesceWindow, cfg.EventCoalesceExempt),
+		paused:                  cfg.Paused,
 		pollInterval:            cfg.PollInterval,
+		heartbeatPeriod:         heartbeatPeriod,
+		storageCallTimeout:      storageCallTimeout,
+		storageFailureThreshold: storageFailureThreshold,
+		shutdownGrace:           shutdownGrace,
 		cleanupInterval:         cleanupInterval,
 		staleThreshold:          staleThreshold,
 		instanceCleanupAge:      instanceCleanupAge,
 		instanceCleanupKeep:     instanceCleanupKeep,
+		backupDir:               cfg.BackupDir,
+		backupInterval:          backupInterval,
+		backupRetention:         backupRetention,
+		artifactsDir:            cfg.ArtifactsDir,
+		artifactMaxFileSize:     artifactMaxFileSize,
+		artifactPolicy:          artifacts.Policy{MaxAge: cfg.ArtifactMaxAge, MaxTotalSize: cfg.ArtifactMaxTotalSize},
+		commentLimit:            artifacts.CommentLimit{Root: cfg.ArtifactsDir, MaxSize: cfg.AICommentMaxSize},
 		enableAISupervision:     cfg.EnableAISupervision,
 		enableQualityGates:      cfg.EnableQualityGates,
 		enableSandboxes:         cfg.EnableSandboxes,
 		enableQualityGateWorker: cfg.EnableQualityGateWorker,
+		enableHealthMonitoring:  cfg.EnableHealthMonitoring,
+		healthCheckInterval:     healthCheckInterval,
 		workingDir:              workingDir,
 		stopCh:                  make(chan struct{}),
 		doneCh:                  make(chan struct{}),
@@ -205,18 +413,49 @@ func New(cfg *Config) (*Executor, error) {
 		cleanupDoneCh:           make(chan struct{}),
 		eventCleanupStopCh:      make(chan struct{}),
 		eventCleanupDoneCh:      make(chan struct{}),
+		healthStopCh:            make(chan struct{}),
+		healthDoneCh:            make(chan struct{}),
+		heartbeatDoneCh:         make(chan struct{}),
+	}
+
+	epicCloser, err := newEpicAutoCloser(cfg.Store, cfg.EpicAutoClose, cfg.Project, e.instanceID)
+	if err != nil {
+		return nil, err
 	}
+	e.epicCloser = epicCloser
 
 	// Initialize AI supervisor if enabled (do this before sandbox manager to provide deduplicator)
-	if cfg.EnableAISupervision {
-		supervisor, err := ai.NewSupervisor(&ai.Config{
-			Store: cfg.Store,
-		})
-		if err != nil {
+	switch {
+	case cfg.Offline && cfg.RequireAI:
+		return nil, fmt.Errorf("offline mode and required AI supervision are mutually exclusive")
+	case cfg.Offline:
+		e.offline, e.offlineReason = true, offlineReasonConfigured
+		e.enableAISupervision = false
+	case cfg.EnableAISupervision:
+		supervisor, err := ai.NewSupervisor(cfg.AIConfig())
+		if err == nil {
+			// Probe credentials and model now rather than on the first
+			// assessment, after an issue is already claimed
+			if err = probeAI(context.Background(), supervisor); err != nil {
+				e.logAIUnavailable(supervisor, err)
+			}
+		}
+		switch {
+		case err != nil && cfg.RequireAI:
+			return nil, fmt.Errorf("AI supervision is required but unavailable: %w", err)
+		case isUnreachable(err):
+			// No network: run offline rather than fail every AI call
+			fmt.Fprintf(os.Stderr, "\n⚠️  %v\n", err)
+			fmt.Fprintf(os.Stderr, "   Running offline (restart once the provider is reachable to go back online).\n\n")
+			e.offline, e.offlineReason = true, offlineReasonUnreachable
+			e.enableAISupervision = false
+		case err != nil:
 			// Don't fail - just disable AI supervision
-			fmt.Fprintf(os.Stderr, "Warning: failed to initialize AI supervisor: %v (continuing without AI supervision)\n", err)
+			fmt.Fprintf(os.Stderr, "\n⚠️  WARNING: failed to initialize AI supervisor: %v (continuing without AI supervision)\n", err)
+			fmt.Fprintf(os.Stderr, "   Issues will run without AI assessment, analysis or deduplication.\n")
+			fmt.Fprintf(os.Stderr, "   Use --require-ai to refuse to start instead.\n\n")
 			e.enableAISupervision = false
-		} else {
+		default:
 			e.supervisor = supervisor
 		}
 	}
@@ -232,17 +471,9 @@ func New(cfg *Config) (*Executor, error) {
 	}
 
 	// Initialize message generator for auto-commit (vc-136)
-	// Only if we have AI supervisor (need API client)
+	// Only if we have AI supervisor; it uses the supervisor's provider and model
 	if e.supervisor != nil {
-		// Get Anthropic API key
-		apiKey := os.Getenv("ANTHROPIC_API_KEY")
-		if apiKey != "" {
-			// Create Anthropic client for message generation
-			client := anthropic.NewClient(option.WithAPIKey(apiKey))
-			e.messageGen = git.NewMessageGenerator(&client, "claude-sonnet-4-5-20250929")
-		} else {
-			fmt.Fprintf(os.Stderr, "Warning: ANTHROPIC_API_KEY not set (auto-commit message generation disabled)\n")
-		}
+		e.messageGen = git.NewMessageGenerator(e.supervisor.Client(), e.supervisor.Model())
 	}
 
 	// Create deduplicator if we have a supervisor (vc-137, vc-148)
@@ -273,6 +504,7 @@ func New(cfg *Config) (*Executor, error) {
 			DeduplicationConfig: cfg.DeduplicationConfig,
 			PreserveOnFailure:   cfg.KeepSandboxOnFailure, // Preserve failed sandboxes for debugging (vc-134)
 			KeepBranches:        cfg.KeepBranches,         // Keep mission branches after cleanup (vc-134)
+			Ignore:              vcignore,                 // Skipped when measuring sandbox disk usage
 		})
 		if err != nil {
 			// Don't fail - just disable sandboxes
@@ -300,6 +532,7 @@ func New(cfg *Config) (*Executor, error) {
 	} else {
 		e.watchdogConfig = cfg.WatchdogConfig
 	}
+	e.monitor.SetLoopDetection(e.watchdogConfig.LoopDetection)
 
 	// Initialize watchdog channels
 	e.watchdogStopCh = make(chan struct{})
@@ -311,6 +544,7 @@ func New(cfg *Config) (*Executor, error) {
 			Monitor:    e.monitor,
 			Supervisor: e.supervisor,
 			Store:      cfg.Store,
+			Config:     e.watchdogConfig,
 		})
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to initialize watchdog analyzer: %v (watchdog disabled)\n", err)
@@ -324,62 +558,51 @@ func New(cfg *Config) (*Executor, error) {
 		Store:              cfg.Store,
 		ExecutorInstanceID: e.instanceID,
 		MaxHistorySize:     e.watchdogConfig.MaxHistorySize,
+		Config:             e.watchdogConfig,
+		Deduplicator:       e.deduplicator,
+		CommentLimit:       e.commentLimit,
 	})
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: failed to initialize intervention controller: %v (watchdog disabled)\n", err)
 	} else {
 		e.intervention = intervention
 	}
+	if warning := e.watchdogConfig.PolicySafetyWarning(); warning != "" {
+		fmt.Fprintln(os.Stderr, warning)
+	}
 
 	// Initialize health monitoring if enabled
 	if cfg.EnableHealthMonitoring {
-		// Set default paths if not specified
-		healthStatePath := cfg.HealthStatePath
-		if healthStatePath == "" {
-			healthStatePath = ".beads/health_state.json"
-		}
-
-		// Create health registry
-		registry, err := health.NewMonitorRegistry(healthStatePath)
-		if err != nil {
-			// Don't fail - just disable health monitoring
-			fmt.Fprintf(os.Stderr, "Warning: failed to initialize health registry: %v (health monitoring disabled)\n", err)
-			e.enableHealthMonitoring = false
-		} else {
-			e.healthRegistry = registry
-
-			// Register monitors (requires supervisor for AI calls)
-			if e.supervisor != nil {
-				// Get project root
-				projectRoot, err := getProjectRootFromStore(cfg.Store)
+		// Register monitors (requires supervisor for AI calls)
+		if e.supervisor != nil {
+			projectRoot, err := getProjectRootFromStore(cfg.Store)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to get project root: %v (health monitoring disabled)\n", err)
+				e.enableHealthMonitoring = false
+			} else {
+				registry, warnings, err := health.NewDefaultRegistry(health.RegistryOptions{
+					ProjectRoot: projectRoot,
+					StatePath:   cfg.HealthStatePath,
+					ConfigPath:  cfg.HealthConfigPath,
+					Supervisor:  e.supervisor,
+					Ignore:      vcignore,
+				})
+				for _, warning := range warnings {
+					fmt.Fprintf(os.Stderr, "Warning: %v\n", warning)
+				}
 				if err != nil {
-					fmt.Fprintf(os.Stderr, "Warning: failed to get project root: %v (health monitoring disabled)\n", err)
+					// Don't fail - just disable health monitoring
+					fmt.Fprintf(os.Stderr, "Warning: failed to initialize health registry: %v (health monitoring disabled)\n", err)
 					e.enableHealthMonitoring = false
 				} else {
-					// Register file size monitor
-					fileSizeMonitor, err := health.NewFileSizeMonitor(projectRoot, e.supervisor)
-					if err != nil {
-						fmt.Fprintf(os.Stderr, "Warning: failed to create file size monitor: %v\n", err)
-					} else {
-						if err := registry.Register(fileSizeMonitor); err != nil {
-							fmt.Fprintf(os.Stderr, "Warning: failed to register file size monitor: %v\n", err)
-						}
-					}
-
-					// Register cruft detector
-					cruftDetector, err := health.NewCruftDetector(projectRoot, e.supervisor)
-					if err != nil {
-						fmt.Fprintf(os.Stderr, "Warning: failed to create cruft detector: %v\n", err)
-					} else {
-						if err := registry.Register(cruftDetector); err != nil {
-							fmt.Fprintf(os.Stderr, "Warning: failed to register cruft detector: %v\n", err)
-						}
-					}
+					e.healthRegistry = registry
 				}
-			} else {
+			}
+		} else {
+			if !e.offline {
 				fmt.Fprintf(os.Stderr, "Warning: health monitoring requires AI supervision (health monitoring disabled)\n")
-				e.enableHealthMonitoring = false
 			}
+			e.enableHealthMonitoring = false
 		}
 	}
 
@@ -457,29 +680,27 @@ func New(cfg *Config) (*Executor, error) {
 // Start begins the executor event loop
 func (e *Executor) Start(ctx context.Context) error {
 	e.mu.Lock()
-	if e.running {
+	switch e.state {
+	case stateStarting, stateRunning:
 		e.mu.Unlock()
 		return fmt.Errorf("executor is already running")
+	case stateStopping, stateStopped:
+		e.mu.Unlock()
+		return fmt.Errorf("executor has been stopped")
 	}
-	e.running = true
+	e.state = stateStarting
+	startDone := make(chan struct{})
+	e.startDone = startDone
 	e.mu.Unlock()
 
-	// Register this executor instance
-	instance := &types.ExecutorInstance{
-		InstanceID:    e.instanceID,
-		Hostname:      e.hostname,
-		PID:           e.pid,
-		Status:        types.ExecutorStatusRunning,
-		StartedAt:     time.Now(),
-		LastHeartbeat: time.Now(),
-		Version:       e.version,
-		Metadata:      "{}",
-	}
-
-	if err := e.store.RegisterInstance(ctx, instance); err != nil {
+	// Register this executor instance. Until that worked nothing has
+	// started, so a failed Start can be retried.
+	e.startedAt = time.Now()
+	if err := e.store.RegisterInstance(ctx, e.instanceRecord()); err != nil {
 		e.mu.Lock()
-		e.running = false
+		e.state = stateCreated
 		e.mu.Unlock()
+		close(startDone)
 		return fmt.Errorf("failed to register executor instance: %w", err)
 	}
 
@@ -494,6 +715,14 @@ func (e *Executor) Start(ctx context.Context) error {
 		fmt.Printf("Cleanup: Cleaned up %d stale/orphaned instance(s) on startup\n", cleaned)
 	}
 
+	// System-level events are filed under the SYSTEM pseudo-issue; recreate
+	// it if it was deleted since the database was opened
+	if created, err := e.store.EnsureSystemIssue(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to ensure the %s pseudo-issue: %v\n", types.SystemIssueID, err)
+	} else if created {
+		fmt.Printf("Recreated the %s pseudo-issue that system-level events are filed under\n", types.SystemIssueID)
+	}
+
 	// Clean up orphaned mission branches on startup (vc-135)
 	// This runs synchronously to ensure branches are cleaned before claiming work
 	if e.enableSandboxes && !e.config.KeepBranches {
@@ -503,11 +732,15 @@ func (e *Executor) Start(ctx context.Context) error {
 		}
 	}
 
-	// Start the event loop
+	// Start the event loop, and the heartbeat in its own loop so a long
+	// execution or a slow store can't make this instance look stale
 	go e.eventLoop(ctx)
+	go e.heartbeatLoop(ctx)
+	fmt.Printf("Executor: Claiming ready work in %s order (discovered blockers first)\n", e.workOrder)
 
 	// Start the watchdog loop if enabled and components are initialized
-	if e.watchdogConfig.IsEnabled() && e.analyzer != nil && e.intervention != nil {
+	// Stall detection needs no AI, so the loop runs even without an analyzer
+	if e.watchdogConfig.IsEnabled() && e.intervention != nil {
 		go e.watchdogLoop(ctx)
 		fmt.Printf("Watchdog: Started monitoring (check_interval=%v, min_confidence=%.2f, min_severity=%s)\n",
 			e.watchdogConfig.GetCheckInterval(),
@@ -523,59 +756,139 @@ func (e *Executor) Start(ctx context.Context) error {
 	// Start the event cleanup loop
 	go e.eventCleanupLoop(ctx)
 
+	// Start the health monitor loop if enabled
+	if e.healthMonitoringActive() {
+		go e.healthLoop(ctx)
+		fmt.Printf("Health: Started monitor scheduling (check_interval=%v)\n", e.healthCheckInterval)
+	}
+
+	// Everything is started; a Stop waiting for Start can go ahead
+	e.mu.Lock()
+	e.state = stateRunning
+	e.mu.Unlock()
+	close(startDone)
+
 	return nil
 }
 
-// Stop gracefully stops the executor
+// instanceRecord describes this running executor instance for
+// RegisterInstance
+func (e *Executor) instanceRecord() *types.ExecutorInstance {
+	return &types.ExecutorInstance{
+		InstanceID:    e.instanceID,
+		Hostname:      e.hostname,
+		PID:           e.pid,
+		Status:        types.ExecutorStatusRunning,
+		StartedAt:     e.startedAt,
+		LastHeartbeat: time.Now(),
+		Version:       e.version,
+		Metadata:      e.instanceMetadata(),
+	}
+}
+
+// Stop gracefully stops the executor. It is safe to call concurrently and
+// more than once: every call waits for the loops to end, the first one to
+// see them end finishes the shutdown, and later calls return nil. A Stop
+// that gave up on its ctx can be retried. Stop during Start waits for Start
+// and then stops what it started; Stop before Start is an error.
 func (e *Executor) Stop(ctx context.Context) error {
 	e.mu.Lock()
-	if !e.running {
+	switch e.state {
+	case stateCreated:
 		e.mu.Unlock()
 		return fmt.Errorf("executor is not running")
+	case stateStarting:
+		startDone := e.startDone
+		e.mu.Unlock()
+		select {
+		case <-startDone:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		return e.Stop(ctx)
+	case stateStopped:
+		e.mu.Unlock()
+		return nil
+	case stateRunning:
+		e.state = stateStopping
 	}
 	e.mu.Unlock()
 
 	// Signal shutdown
-	close(e.stopCh)
-
-	// Stop watchdog if it's running
-	if e.watchdogConfig.IsEnabled() && e.analyzer != nil {
-		close(e.watchdogStopCh)
-	}
-
-	// Stop cleanup goroutine
-	close(e.cleanupStopCh)
-
-	// Stop event cleanup goroutine
-	close(e.eventCleanupStopCh)
+	e.signalStop()
 
 	// Wait for event loop, watchdog, cleanup, and event cleanup to finish concurrently (vc-113, vc-122, vc-195)
 	// This prevents sequential timeouts if one takes longer than expected
 	eventDone := false
-	watchdogDone := !e.watchdogConfig.IsEnabled() || e.analyzer == nil // Skip if not enabled
+	heartbeatDone := false
+	watchdogDone := !e.watchdogConfig.IsEnabled() || e.intervention == nil // Skip if not enabled
 	cleanupDone := false
 	eventCleanupDone := false
+	healthDone := !e.healthMonitoringActive() // Skip if not enabled
 
-	for !eventDone || !watchdogDone || !cleanupDone || !eventCleanupDone {
+	for !eventDone || !heartbeatDone || !watchdogDone || !cleanupDone || !eventCleanupDone || !healthDone {
 		select {
 		case <-e.doneCh:
 			eventDone = true
+		case <-e.heartbeatDoneCh:
+			heartbeatDone = true
 		case <-e.watchdogDoneCh:
 			watchdogDone = true
 		case <-e.cleanupDoneCh:
 			cleanupDone = true
 		case <-e.eventCleanupDoneCh:
 			eventCleanupDone = true
+		case <-e.healthDoneCh:
+			healthDone = true
 		case <-ctx.Done():
 			return ctx.Err()
 		}
 	}
 
-	// Update internal state first (vc-192: set running=false before DB update)
+	// Concurrent callers wait here until the first one is done
+	e.finishStopOnce.Do(func() { e.finishStop(ctx) })
+	return nil
+}
+
+// signalStop tells every loop Start started to stop
+func (e *Executor) signalStop() {
+	e.stopOnce.Do(func() {
+		close(e.stopCh)
+
+		// Stop watchdog if it's running
+		if e.watchdogConfig.IsEnabled() && e.intervention != nil {
+			close(e.watchdogStopCh)
+		}
+
+		// Stop cleanup goroutine
+		close(e.cleanupStopCh)
+
+		// Stop event cleanup goroutine
+		close(e.eventCleanupStopCh)
+
+		// Stop health monitor loop if it's running
+		if e.healthMonitoringActive() {
+			close(e.healthStopCh)
+		}
+	})
+}
+
+// finishStop does what is left of Stop once the loops have ended: it marks
+// the instance stopped and cleans up after it
+func (e *Executor) finishStop(ctx context.Context) {
+	// Update internal state first (vc-192: set the state before the DB update)
 	e.mu.Lock()
-	e.running = false
+	if e.state == stateStopped {
+		// MarkInstanceStoppedOnExit got here first
+		e.mu.Unlock()
+		return
+	}
+	e.state = stateStopped
 	e.mu.Unlock()
 
+	// Store the summaries of warnings still being held back
+	e.flushCoalescedEvents(ctx, true)
+
 	// Prune worktrees on shutdown (vc-194)
 	// This is best-effort cleanup - don't fail shutdown if it doesn't work
 	if e.enableSandboxes && e.config.ParentRepo != "" {
@@ -620,15 +933,28 @@ func (e *Executor) Stop(ctx context.Context) error {
 		// Log success event (vc-32)
 		e.logInstanceCleanupEvent(ctx, deleted, stoppedRemaining, processingTimeMs, olderThanSeconds, e.instanceCleanupKeep, true, "")
 	}
-
-	return nil
 }
 
-// IsRunning returns whether the executor is currently running
+// IsRunning returns whether the executor is currently running: from Start
+// until Stop has finished
 func (e *Executor) IsRunning() bool {
 	e.mu.RLock()
 	defer e.mu.RUnlock()
-	return e.running
+	return e.state == stateStarting || e.state == stateRunning || e.state == stateStopping
+}
+
+// ClaimConflicts returns how many times this executor lost a claim to
+// another executor. Some contention is normal with several executors; a
+// steadily climbing count means they keep picking the same work.
+func (e *Executor) ClaimConflicts() int64 {
+	return e.claimConflicts.Load()
+}
+
+// StorageTimeouts returns how many hot-path storage calls (heartbeat,
+// claims, state updates, events) timed out. Each is also recorded as a
+// storage_timeout SYSTEM event when the store lets it be written.
+func (e *Executor) StorageTimeouts() int64 {
+	return e.storageTimeouts.Load()
 }
 
 // MarkInstanceStoppedOnExit marks this executor instance as stopped.
@@ -637,8 +963,10 @@ func (e *Executor) IsRunning() bool {
 func (e *Executor) MarkInstanceStoppedOnExit(ctx context.Context) error {
 	// Update internal state first (under lock)
 	e.mu.Lock()
-	wasRunning := e.running
-	e.running = false
+	wasRunning := e.state == stateRunning || e.state == stateStopping
+	if wasRunning {
+		e.state = stateStopped
+	}
 	e.mu.Unlock()
 
 	// Only call MarkInstanceStopped if we were running
@@ -646,6 +974,7 @@ func (e *Executor) MarkInstanceStoppedOnExit(ctx context.Context) error {
 	if !wasRunning {
 		return nil
 	}
+	e.signalStop()
 
 	// Mark as stopped in database
 	if err := e.store.MarkInstanceStopped(ctx, e.instanceID); err != nil {
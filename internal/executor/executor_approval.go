@@ -0,0 +1,40 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// noticeMissionsAwaitingApproval reports the open missions whose plan
+// still needs approval. Their work never shows up as ready, so without this
+// nobody would learn why it isn't picked up.
+func (e *Executor) noticeMissionsAwaitingApproval(ctx context.Context) {
+	missions, err := e.store.GetMissionsByStatus(ctx, types.StatusOpen)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to check missions awaiting approval: %v\n", err)
+		return
+	}
+	for _, mission := range missions {
+		if !mission.IsApproved() {
+			e.noticeAwaitingApproval(ctx, mission.ID)
+		}
+	}
+}
+
+// noticeAwaitingApproval emits a mission_awaiting_approval event the first
+// time this executor skips the mission
+func (e *Executor) noticeAwaitingApproval(ctx context.Context, missionID string) {
+	if _, seen := e.approvalNoticed.LoadOrStore(missionID, true); seen {
+		return
+	}
+	fmt.Printf("Mission %s is awaiting plan approval; its work is on hold (vc mission approve %s)\n", missionID, missionID)
+	e.logEvent(ctx, events.EventTypeMissionAwaitingApproval, events.SeverityWarning, missionID,
+		fmt.Sprintf("Mission %s is awaiting plan approval", missionID),
+		map[string]interface{}{
+			"mission_id": missionID,
+		})
+}
@@ -0,0 +1,81 @@
+package executor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage/storagetest"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// TestProcessNextIssueAwaitingApproval verifies the executor leaves the
+// work of an unapproved mission alone and reports the mission once
+func TestProcessNextIssueAwaitingApproval(t *testing.T) {
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+	mission := &types.Mission{
+		Issue: types.Issue{
+			Title:        "Mission",
+			Status:       types.StatusOpen,
+			Priority:     1,
+			IssueType:    types.TypeEpic,
+			IssueSubtype: types.SubtypeMission,
+		},
+		Goal:             "Ship it",
+		ApprovalRequired: true,
+	}
+	if err := store.CreateMission(ctx, mission, "test"); err != nil {
+		t.Fatalf("Failed to create mission: %v", err)
+	}
+	task := &types.Issue{Title: "Task", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, task, "test"); err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	if err := store.AddDependency(ctx, &types.Dependency{IssueID: task.ID, DependsOnID: mission.ID, Type: types.DepParentChild}, "test"); err != nil {
+		t.Fatalf("Failed to add dependency: %v", err)
+	}
+
+	execCfg := DefaultConfig()
+	execCfg.Store = store
+	execCfg.EnableAISupervision = false
+	exec, err := New(execCfg)
+	if err != nil {
+		t.Fatalf("Failed to create executor: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := exec.processNextIssue(ctx); err != nil {
+			t.Fatalf("processNextIssue: %v", err)
+		}
+	}
+	if n := store.CallCount("ClaimIssue"); n != 0 {
+		t.Errorf("Expected no claims while the mission awaits approval, got %d", n)
+	}
+
+	agentEvents, err := store.GetAgentEventsByIssue(ctx, mission.ID)
+	if err != nil {
+		t.Fatalf("Failed to get events: %v", err)
+	}
+	notices := 0
+	for _, event := range agentEvents {
+		if event.Type == events.EventTypeMissionAwaitingApproval {
+			notices++
+		}
+	}
+	if notices != 1 {
+		t.Errorf("Expected 1 awaiting-approval event, got %d", notices)
+	}
+
+	// A claim refused for approval is skipped, not an error
+	store.FailOn("ClaimIssue", &types.AwaitingApprovalError{IssueID: task.ID, MissionID: mission.ID})
+	if err := store.ApproveMission(ctx, mission.ID, "reviewer", ""); err != nil {
+		t.Fatalf("Failed to approve mission: %v", err)
+	}
+	if err := exec.processNextIssue(ctx); err != nil {
+		t.Errorf("Expected a claim awaiting approval to be skipped, got: %v", err)
+	}
+	if n := store.CallCount("ClaimIssue"); n != 1 {
+		t.Errorf("Expected the approved mission's task to be claimed, got %d claims", n)
+	}
+}
@@ -0,0 +1,80 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/artifacts"
+	"github.com/steveyegge/vc/internal/sandbox"
+)
+
+// saveArtifacts saves what the attempt produced (the sandbox's diff against
+// the base branch, the full output of each gate and the agent's summary)
+// before the sandbox is cleaned up, and records their paths on the attempt,
+// along with the agent's transcript.
+// It runs once per attempt; later calls return the paths saved the first
+// time. Failures are logged and never fail the execution.
+func (e *Executor) saveArtifacts(ctx context.Context, attempt *attemptRecorder, sb *sandbox.Sandbox, procResult *ProcessingResult) []string {
+	if e.artifactsDir == "" || attempt.attempt == nil || attempt.artifactsSaved {
+		return attempt.artifacts()
+	}
+	attempt.artifactsSaved = true
+
+	var files []artifacts.File
+	if sb != nil {
+		baseBranch := "main"
+		if e.config != nil && e.config.DefaultBranch != "" {
+			baseBranch = e.config.DefaultBranch
+		}
+		diff, err := sandbox.BranchPatch(ctx, sb, baseBranch)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to diff sandbox %s for artifacts: %v\n", sb.ID, err)
+		}
+		files = append(files, artifacts.File{Name: "diff.patch", Content: diff})
+	}
+	if procResult != nil {
+		for _, gate := range procResult.GateResults {
+			files = append(files, artifacts.File{Name: fmt.Sprintf("gate-%s.log", gate.Gate), Content: gateLog(gate.Output, gate.Error), KeepTail: true})
+		}
+		files = append(files, artifacts.File{Name: "summary.md", Content: procResult.AgentSummary})
+	}
+
+	issueID := attempt.attempt.IssueID
+	paths, err := artifacts.Save(e.artifactsDir, issueID, attempt.number(), files, e.artifactMaxFileSize)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to save artifacts for %s: %v\n", issueID, err)
+	}
+	if attempt.transcript != "" {
+		paths = append(paths, attempt.transcript) // Written by the agent as it ran
+	}
+	attempt.attempt.Artifacts = paths
+	return paths
+}
+
+// gateLog is a gate's artifact: its output, then the error it failed with
+func gateLog(output string, err error) string {
+	if err == nil {
+		return output
+	}
+	if output != "" && !strings.HasSuffix(output, "\n") {
+		output += "\n"
+	}
+	return output + "error: " + err.Error() + "\n"
+}
+
+// sweepArtifacts removes the artifacts the retention policy no longer keeps
+func (e *Executor) sweepArtifacts(now time.Time) {
+	if e.artifactsDir == "" {
+		return
+	}
+	removed, err := artifacts.Sweep(e.artifactsDir, e.artifactPolicy, now)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to sweep artifacts: %v\n", err)
+	}
+	if removed > 0 {
+		fmt.Printf("Cleanup: Deleted the artifacts of %d old attempt(s) from %s\n", removed, e.artifactsDir)
+	}
+}
@@ -0,0 +1,144 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/artifacts"
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/gates"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// TestSaveArtifacts verifies the gate logs and agent summary are saved once
+// per attempt and recorded in its history row
+func TestSaveArtifacts(t *testing.T) {
+	exec, store, issue := newAttemptTestExecutor(t)
+	ctx := context.Background()
+	exec.artifactsDir = t.TempDir()
+
+	attempt := exec.startAttempt(ctx, issue.ID)
+	procResult := &ProcessingResult{
+		AgentSummary: "Fixed the parser",
+		GateResults: []*gates.Result{
+			{Gate: gates.GateTest, Passed: false, Output: "--- FAIL: TestParse", Error: errors.New("exit status 1")},
+			{Gate: gates.GateBuild, Passed: true, Output: "ok"},
+		},
+	}
+	paths := exec.saveArtifacts(ctx, attempt, nil, procResult)
+	dir := artifacts.Dir(exec.artifactsDir, issue.ID, 1)
+	want := []string{filepath.Join(dir, "gate-test.log"), filepath.Join(dir, "gate-build.log"), filepath.Join(dir, "summary.md")}
+	if !reflect.DeepEqual(paths, want) {
+		t.Fatalf("Expected artifacts %v, got %v", want, paths)
+	}
+	if log, _ := os.ReadFile(paths[0]); string(log) != "--- FAIL: TestParse\nerror: exit status 1\n" {
+		t.Errorf("Unexpected test gate log %q", log)
+	}
+	procResult.AgentSummary = "changed"
+	if again := exec.saveArtifacts(ctx, attempt, nil, procResult); !reflect.DeepEqual(again, paths) {
+		t.Errorf("Expected the first save's paths again, got %v", again)
+	}
+	if summary, _ := os.ReadFile(paths[2]); string(summary) != "Fixed the parser" {
+		t.Errorf("Expected the summary saved once, got %q", summary)
+	}
+
+	attempt.finish(ctx, true, "completed", nil)
+	history, err := store.GetExecutionHistory(ctx, issue.ID)
+	if err != nil || len(history) != 1 {
+		t.Fatalf("Expected 1 attempt, got %v (%v)", history, err)
+	}
+	if !reflect.DeepEqual(history[0].Artifacts, want) {
+		t.Errorf("Expected the artifacts recorded on the attempt, got %v", history[0].Artifacts)
+	}
+}
+
+// TestSaveArtifactsDisabled verifies nothing is written without a directory
+func TestSaveArtifactsDisabled(t *testing.T) {
+	exec, _, issue := newAttemptTestExecutor(t)
+	attempt := exec.startAttempt(context.Background(), issue.ID)
+	if paths := exec.saveArtifacts(context.Background(), attempt, nil, &ProcessingResult{AgentSummary: "x"}); paths != nil {
+		t.Errorf("Expected no artifacts, got %v", paths)
+	}
+}
+
+// TestSweepArtifacts verifies the cleanup loop applies the retention policy
+func TestSweepArtifacts(t *testing.T) {
+	exec, _, _ := newAttemptTestExecutor(t)
+	exec.artifactsDir = t.TempDir()
+	exec.artifactPolicy = artifacts.Policy{MaxAge: time.Hour}
+	paths, err := artifacts.Save(exec.artifactsDir, "vc-1", 1, []artifacts.File{{Name: "summary.md", Content: "old"}}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(paths[0], old, old); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(filepath.Dir(paths[0]), old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	exec.sweepArtifacts(time.Now())
+	if _, err := os.Stat(paths[0]); !os.IsNotExist(err) {
+		t.Errorf("Expected the old artifacts swept (%v)", err)
+	}
+	if entries, _ := os.ReadDir(exec.artifactsDir); len(entries) != 0 {
+		t.Errorf("Expected an empty artifacts directory, got %v", entries)
+	}
+}
+
+// TestCleanupExecutionHistory verifies history retention deletes old
+// attempts beyond the per-issue count along with their artifacts
+func TestCleanupExecutionHistory(t *testing.T) {
+	exec, store, issue := newAttemptTestExecutor(t)
+	ctx := context.Background()
+	root := t.TempDir()
+	var saved [][]string
+	for n := 1; n <= 3; n++ {
+		paths, err := artifacts.Save(root, issue.ID, n, []artifacts.File{{Name: "summary.md", Content: "attempt"}}, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		attempt := &types.ExecutionAttempt{
+			IssueID:            issue.ID,
+			ExecutorInstanceID: exec.instanceID,
+			AttemptNumber:      n,
+			StartedAt:          time.Now().AddDate(0, 0, n-100),
+			Artifacts:          paths,
+		}
+		if err := store.RecordExecutionAttempt(ctx, attempt); err != nil {
+			t.Fatal(err)
+		}
+		saved = append(saved, paths)
+	}
+
+	cfg := config.DefaultEventRetentionConfig()
+	cfg.HistoryKeepPerIssue = 1
+	cfg.HistoryRetentionDays = 0
+	if attempts, files, err := exec.cleanupExecutionHistory(ctx, cfg); err != nil || attempts != 0 || files != 0 {
+		t.Fatalf("Expected no cleanup with history kept forever, got %d, %d (%v)", attempts, files, err)
+	}
+
+	cfg.HistoryRetentionDays = 30
+	attempts, files, err := exec.cleanupExecutionHistory(ctx, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attempts != 2 || files != 2 {
+		t.Errorf("Expected 2 attempts and 2 artifacts deleted, got %d and %d", attempts, files)
+	}
+	if _, err := os.Stat(artifacts.Dir(root, issue.ID, 1)); !os.IsNotExist(err) {
+		t.Errorf("Expected the first attempt's artifacts removed (%v)", err)
+	}
+	if _, err := os.Stat(saved[2][0]); err != nil {
+		t.Errorf("Expected the newest attempt's artifacts kept: %v", err)
+	}
+	if history, _ := store.GetExecutionHistory(ctx, issue.ID); len(history) != 1 || history[0].AttemptNumber != 3 {
+		t.Errorf("Expected only the newest attempt left, got %v", history)
+	}
+}
@@ -0,0 +1,69 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/steveyegge/vc/internal/ai"
+	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// formatAssessmentComment renders an assessment as the human-readable
+// comment added to the issue
+func formatAssessmentComment(assessment *ai.Assessment) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "**AI Assessment**\n\nStrategy: %s\n\nConfidence: %.0f%%\n\nSteps:\n",
+		assessment.Strategy, assessment.Confidence*100)
+	for i, step := range assessment.Steps {
+		fmt.Fprintf(&b, "%d. %s\n", i+1, step)
+	}
+	if len(assessment.Risks) > 0 {
+		b.WriteString("\nRisks:\n")
+		for _, risk := range assessment.Risks {
+			fmt.Fprintf(&b, "- %s\n", risk)
+		}
+	}
+	return b.String()
+}
+
+// saveAssessment stores the assessment for the attempt. Steps a previous
+// attempt's agent reported done stay marked when the new plan repeats them.
+// Failures are logged, never fatal.
+func saveAssessment(ctx context.Context, store storage.ExecutionStateStore, issueID string, attempt int, assessment *ai.Assessment) {
+	record := &types.AssessmentRecord{
+		IssueID:    issueID,
+		Attempt:    attempt,
+		Assessment: *assessment,
+	}
+	previous, err := store.GetLatestAssessment(ctx, issueID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to load previous assessment for %s: %v\n", issueID, err)
+	} else if previous != nil && previous.Attempt != attempt {
+		record.MarkCompleted(previous.CompletedStepTexts())
+	}
+	if err := store.SaveAssessment(ctx, record); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to save assessment for %s: %v\n", issueID, err)
+	}
+}
+
+// recordCompletedSteps marks the steps of the issue's latest assessment that
+// match what the agent reported done, so a resumed attempt sees them checked
+func recordCompletedSteps(ctx context.Context, store storage.ExecutionStateStore, issueID string, completed []string) {
+	if len(completed) == 0 {
+		return
+	}
+	record, err := store.GetLatestAssessment(ctx, issueID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to load assessment for %s: %v\n", issueID, err)
+		return
+	}
+	if record == nil || record.MarkCompleted(completed) == 0 {
+		return
+	}
+	if err := store.SaveAssessment(ctx, record); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to record completed steps for %s: %v\n", issueID, err)
+	}
+}
@@ -0,0 +1,49 @@
+package executor
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/ai"
+	"github.com/steveyegge/vc/internal/storage/storagetest"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// TestAssessmentCarriesCompletedSteps verifies steps an agent reported done
+// are recorded on the latest assessment and stay checked in the next
+// attempt's plan when it repeats them
+func TestAssessmentCarriesCompletedSteps(t *testing.T) {
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+	issue := &types.Issue{Title: "Planned", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("Failed to create issue: %v", err)
+	}
+
+	saveAssessment(ctx, store, issue.ID, 1, &ai.Assessment{
+		Strategy: "Test first",
+		Steps:    []string{"Add a failing test", "Fix the bug"},
+	})
+	recordCompletedSteps(ctx, store, issue.ID, []string{"Added a failing test for it: add a failing test"})
+
+	record, err := store.GetLatestAssessment(ctx, issue.ID)
+	if err != nil || record == nil {
+		t.Fatalf("GetLatestAssessment: %v, %v", record, err)
+	}
+	if !reflect.DeepEqual(record.CompletedSteps, []int{0}) {
+		t.Errorf("Expected step 0 done after the first attempt, got %v", record.CompletedSteps)
+	}
+
+	saveAssessment(ctx, store, issue.ID, 2, &ai.Assessment{
+		Strategy: "Finish the fix",
+		Steps:    []string{"Review code", "Add a failing test", "Fix the bug"},
+	})
+	record, err = store.GetLatestAssessment(ctx, issue.ID)
+	if err != nil || record == nil {
+		t.Fatalf("GetLatestAssessment: %v, %v", record, err)
+	}
+	if record.Attempt != 2 || !reflect.DeepEqual(record.CompletedSteps, []int{1}) {
+		t.Errorf("Expected attempt 2 with step 1 carried over, got attempt %d with %v", record.Attempt, record.CompletedSteps)
+	}
+}
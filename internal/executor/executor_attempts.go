@@ -0,0 +1,231 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/sandbox"
+	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// attemptSampleLines caps the output and error samples kept with an attempt
+// (the last lines are kept, since that's where failures show up)
+const attemptSampleLines = 1000
+
+// attemptSummaryLen caps the one-line summary of an attempt
+const attemptSummaryLen = 500
+
+// attemptRecorder writes the execution history row for one executeIssue run:
+// the row is inserted when the claim succeeds and finalized exactly once when
+// the run ends, however it ends
+type attemptRecorder struct {
+	store          storage.ExecutionStateStore
+	attempt        *types.ExecutionAttempt // nil if the row couldn't be inserted
+	phases         *PhaseTimer             // Times the attempt's phases, recorded or not
+	done           bool
+	artifactsSaved bool   // Only touched by saveArtifacts
+	transcript     string // Path of the agent's transcript, once it exited
+}
+
+// startAttempt records the start of a new attempt at the issue, numbered
+// after the ones already in its history. Failing to record it is logged but
+// never stops the execution.
+func (e *Executor) startAttempt(ctx context.Context, issueID string) *attemptRecorder {
+	r := &attemptRecorder{store: e.store, phases: NewPhaseTimer()}
+
+	history, err := e.store.GetExecutionHistory(ctx, issueID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to get execution history for %s: %v\n", issueID, err)
+		return r
+	}
+
+	attempt := &types.ExecutionAttempt{
+		IssueID:            issueID,
+		ExecutorInstanceID: e.instanceID,
+		AttemptNumber:      len(history) + 1,
+		StartedAt:          time.Now(),
+	}
+	if err := e.store.RecordExecutionAttempt(ctx, attempt); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to record execution attempt for %s: %v\n", issueID, err)
+		return r
+	}
+	r.attempt = attempt
+	return r
+}
+
+// number returns the attempt's number, or 0 if it couldn't be recorded
+func (r *attemptRecorder) number() int {
+	if r.attempt == nil {
+		return 0
+	}
+	return r.attempt.AttemptNumber
+}
+
+// artifacts returns the paths of the attempt's saved artifacts
+func (r *attemptRecorder) artifacts() []string {
+	if r.attempt == nil {
+		return nil
+	}
+	return r.attempt.Artifacts
+}
+
+// finish records the attempt's outcome. result may be nil when the run ended
+// before the agent finished. Only the first call has an effect.
+func (r *attemptRecorder) finish(ctx context.Context, success bool, summary string, result *AgentResult) {
+	if r.done || r.attempt == nil {
+		return
+	}
+	r.done = true
+
+	completedAt := time.Now()
+	r.attempt.CompletedAt = &completedAt
+	r.phases.Stop()
+	r.attempt.Phases = r.phases.Phases()
+	r.attempt.Success = &success
+	r.attempt.Summary = truncate(firstLine(summary), attemptSummaryLen)
+	if result != nil {
+		exitCode := result.ExitCode
+		r.attempt.ExitCode = &exitCode
+		r.attempt.OutputSample = lastLines(result.Output, attemptSampleLines)
+		r.attempt.ErrorSample = lastLines(result.Errors, attemptSampleLines)
+	}
+
+	if err := r.store.UpdateExecutionAttempt(ctx, r.attempt); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to finalize execution attempt for %s: %v\n", r.attempt.IssueID, err)
+	}
+}
+
+// recordCommit notes the commit the attempt made and its branch, saved when
+// the attempt finishes
+func (r *attemptRecorder) recordCommit(commit, branch string) {
+	if r.attempt == nil {
+		return
+	}
+	r.attempt.CommitHash = commit
+	r.attempt.Branch = branch
+}
+
+// recordFiles notes the paths the attempt changed, saved when it finishes
+func (r *attemptRecorder) recordFiles(files []string) {
+	if r.attempt == nil {
+		return
+	}
+	r.attempt.Files = files
+}
+
+// recordMergeCommit notes the merge of the attempt's branch into main, if
+// its sandbox cleanup merged it
+func (r *attemptRecorder) recordMergeCommit(mergeCommit string) {
+	if r.attempt == nil || r.attempt.CommitHash == "" || mergeCommit == "" {
+		return
+	}
+	r.attempt.MergeCommit = mergeCommit
+}
+
+// recordClosingCommit stores the commit that closed the issue in its
+// metadata (types.MetaClosingCommit and types.MetaClosingBranch), where vc
+// show and vc list --closed-in read it. A failure is logged, never fatal.
+func (e *Executor) recordClosingCommit(ctx context.Context, issueID, commit, branch string) {
+	err := e.store.SetIssueMeta(ctx, issueID, types.MetaClosingCommit, types.MetaString(commit))
+	if err == nil && branch != "" {
+		err = e.store.SetIssueMeta(ctx, issueID, types.MetaClosingBranch, types.MetaString(branch))
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to record closing commit of %s: %v\n", issueID, err)
+	}
+}
+
+// changedFiles lists the paths an attempt changed: everything its sandbox
+// branch changed, or without a sandbox the files of its commit (or the
+// uncommitted changes if it made none). A failure is logged and lists none.
+func (e *Executor) changedFiles(ctx context.Context, sb *sandbox.Sandbox, workingDir, commitHash string) []string {
+	var files []string
+	var err error
+	switch {
+	case sb != nil:
+		baseBranch := "main"
+		if e.config != nil && e.config.DefaultBranch != "" {
+			baseBranch = e.config.DefaultBranch
+		}
+		files, err = sandbox.BranchFiles(ctx, sb, baseBranch)
+	case commitHash != "":
+		files, err = gitNames(ctx, workingDir, "diff-tree", "--no-commit-id", "--name-only", "-r", "-z", commitHash)
+	default:
+		files, err = gitNames(ctx, workingDir, "diff", "--name-only", "-z", "HEAD")
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to list the files changed in %s: %v\n", workingDir, err)
+		return nil
+	}
+	return files
+}
+
+// gitNames runs a git command printing NUL-separated paths (-z) in dir
+func gitNames(ctx context.Context, dir string, args ...string) ([]string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s failed: %w", args[0], err)
+	}
+	var names []string
+	for _, name := range strings.Split(string(output), "\x00") {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// currentBranch returns the branch checked out in dir, or "" if it can't
+// be read (or HEAD is detached)
+func currentBranch(ctx context.Context, dir string) string {
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "--abbrev-ref", "HEAD")
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if branch := strings.TrimSpace(string(output)); err == nil && branch != "HEAD" {
+		return branch
+	}
+	return ""
+}
+
+// attemptSummary describes how an execution ended, for the attempt row
+func attemptSummary(err error, result *AgentResult, procResult *ProcessingResult) string {
+	switch {
+	case err != nil:
+		return err.Error()
+	case result == nil:
+		return "execution ended before the agent ran"
+	case !result.Success:
+		return fmt.Sprintf("agent failed (exit code %d)", result.ExitCode)
+	case procResult == nil:
+		return "agent succeeded, results were not processed"
+	case !procResult.GatesPassed:
+		return "quality gates failed"
+	case !procResult.Completed:
+		return "agent succeeded, issue left open"
+	default:
+		return "completed"
+	}
+}
+
+// firstLine returns s up to its first newline
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+// lastLines joins the last n lines
+func lastLines(lines []string, n int) string {
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
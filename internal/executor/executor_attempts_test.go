@@ -0,0 +1,247 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage/storagetest"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// newAttemptTestExecutor returns an executor without AI supervision over a
+// fake store holding one claimed issue
+func newAttemptTestExecutor(t *testing.T) (*Executor, *storagetest.FakeStorage, *types.Issue) {
+	t.Helper()
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+
+	execCfg := DefaultConfig()
+	execCfg.Store = store
+	execCfg.EnableAISupervision = false
+	exec, err := New(execCfg)
+	if err != nil {
+		t.Fatalf("Failed to create executor: %v", err)
+	}
+
+	issue := &types.Issue{Title: "Attempted", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("Failed to create issue: %v", err)
+	}
+	if err := store.ClaimIssue(ctx, issue.ID, exec.instanceID); err != nil {
+		t.Fatalf("Failed to claim issue: %v", err)
+	}
+	return exec, store, issue
+}
+
+// TestExecuteIssueRecordsAttempt verifies an execution that ends early
+// (here: canceled before the agent is spawned) still leaves a finalized
+// attempt in the history
+func TestExecuteIssueRecordsAttempt(t *testing.T) {
+	exec, store, issue := newAttemptTestExecutor(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := exec.executeIssue(ctx, issue); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected executeIssue to return context.Canceled, got: %v", err)
+	}
+
+	history, err := store.GetExecutionHistory(context.Background(), issue.ID)
+	if err != nil {
+		t.Fatalf("Failed to get execution history: %v", err)
+	}
+	if len(history) != 1 {
+		t.Fatalf("Expected 1 attempt, got %d", len(history))
+	}
+	attempt := history[0]
+	if attempt.AttemptNumber != 1 || attempt.ExecutorInstanceID != exec.instanceID {
+		t.Errorf("Expected attempt 1 by %s, got attempt %d by %s", exec.instanceID, attempt.AttemptNumber, attempt.ExecutorInstanceID)
+	}
+	if attempt.CompletedAt == nil || attempt.Success == nil || *attempt.Success {
+		t.Errorf("Expected a completed, failed attempt, got %+v", attempt)
+	}
+	if !strings.Contains(attempt.Summary, "canceled") {
+		t.Errorf("Expected the summary to mention the cancellation, got %q", attempt.Summary)
+	}
+	if attempt.ExitCode != nil {
+		t.Errorf("Expected no exit code when the agent never ran, got %d", *attempt.ExitCode)
+	}
+
+	// The next attempt is numbered after the first
+	if err := store.ClaimIssue(context.Background(), issue.ID, exec.instanceID); err != nil {
+		t.Fatalf("Failed to reclaim issue: %v", err)
+	}
+	_ = exec.executeIssue(ctx, issue)
+	history, _ = store.GetExecutionHistory(context.Background(), issue.ID)
+	if len(history) != 2 || history[1].AttemptNumber != 2 {
+		t.Errorf("Expected a second attempt numbered 2, got %d attempts", len(history))
+	}
+}
+
+// TestAttemptRecorderFinish verifies the outcome is written once, with the
+// exit code and the tail of the agent's output
+func TestAttemptRecorderFinish(t *testing.T) {
+	exec, store, issue := newAttemptTestExecutor(t)
+	ctx := context.Background()
+
+	output := make([]string, attemptSampleLines+5)
+	for i := range output {
+		output[i] = fmt.Sprintf("line %d", i)
+	}
+	result := &AgentResult{Success: true, ExitCode: 0, Output: output, Errors: []string{"warning: flaky"}}
+
+	r := exec.startAttempt(ctx, issue.ID)
+	r.phases.Start(types.ExecutionPhaseAgent)
+	r.finish(ctx, true, "completed\nwith details", result)
+	r.finish(ctx, false, "second call", nil)
+
+	history, err := store.GetExecutionHistory(ctx, issue.ID)
+	if err != nil || len(history) != 1 {
+		t.Fatalf("Expected 1 attempt, got %d (%v)", len(history), err)
+	}
+	attempt := history[0]
+	if attempt.Success == nil || !*attempt.Success || attempt.Summary != "completed" {
+		t.Errorf("Expected the first outcome to stick, got success=%v summary=%q", attempt.Success, attempt.Summary)
+	}
+	if attempt.ExitCode == nil || *attempt.ExitCode != 0 {
+		t.Errorf("Expected exit code 0, got %v", attempt.ExitCode)
+	}
+	lines := strings.Split(attempt.OutputSample, "\n")
+	if len(lines) != attemptSampleLines || lines[0] != "line 5" {
+		t.Errorf("Expected the last %d output lines starting at line 5, got %d starting at %q", attemptSampleLines, len(lines), lines[0])
+	}
+	if attempt.ErrorSample != "warning: flaky" {
+		t.Errorf("Expected the error sample, got %q", attempt.ErrorSample)
+	}
+	if len(attempt.Phases) != 1 || attempt.Phases[0].Phase != types.ExecutionPhaseAgent {
+		t.Errorf("Expected the running agent phase to be stopped and recorded, got %+v", attempt.Phases)
+	}
+	if n := store.CallCount("UpdateExecutionAttempt"); n != 1 {
+		t.Errorf("Expected 1 UpdateExecutionAttempt call, got %d", n)
+	}
+}
+
+// TestReleaseIssueWithErrorCountsCurrentAttempt verifies the failing
+// execution counts towards the consecutive-failure limit even though its
+// attempt is only finalized after the release
+func TestReleaseIssueWithErrorCountsCurrentAttempt(t *testing.T) {
+	exec, store, issue := newAttemptTestExecutor(t)
+	ctx := context.Background()
+
+	start := time.Now().Add(-time.Hour)
+	for n := 1; n <= 2; n++ {
+		completed := start.Add(time.Duration(n) * time.Minute)
+		failed := false
+		if err := store.RecordExecutionAttempt(ctx, &types.ExecutionAttempt{
+			IssueID:            issue.ID,
+			ExecutorInstanceID: exec.instanceID,
+			AttemptNumber:      n,
+			StartedAt:          start.Add(time.Duration(n-1) * time.Minute),
+			CompletedAt:        &completed,
+			Success:            &failed,
+		}); err != nil {
+			t.Fatalf("Failed to record attempt %d: %v", n, err)
+		}
+	}
+
+	exec.startAttempt(ctx, issue.ID)
+	exec.releaseIssueWithError(ctx, issue.ID, "Agent execution failed: exit status 1")
+
+	got, err := store.GetIssue(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("Failed to get issue: %v", err)
+	}
+	if got.Status != types.StatusBlocked {
+		t.Errorf("Expected the third consecutive failure to block the issue, got status %s", got.Status)
+	}
+
+	// The block is labeled and recorded apart from dependency blocks
+	labels, err := store.GetLabels(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("Failed to get labels: %v", err)
+	}
+	if len(labels) != 1 || labels[0] != types.AutoBlockedLabel {
+		t.Errorf("Expected the %s label, got %v", types.AutoBlockedLabel, labels)
+	}
+	blocked, err := store.GetAgentEvents(ctx, events.EventFilter{Type: events.EventTypeIssueAutoBlocked})
+	if err != nil {
+		t.Fatalf("Failed to get events: %v", err)
+	}
+	if len(blocked) != 1 || blocked[0].IssueID != issue.ID {
+		t.Fatalf("Expected 1 issue_auto_blocked event for %s, got %v", issue.ID, blocked)
+	}
+	data, err := blocked[0].GetIssueAutoBlockedData()
+	if err != nil {
+		t.Fatalf("Failed to parse event data: %v", err)
+	}
+	if data.FailureCount != 3 || data.LastError != "Agent execution failed: exit status 1" || len(data.AttemptIDs) != 3 {
+		t.Errorf("Expected 3 failures, the last error and 3 attempt IDs, got %+v", data)
+	}
+	if stats, err := store.GetStatistics(ctx); err != nil || stats.AutoBlockedIssues != 1 {
+		t.Errorf("Expected 1 auto-blocked issue in the statistics, got %+v (%v)", stats, err)
+	}
+}
+
+// TestReleaseIssueWithErrorKeepsBlockedIssue verifies a failing execution
+// doesn't reopen an issue that was blocked while it ran, as the watchdog's
+// cancel_and_block policy does
+func TestReleaseIssueWithErrorKeepsBlockedIssue(t *testing.T) {
+	exec, store, issue := newAttemptTestExecutor(t)
+	ctx := context.Background()
+
+	if err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"status": string(types.StatusBlocked)}, "watchdog"); err != nil {
+		t.Fatalf("Failed to block issue: %v", err)
+	}
+	exec.releaseIssueWithError(ctx, issue.ID, "Agent execution failed: context canceled")
+
+	got, err := store.GetIssue(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("Failed to get issue: %v", err)
+	}
+	if got.Status != types.StatusBlocked {
+		t.Errorf("Expected the issue to stay blocked, got status %s", got.Status)
+	}
+	if state, err := store.GetExecutionState(ctx, issue.ID); err != nil || state != nil {
+		t.Errorf("Expected the claim to be released, got %+v (%v)", state, err)
+	}
+}
+
+// TestReleaseIssueWithErrorSkipsInterruptedAttempts verifies that attempts
+// cut short by executor shutdown don't count towards the consecutive-failure
+// limit
+func TestReleaseIssueWithErrorSkipsInterruptedAttempts(t *testing.T) {
+	exec, store, issue := newAttemptTestExecutor(t)
+	ctx := context.Background()
+
+	start := time.Now().Add(-time.Hour)
+	for n := 1; n <= 3; n++ {
+		completed := start.Add(time.Duration(n) * time.Minute)
+		failed := false
+		if err := store.RecordExecutionAttempt(ctx, &types.ExecutionAttempt{
+			IssueID:            issue.ID,
+			ExecutorInstanceID: exec.instanceID,
+			AttemptNumber:      n,
+			StartedAt:          start.Add(time.Duration(n-1) * time.Minute),
+			CompletedAt:        &completed,
+			Success:            &failed,
+			Summary:            errInterrupted.Error() + ": Agent stopped by executor shutdown: signal: killed",
+		}); err != nil {
+			t.Fatalf("Failed to record attempt %d: %v", n, err)
+		}
+	}
+
+	exec.startAttempt(ctx, issue.ID)
+	exec.releaseIssueWithError(ctx, issue.ID, "Agent execution failed: exit status 1")
+
+	got, err := store.GetIssue(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("Failed to get issue: %v", err)
+	}
+	if got.Status != types.StatusOpen {
+		t.Errorf("Expected interrupted attempts not to block the issue, got status %s", got.Status)
+	}
+}
@@ -0,0 +1,107 @@
+package executor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/storage/beads"
+)
+
+// TestScheduledBackup verifies the cleanup loop's periodic backup: one
+// backup per interval, pruned to the configured retention
+func TestScheduledBackup(t *testing.T) {
+	ctx := context.Background()
+
+	cfg := storage.DefaultConfig()
+	cfg.Path = filepath.Join(t.TempDir(), "vc.db")
+	store, err := storage.NewStorage(ctx, cfg)
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	backupDir := filepath.Join(t.TempDir(), "backups")
+	execCfg := DefaultConfig()
+	execCfg.Store = store
+	execCfg.EnableAISupervision = false
+	execCfg.EnableQualityGates = false
+	execCfg.EnableSandboxes = false
+	execCfg.BackupDir = backupDir
+	execCfg.BackupInterval = time.Hour
+	execCfg.BackupRetention = 2
+
+	executor, err := New(execCfg)
+	if err != nil {
+		t.Fatalf("failed to create executor: %v", err)
+	}
+
+	countBackups := func() int {
+		t.Helper()
+		matches, err := filepath.Glob(filepath.Join(backupDir, "vc-backup-*.db"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(matches)
+	}
+
+	start := time.Now()
+	steps := []struct {
+		offset time.Duration
+		want   int
+	}{
+		{0, 1},                 // first tick backs up
+		{30 * time.Minute, 1},  // within the interval: nothing to do
+		{time.Hour, 2},         // interval passed
+		{2 * time.Hour, 2},     // third backup, oldest pruned
+		{150 * time.Minute, 2}, // within the interval again
+	}
+	for _, step := range steps {
+		if err := executor.runScheduledBackup(ctx, start.Add(step.offset)); err != nil {
+			t.Fatalf("runScheduledBackup at +%v failed: %v", step.offset, err)
+		}
+		if got := countBackups(); got != step.want {
+			t.Errorf("at +%v: expected %d backups, got %d", step.offset, step.want, got)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(backupDir, beads.BackupFileName(start))); !os.IsNotExist(err) {
+		t.Errorf("expected the oldest backup to be pruned (stat err: %v)", err)
+	}
+	if _, err := beads.VerifyBackup(ctx, filepath.Join(backupDir, beads.BackupFileName(start.Add(2*time.Hour)))); err != nil {
+		t.Errorf("newest backup failed verification: %v", err)
+	}
+}
+
+// TestScheduledBackupDisabled verifies nothing is written without a BackupDir
+func TestScheduledBackupDisabled(t *testing.T) {
+	ctx := context.Background()
+
+	cfg := storage.DefaultConfig()
+	cfg.Path = ":memory:"
+	store, err := storage.NewStorage(ctx, cfg)
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	execCfg := DefaultConfig()
+	execCfg.Store = store
+	execCfg.EnableAISupervision = false
+	execCfg.EnableQualityGates = false
+	execCfg.EnableSandboxes = false
+
+	executor, err := New(execCfg)
+	if err != nil {
+		t.Fatalf("failed to create executor: %v", err)
+	}
+	if err := executor.runScheduledBackup(ctx, time.Now()); err != nil {
+		t.Errorf("expected a disabled backup to be a no-op, got %v", err)
+	}
+	if !executor.lastBackup.IsZero() {
+		t.Error("expected no backup to be recorded")
+	}
+}
@@ -0,0 +1,56 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/steveyegge/vc/internal/sandbox"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// loadCheckpoint returns the checkpoint a previous attempt left for the
+// issue, or nil. A checkpoint this release can't interpret (another format
+// version, or unversioned data from before checkpoints had one) is discarded
+// so the execution starts fresh instead of failing.
+func (e *Executor) loadCheckpoint(ctx context.Context, issueID string) *types.CheckpointV1 {
+	checkpoint, err := e.store.GetCheckpoint(ctx, issueID)
+	if errors.Is(err, types.ErrCheckpointVersionMismatch) {
+		fmt.Fprintf(os.Stderr, "warning: discarding checkpoint for %s: %v\n", issueID, err)
+		if err := e.store.SaveCheckpoint(ctx, issueID, nil); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to clear checkpoint for %s: %v\n", issueID, err)
+		}
+		return nil
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to load checkpoint for %s: %v\n", issueID, err)
+		return nil
+	}
+	return checkpoint
+}
+
+// saveCheckpoint records where the execution of the issue stands. previous
+// is the checkpoint the execution started from (nil if it started fresh);
+// its creation time and extras carry over.
+func (e *Executor) saveCheckpoint(ctx context.Context, issueID string, previous *types.CheckpointV1, phase types.ExecutionState, sb *sandbox.Sandbox, agentID string) {
+	now := time.Now()
+	checkpoint := &types.CheckpointV1{
+		Phase:     phase,
+		AgentID:   agentID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if previous != nil {
+		checkpoint.CreatedAt = previous.CreatedAt
+		checkpoint.Extras = previous.Extras
+	}
+	if sb != nil {
+		checkpoint.SandboxPath = sb.Path
+		checkpoint.Branch = sb.GitBranch
+	}
+	if err := e.store.SaveCheckpoint(ctx, issueID, checkpoint); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to save checkpoint for %s: %v\n", issueID, err)
+	}
+}
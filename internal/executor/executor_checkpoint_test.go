@@ -0,0 +1,48 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// TestLoadCheckpointDiscardsMismatch verifies a checkpoint from another
+// format version is cleared and the execution starts fresh
+func TestLoadCheckpointDiscardsMismatch(t *testing.T) {
+	exec, store, issue := newAttemptTestExecutor(t)
+	ctx := context.Background()
+
+	store.FailOn("GetCheckpoint", fmt.Errorf("%w: got version 0, want 1", types.ErrCheckpointVersionMismatch))
+	if checkpoint := exec.loadCheckpoint(ctx, issue.ID); checkpoint != nil {
+		t.Errorf("Expected no checkpoint, got %+v", checkpoint)
+	}
+	calls := store.Calls("SaveCheckpoint")
+	if len(calls) != 1 || calls[0].Args[1].(*types.CheckpointV1) != nil {
+		t.Errorf("Expected the checkpoint to be cleared with SaveCheckpoint(nil), got %+v", calls)
+	}
+}
+
+// TestSaveCheckpointCarriesOver verifies a resumed execution keeps the
+// original checkpoint's creation time and extras
+func TestSaveCheckpointCarriesOver(t *testing.T) {
+	exec, store, issue := newAttemptTestExecutor(t)
+	ctx := context.Background()
+
+	exec.saveCheckpoint(ctx, issue.ID, nil, types.ExecutionStateExecuting, nil, "agent-1")
+	first := exec.loadCheckpoint(ctx, issue.ID)
+	if first == nil || first.Phase != types.ExecutionStateExecuting || first.AgentID != "agent-1" {
+		t.Fatalf("Expected the saved checkpoint, got %+v", first)
+	}
+	first.Extras = map[string]interface{}{"rebase": "in progress"}
+
+	exec.saveCheckpoint(ctx, issue.ID, first, types.ExecutionStateExecuting, nil, "agent-2")
+	second, err := store.GetCheckpoint(ctx, issue.ID)
+	if err != nil || second == nil {
+		t.Fatalf("Expected a checkpoint, got (%+v, %v)", second, err)
+	}
+	if second.AgentID != "agent-2" || !second.CreatedAt.Equal(first.CreatedAt) || second.Extras["rebase"] != "in progress" {
+		t.Errorf("Expected the new agent with the original creation time and extras, got %+v", second)
+	}
+}
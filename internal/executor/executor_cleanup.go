@@ -4,10 +4,16 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"time"
 
+	"github.com/steveyegge/vc/internal/artifacts"
 	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/digest"
+	"github.com/steveyegge/vc/internal/events"
 	"github.com/steveyegge/vc/internal/git"
+	"github.com/steveyegge/vc/internal/schedule"
+	"github.com/steveyegge/vc/internal/storage/beads"
 )
 
 // cleanupOrphanedBranches removes orphaned mission branches on startup (vc-135)
@@ -65,6 +71,11 @@ func (e *Executor) cleanupLoop(ctx context.Context) {
 			default:
 			}
 
+			// Stale instances can't be told apart while the database is down
+			if e.StorageDegraded() {
+				continue
+			}
+
 			// Run cleanup with cancellation support
 			// Use a channel to make cleanup interruptible
 			done := make(chan error, 1)
@@ -99,6 +110,23 @@ func (e *Executor) cleanupLoop(ctx context.Context) {
 						deletedInstances, e.instanceCleanupAge, e.instanceCleanupKeep)
 				}
 
+				// Periodic database backup, if configured
+				if err := e.runScheduledBackup(ctx, time.Now()); err != nil {
+					fmt.Fprintf(os.Stderr, "warning: scheduled database backup failed: %v\n", err)
+					// Don't fail the cleanup loop on backup errors
+				}
+
+				// Remove attempt artifacts past the retention policy
+				e.sweepArtifacts(time.Now())
+
+				// File issues from schedules that are due (vc schedule)
+				e.runSchedules(ctx, time.Now())
+
+				// Record the daily digest, if configured
+				if err := e.runDailySummary(ctx, time.Now()); err != nil {
+					fmt.Fprintf(os.Stderr, "warning: daily summary failed: %v\n", err)
+				}
+
 				done <- nil
 			}()
 
@@ -118,6 +146,99 @@ func (e *Executor) cleanupLoop(ctx context.Context) {
 	}
 }
 
+// runScheduledBackup backs up the database into backupDir once
+// backupInterval has passed since the last backup, then prunes the backups
+// beyond backupRetention. It does nothing if backups aren't configured.
+func (e *Executor) runScheduledBackup(ctx context.Context, now time.Time) error {
+	if e.backupDir == "" || now.Sub(e.lastBackup) < e.backupInterval {
+		return nil
+	}
+	vcStorage, ok := e.store.(*beads.VCStorage)
+	if !ok {
+		return fmt.Errorf("storage is not VCStorage (backups need SQLite)")
+	}
+
+	path := filepath.Join(e.backupDir, beads.BackupFileName(now))
+	manifest, err := vcStorage.Backup(ctx, path)
+	if err != nil {
+		return err
+	}
+	e.lastBackup = now
+	fmt.Printf("Backup: Backed up database to %s (%d issues, %d events)\n", path, manifest.Issues, manifest.AgentEvents)
+
+	pruned, err := beads.PruneBackups(e.backupDir, e.backupRetention)
+	if err != nil {
+		return fmt.Errorf("failed to prune old backups: %w", err)
+	}
+	if pruned > 0 {
+		fmt.Printf("Backup: Deleted %d old backup(s) (keeping %d most recent)\n", pruned, e.backupRetention)
+	}
+	return nil
+}
+
+// runSchedules files the issues of due schedules. Errors are logged; one
+// failing schedule doesn't stop the others.
+func (e *Executor) runSchedules(ctx context.Context, now time.Time) {
+	filed, err := schedule.Run(ctx, e.store, now, e.project)
+	for _, issue := range filed {
+		fmt.Printf("Schedule: Filed %s: %s\n", issue.ID, issue.Title)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: scheduled issue creation failed: %v\n", err)
+	}
+}
+
+// dailySummaryPeriod is how often runDailySummary records a digest
+const dailySummaryPeriod = 24 * time.Hour
+
+// runDailySummary records a daily_summary event with the digest of the last
+// day, and posts it to the configured webhook, unless one was recorded less
+// than a day ago by this or another executor. It does nothing unless
+// executor.daily_summary is on.
+func (e *Executor) runDailySummary(ctx context.Context, now time.Time) error {
+	if e.config == nil || !e.config.DailySummary {
+		return nil
+	}
+	since := now.Add(-dailySummaryPeriod)
+	recent, err := e.store.GetAgentEvents(ctx, events.EventFilter{Type: events.EventTypeDailySummary, AfterTime: since, Limit: 1})
+	if err != nil {
+		return fmt.Errorf("failed to query %s events: %w", events.EventTypeDailySummary, err)
+	}
+	if len(recent) > 0 {
+		return nil
+	}
+
+	d, err := digest.Build(ctx, e.store, since, now, digest.Options{Project: e.project})
+	if err != nil {
+		return err
+	}
+	markdown := d.Markdown()
+	event, err := events.NewDailySummaryEvent(e.instanceID, "Daily summary: "+d.Headline(), events.DailySummaryData{
+		Since:      d.Since,
+		Until:      d.Until,
+		Completed:  len(d.Completed),
+		Failed:     len(d.Failed),
+		Blocked:    len(d.Blocked),
+		Discovered: len(d.Discovered),
+		CostUSD:    d.AI.CostUSD,
+		Markdown:   markdown,
+	})
+	if err != nil {
+		return err
+	}
+	if err := e.store.StoreAgentEvent(ctx, event); err != nil {
+		return fmt.Errorf("failed to store daily summary: %w", err)
+	}
+	fmt.Printf("Summary: %s\n", d.Headline())
+
+	if e.config.DailySummaryWebhook != "" {
+		if err := digest.Post(ctx, e.config.DailySummaryWebhook, markdown); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // eventCleanupLoop runs periodic cleanup of old events in a background goroutine
 // This enforces event retention policies to prevent database bloat
 func (e *Executor) eventCleanupLoop(ctx context.Context) {
@@ -183,6 +304,7 @@ func (e *Executor) runEventCleanup(ctx context.Context, cfg config.EventRetentio
 
 	// Track metrics for logging
 	var timeBasedDeleted, perIssueDeleted, globalLimitDeleted int
+	var historyDeleted, artifactsDeleted int
 	var vacuumRan bool
 	var cleanupErr error
 
@@ -191,7 +313,7 @@ func (e *Executor) runEventCleanup(ctx context.Context, cfg config.EventRetentio
 	if err != nil {
 		cleanupErr = fmt.Errorf("time-based cleanup failed: %w", err)
 		// Log error event and return
-		e.logCleanupEvent(ctx, 0, 0, 0, 0, time.Since(startTime).Milliseconds(), false, 0, false, cleanupErr.Error())
+		e.logCleanupEvent(ctx, 0, 0, 0, 0, time.Since(startTime).Milliseconds(), false, 0, 0, 0, false, cleanupErr.Error())
 		return cleanupErr
 	}
 	timeBasedDeleted = deleted
@@ -201,7 +323,7 @@ func (e *Executor) runEventCleanup(ctx context.Context, cfg config.EventRetentio
 	if err != nil {
 		cleanupErr = fmt.Errorf("per-issue limit cleanup failed: %w", err)
 		// Log error event with partial results
-		e.logCleanupEvent(ctx, timeBasedDeleted, timeBasedDeleted, 0, 0, time.Since(startTime).Milliseconds(), false, 0, false, cleanupErr.Error())
+		e.logCleanupEvent(ctx, timeBasedDeleted, timeBasedDeleted, 0, 0, time.Since(startTime).Milliseconds(), false, 0, 0, 0, false, cleanupErr.Error())
 		return cleanupErr
 	}
 	perIssueDeleted = deleted
@@ -213,23 +335,27 @@ func (e *Executor) runEventCleanup(ctx context.Context, cfg config.EventRetentio
 	if err != nil {
 		cleanupErr = fmt.Errorf("global limit cleanup failed: %w", err)
 		// Log error event with partial results
-		e.logCleanupEvent(ctx, timeBasedDeleted+perIssueDeleted, timeBasedDeleted, perIssueDeleted, 0, time.Since(startTime).Milliseconds(), false, 0, false, cleanupErr.Error())
+		e.logCleanupEvent(ctx, timeBasedDeleted+perIssueDeleted, timeBasedDeleted, perIssueDeleted, 0, time.Since(startTime).Milliseconds(), false, 0, 0, 0, false, cleanupErr.Error())
 		return cleanupErr
 	}
 	globalLimitDeleted = deleted
 
 	totalDeleted := timeBasedDeleted + perIssueDeleted + globalLimitDeleted
 
-	// Step 4: Optional VACUUM to reclaim disk space
-	if cfg.CleanupVacuum && totalDeleted > 0 {
-		if err := e.store.VacuumDatabase(ctx); err != nil {
-			// Don't fail the whole cleanup if VACUUM fails
-			fmt.Fprintf(os.Stderr, "event cleanup: warning: VACUUM failed: %v\n", err)
-		} else {
-			vacuumRan = true
-		}
+	// Step 4: Execution history retention, with the artifacts of the
+	// attempts it deletes
+	historyDeleted, artifactsDeleted, err = e.cleanupExecutionHistory(ctx, cfg)
+	if err != nil {
+		cleanupErr = fmt.Errorf("execution history cleanup failed: %w", err)
+		// Log error event with partial results
+		e.logCleanupEvent(ctx, totalDeleted, timeBasedDeleted, perIssueDeleted, globalLimitDeleted, time.Since(startTime).Milliseconds(), false, 0, historyDeleted, artifactsDeleted, false, cleanupErr.Error())
+		return cleanupErr
 	}
 
+	// Step 5: Reclaim free pages if the vacuum policy says it's worth it
+	// (see executor_vacuum.go). Failures are logged, never fail cleanup.
+	vacuumRan = e.maybeVacuum(ctx, cfg)
+
 	// Get remaining event count for metrics
 	eventsRemaining := 0
 	counts, err := e.store.GetEventCounts(ctx)
@@ -242,7 +368,7 @@ func (e *Executor) runEventCleanup(ctx context.Context, cfg config.EventRetentio
 	processingTimeMs := time.Since(startTime).Milliseconds()
 
 	// Log cleanup metrics as structured agent event (vc-196)
-	e.logCleanupEvent(ctx, totalDeleted, timeBasedDeleted, perIssueDeleted, globalLimitDeleted, processingTimeMs, vacuumRan, eventsRemaining, true, "")
+	e.logCleanupEvent(ctx, totalDeleted, timeBasedDeleted, perIssueDeleted, globalLimitDeleted, processingTimeMs, vacuumRan, eventsRemaining, historyDeleted, artifactsDeleted, true, "")
 
 	// Also log to stdout for visibility
 	if totalDeleted > 0 || vacuumRan {
@@ -253,6 +379,29 @@ func (e *Executor) runEventCleanup(ctx context.Context, cfg config.EventRetentio
 		}
 		fmt.Printf(" (remaining=%d)\n", eventsRemaining)
 	}
+	if historyDeleted > 0 {
+		fmt.Printf("Event cleanup: Deleted %d execution attempt(s) and %d artifact(s)\n", historyDeleted, artifactsDeleted)
+	}
 
 	return nil
 }
+
+// cleanupExecutionHistory deletes the execution attempts the retention
+// config no longer keeps (none if HistoryRetentionDays is 0), then their
+// artifacts. It returns how many attempts and artifact files it deleted;
+// artifacts of attempts deleted before a failure are still removed.
+func (e *Executor) cleanupExecutionHistory(ctx context.Context, cfg config.EventRetentionConfig) (int, int, error) {
+	if cfg.HistoryRetentionDays == 0 {
+		return 0, 0, nil
+	}
+	deleted, err := e.store.CleanupExecutionHistory(ctx, cfg.HistoryKeepPerIssue, cfg.HistoryRetentionDays, cfg.CleanupBatchSize)
+	var paths []string
+	for _, attempt := range deleted {
+		paths = append(paths, attempt.Artifacts...)
+	}
+	removed, rmErr := artifacts.Remove(paths)
+	if rmErr != nil {
+		fmt.Fprintf(os.Stderr, "event cleanup: warning: failed to remove artifacts: %v\n", rmErr)
+	}
+	return len(deleted), removed, err
+}
@@ -0,0 +1,79 @@
+package executor
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage/storagetest"
+)
+
+// TestDailySummary verifies the cleanup loop records one digest a day and
+// posts it to the webhook
+func TestDailySummary(t *testing.T) {
+	ctx := context.Background()
+	var posts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), "VC summary") {
+			posts.Add(1)
+		}
+	}))
+	defer server.Close()
+
+	store := storagetest.NewFakeStorage()
+	cfg := DefaultConfig()
+	cfg.Store = store
+	cfg.EnableAISupervision = false
+	exec, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create executor: %v", err)
+	}
+	countSummaries := func() int {
+		t.Helper()
+		stored, err := store.GetAgentEvents(ctx, events.EventFilter{Type: events.EventTypeDailySummary})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(stored)
+	}
+
+	now := time.Now()
+	if err := exec.runDailySummary(ctx, now); err != nil || countSummaries() != 0 {
+		t.Fatalf("Expected no summary while executor.daily_summary is off (%v)", err)
+	}
+
+	exec.config.DailySummary = true
+	exec.config.DailySummaryWebhook = server.URL
+	if err := exec.runDailySummary(ctx, now); err != nil {
+		t.Fatalf("runDailySummary: %v", err)
+	}
+	if err := exec.runDailySummary(ctx, now.Add(time.Hour)); err != nil {
+		t.Fatalf("runDailySummary: %v", err)
+	}
+	if countSummaries() != 1 || posts.Load() != 1 {
+		t.Fatalf("Expected one summary recorded and posted within a day, got %d and %d", countSummaries(), posts.Load())
+	}
+
+	stored, _ := store.GetAgentEvents(ctx, events.EventFilter{Type: events.EventTypeDailySummary})
+	data, err := stored[0].GetDailySummaryData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored[0].IssueID != "SYSTEM" || !strings.HasPrefix(data.Markdown, "## VC summary") || !data.Until.Equal(now) {
+		t.Errorf("Unexpected summary event: %+v, %+v", stored[0], data)
+	}
+
+	if err := exec.runDailySummary(ctx, now.Add(25*time.Hour)); err != nil {
+		t.Fatalf("runDailySummary: %v", err)
+	}
+	if countSummaries() != 2 {
+		t.Errorf("Expected another summary a day later, got %d", countSummaries())
+	}
+}
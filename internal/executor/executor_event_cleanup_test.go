@@ -11,19 +11,10 @@ import (
 	"github.com/steveyegge/vc/internal/types"
 )
 
-// createSystemIssue creates a pseudo-issue for system-level events
+// createSystemIssue makes sure the SYSTEM pseudo-issue for system-level events exists
 func createSystemIssue(ctx context.Context, t *testing.T, store storage.Storage) {
 	t.Helper()
-	systemIssue := &types.Issue{
-		Title:       "System-level events",
-		Description: "Pseudo-issue for system-level events not tied to a specific issue",
-		Status:      types.StatusOpen,
-		Priority:    3,
-		IssueType:   types.TypeTask,
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
-	}
-	if err := store.CreateIssue(ctx, systemIssue, "test"); err != nil {
+	if _, err := store.EnsureSystemIssue(ctx); err != nil {
 		t.Fatalf("failed to create SYSTEM issue: %v", err)
 	}
 }
@@ -106,7 +97,7 @@ func TestEventCleanupMetricsLogging(t *testing.T) {
 	retentionCfg.RetentionCriticalDays = 1    // Also delete critical events
 	retentionCfg.PerIssueLimitEvents = 1000   // High limit
 	retentionCfg.GlobalLimitEvents = 10000    // High limit
-	retentionCfg.CleanupVacuum = false        // Skip VACUUM for speed
+	retentionCfg.VacuumFreeFraction = 0       // Skip VACUUM for speed
 
 	err = executor.runEventCleanup(ctx, retentionCfg)
 	if err != nil {
@@ -324,7 +315,7 @@ func TestEventCleanupPartialFailure(t *testing.T) {
 	retentionCfg.RetentionDays = 7            // Keep 7 days
 	retentionCfg.PerIssueLimitEvents = 1000
 	retentionCfg.GlobalLimitEvents = 10000
-	retentionCfg.CleanupVacuum = false
+	retentionCfg.VacuumFreeFraction = 0
 
 	err = executor.runEventCleanup(ctx, retentionCfg)
 	if err != nil {
@@ -402,7 +393,7 @@ func TestLogCleanupEvent(t *testing.T) {
 	createSystemIssue(ctx, t, store)
 
 	// Test successful cleanup logging
-	executor.logCleanupEvent(ctx, 100, 50, 30, 20, 1234, true, 500, true, "")
+	executor.logCleanupEvent(ctx, 100, 50, 30, 20, 1234, true, 500, 7, 12, true, "")
 
 	// Retrieve the event
 	filter := events.EventFilter{
@@ -451,12 +442,18 @@ func TestLogCleanupEvent(t *testing.T) {
 	if int(data["events_remaining"].(float64)) != 500 {
 		t.Errorf("expected 500 events_remaining")
 	}
+	if int(data["history_deleted"].(float64)) != 7 {
+		t.Errorf("expected 7 history_deleted")
+	}
+	if int(data["artifacts_deleted"].(float64)) != 12 {
+		t.Errorf("expected 12 artifacts_deleted")
+	}
 	if data["success"].(bool) != true {
 		t.Errorf("expected success to be true")
 	}
 
 	// Test error logging
-	executor.logCleanupEvent(ctx, 0, 0, 0, 0, 100, false, 1000, false, "database error")
+	executor.logCleanupEvent(ctx, 0, 0, 0, 0, 100, false, 1000, 0, 0, false, "database error")
 
 	cleanupEvents, err = store.GetAgentEvents(ctx, filter)
 	if err != nil {
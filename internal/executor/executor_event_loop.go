@@ -2,8 +2,10 @@ package executor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/steveyegge/vc/internal/events"
@@ -24,9 +26,29 @@ func (e *Executor) eventLoop(ctx context.Context) {
 		case <-e.stopCh:
 			return
 		case <-ticker.C:
-			// Update heartbeat
-			if err := e.store.UpdateHeartbeat(ctx, e.instanceID); err != nil {
-				fmt.Fprintf(os.Stderr, "failed to update heartbeat: %v\n", err)
+			// select picks at random among ready cases, so a tick can
+			// still win once shutdown has begun; claim nothing then
+			if e.shuttingDown(ctx) {
+				return
+			}
+
+			// Nothing reaches a database that keeps failing until the
+			// heartbeat loop has reopened it
+			if e.StorageDegraded() {
+				continue
+			}
+
+			// Epic bookkeeping claims no work, so it runs while paused too
+			if e.epicCloser != nil {
+				if err := e.epicCloser.sweep(ctx); err != nil {
+					fmt.Fprintf(os.Stderr, "epic auto-close sweep failed: %v\n", err)
+				}
+			}
+
+			// A paused executor claims nothing new. Issues run to
+			// completion inside processNextIssue, so nothing is cut short.
+			if e.checkPaused(ctx) {
+				continue
 			}
 
 			// Process one code work issue (regular tasks)
@@ -42,16 +64,51 @@ func (e *Executor) eventLoop(ctx context.Context) {
 					fmt.Fprintf(os.Stderr, "error processing QA work: %v\n", err)
 				}
 			}
+		}
+	}
+}
 
-			// Check health monitors after completing an issue (if enabled)
-			if e.enableHealthMonitoring && e.healthRegistry != nil {
-				if err := e.checkHealthMonitors(ctx); err != nil {
-					// Log error but continue
-					fmt.Fprintf(os.Stderr, "error running health monitors: %v\n", err)
-				}
-			}
+// shuttingDown reports whether ctx ended or Stop was called
+func (e *Executor) shuttingDown(ctx context.Context) bool {
+	select {
+	case <-ctx.Done():
+		return true
+	case <-e.stopCh:
+		return true
+	default:
+		return false
+	}
+}
+
+// pausedSetting is the config key vc tui and vc config set flip to pause
+// and resume running executors
+const pausedSetting = "executor.paused"
+
+// checkPaused re-reads executor.paused and reports whether to skip claiming
+// work this tick. An unset key keeps Config.Paused. A failed read keeps the
+// previous state, so a busy database can't resume a paused executor.
+func (e *Executor) checkPaused(ctx context.Context) bool {
+	value, err := e.store.GetConfig(ctx, pausedSetting)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read %s: %v\n", pausedSetting, err)
+		return e.paused
+	}
+	paused := e.config.Paused
+	if value != "" {
+		if paused, err = strconv.ParseBool(value); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid %s value %q: %v\n", pausedSetting, value, err)
+			return e.paused
+		}
+	}
+	if paused != e.paused {
+		if paused {
+			fmt.Printf("Paused: not claiming new work (%s)\n", pausedSetting)
+		} else {
+			fmt.Printf("Resumed: claiming work again\n")
 		}
+		e.paused = paused
 	}
+	return paused
 }
 
 // processNextQAWork attempts to claim and process one mission that needs quality gates (vc-254)
@@ -187,15 +244,17 @@ func (e *Executor) getNextReadyBlocker(ctx context.Context) (*types.Issue, error
 	// Use optimized storage method that does filtering in SQL (vc-156)
 	// This replaces the old approach of fetching all blockers then checking dependencies one by one
 	// Performance: O(1) query instead of O(N) queries where N = number of blockers
-	blockers, err := e.store.GetReadyBlockers(ctx, 1)
+	blockers, err := e.store.GetReadyBlockers(ctx, types.WorkFilter{
+		Limit:      1,
+		Project:    e.project,
+		AgentsOnly: e.respectAssignees,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get ready blockers: %w", err)
 	}
-
 	if len(blockers) == 0 {
 		return nil, nil
 	}
-
 	return blockers[0], nil
 }
 
@@ -254,37 +313,90 @@ func (e *Executor) processNextIssue(ctx context.Context) error {
 		}
 	}
 
+	// Work of missions awaiting plan approval is held back; say so once
+	e.noticeMissionsAwaitingApproval(ctx)
+
+	// Work whose files overlap other executors' work in flight waits
+	// (see executor_overlap.go)
+	inFlight := e.inFlightFiles(ctx)
+
 	// Priority 1: Try to get a ready blocker
+	// The ready-work queries and the claim tell whether the database works
+	// (see executor_storage_health.go)
 	issue, err := e.getNextReadyBlocker(ctx)
+	e.noteStorageResult(ctx, err)
 	if err != nil {
 		return fmt.Errorf("failed to get ready blockers: %w", err)
 	}
+	if issue != nil && e.deferOverlapping(ctx, issue, inFlight) {
+		issue = nil
+	}
 
 	// Priority 2: Fall back to regular ready work
 	if issue == nil {
+		limit := 1
+		if len(inFlight) > 0 {
+			limit = overlapCandidates
+		}
 		filter := types.WorkFilter{
 			Status:     types.StatusOpen,
-			Limit:      1,
-			SortPolicy: types.SortPolicyPriority, // vc-190: Always use priority-first sorting
+			Limit:      limit,
+			OrderBy:    e.workOrder, // vc-190: priority first unless executor.work_order says otherwise
+			Project:    e.project,
+			AgentsOnly: e.respectAssignees,
 		}
 
 		issues, err := e.store.GetReadyWork(ctx, filter)
+		e.noteStorageResult(ctx, err)
 		if err != nil {
 			return fmt.Errorf("failed to get ready work: %w", err)
 		}
 
-		if len(issues) == 0 {
-			// No work available
+		for _, candidate := range issues {
+			if !e.deferOverlapping(ctx, candidate, inFlight) {
+				issue = candidate
+				break
+			}
+		}
+		if issue == nil {
+			// No work available, or all of it overlaps work in flight
 			return nil
 		}
-
-		issue = issues[0]
 	}
 
 	// Attempt to claim the issue
-	if err := e.store.ClaimIssue(ctx, issue.ID, e.instanceID); err != nil {
-		// Issue may have been claimed by another executor
-		// This is expected in multi-executor scenarios
+	if err := e.storageCall(ctx, "ClaimIssue", issue.ID, func(ctx context.Context) error {
+		return e.store.ClaimIssue(ctx, issue.ID, e.instanceID)
+	}); err != nil {
+		if errors.Is(err, types.ErrAlreadyClaimed) {
+			// Another executor got there first; expected in multi-executor
+			// scenarios, so just try again next poll
+			e.claimConflicts.Add(1)
+			if os.Getenv("VC_DEBUG_CLAIMS") != "" {
+				fmt.Fprintf(os.Stderr, "[DEBUG] Claim conflict: %v\n", err)
+			}
+			return nil
+		}
+		if errors.Is(err, types.ErrNotClaimable) {
+			// The issue was closed or blocked after the ready-work query;
+			// a lost race like the above, not a storage failure
+			if os.Getenv("VC_DEBUG_CLAIMS") != "" {
+				fmt.Fprintf(os.Stderr, "[DEBUG] Claim lost: %v\n", err)
+			}
+			return nil
+		}
+		var approvalErr *types.AwaitingApprovalError
+		if errors.As(err, &approvalErr) {
+			e.noticeAwaitingApproval(ctx, approvalErr.MissionID)
+			return nil
+		}
+		e.noteStorageResult(ctx, err)
+		return fmt.Errorf("failed to claim issue %s: %w", issue.ID, err)
+	}
+
+	// Work whose prerequisites were dropped goes to a human instead
+	if voided := e.voidedPrerequisites(ctx, issue.ID); len(voided) > 0 {
+		e.holdForTriage(ctx, issue, voided)
 		return nil
 	}
 
@@ -0,0 +1,155 @@
+package executor
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/ai"
+	"github.com/steveyegge/vc/internal/storage/storagetest"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// TestProcessNextIssueRespectsAssignees verifies the executor leaves work
+// assigned to a human alone unless it is labeled agent-ok, including
+// discovered blockers, and claims everything with RespectAssignees off
+func TestProcessNextIssueRespectsAssignees(t *testing.T) {
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+	create := func(title, assignee string, labels ...string) *types.Issue {
+		t.Helper()
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask, Assignee: assignee}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("Failed to create issue: %v", err)
+		}
+		for _, label := range labels {
+			if err := store.AddLabel(ctx, issue.ID, label, "test"); err != nil {
+				t.Fatalf("Failed to add label: %v", err)
+			}
+		}
+		return issue
+	}
+	humanBlocker := create("Human blocker", "alice", "discovered:blocker")
+	create("Human task", "alice")
+
+	execCfg := DefaultConfig()
+	execCfg.Store = store
+	execCfg.EnableAISupervision = false
+	exec, err := New(execCfg)
+	if err != nil {
+		t.Fatalf("Failed to create executor: %v", err)
+	}
+
+	if blocker, err := exec.getNextReadyBlocker(ctx); err != nil || blocker != nil {
+		t.Errorf("getNextReadyBlocker: got (%v, %v), want no blocker assigned to a human", blocker, err)
+	}
+	if err := exec.processNextIssue(ctx); err != nil {
+		t.Fatalf("processNextIssue: %v", err)
+	}
+	if n := store.CallCount("ClaimIssue"); n != 0 {
+		t.Errorf("Expected no claims of work assigned to a human, got %d", n)
+	}
+
+	// A claim refused for approval is skipped, which stops processNextIssue
+	// right after the claim
+	store.FailOn("ClaimIssue", &types.AwaitingApprovalError{IssueID: humanBlocker.ID})
+	exec.respectAssignees = false
+	if err := exec.processNextIssue(ctx); err != nil {
+		t.Fatalf("processNextIssue: %v", err)
+	}
+	if n := store.CallCount("ClaimIssue"); n != 1 {
+		t.Errorf("Expected a claim with RespectAssignees off, got %d", n)
+	}
+
+	exec.respectAssignees = true
+	if err := store.AddLabel(ctx, humanBlocker.ID, types.AgentOKLabel, "test"); err != nil {
+		t.Fatalf("Failed to add label: %v", err)
+	}
+	blocker, err := exec.getNextReadyBlocker(ctx)
+	if err != nil || blocker == nil || blocker.ID != humanBlocker.ID {
+		t.Errorf("getNextReadyBlocker: got (%v, %v), want the agent-ok blocker %s", blocker, err, humanBlocker.ID)
+	}
+}
+
+// TestProcessNextIssueHoldsVoidedPremises verifies the executor blocks an
+// issue for triage instead of executing it when a prerequisite was closed as
+// wontfix, while one closed as a duplicate doesn't count
+func TestProcessNextIssueHoldsVoidedPremises(t *testing.T) {
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+	create := func(title string) *types.Issue {
+		t.Helper()
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("Failed to create issue: %v", err)
+		}
+		return issue
+	}
+	dependOn := func(issue, prerequisite *types.Issue, resolution types.Resolution) {
+		t.Helper()
+		dep := &types.Dependency{IssueID: issue.ID, DependsOnID: prerequisite.ID, Type: types.DepBlocks}
+		if err := store.AddDependency(ctx, dep, "test"); err != nil {
+			t.Fatalf("Failed to add dependency: %v", err)
+		}
+		if err := store.CloseIssueWithResolution(ctx, prerequisite.ID, "Dropped", resolution, "test"); err != nil {
+			t.Fatalf("Failed to close issue: %v", err)
+		}
+	}
+	dropped := create("Adopt the new API")
+	follower := create("Migrate callers to the new API")
+	dependOn(follower, dropped, types.ResolutionWontfix)
+
+	execCfg := DefaultConfig()
+	execCfg.Store = store
+	execCfg.EnableAISupervision = false
+	exec, err := New(execCfg)
+	if err != nil {
+		t.Fatalf("Failed to create executor: %v", err)
+	}
+
+	if err := exec.processNextIssue(ctx); err != nil {
+		t.Fatalf("processNextIssue: %v", err)
+	}
+	if n := store.CallCount("ClaimIssue"); n != 1 {
+		t.Fatalf("Expected the issue to be claimed once, got %d", n)
+	}
+	got, err := store.GetIssue(ctx, follower.ID)
+	if err != nil {
+		t.Fatalf("GetIssue: %v", err)
+	}
+	if got.Status != types.StatusBlocked {
+		t.Errorf("Expected status blocked, got %s", got.Status)
+	}
+	if state, err := store.GetExecutionState(ctx, follower.ID); err != nil || state != nil {
+		t.Errorf("Expected the claim to be released, got (%+v, %v)", state, err)
+	}
+	labels, err := store.GetLabels(ctx, follower.ID)
+	if err != nil {
+		t.Fatalf("GetLabels: %v", err)
+	}
+	if !slices.Contains(labels, ai.LabelNeedsTriage) {
+		t.Errorf("Expected label %s, got %v", ai.LabelNeedsTriage, labels)
+	}
+	events, err := store.GetEvents(ctx, follower.ID, 0)
+	if err != nil {
+		t.Fatalf("GetEvents: %v", err)
+	}
+	commented := false
+	for _, event := range events {
+		if event.EventType == types.EventCommented && event.Comment != nil &&
+			strings.Contains(*event.Comment, dropped.ID+" ("+dropped.Title+") was closed as wontfix") {
+			commented = true
+		}
+	}
+	if !commented {
+		t.Errorf("Expected a comment naming %s as closed wontfix", dropped.ID)
+	}
+
+	// A duplicate was done elsewhere, so the premise still holds
+	original := create("Original")
+	dependOn(original, create("Copy"), types.ResolutionDuplicate)
+	if voided := exec.voidedPrerequisites(ctx, original.ID); len(voided) != 0 {
+		t.Errorf("Expected no voided prerequisites for a duplicate, got %d", len(voided))
+	}
+}
@@ -2,12 +2,17 @@ package executor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/steveyegge/vc/internal/ai"
 	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/sandbox"
+	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/types"
 )
 
 // logEvent creates and stores an agent event for observability
@@ -29,15 +34,52 @@ func (e *Executor) logEvent(ctx context.Context, eventType events.EventType, sev
 		Data:       data,
 		SourceLine: 0, // Not applicable for executor-level events
 	}
+	if e.supervisor != nil && events.IsAIEvent(eventType) {
+		event.SetAIModel(e.supervisor.Provider(), e.supervisor.Model())
+	}
 
-	if err := e.store.StoreAgentEvent(ctx, event); err != nil {
-		// Log error but don't fail execution
-		fmt.Fprintf(os.Stderr, "warning: failed to store agent event: %v\n", err)
+	// Repeats of a warning are held back (see event_coalescer.go)
+	e.storeEvents(ctx, e.coalescer.add(event))
+}
+
+// storeEvents stores events in order; failures are logged, not returned
+func (e *Executor) storeEvents(ctx context.Context, eventList []*events.AgentEvent) {
+	for _, event := range eventList {
+		if err := e.storageCall(ctx, "StoreAgentEvent", event.IssueID, func(ctx context.Context) error {
+			return e.store.StoreAgentEvent(ctx, event)
+		}); err != nil {
+			// Log error but don't fail execution
+			fmt.Fprintf(os.Stderr, "warning: failed to store agent event: %v\n", err)
+		}
+	}
+}
+
+// flushCoalescedEvents stores the summaries of held-back warnings whose
+// window has closed, or of all of them on shutdown
+func (e *Executor) flushCoalescedEvents(ctx context.Context, all bool) {
+	e.storeEvents(ctx, e.coalescer.flush(all))
+}
+
+// logAIUnavailable records a failed AI healthcheck as a SYSTEM event, so
+// the audit trail shows why the executor ran without AI supervision
+func (e *Executor) logAIUnavailable(supervisor *ai.Supervisor, err error) {
+	kind := ai.HealthcheckOther
+	var healthErr *ai.HealthcheckError
+	if errors.As(err, &healthErr) {
+		kind = healthErr.Kind
 	}
+	e.logEvent(context.Background(), events.EventTypeAIUnavailable, events.SeverityError, types.SystemIssueID,
+		fmt.Sprintf("AI supervision unavailable (%s): %v", kind, err),
+		map[string]interface{}{
+			events.DataKeyAIProvider: supervisor.Provider(),
+			events.DataKeyAIModel:    supervisor.Model(),
+			"kind":                   string(kind),
+			"error":                  err.Error(),
+		})
 }
 
 // logCleanupEvent creates and stores a structured event for cleanup metrics (vc-196)
-func (e *Executor) logCleanupEvent(ctx context.Context, totalDeleted, timeBasedDeleted, perIssueDeleted, globalLimitDeleted int, processingTimeMs int64, vacuumRan bool, eventsRemaining int, success bool, errorMsg string) {
+func (e *Executor) logCleanupEvent(ctx context.Context, totalDeleted, timeBasedDeleted, perIssueDeleted, globalLimitDeleted int, processingTimeMs int64, vacuumRan bool, eventsRemaining, historyDeleted, artifactsDeleted int, success bool, errorMsg string) {
 	// Skip logging if context is canceled (e.g., during shutdown)
 	if ctx.Err() != nil {
 		return
@@ -51,6 +93,8 @@ func (e *Executor) logCleanupEvent(ctx context.Context, totalDeleted, timeBasedD
 		"processing_time_ms":   processingTimeMs,
 		"vacuum_ran":           vacuumRan,
 		"events_remaining":     eventsRemaining,
+		"history_deleted":      historyDeleted,
+		"artifacts_deleted":    artifactsDeleted,
 		"success":              success,
 	}
 
@@ -67,7 +111,7 @@ func (e *Executor) logCleanupEvent(ctx context.Context, totalDeleted, timeBasedD
 		ID:         uuid.New().String(),
 		Type:       events.EventTypeEventCleanupCompleted,
 		Timestamp:  time.Now(),
-		IssueID:    "SYSTEM", // System-level event (requires SYSTEM pseudo-issue to exist)
+		IssueID:    types.SystemIssueID, // System-level event
 		ExecutorID: e.instanceID,
 		AgentID:    "", // Not produced by a coding agent
 		Severity:   events.SeverityInfo,
@@ -116,7 +160,7 @@ func (e *Executor) logInstanceCleanupEvent(ctx context.Context, instancesDeleted
 		ID:         uuid.New().String(),
 		Type:       events.EventTypeInstanceCleanupCompleted,
 		Timestamp:  time.Now(),
-		IssueID:    "SYSTEM", // System-level event (requires SYSTEM pseudo-issue to exist)
+		IssueID:    types.SystemIssueID, // System-level event
 		ExecutorID: e.instanceID,
 		AgentID:    "", // Not produced by a coding agent
 		Severity:   events.SeverityInfo,
@@ -134,3 +178,94 @@ func (e *Executor) logInstanceCleanupEvent(ctx context.Context, instancesDeleted
 		fmt.Fprintf(os.Stderr, "warning: failed to store instance cleanup event: %v\n", err)
 	}
 }
+
+// sandboxDiskBytes measures the on-disk size of a sandbox for lifecycle metrics.
+// Failures (including a walk over its time budget) are logged and reported as
+// 0 so they never fail the sandbox operation.
+func sandboxDiskBytes(mgr sandbox.Manager, path string) int64 {
+	if mgr == nil || path == "" {
+		return 0
+	}
+	size, err := mgr.DiskUsage(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to measure sandbox disk usage: %v\n", err)
+		return 0
+	}
+	return size
+}
+
+// logSandboxLifecycleEvent stores a sandbox lifecycle metric event (sandbox_created,
+// sandbox_cleaned, sandbox_cleanup_failed) tied to the issue being executed.
+// sb may be nil when creation failed; opErr is the error from the sandbox operation, if any.
+func logSandboxLifecycleEvent(ctx context.Context, store storage.EventStore, executorID string, eventType events.EventType, issueID string, sb *sandbox.Sandbox, duration time.Duration, diskBytes int64, opErr error) {
+	// Skip logging if context is canceled (e.g., during shutdown)
+	if ctx.Err() != nil {
+		return
+	}
+
+	data := events.SandboxLifecycleData{
+		DurationMs: duration.Milliseconds(),
+		DiskBytes:  diskBytes,
+		Success:    opErr == nil,
+	}
+	if sb != nil {
+		data.SandboxID = sb.ID
+		data.Path = sb.Path
+		data.Branch = sb.GitBranch
+	}
+	if opErr != nil {
+		data.Error = opErr.Error()
+	}
+
+	severity := events.SeverityInfo
+	var message string
+	switch {
+	case opErr != nil && eventType == events.EventTypeSandboxCreated:
+		severity = events.SeverityError
+		message = fmt.Sprintf("Sandbox creation failed for %s after %dms: %v", issueID, data.DurationMs, opErr)
+	case opErr != nil:
+		severity = events.SeverityError
+		message = fmt.Sprintf("Sandbox cleanup failed for %s after %dms: %v", issueID, data.DurationMs, opErr)
+	case eventType == events.EventTypeSandboxCreated:
+		message = fmt.Sprintf("Sandbox created for %s in %dms", issueID, data.DurationMs)
+	default:
+		message = fmt.Sprintf("Sandbox cleaned for %s in %dms (%d bytes reclaimed)", issueID, data.DurationMs, diskBytes)
+	}
+
+	event, err := events.NewSandboxLifecycleEvent(eventType, issueID, executorID, "", severity, message, data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to create %s event: %v\n", eventType, err)
+		return
+	}
+
+	if err := store.StoreAgentEvent(ctx, event); err != nil {
+		// Log error but don't fail the sandbox operation
+		fmt.Fprintf(os.Stderr, "warning: failed to store %s event: %v\n", eventType, err)
+	}
+}
+
+// recordSandboxCreated emits a sandbox_created metric event for a creation
+// attempt. Disk usage is only measured at cleanup, when the sandbox is at its
+// largest, so a fresh worktree isn't walked twice.
+func (e *Executor) recordSandboxCreated(ctx context.Context, issueID string, sb *sandbox.Sandbox, duration time.Duration, createErr error) {
+	logSandboxLifecycleEvent(ctx, e.store, e.instanceID, events.EventTypeSandboxCreated, issueID, sb, duration, 0, createErr)
+}
+
+// cleanupSandboxWithMetrics cleans up a sandbox and emits a sandbox_cleaned or
+// sandbox_cleanup_failed metric event. Disk usage is measured before cleanup
+// so the event reports how much space was reclaimed.
+func (e *Executor) cleanupSandboxWithMetrics(ctx context.Context, issueID string, sb *sandbox.Sandbox) error {
+	diskBytes := sandboxDiskBytes(e.sandboxMgr, sb.Path)
+
+	startTime := time.Now()
+	err := e.sandboxMgr.Cleanup(ctx, sb)
+	duration := time.Since(startTime)
+
+	eventType := events.EventTypeSandboxCleaned
+	if err != nil {
+		eventType = events.EventTypeSandboxCleanupFailed
+	}
+	logSandboxLifecycleEvent(ctx, e.store, e.instanceID, eventType, issueID, sb, duration, diskBytes, err)
+
+	return err
+}
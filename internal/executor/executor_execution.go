@@ -3,23 +3,51 @@ package executor
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/steveyegge/vc/internal/ai"
+	"github.com/steveyegge/vc/internal/artifacts"
 	"github.com/steveyegge/vc/internal/events"
 	"github.com/steveyegge/vc/internal/sandbox"
 	"github.com/steveyegge/vc/internal/types"
 )
 
 // executeIssue executes a single issue by spawning a coding agent
-func (e *Executor) executeIssue(ctx context.Context, issue *types.Issue) error {
+func (e *Executor) executeIssue(ctx context.Context, issue *types.Issue) (err error) {
 	fmt.Printf("Executing issue %s: %s\n", issue.ID, issue.Title)
 
+	// Record this attempt in the execution history. The deferred finish
+	// closes the row on every exit path, early returns and panics included;
+	// it uses a background context since ctx may be canceled by then.
+	attempt := e.startAttempt(ctx, issue.ID)
+	var agentResult *AgentResult
+	var procResult *ProcessingResult
+	defer func() {
+		if p := recover(); p != nil {
+			attempt.finish(context.Background(), false, fmt.Sprintf("panic: %v", p), agentResult)
+			panic(p)
+		}
+		success := err == nil && agentResult != nil && agentResult.Success && procResult != nil && procResult.GatesPassed
+		attempt.finish(context.Background(), success, attemptSummary(err, agentResult, procResult), agentResult)
+		// The task is settled (closed, reopened or blocked) on every path
+		e.updateMissionPhases(context.Background(), issue.ID)
+	}()
+
+	// Pick up where a previous attempt left off, if it left a usable checkpoint
+	checkpoint := e.loadCheckpoint(ctx, issue.ID)
+	if checkpoint != nil {
+		fmt.Printf("Resuming issue %s from checkpoint (phase %s, saved %s)\n",
+			issue.ID, checkpoint.Phase, checkpoint.UpdatedAt.Format(time.RFC3339))
+	}
+
 	// Start telemetry collection for this execution
 	e.monitor.StartExecution(issue.ID, e.instanceID)
+	e.monitor.SetSilenceThreshold(e.watchdogConfig.SilenceThresholdFor(issue.EstimatedMinutes))
 
 	// Log issue claimed event
 	e.logEvent(ctx, events.EventTypeIssueClaimed, events.SeverityInfo, issue.ID,
@@ -29,16 +57,19 @@ func (e *Executor) executeIssue(ctx context.Context, issue *types.Issue) error {
 		})
 	e.monitor.RecordEvent(string(events.EventTypeIssueClaimed))
 
+	// Honor watchdog:off / watchdog:relaxed labels for this execution
+	e.applyWatchdogProtectionMode(ctx, issue.ID)
+
 	// Phase 1: AI Assessment (if enabled)
 	// Always transition to assessing state for state machine consistency (vc-110)
-	if err := e.store.UpdateExecutionState(ctx, issue.ID, types.ExecutionStateAssessing); err != nil {
+	attempt.phases.Start(types.ExecutionPhaseAssessment)
+	if err := e.storageCall(ctx, "UpdateExecutionState", issue.ID, func(ctx context.Context) error {
+		return e.store.UpdateExecutionState(ctx, issue.ID, types.ExecutionStateAssessing)
+	}); err != nil {
 		// Check if context was canceled (shutdown initiated)
 		if ctx.Err() != nil {
-			// Use background context for cleanup since main context is canceled
-			cleanupCtx := context.Background()
-			e.releaseIssueWithError(cleanupCtx, issue.ID, fmt.Sprintf("Execution canceled during state transition: %v", ctx.Err()))
 			e.monitor.EndExecution(false, false)
-			return ctx.Err()
+			return e.releaseInterruptedIssue(issue.ID, "Execution canceled during state transition", ctx.Err())
 		}
 		fmt.Fprintf(os.Stderr, "warning: failed to update execution state: %v\n", err)
 	}
@@ -51,17 +82,17 @@ func (e *Executor) executeIssue(ctx context.Context, issue *types.Issue) error {
 			fmt.Sprintf("Starting AI assessment for issue %s", issue.ID),
 			map[string]interface{}{})
 
-		var err error
-		assessment, err = e.supervisor.AssessIssueState(ctx, issue)
+		estimateHint, err := NewContextGatherer(e.store).GetEstimateHint(ctx, issue)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to get estimate history: %v\n", err)
+		}
+		assessment, err = e.supervisor.AssessIssueState(ctx, issue, estimateHint)
 		if err != nil {
 			// Check if context was canceled (shutdown initiated)
 			if ctx.Err() != nil {
 				fmt.Fprintf(os.Stderr, "Assessment canceled due to executor shutdown\n")
-				// Use background context for cleanup since main context is canceled
-				cleanupCtx := context.Background()
-				e.releaseIssueWithError(cleanupCtx, issue.ID, "Execution canceled during assessment")
 				e.monitor.EndExecution(false, false)
-				return ctx.Err()
+				return e.releaseInterruptedIssue(issue.ID, "Execution canceled during assessment", ctx.Err())
 			}
 			// Real error (not cancellation) - log and continue without assessment
 			fmt.Fprintf(os.Stderr, "Warning: AI assessment failed: %v (continuing without assessment)\n", err)
@@ -73,21 +104,12 @@ func (e *Executor) executeIssue(ctx context.Context, issue *types.Issue) error {
 					"error":   err.Error(),
 				})
 		} else {
-			// Log the assessment as a comment
-			assessmentComment := fmt.Sprintf("**AI Assessment**\n\nStrategy: %s\n\nConfidence: %.0f%%\n\nSteps:\n",
-				assessment.Strategy, assessment.Confidence*100)
-			for i, step := range assessment.Steps {
-				assessmentComment += fmt.Sprintf("%d. %s\n", i+1, step)
-			}
-			if len(assessment.Risks) > 0 {
-				assessmentComment += "\nRisks:\n"
-				for _, risk := range assessment.Risks {
-					assessmentComment += fmt.Sprintf("- %s\n", risk)
-				}
-			}
-			if err := e.store.AddComment(ctx, issue.ID, "ai-supervisor", assessmentComment); err != nil {
+			// Log the assessment as a comment, and keep it structured for
+			// the prompt and for resumed attempts
+			if err := e.store.AddComment(ctx, issue.ID, "ai-supervisor", e.commentLimit.Cap(issue.ID, formatAssessmentComment(assessment))); err != nil {
 				fmt.Fprintf(os.Stderr, "warning: failed to add assessment comment: %v\n", err)
 			}
+			saveAssessment(ctx, e.store, issue.ID, attempt.number(), assessment)
 
 			// Log assessment success
 			e.logEvent(ctx, events.EventTypeAssessmentCompleted, events.SeverityInfo, issue.ID,
@@ -106,7 +128,9 @@ func (e *Executor) executeIssue(ctx context.Context, issue *types.Issue) error {
 	}
 
 	// Phase 2: Get or create mission sandbox if enabled
+	attempt.phases.Start(types.ExecutionPhaseSandbox)
 	var sb *sandbox.Sandbox
+	var missionSandbox *sandbox.Sandbox // sb, if it outlives this execution
 	workingDir := e.workingDir
 	if e.enableSandboxes && e.sandboxMgr != nil {
 		// Look up the mission for this task (vc-244)
@@ -125,7 +149,9 @@ func (e *Executor) executeIssue(ctx context.Context, issue *types.Issue) error {
 			} else if sb == nil {
 				// No sandbox exists yet - create it (auto-create on first task)
 				fmt.Printf("Creating mission sandbox for %s...\n", missionCtx.MissionID)
+				createStart := time.Now()
 				sb, err = sandbox.CreateMissionSandbox(ctx, e.sandboxMgr, e.store, missionCtx.MissionID)
+				e.recordSandboxCreated(ctx, issue.ID, sb, time.Since(createStart), err)
 				if err != nil {
 					fmt.Fprintf(os.Stderr, "Warning: failed to create mission sandbox: %v (continuing in main workspace)\n", err)
 					sb = nil // Clear to continue without sandbox
@@ -139,6 +165,7 @@ func (e *Executor) executeIssue(ctx context.Context, issue *types.Issue) error {
 			// If we have a sandbox, set working directory
 			if sb != nil {
 				workingDir = sb.Path
+				missionSandbox = sb
 				// NOTE: Do NOT cleanup mission sandbox here - it's shared across all tasks in the mission
 				// Cleanup happens when the mission is closed (vc-245)
 			}
@@ -164,7 +191,9 @@ func (e *Executor) executeIssue(ctx context.Context, issue *types.Issue) error {
 				BaseBranch: baseBranch,
 			}
 
+			createStart := time.Now()
 			sb, err = e.sandboxMgr.Create(ctx, sandboxCfg)
+			e.recordSandboxCreated(ctx, issue.ID, sb, time.Since(createStart), err)
 			if err != nil {
 				// Don't fail execution - just log and continue without sandbox
 				fmt.Fprintf(os.Stderr, "Warning: failed to create per-execution sandbox: %v (continuing in main workspace)\n", err)
@@ -177,15 +206,30 @@ func (e *Executor) executeIssue(ctx context.Context, issue *types.Issue) error {
 				defer func() {
 					if sb != nil {
 						fmt.Printf("Cleaning up per-execution sandbox %s...\n", sb.ID)
-						if err := e.sandboxMgr.Cleanup(ctx, sb); err != nil {
+						if err := e.cleanupSandboxWithMetrics(ctx, issue.ID, sb); err != nil {
 							fmt.Fprintf(os.Stderr, "warning: failed to cleanup sandbox: %v\n", err)
 						}
+						attempt.recordMergeCommit(sb.MergeCommit)
 					}
 				}()
 			}
 		}
 	}
 
+	attempt.phases.Stop()
+
+	// Keep the attempt's diff, gate logs and agent summary. Deferred calls
+	// run in reverse order, so this runs before the per-execution sandbox
+	// above is cleaned up and before the attempt row is finalized. The
+	// success path saves them earlier, for the results event.
+	defer func() {
+		e.saveArtifacts(context.Background(), attempt, sb, procResult)
+	}()
+
+	// Missions and phases keep count of their executions and the sandbox
+	// they run in (per-execution sandboxes are gone once it ends)
+	e.recordMissionIteration(ctx, issue, missionSandbox)
+
 	// Phase 2.5: Diagnose baseline test failures (vc-230)
 	// If this is a baseline test issue, use AI to diagnose the failure
 	// vc-261: Use IsBaselineIssue() helper instead of duplicated map
@@ -232,7 +276,7 @@ func (e *Executor) executeIssue(ctx context.Context, issue *types.Issue) error {
 				diagnosisComment += fmt.Sprintf("%d. %s\n", i+1, step)
 			}
 
-			if err := e.store.AddComment(ctx, issue.ID, "ai-supervisor", diagnosisComment); err != nil {
+			if err := e.store.AddComment(ctx, issue.ID, "ai-supervisor", e.commentLimit.Cap(issue.ID, diagnosisComment)); err != nil {
 				fmt.Fprintf(os.Stderr, "warning: failed to add diagnosis comment: %v\n", err)
 			}
 
@@ -258,22 +302,19 @@ func (e *Executor) executeIssue(ctx context.Context, issue *types.Issue) error {
 	// Check if context was canceled before starting execution (vc-101)
 	if ctx.Err() != nil {
 		fmt.Fprintf(os.Stderr, "Execution canceled before spawning agent\n")
-		// Use background context for cleanup since main context is canceled
-		cleanupCtx := context.Background()
-		e.releaseIssueWithError(cleanupCtx, issue.ID, "Execution canceled before spawning agent")
 		e.monitor.EndExecution(false, false)
-		return ctx.Err()
+		return e.releaseInterruptedIssue(issue.ID, "Execution canceled before spawning agent", ctx.Err())
 	}
 
 	// Update execution state to executing
-	if err := e.store.UpdateExecutionState(ctx, issue.ID, types.ExecutionStateExecuting); err != nil {
+	attempt.phases.Start(types.ExecutionPhaseAgent)
+	if err := e.storageCall(ctx, "UpdateExecutionState", issue.ID, func(ctx context.Context) error {
+		return e.store.UpdateExecutionState(ctx, issue.ID, types.ExecutionStateExecuting)
+	}); err != nil {
 		// Check if context was canceled (shutdown initiated)
 		if ctx.Err() != nil {
-			// Use background context for cleanup since main context is canceled
-			cleanupCtx := context.Background()
-			e.releaseIssueWithError(cleanupCtx, issue.ID, fmt.Sprintf("Execution canceled during state transition: %v", ctx.Err()))
 			e.monitor.EndExecution(false, false)
-			return ctx.Err()
+			return e.releaseInterruptedIssue(issue.ID, "Execution canceled during state transition", ctx.Err())
 		}
 		fmt.Fprintf(os.Stderr, "warning: failed to update execution state: %v\n", err)
 	}
@@ -322,7 +363,11 @@ func (e *Executor) executeIssue(ctx context.Context, issue *types.Issue) error {
 		return fmt.Errorf("failed to create prompt builder: %w", err)
 	}
 
-	prompt, err := builder.BuildPrompt(promptCtx)
+	agentType := AgentTypeAmp // Use Amp for structured JSON events (vc-236)
+	if e.config != nil {
+		builder.TokenBudget = PromptBudgetFor(e.config.PromptTokenBudgets, agentType)
+	}
+	prompt, promptStats, err := builder.BuildPromptWithStats(promptCtx)
 	if err != nil {
 		e.logEvent(ctx, events.EventTypeAgentSpawned, events.SeverityError, issue.ID,
 			fmt.Sprintf("Failed to build prompt: %v", err),
@@ -335,6 +380,11 @@ func (e *Executor) executeIssue(ctx context.Context, issue *types.Issue) error {
 		return fmt.Errorf("failed to build prompt: %w", err)
 	}
 
+	if promptStats.OverBudget() {
+		fmt.Fprintf(os.Stderr, "warning: prompt for %s is ~%d tokens, over its %d-token budget even after truncation\n",
+			issue.ID, promptStats.Tokens, promptStats.Budget)
+	}
+
 	// Log prompt for debugging if VC_DEBUG_PROMPTS is set
 	if os.Getenv("VC_DEBUG_PROMPTS") != "" {
 		fmt.Fprintf(os.Stderr, "\n=== AGENT PROMPT ===\n%s\n=== END PROMPT ===\n\n", prompt)
@@ -344,11 +394,11 @@ func (e *Executor) executeIssue(ctx context.Context, issue *types.Issue) error {
 	agentID := uuid.New().String()
 
 	agentCfg := AgentConfig{
-		Type:       AgentTypeAmp, // Use Amp for structured JSON events (vc-236)
+		Type:       agentType,
 		WorkingDir: workingDir,
 		Issue:      issue,
 		StreamJSON: true, // Enable --stream-json for structured events (vc-236)
-		Timeout:    30 * time.Minute,
+		Timeout:    agentTimeout,
 		// Enable event parsing and storage
 		Store:      e.store,
 		ExecutorID: e.instanceID,
@@ -356,6 +406,9 @@ func (e *Executor) executeIssue(ctx context.Context, issue *types.Issue) error {
 		Monitor:    e.monitor, // Pass monitor for watchdog visibility (vc-118)
 		Sandbox:    sb,
 	}
+	if e.artifactsDir != "" && attempt.number() > 0 {
+		agentCfg.Transcript = artifacts.TranscriptPath(e.artifactsDir, issue.ID, attempt.number())
+	}
 
 	agent, err := SpawnAgent(agentCtx, agentCfg, prompt)
 	if err != nil {
@@ -373,24 +426,46 @@ func (e *Executor) executeIssue(ctx context.Context, issue *types.Issue) error {
 		return fmt.Errorf("failed to spawn agent: %w", err)
 	}
 
+	e.saveCheckpoint(ctx, issue.ID, checkpoint, types.ExecutionStateExecuting, sb, agentID)
+
 	// Log agent spawned successfully
 	e.logEvent(ctx, events.EventTypeAgentSpawned, events.SeverityInfo, issue.ID,
 		fmt.Sprintf("Agent spawned for issue %s", issue.ID),
 		map[string]interface{}{
-			"success":    true,
-			"agent_type": agentCfg.Type,
+			"success":          true,
+			"agent_type":       agentCfg.Type,
+			"prompt_chars":     promptStats.Chars,
+			"prompt_tokens":    promptStats.Tokens,
+			"prompt_budget":    promptStats.Budget,
+			"prompt_truncated": promptStats.Truncated,
 		})
 
-	// Wait for agent to complete
+	// Wait for agent to complete; a graceful shutdown waits for it too
+	e.setRunningAgent(issue.ID, time.Now().Add(agentCfg.Timeout))
 	result, err := agent.Wait(agentCtx)
+	e.setRunningAgent("", time.Time{})
+	agentResult = result
+	attempt.transcript = agent.TranscriptPath()
+	if err != nil && ctx.Err() != nil {
+		// Stopped by shutdown: not the agent's fault, nothing to analyze
+		e.monitor.EndExecution(false, false)
+		return e.releaseInterruptedIssue(issue.ID, "Agent stopped by executor shutdown", err)
+	}
 	if err != nil {
 		// Log agent execution failure BEFORE releasing issue
 		e.logEvent(ctx, events.EventTypeAgentCompleted, events.SeverityError, issue.ID,
 			fmt.Sprintf("Agent execution failed: %v", err),
 			map[string]interface{}{
-				"success": false,
-				"error":   err.Error(),
+				"success":    false,
+				"error":      err.Error(),
+				"transcript": attempt.transcript,
 			})
+		summary := err.Error()
+		if result != nil && len(result.Output) > 0 {
+			summary += "\n\nLast output:\n" + strings.Join(result.Output, "\n")
+		}
+		newFailureAnalyzer(e.store, e.supervisor, e.config.EnableFailureAnalysis, e.config.FailureAnalysisCostCap, e.commentLimit).
+			analyze(ctx, issue, ai.FailureReport{Reason: failureReasonAgentError, Summary: summary}, err)
 		e.releaseIssueWithError(ctx, issue.ID, fmt.Sprintf("Agent execution failed: %v", err))
 		// End telemetry collection on failure
 		e.monitor.EndExecution(false, false)
@@ -405,6 +480,7 @@ func (e *Executor) executeIssue(ctx context.Context, issue *types.Issue) error {
 			"exit_code":    result.ExitCode,
 			"duration_ms":  result.Duration.Milliseconds(),
 			"output_lines": len(result.Output),
+			"transcript":   attempt.transcript,
 		})
 
 	// Phase 3: Process results using ResultsProcessor
@@ -429,6 +505,13 @@ func (e *Executor) executeIssue(ctx context.Context, issue *types.Issue) error {
 		Actor:              e.instanceID,
 		Sandbox:            sb,            // Pass sandbox for status tracking (vc-134)
 		SandboxManager:     e.sandboxMgr,  // Pass manager for auto-cleanup (vc-245)
+
+		EnableFailureAnalysis:  e.config.EnableFailureAnalysis,
+		FailureAnalysisCostCap: e.config.FailureAnalysisCostCap,
+		ForceDiscoveredTriage:  e.config.ForceDiscoveredTriage,
+		AutoMergeThreshold:     e.config.AutoMergeThreshold,
+		Phases:                 attempt.phases,
+		CommentLimit:           e.commentLimit,
 	})
 	if err != nil {
 		// Log results processing failure BEFORE releasing issue
@@ -444,14 +527,16 @@ func (e *Executor) executeIssue(ctx context.Context, issue *types.Issue) error {
 		return fmt.Errorf("failed to create results processor: %w", err)
 	}
 
-	procResult, err := processor.ProcessAgentResult(ctx, issue, result)
+	procResult, err = processor.ProcessAgentResult(ctx, issue, result)
+	attempt.phases.Stop()
 	if err != nil {
 		// Log results processing failure BEFORE releasing issue
 		e.logEvent(ctx, events.EventTypeResultsProcessingCompleted, events.SeverityError, issue.ID,
 			fmt.Sprintf("Results processing failed: %v", err),
 			map[string]interface{}{
-				"success": false,
-				"error":   err.Error(),
+				"success":   false,
+				"error":     err.Error(),
+				"phases_ms": phaseDurationsMs(attempt.phases.Phases()),
 			})
 		e.releaseIssueWithError(ctx, issue.ID, fmt.Sprintf("Failed to process results: %v", err))
 		// End telemetry collection on failure
@@ -459,7 +544,24 @@ func (e *Executor) executeIssue(ctx context.Context, issue *types.Issue) error {
 		return fmt.Errorf("failed to process agent result: %w", err)
 	}
 
+	// Record the files and the commit the attempt made, and the commit on the
+	// issue if it closed it. This runs before the per-execution sandbox is
+	// cleaned up, which merges its branch and records the merge commit on
+	// the issues closed on it.
+	attempt.recordFiles(e.changedFiles(ctx, sb, workingDir, procResult.CommitHash))
+	if procResult.CommitHash != "" {
+		branch := currentBranch(ctx, workingDir)
+		if sb != nil {
+			branch = sb.GitBranch
+		}
+		attempt.recordCommit(procResult.CommitHash, branch)
+		if procResult.Completed {
+			e.recordClosingCommit(ctx, issue.ID, procResult.CommitHash, branch)
+		}
+	}
+
 	// Log results processing success
+	artifactPaths := e.saveArtifacts(ctx, attempt, sb, procResult)
 	e.logEvent(ctx, events.EventTypeResultsProcessingCompleted, events.SeverityInfo, issue.ID,
 		fmt.Sprintf("Results processing completed for issue %s", issue.ID),
 		map[string]interface{}{
@@ -468,6 +570,8 @@ func (e *Executor) executeIssue(ctx context.Context, issue *types.Issue) error {
 			"gates_passed":      procResult.GatesPassed,
 			"discovered_issues": len(procResult.DiscoveredIssues),
 			"commit_hash":       procResult.CommitHash,
+			"artifacts":         artifactPaths,
+			"phases_ms":         phaseDurationsMs(attempt.phases.Phases()),
 		})
 
 	// Print summary
@@ -495,6 +599,32 @@ func (e *Executor) executeIssue(ctx context.Context, issue *types.Issue) error {
 	return nil
 }
 
+// agentTimeout is how long a coding agent may run
+const agentTimeout = 30 * time.Minute
+
+// errInterrupted marks executions cut short by executor shutdown. They are
+// transient failures: the issue is reopened without counting toward the
+// consecutive failures that block it.
+var errInterrupted = errors.New("interrupted by executor shutdown")
+
+// releaseInterruptedIssue reopens an issue whose execution shutdown cut
+// short and returns the error executeIssue ends with, which also becomes
+// the attempt's summary. It uses a background context since ctx is already
+// canceled by then.
+func (e *Executor) releaseInterruptedIssue(issueID, reason string, cause error) error {
+	fmt.Printf("Releasing %s for retry: %s\n", issueID, reason)
+	if err := e.store.ReleaseIssueAndReopen(context.Background(), issueID, e.instanceID, reason); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to release and reopen issue %s: %v\n", issueID, err)
+	}
+	return fmt.Errorf("%w: %s: %w", errInterrupted, reason, cause)
+}
+
+// isInterruptedAttempt reports whether an attempt was cut short by shutdown
+// (see errInterrupted)
+func isInterruptedAttempt(attempt *types.ExecutionAttempt) bool {
+	return strings.HasPrefix(attempt.Summary, errInterrupted.Error())
+}
+
 // releaseIssueWithError releases an issue and adds an error comment
 // If there are too many consecutive failures, the issue is marked as blocked instead of reopened
 func (e *Executor) releaseIssueWithError(ctx context.Context, issueID, errMsg string) {
@@ -509,14 +639,26 @@ func (e *Executor) releaseIssueWithError(ctx context.Context, issueID, errMsg st
 
 	// Count recent consecutive failures
 	consecutiveFailures := 0
+	var failedAttempts []int64 // Newest first
 	for i := len(history) - 1; i >= 0; i-- {
 		attempt := history[i]
-		// Only count completed attempts
+		// Attempts cut short by shutdown are transient and don't count
+		if isInterruptedAttempt(attempt) {
+			continue
+		}
+		// Only count completed attempts, except our own latest one: it's
+		// still open because it is the execution failing right now
+		// (executeIssue finalizes it after the release)
 		if attempt.Success == nil {
+			if i == len(history)-1 && attempt.ExecutorInstanceID == e.instanceID {
+				consecutiveFailures++
+				failedAttempts = append(failedAttempts, attempt.ID)
+			}
 			continue // Skip incomplete attempts
 		}
 		if !*attempt.Success {
 			consecutiveFailures++
+			failedAttempts = append(failedAttempts, attempt.ID)
 		} else {
 			break // Stop counting at first success
 		}
@@ -545,6 +687,29 @@ func (e *Executor) releaseIssueWithError(ctx context.Context, issueID, errMsg st
 		if err := e.store.AddComment(ctx, issueID, "executor", blockReason); err != nil {
 			fmt.Fprintf(os.Stderr, "warning: failed to add comment to %s: %v\n", issueID, err)
 		}
+
+		// Tell it apart from an issue blocked by a dependency: it needs a
+		// human (vc unblock once fixed)
+		if err := e.store.AddLabel(ctx, issueID, types.AutoBlockedLabel, "executor"); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to label %s as %s: %v\n", issueID, types.AutoBlockedLabel, err)
+		}
+		e.logIssueAutoBlocked(ctx, issueID, events.IssueAutoBlockedData{
+			FailureCount: consecutiveFailures,
+			LastError:    errMsg,
+			AttemptIDs:   failedAttempts,
+		})
+		return
+	}
+
+	// Someone blocked the issue while it ran (the watchdog's cancel_and_block
+	// policy does): release it, but reopening would undo the block
+	if issue, err := e.store.GetIssue(ctx, issueID); err == nil && issue != nil && issue.Status == types.StatusBlocked {
+		if err := e.store.ReleaseIssue(ctx, issueID); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to release issue %s: %v\n", issueID, err)
+		}
+		if err := e.store.AddComment(ctx, issueID, "executor", errMsg); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to add comment to %s: %v\n", issueID, err)
+		}
 		return
 	}
 
@@ -560,3 +725,22 @@ func (e *Executor) releaseIssueWithError(ctx context.Context, issueID, errMsg st
 		fmt.Fprintf(os.Stderr, "warning: failed to release and reopen issue: %v\n", err)
 	}
 }
+
+// logIssueAutoBlocked stores the issue_auto_blocked event for an issue
+// releaseIssueWithError blocked
+func (e *Executor) logIssueAutoBlocked(ctx context.Context, issueID string, data events.IssueAutoBlockedData) {
+	// Skip logging if context is canceled (e.g., during shutdown)
+	if ctx.Err() != nil {
+		return
+	}
+
+	event, err := events.NewIssueAutoBlockedEvent(issueID, e.instanceID, "", events.SeverityError,
+		fmt.Sprintf("Issue %s blocked after %d consecutive execution failures", issueID, data.FailureCount), data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to create %s event: %v\n", events.EventTypeIssueAutoBlocked, err)
+		return
+	}
+	if err := e.store.StoreAgentEvent(ctx, event); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to store %s event: %v\n", events.EventTypeIssueAutoBlocked, err)
+	}
+}
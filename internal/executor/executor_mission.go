@@ -0,0 +1,52 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/steveyegge/vc/internal/sandbox"
+	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// hasMissionState reports whether the issue is a mission or phase, the
+// issues with a mission state row
+func hasMissionState(issue *types.Issue) bool {
+	return issue.IssueSubtype != types.SubtypeNormal
+}
+
+// recordMissionIteration counts an execution of a mission or phase in its
+// mission state, with the sandbox it runs in (nil if none). Failures are
+// logged but never stop the execution.
+func (e *Executor) recordMissionIteration(ctx context.Context, issue *types.Issue, sb *sandbox.Sandbox) {
+	if !hasMissionState(issue) {
+		return
+	}
+	mission, err := e.store.GetMission(ctx, issue.ID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to get mission state for %s: %v\n", issue.ID, err)
+		return
+	}
+	updates := map[string]interface{}{
+		"iteration_count": mission.IterationCount + 1,
+	}
+	if sb != nil {
+		updates["sandbox_path"] = sb.Path
+		updates["branch_name"] = sb.GitBranch
+	}
+	if err := e.store.UpdateMissionState(ctx, issue.ID, updates); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to update mission state for %s: %v\n", issue.ID, err)
+	}
+}
+
+// setMissionGatesStatus records the quality gates status of a mission or
+// phase; other issues are left alone. Failures are logged.
+func setMissionGatesStatus(ctx context.Context, store storage.IssueStore, issue *types.Issue, status string) {
+	if !hasMissionState(issue) {
+		return
+	}
+	if err := store.SetMissionGatesStatus(ctx, issue.ID, status); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to set gates status of %s to %s: %v\n", issue.ID, status, err)
+	}
+}
@@ -0,0 +1,54 @@
+package executor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/sandbox"
+	"github.com/steveyegge/vc/internal/storage/storagetest"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// TestRecordMissionIteration verifies each execution of a phase is counted
+// in its mission state along with the sandbox it runs in
+func TestRecordMissionIteration(t *testing.T) {
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+	execCfg := DefaultConfig()
+	execCfg.Store = store
+	execCfg.EnableAISupervision = false
+	exec, err := New(execCfg)
+	if err != nil {
+		t.Fatalf("Failed to create executor: %v", err)
+	}
+
+	phase := &types.Issue{Title: "Phase", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeEpic, IssueSubtype: types.SubtypePhase}
+	task := &types.Issue{Title: "Task", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	for _, issue := range []*types.Issue{phase, task} {
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("Failed to create issue: %v", err)
+		}
+	}
+
+	exec.recordMissionIteration(ctx, phase, nil)
+	exec.recordMissionIteration(ctx, phase, &sandbox.Sandbox{Path: "/tmp/sandbox", GitBranch: "mission/vc-1"})
+
+	state, err := store.GetMission(ctx, phase.ID)
+	if err != nil {
+		t.Fatalf("Failed to get mission state: %v", err)
+	}
+	if state.IterationCount != 2 {
+		t.Errorf("Expected 2 iterations, got %d", state.IterationCount)
+	}
+	if state.SandboxPath != "/tmp/sandbox" || state.BranchName != "mission/vc-1" {
+		t.Errorf("Expected the sandbox to be recorded, got %q on %q", state.SandboxPath, state.BranchName)
+	}
+
+	// Normal issues have no mission state to write
+	store.ResetCalls()
+	exec.recordMissionIteration(ctx, task, nil)
+	setMissionGatesStatus(ctx, store, task, types.GatesStatusPassed)
+	if n := store.CallCount("UpdateMissionState") + store.CallCount("SetMissionGatesStatus"); n != 0 {
+		t.Errorf("Expected no mission state writes for a normal issue, got %d", n)
+	}
+}
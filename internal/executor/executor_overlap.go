@@ -0,0 +1,99 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// Executors working side by side merge their branches one after another, so
+// two agents changing the same files at once make the later merge conflict.
+// Before claiming an issue, an executor compares the files the issue is
+// predicted to change (ContextGatherer.GetRelevantFiles) with those of the
+// issues other executors are working on, and defers it while they overlap.
+
+// overlapCandidates bounds the ready issues considered per poll while other
+// executors have work in flight, so one overlapping issue doesn't hold up
+// the rest
+const overlapCandidates = 10
+
+// inFlightWork is an issue another executor is working on and the files
+// it is predicted to change
+type inFlightWork struct {
+	issueID string
+	files   map[string]bool
+}
+
+// inFlightFiles lists the issues of the executor's project that other
+// executors are working on and have predicted files. Failures are logged
+// and leave issues out, so they never stop work from being claimed.
+func (e *Executor) inFlightFiles(ctx context.Context) []inFlightWork {
+	status := types.StatusInProgress
+	issues, err := e.store.SearchIssues(ctx, "", types.IssueFilter{Status: &status, Project: e.project})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to list work in flight: %v\n", err)
+		return nil
+	}
+	gatherer := NewContextGatherer(e.store)
+	var inFlight []inFlightWork
+	for _, issue := range issues {
+		state, err := e.store.GetExecutionState(ctx, issue.ID)
+		if err != nil || state == nil || state.ExecutorInstanceID == e.instanceID {
+			continue // Not claimed by another executor
+		}
+		files, err := gatherer.GetRelevantFiles(ctx, issue)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to predict the files of %s: %v\n", issue.ID, err)
+			continue
+		}
+		if len(files) == 0 {
+			continue
+		}
+		work := inFlightWork{issueID: issue.ID, files: make(map[string]bool, len(files))}
+		for _, file := range files {
+			work.files[file] = true
+		}
+		inFlight = append(inFlight, work)
+	}
+	return inFlight
+}
+
+// deferOverlapping reports whether the issue's predicted files overlap
+// work in flight, emitting a claim_deferred event the first time this
+// executor defers it for that work
+func (e *Executor) deferOverlapping(ctx context.Context, issue *types.Issue, inFlight []inFlightWork) bool {
+	if len(inFlight) == 0 {
+		return false
+	}
+	files, err := NewContextGatherer(e.store).GetRelevantFiles(ctx, issue)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to predict the files of %s: %v\n", issue.ID, err)
+		return false
+	}
+	for _, work := range inFlight {
+		var shared []string
+		for _, file := range files {
+			if work.files[file] {
+				shared = append(shared, file)
+			}
+		}
+		if len(shared) == 0 {
+			continue
+		}
+		if _, seen := e.overlapNoticed.LoadOrStore(issue.ID+" "+work.issueID, true); !seen {
+			fmt.Printf("Deferring %s: it overlaps %s in flight (%s)\n", issue.ID, work.issueID, strings.Join(shared, ", "))
+			e.logEvent(ctx, events.EventTypeClaimDeferred, events.SeverityInfo, issue.ID,
+				fmt.Sprintf("Deferred due to overlap with %s", work.issueID),
+				map[string]interface{}{
+					"overlaps_with": work.issueID,
+					"files":         shared,
+				})
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package executor
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage/storagetest"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// TestProcessNextIssueDefersOverlap verifies the executor skips ready work
+// whose files overlap another executor's work in flight, and reports it once
+func TestProcessNextIssueDefersOverlap(t *testing.T) {
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+	create := func(title, description string, priority int) *types.Issue {
+		t.Helper()
+		issue := &types.Issue{Title: title, Description: description, Status: types.StatusOpen, Priority: priority, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("Failed to create issue: %v", err)
+		}
+		return issue
+	}
+	running := create("Rework the parser", "Split internal/parser/parse.go", 1)
+	if err := store.ClaimIssue(ctx, running.ID, "exec-other"); err != nil {
+		t.Fatalf("Failed to claim issue: %v", err)
+	}
+	overlapping := create("Fix a parser bug", "The bug is in internal/parser/parse.go", 1)
+
+	execCfg := DefaultConfig()
+	execCfg.Store = store
+	execCfg.EnableAISupervision = false
+	exec, err := New(execCfg)
+	if err != nil {
+		t.Fatalf("Failed to create executor: %v", err)
+	}
+
+	inFlight := exec.inFlightFiles(ctx)
+	if len(inFlight) != 1 || inFlight[0].issueID != running.ID || !inFlight[0].files["internal/parser/parse.go"] {
+		t.Fatalf("inFlightFiles() = %+v, want %s and its file", inFlight, running.ID)
+	}
+
+	// All the ready work overlaps: nothing is claimed
+	for i := 0; i < 2; i++ {
+		if err := exec.processNextIssue(ctx); err != nil {
+			t.Fatalf("processNextIssue: %v", err)
+		}
+	}
+	if n := store.CallCount("ClaimIssue"); n != 1 {
+		t.Errorf("Expected no claims while the work overlaps, got %d", n-1)
+	}
+	agentEvents, err := store.GetAgentEventsByIssue(ctx, overlapping.ID)
+	if err != nil {
+		t.Fatalf("Failed to get events: %v", err)
+	}
+	var deferred []*events.AgentEvent
+	for _, event := range agentEvents {
+		if event.Type == events.EventTypeClaimDeferred {
+			deferred = append(deferred, event)
+		}
+	}
+	if len(deferred) != 1 {
+		t.Fatalf("Expected 1 claim_deferred event, got %d", len(deferred))
+	}
+	if !strings.Contains(deferred[0].Message, running.ID) || deferred[0].Data["overlaps_with"] != running.ID {
+		t.Errorf("Expected the event to name %s, got %q %v", running.ID, deferred[0].Message, deferred[0].Data)
+	}
+
+	// Lower-priority work that doesn't overlap is claimed instead
+	create("Update the docs", "Describe the parser in docs/PARSER.md", 2)
+	store.FailOn("ClaimIssue", types.ErrAlreadyClaimed)
+	if err := exec.processNextIssue(ctx); err != nil {
+		t.Fatalf("processNextIssue: %v", err)
+	}
+	if n := store.CallCount("ClaimIssue"); n != 2 {
+		t.Errorf("Expected the work that doesn't overlap to be claimed, got %d claims", n-1)
+	}
+
+	// The executor's own work never defers its claims
+	exec.instanceID = "exec-other"
+	if inFlight := exec.inFlightFiles(ctx); len(inFlight) != 0 {
+		t.Errorf("inFlightFiles() = %+v, want none of the executor's own work", inFlight)
+	}
+}
@@ -0,0 +1,138 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// updateMissionPhases moves a phased mission along after an execution of
+// one of its tasks. Storage only hands out work of the executing phase (the
+// first one in order that isn't closed), so this is the bookkeeping around
+// it: a blocked task fails its phase and stops the mission, and closed
+// phases advance current_phase until the last one closes the mission.
+// Failures are logged but never fail the execution.
+func (e *Executor) updateMissionPhases(ctx context.Context, taskID string) {
+	missionCtx, err := e.store.GetMissionForTask(ctx, taskID)
+	if err != nil || missionCtx.PhaseID == "" {
+		return // Not a task of a phased mission
+	}
+	task, err := e.store.GetIssue(ctx, taskID)
+	if err != nil || task == nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to get %s for its mission phase: %v\n", taskID, err)
+		return
+	}
+
+	if task.Status == types.StatusBlocked {
+		e.failMissionPhase(ctx, missionCtx.MissionID, missionCtx.PhaseID, task)
+		return
+	}
+	if err := e.advanceMissionPhases(ctx, missionCtx.MissionID); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to advance phases of mission %s: %v\n", missionCtx.MissionID, err)
+	}
+}
+
+// advanceMissionPhases moves current_phase past the phases that are closed
+// and closes the mission once all of them are. phase_count follows the
+// mission's phase list, so phases added or archived since planning count.
+func (e *Executor) advanceMissionPhases(ctx context.Context, missionID string) error {
+	mission, err := e.store.GetMission(ctx, missionID)
+	if err != nil {
+		return err
+	}
+	phases, err := e.store.GetMissionPhases(ctx, missionID)
+	if err != nil {
+		return err
+	}
+	if len(phases) == 0 || mission.Status == types.StatusClosed {
+		return nil
+	}
+
+	current := mission.CurrentPhase
+	if mission.PhaseCount != len(phases) || current > len(phases) {
+		current = min(current, len(phases))
+		if err := e.store.UpdateMissionState(ctx, missionID, map[string]interface{}{
+			"phase_count":   len(phases),
+			"current_phase": current,
+		}); err != nil {
+			return err
+		}
+	}
+
+	for current < len(phases) && phases[current].Status == types.StatusClosed {
+		phase := phases[current]
+		if current, err = e.store.AdvanceMissionPhase(ctx, missionID); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Mission %s completed phase %d of %d: %s\n", missionID, current, len(phases), phase.Title)
+		e.logEvent(ctx, events.EventTypeMissionPhaseCompleted, events.SeverityInfo, missionID,
+			fmt.Sprintf("Mission %s completed phase %d of %d (%s)", missionID, current, len(phases), phase.ID),
+			map[string]interface{}{
+				"mission_id":    missionID,
+				"phase_id":      phase.ID,
+				"current_phase": current,
+				"phase_count":   len(phases),
+			})
+	}
+	if current < len(phases) {
+		return nil
+	}
+
+	reason := fmt.Sprintf("All %d phases completed", len(phases))
+	if err := e.store.CloseIssue(ctx, missionID, reason, "executor"); err != nil {
+		return fmt.Errorf("failed to close mission: %w", err)
+	}
+	fmt.Printf("✓ Closed mission %s: %s (%s)\n", missionID, mission.Title, reason)
+	e.logEvent(ctx, events.EventTypeMissionCompleted, events.SeverityInfo, missionID,
+		fmt.Sprintf("Mission %s completed: %s", missionID, reason),
+		map[string]interface{}{
+			"mission_id":  missionID,
+			"phase_count": len(phases),
+		})
+
+	if e.sandboxMgr != nil {
+		if err := cleanupMissionSandboxIfComplete(ctx, e.store, e.sandboxMgr, e.instanceID, missionID); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to cleanup mission sandbox for %s: %v\n", missionID, err)
+		}
+	}
+	return nil
+}
+
+// failMissionPhase stops a mission whose phase has a blocked task: the
+// phase and the mission are blocked until someone resumes the mission
+// ('vc mission resume'), which retries the same phase
+func (e *Executor) failMissionPhase(ctx context.Context, missionID, phaseID string, task *types.Issue) {
+	for _, id := range []string{phaseID, missionID} {
+		issue, err := e.store.GetIssue(ctx, id)
+		if err != nil || issue == nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to get %s to block it: %v\n", id, err)
+			continue
+		}
+		if issue.Status == types.StatusBlocked || issue.Status == types.StatusClosed {
+			continue
+		}
+		if err := e.store.UpdateIssue(ctx, id, map[string]interface{}{
+			"status": string(types.StatusBlocked),
+		}, "executor"); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to block %s: %v\n", id, err)
+		}
+	}
+
+	comment := fmt.Sprintf("**Phase failed**: %s is blocked on task %s (%s). "+
+		"The mission is stopped; fix the task, then run 'vc mission resume %s' to retry the phase.",
+		phaseID, task.ID, task.Title, missionID)
+	if err := e.store.AddComment(ctx, missionID, "executor", comment); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to add comment to %s: %v\n", missionID, err)
+	}
+	fmt.Fprintf(os.Stderr, "Mission %s stopped: phase %s failed on task %s\n", missionID, phaseID, task.ID)
+	e.logEvent(ctx, events.EventTypeMissionPhaseFailed, events.SeverityError, missionID,
+		fmt.Sprintf("Mission %s stopped: phase %s failed on blocked task %s", missionID, phaseID, task.ID),
+		map[string]interface{}{
+			"mission_id": missionID,
+			"phase_id":   phaseID,
+			"task_id":    task.ID,
+		})
+}
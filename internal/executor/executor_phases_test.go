@@ -0,0 +1,234 @@
+package executor
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage/storagetest"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// TestUpdateMissionPhases walks a two-phase mission through a failed phase,
+// a resume, and completion
+func TestUpdateMissionPhases(t *testing.T) {
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+	mission := &types.Mission{
+		Issue: types.Issue{
+			Title:        "Mission",
+			Status:       types.StatusOpen,
+			Priority:     1,
+			IssueType:    types.TypeEpic,
+			IssueSubtype: types.SubtypeMission,
+		},
+		Goal: "Ship it",
+	}
+	if err := store.CreateMission(ctx, mission, "test"); err != nil {
+		t.Fatalf("Failed to create mission: %v", err)
+	}
+	create := func(title string, issueType types.IssueType, subtype types.IssueSubtype, parentID string) *types.Issue {
+		t.Helper()
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 1, IssueType: issueType, IssueSubtype: subtype}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("Failed to create %s: %v", title, err)
+		}
+		if err := store.AddDependency(ctx, &types.Dependency{IssueID: issue.ID, DependsOnID: parentID, Type: types.DepParentChild}, "test"); err != nil {
+			t.Fatalf("Failed to add dependency: %v", err)
+		}
+		return issue
+	}
+	design := create("Design", types.TypeEpic, types.SubtypePhase, mission.ID)
+	build := create("Build", types.TypeEpic, types.SubtypePhase, mission.ID)
+	designTask := create("Write design", types.TypeTask, types.SubtypeNormal, design.ID)
+	buildTask := create("Build it", types.TypeTask, types.SubtypeNormal, build.ID)
+
+	execCfg := DefaultConfig()
+	execCfg.Store = store
+	execCfg.EnableAISupervision = false
+	exec, err := New(execCfg)
+	if err != nil {
+		t.Fatalf("Failed to create executor: %v", err)
+	}
+
+	status := func(id string) types.Status {
+		t.Helper()
+		issue, err := store.GetIssue(ctx, id)
+		if err != nil {
+			t.Fatalf("Failed to get %s: %v", id, err)
+		}
+		return issue.Status
+	}
+	state := func() *types.Mission {
+		t.Helper()
+		got, err := store.GetMission(ctx, mission.ID)
+		if err != nil {
+			t.Fatalf("Failed to get mission: %v", err)
+		}
+		return got
+	}
+	readyIDs := func() []string {
+		t.Helper()
+		ready, err := store.GetReadyWork(ctx, types.WorkFilter{})
+		if err != nil {
+			t.Fatalf("Failed to get ready work: %v", err)
+		}
+		var ids []string
+		for _, issue := range ready {
+			ids = append(ids, issue.ID)
+		}
+		return ids
+	}
+	eventCount := func(eventType events.EventType) int {
+		t.Helper()
+		agentEvents, err := store.GetAgentEventsByIssue(ctx, mission.ID)
+		if err != nil {
+			t.Fatalf("Failed to get events: %v", err)
+		}
+		n := 0
+		for _, event := range agentEvents {
+			if event.Type == eventType {
+				n++
+			}
+		}
+		return n
+	}
+
+	// A task that isn't done yet leaves the phase where it is
+	exec.updateMissionPhases(ctx, designTask.ID)
+	if got := state(); got.CurrentPhase != 0 || got.PhaseCount != 2 {
+		t.Errorf("Expected phase 0 of 2 with the first phase open, got %d of %d", got.CurrentPhase, got.PhaseCount)
+	}
+
+	// First phase done: the mission moves on to the second
+	for _, id := range []string{designTask.ID, design.ID} {
+		if err := store.CloseIssue(ctx, id, "done", "test"); err != nil {
+			t.Fatalf("Failed to close %s: %v", id, err)
+		}
+	}
+	exec.updateMissionPhases(ctx, designTask.ID)
+	if got := state(); got.CurrentPhase != 1 || got.Status != types.StatusOpen {
+		t.Errorf("Expected open mission at phase 1, got status %s phase %d", got.Status, got.CurrentPhase)
+	}
+	if n := eventCount(events.EventTypeMissionPhaseCompleted); n != 1 {
+		t.Errorf("Expected 1 phase completed event, got %d", n)
+	}
+
+	// Second phase fails: phase and mission stop, the pointer stays
+	if err := store.UpdateIssue(ctx, buildTask.ID, map[string]interface{}{"status": string(types.StatusBlocked)}, "executor"); err != nil {
+		t.Fatalf("Failed to block task: %v", err)
+	}
+	exec.updateMissionPhases(ctx, buildTask.ID)
+	if s := status(build.ID); s != types.StatusBlocked {
+		t.Errorf("Expected the failed phase to be blocked, got %s", s)
+	}
+	if got := state(); got.Status != types.StatusBlocked || got.CurrentPhase != 1 {
+		t.Errorf("Expected blocked mission at phase 1, got status %s phase %d", got.Status, got.CurrentPhase)
+	}
+	if n := eventCount(events.EventTypeMissionPhaseFailed); n != 1 {
+		t.Errorf("Expected 1 phase failed event, got %d", n)
+	}
+	comments, err := store.GetEvents(ctx, mission.ID, 0)
+	if err != nil {
+		t.Fatalf("Failed to get events: %v", err)
+	}
+	found := false
+	for _, event := range comments {
+		if event.Comment != nil && strings.Contains(*event.Comment, "**Phase failed**") && strings.Contains(*event.Comment, buildTask.ID) {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Expected a phase failed comment naming the blocked task")
+	}
+
+	// Resuming retries the failed phase, not the finished one
+	for _, id := range []string{mission.ID, build.ID, buildTask.ID} {
+		if err := store.UpdateIssue(ctx, id, map[string]interface{}{"status": string(types.StatusOpen)}, "test"); err != nil {
+			t.Fatalf("Failed to reopen %s: %v", id, err)
+		}
+	}
+	if got := readyIDs(); len(got) != 1 || got[0] != buildTask.ID {
+		t.Errorf("Expected only %s ready after resuming, got %v", buildTask.ID, got)
+	}
+
+	// Last phase done: the mission closes
+	for _, id := range []string{buildTask.ID, build.ID} {
+		if err := store.CloseIssue(ctx, id, "done", "test"); err != nil {
+			t.Fatalf("Failed to close %s: %v", id, err)
+		}
+	}
+	exec.updateMissionPhases(ctx, buildTask.ID)
+	if got := state(); got.Status != types.StatusClosed || got.CurrentPhase != 2 {
+		t.Errorf("Expected closed mission at phase 2, got status %s phase %d", got.Status, got.CurrentPhase)
+	}
+	if n := eventCount(events.EventTypeMissionCompleted); n != 1 {
+		t.Errorf("Expected 1 mission completed event, got %d", n)
+	}
+
+	// Tasks outside phased missions are left alone
+	loose := &types.Issue{Title: "Loose", Status: types.StatusBlocked, Priority: 1, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, loose, "test"); err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	store.ResetCalls()
+	exec.updateMissionPhases(ctx, loose.ID)
+	if n := store.CallCount("UpdateIssue"); n != 0 {
+		t.Errorf("Expected no updates for a task outside a mission, got %d", n)
+	}
+}
+
+// TestUpdateMissionPhasesSkipsClosedPhases verifies phases closed by hand
+// are stepped over together
+func TestUpdateMissionPhasesSkipsClosedPhases(t *testing.T) {
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+	mission := &types.Mission{
+		Issue: types.Issue{
+			Title:        "Mission",
+			Status:       types.StatusOpen,
+			Priority:     1,
+			IssueType:    types.TypeEpic,
+			IssueSubtype: types.SubtypeMission,
+		},
+		Goal: "Ship it",
+	}
+	if err := store.CreateMission(ctx, mission, "test"); err != nil {
+		t.Fatalf("Failed to create mission: %v", err)
+	}
+	var phases []*types.Issue
+	for _, title := range []string{"One", "Two", "Three"} {
+		phase := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 1, IssueType: types.TypeEpic, IssueSubtype: types.SubtypePhase}
+		if err := store.CreateIssue(ctx, phase, "test"); err != nil {
+			t.Fatalf("Failed to create phase: %v", err)
+		}
+		if err := store.AddDependency(ctx, &types.Dependency{IssueID: phase.ID, DependsOnID: mission.ID, Type: types.DepParentChild}, "test"); err != nil {
+			t.Fatalf("Failed to add dependency: %v", err)
+		}
+		phases = append(phases, phase)
+	}
+	for _, phase := range phases[:2] {
+		if err := store.CloseIssue(ctx, phase.ID, "done", "test"); err != nil {
+			t.Fatalf("Failed to close phase: %v", err)
+		}
+	}
+
+	execCfg := DefaultConfig()
+	execCfg.Store = store
+	execCfg.EnableAISupervision = false
+	exec, err := New(execCfg)
+	if err != nil {
+		t.Fatalf("Failed to create executor: %v", err)
+	}
+	if err := exec.advanceMissionPhases(ctx, mission.ID); err != nil {
+		t.Fatalf("advanceMissionPhases: %v", err)
+	}
+	got, err := store.GetMission(ctx, mission.ID)
+	if err != nil {
+		t.Fatalf("Failed to get mission: %v", err)
+	}
+	if got.CurrentPhase != 2 || got.PhaseCount != 3 || got.Status != types.StatusOpen {
+		t.Errorf("Expected open mission at phase 2 of 3, got status %s phase %d of %d", got.Status, got.CurrentPhase, got.PhaseCount)
+	}
+}
@@ -0,0 +1,68 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/steveyegge/vc/internal/ai"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// voidedPrerequisites returns the direct blocking dependencies of an issue
+// that were closed as wontfix or obsolete: they will never be done, so the
+// issue's premise may be gone. Lookup errors are logged and skipped, since
+// a failed check shouldn't hold back work.
+func (e *Executor) voidedPrerequisites(ctx context.Context, issueID string) []*types.Issue {
+	deps, err := e.store.GetDependencyRecords(ctx, issueID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to check the dependencies of %s: %v\n", issueID, err)
+		return nil
+	}
+	var voided []*types.Issue
+	for _, dep := range deps {
+		if dep.Type != types.DepBlocks {
+			continue
+		}
+		prerequisite, err := e.store.GetIssue(ctx, dep.DependsOnID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to get dependency %s of %s: %v\n", dep.DependsOnID, issueID, err)
+			continue
+		}
+		if prerequisite != nil && prerequisite.Status == types.StatusClosed && prerequisite.Resolution.VoidsPremise() {
+			voided = append(voided, prerequisite)
+		}
+	}
+	return voided
+}
+
+// holdForTriage releases a claimed issue without executing it because
+// prerequisites it depends on were dropped. The issue is blocked and labeled
+// needs-triage, with a comment naming them, until someone decides whether
+// the work still makes sense.
+func (e *Executor) holdForTriage(ctx context.Context, issue *types.Issue, voided []*types.Issue) {
+	var gone []string
+	for _, prerequisite := range voided {
+		gone = append(gone, fmt.Sprintf("- %s (%s) was closed as %s", prerequisite.ID, prerequisite.Title, prerequisite.Resolution))
+	}
+	comment := fmt.Sprintf("Not executed: prerequisites of this issue were dropped, so its premise may be gone.\n\n%s\n\n"+
+		"If the work still makes sense, remove those dependencies (vc dep remove %s <id>) and run vc unblock %s; otherwise close it.",
+		strings.Join(gone, "\n"), issue.ID, issue.ID)
+	fmt.Printf("Skipping %s: %d prerequisite(s) closed as wontfix or obsolete, needs triage\n", issue.ID, len(voided))
+
+	if err := e.store.ReleaseIssue(ctx, issue.ID); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to release issue %s: %v\n", issue.ID, err)
+	}
+	if err := e.store.UpdateIssue(ctx, issue.ID, map[string]interface{}{
+		"status": string(types.StatusBlocked),
+	}, "executor"); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to mark issue %s as blocked: %v\n", issue.ID, err)
+	}
+	if err := e.store.AddComment(ctx, issue.ID, "executor", comment); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to add comment to %s: %v\n", issue.ID, err)
+	}
+	if err := e.store.AddLabel(ctx, issue.ID, ai.LabelNeedsTriage, "executor"); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to label %s as %s: %v\n", issue.ID, ai.LabelNeedsTriage, err)
+	}
+}
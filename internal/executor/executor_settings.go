@@ -0,0 +1,227 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// settingFields maps each executor.* setting in config.Settings to the
+// Config field it overrides
+var settingFields = map[string]func(ctx context.Context, c *Config, r config.ConfigReader, key string) error{
+	"executor.ai_api_key_env": func(ctx context.Context, c *Config, r config.ConfigReader, key string) (err error) {
+		c.AIAPIKeyEnv, err = config.GetConfigString(ctx, r, key)
+		return err
+	},
+	"executor.ai_base_url": func(ctx context.Context, c *Config, r config.ConfigReader, key string) (err error) {
+		c.AIBaseURL, err = config.GetConfigString(ctx, r, key)
+		return err
+	},
+	"executor.ai_comment_max_kb": func(ctx context.Context, c *Config, r config.ConfigReader, key string) error {
+		kb, err := config.GetConfigInt(ctx, r, key)
+		c.AICommentMaxSize = kb << 10
+		return err
+	},
+	"executor.ai_model": func(ctx context.Context, c *Config, r config.ConfigReader, key string) (err error) {
+		c.AIModel, err = config.GetConfigString(ctx, r, key)
+		return err
+	},
+	"executor.ai_prices": func(ctx context.Context, c *Config, r config.ConfigReader, key string) error {
+		value, err := config.GetConfigString(ctx, r, key)
+		if err != nil {
+			return err
+		}
+		c.AIPrices, err = config.ParseModelPrices(value)
+		return err
+	},
+	"executor.ai_provider": func(ctx context.Context, c *Config, r config.ConfigReader, key string) (err error) {
+		c.AIProvider, err = config.GetConfigString(ctx, r, key)
+		return err
+	},
+	"executor.artifacts_dir": func(ctx context.Context, c *Config, r config.ConfigReader, key string) (err error) {
+		c.ArtifactsDir, err = config.GetConfigString(ctx, r, key)
+		return err
+	},
+	"executor.artifacts_max_age": func(ctx context.Context, c *Config, r config.ConfigReader, key string) (err error) {
+		c.ArtifactMaxAge, err = config.GetConfigDuration(ctx, r, key)
+		return err
+	},
+	"executor.artifacts_max_file_kb": func(ctx context.Context, c *Config, r config.ConfigReader, key string) error {
+		kb, err := config.GetConfigInt(ctx, r, key)
+		c.ArtifactMaxFileSize = int64(kb) << 10
+		return err
+	},
+	"executor.artifacts_max_total_mb": func(ctx context.Context, c *Config, r config.ConfigReader, key string) error {
+		mb, err := config.GetConfigInt(ctx, r, key)
+		c.ArtifactMaxTotalSize = int64(mb) << 20
+		return err
+	},
+	"executor.auto_merge_threshold": func(ctx context.Context, c *Config, r config.ConfigReader, key string) (err error) {
+		c.AutoMergeThreshold, err = config.GetConfigFloat(ctx, r, key)
+		return err
+	},
+	"executor.cleanup_interval": func(ctx context.Context, c *Config, r config.ConfigReader, key string) (err error) {
+		c.CleanupInterval, err = config.GetConfigDuration(ctx, r, key)
+		return err
+	},
+	"executor.daily_summary": func(ctx context.Context, c *Config, r config.ConfigReader, key string) (err error) {
+		c.DailySummary, err = config.GetConfigBool(ctx, r, key)
+		return err
+	},
+	"executor.daily_summary_webhook": func(ctx context.Context, c *Config, r config.ConfigReader, key string) (err error) {
+		c.DailySummaryWebhook, err = config.GetConfigString(ctx, r, key)
+		return err
+	},
+	"executor.discovered_force_triage": func(ctx context.Context, c *Config, r config.ConfigReader, key string) (err error) {
+		c.ForceDiscoveredTriage, err = config.GetConfigBool(ctx, r, key)
+		return err
+	},
+	"executor.enable_failure_analysis": func(ctx context.Context, c *Config, r config.ConfigReader, key string) (err error) {
+		c.EnableFailureAnalysis, err = config.GetConfigBool(ctx, r, key)
+		return err
+	},
+	"executor.enable_quality_gate_worker": func(ctx context.Context, c *Config, r config.ConfigReader, key string) (err error) {
+		c.EnableQualityGateWorker, err = config.GetConfigBool(ctx, r, key)
+		return err
+	},
+	"executor.enable_quality_gates": func(ctx context.Context, c *Config, r config.ConfigReader, key string) (err error) {
+		c.EnableQualityGates, err = config.GetConfigBool(ctx, r, key)
+		return err
+	},
+	"executor.epic_auto_close": func(ctx context.Context, c *Config, r config.ConfigReader, key string) (err error) {
+		c.EpicAutoClose, err = config.GetConfigString(ctx, r, key)
+		return err
+	},
+	"executor.event_coalesce_exempt": func(ctx context.Context, c *Config, r config.ConfigReader, key string) error {
+		value, err := config.GetConfigString(ctx, r, key)
+		if err != nil {
+			return err
+		}
+		c.EventCoalesceExempt = nil
+		for _, t := range strings.Split(value, ",") {
+			if t != "" {
+				c.EventCoalesceExempt = append(c.EventCoalesceExempt, events.EventType(t))
+			}
+		}
+		return nil
+	},
+	"executor.event_coalesce_window": func(ctx context.Context, c *Config, r config.ConfigReader, key string) (err error) {
+		c.EventCoalesceWindow, err = config.GetConfigDuration(ctx, r, key)
+		return err
+	},
+	"executor.failure_analysis_cost_cap": func(ctx context.Context, c *Config, r config.ConfigReader, key string) (err error) {
+		c.FailureAnalysisCostCap, err = config.GetConfigFloat(ctx, r, key)
+		return err
+	},
+	"executor.heartbeat_period": func(ctx context.Context, c *Config, r config.ConfigReader, key string) (err error) {
+		c.HeartbeatPeriod, err = config.GetConfigDuration(ctx, r, key)
+		return err
+	},
+	"executor.keep_branches": func(ctx context.Context, c *Config, r config.ConfigReader, key string) (err error) {
+		c.KeepBranches, err = config.GetConfigBool(ctx, r, key)
+		return err
+	},
+	"executor.keep_sandbox_on_failure": func(ctx context.Context, c *Config, r config.ConfigReader, key string) (err error) {
+		c.KeepSandboxOnFailure, err = config.GetConfigBool(ctx, r, key)
+		return err
+	},
+	"executor.offline": func(ctx context.Context, c *Config, r config.ConfigReader, key string) (err error) {
+		c.Offline, err = config.GetConfigBool(ctx, r, key)
+		return err
+	},
+	"executor.paused": func(ctx context.Context, c *Config, r config.ConfigReader, key string) (err error) {
+		c.Paused, err = config.GetConfigBool(ctx, r, key)
+		return err
+	},
+	"executor.poll_interval": func(ctx context.Context, c *Config, r config.ConfigReader, key string) (err error) {
+		c.PollInterval, err = config.GetConfigDuration(ctx, r, key)
+		return err
+	},
+	"executor.prompt_token_budgets": func(ctx context.Context, c *Config, r config.ConfigReader, key string) error {
+		value, err := config.GetConfigString(ctx, r, key)
+		if err != nil {
+			return err
+		}
+		c.PromptTokenBudgets, err = config.ParsePromptBudgets(value)
+		return err
+	},
+	"executor.respect_assignees": func(ctx context.Context, c *Config, r config.ConfigReader, key string) (err error) {
+		c.RespectAssignees, err = config.GetConfigBool(ctx, r, key)
+		return err
+	},
+	"executor.sandbox_retention_count": func(ctx context.Context, c *Config, r config.ConfigReader, key string) (err error) {
+		c.SandboxRetentionCount, err = config.GetConfigInt(ctx, r, key)
+		return err
+	},
+	"executor.shutdown_grace": func(ctx context.Context, c *Config, r config.ConfigReader, key string) (err error) {
+		c.ShutdownGrace, err = config.GetConfigDuration(ctx, r, key)
+		return err
+	},
+	"executor.stale_threshold": func(ctx context.Context, c *Config, r config.ConfigReader, key string) (err error) {
+		c.StaleThreshold, err = config.GetConfigDuration(ctx, r, key)
+		return err
+	},
+	"executor.storage_call_timeout": func(ctx context.Context, c *Config, r config.ConfigReader, key string) (err error) {
+		c.StorageCallTimeout, err = config.GetConfigDuration(ctx, r, key)
+		return err
+	},
+	"executor.storage_failure_threshold": func(ctx context.Context, c *Config, r config.ConfigReader, key string) (err error) {
+		c.StorageFailureThreshold, err = config.GetConfigInt(ctx, r, key)
+		return err
+	},
+	"executor.work_order": func(ctx context.Context, c *Config, r config.ConfigReader, key string) error {
+		value, err := config.GetConfigString(ctx, r, key)
+		c.WorkOrder = types.WorkOrder(value)
+		return err
+	},
+}
+
+// LoadSettings overrides the config with the executor.* settings stored in
+// the database (`vc config set`). Unset keys keep the config's value, so
+// command-line flags applied afterwards still win. Returns the keys applied.
+func (c *Config) LoadSettings(ctx context.Context, store storage.Storage) ([]string, error) {
+	stored, err := store.ListConfig(ctx, config.ExecutorNamespace)
+	if err != nil {
+		return nil, err
+	}
+	return c.applySettings(ctx, store, func(key string) bool {
+		_, ok := stored[key]
+		return ok
+	})
+}
+
+// LoadFileSettings overrides the config with the executor.* settings set in
+// config files (.vc/config.yaml). Called after LoadSettings, they win over
+// the database; flags applied afterwards still win over them. Returns the
+// keys applied.
+func (c *Config) LoadFileSettings(ctx context.Context, file *config.File) ([]string, error) {
+	return c.applySettings(ctx, file, func(key string) bool {
+		_, ok := file.Get(key)
+		return ok
+	})
+}
+
+// applySettings reads the known executor settings for which isSet is true
+// from r into the config
+func (c *Config) applySettings(ctx context.Context, r config.ConfigReader, isSet func(key string) bool) ([]string, error) {
+	var applied []string
+	for _, setting := range config.Settings {
+		if !isSet(setting.Key) || !config.IsExecutorSetting(setting.Key) {
+			continue
+		}
+		set, ok := settingFields[setting.Key]
+		if !ok {
+			return applied, fmt.Errorf("setting %s has no executor config field", setting.Key)
+		}
+		if err := set(ctx, c, r, setting.Key); err != nil {
+			return applied, err
+		}
+		applied = append(applied, setting.Key)
+	}
+	return applied, nil
+}
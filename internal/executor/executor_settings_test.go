@@ -0,0 +1,133 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/storage/storagetest"
+)
+
+// TestLoadSettings verifies stored executor settings override the config
+// and unset ones leave it alone
+func TestLoadSettings(t *testing.T) {
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+	for key, value := range map[string]string{
+		"executor.poll_interval":           "2s",
+		"executor.keep_branches":           "true",
+		"executor.sandbox_retention_count": "0",
+		"issue_prefix":                     "xy",
+	} {
+		if err := store.SetConfig(ctx, key, value); err != nil {
+			t.Fatalf("SetConfig %s: %v", key, err)
+		}
+	}
+
+	cfg := DefaultConfig()
+	cfg.HeartbeatPeriod = time.Minute
+	applied, err := cfg.LoadSettings(ctx, store)
+	if err != nil {
+		t.Fatalf("LoadSettings: %v", err)
+	}
+	if got := strings.Join(applied, ","); got != "executor.keep_branches,executor.poll_interval,executor.sandbox_retention_count" {
+		t.Errorf("Unexpected applied keys: %s", got)
+	}
+	if cfg.PollInterval != 2*time.Second || !cfg.KeepBranches || cfg.SandboxRetentionCount != 0 {
+		t.Errorf("Settings not applied: poll %v, keep branches %v, retention %d",
+			cfg.PollInterval, cfg.KeepBranches, cfg.SandboxRetentionCount)
+	}
+	if cfg.HeartbeatPeriod != time.Minute {
+		t.Errorf("Unset setting changed the heartbeat period to %v", cfg.HeartbeatPeriod)
+	}
+}
+
+// TestLoadFileSettings verifies config file settings win over the stored
+// ones and that the file's flag keys are left to vc execute
+func TestLoadFileSettings(t *testing.T) {
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+	if err := store.SetConfig(ctx, "executor.poll_interval", "2s"); err != nil {
+		t.Fatalf("SetConfig: %v", err)
+	}
+	file := &config.File{Values: map[string]config.FileValue{}}
+	err := file.Parse(".vc/config.yaml", []byte("executor:\n  poll_interval: 10s\n  ai_model: small\n  sandbox_root: /tmp/sandboxes\n"))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	cfg := DefaultConfig()
+	if _, err := cfg.LoadSettings(ctx, store); err != nil {
+		t.Fatalf("LoadSettings: %v", err)
+	}
+	applied, err := cfg.LoadFileSettings(ctx, file)
+	if err != nil {
+		t.Fatalf("LoadFileSettings: %v", err)
+	}
+	if got := strings.Join(applied, ","); got != "executor.ai_model,executor.poll_interval" {
+		t.Errorf("Unexpected applied keys: %s", got)
+	}
+	if cfg.PollInterval != 10*time.Second || cfg.AIModel != "small" {
+		t.Errorf("File settings not applied: poll %v, model %q", cfg.PollInterval, cfg.AIModel)
+	}
+}
+
+// TestSettingFieldsCoverTable keeps settingFields in step with the
+// executor.* entries of config.Settings
+func TestSettingFieldsCoverTable(t *testing.T) {
+	ctx := context.Background()
+	n := 0
+	for _, setting := range config.Settings {
+		if !config.IsExecutorSetting(setting.Key) {
+			continue
+		}
+		n++
+		set, ok := settingFields[setting.Key]
+		if !ok {
+			t.Errorf("No executor config field for %s", setting.Key)
+			continue
+		}
+		// The default must apply cleanly through the typed accessor
+		if err := set(ctx, DefaultConfig(), storagetest.NewFakeStorage(), setting.Key); err != nil {
+			t.Errorf("%s: %v", setting.Key, err)
+		}
+	}
+	if n != len(settingFields) {
+		t.Errorf("settingFields has %d entries, config.Settings has %d executor settings", len(settingFields), n)
+	}
+}
+
+// TestCheckPaused verifies executor.paused is re-read on every call, and
+// that Config.Paused applies while it is unset
+func TestCheckPaused(t *testing.T) {
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+	cfg := DefaultConfig()
+	cfg.Paused = true
+	e := &Executor{store: store, config: cfg, paused: cfg.Paused}
+
+	if !e.checkPaused(ctx) {
+		t.Error("Expected Config.Paused to apply while executor.paused is unset")
+	}
+	if err := store.SetConfig(ctx, pausedSetting, "false"); err != nil {
+		t.Fatal(err)
+	}
+	if e.checkPaused(ctx) {
+		t.Error("Expected the stored executor.paused=false to resume")
+	}
+	if err := store.SetConfig(ctx, pausedSetting, "true"); err != nil {
+		t.Fatal(err)
+	}
+	if !e.checkPaused(ctx) {
+		t.Error("Expected the stored executor.paused=true to pause")
+	}
+
+	// A failed read keeps the executor paused
+	store.FailOn("GetConfig", errors.New("database is locked"))
+	if !e.checkPaused(ctx) {
+		t.Error("Expected a failed read to keep the previous state")
+	}
+}
@@ -0,0 +1,121 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// shutdownProgressInterval is how often a graceful shutdown says what it is
+// still waiting for
+const shutdownProgressInterval = 5 * time.Second
+
+// shutdownForceWait is how long Stop may take after the run context was
+// canceled (second signal or deadline) before ShutdownOnSignal gives up on it
+const shutdownForceWait = 10 * time.Second
+
+// setRunningAgent records the coding agent running now and when it times out
+// (an empty issueID when none is)
+func (e *Executor) setRunningAgent(issueID string, deadline time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.agentIssueID = issueID
+	e.agentDeadline = deadline
+}
+
+// runningAgent returns the issue of the coding agent running now and when
+// it times out; the issue is empty when no agent is running
+func (e *Executor) runningAgent() (string, time.Time) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.agentIssueID, e.agentDeadline
+}
+
+// shutdownWait is how long a graceful shutdown may take: the running
+// agent's remaining timeout plus the grace period
+func (e *Executor) shutdownWait() time.Duration {
+	wait := e.shutdownGrace
+	if issueID, deadline := e.runningAgent(); issueID != "" {
+		wait += max(time.Until(deadline), 0)
+	}
+	return wait
+}
+
+// ShutdownOnSignal waits for a signal on sigCh, then stops the executor.
+//
+// The first signal stops it gracefully: no new work is claimed and a
+// running agent may finish, for up to its remaining timeout plus
+// Config.ShutdownGrace. A second signal, or that deadline passing, calls
+// cancelRun (which must cancel the context Start was given), so the agent
+// is stopped and its issue released for a retry that doesn't count as a
+// failure. The instance is marked stopped either way.
+func (e *Executor) ShutdownOnSignal(sigCh <-chan os.Signal, cancelRun context.CancelFunc) error {
+	<-sigCh
+	fmt.Println("\n\nShutting down executor... (press Ctrl+C again to stop immediately)")
+
+	wait := e.shutdownWait()
+	deadline := time.NewTimer(wait)
+	defer deadline.Stop()
+
+	stopCtx, stopCancel := context.WithCancel(context.Background())
+	defer stopCancel()
+	stopped := make(chan error, 1)
+	go func() { stopped <- e.Stop(stopCtx) }()
+
+	progress := time.NewTicker(shutdownProgressInterval)
+	defer progress.Stop()
+	var force <-chan time.Time
+	forced := false
+	forceStop := func(reason string) {
+		if forced {
+			return
+		}
+		forced = true
+		fmt.Printf("%s: stopping now\n", reason)
+		cancelRun()
+		force = time.After(shutdownForceWait)
+	}
+
+	for {
+		select {
+		case err := <-stopped:
+			if err != nil {
+				// Stop gave up before marking the instance stopped
+				markCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				if markErr := e.MarkInstanceStoppedOnExit(markCtx); markErr != nil {
+					fmt.Fprintf(os.Stderr, "warning: %v\n", markErr)
+				}
+				return fmt.Errorf("executor didn't stop cleanly: %w", err)
+			}
+			return nil
+		case <-sigCh:
+			forceStop("Second signal received")
+		case <-deadline.C:
+			forceStop(fmt.Sprintf("Shutdown deadline of %v passed", wait))
+		case <-force:
+			stopCancel()
+		case <-progress.C:
+			if !forced {
+				e.printShutdownProgress()
+			}
+		}
+	}
+}
+
+// printShutdownProgress says what a graceful shutdown is waiting for
+func (e *Executor) printShutdownProgress() {
+	if issueID, deadline := e.runningAgent(); issueID != "" {
+		remaining := max(time.Until(deadline), 0).Round(time.Second)
+		fmt.Printf("Waiting for agent on %s, %v remaining\n", issueID, remaining)
+		return
+	}
+	if e.monitor != nil {
+		if current := e.monitor.GetCurrentExecution(); current != nil {
+			fmt.Printf("Waiting for %s to finish\n", current.IssueID)
+			return
+		}
+	}
+	fmt.Println("Waiting for background tasks to finish")
+}
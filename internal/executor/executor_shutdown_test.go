@@ -2,10 +2,17 @@ package executor
 
 import (
 	"context"
+	"errors"
+	"os"
+	"sync"
+	"sync/atomic"
+	"syscall"
 	"testing"
 	"time"
 
 	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/storage/storagetest"
+	"github.com/steveyegge/vc/internal/types"
 )
 
 // TestShutdownWithoutActiveWork tests that executor shuts down cleanly
@@ -203,3 +210,383 @@ func TestMarkInstanceStoppedOnExit(t *testing.T) {
 
 	t.Log("✓ MarkInstanceStoppedOnExit correctly marks instance as stopped and is idempotent")
 }
+
+// blockingStorage holds execution state updates until the caller's context
+// ends, like an execution that runs until shutdown cuts it short
+type blockingStorage struct {
+	*storagetest.FakeStorage
+	entered chan string // Receives the issue ID once an update is held
+}
+
+func (s *blockingStorage) UpdateExecutionState(ctx context.Context, issueID string, state types.ExecutionState) error {
+	select {
+	case s.entered <- issueID:
+	default:
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// startShutdownTestExecutor starts an executor on store and returns it with
+// a function reporting whether its run context was canceled
+func startShutdownTestExecutor(t *testing.T, store storage.Storage, grace time.Duration) (*Executor, context.CancelFunc, func() bool) {
+	t.Helper()
+	execCfg := DefaultConfig()
+	execCfg.Store = store
+	execCfg.EnableAISupervision = false
+	execCfg.EnableQualityGates = false
+	execCfg.EnableSandboxes = false
+	execCfg.PollInterval = 10 * time.Millisecond
+	execCfg.StorageCallTimeout = time.Minute // Only shutdown ends a held call
+	execCfg.ShutdownGrace = grace
+	exec, err := New(execCfg)
+	if err != nil {
+		t.Fatalf("Failed to create executor: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	var canceled atomic.Bool
+	cancelRun := func() {
+		canceled.Store(true)
+		cancel()
+	}
+	if err := exec.Start(ctx); err != nil {
+		t.Fatalf("Failed to start executor: %v", err)
+	}
+	return exec, cancelRun, canceled.Load
+}
+
+// shutdownOnSignal runs ShutdownOnSignal in the background
+func shutdownOnSignal(exec *Executor, sigCh <-chan os.Signal, cancelRun context.CancelFunc) <-chan error {
+	done := make(chan error, 1)
+	go func() { done <- exec.ShutdownOnSignal(sigCh, cancelRun) }()
+	return done
+}
+
+// createShutdownTestIssue creates a ready issue for the executor to pick up
+func createShutdownTestIssue(t *testing.T, store *blockingStorage) *types.Issue {
+	t.Helper()
+	issue := &types.Issue{Title: "Long task", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	if err := store.CreateIssue(context.Background(), issue, "test"); err != nil {
+		t.Fatalf("Failed to create issue: %v", err)
+	}
+	return issue
+}
+
+// checkInterruptedIssue verifies that an interrupted issue was released for
+// a retry and its attempt recorded as interrupted
+func checkInterruptedIssue(t *testing.T, store *blockingStorage, issueID string) {
+	t.Helper()
+	ctx := context.Background()
+	issue, err := store.GetIssue(ctx, issueID)
+	if err != nil {
+		t.Fatalf("Failed to get issue: %v", err)
+	}
+	if issue.Status != types.StatusOpen {
+		t.Errorf("Expected the interrupted issue to be reopened, got status %s", issue.Status)
+	}
+	history, err := store.GetExecutionHistory(ctx, issueID)
+	if err != nil || len(history) != 1 {
+		t.Fatalf("Expected 1 attempt, got %d (%v)", len(history), err)
+	}
+	if !isInterruptedAttempt(history[0]) {
+		t.Errorf("Expected the attempt to be marked interrupted, got summary %q", history[0].Summary)
+	}
+}
+
+// TestShutdownOnSignalGraceful verifies that a signal stops an idle
+// executor without canceling its run, and marks the instance stopped
+func TestShutdownOnSignalGraceful(t *testing.T) {
+	store := storagetest.NewFakeStorage()
+	exec, cancelRun, canceled := startShutdownTestExecutor(t, store, time.Minute)
+
+	sigCh := make(chan os.Signal, 2)
+	sigCh <- os.Interrupt
+	select {
+	case err := <-shutdownOnSignal(exec, sigCh, cancelRun):
+		if err != nil {
+			t.Fatalf("Expected a clean shutdown, got: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Shutdown didn't finish")
+	}
+	if canceled() {
+		t.Error("Expected a graceful shutdown not to cancel the run context")
+	}
+	if exec.IsRunning() {
+		t.Error("Expected the executor to be stopped")
+	}
+	if n := store.CallCount("MarkInstanceStopped"); n != 1 {
+		t.Errorf("Expected the instance to be marked stopped, got %d calls", n)
+	}
+}
+
+// TestShutdownOnSecondSignal verifies that the first signal waits for the
+// running execution, and a second one cuts it short and releases its issue
+// as interrupted
+func TestShutdownOnSecondSignal(t *testing.T) {
+	store := &blockingStorage{FakeStorage: storagetest.NewFakeStorage(), entered: make(chan string, 1)}
+	issue := createShutdownTestIssue(t, store)
+	exec, cancelRun, canceled := startShutdownTestExecutor(t, store, time.Minute)
+	select {
+	case <-store.entered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Executor didn't start executing the issue")
+	}
+
+	sigCh := make(chan os.Signal, 2)
+	sigCh <- syscall.SIGTERM
+	done := shutdownOnSignal(exec, sigCh, cancelRun)
+	select {
+	case err := <-done:
+		t.Fatalf("Expected the first signal to wait for the execution, shutdown returned: %v", err)
+	case <-time.After(200 * time.Millisecond):
+	}
+	if canceled() {
+		t.Fatal("Expected the first signal not to cancel the run context")
+	}
+
+	sigCh <- syscall.SIGTERM
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Expected a clean shutdown, got: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Shutdown didn't finish after the second signal")
+	}
+	if !canceled() {
+		t.Error("Expected the second signal to cancel the run context")
+	}
+	if n := store.CallCount("MarkInstanceStopped"); n != 1 {
+		t.Errorf("Expected the instance to be marked stopped, got %d calls", n)
+	}
+	checkInterruptedIssue(t, store, issue.ID)
+}
+
+// TestShutdownDeadline verifies that a graceful shutdown stops the
+// execution once the grace period has passed
+func TestShutdownDeadline(t *testing.T) {
+	store := &blockingStorage{FakeStorage: storagetest.NewFakeStorage(), entered: make(chan string, 1)}
+	issue := createShutdownTestIssue(t, store)
+	exec, cancelRun, canceled := startShutdownTestExecutor(t, store, 100*time.Millisecond)
+	select {
+	case <-store.entered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Executor didn't start executing the issue")
+	}
+
+	sigCh := make(chan os.Signal, 2)
+	sigCh <- os.Interrupt
+	select {
+	case err := <-shutdownOnSignal(exec, sigCh, cancelRun):
+		if err != nil {
+			t.Fatalf("Expected a clean shutdown, got: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Shutdown didn't finish after the deadline")
+	}
+	if !canceled() {
+		t.Error("Expected the deadline to cancel the run context")
+	}
+	checkInterruptedIssue(t, store, issue.ID)
+}
+
+// TestShutdownWaitIncludesAgentTimeout verifies that the shutdown deadline
+// leaves a running agent its remaining timeout on top of the grace period
+func TestShutdownWaitIncludesAgentTimeout(t *testing.T) {
+	exec := &Executor{shutdownGrace: 30 * time.Second}
+	if wait := exec.shutdownWait(); wait != 30*time.Second {
+		t.Errorf("Expected only the grace period without an agent, got %v", wait)
+	}
+
+	exec.setRunningAgent("vc-123", time.Now().Add(time.Minute))
+	if wait := exec.shutdownWait(); wait <= 85*time.Second || wait > 90*time.Second {
+		t.Errorf("Expected about 90s with a minute of agent time left, got %v", wait)
+	}
+
+	// An agent past its timeout gets nothing extra
+	exec.setRunningAgent("vc-123", time.Now().Add(-time.Minute))
+	if wait := exec.shutdownWait(); wait != 30*time.Second {
+		t.Errorf("Expected only the grace period for an overdue agent, got %v", wait)
+	}
+}
+
+// slowRegisterStorage holds RegisterInstance until release is closed, then
+// fails it with err (nil = succeed)
+type slowRegisterStorage struct {
+	*storagetest.FakeStorage
+	entered chan struct{}
+	release chan struct{}
+	err     error
+}
+
+func (s *slowRegisterStorage) RegisterInstance(ctx context.Context, instance *types.ExecutorInstance) error {
+	close(s.entered)
+	<-s.release
+	if s.err != nil {
+		return s.err
+	}
+	return s.FakeStorage.RegisterInstance(ctx, instance)
+}
+
+// newLifecycleTestExecutor creates an idle executor on store
+func newLifecycleTestExecutor(t *testing.T, store storage.Storage) *Executor {
+	t.Helper()
+	execCfg := DefaultConfig()
+	execCfg.Store = store
+	execCfg.EnableAISupervision = false
+	execCfg.EnableQualityGates = false
+	execCfg.EnableSandboxes = false
+	execCfg.PollInterval = 10 * time.Millisecond
+	exec, err := New(execCfg)
+	if err != nil {
+		t.Fatalf("Failed to create executor: %v", err)
+	}
+	return exec
+}
+
+// TestStopConcurrent verifies that concurrent Stop calls all succeed and
+// stop the executor once
+func TestStopConcurrent(t *testing.T) {
+	store := storagetest.NewFakeStorage()
+	exec := newLifecycleTestExecutor(t, store)
+	if err := exec.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start executor: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			errs <- exec.Stop(ctx)
+		}()
+		go func() {
+			defer wg.Done()
+			_ = exec.IsRunning()
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Expected every Stop to succeed, got: %v", err)
+		}
+	}
+	if exec.IsRunning() {
+		t.Error("Expected the executor to be stopped")
+	}
+	if n := store.CallCount("MarkInstanceStopped"); n != 1 {
+		t.Errorf("Expected the instance to be marked stopped once, got %d", n)
+	}
+}
+
+// TestStopIdempotent verifies that Stop before Start fails, Stop after Stop
+// succeeds, and a stopped executor can't be started again
+func TestStopIdempotent(t *testing.T) {
+	ctx := context.Background()
+	exec := newLifecycleTestExecutor(t, storagetest.NewFakeStorage())
+	if err := exec.Stop(ctx); err == nil {
+		t.Error("Expected Stop before Start to fail")
+	}
+
+	if err := exec.Start(ctx); err != nil {
+		t.Fatalf("Failed to start executor: %v", err)
+	}
+	if err := exec.Start(ctx); err == nil {
+		t.Error("Expected a second Start to fail")
+	}
+	for i := 0; i < 2; i++ {
+		if err := exec.Stop(ctx); err != nil {
+			t.Errorf("Stop %d failed: %v", i+1, err)
+		}
+	}
+	if err := exec.Start(ctx); err == nil {
+		t.Error("Expected Start after Stop to fail")
+	}
+}
+
+// TestStopRetryAfterTimeout verifies that a Stop that gave up waiting can
+// be called again to finish the shutdown
+func TestStopRetryAfterTimeout(t *testing.T) {
+	store := &blockingStorage{FakeStorage: storagetest.NewFakeStorage(), entered: make(chan string, 1)}
+	createShutdownTestIssue(t, store)
+	exec, cancelRun, _ := startShutdownTestExecutor(t, store, time.Minute)
+	select {
+	case <-store.entered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Executor didn't start executing the issue")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := exec.Stop(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected Stop to give up while the issue runs, got: %v", err)
+	}
+	if !exec.IsRunning() {
+		t.Error("Expected the executor to still be running")
+	}
+
+	cancelRun()
+	if err := exec.Stop(context.Background()); err != nil {
+		t.Fatalf("Expected the second Stop to finish the shutdown, got: %v", err)
+	}
+	if exec.IsRunning() {
+		t.Error("Expected the executor to be stopped")
+	}
+}
+
+// TestStopDuringStart verifies that Stop called while Start is registering
+// waits for it, and then stops the executor or reports that it never ran
+func TestStopDuringStart(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		startErr  error
+		wantError bool
+	}{
+		{name: "start succeeds"},
+		{name: "start fails", startErr: errors.New("database is locked"), wantError: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &slowRegisterStorage{
+				FakeStorage: storagetest.NewFakeStorage(),
+				entered:     make(chan struct{}),
+				release:     make(chan struct{}),
+				err:         tt.startErr,
+			}
+			exec := newLifecycleTestExecutor(t, store)
+			started := make(chan error, 1)
+			go func() { started <- exec.Start(context.Background()) }()
+			<-store.entered
+
+			stopped := make(chan error, 1)
+			go func() { stopped <- exec.Stop(context.Background()) }()
+			select {
+			case err := <-stopped:
+				t.Fatalf("Expected Stop to wait for Start, got: %v", err)
+			case <-time.After(50 * time.Millisecond):
+			}
+
+			close(store.release)
+			if err := <-started; (err != nil) != tt.wantError {
+				t.Fatalf("Unexpected Start result: %v", err)
+			}
+			select {
+			case err := <-stopped:
+				if (err != nil) != tt.wantError {
+					t.Errorf("Unexpected Stop result: %v", err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("Stop didn't return after Start")
+			}
+			if exec.IsRunning() {
+				t.Error("Expected the executor not to be running")
+			}
+		})
+	}
+}
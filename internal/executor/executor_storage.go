@@ -0,0 +1,118 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// storageCall runs a hot-path storage call (heartbeat, claim, state update,
+// event) under Config.StorageCallTimeout, so a locked or slow database
+// stalls the executor for at most that long
+func (e *Executor) storageCall(ctx context.Context, op, issueID string, call func(ctx context.Context) error) error {
+	return e.storageCallWithin(ctx, e.storageCallTimeout, op, issueID, call)
+}
+
+// storageCallWithin runs call under timeout (0 = none). A call that runs
+// out of time is counted and recorded as a storage_timeout event; one cut
+// short because ctx itself ended (shutdown) is not.
+func (e *Executor) storageCallWithin(ctx context.Context, timeout time.Duration, op, issueID string, call func(ctx context.Context) error) error {
+	if timeout <= 0 {
+		return call(ctx)
+	}
+	callCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := call(callCtx)
+	if err == nil || ctx.Err() != nil || !errors.Is(callCtx.Err(), context.DeadlineExceeded) {
+		return err
+	}
+	total := e.storageTimeouts.Add(1)
+	// Written in the background: the store is slow right now, and the
+	// caller (the heartbeat above all) shouldn't wait on it a second time
+	go e.recordStorageTimeout(ctx, timeout, op, issueID, total)
+	return fmt.Errorf("%s timed out after %v: %w", op, timeout, err)
+}
+
+// recordStorageTimeout stores a storage_timeout SYSTEM event. It writes to
+// the store directly rather than through logEvent, so a timed-out event
+// write can't cascade into more of them.
+func (e *Executor) recordStorageTimeout(ctx context.Context, timeout time.Duration, op, issueID string, total int64) {
+	event := &events.AgentEvent{
+		ID:         uuid.New().String(),
+		Type:       events.EventTypeStorageTimeout,
+		Timestamp:  time.Now(),
+		IssueID:    types.SystemIssueID, // System-level event
+		ExecutorID: e.instanceID,
+		AgentID:    "", // Not produced by a coding agent
+		Severity:   events.SeverityWarning,
+		Message:    fmt.Sprintf("Storage call %s timed out after %v", op, timeout),
+		Data: map[string]interface{}{
+			"operation":  op,
+			"issue_id":   issueID,
+			"timeout_ms": timeout.Milliseconds(),
+			"timeouts":   total,
+		},
+		SourceLine: 0, // Not applicable for executor-level events
+	}
+
+	writeCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	// A database that stays locked times out every call; repeats are held
+	// back (see event_coalescer.go)
+	for _, event := range e.coalescer.add(event) {
+		if err := e.store.StoreAgentEvent(writeCtx, event); err != nil && ctx.Err() == nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to store storage timeout event: %v\n", err)
+		}
+	}
+}
+
+// heartbeatLoop updates this instance's heartbeat every heartbeat period.
+// It runs apart from the event loop, which blocks for as long as an issue
+// executes, and stops once the event loop has finished (doneCh), so the
+// claim of an issue a graceful shutdown waits for stays fresh. It also
+// reopens a database that keeps failing (see executor_storage_health.go).
+func (e *Executor) heartbeatLoop(ctx context.Context) {
+	defer close(e.heartbeatDoneCh)
+
+	ticker := time.NewTicker(e.heartbeatPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-e.doneCh:
+			return
+		case <-ticker.C:
+			// A degraded executor's heartbeat is the reopen retry
+			if e.StorageDegraded() {
+				e.retryStorageReopen(ctx)
+				continue
+			}
+			e.sendHeartbeat(ctx)
+			e.checkStorageHealth(ctx)
+			e.flushCoalescedEvents(ctx, false)
+		}
+	}
+}
+
+// sendHeartbeat updates the heartbeat once. The call never outlives the
+// heartbeat period, so a slow store misses at most one heartbeat before the
+// next attempt.
+func (e *Executor) sendHeartbeat(ctx context.Context) {
+	timeout := min(e.storageCallTimeout, e.heartbeatPeriod)
+	err := e.storageCallWithin(ctx, timeout, "UpdateHeartbeat", "", func(ctx context.Context) error {
+		return e.store.UpdateHeartbeat(ctx, e.instanceID)
+	})
+	e.noteStorageResult(ctx, err)
+	if err != nil && ctx.Err() == nil {
+		fmt.Fprintf(os.Stderr, "failed to update heartbeat: %v\n", err)
+	}
+}
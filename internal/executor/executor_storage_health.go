@@ -0,0 +1,166 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// maxStorageReopenBackoff caps the wait between reopen attempts while the
+// database is unavailable
+const maxStorageReopenBackoff = 5 * time.Minute
+
+// storageHealth tracks whether the database keeps failing. The heartbeat
+// and event loops record results; only the heartbeat loop reopens the store
+// and enters or leaves degraded mode.
+type storageHealth struct {
+	mu         sync.Mutex
+	failures   int       // Consecutive failed heartbeat, ready-work and claim calls
+	lastErr    error     // The most recent failure
+	degraded   bool      // Reopening failed: claim nothing until it succeeds
+	since      time.Time // When degraded mode started
+	attempts   int       // Reopen attempts since then
+	backoff    time.Duration
+	nextReopen time.Time
+}
+
+// StorageDegraded reports whether the database kept failing and couldn't
+// be reopened. A degraded executor claims no work and retries the reopen
+// with backoff; it resumes by itself once the database is back.
+func (e *Executor) StorageDegraded() bool {
+	e.storageHealth.mu.Lock()
+	defer e.storageHealth.mu.Unlock()
+	return e.storageHealth.degraded
+}
+
+// noteStorageResult records the outcome of a heartbeat, ready-work or claim
+// call. Calls cut short by shutdown don't count.
+func (e *Executor) noteStorageResult(ctx context.Context, err error) {
+	if ctx.Err() != nil {
+		return
+	}
+	h := &e.storageHealth
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if err == nil {
+		h.failures = 0
+		return
+	}
+	h.failures++
+	h.lastErr = err
+}
+
+// checkStorageHealth reopens the store once failures reach the threshold
+// (0 = never), and enters degraded mode when that doesn't bring the
+// database back
+func (e *Executor) checkStorageHealth(ctx context.Context) {
+	h := &e.storageHealth
+	h.mu.Lock()
+	failures, lastErr := h.failures, h.lastErr
+	h.mu.Unlock()
+	if e.storageFailureThreshold <= 0 || failures < e.storageFailureThreshold || ctx.Err() != nil {
+		return
+	}
+
+	reopenErr := e.reopenStore(ctx)
+	if ctx.Err() != nil {
+		return
+	}
+	if reopenErr == nil {
+		h.mu.Lock()
+		h.failures = 0
+		h.mu.Unlock()
+		fmt.Printf("Storage: reopened the database after %d consecutive failures\n", failures)
+		e.logEvent(ctx, events.EventTypeStorageRecovered, events.SeverityInfo, types.SystemIssueID,
+			fmt.Sprintf("Reopened the database after %d consecutive failures", failures),
+			map[string]interface{}{
+				"failures":        failures,
+				"error":           lastErr.Error(),
+				"degraded":        false,
+				"reopen_attempts": 1,
+			})
+		return
+	}
+
+	now := time.Now()
+	h.mu.Lock()
+	h.degraded = true
+	h.since = now
+	h.attempts = 1
+	h.backoff = e.heartbeatPeriod
+	h.nextReopen = now.Add(h.backoff)
+	h.lastErr = reopenErr
+	h.mu.Unlock()
+
+	// Said once; the retries stay quiet until the database is back
+	fmt.Fprintf(os.Stderr, "\n⚠️  DATABASE UNAVAILABLE: %d consecutive storage failures (last: %v)\n", failures, lastErr)
+	fmt.Fprintf(os.Stderr, "   Reopening it failed: %v\n", reopenErr)
+	fmt.Fprintf(os.Stderr, "   No new work is claimed; reopening is retried with backoff and work resumes once it succeeds.\n\n")
+	e.logEvent(ctx, events.EventTypeStorageDegraded, events.SeverityError, types.SystemIssueID,
+		fmt.Sprintf("Database unavailable after %d consecutive failures; claiming stopped until it can be reopened", failures),
+		map[string]interface{}{
+			"failures":     failures,
+			"error":        lastErr.Error(),
+			"reopen_error": reopenErr.Error(),
+		})
+}
+
+// retryStorageReopen tries to reopen the store of a degraded executor once
+// its backoff has passed, and leaves degraded mode when that works
+func (e *Executor) retryStorageReopen(ctx context.Context) {
+	h := &e.storageHealth
+	h.mu.Lock()
+	due := !time.Now().Before(h.nextReopen)
+	h.mu.Unlock()
+	if !due {
+		return
+	}
+
+	err := e.reopenStore(ctx)
+	if ctx.Err() != nil {
+		return
+	}
+	h.mu.Lock()
+	if err != nil {
+		h.attempts++
+		h.backoff = min(2*h.backoff, maxStorageReopenBackoff)
+		h.nextReopen = time.Now().Add(h.backoff)
+		h.lastErr = err
+		h.mu.Unlock()
+		return
+	}
+	since, attempts, lastErr := h.since, h.attempts+1, h.lastErr
+	h.degraded = false
+	h.failures = 0
+	h.mu.Unlock()
+
+	down := time.Since(since).Round(time.Second)
+	fmt.Printf("Storage: database is back after %v (%d reopen attempts); resuming work\n", down, attempts)
+	e.logEvent(ctx, events.EventTypeStorageRecovered, events.SeverityInfo, types.SystemIssueID,
+		fmt.Sprintf("Database is back after %v unavailable; work resumed", down),
+		map[string]interface{}{
+			"error":           lastErr.Error(),
+			"degraded":        true,
+			"degraded_ms":     time.Since(since).Milliseconds(),
+			"reopen_attempts": attempts,
+		})
+}
+
+// reopenStore reopens the database and registers this instance again. A
+// reopen alone succeeds on any readable file; the write shows the database
+// works, and puts the instance back after a restore from an older backup.
+func (e *Executor) reopenStore(ctx context.Context) error {
+	if err := e.storageCall(ctx, "Reopen", "", func(ctx context.Context) error {
+		return e.store.Reopen(ctx)
+	}); err != nil {
+		return err
+	}
+	return e.storageCall(ctx, "RegisterInstance", "", func(ctx context.Context) error {
+		return e.store.RegisterInstance(ctx, e.instanceRecord())
+	})
+}
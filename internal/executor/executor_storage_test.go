@@ -0,0 +1,382 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage/storagetest"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// slowStorage delays heartbeats and claims like a locked database would,
+// giving up early when the caller's context ends
+type slowStorage struct {
+	*storagetest.FakeStorage
+	delay time.Duration
+
+	mu         sync.Mutex
+	heartbeats []time.Time // When each UpdateHeartbeat call started
+}
+
+func (s *slowStorage) sleep(ctx context.Context) error {
+	select {
+	case <-time.After(s.delay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *slowStorage) UpdateHeartbeat(ctx context.Context, instanceID string) error {
+	s.mu.Lock()
+	s.heartbeats = append(s.heartbeats, time.Now())
+	s.mu.Unlock()
+	if err := s.sleep(ctx); err != nil {
+		return err
+	}
+	return s.FakeStorage.UpdateHeartbeat(ctx, instanceID)
+}
+
+func (s *slowStorage) ClaimIssue(ctx context.Context, issueID, executorInstanceID string) error {
+	if err := s.sleep(ctx); err != nil {
+		return err
+	}
+	return s.FakeStorage.ClaimIssue(ctx, issueID, executorInstanceID)
+}
+
+// waitForStorageTimeoutEvents polls for the storage_timeout events written
+// in the background
+func waitForStorageTimeoutEvents(t *testing.T, store *storagetest.FakeStorage, want int) []*events.AgentEvent {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		found, err := store.GetAgentEvents(context.Background(), events.EventFilter{Type: events.EventTypeStorageTimeout})
+		if err != nil {
+			t.Fatalf("Failed to get events: %v", err)
+		}
+		if len(found) >= want || time.Now().After(deadline) {
+			return found
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// TestClaimIssueTimeout verifies that a claim stuck on a slow store gives up
+// after StorageCallTimeout, and is counted and recorded as a warning
+func TestClaimIssueTimeout(t *testing.T) {
+	ctx := context.Background()
+	fake := storagetest.NewFakeStorage()
+	issue := &types.Issue{Title: "Ready", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	if err := fake.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("Failed to create issue: %v", err)
+	}
+	store := &slowStorage{FakeStorage: fake, delay: 5 * time.Second}
+
+	execCfg := DefaultConfig()
+	execCfg.Store = store
+	execCfg.EnableAISupervision = false
+	execCfg.StorageCallTimeout = 50 * time.Millisecond
+	exec, err := New(execCfg)
+	if err != nil {
+		t.Fatalf("Failed to create executor: %v", err)
+	}
+
+	start := time.Now()
+	err = exec.processNextIssue(ctx)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the claim to give up after the timeout, took %v", elapsed)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected a deadline error, got: %v", err)
+	}
+	if n := exec.StorageTimeouts(); n != 1 {
+		t.Errorf("Expected 1 storage timeout, got %d", n)
+	}
+	if n := exec.ClaimConflicts(); n != 0 {
+		t.Errorf("Expected a timeout not to count as a claim conflict, got %d", n)
+	}
+
+	found := waitForStorageTimeoutEvents(t, fake, 1)
+	if len(found) != 1 {
+		t.Fatalf("Expected 1 storage_timeout event, got %d", len(found))
+	}
+	event := found[0]
+	if event.IssueID != types.SystemIssueID || event.Severity != events.SeverityWarning {
+		t.Errorf("Expected a SYSTEM warning, got issue %s severity %s", event.IssueID, event.Severity)
+	}
+	if event.Data["operation"] != "ClaimIssue" || event.Data["issue_id"] != issue.ID {
+		t.Errorf("Expected the event to name the call and issue, got %v", event.Data)
+	}
+}
+
+// TestStorageCallCanceled verifies that a call cut short by shutdown isn't
+// reported as a timeout
+func TestStorageCallCanceled(t *testing.T) {
+	fake := storagetest.NewFakeStorage()
+	store := &slowStorage{FakeStorage: fake, delay: 5 * time.Second}
+	exec := &Executor{store: store, instanceID: "exec-1", storageCallTimeout: time.Second}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	err := exec.storageCall(ctx, "ClaimIssue", "vc-1", func(ctx context.Context) error {
+		return store.ClaimIssue(ctx, "vc-1", "exec-1")
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the cancellation to propagate, got: %v", err)
+	}
+	if n := exec.StorageTimeouts(); n != 0 {
+		t.Errorf("Expected no storage timeouts, got %d", n)
+	}
+}
+
+// TestHeartbeatSlowStore verifies that a heartbeat on a store slower than
+// the heartbeat period gives up within that period, so the next heartbeat
+// isn't held up
+func TestHeartbeatSlowStore(t *testing.T) {
+	fake := storagetest.NewFakeStorage()
+	store := &slowStorage{FakeStorage: fake, delay: 5 * time.Second}
+	period := 50 * time.Millisecond
+	exec := &Executor{
+		store:              store,
+		instanceID:         "exec-1",
+		heartbeatPeriod:    period,
+		storageCallTimeout: 5 * time.Second, // Capped at the period for heartbeats
+	}
+
+	for i := 0; i < 3; i++ {
+		start := time.Now()
+		exec.sendHeartbeat(context.Background())
+		if elapsed := time.Since(start); elapsed > 3*period {
+			t.Errorf("Heartbeat %d took %v, want at most about %v", i, elapsed, period)
+		}
+	}
+
+	store.mu.Lock()
+	heartbeats := len(store.heartbeats)
+	store.mu.Unlock()
+	if heartbeats != 3 {
+		t.Fatalf("Expected 3 heartbeat attempts, got %d", heartbeats)
+	}
+	if n := exec.StorageTimeouts(); n != 3 {
+		t.Errorf("Expected every heartbeat to time out, got %d timeouts", n)
+	}
+	if found := waitForStorageTimeoutEvents(t, fake, 1); len(found) == 0 {
+		t.Error("Expected storage_timeout events for the heartbeats")
+	}
+}
+
+// TestStorageReopenAndDegradedMode verifies that a database that keeps
+// failing is reopened once, that an executor whose reopen fails stops
+// claiming and retries with backoff, and that it resumes once the database
+// is back
+func TestStorageReopenAndDegradedMode(t *testing.T) {
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+	period := 50 * time.Millisecond
+	exec := &Executor{store: store, instanceID: "exec-1", hostname: "host", pid: 1, version: "test", heartbeatPeriod: period, storageFailureThreshold: 3}
+	exec.startedAt = time.Now()
+	if err := store.RegisterInstance(ctx, exec.instanceRecord()); err != nil {
+		t.Fatalf("Failed to register instance: %v", err)
+	}
+	heartbeat := func(times int) {
+		for i := 0; i < times; i++ {
+			exec.sendHeartbeat(ctx)
+			exec.checkStorageHealth(ctx)
+		}
+	}
+	storageEvents := func(eventType events.EventType) []*events.AgentEvent {
+		found, err := store.GetAgentEvents(ctx, events.EventFilter{Type: eventType})
+		if err != nil {
+			t.Fatalf("Failed to get events: %v", err)
+		}
+		return found
+	}
+
+	// Failures below the threshold, or interrupted by a success, do nothing
+	ioErr := errors.New("disk I/O error")
+	store.FailOn("UpdateHeartbeat", ioErr)
+	heartbeat(2)
+	store.FailOn("UpdateHeartbeat", nil)
+	heartbeat(1)
+	store.FailOn("UpdateHeartbeat", ioErr)
+	heartbeat(2)
+	if n := store.CallCount("Reopen"); n != 0 {
+		t.Fatalf("Expected no reopen below the threshold, got %d", n)
+	}
+
+	// The third failure in a row reopens the database, which helps
+	heartbeat(1)
+	if n := store.CallCount("Reopen"); n != 1 {
+		t.Fatalf("Expected 1 reopen, got %d", n)
+	}
+	if exec.StorageDegraded() {
+		t.Fatal("Expected a successful reopen not to degrade the executor")
+	}
+	if found := storageEvents(events.EventTypeStorageRecovered); len(found) != 1 || found[0].Data["degraded"] != false {
+		t.Fatalf("Expected 1 storage_recovered event without degraded mode, got %v", found)
+	}
+
+	// This time reopening fails too
+	store.FailOn("Reopen", ioErr)
+	heartbeat(3)
+	if !exec.StorageDegraded() {
+		t.Fatal("Expected the executor to be degraded after reopening failed")
+	}
+	degraded := storageEvents(events.EventTypeStorageDegraded)
+	if len(degraded) != 1 || degraded[0].IssueID != types.SystemIssueID || degraded[0].Severity != events.SeverityError {
+		t.Fatalf("Expected 1 SYSTEM storage_degraded error event, got %v", degraded)
+	}
+
+	store.ResetCalls()
+	// Retries wait out the backoff, which doubles after each failure
+	exec.retryStorageReopen(ctx)
+	if n := store.CallCount("Reopen"); n != 0 {
+		t.Errorf("Expected no retry before the backoff passed, got %d", n)
+	}
+	time.Sleep(period)
+	exec.retryStorageReopen(ctx)
+	if n := store.CallCount("Reopen"); n != 1 {
+		t.Errorf("Expected 1 retry after the backoff, got %d", n)
+	}
+	if backoff := exec.storageHealth.backoff; backoff != 2*period {
+		t.Errorf("Expected the backoff to double to %v, got %v", 2*period, backoff)
+	}
+
+	// A degraded executor claims nothing
+	store.ResetCalls()
+	exec.stopCh, exec.doneCh, exec.pollInterval = make(chan struct{}), make(chan struct{}), time.Millisecond
+	go exec.eventLoop(ctx)
+	time.Sleep(10 * time.Millisecond)
+	close(exec.stopCh)
+	<-exec.doneCh
+	for _, method := range []string{"GetReadyBlockers", "GetReadyWork", "ClaimIssue"} {
+		if n := store.CallCount(method); n != 0 {
+			t.Errorf("Expected no %s calls while degraded, got %d", method, n)
+		}
+	}
+
+	// The database comes back: the instance is registered again and work resumes
+	store.FailOn("Reopen", nil)
+	store.FailOn("UpdateHeartbeat", nil)
+	time.Sleep(2 * period)
+	exec.retryStorageReopen(ctx)
+	if exec.StorageDegraded() {
+		t.Fatal("Expected the executor to recover once reopening works")
+	}
+	if n := store.CallCount("RegisterInstance"); n != 1 {
+		t.Errorf("Expected the instance to be registered again, got %d registrations", n)
+	}
+	var recovered *events.AgentEvent
+	for _, event := range storageEvents(events.EventTypeStorageRecovered) {
+		if event.Data["degraded"] == true {
+			recovered = event
+		}
+	}
+	if recovered == nil {
+		t.Fatal("Expected a storage_recovered event after degraded mode")
+	}
+	if attempts := fmt.Sprint(recovered.Data["reopen_attempts"]); attempts != "3" {
+		t.Errorf("Expected 3 reopen attempts, got %v", attempts)
+	}
+}
+
+// TestHeartbeatDuringLongExecution verifies that heartbeats go on while an
+// agent runs for longer than the stale threshold, so another executor's
+// stale-instance cleanup doesn't steal the claim
+func TestHeartbeatDuringLongExecution(t *testing.T) {
+	ctx := context.Background()
+	store := storagetest.TempFile(t)
+	defer func() { _ = store.Close() }()
+	issue := &types.Issue{Title: "Long task", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("Failed to create issue: %v", err)
+	}
+
+	// A slow agent: it says when it starts, then outlasts the stale threshold
+	binDir := t.TempDir()
+	started := filepath.Join(binDir, "started")
+	script := "#!/bin/sh\ntouch " + started + "\nsleep 5\nexit 1\n"
+	if err := os.WriteFile(filepath.Join(binDir, "amp"), []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to write fake agent: %v", err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	execCfg := DefaultConfig()
+	execCfg.Store = store
+	execCfg.EnableAISupervision = false
+	execCfg.EnableQualityGates = false
+	execCfg.EnableSandboxes = false
+	execCfg.PollInterval = 10 * time.Millisecond
+	execCfg.HeartbeatPeriod = 100 * time.Millisecond
+	exec, err := New(execCfg)
+	if err != nil {
+		t.Fatalf("Failed to create executor: %v", err)
+	}
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if err := exec.Start(runCtx); err != nil {
+		t.Fatalf("Failed to start executor: %v", err)
+	}
+	defer func() {
+		cancel() // Kills the agent rather than waiting it out
+		_ = exec.Stop(ctx)
+	}()
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		if _, err := os.Stat(started); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Executor didn't start the agent")
+		}
+	}
+
+	// Another executor cleans up instances without a heartbeat for 1s,
+	// while the agent runs for well over that
+	const staleThreshold = 1 // seconds
+	var beats []time.Time
+	for deadline := time.Now().Add(2500 * time.Millisecond); time.Now().Before(deadline); time.Sleep(200 * time.Millisecond) {
+		cleaned, err := store.CleanupStaleInstances(ctx, staleThreshold)
+		if err != nil {
+			t.Fatalf("Failed to clean up stale instances: %v", err)
+		}
+		if cleaned != 0 {
+			t.Fatalf("Expected the executing instance not to look stale, %d cleaned up", cleaned)
+		}
+		instances, err := store.GetActiveInstances(ctx)
+		if err != nil {
+			t.Fatalf("Failed to get instances: %v", err)
+		}
+		for _, instance := range instances {
+			if instance.InstanceID == exec.instanceID {
+				if len(beats) == 0 || instance.LastHeartbeat.After(beats[len(beats)-1]) {
+					beats = append(beats, instance.LastHeartbeat)
+				}
+			}
+		}
+	}
+	if len(beats) < 3 {
+		t.Errorf("Expected the heartbeat to keep advancing while the agent ran, saw %v", beats)
+	}
+
+	state, err := store.GetExecutionState(ctx, issue.ID)
+	if err != nil || state == nil {
+		t.Fatalf("Failed to get execution state: %v", err)
+	}
+	if state.ExecutorInstanceID != exec.instanceID || state.State != types.ExecutionStateExecuting {
+		t.Errorf("Expected %s to keep executing the issue, got %q in state %s", exec.instanceID, state.ExecutorInstanceID, state.State)
+	}
+	got, err := store.GetIssue(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("Failed to get issue: %v", err)
+	}
+	if got.Status != types.StatusInProgress {
+		t.Errorf("Expected the issue to stay in progress, got %s", got.Status)
+	}
+}
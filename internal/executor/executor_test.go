@@ -3,11 +3,13 @@ package executor
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/storage/storagetest"
 	"github.com/steveyegge/vc/internal/types"
 )
 
@@ -425,8 +427,8 @@ func TestExecutorDoubleClaim(t *testing.T) {
 	if err == nil {
 		t.Fatal("Executor 2 should not be able to claim already-claimed issue")
 	}
-	if !strings.Contains(err.Error(), "already claimed") {
-		t.Errorf("Expected 'already claimed' error, got: %v", err)
+	if !errors.Is(err, types.ErrAlreadyClaimed) {
+		t.Errorf("Expected ErrAlreadyClaimed, got: %v", err)
 	}
 
 	// Verify executor 1 still has the claim
@@ -439,6 +441,87 @@ func TestExecutorDoubleClaim(t *testing.T) {
 	}
 }
 
+// TestProcessNextIssueClaimConflict verifies that losing a claim to another
+// executor is counted and skipped, while a real storage failure is reported
+func TestProcessNextIssueClaimConflict(t *testing.T) {
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+	if err := store.CreateIssue(ctx, &types.Issue{Title: "Ready", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}, "test"); err != nil {
+		t.Fatalf("Failed to create issue: %v", err)
+	}
+
+	execCfg := DefaultConfig()
+	execCfg.Store = store
+	execCfg.EnableAISupervision = false
+	exec, err := New(execCfg)
+	if err != nil {
+		t.Fatalf("Failed to create executor: %v", err)
+	}
+
+	store.FailOn("ClaimIssue", &types.ClaimConflictError{IssueID: "vc-1", ClaimedBy: "other-executor"})
+	if err := exec.processNextIssue(ctx); err != nil {
+		t.Errorf("Expected a lost claim to be skipped, got: %v", err)
+	}
+	if n := exec.ClaimConflicts(); n != 1 {
+		t.Errorf("Expected 1 claim conflict, got %d", n)
+	}
+
+	diskFull := errors.New("database or disk is full")
+	store.FailOn("ClaimIssue", diskFull)
+	if err := exec.processNextIssue(ctx); !errors.Is(err, diskFull) {
+		t.Errorf("Expected the storage error to propagate, got: %v", err)
+	}
+	if n := exec.ClaimConflicts(); n != 1 {
+		t.Errorf("Expected a storage error not to count as a conflict, got %d conflicts", n)
+	}
+}
+
+// closingClaimStorage closes each issue just before claiming it, like
+// another actor closing it between the ready-work query and the claim
+type closingClaimStorage struct {
+	*storagetest.FakeStorage
+}
+
+func (s *closingClaimStorage) ClaimIssue(ctx context.Context, issueID, executorInstanceID string) error {
+	if err := s.CloseIssue(ctx, issueID, "closed elsewhere", "test"); err != nil {
+		return err
+	}
+	return s.FakeStorage.ClaimIssue(ctx, issueID, executorInstanceID)
+}
+
+// TestProcessNextIssueClaimNotOpen verifies that an issue closed before it
+// could be claimed is skipped as a lost race rather than reported as an error
+func TestProcessNextIssueClaimNotOpen(t *testing.T) {
+	ctx := context.Background()
+	store := &closingClaimStorage{FakeStorage: storagetest.NewFakeStorage()}
+	issue := &types.Issue{Title: "Ready", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("Failed to create issue: %v", err)
+	}
+
+	execCfg := DefaultConfig()
+	execCfg.Store = store
+	execCfg.EnableAISupervision = false
+	exec, err := New(execCfg)
+	if err != nil {
+		t.Fatalf("Failed to create executor: %v", err)
+	}
+
+	if err := exec.processNextIssue(ctx); err != nil {
+		t.Errorf("Expected a closed issue to be skipped, got: %v", err)
+	}
+	if n := exec.ClaimConflicts(); n != 0 {
+		t.Errorf("Expected no claim conflicts, got %d", n)
+	}
+	state, err := store.GetExecutionState(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetExecutionState: %v", err)
+	}
+	if state != nil {
+		t.Errorf("Expected no execution state for an unclaimed issue, got %+v", state)
+	}
+}
+
 // TestExecutorShutdownCleansOldInstances verifies that executor shutdown
 // triggers cleanup of old stopped instances (vc-31)
 func TestExecutorShutdownCleansOldInstances(t *testing.T) {
@@ -0,0 +1,157 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"time"
+
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// Vacuum modes recorded in vacuum events
+const (
+	vacuumModeFull        = "full"
+	vacuumModeIncremental = "incremental"
+)
+
+// vacuumDecision is what the vacuum policy decided for one cleanup cycle.
+// An empty mode means don't vacuum; reason says why either way.
+type vacuumDecision struct {
+	mode   string
+	reason string
+}
+
+// decideVacuum applies the vacuum policy: reclaim space only once free pages
+// make up cfg.VacuumFreeFraction of the database. Databases with
+// auto_vacuum=INCREMENTAL get the cheap incremental vacuum; otherwise a full
+// VACUUM runs at most once per cfg.VacuumMinIntervalHours (lastFull is the
+// last successful one, zero if none).
+func decideVacuum(stats *types.DatabaseStats, cfg config.EventRetentionConfig, lastFull, now time.Time) vacuumDecision {
+	if cfg.VacuumFreeFraction == 0 {
+		return vacuumDecision{reason: "automatic vacuum disabled"}
+	}
+
+	free := stats.FreeFraction()
+	if free < cfg.VacuumFreeFraction {
+		return vacuumDecision{reason: fmt.Sprintf("%.1f%% free, below the %.1f%% threshold",
+			free*100, cfg.VacuumFreeFraction*100)}
+	}
+	reason := fmt.Sprintf("%.1f%% free (%d of %d pages), threshold %.1f%%",
+		free*100, stats.FreelistCount, stats.PageCount, cfg.VacuumFreeFraction*100)
+
+	if stats.AutoVacuum == "incremental" {
+		return vacuumDecision{mode: vacuumModeIncremental, reason: reason}
+	}
+
+	minInterval := time.Duration(cfg.VacuumMinIntervalHours) * time.Hour
+	if !lastFull.IsZero() && now.Sub(lastFull) < minInterval {
+		return vacuumDecision{reason: fmt.Sprintf("%s, but the last VACUUM was %v ago (minimum interval %v)",
+			reason, now.Sub(lastFull).Round(time.Minute), minInterval)}
+	}
+	return vacuumDecision{mode: vacuumModeFull, reason: reason}
+}
+
+// lastFullVacuum returns when the last successful full VACUUM completed,
+// from the vacuum_completed events, or zero if there was none
+func (e *Executor) lastFullVacuum(ctx context.Context) (time.Time, error) {
+	recent, err := e.store.GetAgentEvents(ctx, events.EventFilter{
+		Type:     events.EventTypeVacuumCompleted,
+		BeforeID: math.MaxInt64, // newest first
+		Limit:    50,
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to load vacuum history: %w", err)
+	}
+	for _, event := range recent {
+		data, err := event.GetVacuumData()
+		if err != nil {
+			continue
+		}
+		if data.Mode == vacuumModeFull && data.Success {
+			return event.Timestamp, nil
+		}
+	}
+	return time.Time{}, nil
+}
+
+// maybeVacuum runs the vacuum the policy calls for, recording
+// vacuum_started and vacuum_completed SYSTEM events. It reports whether a
+// vacuum ran; failures are logged, not returned, so they never fail cleanup.
+func (e *Executor) maybeVacuum(ctx context.Context, cfg config.EventRetentionConfig) bool {
+	if cfg.VacuumFreeFraction == 0 {
+		return false
+	}
+
+	before, err := e.store.GetDatabaseStats(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "event cleanup: warning: failed to read database stats: %v\n", err)
+		return false
+	}
+	lastFull, err := e.lastFullVacuum(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "event cleanup: warning: %v\n", err)
+		return false
+	}
+
+	decision := decideVacuum(before, cfg, lastFull, time.Now())
+	if decision.mode == "" {
+		return false
+	}
+
+	data := events.VacuumData{
+		Mode:            decision.mode,
+		Reason:          decision.reason,
+		SizeBeforeBytes: before.SizeBytes,
+		FreePagesBefore: before.FreelistCount,
+	}
+	e.logVacuumEvent(ctx, events.EventTypeVacuumStarted, events.SeverityInfo,
+		fmt.Sprintf("Vacuum (%s) started: %s", decision.mode, decision.reason), data)
+
+	startTime := time.Now()
+	if decision.mode == vacuumModeIncremental {
+		err = e.store.IncrementalVacuum(ctx)
+	} else {
+		err = e.store.VacuumDatabase(ctx)
+	}
+	data.DurationMs = time.Since(startTime).Milliseconds()
+
+	if err != nil {
+		data.Error = err.Error()
+		e.logVacuumEvent(ctx, events.EventTypeVacuumCompleted, events.SeverityError,
+			fmt.Sprintf("Vacuum (%s) failed after %dms: %v", decision.mode, data.DurationMs, err), data)
+		fmt.Fprintf(os.Stderr, "event cleanup: warning: vacuum failed: %v\n", err)
+		return false
+	}
+
+	data.Success = true
+	if after, err := e.store.GetDatabaseStats(ctx); err == nil {
+		data.SizeAfterBytes = after.SizeBytes
+		data.FreePagesAfter = after.FreelistCount
+	}
+	e.logVacuumEvent(ctx, events.EventTypeVacuumCompleted, events.SeverityInfo,
+		fmt.Sprintf("Vacuum (%s) completed in %dms: %d -> %d bytes", decision.mode, data.DurationMs,
+			data.SizeBeforeBytes, data.SizeAfterBytes), data)
+	return true
+}
+
+// logVacuumEvent stores a vacuum event as a system-level event
+func (e *Executor) logVacuumEvent(ctx context.Context, eventType events.EventType, severity events.EventSeverity, message string, data events.VacuumData) {
+	// Skip logging if context is canceled (e.g., during shutdown)
+	if ctx.Err() != nil {
+		return
+	}
+
+	event, err := events.NewVacuumEvent(eventType, types.SystemIssueID, e.instanceID, "", severity, message, data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to create %s event: %v\n", eventType, err)
+		return
+	}
+	if err := e.store.StoreAgentEvent(ctx, event); err != nil {
+		// Log error but don't fail cleanup
+		fmt.Fprintf(os.Stderr, "warning: failed to store %s event: %v\n", eventType, err)
+	}
+}
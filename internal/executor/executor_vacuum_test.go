@@ -0,0 +1,162 @@
+package executor
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// TestDecideVacuum covers the vacuum policy: free-page threshold, minimum
+// interval between full vacuums, and incremental vacuum when enabled
+func TestDecideVacuum(t *testing.T) {
+	now := time.Now()
+	cfg := config.DefaultEventRetentionConfig() // 25% free, 168h interval
+
+	disabled := cfg
+	disabled.VacuumFreeFraction = 0
+
+	stats := func(free int64, autoVacuum string) *types.DatabaseStats {
+		return &types.DatabaseStats{PageSize: 4096, PageCount: 1000, FreelistCount: free, AutoVacuum: autoVacuum}
+	}
+
+	tests := []struct {
+		name     string
+		stats    *types.DatabaseStats
+		cfg      config.EventRetentionConfig
+		lastFull time.Time
+		wantMode string
+	}{
+		{"below threshold", stats(100, "none"), cfg, time.Time{}, ""},
+		{"above threshold, never vacuumed", stats(300, "none"), cfg, time.Time{}, vacuumModeFull},
+		{"above threshold, vacuumed recently", stats(300, "none"), cfg, now.Add(-24 * time.Hour), ""},
+		{"above threshold, interval passed", stats(300, "none"), cfg, now.Add(-8 * 24 * time.Hour), vacuumModeFull},
+		{"incremental ignores interval", stats(300, "incremental"), cfg, now.Add(-time.Hour), vacuumModeIncremental},
+		{"incremental below threshold", stats(100, "incremental"), cfg, time.Time{}, ""},
+		{"disabled", stats(900, "none"), disabled, time.Time{}, ""},
+		{"empty database", stats(0, "none"), cfg, time.Time{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := decideVacuum(tt.stats, tt.cfg, tt.lastFull, now)
+			if got.mode != tt.wantMode {
+				t.Errorf("expected mode %q, got %q (%s)", tt.wantMode, got.mode, got.reason)
+			}
+			if got.reason == "" {
+				t.Error("expected a reason")
+			}
+		})
+	}
+}
+
+// TestMaybeVacuumRecordsEvents verifies a full vacuum runs once free pages
+// pass the threshold, records started/completed events with before/after
+// sizes, and isn't repeated within the minimum interval
+func TestMaybeVacuumRecordsEvents(t *testing.T) {
+	ctx := context.Background()
+
+	cfg := storage.DefaultConfig()
+	cfg.Path = filepath.Join(t.TempDir(), "vc.db")
+	store, err := storage.NewStorage(ctx, cfg)
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	execCfg := DefaultConfig()
+	execCfg.Store = store
+	execCfg.EnableAISupervision = false
+	execCfg.EnableQualityGates = false
+	execCfg.EnableSandboxes = false
+
+	executor, err := New(execCfg)
+	if err != nil {
+		t.Fatalf("failed to create executor: %v", err)
+	}
+	createSystemIssue(ctx, t, store)
+
+	issue := &types.Issue{Title: "Noisy issue", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("failed to create issue: %v", err)
+	}
+
+	// makeFreePages stores a batch of large, old events and cleans them up
+	// so the freelist dominates the file
+	makeFreePages := func() {
+		t.Helper()
+		payload := strings.Repeat("x", 4000)
+		for i := 0; i < 300; i++ {
+			event := &events.AgentEvent{
+				Type:      events.EventTypeProgress,
+				Timestamp: time.Now().Add(-10 * 24 * time.Hour),
+				IssueID:   issue.ID,
+				Severity:  events.SeverityInfo,
+				Message:   payload,
+			}
+			if err := store.StoreAgentEvent(ctx, event); err != nil {
+				t.Fatalf("failed to store event: %v", err)
+			}
+		}
+		if _, err := store.CleanupEventsByAge(ctx, 1, 1, 1000); err != nil {
+			t.Fatalf("failed to clean up events: %v", err)
+		}
+	}
+
+	retentionCfg := config.DefaultEventRetentionConfig()
+
+	// Nothing to reclaim yet
+	if executor.maybeVacuum(ctx, retentionCfg) {
+		t.Error("expected no vacuum on a compact database")
+	}
+
+	makeFreePages()
+	if !executor.maybeVacuum(ctx, retentionCfg) {
+		t.Fatal("expected a full vacuum once free pages pass the threshold")
+	}
+
+	completed, err := store.GetAgentEvents(ctx, events.EventFilter{Type: events.EventTypeVacuumCompleted})
+	if err != nil {
+		t.Fatalf("failed to get vacuum events: %v", err)
+	}
+	if len(completed) != 1 {
+		t.Fatalf("expected 1 vacuum_completed event, got %d", len(completed))
+	}
+	data, err := completed[0].GetVacuumData()
+	if err != nil {
+		t.Fatalf("failed to decode vacuum data: %v", err)
+	}
+	if data.Mode != vacuumModeFull || !data.Success {
+		t.Errorf("expected a successful full vacuum, got %+v", data)
+	}
+	if data.SizeAfterBytes >= data.SizeBeforeBytes {
+		t.Errorf("expected the database to shrink: %d -> %d bytes", data.SizeBeforeBytes, data.SizeAfterBytes)
+	}
+	if data.FreePagesBefore == 0 || data.FreePagesAfter != 0 {
+		t.Errorf("expected free pages to be released: %d -> %d", data.FreePagesBefore, data.FreePagesAfter)
+	}
+
+	started, err := store.GetAgentEvents(ctx, events.EventFilter{Type: events.EventTypeVacuumStarted})
+	if err != nil {
+		t.Fatalf("failed to get vacuum events: %v", err)
+	}
+	if len(started) != 1 {
+		t.Errorf("expected 1 vacuum_started event, got %d", len(started))
+	}
+
+	// Free pages again: the minimum interval holds the next full vacuum off
+	makeFreePages()
+	if executor.maybeVacuum(ctx, retentionCfg) {
+		t.Error("expected no second full vacuum within the minimum interval")
+	}
+
+	retentionCfg.VacuumMinIntervalHours = 0
+	if !executor.maybeVacuum(ctx, retentionCfg) {
+		t.Error("expected a full vacuum with no minimum interval")
+	}
+}
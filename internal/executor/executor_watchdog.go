@@ -5,8 +5,15 @@ import (
 	"fmt"
 	"os"
 	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/watchdog"
 )
 
+// telemetrySnapshotInterval is the minimum time between execution_telemetry
+// events for the active execution
+const telemetrySnapshotInterval = time.Minute
+
 // watchdogLoop runs the watchdog monitoring in a background goroutine
 // It periodically checks for anomalies and intervenes when necessary
 func (e *Executor) watchdogLoop(ctx context.Context) {
@@ -29,6 +36,9 @@ func (e *Executor) watchdogLoop(ctx context.Context) {
 			default:
 			}
 
+			// Persist what the watchdog sees so `vc telemetry` can show it
+			e.recordTelemetrySnapshot(ctx, time.Now())
+
 			// Run anomaly detection with cancellation support (vc-113)
 			// Use a channel to make check interruptible
 			done := make(chan error, 1)
@@ -52,8 +62,48 @@ func (e *Executor) watchdogLoop(ctx context.Context) {
 	}
 }
 
+// recordTelemetrySnapshot stores the active execution's telemetry as an
+// execution_telemetry event, at most once per telemetrySnapshotInterval.
+func (e *Executor) recordTelemetrySnapshot(ctx context.Context, now time.Time) {
+	if ctx.Err() != nil || now.Sub(e.lastTelemetrySnapshot) < telemetrySnapshotInterval {
+		return
+	}
+
+	current := e.monitor.GetCurrentExecution()
+	if current == nil {
+		return
+	}
+
+	data := current.Snapshot(now)
+	message := fmt.Sprintf("%s: %s for %s, %.1f events/min",
+		current.IssueID, data.State, now.Sub(data.StateSince).Round(time.Second), data.EventsPerMinute)
+	event, err := events.NewExecutionTelemetryEvent(current.IssueID, e.instanceID, "", events.SeverityInfo, message, data)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to create %s event: %v\n", events.EventTypeExecutionTelemetry, err)
+		return
+	}
+	if err := e.store.StoreAgentEvent(ctx, event); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to store %s event: %v\n", events.EventTypeExecutionTelemetry, err)
+		return
+	}
+	e.lastTelemetrySnapshot = now
+}
+
 // checkForAnomalies performs one cycle of anomaly detection and intervention
 func (e *Executor) checkForAnomalies(ctx context.Context) error {
+	// Nothing executing, nothing to check
+	if !e.monitor.HasActiveExecution() {
+		if os.Getenv("VC_DEBUG_WATCHDOG") != "" {
+			fmt.Fprintf(os.Stderr, "[DEBUG] Watchdog: skipping check, no active execution\n")
+		}
+		return nil
+	}
+
+	// Cheap heuristics first: stalled or looping agents don't need AI analysis
+	if detected, err := e.checkHeuristicAnomalies(ctx); err != nil || detected {
+		return err
+	}
+
 	// Skip if no analyzer (watchdog disabled)
 	if e.analyzer == nil {
 		return nil
@@ -70,8 +120,9 @@ func (e *Executor) checkForAnomalies(ctx context.Context) error {
 		return nil
 	}
 
-	// Check if this anomaly meets the threshold for intervention
-	if !e.watchdogConfig.ShouldIntervene(report) {
+	// Check if this anomaly meets the threshold for intervention, honoring
+	// watchdog:off / watchdog:relaxed labels on the executing issue
+	if !e.watchdogConfig.ShouldInterveneForMode(report, e.monitor.CurrentProtectionMode()) {
 		// Anomaly detected but below threshold - just log it
 		if e.watchdogConfig.AIConfig.EnableAnomalyLogging {
 			fmt.Printf("Watchdog: Anomaly detected but below threshold - type=%s, severity=%s, confidence=%.2f (threshold: confidence=%.2f, severity=%s)\n",
@@ -97,3 +148,78 @@ func (e *Executor) checkForAnomalies(ctx context.Context) error {
 
 	return nil
 }
+
+// checkHeuristicAnomalies runs the checks that need no AI call: an agent that
+// has been silent past the silence threshold (stalled) or one repeating the
+// same output (loop). Returns true if an anomaly was detected.
+func (e *Executor) checkHeuristicAnomalies(ctx context.Context) (bool, error) {
+	if e.intervention == nil || !e.intervention.HasActiveAgent() {
+		return false, nil
+	}
+
+	current := e.monitor.GetCurrentExecution()
+	report := e.watchdogConfig.CheckStall(current, time.Now())
+	if report != nil {
+		// Reset the silence clock so the same stall isn't reported on every tick
+		// while the cancellation propagates
+		e.monitor.RecordEvent(string(events.EventTypeWatchdog))
+	} else if report = e.watchdogConfig.CheckLoop(current, e.monitor.LoopStatus()); report != nil {
+		e.monitor.ResetLoopDetection()
+	}
+	if report == nil {
+		return false, nil
+	}
+
+	fmt.Printf("Watchdog: %s\n", report.Description)
+
+	result, err := e.intervention.Intervene(ctx, report)
+	if err != nil {
+		return true, fmt.Errorf("%s intervention failed: %w", report.AnomalyType, err)
+	}
+
+	data := map[string]interface{}{
+		"anomaly_type":      string(report.AnomalyType),
+		"intervention_type": string(result.InterventionType),
+		"policy_entry":      result.PolicyEntry,
+	}
+	for k, v := range report.Metrics {
+		data[k] = v
+	}
+	e.logEvent(ctx, events.EventTypeWatchdog, events.SeverityWarning, current.IssueID,
+		fmt.Sprintf("Agent %s: %s", report.AnomalyType, result.Message), data)
+
+	return true, nil
+}
+
+// applyWatchdogProtectionMode reads the issue's labels and records any reduced
+// watchdog protection (watchdog:off / watchdog:relaxed) in the execution
+// telemetry and as an event, so post-mortems show it was deliberate
+func (e *Executor) applyWatchdogProtectionMode(ctx context.Context, issueID string) {
+	labels, err := e.store.GetLabels(ctx, issueID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to read labels for %s, using normal watchdog protection: %v\n", issueID, err)
+		return
+	}
+
+	mode := watchdog.ProtectionModeFromLabels(labels)
+	if mode == watchdog.ProtectionNormal {
+		return
+	}
+
+	e.monitor.SetProtectionMode(mode)
+
+	data := map[string]interface{}{
+		"protection_mode": string(mode),
+		"label":           watchdog.ProtectionLabel(mode),
+	}
+	message := fmt.Sprintf("Watchdog anomaly checks disabled for %s (labeled %s)", issueID, watchdog.LabelWatchdogOff)
+	if mode == watchdog.ProtectionRelaxed {
+		data["threshold_multiplier"] = e.watchdogConfig.RelaxedMode.ThresholdMultiplier
+		data["min_severity_level"] = string(e.watchdogConfig.RelaxedMode.MinSeverityLevel)
+		message = fmt.Sprintf("Watchdog thresholds relaxed for %s (labeled %s)", issueID, watchdog.LabelWatchdogRelaxed)
+	}
+
+	fmt.Printf("Watchdog: %s\n", message)
+	e.logEvent(ctx, events.EventTypeWatchdogProtectionReduced, events.SeverityWarning, issueID, message, data)
+	e.monitor.RecordEvent(string(events.EventTypeWatchdogProtectionReduced))
+}
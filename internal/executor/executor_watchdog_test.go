@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/steveyegge/vc/internal/events"
 	"github.com/steveyegge/vc/internal/types"
 	"github.com/steveyegge/vc/internal/watchdog"
 )
@@ -454,3 +455,53 @@ func TestWatchdogIntegration_TelemetryCollection(t *testing.T) {
 
 	t.Log("✓ Telemetry collection test passed: all data recorded correctly")
 }
+
+// TestRecordTelemetrySnapshot verifies the active execution is persisted as an
+// execution_telemetry event, throttled to one per snapshot interval
+func TestRecordTelemetrySnapshot(t *testing.T) {
+	ctx := context.Background()
+	store := setupTestStorage(t, ctx)
+
+	exec, err := New(&Config{Store: store, Version: "test"})
+	if err != nil {
+		t.Fatalf("Failed to create executor: %v", err)
+	}
+
+	// Nothing executing: no snapshot
+	now := time.Now()
+	exec.recordTelemetrySnapshot(ctx, now)
+
+	exec.monitor.StartExecution("vc-telemetry", exec.instanceID)
+	exec.monitor.RecordEvent(string(events.EventTypeAgentToolUse))
+	exec.monitor.RecordStateTransition(types.ExecutionStateClaimed, types.ExecutionStateAssessing)
+	exec.recordTelemetrySnapshot(ctx, now)
+	exec.recordTelemetrySnapshot(ctx, now.Add(telemetrySnapshotInterval/2))
+
+	snapshots, err := store.GetAgentEvents(ctx, events.EventFilter{Type: events.EventTypeExecutionTelemetry})
+	if err != nil {
+		t.Fatalf("Failed to query events: %v", err)
+	}
+	if len(snapshots) != 1 {
+		t.Fatalf("Expected 1 telemetry snapshot, got %d", len(snapshots))
+	}
+
+	data, err := snapshots[0].GetExecutionTelemetryData()
+	if err != nil {
+		t.Fatalf("Failed to parse telemetry data: %v", err)
+	}
+	if snapshots[0].IssueID != "vc-telemetry" || data.State != string(types.ExecutionStateAssessing) {
+		t.Errorf("Unexpected snapshot: issue=%s state=%s", snapshots[0].IssueID, data.State)
+	}
+	if data.TotalEvents != 1 || len(data.Transitions) != 1 {
+		t.Errorf("Expected 1 event and 1 transition, got %d and %d", data.TotalEvents, len(data.Transitions))
+	}
+
+	exec.recordTelemetrySnapshot(ctx, now.Add(telemetrySnapshotInterval))
+	snapshots, err = store.GetAgentEvents(ctx, events.EventFilter{Type: events.EventTypeExecutionTelemetry})
+	if err != nil {
+		t.Fatalf("Failed to query events: %v", err)
+	}
+	if len(snapshots) != 2 {
+		t.Errorf("Expected a second snapshot after the interval, got %d", len(snapshots))
+	}
+}
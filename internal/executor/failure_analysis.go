@@ -0,0 +1,163 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/steveyegge/vc/internal/ai"
+	"github.com/steveyegge/vc/internal/artifacts"
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/gates"
+	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// Failure reasons passed to the supervisor and recorded on failure_analyzed events
+const (
+	failureReasonAgentFailed = "agent_failed"
+	failureReasonGatesFailed = "quality_gates_failed"
+	failureReasonAgentError  = "agent_error"
+)
+
+// failureAnalysisEvents is how many recent warning and error events of the
+// issue are sent with a failure
+const failureAnalysisEvents = 20
+
+// failureAnalysisStore is the part of storage failure analysis uses: the
+// comment it posts and the events it reads and records
+type failureAnalysisStore interface {
+	AddComment(ctx context.Context, issueID, actor, comment string) error
+	storage.EventStore
+}
+
+// failureAnalyzer diagnoses failed attempts with the AI supervisor
+// (executor.enable_failure_analysis)
+type failureAnalyzer struct {
+	store      failureAnalysisStore
+	supervisor *ai.Supervisor
+	costCap    float64 // Max estimated USD per issue, 0 = no cap
+	limit      artifacts.CommentLimit
+}
+
+// newFailureAnalyzer returns a failure analyzer, or nil when failure analysis
+// is disabled or there is no supervisor to ask
+func newFailureAnalyzer(store failureAnalysisStore, supervisor *ai.Supervisor, enabled bool, costCap float64, limit artifacts.CommentLimit) *failureAnalyzer {
+	if !enabled || supervisor == nil {
+		return nil
+	}
+	return &failureAnalyzer{store: store, supervisor: supervisor, costCap: costCap, limit: limit}
+}
+
+// analyze asks the supervisor why the attempt failed, posts its diagnosis and
+// suggested fix as an ai-supervisor comment, and records a failure_analyzed
+// event that the next attempt's prompt includes. It is best effort: it is
+// skipped once the issue's failure analyses have cost costCap, when the
+// execution was canceled, and when the failure is transient (err is a
+// cancellation or timeout); errors are only logged.
+func (fa *failureAnalyzer) analyze(ctx context.Context, issue *types.Issue, report ai.FailureReport, err error) {
+	if fa == nil || fa.supervisor == nil || ctx.Err() != nil {
+		return
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return
+	}
+
+	if fa.costCap > 0 {
+		spent, err := fa.spent(ctx, issue.ID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to total failure analysis cost for %s: %v\n", issue.ID, err)
+			return
+		}
+		if spent >= fa.costCap {
+			fmt.Printf("Skipping failure analysis for %s: $%.2f spent reaches the $%.2f cap\n", issue.ID, spent, fa.costCap)
+			return
+		}
+	}
+
+	report.Events = fa.recentProblems(ctx, issue.ID)
+	diagnosis, err := fa.supervisor.AnalyzeFailure(ctx, issue, report)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failure analysis for %s failed: %v\n", issue.ID, err)
+		return
+	}
+
+	comment := fmt.Sprintf("**Failure Analysis** (%s, confidence %.2f)\n\n**Diagnosis:** %s\n\n**Suggested fix:** %s",
+		report.Reason, diagnosis.Confidence, diagnosis.Diagnosis, diagnosis.Suggestion)
+	if err := fa.store.AddComment(ctx, issue.ID, "ai-supervisor", fa.limit.Cap(issue.ID, comment)); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to add failure analysis comment: %v\n", err)
+	}
+
+	event, err := events.NewFailureAnalyzedEvent(issue.ID,
+		fmt.Sprintf("Failure analyzed for %s: %s", issue.ID, truncate(diagnosis.Diagnosis, 120)),
+		events.FailureAnalysisData{
+			Reason:     report.Reason,
+			Diagnosis:  diagnosis.Diagnosis,
+			Suggestion: diagnosis.Suggestion,
+			Confidence: diagnosis.Confidence,
+		})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to create failure_analyzed event: %v\n", err)
+		return
+	}
+	if err := fa.store.StoreAgentEvent(ctx, event); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to store failure_analyzed event: %v\n", err)
+	}
+}
+
+// spent totals the estimated cost of the issue's failure analysis calls
+func (fa *failureAnalyzer) spent(ctx context.Context, issueID string) (float64, error) {
+	calls, err := fa.store.GetAgentEvents(ctx, events.EventFilter{IssueID: issueID, Type: events.EventTypeAICallCompleted})
+	if err != nil {
+		return 0, err
+	}
+	var total float64
+	for _, call := range calls {
+		data, err := call.GetAICallData()
+		if err != nil || data.Operation != ai.FailureAnalysisOperation {
+			continue
+		}
+		total += data.EstimatedCostUSD
+	}
+	return total, nil
+}
+
+// recentProblems returns the issue's latest warning and error events, oldest
+// first, as one line each
+func (fa *failureAnalyzer) recentProblems(ctx context.Context, issueID string) []string {
+	recent, err := fa.store.GetAgentEvents(ctx, events.EventFilter{IssueID: issueID, BeforeID: math.MaxInt64, Limit: 200})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to get events for failure analysis: %v\n", err)
+		return nil
+	}
+	var lines []string
+	for _, event := range recent {
+		if event.Severity == events.SeverityInfo {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("[%s] %s: %s", event.Severity, event.Type, truncate(event.Message, 300)))
+		if len(lines) == failureAnalysisEvents {
+			break
+		}
+	}
+	// recent is newest first
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+	return lines
+}
+
+// failedGateOutput joins the output of the gates that failed
+func failedGateOutput(results []*gates.Result) string {
+	var b strings.Builder
+	for _, result := range results {
+		if result.Passed {
+			continue
+		}
+		fmt.Fprintf(&b, "=== %s ===\n%s\n", result.Gate, result.Output)
+	}
+	return b.String()
+}
@@ -0,0 +1,145 @@
+package executor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/ai"
+	"github.com/steveyegge/vc/internal/artifacts"
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage/storagetest"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// newTestFailureAnalyzer returns an analyzer whose supervisor answers every
+// call with a diagnosis costing $0.30, and the number of calls made
+func newTestFailureAnalyzer(t *testing.T, store *storagetest.FakeStorage, costCap float64) (*failureAnalyzer, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Write([]byte(`{"model":"llama3","choices":[{"message":{"role":"assistant","content":` +
+			`"{\"diagnosis\": \"The test expects UTC but the code uses local time\", \"suggestion\": \"Call time.Now().UTC() in FormatStamp\", \"confidence\": 0.8}"}}],` +
+			`"usage":{"prompt_tokens":100000,"completion_tokens":0}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	supervisor, err := ai.NewSupervisor(&ai.Config{
+		Provider: ai.ProviderLocal,
+		Model:    "llama3",
+		BaseURL:  server.URL,
+		Store:    store,
+		Prices:   map[string]config.ModelPrice{"llama3": {InputPerMTok: 3}},
+	})
+	if err != nil {
+		t.Fatalf("NewSupervisor: %v", err)
+	}
+	return newFailureAnalyzer(store, supervisor, true, costCap, artifacts.CommentLimit{}), &calls
+}
+
+func createFailingIssue(t *testing.T, store *storagetest.FakeStorage) *types.Issue {
+	t.Helper()
+	issue := &types.Issue{Title: "Stamps are off by an hour", Status: types.StatusInProgress, Priority: 1, IssueType: types.TypeBug}
+	if err := store.CreateIssue(context.Background(), issue, "test"); err != nil {
+		t.Fatalf("Failed to create issue: %v", err)
+	}
+	return issue
+}
+
+func TestFailureAnalyzer_PostsSuggestedFix(t *testing.T) {
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+	issue := createFailingIssue(t, store)
+	fa, _ := newTestFailureAnalyzer(t, store, 0)
+
+	fa.analyze(ctx, issue, ai.FailureReport{
+		Reason:     failureReasonGatesFailed,
+		Summary:    "Changed FormatStamp",
+		GateOutput: "--- FAIL: TestFormatStamp",
+	}, nil)
+
+	history, err := store.GetEvents(ctx, issue.ID, 0)
+	if err != nil {
+		t.Fatalf("GetEvents: %v", err)
+	}
+	var commented bool
+	for _, event := range history {
+		if event.EventType == types.EventCommented && event.Actor == "ai-supervisor" &&
+			strings.Contains(*event.Comment, "**Suggested fix:** Call time.Now().UTC() in FormatStamp") {
+			commented = true
+		}
+	}
+	if !commented {
+		t.Error("Expected an ai-supervisor comment with the suggested fix")
+	}
+
+	// The next attempt's prompt includes the diagnosis
+	pc, err := NewContextGatherer(store).GatherContext(ctx, issue, nil)
+	if err != nil {
+		t.Fatalf("GatherContext: %v", err)
+	}
+	if pc.FailureAnalysis == nil || pc.FailureAnalysis.Reason != failureReasonGatesFailed || pc.FailureAnalysis.Confidence != 0.8 {
+		t.Fatalf("Expected the failure analysis in the context, got %+v", pc.FailureAnalysis)
+	}
+	pc.PreviousAttempts = []*types.ExecutionAttempt{{AttemptNumber: 1}}
+	pb, err := NewPromptBuilder()
+	if err != nil {
+		t.Fatalf("NewPromptBuilder() failed: %v", err)
+	}
+	prompt, err := pb.BuildPrompt(pc)
+	if err != nil {
+		t.Fatalf("BuildPrompt() failed: %v", err)
+	}
+	if !strings.Contains(prompt, "Diagnosis of the Last Failure") || !strings.Contains(prompt, "Suggested fix: Call time.Now().UTC() in FormatStamp") {
+		t.Errorf("Expected the diagnosis in the prompt:\n%s", prompt)
+	}
+}
+
+func TestFailureAnalyzer_CostCap(t *testing.T) {
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+	issue := createFailingIssue(t, store)
+	fa, calls := newTestFailureAnalyzer(t, store, 0.50)
+
+	// $0.30 each: the second call reaches the cap, the third is skipped
+	for i := 0; i < 3; i++ {
+		fa.analyze(ctx, issue, ai.FailureReport{Reason: failureReasonAgentFailed, Summary: "exit 1"}, nil)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Expected 2 supervisor calls under the cap, got %d", calls.Load())
+	}
+	analyzed, err := store.GetAgentEvents(ctx, events.EventFilter{IssueID: issue.ID, Type: events.EventTypeFailureAnalyzed})
+	if err != nil {
+		t.Fatalf("GetAgentEvents: %v", err)
+	}
+	if len(analyzed) != 2 {
+		t.Errorf("Expected 2 failure_analyzed events, got %d", len(analyzed))
+	}
+}
+
+func TestFailureAnalyzer_SkipsCancellation(t *testing.T) {
+	store := storagetest.NewFakeStorage()
+	issue := createFailingIssue(t, store)
+	fa, calls := newTestFailureAnalyzer(t, store, 0)
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	fa.analyze(canceled, issue, ai.FailureReport{Reason: failureReasonAgentError}, context.Canceled)
+	fa.analyze(context.Background(), issue, ai.FailureReport{Reason: failureReasonAgentError}, context.Canceled)
+	fa.analyze(context.Background(), issue, ai.FailureReport{Reason: failureReasonAgentError}, context.DeadlineExceeded)
+	if calls.Load() != 0 {
+		t.Errorf("Expected no supervisor calls for canceled executions, got %d", calls.Load())
+	}
+
+	// Disabled analysis is a nil analyzer, which does nothing
+	var disabled *failureAnalyzer
+	disabled.analyze(context.Background(), issue, ai.FailureReport{}, nil)
+	if newFailureAnalyzer(store, nil, true, 0, artifacts.CommentLimit{}) != nil {
+		t.Error("Expected no analyzer without a supervisor")
+	}
+}
@@ -3,8 +3,14 @@ package executor
 import (
 	"context"
 	"fmt"
+	"math"
+	"regexp"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/steveyegge/vc/internal/estimates"
+	"github.com/steveyegge/vc/internal/events"
 	"github.com/steveyegge/vc/internal/storage"
 	"github.com/steveyegge/vc/internal/types"
 )
@@ -44,6 +50,16 @@ func (g *contextGatherer) GatherContext(ctx context.Context, issue *types.Issue,
 		pc.PreviousAttempts = attempts
 	}
 
+	// 3a. Get the supervisor's diagnosis of the latest failure
+	if analysis, err := g.GetFailureAnalysis(ctx, issue.ID); err == nil {
+		pc.FailureAnalysis = analysis
+	}
+
+	// 3b. Get the latest assessment, steps done so far included
+	if assessment, err := g.store.GetLatestAssessment(ctx, issue.ID); err == nil {
+		pc.Assessment = assessment
+	}
+
 	// 4. Get quality gate status if any
 	// TODO: Implement quality gate status when gates package is ready
 	pc.QualityGateStatus = nil
@@ -149,6 +165,81 @@ func (g *contextGatherer) GetPreviousAttempts(ctx context.Context, issueID strin
 	return attempts, nil
 }
 
+// GetFailureAnalysis retrieves the latest failure_analyzed event's diagnosis
+// Returns nil if no failed attempt of the issue was analyzed
+func (g *contextGatherer) GetFailureAnalysis(ctx context.Context, issueID string) (*events.FailureAnalysisData, error) {
+	analyzed, err := g.store.GetAgentEvents(ctx, events.EventFilter{
+		IssueID:  issueID,
+		Type:     events.EventTypeFailureAnalyzed,
+		BeforeID: math.MaxInt64,
+		Limit:    1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get failure analysis: %w", err)
+	}
+	if len(analyzed) == 0 {
+		return nil, nil
+	}
+	return analyzed[0].GetFailureAnalysisData()
+}
+
+// estimateHintIssues bounds the closed issues GetEstimateHint reads, the most
+// recently closed first
+const estimateHintIssues = 50
+
+// GetEstimateHint tells how long closed issues of the same type and project
+// took against their estimates (see estimates.Hint)
+func (g *contextGatherer) GetEstimateHint(ctx context.Context, issue *types.Issue) (string, error) {
+	issueType := issue.IssueType
+	samples, err := estimates.Samples(ctx, g.store, types.IssueFilter{
+		IssueType:  &issueType,
+		Project:    issue.Project,
+		OrderBy:    "closed_at",
+		Descending: true,
+		Limit:      estimateHintIssues,
+	}, time.Time{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get estimate history: %w", err)
+	}
+	return estimates.Hint(issue, samples), nil
+}
+
+// issuePathPattern matches file paths named in issue text: a directory and
+// a file name with an extension, e.g. internal/executor/agent.go
+var issuePathPattern = regexp.MustCompile(`(?:\./)?[\w.-]+(?:/[\w.-]+)*/[\w-][\w.-]*\.[A-Za-z0-9]+`)
+
+// GetRelevantFiles returns the files previous attempts of the issue changed
+// or, for an issue not attempted yet (or whose attempts changed nothing),
+// the file paths its description, design, acceptance criteria and notes
+// name, sorted
+func (g *contextGatherer) GetRelevantFiles(ctx context.Context, issue *types.Issue) ([]string, error) {
+	attempts, err := g.GetPreviousAttempts(ctx, issue.ID)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]bool)
+	for _, attempt := range attempts {
+		for _, file := range attempt.Files {
+			files[file] = true
+		}
+	}
+	if len(files) == 0 {
+		text := strings.Join([]string{issue.Description, issue.Design, issue.AcceptanceCriteria, issue.Notes}, "\n")
+		for _, path := range issuePathPattern.FindAllString(text, -1) {
+			files[strings.TrimPrefix(path, "./")] = true
+		}
+	}
+	if len(files) == 0 {
+		return nil, nil
+	}
+	relevant := make([]string, 0, len(files))
+	for file := range files {
+		relevant = append(relevant, file)
+	}
+	sort.Strings(relevant)
+	return relevant, nil
+}
+
 // AnalyzeResumeState examines sandbox state and previous attempts to determine
 // where execution left off. Returns a human-readable hint for the AI.
 func (g *contextGatherer) AnalyzeResumeState(ctx context.Context, sandbox interface{}, attempts []*types.ExecutionAttempt) (string, error) {
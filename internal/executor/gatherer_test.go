@@ -2,10 +2,12 @@ package executor
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/storage/storagetest"
 	"github.com/steveyegge/vc/internal/types"
 )
 
@@ -560,3 +562,76 @@ func stringContains(s, substr string) bool {
 	}
 	return false
 }
+
+func TestGetEstimateHint(t *testing.T) {
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+	gatherer := NewContextGatherer(store)
+
+	hour := 60
+	for i := 0; i < 3; i++ {
+		done := &types.Issue{Title: "Done", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, EstimatedMinutes: &hour}
+		if err := store.CreateIssue(ctx, done, "test"); err != nil {
+			t.Fatalf("CreateIssue: %v", err)
+		}
+		if err := store.AddTimeEntry(ctx, &types.TimeEntry{IssueID: done.ID, Minutes: 180, Actor: "test"}); err != nil {
+			t.Fatalf("AddTimeEntry: %v", err)
+		}
+		if err := store.CloseIssue(ctx, done.ID, "done", "test"); err != nil {
+			t.Fatalf("CloseIssue: %v", err)
+		}
+	}
+
+	task := &types.Issue{ID: "vc-new", Title: "Next", IssueType: types.TypeTask, EstimatedMinutes: &hour}
+	hint, err := gatherer.GetEstimateHint(ctx, task)
+	if err != nil {
+		t.Fatalf("GetEstimateHint: %v", err)
+	}
+	if !strings.Contains(hint, "3 closed tasks estimated 30m-2h") || !strings.Contains(hint, "took 3.0x their estimate") {
+		t.Errorf("GetEstimateHint() = %q, want the three closed tasks at 3x", hint)
+	}
+
+	bug := &types.Issue{ID: "vc-bug", Title: "Bug", IssueType: types.TypeBug}
+	if hint, err := gatherer.GetEstimateHint(ctx, bug); err != nil || hint != "" {
+		t.Errorf("GetEstimateHint(bug) = (%q, %v), want no hint without closed bugs", hint, err)
+	}
+}
+
+func TestGetRelevantFiles(t *testing.T) {
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+	gatherer := NewContextGatherer(store)
+
+	issue := &types.Issue{Title: "Fix the parser", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeBug,
+		Description:        "The parser in ./internal/parser/parse.go drops comments (see docs/PARSER.md).",
+		AcceptanceCriteria: "Tests in internal/parser/parse_test.go pass; v1.2 and e.g. aren't paths"}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+	files, err := gatherer.GetRelevantFiles(ctx, issue)
+	if err != nil {
+		t.Fatalf("GetRelevantFiles: %v", err)
+	}
+	if got := strings.Join(files, " "); got != "docs/PARSER.md internal/parser/parse.go internal/parser/parse_test.go" {
+		t.Errorf("GetRelevantFiles() = %q, want the paths the issue names", got)
+	}
+
+	// Once attempted, the files the attempts changed are used instead
+	for i, changed := range [][]string{{"internal/parser/lexer.go"}, nil, {"internal/parser/lexer.go", "go.mod"}} {
+		if err := store.RecordExecutionAttempt(ctx, &types.ExecutionAttempt{IssueID: issue.ID, ExecutorInstanceID: "exec-1",
+			AttemptNumber: i + 1, StartedAt: time.Now(), Files: changed}); err != nil {
+			t.Fatalf("RecordExecutionAttempt: %v", err)
+		}
+	}
+	files, err = gatherer.GetRelevantFiles(ctx, issue)
+	if err != nil {
+		t.Fatalf("GetRelevantFiles: %v", err)
+	}
+	if got := strings.Join(files, " "); got != "go.mod internal/parser/lexer.go" {
+		t.Errorf("GetRelevantFiles() = %q, want the files the attempts changed", got)
+	}
+
+	if files, err := gatherer.GetRelevantFiles(ctx, &types.Issue{ID: "vc-none", Title: "Vague"}); err != nil || files != nil {
+		t.Errorf("GetRelevantFiles() = (%v, %v), want none", files, err)
+	}
+}
@@ -12,20 +12,45 @@ import (
 	"github.com/steveyegge/vc/internal/types"
 )
 
+// healthMonitoringActive reports whether the health loop should run.
+func (e *Executor) healthMonitoringActive() bool {
+	return e.enableHealthMonitoring && e.healthRegistry != nil
+}
+
+// healthLoop drives scheduled health monitors independently of issue execution,
+// so monitors run on their own schedule whether the executor is busy or idle.
+func (e *Executor) healthLoop(ctx context.Context) {
+	defer close(e.healthDoneCh)
+
+	ticker := time.NewTicker(e.healthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-e.healthStopCh:
+			return
+		case <-ticker.C:
+			if err := e.checkHealthMonitors(ctx); err != nil {
+				// Log error but continue
+				fmt.Fprintf(os.Stderr, "error running health monitors: %v\n", err)
+			}
+		}
+	}
+}
+
 // checkHealthMonitors runs health monitors that are due and files discovered issues.
-// This is called after successfully completing an issue.
 func (e *Executor) checkHealthMonitors(ctx context.Context) error {
 	// Skip if health monitoring is not enabled
 	if e.healthRegistry == nil {
 		return nil
 	}
 
-	// Determine the number of issues closed (for event-based scheduling)
-	// For MVP, we'll just increment by 1 when this is called
 	now := time.Now()
 
 	// Get monitors that are due to run
-	monitors := e.healthRegistry.GetScheduledMonitors(now, 1, 0)
+	monitors := e.healthRegistry.GetScheduledMonitors(now, 0, 0)
 	if len(monitors) == 0 {
 		return nil
 	}
@@ -41,9 +66,22 @@ func (e *Executor) checkHealthMonitors(ctx context.Context) error {
 
 	// Run each monitor
 	for _, monitor := range monitors {
+		// Stop promptly on shutdown rather than starting another monitor
+		select {
+		case <-e.healthStopCh:
+			return nil
+		default:
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
 		if err := e.runHealthMonitor(ctx, monitor, projectRoot); err != nil {
 			// Log error but continue with other monitors
 			fmt.Fprintf(os.Stderr, "Health: Error running monitor %s: %v\n", monitor.Name(), err)
+			if recordErr := e.healthRegistry.RecordFailure(monitor.Name(), now, err); recordErr != nil {
+				fmt.Fprintf(os.Stderr, "Health: Failed to record failure for %s: %v\n", monitor.Name(), recordErr)
+			}
 			continue
 		}
 	}
@@ -51,16 +89,47 @@ func (e *Executor) checkHealthMonitors(ctx context.Context) error {
 	return nil
 }
 
+// checkMonitor runs monitor.Check with the registry's per-monitor timeout.
+// Panics are recovered and returned as errors, and a monitor that ignores
+// context cancellation is abandoned at the deadline so it can't wedge the loop.
+func (e *Executor) checkMonitor(ctx context.Context, monitor health.HealthMonitor) (*health.MonitorResult, error) {
+	checkCtx, cancel := context.WithTimeout(ctx, e.healthRegistry.Timeout(monitor.Name()))
+	defer cancel()
+
+	type checkResult struct {
+		result *health.MonitorResult
+		err    error
+	}
+	done := make(chan checkResult, 1)
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- checkResult{err: fmt.Errorf("monitor panicked: %v", r)}
+			}
+		}()
+		result, err := monitor.Check(checkCtx, health.CodebaseContext{})
+		done <- checkResult{result: result, err: err}
+	}()
+
+	select {
+	case res := <-done:
+		if res.err == nil && res.result == nil {
+			return nil, fmt.Errorf("monitor returned no result")
+		}
+		return res.result, res.err
+	case <-checkCtx.Done():
+		return nil, fmt.Errorf("monitor did not finish: %w", checkCtx.Err())
+	}
+}
+
 // runHealthMonitor executes a single health monitor and files any discovered issues.
 func (e *Executor) runHealthMonitor(ctx context.Context, monitor health.HealthMonitor, _ string) error {
 	monitorName := monitor.Name()
 	fmt.Printf("Health: Running %s\n", monitorName)
 
-	// Build codebase context (currently unused by monitors, but part of interface)
-	codebaseCtx := health.CodebaseContext{}
-
 	// Run the monitor
-	result, err := monitor.Check(ctx, codebaseCtx)
+	result, err := e.checkMonitor(ctx, monitor)
 	if err != nil {
 		e.logEvent(ctx, events.EventTypeHealthCheckFailed, events.SeverityError, "",
 			fmt.Sprintf("Health monitor %s failed: %v", monitorName, err),
@@ -71,6 +140,17 @@ func (e *Executor) runHealthMonitor(ctx context.Context, monitor health.HealthMo
 		return fmt.Errorf("monitor check failed: %w", err)
 	}
 
+	// Problems with a monitor's own output are warnings, not failures
+	for _, warning := range result.Warnings {
+		fmt.Fprintf(os.Stderr, "Health: Warning from %s: %s\n", monitorName, warning)
+		e.logEvent(ctx, events.EventTypeHealthCheckWarning, events.SeverityWarning, "",
+			fmt.Sprintf("Health monitor %s: %s", monitorName, warning),
+			map[string]interface{}{
+				"monitor": monitorName,
+				"warning": warning,
+			})
+	}
+
 	// File discovered issues
 	var issuesFiled []string
 	for _, discovered := range result.IssuesFound {
@@ -132,6 +212,18 @@ func (e *Executor) fileHealthIssue(ctx context.Context, monitor health.HealthMon
 		IssueType:   types.TypeTask,
 	}
 
+	// Skip findings that duplicate an open issue (e.g. a coverage drop
+	// reported again before the first issue was fixed)
+	if e.deduplicator != nil {
+		decision, err := e.deduplicator.CheckDuplicate(ctx, issue)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: deduplication check failed for health issue: %v (filing anyway)\n", err)
+		} else if decision.IsDuplicate && decision.DuplicateOf != "" {
+			fmt.Printf("Health: Skipping duplicate of %s: %s\n", decision.DuplicateOf, title)
+			return decision.DuplicateOf, nil
+		}
+	}
+
 	// Add health monitor label
 	err := e.store.CreateIssue(ctx, issue, "vc-health-monitor")
 	if err != nil {
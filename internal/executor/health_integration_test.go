@@ -0,0 +1,73 @@
+package executor
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/health"
+)
+
+// stubHealthMonitor is a HealthMonitor whose Check is supplied by the test
+type stubHealthMonitor struct {
+	name  string
+	check func(ctx context.Context) (*health.MonitorResult, error)
+}
+
+func (m *stubHealthMonitor) Name() string       { return m.name }
+func (m *stubHealthMonitor) Philosophy() string { return "test" }
+func (m *stubHealthMonitor) Schedule() health.ScheduleConfig {
+	return health.ScheduleConfig{Type: health.ScheduleTimeBased, Interval: time.Hour}
+}
+func (m *stubHealthMonitor) Cost() health.CostEstimate { return health.CostEstimate{} }
+func (m *stubHealthMonitor) Check(ctx context.Context, _ health.CodebaseContext) (*health.MonitorResult, error) {
+	return m.check(ctx)
+}
+
+func TestCheckMonitor_RecoversPanicsAndTimesOut(t *testing.T) {
+	registry, err := health.NewMonitorRegistry(filepath.Join(t.TempDir(), "health_state.json"))
+	if err != nil {
+		t.Fatalf("Failed to create registry: %v", err)
+	}
+	e := &Executor{healthRegistry: registry}
+
+	panicking := &stubHealthMonitor{name: "panicking", check: func(ctx context.Context) (*health.MonitorResult, error) {
+		panic("nil map")
+	}}
+	if _, err := e.checkMonitor(context.Background(), panicking); err == nil || !strings.Contains(err.Error(), "panicked") {
+		t.Errorf("Expected recovered panic error, got %v", err)
+	}
+
+	// A monitor that ignores cancellation is abandoned at its timeout
+	release := make(chan struct{})
+	defer close(release)
+	hanging := &stubHealthMonitor{name: "hanging", check: func(ctx context.Context) (*health.MonitorResult, error) {
+		<-release
+		return &health.MonitorResult{}, nil
+	}}
+	if err := registry.Register(hanging); err != nil {
+		t.Fatalf("Failed to register monitor: %v", err)
+	}
+	if err := registry.ApplyConfig(&health.HealthConfig{Monitors: map[string]health.MonitorConfig{
+		"hanging": {Enabled: true, Timeout: "50ms"},
+	}}); err != nil {
+		t.Fatalf("ApplyConfig failed: %v", err)
+	}
+
+	start := time.Now()
+	if _, err := e.checkMonitor(context.Background(), hanging); err == nil {
+		t.Error("Expected timeout error for hanging monitor")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("checkMonitor took %v, expected it to give up at the timeout", elapsed)
+	}
+
+	ok := &stubHealthMonitor{name: "ok", check: func(ctx context.Context) (*health.MonitorResult, error) {
+		return &health.MonitorResult{CheckedAt: time.Now()}, nil
+	}}
+	if result, err := e.checkMonitor(context.Background(), ok); err != nil || result == nil {
+		t.Errorf("Expected successful result, got %v, %v", result, err)
+	}
+}
@@ -257,10 +257,15 @@ func TestErrorRecoveryAndResume(t *testing.T) {
 	}
 
 	// Save checkpoint
-	checkpointData := map[string]interface{}{
-		"step":      2,
-		"completed": []string{"setup", "compile"},
-		"pending":   []string{"test", "deploy"},
+	checkpointData := &types.CheckpointV1{
+		Phase:     types.ExecutionStateExecuting,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+		Extras: map[string]interface{}{
+			"step":      2,
+			"completed": []string{"setup", "compile"},
+			"pending":   []string{"test", "deploy"},
+		},
 	}
 	if err := store.SaveCheckpoint(ctx, task.ID, checkpointData); err != nil {
 		t.Fatalf("Failed to save checkpoint: %v", err)
@@ -305,12 +310,12 @@ func TestErrorRecoveryAndResume(t *testing.T) {
 	}
 
 	// Retrieve checkpoint to verify resume context
-	checkpointJSON, err := store.GetCheckpoint(ctx, task.ID)
+	checkpoint, err := store.GetCheckpoint(ctx, task.ID)
 	if err != nil {
 		t.Fatalf("Failed to get checkpoint: %v", err)
 	}
-	if checkpointJSON == "" {
-		t.Error("Expected checkpoint data, got empty string")
+	if checkpoint == nil || checkpoint.Phase != types.ExecutionStateExecuting {
+		t.Errorf("Expected the checkpoint taken while executing, got %+v", checkpoint)
 	}
 
 	// Continue from where we left off - need to start from assessing since we just claimed
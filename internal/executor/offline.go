@@ -0,0 +1,96 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/ai"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// Why an executor runs offline, recorded in its instance metadata
+const (
+	offlineReasonConfigured  = "configured"
+	offlineReasonUnreachable = "ai_unreachable"
+)
+
+// offlineProbeAttempts is how many startup healthchecks in a row must fail to
+// connect to the AI provider before the executor goes offline on its own
+const offlineProbeAttempts = 3
+
+// offlineProbeDelay is the pause between those healthchecks
+var offlineProbeDelay = 2 * time.Second
+
+// probeAI runs the supervisor's startup healthcheck, retrying connection
+// failures up to offlineProbeAttempts times. It returns the last error, for
+// which isUnreachable is true when no attempt could connect.
+func probeAI(ctx context.Context, supervisor *ai.Supervisor) error {
+	var err error
+	for attempt := 1; attempt <= offlineProbeAttempts; attempt++ {
+		if err = supervisor.Healthcheck(ctx); err == nil || !isUnreachable(err) {
+			return err
+		}
+		if attempt < offlineProbeAttempts {
+			time.Sleep(offlineProbeDelay)
+		}
+	}
+	return err
+}
+
+// isUnreachable reports whether err is a healthcheck that couldn't connect
+// to the AI provider
+func isUnreachable(err error) bool {
+	var healthErr *ai.HealthcheckError
+	return errors.As(err, &healthErr) && healthErr.Kind == ai.HealthcheckNetwork
+}
+
+// Feature is an executor capability and whether this executor has it
+type Feature struct {
+	Name    string
+	Enabled bool
+}
+
+// Features lists which executor capabilities are active, AI-dependent ones
+// first. Offline executors have none of the AI-dependent ones.
+func (e *Executor) Features() []Feature {
+	return []Feature{
+		{"AI supervision", e.supervisor != nil},
+		{"dedup", e.deduplicator != nil},
+		{"watchdog AI analysis", e.analyzer != nil},
+		{"health monitors", e.healthMonitoringActive()},
+		{"heuristic watchdog", e.watchdogConfig.IsEnabled() && e.intervention != nil},
+		{"gates", e.enableQualityGates},
+	}
+}
+
+// FormatFeatures renders features on one line, e.g.
+// "AI supervision: off, dedup: off, gates: on"
+func FormatFeatures(features []Feature) string {
+	parts := make([]string, len(features))
+	for i, feature := range features {
+		state := "off"
+		if feature.Enabled {
+			state = "on"
+		}
+		parts[i] = fmt.Sprintf("%s: %s", feature.Name, state)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Offline reports whether the executor runs without AI, and why
+func (e *Executor) Offline() (bool, string) {
+	return e.offline, e.offlineReason
+}
+
+// instanceMetadata is the metadata the executor registers its instance with
+func (e *Executor) instanceMetadata() string {
+	data, err := json.Marshal(types.InstanceMetadata{Offline: e.offline, OfflineReason: e.offlineReason})
+	if err != nil {
+		return "{}"
+	}
+	return string(data)
+}
@@ -0,0 +1,92 @@
+package executor
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/storage/storagetest"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// offlineFeatures is the feature line of an offline executor with the
+// default watchdog and quality gates
+const offlineFeatures = "AI supervision: off, dedup: off, watchdog AI analysis: off, health monitors: off, heuristic watchdog: on, gates: on"
+
+func TestOfflineConfigured(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Write([]byte(`{"model":"llama3","choices":[{"message":{"role":"assistant","content":"ok"}}]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	cfg := DefaultConfig()
+	cfg.Store = storagetest.NewFakeStorage()
+	cfg.EnableSandboxes = false
+	cfg.AIProvider = "local"
+	cfg.AIModel = "llama3"
+	cfg.AIBaseURL = server.URL + "/v1"
+	cfg.EnableHealthMonitoring = true
+	cfg.Offline = true
+
+	exec, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create executor: %v", err)
+	}
+	if calls.Load() != 0 {
+		t.Errorf("Expected no AI calls offline, got %d", calls.Load())
+	}
+	if offline, reason := exec.Offline(); !offline || reason != offlineReasonConfigured {
+		t.Errorf("Expected configured offline mode, got %v (%s)", offline, reason)
+	}
+	if got := FormatFeatures(exec.Features()); got != offlineFeatures {
+		t.Errorf("Unexpected features:\n got %s\nwant %s", got, offlineFeatures)
+	}
+	instance := types.ExecutorInstance{Metadata: exec.instanceMetadata()}
+	if metadata := instance.ParseMetadata(); !metadata.Offline || metadata.OfflineReason != offlineReasonConfigured {
+		t.Errorf("Unexpected instance metadata %s", instance.Metadata)
+	}
+
+	cfg.RequireAI = true
+	if _, err := New(cfg); err == nil || !strings.Contains(err.Error(), "mutually exclusive") {
+		t.Errorf("Expected offline mode to conflict with RequireAI, got %v", err)
+	}
+}
+
+func TestOfflineDetected(t *testing.T) {
+	original := offlineProbeDelay
+	offlineProbeDelay = 0
+	defer func() { offlineProbeDelay = original }()
+
+	// A closed server refuses connections, like a provider without network
+	cfg := localAIConfig(t, storagetest.NewFakeStorage(), http.StatusOK)
+	server := httptest.NewServer(http.NotFoundHandler())
+	cfg.AIBaseURL = server.URL + "/v1"
+	server.Close()
+
+	exec, err := New(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create executor: %v", err)
+	}
+	if offline, reason := exec.Offline(); !offline || reason != offlineReasonUnreachable {
+		t.Errorf("Expected offline mode after connection failures, got %v (%s)", offline, reason)
+	}
+	if exec.supervisor != nil || exec.deduplicator != nil || exec.analyzer != nil {
+		t.Error("Expected no AI components offline")
+	}
+	if got := FormatFeatures(exec.Features()); got != offlineFeatures {
+		t.Errorf("Unexpected features:\n got %s\nwant %s", got, offlineFeatures)
+	}
+
+	// An online executor's metadata has no offline mode
+	online, err := New(localAIConfig(t, storagetest.NewFakeStorage(), http.StatusOK))
+	if err != nil {
+		t.Fatalf("Failed to create executor: %v", err)
+	}
+	if offline, _ := online.Offline(); offline || online.instanceMetadata() != "{}" {
+		t.Errorf("Expected an online executor, got metadata %s", online.instanceMetadata())
+	}
+}
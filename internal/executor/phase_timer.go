@@ -0,0 +1,82 @@
+package executor
+
+import (
+	"sync"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// PhaseTimer times the execution phases of one attempt. Starting a phase
+// ends the one running, and time spent in a phase that runs again is added
+// to its first entry. Durations come from time.Since, which reads the
+// monotonic clock, so wall clock changes don't skew them. A nil *PhaseTimer
+// ignores every call.
+type PhaseTimer struct {
+	mu      sync.Mutex
+	phases  []types.PhaseTiming
+	current types.ExecutionPhase // "" between phases
+	started time.Time
+}
+
+// NewPhaseTimer returns a timer with no phase running
+func NewPhaseTimer() *PhaseTimer {
+	return &PhaseTimer{}
+}
+
+// Start ends the running phase, if any, and starts phase
+func (t *PhaseTimer) Start(phase types.ExecutionPhase) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stopLocked()
+	t.current, t.started = phase, time.Now()
+}
+
+// Stop ends the running phase, if any
+func (t *PhaseTimer) Stop() {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stopLocked()
+}
+
+func (t *PhaseTimer) stopLocked() {
+	if t.current == "" {
+		return
+	}
+	elapsed := time.Since(t.started).Milliseconds()
+	phase := t.current
+	t.current = ""
+	for i := range t.phases {
+		if t.phases[i].Phase == phase {
+			t.phases[i].DurationMs += elapsed
+			return
+		}
+	}
+	t.phases = append(t.phases, types.PhaseTiming{Phase: phase, DurationMs: elapsed})
+}
+
+// Phases returns the finished phases in the order they first ran. A phase
+// still running isn't included.
+func (t *PhaseTimer) Phases() []types.PhaseTiming {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]types.PhaseTiming(nil), t.phases...)
+}
+
+// phaseDurationsMs maps each phase to its milliseconds, for event data
+func phaseDurationsMs(phases []types.PhaseTiming) map[string]int64 {
+	durations := make(map[string]int64, len(phases))
+	for _, p := range phases {
+		durations[string(p.Phase)] = p.DurationMs
+	}
+	return durations
+}
@@ -0,0 +1,41 @@
+package executor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestPhaseTimer(t *testing.T) {
+	timer := NewPhaseTimer()
+	timer.Start(types.ExecutionPhaseSandbox)
+	time.Sleep(5 * time.Millisecond)
+	timer.Start(types.ExecutionPhaseAgent)
+	timer.Start(types.ExecutionPhaseSandbox)
+	time.Sleep(5 * time.Millisecond)
+	timer.Start(types.ExecutionPhaseGates)
+
+	// The running gates phase isn't reported until stopped
+	phases := timer.Phases()
+	if len(phases) != 2 || phases[0].Phase != types.ExecutionPhaseSandbox || phases[1].Phase != types.ExecutionPhaseAgent {
+		t.Fatalf("Expected sandbox then agent, got %+v", phases)
+	}
+	if phases[0].DurationMs < 10 {
+		t.Errorf("Expected both sandbox runs added up to at least 10ms, got %dms", phases[0].DurationMs)
+	}
+
+	timer.Stop()
+	timer.Stop()
+	if phases := timer.Phases(); len(phases) != 3 || phases[2].Phase != types.ExecutionPhaseGates {
+		t.Errorf("Expected the gates phase after Stop, got %+v", phases)
+	}
+
+	// A nil timer ignores every call
+	var none *PhaseTimer
+	none.Start(types.ExecutionPhaseAgent)
+	none.Stop()
+	if none.Phases() != nil {
+		t.Error("Expected no phases from a nil timer")
+	}
+}
@@ -12,6 +12,10 @@ import (
 // into a well-formatted prompt for AI agents.
 type PromptBuilder struct {
 	template *template.Template
+
+	// TokenBudget bounds the prompt's estimated tokens (0 = unbounded).
+	// NewPromptBuilder sets DefaultPromptTokenBudget.
+	TokenBudget int
 }
 
 // promptTemplate defines the structure for agent prompts
@@ -50,6 +54,20 @@ Mission Goal:
 
 **IMPORTANT**: These criteria define success. ALL criteria must be met. Do not add extra work beyond what's required.
 
+{{end}}
+{{if .Assessment -}}
+{{if .Assessment.Assessment.Steps -}}
+# PLAN
+
+The AI supervisor's plan for this task{{if .Assessment.CompletedSteps}} ([x] = reported done by a previous attempt; don't redo it){{end}}:
+{{if .Assessment.Assessment.Strategy}}
+**Strategy**: {{.Assessment.Assessment.Strategy}}
+{{end}}
+{{range $i, $step := .Assessment.Assessment.Steps -}}
+- [{{if $.Assessment.StepCompleted $i}}x{{else}} {{end}}] {{$step}}
+{{end}}
+
+{{end}}
 {{end}}
 {{if .Sandbox -}}
 # ENVIRONMENT
@@ -86,6 +104,9 @@ Modified files:
 {{range .GitState.ModifiedFiles -}}
 - {{.}}
 {{end}}
+{{with index .Omitted "modified_files" -}}
+- [truncated: {{.}} more]
+{{end}}
 {{end}}
 {{end}}
 {{end}}
@@ -106,6 +127,9 @@ The following tasks are waiting for this:
 {{range .RelatedIssues.Dependents -}}
 - {{.ID}}: {{.Title}}
 {{end}}
+{{with index .Omitted "dependents" -}}
+- [truncated: {{.}} more]
+{{end}}
 
 {{end}}
 {{if .RelatedIssues.Siblings -}}
@@ -115,14 +139,19 @@ Other tasks in the same mission:
 {{range .RelatedIssues.Siblings -}}
 - {{.ID}}: {{.Title}} [{{.Status}}]
 {{end}}
+{{with index .Omitted "siblings" -}}
+- [truncated: {{.}} more]
+{{end}}
 
 {{end}}
 {{end}}
 {{if .PreviousAttempts -}}
 # PREVIOUS ATTEMPTS
 
-This task has been attempted {{len .PreviousAttempts}} time(s) before:
-{{range .PreviousAttempts -}}
+This task has been attempted {{.AttemptCount}} time(s) before:
+{{with index .Omitted "history" -}}
+[truncated: {{.}} earlier attempt(s) omitted]
+{{end}}{{range .PreviousAttempts -}}
 
 ## Attempt #{{.AttemptNumber}} ({{formatTime .StartedAt}})
 {{if .CompletedAt -}}
@@ -149,6 +178,12 @@ This task has been attempted {{len .PreviousAttempts}} time(s) before:
 ## Where We Left Off
 {{.ResumeHint}}
 {{end}}
+{{with .FailureAnalysis -}}
+
+## Supervisor's Diagnosis of the Last Failure
+- Diagnosis: {{.Diagnosis}}
+- Suggested fix: {{.Suggestion}}
+{{end}}
 {{end}}
 {{if .QualityGateStatus -}}
 {{if not .QualityGateStatus.AllPassed -}}
@@ -406,27 +441,29 @@ func NewPromptBuilder() (*PromptBuilder, error) {
 	}
 
 	return &PromptBuilder{
-		template: tmpl,
+		template:    tmpl,
+		TokenBudget: DefaultPromptTokenBudget,
 	}, nil
 }
 
 // BuildPrompt generates a comprehensive prompt from the given context
 // It handles missing or nil context fields gracefully through template conditionals
 func (pb *PromptBuilder) BuildPrompt(ctx *PromptContext) (string, error) {
+	prompt, _, err := pb.BuildPromptWithStats(ctx)
+	return prompt, err
+}
+
+// BuildPromptWithStats generates the prompt like BuildPrompt and reports its
+// size. A prompt over the builder's TokenBudget is shrunk section by section
+// in promptSections order, each shortened section marked "[truncated]"; the
+// caller's context is left untouched.
+func (pb *PromptBuilder) BuildPromptWithStats(ctx *PromptContext) (string, PromptStats, error) {
+	stats := PromptStats{Budget: pb.TokenBudget}
 	if ctx == nil {
-		return "", fmt.Errorf("prompt context cannot be nil")
+		return "", stats, fmt.Errorf("prompt context cannot be nil")
 	}
 	if ctx.Issue == nil {
-		return "", fmt.Errorf("issue cannot be nil in prompt context")
-	}
-
-	// Prepare the context data for template execution
-	// We need to handle the sandbox interface{} type
-	type sandboxData struct {
-		Path          string
-		GitBranch     string
-		BeadsDB       string
-		ModifiedFiles []string
+		return "", stats, fmt.Errorf("issue cannot be nil in prompt context")
 	}
 
 	// Detect baseline issues (vc-210: Self-healing for baseline test failures)
@@ -434,13 +471,11 @@ func (pb *PromptBuilder) BuildPrompt(ctx *PromptContext) (string, error) {
 	isBaselineIssue := IsBaselineIssue(ctx.Issue.ID)
 
 	// Create a wrapper struct that exposes sandbox fields if available
-	templateData := struct {
-		*PromptContext
-		Sandbox         *sandboxData
-		IsBaselineIssue bool
-	}{
+	data := &promptData{
 		PromptContext:   ctx,
 		IsBaselineIssue: isBaselineIssue,
+		AttemptCount:    len(ctx.PreviousAttempts),
+		Omitted:         make(map[string]int),
 	}
 
 	// Extract sandbox data if available
@@ -449,14 +484,58 @@ func (pb *PromptBuilder) BuildPrompt(ctx *PromptContext) (string, error) {
 	if ctx.Sandbox != nil {
 		// This will be replaced when sandbox.SandboxContext is available
 		// For now, just set to nil to avoid panic
-		templateData.Sandbox = nil
+		data.Sandbox = nil
 	}
 
+	prompt, err := pb.render(data)
+	if err != nil {
+		return "", stats, err
+	}
+	if pb.TokenBudget > 0 && EstimateTokens(prompt) > pb.TokenBudget {
+		data.PromptContext = clonePromptContext(ctx)
+		for _, section := range promptSections {
+			for EstimateTokens(prompt) > pb.TokenBudget {
+				excess := (EstimateTokens(prompt) - pb.TokenBudget) * 4
+				if !section.shrink(data, excess) {
+					break
+				}
+				if len(stats.Truncated) == 0 || stats.Truncated[len(stats.Truncated)-1] != section.name {
+					stats.Truncated = append(stats.Truncated, section.name)
+				}
+				if prompt, err = pb.render(data); err != nil {
+					return "", stats, err
+				}
+			}
+		}
+	}
+
+	stats.Chars = len(prompt)
+	stats.Tokens = EstimateTokens(prompt)
+	return prompt, stats, nil
+}
+
+// sandboxData exposes sandbox fields to the template
+type sandboxData struct {
+	Path          string
+	GitBranch     string
+	BeadsDB       string
+	ModifiedFiles []string
+}
+
+// promptData is what the prompt template renders
+type promptData struct {
+	*PromptContext
+	Sandbox         *sandboxData
+	IsBaselineIssue bool
+	AttemptCount    int            // Previous attempts, including ones truncated away
+	Omitted         map[string]int // List items truncated away, by section
+}
+
+func (pb *PromptBuilder) render(data *promptData) (string, error) {
 	var buf bytes.Buffer
-	if err := pb.template.Execute(&buf, templateData); err != nil {
+	if err := pb.template.Execute(&buf, data); err != nil {
 		return "", fmt.Errorf("failed to execute prompt template: %w", err)
 	}
-
 	return buf.String(), nil
 }
 
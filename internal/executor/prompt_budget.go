@@ -0,0 +1,197 @@
+package executor
+
+import (
+	"strings"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// DefaultPromptTokenBudget bounds agent prompts for agent types without an
+// executor.prompt_token_budgets entry. It leaves room in a 200k context
+// window for the agent's own work.
+const DefaultPromptTokenBudget = 150000
+
+// truncatedMarker ends every section shortened to fit the prompt budget
+const truncatedMarker = "\n[truncated]"
+
+// EstimateTokens is a cheap token estimate for prompt budgeting: about four
+// bytes per token, which errs on the high side for English prose and code.
+func EstimateTokens(s string) int {
+	return (len(s) + 3) / 4
+}
+
+// PromptStats describes a built prompt
+type PromptStats struct {
+	Chars     int      // Prompt length in bytes
+	Tokens    int      // Estimated tokens (EstimateTokens)
+	Budget    int      // Token budget it was built for (0 = unbounded)
+	Truncated []string // Sections shortened to fit, in the order they were cut
+}
+
+// OverBudget reports whether the prompt still exceeds its budget after
+// truncation (the sections that are never truncated are too large)
+func (s PromptStats) OverBudget() bool {
+	return s.Budget > 0 && s.Tokens > s.Budget
+}
+
+// PromptBudgetFor returns the prompt token budget of an agent type: its
+// entry in budgets (executor.prompt_token_budgets), else the default
+func PromptBudgetFor(budgets map[string]int, agentType AgentType) int {
+	if budget, ok := budgets[string(agentType)]; ok {
+		return budget
+	}
+	return DefaultPromptTokenBudget
+}
+
+// promptSection is a truncatable part of the prompt. shrink cuts about
+// excess bytes from the section in data and reports whether anything was
+// left to cut.
+type promptSection struct {
+	name   string
+	shrink func(data *promptData, excess int) bool
+}
+
+// promptSections lists the truncatable sections, first truncated first.
+// History and file lists go before the issue's own text; the title, the
+// acceptance criteria, the plan and the directives are never truncated.
+var promptSections = []promptSection{
+	{name: "history", shrink: shrinkHistory},
+	{name: "modified_files", shrink: func(data *promptData, excess int) bool {
+		if data.GitState == nil {
+			return false
+		}
+		var dropped int
+		data.GitState.ModifiedFiles, dropped = dropItems(data.GitState.ModifiedFiles, excess, func(f string) int { return len(f) + 3 })
+		data.Omitted["modified_files"] += dropped
+		return dropped > 0
+	}},
+	{name: "siblings", shrink: func(data *promptData, excess int) bool {
+		if data.RelatedIssues == nil {
+			return false
+		}
+		var dropped int
+		data.RelatedIssues.Siblings, dropped = dropItems(data.RelatedIssues.Siblings, excess, issueLineSize)
+		data.Omitted["siblings"] += dropped
+		return dropped > 0
+	}},
+	{name: "dependents", shrink: func(data *promptData, excess int) bool {
+		if data.RelatedIssues == nil {
+			return false
+		}
+		var dropped int
+		data.RelatedIssues.Dependents, dropped = dropItems(data.RelatedIssues.Dependents, excess, issueLineSize)
+		data.Omitted["dependents"] += dropped
+		return dropped > 0
+	}},
+	{name: "notes", shrink: func(data *promptData, excess int) bool {
+		return shrinkText(&data.Issue.Notes, excess)
+	}},
+	{name: "resume_hint", shrink: func(data *promptData, excess int) bool {
+		return shrinkText(&data.ResumeHint, excess)
+	}},
+	{name: "mission", shrink: func(data *promptData, excess int) bool {
+		if data.ParentMission == nil {
+			return false
+		}
+		return shrinkText(&data.ParentMission.Description, excess)
+	}},
+	{name: "design", shrink: func(data *promptData, excess int) bool {
+		return shrinkText(&data.Issue.Design, excess)
+	}},
+	{name: "description", shrink: func(data *promptData, excess int) bool {
+		return shrinkText(&data.Issue.Description, excess)
+	}},
+}
+
+// shrinkHistory drops the oldest previous attempts, keeping the latest, then
+// shortens the latest one's summary
+func shrinkHistory(data *promptData, excess int) bool {
+	attempts := data.PreviousAttempts
+	if len(attempts) == 0 {
+		return false
+	}
+	dropped := 0
+	for len(attempts) > 1 && excess > 0 {
+		excess -= attemptSize(attempts[0])
+		attempts = attempts[1:]
+		dropped++
+	}
+	data.Omitted["history"] += dropped
+	data.PreviousAttempts = attempts
+	if excess <= 0 {
+		return dropped > 0
+	}
+	latest := *attempts[0]
+	if !shrinkText(&latest.Summary, excess) {
+		return dropped > 0
+	}
+	data.PreviousAttempts = []*types.ExecutionAttempt{&latest}
+	return true
+}
+
+// attemptSize approximates the bytes an attempt takes in the prompt
+func attemptSize(a *types.ExecutionAttempt) int {
+	size := 120 + len(a.Summary)
+	if a.ErrorSample != "" {
+		size += 210
+	}
+	return size
+}
+
+func issueLineSize(issue *types.Issue) int {
+	return len(issue.ID) + len(issue.Title) + 20
+}
+
+// dropItems removes items from the end of a list until about excess bytes
+// are gone, returning the rest and how many were dropped
+func dropItems[T any](items []T, excess int, size func(T) int) ([]T, int) {
+	n := len(items)
+	for n > 0 && excess > 0 {
+		n--
+		excess -= size(items[n])
+	}
+	return items[:n], len(items) - n
+}
+
+// clonePromptContext copies the parts of ctx truncation modifies, so
+// shrinking a prompt never changes the caller's issue or context
+func clonePromptContext(ctx *PromptContext) *PromptContext {
+	clone := *ctx
+	issue := *ctx.Issue
+	clone.Issue = &issue
+	if ctx.ParentMission != nil {
+		mission := *ctx.ParentMission
+		clone.ParentMission = &mission
+	}
+	if ctx.RelatedIssues != nil {
+		related := *ctx.RelatedIssues
+		clone.RelatedIssues = &related
+	}
+	if ctx.GitState != nil {
+		git := *ctx.GitState
+		clone.GitState = &git
+	}
+	return &clone
+}
+
+// shrinkText cuts about excess bytes from the end of s and appends the
+// truncated marker. Reports whether s was shortened.
+func shrinkText(s *string, excess int) bool {
+	text := strings.TrimSuffix(*s, truncatedMarker)
+	if text == "" {
+		return false
+	}
+	keep := len(text) - excess - len(truncatedMarker)
+	if keep < 0 {
+		keep = 0
+	}
+	// Don't split a UTF-8 sequence
+	for keep > 0 && keep < len(text) && text[keep]&0xC0 == 0x80 {
+		keep--
+	}
+	if keep >= len(text) {
+		return false
+	}
+	*s = text[:keep] + truncatedMarker
+	return true
+}
@@ -0,0 +1,171 @@
+package executor
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// oversizedContext builds a context whose history alone is far over any
+// small budget, with every truncatable section populated
+func oversizedContext() *PromptContext {
+	var attempts []*types.ExecutionAttempt
+	for i := 1; i <= 40; i++ {
+		attempts = append(attempts, &types.ExecutionAttempt{
+			AttemptNumber: i,
+			StartedAt:     time.Date(2025, 1, i%28+1, 0, 0, 0, 0, time.UTC),
+			Summary:       fmt.Sprintf("attempt-%d ", i) + strings.Repeat("history ", 200),
+		})
+	}
+	var siblings []*types.Issue
+	for i := 0; i < 50; i++ {
+		siblings = append(siblings, &types.Issue{ID: fmt.Sprintf("vc-%d", 100+i), Title: "Sibling task", Status: types.StatusOpen})
+	}
+	return &PromptContext{
+		Issue: &types.Issue{
+			ID:                 "vc-1",
+			Title:              "Keep this title",
+			Description:        strings.Repeat("description ", 2000),
+			Design:             strings.Repeat("design ", 2000),
+			Notes:              strings.Repeat("notes ", 2000),
+			AcceptanceCriteria: "- ACCEPTANCE CRITERIA STAY " + strings.Repeat("criterion ", 100),
+		},
+		RelatedIssues:    &RelatedIssues{Siblings: siblings},
+		PreviousAttempts: attempts,
+	}
+}
+
+func TestBuildPromptWithStats_RespectsBudget(t *testing.T) {
+	pb, err := NewPromptBuilder()
+	if err != nil {
+		t.Fatalf("NewPromptBuilder() failed: %v", err)
+	}
+	pb.TokenBudget = 6000
+	ctx := oversizedContext()
+
+	prompt, stats, err := pb.BuildPromptWithStats(ctx)
+	if err != nil {
+		t.Fatalf("BuildPromptWithStats() failed: %v", err)
+	}
+	if stats.Tokens > pb.TokenBudget || stats.OverBudget() {
+		t.Errorf("Prompt is %d tokens, over the %d budget", stats.Tokens, pb.TokenBudget)
+	}
+	if stats.Tokens != EstimateTokens(prompt) || stats.Chars != len(prompt) {
+		t.Errorf("Stats don't describe the prompt: %+v", stats)
+	}
+
+	for _, want := range []string{"Keep this title", ctx.Issue.AcceptanceCriteria, "[truncated", "attempted 40 time(s)", "Attempt #40"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("Expected prompt to contain %q", truncate(want, 60))
+		}
+	}
+	// The oldest attempts go first; the latest one is kept
+	if strings.Contains(prompt, "Attempt #1 ") {
+		t.Error("Expected the oldest attempt to be truncated away")
+	}
+
+	// The caller's context is unchanged
+	if len(ctx.PreviousAttempts) != 40 || len(ctx.RelatedIssues.Siblings) != 50 || strings.Contains(ctx.Issue.Notes, "[truncated]") {
+		t.Error("BuildPromptWithStats modified the caller's context")
+	}
+}
+
+func TestBuildPromptWithStats_PriorityOrder(t *testing.T) {
+	pb, err := NewPromptBuilder()
+	if err != nil {
+		t.Fatalf("NewPromptBuilder() failed: %v", err)
+	}
+	ctx := oversizedContext()
+	full, fullStats, err := pb.BuildPromptWithStats(ctx)
+	if err != nil {
+		t.Fatalf("BuildPromptWithStats() failed: %v", err)
+	}
+	if len(fullStats.Truncated) != 0 || strings.Contains(full, "[truncated") {
+		t.Fatalf("Expected the default budget to fit, got %+v", fullStats)
+	}
+
+	order := make(map[string]int)
+	for i, section := range promptSections {
+		order[section.name] = i
+	}
+	// Tighter budgets truncate further down the priority list, always in order
+	for _, budget := range []int{15000, 10000, 6000, 3000} {
+		pb.TokenBudget = budget
+		prompt, stats, err := pb.BuildPromptWithStats(oversizedContext())
+		if err != nil {
+			t.Fatalf("BuildPromptWithStats() failed: %v", err)
+		}
+		if len(stats.Truncated) == 0 || stats.Truncated[0] != "history" {
+			t.Errorf("budget %d: expected history to be truncated first, got %v", budget, stats.Truncated)
+		}
+		for i := 1; i < len(stats.Truncated); i++ {
+			if order[stats.Truncated[i-1]] >= order[stats.Truncated[i]] {
+				t.Errorf("budget %d: sections truncated out of order: %v", budget, stats.Truncated)
+			}
+		}
+		// The description is only cut once everything before it is
+		if slices.Contains(stats.Truncated, "description") {
+			for _, earlier := range []string{"history", "siblings", "notes", "design"} {
+				if !slices.Contains(stats.Truncated, earlier) {
+					t.Errorf("budget %d: description truncated before %s: %v", budget, earlier, stats.Truncated)
+				}
+			}
+		}
+		if !strings.Contains(prompt, "Keep this title") || !strings.Contains(prompt, ctx.Issue.AcceptanceCriteria) {
+			t.Errorf("budget %d: title or acceptance criteria were truncated", budget)
+		}
+	}
+}
+
+func TestBuildPromptWithStats_OverBudget(t *testing.T) {
+	pb, err := NewPromptBuilder()
+	if err != nil {
+		t.Fatalf("NewPromptBuilder() failed: %v", err)
+	}
+	pb.TokenBudget = 1000
+	ctx := &PromptContext{Issue: &types.Issue{
+		ID:                 "vc-1",
+		Title:              "Huge criteria",
+		AcceptanceCriteria: strings.Repeat("must ", 5000),
+	}}
+
+	prompt, stats, err := pb.BuildPromptWithStats(ctx)
+	if err != nil {
+		t.Fatalf("BuildPromptWithStats() failed: %v", err)
+	}
+	if !stats.OverBudget() {
+		t.Errorf("Expected the prompt to stay over budget, got %+v", stats)
+	}
+	if !strings.Contains(prompt, ctx.Issue.AcceptanceCriteria) {
+		t.Error("Acceptance criteria must never be truncated")
+	}
+}
+
+func TestPromptBudgetFor(t *testing.T) {
+	budgets := map[string]int{"claude-code": 90000}
+	if got := PromptBudgetFor(budgets, AgentTypeClaudeCode); got != 90000 {
+		t.Errorf("PromptBudgetFor(claude-code) = %d, want 90000", got)
+	}
+	if got := PromptBudgetFor(budgets, AgentTypeAmp); got != DefaultPromptTokenBudget {
+		t.Errorf("PromptBudgetFor(amp) = %d, want the default", got)
+	}
+}
+
+func TestShrinkText(t *testing.T) {
+	s := "héllo wörld, this is long"
+	if !shrinkText(&s, 10) || !strings.HasSuffix(s, truncatedMarker) {
+		t.Fatalf("Expected s to be truncated, got %q", s)
+	}
+	if !utf8.ValidString(s) {
+		t.Errorf("Truncation split a UTF-8 sequence: %q", s)
+	}
+	empty := ""
+	if shrinkText(&empty, 10) {
+		t.Error("Expected nothing to cut from an empty string")
+	}
+}
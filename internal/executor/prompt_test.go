@@ -333,6 +333,37 @@ func TestBuildPrompt_WithNotes(t *testing.T) {
 	}
 }
 
+// TestBuildPrompt_WithAssessment tests the plan renders with completed steps checked
+func TestBuildPrompt_WithAssessment(t *testing.T) {
+	pb, err := NewPromptBuilder()
+	if err != nil {
+		t.Fatalf("NewPromptBuilder() failed: %v", err)
+	}
+
+	ctx := &PromptContext{
+		Issue: &types.Issue{ID: "vc-101", Title: "Implement PromptBuilder"},
+		Assessment: &types.AssessmentRecord{
+			IssueID: "vc-101",
+			Attempt: 1,
+			Assessment: types.Assessment{
+				Strategy: "Template first",
+				Steps:    []string{"Write the template", "Wire it up"},
+			},
+			CompletedSteps: []int{0},
+		},
+	}
+
+	prompt, err := pb.BuildPrompt(ctx)
+	if err != nil {
+		t.Fatalf("BuildPrompt() failed: %v", err)
+	}
+	for _, want := range []string{"# PLAN", "**Strategy**: Template first", "- [x] Write the template", "- [ ] Wire it up"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("Prompt missing %q", want)
+		}
+	}
+}
+
 // TestBuildPrompt_NilContext tests error handling for nil context
 func TestBuildPrompt_NilContext(t *testing.T) {
 	pb, err := NewPromptBuilder()
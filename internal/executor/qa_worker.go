@@ -202,6 +202,7 @@ func (w *QualityGateWorker) Execute(ctx context.Context, mission *types.Issue) e
 
 	// Log execution start
 	fmt.Printf("Running quality gates for mission %s in sandbox %s\n", mission.ID, sandboxPath)
+	setMissionGatesStatus(ctx, w.store, mission, types.GatesStatusRunning)
 	startEvent := &events.AgentEvent{
 		Type:      events.EventTypeProgress,
 		Timestamp: time.Now(),
@@ -241,8 +242,10 @@ func (w *QualityGateWorker) Execute(ctx context.Context, mission *types.Issue) e
 	// Handle results and transition states
 	// Pass sandboxRunner to avoid recreating it in handlers
 	if allPassed {
+		setMissionGatesStatus(ctx, w.store, mission, types.GatesStatusPassed)
 		return w.handleGatesPass(ctx, mission, results)
 	} else {
+		setMissionGatesStatus(ctx, w.store, mission, types.GatesStatusFailed)
 		return w.handleGatesFail(ctx, mission, sandboxRunner, results)
 	}
 }
@@ -478,6 +478,15 @@ func TestQAWorkerExecuteSuccess(t *testing.T) {
 		t.Error("Expected needs-review label to be added")
 	}
 
+	// Verify the gates result was recorded in the mission state
+	missionState, err := store.GetMission(ctx, mission.ID)
+	if err != nil {
+		t.Fatalf("Failed to get mission: %v", err)
+	}
+	if missionState.GatesStatus != types.GatesStatusPassed || missionState.LastGatesRun == nil {
+		t.Errorf("Expected gates status passed with a run time, got %q (%v)", missionState.GatesStatus, missionState.LastGatesRun)
+	}
+
 	// Verify mission status is open (ready for next stage)
 	issue, err := store.GetIssue(ctx, mission.ID)
 	if err != nil {
@@ -609,6 +618,15 @@ func TestQAWorkerExecuteFailure(t *testing.T) {
 		t.Errorf("Expected status blocked, got %s", issue.Status)
 	}
 
+	// Verify the gates result was recorded in the mission state
+	missionState, err := store.GetMission(ctx, mission.ID)
+	if err != nil {
+		t.Fatalf("Failed to get mission: %v", err)
+	}
+	if missionState.GatesStatus != types.GatesStatusFailed {
+		t.Errorf("Expected gates status failed, got %q", missionState.GatesStatus)
+	}
+
 	// Verify execution state was released
 	execState, err := store.GetExecutionState(ctx, mission.ID)
 	if err != nil {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sort"
 
 	"github.com/steveyegge/vc/internal/ai"
 	"github.com/steveyegge/vc/internal/deduplication"
@@ -11,9 +12,10 @@ import (
 	"github.com/steveyegge/vc/internal/types"
 )
 
-// deduplicateDiscoveredIssues uses the deduplicator to filter out duplicate discovered issues
-// Returns the unique issues to create and deduplication statistics
-func (rp *ResultsProcessor) deduplicateDiscoveredIssues(ctx context.Context, parentIssue *types.Issue, discovered []ai.DiscoveredIssue) ([]ai.DiscoveredIssue, deduplication.DeduplicationStats) {
+// deduplicateDiscoveredIssues uses the deduplicator to filter out duplicate discovered issues.
+// Existing issues that a discovered issue duplicates are cross-referenced with
+// the parent instead. Returns the unique issues to create and deduplication statistics
+func (rp *ResultsProcessor) deduplicateDiscoveredIssues(ctx context.Context, parentIssue *types.Issue, discovered []ai.DiscoveredIssue, origin ai.DiscoveryOrigin) ([]ai.DiscoveredIssue, deduplication.DeduplicationStats) {
 	// Convert discovered issues to types.Issue for deduplication
 	candidates := make([]*types.Issue, len(discovered))
 	for i, disc := range discovered {
@@ -57,8 +59,8 @@ func (rp *ResultsProcessor) deduplicateDiscoveredIssues(ctx context.Context, par
 	// vc-151: Log deduplication batch started event
 	rp.logDeduplicationBatchStarted(ctx, parentIssue.ID, len(candidates), parentIssue.ID)
 
-	// Deduplicate
-	result, err := rp.deduplicator.DeduplicateBatch(ctx, candidates)
+	// Deduplicate; the epic and label scopes compare within the parent issue's
+	result, err := rp.deduplicator.DeduplicateBatch(deduplication.WithScopeIssue(ctx, parentIssue.ID), candidates)
 	if err != nil {
 		// Fail-safe: on error, return all discovered issues
 		fmt.Fprintf(os.Stderr, "Warning: deduplication failed, creating all discovered issues: %v\n", err)
@@ -69,6 +71,7 @@ func (rp *ResultsProcessor) deduplicateDiscoveredIssues(ctx context.Context, par
 
 	// vc-151: Log deduplication batch completed event with stats and individual decisions
 	rp.logDeduplicationBatchCompleted(ctx, parentIssue.ID, result, nil)
+	rp.linkDuplicatesToOrigin(ctx, parentIssue, candidates, result.DuplicatePairs, origin)
 
 	// Build list of unique discovered issues to create
 	// We need to map back from unique issues to original DiscoveredIssue objects
@@ -90,6 +93,41 @@ func (rp *ResultsProcessor) deduplicateDiscoveredIssues(ctx context.Context, par
 	return uniqueDiscovered, result.Stats
 }
 
+// linkDuplicatesToOrigin cross-references each existing issue a discovered
+// issue duplicates with the parent it was discovered in: a related dependency
+// and a comment on both, so the surviving issue records the new sighting
+func (rp *ResultsProcessor) linkDuplicatesToOrigin(ctx context.Context, parentIssue *types.Issue, candidates []*types.Issue, duplicatePairs map[int]string, origin ai.DiscoveryOrigin) {
+	indices := make([]int, 0, len(duplicatePairs))
+	for i := range duplicatePairs {
+		indices = append(indices, i)
+	}
+	sort.Ints(indices)
+
+	linked := make(map[string]bool)
+	for _, i := range indices {
+		existingID := duplicatePairs[i]
+		if existingID == "" || existingID == parentIssue.ID || i >= len(candidates) {
+			continue
+		}
+		if !linked[existingID] {
+			linked[existingID] = true
+			dep := &types.Dependency{IssueID: existingID, DependsOnID: parentIssue.ID, Type: types.DepRelated}
+			if err := rp.store.AddDependency(ctx, dep, "ai-supervisor"); err != nil {
+				fmt.Fprintf(os.Stderr, "warning: failed to link %s to %s: %v\n", existingID, parentIssue.ID, err)
+			}
+		}
+
+		sighting := fmt.Sprintf("Discovered again during execution of %s: %q", origin.Describe(parentIssue.ID), candidates[i].Title)
+		if err := rp.store.AddComment(ctx, existingID, "ai-supervisor", sighting); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to add discovery comment to %s: %v\n", existingID, err)
+		}
+		reference := fmt.Sprintf("Discovered issue %q duplicates %s; linked there instead of filing a new issue", candidates[i].Title, existingID)
+		if err := rp.store.AddComment(ctx, parentIssue.ID, "ai-supervisor", reference); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to add duplicate reference comment: %v\n", err)
+		}
+	}
+}
+
 // logDeduplicationBatchStarted logs a deduplication batch start event (vc-151)
 func (rp *ResultsProcessor) logDeduplicationBatchStarted(ctx context.Context, issueID string, candidateCount int, parentIssueID string) {
 	// Skip logging if context is canceled
@@ -113,6 +151,7 @@ func (rp *ResultsProcessor) logDeduplicationBatchStarted(ctx context.Context, is
 		return
 	}
 
+	rp.stampAIModel(event)
 	if err := rp.store.StoreAgentEvent(ctx, event); err != nil {
 		fmt.Fprintf(os.Stderr, "warning: failed to store deduplication batch started event: %v\n", err)
 	}
@@ -173,6 +212,7 @@ func (rp *ResultsProcessor) logDeduplicationBatchCompleted(ctx context.Context,
 		return
 	}
 
+	rp.stampAIModel(batchEvent)
 	if err := rp.store.StoreAgentEvent(ctx, batchEvent); err != nil {
 		fmt.Fprintf(os.Stderr, "warning: failed to store deduplication batch completed event: %v\n", err)
 		return
@@ -233,6 +273,7 @@ func (rp *ResultsProcessor) logDeduplicationDecision(ctx context.Context, issueI
 		return
 	}
 
+	rp.stampAIModel(event)
 	if err := rp.store.StoreAgentEvent(ctx, event); err != nil {
 		fmt.Fprintf(os.Stderr, "warning: failed to store deduplication decision event: %v\n", err)
 	}
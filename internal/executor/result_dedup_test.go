@@ -0,0 +1,89 @@
+package executor
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/ai"
+	"github.com/steveyegge/vc/internal/deduplication"
+	"github.com/steveyegge/vc/internal/storage/storagetest"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// firstDuplicateDeduplicator reports the first candidate of a batch as a
+// duplicate of an existing issue and the rest as unique
+type firstDuplicateDeduplicator struct {
+	existingID string
+}
+
+func (d *firstDuplicateDeduplicator) CheckDuplicate(ctx context.Context, candidate *types.Issue) (*deduplication.DuplicateDecision, error) {
+	return &deduplication.DuplicateDecision{IsDuplicate: true, DuplicateOf: d.existingID, Confidence: 0.9}, nil
+}
+
+func (d *firstDuplicateDeduplicator) DeduplicateBatch(ctx context.Context, candidates []*types.Issue) (*deduplication.DeduplicationResult, error) {
+	return &deduplication.DeduplicationResult{
+		UniqueIssues:   candidates[1:],
+		DuplicatePairs: map[int]string{0: d.existingID},
+	}, nil
+}
+
+// TestDeduplicateDiscoveredIssues_LinksSurvivor verifies a discovered issue
+// that duplicates an existing one is cross-referenced there instead of filed
+func TestDeduplicateDiscoveredIssues_LinksSurvivor(t *testing.T) {
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+	parent := &types.Issue{Title: "Refactor the parser", Status: types.StatusInProgress, Priority: 1, IssueType: types.TypeTask}
+	existing := &types.Issue{Title: "Parser leaks file handles", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeBug}
+	for _, issue := range []*types.Issue{parent, existing} {
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("Failed to create issue: %v", err)
+		}
+	}
+
+	rp, err := NewResultsProcessor(&ResultsProcessorConfig{
+		Store:        store,
+		Deduplicator: &firstDuplicateDeduplicator{existingID: existing.ID},
+		Actor:        "test",
+	})
+	if err != nil {
+		t.Fatalf("NewResultsProcessor: %v", err)
+	}
+
+	unique, _ := rp.deduplicateDiscoveredIssues(ctx, parent, []ai.DiscoveredIssue{
+		{Title: "File handles are never closed", Type: "bug"},
+		{Title: "Add parser benchmarks", Type: "task"},
+	}, ai.DiscoveryOrigin{Attempt: 2, CommitHash: "abcdef1234567890"})
+
+	if len(unique) != 1 || unique[0].Title != "Add parser benchmarks" {
+		t.Fatalf("Expected only the unique issue to be created, got %+v", unique)
+	}
+
+	records, err := store.GetDependencyRecords(ctx, existing.ID)
+	if err != nil {
+		t.Fatalf("GetDependencyRecords: %v", err)
+	}
+	if len(records) != 1 || records[0].DependsOnID != parent.ID || records[0].Type != types.DepRelated {
+		t.Errorf("Expected %s to be related to %s, got %+v", existing.ID, parent.ID, records)
+	}
+
+	wantComments := map[string]string{
+		existing.ID: "Discovered again during execution of " + parent.ID + " (attempt 2, commit abcdef12)",
+		parent.ID:   "duplicates " + existing.ID,
+	}
+	for issueID, want := range wantComments {
+		history, err := store.GetEvents(ctx, issueID, 0)
+		if err != nil {
+			t.Fatalf("GetEvents: %v", err)
+		}
+		var found bool
+		for _, event := range history {
+			if event.EventType == types.EventCommented && event.Comment != nil && strings.Contains(*event.Comment, want) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Expected a comment on %s containing %q", issueID, want)
+		}
+	}
+}
@@ -219,6 +219,7 @@ func (rp *ResultsProcessor) autoCommit(ctx context.Context, issue *types.Issue)
 		CoAuthors: []string{
 			"Claude <noreply@anthropic.com>",
 		},
+		Trailers:   []string{git.ClosesTrailer(issue.ID)},
 		AddAll:     true, // Stage all changes
 		AllowEmpty: false,
 	}
@@ -2,6 +2,7 @@ package executor
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
 	"os"
 	"strings"
@@ -49,26 +50,20 @@ _This issue was automatically created by AI code review analysis._`,
 		Assignee:    "ai-supervisor",
 	}
 
-	err := rp.store.CreateIssue(ctx, reviewIssue, "ai-supervisor")
+	// Add blocking dependency: parent issue is blocked by review issue
+	// This ensures the parent can't be considered "done" until review is complete.
+	// The issue and dependency are created together or not at all.
+	dep := &types.Dependency{
+		IssueID: parentIssue.ID,  // Parent issue depends on the new review issue
+		Type:    types.DepBlocks, // Review blocks parent
+	}
+	err := rp.store.CreateIssueWithMetadata(ctx, reviewIssue, nil, []*types.Dependency{dep}, "ai-supervisor")
 	if err != nil {
 		return "", fmt.Errorf("failed to create code review issue: %w", err)
 	}
 
 	reviewIssueID := reviewIssue.ID
 
-	// Add blocking dependency: parent issue is blocked by review issue
-	// This ensures the parent can't be considered "done" until review is complete
-	dep := &types.Dependency{
-		IssueID:     parentIssue.ID,          // Parent issue
-		DependsOnID: reviewIssueID,           // Depends on review issue
-		Type:        types.DepBlocks,         // Review blocks parent
-	}
-	if err := rp.store.AddDependency(ctx, dep, "ai-supervisor"); err != nil {
-		// Log warning but don't fail - issue was created successfully
-		fmt.Fprintf(os.Stderr, "warning: failed to add blocking dependency %s -> %s: %v\n",
-			parentIssue.ID, reviewIssueID, err)
-	}
-
 	// Add comment to parent issue about code review
 	reviewComment := fmt.Sprintf("Code review issue created: %s\n\nThis issue is now blocked pending code review.", reviewIssueID)
 	if err := rp.store.AddComment(ctx, parentIssue.ID, "ai-supervisor", reviewComment); err != nil {
@@ -80,10 +75,11 @@ _This issue was automatically created by AI code review analysis._`,
 
 // createQualityIssues creates blocking quality fix issues from automated code analysis (vc-216)
 // Each issue represents a specific fix that should be addressed.
-// If individual issue creation fails, continues creating remaining issues and collects all errors.
+// All issues are created in one batch. If individual issue creation fails, the remaining
+// issues are still created and all errors are collected.
 func (rp *ResultsProcessor) createQualityIssues(ctx context.Context, parentIssue *types.Issue, commitHash string, qualityIssues []ai.DiscoveredIssue) ([]string, error) {
-	var createdIssues []string
-	var errors []error
+	fixIssues := make([]*types.Issue, len(qualityIssues))
+	deps := make([]*types.Dependency, len(qualityIssues))
 
 	for i, qualityIssue := range qualityIssues {
 		// Create issue title with commit reference
@@ -138,31 +134,30 @@ _This issue was automatically created by AI code quality analysis (vc-216)._`,
 			Assignee:    "ai-supervisor",
 		}
 
-		err := rp.store.CreateIssue(ctx, fixIssue, "ai-supervisor")
-		if err != nil {
-			// Collect error but continue creating remaining issues
-			errors = append(errors, fmt.Errorf("failed to create quality fix issue %d (%s): %w", i+1, title, err))
-			fmt.Fprintf(os.Stderr, "warning: failed to create quality fix issue %d (%s): %v (continuing with remaining issues)\n", i+1, title, err)
-			continue
-		}
-
-		fixIssueID := fixIssue.ID
-		createdIssues = append(createdIssues, fixIssueID)
+		fixIssues[i] = fixIssue
 
 		// Add blocking dependency: parent issue is blocked by this fix issue
-		// This ensures the parent can't be considered "done" until quality issues are addressed
-		dep := &types.Dependency{
-			IssueID:     parentIssue.ID,    // Parent issue
-			DependsOnID: fixIssueID,        // Depends on fix issue
-			Type:        types.DepBlocks,   // Fix blocks parent
+		// This ensures the parent can't be considered "done" until quality issues are addressed.
+		// The issue and dependency are created together or not at all.
+		deps[i] = &types.Dependency{
+			IssueID: parentIssue.ID,  // Parent issue depends on the new fix issue
+			Type:    types.DepBlocks, // Fix blocks parent
 		}
-		if err := rp.store.AddDependency(ctx, dep, "ai-supervisor"); err != nil {
-			// Log warning but don't fail - issue was created successfully
-			fmt.Fprintf(os.Stderr, "warning: failed to add blocking dependency %s -> %s: %v\n",
-				parentIssue.ID, fixIssueID, err)
+	}
+
+	var createdIssues []string
+	var errors []error
+	failed := rp.createIssueBatch(ctx, fixIssues, deps)
+	for i, fixIssue := range fixIssues {
+		if err, ok := failed[i]; ok {
+			// Collect error; the remaining issues were still created
+			errors = append(errors, fmt.Errorf("failed to create quality fix issue %d (%s): %w", i+1, fixIssue.Title, err))
+			fmt.Fprintf(os.Stderr, "warning: failed to create quality fix issue %d (%s): %v (continuing with remaining issues)\n", i+1, fixIssue.Title, err)
+			continue
 		}
 
-		fmt.Printf("  ✓ Created %s (%s, P%d): %s\n", fixIssueID, issueType, priority, title)
+		createdIssues = append(createdIssues, fixIssue.ID)
+		fmt.Printf("  ✓ Created %s (%s, P%d): %s\n", fixIssue.ID, fixIssue.IssueType, fixIssue.Priority, fixIssue.Title)
 	}
 
 	// Add comment to parent issue about quality issues
@@ -189,9 +184,10 @@ _This issue was automatically created by AI code quality analysis (vc-216)._`,
 }
 
 // createTestIssues creates test improvement issues from test coverage analysis (vc-217)
+// All issues are created in one batch; failures are collected without stopping the rest.
 func (rp *ResultsProcessor) createTestIssues(ctx context.Context, parentIssue *types.Issue, testIssues []ai.DiscoveredIssue) ([]string, error) {
-	var createdIssues []string
-	var errors []error
+	newIssues := make([]*types.Issue, len(testIssues))
+	deps := make([]*types.Dependency, len(testIssues))
 
 	for i, testIssue := range testIssues {
 		title := testIssue.Title
@@ -241,27 +237,28 @@ _This issue was automatically created by AI test coverage analysis._`,
 			Assignee:    "ai-supervisor",
 		}
 
-		err := rp.store.CreateIssue(ctx, newIssue, "ai-supervisor")
-		if err != nil {
-			errors = append(errors, fmt.Errorf("failed to create test issue %d (%s): %w", i+1, title, err))
-			fmt.Fprintf(os.Stderr, "warning: failed to create test issue %d (%s): %v\n", i+1, title, err)
-			continue
-		}
+		newIssues[i] = newIssue
 
-		createdIssues = append(createdIssues, newIssue.ID)
-
-		// Add related dependency (not blocking - these are follow-on improvements)
-		dep := &types.Dependency{
-			IssueID:     newIssue.ID,             // Test issue
-			DependsOnID: parentIssue.ID,          // Related to parent
+		// Add related dependency (not blocking - these are follow-on improvements).
+		// The issue and dependency are created together or not at all.
+		deps[i] = &types.Dependency{
+			DependsOnID: parentIssue.ID,          // New test issue is related to parent
 			Type:        types.DepDiscoveredFrom, // Discovered from parent work
 		}
-		if err := rp.store.AddDependency(ctx, dep, "ai-supervisor"); err != nil {
-			fmt.Fprintf(os.Stderr, "warning: failed to add dependency %s -> %s: %v\n",
-				newIssue.ID, parentIssue.ID, err)
+	}
+
+	var createdIssues []string
+	var errors []error
+	failed := rp.createIssueBatch(ctx, newIssues, deps)
+	for i, newIssue := range newIssues {
+		if err, ok := failed[i]; ok {
+			errors = append(errors, fmt.Errorf("failed to create test issue %d (%s): %w", i+1, newIssue.Title, err))
+			fmt.Fprintf(os.Stderr, "warning: failed to create test issue %d (%s): %v\n", i+1, newIssue.Title, err)
+			continue
 		}
 
-		fmt.Printf("  ✓ Created %s (%s, P%d): %s\n", newIssue.ID, issueType, priority, title)
+		createdIssues = append(createdIssues, newIssue.ID)
+		fmt.Printf("  ✓ Created %s (%s, P%d): %s\n", newIssue.ID, newIssue.IssueType, newIssue.Priority, newIssue.Title)
 	}
 
 	// Add comment to parent issue about test issues
@@ -284,3 +281,47 @@ _This issue was automatically created by AI test coverage analysis._`,
 
 	return createdIssues, nil
 }
+
+// discoveryOrigin identifies the execution issues are discovered in now: the
+// issue's latest attempt and the commit it made, if any
+func (rp *ResultsProcessor) discoveryOrigin(ctx context.Context, issueID, commitHash string) ai.DiscoveryOrigin {
+	origin := ai.DiscoveryOrigin{CommitHash: commitHash}
+	history, err := rp.store.GetExecutionHistory(ctx, issueID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to get execution history of %s: %v\n", issueID, err)
+		return origin
+	}
+	if len(history) > 0 {
+		origin.Attempt = history[len(history)-1].AttemptNumber
+	}
+	return origin
+}
+
+// createIssueBatch creates issues, each with its dependency, in one storage
+// batch and returns the errors of the issues that failed, keyed by index.
+func (rp *ResultsProcessor) createIssueBatch(ctx context.Context, newIssues []*types.Issue, deps []*types.Dependency) map[int]error {
+	if len(newIssues) == 0 {
+		return nil
+	}
+
+	opts := types.CreateIssuesOptions{Dependencies: make(map[int][]*types.Dependency, len(deps))}
+	for i, dep := range deps {
+		opts.Dependencies[i] = []*types.Dependency{dep}
+	}
+
+	err := rp.store.CreateIssues(ctx, newIssues, "ai-supervisor", opts)
+	if err == nil {
+		return nil
+	}
+	var batchErr *types.BatchCreateError
+	if stderrors.As(err, &batchErr) {
+		return batchErr.Errors
+	}
+
+	// The batch as a whole failed (e.g. the database was unavailable)
+	failed := make(map[int]error, len(newIssues))
+	for i := range newIssues {
+		failed[i] = err
+	}
+	return failed
+}
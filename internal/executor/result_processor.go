@@ -41,6 +41,11 @@ func NewResultsProcessor(cfg *ResultsProcessorConfig) (*ResultsProcessor, error)
 		actor:              cfg.Actor,
 		sandbox:            cfg.Sandbox,
 		sandboxManager:     cfg.SandboxManager,
+		failureAnalyzer:    newFailureAnalyzer(cfg.Store, cfg.Supervisor, cfg.EnableFailureAnalysis, cfg.FailureAnalysisCostCap, cfg.CommentLimit),
+		triagePolicy:       ai.TriagePolicy{UseInferred: true, ForceTriage: cfg.ForceDiscoveredTriage},
+		autoMergeThreshold: cfg.AutoMergeThreshold,
+		phases:             cfg.Phases,
+		commentLimit:       cfg.CommentLimit,
 	}, nil
 }
 
@@ -71,6 +76,7 @@ func (rp *ResultsProcessor) ProcessAgentResult(ctx context.Context, issue *types
 
 	// Step 1: Extract agent output summary
 	agentOutput := rp.extractSummary(ctx, issue, agentResult)
+	result.AgentSummary = agentOutput
 
 	fmt.Printf("\n=== Agent Execution Complete ===\n")
 	fmt.Printf("Success: %v\n", agentResult.Success)
@@ -109,6 +115,7 @@ func (rp *ResultsProcessor) ProcessAgentResult(ctx context.Context, issue *types
 			// Structured report was handled successfully
 			reportHandled = true
 			result.Completed = completed
+			recordCompletedSteps(ctx, rp.store, issue.ID, agentReport.Completed)
 
 			// For certain statuses, we can skip quality gates and AI analysis
 			switch agentReport.Status {
@@ -155,6 +162,7 @@ func (rp *ResultsProcessor) ProcessAgentResult(ctx context.Context, issue *types
 	// Step 2: AI Analysis (vc-138: skip if structured report was successfully handled)
 	// vc-191: Always transition to analyzing state to maintain state machine integrity
 	// even when AI supervision is disabled or structured report was handled
+	rp.phases.Start(types.ExecutionPhaseAnalysis)
 	if err := rp.store.UpdateExecutionState(ctx, issue.ID, types.ExecutionStateAnalyzing); err != nil {
 		fmt.Fprintf(os.Stderr, "warning: failed to update execution state: %v\n", err)
 	}
@@ -192,9 +200,11 @@ func (rp *ResultsProcessor) ProcessAgentResult(ctx context.Context, issue *types
 			// These are issues discovered during execution, independent of quality gates
 			if len(analysis.DiscoveredIssues) > 0 {
 				// Deduplicate discovered issues (vc-145/vc-147)
+				// (dedup runs before linking: duplicates are cross-referenced on the issues they match)
+				origin := rp.discoveryOrigin(ctx, issue.ID, "")
 				discoveredToCreate := analysis.DiscoveredIssues
 				if rp.deduplicator != nil {
-					uniqueDiscovered, dedupStats := rp.deduplicateDiscoveredIssues(ctx, issue, analysis.DiscoveredIssues)
+					uniqueDiscovered, dedupStats := rp.deduplicateDiscoveredIssues(ctx, issue, analysis.DiscoveredIssues, origin)
 					if len(uniqueDiscovered) < len(analysis.DiscoveredIssues) {
 						fmt.Printf("🔍 Deduplication: %d discovered issues → %d unique (filtered %d duplicates)\n",
 							len(analysis.DiscoveredIssues), len(uniqueDiscovered),
@@ -205,7 +215,7 @@ func (rp *ResultsProcessor) ProcessAgentResult(ctx context.Context, issue *types
 					discoveredToCreate = uniqueDiscovered
 				}
 
-				createdIDs, err := rp.supervisor.CreateDiscoveredIssues(ctx, issue, discoveredToCreate)
+				createdIDs, err := rp.supervisor.CreateDiscoveredIssuesFrom(ctx, issue, discoveredToCreate, origin, rp.triagePolicy)
 				if err != nil {
 					fmt.Fprintf(os.Stderr, "Warning: failed to create discovered issues: %v\n", err)
 				} else if len(createdIDs) > 0 {
@@ -237,6 +247,8 @@ func (rp *ResultsProcessor) ProcessAgentResult(ctx context.Context, issue *types
 		fmt.Printf("\n=== Mission Quality Gates Delegation ===\n")
 		fmt.Printf("Mission detected - deferring quality gates to QA worker\n")
 
+		setMissionGatesStatus(ctx, rp.store, issue, types.GatesStatusPending)
+
 		// Add needs-quality-gates label to trigger QA worker
 		if err := rp.store.AddLabel(ctx, issue.ID, labels.LabelNeedsQualityGates, rp.actor); err != nil {
 			fmt.Fprintf(os.Stderr, "warning: failed to add needs-quality-gates label: %v\n", err)
@@ -282,9 +294,11 @@ func (rp *ResultsProcessor) ProcessAgentResult(ctx context.Context, issue *types
 		}
 
 		// Update execution state to gates
+		rp.phases.Start(types.ExecutionPhaseGates)
 		if err := rp.store.UpdateExecutionState(ctx, issue.ID, types.ExecutionStateGates); err != nil {
 			fmt.Fprintf(os.Stderr, "warning: failed to update execution state: %v\n", err)
 		}
+		setMissionGatesStatus(ctx, rp.store, issue, types.GatesStatusRunning)
 
 		// Log quality gates started
 		gatesStartTime := time.Now()
@@ -323,6 +337,7 @@ func (rp *ResultsProcessor) ProcessAgentResult(ctx context.Context, issue *types
 			fmt.Fprintf(os.Stderr, "Warning: failed to create quality gate runner: %v (skipping gates)\n", err)
 			// Set GatesPassed to false to prevent issue from completing without gates
 			result.GatesPassed = false
+			setMissionGatesStatus(ctx, rp.store, issue, types.GatesStatusFailed)
 			// Log quality gates error
 			rp.logEvent(ctx, events.EventTypeQualityGatesCompleted, events.SeverityError, issue.ID,
 				fmt.Sprintf("Quality gate runner creation failed: %v", err),
@@ -341,6 +356,7 @@ func (rp *ResultsProcessor) ProcessAgentResult(ctx context.Context, issue *types
 			// Run gates with timeout protection
 			var allPassed bool
 			gateResults, allPassed = gateRunner.RunAll(gateCtx)
+			result.GateResults = gateResults
 
 			// Log progress for each gate (vc-245)
 			for i, gateResult := range gateResults {
@@ -421,6 +437,16 @@ func (rp *ResultsProcessor) ProcessAgentResult(ctx context.Context, issue *types
 				result.GatesPassed = allPassed
 			}
 
+			// A canceled run is retried, so its gates are pending again
+			switch {
+			case canceled:
+				setMissionGatesStatus(ctx, rp.store, issue, types.GatesStatusPending)
+			case allPassed:
+				setMissionGatesStatus(ctx, rp.store, issue, types.GatesStatusPassed)
+			default:
+				setMissionGatesStatus(ctx, rp.store, issue, types.GatesStatusFailed)
+			}
+
 			// Handle gate results (creates blocking issues on failure)
 			// Skip this on cancellation - let executor release issue back to open (vc-128)
 			if !canceled {
@@ -535,6 +561,12 @@ func (rp *ResultsProcessor) ProcessAgentResult(ctx context.Context, issue *types
 					fmt.Fprintf(os.Stderr, "warning: failed to update issue to blocked: %v\n", err)
 				}
 
+				rp.failureAnalyzer.analyze(ctx, issue, ai.FailureReport{
+					Reason:     failureReasonGatesFailed,
+					Summary:    agentOutput,
+					GateOutput: failedGateOutput(gateResults),
+				}, nil)
+
 				// Release the execution state
 				if err := rp.releaseExecutionState(ctx, issue.ID); err != nil {
 					return nil, fmt.Errorf("failed to release blocked issue: %w", err)
@@ -564,9 +596,22 @@ func (rp *ResultsProcessor) ProcessAgentResult(ctx context.Context, issue *types
 	}
 
 SkipGates:
+	// Step 3.3: Auto-merge decision
+	// With an auto-merge threshold, a risk score replaces the human approval
+	// gate: safe-looking changes merge, the rest wait for review
+	if agentResult.Success && result.GatesPassed && rp.sandbox != nil && rp.autoMergeThreshold > 0 {
+		if held := rp.decideMerge(ctx, issue); held {
+			if err := rp.releaseExecutionState(ctx, issue.ID); err != nil {
+				return nil, fmt.Errorf("failed to release issue held for review: %w", err)
+			}
+			result.Summary = "Changes held for review - issue blocked"
+			return result, nil
+		}
+	}
+
 	// Step 3.4: Human Approval Gate (vc-145)
 	// If sandboxes are enabled and quality gates passed, require human approval before merging
-	if agentResult.Success && result.GatesPassed && rp.sandbox != nil {
+	if agentResult.Success && result.GatesPassed && rp.sandbox != nil && rp.autoMergeThreshold == 0 {
 		fmt.Printf("\n=== Human Approval Gate ===\n")
 
 		approvalGate, err := gates.NewApprovalGate(&gates.ApprovalConfig{
@@ -642,6 +687,7 @@ SkipGates:
 	// After quality gates pass, always transition to committing state
 	// This must happen before auto-commit to maintain valid state transitions
 	if agentResult.Success && result.GatesPassed {
+		rp.phases.Start(types.ExecutionPhaseMerge)
 		if err := rp.store.UpdateExecutionState(ctx, issue.ID, types.ExecutionStateCommitting); err != nil {
 			fmt.Fprintf(os.Stderr, "warning: failed to update execution state to committing: %v\n", err)
 		}
@@ -792,7 +838,7 @@ SkipGates:
 								// Add analysis summary as comment
 								analysisComment := fmt.Sprintf("**Automated Code Quality Analysis**\n\n%s\n\nConfidence: %.0f%%\nIssues Found: %d",
 									qualityAnalysis.Summary, qualityAnalysis.Confidence*100, len(qualityAnalysis.Issues))
-								if err := rp.store.AddComment(ctx, issue.ID, "ai-supervisor", analysisComment); err != nil {
+								if err := rp.store.AddComment(ctx, issue.ID, "ai-supervisor", rp.commentLimit.Cap(issue.ID, analysisComment)); err != nil {
 									fmt.Fprintf(os.Stderr, "warning: failed to add quality analysis comment: %v\n", err)
 								}
 
@@ -832,11 +878,7 @@ SkipGates:
 
 		// Update issue status
 		if shouldClose {
-			updates := map[string]interface{}{
-				"status":    types.StatusClosed,
-				"closed_at": time.Now(),
-			}
-			if err := rp.store.UpdateIssue(ctx, issue.ID, updates, rp.actor); err != nil {
+			if err := rp.store.CloseIssue(ctx, issue.ID, "Completed: gates passed and AI analysis confirmed completion", rp.actor); err != nil {
 				fmt.Fprintf(os.Stderr, "warning: failed to close issue: %v\n", err)
 			} else {
 				fmt.Printf("\n✓ Issue %s marked as closed\n", issue.ID)
@@ -885,23 +927,25 @@ SkipGates:
 		}
 
 		// Step 5: Add completion comment
-		if err := rp.store.AddComment(ctx, issue.ID, rp.actor, agentOutput); err != nil {
+		if err := rp.store.AddComment(ctx, issue.ID, rp.actor, rp.commentLimit.Cap(issue.ID, agentOutput)); err != nil {
 			fmt.Fprintf(os.Stderr, "warning: failed to add comment: %v\n", err)
 		}
 
 		// Step 6: Add AI analysis comment and create discovered issues
 		if analysis != nil {
 			analysisComment := rp.buildAnalysisComment(analysis)
-			if err := rp.store.AddComment(ctx, issue.ID, "ai-supervisor", analysisComment); err != nil {
+			if err := rp.store.AddComment(ctx, issue.ID, "ai-supervisor", rp.commentLimit.Cap(issue.ID, analysisComment)); err != nil {
 				fmt.Fprintf(os.Stderr, "warning: failed to add analysis comment: %v\n", err)
 			}
 
 			// Create discovered issues (vc-149: deduplicate first)
 			if len(analysis.DiscoveredIssues) > 0 {
 				// Deduplicate discovered issues if deduplicator is available
+				// (dedup runs before linking: duplicates are cross-referenced on the issues they match)
+				origin := rp.discoveryOrigin(ctx, issue.ID, result.CommitHash)
 				discoveredToCreate := analysis.DiscoveredIssues
 				if rp.deduplicator != nil {
-					uniqueDiscovered, dedupStats := rp.deduplicateDiscoveredIssues(ctx, issue, analysis.DiscoveredIssues)
+					uniqueDiscovered, dedupStats := rp.deduplicateDiscoveredIssues(ctx, issue, analysis.DiscoveredIssues, origin)
 					if len(uniqueDiscovered) < len(analysis.DiscoveredIssues) {
 						fmt.Printf("🔍 Deduplication: %d discovered issues → %d unique (filtered %d duplicates)\n",
 							len(analysis.DiscoveredIssues), len(uniqueDiscovered),
@@ -912,7 +956,7 @@ SkipGates:
 					discoveredToCreate = uniqueDiscovered
 				}
 
-				createdIDs, err := rp.supervisor.CreateDiscoveredIssues(ctx, issue, discoveredToCreate)
+				createdIDs, err := rp.supervisor.CreateDiscoveredIssuesFrom(ctx, issue, discoveredToCreate, origin, rp.triagePolicy)
 				if err != nil {
 					fmt.Fprintf(os.Stderr, "warning: failed to create discovered issues: %v\n", err)
 				} else {
@@ -988,6 +1032,16 @@ SkipGates:
 			fmt.Printf("✗ Baseline self-healing failed\n")
 		}
 
+		failureReason := failureReasonAgentFailed
+		if agentResult.Success {
+			failureReason = failureReasonGatesFailed
+		}
+		rp.failureAnalyzer.analyze(ctx, issue, ai.FailureReport{
+			Reason:     failureReason,
+			Summary:    agentOutput,
+			GateOutput: failedGateOutput(gateResults),
+		}, nil)
+
 		// Leave issue in in_progress state but release execution lock
 		if err := rp.releaseExecutionState(ctx, issue.ID); err != nil {
 			return nil, fmt.Errorf("failed to release issue: %w", err)
@@ -1182,6 +1236,7 @@ func (rp *ResultsProcessor) logEvent(ctx context.Context, eventType events.Event
 		Data:       data,
 		SourceLine: 0, // Not applicable for executor-level events
 	}
+	rp.stampAIModel(event)
 
 	if err := rp.store.StoreAgentEvent(ctx, event); err != nil {
 		// Log error but don't fail execution
@@ -1189,6 +1244,13 @@ func (rp *ResultsProcessor) logEvent(ctx context.Context, eventType events.Event
 	}
 }
 
+// stampAIModel records the supervisor's provider and model on AI events
+func (rp *ResultsProcessor) stampAIModel(event *events.AgentEvent) {
+	if rp.supervisor != nil && events.IsAIEvent(event.Type) {
+		event.SetAIModel(rp.supervisor.Provider(), rp.supervisor.Model())
+	}
+}
+
 // logProgressEvent creates and stores a quality gates progress event with type-safe data (vc-273)
 func (rp *ResultsProcessor) logProgressEvent(ctx context.Context, severity events.EventSeverity, issueID, message string, data events.QualityGatesProgressData) {
 	// Skip logging if context is canceled (e.g., during shutdown)
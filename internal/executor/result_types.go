@@ -2,7 +2,9 @@ package executor
 
 import (
 	"github.com/steveyegge/vc/internal/ai"
+	"github.com/steveyegge/vc/internal/artifacts"
 	"github.com/steveyegge/vc/internal/deduplication"
+	"github.com/steveyegge/vc/internal/gates"
 	"github.com/steveyegge/vc/internal/git"
 	"github.com/steveyegge/vc/internal/sandbox"
 	"github.com/steveyegge/vc/internal/storage"
@@ -15,9 +17,18 @@ const (
 	minCodeReviewConfidence = 0.70
 )
 
+// ResultsStore is the part of storage.Storage the results processor uses:
+// the issue and its follow-ups, the agent events it logs, and the execution
+// state it advances and releases
+type ResultsStore interface {
+	storage.IssueStore
+	storage.EventStore
+	storage.ExecutionStateStore
+}
+
 // ResultsProcessor handles post-execution results collection and tracker updates
 type ResultsProcessor struct {
-	store              storage.Storage
+	store              ResultsStore
 	supervisor         *ai.Supervisor
 	deduplicator       deduplication.Deduplicator // Can be nil to disable deduplication
 	gitOps             git.GitOperations
@@ -25,14 +36,19 @@ type ResultsProcessor struct {
 	enableQualityGates bool
 	enableAutoCommit   bool
 	workingDir         string
-	actor              string             // The actor performing the update (e.g., "repl", "executor-instance-id")
-	sandbox            *sandbox.Sandbox   // The sandbox being used (can be nil if sandboxing is disabled)
-	sandboxManager     sandbox.Manager    // Sandbox manager for cleanup operations (can be nil if sandboxing is disabled)
+	actor              string                 // The actor performing the update (e.g., "repl", "executor-instance-id")
+	sandbox            *sandbox.Sandbox       // The sandbox being used (can be nil if sandboxing is disabled)
+	sandboxManager     sandbox.Manager        // Sandbox manager for cleanup operations (can be nil if sandboxing is disabled)
+	failureAnalyzer    *failureAnalyzer       // Diagnoses failed attempts (nil if failure analysis is disabled)
+	triagePolicy       ai.TriagePolicy        // How inferred priorities of discovered issues are applied
+	autoMergeThreshold float64                // Auto-merge score replacing the approval gate (0 = approval gate)
+	phases             *PhaseTimer            // Times analysis, gates and merge (can be nil)
+	commentLimit       artifacts.CommentLimit // Caps the summary and analysis comments
 }
 
 // ResultsProcessorConfig holds configuration for the results processor
 type ResultsProcessorConfig struct {
-	Store              storage.Storage
+	Store              ResultsStore
 	Supervisor         *ai.Supervisor             // Can be nil to disable AI analysis
 	Deduplicator       deduplication.Deduplicator // Can be nil to disable deduplication
 	GitOps             git.GitOperations          // Can be nil to disable auto-commit
@@ -43,14 +59,24 @@ type ResultsProcessorConfig struct {
 	Actor              string           // Actor ID for tracking who made the changes
 	Sandbox            *sandbox.Sandbox // The sandbox being used (can be nil if sandboxing is disabled)
 	SandboxManager     sandbox.Manager  // Sandbox manager for cleanup operations (can be nil if sandboxing is disabled)
+
+	EnableFailureAnalysis  bool    // Ask the supervisor to diagnose failed attempts (needs Supervisor)
+	FailureAnalysisCostCap float64 // Max estimated USD spent on failure analysis per issue (0 = no cap)
+	ForceDiscoveredTriage  bool    // Label every discovered issue needs-triage
+	AutoMergeThreshold     float64 // Auto-merge score at which changes merge without approval (0 = always ask)
+
+	Phases       *PhaseTimer            // The attempt's phase timer, to time analysis, gates and merge (can be nil)
+	CommentLimit artifacts.CommentLimit // Size cap of the summary and analysis comments (zero = no cap)
 }
 
 // ProcessingResult contains the outcome of processing agent results
 type ProcessingResult struct {
-	Completed        bool     // Was the issue marked as completed?
-	DiscoveredIssues []string // IDs of discovered issues created
-	GatesPassed      bool     // Did quality gates pass?
-	CommitHash       string   // Git commit hash (if auto-commit succeeded)
-	Summary          string   // Human-readable summary
-	AIAnalysis       *ai.Analysis // The AI analysis result (if available)
+	Completed        bool            // Was the issue marked as completed?
+	DiscoveredIssues []string        // IDs of discovered issues created
+	GatesPassed      bool            // Did quality gates pass?
+	CommitHash       string          // Git commit hash (if auto-commit succeeded)
+	Summary          string          // Human-readable summary
+	AIAnalysis       *ai.Analysis    // The AI analysis result (if available)
+	AgentSummary     string          // The agent's summary of its work
+	GateResults      []*gates.Result // Quality gate results, full output included (nil if gates didn't run here)
 }
@@ -16,7 +16,7 @@ import (
 // ApprovalGate presents sandbox execution results to a human for review
 // before allowing code changes to be merged to main.
 type ApprovalGate struct {
-	store   storage.Storage
+	store   storage.IssueStore
 	sandbox *sandbox.Sandbox
 	issue   *types.Issue
 	results []*Result // Quality gate results to display
@@ -24,7 +24,7 @@ type ApprovalGate struct {
 
 // ApprovalConfig holds configuration for the approval gate
 type ApprovalConfig struct {
-	Store   storage.Storage
+	Store   storage.IssueStore
 	Sandbox *sandbox.Sandbox
 	Issue   *types.Issue
 	Results []*Result // Quality gate results from prior gates
@@ -53,7 +53,7 @@ type ProgressCallback func(currentGate GateType, gatesCompleted, totalGates int,
 
 // Runner executes quality gates for an issue
 type Runner struct {
-	store            storage.Storage
+	store            storage.IssueStore
 	supervisor       *ai.Supervisor // Optional: for AI-driven recovery strategies
 	workingDir       string
 	provider         GateProvider     // Optional: pluggable gate provider (defaults to built-in)
@@ -62,7 +62,7 @@ type Runner struct {
 
 // Config holds quality gate runner configuration
 type Config struct {
-	Store            storage.Storage
+	Store            storage.IssueStore
 	Supervisor       *ai.Supervisor   // Optional: enables AI-driven recovery strategies (ZFC)
 	WorkingDir       string           // Directory where gate commands are executed
 	Provider         GateProvider     // Optional: pluggable gate provider (defaults to built-in)
@@ -14,7 +14,7 @@ import (
 // EventTracker wraps GitOperations and emits events to the event store
 type EventTracker struct {
 	git        GitOperations
-	store      storage.Storage
+	store      storage.EventStore
 	issueID    string
 	executorID string
 	agentID    string
@@ -23,7 +23,7 @@ type EventTracker struct {
 // EventTrackerConfig holds configuration for the event tracker
 type EventTrackerConfig struct {
 	Git        GitOperations
-	Store      storage.Storage
+	Store      storage.EventStore
 	IssueID    string
 	ExecutorID string
 	AgentID    string
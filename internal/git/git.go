@@ -126,13 +126,15 @@ func (g *Git) CommitChanges(ctx context.Context, repoPath string, opts CommitOpt
 		}
 	}
 
-	// Build commit message with co-authors
+	// Build commit message with trailers and co-authors, in one paragraph
+	// so git reads them all as trailers
 	message := opts.Message
-	if len(opts.CoAuthors) > 0 {
-		message += "\n"
-		for _, coAuthor := range opts.CoAuthors {
-			message += fmt.Sprintf("\nCo-Authored-By: %s", coAuthor)
-		}
+	trailers := append([]string{}, opts.Trailers...)
+	for _, coAuthor := range opts.CoAuthors {
+		trailers = append(trailers, fmt.Sprintf("Co-Authored-By: %s", coAuthor))
+	}
+	if len(trailers) > 0 {
+		message += "\n\n" + strings.Join(trailers, "\n")
 	}
 
 	// Build commit command
@@ -92,7 +92,8 @@ func TestGitOperations(t *testing.T) {
 	// Test 4: Commit changes
 	t.Run("CommitChanges", func(t *testing.T) {
 		opts := CommitOptions{
-			Message: "test: add test file\n\nThis is a test commit.",
+			Message:  "test: add test file\n\nThis is a test commit.",
+			Trailers: []string{ClosesTrailer("vc-87")},
 			CoAuthors: []string{
 				"Claude <noreply@anthropic.com>",
 			},
@@ -140,6 +141,17 @@ func TestGitOperations(t *testing.T) {
 		if !strings.Contains(message, "Co-Authored-By: Claude <noreply@anthropic.com>") {
 			t.Errorf("Commit message doesn't contain co-author: %s", message)
 		}
+
+		// git reads the Closes: line as a trailer of the commit
+		cmd = exec.Command("git", "log", "-1", "--format=%(trailers:key=Closes,valueonly)")
+		cmd.Dir = tmpDir
+		output, err = cmd.Output()
+		if err != nil {
+			t.Fatalf("Failed to get commit trailers: %v", err)
+		}
+		if strings.TrimSpace(string(output)) != "vc-87" {
+			t.Errorf("Expected a Closes: vc-87 trailer, got %q", output)
+		}
 	})
 
 	// Test 6: Modify file and commit again
@@ -5,21 +5,21 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/anthropics/anthropic-sdk-go"
 	"github.com/steveyegge/vc/internal/ai"
 )
 
 // MessageGenerator generates commit messages using AI.
 type MessageGenerator struct {
-	client        *anthropic.Client
+	provider      ai.Provider
 	model         string
 	retryAttempts int
 }
 
-// NewMessageGenerator creates a new MessageGenerator.
-func NewMessageGenerator(client *anthropic.Client, model string) *MessageGenerator {
+// NewMessageGenerator creates a new MessageGenerator that asks provider,
+// using model.
+func NewMessageGenerator(provider ai.Provider, model string) *MessageGenerator {
 	return &MessageGenerator{
-		client:        client,
+		provider:      provider,
 		model:         model,
 		retryAttempts: 3,
 	}
@@ -29,14 +29,12 @@ func NewMessageGenerator(client *anthropic.Client, model string) *MessageGenerat
 func (m *MessageGenerator) GenerateCommitMessage(ctx context.Context, req CommitMessageRequest) (*CommitMessageResponse, error) {
 	prompt := m.buildPrompt(req)
 
-	var response *anthropic.Message
+	var response *ai.CompletionResponse
 	err := m.retryWithBackoff(ctx, "commit-message", func(attemptCtx context.Context) error {
-		resp, apiErr := m.client.Messages.New(attemptCtx, anthropic.MessageNewParams{
-			Model:     anthropic.Model(m.model),
+		resp, apiErr := m.provider.StructuredComplete(attemptCtx, ai.CompletionRequest{
+			Model:     m.model,
 			MaxTokens: 2048,
-			Messages: []anthropic.MessageParam{
-				anthropic.NewUserMessage(anthropic.NewTextBlock(prompt)),
-			},
+			Prompt:    prompt,
 		})
 		if apiErr != nil {
 			return apiErr
@@ -49,13 +47,7 @@ func (m *MessageGenerator) GenerateCommitMessage(ctx context.Context, req Commit
 		return nil, fmt.Errorf("failed to generate commit message: %w", err)
 	}
 
-	// Extract text from response
-	var responseText string
-	for _, block := range response.Content {
-		if block.Type == "text" {
-			responseText += block.Text
-		}
-	}
+	responseText := response.Text
 
 	// Parse the JSON response
 	parseResult := ai.Parse[CommitMessageResponse](responseText, ai.ParseOptions{
@@ -0,0 +1,59 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/ai"
+)
+
+// stubProvider answers every prompt with text, after failing the first
+// failures calls
+type stubProvider struct {
+	text     string
+	failures int
+	requests []ai.CompletionRequest
+}
+
+func (p *stubProvider) Name() string { return "stub" }
+
+func (p *stubProvider) Complete(ctx context.Context, req ai.CompletionRequest) (*ai.CompletionResponse, error) {
+	return p.StructuredComplete(ctx, req)
+}
+
+func (p *stubProvider) StructuredComplete(ctx context.Context, req ai.CompletionRequest) (*ai.CompletionResponse, error) {
+	p.requests = append(p.requests, req)
+	if len(p.requests) <= p.failures {
+		return nil, errors.New("overloaded")
+	}
+	return &ai.CompletionResponse{Text: p.text, Model: req.Model}, nil
+}
+
+func TestGenerateCommitMessage(t *testing.T) {
+	provider := &stubProvider{
+		text:     `{"subject": "feat(git): add thing (vc-1)", "body": "Adds the thing.", "reasoning": "It adds a thing"}`,
+		failures: 1,
+	}
+	gen := NewMessageGenerator(provider, "local-model")
+
+	resp, err := gen.GenerateCommitMessage(context.Background(), CommitMessageRequest{
+		IssueID:      "vc-1",
+		IssueTitle:   "Add thing",
+		ChangedFiles: []string{"thing.go"},
+	})
+	if err != nil {
+		t.Fatalf("GenerateCommitMessage: %v", err)
+	}
+	if resp.Subject != "feat(git): add thing (vc-1)" || resp.Body != "Adds the thing." {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+	if len(provider.requests) != 2 {
+		t.Fatalf("Expected a retry after the failure, got %d requests", len(provider.requests))
+	}
+	req := provider.requests[1]
+	if req.Model != "local-model" || !strings.Contains(req.Prompt, "vc-1") || !strings.Contains(req.Prompt, "thing.go") {
+		t.Errorf("Unexpected request: %+v", req)
+	}
+}
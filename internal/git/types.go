@@ -53,6 +53,15 @@ type Status struct {
 	HasChanges bool
 }
 
+// ClosesTrailerKey is the commit message trailer naming the issue a commit
+// closes ("Closes: vc-87"), so the git side maps commits back to issues
+const ClosesTrailerKey = "Closes"
+
+// ClosesTrailer returns the trailer of a commit that closes issueID
+func ClosesTrailer(issueID string) string {
+	return ClosesTrailerKey + ": " + issueID
+}
+
 // CommitOptions configures a git commit operation.
 type CommitOptions struct {
 	// Message is the commit message
@@ -64,6 +73,10 @@ type CommitOptions struct {
 	// CoAuthors is a list of co-authors to add to the commit message
 	CoAuthors []string
 
+	// Trailers are "Key: value" lines added to the commit message, before
+	// the co-authors (e.g. ClosesTrailer)
+	Trailers []string
+
 	// AddAll stages all changes before committing (git add -A)
 	AddAll bool
 
@@ -140,7 +153,7 @@ type RebaseResult struct {
 }
 
 // RebaseCheckpoint stores rebase state for resuming interrupted operations.
-// This can be stored in IssueExecutionState.CheckpointData as JSON.
+// It can be stored in a types.CheckpointV1 Extras entry.
 type RebaseCheckpoint struct {
 	// InProgress indicates if a rebase is currently in progress
 	InProgress bool `json:"in_progress"`
@@ -0,0 +1,338 @@
+// Package github reads and writes GitHub issues for vc import github and
+// vc sync github, which keep vc issues linked to their GitHub numbers.
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the GitHub REST API endpoint
+const DefaultBaseURL = "https://api.github.com"
+
+// TokenEnvVars are the environment variables a token is read from, in order
+var TokenEnvVars = []string{"GITHUB_TOKEN", "GH_TOKEN"}
+
+// maxRateLimitWait bounds how long a call waits for the rate limit to reset
+// before giving up; an unauthenticated hourly limit takes up to an hour
+const maxRateLimitWait = 15 * time.Minute
+
+// Issue is a GitHub issue as returned by the REST API
+type Issue struct {
+	Number    int        `json:"number"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	State     string     `json:"state"` // open or closed
+	HTMLURL   string     `json:"html_url"`
+	Labels    []Label    `json:"labels"`
+	Assignee  *User      `json:"assignee"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	ClosedAt  *time.Time `json:"closed_at"`
+	// RepositoryURL is the API URL of the repository, which differs from
+	// the one asked for when the issue was transferred
+	RepositoryURL string `json:"repository_url"`
+	// PullRequest is set when the issue is a pull request
+	PullRequest *struct{} `json:"pull_request"`
+}
+
+// Repo returns the owner/name of the repository the issue is in, or "" if
+// the response didn't say
+func (i *Issue) Repo() string {
+	_, repo, found := strings.Cut(i.RepositoryURL, "/repos/")
+	if !found {
+		return ""
+	}
+	return repo
+}
+
+// Closed reports whether the issue is closed
+func (i *Issue) Closed() bool {
+	return i.State == "closed"
+}
+
+// Label is a GitHub issue label
+type Label struct {
+	Name string `json:"name"`
+}
+
+// User is a GitHub account
+type User struct {
+	Login string `json:"login"`
+}
+
+// ListOptions filters ListIssues
+type ListOptions struct {
+	State  string    // open, closed or all (default open)
+	Labels []string  // Issues must have every label
+	Since  time.Time // Only issues updated at or after this time (zero for all)
+}
+
+// IssueEdit changes a GitHub issue; nil fields are left alone
+type IssueEdit struct {
+	Title *string `json:"title,omitempty"`
+	State *string `json:"state,omitempty"` // open or closed
+}
+
+// ErrIssueGone is returned for an issue that was deleted, or that the token
+// can no longer see
+var ErrIssueGone = errors.New("GitHub issue is gone")
+
+// APIError is a GitHub API response with an error status
+type APIError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Status     string
+	Message    string
+}
+
+// Error implements the error interface.
+func (e *APIError) Error() string {
+	return fmt.Sprintf("GitHub API %s %s: %s: %s", e.Method, e.URL, e.Status, e.Message)
+}
+
+// Client calls the GitHub REST API, following pagination and waiting out
+// rate limits
+type Client struct {
+	baseURL string
+	token   string
+	http    *http.Client
+	sleep   func(ctx context.Context, d time.Duration) error
+}
+
+// NewClient returns a client for baseURL (DefaultBaseURL if empty)
+// authenticating with token. An empty token makes unauthenticated calls,
+// which GitHub limits to 60 per hour.
+func NewClient(baseURL, token string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+		http:    &http.Client{Timeout: 30 * time.Second},
+		sleep:   sleepContext,
+	}
+}
+
+// TokenFromEnv returns the first token set in TokenEnvVars, or ""
+func TokenFromEnv() string {
+	for _, name := range TokenEnvVars {
+		if token := os.Getenv(name); token != "" {
+			return token
+		}
+	}
+	return ""
+}
+
+// ParseRepo validates an owner/name repository
+func ParseRepo(repo string) (owner, name string, err error) {
+	parts := strings.Split(repo, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("repository must be owner/name, got %q", repo)
+	}
+	return parts[0], parts[1], nil
+}
+
+// ListIssues returns the repository's issues, oldest first, without pull
+// requests
+func (c *Client) ListIssues(ctx context.Context, repo string, opts ListOptions) ([]*Issue, error) {
+	owner, name, err := ParseRepo(repo)
+	if err != nil {
+		return nil, err
+	}
+	state := opts.State
+	if state == "" {
+		state = "open"
+	}
+	query := url.Values{
+		"state":     {state},
+		"sort":      {"created"},
+		"direction": {"asc"},
+		"per_page":  {"100"},
+	}
+	if len(opts.Labels) > 0 {
+		query.Set("labels", strings.Join(opts.Labels, ","))
+	}
+	if !opts.Since.IsZero() {
+		query.Set("since", opts.Since.UTC().Format(time.RFC3339))
+	}
+	next := c.issuesURL(owner, name) + "?" + query.Encode()
+
+	var issues []*Issue
+	for next != "" {
+		var page []*Issue
+		resp, err := c.do(ctx, http.MethodGet, next, nil, &page)
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range page {
+			if issue.PullRequest == nil {
+				issues = append(issues, issue)
+			}
+		}
+		next = nextPage(resp.Header.Get("Link"))
+	}
+	return issues, nil
+}
+
+// GetIssue returns an issue, following it if it was transferred (compare
+// Issue.Repo with repo). Deleted issues return ErrIssueGone.
+func (c *Client) GetIssue(ctx context.Context, repo string, number int) (*Issue, error) {
+	owner, name, err := ParseRepo(repo)
+	if err != nil {
+		return nil, err
+	}
+	var issue Issue
+	if _, err := c.do(ctx, http.MethodGet, fmt.Sprintf("%s/%d", c.issuesURL(owner, name), number), nil, &issue); err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusGone || apiErr.StatusCode == http.StatusNotFound) {
+			return nil, fmt.Errorf("%s#%d: %w", repo, number, ErrIssueGone)
+		}
+		return nil, err
+	}
+	return &issue, nil
+}
+
+// EditIssue changes an issue and returns it as edited
+func (c *Client) EditIssue(ctx context.Context, repo string, number int, edit IssueEdit) (*Issue, error) {
+	owner, name, err := ParseRepo(repo)
+	if err != nil {
+		return nil, err
+	}
+	var issue Issue
+	if _, err := c.do(ctx, http.MethodPatch, fmt.Sprintf("%s/%d", c.issuesURL(owner, name), number), edit, &issue); err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+// CreateComment adds a comment to an issue
+func (c *Client) CreateComment(ctx context.Context, repo string, number int, body string) error {
+	owner, name, err := ParseRepo(repo)
+	if err != nil {
+		return err
+	}
+	payload := map[string]string{"body": body}
+	_, err = c.do(ctx, http.MethodPost, fmt.Sprintf("%s/%d/comments", c.issuesURL(owner, name), number), payload, nil)
+	return err
+}
+
+// issuesURL is the issues endpoint of a repository
+func (c *Client) issuesURL(owner, name string) string {
+	return fmt.Sprintf("%s/repos/%s/%s/issues", c.baseURL, url.PathEscape(owner), url.PathEscape(name))
+}
+
+// do sends a request and decodes the JSON response into out. Rate-limited
+// requests are retried once the limit resets.
+func (c *Client) do(ctx context.Context, method, endpoint string, body interface{}, out interface{}) (*http.Response, error) {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return nil, fmt.Errorf("failed to encode request: %w", err)
+		}
+	}
+
+	for {
+		req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(payload))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Accept", "application/vnd.github+json")
+		req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if c.token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.token)
+		}
+
+		resp, err := c.http.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("GitHub request failed: %w", err)
+		}
+		data, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read GitHub response: %w", err)
+		}
+
+		if wait, limited := rateLimitWait(resp, time.Now()); limited {
+			if wait > maxRateLimitWait {
+				return nil, fmt.Errorf("GitHub rate limit exceeded; it resets in %v (set %s for a higher limit)", wait.Round(time.Second), TokenEnvVars[0])
+			}
+			fmt.Fprintf(os.Stderr, "GitHub rate limit reached, waiting %v\n", wait.Round(time.Second))
+			if err := c.sleep(ctx, wait); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return nil, &APIError{Method: method, URL: endpoint, StatusCode: resp.StatusCode, Status: resp.Status, Message: strings.TrimSpace(string(data))}
+		}
+		if out != nil && len(data) > 0 {
+			if err := json.Unmarshal(data, out); err != nil {
+				return nil, fmt.Errorf("failed to decode GitHub response: %w", err)
+			}
+		}
+		return resp, nil
+	}
+}
+
+// rateLimitWait reports whether resp was rate limited and how long to wait:
+// Retry-After for secondary limits, else until X-RateLimit-Reset
+func rateLimitWait(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		return 0, false // A permission error, not a rate limit
+	}
+	reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return time.Minute, true
+	}
+	wait := time.Unix(reset, 0).Sub(now) + time.Second
+	if wait < time.Second {
+		wait = time.Second
+	}
+	return wait, true
+}
+
+var linkNextPattern = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// nextPage returns the rel="next" URL of a Link header, or ""
+func nextPage(link string) string {
+	if match := linkNextPattern.FindStringSubmatch(link); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// sleepContext waits for d unless ctx is done first
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
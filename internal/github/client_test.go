@@ -0,0 +1,106 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestListIssues(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/acme/widgets/issues" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("Authorization = %q", got)
+		}
+		switch r.URL.Query().Get("page") {
+		case "":
+			if r.URL.Query().Get("state") != "all" || r.URL.Query().Get("labels") != "bug,P1" {
+				t.Errorf("Unexpected query %s", r.URL.RawQuery)
+			}
+			w.Header().Set("Link", fmt.Sprintf(`<%s/repos/acme/widgets/issues?page=2>; rel="next", <%s/repos/acme/widgets/issues?page=2>; rel="last"`, server.URL, server.URL))
+			fmt.Fprint(w, `[{"number":1,"title":"First"},{"number":2,"title":"A PR","pull_request":{}}]`)
+		case "2":
+			fmt.Fprint(w, `[{"number":3,"title":"Third","assignee":{"login":"ada"}}]`)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "secret")
+	issues, err := client.ListIssues(context.Background(), "acme/widgets", ListOptions{State: "all", Labels: []string{"bug", "P1"}})
+	if err != nil {
+		t.Fatalf("ListIssues failed: %v", err)
+	}
+	if len(issues) != 2 || issues[0].Number != 1 || issues[1].Number != 3 {
+		t.Fatalf("Expected issues 1 and 3 without the pull request, got %+v", issues)
+	}
+	if issues[1].Assignee == nil || issues[1].Assignee.Login != "ada" {
+		t.Errorf("Expected assignee ada, got %+v", issues[1].Assignee)
+	}
+}
+
+func TestListIssuesWaitsOutRateLimit(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10))
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		fmt.Fprint(w, `[{"number":1,"title":"First"}]`)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "")
+	var waited time.Duration
+	client.sleep = func(ctx context.Context, d time.Duration) error {
+		waited = d
+		return nil
+	}
+	issues, err := client.ListIssues(context.Background(), "acme/widgets", ListOptions{})
+	if err != nil {
+		t.Fatalf("ListIssues failed: %v", err)
+	}
+	if len(issues) != 1 || calls != 2 {
+		t.Fatalf("Expected a retry after the rate limit, got %d issues in %d calls", len(issues), calls)
+	}
+	if waited < 50*time.Second || waited > 62*time.Second {
+		t.Errorf("Expected to wait about a minute, waited %v", waited)
+	}
+}
+
+func TestRateLimitWait(t *testing.T) {
+	now := time.Unix(1000, 0)
+	tests := []struct {
+		name    string
+		status  int
+		headers map[string]string
+		wait    time.Duration
+		limited bool
+	}{
+		{"ok", http.StatusOK, nil, 0, false},
+		{"permission denied", http.StatusForbidden, map[string]string{"X-RateLimit-Remaining": "12"}, 0, false},
+		{"retry after", http.StatusTooManyRequests, map[string]string{"Retry-After": "30"}, 30 * time.Second, true},
+		{"reset", http.StatusForbidden, map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": "1100"}, 101 * time.Second, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+			for k, v := range tt.headers {
+				resp.Header.Set(k, v)
+			}
+			wait, limited := rateLimitWait(resp, now)
+			if wait != tt.wait || limited != tt.limited {
+				t.Errorf("rateLimitWait = %v, %v; want %v, %v", wait, limited, tt.wait, tt.limited)
+			}
+		})
+	}
+}
@@ -0,0 +1,302 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// RefLabel returns the label recording which GitHub issue a vc issue was
+// imported from, e.g. github:owner/name#123
+func RefLabel(repo string, number int) string {
+	return fmt.Sprintf("github:%s#%d", repo, number)
+}
+
+// MetaKey is the metadata key recording the GitHub issue, as owner/name#N
+const MetaKey = "github.issue"
+
+// ParseRefLabel returns the repository and number of a RefLabel label
+func ParseRefLabel(label string) (repo string, number int, ok bool) {
+	rest, found := strings.CutPrefix(label, "github:")
+	if !found {
+		return "", 0, false
+	}
+	hash := strings.LastIndex(rest, "#")
+	if hash < 0 {
+		return "", 0, false
+	}
+	number, err := strconv.Atoi(rest[hash+1:])
+	if err != nil || number <= 0 {
+		return "", 0, false
+	}
+	return rest[:hash], number, true
+}
+
+// ImportStore is the part of storage the importer uses: the issues it
+// creates, links and records in metadata, the reference labels it looks
+// up, and the dependencies it adds
+type ImportStore interface {
+	CreateIssueWithMetadata(ctx context.Context, issue *types.Issue, labels []string, deps []*types.Dependency, actor string) error
+	SetIssueMeta(ctx context.Context, issueID, key string, value json.RawMessage) error
+	GetIssuesByLabel(ctx context.Context, label string) ([]*types.Issue, error)
+	AddDependency(ctx context.Context, dep *types.Dependency, actor string) error
+	SaveGitHubLink(ctx context.Context, link *types.GitHubLink) error
+}
+
+// PlannedIssue is a GitHub issue mapped to a vc issue
+type PlannedIssue struct {
+	Number int
+	Issue  *types.Issue
+	Labels []string // GitHub labels plus the RefLabel
+	// ExistingID is the vc issue already imported from this number; such
+	// issues are skipped
+	ExistingID string
+}
+
+// PlannedDependency is a "blocked by #N" mention between imported issues
+type PlannedDependency struct {
+	Number          int // The blocked GitHub issue
+	BlockedByNumber int
+}
+
+// ImportPlan is what an import will do
+type ImportPlan struct {
+	Repo         string
+	Issues       []*PlannedIssue
+	Dependencies []PlannedDependency
+	// Unresolved are "blocked by" mentions of issues that are not imported
+	Unresolved []PlannedDependency
+}
+
+// New returns the issues the plan creates
+func (p *ImportPlan) New() []*PlannedIssue {
+	var planned []*PlannedIssue
+	for _, issue := range p.Issues {
+		if issue.ExistingID == "" {
+			planned = append(planned, issue)
+		}
+	}
+	return planned
+}
+
+// ImportResult is what an import did
+type ImportResult struct {
+	Created      map[int]string // GitHub number to new vc issue ID
+	Dependencies int
+}
+
+// Importer maps GitHub issues to vc issues and creates them
+type Importer struct {
+	store ImportStore
+	repo  string
+	actor string
+}
+
+// NewImporter returns an importer of repo's issues into store
+func NewImporter(store ImportStore, repo, actor string) (*Importer, error) {
+	if _, _, err := ParseRepo(repo); err != nil {
+		return nil, err
+	}
+	return &Importer{store: store, repo: repo, actor: actor}, nil
+}
+
+// Plan maps issues to vc issues and their "blocked by" mentions to
+// dependencies. Issues imported before (found by their RefLabel) are marked
+// existing, so running an import again only adds what is new. Dependencies
+// are planned when both ends are imported, now or before, and at least one
+// is new.
+func (im *Importer) Plan(ctx context.Context, issues []*Issue) (*ImportPlan, error) {
+	plan := &ImportPlan{Repo: im.repo}
+	ids := make(map[int]string) // Number to existing vc issue ID
+	newNumbers := make(map[int]bool)
+	for _, gh := range issues {
+		planned := &PlannedIssue{
+			Number: gh.Number,
+			Issue:  im.mapIssue(gh),
+			Labels: append(issueLabels(gh), RefLabel(im.repo, gh.Number)),
+		}
+		existing, err := im.existing(ctx, gh.Number)
+		if err != nil {
+			return nil, err
+		}
+		if existing != "" {
+			planned.ExistingID = existing
+			ids[gh.Number] = existing
+		} else {
+			newNumbers[gh.Number] = true
+		}
+		plan.Issues = append(plan.Issues, planned)
+	}
+
+	for _, gh := range issues {
+		for _, blocker := range BlockedBy(gh.Body) {
+			dep := PlannedDependency{Number: gh.Number, BlockedByNumber: blocker}
+			if !newNumbers[gh.Number] && !newNumbers[blocker] {
+				continue // Planned when the newer of the two was imported
+			}
+			if _, imported := ids[blocker]; !imported && !newNumbers[blocker] {
+				existing, err := im.existing(ctx, blocker)
+				if err != nil {
+					return nil, err
+				}
+				if existing == "" {
+					plan.Unresolved = append(plan.Unresolved, dep)
+					continue
+				}
+				ids[blocker] = existing
+			}
+			plan.Dependencies = append(plan.Dependencies, dep)
+		}
+	}
+	return plan, nil
+}
+
+// Apply creates the plan's new issues, oldest first, then its dependencies.
+// Each issue is created with its RefLabel, so an interrupted import resumes
+// where it stopped when planned again, and linked for vc sync github.
+func (im *Importer) Apply(ctx context.Context, plan *ImportPlan) (*ImportResult, error) {
+	result := &ImportResult{Created: make(map[int]string)}
+	ids := make(map[int]string)
+	for _, planned := range plan.Issues {
+		if planned.ExistingID != "" {
+			ids[planned.Number] = planned.ExistingID
+			continue
+		}
+		if err := im.store.CreateIssueWithMetadata(ctx, planned.Issue, planned.Labels, nil, im.actor); err != nil {
+			return result, fmt.Errorf("failed to import #%d: %w", planned.Number, err)
+		}
+		ids[planned.Number] = planned.Issue.ID
+		result.Created[planned.Number] = planned.Issue.ID
+
+		link := &types.GitHubLink{
+			IssueID:      planned.Issue.ID,
+			Repo:         im.repo,
+			Number:       planned.Number,
+			State:        types.GitHubLinkActive,
+			SyncedTitle:  planned.Issue.Title,
+			SyncedClosed: planned.Issue.Status == types.StatusClosed,
+		}
+		if err := im.store.SaveGitHubLink(ctx, link); err != nil {
+			return result, fmt.Errorf("failed to link #%d: %w", planned.Number, err)
+		}
+		ref, _ := json.Marshal(fmt.Sprintf("%s#%d", im.repo, planned.Number))
+		if err := im.store.SetIssueMeta(ctx, planned.Issue.ID, MetaKey, ref); err != nil {
+			return result, fmt.Errorf("failed to record #%d in metadata: %w", planned.Number, err)
+		}
+	}
+
+	for _, dep := range plan.Dependencies {
+		issueID, blockerID := ids[dep.Number], ids[dep.BlockedByNumber]
+		if blockerID == "" {
+			existing, err := im.existing(ctx, dep.BlockedByNumber)
+			if err != nil {
+				return result, err
+			}
+			blockerID = existing
+		}
+		if issueID == "" || blockerID == "" {
+			continue
+		}
+		err := im.store.AddDependency(ctx, &types.Dependency{IssueID: issueID, DependsOnID: blockerID, Type: types.DepBlocks}, im.actor)
+		if err != nil {
+			return result, fmt.Errorf("failed to add dependency #%d blocked by #%d: %w", dep.Number, dep.BlockedByNumber, err)
+		}
+		result.Dependencies++
+	}
+	return result, nil
+}
+
+// existing returns the vc issue imported from number, or ""
+func (im *Importer) existing(ctx context.Context, number int) (string, error) {
+	issues, err := im.store.GetIssuesByLabel(ctx, RefLabel(im.repo, number))
+	if err != nil {
+		return "", fmt.Errorf("failed to look up #%d: %w", number, err)
+	}
+	if len(issues) == 0 {
+		return "", nil
+	}
+	return issues[0].ID, nil
+}
+
+// mapIssue converts a GitHub issue to a vc issue. The type and priority come
+// from labels (bug, enhancement/feature, chore; P0-P4), defaulting to a P2
+// task, and the description links back to GitHub.
+func (im *Importer) mapIssue(gh *Issue) *types.Issue {
+	issue := &types.Issue{
+		Title:     gh.Title,
+		Status:    types.StatusOpen,
+		Priority:  2,
+		IssueType: types.TypeTask,
+	}
+	if gh.Assignee != nil {
+		issue.Assignee = gh.Assignee.Login
+	}
+	if gh.State == "closed" {
+		issue.Status = types.StatusClosed
+		issue.ClosedAt = gh.ClosedAt
+	}
+
+	for _, label := range gh.Labels {
+		name := strings.ToLower(label.Name)
+		switch name {
+		case "bug":
+			issue.IssueType = types.TypeBug
+		case "enhancement", "feature":
+			issue.IssueType = types.TypeFeature
+		case "chore":
+			issue.IssueType = types.TypeChore
+		}
+		if len(name) == 2 && name[0] == 'p' && name[1] >= '0' && name[1] <= '4' {
+			issue.Priority = int(name[1] - '0')
+		}
+	}
+
+	source := gh.HTMLURL
+	if source == "" {
+		source = fmt.Sprintf("%s#%d", im.repo, gh.Number)
+	}
+	issue.Description = strings.TrimSpace(gh.Body)
+	if issue.Description != "" {
+		issue.Description += "\n\n"
+	}
+	issue.Description += fmt.Sprintf("_Imported from GitHub: %s_", source)
+	return issue
+}
+
+// issueLabels returns the GitHub labels of an issue
+func issueLabels(gh *Issue) []string {
+	labels := make([]string, 0, len(gh.Labels)+1)
+	for _, label := range gh.Labels {
+		labels = append(labels, label.Name)
+	}
+	return labels
+}
+
+var (
+	blockedByPattern = regexp.MustCompile(`(?i)blocked\s+by:?((?:\s*(?:,|and)?\s*#\d+)+)`)
+	issueRefPattern  = regexp.MustCompile(`#(\d+)`)
+)
+
+// BlockedBy returns the issue numbers a body mentions as blockers, as in
+// "Blocked by #12" or "blocked by: #3, #4 and #5", sorted and deduplicated
+func BlockedBy(body string) []int {
+	seen := make(map[int]bool)
+	var numbers []int
+	for _, match := range blockedByPattern.FindAllStringSubmatch(body, -1) {
+		for _, ref := range issueRefPattern.FindAllStringSubmatch(match[1], -1) {
+			number, err := strconv.Atoi(ref[1])
+			if err == nil && !seen[number] {
+				seen[number] = true
+				numbers = append(numbers, number)
+			}
+		}
+	}
+	sort.Ints(numbers)
+	return numbers
+}
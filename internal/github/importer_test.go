@@ -0,0 +1,124 @@
+package github
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/storage/storagetest"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestBlockedBy(t *testing.T) {
+	tests := []struct {
+		body string
+		want []int
+	}{
+		{"No blockers, see #4", nil},
+		{"Blocked by #12", []int{12}},
+		{"blocked by: #5, #3 and #4.\n\nAlso Blocked by #3", []int{3, 4, 5}},
+	}
+	for _, tt := range tests {
+		if got := BlockedBy(tt.body); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("BlockedBy(%q) = %v, want %v", tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestParseRefLabel(t *testing.T) {
+	repo, number, ok := ParseRefLabel(RefLabel("acme/widgets", 42))
+	if !ok || repo != "acme/widgets" || number != 42 {
+		t.Errorf("ParseRefLabel round trip = %q, %d, %v", repo, number, ok)
+	}
+	if _, _, ok := ParseRefLabel("needs-review"); ok {
+		t.Error("Expected a plain label not to parse")
+	}
+}
+
+func TestImport(t *testing.T) {
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+	importer, err := NewImporter(store, "acme/widgets", "tester")
+	if err != nil {
+		t.Fatalf("NewImporter failed: %v", err)
+	}
+
+	closed := time.Now().Add(-time.Hour)
+	first := []*Issue{
+		{Number: 1, Title: "Crash on start", Body: "It crashes.", State: "open", HTMLURL: "https://github.com/acme/widgets/issues/1",
+			Labels: []Label{{Name: "bug"}, {Name: "P1"}}, Assignee: &User{Login: "ada"}},
+		{Number: 2, Title: "Old cleanup", State: "closed", ClosedAt: &closed, Labels: []Label{{Name: "chore"}}},
+		{Number: 3, Title: "Dark mode", Body: "Blocked by #1 and #9", State: "open", Labels: []Label{{Name: "enhancement"}}},
+	}
+	plan, err := importer.Plan(ctx, first)
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	if len(plan.New()) != 3 || len(plan.Dependencies) != 1 || len(plan.Unresolved) != 1 {
+		t.Fatalf("Unexpected plan: %d new, deps %+v, unresolved %+v", len(plan.New()), plan.Dependencies, plan.Unresolved)
+	}
+	result, err := importer.Apply(ctx, plan)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if len(result.Created) != 3 || result.Dependencies != 1 {
+		t.Fatalf("Unexpected result %+v", result)
+	}
+
+	bug, err := store.GetIssue(ctx, result.Created[1])
+	if err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if bug.IssueType != types.TypeBug || bug.Priority != 1 || bug.Assignee != "ada" ||
+		!strings.Contains(bug.Description, "https://github.com/acme/widgets/issues/1") {
+		t.Errorf("Unexpected mapping of #1: %+v", bug)
+	}
+	labels, err := store.GetLabels(ctx, bug.ID)
+	if err != nil {
+		t.Fatalf("GetLabels failed: %v", err)
+	}
+	if !reflect.DeepEqual(labels, []string{"P1", "bug", "github:acme/widgets#1"}) {
+		t.Errorf("Unexpected labels %v", labels)
+	}
+	if link, err := store.GetGitHubLink(ctx, bug.ID); err != nil || link == nil || link.Number != 1 || link.SyncedTitle != "Crash on start" {
+		t.Errorf("Expected #1 linked for sync, got %+v (%v)", link, err)
+	}
+	if meta, _ := store.GetIssueMeta(ctx, bug.ID); string(meta[MetaKey]) != `"acme/widgets#1"` {
+		t.Errorf("Expected %s metadata, got %v", MetaKey, meta)
+	}
+	if chore, _ := store.GetIssue(ctx, result.Created[2]); chore.Status != types.StatusClosed || chore.IssueType != types.TypeChore {
+		t.Errorf("Unexpected mapping of #2: %+v", chore)
+	}
+	blockers, err := store.GetDependencies(ctx, result.Created[3])
+	if err != nil {
+		t.Fatalf("GetDependencies failed: %v", err)
+	}
+	if len(blockers) != 1 || blockers[0].ID != bug.ID {
+		t.Errorf("Expected #3 to depend on #1, got %+v", blockers)
+	}
+
+	// Importing again skips what exists and links new issues to old ones
+	second := append(first, &Issue{Number: 9, Title: "Theme engine", Body: "", State: "open"})
+	plan, err = importer.Plan(ctx, second)
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	if news := plan.New(); len(news) != 1 || news[0].Number != 9 {
+		t.Fatalf("Expected only #9 to be new, got %+v", news)
+	}
+	if want := []PlannedDependency{{Number: 3, BlockedByNumber: 9}}; !reflect.DeepEqual(plan.Dependencies, want) {
+		t.Errorf("Dependencies = %+v, want %+v", plan.Dependencies, want)
+	}
+	result, err = importer.Apply(ctx, plan)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if len(result.Created) != 1 || result.Dependencies != 1 {
+		t.Errorf("Unexpected result %+v", result)
+	}
+	if blockers, _ := store.GetDependencies(ctx, result.Created[9]); len(blockers) != 0 {
+		t.Errorf("Expected #9 to have no blockers, got %+v", blockers)
+	}
+}
@@ -0,0 +1,388 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// SyncStore is the part of storage vc sync github uses: the linked issues it
+// reads and updates, and the links and cursor it keeps
+type SyncStore interface {
+	GetIssue(ctx context.Context, id string) (*types.Issue, error)
+	GetIssuesByLabel(ctx context.Context, label string) ([]*types.Issue, error)
+	UpdateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string) error
+	CloseIssue(ctx context.Context, id string, reason string, actor string) error
+	AddComment(ctx context.Context, issueID, actor, comment string) error
+	GetEvents(ctx context.Context, issueID string, limit int) ([]*types.Event, error)
+	SaveGitHubLink(ctx context.Context, link *types.GitHubLink) error
+	GetGitHubLinks(ctx context.Context, repo string) ([]*types.GitHubLink, error)
+	GetGitHubSyncCursor(ctx context.Context, repo string) (time.Time, error)
+	SetGitHubSyncCursor(ctx context.Context, repo string, cursor time.Time) error
+}
+
+// SyncResult is what a sync did, one line per change
+type SyncResult struct {
+	Pulled     []string // Changes made to vc issues
+	Pushed     []string // Changes made to GitHub issues
+	Overwrites []string // Fields changed on both sides; the most recent write won
+	Unlinked   []string // Links to deleted or transferred GitHub issues
+	// Errors are the issues that failed to sync. The cursor doesn't advance
+	// past them, so the next sync retries them.
+	Errors []error
+	Cursor time.Time
+}
+
+// Syncer keeps vc issues in step with the GitHub issues they are linked to.
+// Titles and open/closed state sync both ways: a field changed on one side
+// since the last sync is copied to the other, and a field changed on both
+// sides takes the most recent write, with a vc comment recording the value
+// it replaced. Closing a vc issue closes the GitHub issue with a comment
+// carrying the close reason, execution summary and commit.
+type Syncer struct {
+	store  SyncStore
+	client *Client
+	repo   string
+	actor  string
+	now    func() time.Time
+}
+
+// NewSyncer returns a syncer of repo's linked issues
+func NewSyncer(store SyncStore, client *Client, repo, actor string) (*Syncer, error) {
+	if _, _, err := ParseRepo(repo); err != nil {
+		return nil, err
+	}
+	return &Syncer{store: store, client: client, repo: repo, actor: actor, now: time.Now}, nil
+}
+
+// Sync syncs the GitHub issues updated since the stored cursor, and the
+// linked vc issues changed since their last sync. A full sync ignores the
+// cursor and also checks every linked issue GitHub no longer lists, which
+// finds deleted and transferred issues; their links stop syncing. Issues
+// imported before links were recorded are linked by their RefLabel the
+// first time GitHub lists them.
+func (s *Syncer) Sync(ctx context.Context, full bool) (*SyncResult, error) {
+	started := s.now()
+	cursor, err := s.store.GetGitHubSyncCursor(ctx, s.repo)
+	if err != nil {
+		return nil, err
+	}
+	if full {
+		cursor = time.Time{}
+	}
+	links, err := s.store.GetGitHubLinks(ctx, s.repo)
+	if err != nil {
+		return nil, err
+	}
+	byNumber := make(map[int]*types.GitHubLink, len(links))
+	for _, link := range links {
+		byNumber[link.Number] = link
+	}
+	upstream, err := s.client.ListIssues(ctx, s.repo, ListOptions{State: "all", Since: cursor})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SyncResult{Cursor: cursor}
+	listed := make(map[int]bool, len(upstream))
+	for _, gh := range upstream {
+		listed[gh.Number] = true
+		link, known := byNumber[gh.Number], true
+		if link == nil {
+			if link, err = s.linkImported(ctx, gh.Number); err != nil {
+				result.Errors = append(result.Errors, err)
+				continue
+			}
+			if link == nil {
+				continue // Not imported
+			}
+			known = false
+		}
+		if link.State != types.GitHubLinkActive {
+			continue
+		}
+		if err := s.syncIssue(ctx, link, gh, known, result); err != nil {
+			result.Errors = append(result.Errors, err)
+		}
+	}
+
+	for _, link := range links {
+		if listed[link.Number] || link.State != types.GitHubLinkActive {
+			continue
+		}
+		if !full {
+			changed, err := s.changedLocally(ctx, link)
+			if err != nil {
+				result.Errors = append(result.Errors, err)
+				continue
+			}
+			if !changed {
+				continue
+			}
+		}
+		gh, err := s.client.GetIssue(ctx, s.repo, link.Number)
+		switch {
+		case errors.Is(err, ErrIssueGone):
+			err = s.unlink(ctx, link, types.GitHubLinkDeleted, "", result)
+		case err != nil:
+		case gh.Repo() != "" && !strings.EqualFold(gh.Repo(), s.repo):
+			err = s.unlink(ctx, link, types.GitHubLinkTransferred, gh.HTMLURL, result)
+		default:
+			err = s.syncIssue(ctx, link, gh, true, result)
+		}
+		if err != nil {
+			result.Errors = append(result.Errors, err)
+		}
+	}
+
+	if len(result.Errors) == 0 {
+		if err := s.store.SetGitHubSyncCursor(ctx, s.repo, started); err != nil {
+			return result, err
+		}
+		result.Cursor = started
+	}
+	return result, nil
+}
+
+// linkImported links the vc issue labeled with number's RefLabel, if any.
+// The link's synced values are unknown, so differences are resolved by the
+// most recent write.
+func (s *Syncer) linkImported(ctx context.Context, number int) (*types.GitHubLink, error) {
+	issues, err := s.store.GetIssuesByLabel(ctx, RefLabel(s.repo, number))
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up #%d: %w", number, err)
+	}
+	if len(issues) == 0 {
+		return nil, nil
+	}
+	return &types.GitHubLink{
+		IssueID: issues[0].ID,
+		Repo:    s.repo,
+		Number:  number,
+		State:   types.GitHubLinkActive,
+	}, nil
+}
+
+// changedLocally reports whether the linked vc issue changed since the last
+// sync
+func (s *Syncer) changedLocally(ctx context.Context, link *types.GitHubLink) (bool, error) {
+	issue, err := s.linkedIssue(ctx, link)
+	if err != nil {
+		return false, err
+	}
+	return issue.Title != link.SyncedTitle || isClosed(issue) != link.SyncedClosed, nil
+}
+
+// syncIssue reconciles a vc issue with its GitHub issue and records what both
+// now agree on. known is false for a link without synced values.
+func (s *Syncer) syncIssue(ctx context.Context, link *types.GitHubLink, gh *Issue, known bool, result *SyncResult) error {
+	issue, err := s.linkedIssue(ctx, link)
+	if err != nil {
+		return err
+	}
+	ref := fmt.Sprintf("%s#%d", s.repo, gh.Number)
+	remoteNewer := gh.UpdatedAt.After(issue.UpdatedAt)
+	var edit IssueEdit
+
+	title, titleConflict := reconcile(link.SyncedTitle, issue.Title, gh.Title, known, remoteNewer)
+	switch title {
+	case pull:
+		if err := s.store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"title": gh.Title}, s.actor); err != nil {
+			return fmt.Errorf("failed to update title of %s: %w", issue.ID, err)
+		}
+		result.Pulled = append(result.Pulled, fmt.Sprintf("%s: title from %s: %q", issue.ID, ref, gh.Title))
+		if titleConflict {
+			s.recordOverwrite(ctx, issue.ID, result, fmt.Sprintf("the title changed in vc and on GitHub; the newer GitHub title replaced the vc title %q", issue.Title))
+		}
+	case push:
+		edit.Title = &issue.Title
+		result.Pushed = append(result.Pushed, fmt.Sprintf("%s: title of %s: %q", issue.ID, ref, issue.Title))
+		if titleConflict {
+			s.recordOverwrite(ctx, issue.ID, result, fmt.Sprintf("the title changed in vc and on GitHub; the newer vc title replaced the GitHub title %q", gh.Title))
+		}
+	}
+
+	closed, stateConflict := reconcile(link.SyncedClosed, isClosed(issue), gh.Closed(), known, remoteNewer)
+	switch closed {
+	case pull:
+		if gh.Closed() {
+			err = s.store.CloseIssue(ctx, issue.ID, fmt.Sprintf("Closed on GitHub (%s)", ref), s.actor)
+		} else {
+			err = s.store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"status": string(types.StatusOpen)}, s.actor)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to update status of %s: %w", issue.ID, err)
+		}
+		result.Pulled = append(result.Pulled, fmt.Sprintf("%s: %s from %s", issue.ID, gh.State, ref))
+		if stateConflict {
+			s.recordOverwrite(ctx, issue.ID, result, fmt.Sprintf("the issue was %s on GitHub after vc changed it; the vc status %s was replaced", gh.State, issue.Status))
+		}
+	case push:
+		state := "open"
+		if isClosed(issue) {
+			state = "closed"
+			comment, err := s.closingComment(ctx, issue)
+			if err != nil {
+				return err
+			}
+			if err := s.client.CreateComment(ctx, s.repo, gh.Number, comment); err != nil {
+				return fmt.Errorf("failed to comment on %s: %w", ref, err)
+			}
+		}
+		edit.State = &state
+		result.Pushed = append(result.Pushed, fmt.Sprintf("%s: %s %s", issue.ID, state, ref))
+		if stateConflict {
+			s.recordOverwrite(ctx, issue.ID, result, fmt.Sprintf("the issue changed in vc after it was %s on GitHub; the GitHub issue was set %s", gh.State, state))
+		}
+	}
+
+	if edit.Title != nil || edit.State != nil {
+		if _, err := s.client.EditIssue(ctx, s.repo, gh.Number, edit); err != nil {
+			return fmt.Errorf("failed to update %s: %w", ref, err)
+		}
+	}
+
+	agreedTitle, agreedClosed := gh.Title, gh.Closed()
+	if title == push {
+		agreedTitle = issue.Title
+	}
+	if closed == push {
+		agreedClosed = isClosed(issue)
+	}
+	if known && title == inSync && closed == inSync && link.SyncedTitle == agreedTitle && link.SyncedClosed == agreedClosed {
+		return nil
+	}
+	link.SyncedTitle, link.SyncedClosed, link.SyncedAt = agreedTitle, agreedClosed, s.now()
+	return s.store.SaveGitHubLink(ctx, link)
+}
+
+// unlink stops syncing a link whose GitHub issue was deleted or transferred
+// and says so on the vc issue, which is otherwise left alone
+func (s *Syncer) unlink(ctx context.Context, link *types.GitHubLink, state types.GitHubLinkState, movedTo string, result *SyncResult) error {
+	link.State, link.MovedTo, link.SyncedAt = state, movedTo, s.now()
+	if err := s.store.SaveGitHubLink(ctx, link); err != nil {
+		return err
+	}
+	what := "was deleted"
+	if state == types.GitHubLinkTransferred {
+		what = "was transferred to " + movedTo
+	}
+	message := fmt.Sprintf("GitHub sync: %s#%d %s; this issue is no longer synced.", link.Repo, link.Number, what)
+	if err := s.store.AddComment(ctx, link.IssueID, s.actor, message); err != nil {
+		return fmt.Errorf("failed to comment on %s: %w", link.IssueID, err)
+	}
+	result.Unlinked = append(result.Unlinked, fmt.Sprintf("%s: %s#%d %s", link.IssueID, link.Repo, link.Number, what))
+	return nil
+}
+
+// recordOverwrite comments on the vc issue that a sync conflict overwrote a
+// value. The comment is best effort: the sync itself succeeded.
+func (s *Syncer) recordOverwrite(ctx context.Context, issueID string, result *SyncResult, what string) {
+	result.Overwrites = append(result.Overwrites, fmt.Sprintf("%s: %s", issueID, what))
+	if err := s.store.AddComment(ctx, issueID, s.actor, "GitHub sync conflict: "+what+"."); err != nil {
+		result.Errors = append(result.Errors, fmt.Errorf("failed to record overwrite on %s: %w", issueID, err))
+	}
+}
+
+// linkedIssue returns the vc issue of a link
+func (s *Syncer) linkedIssue(ctx context.Context, link *types.GitHubLink) (*types.Issue, error) {
+	issue, err := s.store.GetIssue(ctx, link.IssueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s: %w", link.IssueID, err)
+	}
+	if issue == nil {
+		return nil, fmt.Errorf("%s (linked to #%d) not found", link.IssueID, link.Number)
+	}
+	return issue, nil
+}
+
+// Comment prefixes the results processor writes, read back for the closing
+// comment
+const (
+	analysisCommentPrefix = "**AI Analysis**"
+	commitCommentPrefix   = "Auto-committed changes: "
+)
+
+// closingComment is the GitHub comment posted when vc closes an issue: the
+// close reason, the summary of the last AI analysis and the commit, as far
+// as the issue's history has them
+func (s *Syncer) closingComment(ctx context.Context, issue *types.Issue) (string, error) {
+	history, err := s.store.GetEvents(ctx, issue.ID, 0)
+	if err != nil {
+		return "", fmt.Errorf("failed to get history of %s: %w", issue.ID, err)
+	}
+	var reason, summary, commit string
+	for _, event := range history { // Newest first
+		if event.Comment == nil {
+			continue
+		}
+		text := *event.Comment
+		switch {
+		case event.EventType == types.EventClosed && reason == "":
+			reason = text
+		case event.EventType != types.EventCommented:
+		case strings.HasPrefix(text, analysisCommentPrefix) && summary == "":
+			for _, line := range strings.Split(text, "\n") {
+				if rest, found := strings.CutPrefix(line, "Summary: "); found {
+					summary = strings.TrimSpace(rest)
+					break
+				}
+			}
+		case strings.HasPrefix(text, commitCommentPrefix) && commit == "":
+			commit = strings.TrimSpace(strings.TrimPrefix(text, commitCommentPrefix))
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Closed in vc as %s", issue.ID)
+	if reason != "" {
+		fmt.Fprintf(&b, ": %s", reason)
+	}
+	if summary != "" {
+		fmt.Fprintf(&b, "\n\n**Summary:** %s", summary)
+	}
+	if commit != "" {
+		fmt.Fprintf(&b, "\n\n**Commit:** %s", commit)
+	}
+	return b.String(), nil
+}
+
+// isClosed reports whether a vc issue is closed
+func isClosed(issue *types.Issue) bool {
+	return issue.Status == types.StatusClosed
+}
+
+// direction is which way a field syncs
+type direction int
+
+const (
+	inSync direction = iota
+	pull             // GitHub to vc
+	push             // vc to GitHub
+)
+
+// reconcile decides which way a field syncs from its value at the last sync
+// (base), in vc (local) and on GitHub (remote). A field changed on one side
+// only goes to the other. One changed on both sides, or any difference when
+// the base is unknown, is a conflict that the most recent write wins.
+func reconcile[T comparable](base, local, remote T, known, remoteNewer bool) (dir direction, conflict bool) {
+	if local == remote {
+		return inSync, false
+	}
+	localChanged := !known || local != base
+	remoteChanged := !known || remote != base
+	switch {
+	case remoteChanged && !localChanged:
+		return pull, false
+	case localChanged && !remoteChanged:
+		return push, false
+	case remoteNewer:
+		return pull, true
+	default:
+		return push, true
+	}
+}
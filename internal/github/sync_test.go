@@ -0,0 +1,252 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/storage/storagetest"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// fakeGitHub serves the issues endpoints of one repository from memory
+type fakeGitHub struct {
+	t        *testing.T
+	mu       sync.Mutex
+	issues   map[int]*Issue
+	gone     map[int]bool
+	moved    map[int]string // Number to the repository it was transferred to
+	comments map[int][]string
+}
+
+func newFakeGitHub(t *testing.T) (*fakeGitHub, *Client) {
+	f := &fakeGitHub{t: t, issues: make(map[int]*Issue), gone: make(map[int]bool),
+		moved: make(map[int]string), comments: make(map[int][]string)}
+	server := httptest.NewServer(f)
+	t.Cleanup(server.Close)
+	return f, NewClient(server.URL, "token")
+}
+
+func (f *fakeGitHub) add(number int, title, state string, updated time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.issues[number] = &Issue{Number: number, Title: title, State: state, UpdatedAt: updated,
+		HTMLURL:       fmt.Sprintf("https://github.com/acme/widgets/issues/%d", number),
+		RepositoryURL: "https://api.github.com/repos/acme/widgets"}
+}
+
+func (f *fakeGitHub) get(number int) Issue {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return *f.issues[number]
+}
+
+func (f *fakeGitHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/repos/acme/widgets/issues"), "/")
+	if len(parts) == 1 {
+		since, _ := time.Parse(time.RFC3339, r.URL.Query().Get("since"))
+		var listed []*Issue
+		for number := 1; number <= 100; number++ {
+			if issue := f.issues[number]; issue != nil && !f.gone[number] && f.moved[number] == "" && !issue.UpdatedAt.Before(since) {
+				listed = append(listed, issue)
+			}
+		}
+		_ = json.NewEncoder(w).Encode(listed)
+		return
+	}
+	number, _ := strconv.Atoi(parts[1])
+	issue := f.issues[number]
+	switch {
+	case issue == nil || f.gone[number]:
+		w.WriteHeader(http.StatusGone)
+	case f.moved[number] != "":
+		moved := *issue
+		moved.RepositoryURL = "https://api.github.com/repos/" + f.moved[number]
+		moved.HTMLURL = "https://github.com/" + f.moved[number] + "/issues/1"
+		_ = json.NewEncoder(w).Encode(&moved)
+	case len(parts) == 3 && r.Method == http.MethodPost:
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		f.comments[number] = append(f.comments[number], body["body"])
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodPatch:
+		var edit IssueEdit
+		_ = json.NewDecoder(r.Body).Decode(&edit)
+		if edit.Title != nil {
+			issue.Title = *edit.Title
+		}
+		if edit.State != nil {
+			issue.State = *edit.State
+		}
+		issue.UpdatedAt = time.Now()
+		_ = json.NewEncoder(w).Encode(issue)
+	default:
+		_ = json.NewEncoder(w).Encode(issue)
+	}
+}
+
+// importAll imports every issue of the fake repository
+func importAll(t *testing.T, store *storagetest.FakeStorage, client *Client) map[int]string {
+	t.Helper()
+	ctx := context.Background()
+	issues, err := client.ListIssues(ctx, "acme/widgets", ListOptions{State: "all"})
+	if err != nil {
+		t.Fatalf("ListIssues failed: %v", err)
+	}
+	importer, _ := NewImporter(store, "acme/widgets", "tester")
+	plan, err := importer.Plan(ctx, issues)
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	result, err := importer.Apply(ctx, plan)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	return result.Created
+}
+
+func TestReconcile(t *testing.T) {
+	tests := []struct {
+		name                string
+		base, local, remote string
+		known, remoteNewer  bool
+		dir                 direction
+		conflict            bool
+	}{
+		{"unchanged", "a", "a", "a", true, false, inSync, false},
+		{"remote edit", "a", "a", "b", true, false, pull, false},
+		{"local edit", "a", "b", "a", true, true, push, false},
+		{"same edit", "a", "b", "b", true, false, inSync, false},
+		{"both, remote newer", "a", "b", "c", true, true, pull, true},
+		{"both, local newer", "a", "b", "c", true, false, push, true},
+		{"unknown base", "", "b", "c", false, true, pull, true},
+	}
+	for _, tt := range tests {
+		dir, conflict := reconcile(tt.base, tt.local, tt.remote, tt.known, tt.remoteNewer)
+		if dir != tt.dir || conflict != tt.conflict {
+			t.Errorf("%s: reconcile = %v, %v; want %v, %v", tt.name, dir, conflict, tt.dir, tt.conflict)
+		}
+	}
+}
+
+func TestSync(t *testing.T) {
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+	gh, client := newFakeGitHub(t)
+	old := time.Now().Add(-time.Hour)
+	gh.add(1, "Crash on start", "open", old)
+	gh.add(2, "Dark mode", "open", old)
+	gh.add(3, "Old idea", "open", old)
+	gh.add(4, "Moved away", "open", old)
+	ids := importAll(t, store, client)
+
+	syncer, err := NewSyncer(store, client, "acme/widgets", "tester")
+	if err != nil {
+		t.Fatalf("NewSyncer failed: %v", err)
+	}
+	if result, err := syncer.Sync(ctx, false); err != nil || len(result.Pulled)+len(result.Pushed) != 0 {
+		t.Fatalf("Expected nothing to sync after the import, got %+v, %v", result, err)
+	}
+
+	// vc closes #1; #2 is retitled upstream; #3 is deleted and #4 transferred
+	if err := store.AddComment(ctx, ids[1], "vc", "**AI Analysis**\n\nCompleted: true\n\nSummary: Fixed the nil map\n\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.AddComment(ctx, ids[1], "vc", "Auto-committed changes: abc123"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.CloseIssue(ctx, ids[1], "Completed: gates passed", "vc"); err != nil {
+		t.Fatal(err)
+	}
+	gh.mu.Lock()
+	gh.issues[2].Title, gh.issues[2].UpdatedAt = "Dark mode everywhere", time.Now()
+	gh.gone[3] = true
+	gh.moved[4] = "acme/gadgets"
+	gh.mu.Unlock()
+
+	result, err := syncer.Sync(ctx, false)
+	if err != nil || len(result.Errors) != 0 {
+		t.Fatalf("Sync failed: %v %v", err, result.Errors)
+	}
+	if closed := gh.get(1); closed.State != "closed" {
+		t.Errorf("Expected #1 closed on GitHub, got %s", closed.State)
+	}
+	if comments := gh.comments[1]; len(comments) != 1 || !strings.Contains(comments[0], "Completed: gates passed") ||
+		!strings.Contains(comments[0], "Fixed the nil map") || !strings.Contains(comments[0], "abc123") {
+		t.Errorf("Unexpected closing comment: %q", comments)
+	}
+	if issue, _ := store.GetIssue(ctx, ids[2]); issue.Title != "Dark mode everywhere" {
+		t.Errorf("Expected the upstream title, got %q", issue.Title)
+	}
+	if len(result.Unlinked) != 0 {
+		t.Errorf("Expected an incremental sync not to look for deleted issues, got %v", result.Unlinked)
+	}
+
+	// A full sync finds the deleted and transferred issues
+	result, err = syncer.Sync(ctx, true)
+	if err != nil || len(result.Errors) != 0 {
+		t.Fatalf("Sync failed: %v %v", err, result.Errors)
+	}
+	if len(result.Unlinked) != 2 {
+		t.Fatalf("Expected #3 and #4 unlinked, got %v", result.Unlinked)
+	}
+	deleted, _ := store.GetGitHubLink(ctx, ids[3])
+	moved, _ := store.GetGitHubLink(ctx, ids[4])
+	if deleted.State != types.GitHubLinkDeleted || moved.State != types.GitHubLinkTransferred ||
+		moved.MovedTo != "https://github.com/acme/gadgets/issues/1" {
+		t.Errorf("Unexpected links %+v and %+v", deleted, moved)
+	}
+	if issue, _ := store.GetIssue(ctx, ids[3]); issue.Status != types.StatusOpen {
+		t.Errorf("Expected the vc issue of a deleted GitHub issue left alone, got %s", issue.Status)
+	}
+}
+
+func TestSyncConflict(t *testing.T) {
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+	gh, client := newFakeGitHub(t)
+	gh.add(1, "Crash", "open", time.Now().Add(-time.Hour))
+	ids := importAll(t, store, client)
+	syncer, _ := NewSyncer(store, client, "acme/widgets", "tester")
+
+	// vc retitles first, GitHub later: GitHub wins and vc keeps a record
+	if err := store.UpdateIssue(ctx, ids[1], map[string]interface{}{"title": "Crash (vc)"}, "tester"); err != nil {
+		t.Fatal(err)
+	}
+	gh.mu.Lock()
+	gh.issues[1].Title, gh.issues[1].UpdatedAt = "Crash (GitHub)", time.Now().Add(time.Minute)
+	gh.mu.Unlock()
+
+	result, err := syncer.Sync(ctx, false)
+	if err != nil || len(result.Errors) != 0 {
+		t.Fatalf("Sync failed: %v %v", err, result.Errors)
+	}
+	if issue, _ := store.GetIssue(ctx, ids[1]); issue.Title != "Crash (GitHub)" {
+		t.Errorf("Expected the newer GitHub title, got %q", issue.Title)
+	}
+	if len(result.Overwrites) != 1 {
+		t.Fatalf("Expected one overwrite, got %v", result.Overwrites)
+	}
+	history, _ := store.GetEvents(ctx, ids[1], 0)
+	found := false
+	for _, event := range history {
+		if event.Comment != nil && strings.Contains(*event.Comment, `replaced the vc title "Crash (vc)"`) {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Expected a comment recording the overwritten vc title")
+	}
+	if link, _ := store.GetGitHubLink(ctx, ids[1]); link.SyncedTitle != "Crash (GitHub)" {
+		t.Errorf("Expected the link to record the agreed title, got %q", link.SyncedTitle)
+	}
+}
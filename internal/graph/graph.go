@@ -0,0 +1,330 @@
+// Package graph builds the dependency graph of the tracker's issues (vc
+// graph) and renders it as Graphviz DOT or Mermaid. Edges point from the
+// issue depended on to the issue that depends on it: blocker to blocked,
+// parent to child, origin to discovered issue.
+//
+// Output is deterministic: nodes are sorted by ID (numerically within a
+// prefix), edges by their ends and type, so two renderings of the same
+// tracker state are byte-identical and diffs between runs show real changes.
+package graph
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// DefaultMaxNodes is the default --max-nodes guard
+const DefaultMaxNodes = 300
+
+// DefaultRadius is how far from the focus issue a focused graph reaches
+const DefaultRadius = 2
+
+// CollapsedID is the ID of the node that stands for collapsed closed issues
+const CollapsedID = "closed"
+
+// Store is the part of the storage the graph is read from
+type Store interface {
+	SearchIssues(ctx context.Context, query string, filter types.IssueFilter) ([]*types.Issue, error)
+	GetDependencyRecords(ctx context.Context, issueID string) ([]*types.Dependency, error)
+}
+
+// Options scope the graph. Structural scopes (Epic, Focus) walk the whole
+// graph; Labels and Statuses then keep only matching issues, and edges are
+// kept between the issues left.
+type Options struct {
+	// Project only includes the project's issues ("" for every project)
+	Project string
+	// Labels keeps issues that have all of them
+	Labels []string
+	// Statuses keeps issues in one of them (empty for all)
+	Statuses []types.Status
+	// Epic keeps the epic and its descendants through parent-child links
+	Epic string
+	// Focus keeps the issues within Radius links of this one, in either
+	// direction and through every dependency type
+	Focus  string
+	Radius int
+	// CollapseClosed replaces closed issues with one node counting them,
+	// keeping their links
+	CollapseClosed bool
+	// MaxNodes fails rendering graphs with more nodes (0 for no limit)
+	MaxNodes int
+}
+
+// Node is an issue in the graph, or the node standing for collapsed closed
+// issues
+type Node struct {
+	Issue *types.Issue
+	// Collapsed is the number of closed issues the node stands for (0 for
+	// issue nodes)
+	Collapsed int
+}
+
+// ID is the node's issue ID, or CollapsedID
+func (n *Node) ID() string {
+	if n.Collapsed > 0 {
+		return CollapsedID
+	}
+	return n.Issue.ID
+}
+
+// Edge is a dependency: To depends on From
+type Edge struct {
+	From string
+	To   string
+	Type types.DependencyType
+}
+
+// Graph is a set of issues and the dependencies between them
+type Graph struct {
+	// Nodes sorted by ID (CollapsedID last)
+	Nodes []*Node
+	// Edges sorted by From, To and Type
+	Edges []Edge
+}
+
+// TooLargeError is returned when a graph has more nodes than Options.MaxNodes
+type TooLargeError struct {
+	Nodes    int
+	MaxNodes int
+}
+
+func (e *TooLargeError) Error() string {
+	return fmt.Sprintf("graph has %d nodes, more than the limit of %d", e.Nodes, e.MaxNodes)
+}
+
+// Load reads the issues (archived ones are left out) and their dependency
+// records and builds the scoped graph
+func Load(ctx context.Context, store Store, opts Options) (*Graph, error) {
+	issues, err := store.SearchIssues(ctx, "", types.IssueFilter{Project: opts.Project})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read issues: %w", err)
+	}
+	var deps []*types.Dependency
+	for _, issue := range issues {
+		records, err := store.GetDependencyRecords(ctx, issue.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read dependencies of %s: %w", issue.ID, err)
+		}
+		deps = append(deps, records...)
+	}
+
+	var labeled map[string]bool
+	if len(opts.Labels) > 0 {
+		matches, err := store.SearchIssues(ctx, "", types.IssueFilter{Project: opts.Project, Labels: opts.Labels})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read labeled issues: %w", err)
+		}
+		labeled = make(map[string]bool, len(matches))
+		for _, issue := range matches {
+			labeled[issue.ID] = true
+		}
+	}
+	return Build(issues, deps, labeled, opts)
+}
+
+// Build builds the scoped graph from issues and dependency records.
+// labeled holds the issues matching opts.Labels (nil when it is empty).
+// Dependencies on issues not in issues are left out.
+func Build(issues []*types.Issue, deps []*types.Dependency, labeled map[string]bool, opts Options) (*Graph, error) {
+	byID := make(map[string]*types.Issue, len(issues))
+	for _, issue := range issues {
+		byID[issue.ID] = issue
+	}
+	var edges []Edge
+	for _, dep := range deps {
+		if byID[dep.IssueID] != nil && byID[dep.DependsOnID] != nil {
+			edges = append(edges, Edge{From: dep.DependsOnID, To: dep.IssueID, Type: dep.Type})
+		}
+	}
+
+	keep := make(map[string]bool, len(issues))
+	for id := range byID {
+		keep[id] = true
+	}
+	if opts.Epic != "" {
+		if byID[opts.Epic] == nil {
+			return nil, fmt.Errorf("issue %s not found", opts.Epic)
+		}
+		keep = intersect(keep, descendants(opts.Epic, edges))
+	}
+	if opts.Focus != "" {
+		if byID[opts.Focus] == nil {
+			return nil, fmt.Errorf("issue %s not found", opts.Focus)
+		}
+		radius := opts.Radius
+		if radius <= 0 {
+			radius = DefaultRadius
+		}
+		keep = intersect(keep, neighborhood(opts.Focus, radius, edges))
+	}
+	for id := range keep {
+		issue := byID[id]
+		switch {
+		case id == opts.Focus || id == opts.Epic:
+			// The issue asked for always shows
+		case labeled != nil && !labeled[id]:
+			delete(keep, id)
+		case len(opts.Statuses) > 0 && !hasStatus(issue, opts.Statuses):
+			delete(keep, id)
+		}
+	}
+
+	g := &Graph{}
+	collapsed := 0
+	nodeID := func(id string) string {
+		if opts.CollapseClosed && byID[id].Status == types.StatusClosed {
+			return CollapsedID
+		}
+		return id
+	}
+	for id := range keep {
+		if nodeID(id) == CollapsedID {
+			collapsed++
+			continue
+		}
+		g.Nodes = append(g.Nodes, &Node{Issue: byID[id]})
+	}
+	sort.Slice(g.Nodes, func(i, j int) bool { return IDLess(g.Nodes[i].Issue.ID, g.Nodes[j].Issue.ID) })
+	if collapsed > 0 {
+		g.Nodes = append(g.Nodes, &Node{Collapsed: collapsed})
+	}
+
+	seen := make(map[Edge]bool)
+	for _, edge := range edges {
+		if !keep[edge.From] || !keep[edge.To] {
+			continue
+		}
+		edge.From, edge.To = nodeID(edge.From), nodeID(edge.To)
+		if edge.From == edge.To || seen[edge] {
+			continue
+		}
+		seen[edge] = true
+		g.Edges = append(g.Edges, edge)
+	}
+	sort.Slice(g.Edges, func(i, j int) bool {
+		a, b := g.Edges[i], g.Edges[j]
+		switch {
+		case a.From != b.From:
+			return IDLess(a.From, b.From)
+		case a.To != b.To:
+			return IDLess(a.To, b.To)
+		default:
+			return a.Type < b.Type
+		}
+	})
+
+	if opts.MaxNodes > 0 && len(g.Nodes) > opts.MaxNodes {
+		return nil, &TooLargeError{Nodes: len(g.Nodes), MaxNodes: opts.MaxNodes}
+	}
+	return g, nil
+}
+
+// descendants returns the epic and the issues below it through parent-child
+// links
+func descendants(epic string, edges []Edge) map[string]bool {
+	children := make(map[string][]string)
+	for _, edge := range edges {
+		if edge.Type == types.DepParentChild {
+			children[edge.From] = append(children[edge.From], edge.To)
+		}
+	}
+	found := map[string]bool{epic: true}
+	queue := []string{epic}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, child := range children[id] {
+			if !found[child] {
+				found[child] = true
+				queue = append(queue, child)
+			}
+		}
+	}
+	return found
+}
+
+// neighborhood returns the issues within radius links of focus, following
+// links in both directions
+func neighborhood(focus string, radius int, edges []Edge) map[string]bool {
+	adjacent := make(map[string][]string)
+	for _, edge := range edges {
+		adjacent[edge.From] = append(adjacent[edge.From], edge.To)
+		adjacent[edge.To] = append(adjacent[edge.To], edge.From)
+	}
+	found := map[string]bool{focus: true}
+	frontier := []string{focus}
+	for step := 0; step < radius && len(frontier) > 0; step++ {
+		var next []string
+		for _, id := range frontier {
+			for _, other := range adjacent[id] {
+				if !found[other] {
+					found[other] = true
+					next = append(next, other)
+				}
+			}
+		}
+		frontier = next
+	}
+	return found
+}
+
+// intersect returns the keys of a that are also in b
+func intersect(a, b map[string]bool) map[string]bool {
+	result := make(map[string]bool)
+	for id := range a {
+		if b[id] {
+			result[id] = true
+		}
+	}
+	return result
+}
+
+// hasStatus reports whether the issue has one of the statuses
+func hasStatus(issue *types.Issue, statuses []types.Status) bool {
+	for _, status := range statuses {
+		if issue.Status == status {
+			return true
+		}
+	}
+	return false
+}
+
+// IDLess orders issue IDs by prefix, then by number, so vc-2 sorts before
+// vc-10. IDs without a numeric suffix sort after the numbered ones of
+// their prefix, and CollapsedID after every issue.
+func IDLess(a, b string) bool {
+	if (a == CollapsedID) != (b == CollapsedID) {
+		return b == CollapsedID
+	}
+	prefixA, numA, okA := splitID(a)
+	prefixB, numB, okB := splitID(b)
+	switch {
+	case prefixA != prefixB:
+		return prefixA < prefixB
+	case okA && okB && numA != numB:
+		return numA < numB
+	case okA != okB:
+		return okA
+	default:
+		return a < b
+	}
+}
+
+// splitID splits an ID like vc-12 into its prefix and number
+func splitID(id string) (string, int, bool) {
+	i := strings.LastIndex(id, "-")
+	if i < 0 {
+		return id, 0, false
+	}
+	n, err := strconv.Atoi(id[i+1:])
+	if err != nil {
+		return id, 0, false
+	}
+	return id[:i], n, true
+}
@@ -0,0 +1,224 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/storage/storagetest"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// fixture is an epic vc-1 with children vc-2 and vc-3; vc-3 blocks vc-2,
+// vc-4 was discovered from vc-2, and vc-5 (closed) and vc-10 are related to
+// vc-4 and vc-3
+func fixture() ([]*types.Issue, []*types.Dependency) {
+	issue := func(id string, issueType types.IssueType, status types.Status) *types.Issue {
+		return &types.Issue{ID: id, Title: "Issue " + id, IssueType: issueType, Status: status, Priority: 2}
+	}
+	issues := []*types.Issue{
+		issue("vc-10", types.TypeChore, types.StatusOpen),
+		issue("vc-1", types.TypeEpic, types.StatusOpen),
+		issue("vc-2", types.TypeTask, types.StatusInProgress),
+		issue("vc-3", types.TypeBug, types.StatusOpen),
+		issue("vc-4", types.TypeFeature, types.StatusOpen),
+		issue("vc-5", types.TypeTask, types.StatusClosed),
+	}
+	deps := []*types.Dependency{
+		{IssueID: "vc-2", DependsOnID: "vc-1", Type: types.DepParentChild},
+		{IssueID: "vc-3", DependsOnID: "vc-1", Type: types.DepParentChild},
+		{IssueID: "vc-2", DependsOnID: "vc-3", Type: types.DepBlocks},
+		{IssueID: "vc-4", DependsOnID: "vc-2", Type: types.DepDiscoveredFrom},
+		{IssueID: "vc-5", DependsOnID: "vc-4", Type: types.DepRelated},
+		{IssueID: "vc-10", DependsOnID: "vc-3", Type: types.DepRelated},
+		{IssueID: "vc-2", DependsOnID: "vc-99", Type: types.DepBlocks}, // Not loaded
+	}
+	return issues, deps
+}
+
+// nodeIDs lists the graph's node IDs in order
+func nodeIDs(g *Graph) string {
+	ids := make([]string, len(g.Nodes))
+	for i, node := range g.Nodes {
+		ids[i] = node.ID()
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestBuild(t *testing.T) {
+	issues, deps := fixture()
+	g, err := Build(issues, deps, nil, Options{})
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if got := nodeIDs(g); got != "vc-1,vc-2,vc-3,vc-4,vc-5,vc-10" {
+		t.Errorf("Unexpected nodes: %s", got)
+	}
+	if len(g.Edges) != 6 {
+		t.Fatalf("Expected 6 edges (the one to vc-99 dropped), got %v", g.Edges)
+	}
+	if first := g.Edges[0]; first != (Edge{From: "vc-1", To: "vc-2", Type: types.DepParentChild}) {
+		t.Errorf("Unexpected first edge: %+v", first)
+	}
+}
+
+func TestBuildScopes(t *testing.T) {
+	issues, deps := fixture()
+	tests := []struct {
+		name    string
+		labeled map[string]bool
+		opts    Options
+		want    string
+	}{
+		{"epic", nil, Options{Epic: "vc-1"}, "vc-1,vc-2,vc-3"},
+		{"focus radius 1", nil, Options{Focus: "vc-4", Radius: 1}, "vc-2,vc-4,vc-5"},
+		{"focus default radius", nil, Options{Focus: "vc-4"}, "vc-1,vc-2,vc-3,vc-4,vc-5"},
+		{"status", nil, Options{Statuses: []types.Status{types.StatusOpen}}, "vc-1,vc-3,vc-4,vc-10"},
+		{"label keeps the focus", map[string]bool{"vc-2": true}, Options{Focus: "vc-4", Radius: 1}, "vc-2,vc-4"},
+		{"epic and status", nil, Options{Epic: "vc-1", Statuses: []types.Status{types.StatusInProgress}}, "vc-1,vc-2"},
+		{"collapse closed", nil, Options{CollapseClosed: true}, "vc-1,vc-2,vc-3,vc-4,vc-10,closed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := Build(issues, deps, tt.labeled, tt.opts)
+			if err != nil {
+				t.Fatalf("Build: %v", err)
+			}
+			if got := nodeIDs(g); got != tt.want {
+				t.Errorf("Got nodes %s, want %s", got, tt.want)
+			}
+			for _, edge := range g.Edges {
+				if !strings.Contains(","+tt.want+",", ","+edge.From+",") || !strings.Contains(","+tt.want+",", ","+edge.To+",") {
+					t.Errorf("Edge %+v leaves the graph", edge)
+				}
+			}
+		})
+	}
+
+	if _, err := Build(issues, deps, nil, Options{Focus: "vc-42"}); err == nil {
+		t.Error("Expected an error for an unknown focus issue")
+	}
+	var tooLarge *TooLargeError
+	if _, err := Build(issues, deps, nil, Options{MaxNodes: 5}); !errors.As(err, &tooLarge) || tooLarge.Nodes != 6 {
+		t.Errorf("Expected a TooLargeError for 6 nodes, got %v", err)
+	}
+}
+
+func TestWriteDOT(t *testing.T) {
+	issues, deps := fixture()
+	issues[1].Title = `Split "parser"` + "\ninto stages"
+	g, err := Build(issues, deps, nil, Options{Focus: "vc-2", Radius: 1, CollapseClosed: true})
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := g.WriteDOT(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := `// vc dependency graph: edges point from the issue depended on to the one that depends on it
+digraph vc {
+  rankdir=LR;
+  node [style=filled, fontname="Helvetica", fontsize=10];
+  edge [fontname="Helvetica", fontsize=9];
+
+  "vc-1" [label="vc-1\nSplit \"parser\" into stages\nP2 open", shape=folder, fillcolor="#dbeafe"];
+  "vc-2" [label="vc-2\nIssue vc-2\nP2 in_progress", shape=box, fillcolor="#fef3c7"];
+  "vc-3" [label="vc-3\nIssue vc-3\nP2 open", shape=octagon, fillcolor="#dbeafe"];
+  "vc-4" [label="vc-4\nIssue vc-4\nP2 open", shape=ellipse, fillcolor="#dbeafe"];
+
+  "vc-1" -> "vc-2" [style=dashed, arrowhead=empty];
+  "vc-1" -> "vc-3" [style=dashed, arrowhead=empty];
+  "vc-2" -> "vc-4" [style=dotted, color="#6b7280"];
+  "vc-3" -> "vc-2" [style=solid, penwidth=2];
+}
+`
+	if buf.String() != want {
+		t.Errorf("Unexpected DOT:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	// Input order doesn't change the output
+	sort.Slice(issues, func(i, j int) bool { return issues[i].ID > issues[j].ID })
+	sort.Slice(deps, func(i, j int) bool { return deps[i].IssueID > deps[j].IssueID })
+	g2, _ := Build(issues, deps, nil, Options{Focus: "vc-2", Radius: 1, CollapseClosed: true})
+	var again bytes.Buffer
+	if err := g2.WriteDOT(&again); err != nil {
+		t.Fatal(err)
+	}
+	if again.String() != buf.String() {
+		t.Errorf("DOT output depends on input order:\n%s", again.String())
+	}
+}
+
+func TestWriteMermaid(t *testing.T) {
+	issues, deps := fixture()
+	g, err := Build(issues, deps, nil, Options{Focus: "vc-4", Radius: 1, CollapseClosed: true})
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := g.Write(&buf, FormatMermaid); err != nil {
+		t.Fatal(err)
+	}
+	want := `%% vc dependency graph: edges point from the issue depended on to the one that depends on it
+flowchart LR
+  vc_2["vc-2: Issue vc-2 (P2)"]
+  vc_4(["vc-4: Issue vc-4 (P2)"])
+  closed[("1 closed issue")]
+  vc_2 -.-> vc_4
+  vc_4 -.- closed
+  classDef open fill:#dbeafe
+  class vc_4 open
+  classDef in_progress fill:#fef3c7
+  class vc_2 in_progress
+  classDef collapsed fill:#f3f4f6,color:#6b7280
+  class closed collapsed
+`
+	if buf.String() != want {
+		t.Errorf("Unexpected Mermaid:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	if err := g.Write(&buf, "svg"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+	for _, title := range []string{"Parser", "Lexer", "Docs"} {
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.AddDependency(ctx, &types.Dependency{IssueID: "vc-1", DependsOnID: "vc-2", Type: types.DepBlocks}, "test"); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"vc-1", "vc-2"} {
+		if err := store.AddLabel(ctx, id, "frontend", "test"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	g, err := Load(ctx, store, Options{Labels: []string{"frontend"}})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := nodeIDs(g); got != "vc-1,vc-2" {
+		t.Errorf("Expected the frontend issues, got %s", got)
+	}
+	if len(g.Edges) != 1 || g.Edges[0] != (Edge{From: "vc-2", To: "vc-1", Type: types.DepBlocks}) {
+		t.Errorf("Unexpected edges: %+v", g.Edges)
+	}
+}
+
+func TestIDLess(t *testing.T) {
+	ids := []string{"closed", "vc-10", "web-1", "vc-2", "vc-x", "vc-1"}
+	sort.Slice(ids, func(i, j int) bool { return IDLess(ids[i], ids[j]) })
+	if got := strings.Join(ids, ","); got != "vc-1,vc-2,vc-10,vc-x,web-1,closed" {
+		t.Errorf("Unexpected order: %s", got)
+	}
+}
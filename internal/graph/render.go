@@ -0,0 +1,204 @@
+package graph
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// Formats Write renders
+const (
+	FormatDOT     = "dot"
+	FormatMermaid = "mermaid"
+)
+
+// maxTitleLen is how much of a title a node label shows
+const maxTitleLen = 40
+
+// Node fill colors by status, shared by both formats
+var statusColors = map[types.Status]string{
+	types.StatusOpen:       "#dbeafe",
+	types.StatusInProgress: "#fef3c7",
+	types.StatusBlocked:    "#fecaca",
+	types.StatusClosed:     "#e5e7eb",
+}
+
+// collapsedColor fills the node standing for collapsed closed issues
+const collapsedColor = "#f3f4f6"
+
+// DOT node shapes by issue type
+var dotShapes = map[types.IssueType]string{
+	types.TypeTask:    "box",
+	types.TypeBug:     "octagon",
+	types.TypeFeature: "ellipse",
+	types.TypeEpic:    "folder",
+	types.TypeChore:   "note",
+}
+
+// DOT edge attributes by dependency type
+var dotEdgeStyles = map[types.DependencyType]string{
+	types.DepBlocks:         `style=solid, penwidth=2`,
+	types.DepParentChild:    `style=dashed, arrowhead=empty`,
+	types.DepDiscoveredFrom: `style=dotted, color="#6b7280"`,
+	types.DepRelated:        `style=dotted, dir=none, color="#6b7280"`,
+}
+
+// Mermaid node brackets by issue type
+var mermaidShapes = map[types.IssueType][2]string{
+	types.TypeTask:    {"[", "]"},
+	types.TypeBug:     {"{{", "}}"},
+	types.TypeFeature: {"([", "])"},
+	types.TypeEpic:    {"[[", "]]"},
+	types.TypeChore:   {">", "]"},
+}
+
+// Mermaid edge arrows by dependency type
+var mermaidArrows = map[types.DependencyType]string{
+	types.DepBlocks:         "==>",
+	types.DepParentChild:    "-->",
+	types.DepDiscoveredFrom: "-.->",
+	types.DepRelated:        "-.-",
+}
+
+// Write renders the graph in format (FormatDOT or FormatMermaid)
+func (g *Graph) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatDOT:
+		return g.WriteDOT(w)
+	case FormatMermaid:
+		return g.WriteMermaid(w)
+	default:
+		return fmt.Errorf("unknown format %q (want %s or %s)", format, FormatDOT, FormatMermaid)
+	}
+}
+
+// WriteDOT renders the graph as a Graphviz digraph: nodes filled by status
+// and shaped by type, edges styled by dependency type
+func (g *Graph) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("// vc dependency graph: edges point from the issue depended on to the one that depends on it\n")
+	b.WriteString("digraph vc {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [style=filled, fontname=\"Helvetica\", fontsize=10];\n")
+	b.WriteString("  edge [fontname=\"Helvetica\", fontsize=9];\n")
+	if len(g.Nodes) > 0 {
+		b.WriteString("\n")
+	}
+	for _, node := range g.Nodes {
+		if node.Collapsed > 0 {
+			fmt.Fprintf(&b, "  %s [label=%s, shape=box3d, fillcolor=%q, fontcolor=\"#6b7280\"];\n",
+				dotQuote(CollapsedID), dotQuote(collapsedLabel(node)), collapsedColor)
+			continue
+		}
+		issue := node.Issue
+		attrs := fmt.Sprintf("label=%s, shape=%s, fillcolor=%q",
+			dotQuote(fmt.Sprintf("%s\n%s\nP%d %s", issue.ID, shortTitle(issue.Title), issue.Priority, issue.Status)),
+			lookup(dotShapes, issue.IssueType, "box"), lookup(statusColors, issue.Status, "#ffffff"))
+		if issue.Status == types.StatusClosed {
+			attrs += `, fontcolor="#6b7280"`
+		}
+		fmt.Fprintf(&b, "  %s [%s];\n", dotQuote(issue.ID), attrs)
+	}
+	if len(g.Edges) > 0 {
+		b.WriteString("\n")
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&b, "  %s -> %s [%s];\n", dotQuote(edge.From), dotQuote(edge.To),
+			lookup(dotEdgeStyles, edge.Type, "style=solid"))
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteMermaid renders the graph as a Mermaid flowchart: nodes classed by
+// status and shaped by type, edges drawn by dependency type (==> blocks,
+// --> parent-child, -.-> discovered-from, -.- related)
+func (g *Graph) WriteMermaid(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("%% vc dependency graph: edges point from the issue depended on to the one that depends on it\n")
+	b.WriteString("flowchart LR\n")
+
+	classes := make(map[string][]string)
+	for _, node := range g.Nodes {
+		id := mermaidID(node.ID())
+		if node.Collapsed > 0 {
+			fmt.Fprintf(&b, "  %s[(%s)]\n", id, mermaidQuote(collapsedLabel(node)))
+			classes["collapsed"] = append(classes["collapsed"], id)
+			continue
+		}
+		issue := node.Issue
+		shape := lookup(mermaidShapes, issue.IssueType, [2]string{"[", "]"})
+		label := fmt.Sprintf("%s: %s (P%d)", issue.ID, shortTitle(issue.Title), issue.Priority)
+		fmt.Fprintf(&b, "  %s%s%s%s\n", id, shape[0], mermaidQuote(label), shape[1])
+		classes[string(issue.Status)] = append(classes[string(issue.Status)], id)
+	}
+	for _, edge := range g.Edges {
+		fmt.Fprintf(&b, "  %s %s %s\n", mermaidID(edge.From), lookup(mermaidArrows, edge.Type, "-->"), mermaidID(edge.To))
+	}
+
+	// Class definitions in a fixed order, for stable output
+	for _, status := range []types.Status{types.StatusOpen, types.StatusInProgress, types.StatusBlocked, types.StatusClosed} {
+		class := string(status)
+		if ids := classes[class]; len(ids) > 0 {
+			fmt.Fprintf(&b, "  classDef %s fill:%s\n", class, statusColors[status])
+			fmt.Fprintf(&b, "  class %s %s\n", strings.Join(ids, ","), class)
+		}
+	}
+	if ids := classes["collapsed"]; len(ids) > 0 {
+		fmt.Fprintf(&b, "  classDef collapsed fill:%s,color:#6b7280\n", collapsedColor)
+		fmt.Fprintf(&b, "  class %s collapsed\n", strings.Join(ids, ","))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// collapsedLabel labels the node standing for collapsed closed issues
+func collapsedLabel(node *Node) string {
+	if node.Collapsed == 1 {
+		return "1 closed issue"
+	}
+	return fmt.Sprintf("%d closed issues", node.Collapsed)
+}
+
+// shortTitle flattens a title to one line of at most maxTitleLen runes
+func shortTitle(title string) string {
+	title = strings.Join(strings.Fields(title), " ")
+	if utf8.RuneCountInString(title) <= maxTitleLen {
+		return title
+	}
+	return string([]rune(title)[:maxTitleLen-1]) + "…"
+}
+
+// dotQuote quotes s as a DOT string; newlines become line breaks
+func dotQuote(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+	return `"` + s + `"`
+}
+
+// mermaidID turns an issue ID into a Mermaid node ID (letters, digits and
+// underscores)
+func mermaidID(id string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9') {
+			return r
+		}
+		return '_'
+	}, id)
+}
+
+// mermaidQuote quotes s as a Mermaid label, escaping quotes as entities
+func mermaidQuote(s string) string {
+	return `"` + strings.NewReplacer(`"`, "#quot;").Replace(s) + `"`
+}
+
+// lookup returns m[key], or fallback if it is missing
+func lookup[K comparable, V any](m map[K]V, key K, fallback V) V {
+	if v, ok := m[key]; ok {
+		return v
+	}
+	return fallback
+}
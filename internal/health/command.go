@@ -0,0 +1,249 @@
+package health
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// MonitorTypeCommand marks a health_monitors.yaml entry as a custom monitor
+// backed by an external command.
+const MonitorTypeCommand = "command"
+
+// maxCommandOutput caps how much command output is quoted in warnings.
+const maxCommandOutput = 500
+
+// CommandMonitor runs a user-provided command and turns its JSON output into
+// findings, so teams can add their own checks (license headers, schema drift)
+// without changing VC. The command must print:
+//
+//	{"findings": [{"title": "...", "detail": "...", "severity": "low|medium|high", "files": ["..."]}]}
+//
+// A non-zero exit status with valid output is fine; checks often exit 1 when
+// they find something.
+type CommandMonitor struct {
+	// MonitorName is the key of the monitor in health_monitors.yaml
+	MonitorName string
+
+	// Principle is returned by Philosophy (optional)
+	Principle string
+
+	// Command and arguments to run (no shell is involved)
+	Command []string
+
+	// WorkingDir for the command (default: the project root)
+	WorkingDir string
+
+	// Env holds extra environment variables on top of the executor's environment
+	Env map[string]string
+
+	// Timeout bounds a single run (default: DefaultMonitorTimeout)
+	Timeout time.Duration
+
+	// RunSchedule is returned by Schedule (default: daily)
+	RunSchedule ScheduleConfig
+}
+
+// NewCommandMonitor creates a custom monitor from its health_monitors.yaml entry.
+// Relative working directories are resolved against rootPath.
+func NewCommandMonitor(name string, rootPath string, cfg MonitorConfig) (*CommandMonitor, error) {
+	if len(cfg.Command) == 0 || strings.TrimSpace(cfg.Command[0]) == "" {
+		return nil, fmt.Errorf("monitor %s: command is required", name)
+	}
+
+	workingDir := cfg.WorkingDir
+	if workingDir == "" {
+		workingDir = rootPath
+	} else if !filepath.IsAbs(workingDir) {
+		workingDir = filepath.Join(rootPath, workingDir)
+	}
+
+	timeout := DefaultMonitorTimeout
+	if cfg.Timeout != "" {
+		parsed, err := parseDuration(cfg.Timeout)
+		if err != nil || parsed <= 0 {
+			return nil, fmt.Errorf("monitor %s: invalid timeout %q", name, cfg.Timeout)
+		}
+		timeout = parsed
+	}
+
+	schedule := ScheduleConfig{Type: ScheduleTimeBased, Interval: 24 * time.Hour}
+	if cfg.Schedule.Type != "" {
+		parsed, err := cfg.Schedule.ToScheduleConfig()
+		if err != nil {
+			return nil, fmt.Errorf("monitor %s: %w", name, err)
+		}
+		schedule = parsed
+	}
+
+	return &CommandMonitor{
+		MonitorName: name,
+		Principle:   cfg.Philosophy,
+		Command:     cfg.Command,
+		WorkingDir:  workingDir,
+		Env:         cfg.Env,
+		Timeout:     timeout,
+		RunSchedule: schedule,
+	}, nil
+}
+
+// Name implements HealthMonitor.
+func (m *CommandMonitor) Name() string {
+	return m.MonitorName
+}
+
+// Philosophy implements HealthMonitor.
+func (m *CommandMonitor) Philosophy() string {
+	if m.Principle != "" {
+		return m.Principle
+	}
+	return "Project-specific conventions deserve the same follow-through as built-in checks."
+}
+
+// Schedule implements HealthMonitor.
+func (m *CommandMonitor) Schedule() ScheduleConfig {
+	return m.RunSchedule
+}
+
+// Cost implements HealthMonitor.
+func (m *CommandMonitor) Cost() CostEstimate {
+	return CostEstimate{
+		EstimatedDuration: m.Timeout,
+		AICallsEstimated:  0,
+		Category:          CostModerate,
+	}
+}
+
+// commandOutput is the JSON contract for custom monitor commands.
+type commandOutput struct {
+	Findings []commandFinding `json:"findings"`
+}
+
+// commandFinding is one finding reported by a custom monitor command.
+type commandFinding struct {
+	Title    string   `json:"title"`
+	Detail   string   `json:"detail"`
+	Severity string   `json:"severity"`
+	Files    []string `json:"files"`
+}
+
+// Check implements HealthMonitor.
+// Output that can't be parsed is reported as a warning on an otherwise empty
+// result rather than an error, so one broken script can't take down the loop.
+func (m *CommandMonitor) Check(ctx context.Context, codebase CodebaseContext) (*MonitorResult, error) {
+	startTime := time.Now()
+
+	if m.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.Timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(ctx, m.Command[0], m.Command[1:]...)
+	cmd.Dir = m.WorkingDir
+	cmd.Env = os.Environ()
+	for key, value := range m.Env {
+		cmd.Env = append(cmd.Env, key+"="+value)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// Don't wait forever on pipes held open by grandchildren after a timeout
+	cmd.WaitDelay = 5 * time.Second
+
+	runErr := cmd.Run()
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("command %q did not finish: %w", m.Command[0], ctx.Err())
+	}
+	var exitErr *exec.ExitError
+	if runErr != nil && !errors.As(runErr, &exitErr) {
+		// The command could not be started at all (missing binary, bad working dir)
+		return nil, fmt.Errorf("running command %q: %w", m.Command[0], runErr)
+	}
+
+	result := &MonitorResult{
+		CheckedAt: startTime,
+		Context:   fmt.Sprintf("Ran %s", strings.Join(m.Command, " ")),
+	}
+
+	var output commandOutput
+	if err := json.Unmarshal(bytes.TrimSpace(stdout.Bytes()), &output); err != nil {
+		warning := fmt.Sprintf("command %q produced malformed output (%v): %s",
+			m.Command[0], err, truncateOutput(stdout.String()))
+		if runErr != nil {
+			warning += fmt.Sprintf("; exit: %v; stderr: %s", runErr, truncateOutput(stderr.String()))
+		}
+		result.Warnings = append(result.Warnings, warning)
+		result.Stats.Duration = time.Since(startTime)
+		return result, nil
+	}
+
+	for i, finding := range output.Findings {
+		issue, warning := m.buildIssue(finding)
+		if warning != "" {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("finding %d: %s", i, warning))
+		}
+		if issue != nil {
+			result.IssuesFound = append(result.IssuesFound, *issue)
+		}
+	}
+
+	result.Stats.IssuesFound = len(result.IssuesFound)
+	result.Stats.Duration = time.Since(startTime)
+	return result, nil
+}
+
+// buildIssue converts a command finding. Findings without a title are
+// dropped; unknown severities fall back to medium. Either case returns a warning.
+func (m *CommandMonitor) buildIssue(finding commandFinding) (*DiscoveredIssue, string) {
+	title := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(finding.Title), "."))
+	if title == "" {
+		return nil, "missing title, skipped"
+	}
+
+	var warning string
+	severity := strings.ToLower(strings.TrimSpace(finding.Severity))
+	if _, ok := severityRank[severity]; !ok {
+		warning = fmt.Sprintf("invalid severity %q, using medium", finding.Severity)
+		severity = "medium"
+	}
+
+	// The title is the first sentence so it becomes the issue title
+	description := title + "."
+	if detail := strings.TrimSpace(finding.Detail); detail != "" {
+		description += "\n\n" + detail
+	}
+
+	issue := &DiscoveredIssue{
+		Category:    m.MonitorName,
+		Severity:    severity,
+		Description: description,
+		Evidence:    map[string]interface{}{},
+	}
+	if len(finding.Files) == 1 {
+		issue.FilePath = finding.Files[0]
+	}
+	if len(finding.Files) > 0 {
+		issue.Evidence["files"] = strings.Join(finding.Files, ", ")
+	}
+	return issue, warning
+}
+
+// truncateOutput shortens command output for warnings.
+func truncateOutput(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) > maxCommandOutput {
+		return s[:maxCommandOutput] + "..."
+	}
+	if s == "" {
+		return "(empty)"
+	}
+	return s
+}
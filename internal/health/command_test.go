@@ -0,0 +1,91 @@
+package health
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newShellMonitor(t *testing.T, root, script string, cfg MonitorConfig) *CommandMonitor {
+	t.Helper()
+	cfg.Type = MonitorTypeCommand
+	cfg.Command = []string{"sh", "-c", script}
+	monitor, err := NewCommandMonitor("license_headers", root, cfg)
+	require.NoError(t, err)
+	return monitor
+}
+
+func TestCommandMonitor_ParsesFindings(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "sub"), 0755))
+
+	// Checks commonly exit non-zero when they find something
+	script := `cat <<JSON
+{"findings": [
+  {"title": "Missing license header", "detail": "Found in $(basename "$PWD") for $TEAM", "severity": "HIGH", "files": ["a.go", "b.go"]},
+  {"title": "Stale header year", "severity": "urgent", "files": ["c.go"]},
+  {"title": "", "severity": "low"}
+]}
+JSON
+exit 1`
+	monitor := newShellMonitor(t, root, script, MonitorConfig{
+		WorkingDir: "sub",
+		Env:        map[string]string{"TEAM": "platform"},
+	})
+
+	result, err := monitor.Check(context.Background(), CodebaseContext{})
+	require.NoError(t, err)
+	require.Len(t, result.IssuesFound, 2)
+
+	first := result.IssuesFound[0]
+	assert.Equal(t, "license_headers", first.Category)
+	assert.Equal(t, "high", first.Severity)
+	assert.Equal(t, "Missing license header.\n\nFound in sub for platform", first.Description)
+	assert.Equal(t, "a.go, b.go", first.Evidence["files"])
+	assert.Empty(t, first.FilePath)
+
+	second := result.IssuesFound[1]
+	assert.Equal(t, "medium", second.Severity)
+	assert.Equal(t, "c.go", second.FilePath)
+
+	require.Len(t, result.Warnings, 2)
+	assert.Contains(t, result.Warnings[0], "invalid severity")
+	assert.Contains(t, result.Warnings[1], "missing title")
+}
+
+func TestCommandMonitor_MalformedOutputIsWarning(t *testing.T) {
+	monitor := newShellMonitor(t, t.TempDir(), `echo "not json"; echo "oops" >&2; exit 3`, MonitorConfig{})
+
+	result, err := monitor.Check(context.Background(), CodebaseContext{})
+	require.NoError(t, err)
+	assert.Empty(t, result.IssuesFound)
+	require.Len(t, result.Warnings, 1)
+	assert.Contains(t, result.Warnings[0], "malformed output")
+	assert.Contains(t, result.Warnings[0], "not json")
+	assert.Contains(t, result.Warnings[0], "oops")
+}
+
+func TestCommandMonitor_Errors(t *testing.T) {
+	root := t.TempDir()
+
+	_, err := NewCommandMonitor("empty", root, MonitorConfig{Type: MonitorTypeCommand})
+	assert.Error(t, err)
+
+	_, err = NewCommandMonitor("bad_timeout", root, MonitorConfig{Command: []string{"true"}, Timeout: "forever"})
+	assert.Error(t, err)
+
+	missing, err := NewCommandMonitor("missing", root, MonitorConfig{Command: []string{"/nonexistent/check"}})
+	require.NoError(t, err)
+	_, err = missing.Check(context.Background(), CodebaseContext{})
+	assert.Error(t, err)
+
+	slow := newShellMonitor(t, root, "exec sleep 5", MonitorConfig{Timeout: "100ms"})
+	_, err = slow.Check(context.Background(), CodebaseContext{})
+	require.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "did not finish"), err.Error())
+}
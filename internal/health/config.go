@@ -22,8 +22,33 @@ type MonitorConfig struct {
 	// Enabled controls whether this specific monitor runs
 	Enabled bool `yaml:"enabled"`
 
-	// Schedule configuration
+	// Schedule configuration (overrides the monitor's built-in schedule)
 	Schedule ScheduleYAMLConfig `yaml:"schedule"`
+
+	// Timeout bounds a single run, e.g. "5m" (default: 10m)
+	Timeout string `yaml:"timeout,omitempty"`
+
+	// Markers maps comment markers to the severity of findings they cause,
+	// e.g. {"FIXME": "high", "TODO": "low"} (todo_density_monitor only)
+	Markers map[string]string `yaml:"markers,omitempty"`
+
+	// ExcludePatterns adds paths to skip, e.g. generated or vendored code
+	ExcludePatterns []string `yaml:"exclude_patterns,omitempty"`
+
+	// Type is "command" for a custom monitor; empty for built-in monitors
+	Type string `yaml:"type,omitempty"`
+
+	// Command and arguments run by a custom monitor, e.g. ["./scripts/check-licenses", "--json"]
+	Command []string `yaml:"command,omitempty"`
+
+	// WorkingDir for a custom monitor's command (default: project root)
+	WorkingDir string `yaml:"working_dir,omitempty"`
+
+	// Env holds extra environment variables for a custom monitor's command
+	Env map[string]string `yaml:"env,omitempty"`
+
+	// Philosophy describes the principle a custom monitor upholds
+	Philosophy string `yaml:"philosophy,omitempty"`
 }
 
 // ScheduleYAMLConfig represents a schedule in the YAML config file.
@@ -42,6 +67,9 @@ type ScheduleYAMLConfig struct {
 	// For hybrid schedules
 	MinInterval string `yaml:"min_interval,omitempty"` // e.g., "1h"
 	MaxInterval string `yaml:"max_interval,omitempty"` // e.g., "168h"
+
+	// Daily time-of-day window in local time, e.g. "01:00-05:00"
+	Window string `yaml:"window,omitempty"`
 }
 
 // LoadConfig loads health monitoring configuration from a YAML file.
@@ -102,6 +130,14 @@ func (c *ScheduleYAMLConfig) ToScheduleConfig() (ScheduleConfig, error) {
 		config.MaxInterval = maxInterval
 	}
 
+	if c.Window != "" {
+		window, err := ParseTimeWindow(c.Window)
+		if err != nil {
+			return config, fmt.Errorf("invalid window %q: %w", c.Window, err)
+		}
+		config.Window = window
+	}
+
 	// Parse event-based fields
 	if c.EveryNIssues > 0 {
 		config.EventTrigger = fmt.Sprintf("every_%d_issues", c.EveryNIssues)
@@ -149,6 +185,14 @@ func DefaultConfig() *HealthConfig {
 					Interval: "24h",
 				},
 			},
+			"todo_density_monitor": {
+				Enabled: true,
+				Schedule: ScheduleYAMLConfig{
+					Type:     "time_based",
+					Interval: "24h",
+				},
+				Markers: DefaultMarkerSeverities(),
+			},
 		},
 	}
 }
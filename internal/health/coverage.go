@@ -0,0 +1,327 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CoverageSnapshot records per-package test coverage at a point in time.
+type CoverageSnapshot struct {
+	Timestamp time.Time          `json:"timestamp"`
+	Packages  map[string]float64 `json:"packages"` // Package path -> percent of statements covered
+}
+
+// CoverageHistoryStore persists coverage snapshots between runs.
+// MonitorRegistry implements this via health_state.json.
+type CoverageHistoryStore interface {
+	GetCoverageHistory(monitorName string) []CoverageSnapshot
+	RecordCoverage(monitorName string, snapshot CoverageSnapshot, keepSince time.Time) error
+}
+
+// CoverageRunner runs the test suite with coverage in dir and returns the combined output.
+type CoverageRunner func(ctx context.Context, dir string) (string, error)
+
+// CoverageMonitor tracks per-package test coverage over time and reports
+// packages whose coverage dropped by more than DropThreshold within Window.
+//
+// ZFC Compliance: Coverage numbers and drops are facts computed from `go test`
+// output. The AI supervisor is only used to write the human-readable
+// description of the finding.
+type CoverageMonitor struct {
+	// RootPath is the codebase root directory
+	RootPath string
+
+	// DropThreshold is how many percentage points a package may lose within
+	// Window before an issue is filed. Default: 5.0
+	DropThreshold float64
+
+	// Window is how far back the baseline snapshot may be. Default: 7 days
+	Window time.Duration
+
+	// Interval between runs. The test suite can be slow, so this runs on its
+	// own schedule rather than after every executed issue. Default: 24 hours
+	Interval time.Duration
+
+	// Timeout bounds a single test suite run. Default: 10 minutes
+	Timeout time.Duration
+
+	// History persists snapshots between runs (nil keeps no history)
+	History CoverageHistoryStore
+
+	// Runner executes the test suite (default: go test -short -cover ./...)
+	Runner CoverageRunner
+
+	// AI supervisor for writing issue descriptions (optional)
+	Supervisor AISupervisor
+}
+
+// NewCoverageMonitor creates a coverage monitor with sensible defaults.
+// Returns an error if the rootPath cannot be resolved to an absolute path.
+func NewCoverageMonitor(rootPath string, supervisor AISupervisor, history CoverageHistoryStore) (*CoverageMonitor, error) {
+	absPath, err := filepath.Abs(rootPath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid root path %q: %w", rootPath, err)
+	}
+
+	return &CoverageMonitor{
+		RootPath:      absPath,
+		DropThreshold: 5.0,
+		Window:        7 * 24 * time.Hour,
+		Interval:      24 * time.Hour,
+		Timeout:       10 * time.Minute,
+		History:       history,
+		Runner:        runGoTestCoverage,
+		Supervisor:    supervisor,
+	}, nil
+}
+
+// Name implements HealthMonitor.
+func (m *CoverageMonitor) Name() string {
+	return "coverage_monitor"
+}
+
+// Philosophy implements HealthMonitor.
+func (m *CoverageMonitor) Philosophy() string {
+	return "Tests are how a codebase defends its behavior. " +
+		"Coverage that quietly erodes means changes are landing without that defense."
+}
+
+// Schedule implements HealthMonitor.
+func (m *CoverageMonitor) Schedule() ScheduleConfig {
+	return ScheduleConfig{
+		Type:     ScheduleTimeBased,
+		Interval: m.Interval,
+	}
+}
+
+// Cost implements HealthMonitor.
+func (m *CoverageMonitor) Cost() CostEstimate {
+	return CostEstimate{
+		EstimatedDuration: 2 * time.Minute,
+		AICallsEstimated:  1, // Only when a drop is found
+		RequiresFullScan:  true,
+		Category:          CostExpensive,
+	}
+}
+
+// coverageDrop describes a package whose coverage fell within the window.
+type coverageDrop struct {
+	Package  string
+	Baseline float64
+	Current  float64
+	Since    time.Time
+}
+
+// Drop returns the loss in percentage points.
+func (d coverageDrop) Drop() float64 {
+	return d.Baseline - d.Current
+}
+
+// Check implements HealthMonitor.
+// The codebase parameter is unused; coverage comes from running the test suite.
+func (m *CoverageMonitor) Check(ctx context.Context, codebase CodebaseContext) (*MonitorResult, error) {
+	startTime := time.Now()
+
+	runCtx := ctx
+	if m.Timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, m.Timeout)
+		defer cancel()
+	}
+
+	// Failing tests still report coverage for the packages that passed, so a
+	// runner error only matters when no coverage was produced at all
+	output, runErr := m.Runner(runCtx, m.RootPath)
+	current := parseCoverageOutput(output)
+	if len(current) == 0 {
+		if runErr != nil {
+			return nil, fmt.Errorf("running tests with coverage: %w", runErr)
+		}
+		return &MonitorResult{
+			Context:   "No packages reported coverage",
+			CheckedAt: startTime,
+			Stats:     CheckStats{Duration: time.Since(startTime)},
+		}, nil
+	}
+
+	var history []CoverageSnapshot
+	if m.History != nil {
+		history = m.History.GetCoverageHistory(m.Name())
+	}
+	windowStart := startTime.Add(-m.Window)
+	drops := findCoverageDrops(history, current, windowStart, m.DropThreshold)
+
+	if m.History != nil {
+		snapshot := CoverageSnapshot{Timestamp: startTime, Packages: current}
+		if err := m.History.RecordCoverage(m.Name(), snapshot, windowStart); err != nil {
+			return nil, fmt.Errorf("recording coverage history: %w", err)
+		}
+	}
+
+	result := &MonitorResult{
+		Context:   m.buildContext(current, history, windowStart),
+		CheckedAt: startTime,
+	}
+
+	if len(drops) > 0 {
+		description, aiCalls := m.describeDrops(ctx, drops)
+		result.IssuesFound = []DiscoveredIssue{m.buildIssue(drops, description)}
+		result.Reasoning = fmt.Sprintf("%d package(s) lost more than %.1f percentage points of coverage within %v",
+			len(drops), m.DropThreshold, m.Window)
+		result.Stats.AICallsMade = aiCalls
+	}
+
+	result.Stats.IssuesFound = len(result.IssuesFound)
+	result.Stats.Duration = time.Since(startTime)
+	return result, nil
+}
+
+// coverageLinePattern matches `go test -cover` lines such as
+// "ok  	example.com/pkg	0.01s	coverage: 81.2% of statements".
+var coverageLinePattern = regexp.MustCompile(`^\s*(?:ok\s+)?(\S+)\s+.*?coverage: (\d+(?:\.\d+)?)% of statements`)
+
+// parseCoverageOutput extracts per-package coverage from `go test -cover` output.
+func parseCoverageOutput(output string) map[string]float64 {
+	coverage := make(map[string]float64)
+	for _, line := range strings.Split(output, "\n") {
+		match := coverageLinePattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		percent, err := strconv.ParseFloat(match[2], 64)
+		if err != nil {
+			continue
+		}
+		coverage[match[1]] = percent
+	}
+	return coverage
+}
+
+// findCoverageDrops compares current coverage against the oldest snapshot in
+// the window that includes each package. Packages that disappeared are ignored.
+// Results are sorted by largest drop first.
+func findCoverageDrops(history []CoverageSnapshot, current map[string]float64, windowStart time.Time, threshold float64) []coverageDrop {
+	var drops []coverageDrop
+	for pkg, now := range current {
+		for _, snapshot := range history {
+			if snapshot.Timestamp.Before(windowStart) {
+				continue
+			}
+			baseline, ok := snapshot.Packages[pkg]
+			if !ok {
+				continue
+			}
+			if baseline-now > threshold {
+				drops = append(drops, coverageDrop{Package: pkg, Baseline: baseline, Current: now, Since: snapshot.Timestamp})
+			}
+			break
+		}
+	}
+
+	sort.Slice(drops, func(i, j int) bool {
+		if drops[i].Drop() != drops[j].Drop() {
+			return drops[i].Drop() > drops[j].Drop()
+		}
+		return drops[i].Package < drops[j].Package
+	})
+	return drops
+}
+
+// describeDrops returns the issue description. The first sentence is
+// deterministic so repeated findings get the same title; the supervisor, if
+// available, adds a short narrative. Returns the number of AI calls made.
+func (m *CoverageMonitor) describeDrops(ctx context.Context, drops []coverageDrop) (string, int) {
+	summary := fmt.Sprintf("Test coverage dropped in %d package(s) over the last %s.", len(drops), formatWindow(m.Window))
+
+	var details strings.Builder
+	for _, d := range drops {
+		details.WriteString(fmt.Sprintf("- %s: %.1f%% -> %.1f%% (-%.1f points since %s)\n",
+			d.Package, d.Baseline, d.Current, d.Drop(), d.Since.Format("2006-01-02")))
+	}
+
+	if m.Supervisor == nil {
+		return summary + "\n\n" + details.String(), 0
+	}
+
+	prompt := fmt.Sprintf(`You are writing the description of a code health issue about declining test coverage.
+
+PHILOSOPHY: %s
+
+The following packages lost more than %.1f percentage points of statement coverage in the last %s:
+%s
+Write 2-4 sentences for the engineer who picks up this issue: summarize which areas lost coverage
+and suggest where to start adding tests. Do not restate or recompute the numbers and do not speculate
+about causes you cannot see. Respond with plain text only.`,
+		m.Philosophy(), m.DropThreshold, formatWindow(m.Window), details.String())
+
+	response, err := m.Supervisor.CallAI(ctx, prompt, "coverage_description", "", 1024)
+	if err != nil || strings.TrimSpace(response) == "" {
+		return summary + "\n\n" + details.String(), 1
+	}
+
+	return summary + " " + strings.TrimSpace(response) + "\n\n" + details.String(), 1
+}
+
+// buildIssue creates the discovered issue for a set of coverage drops.
+func (m *CoverageMonitor) buildIssue(drops []coverageDrop, description string) DiscoveredIssue {
+	severity := "medium"
+	packages := make([]string, len(drops))
+	for i, d := range drops {
+		packages[i] = d.Package
+		if d.Drop() >= 2*m.DropThreshold {
+			severity = "high"
+		}
+	}
+
+	return DiscoveredIssue{
+		Category:    "coverage",
+		Severity:    severity,
+		Description: description,
+		Evidence: map[string]interface{}{
+			"packages":          packages,
+			"largest_drop":      drops[0].Drop(),
+			"threshold":         m.DropThreshold,
+			"window":            m.Window.String(),
+			"packages_affected": len(drops),
+		},
+	}
+}
+
+// buildContext describes what was measured for the AI/verbose output.
+func (m *CoverageMonitor) buildContext(current map[string]float64, history []CoverageSnapshot, windowStart time.Time) string {
+	inWindow := 0
+	for _, snapshot := range history {
+		if !snapshot.Timestamp.Before(windowStart) {
+			inWindow++
+		}
+	}
+	return fmt.Sprintf("Measured coverage for %d packages; compared against %d snapshot(s) from the last %s",
+		len(current), inWindow, formatWindow(m.Window))
+}
+
+// formatWindow renders whole-day windows as days and anything else as a duration.
+func formatWindow(window time.Duration) string {
+	day := 24 * time.Hour
+	if window >= day && window%day == 0 {
+		return fmt.Sprintf("%d days", window/day)
+	}
+	return window.String()
+}
+
+// runGoTestCoverage runs the Go test suite with coverage enabled.
+// Databases are kept in memory like the quality gates' test run.
+func runGoTestCoverage(ctx context.Context, dir string) (string, error) {
+	cmd := exec.CommandContext(ctx, "go", "test", "-short", "-cover", "./...")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "VC_DB_PATH=:memory:", "BD_DB_PATH=:memory:")
+	output, err := cmd.CombinedOutput()
+	return string(output), err
+}
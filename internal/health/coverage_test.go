@@ -0,0 +1,166 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleCoverageOutput = `ok  	github.com/example/app/api	0.012s	coverage: 82.5% of statements
+ok  	github.com/example/app/store	(cached)	coverage: 61.0% of statements
+	github.com/example/app/cmd		coverage: 0.0% of statements
+ok  	github.com/example/app/empty	0.001s	coverage: [no statements]
+?   	github.com/example/app/docs	[no test files]
+--- FAIL: TestSomething (0.00s)
+FAIL	github.com/example/app/broken	0.004s
+`
+
+func TestParseCoverageOutput(t *testing.T) {
+	coverage := parseCoverageOutput(sampleCoverageOutput)
+
+	assert.Equal(t, map[string]float64{
+		"github.com/example/app/api":   82.5,
+		"github.com/example/app/store": 61.0,
+		"github.com/example/app/cmd":   0.0,
+	}, coverage)
+}
+
+func TestFindCoverageDrops(t *testing.T) {
+	now := time.Now()
+	windowStart := now.Add(-7 * 24 * time.Hour)
+	history := []CoverageSnapshot{
+		{Timestamp: now.Add(-10 * 24 * time.Hour), Packages: map[string]float64{"a": 99, "b": 99}},
+		{Timestamp: now.Add(-5 * 24 * time.Hour), Packages: map[string]float64{"a": 80, "b": 70}},
+		{Timestamp: now.Add(-1 * 24 * time.Hour), Packages: map[string]float64{"a": 60, "b": 70, "c": 90}},
+	}
+	current := map[string]float64{"a": 70, "b": 68, "c": 50, "d": 10}
+
+	drops := findCoverageDrops(history, current, windowStart, 5.0)
+
+	// a: baseline is the oldest in-window snapshot (80), not the pre-window 99
+	// b: 2 points is under the threshold
+	// c: first seen a day ago, 40 point drop
+	// d: no baseline
+	require.Len(t, drops, 2)
+	assert.Equal(t, "c", drops[0].Package)
+	assert.InDelta(t, 40.0, drops[0].Drop(), 0.001)
+	assert.Equal(t, "a", drops[1].Package)
+	assert.InDelta(t, 80.0, drops[1].Baseline, 0.001)
+}
+
+func TestCoverageMonitor_Check(t *testing.T) {
+	registry, err := NewMonitorRegistry(filepath.Join(t.TempDir(), "health_state.json"))
+	require.NoError(t, err)
+
+	output := "ok  \tgithub.com/example/app/api\t0.01s\tcoverage: 80.0% of statements\n"
+	monitor, err := NewCoverageMonitor(t.TempDir(), nil, registry)
+	require.NoError(t, err)
+	monitor.Runner = func(ctx context.Context, dir string) (string, error) {
+		return output, nil
+	}
+
+	// First run only records a baseline
+	result, err := monitor.Check(context.Background(), CodebaseContext{})
+	require.NoError(t, err)
+	assert.Empty(t, result.IssuesFound)
+	require.Len(t, registry.GetCoverageHistory(monitor.Name()), 1)
+
+	// Coverage drops by 12 points: one high-severity issue
+	output = "ok  \tgithub.com/example/app/api\t0.01s\tcoverage: 68.0% of statements\n"
+	result, err = monitor.Check(context.Background(), CodebaseContext{})
+	require.NoError(t, err)
+	require.Len(t, result.IssuesFound, 1)
+
+	issue := result.IssuesFound[0]
+	assert.Equal(t, "coverage", issue.Category)
+	assert.Equal(t, "high", issue.Severity)
+	assert.Contains(t, issue.Description, "Test coverage dropped in 1 package(s) over the last 7 days.")
+	assert.Contains(t, issue.Description, "github.com/example/app/api: 80.0% -> 68.0%")
+	assert.Equal(t, []string{"github.com/example/app/api"}, issue.Evidence["packages"])
+	assert.Equal(t, 0, result.Stats.AICallsMade)
+	assert.Len(t, registry.GetCoverageHistory(monitor.Name()), 2)
+}
+
+func TestCoverageMonitor_SupervisorWritesDescriptionOnly(t *testing.T) {
+	now := time.Now()
+	history := &memoryCoverageHistory{snapshots: []CoverageSnapshot{
+		{Timestamp: now.Add(-time.Hour), Packages: map[string]float64{"pkg": 90}},
+	}}
+
+	monitor, err := NewCoverageMonitor(t.TempDir(), &mockSupervisor{response: "Add tests for the new handlers."}, history)
+	require.NoError(t, err)
+	monitor.Runner = func(ctx context.Context, dir string) (string, error) {
+		return "ok  \tpkg\t0.01s\tcoverage: 83.0% of statements\n", nil
+	}
+
+	result, err := monitor.Check(context.Background(), CodebaseContext{})
+	require.NoError(t, err)
+	require.Len(t, result.IssuesFound, 1)
+	assert.Equal(t, "medium", result.IssuesFound[0].Severity)
+	assert.Contains(t, result.IssuesFound[0].Description, "Test coverage dropped in 1 package(s) over the last 7 days. Add tests for the new handlers.")
+	assert.Equal(t, 1, result.Stats.AICallsMade)
+
+	// Supervisor failures fall back to the computed description
+	monitor.Supervisor = &mockSupervisor{err: errors.New("unavailable")}
+	result, err = monitor.Check(context.Background(), CodebaseContext{})
+	require.NoError(t, err)
+	require.Len(t, result.IssuesFound, 1)
+	assert.Contains(t, result.IssuesFound[0].Description, "- pkg: 90.0% -> 83.0%")
+}
+
+func TestCoverageMonitor_RunnerFailure(t *testing.T) {
+	monitor, err := NewCoverageMonitor(t.TempDir(), nil, nil)
+	require.NoError(t, err)
+
+	// Failing tests with partial coverage still produce a result
+	monitor.Runner = func(ctx context.Context, dir string) (string, error) {
+		return sampleCoverageOutput, errors.New("exit status 1")
+	}
+	result, err := monitor.Check(context.Background(), CodebaseContext{})
+	require.NoError(t, err)
+	assert.Contains(t, result.Context, "3 packages")
+
+	// No coverage at all surfaces the error
+	monitor.Runner = func(ctx context.Context, dir string) (string, error) {
+		return "build failed", errors.New("exit status 2")
+	}
+	_, err = monitor.Check(context.Background(), CodebaseContext{})
+	assert.Error(t, err)
+}
+
+func TestMonitorRegistry_RecordCoveragePrunesAndPersists(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "health_state.json")
+	registry, err := NewMonitorRegistry(statePath)
+	require.NoError(t, err)
+
+	now := time.Now()
+	old := CoverageSnapshot{Timestamp: now.Add(-48 * time.Hour), Packages: map[string]float64{"pkg": 90}}
+	recent := CoverageSnapshot{Timestamp: now, Packages: map[string]float64{"pkg": 85}}
+	require.NoError(t, registry.RecordCoverage("coverage_monitor", old, now.Add(-72*time.Hour)))
+	require.NoError(t, registry.RecordCoverage("coverage_monitor", recent, now.Add(-24*time.Hour)))
+
+	reloaded, err := NewMonitorRegistry(statePath)
+	require.NoError(t, err)
+	history := reloaded.GetCoverageHistory("coverage_monitor")
+	require.Len(t, history, 1)
+	assert.Equal(t, 85.0, history[0].Packages["pkg"])
+}
+
+// memoryCoverageHistory is an in-memory CoverageHistoryStore for tests
+type memoryCoverageHistory struct {
+	snapshots []CoverageSnapshot
+}
+
+func (h *memoryCoverageHistory) GetCoverageHistory(monitorName string) []CoverageSnapshot {
+	return h.snapshots
+}
+
+func (h *memoryCoverageHistory) RecordCoverage(monitorName string, snapshot CoverageSnapshot, keepSince time.Time) error {
+	h.snapshots = append(h.snapshots, snapshot)
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/vc/internal/ai"
+	"github.com/steveyegge/vc/internal/ignore"
 )
 
 const (
@@ -38,6 +39,11 @@ type CruftDetector struct {
 	// ExcludePatterns for files/directories to skip
 	ExcludePatterns []string
 
+	// Ignore holds the project's .vcignore rules (nil means none). The
+	// constructor sets the built-in defaults; callers that load the
+	// .vcignore once (see NewDefaultRegistry) replace it.
+	Ignore *ignore.Matcher
+
 	// MinimumCruftThreshold - only file issue if this many cruft files found
 	MinimumCruftThreshold int
 
@@ -76,6 +82,7 @@ func NewCruftDetector(rootPath string, supervisor AISupervisor) (*CruftDetector,
 			".beads/",      // Issue tracker database
 		},
 		MinimumCruftThreshold: 3, // Only file issue if ≥3 files found
+		Ignore:                ignore.Defaults(),
 		Supervisor:            supervisor,
 	}, nil
 }
@@ -189,7 +196,7 @@ func (d *CruftDetector) scanFiles(ctx context.Context) ([]cruftFile, error) {
 		}
 
 		// Skip excluded patterns
-		if ShouldExcludePath(relPath, info, d.ExcludePatterns) {
+		if ShouldExcludePath(relPath, info, d.ExcludePatterns) || d.Ignore.Match(relPath, info.IsDir()) {
 			if info.IsDir() {
 				return filepath.SkipDir
 			}
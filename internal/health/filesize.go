@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/vc/internal/ai"
+	"github.com/steveyegge/vc/internal/ignore"
 )
 
 // FileSizeMonitor detects oversized files using statistical analysis
@@ -33,6 +34,11 @@ type FileSizeMonitor struct {
 	// ExcludePatterns for files/directories to skip
 	ExcludePatterns []string
 
+	// Ignore holds the project's .vcignore rules (nil means none). The
+	// constructor sets the built-in defaults; callers that load the
+	// .vcignore once (see NewDefaultRegistry) replace it.
+	Ignore *ignore.Matcher
+
 	// AI supervisor for evaluating outliers (interface for easier testing)
 	Supervisor AISupervisor
 }
@@ -63,6 +69,7 @@ func NewFileSizeMonitor(rootPath string, supervisor AISupervisor) (*FileSizeMoni
 			".gen.go", // Other generated code
 			"testdata/",
 		},
+		Ignore:     ignore.Defaults(),
 		Supervisor: supervisor,
 	}, nil
 }
@@ -206,7 +213,7 @@ func (m *FileSizeMonitor) scanFiles(ctx context.Context) ([]fileSize, error) {
 			return nil
 		}
 
-		if ShouldExcludePath(relPath, info, m.ExcludePatterns) {
+		if ShouldExcludePath(relPath, info, m.ExcludePatterns) || m.Ignore.Match(relPath, info.IsDir()) {
 			if info.IsDir() {
 				return filepath.SkipDir
 			}
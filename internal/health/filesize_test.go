@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/steveyegge/vc/internal/ignore"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -905,3 +906,37 @@ func TestFileSizeMonitor_ScanFiles_RelPathHandling(t *testing.T) {
 	// The error handling in scanFiles is defensive programming.
 	// We verify normal behavior here and rely on code inspection for error path.
 }
+
+func TestFileSizeMonitor_ScanFiles_HonorsVCIgnore(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"main.go":               "package main\n",
+		"generated/api.go":      "package generated\n",
+		"generated/keep.go":     "package generated\n",
+		".sandboxes/m-1/big.go": "package sandbox\n",
+		"internal/schema_gen.go": "package internal\n",
+		"internal/handler.go":   "package internal\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	vcignore := "# generated code\n/generated/\n*_gen.go\n"
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, ".vcignore"), []byte(vcignore), 0644))
+
+	monitor, err := NewFileSizeMonitor(tmpDir, nil)
+	require.NoError(t, err)
+	monitor.Ignore, err = ignore.Load(tmpDir)
+	require.NoError(t, err)
+
+	sizes, err := monitor.scanFiles(context.Background())
+	require.NoError(t, err)
+
+	var paths []string
+	for _, s := range sizes {
+		paths = append(paths, filepath.ToSlash(s.Path))
+	}
+	assert.ElementsMatch(t, []string{"main.go", "internal/handler.go"}, paths)
+}
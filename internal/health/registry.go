@@ -17,8 +17,16 @@ type MonitorRegistry struct {
 	monitors map[string]HealthMonitor
 	state    *MonitorState
 	statePath string // Path to state file (e.g., .beads/health_state.json)
+
+	// Overrides from health_monitors.yaml
+	schedules map[string]ScheduleConfig
+	timeouts  map[string]time.Duration
+	disabled  map[string]bool
 }
 
+// DefaultMonitorTimeout bounds a single monitor run unless configured otherwise.
+const DefaultMonitorTimeout = 10 * time.Minute
+
 // MonitorState tracks the execution history of health monitors.
 // This is persisted to disk to survive restarts.
 type MonitorState struct {
@@ -28,11 +36,19 @@ type MonitorState struct {
 // MonitorRunState tracks the execution history for a single monitor.
 type MonitorRunState struct {
 	LastRun          time.Time `json:"last_run"`
+	LastError        string    `json:"last_error,omitempty"` // Set when the last run failed
 	LastIssuesFiled  []string  `json:"last_issues_filed"`
 	LastIssueCount   int       `json:"last_issue_count"`
+	LastFindings     int       `json:"last_findings"` // Issues found, whether or not they were filed
 	RunsSinceEpoch   int       `json:"runs_since_epoch"`
 	IssuesClosedSince int      `json:"issues_closed_since"` // For event-based scheduling
 	CommitsSince     int       `json:"commits_since"`       // For event-based scheduling
+
+	// CoverageHistory holds per-package coverage snapshots (coverage monitor only)
+	CoverageHistory []CoverageSnapshot `json:"coverage_history,omitempty"`
+
+	// MarkerHistory holds per-package TODO/FIXME counts (TODO density monitor only)
+	MarkerHistory []MarkerSnapshot `json:"marker_history,omitempty"`
 }
 
 // NewMonitorRegistry creates a new monitor registry.
@@ -48,6 +64,9 @@ func NewMonitorRegistry(statePath string) (*MonitorRegistry, error) {
 		monitors:  make(map[string]HealthMonitor),
 		state:     &MonitorState{Monitors: make(map[string]*MonitorRunState)},
 		statePath: statePath,
+		schedules: make(map[string]ScheduleConfig),
+		timeouts:  make(map[string]time.Duration),
+		disabled:  make(map[string]bool),
 	}
 
 	// Load existing state if it exists
@@ -113,7 +132,10 @@ func (r *MonitorRegistry) GetScheduledMonitors(now time.Time, issuesClosed int,
 	var scheduled []HealthMonitor
 
 	for name, monitor := range r.monitors {
-		schedule := monitor.Schedule()
+		if r.disabled[name] {
+			continue
+		}
+		schedule := r.scheduleFor(name, monitor)
 		runState := r.state.Monitors[name]
 
 		if r.shouldRun(schedule, runState, now, issuesClosed, commits) {
@@ -131,6 +153,11 @@ func (r *MonitorRegistry) shouldRun(schedule ScheduleConfig, state *MonitorRunSt
 		return false
 	}
 
+	// Only run inside the configured time-of-day window
+	if !schedule.Window.Contains(now) {
+		return false
+	}
+
 	// Handle time-based schedules
 	if schedule.Type == ScheduleTimeBased {
 		if state.LastRun.IsZero() {
@@ -211,8 +238,10 @@ func (r *MonitorRegistry) RecordRun(monitorName string, result *MonitorResult, i
 
 	// Update state
 	state.LastRun = result.CheckedAt
+	state.LastError = ""
 	state.LastIssuesFiled = issuesFiled
 	state.LastIssueCount = len(issuesFiled)
+	state.LastFindings = len(result.IssuesFound)
 	state.RunsSinceEpoch++
 
 	// Reset event counters
@@ -223,6 +252,121 @@ func (r *MonitorRegistry) RecordRun(monitorName string, result *MonitorResult, i
 	return r.saveState()
 }
 
+// RecordFailure updates the state after a monitor run failed, so a broken
+// monitor waits for its next scheduled slot instead of retrying immediately.
+func (r *MonitorRegistry) RecordFailure(monitorName string, at time.Time, runErr error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	state, exists := r.state.Monitors[monitorName]
+	if !exists {
+		state = &MonitorRunState{
+			LastIssuesFiled: []string{},
+		}
+		r.state.Monitors[monitorName] = state
+	}
+
+	state.LastRun = at
+	state.LastError = runErr.Error()
+	state.IssuesClosedSince = 0
+	state.CommitsSince = 0
+
+	return r.saveState()
+}
+
+// ApplyConfig applies per-monitor settings from health_monitors.yaml:
+// monitors with enabled: false are never scheduled, a schedule section
+// replaces the monitor's built-in schedule (a window alone only restricts it),
+// and timeout bounds each run. Monitors missing from the config keep their
+// defaults. Call this after registering monitors.
+func (r *MonitorRegistry) ApplyConfig(config *HealthConfig) error {
+	if config == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for name, monitorConfig := range config.Monitors {
+		if !monitorConfig.Enabled {
+			r.disabled[name] = true
+			continue
+		}
+		delete(r.disabled, name)
+
+		yamlSchedule := monitorConfig.Schedule
+		if yamlSchedule.Type != "" {
+			schedule, err := yamlSchedule.ToScheduleConfig()
+			if err != nil {
+				return fmt.Errorf("monitor %s: %w", name, err)
+			}
+			r.schedules[name] = schedule
+		} else if yamlSchedule.Window != "" {
+			window, err := ParseTimeWindow(yamlSchedule.Window)
+			if err != nil {
+				return fmt.Errorf("monitor %s: invalid window %q: %w", name, yamlSchedule.Window, err)
+			}
+			if monitor, ok := r.monitors[name]; ok {
+				schedule := monitor.Schedule()
+				schedule.Window = window
+				r.schedules[name] = schedule
+			}
+		}
+
+		if monitorConfig.Timeout != "" {
+			timeout, err := parseDuration(monitorConfig.Timeout)
+			if err != nil || timeout <= 0 {
+				return fmt.Errorf("monitor %s: invalid timeout %q", name, monitorConfig.Timeout)
+			}
+			r.timeouts[name] = timeout
+		}
+	}
+
+	return nil
+}
+
+// Schedule returns the effective schedule for a monitor, including overrides
+// from health_monitors.yaml.
+func (r *MonitorRegistry) Schedule(monitorName string) (ScheduleConfig, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	monitor, exists := r.monitors[monitorName]
+	if !exists {
+		return ScheduleConfig{}, false
+	}
+	return r.scheduleFor(monitorName, monitor), true
+}
+
+// scheduleFor returns the configured schedule override or the monitor's own.
+// Callers must hold r.mu.
+func (r *MonitorRegistry) scheduleFor(name string, monitor HealthMonitor) ScheduleConfig {
+	if schedule, ok := r.schedules[name]; ok {
+		return schedule
+	}
+	return monitor.Schedule()
+}
+
+// IsEnabled reports whether a monitor is scheduled at all (enabled: false in
+// health_monitors.yaml turns it off).
+func (r *MonitorRegistry) IsEnabled(monitorName string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return !r.disabled[monitorName]
+}
+
+// Timeout returns how long a single run of the monitor may take.
+func (r *MonitorRegistry) Timeout(monitorName string) time.Duration {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if timeout, ok := r.timeouts[monitorName]; ok {
+		return timeout
+	}
+	return DefaultMonitorTimeout
+}
+
 // IncrementIssuesClosed increments the issues closed counter for event-based scheduling.
 func (r *MonitorRegistry) IncrementIssuesClosed(count int) error {
 	r.mu.Lock()
@@ -256,6 +400,86 @@ func (r *MonitorRegistry) GetMonitorState(monitorName string) (*MonitorRunState,
 	return state, exists
 }
 
+// GetCoverageHistory returns a copy of the coverage snapshots recorded for a monitor,
+// oldest first.
+func (r *MonitorRegistry) GetCoverageHistory(monitorName string) []CoverageSnapshot {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	state, exists := r.state.Monitors[monitorName]
+	if !exists {
+		return nil
+	}
+	history := make([]CoverageSnapshot, len(state.CoverageHistory))
+	copy(history, state.CoverageHistory)
+	return history
+}
+
+// RecordCoverage appends a coverage snapshot for a monitor, drops snapshots
+// taken before keepSince, and persists the state.
+func (r *MonitorRegistry) RecordCoverage(monitorName string, snapshot CoverageSnapshot, keepSince time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	state, exists := r.state.Monitors[monitorName]
+	if !exists {
+		state = &MonitorRunState{
+			LastIssuesFiled: []string{},
+		}
+		r.state.Monitors[monitorName] = state
+	}
+
+	kept := state.CoverageHistory[:0]
+	for _, existing := range state.CoverageHistory {
+		if !existing.Timestamp.Before(keepSince) {
+			kept = append(kept, existing)
+		}
+	}
+	state.CoverageHistory = append(kept, snapshot)
+
+	return r.saveState()
+}
+
+// GetMarkerHistory returns a copy of the marker count snapshots recorded for a
+// monitor, oldest first.
+func (r *MonitorRegistry) GetMarkerHistory(monitorName string) []MarkerSnapshot {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	state, exists := r.state.Monitors[monitorName]
+	if !exists {
+		return nil
+	}
+	history := make([]MarkerSnapshot, len(state.MarkerHistory))
+	copy(history, state.MarkerHistory)
+	return history
+}
+
+// RecordMarkers appends a marker count snapshot for a monitor, drops snapshots
+// taken before keepSince, and persists the state.
+func (r *MonitorRegistry) RecordMarkers(monitorName string, snapshot MarkerSnapshot, keepSince time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	state, exists := r.state.Monitors[monitorName]
+	if !exists {
+		state = &MonitorRunState{
+			LastIssuesFiled: []string{},
+		}
+		r.state.Monitors[monitorName] = state
+	}
+
+	kept := state.MarkerHistory[:0]
+	for _, existing := range state.MarkerHistory {
+		if !existing.Timestamp.Before(keepSince) {
+			kept = append(kept, existing)
+		}
+	}
+	state.MarkerHistory = append(kept, snapshot)
+
+	return r.saveState()
+}
+
 // loadState loads monitor state from disk.
 func (r *MonitorRegistry) loadState() error {
 	data, err := os.ReadFile(r.statePath)
@@ -2,6 +2,7 @@ package health
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -451,3 +452,107 @@ func TestRunMonitor(t *testing.T) {
 		t.Error("Expected error when running non-existent monitor")
 	}
 }
+
+func TestApplyConfig(t *testing.T) {
+	registry, err := NewMonitorRegistry(filepath.Join(t.TempDir(), "health_state.json"))
+	if err != nil {
+		t.Fatalf("Failed to create registry: %v", err)
+	}
+
+	daily := ScheduleConfig{Type: ScheduleTimeBased, Interval: 24 * time.Hour}
+	for _, name := range []string{"overridden", "windowed", "disabled", "untouched"} {
+		if err := registry.Register(&mockMonitor{name: name, schedule: daily}); err != nil {
+			t.Fatalf("Failed to register monitor: %v", err)
+		}
+	}
+
+	err = registry.ApplyConfig(&HealthConfig{Monitors: map[string]MonitorConfig{
+		"overridden": {Enabled: true, Schedule: ScheduleYAMLConfig{Type: "time_based", Interval: "1h"}, Timeout: "90s"},
+		"windowed":   {Enabled: true, Schedule: ScheduleYAMLConfig{Window: "01:00-05:00"}},
+		"disabled":   {Enabled: false},
+	}})
+	if err != nil {
+		t.Fatalf("ApplyConfig failed: %v", err)
+	}
+
+	if schedule, _ := registry.Schedule("overridden"); schedule.Interval != time.Hour {
+		t.Errorf("Expected overridden interval 1h, got %v", schedule.Interval)
+	}
+	if schedule, _ := registry.Schedule("windowed"); schedule.Interval != 24*time.Hour || schedule.Window == nil {
+		t.Errorf("Expected built-in interval restricted to a window, got %+v", schedule)
+	}
+	if got := registry.Timeout("overridden"); got != 90*time.Second {
+		t.Errorf("Expected timeout 90s, got %v", got)
+	}
+	if got := registry.Timeout("untouched"); got != DefaultMonitorTimeout {
+		t.Errorf("Expected default timeout, got %v", got)
+	}
+
+	// At noon the windowed monitor is outside its window and the disabled one never runs
+	noon := time.Date(2025, 1, 1, 12, 0, 0, 0, time.Local)
+	names := make(map[string]bool)
+	for _, monitor := range registry.GetScheduledMonitors(noon, 0, 0) {
+		names[monitor.Name()] = true
+	}
+	if !names["overridden"] || !names["untouched"] || names["windowed"] || names["disabled"] {
+		t.Errorf("Unexpected scheduled monitors at noon: %v", names)
+	}
+
+	at2am := time.Date(2025, 1, 1, 2, 0, 0, 0, time.Local)
+	found := false
+	for _, monitor := range registry.GetScheduledMonitors(at2am, 0, 0) {
+		found = found || monitor.Name() == "windowed"
+	}
+	if !found {
+		t.Error("Expected windowed monitor to be scheduled inside its window")
+	}
+
+	err = registry.ApplyConfig(&HealthConfig{Monitors: map[string]MonitorConfig{
+		"overridden": {Enabled: true, Timeout: "soon"},
+	}})
+	if err == nil {
+		t.Error("Expected error for invalid timeout")
+	}
+}
+
+func TestRecordFailure(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "health_state.json")
+	registry, err := NewMonitorRegistry(statePath)
+	if err != nil {
+		t.Fatalf("Failed to create registry: %v", err)
+	}
+
+	monitor := &mockMonitor{
+		name:     "flaky",
+		schedule: ScheduleConfig{Type: ScheduleTimeBased, Interval: time.Hour},
+	}
+	if err := registry.Register(monitor); err != nil {
+		t.Fatalf("Failed to register monitor: %v", err)
+	}
+
+	now := time.Now()
+	if err := registry.RecordFailure("flaky", now, fmt.Errorf("boom")); err != nil {
+		t.Fatalf("RecordFailure failed: %v", err)
+	}
+
+	// A failed run waits for the next interval instead of retrying every tick
+	if scheduled := registry.GetScheduledMonitors(now.Add(time.Minute), 0, 0); len(scheduled) != 0 {
+		t.Errorf("Expected failed monitor to wait for its interval, got %d scheduled", len(scheduled))
+	}
+
+	reloaded, err := NewMonitorRegistry(statePath)
+	if err != nil {
+		t.Fatalf("Failed to reload registry: %v", err)
+	}
+	state, _ := reloaded.GetMonitorState("flaky")
+	if state.LastError != "boom" {
+		t.Errorf("Expected persisted last error, got %q", state.LastError)
+	}
+
+	if err := registry.RecordRun("flaky", &MonitorResult{CheckedAt: now}, nil); err != nil {
+		t.Fatalf("RecordRun failed: %v", err)
+	}
+	if state, _ := registry.GetMonitorState("flaky"); state.LastError != "" {
+		t.Errorf("Expected successful run to clear last error, got %q", state.LastError)
+	}
+}
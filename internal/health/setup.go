@@ -0,0 +1,164 @@
+package health
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/steveyegge/vc/internal/ignore"
+)
+
+// Default locations of the health files, relative to the project root.
+const (
+	DefaultConfigPath = ".beads/health_monitors.yaml"
+	DefaultStatePath  = ".beads/health_state.json"
+)
+
+// RegistryOptions configures NewDefaultRegistry.
+type RegistryOptions struct {
+	// ProjectRoot is the codebase root the monitors scan
+	ProjectRoot string
+
+	// StatePath is the health_state.json location (default: DefaultStatePath).
+	// Relative paths are resolved against ProjectRoot.
+	StatePath string
+
+	// ConfigPath is the health_monitors.yaml location (default: DefaultConfigPath).
+	// Relative paths are resolved against ProjectRoot. A missing file is fine.
+	ConfigPath string
+
+	// Supervisor is used by AI-backed monitors. It may be nil for read-only
+	// uses such as inspecting state; AI-backed checks then fail when run.
+	Supervisor AISupervisor
+
+	// Ignore holds the project's .vcignore rules, shared by every monitor
+	// that scans files. If nil, the .vcignore under ProjectRoot is loaded.
+	Ignore *ignore.Matcher
+
+	// WithoutAI leaves out the monitors that can't run without a Supervisor
+	// (file size and cruft), so the rest can run when no AI is configured
+	WithoutAI bool
+}
+
+// NewDefaultRegistry creates a registry with the built-in monitors registered
+// and health_monitors.yaml applied. This is shared by the executor and the
+// `vc health` command so both see the same monitors, schedules and state.
+//
+// Monitors that fail to initialize and invalid config sections are reported
+// in warnings rather than failing the whole registry.
+func NewDefaultRegistry(opts RegistryOptions) (registry *MonitorRegistry, warnings []error, err error) {
+	statePath := resolveHealthPath(opts.ProjectRoot, opts.StatePath, DefaultStatePath)
+	configPath := resolveHealthPath(opts.ProjectRoot, opts.ConfigPath, DefaultConfigPath)
+
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			warnings = append(warnings, fmt.Errorf("loading health config: %w (using default monitor settings)", err))
+		}
+		config = nil
+	}
+
+	registry, err = NewMonitorRegistry(statePath)
+	if err != nil {
+		return nil, warnings, err
+	}
+
+	// The .vcignore is parsed once and shared by the monitors that scan files
+	matcher := opts.Ignore
+	if matcher == nil {
+		if matcher, err = ignore.Load(opts.ProjectRoot); err != nil {
+			warnings = append(warnings, fmt.Errorf("%w (using the built-in ignore defaults)", err))
+			matcher = ignore.Defaults()
+		}
+	}
+
+	register := func(label string, monitor HealthMonitor, createErr error) {
+		if createErr != nil {
+			warnings = append(warnings, fmt.Errorf("failed to create %s: %w", label, createErr))
+			return
+		}
+		if err := registry.Register(monitor); err != nil {
+			warnings = append(warnings, fmt.Errorf("failed to register %s: %w", label, err))
+		}
+	}
+
+	if !opts.WithoutAI {
+		fileSizeMonitor, err := NewFileSizeMonitor(opts.ProjectRoot, opts.Supervisor)
+		if err == nil {
+			fileSizeMonitor.Ignore = matcher
+		}
+		register("file size monitor", fileSizeMonitor, err)
+
+		cruftDetector, err := NewCruftDetector(opts.ProjectRoot, opts.Supervisor)
+		if err == nil {
+			cruftDetector.Ignore = matcher
+		}
+		register("cruft detector", cruftDetector, err)
+	}
+
+	// TODO density takes marker severities and exclusions from its config section
+	todoMonitor, err := NewTodoDensityMonitor(opts.ProjectRoot, registry)
+	if err == nil {
+		todoMonitor.Ignore = matcher
+	}
+	if err == nil && config != nil {
+		if monitorConfig, ok := config.Monitors[todoMonitor.Name()]; ok {
+			if applyErr := todoMonitor.ApplyConfig(monitorConfig); applyErr != nil {
+				warnings = append(warnings, fmt.Errorf("%w (using TODO density defaults)", applyErr))
+			}
+		}
+	}
+	register("TODO density monitor", todoMonitor, err)
+
+	// Coverage history lives in the registry state file
+	coverageMonitor, err := NewCoverageMonitor(opts.ProjectRoot, opts.Supervisor, registry)
+	register("coverage monitor", coverageMonitor, err)
+
+	// Custom monitors backed by external commands
+	if config != nil {
+		for _, name := range sortedConfigNames(config) {
+			monitorConfig := config.Monitors[name]
+			if monitorConfig.Type != MonitorTypeCommand {
+				continue
+			}
+			commandMonitor, err := NewCommandMonitor(name, opts.ProjectRoot, monitorConfig)
+			register("custom monitor "+name, commandMonitor, err)
+		}
+	}
+
+	if err := registry.ApplyConfig(config); err != nil {
+		warnings = append(warnings, fmt.Errorf("invalid health config: %w (using default schedules)", err))
+	}
+
+	return registry, warnings, nil
+}
+
+// SortedMonitorNames returns the registered monitor names in alphabetical order.
+func (r *MonitorRegistry) SortedMonitorNames() []string {
+	names := r.ListMonitors()
+	sort.Strings(names)
+	return names
+}
+
+// sortedConfigNames returns the monitor names in a config in alphabetical order.
+func sortedConfigNames(config *HealthConfig) []string {
+	names := make([]string, 0, len(config.Monitors))
+	for name := range config.Monitors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// resolveHealthPath applies the default and resolves relative paths against root.
+func resolveHealthPath(root, path, defaultPath string) string {
+	if path == "" {
+		path = defaultPath
+	}
+	if filepath.IsAbs(path) || root == "" {
+		return path
+	}
+	return filepath.Join(root, path)
+}
@@ -0,0 +1,143 @@
+package health
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/ignore"
+)
+
+func TestNewDefaultRegistry(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, ".beads"), 0755); err != nil {
+		t.Fatalf("Failed to create .beads: %v", err)
+	}
+	config := `monitors:
+  coverage_monitor:
+    enabled: false
+  todo_density_monitor:
+    enabled: true
+    schedule:
+      window: "01:00-05:00"
+    timeout: 2m
+    markers:
+      FIXME: high
+  schema_drift:
+    enabled: true
+    type: command
+    command: ["./scripts/schema-drift", "--json"]
+    timeout: 30s
+  broken_custom:
+    enabled: true
+    type: command
+`
+	if err := os.WriteFile(filepath.Join(root, DefaultConfigPath), []byte(config), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	registry, warnings, err := NewDefaultRegistry(RegistryOptions{ProjectRoot: root})
+	if err != nil {
+		t.Fatalf("NewDefaultRegistry failed: %v", err)
+	}
+	// The custom monitor without a command is skipped with a warning
+	if len(warnings) != 1 || !strings.Contains(warnings[0].Error(), "broken_custom") {
+		t.Errorf("Expected one warning for broken_custom, got %v", warnings)
+	}
+
+	want := []string{"coverage_monitor", "cruft_detector", "file_size_monitor", "schema_drift", "todo_density_monitor"}
+	got := registry.SortedMonitorNames()
+	if len(got) != len(want) {
+		t.Fatalf("Registered monitors = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Registered monitors = %v, want %v", got, want)
+			break
+		}
+	}
+
+	if registry.IsEnabled("coverage_monitor") {
+		t.Error("Expected coverage monitor to be disabled by config")
+	}
+	if schedule, _ := registry.Schedule("todo_density_monitor"); schedule.Window == nil {
+		t.Error("Expected TODO density window from config")
+	}
+	if timeout := registry.Timeout("todo_density_monitor"); timeout != 2*time.Minute {
+		t.Errorf("Timeout = %v, want 2m", timeout)
+	}
+
+	monitor, _ := registry.GetMonitor("todo_density_monitor")
+	if markers := monitor.(*TodoDensityMonitor).MarkerSeverities; len(markers) != 1 || markers["FIXME"] != "high" {
+		t.Errorf("Expected markers from config, got %v", markers)
+	}
+
+	if timeout := registry.Timeout("schema_drift"); timeout != 30*time.Second {
+		t.Errorf("schema_drift timeout = %v, want 30s", timeout)
+	}
+
+	// State is kept under the project root
+	if registry.statePath != filepath.Join(root, DefaultStatePath) {
+		t.Errorf("statePath = %s, want under project root", registry.statePath)
+	}
+}
+
+func TestNewDefaultRegistryWithoutAI(t *testing.T) {
+	registry, warnings, err := NewDefaultRegistry(RegistryOptions{ProjectRoot: t.TempDir(), WithoutAI: true})
+	if err != nil {
+		t.Fatalf("NewDefaultRegistry failed: %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("Unexpected warnings: %v", warnings)
+	}
+
+	// Only the monitors that need no AI supervisor are registered
+	want := []string{"coverage_monitor", "todo_density_monitor"}
+	got := registry.SortedMonitorNames()
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Registered monitors = %v, want %v", got, want)
+	}
+}
+
+func TestNewDefaultRegistrySharesIgnore(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, ignore.FileName), []byte("fixtures/\n"), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", ignore.FileName, err)
+	}
+
+	registry, warnings, err := NewDefaultRegistry(RegistryOptions{ProjectRoot: root})
+	if err != nil {
+		t.Fatalf("NewDefaultRegistry failed: %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("Unexpected warnings: %v", warnings)
+	}
+
+	// Every scanning monitor gets the one Matcher parsed from the .vcignore
+	fileSize, _ := registry.GetMonitor("file_size_monitor")
+	cruft, _ := registry.GetMonitor("cruft_detector")
+	todo, _ := registry.GetMonitor("todo_density_monitor")
+	matcher := fileSize.(*FileSizeMonitor).Ignore
+	if !matcher.Match("fixtures/big.bin", false) {
+		t.Error("Expected the .vcignore rules to be loaded")
+	}
+	if cruft.(*CruftDetector).Ignore != matcher || todo.(*TodoDensityMonitor).Ignore != matcher {
+		t.Error("Expected the monitors to share one Matcher")
+	}
+
+	// A Matcher passed in is used as is
+	given, err := ignore.NewWithDefaults([]string{"other/"})
+	if err != nil {
+		t.Fatalf("NewWithDefaults failed: %v", err)
+	}
+	registry, _, err = NewDefaultRegistry(RegistryOptions{ProjectRoot: root, Ignore: given})
+	if err != nil {
+		t.Fatalf("NewDefaultRegistry failed: %v", err)
+	}
+	todo, _ = registry.GetMonitor("todo_density_monitor")
+	if todo.(*TodoDensityMonitor).Ignore != given {
+		t.Error("Expected the given Matcher to be used")
+	}
+}
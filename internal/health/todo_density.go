@@ -0,0 +1,525 @@
+package health
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/ignore"
+)
+
+// MarkerSnapshot records per-package marker counts at a point in time.
+type MarkerSnapshot struct {
+	Timestamp time.Time      `json:"timestamp"`
+	Packages  map[string]int `json:"packages"` // Package directory -> marker count
+}
+
+// MarkerHistoryStore persists marker count snapshots between runs.
+// MonitorRegistry implements this via health_state.json.
+type MarkerHistoryStore interface {
+	GetMarkerHistory(monitorName string) []MarkerSnapshot
+	RecordMarkers(monitorName string, snapshot MarkerSnapshot, keepSince time.Time) error
+}
+
+// DefaultMarkerSeverities returns the markers scanned by default and the
+// severity of findings they cause.
+func DefaultMarkerSeverities() map[string]string {
+	return map[string]string{
+		"TODO":  "low",
+		"FIXME": "medium",
+		"HACK":  "medium",
+	}
+}
+
+// severityRank orders finding severities so the highest one present wins.
+var severityRank = map[string]int{
+	"low":    1,
+	"medium": 2,
+	"high":   3,
+}
+
+// generatedCodePattern matches the standard Go generated-code header.
+var generatedCodePattern = regexp.MustCompile(`^// Code generated .* DO NOT EDIT\.$`)
+
+// TodoDensityMonitor tracks TODO/FIXME/HACK markers per package and reports
+// packages where they are dense or growing quickly.
+//
+// ZFC Compliance: Marker counts, densities and growth are facts; the monitor
+// files findings with the evidence and leaves prioritization to whoever
+// picks up the issue. No AI call is needed.
+type TodoDensityMonitor struct {
+	// RootPath is the codebase root directory
+	RootPath string
+
+	// MarkerSeverities maps each scanned marker to the severity of findings it causes
+	MarkerSeverities map[string]string
+
+	// FileExtensions to scan (default: [".go"])
+	FileExtensions []string
+
+	// ExcludePatterns for files/directories to skip (vendored and generated code)
+	ExcludePatterns []string
+
+	// Ignore holds the project's .vcignore rules (nil means none). The
+	// constructor sets the built-in defaults; callers that load the
+	// .vcignore once (see NewDefaultRegistry) replace it.
+	Ignore *ignore.Matcher
+
+	// DensityThreshold is the markers per 1000 lines above which a package is
+	// reported. Default: 10
+	DensityThreshold float64
+
+	// MinPackageLines keeps tiny packages from being reported on density alone.
+	// Default: 200
+	MinPackageLines int
+
+	// GrowthThreshold is how many markers a package may gain within Window
+	// before it is reported. Default: 10
+	GrowthThreshold int
+
+	// Window is how far back the growth baseline may be. Default: 7 days
+	Window time.Duration
+
+	// TopFiles is how many offending files to list per finding. Default: 5
+	TopFiles int
+
+	// History persists snapshots between runs (nil disables growth detection)
+	History MarkerHistoryStore
+}
+
+// NewTodoDensityMonitor creates a TODO density monitor with sensible defaults.
+func NewTodoDensityMonitor(rootPath string, history MarkerHistoryStore) (*TodoDensityMonitor, error) {
+	absPath, err := filepath.Abs(rootPath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid root path %q: %w", rootPath, err)
+	}
+
+	return &TodoDensityMonitor{
+		RootPath:         absPath,
+		MarkerSeverities: DefaultMarkerSeverities(),
+		FileExtensions:   []string{".go"},
+		ExcludePatterns: []string{
+			"vendor/",
+			".git/",
+			"testdata/",
+			"node_modules/",
+			".pb.go",  // Generated protobuf
+			".gen.go", // Other generated code
+		},
+		Ignore:           ignore.Defaults(),
+		DensityThreshold: 10,
+		MinPackageLines:  200,
+		GrowthThreshold:  10,
+		Window:           7 * 24 * time.Hour,
+		TopFiles:         5,
+		History:          history,
+	}, nil
+}
+
+// ApplyConfig applies marker severities and extra exclude patterns from
+// health_monitors.yaml.
+func (m *TodoDensityMonitor) ApplyConfig(cfg MonitorConfig) error {
+	if len(cfg.Markers) > 0 {
+		markers := make(map[string]string, len(cfg.Markers))
+		for marker, severity := range cfg.Markers {
+			if strings.TrimSpace(marker) == "" {
+				return fmt.Errorf("empty marker in todo_density_monitor config")
+			}
+			if _, ok := severityRank[severity]; !ok {
+				return fmt.Errorf("invalid severity %q for marker %s (must be low, medium, or high)", severity, marker)
+			}
+			markers[marker] = severity
+		}
+		m.MarkerSeverities = markers
+	}
+	m.ExcludePatterns = append(m.ExcludePatterns, cfg.ExcludePatterns...)
+	return nil
+}
+
+// Name implements HealthMonitor.
+func (m *TodoDensityMonitor) Name() string {
+	return "todo_density_monitor"
+}
+
+// Philosophy implements HealthMonitor.
+func (m *TodoDensityMonitor) Philosophy() string {
+	return "Deferred work should be tracked where it can be prioritized. " +
+		"Markers that pile up in code are promises nobody is scheduled to keep."
+}
+
+// Schedule implements HealthMonitor.
+func (m *TodoDensityMonitor) Schedule() ScheduleConfig {
+	return ScheduleConfig{
+		Type:     ScheduleTimeBased,
+		Interval: 24 * time.Hour, // Daily
+	}
+}
+
+// Cost implements HealthMonitor.
+func (m *TodoDensityMonitor) Cost() CostEstimate {
+	return CostEstimate{
+		EstimatedDuration: 2 * time.Second,
+		AICallsEstimated:  0,
+		RequiresFullScan:  true,
+		Category:          CostCheap,
+	}
+}
+
+// markerHit is a single marker occurrence.
+type markerHit struct {
+	Line   int
+	Marker string
+}
+
+// markerFile holds the markers found in one file.
+type markerFile struct {
+	Path  string
+	Lines int
+	Hits  []markerHit
+}
+
+// markerPackage aggregates markers for one package directory.
+type markerPackage struct {
+	Dir      string
+	Lines    int
+	Markers  int
+	ByMarker map[string]int
+	Files    []markerFile // Only files with at least one marker
+}
+
+// Density returns markers per 1000 lines.
+func (p *markerPackage) Density() float64 {
+	if p.Lines == 0 {
+		return 0
+	}
+	return float64(p.Markers) * 1000 / float64(p.Lines)
+}
+
+// Check implements HealthMonitor.
+// The codebase parameter is unused; this monitor scans files itself.
+func (m *TodoDensityMonitor) Check(ctx context.Context, codebase CodebaseContext) (*MonitorResult, error) {
+	startTime := time.Now()
+
+	if len(m.MarkerSeverities) == 0 {
+		return nil, fmt.Errorf("no markers configured for TODO density monitoring")
+	}
+	markerPattern := m.markerPattern()
+
+	files, err := m.listFiles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing files: %w", err)
+	}
+
+	packages := make(map[string]*markerPackage)
+	scanned := 0
+	for _, relPath := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		file, generated, err := scanMarkers(filepath.Join(m.RootPath, relPath), markerPattern)
+		if err != nil || generated {
+			continue // Unreadable or generated files don't count
+		}
+		scanned++
+
+		dir := filepath.ToSlash(filepath.Dir(relPath))
+		if dir == "." {
+			dir = "(root)" // Keeps the issue title free of a bare "."
+		}
+		pkg, ok := packages[dir]
+		if !ok {
+			pkg = &markerPackage{Dir: dir, ByMarker: make(map[string]int)}
+			packages[dir] = pkg
+		}
+		pkg.Lines += file.Lines
+		if len(file.Hits) == 0 {
+			continue
+		}
+		file.Path = filepath.ToSlash(relPath)
+		pkg.Files = append(pkg.Files, file)
+		pkg.Markers += len(file.Hits)
+		for _, hit := range file.Hits {
+			pkg.ByMarker[hit.Marker]++
+		}
+	}
+
+	counts := make(map[string]int, len(packages))
+	for dir, pkg := range packages {
+		if pkg.Markers > 0 {
+			counts[dir] = pkg.Markers
+		}
+	}
+
+	var history []MarkerSnapshot
+	if m.History != nil {
+		history = m.History.GetMarkerHistory(m.Name())
+	}
+	windowStart := startTime.Add(-m.Window)
+	baselines := markerBaselines(history, windowStart)
+
+	if m.History != nil {
+		snapshot := MarkerSnapshot{Timestamp: startTime, Packages: counts}
+		if err := m.History.RecordMarkers(m.Name(), snapshot, windowStart); err != nil {
+			return nil, fmt.Errorf("recording marker history: %w", err)
+		}
+	}
+
+	dirs := make([]string, 0, len(counts))
+	for dir := range counts {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	var issues []DiscoveredIssue
+	for _, dir := range dirs {
+		pkg := packages[dir]
+		dense := pkg.Lines >= m.MinPackageLines && pkg.Density() > m.DensityThreshold
+		baseline, hasBaseline := baselines[dir]
+		growing := hasBaseline && pkg.Markers-baseline >= m.GrowthThreshold
+		if !dense && !growing {
+			continue
+		}
+		issues = append(issues, m.buildIssue(pkg, dense, growing, baseline))
+	}
+
+	totalMarkers := 0
+	for _, count := range counts {
+		totalMarkers += count
+	}
+
+	return &MonitorResult{
+		IssuesFound: issues,
+		Context: fmt.Sprintf("Scanned %d files in %d packages; found %d markers (%s)",
+			scanned, len(packages), totalMarkers, strings.Join(m.markerNames(), ", ")),
+		Reasoning: fmt.Sprintf("Packages above %.1f markers per 1000 lines or gaining %d+ markers within %v are reported",
+			m.DensityThreshold, m.GrowthThreshold, m.Window),
+		CheckedAt: startTime,
+		Stats: CheckStats{
+			FilesScanned: scanned,
+			IssuesFound:  len(issues),
+			Duration:     time.Since(startTime),
+		},
+	}, nil
+}
+
+// markerNames returns the configured markers in stable order.
+func (m *TodoDensityMonitor) markerNames() []string {
+	names := make([]string, 0, len(m.MarkerSeverities))
+	for marker := range m.MarkerSeverities {
+		names = append(names, marker)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// markerPattern matches any configured marker as a whole word.
+func (m *TodoDensityMonitor) markerPattern() *regexp.Regexp {
+	quoted := make([]string, 0, len(m.MarkerSeverities))
+	for _, marker := range m.markerNames() {
+		quoted = append(quoted, regexp.QuoteMeta(marker))
+	}
+	return regexp.MustCompile(`\b(` + strings.Join(quoted, "|") + `)\b`)
+}
+
+// listFiles returns the tracked source files to scan, relative to RootPath.
+// Files tracked by git are preferred; outside a git checkout the tree is walked.
+func (m *TodoDensityMonitor) listFiles(ctx context.Context) ([]string, error) {
+	candidates, err := gitTrackedFiles(ctx, m.RootPath)
+	if err != nil {
+		candidates, err = walkFiles(ctx, m.RootPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var files []string
+	for _, relPath := range candidates {
+		if !m.hasExtension(relPath) {
+			continue
+		}
+		info, err := os.Lstat(filepath.Join(m.RootPath, relPath))
+		if err != nil || !info.Mode().IsRegular() {
+			continue // Deleted in the working tree, or a symlink
+		}
+		slashPath := filepath.ToSlash(relPath)
+		if ShouldExcludePath(slashPath, info, m.ExcludePatterns) || m.Ignore.Match(relPath, false) {
+			continue
+		}
+		files = append(files, relPath)
+	}
+	return files, nil
+}
+
+// hasExtension reports whether path has one of the scanned extensions.
+func (m *TodoDensityMonitor) hasExtension(path string) bool {
+	for _, ext := range m.FileExtensions {
+		if strings.HasSuffix(path, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// gitTrackedFiles lists files tracked by git under root.
+func gitTrackedFiles(ctx context.Context, root string) ([]string, error) {
+	cmd := exec.CommandContext(ctx, "git", "ls-files", "-z")
+	cmd.Dir = root
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, name := range bytes.Split(output, []byte{0}) {
+		if len(name) > 0 {
+			files = append(files, filepath.FromSlash(string(name)))
+		}
+	}
+	return files, nil
+}
+
+// walkFiles lists all regular files under root, skipping .git.
+func walkFiles(ctx context.Context, root string) ([]string, error) {
+	var files []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		relPath, err := filepath.Rel(root, path)
+		if err != nil {
+			return nil
+		}
+		files = append(files, relPath)
+		return nil
+	})
+	return files, err
+}
+
+// scanMarkers counts lines and marker occurrences in a file. generated is
+// true if the file carries a "Code generated ... DO NOT EDIT." header.
+func scanMarkers(path string, pattern *regexp.Regexp) (file markerFile, generated bool, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return markerFile{}, false, err
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		file.Lines++
+		line := scanner.Text()
+		if generatedCodePattern.MatchString(line) {
+			return markerFile{}, true, nil
+		}
+		for _, marker := range pattern.FindAllString(line, -1) {
+			file.Hits = append(file.Hits, markerHit{Line: file.Lines, Marker: marker})
+		}
+	}
+	return file, false, scanner.Err()
+}
+
+// markerBaselines returns, for each package, the count from the oldest
+// snapshot within the window that includes it.
+func markerBaselines(history []MarkerSnapshot, windowStart time.Time) map[string]int {
+	baselines := make(map[string]int)
+	for _, snapshot := range history {
+		if snapshot.Timestamp.Before(windowStart) {
+			continue
+		}
+		for dir, count := range snapshot.Packages {
+			if _, seen := baselines[dir]; !seen {
+				baselines[dir] = count
+			}
+		}
+	}
+	return baselines
+}
+
+// buildIssue creates a finding for a package. The first sentence names the
+// package and the kind of problem only, so repeated findings share a title.
+func (m *TodoDensityMonitor) buildIssue(pkg *markerPackage, dense, growing bool, baseline int) DiscoveredIssue {
+	var sb strings.Builder
+	if dense {
+		sb.WriteString(fmt.Sprintf("Package %s has a high density of unresolved code markers. ", pkg.Dir))
+	} else {
+		sb.WriteString(fmt.Sprintf("Package %s is accumulating unresolved code markers. ", pkg.Dir))
+	}
+	sb.WriteString(fmt.Sprintf("It has %d markers across %d lines (%.1f per 1000 lines, threshold %.1f)",
+		pkg.Markers, pkg.Lines, pkg.Density(), m.DensityThreshold))
+	if growing {
+		sb.WriteString(fmt.Sprintf(", up from %d within the last %s", baseline, formatWindow(m.Window)))
+	}
+	sb.WriteString(".\n\nTop files:\n")
+
+	files := make([]markerFile, len(pkg.Files))
+	copy(files, pkg.Files)
+	sort.Slice(files, func(i, j int) bool {
+		if len(files[i].Hits) != len(files[j].Hits) {
+			return len(files[i].Hits) > len(files[j].Hits)
+		}
+		return files[i].Path < files[j].Path
+	})
+	if len(files) > m.TopFiles {
+		files = files[:m.TopFiles]
+	}
+
+	topFiles := make([]string, 0, len(files))
+	for _, file := range files {
+		lines := make([]string, 0, len(file.Hits))
+		for i, hit := range file.Hits {
+			if i == 10 {
+				lines = append(lines, "...")
+				break
+			}
+			lines = append(lines, fmt.Sprintf("%d", hit.Line))
+		}
+		ref := fmt.Sprintf("%s:%s (%d)", file.Path, strings.Join(lines, ","), len(file.Hits))
+		topFiles = append(topFiles, ref)
+		sb.WriteString("- " + ref + "\n")
+	}
+
+	severity := "low"
+	for marker := range pkg.ByMarker {
+		if s := m.MarkerSeverities[marker]; severityRank[s] > severityRank[severity] {
+			severity = s
+		}
+	}
+
+	evidence := map[string]interface{}{
+		"package":          pkg.Dir,
+		"markers":          pkg.Markers,
+		"lines":            pkg.Lines,
+		"density_per_kloc": pkg.Density(),
+		"by_marker":        pkg.ByMarker,
+		"top_files":        topFiles,
+	}
+	if growing {
+		evidence["baseline_markers"] = baseline
+	}
+
+	return DiscoveredIssue{
+		FilePath:    pkg.Dir,
+		Category:    "todo_density",
+		Severity:    severity,
+		Description: sb.String(),
+		Evidence:    evidence,
+	}
+}
@@ -0,0 +1,124 @@
+package health
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/ignore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeMarkerFile writes a Go file with the given number of plain lines and
+// one marker comment per entry in markers.
+func writeMarkerFile(t *testing.T, root, relPath string, plainLines int, markers ...string) {
+	t.Helper()
+	var sb strings.Builder
+	sb.WriteString("package p\n")
+	for _, marker := range markers {
+		sb.WriteString("// " + marker + ": revisit this\n")
+	}
+	for i := 0; i < plainLines; i++ {
+		sb.WriteString("var _ = 1\n")
+	}
+	path := filepath.Join(root, relPath)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(sb.String()), 0644))
+}
+
+func TestTodoDensityMonitor_DenseAndExcluded(t *testing.T) {
+	root := t.TempDir()
+	writeMarkerFile(t, root, "clean/clean.go", 300)
+	writeMarkerFile(t, root, "messy/a.go", 150, "TODO", "TODO", "FIXME")
+	writeMarkerFile(t, root, "messy/b.go", 100, "HACK")
+	writeMarkerFile(t, root, "vendor/dep/dep.go", 10, "FIXME", "FIXME", "FIXME")
+	writeMarkerFile(t, root, "skipped/skipped.go", 10, "FIXME", "FIXME", "FIXME")
+
+	generated := "// Code generated by stringer. DO NOT EDIT.\n\npackage gen\n// TODO: nope\n"
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "gen"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "gen", "gen.go"), []byte(generated), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".vcignore"), []byte("skipped/\n"), 0644))
+
+	monitor, err := NewTodoDensityMonitor(root, nil)
+	require.NoError(t, err)
+	monitor.Ignore, err = ignore.Load(root)
+	require.NoError(t, err)
+
+	result, err := monitor.Check(context.Background(), CodebaseContext{})
+	require.NoError(t, err)
+
+	// messy: 4 markers in ~257 lines is ~15.6 per 1000 lines
+	require.Len(t, result.IssuesFound, 1)
+	issue := result.IssuesFound[0]
+	assert.Equal(t, "todo_density", issue.Category)
+	assert.Equal(t, "medium", issue.Severity) // FIXME/HACK outrank TODO
+	assert.True(t, strings.HasPrefix(issue.Description, "Package messy has a high density of unresolved code markers."))
+	assert.Contains(t, issue.Description, "- messy/a.go:2,3,4 (3)")
+	assert.Contains(t, issue.Description, "- messy/b.go:2 (1)")
+	assert.Equal(t, 4, issue.Evidence["markers"])
+	assert.Equal(t, 3, result.Stats.FilesScanned) // vendored, ignored and generated files skipped
+}
+
+func TestTodoDensityMonitor_Growth(t *testing.T) {
+	root := t.TempDir()
+	registry, err := NewMonitorRegistry(filepath.Join(t.TempDir(), "health_state.json"))
+	require.NoError(t, err)
+
+	writeMarkerFile(t, root, "pkg/big.go", 5000, "TODO")
+	monitor, err := NewTodoDensityMonitor(root, registry)
+	require.NoError(t, err)
+	monitor.GrowthThreshold = 3
+
+	result, err := monitor.Check(context.Background(), CodebaseContext{})
+	require.NoError(t, err)
+	assert.Empty(t, result.IssuesFound)
+
+	// Density stays low but the package gained 3 markers
+	writeMarkerFile(t, root, "pkg/big.go", 5000, "TODO", "TODO", "TODO", "TODO")
+	result, err = monitor.Check(context.Background(), CodebaseContext{})
+	require.NoError(t, err)
+	require.Len(t, result.IssuesFound, 1)
+	assert.Contains(t, result.IssuesFound[0].Description, "Package pkg is accumulating unresolved code markers.")
+	assert.Contains(t, result.IssuesFound[0].Description, "up from 1 within the last 7 days")
+	assert.Equal(t, "low", result.IssuesFound[0].Severity)
+	assert.Len(t, registry.GetMarkerHistory(monitor.Name()), 2)
+}
+
+func TestTodoDensityMonitor_ApplyConfig(t *testing.T) {
+	root := t.TempDir()
+	writeMarkerFile(t, root, "pkg/a.go", 50, "XXX", "TODO")
+	writeMarkerFile(t, root, "generated/z.go", 10, "XXX", "XXX")
+
+	monitor, err := NewTodoDensityMonitor(root, nil)
+	require.NoError(t, err)
+	require.NoError(t, monitor.ApplyConfig(MonitorConfig{
+		Markers:         map[string]string{"XXX": "high"},
+		ExcludePatterns: []string{"generated/"},
+	}))
+	monitor.MinPackageLines = 0
+
+	result, err := monitor.Check(context.Background(), CodebaseContext{})
+	require.NoError(t, err)
+	require.Len(t, result.IssuesFound, 1)
+	assert.Equal(t, "high", result.IssuesFound[0].Severity)
+	assert.Equal(t, map[string]int{"XXX": 1}, result.IssuesFound[0].Evidence["by_marker"])
+
+	err = monitor.ApplyConfig(MonitorConfig{Markers: map[string]string{"TODO": "urgent"}})
+	assert.Error(t, err)
+}
+
+func TestMarkerBaselines(t *testing.T) {
+	now := time.Now()
+	history := []MarkerSnapshot{
+		{Timestamp: now.Add(-10 * 24 * time.Hour), Packages: map[string]int{"a": 1}},
+		{Timestamp: now.Add(-3 * 24 * time.Hour), Packages: map[string]int{"a": 5}},
+		{Timestamp: now.Add(-1 * 24 * time.Hour), Packages: map[string]int{"a": 7, "b": 2}},
+	}
+
+	baselines := markerBaselines(history, now.Add(-7*24*time.Hour))
+	assert.Equal(t, map[string]int{"a": 5, "b": 2}, baselines)
+}
@@ -70,6 +70,10 @@ type MonitorResult struct {
 
 	// Statistics from the check
 	Stats CheckStats
+
+	// Warnings about problems that didn't fail the check (e.g. malformed
+	// output from a custom monitor)
+	Warnings []string
 }
 
 // DiscoveredIssue represents a potential code health problem.
@@ -103,6 +107,9 @@ type ScheduleConfig struct {
 	// For hybrid schedules
 	MinInterval time.Duration // Minimum time between runs
 	MaxInterval time.Duration // Maximum time between runs
+
+	// Window restricts runs to a daily time-of-day window (nil = any time)
+	Window *TimeWindow
 }
 
 // ScheduleType determines when a monitor runs.
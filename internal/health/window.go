@@ -0,0 +1,67 @@
+package health
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TimeWindow is a daily time-of-day range in local time, such as "01:00-05:00".
+// A window whose end is before its start wraps past midnight ("22:00-06:00").
+type TimeWindow struct {
+	Start time.Duration // Offset from midnight
+	End   time.Duration // Offset from midnight
+}
+
+// ParseTimeWindow parses a window in "HH:MM-HH:MM" form.
+func ParseTimeWindow(s string) (*TimeWindow, error) {
+	startStr, endStr, ok := strings.Cut(strings.TrimSpace(s), "-")
+	if !ok {
+		return nil, fmt.Errorf("expected HH:MM-HH:MM")
+	}
+
+	start, err := parseClock(strings.TrimSpace(startStr))
+	if err != nil {
+		return nil, fmt.Errorf("invalid start: %w", err)
+	}
+	end, err := parseClock(strings.TrimSpace(endStr))
+	if err != nil {
+		return nil, fmt.Errorf("invalid end: %w", err)
+	}
+	if start == end {
+		return nil, fmt.Errorf("start and end must differ")
+	}
+
+	return &TimeWindow{Start: start, End: end}, nil
+}
+
+// parseClock parses "HH:MM" into an offset from midnight.
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not HH:MM", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Contains reports whether t's local time of day falls inside the window.
+// The start is inclusive and the end exclusive.
+func (w *TimeWindow) Contains(t time.Time) bool {
+	if w == nil {
+		return true
+	}
+	t = t.Local()
+	offset := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+	if w.Start < w.End {
+		return offset >= w.Start && offset < w.End
+	}
+	return offset >= w.Start || offset < w.End
+}
+
+// String formats the window as "HH:MM-HH:MM".
+func (w *TimeWindow) String() string {
+	format := func(d time.Duration) string {
+		return fmt.Sprintf("%02d:%02d", int(d/time.Hour), int(d%time.Hour/time.Minute))
+	}
+	return format(w.Start) + "-" + format(w.End)
+}
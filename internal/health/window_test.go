@@ -0,0 +1,60 @@
+package health
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimeWindow(t *testing.T) {
+	window, err := ParseTimeWindow("01:30-05:00")
+	if err != nil {
+		t.Fatalf("ParseTimeWindow failed: %v", err)
+	}
+	if window.Start != 90*time.Minute || window.End != 5*time.Hour {
+		t.Errorf("Unexpected window %+v", window)
+	}
+	if window.String() != "01:30-05:00" {
+		t.Errorf("String() = %q", window.String())
+	}
+
+	for _, invalid := range []string{"", "01:00", "1am-5am", "25:00-01:00", "03:00-03:00"} {
+		if _, err := ParseTimeWindow(invalid); err == nil {
+			t.Errorf("Expected error for %q", invalid)
+		}
+	}
+}
+
+func TestTimeWindowContains(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2025, 1, 1, hour, minute, 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		window string
+		t      time.Time
+		want   bool
+	}{
+		{"01:00-05:00", at(1, 0), true},
+		{"01:00-05:00", at(4, 59), true},
+		{"01:00-05:00", at(5, 0), false},
+		{"01:00-05:00", at(12, 0), false},
+		{"22:00-06:00", at(23, 0), true},
+		{"22:00-06:00", at(3, 0), true},
+		{"22:00-06:00", at(12, 0), false},
+	}
+
+	for _, tt := range tests {
+		window, err := ParseTimeWindow(tt.window)
+		if err != nil {
+			t.Fatalf("ParseTimeWindow(%q) failed: %v", tt.window, err)
+		}
+		if got := window.Contains(tt.t); got != tt.want {
+			t.Errorf("%s Contains(%s) = %v, want %v", tt.window, tt.t.Format("15:04"), got, tt.want)
+		}
+	}
+
+	var always *TimeWindow
+	if !always.Contains(at(12, 0)) {
+		t.Error("Expected nil window to contain every time")
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/vc/internal/ai"
+	"github.com/steveyegge/vc/internal/ignore"
 )
 
 const (
@@ -39,6 +40,11 @@ type ZFCDetector struct {
 	// ExcludePatterns for files/directories to skip
 	ExcludePatterns []string
 
+	// Ignore holds the project's .vcignore rules (nil means none). The
+	// constructor sets the built-in defaults; callers that load the
+	// .vcignore once (see NewDefaultRegistry) replace it.
+	Ignore *ignore.Matcher
+
 	// MinimumViolationThreshold - only file issue if this many violations found
 	MinimumViolationThreshold int
 
@@ -69,6 +75,7 @@ func NewZFCDetector(rootPath string, supervisor AISupervisor) (*ZFCDetector, err
 			"migrations/", // Migration files often have version numbers
 		},
 		MinimumViolationThreshold: 3, // Only file issue if ≥3 violations found
+		Ignore:                    ignore.Defaults(),
 		Supervisor:                supervisor,
 	}, nil
 }
@@ -186,7 +193,7 @@ func (d *ZFCDetector) scanFiles(ctx context.Context) ([]zfcViolation, error) {
 		}
 
 		// Skip excluded patterns
-		if ShouldExcludePath(relPath, info, d.ExcludePatterns) {
+		if ShouldExcludePath(relPath, info, d.ExcludePatterns) || d.Ignore.Match(relPath, info.IsDir()) {
 			if info.IsDir() {
 				return filepath.SkipDir
 			}
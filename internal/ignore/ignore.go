@@ -0,0 +1,242 @@
+// Package ignore parses .vcignore files and matches paths against them.
+//
+// A .vcignore file lives at the project root and uses gitignore syntax:
+//   - Blank lines and lines starting with "#" are ignored
+//   - A leading "!" negates the pattern (re-includes a previously ignored path)
+//   - A trailing "/" matches directories only
+//   - A pattern containing "/" (other than a trailing one) is anchored to the root
+//   - "*" and "?" never match "/", "**" matches across directories
+//
+// Built-in defaults (.git, .sandboxes, .beads, node_modules) apply even when
+// no .vcignore file exists. Patterns in the file are evaluated after the
+// defaults, so a negation can re-include a default entry.
+package ignore
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// FileName is the name of the ignore file at the project root.
+const FileName = ".vcignore"
+
+// DefaultPatterns are always applied, before any .vcignore patterns.
+var DefaultPatterns = []string{
+	".git/",
+	".sandboxes/",
+	".beads/",
+	"node_modules/",
+}
+
+// rule is a single compiled pattern.
+type rule struct {
+	pattern string
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// Matcher decides whether slash-separated paths relative to the project root
+// are ignored. A nil *Matcher ignores nothing.
+type Matcher struct {
+	rules []rule
+}
+
+// New compiles the given gitignore-style patterns into a Matcher.
+// Defaults are NOT added; use Load or NewWithDefaults for that.
+func New(patterns []string) (*Matcher, error) {
+	m := &Matcher{}
+	for _, p := range patterns {
+		r, ok, err := compile(p)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			m.rules = append(m.rules, r)
+		}
+	}
+	return m, nil
+}
+
+// NewWithDefaults compiles DefaultPatterns followed by patterns.
+func NewWithDefaults(patterns []string) (*Matcher, error) {
+	all := make([]string, 0, len(DefaultPatterns)+len(patterns))
+	all = append(all, DefaultPatterns...)
+	all = append(all, patterns...)
+	return New(all)
+}
+
+// Defaults returns a Matcher with only DefaultPatterns, for scans that
+// aren't handed the project's Matcher.
+func Defaults() *Matcher {
+	m, _ := NewWithDefaults(nil) // The defaults always compile
+	return m
+}
+
+// Load reads root/.vcignore (if present) and returns a Matcher combining it
+// with DefaultPatterns. A missing file is not an error.
+func Load(root string) (*Matcher, error) {
+	f, err := os.Open(filepath.Join(root, FileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return NewWithDefaults(nil)
+		}
+		return nil, fmt.Errorf("failed to open %s: %w", FileName, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	patterns, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", FileName, err)
+	}
+	m, err := NewWithDefaults(patterns)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", FileName, err)
+	}
+	return m, nil
+}
+
+// Parse reads pattern lines from r, dropping blank lines and comments.
+func Parse(r io.Reader) ([]string, error) {
+	var patterns []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return patterns, nil
+}
+
+// Match reports whether relPath is ignored. relPath is relative to the
+// project root and may use OS-specific separators. A path is also ignored if
+// any of its parent directories is ignored, matching git's behavior of not
+// descending into excluded directories.
+func (m *Matcher) Match(relPath string, isDir bool) bool {
+	if m == nil || len(m.rules) == 0 {
+		return false
+	}
+	p := strings.Trim(filepath.ToSlash(relPath), "/")
+	if p == "" || p == "." {
+		return false
+	}
+
+	parts := strings.Split(p, "/")
+	for i := 1; i < len(parts); i++ {
+		if m.matchOne(strings.Join(parts[:i], "/"), true) {
+			return true
+		}
+	}
+	return m.matchOne(p, isDir)
+}
+
+// matchOne evaluates all rules against a single path; the last match wins.
+func (m *Matcher) matchOne(p string, isDir bool) bool {
+	ignored := false
+	base := path.Base(p)
+	for _, r := range m.rules {
+		if r.dirOnly && !isDir {
+			continue
+		}
+		target := p
+		if !strings.Contains(r.pattern, "/") {
+			target = base
+		}
+		if r.re.MatchString(target) {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
+
+// compile turns one pattern line into a rule. ok is false for lines that
+// carry no pattern (e.g. a bare "!" or "/").
+func compile(line string) (r rule, ok bool, err error) {
+	p := line
+	if strings.HasPrefix(p, "!") {
+		r.negate = true
+		p = p[1:]
+	} else if strings.HasPrefix(p, `\!`) || strings.HasPrefix(p, `\#`) {
+		p = p[1:]
+	}
+	if strings.HasSuffix(p, "/") {
+		r.dirOnly = true
+		p = strings.TrimRight(p, "/")
+	}
+	anchored := strings.Contains(p, "/")
+	p = strings.TrimPrefix(p, "/")
+	if p == "" {
+		return rule{}, false, nil
+	}
+
+	// Keep a marker slash so matchOne knows to compare the full path.
+	if anchored {
+		r.pattern = "/" + p
+	} else {
+		r.pattern = p
+	}
+
+	re, err := regexp.Compile("^" + globToRegexp(p) + "$")
+	if err != nil {
+		return rule{}, false, fmt.Errorf("invalid pattern %q: %w", line, err)
+	}
+	r.re = re
+	return r, true, nil
+}
+
+// globToRegexp translates gitignore glob syntax into a regular expression.
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				// "**/" matches zero or more directories; "/**" and a bare
+				// "**" match everything below.
+				if i+2 < len(glob) && glob[i+2] == '/' {
+					b.WriteString("(?:.*/)?")
+					i += 2
+				} else {
+					b.WriteString(".*")
+					i++
+				}
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end + 1
+		case '\\':
+			if i+1 < len(glob) {
+				i++
+				b.WriteString(regexp.QuoteMeta(string(glob[i])))
+			}
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,113 @@
+package ignore
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		path     string
+		isDir    bool
+		want     bool
+	}{
+		{"basename anywhere", []string{"*.log"}, "a/b/debug.log", false, true},
+		{"basename no match", []string{"*.log"}, "a/b/debug.txt", false, false},
+		{"plain name matches dir", []string{"build"}, "build", true, true},
+		{"plain name matches nested", []string{"build"}, "src/build", true, true},
+		{"child of ignored dir", []string{"build"}, "src/build/out.go", false, true},
+		{"dir-only skips file", []string{"tmp/"}, "tmp", false, false},
+		{"dir-only matches dir", []string{"tmp/"}, "tmp", true, true},
+		{"dir-only covers contents", []string{"tmp/"}, "tmp/x.go", false, true},
+		{"anchored at root", []string{"/docs"}, "docs", true, true},
+		{"anchored not nested", []string{"/docs"}, "pkg/docs", true, false},
+		{"slash anchors pattern", []string{"pkg/gen"}, "pkg/gen/a.go", false, true},
+		{"slash anchors not nested", []string{"pkg/gen"}, "x/pkg/gen/a.go", false, false},
+		{"leading double star", []string{"**/fixtures"}, "a/b/fixtures/f.json", false, true},
+		{"leading double star at root", []string{"**/fixtures"}, "fixtures", true, true},
+		{"trailing double star", []string{"out/**"}, "out/a/b.txt", false, true},
+		{"middle double star", []string{"a/**/z.go"}, "a/b/c/z.go", false, true},
+		{"middle double star zero dirs", []string{"a/**/z.go"}, "a/z.go", false, true},
+		{"star stops at slash", []string{"/a/*.go"}, "a/b/c.go", false, false},
+		{"question mark", []string{"file?.go"}, "file1.go", false, true},
+		{"char class", []string{"file[0-9].go"}, "file7.go", false, true},
+		{"negated char class", []string{"file[!0-9].go"}, "file7.go", false, false},
+		{"negation re-includes", []string{"*.go", "!keep.go"}, "keep.go", false, false},
+		{"negation order matters", []string{"!keep.go", "*.go"}, "keep.go", false, true},
+		{"negation cannot escape parent", []string{"vendor/", "!vendor/keep.go"}, "vendor/keep.go", false, true},
+		{"escaped hash", []string{`\#notes`}, "#notes", false, true},
+		{"windows separators", []string{"/pkg/gen"}, filepath.Join("pkg", "gen", "a.go"), false, true},
+		{"root never ignored", []string{"*"}, ".", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := New(tt.patterns)
+			if err != nil {
+				t.Fatalf("New(%v) error: %v", tt.patterns, err)
+			}
+			if got := m.Match(tt.path, tt.isDir); got != tt.want {
+				t.Errorf("Match(%q, %v) with %v = %v, want %v", tt.path, tt.isDir, tt.patterns, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	input := "# comment\n\n*.log  \n!keep.log\n/build/\n"
+	got, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	want := []string{"*.log", "!keep.log", "/build/"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Parse = %v, want %v", got, want)
+	}
+}
+
+func TestLoadDefaultsWithoutFile(t *testing.T) {
+	m, err := Load(t.TempDir())
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	for _, p := range []string{".git/config", ".sandboxes/mission-1/main.go", ".beads/vc.db", "web/node_modules/x/index.js"} {
+		if !m.Match(p, false) {
+			t.Errorf("expected default to ignore %q", p)
+		}
+	}
+	if m.Match("internal/main.go", false) {
+		t.Error("expected internal/main.go not to be ignored")
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	root := t.TempDir()
+	content := "generated/\n!.beads/\n"
+	if err := os.WriteFile(filepath.Join(root, FileName), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m, err := Load(root)
+	if err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	if !m.Match("generated/api.go", false) {
+		t.Error("expected generated/api.go to be ignored")
+	}
+	if !m.Match(".git/HEAD", false) {
+		t.Error("expected defaults to still apply")
+	}
+	if m.Match(".beads/vc.db", false) {
+		t.Error("expected negation to re-include .beads/")
+	}
+}
+
+func TestNilMatcher(t *testing.T) {
+	var m *Matcher
+	if m.Match("anything", false) {
+		t.Error("nil matcher should ignore nothing")
+	}
+}
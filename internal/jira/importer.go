@@ -0,0 +1,424 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// DefaultBatchSize is how many issues the importer creates per transaction
+const DefaultBatchSize = 500
+
+// RefLabel returns the label recording which Jira issue a vc issue was
+// imported from, e.g. jira:PROJ-12
+func RefLabel(key string) string {
+	return "jira:" + key
+}
+
+// MetaKey is the metadata key recording the Jira issue key
+const MetaKey = "jira.key"
+
+// SprintLabel returns the label a sprint maps to, e.g. sprint:Sprint 4
+func SprintLabel(sprint string) string {
+	return "sprint:" + sprint
+}
+
+// ImportStore is the part of storage the importer uses
+type ImportStore interface {
+	CreateIssues(ctx context.Context, issues []*types.Issue, actor string, opts types.CreateIssuesOptions) error
+	SetIssueMeta(ctx context.Context, issueID, key string, value json.RawMessage) error
+	GetIssuesByLabel(ctx context.Context, label string) ([]*types.Issue, error)
+	AddDependency(ctx context.Context, dep *types.Dependency, actor string) error
+}
+
+// ImportResult counts what an import did
+type ImportResult struct {
+	Created      int
+	Skipped      int // Imported before
+	Failed       int
+	Dependencies int
+	Unresolved   int // Links to issues that weren't imported
+}
+
+// Importer maps Jira records to vc issues and creates them in batches. Every
+// decision it makes (type, priority and status mappings, labels, links,
+// fields kept in notes, skips and failures) is written to the report, one
+// line per decision, so the import can be audited.
+type Importer struct {
+	store     ImportStore
+	actor     string
+	report    io.Writer
+	batchSize int
+}
+
+// NewImporter returns an importer into store that reports to report
+func NewImporter(store ImportStore, actor string, report io.Writer, batchSize int) *Importer {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	if report == nil {
+		report = io.Discard
+	}
+	return &Importer{store: store, actor: actor, report: report, batchSize: batchSize}
+}
+
+// link is a dependency between two Jira issues, resolved once all issues
+// exist
+type link struct {
+	from, to string // Keys (or IDs, for CSV parents); from depends on to
+	depType  types.DependencyType
+	reason   string
+}
+
+// pendingIssue is a mapped record waiting for its batch to be created
+type pendingIssue struct {
+	key    string
+	issue  *types.Issue
+	labels []string
+}
+
+// importRun is the state of one Import call
+type importRun struct {
+	*Importer
+	result  ImportResult
+	ids     map[string]string // Jira key to vc issue ID
+	keyByID map[string]string // Jira ID to key
+	fresh   map[string]bool   // Keys created by this run
+	links   []link
+	batch   []pendingIssue
+}
+
+// Import reads every record, creates the issues not imported before (found
+// by their RefLabel, so an interrupted import can be rerun), then adds the
+// epic, parent and "blocks" links between them as dependencies. Records are
+// streamed and created in batches; only the keys and links are kept.
+func (im *Importer) Import(ctx context.Context, reader Reader) (*ImportResult, error) {
+	run := &importRun{
+		Importer: im,
+		ids:      make(map[string]string),
+		keyByID:  make(map[string]string),
+		fresh:    make(map[string]bool),
+	}
+	for row := 1; ; row++ {
+		record, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return &run.result, fmt.Errorf("record %d: %w", row, err)
+		}
+		if err := run.add(ctx, row, record); err != nil {
+			return &run.result, err
+		}
+		if len(run.batch) >= im.batchSize {
+			if err := run.flush(ctx); err != nil {
+				return &run.result, err
+			}
+		}
+	}
+	if err := run.flush(ctx); err != nil {
+		return &run.result, err
+	}
+	if err := run.addLinks(ctx); err != nil {
+		return &run.result, err
+	}
+	return &run.result, nil
+}
+
+// add maps a record into the current batch, unless it was imported before
+func (run *importRun) add(ctx context.Context, row int, record *Record) error {
+	key := record.Key
+	if key == "" {
+		key = fmt.Sprintf("row %d", row)
+	} else if record.ID != "" {
+		run.keyByID[record.ID] = key
+	}
+	run.collectLinks(key, record)
+
+	if record.Key != "" {
+		existing, err := run.store.GetIssuesByLabel(ctx, RefLabel(record.Key))
+		if err != nil {
+			return fmt.Errorf("failed to look up %s: %w", record.Key, err)
+		}
+		if len(existing) > 0 {
+			run.ids[key] = existing[0].ID
+			run.result.Skipped++
+			run.decide(key, "skipped: already imported as %s", existing[0].ID)
+			return nil
+		}
+	} else {
+		run.decide(key, "no issue key: imported without a jira: label, so links to it can't resolve and a rerun imports it again")
+	}
+
+	issue, labels := run.mapRecord(key, record)
+	run.batch = append(run.batch, pendingIssue{key: key, issue: issue, labels: labels})
+	return nil
+}
+
+// mapRecord converts a record to a vc issue and its labels, reporting each
+// decision
+func (run *importRun) mapRecord(key string, record *Record) (*types.Issue, []string) {
+	extra := append([]Field(nil), record.Extra...)
+	issue := &types.Issue{
+		Title:       record.Summary,
+		Description: record.Description,
+		Assignee:    record.Assignee,
+		Status:      types.StatusOpen,
+	}
+	if issue.Title == "" {
+		issue.Title = key
+		run.decide(key, "no summary: titled %q", key)
+	}
+	if len(issue.Title) > 500 {
+		issue.Title = issue.Title[:497] + "..."
+		extra = append(extra, Field{Name: "Summary", Value: record.Summary})
+		run.decide(key, "summary truncated to 500 characters; full summary kept in notes")
+	}
+
+	issueType, ok := mapType(record.Type)
+	issue.IssueType = issueType
+	if ok {
+		run.decide(key, "type %q -> %s", record.Type, issueType)
+	} else {
+		if record.Type != "" {
+			extra = append(extra, Field{Name: "Issue Type", Value: record.Type})
+		}
+		run.decide(key, "type %q unknown -> %s (kept in notes)", record.Type, issueType)
+	}
+
+	priority, ok := mapPriority(record.Priority)
+	issue.Priority = priority
+	if ok {
+		run.decide(key, "priority %q -> P%d", record.Priority, priority)
+	} else {
+		if record.Priority != "" {
+			extra = append(extra, Field{Name: "Priority", Value: record.Priority})
+		}
+		run.decide(key, "priority %q unknown -> P%d (kept in notes)", record.Priority, priority)
+	}
+
+	if record.Status != "" {
+		if isDoneStatus(record.Status) {
+			issue.Status = types.StatusClosed
+			closedAt := parseDate(record.Resolved)
+			issue.ClosedAt = &closedAt
+		} else if !strings.EqualFold(record.Status, "open") && !strings.EqualFold(record.Status, "to do") {
+			extra = append(extra, Field{Name: "Status", Value: record.Status})
+		}
+		run.decide(key, "status %q -> %s", record.Status, issue.Status)
+	}
+
+	var labels []string
+	for _, label := range record.Labels {
+		labels = append(labels, label)
+		run.decide(key, "label %q", label)
+	}
+	for _, sprint := range record.Sprints {
+		labels = append(labels, SprintLabel(sprint))
+		run.decide(key, "sprint %q -> label %s", sprint, SprintLabel(sprint))
+	}
+	if record.Key != "" {
+		labels = append(labels, RefLabel(record.Key))
+	}
+
+	if len(extra) > 0 {
+		var notes strings.Builder
+		notes.WriteString("Jira fields without a vc equivalent:\n")
+		for _, field := range extra {
+			fmt.Fprintf(&notes, "\n%s: %s", field.Name, field.Value)
+			run.decide(key, "field %q -> notes", field.Name)
+		}
+		issue.Notes = notes.String()
+	}
+	return issue, labels
+}
+
+// collectLinks remembers the record's epic, parent and "blocks" links
+func (run *importRun) collectLinks(key string, record *Record) {
+	if record.EpicLink != "" {
+		run.links = append(run.links, link{key, record.EpicLink, types.DepParentChild, "epic link"})
+	}
+	if record.Parent != "" && record.Parent != record.EpicLink {
+		run.links = append(run.links, link{key, record.Parent, types.DepParentChild, "parent"})
+	}
+	for _, blocked := range record.Blocks {
+		run.links = append(run.links, link{blocked, key, types.DepBlocks, "blocks"})
+	}
+	for _, blocker := range record.BlockedBy {
+		run.links = append(run.links, link{key, blocker, types.DepBlocks, "blocks"})
+	}
+}
+
+// flush creates the current batch. Issues that fail are reported and
+// counted; the rest are created.
+func (run *importRun) flush(ctx context.Context) error {
+	if len(run.batch) == 0 {
+		return nil
+	}
+	issues := make([]*types.Issue, len(run.batch))
+	opts := types.CreateIssuesOptions{Labels: make(map[int][]string)}
+	for i, pending := range run.batch {
+		issues[i] = pending.issue
+		opts.Labels[i] = pending.labels
+	}
+
+	err := run.store.CreateIssues(ctx, issues, run.actor, opts)
+	var batchErr *types.BatchCreateError
+	if err != nil && !errors.As(err, &batchErr) {
+		return fmt.Errorf("failed to create issues: %w", err)
+	}
+	for i, pending := range run.batch {
+		if batchErr != nil && batchErr.Errors[i] != nil {
+			run.result.Failed++
+			run.decide(pending.key, "failed: %v", batchErr.Errors[i])
+			continue
+		}
+		run.ids[pending.key] = pending.issue.ID
+		run.fresh[pending.key] = true
+		run.result.Created++
+		run.decide(pending.key, "created as %s", pending.issue.ID)
+		key, _ := json.Marshal(pending.key)
+		if err := run.store.SetIssueMeta(ctx, pending.issue.ID, MetaKey, key); err != nil {
+			return fmt.Errorf("failed to record %s in metadata: %w", pending.key, err)
+		}
+	}
+	run.batch = run.batch[:0]
+	return nil
+}
+
+// addLinks adds the collected links between issues that exist, skipping
+// those whose both ends were imported before (a rerun added them already)
+func (run *importRun) addLinks(ctx context.Context) error {
+	seen := make(map[link]bool)
+	for _, l := range run.links {
+		l.from, l.to = run.resolveKey(l.from), run.resolveKey(l.to)
+		dedup := link{from: l.from, to: l.to, depType: l.depType}
+		if seen[dedup] {
+			continue
+		}
+		seen[dedup] = true
+		if !run.fresh[l.from] && !run.fresh[l.to] {
+			continue
+		}
+
+		fromID, err := run.lookup(ctx, l.from)
+		if err != nil {
+			return err
+		}
+		toID, err := run.lookup(ctx, l.to)
+		if err != nil {
+			return err
+		}
+		if fromID == "" || toID == "" {
+			run.result.Unresolved++
+			run.decide(l.from, "%s %s not imported: link dropped", l.reason, l.to)
+			continue
+		}
+		dep := &types.Dependency{IssueID: fromID, DependsOnID: toID, Type: l.depType}
+		if err := run.store.AddDependency(ctx, dep, run.actor); err != nil {
+			run.result.Failed++
+			run.decide(l.from, "%s %s: failed to add dependency: %v", l.reason, l.to, err)
+			continue
+		}
+		run.result.Dependencies++
+		run.decide(l.from, "%s %s -> %s depends on %s (%s)", l.reason, l.to, fromID, toID, l.depType)
+	}
+	return nil
+}
+
+// resolveKey turns a Jira ID into its key where the export had both
+func (run *importRun) resolveKey(ref string) string {
+	if key, ok := run.keyByID[ref]; ok {
+		return key
+	}
+	return ref
+}
+
+// lookup returns the vc issue of a Jira key, imported now or before, or ""
+func (run *importRun) lookup(ctx context.Context, key string) (string, error) {
+	if id, ok := run.ids[key]; ok {
+		return id, nil
+	}
+	existing, err := run.store.GetIssuesByLabel(ctx, RefLabel(key))
+	if err != nil {
+		return "", fmt.Errorf("failed to look up %s: %w", key, err)
+	}
+	id := ""
+	if len(existing) > 0 {
+		id = existing[0].ID
+	}
+	run.ids[key] = id
+	return id, nil
+}
+
+// decide writes one decision to the report
+func (run *importRun) decide(key, format string, args ...interface{}) {
+	fmt.Fprintf(run.report, "%s\t%s\n", key, fmt.Sprintf(format, args...))
+}
+
+// mapType maps a Jira issue type to a vc type. Unknown types become tasks.
+func mapType(jiraType string) (types.IssueType, bool) {
+	switch strings.ToLower(strings.TrimSpace(jiraType)) {
+	case "story", "new feature", "improvement":
+		return types.TypeFeature, true
+	case "bug", "defect":
+		return types.TypeBug, true
+	case "task", "sub-task", "subtask":
+		return types.TypeTask, true
+	case "epic":
+		return types.TypeEpic, true
+	}
+	return types.TypeTask, false
+}
+
+// mapPriority maps a Jira priority name to 0-4. Unknown priorities are 2.
+func mapPriority(name string) (int, bool) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "highest", "blocker":
+		return 0, true
+	case "high", "critical":
+		return 1, true
+	case "medium", "major":
+		return 2, true
+	case "low", "minor":
+		return 3, true
+	case "lowest", "trivial":
+		return 4, true
+	}
+	return 2, false
+}
+
+// isDoneStatus reports whether a Jira status means the work is finished
+func isDoneStatus(status string) bool {
+	switch strings.ToLower(strings.TrimSpace(status)) {
+	case "done", "closed", "resolved", "complete", "completed", "won't do", "won't fix", "cancelled", "canceled":
+		return true
+	}
+	return false
+}
+
+// jiraDateLayouts are the date formats of Jira exports: JSON, then the CSV
+// default and ISO-ish variants
+var jiraDateLayouts = []string{
+	"2006-01-02T15:04:05.000-0700",
+	time.RFC3339,
+	"02/Jan/06 3:04 PM",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// parseDate parses an exported date, or returns now if it can't
+func parseDate(value string) time.Time {
+	for _, layout := range jiraDateLayouts {
+		if t, err := time.Parse(layout, strings.TrimSpace(value)); err == nil {
+			return t
+		}
+	}
+	return time.Now()
+}
@@ -0,0 +1,95 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/storage/storagetest"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+const testExport = "Issue key,Summary,Issue Type,Priority,Status,Custom field (Epic Link),Outward issue link (Blocks),Sprint,Reporter\n" +
+	"PROJ-1,Checkout,Epic,Medium,In Progress,,,,ada\n" +
+	"PROJ-2,Pay,Story,Highest,To Do,PROJ-1,PROJ-3,Sprint 4,ada\n" +
+	"PROJ-3,Receipt,Spike,Whenever,Done,PROJ-1,,,bob\n"
+
+func TestImport(t *testing.T) {
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+	var report bytes.Buffer
+	importer := NewImporter(store, "tester", &report, 2)
+
+	reader, _ := NewCSVReader(strings.NewReader(testExport))
+	result, err := importer.Import(ctx, reader)
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if result.Created != 3 || result.Dependencies != 3 || result.Failed != 0 {
+		t.Fatalf("Unexpected result %+v\n%s", result, report.String())
+	}
+	if calls := store.Calls("CreateIssues"); len(calls) != 2 {
+		t.Errorf("Expected 2 batches of at most 2 issues, got %d", len(calls))
+	}
+
+	issue := func(key string) *types.Issue {
+		t.Helper()
+		found, err := store.GetIssuesByLabel(ctx, RefLabel(key))
+		if err != nil || len(found) != 1 {
+			t.Fatalf("Expected one issue labeled %s, got %v (%v)", RefLabel(key), found, err)
+		}
+		return found[0]
+	}
+	epic, story, spike := issue("PROJ-1"), issue("PROJ-2"), issue("PROJ-3")
+	if epic.IssueType != types.TypeEpic || epic.Status != types.StatusOpen || !strings.Contains(epic.Notes, "Status: In Progress") {
+		t.Errorf("Unexpected epic %+v", epic)
+	}
+	if story.IssueType != types.TypeFeature || story.Priority != 0 || !strings.Contains(story.Notes, "Reporter: ada") {
+		t.Errorf("Unexpected story %+v", story)
+	}
+	if spike.IssueType != types.TypeTask || spike.Priority != 2 || spike.Status != types.StatusClosed ||
+		!strings.Contains(spike.Notes, "Issue Type: Spike") || !strings.Contains(spike.Notes, "Priority: Whenever") {
+		t.Errorf("Unexpected spike %+v", spike)
+	}
+	if meta, _ := store.GetIssueMeta(ctx, story.ID); string(meta[MetaKey]) != `"PROJ-2"` {
+		t.Errorf("Expected %s metadata, got %v", MetaKey, meta)
+	}
+	if labels, _ := store.GetLabels(ctx, story.ID); !strings.Contains(strings.Join(labels, ","), "sprint:Sprint 4") {
+		t.Errorf("Expected a sprint label, got %v", labels)
+	}
+
+	records, _ := store.GetDependencyRecords(ctx, spike.ID)
+	var blockedBy, parent bool
+	for _, dep := range records {
+		blockedBy = blockedBy || (dep.DependsOnID == story.ID && dep.Type == types.DepBlocks)
+		parent = parent || (dep.DependsOnID == epic.ID && dep.Type == types.DepParentChild)
+	}
+	if !blockedBy || !parent {
+		t.Errorf("Expected PROJ-3 blocked by PROJ-2 and a child of PROJ-1, got %+v", records)
+	}
+
+	for _, want := range []string{
+		"PROJ-2\ttype \"Story\" -> feature",
+		"PROJ-2\tpriority \"Highest\" -> P0",
+		"PROJ-3\ttype \"Spike\" unknown -> task (kept in notes)",
+		"PROJ-2\tsprint \"Sprint 4\" -> label sprint:Sprint 4",
+		"PROJ-1\tfield \"Reporter\" -> notes",
+		"PROJ-3\tblocks PROJ-2 -> " + spike.ID + " depends on " + story.ID + " (blocks)",
+	} {
+		if !strings.Contains(report.String(), want) {
+			t.Errorf("Expected %q in the report:\n%s", want, report.String())
+		}
+	}
+
+	// A rerun skips everything and adds no links twice
+	report.Reset()
+	reader, _ = NewCSVReader(strings.NewReader(testExport + "PROJ-4,Refund,Task,Low,To Do,,PROJ-1,,\n"))
+	result, err = importer.Import(ctx, reader)
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if result.Created != 1 || result.Skipped != 3 || result.Dependencies != 1 {
+		t.Errorf("Unexpected rerun result %+v\n%s", result, report.String())
+	}
+}
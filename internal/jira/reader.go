@@ -0,0 +1,431 @@
+// Package jira reads Jira CSV and JSON exports for vc import jira. Both are
+// streamed record by record, so exports of any size fit in memory.
+package jira
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Export formats
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// Record is one Jira issue as exported, before mapping
+type Record struct {
+	Key         string // e.g. PROJ-12
+	ID          string // Numeric Jira ID; CSV parent columns may refer to it
+	Summary     string
+	Description string
+	Type        string
+	Priority    string
+	Status      string
+	Assignee    string
+	Resolved    string // Resolution date, as exported
+	Labels      []string
+	Sprints     []string
+	EpicLink    string   // Key of the issue's epic
+	Parent      string   // Key or ID of the parent issue
+	Blocks      []string // Keys of the issues this one blocks
+	BlockedBy   []string // Keys of the issues blocking this one
+	// Extra are the fields vc has no place for, verbatim: in column order for
+	// CSV exports, by field ID for JSON ones
+	Extra []Field
+}
+
+// Field is an exported field that isn't mapped
+type Field struct {
+	Name  string
+	Value string
+}
+
+// Reader streams the records of an export. Next returns io.EOF after the
+// last record.
+type Reader interface {
+	Next() (*Record, error)
+}
+
+// DetectFormat picks the format from a file's extension
+func DetectFormat(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".json":
+		return FormatJSON, nil
+	}
+	return "", fmt.Errorf("can't tell the format of %s; use --format csv or --format json", path)
+}
+
+// NewReader returns a reader of an export in format
+func NewReader(r io.Reader, format string) (Reader, error) {
+	switch format {
+	case FormatCSV:
+		return NewCSVReader(r)
+	case FormatJSON:
+		return NewJSONReader(r)
+	}
+	return nil, fmt.Errorf("unknown format %q (want csv or json)", format)
+}
+
+// ======================================================================
+// CSV
+// ======================================================================
+
+// CSVReader reads Jira's CSV export, which repeats a column once per value
+// of a multi-valued field (Labels, Sprint, issue links)
+type CSVReader struct {
+	csv    *csv.Reader
+	header []string
+}
+
+// NewCSVReader reads the header row of a CSV export
+func NewCSVReader(r io.Reader) (*CSVReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff") // Excel's byte order mark
+	}
+	return &CSVReader{csv: reader, header: header}, nil
+}
+
+// Next returns the next row as a record
+func (c *CSVReader) Next() (*Record, error) {
+	row, err := c.csv.Read()
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV row: %w", err)
+	}
+
+	record := &Record{}
+	for i, value := range row {
+		if i >= len(c.header) || strings.TrimSpace(value) == "" {
+			continue
+		}
+		name := strings.TrimSpace(c.header[i])
+		trimmed := strings.TrimSpace(value)
+		switch strings.ToLower(name) {
+		case "issue key":
+			record.Key = trimmed
+		case "issue id":
+			record.ID = trimmed
+		case "summary":
+			record.Summary = trimmed
+		case "description":
+			record.Description = value
+		case "issue type":
+			record.Type = trimmed
+		case "priority":
+			record.Priority = trimmed
+		case "status":
+			record.Status = trimmed
+		case "assignee":
+			record.Assignee = trimmed
+		case "resolved":
+			record.Resolved = trimmed
+		case "labels":
+			record.Labels = append(record.Labels, trimmed)
+		case "sprint":
+			record.Sprints = append(record.Sprints, trimmed)
+		case "custom field (epic link)", "epic link":
+			record.EpicLink = trimmed
+		case "parent", "parent id", "parent key":
+			if record.Parent == "" {
+				record.Parent = trimmed
+			}
+		case "outward issue link (blocks)":
+			record.Blocks = append(record.Blocks, trimmed)
+		case "inward issue link (blocks)":
+			record.BlockedBy = append(record.BlockedBy, trimmed)
+		default:
+			record.Extra = append(record.Extra, Field{Name: name, Value: value})
+		}
+	}
+	return record, nil
+}
+
+// ======================================================================
+// JSON
+// ======================================================================
+
+// JSONReader reads Jira's JSON export: a search result ({"issues": [...]},
+// optionally with the "names" of custom fields) or a bare array of issues
+type JSONReader struct {
+	dec   *json.Decoder
+	names map[string]string // Custom field ID to display name
+	done  bool
+}
+
+// jsonIssue is an issue of a JSON export
+type jsonIssue struct {
+	ID     string                     `json:"id"`
+	Key    string                     `json:"key"`
+	Fields map[string]json.RawMessage `json:"fields"`
+}
+
+// NewJSONReader positions the decoder at the first issue of a JSON export
+func NewJSONReader(r io.Reader) (*JSONReader, error) {
+	j := &JSONReader{dec: json.NewDecoder(r)}
+	token, err := j.dec.Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JSON export: %w", err)
+	}
+	switch token {
+	case json.Delim('['):
+		return j, nil
+	case json.Delim('{'):
+	default:
+		return nil, fmt.Errorf("JSON export must be an object or an array of issues")
+	}
+
+	for j.dec.More() {
+		key, err := j.dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JSON export: %w", err)
+		}
+		switch key {
+		case "issues":
+			if token, err := j.dec.Token(); err != nil || token != json.Delim('[') {
+				return nil, fmt.Errorf("JSON export: \"issues\" must be an array")
+			}
+			return j, nil
+		case "names":
+			if err := j.dec.Decode(&j.names); err != nil {
+				return nil, fmt.Errorf("JSON export: failed to read \"names\": %w", err)
+			}
+		default:
+			var skip json.RawMessage
+			if err := j.dec.Decode(&skip); err != nil {
+				return nil, fmt.Errorf("failed to read JSON export: %w", err)
+			}
+		}
+	}
+	return nil, fmt.Errorf("JSON export has no \"issues\" array")
+}
+
+// Next decodes the next issue as a record
+func (j *JSONReader) Next() (*Record, error) {
+	if j.done || !j.dec.More() {
+		j.done = true
+		return nil, io.EOF
+	}
+	var issue jsonIssue
+	if err := j.dec.Decode(&issue); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON issue: %w", err)
+	}
+
+	record := &Record{Key: issue.Key, ID: issue.ID}
+	fieldIDs := make([]string, 0, len(issue.Fields))
+	for id := range issue.Fields {
+		fieldIDs = append(fieldIDs, id)
+	}
+	sort.Strings(fieldIDs) // Maps lose the export order; sort for stable notes
+
+	for _, id := range fieldIDs {
+		raw := issue.Fields[id]
+		if isEmptyJSON(raw) {
+			continue
+		}
+		name := id
+		if display := j.names[id]; display != "" {
+			name = display
+		}
+		switch strings.ToLower(name) {
+		case "summary":
+			record.Summary = jsonString(raw)
+		case "description":
+			record.Description = jsonText(raw)
+		case "issuetype", "issue type":
+			record.Type = jsonName(raw)
+		case "priority":
+			record.Priority = jsonName(raw)
+		case "status":
+			record.Status = jsonName(raw)
+		case "assignee":
+			record.Assignee = jsonUser(raw)
+		case "resolutiondate", "resolved":
+			record.Resolved = jsonString(raw)
+		case "labels":
+			_ = json.Unmarshal(raw, &record.Labels)
+		case "parent":
+			var parent struct {
+				Key string `json:"key"`
+			}
+			_ = json.Unmarshal(raw, &parent)
+			record.Parent = parent.Key
+		case "epic link":
+			record.EpicLink = jsonString(raw)
+		case "issuelinks":
+			record.Blocks, record.BlockedBy = jsonBlockLinks(raw)
+		default:
+			if sprints := jsonSprints(raw); sprints != nil && (strings.EqualFold(name, "sprint") || strings.HasPrefix(id, "customfield_")) {
+				record.Sprints = append(record.Sprints, sprints...)
+				continue
+			}
+			record.Extra = append(record.Extra, Field{Name: name, Value: jsonVerbatim(raw)})
+		}
+	}
+	return record, nil
+}
+
+// isEmptyJSON reports whether a field has no value worth keeping
+func isEmptyJSON(raw json.RawMessage) bool {
+	switch strings.TrimSpace(string(raw)) {
+	case "", "null", `""`, "[]", "{}":
+		return true
+	}
+	return false
+}
+
+// jsonString returns a string field, or the field verbatim if it isn't one
+func jsonString(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	return jsonVerbatim(raw)
+}
+
+// jsonName returns the name of an object field like {"name": "Bug"}
+func jsonName(raw json.RawMessage) string {
+	var named struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(raw, &named); err == nil && named.Name != "" {
+		return named.Name
+	}
+	return jsonString(raw)
+}
+
+// jsonUser returns a user's display name, else their email address
+func jsonUser(raw json.RawMessage) string {
+	var user struct {
+		DisplayName  string `json:"displayName"`
+		EmailAddress string `json:"emailAddress"`
+		Name         string `json:"name"`
+	}
+	if err := json.Unmarshal(raw, &user); err != nil {
+		return jsonString(raw)
+	}
+	for _, name := range []string{user.DisplayName, user.EmailAddress, user.Name} {
+		if name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// jsonText returns a description, which is a string in Jira Server exports
+// and an Atlassian Document Format tree in Jira Cloud ones. The tree is
+// flattened to its text, one paragraph per line.
+func jsonText(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	var node adfNode
+	if err := json.Unmarshal(raw, &node); err != nil {
+		return jsonVerbatim(raw)
+	}
+	var b strings.Builder
+	node.text(&b)
+	return strings.TrimSpace(b.String())
+}
+
+// adfNode is a node of an Atlassian Document Format tree
+type adfNode struct {
+	Type    string    `json:"type"`
+	Text    string    `json:"text"`
+	Content []adfNode `json:"content"`
+}
+
+// text writes the node's text, ending block nodes with a newline
+func (n *adfNode) text(b *strings.Builder) {
+	b.WriteString(n.Text)
+	for i := range n.Content {
+		n.Content[i].text(b)
+	}
+	switch n.Type {
+	case "paragraph", "heading", "listItem", "codeBlock", "blockquote", "hardBreak":
+		b.WriteString("\n")
+	}
+}
+
+// jsonBlockLinks returns the keys of the issues an issue blocks and is
+// blocked by, from its "issuelinks" field
+func jsonBlockLinks(raw json.RawMessage) (blocks, blockedBy []string) {
+	var links []struct {
+		Type struct {
+			Name string `json:"name"`
+		} `json:"type"`
+		InwardIssue *struct {
+			Key string `json:"key"`
+		} `json:"inwardIssue"`
+		OutwardIssue *struct {
+			Key string `json:"key"`
+		} `json:"outwardIssue"`
+	}
+	if err := json.Unmarshal(raw, &links); err != nil {
+		return nil, nil
+	}
+	for _, link := range links {
+		if !strings.EqualFold(link.Type.Name, "blocks") {
+			continue
+		}
+		if link.OutwardIssue != nil && link.OutwardIssue.Key != "" {
+			blocks = append(blocks, link.OutwardIssue.Key)
+		}
+		if link.InwardIssue != nil && link.InwardIssue.Key != "" {
+			blockedBy = append(blockedBy, link.InwardIssue.Key)
+		}
+	}
+	return blocks, blockedBy
+}
+
+// jsonSprints returns the sprint names of a sprint field: an array of
+// objects with a name and a boardId. It returns nil for any other field.
+func jsonSprints(raw json.RawMessage) []string {
+	var sprints []struct {
+		Name    string `json:"name"`
+		BoardID *int   `json:"boardId"`
+	}
+	if err := json.Unmarshal(raw, &sprints); err != nil || len(sprints) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(sprints))
+	for _, sprint := range sprints {
+		if sprint.Name == "" || sprint.BoardID == nil {
+			return nil
+		}
+		names = append(names, sprint.Name)
+	}
+	return names
+}
+
+// jsonVerbatim returns a field as exported: strings unquoted, anything else
+// as compact JSON
+func jsonVerbatim(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, raw); err != nil {
+		return string(raw)
+	}
+	return buf.String()
+}
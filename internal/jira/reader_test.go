@@ -0,0 +1,107 @@
+package jira
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// readAll reads every record of an export
+func readAll(t *testing.T, reader Reader) []*Record {
+	t.Helper()
+	var records []*Record
+	for {
+		record, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return records
+		}
+		if err != nil {
+			t.Fatalf("Next failed: %v", err)
+		}
+		records = append(records, record)
+	}
+}
+
+func TestCSVReader(t *testing.T) {
+	export := "\ufeffSummary,Issue key,Issue id,Issue Type,Priority,Labels,Labels,Sprint,Sprint,Outward issue link (Blocks),Custom field (Epic Link),Environment,Description\n" +
+		"Login fails,PROJ-2,10002,Bug,High,auth,,Sprint 1,Sprint 2,PROJ-3,PROJ-1,staging,\"Steps:\n1. log in\"\n"
+	reader, err := NewCSVReader(strings.NewReader(export))
+	if err != nil {
+		t.Fatalf("NewCSVReader failed: %v", err)
+	}
+	records := readAll(t, reader)
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(records))
+	}
+	want := &Record{
+		Key: "PROJ-2", ID: "10002", Summary: "Login fails", Description: "Steps:\n1. log in",
+		Type: "Bug", Priority: "High", Labels: []string{"auth"}, Sprints: []string{"Sprint 1", "Sprint 2"},
+		EpicLink: "PROJ-1", Blocks: []string{"PROJ-3"}, Extra: []Field{{Name: "Environment", Value: "staging"}},
+	}
+	if !reflect.DeepEqual(records[0], want) {
+		t.Errorf("Got %+v\nwant %+v", records[0], want)
+	}
+}
+
+func TestJSONReader(t *testing.T) {
+	export := `{"startAt": 0, "names": {"customfield_10020": "Sprint", "customfield_10030": "Story Points"},
+	"issues": [
+		{"id": "10001", "key": "PROJ-1", "fields": {
+			"summary": "Checkout", "issuetype": {"name": "Epic"}, "priority": {"name": "Medium"},
+			"status": {"name": "Done"}, "resolutiondate": "2024-03-01T10:00:00.000+0000",
+			"description": {"type": "doc", "content": [
+				{"type": "paragraph", "content": [{"type": "text", "text": "First line"}]},
+				{"type": "paragraph", "content": [{"type": "text", "text": "Second line"}]}]},
+			"assignee": {"displayName": "Ada Lovelace"},
+			"customfield_10020": [{"id": 1, "name": "Sprint 7", "boardId": 3}],
+			"customfield_10030": 5, "watches": null
+		}},
+		{"id": "10002", "key": "PROJ-2", "fields": {
+			"summary": "Pay", "issuetype": {"name": "Story"}, "parent": {"key": "PROJ-1"},
+			"issuelinks": [
+				{"type": {"name": "Blocks"}, "outwardIssue": {"key": "PROJ-3"}},
+				{"type": {"name": "Blocks"}, "inwardIssue": {"key": "PROJ-4"}},
+				{"type": {"name": "Relates"}, "outwardIssue": {"key": "PROJ-5"}}]
+		}}
+	], "total": 2}`
+	reader, err := NewJSONReader(strings.NewReader(export))
+	if err != nil {
+		t.Fatalf("NewJSONReader failed: %v", err)
+	}
+	records := readAll(t, reader)
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(records))
+	}
+	epic := records[0]
+	if epic.Type != "Epic" || epic.Status != "Done" || epic.Assignee != "Ada Lovelace" ||
+		epic.Description != "First line\nSecond line" || !reflect.DeepEqual(epic.Sprints, []string{"Sprint 7"}) {
+		t.Errorf("Unexpected epic record %+v", epic)
+	}
+	if !reflect.DeepEqual(epic.Extra, []Field{{Name: "Story Points", Value: "5"}}) {
+		t.Errorf("Expected only the story points kept verbatim, got %+v", epic.Extra)
+	}
+	story := records[1]
+	if story.Parent != "PROJ-1" || !reflect.DeepEqual(story.Blocks, []string{"PROJ-3"}) || !reflect.DeepEqual(story.BlockedBy, []string{"PROJ-4"}) {
+		t.Errorf("Unexpected story links %+v", story)
+	}
+
+	// A bare array of issues works too
+	reader, err = NewJSONReader(strings.NewReader(`[{"key": "A-1", "fields": {"summary": "One"}}]`))
+	if err != nil {
+		t.Fatalf("NewJSONReader failed: %v", err)
+	}
+	if records := readAll(t, reader); len(records) != 1 || records[0].Summary != "One" {
+		t.Errorf("Unexpected records %+v", records)
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	if format, err := DetectFormat("export.CSV"); err != nil || format != FormatCSV {
+		t.Errorf("DetectFormat(export.CSV) = %q, %v", format, err)
+	}
+	if _, err := DetectFormat("export.xml"); err == nil {
+		t.Error("Expected an error for an unknown extension")
+	}
+}
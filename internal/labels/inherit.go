@@ -0,0 +1,21 @@
+package labels
+
+import "strings"
+
+// InheritablePrefixes are the label prefixes an issue discovered during
+// another issue's execution inherits from it: where in the codebase the work is
+var InheritablePrefixes = []string{"area:", "component:"}
+
+// Inheritable returns the labels that have an inheritable prefix, in order
+func Inheritable(labels []string) []string {
+	var inherited []string
+	for _, label := range labels {
+		for _, prefix := range InheritablePrefixes {
+			if strings.HasPrefix(label, prefix) {
+				inherited = append(inherited, label)
+				break
+			}
+		}
+	}
+	return inherited
+}
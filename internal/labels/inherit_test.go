@@ -0,0 +1,17 @@
+package labels
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestInheritable(t *testing.T) {
+	got := Inheritable([]string{"area:storage", "discovered:blocker", "component:parser", "needs-review", "areas"})
+	want := []string{"area:storage", "component:parser"}
+	if !slices.Equal(got, want) {
+		t.Errorf("Inheritable() = %v, want %v", got, want)
+	}
+	if got := Inheritable(nil); len(got) != 0 {
+		t.Errorf("Inheritable(nil) = %v, want none", got)
+	}
+}
@@ -0,0 +1,309 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage/storagetest"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// client is a minimal MCP client talking to a Server over pipes
+type client struct {
+	t      *testing.T
+	in     *io.PipeWriter
+	out    *bufio.Scanner
+	nextID int
+	done   chan error
+}
+
+// startClient serves store and returns a client connected to it. The
+// server stops when the test ends.
+func startClient(t *testing.T, store Store) *client {
+	t.Helper()
+	serverIn, clientOut := io.Pipe()
+	clientIn, serverOut := io.Pipe()
+	c := &client{t: t, in: clientOut, out: bufio.NewScanner(clientIn), done: make(chan error, 1)}
+	c.out.Buffer(make([]byte, 64*1024), maxMessageSize)
+	go func() {
+		c.done <- NewServer(store, "test", "fallback").Serve(context.Background(), serverIn, serverOut)
+		serverOut.Close()
+	}()
+	t.Cleanup(func() {
+		clientOut.Close()
+		if err := <-c.done; err != nil {
+			t.Errorf("Serve: %v", err)
+		}
+	})
+	return c
+}
+
+// send writes a raw message
+func (c *client) send(message string) {
+	c.t.Helper()
+	if _, err := io.WriteString(c.in, message+"\n"); err != nil {
+		c.t.Fatalf("write: %v", err)
+	}
+}
+
+// call sends a request and returns its response
+func (c *client) call(method string, params interface{}) response {
+	c.t.Helper()
+	c.nextID++
+	body, err := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": c.nextID, "method": method, "params": params})
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	c.send(string(body))
+	if !c.out.Scan() {
+		c.t.Fatalf("%s: no response (%v)", method, c.out.Err())
+	}
+	var resp response
+	if err := json.Unmarshal(c.out.Bytes(), &resp); err != nil {
+		c.t.Fatalf("%s: invalid response %q: %v", method, c.out.Text(), err)
+	}
+	if string(resp.ID) != fmt.Sprint(c.nextID) {
+		c.t.Fatalf("%s: response ID %s, want %d", method, resp.ID, c.nextID)
+	}
+	return resp
+}
+
+// initialize performs the handshake as clientName
+func (c *client) initialize(clientName string) {
+	c.t.Helper()
+	resp := c.call("initialize", map[string]interface{}{
+		"protocolVersion": "2025-03-26",
+		"capabilities":    map[string]interface{}{},
+		"clientInfo":      map[string]string{"name": clientName, "version": "1"},
+	})
+	if resp.Error != nil {
+		c.t.Fatalf("initialize: %v", resp.Error)
+	}
+	c.send(`{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+}
+
+// toolResult is a tools/call result
+type toolResult struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	StructuredContent json.RawMessage `json:"structuredContent"`
+	IsError           bool            `json:"isError"`
+}
+
+// callTool calls a tool, failing the test on protocol errors
+func (c *client) callTool(name string, args map[string]interface{}) toolResult {
+	c.t.Helper()
+	resp := c.call("tools/call", map[string]interface{}{"name": name, "arguments": args})
+	if resp.Error != nil {
+		c.t.Fatalf("%s: protocol error %v", name, resp.Error)
+	}
+	raw, _ := json.Marshal(resp.Result)
+	var result toolResult
+	if err := json.Unmarshal(raw, &result); err != nil {
+		c.t.Fatalf("%s: invalid result %s: %v", name, raw, err)
+	}
+	return result
+}
+
+// mustCallTool calls a tool that must succeed and decodes its structured
+// content into out
+func (c *client) mustCallTool(name string, args map[string]interface{}, out interface{}) {
+	c.t.Helper()
+	result := c.callTool(name, args)
+	if result.IsError || len(result.Content) != 1 {
+		c.t.Fatalf("%s: got %+v", name, result)
+	}
+	if out != nil {
+		if err := json.Unmarshal(result.StructuredContent, out); err != nil {
+			c.t.Fatalf("%s: invalid structuredContent: %v", name, err)
+		}
+	}
+}
+
+// toolErrorOf calls a tool that must fail and returns its error
+func (c *client) toolErrorOf(name string, args map[string]interface{}) ToolError {
+	c.t.Helper()
+	result := c.callTool(name, args)
+	if !result.IsError {
+		c.t.Fatalf("%s: expected a tool error, got %s", name, result.StructuredContent)
+	}
+	var content struct {
+		Error ToolError `json:"error"`
+	}
+	if err := json.Unmarshal(result.StructuredContent, &content); err != nil {
+		c.t.Fatal(err)
+	}
+	return content.Error
+}
+
+func TestHandshakeAndToolList(t *testing.T) {
+	c := startClient(t, storagetest.NewFakeStorage())
+
+	if resp := c.call("tools/list", nil); resp.Error == nil || resp.Error.Code != codeInvalidRequest {
+		t.Errorf("tools/list before initialize: got %+v", resp)
+	}
+
+	resp := c.call("initialize", map[string]interface{}{"protocolVersion": "1999-01-01", "clientInfo": map[string]string{"name": "agent"}})
+	result, _ := resp.Result.(map[string]interface{})
+	if resp.Error != nil || result["protocolVersion"] != ProtocolVersions[0] {
+		t.Errorf("initialize with an unknown version: got %+v", resp)
+	}
+	if resp := c.call("ping", nil); resp.Error != nil {
+		t.Errorf("ping: %v", resp.Error)
+	}
+	if resp := c.call("resources/list", nil); resp.Error == nil || resp.Error.Code != codeMethodNotFound {
+		t.Errorf("resources/list: got %+v", resp)
+	}
+
+	resp = c.call("tools/list", nil)
+	raw, _ := json.Marshal(resp.Result)
+	var list struct {
+		Tools []struct {
+			Name        string                 `json:"name"`
+			Description string                 `json:"description"`
+			InputSchema map[string]interface{} `json:"inputSchema"`
+		} `json:"tools"`
+	}
+	if err := json.Unmarshal(raw, &list); err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, tool := range list.Tools {
+		names = append(names, tool.Name)
+		if tool.Description == "" || tool.InputSchema["type"] != "object" {
+			t.Errorf("Tool %s: incomplete definition %+v", tool.Name, tool)
+		}
+	}
+	want := "create_issue get_issue search_issues update_issue add_comment add_dependency list_ready_work get_agent_events"
+	if got := strings.Join(names, " "); got != want {
+		t.Errorf("tools/list: got %s, want %s", got, want)
+	}
+
+	if resp := c.call("tools/call", map[string]interface{}{"name": "delete_everything"}); resp.Error == nil || resp.Error.Code != codeInvalidParams {
+		t.Errorf("Unknown tool: got %+v", resp)
+	}
+	if resp := c.call("tools/call", map[string]interface{}{"name": "get_issue", "arguments": map[string]interface{}{"issue": "vc-1"}}); resp.Error == nil || resp.Error.Code != codeInvalidParams {
+		t.Errorf("Unknown argument: got %+v", resp)
+	}
+	c.send("{not json")
+	if !c.out.Scan() || !strings.Contains(c.out.Text(), fmt.Sprint(codeParseError)) {
+		t.Errorf("Malformed message: got %q", c.out.Text())
+	}
+}
+
+func TestIssueTools(t *testing.T) {
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+	c := startClient(t, store)
+	c.initialize("claude-desktop")
+
+	var created issueResult
+	c.mustCallTool("create_issue", map[string]interface{}{"title": "Parser crash", "type": "bug", "priority": 1, "labels": []string{"parser"}}, &created)
+	if created.Issue.ID != "vc-1" || created.Issue.IssueType != types.TypeBug || len(created.Labels) != 1 {
+		t.Fatalf("create_issue: got %+v", created)
+	}
+	c.mustCallTool("create_issue", map[string]interface{}{"title": "Lexer cleanup"}, nil)
+
+	c.mustCallTool("add_dependency", map[string]interface{}{"issue_id": "vc-1", "depends_on_id": "vc-2"}, nil)
+	c.mustCallTool("add_comment", map[string]interface{}{"id": "vc-1", "comment": "Reproduced on main"}, nil)
+
+	var got issueResult
+	c.mustCallTool("get_issue", map[string]interface{}{"id": "vc-1"}, &got)
+	if len(got.Dependencies) != 1 || got.Dependencies[0].DependsOnID != "vc-2" {
+		t.Errorf("get_issue: got %+v", got)
+	}
+
+	var ready issueListResult
+	c.mustCallTool("list_ready_work", nil, &ready)
+	if len(ready.Issues) != 1 || ready.Issues[0].ID != "vc-2" {
+		t.Errorf("list_ready_work: got %+v", ready)
+	}
+
+	var found issueListResult
+	c.mustCallTool("search_issues", map[string]interface{}{"query": "parser", "status": "open"}, &found)
+	if found.Total != 1 || len(found.Issues) != 1 || found.Issues[0].ID != "vc-1" {
+		t.Errorf("search_issues: got %+v", found)
+	}
+
+	var updated issueResult
+	c.mustCallTool("update_issue", map[string]interface{}{"id": "vc-2", "status": "closed", "reason": "Done", "notes": "Merged"}, &updated)
+	if updated.Issue.Status != types.StatusClosed || updated.Issue.Notes != "Merged" {
+		t.Errorf("update_issue: got %+v", updated.Issue)
+	}
+
+	// Writes are recorded under the client's name
+	history, err := store.GetEvents(ctx, "vc-1", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, event := range history {
+		if event.Actor != "claude-desktop" {
+			t.Errorf("Event %s by %q, want claude-desktop", event.EventType, event.Actor)
+		}
+	}
+	if len(history) < 2 {
+		t.Errorf("Expected create and comment events, got %d", len(history))
+	}
+}
+
+func TestToolErrors(t *testing.T) {
+	store := storagetest.NewFakeStorage()
+	c := startClient(t, store)
+	c.initialize("agent")
+	c.mustCallTool("create_issue", map[string]interface{}{"title": "Parser crash"}, nil)
+	c.mustCallTool("update_issue", map[string]interface{}{"id": "vc-1", "status": "blocked"}, nil)
+
+	tests := []struct {
+		name string
+		args map[string]interface{}
+		want string
+	}{
+		{"get_issue", map[string]interface{}{"id": "vc-99"}, ErrCodeNotFound},
+		{"update_issue", map[string]interface{}{"id": "vc-99", "title": "x"}, ErrCodeNotFound},
+		{"update_issue", map[string]interface{}{"id": "vc-1", "status": "in_progress"}, ErrCodeInvalidTransition},
+		{"update_issue", map[string]interface{}{"id": "vc-1", "status": "closed"}, ErrCodeInvalidArgument},
+		{"update_issue", map[string]interface{}{"id": "vc-1"}, ErrCodeInvalidArgument},
+		{"search_issues", map[string]interface{}{"status": "done"}, ErrCodeInvalidArgument},
+		{"add_dependency", map[string]interface{}{"issue_id": "vc-1", "depends_on_id": "vc-1", "type": "sometimes"}, ErrCodeInvalidArgument},
+		{"create_issue", map[string]interface{}{"title": "x", "project": "nope"}, ErrCodeInvalidArgument},
+	}
+	for _, tt := range tests {
+		if got := c.toolErrorOf(tt.name, tt.args); got.Code != tt.want || got.Message == "" {
+			t.Errorf("%s %v: got %+v, want code %s", tt.name, tt.args, got, tt.want)
+		}
+	}
+
+	store.FailOn("GetIssue", fmt.Errorf("disk I/O error"))
+	if got := c.toolErrorOf("get_issue", map[string]interface{}{"id": "vc-1"}); got.Code != ErrCodeInternal {
+		t.Errorf("Storage failure: got %+v", got)
+	}
+}
+
+func TestGetAgentEvents(t *testing.T) {
+	store := storagetest.NewFakeStorage()
+	for i, severity := range []events.EventSeverity{events.SeverityInfo, events.SeverityError} {
+		event := &events.AgentEvent{ID: fmt.Sprint(i), Type: events.EventTypeProgress, Timestamp: time.Now(), IssueID: "vc-1", Severity: severity, Message: "step"}
+		if err := store.StoreAgentEvent(context.Background(), event); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c := startClient(t, store)
+	c.initialize("agent")
+
+	var got struct {
+		Events []*events.AgentEvent `json:"events"`
+	}
+	c.mustCallTool("get_agent_events", map[string]interface{}{"issue_id": "vc-1", "severity": "error"}, &got)
+	if len(got.Events) != 1 || got.Events[0].Severity != events.SeverityError {
+		t.Errorf("get_agent_events: got %+v", got.Events)
+	}
+}
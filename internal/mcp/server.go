@@ -0,0 +1,208 @@
+// Package mcp serves vc's issue tracker as Model Context Protocol tools over
+// stdio (vc mcp), so agent sessions can create, query and update issues
+// without parsing CLI output. Messages are JSON-RPC 2.0, one per line.
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+)
+
+// ProtocolVersions are the MCP revisions the server speaks, newest first
+var ProtocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// JSON-RPC error codes
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+// maxMessageSize bounds one JSON-RPC message
+const maxMessageSize = 10 << 20
+
+// request is a JSON-RPC request or notification (no ID)
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// response is a JSON-RPC response
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// rpcError is a JSON-RPC error: the request itself was wrong, as opposed to
+// a tool that failed (see ToolError)
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return e.Message
+}
+
+// Server answers MCP requests with the tools in tools.go
+type Server struct {
+	store   Store
+	version string
+
+	mu           sync.Mutex
+	initialized  bool
+	actor        string // The client's name once initialized
+	defaultActor string
+}
+
+// NewServer returns a server over store. version is reported to clients;
+// defaultActor is the actor of writes when the client gives no name.
+func NewServer(store Store, version, defaultActor string) *Server {
+	if defaultActor == "" {
+		defaultActor = "mcp"
+	}
+	return &Server{store: store, version: version, defaultActor: defaultActor}
+}
+
+// Serve reads requests from r and writes responses to w until r ends or ctx
+// is done. Requests are answered in order.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxMessageSize)
+	enc := json.NewEncoder(w)
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		resp := s.handle(ctx, line)
+		if resp == nil {
+			continue
+		}
+		if err := enc.Encode(resp); err != nil {
+			return fmt.Errorf("failed to write response: %w", err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read request: %w", err)
+	}
+	return nil
+}
+
+// handle answers one message; notifications get no response
+func (s *Server) handle(ctx context.Context, message []byte) *response {
+	var req request
+	if err := json.Unmarshal(message, &req); err != nil {
+		return &response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{Code: codeParseError, Message: "parse error: " + err.Error()}}
+	}
+	if req.ID == nil {
+		// notifications/initialized and friends need no answer
+		return nil
+	}
+	result, err := s.dispatch(ctx, &req)
+	resp := &response{JSONRPC: "2.0", ID: req.ID}
+	if err != nil {
+		var rpcErr *rpcError
+		if !errors.As(err, &rpcErr) {
+			rpcErr = &rpcError{Code: codeInternalError, Message: err.Error()}
+		}
+		resp.Error = rpcErr
+	} else {
+		resp.Result = result
+	}
+	return resp
+}
+
+// dispatch runs a request's method
+func (s *Server) dispatch(ctx context.Context, req *request) (interface{}, error) {
+	if req.JSONRPC != "2.0" {
+		return nil, &rpcError{Code: codeInvalidRequest, Message: `jsonrpc must be "2.0"`}
+	}
+	switch req.Method {
+	case "initialize":
+		return s.initialize(req.Params)
+	case "ping":
+		return struct{}{}, nil
+	}
+
+	s.mu.Lock()
+	initialized := s.initialized
+	s.mu.Unlock()
+	if !initialized {
+		return nil, &rpcError{Code: codeInvalidRequest, Message: "server not initialized"}
+	}
+	switch req.Method {
+	case "tools/list":
+		return map[string]interface{}{"tools": toolList()}, nil
+	case "tools/call":
+		return s.callTool(ctx, req.Params)
+	}
+	return nil, &rpcError{Code: codeMethodNotFound, Message: "method not found: " + req.Method}
+}
+
+// initialize agrees on a protocol version and records the client's name as
+// the actor of its writes
+func (s *Server) initialize(params json.RawMessage) (interface{}, error) {
+	var p struct {
+		ProtocolVersion string `json:"protocolVersion"`
+		ClientInfo      struct {
+			Name string `json:"name"`
+		} `json:"clientInfo"`
+	}
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	version := ProtocolVersions[0]
+	if slices.Contains(ProtocolVersions, p.ProtocolVersion) {
+		version = p.ProtocolVersion
+	}
+
+	s.mu.Lock()
+	s.initialized = true
+	s.actor = p.ClientInfo.Name
+	s.mu.Unlock()
+
+	return map[string]interface{}{
+		"protocolVersion": version,
+		"capabilities":    map[string]interface{}{"tools": map[string]interface{}{}},
+		"serverInfo":      map[string]string{"name": "vc", "version": s.version},
+		"instructions":    "Tools for vc's issue tracker. Issue IDs look like vc-42. Writes are recorded under your client name.",
+	}, nil
+}
+
+// Actor is the actor writes are recorded as: the client's name, or the
+// default actor before a named client initialized
+func (s *Server) Actor() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.actor == "" {
+		return s.defaultActor
+	}
+	return s.actor
+}
+
+// decodeParams decodes request params into v
+func decodeParams(params json.RawMessage, v interface{}) error {
+	if len(params) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return &rpcError{Code: codeInvalidParams, Message: "invalid params: " + err.Error()}
+	}
+	return nil
+}
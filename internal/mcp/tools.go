@@ -0,0 +1,570 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// Store is the part of the storage the tools use
+type Store interface {
+	CreateIssueWithMetadata(ctx context.Context, issue *types.Issue, labels []string, deps []*types.Dependency, actor string) error
+	GetIssue(ctx context.Context, id string) (*types.Issue, error)
+	SearchIssues(ctx context.Context, query string, filter types.IssueFilter) ([]*types.Issue, error)
+	CountIssues(ctx context.Context, query string, filter types.IssueFilter) (int, error)
+	UpdateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string) error
+	CloseIssue(ctx context.Context, id string, reason string, actor string) error
+	AddComment(ctx context.Context, issueID, actor, comment string) error
+	AddDependency(ctx context.Context, dep *types.Dependency, actor string) error
+	GetLabels(ctx context.Context, issueID string) ([]string, error)
+	GetDependencyRecords(ctx context.Context, issueID string) ([]*types.Dependency, error)
+	GetReadyWork(ctx context.Context, filter types.WorkFilter) ([]*types.Issue, error)
+	GetAgentEvents(ctx context.Context, filter events.EventFilter) ([]*events.AgentEvent, error)
+}
+
+// Tool error codes, in ToolError.Code
+const (
+	ErrCodeNotFound          = "not_found"
+	ErrCodeInvalidArgument   = "invalid_argument"
+	ErrCodeInvalidTransition = "invalid_transition"
+	ErrCodeConflict          = "conflict"
+	ErrCodeInternal          = "internal"
+)
+
+// ToolError is a tool that failed. The client gets it as a tool result with
+// isError set, so the calling model can read it and react, and as
+// structuredContent {"error": {"code", "message"}}.
+type ToolError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *ToolError) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+// toolError classifies a storage error
+func toolError(err error) *ToolError {
+	var toolErr *ToolError
+	switch {
+	case errors.As(err, &toolErr):
+		return toolErr
+	case errors.Is(err, types.ErrIllegalTransition):
+		return &ToolError{Code: ErrCodeInvalidTransition, Message: err.Error()}
+	case errors.Is(err, types.ErrConflict), errors.Is(err, types.ErrAlreadyClaimed):
+		return &ToolError{Code: ErrCodeConflict, Message: err.Error()}
+	case strings.Contains(err.Error(), "not found"):
+		return &ToolError{Code: ErrCodeNotFound, Message: err.Error()}
+	case strings.Contains(err.Error(), "validation failed"), strings.Contains(err.Error(), "invalid"),
+		strings.Contains(err.Error(), "unknown project"), strings.Contains(err.Error(), "cycle"):
+		return &ToolError{Code: ErrCodeInvalidArgument, Message: err.Error()}
+	}
+	return &ToolError{Code: ErrCodeInternal, Message: err.Error()}
+}
+
+// invalidArgument returns an invalid_argument tool error
+func invalidArgument(format string, args ...interface{}) *ToolError {
+	return &ToolError{Code: ErrCodeInvalidArgument, Message: fmt.Sprintf(format, args...)}
+}
+
+// tool is one MCP tool
+type tool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	InputSchema json.RawMessage `json:"inputSchema"`
+	run         func(s *Server, ctx context.Context, args json.RawMessage) (interface{}, error)
+}
+
+// tools are the tools the server offers, in tools/list order
+var tools = []tool{
+	{
+		Name:        "create_issue",
+		Description: "Create an issue. Returns the issue with its new ID.",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"title": {"type": "string", "minLength": 1, "maxLength": 500},
+				"description": {"type": "string"},
+				"design": {"type": "string"},
+				"acceptance_criteria": {"type": "string"},
+				"priority": {"type": "integer", "minimum": 0, "maximum": 4, "default": 2, "description": "0 is the highest"},
+				"type": {"type": "string", "enum": ["bug", "feature", "task", "epic", "chore"], "default": "task"},
+				"assignee": {"type": "string"},
+				"labels": {"type": "array", "items": {"type": "string"}},
+				"project": {"type": "string", "description": "Project whose ID prefix to use (default: the default project)"}
+			},
+			"required": ["title"],
+			"additionalProperties": false
+		}`),
+		run: (*Server).createIssue,
+	},
+	{
+		Name:        "get_issue",
+		Description: "Get an issue with its labels and dependencies.",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {"id": {"type": "string", "description": "Issue ID, e.g. vc-42"}},
+			"required": ["id"],
+			"additionalProperties": false
+		}`),
+		run: (*Server).getIssue,
+	},
+	{
+		Name:        "search_issues",
+		Description: "Search issues by text and filters. Returns a page of issues and the total number of matches.",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"query": {"type": "string", "description": "Text in the title, description or ID"},
+				"status": {"type": "string", "enum": ["open", "in_progress", "blocked", "closed"]},
+				"priority": {"type": "integer", "minimum": 0, "maximum": 4},
+				"type": {"type": "string", "enum": ["bug", "feature", "task", "epic", "chore"]},
+				"assignee": {"type": "string"},
+				"labels": {"type": "array", "items": {"type": "string"}, "description": "Issues must have every label"},
+				"project": {"type": "string"},
+				"limit": {"type": "integer", "minimum": 1, "maximum": 200, "default": 50},
+				"offset": {"type": "integer", "minimum": 0}
+			},
+			"additionalProperties": false
+		}`),
+		run: (*Server).searchIssues,
+	},
+	{
+		Name:        "update_issue",
+		Description: "Update an issue's fields. Setting status to closed closes it and needs a reason. Returns the updated issue.",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"id": {"type": "string"},
+				"title": {"type": "string", "minLength": 1, "maxLength": 500},
+				"description": {"type": "string"},
+				"design": {"type": "string"},
+				"acceptance_criteria": {"type": "string"},
+				"notes": {"type": "string"},
+				"status": {"type": "string", "enum": ["open", "in_progress", "blocked", "closed"]},
+				"priority": {"type": "integer", "minimum": 0, "maximum": 4},
+				"assignee": {"type": "string"},
+				"reason": {"type": "string", "description": "Why the issue is closed (with status closed)"}
+			},
+			"required": ["id"],
+			"additionalProperties": false
+		}`),
+		run: (*Server).updateIssue,
+	},
+	{
+		Name:        "add_comment",
+		Description: "Add a comment to an issue's history.",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"id": {"type": "string"},
+				"comment": {"type": "string", "minLength": 1}
+			},
+			"required": ["id", "comment"],
+			"additionalProperties": false
+		}`),
+		run: (*Server).addComment,
+	},
+	{
+		Name:        "add_dependency",
+		Description: "Record that issue_id depends on depends_on_id. Only blocks dependencies hold issue_id back from ready work.",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"issue_id": {"type": "string"},
+				"depends_on_id": {"type": "string"},
+				"type": {"type": "string", "enum": ["blocks", "related", "parent-child", "discovered-from"], "default": "blocks"}
+			},
+			"required": ["issue_id", "depends_on_id"],
+			"additionalProperties": false
+		}`),
+		run: (*Server).addDependency,
+	},
+	{
+		Name:        "list_ready_work",
+		Description: "List open issues with nothing blocking them, in the order executors take them.",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"priority": {"type": "integer", "minimum": 0, "maximum": 4},
+				"assignee": {"type": "string"},
+				"project": {"type": "string"},
+				"limit": {"type": "integer", "minimum": 1, "maximum": 200, "default": 20}
+			},
+			"additionalProperties": false
+		}`),
+		run: (*Server).listReadyWork,
+	},
+	{
+		Name:        "get_agent_events",
+		Description: "Get recent agent events (what executors and their agents did), newest first.",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"issue_id": {"type": "string"},
+				"type": {"type": "string", "description": "Event type, e.g. error or file_modified"},
+				"severity": {"type": "string", "enum": ["info", "warning", "error", "critical"]},
+				"query": {"type": "string", "description": "Text in the message or data"},
+				"limit": {"type": "integer", "minimum": 1, "maximum": 500, "default": 50}
+			},
+			"additionalProperties": false
+		}`),
+		run: (*Server).getAgentEvents,
+	},
+}
+
+// toolList returns the tools for tools/list
+func toolList() []tool {
+	return tools
+}
+
+// findTool returns the named tool, or nil
+func findTool(name string) *tool {
+	for i := range tools {
+		if tools[i].Name == name {
+			return &tools[i]
+		}
+	}
+	return nil
+}
+
+// callTool runs a tools/call request. Unknown tools and malformed arguments
+// are protocol errors; a tool that fails returns an isError result.
+func (s *Server) callTool(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var p struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	t := findTool(p.Name)
+	if t == nil {
+		return nil, &rpcError{Code: codeInvalidParams, Message: "unknown tool: " + p.Name}
+	}
+	if len(p.Arguments) == 0 || string(p.Arguments) == "null" {
+		p.Arguments = json.RawMessage("{}")
+	}
+
+	result, err := t.run(s, ctx, p.Arguments)
+	if err != nil {
+		var rpcErr *rpcError
+		if errors.As(err, &rpcErr) {
+			return nil, err
+		}
+		toolErr := toolError(err)
+		return map[string]interface{}{
+			"content":           []map[string]string{{"type": "text", "text": toolErr.Error()}},
+			"structuredContent": map[string]interface{}{"error": toolErr},
+			"isError":           true,
+		}, nil
+	}
+	text, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s result: %w", p.Name, err)
+	}
+	return map[string]interface{}{
+		"content":           []map[string]string{{"type": "text", "text": string(text)}},
+		"structuredContent": result,
+	}, nil
+}
+
+// decodeArgs decodes tool arguments, rejecting unknown ones
+func decodeArgs(args json.RawMessage, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(args))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return &rpcError{Code: codeInvalidParams, Message: "invalid arguments: " + err.Error()}
+	}
+	return nil
+}
+
+// issueResult is the result of the tools returning one issue
+type issueResult struct {
+	Issue        *types.Issue        `json:"issue"`
+	Labels       []string            `json:"labels,omitempty"`
+	Dependencies []*types.Dependency `json:"dependencies,omitempty"`
+}
+
+// issueListResult is the result of the tools returning issues
+type issueListResult struct {
+	Issues []*types.Issue `json:"issues"`
+	Total  int            `json:"total,omitempty"`
+}
+
+func (s *Server) createIssue(ctx context.Context, args json.RawMessage) (interface{}, error) {
+	var a struct {
+		Title              string   `json:"title"`
+		Description        string   `json:"description"`
+		Design             string   `json:"design"`
+		AcceptanceCriteria string   `json:"acceptance_criteria"`
+		Priority           *int     `json:"priority"`
+		Type               string   `json:"type"`
+		Assignee           string   `json:"assignee"`
+		Labels             []string `json:"labels"`
+		Project            string   `json:"project"`
+	}
+	if err := decodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	issue := &types.Issue{
+		Title:              a.Title,
+		Description:        a.Description,
+		Design:             a.Design,
+		AcceptanceCriteria: a.AcceptanceCriteria,
+		Status:             types.StatusOpen,
+		Priority:           2,
+		IssueType:          types.TypeTask,
+		Assignee:           a.Assignee,
+		Project:            a.Project,
+	}
+	if a.Priority != nil {
+		issue.Priority = *a.Priority
+	}
+	if a.Type != "" {
+		issue.IssueType = types.IssueType(a.Type)
+	}
+	if err := s.store.CreateIssueWithMetadata(ctx, issue, a.Labels, nil, s.Actor()); err != nil {
+		return nil, err
+	}
+	return s.issueResult(ctx, issue.ID)
+}
+
+func (s *Server) getIssue(ctx context.Context, args json.RawMessage) (interface{}, error) {
+	var a struct {
+		ID string `json:"id"`
+	}
+	if err := decodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	return s.issueResult(ctx, a.ID)
+}
+
+// issueResult reads an issue with its labels and dependencies
+func (s *Server) issueResult(ctx context.Context, id string) (*issueResult, error) {
+	issue, err := s.store.GetIssue(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if issue == nil {
+		return nil, &ToolError{Code: ErrCodeNotFound, Message: fmt.Sprintf("issue %s not found", id)}
+	}
+	labels, err := s.store.GetLabels(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	deps, err := s.store.GetDependencyRecords(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &issueResult{Issue: issue, Labels: labels, Dependencies: deps}, nil
+}
+
+func (s *Server) searchIssues(ctx context.Context, args json.RawMessage) (interface{}, error) {
+	var a struct {
+		Query    string   `json:"query"`
+		Status   string   `json:"status"`
+		Priority *int     `json:"priority"`
+		Type     string   `json:"type"`
+		Assignee string   `json:"assignee"`
+		Labels   []string `json:"labels"`
+		Project  string   `json:"project"`
+		Limit    int      `json:"limit"`
+		Offset   int      `json:"offset"`
+	}
+	if err := decodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	filter := types.IssueFilter{Priority: a.Priority, Labels: a.Labels, Project: a.Project, Limit: a.Limit, Offset: a.Offset}
+	if filter.Limit <= 0 || filter.Limit > 200 {
+		filter.Limit = 50
+	}
+	if a.Status != "" {
+		status := types.Status(a.Status)
+		if !status.IsValid() {
+			return nil, invalidArgument("invalid status %q", a.Status)
+		}
+		filter.Status = &status
+	}
+	if a.Type != "" {
+		issueType := types.IssueType(a.Type)
+		if !issueType.IsValid() {
+			return nil, invalidArgument("invalid type %q", a.Type)
+		}
+		filter.IssueType = &issueType
+	}
+	if a.Assignee != "" {
+		filter.Assignee = &a.Assignee
+	}
+	issues, err := s.store.SearchIssues(ctx, a.Query, filter)
+	if err != nil {
+		return nil, err
+	}
+	total, err := s.store.CountIssues(ctx, a.Query, filter)
+	if err != nil {
+		return nil, err
+	}
+	if issues == nil {
+		issues = []*types.Issue{}
+	}
+	return &issueListResult{Issues: issues, Total: total}, nil
+}
+
+func (s *Server) updateIssue(ctx context.Context, args json.RawMessage) (interface{}, error) {
+	var a struct {
+		ID                 string  `json:"id"`
+		Title              *string `json:"title"`
+		Description        *string `json:"description"`
+		Design             *string `json:"design"`
+		AcceptanceCriteria *string `json:"acceptance_criteria"`
+		Notes              *string `json:"notes"`
+		Status             *string `json:"status"`
+		Priority           *int    `json:"priority"`
+		Assignee           *string `json:"assignee"`
+		Reason             string  `json:"reason"`
+	}
+	if err := decodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	updates := make(map[string]interface{})
+	for key, value := range map[string]*string{
+		"title":               a.Title,
+		"description":         a.Description,
+		"design":              a.Design,
+		"acceptance_criteria": a.AcceptanceCriteria,
+		"notes":               a.Notes,
+		"assignee":            a.Assignee,
+	} {
+		if value != nil {
+			updates[key] = *value
+		}
+	}
+	if a.Priority != nil {
+		updates["priority"] = *a.Priority
+	}
+	closing := a.Status != nil && types.Status(*a.Status) == types.StatusClosed
+	if a.Status != nil && !closing {
+		updates["status"] = *a.Status
+	}
+	if closing && a.Reason == "" {
+		return nil, invalidArgument("closing an issue needs a reason")
+	}
+	if len(updates) == 0 && !closing {
+		return nil, invalidArgument("nothing to update")
+	}
+
+	if len(updates) > 0 {
+		if err := s.store.UpdateIssue(ctx, a.ID, updates, s.Actor()); err != nil {
+			return nil, err
+		}
+	}
+	if closing {
+		if err := s.store.CloseIssue(ctx, a.ID, a.Reason, s.Actor()); err != nil {
+			return nil, err
+		}
+	}
+	return s.issueResult(ctx, a.ID)
+}
+
+func (s *Server) addComment(ctx context.Context, args json.RawMessage) (interface{}, error) {
+	var a struct {
+		ID      string `json:"id"`
+		Comment string `json:"comment"`
+	}
+	if err := decodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(a.Comment) == "" {
+		return nil, invalidArgument("comment is empty")
+	}
+	if err := s.store.AddComment(ctx, a.ID, s.Actor(), a.Comment); err != nil {
+		return nil, err
+	}
+	return map[string]string{"issue_id": a.ID, "status": "commented"}, nil
+}
+
+func (s *Server) addDependency(ctx context.Context, args json.RawMessage) (interface{}, error) {
+	var a struct {
+		IssueID     string `json:"issue_id"`
+		DependsOnID string `json:"depends_on_id"`
+		Type        string `json:"type"`
+	}
+	if err := decodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	dep := &types.Dependency{IssueID: a.IssueID, DependsOnID: a.DependsOnID, Type: types.DepBlocks}
+	if a.Type != "" {
+		dep.Type = types.DependencyType(a.Type)
+	}
+	if !dep.Type.IsValid() {
+		return nil, invalidArgument("invalid dependency type %q", a.Type)
+	}
+	if err := s.store.AddDependency(ctx, dep, s.Actor()); err != nil {
+		return nil, err
+	}
+	return dep, nil
+}
+
+func (s *Server) listReadyWork(ctx context.Context, args json.RawMessage) (interface{}, error) {
+	var a struct {
+		Priority *int   `json:"priority"`
+		Assignee string `json:"assignee"`
+		Project  string `json:"project"`
+		Limit    int    `json:"limit"`
+	}
+	if err := decodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	filter := types.WorkFilter{Status: types.StatusOpen, Priority: a.Priority, Project: a.Project, Limit: a.Limit}
+	if filter.Limit <= 0 || filter.Limit > 200 {
+		filter.Limit = 20
+	}
+	if a.Assignee != "" {
+		filter.Assignee = &a.Assignee
+	}
+	issues, err := s.store.GetReadyWork(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if issues == nil {
+		issues = []*types.Issue{}
+	}
+	return &issueListResult{Issues: issues}, nil
+}
+
+func (s *Server) getAgentEvents(ctx context.Context, args json.RawMessage) (interface{}, error) {
+	var a struct {
+		IssueID  string `json:"issue_id"`
+		Type     string `json:"type"`
+		Severity string `json:"severity"`
+		Query    string `json:"query"`
+		Limit    int    `json:"limit"`
+	}
+	if err := decodeArgs(args, &a); err != nil {
+		return nil, err
+	}
+	filter := events.EventFilter{
+		IssueID:         a.IssueID,
+		Type:            events.EventType(a.Type),
+		Severity:        events.EventSeverity(a.Severity),
+		MessageContains: a.Query,
+		Limit:           a.Limit,
+	}
+	if filter.Limit <= 0 || filter.Limit > 500 {
+		filter.Limit = 50
+	}
+	list, err := s.store.GetAgentEvents(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if list == nil {
+		list = []*events.AgentEvent{}
+	}
+	return map[string]interface{}{"events": list}, nil
+}
@@ -87,7 +87,7 @@ func (o *Orchestrator) ApprovePlan(ctx context.Context, missionID string, approv
 		"approved_at": now,
 		"approved_by": approvedBy,
 	}
-	if err := o.store.UpdateIssue(ctx, missionID, updates, approvedBy); err != nil {
+	if err := o.store.UpdateMission(ctx, missionID, updates, approvedBy); err != nil {
 		return fmt.Errorf("failed to update approval metadata: %w", err)
 	}
 
@@ -233,6 +233,16 @@ func (o *Orchestrator) ProcessMission(ctx context.Context, mission *types.Missio
 		return result, fmt.Errorf("failed to create phases: %w", err)
 	}
 
+	// Record the plan's size in mission state; executors run the phases
+	// from the first one on
+	if err := o.store.UpdateMissionState(ctx, mission.ID, map[string]interface{}{
+		"phase_count":   len(phaseIDs),
+		"current_phase": 0,
+	}); err != nil {
+		// Non-fatal, the executor recounts phases as it goes
+		fmt.Printf("Warning: failed to record phase count: %v\n", err)
+	}
+
 	// Add comment documenting phase creation
 	comment := fmt.Sprintf("Created %d phases from approved plan: %v", len(phaseIDs), phaseIDs)
 	if err := o.store.AddComment(ctx, mission.ID, actor, comment); err != nil {
@@ -2,9 +2,11 @@ package mission
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
+	"github.com/steveyegge/vc/internal/storage/storagetest"
 	"github.com/steveyegge/vc/internal/types"
 )
 
@@ -12,19 +14,25 @@ import (
 func TestCreatePhasesFromPlan_Rollback(t *testing.T) {
 	ctx := context.Background()
 
-	// Create mock storage that will fail on the 3rd issue
-	store := NewMockStorage()
-	store.failOnIssueID = "test-3" // Fail when creating 3rd phase
+	// Create a store that will fail on the 3rd issue
+	store := storagetest.NewFakeStorage()
+	creates := 0
+	store.FailWhen("CreateIssue", func(args []interface{}) error {
+		creates++
+		if creates == 3 { // Fail when creating 3rd phase
+			return errors.New("simulated create issue failure")
+		}
+		return nil
+	})
 
 	// Add mission to store (required for priority inheritance)
-	mission := &types.Issue{
+	store.AddIssue(&types.Issue{
 		ID:        "mission-1",
 		Title:     "Test Mission",
 		IssueType: types.TypeEpic,
 		Status:    types.StatusOpen,
 		Priority:  0,
-	}
-	store.issues["mission-1"] = mission
+	})
 
 	planner := &MockPlanner{}
 	orchestrator, err := NewOrchestrator(&Config{
@@ -85,13 +93,18 @@ func TestCreatePhasesFromPlan_Rollback(t *testing.T) {
 	}
 
 	// Verify previously created phases were closed (rollback)
-	if len(store.closedIssues) != 2 {
-		t.Errorf("Expected 2 phases to be closed during rollback, got %d: %v", len(store.closedIssues), store.closedIssues)
+	if n := store.CallCount("CloseIssue"); n != 2 {
+		t.Errorf("Expected 2 phases to be closed during rollback, got %d", n)
 	}
 
 	// Verify no open phases remain (only mission should be left)
-	if len(store.issues) != 1 {
-		t.Errorf("Expected 1 issue after rollback (mission only), got %d", len(store.issues))
+	open := types.StatusOpen
+	openIssues, err := store.SearchIssues(ctx, "", types.IssueFilter{Status: &open})
+	if err != nil {
+		t.Fatalf("Failed to search issues: %v", err)
+	}
+	if len(openIssues) != 1 || openIssues[0].ID != "mission-1" {
+		t.Errorf("Expected only the mission open after rollback, got %d issues", len(openIssues))
 	}
 }
 
@@ -99,19 +112,25 @@ func TestCreatePhasesFromPlan_Rollback(t *testing.T) {
 func TestCreatePhasesFromPlan_RollbackOnDependencyFailure(t *testing.T) {
 	ctx := context.Background()
 
-	// Create mock storage that will fail on 3rd AddDependency call
-	store := NewMockStorage()
-	store.failOnDepCount = 3 // Fail after 3 dependency calls
+	// Create a store that will fail on the 3rd AddDependency call
+	store := storagetest.NewFakeStorage()
+	depCalls := 0
+	store.FailWhen("AddDependency", func(args []interface{}) error {
+		depCalls++
+		if depCalls >= 3 { // Fail after 3 dependency calls
+			return errors.New("simulated add dependency failure")
+		}
+		return nil
+	})
 
 	// Add mission to store (required for priority inheritance)
-	mission := &types.Issue{
+	store.AddIssue(&types.Issue{
 		ID:        "mission-1",
 		Title:     "Test Mission",
 		IssueType: types.TypeEpic,
 		Status:    types.StatusOpen,
 		Priority:  0,
-	}
-	store.issues["mission-1"] = mission
+	})
 
 	planner := &MockPlanner{}
 	orchestrator, err := NewOrchestrator(&Config{
@@ -164,7 +183,7 @@ func TestCreatePhasesFromPlan_RollbackOnDependencyFailure(t *testing.T) {
 	}
 
 	// Verify created phases were closed (rollback)
-	if len(store.closedIssues) == 0 {
+	if store.CallCount("CloseIssue") == 0 {
 		t.Error("Expected phases to be closed during rollback, got 0")
 	}
 }
@@ -252,12 +252,12 @@ func (c *ConversationHandler) getTools() []anthropic.ToolUnionParam {
 		},
 		{
 			Name:        "add_child_to_epic",
-			Description: anthropic.String("Add an issue as a child of an epic with parent-child dependency. Optionally mark it as blocking."),
+			Description: anthropic.String("Add an issue as a child of an epic. Blocking children (the default) must close before the epic is complete; non-blocking children are only related to it."),
 			InputSchema: anthropic.ToolInputSchemaParam{
 				Properties: map[string]interface{}{
 					"epic_id":        map[string]interface{}{"type": "string", "description": "Epic ID (required)"},
 					"child_issue_id": map[string]interface{}{"type": "string", "description": "Child issue ID (required)"},
-					"blocks":         map[string]interface{}{"type": "boolean", "description": "Whether this child blocks the epic (default: true)"},
+					"blocks":         map[string]interface{}{"type": "boolean", "description": "Whether the epic waits for this child to close (default: true)"},
 				},
 				Required: []string{"epic_id", "child_issue_id"},
 			},
@@ -577,7 +577,10 @@ func (c *ConversationHandler) toolCreateEpic(ctx context.Context, input map[stri
 }
 
 // toolAddChildToEpic links an issue as a child of an epic.
-// Creates parent-child dependency and optionally a blocks relationship.
+// A blocking child gets a parent-child dependency, which holds the epic open
+// until the child closes (see IsEpicComplete). A non-blocking child is only
+// related to the epic. A reverse "epic blocked by child" edge is never added:
+// together with the parent-child edge it forms a cycle the store rejects.
 // Input: epic_id (required), child_issue_id (required), blocks (default: true)
 // Returns: Formatted success message with both IDs
 func (c *ConversationHandler) toolAddChildToEpic(ctx context.Context, input map[string]interface{}) (string, error) {
@@ -592,34 +595,20 @@ func (c *ConversationHandler) toolAddChildToEpic(ctx context.Context, input map[
 		blocks = b
 	}
 
-	// Always create parent-child relationship: child belongs to epic
-	parentChildDep := &types.Dependency{
+	depType := types.DepParentChild
+	if !blocks {
+		depType = types.DepRelated
+	}
+	dep := &types.Dependency{
 		IssueID:     childID, // child depends on parent
 		DependsOnID: epicID,
-		Type:        types.DepParentChild,
+		Type:        depType,
 		CreatedAt:   time.Now(),
 		CreatedBy:   AIActor,
 	}
 
-	err := c.storage.AddDependency(ctx, parentChildDep, AIActor)
-	if err != nil {
-		return "", fmt.Errorf("failed to add parent-child dependency: %w", err)
-	}
-
-	// If blocks=true, also create blocks relationship: epic blocked by child
-	if blocks {
-		blocksDep := &types.Dependency{
-			IssueID:     epicID, // epic depends on child
-			DependsOnID: childID,
-			Type:        types.DepBlocks,
-			CreatedAt:   time.Now(),
-			CreatedBy:   AIActor,
-		}
-
-		err = c.storage.AddDependency(ctx, blocksDep, AIActor)
-		if err != nil {
-			return "", fmt.Errorf("failed to add blocks dependency: %w", err)
-		}
+	if err := c.storage.AddDependency(ctx, dep, AIActor); err != nil {
+		return "", fmt.Errorf("failed to add %s dependency: %w", depType, err)
 	}
 
 	return fmt.Sprintf("Added %s as child of epic %s (blocks=%v)", childID, epicID, blocks), nil
@@ -671,6 +660,9 @@ func (c *ConversationHandler) toolGetIssue(ctx context.Context, input map[string
 	if err != nil {
 		return "", fmt.Errorf("failed to get issue: %w", err)
 	}
+	if issue == nil {
+		return "", fmt.Errorf("issue %s not found", issueID)
+	}
 
 	data, err := json.MarshalIndent(issue, "", "  ")
 	if err != nil {
@@ -877,6 +869,9 @@ func (c *ConversationHandler) toolContinueExecution(ctx context.Context, input m
 		if err != nil {
 			return "", fmt.Errorf("failed to get issue %s: %w", issueID, err)
 		}
+		if issue == nil {
+			return "", fmt.Errorf("issue %s not found", issueID)
+		}
 
 		// Validate issue can be executed
 		if errMsg, err := c.validateIssueForExecution(ctx, issue); err != nil {
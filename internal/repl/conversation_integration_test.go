@@ -2,104 +2,22 @@ package repl
 
 import (
 	"context"
-	"fmt"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage/storagetest"
 	"github.com/steveyegge/vc/internal/types"
 )
 
-// mockStorageIntegration is an enhanced mock for integration tests
-type mockStorageIntegration struct {
-	mockStorage
-	createdIssues    []*types.Issue
-	readyWork        []*types.Issue
-	claimedIssues    map[string]string
-	executionStates  map[string]types.ExecutionState
-	addedDeps        []*types.Dependency
-	comments         map[string][]string
-}
-
-func (m *mockStorageIntegration) CreateIssue(ctx context.Context, issue *types.Issue, actor string) error {
-	// Auto-generate ID
-	if issue.ID == "" {
-		issue.ID = "vc-" + string(rune('1'+len(m.createdIssues)))
-	}
-	m.createdIssues = append(m.createdIssues, issue)
-	if m.issues == nil {
-		m.issues = make(map[string]*types.Issue)
-	}
-	m.issues[issue.ID] = issue
-	return nil
-}
-
-func (m *mockStorageIntegration) CreateMission(ctx context.Context, mission *types.Mission, actor string) error {
-	// Generate a simple ID for mission-based issues
-	return m.CreateIssue(ctx, &mission.Issue, actor)
-}
-
-func (m *mockStorageIntegration) GetReadyWork(ctx context.Context, filter types.WorkFilter) ([]*types.Issue, error) {
-	if m.readyWork != nil {
-		result := m.readyWork
-		if filter.Limit > 0 && len(result) > filter.Limit {
-			result = result[:filter.Limit]
-		}
-		return result, nil
-	}
-	return nil, nil
-}
-
-func (m *mockStorageIntegration) IsEpicComplete(ctx context.Context, epicID string) (bool, error) {
-	return false, nil
-}
-
-func (m *mockStorageIntegration) GetMissionForTask(ctx context.Context, taskID string) (*types.MissionContext, error) {
-	return nil, fmt.Errorf("not implemented in mock")
-}
-
-func (m *mockStorageIntegration) ClaimIssue(ctx context.Context, issueID, executorInstanceID string) error {
-	if m.claimedIssues == nil {
-		m.claimedIssues = make(map[string]string)
-	}
-	m.claimedIssues[issueID] = executorInstanceID
-	return nil
-}
-
-func (m *mockStorageIntegration) UpdateExecutionState(ctx context.Context, issueID string, state types.ExecutionState) error {
-	if m.executionStates == nil {
-		m.executionStates = make(map[string]types.ExecutionState)
-	}
-	m.executionStates[issueID] = state
-	return nil
-}
-
-func (m *mockStorageIntegration) ReleaseIssue(ctx context.Context, issueID string) error {
-	if m.claimedIssues != nil {
-		delete(m.claimedIssues, issueID)
-	}
-	return nil
-}
-
-func (m *mockStorageIntegration) ReleaseIssueAndReopen(ctx context.Context, issueID, actor, errorComment string) error {
-	if m.claimedIssues != nil {
-		delete(m.claimedIssues, issueID)
+// createdIssues returns the issues passed to CreateIssue, in call order
+func createdIssues(store *storagetest.FakeStorage) []*types.Issue {
+	var issues []*types.Issue
+	for _, call := range store.Calls("CreateIssue") {
+		issues = append(issues, call.Args[0].(*types.Issue))
 	}
-	return nil
-}
-
-func (m *mockStorageIntegration) AddDependency(ctx context.Context, dep *types.Dependency, actor string) error {
-	m.addedDeps = append(m.addedDeps, dep)
-	return nil
-}
-
-func (m *mockStorageIntegration) AddComment(ctx context.Context, issueID, actor, comment string) error {
-	if m.comments == nil {
-		m.comments = make(map[string][]string)
-	}
-	m.comments[issueID] = append(m.comments[issueID], comment)
-	return nil
+	return issues
 }
 
 // TestConversationalFlows tests end-to-end conversation scenarios
@@ -109,8 +27,8 @@ func TestConversationalFlows(t *testing.T) {
 	// For full conversation testing, see manual test scenarios in docs.
 
 	t.Run("create issue flow", func(t *testing.T) {
-		mock := &mockStorageIntegration{}
-		handler := &ConversationHandler{storage: mock}
+		store := storagetest.NewFakeStorage()
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		// Simulate: User says "Add Docker support"
@@ -128,31 +46,29 @@ func TestConversationalFlows(t *testing.T) {
 			t.Errorf("Expected feature creation, got: %s", result)
 		}
 
-		if len(mock.createdIssues) != 1 {
-			t.Errorf("Expected 1 issue created, got %d", len(mock.createdIssues))
+		if len(createdIssues(store)) != 1 {
+			t.Errorf("Expected 1 issue created, got %d", len(createdIssues(store)))
 		}
 	})
 
 	t.Run("check ready work flow", func(t *testing.T) {
-		mock := &mockStorageIntegration{
-			readyWork: []*types.Issue{
-				{
-					ID:        "vc-1",
-					Title:     "Fix login bug",
-					IssueType: types.TypeBug,
-					Priority:  0,
-					Status:    types.StatusOpen,
-				},
-				{
-					ID:        "vc-2",
-					Title:     "Add logging",
-					IssueType: types.TypeTask,
-					Priority:  2,
-					Status:    types.StatusOpen,
-				},
+		store := newTestStore([]*types.Issue{
+			{
+				ID:        "vc-1",
+				Title:     "Fix login bug",
+				IssueType: types.TypeBug,
+				Priority:  0,
+				Status:    types.StatusOpen,
 			},
-		}
-		handler := &ConversationHandler{storage: mock}
+			{
+				ID:        "vc-2",
+				Title:     "Add logging",
+				IssueType: types.TypeTask,
+				Priority:  2,
+				Status:    types.StatusOpen,
+			},
+		}...)
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		// Simulate: User says "What's ready to work on?"
@@ -169,20 +85,14 @@ func TestConversationalFlows(t *testing.T) {
 	})
 
 	t.Run("check blocked issues flow", func(t *testing.T) {
-		mock := &mockStorageIntegration{}
-		mock.blockedIssues = []*types.BlockedIssue{
-			{
-				Issue: types.Issue{
-					ID:        "vc-10",
-					Title:     "Deploy to prod",
-					IssueType: types.TypeTask,
-					Priority:  1,
-				},
-				BlockedByCount: 2,
-				BlockedBy:      []string{"vc-8", "vc-9"},
-			},
-		}
-		handler := &ConversationHandler{storage: mock}
+		store := newTestStore(
+			&types.Issue{ID: "vc-8", Title: "Run migrations", Status: types.StatusOpen, IssueType: types.TypeTask, Priority: 1},
+			&types.Issue{ID: "vc-9", Title: "Update configs", Status: types.StatusOpen, IssueType: types.TypeTask, Priority: 1},
+			&types.Issue{ID: "vc-10", Title: "Deploy to prod", Status: types.StatusOpen, IssueType: types.TypeTask, Priority: 1},
+		)
+		addBlocker(t, store, "vc-10", "vc-8")
+		addBlocker(t, store, "vc-10", "vc-9")
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		// Simulate: User says "What's blocked?"
@@ -202,17 +112,13 @@ func TestConversationalFlows(t *testing.T) {
 	})
 
 	t.Run("check project status flow", func(t *testing.T) {
-		mock := &mockStorageIntegration{}
-		mock.statistics = &types.Statistics{
-			TotalIssues:      50,
-			OpenIssues:       20,
-			InProgressIssues: 5,
-			BlockedIssues:    3,
-			ClosedIssues:     22,
-			ReadyIssues:      12,
-			AverageLeadTime:  4.5,
-		}
-		handler := &ConversationHandler{storage: mock}
+		store := newTestStore(
+			&types.Issue{ID: "vc-1", Title: "Ready", Status: types.StatusOpen, IssueType: types.TypeTask},
+			&types.Issue{ID: "vc-2", Title: "Also ready", Status: types.StatusOpen, IssueType: types.TypeTask},
+			&types.Issue{ID: "vc-3", Title: "Working", Status: types.StatusInProgress, IssueType: types.TypeTask},
+			&types.Issue{ID: "vc-4", Title: "Done", Status: types.StatusClosed, IssueType: types.TypeTask},
+		)
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		// Simulate: User says "How's the project doing?"
@@ -223,17 +129,16 @@ func TestConversationalFlows(t *testing.T) {
 			t.Fatalf("Failed to get status: %v", err)
 		}
 
-		if !strings.Contains(result, "Total Issues: 50") {
+		if !strings.Contains(result, "Total Issues: 4") {
 			t.Errorf("Expected total in result, got: %s", result)
 		}
-		if !strings.Contains(result, "Ready to Work: 12") {
+		if !strings.Contains(result, "Ready to Work: 2") {
 			t.Errorf("Expected ready count in result, got: %s", result)
 		}
 	})
 
 	t.Run("search issues flow", func(t *testing.T) {
-		mock := &mockStorageIntegration{}
-		mock.searchResults = []*types.Issue{
+		store := newTestStore([]*types.Issue{
 			{
 				ID:          "vc-15",
 				Title:       "Fix authentication bug",
@@ -242,8 +147,8 @@ func TestConversationalFlows(t *testing.T) {
 				Priority:    0,
 				Status:      types.StatusOpen,
 			},
-		}
-		handler := &ConversationHandler{storage: mock}
+		}...)
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		// Simulate: User says "Show me issues about authentication"
@@ -265,8 +170,8 @@ func TestConversationalFlows(t *testing.T) {
 	})
 
 	t.Run("create epic with children flow", func(t *testing.T) {
-		mock := &mockStorageIntegration{}
-		handler := &ConversationHandler{storage: mock}
+		store := storagetest.NewFakeStorage()
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		// Simulate: User says "Build a payment system"
@@ -285,11 +190,11 @@ func TestConversationalFlows(t *testing.T) {
 		}
 
 		// Verify epic was created
-		if len(mock.createdIssues) != 1 {
-			t.Fatalf("Expected 1 epic created, got %d", len(mock.createdIssues))
+		if len(createdIssues(store)) != 1 {
+			t.Fatalf("Expected 1 epic created, got %d", len(createdIssues(store)))
 		}
 
-		epicID := mock.createdIssues[0].ID
+		epicID := createdIssues(store)[0].ID
 
 		// AI then creates child issues
 		_, err = handler.toolCreateIssue(ctx, map[string]interface{}{
@@ -300,7 +205,7 @@ func TestConversationalFlows(t *testing.T) {
 			t.Fatalf("Failed to create child issue: %v", err)
 		}
 
-		childID := mock.createdIssues[1].ID
+		childID := createdIssues(store)[1].ID
 
 		// AI adds child to epic
 		addResult, err := handler.toolAddChildToEpic(ctx, map[string]interface{}{
@@ -318,15 +223,19 @@ func TestConversationalFlows(t *testing.T) {
 		}
 
 		// Verify dependency was created
-		if len(mock.addedDeps) < 1 {
-			t.Errorf("Expected at least 1 dependency added, got %d", len(mock.addedDeps))
+		dependents, err := store.GetDependents(ctx, epicID)
+		if err != nil {
+			t.Fatalf("Failed to get epic dependents: %v", err)
+		}
+		if len(dependents) != 1 || dependents[0].ID != childID {
+			t.Errorf("Expected %s to depend on epic %s, got %v", childID, epicID, dependents)
 		}
 	})
 
 	t.Run("recent activity flow", func(t *testing.T) {
 		now := time.Now()
-		mock := &mockStorageIntegration{}
-		mock.agentEvents = []*events.AgentEvent{
+		store := storagetest.NewFakeStorage()
+		storeAgentEvents(t, store, []*events.AgentEvent{
 			{
 				ID:        "evt-1",
 				Type:      events.EventTypeAgentSpawned,
@@ -343,8 +252,8 @@ func TestConversationalFlows(t *testing.T) {
 				Severity:  events.SeverityInfo,
 				Message:   "Completed authentication feature",
 			},
-		}
-		handler := &ConversationHandler{storage: mock}
+		})
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		// Simulate: User says "What's been happening?"
@@ -364,9 +273,8 @@ func TestConversationalFlows(t *testing.T) {
 	})
 
 	t.Run("get issue details flow", func(t *testing.T) {
-		mock := &mockStorageIntegration{}
-		mock.issues = map[string]*types.Issue{
-			"vc-42": {
+		store := newTestStore([]*types.Issue{
+			{
 				ID:                 "vc-42",
 				Title:              "Implement caching layer",
 				Description:        "Add Redis caching",
@@ -376,8 +284,8 @@ func TestConversationalFlows(t *testing.T) {
 				Priority:           1,
 				Status:             types.StatusOpen,
 			},
-		}
-		handler := &ConversationHandler{storage: mock}
+		}...)
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		// Simulate: User says "Tell me about vc-42"
@@ -405,8 +313,8 @@ func TestMultiTurnContextPreservation(t *testing.T) {
 	// Real conversation context is handled by the Anthropic API
 
 	t.Run("create then view issue", func(t *testing.T) {
-		mock := &mockStorageIntegration{}
-		handler := &ConversationHandler{storage: mock}
+		store := storagetest.NewFakeStorage()
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		// Turn 1: Create issue
@@ -419,10 +327,10 @@ func TestMultiTurnContextPreservation(t *testing.T) {
 			t.Fatalf("Failed to create issue: %v", err)
 		}
 
-		if len(mock.createdIssues) != 1 {
-			t.Fatalf("Expected 1 issue created, got %d", len(mock.createdIssues))
+		if len(createdIssues(store)) != 1 {
+			t.Fatalf("Expected 1 issue created, got %d", len(createdIssues(store)))
 		}
-		issueID := mock.createdIssues[0].ID
+		issueID := createdIssues(store)[0].ID
 
 		// Turn 2: View the created issue
 		viewResult, err := handler.toolGetIssue(ctx, map[string]interface{}{
@@ -443,8 +351,7 @@ func TestMultiTurnContextPreservation(t *testing.T) {
 	})
 
 	t.Run("search then get specific issue", func(t *testing.T) {
-		mock := &mockStorageIntegration{}
-		mock.searchResults = []*types.Issue{
+		store := newTestStore([]*types.Issue{
 			{
 				ID:          "vc-20",
 				Title:       "Database migration",
@@ -453,11 +360,8 @@ func TestMultiTurnContextPreservation(t *testing.T) {
 				Priority:    1,
 				Status:      types.StatusOpen,
 			},
-		}
-		mock.issues = map[string]*types.Issue{
-			"vc-20": mock.searchResults[0],
-		}
-		handler := &ConversationHandler{storage: mock}
+		}...)
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		// Turn 1: Search for database issues
@@ -486,8 +390,8 @@ func TestMultiTurnContextPreservation(t *testing.T) {
 	})
 
 	t.Run("create epic then add multiple children", func(t *testing.T) {
-		mock := &mockStorageIntegration{}
-		handler := &ConversationHandler{storage: mock}
+		store := storagetest.NewFakeStorage()
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		// Turn 1: Create epic
@@ -497,7 +401,7 @@ func TestMultiTurnContextPreservation(t *testing.T) {
 		if err != nil {
 			t.Fatalf("Failed to create epic: %v", err)
 		}
-		epicID := mock.createdIssues[0].ID
+		epicID := createdIssues(store)[0].ID
 
 		// Turn 2: Create first child
 		_, err = handler.toolCreateIssue(ctx, map[string]interface{}{
@@ -507,7 +411,7 @@ func TestMultiTurnContextPreservation(t *testing.T) {
 		if err != nil {
 			t.Fatalf("Failed to create child 1: %v", err)
 		}
-		child1ID := mock.createdIssues[1].ID
+		child1ID := createdIssues(store)[1].ID
 
 		// Turn 3: Create second child
 		_, err = handler.toolCreateIssue(ctx, map[string]interface{}{
@@ -517,7 +421,7 @@ func TestMultiTurnContextPreservation(t *testing.T) {
 		if err != nil {
 			t.Fatalf("Failed to create child 2: %v", err)
 		}
-		child2ID := mock.createdIssues[2].ID
+		child2ID := createdIssues(store)[2].ID
 
 		// Turn 4: Add first child to epic
 		_, err = handler.toolAddChildToEpic(ctx, map[string]interface{}{
@@ -538,12 +442,16 @@ func TestMultiTurnContextPreservation(t *testing.T) {
 		}
 
 		// Verify structure
-		if len(mock.createdIssues) != 3 {
-			t.Errorf("Expected 3 issues (1 epic, 2 children), got %d", len(mock.createdIssues))
+		if len(createdIssues(store)) != 3 {
+			t.Errorf("Expected 3 issues (1 epic, 2 children), got %d", len(createdIssues(store)))
 		}
 
-		if len(mock.addedDeps) < 2 {
-			t.Errorf("Expected at least 2 dependencies added, got %d", len(mock.addedDeps))
+		dependents, err := store.GetDependents(ctx, epicID)
+		if err != nil {
+			t.Fatalf("Failed to get epic dependents: %v", err)
+		}
+		if len(dependents) != 2 {
+			t.Errorf("Expected 2 children of epic %s, got %d", epicID, len(dependents))
 		}
 	})
 }
@@ -551,8 +459,8 @@ func TestMultiTurnContextPreservation(t *testing.T) {
 // TestErrorHandlingAndRecovery tests error scenarios
 func TestErrorHandlingAndRecovery(t *testing.T) {
 	t.Run("missing required parameters", func(t *testing.T) {
-		mock := &mockStorageIntegration{}
-		handler := &ConversationHandler{storage: mock}
+		store := storagetest.NewFakeStorage()
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		// Missing title
@@ -575,8 +483,8 @@ func TestErrorHandlingAndRecovery(t *testing.T) {
 	})
 
 	t.Run("invalid parameter values", func(t *testing.T) {
-		mock := &mockStorageIntegration{}
-		handler := &ConversationHandler{storage: mock}
+		store := storagetest.NewFakeStorage()
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		// Invalid issue type
@@ -598,9 +506,8 @@ func TestErrorHandlingAndRecovery(t *testing.T) {
 	})
 
 	t.Run("issue not found", func(t *testing.T) {
-		mock := &mockStorageIntegration{}
-		mock.issues = map[string]*types.Issue{}
-		handler := &ConversationHandler{storage: mock}
+		store := storagetest.NewFakeStorage()
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		_, err := handler.toolGetIssue(ctx, map[string]interface{}{
@@ -612,8 +519,8 @@ func TestErrorHandlingAndRecovery(t *testing.T) {
 	})
 
 	t.Run("empty results scenarios", func(t *testing.T) {
-		mock := &mockStorageIntegration{}
-		handler := &ConversationHandler{storage: mock}
+		store := storagetest.NewFakeStorage()
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		// No ready work
@@ -645,8 +552,8 @@ func TestErrorHandlingAndRecovery(t *testing.T) {
 	})
 
 	t.Run("continue_execution validation errors", func(t *testing.T) {
-		mock := &mockStorageIntegration{}
-		handler := &ConversationHandler{storage: mock}
+		store := storagetest.NewFakeStorage()
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		// Async not supported
@@ -658,12 +565,7 @@ func TestErrorHandlingAndRecovery(t *testing.T) {
 		}
 
 		// Closed issue
-		mock.issues = map[string]*types.Issue{
-			"vc-1": {
-				ID:     "vc-1",
-				Status: types.StatusClosed,
-			},
-		}
+		store.AddIssue(&types.Issue{ID: "vc-1", Title: "Done", Status: types.StatusClosed, IssueType: types.TypeTask})
 		result, err := handler.toolContinueExecution(ctx, map[string]interface{}{
 			"issue_id": "vc-1",
 		})
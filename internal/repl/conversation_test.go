@@ -10,248 +10,48 @@ import (
 
 	"github.com/anthropics/anthropic-sdk-go"
 	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage/storagetest"
 	"github.com/steveyegge/vc/internal/types"
 )
 
-// mockStorage implements storage.Storage for testing
-type mockStorage struct {
-	statistics       *types.Statistics
-	blockedIssues    []*types.BlockedIssue
-	agentEvents      []*events.AgentEvent
-	issues           map[string]*types.Issue
-	dependencies     map[string][]*types.Issue
-	searchResults    []*types.Issue
-	statisticsError  error
-	blockedError     error
-	agentEventsError error
-	issueError       error
-	searchError      error
+// newTestStore returns a fake store seeded with the given issues
+func newTestStore(issues ...*types.Issue) *storagetest.FakeStorage {
+	store := storagetest.NewFakeStorage()
+	store.AddIssue(issues...)
+	return store
 }
 
-func (m *mockStorage) GetStatistics(ctx context.Context) (*types.Statistics, error) {
-	if m.statisticsError != nil {
-		return nil, m.statisticsError
+// addBlocker records that issueID is blocked by blockerID
+func addBlocker(t *testing.T, store *storagetest.FakeStorage, issueID, blockerID string) {
+	t.Helper()
+	dep := &types.Dependency{IssueID: issueID, DependsOnID: blockerID, Type: types.DepBlocks}
+	if err := store.AddDependency(context.Background(), dep, "test"); err != nil {
+		t.Fatalf("AddDependency(%s -> %s) failed: %v", issueID, blockerID, err)
 	}
-	return m.statistics, nil
 }
 
-func (m *mockStorage) GetBlockedIssues(ctx context.Context) ([]*types.BlockedIssue, error) {
-	if m.blockedError != nil {
-		return nil, m.blockedError
-	}
-	return m.blockedIssues, nil
-}
-func (m *mockStorage) GetReadyBlockers(ctx context.Context, limit int) ([]*types.Issue, error) {
-	return nil, nil
-}
-func (m *mockStorage) IsEpicComplete(ctx context.Context, epicID string) (bool, error) {
-	return false, nil
-}
-func (m *mockStorage) GetMissionForTask(ctx context.Context, taskID string) (*types.MissionContext, error) {
-	return nil, fmt.Errorf("not implemented in mock")
-}
-func (m *mockStorage) GetMissionsNeedingGates(ctx context.Context) ([]*types.Issue, error) {
-	return nil, nil
-}
-
-func (m *mockStorage) GetRecentAgentEvents(ctx context.Context, limit int) ([]*events.AgentEvent, error) {
-	if m.agentEventsError != nil {
-		return nil, m.agentEventsError
-	}
-	results := m.agentEvents
-	if len(results) > limit {
-		results = results[:limit]
-	}
-	return results, nil
-}
-
-func (m *mockStorage) GetAgentEventsByIssue(ctx context.Context, issueID string) ([]*events.AgentEvent, error) {
-	if m.agentEventsError != nil {
-		return nil, m.agentEventsError
-	}
-	var results []*events.AgentEvent
-	for _, evt := range m.agentEvents {
-		if evt.IssueID == issueID {
-			results = append(results, evt)
+// storeAgentEvents stores the given agent events in the fake store
+func storeAgentEvents(t *testing.T, store *storagetest.FakeStorage, evts []*events.AgentEvent) {
+	t.Helper()
+	for _, evt := range evts {
+		if err := store.StoreAgentEvent(context.Background(), evt); err != nil {
+			t.Fatalf("StoreAgentEvent failed: %v", err)
 		}
 	}
-	return results, nil
-}
-
-func (m *mockStorage) SearchIssues(ctx context.Context, query string, filter types.IssueFilter) ([]*types.Issue, error) {
-	if m.searchError != nil {
-		return nil, m.searchError
-	}
-	return m.searchResults, nil
-}
-
-func (m *mockStorage) GetIssue(ctx context.Context, id string) (*types.Issue, error) {
-	if m.issueError != nil {
-		return nil, m.issueError
-	}
-	issue, ok := m.issues[id]
-	if !ok {
-		return nil, context.DeadlineExceeded // Use a standard error
-	}
-	return issue, nil
-}
-
-func (m *mockStorage) GetDependencies(ctx context.Context, issueID string) ([]*types.Issue, error) {
-	return m.dependencies[issueID], nil
-}
-
-func (m *mockStorage) GetDependencyRecords(ctx context.Context, issueID string) ([]*types.Dependency, error) {
-	return nil, nil
-}
-
-func (m *mockStorage) GetExecutionHistory(ctx context.Context, issueID string) ([]*types.ExecutionAttempt, error) {
-	return nil, nil
-}
-
-func (m *mockStorage) RecordExecutionAttempt(ctx context.Context, attempt *types.ExecutionAttempt) error {
-	return nil
-}
-
-// Stub implementations for other storage interface methods
-func (m *mockStorage) StoreAgentEvent(ctx context.Context, event *events.AgentEvent) error {
-	return nil
-}
-func (m *mockStorage) GetAgentEvents(ctx context.Context, filter events.EventFilter) ([]*events.AgentEvent, error) {
-	return nil, nil
-}
-func (m *mockStorage) CreateIssue(ctx context.Context, issue *types.Issue, actor string) error {
-	return nil
-}
-func (m *mockStorage) CreateMission(ctx context.Context, mission *types.Mission, actor string) error {
-	return nil
-}
-func (m *mockStorage) GetMission(ctx context.Context, id string) (*types.Mission, error) {
-	return nil, nil
-}
-func (m *mockStorage) UpdateMission(ctx context.Context, id string, updates map[string]interface{}, actor string) error {
-	return nil
-}
-func (m *mockStorage) UpdateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string) error {
-	return nil
-}
-func (m *mockStorage) CloseIssue(ctx context.Context, id string, reason string, actor string) error {
-	return nil
-}
-func (m *mockStorage) AddDependency(ctx context.Context, dep *types.Dependency, actor string) error {
-	return nil
-}
-func (m *mockStorage) RemoveDependency(ctx context.Context, issueID, dependsOnID string, actor string) error {
-	return nil
-}
-func (m *mockStorage) GetDependents(ctx context.Context, issueID string) ([]*types.Issue, error) {
-	return nil, nil
-}
-func (m *mockStorage) GetDependencyTree(ctx context.Context, issueID string, maxDepth int) ([]*types.TreeNode, error) {
-	return nil, nil
-}
-func (m *mockStorage) DetectCycles(ctx context.Context) ([][]*types.Issue, error) {
-	return nil, nil
-}
-func (m *mockStorage) AddLabel(ctx context.Context, issueID, label, actor string) error {
-	return nil
-}
-func (m *mockStorage) RemoveLabel(ctx context.Context, issueID, label, actor string) error {
-	return nil
-}
-func (m *mockStorage) GetLabels(ctx context.Context, issueID string) ([]string, error) {
-	return nil, nil
-}
-func (m *mockStorage) GetIssuesByLabel(ctx context.Context, label string) ([]*types.Issue, error) {
-	return nil, nil
-}
-func (m *mockStorage) GetReadyWork(ctx context.Context, filter types.WorkFilter) ([]*types.Issue, error) {
-	return nil, nil
-}
-func (m *mockStorage) AddComment(ctx context.Context, issueID, actor, comment string) error {
-	return nil
-}
-func (m *mockStorage) GetEvents(ctx context.Context, issueID string, limit int) ([]*types.Event, error) {
-	return nil, nil
-}
-func (m *mockStorage) RegisterInstance(ctx context.Context, instance *types.ExecutorInstance) error {
-	return nil
-}
-func (m *mockStorage) MarkInstanceStopped(ctx context.Context, instanceID string) error {
-	return nil
-}
-func (m *mockStorage) UpdateHeartbeat(ctx context.Context, instanceID string) error {
-	return nil
-}
-func (m *mockStorage) GetActiveInstances(ctx context.Context) ([]*types.ExecutorInstance, error) {
-	return nil, nil
-}
-func (m *mockStorage) CleanupStaleInstances(ctx context.Context, staleThreshold int) (int, error) {
-	return 0, nil
-}
-func (m *mockStorage) DeleteOldStoppedInstances(ctx context.Context, olderThanSeconds int, maxToKeep int) (int, error) {
-	return 0, nil
-}
-func (m *mockStorage) ClaimIssue(ctx context.Context, issueID, executorInstanceID string) error {
-	return nil
-}
-func (m *mockStorage) GetExecutionState(ctx context.Context, issueID string) (*types.IssueExecutionState, error) {
-	return nil, nil
-}
-func (m *mockStorage) UpdateExecutionState(ctx context.Context, issueID string, state types.ExecutionState) error {
-	return nil
-}
-func (m *mockStorage) SaveCheckpoint(ctx context.Context, issueID string, checkpointData interface{}) error {
-	return nil
-}
-func (m *mockStorage) GetCheckpoint(ctx context.Context, issueID string) (string, error) {
-	return "", nil
-}
-func (m *mockStorage) ReleaseIssue(ctx context.Context, issueID string) error {
-	return nil
-}
-func (m *mockStorage) ReleaseIssueAndReopen(ctx context.Context, issueID, actor, errorComment string) error {
-	return nil
-}
-func (m *mockStorage) Close() error {
-	return nil
-}
-func (m *mockStorage) GetConfig(ctx context.Context, key string) (string, error) {
-	return "", nil
-}
-func (m *mockStorage) SetConfig(ctx context.Context, key, value string) error {
-	return nil
-}
-func (m *mockStorage) CleanupEventsByAge(ctx context.Context, retentionDays, criticalRetentionDays, batchSize int) (int, error) {
-	return 0, nil
-}
-func (m *mockStorage) CleanupEventsByIssueLimit(ctx context.Context, perIssueLimit, batchSize int) (int, error) {
-	return 0, nil
-}
-func (m *mockStorage) CleanupEventsByGlobalLimit(ctx context.Context, globalLimit, batchSize int) (int, error) {
-	return 0, nil
-}
-func (m *mockStorage) GetEventCounts(ctx context.Context) (*types.EventCounts, error) {
-	return &types.EventCounts{}, nil
-}
-func (m *mockStorage) VacuumDatabase(ctx context.Context) error {
-	return nil
 }
 
 // TestToolGetStatus tests the get_status tool
 func TestToolGetStatus(t *testing.T) {
 	t.Run("successful status retrieval", func(t *testing.T) {
-		mock := &mockStorage{
-			statistics: &types.Statistics{
-				TotalIssues:      100,
-				OpenIssues:       40,
-				InProgressIssues: 10,
-				BlockedIssues:    5,
-				ClosedIssues:     45,
-				ReadyIssues:      25,
-				AverageLeadTime:  12.5,
-			},
-		}
-		handler := &ConversationHandler{storage: mock}
+		store := newTestStore(
+			&types.Issue{ID: "vc-1", Title: "Ready", Status: types.StatusOpen, IssueType: types.TypeTask},
+			&types.Issue{ID: "vc-2", Title: "Blocked", Status: types.StatusOpen, IssueType: types.TypeTask},
+			&types.Issue{ID: "vc-3", Title: "Blocker", Status: types.StatusOpen, IssueType: types.TypeTask},
+			&types.Issue{ID: "vc-4", Title: "Working", Status: types.StatusInProgress, IssueType: types.TypeTask},
+			&types.Issue{ID: "vc-5", Title: "Done", Status: types.StatusClosed, IssueType: types.TypeTask},
+		)
+		addBlocker(t, store, "vc-2", "vc-3")
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		result, err := handler.toolGetStatus(ctx, map[string]interface{}{})
@@ -260,20 +60,23 @@ func TestToolGetStatus(t *testing.T) {
 		}
 
 		// Verify output contains expected values
-		if !strings.Contains(result, "Total Issues: 100") {
+		if !strings.Contains(result, "Total Issues: 5") {
 			t.Errorf("Expected total issues in output, got: %s", result)
 		}
-		if !strings.Contains(result, "Open: 40") {
+		if !strings.Contains(result, "Open: 3") {
 			t.Errorf("Expected open issues in output, got: %s", result)
 		}
-		if !strings.Contains(result, "Ready to Work: 25") {
+		if !strings.Contains(result, "Blocked: 1") {
+			t.Errorf("Expected blocked issues in output, got: %s", result)
+		}
+		if !strings.Contains(result, "Ready to Work: 2") {
 			t.Errorf("Expected ready issues in output, got: %s", result)
 		}
 	})
 
 	t.Run("rejects unexpected parameters", func(t *testing.T) {
-		mock := &mockStorage{}
-		handler := &ConversationHandler{storage: mock}
+		store := storagetest.NewFakeStorage()
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		_, err := handler.toolGetStatus(ctx, map[string]interface{}{"foo": "bar"})
@@ -289,31 +92,17 @@ func TestToolGetStatus(t *testing.T) {
 // TestToolGetBlockedIssues tests the get_blocked_issues tool
 func TestToolGetBlockedIssues(t *testing.T) {
 	t.Run("successful blocked issues retrieval", func(t *testing.T) {
-		mock := &mockStorage{
-			blockedIssues: []*types.BlockedIssue{
-				{
-					Issue: types.Issue{
-						ID:        "vc-1",
-						Title:     "Blocked Issue 1",
-						IssueType: types.TypeTask,
-						Priority:  1,
-					},
-					BlockedByCount: 2,
-					BlockedBy:      []string{"vc-2", "vc-3"},
-				},
-				{
-					Issue: types.Issue{
-						ID:        "vc-4",
-						Title:     "Blocked Issue 2",
-						IssueType: types.TypeFeature,
-						Priority:  0,
-					},
-					BlockedByCount: 1,
-					BlockedBy:      []string{"vc-5"},
-				},
-			},
-		}
-		handler := &ConversationHandler{storage: mock}
+		store := newTestStore(
+			&types.Issue{ID: "vc-1", Title: "Blocked Issue 1", Status: types.StatusOpen, IssueType: types.TypeTask, Priority: 1},
+			&types.Issue{ID: "vc-2", Title: "Blocker 1", Status: types.StatusOpen, IssueType: types.TypeTask, Priority: 1},
+			&types.Issue{ID: "vc-3", Title: "Blocker 2", Status: types.StatusOpen, IssueType: types.TypeTask, Priority: 1},
+			&types.Issue{ID: "vc-4", Title: "Blocked Issue 2", Status: types.StatusOpen, IssueType: types.TypeFeature, Priority: 0},
+			&types.Issue{ID: "vc-5", Title: "Blocker 3", Status: types.StatusOpen, IssueType: types.TypeTask, Priority: 1},
+		)
+		addBlocker(t, store, "vc-1", "vc-2")
+		addBlocker(t, store, "vc-1", "vc-3")
+		addBlocker(t, store, "vc-4", "vc-5")
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		result, err := handler.toolGetBlockedIssues(ctx, map[string]interface{}{})
@@ -330,21 +119,13 @@ func TestToolGetBlockedIssues(t *testing.T) {
 	})
 
 	t.Run("applies limit correctly", func(t *testing.T) {
-		blockedIssues := make([]*types.BlockedIssue, 20)
-		for i := 0; i < 20; i++ {
-			blockedIssues[i] = &types.BlockedIssue{
-				Issue: types.Issue{
-					ID:        "vc-" + string(rune('1'+i)),
-					Title:     "Issue",
-					IssueType: types.TypeTask,
-					Priority:  1,
-				},
-				BlockedByCount: 1,
-				BlockedBy:      []string{"dep"},
-			}
+		store := newTestStore(&types.Issue{ID: "dep", Title: "Blocker", Status: types.StatusOpen, IssueType: types.TypeTask, Priority: 1})
+		for i := 1; i <= 20; i++ {
+			id := fmt.Sprintf("vc-%d", i)
+			store.AddIssue(&types.Issue{ID: id, Title: "Issue", Status: types.StatusOpen, IssueType: types.TypeTask, Priority: 1})
+			addBlocker(t, store, id, "dep")
 		}
-		mock := &mockStorage{blockedIssues: blockedIssues}
-		handler := &ConversationHandler{storage: mock}
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		result, err := handler.toolGetBlockedIssues(ctx, map[string]interface{}{"limit": float64(5)})
@@ -359,8 +140,8 @@ func TestToolGetBlockedIssues(t *testing.T) {
 	})
 
 	t.Run("handles no blocked issues", func(t *testing.T) {
-		mock := &mockStorage{blockedIssues: []*types.BlockedIssue{}}
-		handler := &ConversationHandler{storage: mock}
+		store := newTestStore(&types.Issue{ID: "vc-1", Title: "Unblocked", Status: types.StatusOpen, IssueType: types.TypeTask})
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		result, err := handler.toolGetBlockedIssues(ctx, map[string]interface{}{})
@@ -379,27 +160,26 @@ func TestToolGetRecentActivity(t *testing.T) {
 	now := time.Now()
 
 	t.Run("gets all recent activity", func(t *testing.T) {
-		mock := &mockStorage{
-			agentEvents: []*events.AgentEvent{
-				{
-					ID:        "evt-1",
-					Type:      events.EventTypeAgentSpawned,
-					Timestamp: now,
-					IssueID:   "vc-1",
-					Severity:  events.SeverityInfo,
-					Message:   "Agent spawned",
-				},
-				{
-					ID:        "evt-2",
-					Type:      events.EventTypeError,
-					Timestamp: now.Add(-1 * time.Minute),
-					IssueID:   "vc-2",
-					Severity:  events.SeverityError,
-					Message:   "Build failed",
-				},
+		store := storagetest.NewFakeStorage()
+		storeAgentEvents(t, store, []*events.AgentEvent{
+			{
+				ID:        "evt-1",
+				Type:      events.EventTypeAgentSpawned,
+				Timestamp: now,
+				IssueID:   "vc-1",
+				Severity:  events.SeverityInfo,
+				Message:   "Agent spawned",
 			},
-		}
-		handler := &ConversationHandler{storage: mock}
+			{
+				ID:        "evt-2",
+				Type:      events.EventTypeError,
+				Timestamp: now.Add(-1 * time.Minute),
+				IssueID:   "vc-2",
+				Severity:  events.SeverityError,
+				Message:   "Build failed",
+			},
+		})
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		result, err := handler.toolGetRecentActivity(ctx, map[string]interface{}{})
@@ -419,27 +199,26 @@ func TestToolGetRecentActivity(t *testing.T) {
 	})
 
 	t.Run("filters by issue_id", func(t *testing.T) {
-		mock := &mockStorage{
-			agentEvents: []*events.AgentEvent{
-				{
-					ID:        "evt-1",
-					Type:      events.EventTypeProgress,
-					Timestamp: now,
-					IssueID:   "vc-1",
-					Severity:  events.SeverityInfo,
-					Message:   "Working on vc-1",
-				},
-				{
-					ID:        "evt-2",
-					Type:      events.EventTypeProgress,
-					Timestamp: now,
-					IssueID:   "vc-2",
-					Severity:  events.SeverityInfo,
-					Message:   "Working on vc-2",
-				},
+		store := storagetest.NewFakeStorage()
+		storeAgentEvents(t, store, []*events.AgentEvent{
+			{
+				ID:        "evt-1",
+				Type:      events.EventTypeProgress,
+				Timestamp: now,
+				IssueID:   "vc-1",
+				Severity:  events.SeverityInfo,
+				Message:   "Working on vc-1",
 			},
-		}
-		handler := &ConversationHandler{storage: mock}
+			{
+				ID:        "evt-2",
+				Type:      events.EventTypeProgress,
+				Timestamp: now,
+				IssueID:   "vc-2",
+				Severity:  events.SeverityInfo,
+				Message:   "Working on vc-2",
+			},
+		})
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		result, err := handler.toolGetRecentActivity(ctx, map[string]interface{}{"issue_id": "vc-1"})
@@ -459,7 +238,6 @@ func TestToolGetRecentActivity(t *testing.T) {
 		manyEvents := make([]*events.AgentEvent, 50)
 		for i := 0; i < 50; i++ {
 			manyEvents[i] = &events.AgentEvent{
-				ID:        "evt-" + string(rune('1'+i)),
 				Type:      events.EventTypeProgress,
 				Timestamp: now,
 				IssueID:   "vc-1",
@@ -467,8 +245,9 @@ func TestToolGetRecentActivity(t *testing.T) {
 				Message:   "Event",
 			}
 		}
-		mock := &mockStorage{agentEvents: manyEvents}
-		handler := &ConversationHandler{storage: mock}
+		store := storagetest.NewFakeStorage()
+		storeAgentEvents(t, store, manyEvents)
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		result, err := handler.toolGetRecentActivity(ctx, map[string]interface{}{"limit": float64(10)})
@@ -483,8 +262,8 @@ func TestToolGetRecentActivity(t *testing.T) {
 	})
 
 	t.Run("handles no activity", func(t *testing.T) {
-		mock := &mockStorage{agentEvents: []*events.AgentEvent{}}
-		handler := &ConversationHandler{storage: mock}
+		store := storagetest.NewFakeStorage()
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		result, err := handler.toolGetRecentActivity(ctx, map[string]interface{}{})
@@ -501,27 +280,25 @@ func TestToolGetRecentActivity(t *testing.T) {
 // TestToolSearchIssues tests the search_issues tool
 func TestToolSearchIssues(t *testing.T) {
 	t.Run("successful search", func(t *testing.T) {
-		mock := &mockStorage{
-			searchResults: []*types.Issue{
-				{
-					ID:          "vc-1",
-					Title:       "Add authentication",
-					Description: "Implement user authentication",
-					IssueType:   types.TypeFeature,
-					Priority:    1,
-					Status:      types.StatusOpen,
-				},
-				{
-					ID:          "vc-2",
-					Title:       "Fix auth bug",
-					Description: "Users can't log in",
-					IssueType:   types.TypeBug,
-					Priority:    0,
-					Status:      types.StatusInProgress,
-				},
+		store := newTestStore([]*types.Issue{
+			{
+				ID:          "vc-1",
+				Title:       "Add authentication",
+				Description: "Implement user authentication",
+				IssueType:   types.TypeFeature,
+				Priority:    1,
+				Status:      types.StatusOpen,
 			},
-		}
-		handler := &ConversationHandler{storage: mock}
+			{
+				ID:          "vc-2",
+				Title:       "Fix auth bug",
+				Description: "Users can't log in",
+				IssueType:   types.TypeBug,
+				Priority:    0,
+				Status:      types.StatusInProgress,
+			},
+		}...)
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		result, err := handler.toolSearchIssues(ctx, map[string]interface{}{"query": "auth"})
@@ -538,8 +315,8 @@ func TestToolSearchIssues(t *testing.T) {
 	})
 
 	t.Run("requires query parameter", func(t *testing.T) {
-		mock := &mockStorage{}
-		handler := &ConversationHandler{storage: mock}
+		store := storagetest.NewFakeStorage()
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		_, err := handler.toolSearchIssues(ctx, map[string]interface{}{})
@@ -552,8 +329,8 @@ func TestToolSearchIssues(t *testing.T) {
 	})
 
 	t.Run("handles no results", func(t *testing.T) {
-		mock := &mockStorage{searchResults: []*types.Issue{}}
-		handler := &ConversationHandler{storage: mock}
+		store := newTestStore(&types.Issue{ID: "vc-1", Title: "Unrelated", Status: types.StatusOpen, IssueType: types.TypeTask})
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		result, err := handler.toolSearchIssues(ctx, map[string]interface{}{"query": "nonexistent"})
@@ -568,19 +345,17 @@ func TestToolSearchIssues(t *testing.T) {
 
 	t.Run("truncates long descriptions", func(t *testing.T) {
 		longDesc := strings.Repeat("a", 150)
-		mock := &mockStorage{
-			searchResults: []*types.Issue{
-				{
-					ID:          "vc-1",
-					Title:       "Test",
-					Description: longDesc,
-					IssueType:   types.TypeTask,
-					Priority:    1,
-					Status:      types.StatusOpen,
-				},
+		store := newTestStore([]*types.Issue{
+			{
+				ID:          "vc-1",
+				Title:       "Test",
+				Description: longDesc,
+				IssueType:   types.TypeTask,
+				Priority:    1,
+				Status:      types.StatusOpen,
 			},
-		}
-		handler := &ConversationHandler{storage: mock}
+		}...)
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		result, err := handler.toolSearchIssues(ctx, map[string]interface{}{"query": "test"})
@@ -601,16 +376,14 @@ func TestToolSearchIssues(t *testing.T) {
 // TestToolContinueExecution tests the continue_execution tool validation
 func TestToolContinueExecution(t *testing.T) {
 	t.Run("rejects closed issues", func(t *testing.T) {
-		mock := &mockStorage{
-			issues: map[string]*types.Issue{
-				"vc-1": {
-					ID:     "vc-1",
-					Title:  "Closed issue",
-					Status: types.StatusClosed,
-				},
+		store := newTestStore([]*types.Issue{
+			{
+				ID:     "vc-1",
+				Title:  "Closed issue",
+				Status: types.StatusClosed,
 			},
-		}
-		handler := &ConversationHandler{storage: mock}
+		}...)
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		result, err := handler.toolContinueExecution(ctx, map[string]interface{}{"issue_id": "vc-1"})
@@ -624,16 +397,14 @@ func TestToolContinueExecution(t *testing.T) {
 	})
 
 	t.Run("rejects in-progress issues", func(t *testing.T) {
-		mock := &mockStorage{
-			issues: map[string]*types.Issue{
-				"vc-1": {
-					ID:     "vc-1",
-					Title:  "In progress issue",
-					Status: types.StatusInProgress,
-				},
+		store := newTestStore([]*types.Issue{
+			{
+				ID:     "vc-1",
+				Title:  "In progress issue",
+				Status: types.StatusInProgress,
 			},
-		}
-		handler := &ConversationHandler{storage: mock}
+		}...)
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		result, err := handler.toolContinueExecution(ctx, map[string]interface{}{"issue_id": "vc-1"})
@@ -647,25 +418,12 @@ func TestToolContinueExecution(t *testing.T) {
 	})
 
 	t.Run("rejects blocked issues with blocker details", func(t *testing.T) {
-		mock := &mockStorage{
-			issues: map[string]*types.Issue{
-				"vc-1": {
-					ID:     "vc-1",
-					Title:  "Blocked issue",
-					Status: types.StatusBlocked,
-				},
-			},
-			dependencies: map[string][]*types.Issue{
-				"vc-1": {
-					{
-						ID:     "vc-2",
-						Title:  "Blocker",
-						Status: types.StatusOpen,
-					},
-				},
-			},
-		}
-		handler := &ConversationHandler{storage: mock}
+		store := newTestStore(
+			&types.Issue{ID: "vc-1", Title: "Blocked issue", Status: types.StatusBlocked, IssueType: types.TypeTask},
+			&types.Issue{ID: "vc-2", Title: "Blocker", Status: types.StatusOpen, IssueType: types.TypeTask},
+		)
+		addBlocker(t, store, "vc-1", "vc-2")
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		result, err := handler.toolContinueExecution(ctx, map[string]interface{}{"issue_id": "vc-1"})
@@ -682,8 +440,8 @@ func TestToolContinueExecution(t *testing.T) {
 	})
 
 	t.Run("rejects async mode", func(t *testing.T) {
-		mock := &mockStorage{}
-		handler := &ConversationHandler{storage: mock}
+		store := storagetest.NewFakeStorage()
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		_, err := handler.toolContinueExecution(ctx, map[string]interface{}{"async": true})
@@ -699,8 +457,8 @@ func TestToolContinueExecution(t *testing.T) {
 // TestToolCreateIssue tests the create_issue tool
 func TestToolCreateIssue(t *testing.T) {
 	t.Run("creates issue with required fields", func(t *testing.T) {
-		mock := &mockStorage{}
-		handler := &ConversationHandler{storage: mock}
+		store := storagetest.NewFakeStorage()
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		result, err := handler.toolCreateIssue(ctx, map[string]interface{}{
@@ -719,8 +477,8 @@ func TestToolCreateIssue(t *testing.T) {
 	})
 
 	t.Run("creates issue with all fields", func(t *testing.T) {
-		mock := &mockStorage{}
-		handler := &ConversationHandler{storage: mock}
+		store := storagetest.NewFakeStorage()
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		result, err := handler.toolCreateIssue(ctx, map[string]interface{}{
@@ -741,8 +499,8 @@ func TestToolCreateIssue(t *testing.T) {
 	})
 
 	t.Run("requires title", func(t *testing.T) {
-		mock := &mockStorage{}
-		handler := &ConversationHandler{storage: mock}
+		store := storagetest.NewFakeStorage()
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		_, err := handler.toolCreateIssue(ctx, map[string]interface{}{})
@@ -755,8 +513,8 @@ func TestToolCreateIssue(t *testing.T) {
 	})
 
 	t.Run("validates issue type", func(t *testing.T) {
-		mock := &mockStorage{}
-		handler := &ConversationHandler{storage: mock}
+		store := storagetest.NewFakeStorage()
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		_, err := handler.toolCreateIssue(ctx, map[string]interface{}{
@@ -772,8 +530,8 @@ func TestToolCreateIssue(t *testing.T) {
 	})
 
 	t.Run("defaults to task type", func(t *testing.T) {
-		mock := &mockStorage{}
-		handler := &ConversationHandler{storage: mock}
+		store := storagetest.NewFakeStorage()
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		result, err := handler.toolCreateIssue(ctx, map[string]interface{}{
@@ -789,8 +547,8 @@ func TestToolCreateIssue(t *testing.T) {
 	})
 
 	t.Run("defaults to priority 2", func(t *testing.T) {
-		mock := &mockStorage{}
-		handler := &ConversationHandler{storage: mock}
+		store := storagetest.NewFakeStorage()
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		// We can't directly test priority in the result string,
@@ -807,8 +565,8 @@ func TestToolCreateIssue(t *testing.T) {
 // TestToolCreateEpic tests the create_epic tool
 func TestToolCreateEpic(t *testing.T) {
 	t.Run("creates epic with required fields", func(t *testing.T) {
-		mock := &mockStorage{}
-		handler := &ConversationHandler{storage: mock}
+		store := storagetest.NewFakeStorage()
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		result, err := handler.toolCreateEpic(ctx, map[string]interface{}{
@@ -827,8 +585,8 @@ func TestToolCreateEpic(t *testing.T) {
 	})
 
 	t.Run("creates epic with all fields", func(t *testing.T) {
-		mock := &mockStorage{}
-		handler := &ConversationHandler{storage: mock}
+		store := storagetest.NewFakeStorage()
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		result, err := handler.toolCreateEpic(ctx, map[string]interface{}{
@@ -847,8 +605,8 @@ func TestToolCreateEpic(t *testing.T) {
 	})
 
 	t.Run("requires title", func(t *testing.T) {
-		mock := &mockStorage{}
-		handler := &ConversationHandler{storage: mock}
+		store := storagetest.NewFakeStorage()
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		_, err := handler.toolCreateEpic(ctx, map[string]interface{}{})
@@ -864,8 +622,11 @@ func TestToolCreateEpic(t *testing.T) {
 // TestToolAddChildToEpic tests the add_child_to_epic tool
 func TestToolAddChildToEpic(t *testing.T) {
 	t.Run("adds child with default blocks=true", func(t *testing.T) {
-		mock := &mockStorage{}
-		handler := &ConversationHandler{storage: mock}
+		store := newTestStore(
+			&types.Issue{ID: "vc-1", Title: "Epic", Status: types.StatusOpen, IssueType: types.TypeEpic},
+			&types.Issue{ID: "vc-2", Title: "Child", Status: types.StatusOpen, IssueType: types.TypeTask},
+		)
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		result, err := handler.toolAddChildToEpic(ctx, map[string]interface{}{
@@ -882,11 +643,23 @@ func TestToolAddChildToEpic(t *testing.T) {
 		if !strings.Contains(result, "blocks=true") {
 			t.Errorf("Expected blocks=true in result, got: %s", result)
 		}
+
+		// A blocking child holds the epic open until it closes
+		complete, err := store.IsEpicComplete(ctx, "vc-1")
+		if err != nil {
+			t.Fatalf("IsEpicComplete failed: %v", err)
+		}
+		if complete {
+			t.Error("Expected epic with an open blocking child to be incomplete")
+		}
 	})
 
 	t.Run("adds child with blocks=false", func(t *testing.T) {
-		mock := &mockStorage{}
-		handler := &ConversationHandler{storage: mock}
+		store := newTestStore(
+			&types.Issue{ID: "vc-1", Title: "Epic", Status: types.StatusOpen, IssueType: types.TypeEpic},
+			&types.Issue{ID: "vc-2", Title: "Child", Status: types.StatusOpen, IssueType: types.TypeTask},
+		)
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		result, err := handler.toolAddChildToEpic(ctx, map[string]interface{}{
@@ -901,11 +674,19 @@ func TestToolAddChildToEpic(t *testing.T) {
 		if !strings.Contains(result, "blocks=false") {
 			t.Errorf("Expected blocks=false in result, got: %s", result)
 		}
+
+		deps, err := store.GetDependencyRecords(ctx, "vc-2")
+		if err != nil {
+			t.Fatalf("GetDependencyRecords failed: %v", err)
+		}
+		if len(deps) != 1 || deps[0].Type != types.DepRelated {
+			t.Errorf("Expected a single related dependency, got: %+v", deps)
+		}
 	})
 
 	t.Run("requires epic_id", func(t *testing.T) {
-		mock := &mockStorage{}
-		handler := &ConversationHandler{storage: mock}
+		store := storagetest.NewFakeStorage()
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		_, err := handler.toolAddChildToEpic(ctx, map[string]interface{}{
@@ -920,8 +701,8 @@ func TestToolAddChildToEpic(t *testing.T) {
 	})
 
 	t.Run("requires child_issue_id", func(t *testing.T) {
-		mock := &mockStorage{}
-		handler := &ConversationHandler{storage: mock}
+		store := storagetest.NewFakeStorage()
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		_, err := handler.toolAddChildToEpic(ctx, map[string]interface{}{
@@ -939,13 +720,13 @@ func TestToolAddChildToEpic(t *testing.T) {
 // TestToolGetReadyWork tests the get_ready_work tool
 func TestToolGetReadyWork(t *testing.T) {
 	t.Run("gets ready work with default limit", func(t *testing.T) {
-		mock := &mockStorage{
-			// GetReadyWork is called by the tool, needs to be implemented in mock
-		}
-		handler := &ConversationHandler{storage: mock}
+		store := newTestStore(
+			&types.Issue{ID: "vc-1", Title: "Done", Status: types.StatusClosed, IssueType: types.TypeTask},
+		)
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
-		// Mock will return empty list, which is fine for this test
+		// Closed issues are never ready
 		result, err := handler.toolGetReadyWork(ctx, map[string]interface{}{})
 		if err != nil {
 			t.Fatalf("toolGetReadyWork failed: %v", err)
@@ -957,36 +738,40 @@ func TestToolGetReadyWork(t *testing.T) {
 	})
 
 	t.Run("applies limit parameter", func(t *testing.T) {
-		mock := &mockStorage{}
-		handler := &ConversationHandler{storage: mock}
+		store := newTestStore(
+			&types.Issue{ID: "vc-1", Title: "First", Status: types.StatusOpen, IssueType: types.TypeTask, Priority: 1},
+			&types.Issue{ID: "vc-2", Title: "Second", Status: types.StatusOpen, IssueType: types.TypeTask, Priority: 1},
+			&types.Issue{ID: "vc-3", Title: "Third", Status: types.StatusOpen, IssueType: types.TypeTask, Priority: 1},
+		)
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
-		// Just verify it doesn't error with limit param
-		_, err := handler.toolGetReadyWork(ctx, map[string]interface{}{
-			"limit": float64(10),
+		result, err := handler.toolGetReadyWork(ctx, map[string]interface{}{
+			"limit": float64(2),
 		})
 		if err != nil {
 			t.Fatalf("toolGetReadyWork failed: %v", err)
 		}
+		if !strings.Contains(result, "Found 2 ready issues") {
+			t.Errorf("Expected 2 ready issues with limit, got: %s", result)
+		}
 	})
 }
 
 // TestToolGetIssue tests the get_issue tool
 func TestToolGetIssue(t *testing.T) {
 	t.Run("gets issue successfully", func(t *testing.T) {
-		mock := &mockStorage{
-			issues: map[string]*types.Issue{
-				"vc-1": {
-					ID:          "vc-1",
-					Title:       "Test Issue",
-					Description: "Test description",
-					IssueType:   types.TypeTask,
-					Priority:    1,
-					Status:      types.StatusOpen,
-				},
+		store := newTestStore([]*types.Issue{
+			{
+				ID:          "vc-1",
+				Title:       "Test Issue",
+				Description: "Test description",
+				IssueType:   types.TypeTask,
+				Priority:    1,
+				Status:      types.StatusOpen,
 			},
-		}
-		handler := &ConversationHandler{storage: mock}
+		}...)
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		result, err := handler.toolGetIssue(ctx, map[string]interface{}{
@@ -1005,8 +790,8 @@ func TestToolGetIssue(t *testing.T) {
 	})
 
 	t.Run("requires issue_id", func(t *testing.T) {
-		mock := &mockStorage{}
-		handler := &ConversationHandler{storage: mock}
+		store := storagetest.NewFakeStorage()
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		_, err := handler.toolGetIssue(ctx, map[string]interface{}{})
@@ -1019,10 +804,8 @@ func TestToolGetIssue(t *testing.T) {
 	})
 
 	t.Run("handles issue not found", func(t *testing.T) {
-		mock := &mockStorage{
-			issues: map[string]*types.Issue{},
-		}
-		handler := &ConversationHandler{storage: mock}
+		store := storagetest.NewFakeStorage()
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		_, err := handler.toolGetIssue(ctx, map[string]interface{}{
@@ -1037,8 +820,8 @@ func TestToolGetIssue(t *testing.T) {
 // TestToolContinueUntilBlocked tests the continue_until_blocked tool
 func TestToolContinueUntilBlocked(t *testing.T) {
 	t.Run("stops when no ready work", func(t *testing.T) {
-		mock := &mockStorage{}
-		handler := &ConversationHandler{storage: mock}
+		store := storagetest.NewFakeStorage()
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		result, err := handler.toolContinueUntilBlocked(ctx, map[string]interface{}{})
@@ -1055,8 +838,8 @@ func TestToolContinueUntilBlocked(t *testing.T) {
 	})
 
 	t.Run("accepts max_iterations parameter", func(t *testing.T) {
-		mock := &mockStorage{}
-		handler := &ConversationHandler{storage: mock}
+		store := storagetest.NewFakeStorage()
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		// Should not error with max_iterations
@@ -1069,8 +852,8 @@ func TestToolContinueUntilBlocked(t *testing.T) {
 	})
 
 	t.Run("accepts timeout_minutes parameter", func(t *testing.T) {
-		mock := &mockStorage{}
-		handler := &ConversationHandler{storage: mock}
+		store := storagetest.NewFakeStorage()
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		// Should not error with timeout_minutes
@@ -1083,8 +866,8 @@ func TestToolContinueUntilBlocked(t *testing.T) {
 	})
 
 	t.Run("accepts error_threshold parameter", func(t *testing.T) {
-		mock := &mockStorage{}
-		handler := &ConversationHandler{storage: mock}
+		store := storagetest.NewFakeStorage()
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		// Should not error with error_threshold
@@ -1097,8 +880,8 @@ func TestToolContinueUntilBlocked(t *testing.T) {
 	})
 
 	t.Run("uses default parameters", func(t *testing.T) {
-		mock := &mockStorage{}
-		handler := &ConversationHandler{storage: mock}
+		store := storagetest.NewFakeStorage()
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		// Should work with no parameters (all defaults)
@@ -1113,8 +896,8 @@ func TestToolContinueUntilBlocked(t *testing.T) {
 	})
 
 	t.Run("formats result correctly", func(t *testing.T) {
-		mock := &mockStorage{}
-		handler := &ConversationHandler{storage: mock}
+		store := storagetest.NewFakeStorage()
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		result, err := handler.toolContinueUntilBlocked(ctx, map[string]interface{}{})
@@ -1143,12 +926,8 @@ func TestToolContinueUntilBlocked(t *testing.T) {
 // TestExecuteTool tests the tool dispatcher
 func TestExecuteTool(t *testing.T) {
 	t.Run("dispatches to correct tool", func(t *testing.T) {
-		mock := &mockStorage{
-			statistics: &types.Statistics{
-				TotalIssues: 10,
-			},
-		}
-		handler := &ConversationHandler{storage: mock}
+		store := storagetest.NewFakeStorage()
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		// Test dispatching to get_status
@@ -1173,8 +952,8 @@ func TestExecuteTool(t *testing.T) {
 	})
 
 	t.Run("returns error for unknown tool", func(t *testing.T) {
-		mock := &mockStorage{}
-		handler := &ConversationHandler{storage: mock}
+		store := storagetest.NewFakeStorage()
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		_, err := handler.executeTool(ctx, "unknown_tool", map[string]interface{}{})
@@ -1187,8 +966,8 @@ func TestExecuteTool(t *testing.T) {
 	})
 
 	t.Run("returns error for invalid input format", func(t *testing.T) {
-		mock := &mockStorage{}
-		handler := &ConversationHandler{storage: mock}
+		store := storagetest.NewFakeStorage()
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		_, err := handler.executeTool(ctx, "get_status", "invalid")
@@ -1201,8 +980,8 @@ func TestExecuteTool(t *testing.T) {
 	})
 
 	t.Run("handles JSON byte input from Anthropic SDK", func(t *testing.T) {
-		mock := &mockStorage{}
-		handler := &ConversationHandler{storage: mock}
+		store := storagetest.NewFakeStorage()
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		// Simulate what Anthropic SDK sends: raw JSON bytes
@@ -1216,8 +995,8 @@ func TestExecuteTool(t *testing.T) {
 	})
 
 	t.Run("handles json.RawMessage input", func(t *testing.T) {
-		mock := &mockStorage{}
-		handler := &ConversationHandler{storage: mock}
+		store := storagetest.NewFakeStorage()
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		// Simulate json.RawMessage input
@@ -1230,12 +1009,8 @@ func TestExecuteTool(t *testing.T) {
 	})
 
 	t.Run("handles empty JSON object as bytes", func(t *testing.T) {
-		mock := &mockStorage{
-			statistics: &types.Statistics{
-				TotalIssues: 5,
-			},
-		}
-		handler := &ConversationHandler{storage: mock}
+		store := storagetest.NewFakeStorage()
+		handler := &ConversationHandler{storage: store}
 		ctx := context.Background()
 
 		// Empty JSON object as bytes (common for tools with no parameters)
@@ -1253,8 +1028,8 @@ func TestExecuteTool(t *testing.T) {
 
 // TestClearHistory tests conversation history clearing
 func TestClearHistory(t *testing.T) {
-	mock := &mockStorage{}
-	handler := &ConversationHandler{storage: mock}
+	store := storagetest.NewFakeStorage()
+	handler := &ConversationHandler{storage: store}
 
 	// Add some history (using the proper type)
 	handler.history = append(handler.history,
@@ -1273,8 +1048,8 @@ func TestClearHistory(t *testing.T) {
 
 // TestGetTools tests tool definition generation
 func TestGetTools(t *testing.T) {
-	mock := &mockStorage{}
-	handler := &ConversationHandler{storage: mock}
+	store := storagetest.NewFakeStorage()
+	handler := &ConversationHandler{storage: store}
 
 	tools := handler.getTools()
 
@@ -1315,8 +1090,8 @@ func TestGetTools(t *testing.T) {
 
 // TestSystemPrompt tests that system prompt is generated
 func TestSystemPrompt(t *testing.T) {
-	mock := &mockStorage{}
-	handler := &ConversationHandler{storage: mock}
+	store := storagetest.NewFakeStorage()
+	handler := &ConversationHandler{storage: store}
 
 	prompt := handler.systemPrompt()
 
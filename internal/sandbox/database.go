@@ -303,26 +303,13 @@ func mergeResults(ctx context.Context, sandboxDB, mainDB storage.Storage, missio
 
 	// Update status if it changed (vc-263: only update from sandbox if advancing state)
 	if sandboxMission.Status != mainMission.Status {
-		// Use CloseIssue if the sandbox mission was closed
-		if sandboxMission.Status == types.StatusClosed {
-			closeReason := "Completed in sandbox execution"
-			if err := mainDB.CloseIssue(ctx, missionID, closeReason, "sandbox-merge"); err != nil {
-				return fmt.Errorf("failed to close mission: %w", err)
-			}
-		} else if mainMission.Status != types.StatusClosed {
-			// vc-263: Only update to non-closed status if main mission isn't already closed
-			// If main mission was closed (e.g., by epic completion logic), don't revert it
-			// to the sandbox's stale 'open' status
-			// For other status changes, use UpdateIssue
-			// vc-262: Pass status as string (beads expects string, not vc types.Status)
-			updates := map[string]interface{}{
-				"status": string(sandboxMission.Status),
-			}
-			if err := mainDB.UpdateIssue(ctx, missionID, updates, "sandbox-merge"); err != nil {
-				return fmt.Errorf("failed to update mission status: %w", err)
+		// vc-263: If main mission was closed (e.g., by epic completion logic), don't revert it
+		// to the sandbox's stale 'open' status
+		if sandboxMission.Status == types.StatusClosed || mainMission.Status != types.StatusClosed {
+			if err := mergeIssueStatus(ctx, mainDB, mainMission, sandboxMission.Status); err != nil {
+				return err
 			}
 		}
-		// else: main mission is closed but sandbox isn't - don't revert the close
 	}
 
 	// Merge any new issues created in the sandbox (discovered issues, follow-up tasks, etc.)
@@ -359,14 +346,14 @@ func mergeResults(ctx context.Context, sandboxDB, mainDB storage.Storage, missio
 		log.Printf("[SANDBOX] Running deduplication on %d discovered issues", len(candidateDiscoveredIssues))
 
 		// vc-151: Log deduplication batch started event
-		logSandboxDeduplicationBatchStarted(ctx, mainDB, missionID, len(candidateDiscoveredIssues))
+		logSandboxDeduplicationBatchStarted(ctx, mainDB, deduplicator, missionID, len(candidateDiscoveredIssues))
 
-		result, err := deduplicator.DeduplicateBatch(ctx, candidateDiscoveredIssues)
+		result, err := deduplicator.DeduplicateBatch(deduplication.WithScopeIssue(ctx, missionID), candidateDiscoveredIssues)
 		if err != nil {
 			// Fail-safe: if deduplication fails, file all issues with warning
 			log.Printf("[SANDBOX] WARNING: Deduplication failed (%v), filing all issues", err)
 			// vc-151: Log deduplication failure
-			logSandboxDeduplicationBatchCompleted(ctx, mainDB, missionID, nil, err)
+			logSandboxDeduplicationBatchCompleted(ctx, mainDB, deduplicator, missionID, nil, err)
 			issuesToFile = candidateDiscoveredIssues
 		} else {
 			issuesToFile = result.UniqueIssues
@@ -377,7 +364,7 @@ func mergeResults(ctx context.Context, sandboxDB, mainDB storage.Storage, missio
 				result.Stats.UniqueCount, result.Stats.DuplicateCount, result.Stats.WithinBatchDuplicateCount)
 
 			// vc-151: Log deduplication success with stats and decisions
-			logSandboxDeduplicationBatchCompleted(ctx, mainDB, missionID, result, nil)
+			logSandboxDeduplicationBatchCompleted(ctx, mainDB, deduplicator, missionID, result, nil)
 
 			// Add cross-reference comments for duplicates
 			for idx, existingID := range result.DuplicatePairs {
@@ -460,11 +447,8 @@ func mergeResults(ctx context.Context, sandboxDB, mainDB storage.Storage, missio
 
 		// If issue exists and status changed, update it
 		if mainIssue != nil && mainIssue.Status != sandboxIssue.Status {
-			updates := map[string]interface{}{
-				"status": sandboxIssue.Status,
-			}
-			if err := mainDB.UpdateIssue(ctx, sandboxIssue.ID, updates, "sandbox-merge"); err != nil {
-				return fmt.Errorf("failed to update issue %s status: %w", sandboxIssue.ID, err)
+			if err := mergeIssueStatus(ctx, mainDB, mainIssue, sandboxIssue.Status); err != nil {
+				return err
 			}
 		}
 	}
@@ -524,8 +508,42 @@ func mergeResults(ctx context.Context, sandboxDB, mainDB storage.Storage, missio
 	return nil
 }
 
+// mergeIssueStatus gives an issue in the main database the status it ended
+// with in the sandbox. Closing goes through CloseIssue, since UpdateIssue
+// can't close; a transition the status graph doesn't allow from the issue's
+// main status is skipped, so what happened there since the sandbox was
+// created stands.
+func mergeIssueStatus(ctx context.Context, mainDB storage.Storage, mainIssue *types.Issue, status types.Status) error {
+	if status == types.StatusClosed {
+		if err := mainDB.CloseIssue(ctx, mainIssue.ID, "Completed in sandbox execution", "sandbox-merge"); err != nil {
+			return fmt.Errorf("failed to close issue %s: %w", mainIssue.ID, err)
+		}
+		return nil
+	}
+	if err := types.ValidateStatusTransition(mainIssue.ID, mainIssue.Status, status); err != nil {
+		log.Printf("[SANDBOX] Keeping %s %s in the main database: %v", mainIssue.ID, mainIssue.Status, err)
+		return nil
+	}
+	// vc-262: Pass status as string (beads expects string, not vc types.Status)
+	updates := map[string]interface{}{
+		"status": string(status),
+	}
+	if err := mainDB.UpdateIssue(ctx, mainIssue.ID, updates, "sandbox-merge"); err != nil {
+		return fmt.Errorf("failed to update issue %s status: %w", mainIssue.ID, err)
+	}
+	return nil
+}
+
+// stampDedupAIModel records the AI provider and model behind the
+// deduplicator on a deduplication event
+func stampDedupAIModel(event *events.AgentEvent, dedup deduplication.Deduplicator) {
+	if reporter, ok := dedup.(deduplication.ModelReporter); ok {
+		event.SetAIModel(reporter.AIModel())
+	}
+}
+
 // logSandboxDeduplicationBatchStarted logs a deduplication batch start event from sandbox merge (vc-151)
-func logSandboxDeduplicationBatchStarted(ctx context.Context, store storage.Storage, issueID string, candidateCount int) {
+func logSandboxDeduplicationBatchStarted(ctx context.Context, store storage.Storage, dedup deduplication.Deduplicator, issueID string, candidateCount int) {
 	// Skip logging if context is canceled
 	if ctx.Err() != nil {
 		return
@@ -547,13 +565,14 @@ func logSandboxDeduplicationBatchStarted(ctx context.Context, store storage.Stor
 		return
 	}
 
+	stampDedupAIModel(event, dedup)
 	if err := store.StoreAgentEvent(ctx, event); err != nil {
 		log.Printf("[SANDBOX] warning: failed to store deduplication batch started event: %v", err)
 	}
 }
 
 // logSandboxDeduplicationBatchCompleted logs a deduplication batch completion event from sandbox merge (vc-151)
-func logSandboxDeduplicationBatchCompleted(ctx context.Context, store storage.Storage, issueID string, result *deduplication.DeduplicationResult, dedupErr error) {
+func logSandboxDeduplicationBatchCompleted(ctx context.Context, store storage.Storage, dedup deduplication.Deduplicator, issueID string, result *deduplication.DeduplicationResult, dedupErr error) {
 	// Skip logging if context is canceled
 	if ctx.Err() != nil {
 		return
@@ -607,6 +626,7 @@ func logSandboxDeduplicationBatchCompleted(ctx context.Context, store storage.St
 		return
 	}
 
+	stampDedupAIModel(batchEvent, dedup)
 	if err := store.StoreAgentEvent(ctx, batchEvent); err != nil {
 		log.Printf("[SANDBOX] warning: failed to store deduplication batch completed event: %v", err)
 		return
@@ -615,13 +635,13 @@ func logSandboxDeduplicationBatchCompleted(ctx context.Context, store storage.St
 	// Log individual decision events (for confidence score distribution analysis)
 	if result != nil && len(result.Decisions) > 0 {
 		for _, decision := range result.Decisions {
-			logSandboxDeduplicationDecision(ctx, store, issueID, decision)
+			logSandboxDeduplicationDecision(ctx, store, dedup, issueID, decision)
 		}
 	}
 }
 
 // logSandboxDeduplicationDecision logs an individual deduplication decision from sandbox merge (vc-151)
-func logSandboxDeduplicationDecision(ctx context.Context, store storage.Storage, issueID string, decision deduplication.DecisionDetail) {
+func logSandboxDeduplicationDecision(ctx context.Context, store storage.Storage, dedup deduplication.Deduplicator, issueID string, decision deduplication.DecisionDetail) {
 	// Skip logging if context is canceled
 	if ctx.Err() != nil {
 		return
@@ -667,6 +687,7 @@ func logSandboxDeduplicationDecision(ctx context.Context, store storage.Storage,
 		return
 	}
 
+	stampDedupAIModel(event, dedup)
 	if err := store.StoreAgentEvent(ctx, event); err != nil {
 		log.Printf("[SANDBOX] warning: failed to store deduplication decision event: %v", err)
 	}
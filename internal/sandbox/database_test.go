@@ -408,6 +408,67 @@ func TestMergeResults(t *testing.T) {
 	}
 }
 
+// TestMergeResultsChildStatus verifies that a child issue closed in the
+// sandbox is closed in the main database, and that a status change the main
+// database doesn't allow is skipped rather than failing the merge
+func TestMergeResultsChildStatus(t *testing.T) {
+	ctx := context.Background()
+
+	tmpDir := t.TempDir()
+	mainDB, err := storage.NewStorage(ctx, &storage.Config{Path: filepath.Join(tmpDir, "main.db")})
+	if err != nil {
+		t.Fatalf("failed to create main DB: %v", err)
+	}
+	defer func() { _ = mainDB.Close() }()
+
+	sandboxDB, err := storage.NewStorage(ctx, &storage.Config{Path: filepath.Join(tmpDir, "sandbox.db")})
+	if err != nil {
+		t.Fatalf("failed to create sandbox DB: %v", err)
+	}
+	defer func() { _ = sandboxDB.Close() }()
+
+	// A mission with two children, in both databases
+	issues := []*types.Issue{
+		{ID: "vc-500", Title: "Test Mission", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeEpic},
+		{ID: "vc-501", Title: "Finished child", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask},
+		{ID: "vc-502", Title: "Child closed meanwhile", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask},
+	}
+	for _, db := range []storage.Storage{mainDB, sandboxDB} {
+		for _, issue := range issues {
+			copied := *issue
+			if err := db.CreateIssue(ctx, &copied, "test"); err != nil {
+				t.Fatalf("failed to create %s: %v", issue.ID, err)
+			}
+		}
+	}
+
+	// The sandbox closes one child and starts the other, which was closed
+	// in the main database in the meantime
+	if err := sandboxDB.CloseIssue(ctx, "vc-501", "done", "agent"); err != nil {
+		t.Fatalf("failed to close child in sandbox: %v", err)
+	}
+	if err := sandboxDB.UpdateIssue(ctx, "vc-502", map[string]interface{}{"status": string(types.StatusInProgress)}, "agent"); err != nil {
+		t.Fatalf("failed to start child in sandbox: %v", err)
+	}
+	if err := mainDB.CloseIssue(ctx, "vc-502", "not needed", "human"); err != nil {
+		t.Fatalf("failed to close child in main DB: %v", err)
+	}
+
+	if err := mergeResults(ctx, sandboxDB, mainDB, "vc-500", nil); err != nil {
+		t.Fatalf("mergeResults failed: %v", err)
+	}
+
+	for id, want := range map[string]types.Status{"vc-501": types.StatusClosed, "vc-502": types.StatusClosed} {
+		issue, err := mainDB.GetIssue(ctx, id)
+		if err != nil {
+			t.Fatalf("failed to get %s: %v", id, err)
+		}
+		if issue.Status != want {
+			t.Errorf("%s: expected status %s, got %s", id, want, issue.Status)
+		}
+	}
+}
+
 func TestMergeResultsWithComments(t *testing.T) {
 	ctx := context.Background()
 
@@ -294,25 +294,25 @@ func validateGitRefName(name string) error {
 // This preserves code changes made during sandbox execution.
 // The merge is performed in the parent repository (not the worktree).
 //
-// Returns an error if the merge fails or if there are conflicts.
-// The caller should handle merge conflicts appropriately.
-func mergeBranchToMain(ctx context.Context, repoPath, branchName string) error {
+// Returns the merge commit's hash, or an error if the merge fails or if there
+// are conflicts. The caller should handle merge conflicts appropriately.
+func mergeBranchToMain(ctx context.Context, repoPath, branchName string) (string, error) {
 	mainBranch := "main"
 	// Validate repo is a git repository
 	if err := validateGitRepo(repoPath); err != nil {
-		return fmt.Errorf("repo validation failed: %w", err)
+		return "", fmt.Errorf("repo validation failed: %w", err)
 	}
 
 	// Validate branch name
 	if err := validateGitRefName(branchName); err != nil {
-		return fmt.Errorf("invalid branch name: %w", err)
+		return "", fmt.Errorf("invalid branch name: %w", err)
 	}
 
 	// Check if branch exists
 	checkCmd := exec.CommandContext(ctx, "git", "rev-parse", "--verify", branchName)
 	checkCmd.Dir = repoPath
 	if err := checkCmd.Run(); err != nil {
-		return fmt.Errorf("branch %s does not exist", branchName)
+		return "", fmt.Errorf("branch %s does not exist", branchName)
 	}
 
 	// Save current branch so we can return to it
@@ -320,7 +320,7 @@ func mergeBranchToMain(ctx context.Context, repoPath, branchName string) error {
 	getCurrentBranchCmd.Dir = repoPath
 	currentBranchOutput, err := getCurrentBranchCmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("failed to get current branch: %w (output: %s)", err, string(currentBranchOutput))
+		return "", fmt.Errorf("failed to get current branch: %w (output: %s)", err, string(currentBranchOutput))
 	}
 	currentBranch := strings.TrimSpace(string(currentBranchOutput))
 
@@ -329,7 +329,7 @@ func mergeBranchToMain(ctx context.Context, repoPath, branchName string) error {
 	checkoutCmd.Dir = repoPath
 	output, err := checkoutCmd.CombinedOutput()
 	if err != nil {
-		return fmt.Errorf("failed to checkout %s: %w (output: %s)", mainBranch, err, string(output))
+		return "", fmt.Errorf("failed to checkout %s: %w (output: %s)", mainBranch, err, string(output))
 	}
 
 	// Attempt the merge
@@ -341,7 +341,13 @@ func mergeBranchToMain(ctx context.Context, repoPath, branchName string) error {
 
 	// If merge succeeded, we're done
 	if mergeErr == nil {
-		return nil
+		hashCmd := exec.CommandContext(ctx, "git", "rev-parse", "HEAD")
+		hashCmd.Dir = repoPath
+		hash, err := hashCmd.Output()
+		if err != nil {
+			return "", fmt.Errorf("merged %s but failed to get the merge commit: %w", branchName, err)
+		}
+		return strings.TrimSpace(string(hash)), nil
 	}
 
 	// Merge failed - check if it's due to conflicts
@@ -363,12 +369,12 @@ func mergeBranchToMain(ctx context.Context, repoPath, branchName string) error {
 		abortCmd.Dir = repoPath
 		_ = abortCmd.Run() // Best-effort
 
-		return fmt.Errorf("merge conflicts detected when merging %s to %s: %s",
+		return "", fmt.Errorf("merge conflicts detected when merging %s to %s: %s",
 			branchName, mainBranch, string(mergeOutput))
 	}
 
 	// Some other merge error
-	return fmt.Errorf("git merge failed: %w (output: %s)", mergeErr, string(mergeOutput))
+	return "", fmt.Errorf("git merge failed: %w (output: %s)", mergeErr, string(mergeOutput))
 }
 
 // PruneWorktrees removes stale worktree administrative files.
@@ -762,9 +762,19 @@ func TestMergeBranchToMain(t *testing.T) {
 	}
 
 	// Merge the feature branch
-	if err := mergeBranchToMain(ctx, repo, "feature/test-merge"); err != nil {
+	mergeCommit, err := mergeBranchToMain(ctx, repo, "feature/test-merge")
+	if err != nil {
 		t.Fatalf("mergeBranchToMain failed: %v", err)
 	}
+	cmd = exec.Command("git", "rev-parse", "HEAD")
+	cmd.Dir = repo
+	head, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("Failed to get HEAD: %v", err)
+	}
+	if mergeCommit != strings.TrimSpace(string(head)) {
+		t.Errorf("Expected the merge commit to be HEAD %s, got %q", head, mergeCommit)
+	}
 
 	// Verify we're still on main
 	cmd = exec.Command("git", "branch", "--show-current")
@@ -870,7 +880,7 @@ func TestMergeBranchToMainWithConflicts(t *testing.T) {
 	}
 
 	// Attempt to merge - should fail with conflict error
-	err := mergeBranchToMain(ctx, repo, "feature/conflict-test")
+	_, err := mergeBranchToMain(ctx, repo, "feature/conflict-test")
 	if err == nil {
 		t.Fatal("mergeBranchToMain should fail with merge conflicts")
 	}
@@ -911,7 +921,7 @@ func TestMergeBranchToMainNonExistent(t *testing.T) {
 	ctx := context.Background()
 
 	// Attempt to merge non-existent branch
-	err := mergeBranchToMain(ctx, repo, "nonexistent-branch")
+	_, err := mergeBranchToMain(ctx, repo, "nonexistent-branch")
 	if err == nil {
 		t.Fatal("mergeBranchToMain should fail with non-existent branch")
 	}
@@ -10,7 +10,9 @@ import (
 	"time"
 
 	"github.com/steveyegge/vc/internal/deduplication"
+	"github.com/steveyegge/vc/internal/ignore"
 	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/types"
 )
 
 // Manager handles creation, management, and cleanup of sandboxed development environments.
@@ -46,6 +48,11 @@ type Manager interface {
 	// keeping only the most recent N as specified by retentionCount.
 	// If retentionCount is 0, all failed sandboxes are kept.
 	CleanupStaleFailedSandboxes(ctx context.Context, retentionCount int) error
+
+	// DiskUsage measures a sandbox's size in bytes for lifecycle metrics,
+	// skipping the paths the project's .vcignore ignores, within the
+	// configured time budget (see DiskUsage).
+	DiskUsage(path string) (int64, error)
 }
 
 // Config holds configuration for the sandbox manager
@@ -76,6 +83,14 @@ type Config struct {
 
 	// MaxAge is the maximum age for sandboxes before they're considered stale
 	MaxAge time.Duration
+
+	// Ignore holds the project's .vcignore rules, which DiskUsage skips
+	// Optional: if nil, only the built-in defaults are skipped
+	Ignore *ignore.Matcher
+
+	// DiskUsageBudget bounds how long DiskUsage walks a sandbox
+	// Optional: if zero, DefaultDiskUsageBudget is used
+	DiskUsageBudget time.Duration
 }
 
 // manager is the concrete implementation of Manager
@@ -112,6 +127,12 @@ func NewManager(cfg Config) (Manager, error) {
 	if cfg.MaxAge == 0 {
 		cfg.MaxAge = 24 * time.Hour // Default to 24 hours
 	}
+	if cfg.Ignore == nil {
+		cfg.Ignore = ignore.Defaults()
+	}
+	if cfg.DiskUsageBudget == 0 {
+		cfg.DiskUsageBudget = DefaultDiskUsageBudget
+	}
 
 	m := &manager{
 		config:          cfg,
@@ -319,12 +340,19 @@ func (m *manager) Cleanup(ctx context.Context, sandbox *Sandbox) error {
 	// This must happen AFTER merging database results but BEFORE deleting the branch
 	if sandbox.ApprovalStatus == "approved" {
 		fmt.Printf("Merging approved code changes from %s to main...\n", sandbox.GitBranch)
-		if err := mergeBranchToMain(ctx, sandbox.ParentRepo, sandbox.GitBranch); err != nil {
+		mergeCommit, err := mergeBranchToMain(ctx, sandbox.ParentRepo, sandbox.GitBranch)
+		if err != nil {
 			return fmt.Errorf("failed to merge code changes: %w", err)
 		}
+		sandbox.MergeCommit = mergeCommit
 		fmt.Printf("✓ Code changes merged to main\n")
+		if err := m.recordMergeCommit(ctx, sandbox.GitBranch, mergeCommit); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to record merge commit %s: %v\n", mergeCommit, err)
+		}
 	} else if sandbox.ApprovalStatus == "rejected" {
 		fmt.Printf("Skipping code merge - sandbox was rejected by human review\n")
+	} else if sandbox.ApprovalStatus == ApprovalStatusReviewRequired {
+		fmt.Printf("Skipping code merge - branch %s is kept for review\n", sandbox.GitBranch)
 	} else if sandbox.Status == SandboxStatusCompleted {
 		// Sandbox completed but no approval status set - log warning
 		fmt.Fprintf(os.Stderr, "warning: sandbox completed but no approval status set (branch %s will be deleted without merging)\n", sandbox.GitBranch)
@@ -343,8 +371,9 @@ func (m *manager) Cleanup(ctx context.Context, sandbox *Sandbox) error {
 			return fmt.Errorf("failed to remove worktree: %w", err)
 		}
 
-		// Delete mission branch unless KeepBranches is set (vc-134)
-		if !m.config.KeepBranches {
+		// Delete mission branch unless KeepBranches is set (vc-134) or
+		// it waits for review
+		if !m.config.KeepBranches && sandbox.ApprovalStatus != ApprovalStatusReviewRequired {
 			if err := deleteBranch(ctx, sandbox.ParentRepo, sandbox.GitBranch); err != nil {
 				// Log warning but don't fail - branch deletion is not critical
 				fmt.Fprintf(os.Stderr, "warning: failed to delete branch %s: %v\n", sandbox.GitBranch, err)
@@ -371,6 +400,33 @@ func (m *manager) Cleanup(ctx context.Context, sandbox *Sandbox) error {
 	return nil
 }
 
+// recordMergeCommit sets the merge commit on the issues closed by commits on
+// branch (the types.MetaClosingBranch metadata the executor records), so vc
+// list --closed-in finds them by the merge as well. A fast-forward merge
+// leaves the closing commits as they are.
+func (m *manager) recordMergeCommit(ctx context.Context, branch, mergeCommit string) error {
+	issues, err := m.config.MainDB.SearchIssues(ctx, "", types.IssueFilter{
+		MetaEquals:      map[string]string{types.MetaClosingBranch: string(types.MetaString(branch))},
+		IncludeArchived: true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to find issues closed on %s: %w", branch, err)
+	}
+	for _, issue := range issues {
+		meta, err := m.config.MainDB.GetIssueMeta(ctx, issue.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get metadata of %s: %w", issue.ID, err)
+		}
+		if _, merged := meta[types.MetaMergeCommit]; merged {
+			continue // Closed on an earlier branch of the same name
+		}
+		if err := m.config.MainDB.SetIssueMeta(ctx, issue.ID, types.MetaMergeCommit, types.MetaString(mergeCommit)); err != nil {
+			return fmt.Errorf("failed to record merge commit on %s: %w", issue.ID, err)
+		}
+	}
+	return nil
+}
+
 // CleanupAll removes all sandboxes older than the specified duration
 func (m *manager) CleanupAll(ctx context.Context, olderThan time.Duration) error {
 	m.mu.RLock()
@@ -397,6 +453,12 @@ func (m *manager) CleanupAll(ctx context.Context, olderThan time.Duration) error
 	return lastErr
 }
 
+// DiskUsage measures a sandbox's size, skipping .vcignore'd paths, within
+// the configured budget
+func (m *manager) DiskUsage(path string) (int64, error) {
+	return DiskUsage(path, m.config.Ignore, m.config.DiskUsageBudget)
+}
+
 // CleanupStaleFailedSandboxes removes old failed sandboxes from disk,
 // keeping only the most recent N as specified by retentionCount.
 // This implements the retention policy from vc-134.
@@ -0,0 +1,71 @@
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/steveyegge/vc/internal/ignore"
+)
+
+// DefaultDiskUsageBudget is how long DiskUsage may walk a sandbox when the
+// manager's Config sets no budget
+const DefaultDiskUsageBudget = 2 * time.Second
+
+// ErrDiskUsageBudget is returned by DiskUsage when the walk ran out of time
+var ErrDiskUsageBudget = errors.New("disk usage walk exceeded its time budget")
+
+// DiskUsage returns the total size in bytes of regular files under path.
+// It is a cheap, du-like walk intended for lifecycle metrics: symlinks are not
+// followed, entries that cannot be read are skipped rather than reported, and
+// paths ignored matches (relative to path, as in the project it checks out)
+// are neither descended into nor counted. A walk that takes longer than
+// budget (0 means no limit) stops with ErrDiskUsageBudget. Any other error
+// means path itself cannot be accessed.
+func DiskUsage(path string, ignored *ignore.Matcher, budget time.Duration) (int64, error) {
+	if _, err := os.Lstat(path); err != nil {
+		return 0, fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+
+	var deadline time.Time
+	if budget > 0 {
+		deadline = time.Now().Add(budget)
+	}
+
+	var total int64
+	err := filepath.WalkDir(path, func(entryPath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Unreadable entry - skip it (and its subtree if it is a directory)
+			if d != nil && d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			return ErrDiskUsageBudget
+		}
+		if relPath, relErr := filepath.Rel(path, entryPath); relErr == nil && ignored.Match(relPath, d.IsDir()) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil // File disappeared mid-walk
+		}
+		total += info.Size()
+		return nil
+	})
+	if errors.Is(err, ErrDiskUsageBudget) {
+		return 0, fmt.Errorf("measuring %s: %w (%s)", path, err, budget)
+	}
+
+	return total, nil
+}
@@ -0,0 +1,92 @@
+package sandbox
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/ignore"
+)
+
+func TestDiskUsage(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), make([]byte, 100), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "nested", "deeper"), 0755); err != nil {
+		t.Fatalf("Failed to create dirs: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "nested", "deeper", "b.txt"), make([]byte, 250), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	// Symlinks must not be followed or counted
+	if err := os.Symlink(filepath.Join(dir, "a.txt"), filepath.Join(dir, "link.txt")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	size, err := DiskUsage(dir, nil, 0)
+	if err != nil {
+		t.Fatalf("DiskUsage failed: %v", err)
+	}
+	if size != 350 {
+		t.Errorf("Expected 350 bytes, got %d", size)
+	}
+}
+
+func TestDiskUsage_MissingPath(t *testing.T) {
+	size, err := DiskUsage(filepath.Join(t.TempDir(), "does-not-exist"), nil, 0)
+	if err == nil {
+		t.Error("Expected error for missing path")
+	}
+	if size != 0 {
+		t.Errorf("Expected 0 bytes for missing path, got %d", size)
+	}
+}
+
+func TestDiskUsage_SkipsIgnored(t *testing.T) {
+	dir := t.TempDir()
+	for name, size := range map[string]int{
+		"main.go":               100,
+		"fixtures/huge.bin":     5000,
+		"node_modules/dep/a.js": 700,
+		"cache.tmp":             300,
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create dirs: %v", err)
+		}
+		if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+	matcher, err := ignore.NewWithDefaults([]string{"/fixtures/", "*.tmp"})
+	if err != nil {
+		t.Fatalf("Failed to compile patterns: %v", err)
+	}
+
+	size, err := DiskUsage(dir, matcher, 0)
+	if err != nil {
+		t.Fatalf("DiskUsage failed: %v", err)
+	}
+	if size != 100 {
+		t.Errorf("Expected 100 bytes with ignored paths skipped, got %d", size)
+	}
+}
+
+func TestDiskUsage_Budget(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), make([]byte, 100), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	size, err := DiskUsage(dir, nil, time.Nanosecond)
+	if !errors.Is(err, ErrDiskUsageBudget) {
+		t.Errorf("Expected ErrDiskUsageBudget, got %v", err)
+	}
+	if size != 0 {
+		t.Errorf("Expected 0 bytes for a walk over budget, got %d", size)
+	}
+}
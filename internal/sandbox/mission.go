@@ -15,11 +15,18 @@ import (
 	"github.com/steveyegge/vc/internal/types"
 )
 
+// MissionStore is the storage the mission sandbox functions need: the
+// mission itself and somewhere to log sandbox lifecycle events
+type MissionStore interface {
+	storage.IssueStore
+	storage.EventStore
+}
+
 // slugifyRegex is compiled once at package initialization for performance (vc-249)
 var slugifyRegex = regexp.MustCompile(`[^a-z0-9]+`)
 
 // logSandboxEvent creates and stores an event for sandbox lifecycle observability (vc-265)
-func logSandboxEvent(ctx context.Context, store storage.Storage, eventType events.EventType, severity events.EventSeverity, missionID, message string, data map[string]interface{}) {
+func logSandboxEvent(ctx context.Context, store storage.EventStore, eventType events.EventType, severity events.EventSeverity, missionID, message string, data map[string]interface{}) {
 	// Skip logging if context is canceled (e.g., during shutdown)
 	if ctx.Err() != nil {
 		return
@@ -114,7 +121,7 @@ func reconstructSandbox(ctx context.Context, m Manager, mission *types.Mission)
 //
 // This function is idempotent: calling it multiple times for the same mission
 // returns the existing sandbox if one already exists.
-func CreateMissionSandbox(ctx context.Context, manager Manager, store storage.Storage, missionID string) (*Sandbox, error) {
+func CreateMissionSandbox(ctx context.Context, manager Manager, store MissionStore, missionID string) (*Sandbox, error) {
 	startTime := time.Now()
 
 	// 1. Get mission metadata to generate stable paths
@@ -281,7 +288,7 @@ func slugify(s string) string {
 
 // CleanupMissionSandbox removes a mission sandbox and clears metadata.
 // This is called when a mission is closed or abandoned.
-func CleanupMissionSandbox(ctx context.Context, manager Manager, store storage.Storage, missionID string) error {
+func CleanupMissionSandbox(ctx context.Context, manager Manager, store MissionStore, missionID string) error {
 	startTime := time.Now()
 
 	// 1. Get mission metadata to find sandbox
@@ -417,7 +424,7 @@ func CleanupMissionSandbox(ctx context.Context, manager Manager, store storage.S
 // This function handles the executor restart scenario (vc-247, vc-250):
 // If metadata exists but sandbox not in manager's active list, it attempts to
 // reconstruct the sandbox from metadata + git state.
-func GetMissionSandbox(ctx context.Context, manager Manager, store storage.Storage, missionID string) (*Sandbox, error) {
+func GetMissionSandbox(ctx context.Context, manager Manager, store MissionStore, missionID string) (*Sandbox, error) {
 	// 1. Get mission metadata
 	mission, err := store.GetMission(ctx, missionID)
 	if err != nil {
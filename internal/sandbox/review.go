@@ -0,0 +1,155 @@
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// ApprovalStatusReviewRequired marks a sandbox whose changes wait for human
+// review instead of merging. Cleanup keeps its branch.
+const ApprovalStatusReviewRequired = "review_required"
+
+// FileDiff is one changed file of a sandbox diff. Binary files count no lines.
+type FileDiff struct {
+	Path    string
+	Added   int
+	Deleted int
+}
+
+// DiffSummary describes the changes in a sandbox worktree against HEAD
+type DiffSummary struct {
+	Files []FileDiff
+	Stat  string // git diff --stat output
+}
+
+// LinesChanged is the number of lines added and deleted
+func (d *DiffSummary) LinesChanged() int {
+	total := 0
+	for _, file := range d.Files {
+		total += file.Added + file.Deleted
+	}
+	return total
+}
+
+// Diff summarizes the uncommitted changes in a worktree, new files included.
+// New files are marked intent-to-add so git diff sees them.
+func Diff(ctx context.Context, worktreePath string) (*DiffSummary, error) {
+	if err := git(ctx, worktreePath, "add", "--all", "--intent-to-add"); err != nil {
+		return nil, err
+	}
+	numstat, err := gitOutput(ctx, worktreePath, "diff", "--numstat", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	stat, err := gitOutput(ctx, worktreePath, "diff", "--stat", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	return &DiffSummary{Files: parseNumstat(numstat), Stat: strings.TrimSpace(stat)}, nil
+}
+
+// BranchPatch returns the patch of everything the sandbox changed since its
+// branch left baseBranch: commits, uncommitted edits and new files (marked
+// intent-to-add, as Diff does).
+func BranchPatch(ctx context.Context, sb *Sandbox, baseBranch string) (string, error) {
+	if err := git(ctx, sb.GitWorktree, "add", "--all", "--intent-to-add"); err != nil {
+		return "", err
+	}
+	mergeBase, err := gitOutput(ctx, sb.GitWorktree, "merge-base", baseBranch, "HEAD")
+	if err != nil {
+		return "", err
+	}
+	return gitOutput(ctx, sb.GitWorktree, "diff", strings.TrimSpace(mergeBase))
+}
+
+// BranchFiles lists the paths the sandbox changed since its branch left
+// baseBranch, as BranchPatch diffs them
+func BranchFiles(ctx context.Context, sb *Sandbox, baseBranch string) ([]string, error) {
+	if err := git(ctx, sb.GitWorktree, "add", "--all", "--intent-to-add"); err != nil {
+		return nil, err
+	}
+	mergeBase, err := gitOutput(ctx, sb.GitWorktree, "merge-base", baseBranch, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	names, err := gitOutput(ctx, sb.GitWorktree, "diff", "--name-only", "-z", strings.TrimSpace(mergeBase))
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, name := range strings.Split(names, "\x00") {
+		if name != "" {
+			files = append(files, name)
+		}
+	}
+	return files, nil
+}
+
+// parseNumstat parses git diff --numstat output ("added<TAB>deleted<TAB>path",
+// with "-" counts for binary files)
+func parseNumstat(output string) []FileDiff {
+	var files []FileDiff
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		added, _ := strconv.Atoi(fields[0])
+		deleted, _ := strconv.Atoi(fields[1])
+		files = append(files, FileDiff{Path: fields[2], Added: added, Deleted: deleted})
+	}
+	return files
+}
+
+// PrepareForReview commits the sandbox's uncommitted changes to its mission
+// branch and, if the parent repository has an origin remote, pushes the
+// branch there for a reviewer. It reports whether the branch was pushed.
+func PrepareForReview(ctx context.Context, sb *Sandbox, message string) (bool, error) {
+	if err := git(ctx, sb.GitWorktree, "add", "--all"); err != nil {
+		return false, err
+	}
+	// diff --cached --quiet exits 1 when there is something to commit
+	err := git(ctx, sb.GitWorktree, "diff", "--cached", "--quiet")
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr) && exitErr.ExitCode() == 1:
+		if err := git(ctx, sb.GitWorktree, "commit", "-m", message); err != nil {
+			return false, err
+		}
+	case err != nil:
+		return false, err
+	}
+
+	if err := git(ctx, sb.ParentRepo, "remote", "get-url", "origin"); err != nil {
+		return false, nil // No remote: the kept branch is the review copy
+	}
+	if err := git(ctx, sb.GitWorktree, "push", "--set-upstream", "origin", sb.GitBranch); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// git runs a git command in dir
+func git(ctx context.Context, dir string, args ...string) error {
+	_, err := gitOutput(ctx, dir, args...)
+	return err
+}
+
+// gitOutput runs a git command in dir and returns its output
+func gitOutput(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", fmt.Errorf("git %s failed: %w (output: %s)", args[0], err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("git %s failed: %w", args[0], err)
+	}
+	return string(output), nil
+}
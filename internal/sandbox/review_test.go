@@ -0,0 +1,88 @@
+package sandbox
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseNumstat(t *testing.T) {
+	output := "10\t2\tinternal/parser/parser.go\n" +
+		"35\t0\tinternal/parser/parser_test.go\n" +
+		"-\t-\tdocs/diagram.png\n"
+	files := parseNumstat(output)
+	if len(files) != 3 {
+		t.Fatalf("Expected 3 files, got %+v", files)
+	}
+	if files[0] != (FileDiff{Path: "internal/parser/parser.go", Added: 10, Deleted: 2}) {
+		t.Errorf("Unexpected first file: %+v", files[0])
+	}
+	if files[2] != (FileDiff{Path: "docs/diagram.png"}) {
+		t.Errorf("Expected a binary file without line counts, got %+v", files[2])
+	}
+	summary := DiffSummary{Files: files}
+	if summary.LinesChanged() != 47 {
+		t.Errorf("Expected 47 lines changed, got %d", summary.LinesChanged())
+	}
+	if parseNumstat("") != nil {
+		t.Error("Expected no files for an empty diff")
+	}
+}
+
+func TestBranchPatch(t *testing.T) {
+	repo, cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	sandboxRoot := t.TempDir()
+	worktreePath, err := createWorktree(ctx, SandboxConfig{
+		MissionID:   "test-diff",
+		ParentRepo:  repo,
+		BaseBranch:  "main",
+		SandboxRoot: sandboxRoot,
+	}, "mission-test-diff")
+	if err != nil {
+		t.Fatalf("createWorktree failed: %v", err)
+	}
+	defer func() { _ = removeWorktree(ctx, repo, worktreePath) }()
+
+	// One committed change and one uncommitted
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = worktreePath
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v (%s)", args, err, output)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(worktreePath, "feature.go"), []byte("package feature\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run("add", "feature.go")
+	run("commit", "-m", "Add feature")
+	if err := os.WriteFile(filepath.Join(worktreePath, "README.md"), []byte("# Test Repo\n\nMore.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(worktreePath, "new.txt"), []byte("untracked\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := BranchPatch(ctx, &Sandbox{GitWorktree: worktreePath}, "main")
+	if err != nil {
+		t.Fatalf("BranchPatch failed: %v", err)
+	}
+	if !strings.Contains(diff, "+package feature") || !strings.Contains(diff, "+More.") || !strings.Contains(diff, "+untracked") {
+		t.Errorf("Expected the commit, the uncommitted change and the new file in the diff, got:\n%s", diff)
+	}
+
+	files, err := BranchFiles(ctx, &Sandbox{GitWorktree: worktreePath}, "main")
+	if err != nil {
+		t.Fatalf("BranchFiles failed: %v", err)
+	}
+	if strings.Join(files, " ") != "README.md feature.go new.txt" {
+		t.Errorf("Expected the paths of the same three changes, got %q", files)
+	}
+}
@@ -37,8 +37,12 @@ type Sandbox struct {
 	Status SandboxStatus
 
 	// ApprovalStatus tracks whether the human has approved merging this sandbox (vc-145)
-	// Values: "", "pending", "approved", "rejected"
+	// Values: "", "pending", "approved", "rejected", ApprovalStatusReviewRequired
 	ApprovalStatus string
+
+	// MergeCommit is the commit that merged GitBranch into main, set by
+	// Cleanup when it merges an approved sandbox
+	MergeCommit string
 }
 
 // SandboxStatus represents the lifecycle state of a sandbox
@@ -0,0 +1,177 @@
+// Package schedule files issues from recurring schedules (vc schedule).
+//
+// Each schedule handles one window at a time: when the window after the last
+// one handled has started, the scheduler claims it with a compare-and-set on
+// the schedule's run count, so two executors (or one with a skewed clock)
+// never file the same window twice. The claim records the current time, not
+// the window's, so an executor that was down over several windows files a
+// single catch-up issue instead of one per missed window.
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// Actor is the actor of the issues the scheduler files
+const Actor = "vc-scheduler"
+
+// Store is the storage the scheduler needs
+type Store interface {
+	ListSchedules(ctx context.Context) ([]*types.Schedule, error)
+	ClaimScheduleRun(ctx context.Context, id int64, runs int, runAt time.Time) (bool, error)
+	SetScheduleIssue(ctx context.Context, id int64, issueID string) error
+	GetIssue(ctx context.Context, id string) (*types.Issue, error)
+	CreateIssueWithMetadata(ctx context.Context, issue *types.Issue, labels []string, deps []*types.Dependency, actor string) error
+}
+
+// SpecOf returns when the schedule's windows start
+func SpecOf(s *types.Schedule) (Spec, error) {
+	if s.Cron != "" {
+		return ParseCron(s.Cron)
+	}
+	if s.Interval < types.MinScheduleInterval {
+		return nil, fmt.Errorf("interval must be at least %v (got %v)", types.MinScheduleInterval, s.Interval)
+	}
+	return intervalSpec{anchor: s.CreatedAt, every: s.Interval}, nil
+}
+
+// NextRun returns when the schedule's next window starts, or the zero time
+// if it never does
+func NextRun(s *types.Schedule) (time.Time, error) {
+	spec, err := SpecOf(s)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return spec.Next(lastRun(s)), nil
+}
+
+// Describe returns the schedule's cron expression or interval for display
+func Describe(s *types.Schedule) string {
+	if s.Cron != "" {
+		return s.Cron
+	}
+	return "every " + s.Interval.String()
+}
+
+// LabelOf returns the label linking issues back to their schedule
+func LabelOf(id int64) string {
+	return fmt.Sprintf("schedule:%d", id)
+}
+
+// Run files an issue for every enabled schedule whose window has started by
+// now and returns the issues filed. With a project, only that project's
+// schedules run. A failing schedule doesn't stop the others; their errors
+// are joined.
+func Run(ctx context.Context, store Store, now time.Time, project string) ([]*types.Issue, error) {
+	schedules, err := store.ListSchedules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list schedules: %w", err)
+	}
+	var filed []*types.Issue
+	var errs []error
+	for _, s := range schedules {
+		if !s.Enabled || (project != "" && projectOf(s) != project) {
+			continue
+		}
+		issue, err := runSchedule(ctx, store, s, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("schedule %d: %w", s.ID, err))
+			continue
+		}
+		if issue != nil {
+			filed = append(filed, issue)
+		}
+	}
+	return filed, errors.Join(errs...)
+}
+
+// runSchedule handles the schedule's next window if it has started, and
+// returns the issue filed for it, if any
+func runSchedule(ctx context.Context, store Store, s *types.Schedule, now time.Time) (*types.Issue, error) {
+	spec, err := SpecOf(s)
+	if err != nil {
+		return nil, err
+	}
+	from := lastRun(s)
+	next := spec.Next(from)
+	if next.IsZero() || next.After(now) {
+		return nil, nil
+	}
+	// Several windows have passed since the last run: this run stands in for
+	// all of them
+	catchUp := !spec.Next(next).After(now)
+
+	if s.Policy == types.ScheduleSkipOpen && s.LastIssueID != "" {
+		previous, err := store.GetIssue(ctx, s.LastIssueID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get previous issue %s: %w", s.LastIssueID, err)
+		}
+		if previous != nil && previous.Status != types.StatusClosed {
+			// Use up the window so the next one is measured from now
+			if _, err := store.ClaimScheduleRun(ctx, s.ID, s.Runs, now); err != nil {
+				return nil, err
+			}
+			return nil, nil
+		}
+	}
+
+	// Claim before filing: a failed create loses the window rather than
+	// risking a duplicate
+	claimed, err := store.ClaimScheduleRun(ctx, s.ID, s.Runs, now)
+	if err != nil || !claimed {
+		return nil, err
+	}
+	issue := &types.Issue{
+		Title:       s.Title,
+		Description: describeIssue(s, from, catchUp),
+		Status:      types.StatusOpen,
+		Priority:    s.Priority,
+		IssueType:   s.IssueType,
+		Project:     s.Project,
+	}
+	labels := append([]string{types.ScheduledLabel, LabelOf(s.ID)}, s.Labels...)
+	if err := store.CreateIssueWithMetadata(ctx, issue, labels, nil, Actor); err != nil {
+		return nil, fmt.Errorf("failed to create issue: %w", err)
+	}
+	if err := store.SetScheduleIssue(ctx, s.ID, issue.ID); err != nil {
+		return issue, err
+	}
+	return issue, nil
+}
+
+// describeIssue appends the backlink to the schedule, and a catch-up note if
+// windows were missed, to the template description
+func describeIssue(s *types.Schedule, from time.Time, catchUp bool) string {
+	var b strings.Builder
+	if s.Description != "" {
+		b.WriteString(s.Description)
+		b.WriteString("\n\n---\n")
+	}
+	fmt.Fprintf(&b, "Created by schedule %d (%s).", s.ID, Describe(s))
+	if catchUp {
+		fmt.Fprintf(&b, " Catches up on the windows missed since %s.", from.Format(time.RFC3339))
+	}
+	return b.String()
+}
+
+// lastRun returns when the schedule last handled a window, or its creation
+func lastRun(s *types.Schedule) time.Time {
+	if s.LastRunAt != nil {
+		return *s.LastRunAt
+	}
+	return s.CreatedAt
+}
+
+// projectOf returns the schedule's project, DefaultProject if unset
+func projectOf(s *types.Schedule) string {
+	if s.Project == "" {
+		return types.DefaultProject
+	}
+	return s.Project
+}
@@ -0,0 +1,186 @@
+package schedule
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/storage/storagetest"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// newSchedule creates an enabled schedule; tests run the scheduler at times
+// relative to its CreatedAt
+func newSchedule(t *testing.T, store *storagetest.FakeStorage, s *types.Schedule) *types.Schedule {
+	t.Helper()
+	if s.IssueType == "" {
+		s.IssueType = types.TypeChore
+	}
+	if s.Policy == "" {
+		s.Policy = types.ScheduleFileAnyway
+	}
+	s.Enabled = true
+	if err := store.CreateSchedule(context.Background(), s); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// run runs the scheduler at now, failing the test on error
+func run(t *testing.T, store *storagetest.FakeStorage, now time.Time) []*types.Issue {
+	t.Helper()
+	filed, err := Run(context.Background(), store, now, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return filed
+}
+
+func TestRunFilesDueIssues(t *testing.T) {
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+	s := newSchedule(t, store, &types.Schedule{Title: "Rotate keys", Description: "Rotate the API keys.",
+		Priority: 1, Labels: []string{"security"}, Interval: time.Hour})
+	start := s.CreatedAt
+
+	if filed := run(t, store, start.Add(59*time.Minute)); len(filed) != 0 {
+		t.Fatalf("Filed %d issues before the window started", len(filed))
+	}
+	filed := run(t, store, start.Add(time.Hour))
+	if len(filed) != 1 {
+		t.Fatalf("Expected one issue, got %d", len(filed))
+	}
+	issue := filed[0]
+	if issue.Title != "Rotate keys" || issue.Priority != 1 || issue.IssueType != types.TypeChore {
+		t.Errorf("Issue doesn't follow the template: %+v", issue)
+	}
+	if !strings.Contains(issue.Description, "Rotate the API keys.") || !strings.Contains(issue.Description, "Created by schedule 1") {
+		t.Errorf("Description lacks the template or the backlink:\n%s", issue.Description)
+	}
+	if strings.Contains(issue.Description, "Catches up") {
+		t.Errorf("On-time issue marked as a catch-up:\n%s", issue.Description)
+	}
+	labels, err := store.GetLabels(ctx, issue.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{types.ScheduledLabel, LabelOf(s.ID), "security"} {
+		if !slices.Contains(labels, want) {
+			t.Errorf("Issue lacks label %q: %v", want, labels)
+		}
+	}
+	got, err := store.GetSchedule(ctx, s.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.LastIssueID != issue.ID || got.Runs != 1 {
+		t.Errorf("Schedule not updated: %+v", got)
+	}
+
+	// The same window isn't filed twice
+	if filed := run(t, store, start.Add(time.Hour+time.Minute)); len(filed) != 0 {
+		t.Errorf("Filed the same window again")
+	}
+	if filed := run(t, store, start.Add(2*time.Hour)); len(filed) != 1 {
+		t.Errorf("Expected the next window filed anyway with the previous issue open, got %d", len(filed))
+	}
+}
+
+// TestRunCatchUp verifies an executor that was down over many windows files
+// a single catch-up issue
+func TestRunCatchUp(t *testing.T) {
+	store := storagetest.NewFakeStorage()
+	s := newSchedule(t, store, &types.Schedule{Title: "Triage", Cron: "0 * * * *"})
+
+	// Down over the weekend
+	monday := s.CreatedAt.Truncate(time.Hour).Add(64 * time.Hour)
+	filed := run(t, store, monday)
+	if len(filed) != 1 {
+		t.Fatalf("Expected one catch-up issue, got %d", len(filed))
+	}
+	if !strings.Contains(filed[0].Description, "Catches up") {
+		t.Errorf("Catch-up issue not marked:\n%s", filed[0].Description)
+	}
+	if filed := run(t, store, monday.Add(30*time.Minute)); len(filed) != 0 {
+		t.Errorf("Filed %d more issues within the same hour", len(filed))
+	}
+	if filed := run(t, store, monday.Add(time.Hour)); len(filed) != 1 {
+		t.Errorf("Expected the next hour filed, got %d", len(filed))
+	}
+}
+
+// TestRunClaimIsIdempotent verifies two schedulers racing over one window,
+// e.g. executors with skewed clocks, file it once
+func TestRunClaimIsIdempotent(t *testing.T) {
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+	s := newSchedule(t, store, &types.Schedule{Title: "Backup check", Interval: time.Hour})
+	start := s.CreatedAt
+
+	// Both schedulers read the schedule before either claims it
+	schedules, err := store.ListSchedules(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := runSchedule(ctx, store, schedules[0], start.Add(time.Hour))
+	if err != nil || first == nil {
+		t.Fatalf("Expected the first scheduler to file, got %v, %v", first, err)
+	}
+	second, err := runSchedule(ctx, store, schedules[0], start.Add(time.Hour+3*time.Minute))
+	if err != nil || second != nil {
+		t.Errorf("Expected the second scheduler to lose the claim, got %v, %v", second, err)
+	}
+}
+
+func TestRunSkipOpen(t *testing.T) {
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+	s := newSchedule(t, store, &types.Schedule{Title: "Review", Interval: time.Hour, Policy: types.ScheduleSkipOpen})
+	start := s.CreatedAt
+
+	filed := run(t, store, start.Add(time.Hour))
+	if len(filed) != 1 {
+		t.Fatalf("Expected one issue, got %d", len(filed))
+	}
+	if skipped := run(t, store, start.Add(2*time.Hour)); len(skipped) != 0 {
+		t.Fatalf("Filed another issue with the previous one open")
+	}
+	if err := store.CloseIssue(ctx, filed[0].ID, "done", "test"); err != nil {
+		t.Fatal(err)
+	}
+	// The skipped window was used up; the next one files again
+	if again := run(t, store, start.Add(2*time.Hour+30*time.Minute)); len(again) != 0 {
+		t.Errorf("Filed the skipped window after the previous issue closed")
+	}
+	if again := run(t, store, start.Add(3*time.Hour)); len(again) != 1 {
+		t.Errorf("Expected the next window filed after the previous issue closed, got %d", len(again))
+	}
+}
+
+func TestRunFilters(t *testing.T) {
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+	disabled := newSchedule(t, store, &types.Schedule{Title: "Off", Interval: time.Hour})
+	start := disabled.CreatedAt
+	if err := store.SetScheduleEnabled(ctx, disabled.ID, false); err != nil {
+		t.Fatal(err)
+	}
+	newSchedule(t, store, &types.Schedule{Title: "Default project", Interval: time.Hour})
+
+	filed, err := Run(ctx, store, start.Add(time.Hour+time.Minute), "other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(filed) != 0 {
+		t.Errorf("Executor of another project filed %d issues", len(filed))
+	}
+	filed, err = Run(ctx, store, start.Add(time.Hour+time.Minute), types.DefaultProject)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(filed) != 1 || filed[0].Title != "Default project" {
+		t.Errorf("Expected only the enabled default-project schedule filed, got %v", filed)
+	}
+}
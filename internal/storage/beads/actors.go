@@ -0,0 +1,167 @@
+package beads
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// ACTOR STATISTICS (per actor and executor instance)
+// ======================================================================
+
+// GetActorStatistics summarizes activity per actor since the given time,
+// sorted by actor. Issue and comment counts come from the Beads events table,
+// attempts from vc_execution_history and gate results from
+// quality_gates_completed agent events. Each query is bounded by since on an
+// indexed timestamp column, so the cost follows the window, not the size of
+// the event tables.
+func (s *VCStorage) GetActorStatistics(ctx context.Context, since time.Time) ([]*types.ActorStatistics, error) {
+	// Beads stamps events with CURRENT_TIMESTAMP, which is UTC
+	since = since.UTC()
+
+	byActor := make(map[string]*types.ActorStatistics)
+	get := func(actor string) *types.ActorStatistics {
+		if stats, ok := byActor[actor]; ok {
+			return stats
+		}
+		stats := &types.ActorStatistics{Actor: actor}
+		byActor[actor] = stats
+		return stats
+	}
+
+	// Issue activity (idx_events_created_at)
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT actor,
+		       SUM(CASE WHEN event_type = 'created' THEN 1 ELSE 0 END),
+		       SUM(CASE WHEN event_type = 'closed' THEN 1 ELSE 0 END),
+		       SUM(CASE WHEN event_type = 'commented' THEN 1 ELSE 0 END)
+		FROM events
+		WHERE created_at >= ?
+		GROUP BY actor
+	`, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query issue activity: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var actor string
+		var created, closed, comments int
+		if err := rows.Scan(&actor, &created, &closed, &comments); err != nil {
+			return nil, fmt.Errorf("failed to scan issue activity: %w", err)
+		}
+		if created+closed+comments == 0 {
+			continue
+		}
+		stats := get(actor)
+		stats.IssuesCreated = created
+		stats.IssuesClosed = closed
+		stats.Comments = comments
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating issue activity: %w", err)
+	}
+
+	// Execution attempts (idx_vc_history_started)
+	rows, err = s.db.QueryContext(ctx, `
+		SELECT executor_instance_id,
+		       COUNT(*),
+		       COALESCE(SUM(CASE WHEN success = 1 THEN 1 ELSE 0 END), 0),
+		       COALESCE(SUM(CASE WHEN completed_at IS NOT NULL AND success IS NOT NULL THEN 1 ELSE 0 END), 0),
+		       AVG(CASE WHEN completed_at IS NOT NULL
+		                THEN (julianday(completed_at) - julianday(started_at)) * 86400 END)
+		FROM vc_execution_history
+		WHERE started_at >= ?
+		  AND executor_instance_id IS NOT NULL
+		GROUP BY executor_instance_id
+	`, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query execution history: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var instanceID string
+		var attempts, succeeded, finished int
+		var avgDuration sql.NullFloat64
+		if err := rows.Scan(&instanceID, &attempts, &succeeded, &finished, &avgDuration); err != nil {
+			return nil, fmt.Errorf("failed to scan execution history: %w", err)
+		}
+		stats := get(instanceID)
+		stats.Executor = true
+		stats.ExecutionAttempts = attempts
+		stats.SuccessfulAttempts = succeeded
+		if finished > 0 {
+			stats.SuccessRate = float64(succeeded) / float64(finished)
+		}
+		if avgDuration.Valid {
+			stats.AverageDurationSeconds = avgDuration.Float64
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating execution history: %w", err)
+	}
+
+	// Quality gate results. Left alone, SQLite prefers the type index, which
+	// grows with every gate run ever recorded; pin the timestamp index.
+	rows, err = s.db.QueryContext(ctx, `
+		SELECT executor_id,
+		       COUNT(*),
+		       COALESCE(SUM(CASE WHEN json_extract(data, '$.all_passed') = 1 THEN 1 ELSE 0 END), 0)
+		FROM vc_agent_events INDEXED BY idx_vc_agent_events_timestamp
+		WHERE timestamp >= ?
+		  AND type = ?
+		  AND executor_id IS NOT NULL AND executor_id != ''
+		GROUP BY executor_id
+	`, since, string(events.EventTypeQualityGatesCompleted))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query gate results: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var executorID string
+		var runs, passed int
+		if err := rows.Scan(&executorID, &runs, &passed); err != nil {
+			return nil, fmt.Errorf("failed to scan gate results: %w", err)
+		}
+		stats := get(executorID)
+		stats.Executor = true
+		stats.GateRuns = runs
+		stats.GatesPassed = passed
+		stats.GatePassRate = float64(passed) / float64(runs)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating gate results: %w", err)
+	}
+
+	// Registered executor instances also act on issues (claims, closes)
+	rows, err = s.db.QueryContext(ctx, `SELECT id, hostname FROM vc_executor_instances`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query executor instances: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, hostname string
+		if err := rows.Scan(&id, &hostname); err != nil {
+			return nil, fmt.Errorf("failed to scan executor instance: %w", err)
+		}
+		if stats, ok := byActor[id]; ok {
+			stats.Executor = true
+			stats.Hostname = hostname
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating executor instances: %w", err)
+	}
+
+	result := make([]*types.ActorStatistics, 0, len(byActor))
+	for _, stats := range byActor {
+		result = append(result, stats)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Actor < result[j].Actor })
+	return result, nil
+}
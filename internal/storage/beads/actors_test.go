@@ -0,0 +1,132 @@
+package beads
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestGetActorStatistics(t *testing.T) {
+	ctx := context.Background()
+	store, parent := newCreateTestStore(t)
+	since := time.Now().Add(-time.Hour)
+
+	if err := store.RegisterInstance(ctx, &types.ExecutorInstance{
+		InstanceID: "exec-1", Hostname: "build-01", PID: 42, Status: types.ExecutorStatusRunning,
+		StartedAt: time.Now(), LastHeartbeat: time.Now(), Version: "test", Metadata: "{}",
+	}); err != nil {
+		t.Fatalf("RegisterInstance failed: %v", err)
+	}
+
+	// alice files two issues and comments; exec-1 closes one
+	var ids []string
+	for i := 0; i < 2; i++ {
+		issue := &types.Issue{Title: "task", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "alice"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		ids = append(ids, issue.ID)
+	}
+	if err := store.AddComment(ctx, ids[0], "alice", "please look at this"); err != nil {
+		t.Fatalf("AddComment failed: %v", err)
+	}
+	if err := store.CloseIssue(ctx, ids[0], "done", "exec-1"); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+	// Activity before the window is ignored
+	if _, err := store.db.ExecContext(ctx, `INSERT INTO events (issue_id, event_type, actor, created_at) VALUES (?, 'created', 'alice', ?)`,
+		parent.ID, time.Now().Add(-48*time.Hour).UTC()); err != nil {
+		t.Fatalf("Failed to insert old event: %v", err)
+	}
+
+	// exec-1: two finished attempts (one success) and one still running
+	yes, no := true, false
+	started := time.Now().Add(-30 * time.Minute)
+	attempts := []*types.ExecutionAttempt{
+		{IssueID: ids[0], ExecutorInstanceID: "exec-1", AttemptNumber: 1, StartedAt: started, Success: &no},
+		{IssueID: ids[0], ExecutorInstanceID: "exec-1", AttemptNumber: 2, StartedAt: started, Success: &yes},
+		{IssueID: ids[1], ExecutorInstanceID: "exec-1", AttemptNumber: 1, StartedAt: started},
+		{IssueID: ids[1], ExecutorInstanceID: "exec-1", AttemptNumber: 3, StartedAt: time.Now().Add(-48 * time.Hour), Success: &yes},
+	}
+	for _, attempt := range attempts {
+		if attempt.Success != nil {
+			completed := attempt.StartedAt.Add(10 * time.Minute)
+			if *attempt.Success {
+				completed = attempt.StartedAt.Add(20 * time.Minute)
+			}
+			attempt.CompletedAt = &completed
+		}
+		if err := store.RecordExecutionAttempt(ctx, attempt); err != nil {
+			t.Fatalf("RecordExecutionAttempt failed: %v", err)
+		}
+	}
+
+	// exec-1 ran gates three times, passing twice; exec-gone's instance row
+	// has since been cleaned up
+	gateRuns := []struct {
+		executor string
+		passed   bool
+	}{{"exec-1", true}, {"exec-1", false}, {"exec-1", true}, {"exec-gone", true}}
+	for _, run := range gateRuns {
+		event := &events.AgentEvent{
+			Type: events.EventTypeQualityGatesCompleted, Timestamp: time.Now(),
+			IssueID: ids[0], ExecutorID: run.executor, Severity: events.SeverityInfo, Message: "gates done",
+			Data: map[string]interface{}{"all_passed": run.passed},
+		}
+		if err := store.StoreAgentEvent(ctx, event); err != nil {
+			t.Fatalf("StoreAgentEvent failed: %v", err)
+		}
+	}
+
+	stats, err := store.GetActorStatistics(ctx, since)
+	if err != nil {
+		t.Fatalf("GetActorStatistics failed: %v", err)
+	}
+	byActor := make(map[string]*types.ActorStatistics)
+	var order []string
+	for _, s := range stats {
+		byActor[s.Actor] = s
+		order = append(order, s.Actor)
+	}
+	// "test" created the parent issue
+	if strings.Join(order, ",") != "alice,exec-1,exec-gone,test" {
+		t.Fatalf("Expected actors [alice exec-1 exec-gone test], got %v", order)
+	}
+
+	alice := byActor["alice"]
+	if alice.Executor || alice.IssuesCreated != 2 || alice.Comments != 1 || alice.IssuesClosed != 0 || alice.ExecutionAttempts != 0 {
+		t.Errorf("Unexpected stats for alice: %+v", alice)
+	}
+
+	exec := byActor["exec-1"]
+	if !exec.Executor || exec.Hostname != "build-01" {
+		t.Errorf("Expected exec-1 to be an executor on build-01, got %+v", exec)
+	}
+	if exec.IssuesClosed != 1 || exec.ExecutionAttempts != 3 || exec.SuccessfulAttempts != 1 || exec.SuccessRate != 0.5 {
+		t.Errorf("Unexpected attempt stats for exec-1: %+v", exec)
+	}
+	if d := exec.AverageDurationSeconds; d < 899 || d > 901 {
+		t.Errorf("Expected an average duration of 15m, got %.1fs", d)
+	}
+	if exec.GateRuns != 3 || exec.GatesPassed != 2 || exec.GatePassRate < 0.66 || exec.GatePassRate > 0.67 {
+		t.Errorf("Unexpected gate stats for exec-1: %+v", exec)
+	}
+
+	gone := byActor["exec-gone"]
+	if !gone.Executor || gone.Hostname != "" || gone.GatePassRate != 1 || gone.ExecutionAttempts != 0 {
+		t.Errorf("Unexpected stats for exec-gone: %+v", gone)
+	}
+
+	// Nothing happened in the future
+	stats, err = store.GetActorStatistics(ctx, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("GetActorStatistics failed: %v", err)
+	}
+	if len(stats) != 0 {
+		t.Errorf("Expected no activity, got %d actors", len(stats))
+	}
+}
@@ -0,0 +1,171 @@
+package beads
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// ARCHIVED ISSUES (soft delete, VC extension table)
+// ======================================================================
+
+// ArchiveIssue hides an issue from SearchIssues, GetReadyWork, GetStatistics
+// and the dependency queries without deleting it or its history. GetIssue
+// still returns it (with Archived set). A comment records who archived it.
+// Archiving twice is a no-op.
+func (s *VCStorage) ArchiveIssue(ctx context.Context, id string, actor string) error {
+	issue, err := s.Storage.GetIssue(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get issue: %w", err)
+	}
+	if issue == nil {
+		return fmt.Errorf("issue %s not found", id)
+	}
+
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO vc_archived_issues (issue_id, archived_at, archived_by)
+		VALUES (?, ?, ?)
+		ON CONFLICT(issue_id) DO NOTHING
+	`, id, time.Now(), actor)
+	if err != nil {
+		return fmt.Errorf("failed to archive issue %s: %w", id, err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if rows == 0 {
+		return nil // Already archived
+	}
+	if err := s.AddComment(ctx, id, actor, "Archived by "+actor); err != nil {
+		return fmt.Errorf("archived %s but failed to comment: %w", id, err)
+	}
+	return nil
+}
+
+// UnarchiveIssue makes an archived issue visible to queries again, with a
+// comment recording who brought it back
+func (s *VCStorage) UnarchiveIssue(ctx context.Context, id string, actor string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM vc_archived_issues WHERE issue_id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to unarchive issue %s: %w", id, err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("issue %s is not archived", id)
+	}
+	if err := s.AddComment(ctx, id, actor, "Unarchived by "+actor); err != nil {
+		return fmt.Errorf("unarchived %s but failed to comment: %w", id, err)
+	}
+	return nil
+}
+
+// isArchived reports whether a single issue is archived
+func (s *VCStorage) isArchived(ctx context.Context, id string) (bool, error) {
+	var count int
+	err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM vc_archived_issues WHERE issue_id = ?`, id).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check archived state: %w", err)
+	}
+	return count > 0, nil
+}
+
+// archivedIssueIDs returns the set of archived issue IDs. Archives are
+// expected to stay small relative to the issue count, so queries delegated
+// to Beads are filtered in memory.
+func (s *VCStorage) archivedIssueIDs(ctx context.Context) (map[string]bool, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT issue_id FROM vc_archived_issues`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query archived issues: %w", err)
+	}
+	defer rows.Close()
+
+	archived := make(map[string]bool)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan archived issue: %w", err)
+		}
+		archived[id] = true
+	}
+	return archived, rows.Err()
+}
+
+// withoutArchived drops archived issues, keeping at most limit (0 = all)
+func withoutArchived(issues []*types.Issue, archived map[string]bool, limit int) []*types.Issue {
+	if len(archived) == 0 {
+		return issues
+	}
+	kept := issues[:0]
+	for _, issue := range issues {
+		if archived[issue.ID] {
+			continue
+		}
+		if limit > 0 && len(kept) == limit {
+			break
+		}
+		kept = append(kept, issue)
+	}
+	return kept
+}
+
+// archivedStatistics counts the archived issues, and the SYSTEM pseudo-issue,
+// in each statistic, using the same definitions as Beads' GetStatistics, so
+// they can be subtracted
+func (s *VCStorage) archivedStatistics(ctx context.Context) (*types.Statistics, error) {
+	var stats types.Statistics
+	err := s.db.QueryRowContext(ctx, `
+		SELECT
+			COUNT(*),
+			COALESCE(SUM(CASE WHEN i.status = 'open' THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN i.status = 'in_progress' THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN i.status = 'closed' THEN 1 ELSE 0 END), 0)
+		FROM issues i
+		WHERE i.id IN (SELECT issue_id FROM vc_archived_issues) OR i.id = ?
+	`, types.SystemIssueID).Scan(&stats.TotalIssues, &stats.OpenIssues, &stats.InProgressIssues, &stats.ClosedIssues)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count archived issues: %w", err)
+	}
+	if stats.TotalIssues == stats.ClosedIssues {
+		// Closed issues are never blocked or ready
+		return &stats, nil
+	}
+
+	err = s.db.QueryRowContext(ctx, `
+		SELECT COUNT(DISTINCT i.id)
+		FROM issues i
+		JOIN dependencies d ON i.id = d.issue_id
+		JOIN issues blocker ON d.depends_on_id = blocker.id
+		WHERE (i.id IN (SELECT issue_id FROM vc_archived_issues) OR i.id = ?)
+		  AND i.status IN ('open', 'in_progress', 'blocked')
+		  AND d.type = 'blocks'
+		  AND blocker.status IN ('open', 'in_progress', 'blocked')
+	`, types.SystemIssueID).Scan(&stats.BlockedIssues)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count archived blocked issues: %w", err)
+	}
+
+	err = s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*)
+		FROM issues i
+		WHERE (i.id IN (SELECT issue_id FROM vc_archived_issues) OR i.id = ?)
+		  AND i.status = 'open'
+		  AND NOT EXISTS (
+		    SELECT 1 FROM dependencies d
+		    JOIN issues blocked ON d.depends_on_id = blocked.id
+		    WHERE d.issue_id = i.id
+		      AND d.type = 'blocks'
+		      AND blocked.status IN ('open', 'in_progress', 'blocked')
+		  )
+	`, types.SystemIssueID).Scan(&stats.ReadyIssues)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count archived ready issues: %w", err)
+	}
+	return &stats, nil
+}
@@ -0,0 +1,159 @@
+package beads
+
+import (
+	"context"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func issueIDs(issues []*types.Issue) map[string]bool {
+	ids := make(map[string]bool, len(issues))
+	for _, issue := range issues {
+		ids[issue.ID] = true
+	}
+	return ids
+}
+
+func TestArchiveIssue(t *testing.T) {
+	ctx := context.Background()
+	store, parent := newCreateTestStore(t)
+
+	newIssue := func(title string) *types.Issue {
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		return issue
+	}
+	old := newIssue("Old")
+	kept := newIssue("Kept")
+	blocker := newIssue("Blocker")
+	for _, dep := range []*types.Dependency{
+		{IssueID: parent.ID, DependsOnID: old.ID, Type: types.DepRelated},
+		{IssueID: kept.ID, DependsOnID: blocker.ID, Type: types.DepBlocks},
+	} {
+		if err := store.AddDependency(ctx, dep, "test"); err != nil {
+			t.Fatalf("AddDependency failed: %v", err)
+		}
+	}
+	for _, issue := range []*types.Issue{old, kept} {
+		if err := store.AddLabel(ctx, issue.ID, "discovered:blocker", "test"); err != nil {
+			t.Fatalf("AddLabel failed: %v", err)
+		}
+	}
+
+	before, err := store.GetStatistics(ctx)
+	if err != nil {
+		t.Fatalf("GetStatistics failed: %v", err)
+	}
+
+	if err := store.ArchiveIssue(ctx, old.ID, "test"); err != nil {
+		t.Fatalf("ArchiveIssue failed: %v", err)
+	}
+	if err := store.ArchiveIssue(ctx, old.ID, "test"); err != nil {
+		t.Errorf("Archiving twice should be a no-op, got %v", err)
+	}
+	if err := store.ArchiveIssue(ctx, "vc-9999", "test"); err == nil {
+		t.Error("Expected archiving a missing issue to fail")
+	}
+
+	// Still readable by ID, flagged as archived
+	got, err := store.GetIssue(ctx, old.ID)
+	if err != nil || got == nil || !got.Archived {
+		t.Fatalf("Expected archived issue from GetIssue, got %+v (%v)", got, err)
+	}
+
+	issues, err := store.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		t.Fatalf("SearchIssues failed: %v", err)
+	}
+	if ids := issueIDs(issues); ids[old.ID] || len(ids) != 3 {
+		t.Errorf("Expected archived issue to be hidden, got %v", ids)
+	}
+	// The limit counts visible issues only
+	issues, err = store.SearchIssues(ctx, "", types.IssueFilter{Limit: 3})
+	if err != nil || len(issues) != 3 || issueIDs(issues)[old.ID] {
+		t.Errorf("Expected 3 visible issues with limit 3, got %d (%v)", len(issues), err)
+	}
+	issues, err = store.SearchIssues(ctx, "", types.IssueFilter{IncludeArchived: true})
+	if err != nil {
+		t.Fatalf("SearchIssues failed: %v", err)
+	}
+	for _, issue := range issues {
+		if issue.Archived != (issue.ID == old.ID) {
+			t.Errorf("Wrong archived flag on %s: %v", issue.ID, issue.Archived)
+		}
+	}
+	if len(issues) != 4 {
+		t.Errorf("Expected 4 issues including archived, got %d", len(issues))
+	}
+
+	ready, err := store.GetReadyWork(ctx, types.WorkFilter{Status: types.StatusOpen, Limit: 10})
+	if err != nil {
+		t.Fatalf("GetReadyWork failed: %v", err)
+	}
+	if issueIDs(ready)[old.ID] {
+		t.Error("Archived issue returned as ready work")
+	}
+	blockers, err := store.GetReadyBlockers(ctx, types.WorkFilter{Limit: 10})
+	if err != nil {
+		t.Fatalf("GetReadyBlockers failed: %v", err)
+	}
+	if issueIDs(blockers)[old.ID] {
+		t.Error("Archived issue returned as ready blocker")
+	}
+	labeled, err := store.GetIssuesByLabel(ctx, "discovered:blocker")
+	if err != nil || len(labeled) != 1 || labeled[0].ID != kept.ID {
+		t.Errorf("Expected only %s by label, got %v (%v)", kept.ID, issueIDs(labeled), err)
+	}
+
+	deps, err := store.GetDependencies(ctx, parent.ID)
+	if err != nil || len(deps) != 0 {
+		t.Errorf("Expected archived dependency to be hidden, got %v (%v)", issueIDs(deps), err)
+	}
+	dependents, err := store.GetDependents(ctx, old.ID)
+	if err != nil || len(dependents) != 1 {
+		t.Errorf("Expected the dependent of an archived issue to stay visible, got %v (%v)", issueIDs(dependents), err)
+	}
+	tree, err := store.GetDependencyTree(ctx, parent.ID, 10)
+	if err != nil {
+		t.Fatalf("GetDependencyTree failed: %v", err)
+	}
+	for _, node := range tree {
+		if node.ID == old.ID {
+			t.Error("Archived issue in dependency tree")
+		}
+	}
+
+	after, err := store.GetStatistics(ctx)
+	if err != nil {
+		t.Fatalf("GetStatistics failed: %v", err)
+	}
+	if after.TotalIssues != before.TotalIssues-1 || after.OpenIssues != before.OpenIssues-1 || after.ReadyIssues != before.ReadyIssues-1 {
+		t.Errorf("Expected statistics to drop the archived issue: before %+v, after %+v", before, after)
+	}
+
+	// Archiving the blocker hides it, but kept is still blocked
+	if err := store.ArchiveIssue(ctx, blocker.ID, "test"); err != nil {
+		t.Fatalf("ArchiveIssue failed: %v", err)
+	}
+	blocked, err := store.GetBlockedIssues(ctx)
+	if err != nil {
+		t.Fatalf("GetBlockedIssues failed: %v", err)
+	}
+	if len(blocked) != 1 || blocked[0].ID != kept.ID {
+		t.Errorf("Expected %s to stay blocked, got %+v", kept.ID, blocked)
+	}
+
+	if err := store.UnarchiveIssue(ctx, old.ID, "test"); err != nil {
+		t.Fatalf("UnarchiveIssue failed: %v", err)
+	}
+	if err := store.UnarchiveIssue(ctx, old.ID, "test"); err == nil {
+		t.Error("Expected unarchiving a visible issue to fail")
+	}
+	issues, err = store.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil || !issueIDs(issues)[old.ID] {
+		t.Errorf("Expected unarchived issue to be visible again (%v)", err)
+	}
+}
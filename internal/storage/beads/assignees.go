@@ -0,0 +1,73 @@
+package beads
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// ASSIGNEES (who may take an issue)
+// ======================================================================
+
+// An issue assigned to a human isn't ready work for the executors unless it
+// has the agent-ok label. A claim puts the executor's short ID in the
+// assignee and keeps the previous one in vc_issue_execution_state, and
+// releasing the claim puts it back.
+
+// humanAssignedSQL matches issues rows aliased %[1]s that are assigned to a
+// human (see types.AgentAssignable) and not labeled agent-ok. An executor's
+// short ID only stays behind when a release failed to restore the assignee.
+var humanAssignedSQL = `COALESCE(%[1]s.assignee, '') NOT IN (` + agentAssigneesSQL() + `)
+	AND %[1]s.assignee NOT IN (SELECT substr(id, 1, 8) FROM vc_executor_instances)
+	AND NOT EXISTS (SELECT 1 FROM labels al WHERE al.issue_id = %[1]s.id AND al.label = '` + types.AgentOKLabel + `')`
+
+// agentAssigneesSQL lists types.AgentAssignees as the body of an SQL tuple
+func agentAssigneesSQL() string {
+	quoted := make([]string, 0, len(types.AgentAssignees()))
+	for _, assignee := range types.AgentAssignees() {
+		quoted = append(quoted, "'"+assignee+"'")
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// assignToExecutor saves the issue's assignee in its execution state and
+// replaces it with the claiming executor's short ID as of now
+func assignToExecutor(ctx context.Context, db execer, issueID, executorInstanceID string, now time.Time) error {
+	_, err := db.ExecContext(ctx, `
+		UPDATE vc_issue_execution_state
+		SET previous_assignee = (SELECT assignee FROM issues WHERE id = ?)
+		WHERE issue_id = ?
+	`, issueID, issueID)
+	if err != nil {
+		return fmt.Errorf("failed to save assignee: %w", err)
+	}
+	_, err = db.ExecContext(ctx, `UPDATE issues SET assignee = ?, updated_at = ? WHERE id = ?`,
+		types.ExecutorShortID(executorInstanceID), now, issueID)
+	if err != nil {
+		return fmt.Errorf("failed to assign issue to executor: %w", err)
+	}
+	return nil
+}
+
+// restoreAssignee gives a released issue back the assignee it had before
+// its claim, unless someone reassigned it in the meantime. It must run
+// before the claim's execution state is deleted or cleared.
+func restoreAssignee(ctx context.Context, db execer, issueID string) error {
+	_, err := db.ExecContext(ctx, `
+		UPDATE issues
+		SET assignee = (SELECT previous_assignee FROM vc_issue_execution_state WHERE issue_id = ?),
+		    updated_at = ?
+		WHERE id = ?
+		  AND assignee = (
+		    SELECT substr(executor_instance_id, 1, 8) FROM vc_issue_execution_state WHERE issue_id = ?
+		  )
+	`, issueID, time.Now(), issueID, issueID)
+	if err != nil {
+		return fmt.Errorf("failed to restore assignee of %s: %w", issueID, err)
+	}
+	return nil
+}
@@ -0,0 +1,393 @@
+package beads
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+	"modernc.org/sqlite"
+)
+
+// ======================================================================
+// BACKUP AND RESTORE
+// ======================================================================
+
+// BackupManifestSuffix is appended to a backup's path to name its manifest
+const BackupManifestSuffix = ".manifest.json"
+
+// backupFilePrefix and backupFileExt name backups made by BackupFileName,
+// which PruneBackups relies on
+const (
+	backupFilePrefix = "vc-backup-"
+	backupFileExt    = ".db"
+)
+
+// executorHeartbeatWindow is how recent a running executor's heartbeat must
+// be for RestoreBackup to consider it alive (the executor's default
+// StaleThreshold)
+const executorHeartbeatWindow = 5 * time.Minute
+
+// ErrExecutorActive is returned by RestoreBackup when an executor is still
+// heartbeating against the database it would replace
+var ErrExecutorActive = errors.New("an executor is running against the database")
+
+// BackupManifest describes a backup. It is written next to the backup and
+// checked against it before a restore.
+type BackupManifest struct {
+	CreatedAt     time.Time `json:"created_at"`
+	SourcePath    string    `json:"source_path"`
+	SchemaVersion int       `json:"schema_version"`
+	Issues        int       `json:"issues"` // Not counting the SYSTEM pseudo-issue
+	AgentEvents   int       `json:"agent_events"`
+	SizeBytes     int64     `json:"size_bytes"`
+}
+
+// DefaultBackupDir is where backups of the database at dbPath go by
+// default: a backups directory next to it (.beads/backups)
+func DefaultBackupDir(dbPath string) string {
+	return filepath.Join(filepath.Dir(dbPath), "backups")
+}
+
+// BackupFileName returns the timestamped file name for a backup taken at t
+func BackupFileName(t time.Time) string {
+	return backupFilePrefix + t.UTC().Format("20060102T150405Z") + backupFileExt
+}
+
+// backuper is implemented by modernc.org/sqlite's driver connection
+type backuper interface {
+	NewBackup(dstURI string) (*sqlite.Backup, error)
+}
+
+// Backup writes a consistent copy of the database to destPath using SQLite's
+// online backup API, so it is safe while executors are writing, and writes
+// a manifest next to it. destPath must not exist.
+func (s *VCStorage) Backup(ctx context.Context, destPath string) (*BackupManifest, error) {
+	if _, err := os.Stat(destPath); err == nil {
+		return nil, fmt.Errorf("backup %s already exists", destPath)
+	}
+	if err := os.MkdirAll(filepath.Dir(destPath), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	// Copy to a temporary name so a failed backup never looks complete
+	partialPath := destPath + ".partial"
+	_ = os.Remove(partialPath)
+	defer func() { _ = os.Remove(partialPath) }()
+
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	err = conn.Raw(func(driverConn interface{}) error {
+		source, ok := driverConn.(backuper)
+		if !ok {
+			return fmt.Errorf("SQLite driver does not support online backup")
+		}
+		backup, err := source.NewBackup(plainDSN(partialPath, "rwc"))
+		if err != nil {
+			return err
+		}
+		// A negative step copies every page in one pass, under a single
+		// read transaction, so concurrent writes can't restart the copy
+		for more := true; more; {
+			if more, err = backup.Step(-1); err != nil {
+				_ = backup.Finish()
+				return err
+			}
+		}
+		return backup.Finish()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to back up database: %w", err)
+	}
+	if err := useRollbackJournal(ctx, partialPath); err != nil {
+		return nil, err
+	}
+
+	manifest, err := inspectBackup(ctx, partialPath)
+	if err != nil {
+		return nil, err
+	}
+	manifest.CreatedAt = time.Now().UTC()
+	manifest.SourcePath = s.dbPath
+
+	if err := os.Rename(partialPath, destPath); err != nil {
+		return nil, fmt.Errorf("failed to move backup into place: %w", err)
+	}
+	if err := writeBackupManifest(destPath, manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// useRollbackJournal switches a backup out of WAL mode, which it inherits
+// from the database's header. Read-only connections can't clean up after a
+// WAL database, so inspecting it would leave -wal and -shm files behind.
+func useRollbackJournal(ctx context.Context, path string) error {
+	db, err := sql.Open("sqlite", plainDSN(path, "rwc"))
+	if err != nil {
+		return fmt.Errorf("failed to open backup: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+	if _, err := db.ExecContext(ctx, `PRAGMA journal_mode = DELETE`); err != nil {
+		return fmt.Errorf("failed to switch backup to a rollback journal: %w", err)
+	}
+	return nil
+}
+
+// inspectBackup checks a database file's integrity and reads the schema
+// version and counts recorded in a manifest
+func inspectBackup(ctx context.Context, path string) (*BackupManifest, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat backup: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", plainDSN(path, "ro"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open backup: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	var check string
+	if err := db.QueryRowContext(ctx, `PRAGMA quick_check`).Scan(&check); err != nil {
+		return nil, fmt.Errorf("failed to check backup integrity: %w", err)
+	}
+	if check != "ok" {
+		return nil, fmt.Errorf("backup failed integrity check: %s", check)
+	}
+
+	manifest := &BackupManifest{SizeBytes: info.Size()}
+	for _, q := range []struct {
+		query string
+		args  []interface{}
+		dest  *int
+	}{
+		{`SELECT COALESCE(MAX(version), 0) FROM vc_schema_migrations`, nil, &manifest.SchemaVersion},
+		{`SELECT COUNT(*) FROM issues WHERE id != ?`, []interface{}{types.SystemIssueID}, &manifest.Issues},
+		{`SELECT COUNT(*) FROM vc_agent_events`, nil, &manifest.AgentEvents},
+	} {
+		if err := db.QueryRowContext(ctx, q.query, q.args...).Scan(q.dest); err != nil {
+			return nil, fmt.Errorf("failed to inspect backup: %w", err)
+		}
+	}
+	return manifest, nil
+}
+
+// writeBackupManifest writes the manifest for the backup at backupPath
+func writeBackupManifest(backupPath string, manifest *BackupManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode backup manifest: %w", err)
+	}
+	manifestPath := backupPath + BackupManifestSuffix
+	if err := os.WriteFile(manifestPath+".partial", append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write backup manifest: %w", err)
+	}
+	if err := os.Rename(manifestPath+".partial", manifestPath); err != nil {
+		return fmt.Errorf("failed to write backup manifest: %w", err)
+	}
+	return nil
+}
+
+// ReadBackupManifest reads the manifest written next to a backup
+func ReadBackupManifest(backupPath string) (*BackupManifest, error) {
+	data, err := os.ReadFile(backupPath + BackupManifestSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup manifest: %w", err)
+	}
+	var manifest BackupManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid backup manifest: %w", err)
+	}
+	return &manifest, nil
+}
+
+// VerifyBackup checks a backup against its manifest: the file must pass an
+// integrity check, match the recorded schema version and counts, and not be
+// from a newer schema than this binary supports.
+func VerifyBackup(ctx context.Context, backupPath string) (*BackupManifest, error) {
+	manifest, err := ReadBackupManifest(backupPath)
+	if err != nil {
+		return nil, err
+	}
+	if latest := LatestSchemaVersion(); manifest.SchemaVersion > latest {
+		return nil, &SchemaVersionError{DatabaseVersion: manifest.SchemaVersion, SupportedVersion: latest}
+	}
+
+	actual, err := inspectBackup(ctx, backupPath)
+	if err != nil {
+		return nil, err
+	}
+	if actual.SchemaVersion != manifest.SchemaVersion || actual.Issues != manifest.Issues ||
+		actual.AgentEvents != manifest.AgentEvents || actual.SizeBytes != manifest.SizeBytes {
+		return nil, fmt.Errorf("backup does not match its manifest (schema %d, %d issues, %d events, %d bytes; manifest says schema %d, %d issues, %d events, %d bytes)",
+			actual.SchemaVersion, actual.Issues, actual.AgentEvents, actual.SizeBytes,
+			manifest.SchemaVersion, manifest.Issues, manifest.AgentEvents, manifest.SizeBytes)
+	}
+	return manifest, nil
+}
+
+// RestoreBackup replaces the database at dbPath with a verified backup. It
+// refuses (with ErrExecutorActive) while an executor is heartbeating against
+// dbPath. The backup is staged next to dbPath and renamed over it, so the
+// database is never half-written; the replaced database is kept as
+// dbPath + ".pre-restore".
+//
+// Other vc processes must not have dbPath open during a restore: they would
+// keep using the replaced file.
+func RestoreBackup(ctx context.Context, backupPath, dbPath string) (*BackupManifest, error) {
+	manifest, err := VerifyBackup(ctx, backupPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := os.Stat(dbPath); err == nil {
+		if err := quiesceDatabase(ctx, dbPath); err != nil {
+			return nil, err
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to stat database: %w", err)
+	}
+
+	stagedPath := dbPath + ".restore"
+	if err := copyFileSync(backupPath, stagedPath); err != nil {
+		_ = os.Remove(stagedPath)
+		return nil, fmt.Errorf("failed to stage backup: %w", err)
+	}
+
+	if _, err := os.Stat(dbPath); err == nil {
+		previousPath := dbPath + ".pre-restore"
+		_ = os.Remove(previousPath)
+		if err := os.Link(dbPath, previousPath); err != nil {
+			if err := copyFileSync(dbPath, previousPath); err != nil {
+				_ = os.Remove(stagedPath)
+				return nil, fmt.Errorf("failed to keep the replaced database: %w", err)
+			}
+		}
+	}
+
+	if err := os.Rename(stagedPath, dbPath); err != nil {
+		_ = os.Remove(stagedPath)
+		return nil, fmt.Errorf("failed to swap in restored database: %w", err)
+	}
+	// The old WAL (checkpointed and empty) must not be replayed into the
+	// restored database
+	for _, suffix := range []string{"-wal", "-shm"} {
+		_ = os.Remove(dbPath + suffix)
+	}
+	return manifest, nil
+}
+
+// quiesceDatabase refuses to continue while an executor is heartbeating
+// against dbPath, then checkpoints its WAL into the main file
+func quiesceDatabase(ctx context.Context, dbPath string) error {
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	var hasInstances bool
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) > 0 FROM sqlite_master WHERE type = 'table' AND name = 'vc_executor_instances'`).Scan(&hasInstances); err != nil {
+		return fmt.Errorf("failed to inspect database: %w", err)
+	}
+	if hasInstances {
+		rows, err := db.QueryContext(ctx, `SELECT id, hostname, last_heartbeat FROM vc_executor_instances WHERE status = 'running'`)
+		if err != nil {
+			return fmt.Errorf("failed to check executor instances: %w", err)
+		}
+		defer rows.Close()
+		cutoff := time.Now().Add(-executorHeartbeatWindow)
+		for rows.Next() {
+			var id, hostname string
+			var heartbeat time.Time
+			if err := rows.Scan(&id, &hostname, &heartbeat); err != nil {
+				return fmt.Errorf("failed to scan executor instance: %w", err)
+			}
+			if heartbeat.After(cutoff) {
+				return fmt.Errorf("%w: instance %s on %s heartbeat %s ago; stop it before restoring",
+					ErrExecutorActive, id, hostname, time.Since(heartbeat).Round(time.Second))
+			}
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("error iterating executor instances: %w", err)
+		}
+	}
+
+	if _, err := db.ExecContext(ctx, `PRAGMA wal_checkpoint(TRUNCATE)`); err != nil {
+		return fmt.Errorf("failed to checkpoint database: %w", err)
+	}
+	return nil
+}
+
+// copyFileSync copies src to dst and syncs dst to disk
+func copyFileSync(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// PruneBackups deletes all but the newest keep backups (named by
+// BackupFileName) in dir, with their manifests. It returns how many
+// backups were deleted.
+func PruneBackups(dir string, keep int) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to list backups: %w", err)
+	}
+
+	var backups []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() && strings.HasPrefix(name, backupFilePrefix) && strings.HasSuffix(name, backupFileExt) {
+			backups = append(backups, name)
+		}
+	}
+	if len(backups) <= keep {
+		return 0, nil
+	}
+
+	// Timestamped names sort oldest first
+	sort.Strings(backups)
+	deleted := 0
+	for _, name := range backups[:len(backups)-keep] {
+		path := filepath.Join(dir, name)
+		if err := os.Remove(path); err != nil {
+			return deleted, fmt.Errorf("failed to delete backup %s: %w", name, err)
+		}
+		_ = os.Remove(path + BackupManifestSuffix)
+		deleted++
+	}
+	return deleted, nil
+}
@@ -0,0 +1,294 @@
+package beads
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestBackupWhileWriting(t *testing.T) {
+	ctx := context.Background()
+	store, parent := newCreateTestStore(t)
+
+	// An executor streaming events while the backup runs
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			event := &events.AgentEvent{Type: events.EventTypeProgress, Timestamp: time.Now(), IssueID: parent.ID,
+				Severity: events.SeverityInfo, Message: "working"}
+			if err := store.StoreAgentEvent(ctx, event); err != nil {
+				t.Errorf("StoreAgentEvent failed: %v", err)
+				return
+			}
+		}
+	}()
+
+	backupPath := filepath.Join(t.TempDir(), "backups", BackupFileName(time.Now()))
+	manifest, err := store.Backup(ctx, backupPath)
+	close(stop)
+	wg.Wait()
+	if err != nil {
+		t.Fatalf("Backup failed: %v", err)
+	}
+
+	if manifest.SchemaVersion != LatestSchemaVersion() || manifest.Issues != 1 || manifest.SourcePath != store.dbPath {
+		t.Errorf("Unexpected manifest: %+v", manifest)
+	}
+	verified, err := VerifyBackup(ctx, backupPath)
+	if err != nil {
+		t.Fatalf("VerifyBackup failed: %v", err)
+	}
+	if verified.AgentEvents != manifest.AgentEvents || verified.SizeBytes != manifest.SizeBytes {
+		t.Errorf("Manifest read back as %+v, wrote %+v", verified, manifest)
+	}
+
+	if _, err := store.Backup(ctx, backupPath); err == nil {
+		t.Error("Expected Backup to refuse to overwrite an existing backup")
+	}
+}
+
+func TestVerifyBackupLeavesFileUntouched(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newCreateTestStore(t)
+
+	dir := t.TempDir()
+	backupPath := filepath.Join(dir, BackupFileName(time.Now()))
+	if _, err := store.Backup(ctx, backupPath); err != nil {
+		t.Fatalf("Backup failed: %v", err)
+	}
+	before, err := os.ReadFile(backupPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := VerifyBackup(ctx, backupPath); err != nil {
+		t.Fatalf("VerifyBackup failed: %v", err)
+	}
+	after, err := os.ReadFile(backupPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(before, after) {
+		t.Error("VerifyBackup modified the backup")
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), "-wal") || strings.HasSuffix(entry.Name(), "-shm") {
+			t.Errorf("Backup directory has %s after verification", entry.Name())
+		}
+	}
+}
+
+func TestVerifyBackupRejectsMismatch(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newCreateTestStore(t)
+
+	backupPath := filepath.Join(t.TempDir(), BackupFileName(time.Now()))
+	manifest, err := store.Backup(ctx, backupPath)
+	if err != nil {
+		t.Fatalf("Backup failed: %v", err)
+	}
+
+	manifest.Issues++
+	if err := writeBackupManifest(backupPath, manifest); err != nil {
+		t.Fatalf("writeBackupManifest failed: %v", err)
+	}
+	if _, err := VerifyBackup(ctx, backupPath); err == nil {
+		t.Error("Expected a manifest mismatch to fail verification")
+	}
+
+	manifest.Issues--
+	manifest.SchemaVersion = LatestSchemaVersion() + 1
+	if err := writeBackupManifest(backupPath, manifest); err != nil {
+		t.Fatalf("writeBackupManifest failed: %v", err)
+	}
+	if _, err := VerifyBackup(ctx, backupPath); !errors.Is(err, ErrSchemaTooNew) {
+		t.Errorf("Expected ErrSchemaTooNew for a backup from a newer vc, got %v", err)
+	}
+
+	if err := os.Remove(backupPath + BackupManifestSuffix); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := VerifyBackup(ctx, backupPath); err == nil {
+		t.Error("Expected a backup without a manifest to fail verification")
+	}
+}
+
+func TestRestoreBackup(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "vc.db")
+	store, err := NewVCStorage(ctx, dbPath)
+	if err != nil {
+		t.Fatalf("NewVCStorage failed: %v", err)
+	}
+
+	kept := &types.Issue{Title: "Before backup", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, kept, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	backupPath := filepath.Join(t.TempDir(), BackupFileName(time.Now()))
+	if _, err := store.Backup(ctx, backupPath); err != nil {
+		t.Fatalf("Backup failed: %v", err)
+	}
+	lost := &types.Issue{Title: "After backup", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, lost, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	// A live executor blocks the restore
+	instance := &types.ExecutorInstance{InstanceID: "exec-1", Hostname: "host", PID: 1, Version: "test",
+		StartedAt: time.Now(), LastHeartbeat: time.Now(), Status: types.ExecutorStatusRunning}
+	if err := store.RegisterInstance(ctx, instance); err != nil {
+		t.Fatalf("RegisterInstance failed: %v", err)
+	}
+	if _, err := RestoreBackup(ctx, backupPath, dbPath); !errors.Is(err, ErrExecutorActive) {
+		t.Fatalf("Expected ErrExecutorActive, got %v", err)
+	}
+
+	if err := store.MarkInstanceStopped(ctx, instance.InstanceID); err != nil {
+		t.Fatalf("MarkInstanceStopped failed: %v", err)
+	}
+	_ = store.Close()
+
+	if _, err := RestoreBackup(ctx, backupPath, dbPath); err != nil {
+		t.Fatalf("RestoreBackup failed: %v", err)
+	}
+	if _, err := os.Stat(dbPath + ".pre-restore"); err != nil {
+		t.Errorf("Expected the replaced database to be kept: %v", err)
+	}
+
+	store, err = NewVCStorage(ctx, dbPath)
+	if err != nil {
+		t.Fatalf("Reopening restored database failed: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+	if issue, err := store.GetIssue(ctx, kept.ID); err != nil || issue == nil {
+		t.Errorf("Expected %s to be restored (err %v)", kept.ID, err)
+	}
+	if issue, err := store.GetIssue(ctx, lost.ID); err != nil || issue != nil {
+		t.Errorf("Expected %s, created after the backup, to be gone (err %v)", lost.ID, err)
+	}
+}
+
+// TestReopenAfterRestore verifies that a store left open across a restore
+// sees the restored database once reopened, and that a missing database
+// file fails the reopen instead of being recreated empty
+func TestReopenAfterRestore(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "vc.db")
+	store, err := NewVCStorage(ctx, dbPath)
+	if err != nil {
+		t.Fatalf("NewVCStorage failed: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	kept := &types.Issue{Title: "Before backup", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, kept, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	backupPath := filepath.Join(t.TempDir(), BackupFileName(time.Now()))
+	if _, err := store.Backup(ctx, backupPath); err != nil {
+		t.Fatalf("Backup failed: %v", err)
+	}
+	lost := &types.Issue{Title: "After backup", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, lost, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	if _, err := RestoreBackup(ctx, backupPath, dbPath); err != nil {
+		t.Fatalf("RestoreBackup failed: %v", err)
+	}
+	if err := store.Reopen(ctx); err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	if issue, err := store.GetIssue(ctx, kept.ID); err != nil || issue == nil {
+		t.Errorf("Expected %s in the restored database (err %v)", kept.ID, err)
+	}
+	if issue, _ := store.GetIssue(ctx, lost.ID); issue != nil {
+		t.Errorf("Expected %s to be gone after reopening the restored database", lost.ID)
+	}
+
+	// The file goes missing: reopening fails and creates nothing
+	movedPath := dbPath + ".moved"
+	if err := os.Rename(dbPath, movedPath); err != nil {
+		t.Fatalf("Rename failed: %v", err)
+	}
+	if err := store.Reopen(ctx); err == nil {
+		t.Error("Expected Reopen to fail while the database file is missing")
+	}
+	if _, err := os.Stat(dbPath); !os.IsNotExist(err) {
+		t.Errorf("Expected Reopen not to create a database, stat: %v", err)
+	}
+
+	// ...and comes back
+	if err := os.Rename(movedPath, dbPath); err != nil {
+		t.Fatalf("Rename failed: %v", err)
+	}
+	if err := store.Reopen(ctx); err != nil {
+		t.Fatalf("Reopen after the file came back failed: %v", err)
+	}
+	if issue, err := store.GetIssue(ctx, kept.ID); err != nil || issue == nil {
+		t.Errorf("Expected %s after the file came back (err %v)", kept.ID, err)
+	}
+}
+
+func TestPruneBackups(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var names []string
+	for i := 0; i < 5; i++ {
+		name := BackupFileName(start.Add(time.Duration(i) * time.Hour))
+		names = append(names, name)
+		for _, path := range []string{name, name + BackupManifestSuffix} {
+			if err := os.WriteFile(filepath.Join(dir, path), []byte("x"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	// Files that aren't backups are left alone
+	if err := os.WriteFile(filepath.Join(dir, "notes.db"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	deleted, err := PruneBackups(dir, 2)
+	if err != nil {
+		t.Fatalf("PruneBackups failed: %v", err)
+	}
+	if deleted != 3 {
+		t.Errorf("Expected 3 backups deleted, got %d", deleted)
+	}
+	for i, name := range names {
+		_, err := os.Stat(filepath.Join(dir, name))
+		_, manifestErr := os.Stat(filepath.Join(dir, name+BackupManifestSuffix))
+		if keep := i >= 3; keep != (err == nil) || keep != (manifestErr == nil) {
+			t.Errorf("Backup %s: expected kept=%v", name, keep)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "notes.db")); err != nil {
+		t.Error("Expected unrelated files to survive pruning")
+	}
+
+	if deleted, err := PruneBackups(filepath.Join(dir, "missing"), 2); err != nil || deleted != 0 {
+		t.Errorf("Expected a missing directory to prune nothing, got %d (err %v)", deleted, err)
+	}
+}
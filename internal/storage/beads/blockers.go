@@ -0,0 +1,93 @@
+package beads
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// ======================================================================
+// BLOCKING CHAINS (root blockers of blocked issues)
+// ======================================================================
+
+// maxBlockerChainDepth caps how far rootBlockers follows blocking
+// dependencies. Real chains are a handful of levels deep; the cap bounds the
+// recursion on pathological graphs.
+const maxBlockerChainDepth = 32
+
+// rootBlockers follows the open 'blocks' dependencies of every blocked issue
+// transitively and returns, per blocked issue, the sorted root blockers: the
+// open issues it depends on that have no open blockers of their own. Issues
+// whose chain reached maxBlockerChainDepth without ending are reported in
+// truncated.
+//
+// The depth cap also protects against dependency cycles (Beads refuses new
+// ones, but older databases may have them): a cycle never ends, so every
+// issue on or behind it reaches the cap and is reported as truncated.
+func (s *VCStorage) rootBlockers(ctx context.Context) (roots map[string][]string, truncated map[string]bool, err error) {
+	rows, err := s.db.QueryContext(ctx, `
+		WITH RECURSIVE chain(blocked_id, blocker_id, depth) AS (
+			SELECT d.issue_id, d.depends_on_id, 1
+			FROM dependencies d
+			JOIN issues i ON i.id = d.issue_id
+			JOIN issues b ON b.id = d.depends_on_id
+			WHERE d.type = 'blocks'
+			  AND i.status IN ('open', 'in_progress', 'blocked')
+			  AND b.status IN ('open', 'in_progress', 'blocked')
+			UNION
+			SELECT c.blocked_id, d.depends_on_id, c.depth + 1
+			FROM chain c
+			JOIN dependencies d ON d.issue_id = c.blocker_id
+			JOIN issues b ON b.id = d.depends_on_id
+			WHERE d.type = 'blocks'
+			  AND b.status IN ('open', 'in_progress', 'blocked')
+			  AND c.depth < ?
+		)
+		SELECT DISTINCT c.blocked_id, c.blocker_id, c.depth,
+		       NOT EXISTS (
+		         SELECT 1 FROM dependencies d
+		         JOIN issues b ON b.id = d.depends_on_id
+		         WHERE d.issue_id = c.blocker_id
+		           AND d.type = 'blocks'
+		           AND b.status IN ('open', 'in_progress', 'blocked')
+		       ) AS is_root
+		FROM chain c
+	`, maxBlockerChainDepth)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to follow blocking chains: %w", err)
+	}
+	defer rows.Close()
+
+	rootSets := make(map[string]map[string]bool)
+	truncated = make(map[string]bool)
+	for rows.Next() {
+		var blockedID, blockerID string
+		var depth int
+		var isRoot bool
+		if err := rows.Scan(&blockedID, &blockerID, &depth, &isRoot); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan blocking chain: %w", err)
+		}
+		if isRoot {
+			if rootSets[blockedID] == nil {
+				rootSets[blockedID] = make(map[string]bool)
+			}
+			rootSets[blockedID][blockerID] = true
+		} else if depth == maxBlockerChainDepth {
+			truncated[blockedID] = true
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("error iterating blocking chains: %w", err)
+	}
+
+	roots = make(map[string][]string, len(rootSets))
+	for blockedID, set := range rootSets {
+		ids := make([]string, 0, len(set))
+		for id := range set {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		roots[blockedID] = ids
+	}
+	return roots, truncated, nil
+}
@@ -0,0 +1,133 @@
+package beads
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestGetBlockedIssuesRootBlockers(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newCreateTestStore(t)
+
+	newIssue := func(title string) string {
+		t.Helper()
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		return issue.ID
+	}
+	blocks := func(issueID, dependsOnID string) {
+		t.Helper()
+		dep := &types.Dependency{IssueID: issueID, DependsOnID: dependsOnID, Type: types.DepBlocks}
+		if err := store.AddDependency(ctx, dep, "test"); err != nil {
+			t.Fatalf("AddDependency failed: %v", err)
+		}
+	}
+
+	// d → c → b → a, plus e → {c, f}
+	a, b, c, d := newIssue("a"), newIssue("b"), newIssue("c"), newIssue("d")
+	e, f := newIssue("e"), newIssue("f")
+	blocks(b, a)
+	blocks(c, b)
+	blocks(d, c)
+	blocks(e, c)
+	blocks(e, f)
+
+	// g → h → (closed) i: the chain ends at h
+	g, h, i := newIssue("g"), newIssue("h"), newIssue("i")
+	blocks(g, h)
+	blocks(h, i)
+	if err := store.CloseIssue(ctx, i, "done", "test"); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+
+	// z → x ⇄ y: a cycle left behind by older versions (Beads refuses new ones)
+	x, y, z := newIssue("x"), newIssue("y"), newIssue("z")
+	blocks(x, y)
+	blocks(z, x)
+	if _, err := store.db.ExecContext(ctx, `INSERT INTO dependencies (issue_id, depends_on_id, type, created_by) VALUES (?, ?, 'blocks', 'test')`, y, x); err != nil {
+		t.Fatalf("Failed to insert cycle: %v", err)
+	}
+
+	blocked, err := store.GetBlockedIssues(ctx)
+	if err != nil {
+		t.Fatalf("GetBlockedIssues failed: %v", err)
+	}
+	roots := make(map[string]string)
+	for _, issue := range blocked {
+		roots[issue.ID] = strings.Join(issue.RootBlockers, ",")
+		// Only the chains into the cycle never end
+		inCycle := issue.ID == x || issue.ID == y || issue.ID == z
+		if issue.RootsTruncated != inCycle {
+			t.Errorf("%s: RootsTruncated = %v, want %v", issue.ID, issue.RootsTruncated, inCycle)
+		}
+	}
+
+	want := map[string]string{
+		b: a, c: a, d: a,
+		e: strings.Join(sortedIDs(a, f), ","),
+		g: h, h: "",
+		x: "", y: "", z: "",
+	}
+	for id, wantRoots := range want {
+		got, ok := roots[id]
+		if id == h {
+			// h's only blocker is closed, so it isn't blocked at all
+			if ok {
+				t.Errorf("%s should not be blocked", id)
+			}
+			continue
+		}
+		if !ok {
+			t.Errorf("%s should be blocked", id)
+			continue
+		}
+		if got != wantRoots {
+			t.Errorf("%s: root blockers = %q, want %q", id, got, wantRoots)
+		}
+	}
+}
+
+func TestRootBlockersDepthCap(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newCreateTestStore(t)
+
+	// A chain one longer than the cap: the head can't be followed to the end
+	var ids []string
+	for n := 0; n <= maxBlockerChainDepth+1; n++ {
+		issue := &types.Issue{Title: "link", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		if len(ids) > 0 {
+			dep := &types.Dependency{IssueID: issue.ID, DependsOnID: ids[len(ids)-1], Type: types.DepBlocks}
+			if err := store.AddDependency(ctx, dep, "test"); err != nil {
+				t.Fatalf("AddDependency failed: %v", err)
+			}
+		}
+		ids = append(ids, issue.ID)
+	}
+
+	roots, truncated, err := store.rootBlockers(ctx)
+	if err != nil {
+		t.Fatalf("rootBlockers failed: %v", err)
+	}
+	head, second := ids[len(ids)-1], ids[len(ids)-2]
+	if !truncated[head] || len(roots[head]) != 0 {
+		t.Errorf("Expected the head's chain to be truncated, got roots %v truncated %v", roots[head], truncated[head])
+	}
+	if truncated[second] || strings.Join(roots[second], ",") != ids[0] {
+		t.Errorf("Expected %s to reach root %s, got %v (truncated %v)", second, ids[0], roots[second], truncated[second])
+	}
+}
+
+func sortedIDs(ids ...string) []string {
+	sorted := append([]string(nil), ids...)
+	sort.Strings(sorted)
+	return sorted
+}
@@ -0,0 +1,52 @@
+package beads
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// TestGetCheckpointLegacyData verifies checkpoint blobs written before
+// checkpoints were versioned are reported as a version mismatch rather than
+// misread
+func TestGetCheckpointLegacyData(t *testing.T) {
+	ctx := context.Background()
+	store, issue := newCreateTestStore(t)
+
+	instance := &types.ExecutorInstance{
+		InstanceID: "test-instance-1",
+		Version:    "test",
+		StartedAt:  time.Now(),
+		Hostname:   "test-host",
+		Status:     "running",
+	}
+	if err := store.RegisterInstance(ctx, instance); err != nil {
+		t.Fatalf("Failed to register instance: %v", err)
+	}
+	if err := store.ClaimIssue(ctx, issue.ID, instance.InstanceID); err != nil {
+		t.Fatalf("Failed to claim issue: %v", err)
+	}
+
+	if _, err := store.db.ExecContext(ctx, `UPDATE vc_issue_execution_state SET checkpoint_data = ? WHERE issue_id = ?`,
+		`{"step":2,"completed":["setup","compile"]}`, issue.ID); err != nil {
+		t.Fatalf("Failed to write legacy checkpoint: %v", err)
+	}
+	checkpoint, err := store.GetCheckpoint(ctx, issue.ID)
+	if !errors.Is(err, types.ErrCheckpointVersionMismatch) {
+		t.Fatalf("Expected ErrCheckpointVersionMismatch, got (%+v, %v)", checkpoint, err)
+	}
+
+	// Discarding it leaves no checkpoint
+	if err := store.SaveCheckpoint(ctx, issue.ID, nil); err != nil {
+		t.Fatalf("Failed to clear checkpoint: %v", err)
+	}
+	if checkpoint, err := store.GetCheckpoint(ctx, issue.ID); err != nil || checkpoint != nil {
+		t.Errorf("Expected no checkpoint after clearing, got (%+v, %v)", checkpoint, err)
+	}
+	if n := countRows(t, store, `SELECT COUNT(*) FROM vc_issue_execution_state WHERE issue_id = ? AND checkpoint_data IS NULL`, issue.ID); n != 1 {
+		t.Errorf("Expected checkpoint_data to be NULL after clearing")
+	}
+}
@@ -0,0 +1,83 @@
+package beads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// TestClaimIssueConcurrent has a dozen executors race for the same ready
+// issue: exactly one wins and every other claim reports ErrAlreadyClaimed
+// rather than a storage error
+func TestClaimIssueConcurrent(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewVCStorage(ctx, filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create test storage: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	const executors = 12
+	for i := 0; i < executors; i++ {
+		instance := &types.ExecutorInstance{
+			InstanceID: fmt.Sprintf("executor-%d", i),
+			Version:    "test",
+			StartedAt:  time.Now(),
+			Hostname:   "test-host",
+			Status:     "running",
+		}
+		if err := store.RegisterInstance(ctx, instance); err != nil {
+			t.Fatalf("Failed to register instance: %v", err)
+		}
+	}
+
+	issue := &types.Issue{Title: "Contended", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("Failed to create issue: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	errs := make([]error, executors)
+	for i := 0; i < executors; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			errs[i] = store.ClaimIssue(ctx, issue.ID, fmt.Sprintf("executor-%d", i))
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	winner := ""
+	for i, err := range errs {
+		switch {
+		case err == nil:
+			if winner != "" {
+				t.Errorf("Both %s and executor-%d claimed the issue", winner, i)
+			}
+			winner = fmt.Sprintf("executor-%d", i)
+		case errors.Is(err, types.ErrAlreadyClaimed):
+		default:
+			t.Errorf("executor-%d: expected ErrAlreadyClaimed, got %v", i, err)
+		}
+	}
+	if winner == "" {
+		t.Fatal("No executor claimed the issue")
+	}
+
+	state, err := store.GetExecutionState(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("Failed to get execution state: %v", err)
+	}
+	if state == nil || state.ExecutorInstanceID != winner {
+		t.Errorf("Expected the claim to belong to %s, got %+v", winner, state)
+	}
+}
@@ -0,0 +1,380 @@
+package beads
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ======================================================================
+// BEADS LIBRARY COMPATIBILITY
+// ======================================================================
+
+// VC reads and writes the Beads core tables directly (see create.go and the
+// queries throughout this package), so a database laid out by a different
+// Beads library version can break it in ways the library itself would not
+// notice. Opening a database checks that it still has what VC depends on and
+// records the compatibility level and library version that last opened it.
+
+// BeadsCompatLevel is the Beads database layout this vc works with. Bump it
+// when VC starts depending on a layout older vc binaries can't handle, so
+// they refuse the database instead of corrupting it.
+const BeadsCompatLevel = 1
+
+const (
+	// BeadsCompatConfigKey records the BeadsCompatLevel of the newest vc
+	// that opened the database
+	BeadsCompatConfigKey = "vc_beads_compat"
+	// BeadsVersionConfigKey records the Beads library version of the vc that
+	// last opened the database (informational)
+	BeadsVersionConfigKey = "vc_beads_version"
+)
+
+// BeadsCompatEnvVar selects what happens when a database is incompatible:
+// unset or "refuse" fails to open it, "warn" opens it read-only
+const BeadsCompatEnvVar = "VC_BEADS_COMPAT"
+
+// beadsModulePath is the Go module the Beads library is built from
+const beadsModulePath = "github.com/steveyegge/beads"
+
+// beadsCoreColumns are the Beads core tables and columns VC's own SQL uses.
+// Tables Beads creates when it opens a database (dirty_issues,
+// issue_counters) and columns its migrations add (issues.external_ref) are
+// left out: a database from an older Beads lacks them until then.
+var beadsCoreColumns = map[string][]string{
+	"issues": {
+		"id", "title", "description", "design", "acceptance_criteria", "notes",
+		"status", "priority", "issue_type", "assignee", "estimated_minutes",
+		"created_at", "updated_at", "closed_at",
+	},
+	"dependencies": {"issue_id", "depends_on_id", "type"},
+	"labels":       {"issue_id", "label"},
+	"events":       {"issue_id", "event_type", "actor", "old_value", "new_value", "comment", "created_at"},
+	"config":       {"key", "value"},
+}
+
+// ErrBeadsIncompatible is returned when opening a database whose Beads
+// layout this vc can't work with. The error is a *BeadsCompatError.
+var ErrBeadsIncompatible = errors.New("database is incompatible with this vc's Beads library")
+
+// BeadsCompatError lists why a database is incompatible
+type BeadsCompatError struct {
+	Path     string
+	Problems []string
+}
+
+// Error implements the error interface.
+func (e *BeadsCompatError) Error() string {
+	return fmt.Sprintf("%v: %s: %s (see \"Beads Compatibility\" in docs/CONFIGURATION.md; %s=warn opens it read-only)",
+		ErrBeadsIncompatible, e.Path, strings.Join(e.Problems, "; "), BeadsCompatEnvVar)
+}
+
+// Unwrap lets errors.Is(err, ErrBeadsIncompatible) match.
+func (e *BeadsCompatError) Unwrap() error {
+	return ErrBeadsIncompatible
+}
+
+// BeadsLibraryVersion returns the version of the Beads library this binary
+// was built with, or "unknown"
+func BeadsLibraryVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	for _, dep := range info.Deps {
+		if dep.Path != beadsModulePath {
+			continue
+		}
+		if dep.Replace != nil && dep.Replace.Version != "" {
+			return dep.Replace.Version
+		}
+		return dep.Version
+	}
+	return "unknown"
+}
+
+// BeadsCompat is what a database records about the Beads library, and what
+// is wrong with it
+type BeadsCompat struct {
+	Level    int    // BeadsCompatConfigKey; 0 if never recorded
+	Version  string // BeadsVersionConfigKey; "" if never recorded
+	Problems []string
+}
+
+// Compatible reports whether the database can be opened read-write
+func (c *BeadsCompat) Compatible() bool {
+	return len(c.Problems) == 0
+}
+
+// beadsCatalog is how the compatibility check reads a Beads database
+type beadsCatalog interface {
+	// Columns returns the columns of table, or nil if there is no such table
+	Columns(ctx context.Context, table string) ([]string, error)
+	// Config returns a config value, or "" if it is unset
+	Config(ctx context.Context, key string) (string, error)
+}
+
+// checkBeadsCompat reads what the database records and lists what VC can't
+// work with: a newer compatibility level and missing core columns
+func checkBeadsCompat(ctx context.Context, catalog beadsCatalog) (*BeadsCompat, error) {
+	compat := &BeadsCompat{}
+	level, err := catalog.Config(ctx, BeadsCompatConfigKey)
+	if err != nil {
+		return nil, err
+	}
+	if level != "" {
+		if compat.Level, err = strconv.Atoi(level); err != nil {
+			compat.Problems = append(compat.Problems, fmt.Sprintf("unreadable %s %q", BeadsCompatConfigKey, level))
+		} else if compat.Level > BeadsCompatLevel {
+			compat.Problems = append(compat.Problems, fmt.Sprintf(
+				"it was opened by a vc with Beads compatibility level %d, this vc supports up to %d",
+				compat.Level, BeadsCompatLevel))
+		}
+	}
+	if compat.Version, err = catalog.Config(ctx, BeadsVersionConfigKey); err != nil {
+		return nil, err
+	}
+
+	tables := make([]string, 0, len(beadsCoreColumns))
+	for table := range beadsCoreColumns {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	for _, table := range tables {
+		columns, err := catalog.Columns(ctx, table)
+		if err != nil {
+			return nil, err
+		}
+		if columns == nil {
+			continue // Beads creates missing tables when it opens the database
+		}
+		have := make(map[string]bool, len(columns))
+		for _, column := range columns {
+			have[column] = true
+		}
+		var missing []string
+		for _, column := range beadsCoreColumns[table] {
+			if !have[column] {
+				missing = append(missing, column)
+			}
+		}
+		if len(missing) > 0 {
+			compat.Problems = append(compat.Problems, fmt.Sprintf("table %s lacks column(s) %s", table, strings.Join(missing, ", ")))
+		}
+	}
+
+	return compat, nil
+}
+
+// queryer is satisfied by *sql.DB and *sql.Conn
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// sqlCatalog reads a Beads database over SQL
+type sqlCatalog struct {
+	db queryer
+}
+
+func (c sqlCatalog) Columns(ctx context.Context, table string) ([]string, error) {
+	rows, err := c.db.QueryContext(ctx, `SELECT name FROM pragma_table_info(?)`, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns of %s: %w", table, err)
+	}
+	defer rows.Close()
+	var columns []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to read columns of %s: %w", table, err)
+		}
+		columns = append(columns, name)
+	}
+	return columns, rows.Err()
+}
+
+func (c sqlCatalog) Config(ctx context.Context, key string) (string, error) {
+	columns, err := c.Columns(ctx, "config")
+	if err != nil || !containsAll(columns, "key", "value") {
+		return "", err // The column check reports a config table without them
+	}
+	var value string
+	err = c.db.QueryRowContext(ctx, `SELECT value FROM config WHERE key = ?`, key).Scan(&value)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s config: %w", key, err)
+	}
+	return value, nil
+}
+
+// containsAll reports whether columns has every one of want
+func containsAll(columns []string, want ...string) bool {
+	for _, w := range want {
+		found := false
+		for _, column := range columns {
+			found = found || column == w
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckBeadsCompat checks an existing database file without opening it
+// through Beads, which would migrate it. A missing or empty file is
+// compatible.
+func CheckBeadsCompat(ctx context.Context, dbPath string) (*BeadsCompat, error) {
+	if isInMemoryPath(dbPath) {
+		return &BeadsCompat{}, nil
+	}
+	if info, err := os.Stat(dbPath); err != nil || info.Size() == 0 {
+		return &BeadsCompat{}, nil
+	}
+
+	registerConnectionPragmas()
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", dbPath, err)
+	}
+	defer db.Close()
+
+	catalog := sqlCatalog{db: db}
+	issues, err := catalog.Columns(ctx, "issues")
+	if err != nil {
+		return nil, err
+	}
+	if issues == nil {
+		return &BeadsCompat{}, nil // Not a Beads database yet; Beads creates it
+	}
+	return checkBeadsCompat(ctx, catalog)
+}
+
+// beadsConfig is the Beads config access VC uses
+type beadsConfig interface {
+	GetConfig(ctx context.Context, key string) (string, error)
+	SetConfig(ctx context.Context, key, value string) error
+}
+
+// recordBeadsCompat records this vc's compatibility level and Beads library
+// version, writing only what changed. The level never goes down, so an
+// older vc opening the database afterwards still sees the newest level.
+func recordBeadsCompat(ctx context.Context, config beadsConfig) error {
+	level, err := config.GetConfig(ctx, BeadsCompatConfigKey)
+	if err != nil {
+		return fmt.Errorf("failed to read %s config: %w", BeadsCompatConfigKey, err)
+	}
+	if recorded, err := strconv.Atoi(level); err != nil || recorded < BeadsCompatLevel {
+		if err := config.SetConfig(ctx, BeadsCompatConfigKey, strconv.Itoa(BeadsCompatLevel)); err != nil {
+			return fmt.Errorf("failed to set %s config: %w", BeadsCompatConfigKey, err)
+		}
+	}
+
+	version, err := config.GetConfig(ctx, BeadsVersionConfigKey)
+	if err != nil {
+		return fmt.Errorf("failed to read %s config: %w", BeadsVersionConfigKey, err)
+	}
+	if current := BeadsLibraryVersion(); version != current {
+		if err := config.SetConfig(ctx, BeadsVersionConfigKey, current); err != nil {
+			return fmt.Errorf("failed to set %s config: %w", BeadsVersionConfigKey, err)
+		}
+	}
+	return nil
+}
+
+// beadsBackend is everything VC uses from the Beads library besides its
+// issue methods: the connection pool and scoped connections for VC's own
+// SQL, and the config table. NewVCStorage reaches the database only through
+// it, so this is the surface a Beads upgrade must keep.
+type beadsBackend interface {
+	beadsConfig
+	UnderlyingDB() *sql.DB
+	UnderlyingConn(ctx context.Context) (*sql.Conn, error)
+}
+
+// readOnlyPaths are the database paths opened read-only (incompatible ones,
+// and those of SetReadOnly); the connection hook makes their connections
+// query-only
+var readOnlyPaths sync.Map
+
+// snapshotDatabase copies an incompatible database to a temporary directory
+// for opening read-only. Beads creates its schema and runs its migrations
+// whenever it opens a database, which a query-only connection refuses and
+// which must not touch the original, so Beads opens the copy instead and
+// the copy is then made query-only (see makeReadOnly). Reads see the
+// database as it was when it was opened.
+func snapshotDatabase(ctx context.Context, dbPath string) (dir, path string, err error) {
+	dir, err = os.MkdirTemp("", "vc-readonly-*")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create read-only snapshot directory: %w", err)
+	}
+	path = filepath.Join(dir, filepath.Base(dbPath))
+
+	db, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		removeSnapshot(dir)
+		return "", "", fmt.Errorf("failed to open %s: %w", dbPath, err)
+	}
+	defer db.Close()
+	if _, err := db.ExecContext(ctx, `VACUUM INTO ?`, path); err != nil {
+		removeSnapshot(dir)
+		return "", "", fmt.Errorf("failed to snapshot %s for reading: %w", dbPath, err)
+	}
+	return dir, path, nil
+}
+
+// makeReadOnly makes every connection to path query-only. The connections
+// Beads opened are dropped from the pool, so the next ones go through the
+// connection hook.
+func makeReadOnly(db *sql.DB, path string) {
+	readOnlyPaths.Store(path, true)
+	db.SetMaxIdleConns(0)
+	db.SetMaxIdleConns(2) // database/sql's default
+}
+
+// removeSnapshot removes a snapshotDatabase directory, if any
+func removeSnapshot(dir string) {
+	if dir != "" {
+		_ = os.RemoveAll(dir)
+	}
+}
+
+// isReadOnlyDSN reports whether a connection DSN is for a read-only path
+func isReadOnlyDSN(dsn string) bool {
+	path, _, _ := strings.Cut(dsn, "?")
+	_, ok := readOnlyPaths.Load(path)
+	return ok
+}
+
+// beadsCompatMode returns how to handle an incompatible database: "refuse"
+// or "warn"
+func beadsCompatMode() string {
+	switch mode := strings.ToLower(os.Getenv(BeadsCompatEnvVar)); mode {
+	case "", "refuse":
+		return "refuse"
+	case "warn":
+		return mode
+	default:
+		fmt.Fprintf(os.Stderr, "warning: ignoring invalid %s %q (using refuse)\n", BeadsCompatEnvVar, mode)
+		return "refuse"
+	}
+}
+
+// warnIncompatible prints the loud warning for opening an incompatible
+// database read-only
+func warnIncompatible(compatErr *BeadsCompatError) {
+	banner := strings.Repeat("!", 72)
+	fmt.Fprintf(os.Stderr, "%s\nWARNING: %s\nOpening a READ-ONLY snapshot because %s=warn; every write will fail.\n%s\n",
+		banner, compatErr.Error(), BeadsCompatEnvVar, banner)
+}
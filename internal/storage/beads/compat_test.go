@@ -0,0 +1,206 @@
+package beads
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// fakeCatalog is a beadsCatalog over in-memory tables
+type fakeCatalog struct {
+	columns map[string][]string
+	config  map[string]string
+}
+
+func newFakeCatalog() *fakeCatalog {
+	columns := make(map[string][]string)
+	for table, cols := range beadsCoreColumns {
+		columns[table] = append([]string{}, cols...)
+	}
+	return &fakeCatalog{columns: columns, config: map[string]string{"issue_prefix": "vc"}}
+}
+
+func (c *fakeCatalog) Columns(ctx context.Context, table string) ([]string, error) {
+	return c.columns[table], nil
+}
+
+func (c *fakeCatalog) Config(ctx context.Context, key string) (string, error) {
+	return c.config[key], nil
+}
+
+func TestCheckBeadsCompat(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(c *fakeCatalog)
+		problem string // "" for compatible
+	}{
+		{"current layout", func(c *fakeCatalog) {}, ""},
+		{"recorded level", func(c *fakeCatalog) { c.config[BeadsCompatConfigKey] = strconv.Itoa(BeadsCompatLevel) }, ""},
+		{"missing table is created by Beads", func(c *fakeCatalog) { delete(c.columns, "labels") }, ""},
+		{"newer level", func(c *fakeCatalog) { c.config[BeadsCompatConfigKey] = strconv.Itoa(BeadsCompatLevel + 1) }, "supports up to"},
+		{"unreadable level", func(c *fakeCatalog) { c.config[BeadsCompatConfigKey] = "two" }, "unreadable"},
+		{"renamed column", func(c *fakeCatalog) { c.columns["issues"] = []string{"id", "title", "kind"} }, "table issues lacks column(s) description"},
+		{"extra column", func(c *fakeCatalog) { c.columns["labels"] = append(c.columns["labels"], "color") }, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			catalog := newFakeCatalog()
+			tt.change(catalog)
+			compat, err := checkBeadsCompat(context.Background(), catalog)
+			if err != nil {
+				t.Fatalf("checkBeadsCompat failed: %v", err)
+			}
+			if tt.problem == "" {
+				if !compat.Compatible() {
+					t.Errorf("Expected compatible, got %v", compat.Problems)
+				}
+				return
+			}
+			if compat.Compatible() || !strings.Contains(strings.Join(compat.Problems, "; "), tt.problem) {
+				t.Errorf("Expected a problem containing %q, got %v", tt.problem, compat.Problems)
+			}
+		})
+	}
+}
+
+// fakeConfig is a beadsConfig counting writes
+type fakeConfig struct {
+	values map[string]string
+	writes int
+}
+
+func (c *fakeConfig) GetConfig(ctx context.Context, key string) (string, error) {
+	return c.values[key], nil
+}
+
+func (c *fakeConfig) SetConfig(ctx context.Context, key, value string) error {
+	c.values[key] = value
+	c.writes++
+	return nil
+}
+
+func TestRecordBeadsCompat(t *testing.T) {
+	ctx := context.Background()
+	config := &fakeConfig{values: map[string]string{}}
+	if err := recordBeadsCompat(ctx, config); err != nil {
+		t.Fatalf("recordBeadsCompat failed: %v", err)
+	}
+	if config.values[BeadsCompatConfigKey] != strconv.Itoa(BeadsCompatLevel) || config.values[BeadsVersionConfigKey] != BeadsLibraryVersion() {
+		t.Errorf("Unexpected config %v", config.values)
+	}
+
+	// Unchanged values aren't written again, and a newer level is kept
+	config.writes = 0
+	config.values[BeadsCompatConfigKey] = strconv.Itoa(BeadsCompatLevel + 1)
+	if err := recordBeadsCompat(ctx, config); err != nil {
+		t.Fatalf("recordBeadsCompat failed: %v", err)
+	}
+	if config.writes != 0 {
+		t.Errorf("Expected no writes, got %d", config.writes)
+	}
+	if config.values[BeadsCompatConfigKey] != strconv.Itoa(BeadsCompatLevel+1) {
+		t.Errorf("Expected the newer level to be kept, got %s", config.values[BeadsCompatConfigKey])
+	}
+}
+
+// TestNewVCStorageBeadsCompat records compatibility on open, then refuses a
+// database last opened by a newer vc, or opens it read-only with VC_BEADS_COMPAT=warn
+func TestNewVCStorageBeadsCompat(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "compat.db")
+	store, err := NewVCStorage(ctx, path)
+	if err != nil {
+		t.Fatalf("NewVCStorage failed: %v", err)
+	}
+	if level, _ := store.GetConfig(ctx, BeadsCompatConfigKey); level != strconv.Itoa(BeadsCompatLevel) {
+		t.Errorf("Expected level %d recorded, got %q", BeadsCompatLevel, level)
+	}
+	if store.ReadOnly() {
+		t.Error("Expected a compatible database to be writable")
+	}
+	issue := &types.Issue{Title: "Existing", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	future := BeadsCompatLevel + 1
+	if err := store.SetConfig(ctx, BeadsCompatConfigKey, strconv.Itoa(future)); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+	_ = store.Close()
+
+	compat, err := CheckBeadsCompat(ctx, path)
+	if err != nil {
+		t.Fatalf("CheckBeadsCompat failed: %v", err)
+	}
+	if compat.Compatible() || compat.Level != future || compat.Version != BeadsLibraryVersion() {
+		t.Errorf("Expected an incompatible level-%d database, got %+v", future, compat)
+	}
+
+	_, err = NewVCStorage(ctx, path)
+	var compatErr *BeadsCompatError
+	if !errors.Is(err, ErrBeadsIncompatible) || !errors.As(err, &compatErr) || compatErr.Path != path {
+		t.Fatalf("Expected a BeadsCompatError, got %v", err)
+	}
+
+	t.Setenv(BeadsCompatEnvVar, "warn")
+	store, err = NewVCStorage(ctx, path)
+	if err != nil {
+		t.Fatalf("NewVCStorage with %s=warn failed: %v", BeadsCompatEnvVar, err)
+	}
+	defer store.Close()
+	if !store.ReadOnly() {
+		t.Error("Expected the database to be opened read-only")
+	}
+	if got, err := store.GetIssue(ctx, issue.ID); err != nil || got == nil {
+		t.Errorf("Expected reads to work, got %v, %v", got, err)
+	}
+	err = store.CreateIssue(ctx, &types.Issue{Title: "New", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}, "test")
+	if err == nil || !strings.Contains(err.Error(), "readonly") {
+		t.Errorf("Expected writes to fail as read-only, got %v", err)
+	}
+}
+
+// TestSetReadOnly makes a store query-only while another writer keeps
+// changing the database
+func TestSetReadOnly(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "serve.db")
+	store, err := NewVCStorage(ctx, path)
+	if err != nil {
+		t.Fatalf("NewVCStorage failed: %v", err)
+	}
+	defer store.Close()
+	issue := &types.Issue{Title: "Before", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	store.SetReadOnly()
+	if !store.ReadOnly() {
+		t.Error("Expected the store to be read-only")
+	}
+	err = store.CreateIssue(ctx, &types.Issue{Title: "After", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}, "test")
+	if err == nil || !strings.Contains(err.Error(), "readonly") {
+		t.Errorf("Expected writes to fail as read-only, got %v", err)
+	}
+
+	// A writer elsewhere (a differently spelled DSN stands in for another
+	// process) still writes, and the store sees it
+	writer, err := sql.Open("sqlite", "file:"+path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer writer.Close()
+	if _, err := writer.ExecContext(ctx, `UPDATE issues SET title = 'Renamed' WHERE id = ?`, issue.ID); err != nil {
+		t.Fatalf("Writer failed: %v", err)
+	}
+	if got, err := store.GetIssue(ctx, issue.ID); err != nil || got == nil || got.Title != "Renamed" {
+		t.Errorf("Expected the store to read the new title, got %+v, %v", got, err)
+	}
+}
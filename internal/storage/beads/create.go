@@ -0,0 +1,394 @@
+package beads
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	beadsLib "github.com/steveyegge/beads"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// ATOMIC ISSUE CREATION (issues + labels + dependencies in one transaction)
+// ======================================================================
+
+// maxDependencyDepth bounds the cycle check, matching Beads' AddDependency
+const maxDependencyDepth = 100
+
+// CreateIssueWithMetadata creates an issue together with its labels and
+// dependencies in a single SQL transaction, so a failure anywhere leaves no
+// trace of the issue. An empty IssueID or DependsOnID in a dependency refers
+// to the new issue, whose ID is only known inside the transaction.
+func (s *VCStorage) CreateIssueWithMetadata(ctx context.Context, issue *types.Issue, labels []string, deps []*types.Dependency, actor string) error {
+	err := s.CreateIssues(ctx, []*types.Issue{issue}, actor, types.CreateIssuesOptions{
+		Labels:       map[int][]string{0: labels},
+		Dependencies: map[int][]*types.Dependency{0: deps},
+		AllOrNothing: true,
+	})
+	var batchErr *types.BatchCreateError
+	if errors.As(err, &batchErr) {
+		return batchErr.Errors[0]
+	}
+	return err
+}
+
+// CreateIssues creates a batch of issues, with the labels and dependencies
+// in opts, in a single SQL transaction. The batch gets consecutive IDs and
+// pays for one write lock and one commit instead of one per issue.
+//
+// Each issue is created in its own savepoint: an invalid issue (or a
+// dependency that fails) rolls back only that issue's writes, and the rest
+// of the batch is committed. With opts.AllOrNothing, any failure rolls back
+// the whole batch. Either way the failures are returned as a
+// *types.BatchCreateError. Created issues get their ID and timestamps set.
+//
+// Beads has no API for joining its writes to a caller's transaction, so the
+// Beads rows (issue, labels, dependencies, audit events, dirty markers) are
+// written directly through the shared database, the same way Beads' own
+// CreateIssue, AddLabel and AddDependency write them.
+func (s *VCStorage) CreateIssues(ctx context.Context, issues []*types.Issue, actor string, opts types.CreateIssuesOptions) error {
+	if len(issues) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	failed := make(map[int]error)
+	beadsIssues := make([]*beadsLib.Issue, len(issues))
+	for i, issue := range issues {
+		if issue == nil {
+			failed[i] = fmt.Errorf("issue is nil")
+			continue
+		}
+		if err := validateNewIssue(issue, opts.Dependencies[i]); err != nil {
+			failed[i] = err
+			continue
+		}
+		beadsIssues[i] = vcIssueToBeads(issue)
+		beadsIssues[i].CreatedAt = now
+		beadsIssues[i].UpdatedAt = now
+	}
+	if opts.AllOrNothing && len(failed) > 0 {
+		return &types.BatchCreateError{Errors: failed, Total: len(issues), RolledBack: true}
+	}
+	if len(failed) == len(issues) {
+		return &types.BatchCreateError{Errors: failed, Total: len(issues)}
+	}
+
+	// BEGIN IMMEDIATE needs raw SQL on one connection (database/sql's BeginTx
+	// is always DEFERRED). Taking the write lock up front serializes ID
+	// generation with other writers, as Beads does.
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	if _, err := conn.ExecContext(ctx, "BEGIN IMMEDIATE"); err != nil {
+		return fmt.Errorf("failed to begin immediate transaction: %w", err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			// Background context so the rollback happens even if ctx was canceled
+			_, _ = conn.ExecContext(context.Background(), "ROLLBACK")
+		}
+	}()
+
+	for i, beadsIssue := range beadsIssues {
+		if beadsIssue == nil {
+			continue
+		}
+		if opts.AllOrNothing {
+			if err := createIssueTx(ctx, conn, beadsIssue, issues[i].IssueSubtype, issues[i].Project, opts.Labels[i], opts.Dependencies[i], actor); err != nil {
+				failed[i] = err
+				return &types.BatchCreateError{Errors: failed, Total: len(issues), RolledBack: true}
+			}
+			continue
+		}
+
+		if _, err := conn.ExecContext(ctx, "SAVEPOINT create_issue"); err != nil {
+			return fmt.Errorf("failed to create savepoint: %w", err)
+		}
+		if err := createIssueTx(ctx, conn, beadsIssue, issues[i].IssueSubtype, issues[i].Project, opts.Labels[i], opts.Dependencies[i], actor); err != nil {
+			failed[i] = err
+			beadsIssues[i] = nil
+			if _, err := conn.ExecContext(ctx, "ROLLBACK TO create_issue"); err != nil {
+				return fmt.Errorf("failed to roll back issue %d: %w", i, err)
+			}
+		}
+		if _, err := conn.ExecContext(ctx, "RELEASE create_issue"); err != nil {
+			return fmt.Errorf("failed to release savepoint: %w", err)
+		}
+	}
+
+	if _, err := conn.ExecContext(ctx, "COMMIT"); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	committed = true
+
+	for i, beadsIssue := range beadsIssues {
+		if beadsIssue == nil {
+			continue
+		}
+		issues[i].ID = beadsIssue.ID
+		issues[i].CreatedAt = now
+		issues[i].UpdatedAt = now
+	}
+
+	if len(failed) > 0 {
+		return &types.BatchCreateError{Errors: failed, Total: len(issues)}
+	}
+	return nil
+}
+
+// validateNewIssue runs the checks that don't need the database
+func validateNewIssue(issue *types.Issue, deps []*types.Dependency) error {
+	if err := issue.Validate(); err != nil {
+		return err
+	}
+	if err := vcIssueToBeads(issue).Validate(); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+	for _, dep := range deps {
+		if !beadsLib.DependencyType(dep.Type).IsValid() {
+			return fmt.Errorf("invalid dependency type: %s (must be blocks, related, parent-child, or discovered-from)", dep.Type)
+		}
+	}
+	return nil
+}
+
+// createIssueTx writes one issue with its project, mission state, labels
+// and dependencies. The caller owns the transaction. A generated ID, from
+// the project's prefix, is stored on beadsIssue.
+func createIssueTx(ctx context.Context, conn *sql.Conn, beadsIssue *beadsLib.Issue, subtype types.IssueSubtype, project string, labels []string, deps []*types.Dependency, actor string) error {
+	if beadsIssue.ID == "" {
+		prefix, err := projectPrefix(ctx, conn, project)
+		if err != nil {
+			return err
+		}
+		id, err := nextIssueID(ctx, conn, prefix)
+		if err != nil {
+			return err
+		}
+		beadsIssue.ID = id
+	}
+
+	if err := insertIssue(ctx, conn, beadsIssue, actor); err != nil {
+		return err
+	}
+	if err := assignProject(ctx, conn, beadsIssue.ID, project); err != nil {
+		return err
+	}
+
+	if subtype != "" && subtype != types.SubtypeNormal {
+		if _, err := conn.ExecContext(ctx, `
+			INSERT INTO vc_mission_state (issue_id, subtype, created_at, updated_at)
+			VALUES (?, ?, ?, ?)
+		`, beadsIssue.ID, subtype, beadsIssue.CreatedAt, beadsIssue.CreatedAt); err != nil {
+			return fmt.Errorf("failed to create mission state: %w", err)
+		}
+	}
+
+	for _, label := range labels {
+		if err := insertLabel(ctx, conn, beadsIssue.ID, label, actor); err != nil {
+			return err
+		}
+	}
+
+	for _, dep := range deps {
+		beadsDep := &beadsLib.Dependency{
+			IssueID:     dep.IssueID,
+			DependsOnID: dep.DependsOnID,
+			Type:        beadsLib.DependencyType(dep.Type),
+			CreatedAt:   beadsIssue.CreatedAt,
+			CreatedBy:   actor,
+		}
+		if beadsDep.IssueID == "" {
+			beadsDep.IssueID = beadsIssue.ID
+		}
+		if beadsDep.DependsOnID == "" {
+			beadsDep.DependsOnID = beadsIssue.ID
+		}
+		if err := insertDependency(ctx, conn, beadsDep, actor); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// nextIssueID allocates the next sequential issue ID for a prefix (see
+// projectPrefix). The counter starts from the highest existing ID, as in Beads.
+func nextIssueID(ctx context.Context, conn *sql.Conn, prefix string) (string, error) {
+	var nextID int
+	err := conn.QueryRowContext(ctx, `
+		INSERT INTO issue_counters (prefix, last_id)
+		SELECT ?, COALESCE(MAX(CAST(substr(id, LENGTH(?) + 2) AS INTEGER)), 0) + 1
+		FROM issues
+		WHERE id LIKE ? || '-%'
+		  AND substr(id, LENGTH(?) + 2) GLOB '[0-9]*'
+		ON CONFLICT(prefix) DO UPDATE SET
+			last_id = MAX(
+				last_id,
+				(SELECT COALESCE(MAX(CAST(substr(id, LENGTH(?) + 2) AS INTEGER)), 0)
+				 FROM issues
+				 WHERE id LIKE ? || '-%'
+				   AND substr(id, LENGTH(?) + 2) GLOB '[0-9]*')
+			) + 1
+		RETURNING last_id
+	`, prefix, prefix, prefix, prefix, prefix, prefix, prefix).Scan(&nextID)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate next ID for prefix %s: %w", prefix, err)
+	}
+
+	return fmt.Sprintf("%s-%d", prefix, nextID), nil
+}
+
+// insertIssue writes the issue row with its creation event and dirty marker
+func insertIssue(ctx context.Context, conn *sql.Conn, issue *beadsLib.Issue, actor string) error {
+	_, err := conn.ExecContext(ctx, `
+		INSERT INTO issues (
+			id, title, description, design, acceptance_criteria, notes,
+			status, priority, issue_type, assignee, estimated_minutes,
+			created_at, updated_at, closed_at, external_ref
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		issue.ID, issue.Title, issue.Description, issue.Design,
+		issue.AcceptanceCriteria, issue.Notes, issue.Status,
+		issue.Priority, issue.IssueType, issue.Assignee,
+		issue.EstimatedMinutes, issue.CreatedAt, issue.UpdatedAt,
+		issue.ClosedAt, issue.ExternalRef,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert issue: %w", err)
+	}
+
+	eventData, err := json.Marshal(issue)
+	if err != nil {
+		eventData = []byte(fmt.Sprintf(`{"id":%q,"title":%q}`, issue.ID, issue.Title))
+	}
+	if _, err := conn.ExecContext(ctx, `
+		INSERT INTO events (issue_id, event_type, actor, new_value)
+		VALUES (?, ?, ?, ?)
+	`, issue.ID, beadsLib.EventCreated, actor, string(eventData)); err != nil {
+		return fmt.Errorf("failed to record event: %w", err)
+	}
+
+	return markIssuesDirty(ctx, conn, issue.ID)
+}
+
+// insertLabel adds a label with its audit event
+func insertLabel(ctx context.Context, conn *sql.Conn, issueID, label, actor string) error {
+	if _, err := conn.ExecContext(ctx, `INSERT OR IGNORE INTO labels (issue_id, label) VALUES (?, ?)`, issueID, label); err != nil {
+		return fmt.Errorf("failed to add label %s: %w", label, err)
+	}
+	if _, err := conn.ExecContext(ctx, `
+		INSERT INTO events (issue_id, event_type, actor, comment)
+		VALUES (?, ?, ?, ?)
+	`, issueID, beadsLib.EventLabelAdded, actor, fmt.Sprintf("Added label: %s", label)); err != nil {
+		return fmt.Errorf("failed to record event: %w", err)
+	}
+	return nil
+}
+
+// insertDependency adds a dependency with the same checks as Beads'
+// AddDependency: both issues exist, no self-dependency, parent-child points
+// from child to parent, and no cycles.
+func insertDependency(ctx context.Context, conn *sql.Conn, dep *beadsLib.Dependency, actor string) error {
+	if dep.IssueID == dep.DependsOnID {
+		return fmt.Errorf("issue cannot depend on itself")
+	}
+
+	issueType, err := issueTypeOf(ctx, conn, dep.IssueID)
+	if err != nil {
+		return err
+	}
+	if issueType == "" {
+		return fmt.Errorf("issue %s not found", dep.IssueID)
+	}
+	dependsOnType, err := issueTypeOf(ctx, conn, dep.DependsOnID)
+	if err != nil {
+		return err
+	}
+	if dependsOnType == "" {
+		return fmt.Errorf("dependency target %s not found", dep.DependsOnID)
+	}
+
+	if dep.Type == beadsLib.DepParentChild && issueType == beadsLib.TypeEpic && dependsOnType != beadsLib.TypeEpic {
+		return fmt.Errorf("invalid parent-child dependency: parent (%s) cannot depend on child (%s)", dep.IssueID, dep.DependsOnID)
+	}
+
+	var cycleExists bool
+	err = conn.QueryRowContext(ctx, `
+		WITH RECURSIVE paths AS (
+			SELECT issue_id, depends_on_id, 1 as depth
+			FROM dependencies
+			WHERE issue_id = ?
+
+			UNION ALL
+
+			SELECT d.issue_id, d.depends_on_id, p.depth + 1
+			FROM dependencies d
+			JOIN paths p ON d.issue_id = p.depends_on_id
+			WHERE p.depth < ?
+		)
+		SELECT EXISTS(SELECT 1 FROM paths WHERE depends_on_id = ?)
+	`, dep.DependsOnID, maxDependencyDepth, dep.IssueID).Scan(&cycleExists)
+	if err != nil {
+		return fmt.Errorf("failed to check for cycles: %w", err)
+	}
+	if cycleExists {
+		return fmt.Errorf("cannot add dependency: would create a cycle (%s → %s → ... → %s)",
+			dep.IssueID, dep.DependsOnID, dep.IssueID)
+	}
+
+	if _, err := conn.ExecContext(ctx, `
+		INSERT INTO dependencies (issue_id, depends_on_id, type, created_at, created_by)
+		VALUES (?, ?, ?, ?, ?)
+	`, dep.IssueID, dep.DependsOnID, dep.Type, dep.CreatedAt, dep.CreatedBy); err != nil {
+		return fmt.Errorf("failed to add dependency %s -> %s: %w", dep.IssueID, dep.DependsOnID, err)
+	}
+
+	if _, err := conn.ExecContext(ctx, `
+		INSERT INTO events (issue_id, event_type, actor, comment)
+		VALUES (?, ?, ?, ?)
+	`, dep.IssueID, beadsLib.EventDependencyAdded, actor,
+		fmt.Sprintf("Added dependency: %s %s %s", dep.IssueID, dep.Type, dep.DependsOnID)); err != nil {
+		return fmt.Errorf("failed to record event: %w", err)
+	}
+
+	// Dependencies are exported with each issue, so both need updating
+	return markIssuesDirty(ctx, conn, dep.IssueID, dep.DependsOnID)
+}
+
+// issueTypeOf returns an issue's type, or "" if it doesn't exist
+func issueTypeOf(ctx context.Context, conn *sql.Conn, issueID string) (beadsLib.IssueType, error) {
+	var issueType string
+	err := conn.QueryRowContext(ctx, `SELECT issue_type FROM issues WHERE id = ?`, issueID).Scan(&issueType)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to check issue %s: %w", issueID, err)
+	}
+	return beadsLib.IssueType(issueType), nil
+}
+
+// markIssuesDirty flags issues for Beads' incremental JSONL export
+func markIssuesDirty(ctx context.Context, conn *sql.Conn, issueIDs ...string) error {
+	now := time.Now()
+	for _, issueID := range issueIDs {
+		if _, err := conn.ExecContext(ctx, `
+			INSERT INTO dirty_issues (issue_id, marked_at)
+			VALUES (?, ?)
+			ON CONFLICT (issue_id) DO UPDATE SET marked_at = excluded.marked_at
+		`, issueID, now); err != nil {
+			return fmt.Errorf("failed to mark issue %s dirty: %w", issueID, err)
+		}
+	}
+	return nil
+}
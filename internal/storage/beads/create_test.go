@@ -0,0 +1,369 @@
+package beads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// countRows runs a COUNT(*) query
+func countRows(t *testing.T, store *VCStorage, query string, args ...interface{}) int {
+	t.Helper()
+	var count int
+	if err := store.db.QueryRowContext(context.Background(), query, args...).Scan(&count); err != nil {
+		t.Fatalf("Query %q failed: %v", query, err)
+	}
+	return count
+}
+
+func newCreateTestStore(t *testing.T) (*VCStorage, *types.Issue) {
+	t.Helper()
+	ctx := context.Background()
+	store, err := NewVCStorage(ctx, filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create VC storage: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	parent := &types.Issue{Title: "Parent", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, parent, "test"); err != nil {
+		t.Fatalf("Failed to create parent: %v", err)
+	}
+	return store, parent
+}
+
+func TestCreateIssueWithMetadata(t *testing.T) {
+	ctx := context.Background()
+	store, parent := newCreateTestStore(t)
+
+	blocked := &types.Issue{Title: "Blocked", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, blocked, "test"); err != nil {
+		t.Fatalf("Failed to create blocked issue: %v", err)
+	}
+
+	issue := &types.Issue{Title: "Discovered", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeBug}
+	deps := []*types.Dependency{
+		{DependsOnID: parent.ID, Type: types.DepDiscoveredFrom}, // new issue -> parent
+		{IssueID: blocked.ID, Type: types.DepBlocks},            // blocked -> new issue
+	}
+	if err := store.CreateIssueWithMetadata(ctx, issue, []string{"discovered:blocker", "area:storage"}, deps, "test"); err != nil {
+		t.Fatalf("CreateIssueWithMetadata failed: %v", err)
+	}
+	if issue.ID == "" || issue.CreatedAt.IsZero() {
+		t.Fatalf("Expected ID and timestamps to be set, got %+v", issue)
+	}
+
+	stored, err := store.GetIssue(ctx, issue.ID)
+	if err != nil || stored == nil {
+		t.Fatalf("Failed to read back issue %s: %v", issue.ID, err)
+	}
+	if stored.Title != "Discovered" || stored.IssueType != types.TypeBug {
+		t.Errorf("Unexpected stored issue: %+v", stored)
+	}
+
+	labels, err := store.GetLabels(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetLabels failed: %v", err)
+	}
+	if fmt.Sprint(labels) != "[area:storage discovered:blocker]" {
+		t.Errorf("Unexpected labels: %v", labels)
+	}
+
+	records, err := store.GetDependencyRecords(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetDependencyRecords failed: %v", err)
+	}
+	if len(records) != 1 || records[0].DependsOnID != parent.ID || records[0].Type != types.DepDiscoveredFrom {
+		t.Errorf("Unexpected dependencies of new issue: %+v", records)
+	}
+	records, err = store.GetDependencyRecords(ctx, blocked.ID)
+	if err != nil {
+		t.Fatalf("GetDependencyRecords failed: %v", err)
+	}
+	if len(records) != 1 || records[0].DependsOnID != issue.ID || records[0].Type != types.DepBlocks {
+		t.Errorf("Unexpected dependencies of blocked issue: %+v", records)
+	}
+
+	// Same audit trail as the separate Beads calls: created, 2 labels and a
+	// dependency on the new issue, the other dependency on the blocked one
+	if n := countRows(t, store, `SELECT COUNT(*) FROM events WHERE issue_id = ?`, issue.ID); n != 4 {
+		t.Errorf("Expected 4 audit events on the new issue, got %d", n)
+	}
+	if n := countRows(t, store, `SELECT COUNT(*) FROM events WHERE issue_id = ? AND event_type = 'dependency_added'`, blocked.ID); n != 1 {
+		t.Errorf("Expected a dependency event on the blocked issue, got %d", n)
+	}
+	if n := countRows(t, store, `SELECT COUNT(*) FROM dirty_issues WHERE issue_id IN (?, ?, ?)`, issue.ID, parent.ID, blocked.ID); n != 3 {
+		t.Errorf("Expected all three issues marked dirty, got %d", n)
+	}
+
+	// IDs keep counting from where Beads left off
+	next := &types.Issue{Title: "Next", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, next, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	if next.ID == issue.ID || next.ID == parent.ID || next.ID == blocked.ID {
+		t.Errorf("Beads reused ID %s", next.ID)
+	}
+}
+
+// TestCreateIssueWithMetadataRollback verifies a failure anywhere leaves no
+// trace of the issue
+func TestCreateIssueWithMetadataRollback(t *testing.T) {
+	ctx := context.Background()
+	store, parent := newCreateTestStore(t)
+
+	tests := []struct {
+		name string
+		deps []*types.Dependency
+	}{
+		{"missing dependency target", []*types.Dependency{
+			{DependsOnID: parent.ID, Type: types.DepBlocks},
+			{DependsOnID: "vc-9999", Type: types.DepBlocks},
+		}},
+		{"cycle", []*types.Dependency{
+			{DependsOnID: parent.ID, Type: types.DepBlocks},
+			{IssueID: parent.ID, Type: types.DepBlocks},
+		}},
+		{"self dependency", []*types.Dependency{{Type: types.DepRelated}}},
+		{"invalid type", []*types.Dependency{{DependsOnID: parent.ID, Type: "bogus"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issue := &types.Issue{Title: "Doomed " + tt.name, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+			if err := store.CreateIssueWithMetadata(ctx, issue, []string{"doomed"}, tt.deps, "test"); err == nil {
+				t.Fatal("Expected CreateIssueWithMetadata to fail")
+			}
+			if issue.ID != "" {
+				t.Errorf("Expected no ID on failure, got %s", issue.ID)
+			}
+
+			if n := countRows(t, store, `SELECT COUNT(*) FROM issues WHERE id NOT IN (?, ?)`, parent.ID, types.SystemIssueID); n != 0 {
+				t.Errorf("Expected no new issues, got %d", n)
+			}
+			if n := countRows(t, store, `SELECT COUNT(*) FROM labels`); n != 0 {
+				t.Errorf("Expected no labels, got %d", n)
+			}
+			if n := countRows(t, store, `SELECT COUNT(*) FROM dependencies`); n != 0 {
+				t.Errorf("Expected no dependencies, got %d", n)
+			}
+			if n := countRows(t, store, `SELECT COUNT(*) FROM events WHERE issue_id != ?`, parent.ID); n != 0 {
+				t.Errorf("Expected no audit events, got %d", n)
+			}
+		})
+	}
+}
+
+// TestCreateIssueWithMetadataConcurrent verifies parallel creates never tear:
+// every create either lands with all its metadata or not at all, and IDs
+// are never handed out twice
+func TestCreateIssueWithMetadataConcurrent(t *testing.T) {
+	ctx := context.Background()
+	store, parent := newCreateTestStore(t)
+
+	const workers = 20
+	var wg sync.WaitGroup
+	ids := make([]string, workers)
+	errs := make([]error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			deps := []*types.Dependency{{DependsOnID: parent.ID, Type: types.DepDiscoveredFrom}}
+			if i%2 == 1 {
+				// Odd workers fail on their last dependency
+				deps = append(deps, &types.Dependency{DependsOnID: "vc-missing", Type: types.DepBlocks})
+			}
+			issue := &types.Issue{Title: fmt.Sprintf("Parallel %d", i), Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+			errs[i] = store.CreateIssueWithMetadata(ctx, issue, []string{"parallel", fmt.Sprintf("worker:%d", i)}, deps, "test")
+			ids[i] = issue.ID
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[string]bool)
+	for i := 0; i < workers; i++ {
+		if i%2 == 1 {
+			if errs[i] == nil {
+				t.Errorf("Worker %d: expected failure", i)
+			}
+			continue
+		}
+		if errs[i] != nil {
+			t.Fatalf("Worker %d: CreateIssueWithMetadata failed: %v", i, errs[i])
+		}
+		if seen[ids[i]] {
+			t.Errorf("ID %s handed out twice", ids[i])
+		}
+		seen[ids[i]] = true
+
+		if n := countRows(t, store, `SELECT COUNT(*) FROM labels WHERE issue_id = ?`, ids[i]); n != 2 {
+			t.Errorf("Worker %d: expected 2 labels on %s, got %d", i, ids[i], n)
+		}
+		if n := countRows(t, store, `SELECT COUNT(*) FROM dependencies WHERE issue_id = ?`, ids[i]); n != 1 {
+			t.Errorf("Worker %d: expected 1 dependency on %s, got %d", i, ids[i], n)
+		}
+	}
+
+	if n := countRows(t, store, `SELECT COUNT(*) FROM issues WHERE id NOT IN (?, ?)`, parent.ID, types.SystemIssueID); n != workers/2 {
+		t.Errorf("Expected %d issues, got %d", workers/2, n)
+	}
+	if n := countRows(t, store, `SELECT COUNT(*) FROM labels`); n != workers {
+		t.Errorf("Expected %d labels, got %d", workers, n)
+	}
+	if n := countRows(t, store, `SELECT COUNT(*) FROM dependencies`); n != workers/2 {
+		t.Errorf("Expected %d dependencies, got %d", workers/2, n)
+	}
+}
+
+func TestCreateIssues(t *testing.T) {
+	ctx := context.Background()
+	store, parent := newCreateTestStore(t)
+
+	issues := []*types.Issue{
+		{Title: "First", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask},
+		{Title: "Bad priority", Status: types.StatusOpen, Priority: 9, IssueType: types.TypeTask},
+		{Title: "Second", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeBug},
+		{Title: "Missing target", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask},
+	}
+	opts := types.CreateIssuesOptions{
+		Labels: map[int][]string{0: {"batch"}, 2: {"batch"}, 3: {"batch"}},
+		Dependencies: map[int][]*types.Dependency{
+			0: {{DependsOnID: parent.ID, Type: types.DepDiscoveredFrom}},
+			2: {{DependsOnID: parent.ID, Type: types.DepDiscoveredFrom}},
+			3: {{DependsOnID: "vc-9999", Type: types.DepBlocks}},
+		},
+	}
+	err := store.CreateIssues(ctx, issues, "test", opts)
+
+	var batchErr *types.BatchCreateError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("Expected a BatchCreateError, got %v", err)
+	}
+	if batchErr.RolledBack || batchErr.Total != 4 || len(batchErr.Errors) != 2 {
+		t.Fatalf("Unexpected batch error: %+v", batchErr)
+	}
+	if batchErr.Errors[1] == nil || batchErr.Errors[3] == nil {
+		t.Errorf("Expected errors for #1 and #3, got %v", batchErr)
+	}
+
+	if issues[1].ID != "" || issues[3].ID != "" {
+		t.Errorf("Expected failed issues to have no ID, got %q and %q", issues[1].ID, issues[3].ID)
+	}
+	for _, i := range []int{0, 2} {
+		if issues[i].ID == "" || issues[i].CreatedAt.IsZero() {
+			t.Fatalf("Expected issue #%d to be created, got %+v", i, issues[i])
+		}
+		if n := countRows(t, store, `SELECT COUNT(*) FROM labels WHERE issue_id = ?`, issues[i].ID); n != 1 {
+			t.Errorf("Expected a label on %s, got %d", issues[i].ID, n)
+		}
+		if n := countRows(t, store, `SELECT COUNT(*) FROM dependencies WHERE issue_id = ?`, issues[i].ID); n != 1 {
+			t.Errorf("Expected a dependency on %s, got %d", issues[i].ID, n)
+		}
+	}
+
+	// Rolled back issues give their IDs back, so the created ones are consecutive
+	var first, second int
+	fmt.Sscanf(issues[0].ID, "vc-%d", &first)
+	fmt.Sscanf(issues[2].ID, "vc-%d", &second)
+	if second != first+1 {
+		t.Errorf("Expected consecutive IDs, got %s and %s", issues[0].ID, issues[2].ID)
+	}
+
+	if n := countRows(t, store, `SELECT COUNT(*) FROM issues WHERE id NOT IN (?, ?)`, parent.ID, types.SystemIssueID); n != 2 {
+		t.Errorf("Expected 2 new issues, got %d", n)
+	}
+	if n := countRows(t, store, `SELECT COUNT(*) FROM labels`); n != 2 {
+		t.Errorf("Expected 2 labels, got %d", n)
+	}
+}
+
+func TestCreateIssuesAllOrNothing(t *testing.T) {
+	ctx := context.Background()
+	store, parent := newCreateTestStore(t)
+
+	issues := []*types.Issue{
+		{Title: "First", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask},
+		{Title: "Second", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask},
+	}
+	opts := types.CreateIssuesOptions{
+		Labels:       map[int][]string{0: {"batch"}},
+		Dependencies: map[int][]*types.Dependency{1: {{DependsOnID: "vc-9999", Type: types.DepBlocks}}},
+		AllOrNothing: true,
+	}
+	err := store.CreateIssues(ctx, issues, "test", opts)
+
+	var batchErr *types.BatchCreateError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("Expected a BatchCreateError, got %v", err)
+	}
+	if !batchErr.RolledBack || batchErr.Errors[1] == nil {
+		t.Errorf("Expected a rolled back batch failing on #1, got %v", batchErr)
+	}
+	for i, issue := range issues {
+		if issue.ID != "" {
+			t.Errorf("Expected issue #%d to have no ID, got %s", i, issue.ID)
+		}
+	}
+
+	if n := countRows(t, store, `SELECT COUNT(*) FROM issues WHERE id NOT IN (?, ?)`, parent.ID, types.SystemIssueID); n != 0 {
+		t.Errorf("Expected no new issues, got %d", n)
+	}
+	if n := countRows(t, store, `SELECT COUNT(*) FROM labels`); n != 0 {
+		t.Errorf("Expected no labels, got %d", n)
+	}
+	if n := countRows(t, store, `SELECT COUNT(*) FROM events WHERE issue_id != ?`, parent.ID); n != 0 {
+		t.Errorf("Expected no audit events, got %d", n)
+	}
+}
+
+// BenchmarkCreateIssues compares creating a typical analysis result of 50
+// discovered issues one at a time against a single batch
+func BenchmarkCreateIssues(b *testing.B) {
+	const batchSize = 50
+	ctx := context.Background()
+
+	newIssues := func() []*types.Issue {
+		issues := make([]*types.Issue, batchSize)
+		for i := range issues {
+			issues[i] = &types.Issue{Title: fmt.Sprintf("Discovered %d", i), Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		}
+		return issues
+	}
+
+	b.Run("sequential", func(b *testing.B) {
+		store, err := NewVCStorage(ctx, filepath.Join(b.TempDir(), "bench.db"))
+		if err != nil {
+			b.Fatalf("Failed to create VC storage: %v", err)
+		}
+		defer store.Close()
+
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			for _, issue := range newIssues() {
+				if err := store.CreateIssue(ctx, issue, "bench"); err != nil {
+					b.Fatalf("CreateIssue failed: %v", err)
+				}
+			}
+		}
+	})
+
+	b.Run("batch", func(b *testing.B) {
+		store, err := NewVCStorage(ctx, filepath.Join(b.TempDir(), "bench.db"))
+		if err != nil {
+			b.Fatalf("Failed to create VC storage: %v", err)
+		}
+		defer store.Close()
+
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			if err := store.CreateIssues(ctx, newIssues(), "bench", types.CreateIssuesOptions{}); err != nil {
+				b.Fatalf("CreateIssues failed: %v", err)
+			}
+		}
+	})
+}
@@ -0,0 +1,112 @@
+package beads
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestNonBlockingDependencies(t *testing.T) {
+	ctx := context.Background()
+	store, origin := newCreateTestStore(t)
+
+	linked := make(map[types.DependencyType]string)
+	for _, depType := range []types.DependencyType{types.DepBlocks, types.DepRelated, types.DepDiscoveredFrom} {
+		issue := &types.Issue{Title: string(depType), Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		dep := &types.Dependency{IssueID: issue.ID, DependsOnID: origin.ID, Type: depType}
+		if err := store.AddDependency(ctx, dep, "test"); err != nil {
+			t.Fatalf("AddDependency(%s) failed: %v", depType, err)
+		}
+		linked[depType] = issue.ID
+	}
+
+	// Only the blocks link holds its issue back
+	ready, err := store.GetReadyWork(ctx, types.WorkFilter{Status: types.StatusOpen})
+	if err != nil {
+		t.Fatalf("GetReadyWork failed: %v", err)
+	}
+	readyIDs := make(map[string]bool)
+	for _, issue := range ready {
+		readyIDs[issue.ID] = true
+	}
+	if readyIDs[linked[types.DepBlocks]] || !readyIDs[linked[types.DepRelated]] || !readyIDs[linked[types.DepDiscoveredFrom]] || !readyIDs[origin.ID] {
+		t.Errorf("Unexpected ready set: %v", readyIDs)
+	}
+
+	blocked, err := store.GetBlockedIssues(ctx)
+	if err != nil {
+		t.Fatalf("GetBlockedIssues failed: %v", err)
+	}
+	if len(blocked) != 1 || blocked[0].ID != linked[types.DepBlocks] {
+		t.Errorf("Expected only %s to be blocked, got %d issues", linked[types.DepBlocks], len(blocked))
+	}
+
+	// The tree hangs every linked issue off the origin with its link type
+	tree, err := store.GetDependencyTree(ctx, linked[types.DepDiscoveredFrom], 10)
+	if err != nil {
+		t.Fatalf("GetDependencyTree failed: %v", err)
+	}
+	if len(tree) != 2 || tree[0].ParentID != "" || tree[0].DependencyType != "" {
+		t.Fatalf("Unexpected tree: %+v", tree)
+	}
+	if tree[1].ID != origin.ID || tree[1].ParentID != linked[types.DepDiscoveredFrom] || tree[1].DependencyType != types.DepDiscoveredFrom {
+		t.Errorf("Expected %s under %s via discovered-from, got parent %q type %q",
+			origin.ID, linked[types.DepDiscoveredFrom], tree[1].ParentID, tree[1].DependencyType)
+	}
+
+	if err := store.AddDependency(ctx, &types.Dependency{IssueID: origin.ID, DependsOnID: linked[types.DepRelated], Type: "depends"}, "test"); err == nil {
+		t.Error("Expected an unknown dependency type to be rejected")
+	}
+}
+
+func TestMigrateDependencyTypes(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "legacy.db")
+	store, err := NewVCStorage(ctx, dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create VC storage: %v", err)
+	}
+
+	var ids []string
+	for i := 0; i < 2; i++ {
+		issue := &types.Issue{Title: "legacy", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		ids = append(ids, issue.ID)
+	}
+	// An untyped row, as written by versions before migration 003
+	if _, err := store.db.ExecContext(ctx, `INSERT INTO dependencies (issue_id, depends_on_id, type, created_by) VALUES (?, ?, '', 'test')`, ids[0], ids[1]); err != nil {
+		t.Fatalf("Failed to insert legacy dependency: %v", err)
+	}
+	if _, err := store.db.ExecContext(ctx, `DELETE FROM vc_schema_migrations WHERE version >= 3`); err != nil {
+		t.Fatalf("Failed to roll back schema version: %v", err)
+	}
+	_ = store.Close()
+
+	store, err = NewVCStorage(ctx, dbPath)
+	if err != nil {
+		t.Fatalf("Failed to reopen VC storage: %v", err)
+	}
+	defer store.Close()
+
+	records, err := store.GetDependencyRecords(ctx, ids[0])
+	if err != nil {
+		t.Fatalf("GetDependencyRecords failed: %v", err)
+	}
+	if len(records) != 1 || records[0].Type != types.DepBlocks {
+		t.Errorf("Expected the legacy dependency to become blocks, got %+v", records)
+	}
+	blocked, err := store.GetBlockedIssues(ctx)
+	if err != nil {
+		t.Fatalf("GetBlockedIssues failed: %v", err)
+	}
+	if len(blocked) != 1 || blocked[0].ID != ids[0] {
+		t.Errorf("Expected %s to be blocked after migration", ids[0])
+	}
+}
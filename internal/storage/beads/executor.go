@@ -6,8 +6,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/events"
 	"github.com/steveyegge/vc/internal/types"
 )
 
@@ -22,17 +25,18 @@ func (s *VCStorage) RegisterInstance(ctx context.Context, instance *types.Execut
 	// IMPORTANT: We use ON CONFLICT DO UPDATE instead of INSERT OR REPLACE because
 	// REPLACE triggers DELETE, which cascades to execution_state.executor_instance_id (ON DELETE SET NULL)
 	_, err := s.db.ExecContext(ctx, `
-		INSERT INTO vc_executor_instances (id, hostname, pid, version, started_at, last_heartbeat, status)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO vc_executor_instances (id, hostname, pid, version, started_at, last_heartbeat, status, metadata)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(id) DO UPDATE SET
 			hostname = excluded.hostname,
 			pid = excluded.pid,
 			version = excluded.version,
 			started_at = excluded.started_at,
 			last_heartbeat = excluded.last_heartbeat,
-			status = excluded.status
+			status = excluded.status,
+			metadata = excluded.metadata
 	`, instance.InstanceID, instance.Hostname, instance.PID, instance.Version,
-		instance.StartedAt, instance.LastHeartbeat, instance.Status)
+		instance.StartedAt, instance.LastHeartbeat, instance.Status, instanceMetadata(instance))
 
 	if err != nil {
 		return fmt.Errorf("failed to register executor instance: %w", err)
@@ -41,6 +45,14 @@ func (s *VCStorage) RegisterInstance(ctx context.Context, instance *types.Execut
 	return nil
 }
 
+// instanceMetadata returns the instance's metadata, "{}" if unset
+func instanceMetadata(instance *types.ExecutorInstance) string {
+	if instance.Metadata == "" {
+		return "{}"
+	}
+	return instance.Metadata
+}
+
 // MarkInstanceStopped marks an executor instance as stopped
 func (s *VCStorage) MarkInstanceStopped(ctx context.Context, instanceID string) error {
 	result, err := s.db.ExecContext(ctx, `
@@ -92,7 +104,7 @@ func (s *VCStorage) UpdateHeartbeat(ctx context.Context, instanceID string) erro
 // GetActiveInstances retrieves all active executor instances
 func (s *VCStorage) GetActiveInstances(ctx context.Context) ([]*types.ExecutorInstance, error) {
 	rows, err := s.db.QueryContext(ctx, `
-		SELECT id, hostname, pid, version, started_at, last_heartbeat, status
+		SELECT id, hostname, pid, version, started_at, last_heartbeat, status, metadata
 		FROM vc_executor_instances
 		WHERE status = 'running'
 		ORDER BY started_at
@@ -106,7 +118,7 @@ func (s *VCStorage) GetActiveInstances(ctx context.Context) ([]*types.ExecutorIn
 	for rows.Next() {
 		var inst types.ExecutorInstance
 		if err := rows.Scan(&inst.InstanceID, &inst.Hostname, &inst.PID, &inst.Version,
-			&inst.StartedAt, &inst.LastHeartbeat, &inst.Status); err != nil {
+			&inst.StartedAt, &inst.LastHeartbeat, &inst.Status, &inst.Metadata); err != nil {
 			return nil, fmt.Errorf("failed to scan instance: %w", err)
 		}
 		instances = append(instances, &inst)
@@ -222,6 +234,10 @@ func (s *VCStorage) CleanupStaleInstances(ctx context.Context, staleThresholdSec
 
 		// Release each claimed issue
 		for _, issueID := range issueIDs {
+			if err := restoreAssignee(ctx, tx, issueID); err != nil {
+				return 0, err
+			}
+
 			// Clear the executor claim but preserve checkpoint data
 			// This allows recovery/resume after cleanup
 			_, err = tx.ExecContext(ctx, `
@@ -336,72 +352,133 @@ func (s *VCStorage) DeleteOldStoppedInstances(ctx context.Context, olderThanSeco
 // ======================================================================
 
 // ClaimIssue atomically claims an issue for execution
+//
+// Both writes are conditional, so concurrent claims can't race (there is no
+// read-then-write window): the first flips the issue from open to
+// in_progress, the second takes the execution state row unless an executor
+// holds an active claim on it. A claim that loses returns a
+// *types.ClaimConflictError (errors.Is ErrAlreadyClaimed). A mission, or work
+// in a mission, whose plan awaits approval is refused with a
+// *types.AwaitingApprovalError. Any other error is a real failure.
+//
+// The claiming executor's short ID becomes the assignee; releasing the
+// claim restores the previous one.
 func (s *VCStorage) ClaimIssue(ctx context.Context, issueID, executorInstanceID string) error {
-	// Begin transaction to ensure atomicity
-	tx, err := s.db.BeginTx(ctx, nil)
+	// Take the write lock up front (BEGIN IMMEDIATE needs raw SQL on one
+	// connection): a deferred transaction would fail with SQLITE_BUSY when
+	// upgrading its read lock under contention, without waiting out
+	// busy_timeout, and the losing claims would look like storage errors
+	conn, err := s.db.Conn(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return fmt.Errorf("failed to acquire connection: %w", err)
 	}
-	defer func() { _ = tx.Rollback() }() // Rollback if not committed
-
-	// First, check if issue is already claimed or being executed
-	var existingClaim string
-	err = tx.QueryRowContext(ctx, `
-		SELECT executor_instance_id
-		FROM vc_issue_execution_state
-		WHERE issue_id = ? AND state IN ('claimed', 'assessing', 'executing', 'analyzing', 'gates', 'committing')
-	`, issueID).Scan(&existingClaim)
+	defer func() { _ = conn.Close() }()
 
-	if err == nil {
-		// Already claimed or being executed
-		return fmt.Errorf("issue %s already claimed by %s", issueID, existingClaim)
-	} else if err != sql.ErrNoRows {
-		return fmt.Errorf("failed to check existing claim: %w", err)
+	if _, err := conn.ExecContext(ctx, "BEGIN IMMEDIATE"); err != nil {
+		return fmt.Errorf("failed to begin immediate transaction: %w", err)
 	}
+	committed := false
+	defer func() {
+		if !committed {
+			// Background context so the rollback happens even if ctx was canceled
+			_, _ = conn.ExecContext(context.Background(), "ROLLBACK")
+		}
+	}()
 
-	// Insert or update claim
-	_, err = tx.ExecContext(ctx, `
-		INSERT INTO vc_issue_execution_state (issue_id, executor_instance_id, claimed_at, state, updated_at)
-		VALUES (?, ?, ?, ?, ?)
-		ON CONFLICT(issue_id) DO UPDATE SET
-			executor_instance_id = excluded.executor_instance_id,
-			claimed_at = excluded.claimed_at,
-			state = ?,
-			updated_at = excluded.updated_at
-	`, issueID, executorInstanceID, time.Now(), types.ExecutionStateClaimed, time.Now(), types.ExecutionStateClaimed)
+	now := time.Now()
 
-	if err != nil {
-		return fmt.Errorf("failed to claim issue: %w", err)
+	// Missions and their work wait for plan approval
+	if err := checkMissionApproved(ctx, conn, issueID); err != nil {
+		return err
 	}
 
 	// Update issue status to in_progress in Beads (through transaction)
 	// Only update if current status is 'open' - refuse to claim closed issues (vc-173)
-	result, err := tx.ExecContext(ctx, `
+	result, err := conn.ExecContext(ctx, `
 		UPDATE issues SET status = ?, updated_at = ?
 		WHERE id = ? AND status = 'open'
-	`, "in_progress", time.Now(), issueID)
-
+	`, types.StatusInProgress, now, issueID)
 	if err != nil {
 		return fmt.Errorf("failed to update issue status: %w", err)
 	}
-
-	// Check if any rows were affected - if not, issue was not open
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
 		return fmt.Errorf("failed to check rows affected: %w", err)
 	}
 	if rowsAffected == 0 {
-		return fmt.Errorf("cannot claim issue %s: issue is not open (may be closed or in_progress)", issueID)
+		return claimFailure(ctx, conn, issueID)
+	}
+
+	// Insert or take over the claim, unless it's held by an active execution
+	result, err = conn.ExecContext(ctx, `
+		INSERT INTO vc_issue_execution_state (issue_id, executor_instance_id, claimed_at, state, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(issue_id) DO UPDATE SET
+			executor_instance_id = excluded.executor_instance_id,
+			claimed_at = excluded.claimed_at,
+			state = excluded.state,
+			updated_at = excluded.updated_at
+		WHERE vc_issue_execution_state.state NOT IN `+activeExecutionStates+`
+	`, issueID, executorInstanceID, now, types.ExecutionStateClaimed, now)
+	if err != nil {
+		return fmt.Errorf("failed to claim issue: %w", err)
+	}
+	rowsAffected, err = result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return claimFailure(ctx, conn, issueID)
+	}
+
+	// The executor is the assignee until it releases the issue
+	if err := assignToExecutor(ctx, conn, issueID, executorInstanceID, now); err != nil {
+		return err
 	}
 
 	// Commit transaction
-	if err := tx.Commit(); err != nil {
+	if _, err := conn.ExecContext(ctx, "COMMIT"); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
+	committed = true
 
 	return nil
 }
 
+// activeExecutionStates lists, as an SQL tuple, the execution states in
+// which an executor holds the issue
+const activeExecutionStates = `('claimed', 'assessing', 'executing', 'analyzing', 'gates', 'committing')`
+
+// claimFailure explains a claim whose conditional write matched nothing: the
+// issue is held by someone else (contention), or it isn't claimable at all
+func claimFailure(ctx context.Context, conn *sql.Conn, issueID string) error {
+	var holder string
+	err := conn.QueryRowContext(ctx, `
+		SELECT executor_instance_id
+		FROM vc_issue_execution_state
+		WHERE issue_id = ? AND state IN `+activeExecutionStates+`
+	`, issueID).Scan(&holder)
+	if err == nil {
+		return &types.ClaimConflictError{IssueID: issueID, ClaimedBy: holder}
+	}
+	if err != sql.ErrNoRows {
+		return fmt.Errorf("failed to check existing claim: %w", err)
+	}
+
+	var status string
+	err = conn.QueryRowContext(ctx, `SELECT status FROM issues WHERE id = ?`, issueID).Scan(&status)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("cannot claim issue %s: issue not found", issueID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check issue status: %w", err)
+	}
+	if status == string(types.StatusInProgress) {
+		return &types.ClaimConflictError{IssueID: issueID}
+	}
+	return fmt.Errorf("cannot claim issue %s: %w (status %s)", issueID, types.ErrNotClaimable, status)
+}
+
 // GetExecutionState retrieves execution state for an issue
 // Returns (nil, nil) if no execution state exists (not an error condition)
 func (s *VCStorage) GetExecutionState(ctx context.Context, issueID string) (*types.IssueExecutionState, error) {
@@ -410,9 +487,10 @@ func (s *VCStorage) GetExecutionState(ctx context.Context, issueID string) (*typ
 	var claimedAt sql.NullTime
 	var checkpointData sql.NullString
 	var errorMessage sql.NullString
+	var previousAssignee sql.NullString
 
 	err := s.db.QueryRowContext(ctx, `
-		SELECT issue_id, executor_instance_id, claimed_at, state, checkpoint_data, error_message, updated_at
+		SELECT issue_id, executor_instance_id, claimed_at, state, checkpoint_data, error_message, updated_at, previous_assignee
 		FROM vc_issue_execution_state
 		WHERE issue_id = ?
 	`, issueID).Scan(
@@ -423,6 +501,7 @@ func (s *VCStorage) GetExecutionState(ctx context.Context, issueID string) (*typ
 		&checkpointData,
 		&errorMessage,
 		&state.UpdatedAt,
+		&previousAssignee,
 	)
 
 	if err != nil {
@@ -445,6 +524,9 @@ func (s *VCStorage) GetExecutionState(ctx context.Context, issueID string) (*typ
 	if errorMessage.Valid {
 		state.ErrorMessage = errorMessage.String
 	}
+	if previousAssignee.Valid {
+		state.PreviousAssignee = previousAssignee.String
+	}
 
 	return &state, nil
 }
@@ -501,19 +583,22 @@ func (s *VCStorage) UpdateExecutionState(ctx context.Context, issueID string, ne
 	return nil
 }
 
-// SaveCheckpoint saves checkpoint data for an issue
-func (s *VCStorage) SaveCheckpoint(ctx context.Context, issueID string, checkpointData interface{}) error {
-	// Marshal checkpoint data to JSON
-	dataJSON, err := json.Marshal(checkpointData)
-	if err != nil {
-		return fmt.Errorf("failed to marshal checkpoint data: %w", err)
+// SaveCheckpoint saves checkpoint data for an issue (nil clears it)
+func (s *VCStorage) SaveCheckpoint(ctx context.Context, issueID string, checkpoint *types.CheckpointV1) error {
+	var data sql.NullString
+	if checkpoint != nil {
+		encoded, err := types.MarshalCheckpoint(checkpoint)
+		if err != nil {
+			return err
+		}
+		data = sql.NullString{String: encoded, Valid: true}
 	}
 
-	_, err = s.db.ExecContext(ctx, `
+	_, err := s.db.ExecContext(ctx, `
 		UPDATE vc_issue_execution_state
 		SET checkpoint_data = ?, updated_at = ?
 		WHERE issue_id = ?
-	`, string(dataJSON), time.Now(), issueID)
+	`, data, time.Now(), issueID)
 
 	if err != nil {
 		return fmt.Errorf("failed to save checkpoint: %w", err)
@@ -522,8 +607,8 @@ func (s *VCStorage) SaveCheckpoint(ctx context.Context, issueID string, checkpoi
 	return nil
 }
 
-// GetCheckpoint retrieves checkpoint data for an issue
-func (s *VCStorage) GetCheckpoint(ctx context.Context, issueID string) (string, error) {
+// GetCheckpoint retrieves and decodes the checkpoint for an issue
+func (s *VCStorage) GetCheckpoint(ctx context.Context, issueID string) (*types.CheckpointV1, error) {
 	var checkpointData sql.NullString
 
 	err := s.db.QueryRowContext(ctx, `
@@ -534,19 +619,19 @@ func (s *VCStorage) GetCheckpoint(ctx context.Context, issueID string) (string,
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return "", nil // No checkpoint
+			return nil, nil // No checkpoint
 		}
-		return "", fmt.Errorf("failed to query checkpoint: %w", err)
+		return nil, fmt.Errorf("failed to query checkpoint: %w", err)
 	}
 
-	if checkpointData.Valid {
-		return checkpointData.String, nil
+	if !checkpointData.Valid || checkpointData.String == "" {
+		return nil, nil
 	}
-
-	return "", nil
+	return types.UnmarshalCheckpoint(checkpointData.String)
 }
 
-// ReleaseIssue releases an issue claim (deletes execution state)
+// ReleaseIssue releases an issue claim (deletes execution state) and
+// restores the assignee from before the claim
 func (s *VCStorage) ReleaseIssue(ctx context.Context, issueID string) error {
 	// Check if execution state exists first
 	state, err := s.GetExecutionState(ctx, issueID)
@@ -556,6 +641,9 @@ func (s *VCStorage) ReleaseIssue(ctx context.Context, issueID string) error {
 	if state == nil {
 		return fmt.Errorf("execution state not found for issue %s", issueID)
 	}
+	if err := restoreAssignee(ctx, s.db, issueID); err != nil {
+		return err
+	}
 
 	// Delete the execution state
 	result, err := s.db.ExecContext(ctx, `
@@ -579,8 +667,13 @@ func (s *VCStorage) ReleaseIssue(ctx context.Context, issueID string) error {
 	return nil
 }
 
-// ReleaseIssueAndReopen releases claim and reopens the issue
+// ReleaseIssueAndReopen releases claim, restoring the assignee, and reopens
+// the issue
 func (s *VCStorage) ReleaseIssueAndReopen(ctx context.Context, issueID, actor, errorComment string) error {
+	if err := restoreAssignee(ctx, s.db, issueID); err != nil {
+		return err
+	}
+
 	// Update execution state to failed
 	_, err := s.db.ExecContext(ctx, `
 		UPDATE vc_issue_execution_state
@@ -616,25 +709,88 @@ func (s *VCStorage) ReleaseIssueAndReopen(ctx context.Context, issueID, actor, e
 // EXECUTION HISTORY (VC extension table: vc_execution_history)
 // ======================================================================
 
-// RecordExecutionAttempt records an execution attempt in history
+// RecordExecutionAttempt records an execution attempt in history and sets
+// its ID
 func (s *VCStorage) RecordExecutionAttempt(ctx context.Context, attempt *types.ExecutionAttempt) error {
-	_, err := s.db.ExecContext(ctx, `
-		INSERT INTO vc_execution_history (issue_id, executor_instance_id, attempt_number, started_at, completed_at, success, exit_code, summary, output_sample, error_sample)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	if err := attempt.Validate(); err != nil {
+		return fmt.Errorf("invalid execution attempt: %w", err)
+	}
+
+	artifacts, err := marshalArtifacts(attempt.Artifacts)
+	if err != nil {
+		return err
+	}
+	phases, err := marshalPhases(attempt.Phases)
+	if err != nil {
+		return err
+	}
+	files, err := marshalFiles(attempt.Files)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO vc_execution_history (issue_id, executor_instance_id, attempt_number, started_at, completed_at, success, exit_code, summary, output_sample, error_sample, artifacts, phases, commit_hash, branch, merge_commit, files)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`, attempt.IssueID, attempt.ExecutorInstanceID, attempt.AttemptNumber, attempt.StartedAt, attempt.CompletedAt,
-		attempt.Success, attempt.ExitCode, attempt.Summary, attempt.OutputSample, attempt.ErrorSample)
+		attempt.Success, attempt.ExitCode, attempt.Summary, attempt.OutputSample, attempt.ErrorSample, artifacts, phases,
+		attempt.CommitHash, attempt.Branch, attempt.MergeCommit, files)
 
 	if err != nil {
 		return fmt.Errorf("failed to record execution attempt: %w", err)
 	}
 
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get execution attempt ID: %w", err)
+	}
+	attempt.ID = id
+
+	return nil
+}
+
+// UpdateExecutionAttempt records the outcome of a previously recorded attempt
+func (s *VCStorage) UpdateExecutionAttempt(ctx context.Context, attempt *types.ExecutionAttempt) error {
+	artifacts, err := marshalArtifacts(attempt.Artifacts)
+	if err != nil {
+		return err
+	}
+	phases, err := marshalPhases(attempt.Phases)
+	if err != nil {
+		return err
+	}
+	files, err := marshalFiles(attempt.Files)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE vc_execution_history
+		SET completed_at = ?, success = ?, exit_code = ?, summary = ?, output_sample = ?, error_sample = ?, artifacts = ?, phases = ?,
+		    commit_hash = ?, branch = ?, merge_commit = ?, files = ?
+		WHERE id = ?
+	`, attempt.CompletedAt, attempt.Success, attempt.ExitCode, attempt.Summary, attempt.OutputSample, attempt.ErrorSample, artifacts, phases,
+		attempt.CommitHash, attempt.Branch, attempt.MergeCommit, files, attempt.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update execution attempt: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("execution attempt %d not found", attempt.ID)
+	}
+
 	return nil
 }
 
 // GetExecutionHistory retrieves execution history for an issue
 func (s *VCStorage) GetExecutionHistory(ctx context.Context, issueID string) ([]*types.ExecutionAttempt, error) {
 	rows, err := s.db.QueryContext(ctx, `
-		SELECT id, issue_id, executor_instance_id, attempt_number, started_at, completed_at, success, exit_code, summary, output_sample, error_sample
+		SELECT id, issue_id, executor_instance_id, attempt_number, started_at, completed_at, success, exit_code, summary, output_sample, error_sample, artifacts, phases,
+		       commit_hash, branch, merge_commit, files
 		FROM vc_execution_history
 		WHERE issue_id = ?
 		ORDER BY started_at ASC
@@ -650,12 +806,30 @@ func (s *VCStorage) GetExecutionHistory(ctx context.Context, issueID string) ([]
 		var completedAt sql.NullTime
 		var success sql.NullBool
 		var exitCode sql.NullInt64
+		var artifacts, phases, commitHash, branch, mergeCommit, files sql.NullString
 
 		if err := rows.Scan(&attempt.ID, &attempt.IssueID, &attempt.ExecutorInstanceID,
 			&attempt.AttemptNumber, &attempt.StartedAt, &completedAt, &success, &exitCode,
-			&attempt.Summary, &attempt.OutputSample, &attempt.ErrorSample); err != nil {
+			&attempt.Summary, &attempt.OutputSample, &attempt.ErrorSample, &artifacts, &phases,
+			&commitHash, &branch, &mergeCommit, &files); err != nil {
 			return nil, fmt.Errorf("failed to scan execution attempt: %w", err)
 		}
+		attempt.CommitHash, attempt.Branch, attempt.MergeCommit = commitHash.String, branch.String, mergeCommit.String
+		if artifacts.Valid && artifacts.String != "" {
+			if err := json.Unmarshal([]byte(artifacts.String), &attempt.Artifacts); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal artifacts of attempt %d: %w", attempt.ID, err)
+			}
+		}
+		if phases.Valid && phases.String != "" {
+			if err := json.Unmarshal([]byte(phases.String), &attempt.Phases); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal phases of attempt %d: %w", attempt.ID, err)
+			}
+		}
+		if files.Valid && files.String != "" {
+			if err := json.Unmarshal([]byte(files.String), &attempt.Files); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal files of attempt %d: %w", attempt.ID, err)
+			}
+		}
 
 		if completedAt.Valid {
 			attempt.CompletedAt = &completedAt.Time
@@ -675,9 +849,298 @@ func (s *VCStorage) GetExecutionHistory(ctx context.Context, issueID string) ([]
 	return history, rows.Err()
 }
 
+// GetExecutionPhases returns the attempts started since the given time that
+// recorded phase timings, with only the columns needed to aggregate them
+func (s *VCStorage) GetExecutionPhases(ctx context.Context, since time.Time) ([]*types.ExecutionAttempt, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, issue_id, attempt_number, started_at, success, phases
+		FROM vc_execution_history
+		WHERE started_at >= ? AND phases IS NOT NULL
+		ORDER BY started_at ASC, id ASC
+	`, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query execution phases: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var attempts []*types.ExecutionAttempt
+	for rows.Next() {
+		var attempt types.ExecutionAttempt
+		var success sql.NullBool
+		var phases string
+		if err := rows.Scan(&attempt.ID, &attempt.IssueID, &attempt.AttemptNumber, &attempt.StartedAt, &success, &phases); err != nil {
+			return nil, fmt.Errorf("failed to scan execution phases: %w", err)
+		}
+		if err := json.Unmarshal([]byte(phases), &attempt.Phases); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal phases of attempt %d: %w", attempt.ID, err)
+		}
+		if success.Valid {
+			successVal := success.Bool
+			attempt.Success = &successVal
+		}
+		attempts = append(attempts, &attempt)
+	}
+	return attempts, rows.Err()
+}
+
+// CleanupExecutionHistory deletes the attempts that are both beyond the
+// newest keepPerIssue of their issue and older than retentionDays, in
+// transactions of at most batchSize rows. Attempts of blocked issues, of
+// unclosed issues labeled escalated and of issues whose watchdog escalation
+// issue is still open are kept. It returns the deleted attempts, so their
+// artifacts can be removed too.
+func (s *VCStorage) CleanupExecutionHistory(ctx context.Context, keepPerIssue, retentionDays, batchSize int) ([]*types.ExecutionAttempt, error) {
+	if keepPerIssue < 0 || retentionDays < 0 {
+		return nil, fmt.Errorf("history retention cannot be negative")
+	}
+	if batchSize < 1 {
+		return nil, fmt.Errorf("batch size must be at least 1")
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -retentionDays)
+	var deleted []*types.ExecutionAttempt
+	for {
+		select {
+		case <-ctx.Done():
+			return deleted, ctx.Err()
+		default:
+		}
+
+		batch, err := s.deleteHistoryBatch(ctx, keepPerIssue, cutoff, batchSize)
+		deleted = append(deleted, batch...)
+		if err != nil {
+			return deleted, err
+		}
+		// If we deleted fewer than batchSize, we're done
+		if len(batch) < batchSize {
+			return deleted, nil
+		}
+	}
+}
+
+// deleteHistoryBatch deletes up to batchSize of the oldest attempts
+// CleanupExecutionHistory may delete, in one transaction. Deleting old rows
+// doesn't change the rank of newer ones, so batches can be recomputed.
+func (s *VCStorage) deleteHistoryBatch(ctx context.Context, keepPerIssue int, cutoff time.Time, batchSize int) ([]*types.ExecutionAttempt, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, issue_id, executor_instance_id, attempt_number, started_at, artifacts
+		FROM (
+			SELECT h.*, ROW_NUMBER() OVER (PARTITION BY h.issue_id ORDER BY h.started_at DESC, h.id DESC) AS newest
+			FROM vc_execution_history h
+		)
+		WHERE newest > ? AND started_at < ?
+		AND issue_id NOT IN (
+			SELECT id FROM issues WHERE status = 'blocked'
+			UNION
+			SELECT l.issue_id FROM labels l JOIN issues i ON i.id = l.issue_id
+			WHERE l.label = 'escalated' AND i.status != 'closed'
+			UNION
+			SELECT w.issue_id FROM vc_watchdog_interventions w JOIN issues esc ON esc.id = w.escalation_issue_id
+			WHERE esc.status != 'closed' AND w.issue_id IS NOT NULL
+		)
+		ORDER BY started_at ASC
+		LIMIT ?
+	`, keepPerIssue, cutoff, batchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query expired execution history: %w", err)
+	}
+	var batch []*types.ExecutionAttempt
+	for rows.Next() {
+		var attempt types.ExecutionAttempt
+		var instanceID, artifacts sql.NullString
+		if err := rows.Scan(&attempt.ID, &attempt.IssueID, &instanceID, &attempt.AttemptNumber, &attempt.StartedAt, &artifacts); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("failed to scan execution attempt: %w", err)
+		}
+		attempt.ExecutorInstanceID = instanceID.String
+		if artifacts.Valid && artifacts.String != "" {
+			if err := json.Unmarshal([]byte(artifacts.String), &attempt.Artifacts); err != nil {
+				_ = rows.Close()
+				return nil, fmt.Errorf("failed to unmarshal artifacts of attempt %d: %w", attempt.ID, err)
+			}
+		}
+		batch = append(batch, &attempt)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating execution history: %w", err)
+	}
+
+	for _, attempt := range batch {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM vc_execution_history WHERE id = ?`, attempt.ID); err != nil {
+			return nil, fmt.Errorf("failed to delete execution attempt %d: %w", attempt.ID, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit: %w", err)
+	}
+	return batch, nil
+}
+
+// marshalArtifacts encodes artifact paths for vc_execution_history.artifacts
+// (NULL when there are none)
+func marshalArtifacts(paths []string) (interface{}, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(paths)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal artifacts: %w", err)
+	}
+	return string(data), nil
+}
+
+// marshalFiles encodes the paths an attempt changed for
+// vc_execution_history.files (NULL when there are none)
+func marshalFiles(files []string) (interface{}, error) {
+	if len(files) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(files)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal files: %w", err)
+	}
+	return string(data), nil
+}
+
+// marshalPhases encodes phase timings for vc_execution_history.phases (NULL
+// when there are none)
+func marshalPhases(phases []types.PhaseTiming) (interface{}, error) {
+	if len(phases) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(phases)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal phases: %w", err)
+	}
+	return string(data), nil
+}
+
+// ======================================================================
+// ASSESSMENTS
+// ======================================================================
+
+// SaveAssessment stores an assessment record, replacing the one for the same
+// issue and attempt (whose created_at is kept)
+func (s *VCStorage) SaveAssessment(ctx context.Context, record *types.AssessmentRecord) error {
+	if err := record.Validate(); err != nil {
+		return fmt.Errorf("invalid assessment: %w", err)
+	}
+	assessment, err := json.Marshal(record.Assessment)
+	if err != nil {
+		return fmt.Errorf("failed to marshal assessment: %w", err)
+	}
+	completed, err := json.Marshal(record.CompletedSteps)
+	if err != nil {
+		return fmt.Errorf("failed to marshal completed steps: %w", err)
+	}
+
+	now := time.Now()
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = now
+	}
+	record.UpdatedAt = now
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO vc_assessments (issue_id, attempt, assessment, completed_steps, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(issue_id, attempt) DO UPDATE SET
+			assessment = excluded.assessment,
+			completed_steps = excluded.completed_steps,
+			updated_at = excluded.updated_at
+	`, record.IssueID, record.Attempt, string(assessment), string(completed), record.CreatedAt, record.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save assessment: %w", err)
+	}
+	return nil
+}
+
+// GetLatestAssessment returns the issue's assessment with the highest
+// attempt number, or nil if there is none
+func (s *VCStorage) GetLatestAssessment(ctx context.Context, issueID string) (*types.AssessmentRecord, error) {
+	record := &types.AssessmentRecord{IssueID: issueID}
+	var assessment string
+	var completed sql.NullString
+	err := s.db.QueryRowContext(ctx, `
+		SELECT attempt, assessment, completed_steps, created_at, updated_at
+		FROM vc_assessments
+		WHERE issue_id = ?
+		ORDER BY attempt DESC
+		LIMIT 1
+	`, issueID).Scan(&record.Attempt, &assessment, &completed, &record.CreatedAt, &record.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query assessment: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(assessment), &record.Assessment); err != nil {
+		return nil, fmt.Errorf("failed to parse assessment: %w", err)
+	}
+	if completed.Valid && completed.String != "" {
+		if err := json.Unmarshal([]byte(completed.String), &record.CompletedSteps); err != nil {
+			return nil, fmt.Errorf("failed to parse completed steps: %w", err)
+		}
+	}
+	return record, nil
+}
+
 // ======================================================================
 // CONFIG (delegate to Beads)
 // ======================================================================
 
-// GetConfig, SetConfig delegate to Beads
+// GetConfig delegates to Beads
 // (Already available via embedded beads.Storage)
+
+// SetConfig stores a config value in Beads, validating known settings.
+// Changing a setting the executor reads is recorded as a SYSTEM
+// config_changed event.
+func (s *VCStorage) SetConfig(ctx context.Context, key, value string) error {
+	if err := config.CheckSetting(key, value); err != nil {
+		return err
+	}
+	old, err := s.Storage.GetConfig(ctx, key)
+	if err != nil {
+		return fmt.Errorf("failed to read config %s: %w", key, err)
+	}
+	if err := s.Storage.SetConfig(ctx, key, value); err != nil {
+		return err
+	}
+
+	if old != value && config.IsExecutorSetting(key) {
+		data := events.ConfigChangedData{Key: key, OldValue: old, NewValue: value}
+		event, err := events.NewConfigChangedEvent("SYSTEM", "", "", events.SeverityInfo,
+			fmt.Sprintf("Config %s changed from %q to %q", key, old, value), data)
+		if err == nil {
+			err = s.StoreAgentEvent(ctx, event)
+		}
+		if err != nil {
+			// Best-effort: the setting itself is stored
+			fmt.Fprintf(os.Stderr, "Warning: failed to store config_changed event for %s: %v\n", key, err)
+		}
+	}
+	return nil
+}
+
+// ListConfig returns the config values whose key starts with prefix
+func (s *VCStorage) ListConfig(ctx context.Context, prefix string) (map[string]string, error) {
+	all, err := s.Storage.GetAllConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list config: %w", err)
+	}
+	result := make(map[string]string)
+	for key, value := range all {
+		if strings.HasPrefix(key, prefix) {
+			result[key] = value
+		}
+	}
+	return result, nil
+}
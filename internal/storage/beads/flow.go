@@ -0,0 +1,154 @@
+package beads
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// FLOW METRICS (lead time, cycle time, throughput)
+// ======================================================================
+
+// flowMetrics computes the flow metrics for the weeks trailing now. Only the
+// issues closed inside the window are loaded, so the result set stays bounded
+// by recent throughput rather than by the size of the tracker; the
+// aggregation happens here. Archived issues are left out.
+func (s *VCStorage) flowMetrics(ctx context.Context, now time.Time, weeks int) (*types.FlowMetrics, error) {
+	if weeks < 1 {
+		return nil, fmt.Errorf("weeks must be at least 1, got %d", weeks)
+	}
+	now = now.UTC()
+	since := weekStart(now).AddDate(0, 0, -7*(weeks-1))
+
+	flow := &types.FlowMetrics{
+		Since:      since,
+		Weeks:      weeks,
+		Throughput: make([]types.WeeklyThroughput, weeks),
+	}
+	for i := range flow.Throughput {
+		flow.Throughput[i].WeekStart = since.AddDate(0, 0, 7*i)
+	}
+
+	// julianday() normalizes the stored timezone offsets before comparing
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, created_at, closed_at
+		FROM issues
+		WHERE status = 'closed'
+		  AND closed_at IS NOT NULL
+		  AND julianday(closed_at) >= julianday(?)
+		  AND id NOT IN (SELECT issue_id FROM vc_archived_issues)
+		  AND id != ?
+	`, since.Format(time.RFC3339), types.SystemIssueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query closed issues: %w", err)
+	}
+	defer rows.Close()
+
+	closedAt := make(map[string]time.Time)
+	var leadTimes []float64
+	for rows.Next() {
+		var id string
+		var created, closed time.Time
+		if err := rows.Scan(&id, &created, &closed); err != nil {
+			return nil, fmt.Errorf("failed to scan closed issue: %w", err)
+		}
+		closed = closed.UTC()
+		closedAt[id] = closed
+		leadTimes = append(leadTimes, closed.Sub(created).Hours())
+
+		week := int(closed.Sub(since).Hours() / (24 * 7))
+		if week >= 0 && week < weeks {
+			flow.Throughput[week].Closed++
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating closed issues: %w", err)
+	}
+	flow.LeadTime = durationStats(leadTimes)
+
+	// The first execution attempt marks the first claim
+	firstStarted := make(map[string]time.Time)
+	rows, err = s.db.QueryContext(ctx, `
+		SELECT h.issue_id, h.started_at
+		FROM vc_execution_history h
+		JOIN issues i ON i.id = h.issue_id
+		WHERE i.status = 'closed'
+		  AND i.closed_at IS NOT NULL
+		  AND julianday(i.closed_at) >= julianday(?)
+	`, since.Format(time.RFC3339))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query execution history: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id string
+		var started time.Time
+		if err := rows.Scan(&id, &started); err != nil {
+			return nil, fmt.Errorf("failed to scan execution attempt: %w", err)
+		}
+		if first, ok := firstStarted[id]; !ok || started.Before(first) {
+			firstStarted[id] = started
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating execution history: %w", err)
+	}
+
+	var cycleTimes []float64
+	for id, started := range firstStarted {
+		if closed, ok := closedAt[id]; ok {
+			cycleTimes = append(cycleTimes, closed.Sub(started).Hours())
+		}
+	}
+	flow.CycleTime = durationStats(cycleTimes)
+
+	err = s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM issues
+		WHERE status = 'in_progress'
+		  AND id NOT IN (SELECT issue_id FROM vc_archived_issues)
+		  AND id != ?
+	`, types.SystemIssueID).Scan(&flow.WIP)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count work in progress: %w", err)
+	}
+	return flow, nil
+}
+
+// weekStart returns Monday 00:00 UTC of the week containing t
+func weekStart(t time.Time) time.Time {
+	t = t.UTC()
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-daysSinceMonday, 0, 0, 0, 0, time.UTC)
+}
+
+// durationStats summarizes hours with nearest-rank percentiles
+func durationStats(hours []float64) types.DurationStats {
+	if len(hours) == 0 {
+		return types.DurationStats{}
+	}
+	sorted := append([]float64(nil), hours...)
+	sort.Float64s(sorted)
+
+	var sum float64
+	for _, h := range sorted {
+		sum += h
+	}
+	percentile := func(p float64) float64 {
+		rank := int(math.Ceil(p * float64(len(sorted))))
+		if rank < 1 {
+			rank = 1
+		}
+		return sorted[rank-1]
+	}
+	return types.DurationStats{
+		Count:   len(sorted),
+		Average: sum / float64(len(sorted)),
+		P50:     percentile(0.5),
+		P90:     percentile(0.9),
+	}
+}
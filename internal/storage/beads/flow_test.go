@@ -0,0 +1,144 @@
+package beads
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestFlowMetrics(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newCreateTestStore(t)
+
+	// Wednesday; with 3 weeks the window starts Monday 2026-09-28
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	day := func(d, h int) time.Time { return time.Date(2026, 9, d, h, 0, 0, 0, time.UTC) }
+
+	closed := []struct {
+		created, firstRun, closed time.Time
+	}{
+		{day(20, 0), day(29, 0), day(29, 10)},                                   // week 1, lead 9d10h, cycle 10h
+		{day(28, 0), day(28, 2), day(28, 6)},                                    // week 1, lead 6h, cycle 4h
+		{day(30, 0), time.Time{}, day(30, 2)},                                   // week 1, never executed
+		{day(30, 0), day(30, 1), time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)}, // week 3, Monday 00:00
+		{day(1, 0), day(1, 0), day(27, 23)},                                     // Sunday before the window
+	}
+	var ids []string
+	for i, c := range closed {
+		issue := &types.Issue{Title: "closed", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue %d failed: %v", i, err)
+		}
+		ids = append(ids, issue.ID)
+		if _, err := store.db.ExecContext(ctx, `UPDATE issues SET status = 'closed', created_at = ?, closed_at = ? WHERE id = ?`,
+			c.created, c.closed, issue.ID); err != nil {
+			t.Fatalf("Failed to close issue %d: %v", i, err)
+		}
+		if c.firstRun.IsZero() {
+			continue
+		}
+		// A later retry must not move the first claim
+		for attempt, started := range []time.Time{c.firstRun, c.firstRun.Add(time.Hour)} {
+			if _, err := store.db.ExecContext(ctx, `INSERT INTO vc_execution_history (issue_id, attempt_number, started_at) VALUES (?, ?, ?)`,
+				issue.ID, attempt+1, started); err != nil {
+				t.Fatalf("Failed to record attempt: %v", err)
+			}
+		}
+	}
+
+	wip := &types.Issue{Title: "wip", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, wip, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+	if err := store.UpdateIssue(ctx, wip.ID, map[string]interface{}{"status": types.StatusInProgress}, "test"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+
+	flow, err := store.flowMetrics(ctx, now, 3)
+	if err != nil {
+		t.Fatalf("flowMetrics failed: %v", err)
+	}
+	if want := day(28, 0); !flow.Since.Equal(want) {
+		t.Errorf("Expected window to start %v, got %v", want, flow.Since)
+	}
+	if flow.WIP != 1 {
+		t.Errorf("Expected WIP 1, got %d", flow.WIP)
+	}
+
+	var throughput []int
+	for _, week := range flow.Throughput {
+		throughput = append(throughput, week.Closed)
+	}
+	if len(throughput) != 3 || throughput[0] != 3 || throughput[1] != 0 || throughput[2] != 1 {
+		t.Errorf("Expected throughput [3 0 1], got %v", throughput)
+	}
+	if got := flow.Throughput[2].WeekStart; !got.Equal(time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected last week start: %v", got)
+	}
+
+	// Lead times: 2h, 6h, 226h, 288h
+	if flow.LeadTime.Count != 4 || flow.LeadTime.Average != 130.5 || flow.LeadTime.P50 != 6 || flow.LeadTime.P90 != 288 {
+		t.Errorf("Unexpected lead time: %+v", flow.LeadTime)
+	}
+	// Cycle times: 4h, 10h, 287h
+	if flow.CycleTime.Count != 3 || flow.CycleTime.P50 != 10 || flow.CycleTime.P90 != 287 {
+		t.Errorf("Unexpected cycle time: %+v", flow.CycleTime)
+	}
+
+	// Archived issues drop out
+	if err := store.ArchiveIssue(ctx, ids[3], "test"); err != nil {
+		t.Fatalf("ArchiveIssue failed: %v", err)
+	}
+	flow, err = store.flowMetrics(ctx, now, 3)
+	if err != nil {
+		t.Fatalf("flowMetrics failed: %v", err)
+	}
+	if flow.Throughput[2].Closed != 0 || flow.LeadTime.Count != 3 || flow.CycleTime.Count != 2 {
+		t.Errorf("Archived issue still counted: %+v", flow)
+	}
+
+	if _, err := store.flowMetrics(ctx, now, 0); err == nil {
+		t.Error("Expected an error for zero weeks")
+	}
+
+	stats, err := store.GetStatistics(ctx)
+	if err != nil {
+		t.Fatalf("GetStatistics failed: %v", err)
+	}
+	if stats.Flow == nil || stats.Flow.Weeks != types.FlowWeeks || len(stats.Flow.Throughput) != types.FlowWeeks {
+		t.Errorf("Expected %d weeks of flow metrics, got %+v", types.FlowWeeks, stats.Flow)
+	}
+}
+
+func TestWeekStart(t *testing.T) {
+	monday := time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		t    time.Time
+		want time.Time
+	}{
+		{"monday midnight", monday, monday},
+		{"sunday night", time.Date(2026, 10, 18, 23, 59, 59, 0, time.UTC), monday},
+		{"next monday", time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC), monday.AddDate(0, 0, 7)},
+		// Monday 01:00 in UTC+2 is still Sunday in UTC
+		{"other timezone", time.Date(2026, 10, 19, 1, 0, 0, 0, time.FixedZone("CEST", 2*3600)), monday},
+	}
+	for _, tt := range tests {
+		if got := weekStart(tt.t); !got.Equal(tt.want) {
+			t.Errorf("%s: weekStart(%v) = %v, want %v", tt.name, tt.t, got, tt.want)
+		}
+	}
+}
+
+func TestDurationStats(t *testing.T) {
+	if got := durationStats(nil); got != (types.DurationStats{}) {
+		t.Errorf("Expected zero stats for no durations, got %+v", got)
+	}
+	got := durationStats([]float64{10, 1, 9, 2, 8, 3, 7, 4, 6, 5})
+	want := types.DurationStats{Count: 10, Average: 5.5, P50: 5, P90: 9}
+	if got != want {
+		t.Errorf("durationStats = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,125 @@
+package beads
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// GITHUB LINKS AND SYNC CURSORS (VC extension tables)
+// ======================================================================
+
+// SaveGitHubLink creates or replaces the link of link.IssueID
+func (s *VCStorage) SaveGitHubLink(ctx context.Context, link *types.GitHubLink) error {
+	if err := link.Validate(); err != nil {
+		return fmt.Errorf("invalid GitHub link: %w", err)
+	}
+	syncedAt := link.SyncedAt
+	if syncedAt.IsZero() {
+		syncedAt = time.Now()
+	}
+	movedTo := sql.NullString{String: link.MovedTo, Valid: link.MovedTo != ""}
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO vc_github_links (issue_id, repo, number, state, synced_title, synced_closed, synced_at, moved_to)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(issue_id) DO UPDATE SET
+			repo = excluded.repo,
+			number = excluded.number,
+			state = excluded.state,
+			synced_title = excluded.synced_title,
+			synced_closed = excluded.synced_closed,
+			synced_at = excluded.synced_at,
+			moved_to = excluded.moved_to
+	`, link.IssueID, link.Repo, link.Number, string(link.State), link.SyncedTitle, link.SyncedClosed, syncedAt, movedTo)
+	if err != nil {
+		return fmt.Errorf("failed to save GitHub link of %s: %w", link.IssueID, err)
+	}
+	link.SyncedAt = syncedAt
+	return nil
+}
+
+// GetGitHubLink returns the issue's link, or (nil, nil) if it has none
+func (s *VCStorage) GetGitHubLink(ctx context.Context, issueID string) (*types.GitHubLink, error) {
+	row := s.db.QueryRowContext(ctx, githubLinkSelect+` WHERE issue_id = ?`, issueID)
+	link, err := scanGitHubLink(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get GitHub link of %s: %w", issueID, err)
+	}
+	return link, nil
+}
+
+// GetGitHubLinks returns the repository's links by number
+func (s *VCStorage) GetGitHubLinks(ctx context.Context, repo string) ([]*types.GitHubLink, error) {
+	rows, err := s.db.QueryContext(ctx, githubLinkSelect+` WHERE repo = ? ORDER BY number`, repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query GitHub links: %w", err)
+	}
+	defer rows.Close()
+
+	var links []*types.GitHubLink
+	for rows.Next() {
+		link, err := scanGitHubLink(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan GitHub link: %w", err)
+		}
+		links = append(links, link)
+	}
+	return links, rows.Err()
+}
+
+// GetGitHubSyncCursor returns how far the repository has been synced, or the
+// zero time if it never was
+func (s *VCStorage) GetGitHubSyncCursor(ctx context.Context, repo string) (time.Time, error) {
+	var cursor time.Time
+	err := s.db.QueryRowContext(ctx, `SELECT cursor FROM vc_github_sync WHERE repo = ?`, repo).Scan(&cursor)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get sync cursor of %s: %w", repo, err)
+	}
+	return cursor, nil
+}
+
+// SetGitHubSyncCursor records how far the repository has been synced
+func (s *VCStorage) SetGitHubSyncCursor(ctx context.Context, repo string, cursor time.Time) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO vc_github_sync (repo, cursor, updated_at) VALUES (?, ?, ?)
+		ON CONFLICT(repo) DO UPDATE SET cursor = excluded.cursor, updated_at = excluded.updated_at
+	`, repo, cursor, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to set sync cursor of %s: %w", repo, err)
+	}
+	return nil
+}
+
+const githubLinkSelect = `
+	SELECT issue_id, repo, number, state, synced_title, synced_closed, synced_at, moved_to
+	FROM vc_github_links`
+
+// rowScanner is a *sql.Row or *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanGitHubLink reads a row selected with githubLinkSelect
+func scanGitHubLink(row rowScanner) (*types.GitHubLink, error) {
+	var link types.GitHubLink
+	var state string
+	var movedTo sql.NullString
+	if err := row.Scan(&link.IssueID, &link.Repo, &link.Number, &state, &link.SyncedTitle,
+		&link.SyncedClosed, &link.SyncedAt, &movedTo); err != nil {
+		return nil, err
+	}
+	link.State = types.GitHubLinkState(state)
+	link.MovedTo = movedTo.String
+	return &link, nil
+}
@@ -3,8 +3,10 @@ package beads
 import (
 	"context"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -502,6 +504,203 @@ func TestGetAgentEvents(t *testing.T) {
 	})
 }
 
+// TestAgentEventsExecutorAndAgentFilter verifies filtering by executor and agent ID,
+// which matters once several executors share a database
+func TestAgentEventsExecutorAndAgentFilter(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	store, err := NewVCStorage(ctx, dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create VC storage: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	issue := &types.Issue{Title: "Shared issue", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("Failed to create issue: %v", err)
+	}
+	otherIssue := &types.Issue{Title: "Other issue", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, otherIssue, "test"); err != nil {
+		t.Fatalf("Failed to create issue: %v", err)
+	}
+
+	now := time.Now()
+	testEvents := []events.AgentEvent{
+		{Timestamp: now.Add(-4 * time.Minute), IssueID: issue.ID, ExecutorID: "exec-a", AgentID: "agent-1", Type: events.EventTypeProgress, Severity: events.SeverityInfo, Message: "a1"},
+		{Timestamp: now.Add(-3 * time.Minute), IssueID: issue.ID, ExecutorID: "exec-b", AgentID: "agent-2", Type: events.EventTypeProgress, Severity: events.SeverityInfo, Message: "b2"},
+		{Timestamp: now.Add(-2 * time.Minute), IssueID: otherIssue.ID, ExecutorID: "exec-a", AgentID: "agent-3", Type: events.EventTypeProgress, Severity: events.SeverityInfo, Message: "a3"},
+		{Timestamp: now.Add(-1 * time.Minute), IssueID: issue.ID, ExecutorID: "exec-a", Type: events.EventTypeIssueClaimed, Severity: events.SeverityInfo, Message: "a-executor"},
+	}
+	for i := range testEvents {
+		if err := store.StoreAgentEvent(ctx, &testEvents[i]); err != nil {
+			t.Fatalf("Failed to store event: %v", err)
+		}
+	}
+
+	messages := func(t *testing.T, filter events.EventFilter) []string {
+		t.Helper()
+		results, err := store.GetAgentEvents(ctx, filter)
+		if err != nil {
+			t.Fatalf("GetAgentEvents failed: %v", err)
+		}
+		var got []string
+		for _, e := range results {
+			got = append(got, e.Message)
+		}
+		return got
+	}
+
+	tests := []struct {
+		name   string
+		filter events.EventFilter
+		want   []string
+	}{
+		{"executor only", events.EventFilter{ExecutorID: "exec-a"}, []string{"a-executor", "a3", "a1"}},
+		{"issue and executor", events.EventFilter{IssueID: issue.ID, ExecutorID: "exec-a"}, []string{"a-executor", "a1"}},
+		{"agent only", events.EventFilter{AgentID: "agent-2"}, []string{"b2"}},
+		{"executor and agent", events.EventFilter{ExecutorID: "exec-a", AgentID: "agent-2"}, nil},
+		{"executor and type", events.EventFilter{ExecutorID: "exec-a", Type: events.EventTypeProgress}, []string{"a3", "a1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := messages(t, tt.filter)
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("Got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Guard against the new filters degrading into full table scans
+	t.Run("query plan uses indexes", func(t *testing.T) {
+		plans := map[string]events.EventFilter{
+			"idx_vc_agent_events_executor": {ExecutorID: "exec-a"},
+			"idx_vc_agent_events_agent":    {AgentID: "agent-1"},
+		}
+		for index, filter := range plans {
+			query, args := store.buildAgentEventsQuery(filter)
+			rows, err := store.db.QueryContext(ctx, "EXPLAIN QUERY PLAN "+query, args...)
+			if err != nil {
+				t.Fatalf("EXPLAIN QUERY PLAN failed: %v", err)
+			}
+			var plan []string
+			for rows.Next() {
+				var id, parent, notUsed int
+				var detail string
+				if err := rows.Scan(&id, &parent, &notUsed, &detail); err != nil {
+					t.Fatalf("Failed to scan query plan: %v", err)
+				}
+				plan = append(plan, detail)
+			}
+			_ = rows.Close()
+
+			if !strings.Contains(strings.Join(plan, "\n"), index) {
+				t.Errorf("Expected query plan for %+v to use %s, got %v", filter, index, plan)
+			}
+		}
+	})
+}
+
+// TestAgentEventsCursorPagination verifies AfterID/BeforeID cursors page in
+// stable id order, even when events share a timestamp
+func TestAgentEventsCursorPagination(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewVCStorage(ctx, filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create VC storage: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		event := &events.AgentEvent{Timestamp: now, Type: events.EventTypeProgress, Severity: events.SeverityInfo, Message: fmt.Sprintf("event %d", i)}
+		if err := store.StoreAgentEvent(ctx, event); err != nil {
+			t.Fatalf("Failed to store event: %v", err)
+		}
+	}
+
+	messages := func(list []*events.AgentEvent) []string {
+		var got []string
+		for _, e := range list {
+			got = append(got, e.Message)
+		}
+		return got
+	}
+
+	// Page back from the newest event, two at a time
+	var pages [][]string
+	cursor := int64(math.MaxInt64)
+	for {
+		page, err := store.GetAgentEvents(ctx, events.EventFilter{BeforeID: cursor, Limit: 2})
+		if err != nil {
+			t.Fatalf("GetAgentEvents failed: %v", err)
+		}
+		if len(page) == 0 {
+			break
+		}
+		pages = append(pages, messages(page))
+		cursor = page[len(page)-1].Cursor()
+	}
+	want := "[[event 4 event 3] [event 2 event 1] [event 0]]"
+	if fmt.Sprint(pages) != want {
+		t.Errorf("Pages = %v, want %s", pages, want)
+	}
+
+	// Read forward from a cursor, oldest first
+	all, err := store.GetAgentEvents(ctx, events.EventFilter{BeforeID: math.MaxInt64})
+	if err != nil {
+		t.Fatalf("GetAgentEvents failed: %v", err)
+	}
+	after, err := store.GetAgentEvents(ctx, events.EventFilter{AfterID: all[2].Cursor(), Limit: 10})
+	if err != nil {
+		t.Fatalf("GetAgentEvents failed: %v", err)
+	}
+	if got := fmt.Sprint(messages(after)); got != "[event 3 event 4]" {
+		t.Errorf("Events after cursor = %s, want [event 3 event 4]", got)
+	}
+}
+
+// TestStreamAgentEvents verifies streaming yields events oldest first and stops on callback errors
+func TestStreamAgentEvents(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewVCStorage(ctx, filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create VC storage: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	now := time.Now()
+	for i := 0; i < 4; i++ {
+		event := &events.AgentEvent{Timestamp: now.Add(time.Duration(-i) * time.Minute), Type: events.EventTypeProgress, Severity: events.SeverityInfo, Message: fmt.Sprintf("event %d", i)}
+		if err := store.StoreAgentEvent(ctx, event); err != nil {
+			t.Fatalf("Failed to store event: %v", err)
+		}
+	}
+
+	// Storage order, not timestamp order
+	var got []string
+	err = store.StreamAgentEvents(ctx, events.EventFilter{Limit: 3}, func(e *events.AgentEvent) error {
+		got = append(got, e.Message)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamAgentEvents failed: %v", err)
+	}
+	if fmt.Sprint(got) != "[event 0 event 1 event 2]" {
+		t.Errorf("Streamed %v, want [event 0 event 1 event 2]", got)
+	}
+
+	stop := fmt.Errorf("stop")
+	calls := 0
+	err = store.StreamAgentEvents(ctx, events.EventFilter{}, func(e *events.AgentEvent) error {
+		calls++
+		return stop
+	})
+	if err != stop || calls != 1 {
+		t.Errorf("Expected the callback error after 1 call, got %v after %d", err, calls)
+	}
+}
+
 // TestAgentEventDataPersistence verifies that Data field is properly stored and retrieved
 func TestAgentEventDataPersistence(t *testing.T) {
 	ctx := context.Background()
@@ -1639,7 +1838,7 @@ func TestGetReadyBlockersFiltersDependencyTypes(t *testing.T) {
 
 	t.Run("blocker with discovered-from dependency is ready", func(t *testing.T) {
 		// GetReadyBlockers should return the blocker because discovered-from doesn't block
-		blockers, err := store.GetReadyBlockers(ctx, 10)
+		blockers, err := store.GetReadyBlockers(ctx, types.WorkFilter{Limit: 10})
 		if err != nil {
 			t.Fatalf("GetReadyBlockers failed: %v", err)
 		}
@@ -1679,7 +1878,7 @@ func TestGetReadyBlockersFiltersDependencyTypes(t *testing.T) {
 
 	t.Run("blocker with open blocks dependency is not ready", func(t *testing.T) {
 		// Now the blocker should NOT be ready (has open blocking dependency)
-		blockers, err := store.GetReadyBlockers(ctx, 10)
+		blockers, err := store.GetReadyBlockers(ctx, types.WorkFilter{Limit: 10})
 		if err != nil {
 			t.Fatalf("GetReadyBlockers failed: %v", err)
 		}
@@ -1699,7 +1898,7 @@ func TestGetReadyBlockersFiltersDependencyTypes(t *testing.T) {
 
 	t.Run("blocker becomes ready when blocking dependency closes", func(t *testing.T) {
 		// Now the blocker should be ready again (blocking dependency closed)
-		blockers, err := store.GetReadyBlockers(ctx, 10)
+		blockers, err := store.GetReadyBlockers(ctx, types.WorkFilter{Limit: 10})
 		if err != nil {
 			t.Fatalf("GetReadyBlockers failed: %v", err)
 		}
@@ -1745,7 +1944,7 @@ func TestGetReadyBlockersFiltersDependencyTypes(t *testing.T) {
 		}
 
 		// Should still be ready (parent-child doesn't block)
-		blockers, err := store.GetReadyBlockers(ctx, 10)
+		blockers, err := store.GetReadyBlockers(ctx, types.WorkFilter{Limit: 10})
 		if err != nil {
 			t.Fatalf("GetReadyBlockers failed: %v", err)
 		}
@@ -1867,7 +2066,7 @@ func TestEpicsExcludedFromReadyWork(t *testing.T) {
 		}
 
 		// Get ready blockers
-		blockers, err := store.GetReadyBlockers(ctx, 10)
+		blockers, err := store.GetReadyBlockers(ctx, types.WorkFilter{Limit: 10})
 		if err != nil {
 			t.Fatalf("GetReadyBlockers failed: %v", err)
 		}
@@ -3735,3 +3934,61 @@ func TestMissionLifecycleEvents(t *testing.T) {
 		t.Logf("✓ Mixed base issue and mission field updates tracked correctly")
 	})
 }
+
+// TestSearchIssuesLabelFilter verifies label and limit filters reach Beads
+func TestSearchIssuesLabelFilter(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	store, err := NewVCStorage(ctx, dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create VC storage: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		issue := &types.Issue{
+			Title:     fmt.Sprintf("Issue %d", i),
+			Status:    types.StatusOpen,
+			Priority:  2,
+			IssueType: types.TypeTask,
+		}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		ids = append(ids, issue.ID)
+	}
+	for _, id := range ids[:2] {
+		if err := store.AddLabel(ctx, id, "shared", "test"); err != nil {
+			t.Fatalf("AddLabel failed: %v", err)
+		}
+	}
+	if err := store.AddLabel(ctx, ids[1], "only-one", "test"); err != nil {
+		t.Fatalf("AddLabel failed: %v", err)
+	}
+
+	shared, err := store.SearchIssues(ctx, "", types.IssueFilter{Labels: []string{"shared"}})
+	if err != nil {
+		t.Fatalf("SearchIssues failed: %v", err)
+	}
+	if len(shared) != 2 {
+		t.Errorf("Expected 2 issues labeled shared, got %d", len(shared))
+	}
+
+	both, err := store.SearchIssues(ctx, "", types.IssueFilter{Labels: []string{"shared", "only-one"}})
+	if err != nil {
+		t.Fatalf("SearchIssues failed: %v", err)
+	}
+	if len(both) != 1 || both[0].ID != ids[1] {
+		t.Errorf("Expected only %s to have both labels, got %d issues", ids[1], len(both))
+	}
+
+	limited, err := store.SearchIssues(ctx, "", types.IssueFilter{Limit: 1})
+	if err != nil {
+		t.Fatalf("SearchIssues failed: %v", err)
+	}
+	if len(limited) != 1 {
+		t.Errorf("Expected limit 1 to return 1 issue, got %d", len(limited))
+	}
+}
@@ -0,0 +1,41 @@
+package beads
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// ======================================================================
+// IN-MEMORY DATABASES
+// ======================================================================
+//
+// Beads turns ":memory:" into "file::memory:?cache=shared": one database
+// shared by every connection in the process. That keeps Beads' pool and the
+// VC extension tables on the same data, but it also means two stores opened
+// on ":memory:" (two tests, say) see each other's issues, and the database
+// vanishes whenever the pool happens to close its last connection.
+//
+// NewVCStorage therefore gives each ":memory:" store its own named
+// shared-cache database and pins one connection to it for the store's
+// lifetime. An explicit shared-cache URI (file::memory:?cache=shared or
+// file:name?mode=memory&cache=shared) is left alone, so callers that want
+// several stores on one in-memory database can still ask for it.
+
+// memoryDatabaseSeq numbers the private in-memory databases
+var memoryDatabaseSeq atomic.Int64
+
+// isInMemoryPath reports whether a database path or DSN names an in-memory
+// database
+func isInMemoryPath(path string) bool {
+	return strings.Contains(path, ":memory:") || strings.Contains(path, "mode=memory")
+}
+
+// inMemoryDSN returns the DSN to open for dbPath: a fresh private
+// shared-cache database for ":memory:", dbPath unchanged otherwise
+func inMemoryDSN(dbPath string) string {
+	if dbPath != ":memory:" {
+		return dbPath
+	}
+	return fmt.Sprintf("file:vc-memory-%d?mode=memory&cache=shared", memoryDatabaseSeq.Add(1))
+}
@@ -0,0 +1,79 @@
+package beads
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// ISSUE METADATA (VC extension table)
+// ======================================================================
+
+// SetIssueMeta stores value under key on the issue, replacing any previous
+// value
+func (s *VCStorage) SetIssueMeta(ctx context.Context, issueID, key string, value json.RawMessage) error {
+	if err := types.ValidateMetaKey(key); err != nil {
+		return err
+	}
+	value, err := types.NormalizeMetaValue(value)
+	if err != nil {
+		return fmt.Errorf("invalid value of %s: %w", key, err)
+	}
+	var exists int
+	err = s.db.QueryRowContext(ctx, `SELECT 1 FROM issues WHERE id = ?`, issueID).Scan(&exists)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("issue %s not found", issueID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get issue %s: %w", issueID, err)
+	}
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO vc_issue_metadata (issue_id, key, value, updated_at)
+		VALUES (?, ?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT (issue_id, key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at
+	`, issueID, key, string(value))
+	if err != nil {
+		return fmt.Errorf("failed to set metadata %s of %s: %w", key, issueID, err)
+	}
+	return nil
+}
+
+// GetIssueMeta returns the issue's metadata by key
+func (s *VCStorage) GetIssueMeta(ctx context.Context, issueID string) (map[string]json.RawMessage, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT key, value FROM vc_issue_metadata WHERE issue_id = ?`, issueID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metadata of %s: %w", issueID, err)
+	}
+	defer rows.Close()
+
+	meta := make(map[string]json.RawMessage)
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return nil, fmt.Errorf("failed to scan metadata: %w", err)
+		}
+		meta[key] = json.RawMessage(value)
+	}
+	return meta, rows.Err()
+}
+
+// DeleteIssueMeta removes key from the issue's metadata
+func (s *VCStorage) DeleteIssueMeta(ctx context.Context, issueID, key string) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM vc_issue_metadata WHERE issue_id = ? AND key = ?`, issueID, key)
+	if err != nil {
+		return fmt.Errorf("failed to delete metadata %s of %s: %w", key, issueID, err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to delete metadata %s of %s: %w", key, issueID, err)
+	}
+	if n == 0 {
+		return fmt.Errorf("issue %s has no metadata %s", issueID, key)
+	}
+	return nil
+}
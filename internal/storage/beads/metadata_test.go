@@ -0,0 +1,26 @@
+package beads
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+// TestIssueMetaCascade verifies an issue's metadata goes with the issue
+func TestIssueMetaCascade(t *testing.T) {
+	ctx := context.Background()
+	store, issue := newCreateTestStore(t)
+	if err := store.SetIssueMeta(ctx, issue.ID, "jira.key", json.RawMessage(`"PROJ-1"`)); err != nil {
+		t.Fatalf("SetIssueMeta failed: %v", err)
+	}
+	if _, err := store.db.ExecContext(ctx, `DELETE FROM issues WHERE id = ?`, issue.ID); err != nil {
+		t.Fatalf("Failed to delete issue: %v", err)
+	}
+	var rows int
+	if err := store.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM vc_issue_metadata`).Scan(&rows); err != nil {
+		t.Fatal(err)
+	}
+	if rows != 0 {
+		t.Errorf("Expected the metadata deleted with the issue, %d rows left", rows)
+	}
+}
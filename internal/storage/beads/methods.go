@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
@@ -42,6 +43,22 @@ func (s *VCStorage) GetIssue(ctx context.Context, id string) (*types.Issue, erro
 		return nil, fmt.Errorf("failed to query mission state: %w", err)
 	}
 
+	if vcIssue != nil {
+		if vcIssue.Archived, err = s.isArchived(ctx, id); err != nil {
+			return nil, err
+		}
+		projects, err := s.issueProjects(ctx, []string{id})
+		if err != nil {
+			return nil, err
+		}
+		vcIssue.Project = projects[id]
+		if vcIssue.Status == types.StatusClosed {
+			if vcIssue.Resolution, err = s.resolution(ctx, id); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	return vcIssue, nil
 }
 
@@ -61,12 +78,13 @@ func (s *VCStorage) GetMission(ctx context.Context, id string) (*types.Mission,
 	mission.Issue = *issue
 
 	var sandboxPath, branchName, gatesStatus, goal, context, approvedBy sql.NullString
-	var approvedAt sql.NullTime
+	var approvedAt, lastGatesRun sql.NullTime
 	var iterationCount sql.NullInt64
 
 	err = s.db.QueryRowContext(ctx, `
 		SELECT sandbox_path, branch_name, iteration_count, gates_status,
-		       goal, context, phase_count, current_phase, approval_required, approved_at, approved_by
+		       goal, context, phase_count, current_phase, approval_required, approved_at, approved_by,
+		       last_gates_run
 		FROM vc_mission_state
 		WHERE issue_id = ? AND subtype IN ('mission', 'phase')
 	`, id).Scan(
@@ -81,6 +99,7 @@ func (s *VCStorage) GetMission(ctx context.Context, id string) (*types.Mission,
 		&mission.ApprovalRequired,
 		&approvedAt,
 		&approvedBy,
+		&lastGatesRun,
 	)
 
 	if err != nil {
@@ -115,12 +134,24 @@ func (s *VCStorage) GetMission(ctx context.Context, id string) (*types.Mission,
 	if approvedBy.Valid {
 		mission.ApprovedBy = approvedBy.String
 	}
+	if lastGatesRun.Valid {
+		mission.LastGatesRun = &lastGatesRun.Time
+	}
 
 	return &mission, nil
 }
 
 // CreateIssue creates an issue in Beads + VC extension table if needed
 func (s *VCStorage) CreateIssue(ctx context.Context, issue *types.Issue, actor string) error {
+	if err := issue.Validate(); err != nil {
+		return err
+	}
+
+	// Beads only knows the default project's prefix
+	if issue.Project != "" && issue.Project != types.DefaultProject {
+		return s.CreateIssueWithMetadata(ctx, issue, nil, nil, actor)
+	}
+
 	// Convert to Beads type
 	beadsIssue := vcIssueToBeads(issue)
 
@@ -236,25 +267,11 @@ func (s *VCStorage) UpdateMission(ctx context.Context, id string, updates map[st
 	}
 
 	// Separate updates into base issue fields and mission-specific fields
-	missionFields := map[string]interface{}{
-		"approved_at":       nil,
-		"approved_by":       nil,
-		"goal":              nil,
-		"context":           nil,
-		"sandbox_path":      nil,
-		"branch_name":       nil,
-		"phase_count":       nil,
-		"current_phase":     nil,
-		"approval_required": nil,
-		"iteration_count":   nil,
-		"gates_status":      nil,
-	}
-
 	baseUpdates := make(map[string]interface{})
 	missionUpdates := make(map[string]interface{})
 
 	for key, value := range updates {
-		if _, isMissionField := missionFields[key]; isMissionField {
+		if missionStateColumns[key] {
 			missionUpdates[key] = value
 		} else {
 			baseUpdates[key] = value
@@ -263,6 +280,11 @@ func (s *VCStorage) UpdateMission(ctx context.Context, id string, updates map[st
 
 	// Update base issue fields if any
 	if len(baseUpdates) > 0 {
+		if status, ok := baseUpdates["status"]; ok && oldMission != nil {
+			if err := validateStatusUpdate(id, oldMission.Status, types.Status(fmt.Sprint(status))); err != nil {
+				return err
+			}
+		}
 		if err := s.Storage.UpdateIssue(ctx, id, baseUpdates, actor); err != nil {
 			return fmt.Errorf("failed to update base issue fields: %w", err)
 		}
@@ -271,25 +293,7 @@ func (s *VCStorage) UpdateMission(ctx context.Context, id string, updates map[st
 	// Update mission-specific fields if any
 	if len(missionUpdates) > 0 {
 		// Build dynamic UPDATE query
-		setClauses := []string{}
-		args := []interface{}{}
-
-		for key, value := range missionUpdates {
-			setClauses = append(setClauses, fmt.Sprintf("%s = ?", key))
-			args = append(args, value)
-		}
-
-		setClauses = append(setClauses, "updated_at = ?")
-		args = append(args, time.Now())
-		args = append(args, id) // WHERE clause
-
-		query := fmt.Sprintf(`
-			UPDATE vc_mission_state
-			SET %s
-			WHERE issue_id = ?
-		`, strings.Join(setClauses, ", "))
-
-		if _, err := s.db.ExecContext(ctx, query, args...); err != nil {
+		if _, err := s.updateMissionState(ctx, id, missionUpdates); err != nil {
 			return fmt.Errorf("failed to update mission metadata: %w", err)
 		}
 	}
@@ -380,49 +384,122 @@ func (s *VCStorage) UpdateMission(ctx context.Context, id string, updates map[st
 }
 
 // UpdateIssue updates issue fields in Beads
+// The values must pass types.ValidateUpdates, and a status change must
+// follow the legal status graph (types.Status.CanTransitionTo)
 func (s *VCStorage) UpdateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string) error {
+	if err := types.ValidateUpdates(updates); err != nil {
+		return err
+	}
+	if err := s.checkStatusUpdate(ctx, id, updates); err != nil {
+		return err
+	}
 	// Delegate to Beads (it handles all core issue fields)
 	return s.Storage.UpdateIssue(ctx, id, updates, actor)
 }
 
-// CloseIssue closes an issue in Beads
+// CloseIssue closes an issue in Beads as fixed. Any issue may be closed, but only with a reason.
 func (s *VCStorage) CloseIssue(ctx context.Context, id string, reason string, actor string) error {
-	return s.Storage.CloseIssue(ctx, id, reason, actor)
+	return s.CloseIssueWithResolution(ctx, id, reason, types.ResolutionFixed, actor)
 }
 
-// SearchIssues searches issues in Beads
-func (s *VCStorage) SearchIssues(ctx context.Context, query string, filter types.IssueFilter) ([]*types.Issue, error) {
-	// Convert VC filter to Beads filter
-	beadsFilter := beads.IssueFilter{
-		Priority: filter.Priority,
-		Assignee: filter.Assignee,
+// CloseIssueWithResolution closes an issue and records its resolution.
+// Blocked issues it was the last open blocker of are then unblocked per the
+// unblock_dependents policy (see unblockDependents).
+func (s *VCStorage) CloseIssueWithResolution(ctx context.Context, id string, reason string, resolution types.Resolution, actor string) error {
+	if strings.TrimSpace(reason) == "" {
+		return fmt.Errorf("closing %s requires a reason", id)
+	}
+	if !resolution.IsValid() {
+		return fmt.Errorf("invalid resolution %q (valid: %v)", resolution, types.Resolutions)
+	}
+	if err := s.Storage.CloseIssue(ctx, id, reason, actor); err != nil {
+		return err
+	}
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO vc_issue_resolutions (issue_id, resolution, resolved_at, resolved_by)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(issue_id) DO UPDATE SET
+			resolution = excluded.resolution,
+			resolved_at = excluded.resolved_at,
+			resolved_by = excluded.resolved_by
+	`, id, resolution, time.Now(), actor)
+	if err != nil {
+		return fmt.Errorf("closed %s but failed to record its resolution: %w", id, err)
 	}
+	// The close stands either way
+	if err := s.unblockDependents(ctx, id, actor); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: closed %s but failed to unblock its dependents: %v\n", id, err)
+	}
+	return nil
+}
 
-	// Convert pointer fields if not nil
-	if filter.Status != nil {
-		beadsStatus := beads.Status(*filter.Status)
-		beadsFilter.Status = &beadsStatus
+// resolution returns how a closed issue was resolved; closed without a
+// recorded resolution means fixed
+func (s *VCStorage) resolution(ctx context.Context, id string) (types.Resolution, error) {
+	var resolution string
+	err := s.db.QueryRowContext(ctx, `SELECT resolution FROM vc_issue_resolutions WHERE issue_id = ?`, id).Scan(&resolution)
+	if err == sql.ErrNoRows {
+		return types.ResolutionFixed, nil
 	}
-	if filter.Type != nil {
-		beadsType := beads.IssueType(*filter.Type)
-		beadsFilter.IssueType = &beadsType
-	} else if filter.IssueType != nil {
-		beadsType := beads.IssueType(*filter.IssueType)
-		beadsFilter.IssueType = &beadsType
+	if err != nil {
+		return "", fmt.Errorf("failed to query resolution: %w", err)
 	}
+	return types.Resolution(resolution), nil
+}
 
-	beadsIssues, err := s.Storage.SearchIssues(ctx, query, beadsFilter)
+// OverrideIssueStatus sets an issue's status without checking the status
+// graph, for admins repairing state. The override is recorded in a comment.
+func (s *VCStorage) OverrideIssueStatus(ctx context.Context, id string, status types.Status, reason string, actor string) error {
+	if !status.IsValid() {
+		return fmt.Errorf("invalid status: %s", status)
+	}
+	issue, err := s.Storage.GetIssue(ctx, id)
 	if err != nil {
-		return nil, err
+		return fmt.Errorf("failed to get issue: %w", err)
+	}
+	if issue == nil {
+		return fmt.Errorf("issue %s not found", id)
 	}
 
-	// Convert back to VC types
-	vcIssues := make([]*types.Issue, len(beadsIssues))
-	for i, bi := range beadsIssues {
-		vcIssues[i] = beadsIssueToVC(bi)
+	if err := s.Storage.UpdateIssue(ctx, id, map[string]interface{}{"status": string(status)}, actor); err != nil {
+		return err
 	}
 
-	return vcIssues, nil
+	comment := fmt.Sprintf("Status override by %s: %s → %s (status graph check skipped)", actor, issue.Status, status)
+	if reason != "" {
+		comment += "\n\nReason: " + reason
+	}
+	if err := s.AddComment(ctx, id, actor, comment); err != nil {
+		return fmt.Errorf("status overridden but failed to record audit comment: %w", err)
+	}
+	return nil
+}
+
+// checkStatusUpdate rejects a "status" update outside the legal status
+// graph. Closing goes through CloseIssue, which records a reason.
+func (s *VCStorage) checkStatusUpdate(ctx context.Context, id string, updates map[string]interface{}) error {
+	value, ok := updates["status"]
+	if !ok {
+		return nil
+	}
+	to := types.Status(fmt.Sprint(value))
+
+	issue, err := s.Storage.GetIssue(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get issue: %w", err)
+	}
+	if issue == nil {
+		return fmt.Errorf("issue %s not found", id)
+	}
+	return validateStatusUpdate(id, types.Status(issue.Status), to)
+}
+
+// validateStatusUpdate checks a status change made through an update (not CloseIssue)
+func validateStatusUpdate(id string, from, to types.Status) error {
+	if to == types.StatusClosed && from != types.StatusClosed {
+		return &types.StatusTransitionError{IssueID: id, From: from, To: to, Reason: "closing requires a reason, use CloseIssue"}
+	}
+	return types.ValidateStatusTransition(id, from, to)
 }
 
 // ======================================================================
@@ -450,12 +527,16 @@ func (s *VCStorage) GetDependencies(ctx context.Context, issueID string) ([]*typ
 	if err != nil {
 		return nil, err
 	}
+	archived, err := s.archivedIssueIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
 
 	vcIssues := make([]*types.Issue, len(beadsIssues))
 	for i, bi := range beadsIssues {
 		vcIssues[i] = beadsIssueToVC(bi)
 	}
-	return vcIssues, nil
+	return withoutArchived(vcIssues, archived, 0), nil
 }
 
 // GetDependents retrieves dependents from Beads
@@ -464,12 +545,16 @@ func (s *VCStorage) GetDependents(ctx context.Context, issueID string) ([]*types
 	if err != nil {
 		return nil, err
 	}
+	archived, err := s.archivedIssueIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
 
 	vcIssues := make([]*types.Issue, len(beadsIssues))
 	for i, bi := range beadsIssues {
 		vcIssues[i] = beadsIssueToVC(bi)
 	}
-	return vcIssues, nil
+	return withoutArchived(vcIssues, archived, 0), nil
 }
 
 // GetDependencyRecords retrieves dependency records from Beads
@@ -496,18 +581,98 @@ func (s *VCStorage) GetDependencyTree(ctx context.Context, issueID string, maxDe
 	if err != nil {
 		return nil, err
 	}
+	archived, err := s.archivedIssueIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-	vcNodes := make([]*types.TreeNode, len(beadsNodes))
-	for i, bn := range beadsNodes {
-		vcNodes[i] = &types.TreeNode{
+	// The root is kept even if archived: the caller asked for it by ID
+	vcNodes := make([]*types.TreeNode, 0, len(beadsNodes))
+	ids := make([]string, 0, len(beadsNodes))
+	for _, bn := range beadsNodes {
+		if archived[bn.ID] && bn.ID != issueID {
+			continue
+		}
+		vcNodes = append(vcNodes, &types.TreeNode{
 			Issue:     *beadsIssueToVC(&bn.Issue),
 			Depth:     bn.Depth,
 			Truncated: bn.Truncated,
-		}
+		})
+		ids = append(ids, bn.ID)
+	}
+	projects, err := s.issueProjects(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, node := range vcNodes {
+		node.Project = projects[node.ID]
+	}
+	if err := s.linkTreeNodes(ctx, vcNodes); err != nil {
+		return nil, err
 	}
 	return vcNodes, nil
 }
 
+// linkTreeNodes sets ParentID and DependencyType on the nodes of a dependency
+// tree, which Beads doesn't return. Beads keeps each issue at its shallowest
+// depth, so a node's parent is an issue one level up that depends on it; when
+// there are several, a blocking link wins, then the lowest ID.
+func (s *VCStorage) linkTreeNodes(ctx context.Context, nodes []*types.TreeNode) error {
+	if len(nodes) < 2 {
+		return nil
+	}
+	depth := make(map[string]int, len(nodes))
+	ids := make([]interface{}, len(nodes))
+	for i, node := range nodes {
+		depth[node.ID] = node.Depth
+		ids[i] = node.ID
+	}
+
+	// #nosec G201 - only placeholders are interpolated
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT issue_id, depends_on_id, type
+		FROM dependencies
+		WHERE depends_on_id IN (%s)
+		ORDER BY issue_id
+	`, strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")), ids...)
+	if err != nil {
+		return fmt.Errorf("failed to get tree links: %w", err)
+	}
+	defer rows.Close()
+
+	type link struct {
+		parentID string
+		depType  types.DependencyType
+	}
+	best := make(map[string]link)
+	for rows.Next() {
+		var issueID, dependsOnID string
+		var depType types.DependencyType
+		if err := rows.Scan(&issueID, &dependsOnID, &depType); err != nil {
+			return fmt.Errorf("failed to scan tree link: %w", err)
+		}
+		parentDepth, ok := depth[issueID]
+		if !ok || parentDepth != depth[dependsOnID]-1 {
+			continue
+		}
+		current, seen := best[dependsOnID]
+		if !seen || (depType.IsBlocking() && !current.depType.IsBlocking()) {
+			best[dependsOnID] = link{parentID: issueID, depType: depType}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating tree links: %w", err)
+	}
+
+	for _, node := range nodes {
+		if l, ok := best[node.ID]; ok && node.Depth > 0 {
+			node.ParentID = l.parentID
+			node.DependencyType = l.depType
+		}
+	}
+	return nil
+}
+
 // DetectCycles detects dependency cycles in Beads
 func (s *VCStorage) DetectCycles(ctx context.Context) ([][]*types.Issue, error) {
 	beadsCycles, err := s.Storage.DetectCycles(ctx)
@@ -539,54 +704,172 @@ func (s *VCStorage) GetIssuesByLabel(ctx context.Context, label string) ([]*type
 	if err != nil {
 		return nil, err
 	}
+	archived, err := s.archivedIssueIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
 
 	vcIssues := make([]*types.Issue, len(beadsIssues))
 	for i, bi := range beadsIssues {
 		vcIssues[i] = beadsIssueToVC(bi)
 	}
-	return vcIssues, nil
+	return withoutArchived(vcIssues, archived, 0), nil
 }
 
 // ======================================================================
-// READY WORK & BLOCKING (delegate to Beads)
+// UNBLOCKING
 // ======================================================================
 
-// GetReadyWork retrieves ready work from Beads with mission context (vc-234)
-func (s *VCStorage) GetReadyWork(ctx context.Context, filter types.WorkFilter) ([]*types.Issue, error) {
-	beadsFilter := beads.WorkFilter{
-		Status:     beads.Status(filter.Status),
-		Priority:   filter.Priority,
-		Limit:      filter.Limit,
-		SortPolicy: beads.SortPolicy(filter.SortPolicy), // Pass through sort policy (vc-190)
+// UnblockIssue reopens a blocked issue and removes the auto-blocked and
+// ready-to-unblock labels.
+// The issue_unblocked event is best-effort: the issue is already reopened.
+func (s *VCStorage) UnblockIssue(ctx context.Context, id string, actor string) error {
+	issue, err := s.Storage.GetIssue(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get issue: %w", err)
+	}
+	if issue == nil {
+		return fmt.Errorf("issue %s not found", id)
+	}
+	if types.Status(issue.Status) != types.StatusBlocked {
+		return fmt.Errorf("issue %s is not blocked (status %s)", id, issue.Status)
+	}
+	labels, err := s.GetLabels(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get labels: %w", err)
+	}
+	autoBlocked := slices.Contains(labels, types.AutoBlockedLabel)
+
+	if err := s.UpdateIssue(ctx, id, map[string]interface{}{"status": string(types.StatusOpen)}, actor); err != nil {
+		return fmt.Errorf("failed to reopen issue %s: %w", id, err)
+	}
+	for _, label := range []string{types.AutoBlockedLabel, types.ReadyToUnblockLabel} {
+		if !slices.Contains(labels, label) {
+			continue
+		}
+		if err := s.RemoveLabel(ctx, id, label, actor); err != nil {
+			return fmt.Errorf("failed to remove the %s label: %w", label, err)
+		}
 	}
 
-	beadsIssues, err := s.Storage.GetReadyWork(ctx, beadsFilter)
+	event, err := events.NewIssueUnblockedEvent(id, "", "", events.SeverityInfo,
+		fmt.Sprintf("Issue %s unblocked by %s", id, actor),
+		events.IssueUnblockedData{AutoBlocked: autoBlocked, Actor: actor})
+	if err == nil {
+		err = s.StoreAgentEvent(ctx, event)
+	}
 	if err != nil {
-		return nil, err
+		fmt.Fprintf(os.Stderr, "Warning: failed to store issue_unblocked event for %s: %v\n", id, err)
 	}
+	return nil
+}
 
-	// vc-203: Filter out epics - they are tracking/meta issues, not executable work
-	vcIssues := make([]*types.Issue, 0, len(beadsIssues))
-	for _, bi := range beadsIssues {
-		if bi.IssueType == beads.TypeEpic {
-			continue // Skip epics
+// ======================================================================
+// READY WORK & BLOCKING (delegate to Beads)
+// ======================================================================
+
+// GetReadyWork retrieves ready work with mission context (vc-234): open,
+// unblocked issues that aren't epics, archived or the SYSTEM pseudo-issue,
+// nor assigned to a human for filter.AgentsOnly. The query follows Beads'
+// ready-work rules but orders and limits the work in SQL (see ordering.go),
+// so a poll only loads and enriches the issues it can return.
+func (s *VCStorage) GetReadyWork(ctx context.Context, filter types.WorkFilter) ([]*types.Issue, error) {
+	if filter.OrderBy != "" && !filter.OrderBy.IsValid() {
+		return nil, fmt.Errorf("invalid work order %q (valid: %v)", filter.OrderBy, types.WorkOrders)
+	}
+	query, args := readyWorkQuery(filter)
+
+	// vc-234: Enrich with mission context and filter by mission active state.
+	// Held tasks can't crowd out other ready work: while they leave a page
+	// short of the limit, the next page is fetched.
+	pageSize := filter.Limit
+	if pageSize <= 0 {
+		pageSize = -1 // No limit: a single page
+	}
+	var ready []*types.Issue
+	for offset := 0; ; offset += pageSize {
+		page, err := s.queryIssueRows(ctx, query+"\n\t\tLIMIT ? OFFSET ?", append(args, pageSize, offset)...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query ready work: %w", err)
+		}
+		enriched, err := s.enrichWithMissionContext(ctx, page)
+		if err != nil {
+			return nil, err
 		}
-		vcIssues = append(vcIssues, beadsIssueToVC(bi))
+		ready = append(ready, enriched...)
+		if pageSize < 0 || len(page) < pageSize || len(ready) >= filter.Limit {
+			break
+		}
+	}
+	if filter.Limit > 0 && len(ready) > filter.Limit {
+		ready = ready[:filter.Limit]
+	}
+	return ready, nil
+}
+
+// readyWorkQuery builds the ready-work query for filter, without the page
+// limits. Like Beads, an issue is blocked by a blocks dependency on an issue
+// that isn't closed, and passes a blockage on to its parent-child children.
+func readyWorkQuery(filter types.WorkFilter) (string, []interface{}) {
+	status := filter.Status
+	if status == "" {
+		status = types.StatusOpen // Beads' default
+	}
+	filterSQL := ""
+	args := []interface{}{status, types.SystemIssueID}
+	if filter.Priority != nil {
+		filterSQL += " AND i.priority = ?"
+		args = append(args, *filter.Priority)
+	}
+	if filter.Assignee != nil {
+		filterSQL += " AND i.assignee = ?"
+		args = append(args, *filter.Assignee)
+	}
+	if filter.Project != "" {
+		clause, projectArgs := projectClause("i", filter.Project)
+		filterSQL += " AND " + clause
+		args = append(args, projectArgs...)
+	}
+	if filter.AgentsOnly {
+		filterSQL += " AND NOT (" + fmt.Sprintf(humanAssignedSQL, "i") + ")"
 	}
+	orderSQL, orderArgs := readyWorkOrderSQL("i", filter)
+	args = append(args, orderArgs...)
 
-	// vc-234: Enrich with mission context and filter by mission active state
-	return s.enrichWithMissionContext(ctx, vcIssues)
+	// #nosec G201 - only constant expressions are interpolated
+	query := fmt.Sprintf(`
+		WITH RECURSIVE blocked(issue_id, depth) AS (
+		  SELECT d.issue_id, 0
+		  FROM dependencies d
+		  INNER JOIN issues blocker ON d.depends_on_id = blocker.id
+		  WHERE d.type = 'blocks' AND blocker.status != 'closed'
+		  UNION
+		  SELECT d.issue_id, b.depth + 1
+		  FROM blocked b
+		  INNER JOIN dependencies d ON d.depends_on_id = b.issue_id
+		  WHERE d.type = 'parent-child' AND b.depth < 50
+		)
+		SELECT i.id, i.title, i.description, i.design, i.acceptance_criteria,
+		       i.notes, i.status, i.priority, i.issue_type, i.assignee,
+		       i.estimated_minutes, i.created_at, i.updated_at, i.closed_at, %s
+		FROM issues i
+		WHERE i.status = ?
+		  AND i.issue_type != 'epic'
+		  AND i.id != ?
+		  AND NOT EXISTS (SELECT 1 FROM vc_archived_issues a WHERE a.issue_id = i.id)
+		  AND NOT EXISTS (SELECT 1 FROM blocked b WHERE b.issue_id = i.id)%s
+		ORDER BY %s`, fmt.Sprintf(issueProjectSQL, "i"), filterSQL, orderSQL)
+	return query, args
 }
 
 // enrichWithMissionContext populates mission context for each issue and filters out
-// issues from missions with needs-quality-gates label (vc-234, vc-239)
+// issues from missions with needs-quality-gates label (vc-234, vc-239),
+// whose plan awaits approval, or whose phase isn't executing yet
 func (s *VCStorage) enrichWithMissionContext(ctx context.Context, issues []*types.Issue) ([]*types.Issue, error) {
 	if len(issues) == 0 {
 		return issues, nil
 	}
 
-	// Cache to avoid N+1 queries: map[missionID]missionContext
-	missionCache := make(map[string]*types.MissionContext)
 	// Track unique mission IDs for batch label loading
 	uniqueMissionIDs := make(map[string]bool)
 
@@ -594,7 +877,7 @@ func (s *VCStorage) enrichWithMissionContext(ctx context.Context, issues []*type
 	issuesWithMissions := make([]*types.Issue, 0, len(issues))
 	for _, issue := range issues {
 		// Try to get mission context (may fail if task is not part of a mission)
-		missionCtx, err := s.getMissionForTaskCached(ctx, issue.ID, missionCache)
+		missionCtx, err := s.GetMissionForTask(ctx, issue.ID)
 		if err != nil {
 			// Task is not part of a mission - include it without mission context
 			issuesWithMissions = append(issuesWithMissions, issue)
@@ -624,6 +907,11 @@ func (s *VCStorage) enrichWithMissionContext(ctx context.Context, issues []*type
 			continue
 		}
 
+		// Work waits until the mission's plan is approved, and for its phase
+		if issue.MissionContext.AwaitingApproval || issue.MissionContext.PhaseWaiting {
+			continue
+		}
+
 		// Check if mission has needs-quality-gates label
 		labels := missionLabels[issue.MissionContext.MissionID]
 		hasNeedsGates := false
@@ -694,62 +982,78 @@ func (s *VCStorage) batchLoadLabels(ctx context.Context, issueIDs map[string]boo
 	return result, nil
 }
 
-// getMissionForTaskCached gets mission context with caching to avoid N+1 queries
-func (s *VCStorage) getMissionForTaskCached(ctx context.Context, taskID string, cache map[string]*types.MissionContext) (*types.MissionContext, error) {
-	// Walk up the dependency tree to find mission
-	missionCtx, err := s.GetMissionForTask(ctx, taskID)
+// GetBlockedIssues retrieves blocked issues from Beads, adding the root
+// blockers at the end of each issue's blocking chains
+func (s *VCStorage) GetBlockedIssues(ctx context.Context) ([]*types.BlockedIssue, error) {
+	beadsBlocked, err := s.Storage.GetBlockedIssues(ctx)
 	if err != nil {
 		return nil, err
 	}
-
-	// Check if we've already loaded this mission's full context
-	if cached, ok := cache[missionCtx.MissionID]; ok {
-		return cached, nil
+	archived, err := s.archivedIssueIDs(ctx)
+	if err != nil {
+		return nil, err
 	}
-
-	// Cache it for future lookups
-	cache[missionCtx.MissionID] = missionCtx
-	return missionCtx, nil
-}
-
-// GetBlockedIssues retrieves blocked issues from Beads
-func (s *VCStorage) GetBlockedIssues(ctx context.Context) ([]*types.BlockedIssue, error) {
-	beadsBlocked, err := s.Storage.GetBlockedIssues(ctx)
+	roots, truncated, err := s.rootBlockers(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	vcBlocked := make([]*types.BlockedIssue, len(beadsBlocked))
-	for i, bb := range beadsBlocked {
-		vcBlocked[i] = &types.BlockedIssue{
+	vcBlocked := make([]*types.BlockedIssue, 0, len(beadsBlocked))
+	for _, bb := range beadsBlocked {
+		if archived[bb.ID] {
+			continue
+		}
+		vcBlocked = append(vcBlocked, &types.BlockedIssue{
 			Issue:          *beadsIssueToVC(&bb.Issue),
 			BlockedByCount: bb.BlockedByCount,
 			BlockedBy:      bb.BlockedBy,
-		}
+			RootBlockers:   roots[bb.ID],
+			RootsTruncated: truncated[bb.ID],
+		})
 	}
 	return vcBlocked, nil
 }
 
 // GetReadyBlockers retrieves blocker issues that are ready to execute
 // This is an optimized query that filters for label='discovered:blocker' AND status='open'
-// and checks for open blocking dependencies in a single SQL query (vc-156)
-func (s *VCStorage) GetReadyBlockers(ctx context.Context, limit int) ([]*types.Issue, error) {
+// and checks for open blocking dependencies in a single SQL query (vc-156).
+// Of the filter, only Limit, Project and AgentsOnly apply; they are part of
+// the query so skipped blockers can't use up the limit.
+func (s *VCStorage) GetReadyBlockers(ctx context.Context, filter types.WorkFilter) ([]*types.Issue, error) {
+	var filterSQL string
+	var args []interface{}
+	if filter.Project != "" {
+		clause, projectArgs := projectClause("i", filter.Project)
+		filterSQL += " AND " + clause
+		args = append(args, projectArgs...)
+	}
+	if filter.AgentsOnly {
+		filterSQL += " AND NOT (" + fmt.Sprintf(humanAssignedSQL, "i") + ")"
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = -1 // No limit
+	}
+	args = append(args, limit)
+
 	// Optimized single SQL query that:
 	// 1. Filters for issues with discovered:blocker label
 	// 2. Filters for status='open'
-	// 3. Filters out epics (vc-203)
+	// 3. Filters out epics (vc-203) and archived issues
 	// 4. LEFT JOINs to check for open blocking dependencies
 	// 5. Returns only issues with NO open blockers (ready to execute)
-	// 6. Orders by priority (lower = higher priority)
-	query := `
+	// 6. Orders by priority (lower = higher priority), then age and ID
+	// #nosec G201 - only constant expressions are interpolated
+	query := fmt.Sprintf(`
 		SELECT DISTINCT i.id, i.title, i.description, i.design, i.acceptance_criteria,
 		       i.notes, i.status, i.priority, i.issue_type, i.assignee,
-		       i.estimated_minutes, i.created_at, i.updated_at, i.closed_at
+		       i.estimated_minutes, i.created_at, i.updated_at, i.closed_at, %s
 		FROM issues i
 		INNER JOIN labels l ON i.id = l.issue_id
 		WHERE l.label = 'discovered:blocker'
 		  AND i.status = 'open'
 		  AND i.issue_type != 'epic'
+		  AND NOT EXISTS (SELECT 1 FROM vc_archived_issues a WHERE a.issue_id = i.id)
 		  AND NOT EXISTS (
 		    -- Check if this issue has any open blocking dependencies (vc-157)
 		    -- Only check type='blocks', not related/parent-child/discovered-from
@@ -758,15 +1062,25 @@ func (s *VCStorage) GetReadyBlockers(ctx context.Context, limit int) ([]*types.I
 		    WHERE d.issue_id = i.id
 		      AND d.type = 'blocks'
 		      AND dep_issue.status != 'closed'
-		  )
-		ORDER BY i.priority ASC
+		  )%s
+		ORDER BY %s
 		LIMIT ?
-	`
+	`, fmt.Sprintf(issueProjectSQL, "i"), filterSQL, fmt.Sprintf(byPrioritySQL, "i"))
 
-	rows, err := s.db.QueryContext(ctx, query, limit)
+	issues, err := s.queryIssueRows(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query ready blockers: %w", err)
 	}
+	return issues, nil
+}
+
+// queryIssueRows runs a query selecting the issue columns GetReadyBlockers
+// lists, ending with the project, and scans the issues
+func (s *VCStorage) queryIssueRows(ctx context.Context, query string, args ...interface{}) ([]*types.Issue, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
 	defer rows.Close()
 
 	var issues []*types.Issue
@@ -791,6 +1105,7 @@ func (s *VCStorage) GetReadyBlockers(ctx context.Context, limit int) ([]*types.I
 			&issue.CreatedAt,
 			&issue.UpdatedAt,
 			&closedAt,
+			&issue.Project,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan issue: %w", err)
@@ -851,20 +1166,47 @@ func (s *VCStorage) GetEvents(ctx context.Context, issueID string, limit int) ([
 // STATISTICS (delegate to Beads)
 // ======================================================================
 
-// GetStatistics retrieves statistics from Beads
+// GetStatistics retrieves statistics from Beads, adding flow metrics for
+// the trailing types.FlowWeeks weeks
 func (s *VCStorage) GetStatistics(ctx context.Context) (*types.Statistics, error) {
 	beadsStats, err := s.Storage.GetStatistics(ctx)
 	if err != nil {
 		return nil, err
 	}
+	// Archived issues and the SYSTEM pseudo-issue are left out of every count
+	archived, err := s.archivedStatistics(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	flow, err := s.flowMetrics(ctx, time.Now(), types.FlowWeeks)
+	if err != nil {
+		return nil, err
+	}
+
+	var autoBlocked int
+	err = s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*)
+		FROM issues i
+		WHERE i.status = 'blocked'
+		  AND i.id IN (SELECT issue_id FROM labels WHERE label = ?)
+		  AND i.id NOT IN (SELECT issue_id FROM vc_archived_issues)
+		  AND i.id != ?
+	`, types.AutoBlockedLabel, types.SystemIssueID).Scan(&autoBlocked)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count auto-blocked issues: %w", err)
+	}
 
 	return &types.Statistics{
-		TotalIssues:      beadsStats.TotalIssues,
-		OpenIssues:       beadsStats.OpenIssues,
-		InProgressIssues: beadsStats.InProgressIssues,
-		ClosedIssues:     beadsStats.ClosedIssues,
-		BlockedIssues:    beadsStats.BlockedIssues,
-		ReadyIssues:      beadsStats.ReadyIssues, // vc-166: Include ready issues count
+		TotalIssues:       beadsStats.TotalIssues - archived.TotalIssues,
+		OpenIssues:        beadsStats.OpenIssues - archived.OpenIssues,
+		InProgressIssues:  beadsStats.InProgressIssues - archived.InProgressIssues,
+		ClosedIssues:      beadsStats.ClosedIssues - archived.ClosedIssues,
+		BlockedIssues:     beadsStats.BlockedIssues - archived.BlockedIssues,
+		ReadyIssues:       beadsStats.ReadyIssues - archived.ReadyIssues, // vc-166: Include ready issues count
+		AutoBlockedIssues: autoBlocked,
+		AverageLeadTime:   beadsStats.AverageLeadTime,
+		Flow:              flow,
 	}, nil
 }
 
@@ -1277,9 +1619,10 @@ func (s *VCStorage) GetMissionForTask(ctx context.Context, taskID string) (*type
 		  WHERE d.type = ? AND p.depth < 10  -- Prevent infinite loops
 		)
 		-- Find the first mission epic in the parent chain
-		SELECT i.id, i.issue_type, COALESCE(m.subtype, '') as subtype,
+		SELECT i.id, i.issue_type, i.status, COALESCE(m.subtype, '') as subtype,
 		       COALESCE(m.sandbox_path, '') as sandbox_path,
-		       COALESCE(m.branch_name, '') as branch_name
+		       COALESCE(m.branch_name, '') as branch_name,
+		       COALESCE(m.approval_required, 0) = 1 AND m.approved_at IS NULL as awaiting_approval
 		FROM issues i
 		JOIN parent_chain p ON i.id = p.depends_on_id
 		LEFT JOIN vc_mission_state m ON i.id = m.issue_id
@@ -1289,12 +1632,13 @@ func (s *VCStorage) GetMissionForTask(ctx context.Context, taskID string) (*type
 		LIMIT 1
 	`
 
-	var missionID, issueType, subtype, sandboxPath, branchName string
+	var missionID, issueType, status, subtype, sandboxPath, branchName string
+	var awaitingApproval bool
 	err := s.db.QueryRowContext(ctx, query,
 		taskID, types.DepParentChild, // Base case parameters
 		types.DepParentChild, // Recursive case parameter
 		types.TypeEpic, types.SubtypeMission, // WHERE clause parameters
-	).Scan(&missionID, &issueType, &subtype, &sandboxPath, &branchName)
+	).Scan(&missionID, &issueType, &status, &subtype, &sandboxPath, &branchName, &awaitingApproval)
 
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("task %s is not part of a mission (no parent-child dependency to mission epic)", taskID)
@@ -1303,10 +1647,18 @@ func (s *VCStorage) GetMissionForTask(ctx context.Context, taskID string) (*type
 		return nil, fmt.Errorf("failed to query mission for task %s: %w", taskID, err)
 	}
 
+	phaseID, phaseWaiting, err := s.taskPhase(ctx, taskID, missionID, types.Status(status) == types.StatusBlocked)
+	if err != nil {
+		return nil, err
+	}
+
 	return &types.MissionContext{
-		MissionID:   missionID,
-		SandboxPath: sandboxPath,
-		BranchName:  branchName,
+		MissionID:        missionID,
+		SandboxPath:      sandboxPath,
+		BranchName:       branchName,
+		AwaitingApproval: awaitingApproval,
+		PhaseID:          phaseID,
+		PhaseWaiting:     phaseWaiting,
 	}, nil
 }
 
@@ -1433,3 +1785,49 @@ func (s *VCStorage) VacuumDatabase(ctx context.Context) error {
 	_, err := s.db.ExecContext(ctx, "VACUUM")
 	return err
 }
+
+// IncrementalVacuum releases all freelist pages with PRAGMA incremental_vacuum.
+// It only has an effect when the database uses auto_vacuum=INCREMENTAL.
+func (s *VCStorage) IncrementalVacuum(ctx context.Context) error {
+	if _, err := s.db.ExecContext(ctx, "PRAGMA incremental_vacuum"); err != nil {
+		return fmt.Errorf("incremental vacuum failed: %w", err)
+	}
+	return nil
+}
+
+// GetDatabaseStats reports the database's page counts, free pages and size
+func (s *VCStorage) GetDatabaseStats(ctx context.Context) (*types.DatabaseStats, error) {
+	var stats types.DatabaseStats
+	var autoVacuum int
+	for _, p := range []struct {
+		pragma string
+		dest   interface{}
+	}{
+		{"page_size", &stats.PageSize},
+		{"page_count", &stats.PageCount},
+		{"freelist_count", &stats.FreelistCount},
+		{"auto_vacuum", &autoVacuum},
+	} {
+		// #nosec G201 - pragma names are constants
+		if err := s.db.QueryRowContext(ctx, "PRAGMA "+p.pragma).Scan(p.dest); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", p.pragma, err)
+		}
+	}
+
+	switch autoVacuum {
+	case 1:
+		stats.AutoVacuum = "full"
+	case 2:
+		stats.AutoVacuum = "incremental"
+	default:
+		stats.AutoVacuum = "none"
+	}
+	stats.SizeBytes = stats.PageCount * stats.PageSize
+	if isInMemoryPath(s.dbPath) {
+		return &stats, nil
+	}
+	if info, err := os.Stat(s.dbPath + "-wal"); err == nil {
+		stats.WALSizeBytes = info.Size()
+	}
+	return &stats, nil
+}
@@ -0,0 +1,248 @@
+package beads
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ======================================================================
+// SCHEMA MIGRATIONS (VC extension tables)
+// ======================================================================
+
+// schemaMigration is one step in the evolution of the VC extension schema.
+// Migrations run in version order, each in its own transaction, and are
+// recorded in vc_schema_migrations so they run once per database.
+//
+// Migrations must tolerate a schema that already has their change:
+// vcExtensionTableSchema creates new databases at the latest shape, and
+// databases from before vc_schema_migrations have no record of what ran.
+type schemaMigration struct {
+	version int
+	name    string
+	apply   func(ctx context.Context, conn *sql.Conn) error
+}
+
+// schemaMigrations lists every migration in version order. Append new
+// migrations at the end with the next version number; never renumber or
+// remove one that has shipped.
+var schemaMigrations = []schemaMigration{
+	{1, "add executor_id, agent_id and source_line to vc_agent_events", migrateAgentEventsTable},
+	{2, "add policy_entry to vc_watchdog_interventions", migrateInterventionsTable},
+	{3, "type untyped dependencies as blocks", migrateDependencyTypes},
+	{4, "add metadata to vc_executor_instances", migrateExecutorInstancesTable},
+	{5, "add artifacts to vc_execution_history", migrateExecutionHistoryTable},
+	{6, "add previous_assignee to vc_issue_execution_state", migrateExecutionStateTable},
+	{7, "add phases to vc_execution_history", migrateExecutionHistoryPhases},
+	{8, "add commit_hash, branch and merge_commit to vc_execution_history", migrateExecutionHistoryCommits},
+	{9, "add files to vc_execution_history", migrateExecutionHistoryFiles},
+}
+
+// LatestSchemaVersion is the schema version this binary migrates databases to
+func LatestSchemaVersion() int {
+	return schemaMigrations[len(schemaMigrations)-1].version
+}
+
+// ErrSchemaTooNew is returned when opening a database migrated by a newer
+// version of vc. Match it with errors.Is; the concrete error is a
+// *SchemaVersionError.
+var ErrSchemaTooNew = errors.New("database schema is newer than this vc binary")
+
+// SchemaVersionError reports a database whose schema version is ahead of the
+// migrations this binary knows about
+type SchemaVersionError struct {
+	// DatabaseVersion is the highest migration recorded in the database
+	DatabaseVersion int
+	// SupportedVersion is LatestSchemaVersion for this binary
+	SupportedVersion int
+}
+
+// Error implements the error interface.
+func (e *SchemaVersionError) Error() string {
+	return fmt.Sprintf("%v: database is at schema version %d, this vc supports up to %d (upgrade vc to use this database)",
+		ErrSchemaTooNew, e.DatabaseVersion, e.SupportedVersion)
+}
+
+// Unwrap lets errors.Is(err, ErrSchemaTooNew) match.
+func (e *SchemaVersionError) Unwrap() error {
+	return ErrSchemaTooNew
+}
+
+const schemaMigrationsTable = `
+CREATE TABLE IF NOT EXISTS vc_schema_migrations (
+    version INTEGER PRIMARY KEY,
+    name TEXT NOT NULL,
+    applied_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`
+
+// SchemaVersion returns the highest migration applied to the database
+func (s *VCStorage) SchemaVersion(ctx context.Context) (int, error) {
+	var version int
+	err := s.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM vc_schema_migrations`).Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return version, nil
+}
+
+// checkSchemaVersion creates vc_schema_migrations if needed and fails with a
+// *SchemaVersionError if the database has migrations this binary lacks
+func checkSchemaVersion(ctx context.Context, conn *sql.Conn) error {
+	if _, err := conn.ExecContext(ctx, schemaMigrationsTable); err != nil {
+		return fmt.Errorf("failed to create vc_schema_migrations table: %w", err)
+	}
+
+	var version int
+	err := conn.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM vc_schema_migrations`).Scan(&version)
+	if err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if latest := LatestSchemaVersion(); version > latest {
+		return &SchemaVersionError{DatabaseVersion: version, SupportedVersion: latest}
+	}
+	return nil
+}
+
+// runSchemaMigrations applies the migrations not yet recorded in
+// vc_schema_migrations
+func runSchemaMigrations(ctx context.Context, conn *sql.Conn) error {
+	for _, m := range schemaMigrations {
+		if err := applySchemaMigration(ctx, conn, m); err != nil {
+			return fmt.Errorf("schema migration %03d (%s) failed: %w", m.version, m.name, err)
+		}
+	}
+	return nil
+}
+
+// applySchemaMigration runs m and records it in one transaction, unless it
+// was already applied. BEGIN IMMEDIATE takes the write lock before checking,
+// so concurrent processes opening the same database apply m only once.
+func applySchemaMigration(ctx context.Context, conn *sql.Conn, m schemaMigration) error {
+	if _, err := conn.ExecContext(ctx, "BEGIN IMMEDIATE"); err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			// Background context so the rollback happens even if ctx was canceled
+			_, _ = conn.ExecContext(context.Background(), "ROLLBACK")
+		}
+	}()
+
+	var applied bool
+	err := conn.QueryRowContext(ctx, `SELECT COUNT(*) > 0 FROM vc_schema_migrations WHERE version = ?`, m.version).Scan(&applied)
+	if err != nil {
+		return fmt.Errorf("failed to check migration status: %w", err)
+	}
+	if applied {
+		return nil // deferred ROLLBACK ends the read-only transaction
+	}
+
+	if err := m.apply(ctx, conn); err != nil {
+		return err
+	}
+	if _, err := conn.ExecContext(ctx, `INSERT INTO vc_schema_migrations (version, name) VALUES (?, ?)`, m.version, m.name); err != nil {
+		return fmt.Errorf("failed to record migration: %w", err)
+	}
+
+	if _, err := conn.ExecContext(ctx, "COMMIT"); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+	committed = true
+	return nil
+}
+
+// addColumnIfMissing adds column to table unless it already exists
+func addColumnIfMissing(ctx context.Context, conn *sql.Conn, table, column, definition string) error {
+	var exists bool
+	err := conn.QueryRowContext(ctx, `
+		SELECT COUNT(*) > 0
+		FROM pragma_table_info(?)
+		WHERE name = ?
+	`, table, column).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to check for %s column: %w", column, err)
+	}
+	if exists {
+		return nil
+	}
+
+	// #nosec G201 - table, column and definition come from migration code
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)); err != nil {
+		return fmt.Errorf("failed to add %s column: %w", column, err)
+	}
+	return nil
+}
+
+// migrateAgentEventsTable (001) adds the columns vc_agent_events gained
+// after it first shipped. Their indexes are in vcExtensionIndexSchema.
+func migrateAgentEventsTable(ctx context.Context, conn *sql.Conn) error {
+	if err := addColumnIfMissing(ctx, conn, "vc_agent_events", "executor_id", "TEXT"); err != nil {
+		return err
+	}
+	if err := addColumnIfMissing(ctx, conn, "vc_agent_events", "agent_id", "TEXT"); err != nil {
+		return err
+	}
+	return addColumnIfMissing(ctx, conn, "vc_agent_events", "source_line", "INTEGER DEFAULT 0")
+}
+
+// migrateInterventionsTable (002) adds the intervention policy entry column
+func migrateInterventionsTable(ctx context.Context, conn *sql.Conn) error {
+	return addColumnIfMissing(ctx, conn, "vc_watchdog_interventions", "policy_entry", "TEXT")
+}
+
+// migrateDependencyTypes (003) types the untyped dependencies written by
+// older versions as 'blocks', which is how they were treated
+func migrateDependencyTypes(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, `
+		UPDATE dependencies SET type = 'blocks'
+		WHERE type IS NULL OR TRIM(type) = ''
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to default dependency types: %w", err)
+	}
+	return nil
+}
+
+// migrateExecutorInstancesTable (004) adds the instance metadata column, which
+// records how each executor runs (e.g. offline)
+func migrateExecutorInstancesTable(ctx context.Context, conn *sql.Conn) error {
+	return addColumnIfMissing(ctx, conn, "vc_executor_instances", "metadata", "TEXT NOT NULL DEFAULT '{}'")
+}
+
+// migrateExecutionHistoryTable (005) adds the column listing the artifacts
+// saved for each attempt
+func migrateExecutionHistoryTable(ctx context.Context, conn *sql.Conn) error {
+	return addColumnIfMissing(ctx, conn, "vc_execution_history", "artifacts", "TEXT")
+}
+
+// migrateExecutionStateTable (006) adds the column holding an issue's
+// assignee from before its claim, which releasing the claim restores
+func migrateExecutionStateTable(ctx context.Context, conn *sql.Conn) error {
+	return addColumnIfMissing(ctx, conn, "vc_issue_execution_state", "previous_assignee", "TEXT")
+}
+
+// migrateExecutionHistoryPhases (007) adds the column holding how long each
+// attempt spent in each execution phase
+func migrateExecutionHistoryPhases(ctx context.Context, conn *sql.Conn) error {
+	return addColumnIfMissing(ctx, conn, "vc_execution_history", "phases", "TEXT")
+}
+
+// migrateExecutionHistoryCommits (008) adds the columns recording the commit
+// each attempt made, its branch and the merge that brought it into main
+func migrateExecutionHistoryCommits(ctx context.Context, conn *sql.Conn) error {
+	for _, column := range []string{"commit_hash", "branch", "merge_commit"} {
+		if err := addColumnIfMissing(ctx, conn, "vc_execution_history", column, "TEXT"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateExecutionHistoryFiles (009) adds the column listing the files each
+// attempt changed
+func migrateExecutionHistoryFiles(ctx context.Context, conn *sql.Conn) error {
+	return addColumnIfMissing(ctx, conn, "vc_execution_history", "files", "TEXT")
+}
@@ -0,0 +1,156 @@
+package beads
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+func TestSchemaMigrationsFreshDatabase(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newCreateTestStore(t)
+
+	version, err := store.SchemaVersion(ctx)
+	if err != nil {
+		t.Fatalf("SchemaVersion failed: %v", err)
+	}
+	if version != LatestSchemaVersion() {
+		t.Errorf("Expected schema version %d, got %d", LatestSchemaVersion(), version)
+	}
+	if n := countRows(t, store, `SELECT COUNT(*) FROM vc_schema_migrations`); n != len(schemaMigrations) {
+		t.Errorf("Expected %d recorded migrations, got %d", len(schemaMigrations), n)
+	}
+}
+
+func TestSchemaMigrationsAreOrdered(t *testing.T) {
+	for i, m := range schemaMigrations {
+		if m.version != i+1 {
+			t.Errorf("Migration %q has version %d, expected %d", m.name, m.version, i+1)
+		}
+		if m.name == "" || m.apply == nil {
+			t.Errorf("Migration %d needs a name and an apply function", m.version)
+		}
+	}
+}
+
+// TestSchemaMigrationsLegacyDatabase upgrades a database from before
+// vc_schema_migrations that lacks the migrated columns
+func TestSchemaMigrationsLegacyDatabase(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "legacy.db")
+	store, err := NewVCStorage(ctx, path)
+	if err != nil {
+		t.Fatalf("NewVCStorage failed: %v", err)
+	}
+	for _, stmt := range []string{
+		`DROP TABLE vc_schema_migrations`,
+		`ALTER TABLE vc_agent_events DROP COLUMN source_line`,
+		`ALTER TABLE vc_watchdog_interventions DROP COLUMN policy_entry`,
+		`ALTER TABLE vc_executor_instances DROP COLUMN metadata`,
+		`ALTER TABLE vc_issue_execution_state DROP COLUMN previous_assignee`,
+		`ALTER TABLE vc_execution_history DROP COLUMN phases`,
+		`ALTER TABLE vc_execution_history DROP COLUMN commit_hash`,
+		`ALTER TABLE vc_execution_history DROP COLUMN branch`,
+		`ALTER TABLE vc_execution_history DROP COLUMN merge_commit`,
+		`ALTER TABLE vc_execution_history DROP COLUMN files`,
+	} {
+		if _, err := store.db.ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+	_ = store.Close()
+
+	store, err = NewVCStorage(ctx, path)
+	if err != nil {
+		t.Fatalf("Reopening legacy database failed: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	for _, column := range []struct{ table, name string }{
+		{"vc_agent_events", "source_line"},
+		{"vc_watchdog_interventions", "policy_entry"},
+		{"vc_executor_instances", "metadata"},
+		{"vc_issue_execution_state", "previous_assignee"},
+		{"vc_execution_history", "phases"},
+		{"vc_execution_history", "commit_hash"},
+		{"vc_execution_history", "branch"},
+		{"vc_execution_history", "merge_commit"},
+		{"vc_execution_history", "files"},
+	} {
+		if n := countRows(t, store, `SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`, column.table, column.name); n != 1 {
+			t.Errorf("Expected %s.%s to be restored", column.table, column.name)
+		}
+	}
+	if version, err := store.SchemaVersion(ctx); err != nil || version != LatestSchemaVersion() {
+		t.Errorf("Expected schema version %d, got %d (err %v)", LatestSchemaVersion(), version, err)
+	}
+}
+
+func TestSchemaMigrationsRefuseNewerDatabase(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "newer.db")
+	store, err := NewVCStorage(ctx, path)
+	if err != nil {
+		t.Fatalf("NewVCStorage failed: %v", err)
+	}
+	future := LatestSchemaVersion() + 1
+	if _, err := store.db.ExecContext(ctx, `INSERT INTO vc_schema_migrations (version, name) VALUES (?, 'from the future')`, future); err != nil {
+		t.Fatalf("Failed to record future migration: %v", err)
+	}
+	_ = store.Close()
+
+	store, err = NewVCStorage(ctx, path)
+	if err == nil {
+		_ = store.Close()
+		t.Fatal("Expected opening a newer database to fail")
+	}
+	if !errors.Is(err, ErrSchemaTooNew) {
+		t.Errorf("Expected ErrSchemaTooNew, got %v", err)
+	}
+	var versionErr *SchemaVersionError
+	if !errors.As(err, &versionErr) || versionErr.DatabaseVersion != future || versionErr.SupportedVersion != LatestSchemaVersion() {
+		t.Errorf("Expected a SchemaVersionError for version %d, got %v", future, err)
+	}
+}
+
+// TestSchemaMigrationsRollBackOnFailure checks that a failing migration
+// leaves neither its changes nor a version record behind
+func TestSchemaMigrationsRollBackOnFailure(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "failing.db")
+
+	original := schemaMigrations
+	defer func() { schemaMigrations = original }()
+	schemaMigrations = append(append([]schemaMigration{}, original...), schemaMigration{
+		version: len(original) + 1,
+		name:    "half-applied",
+		apply: func(ctx context.Context, conn *sql.Conn) error {
+			if err := addColumnIfMissing(ctx, conn, "vc_agent_events", "half_applied", "TEXT"); err != nil {
+				return err
+			}
+			return fmt.Errorf("boom")
+		},
+	})
+
+	if store, err := NewVCStorage(ctx, path); err == nil {
+		_ = store.Close()
+		t.Fatal("Expected the failing migration to fail NewVCStorage")
+	}
+
+	schemaMigrations = original
+	store, err := NewVCStorage(ctx, path)
+	if err != nil {
+		t.Fatalf("NewVCStorage failed: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	if n := countRows(t, store, `SELECT COUNT(*) FROM pragma_table_info('vc_agent_events') WHERE name = 'half_applied'`); n != 0 {
+		t.Error("Expected the failed migration's column to be rolled back")
+	}
+	if version, err := store.SchemaVersion(ctx); err != nil || version != len(original) {
+		t.Errorf("Expected schema version %d, got %d (err %v)", len(original), version, err)
+	}
+}
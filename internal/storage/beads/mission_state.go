@@ -0,0 +1,411 @@
+package beads
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// MISSION STATE (vc_mission_state extension table)
+// ======================================================================
+
+// missionStateColumns are the vc_mission_state columns that can be updated
+// (issue_id, subtype and the timestamps are managed by the storage layer)
+var missionStateColumns = map[string]bool{
+	"sandbox_path":      true,
+	"branch_name":       true,
+	"iteration_count":   true,
+	"last_gates_run":    true,
+	"gates_status":      true,
+	"goal":              true,
+	"context":           true,
+	"phase_count":       true,
+	"current_phase":     true,
+	"approval_required": true,
+	"approved_at":       true,
+	"approved_by":       true,
+}
+
+// UpdateMissionState updates mission state columns of a mission or phase
+func (s *VCStorage) UpdateMissionState(ctx context.Context, issueID string, updates map[string]interface{}) error {
+	for key := range updates {
+		if !missionStateColumns[key] {
+			return fmt.Errorf("invalid mission state field: %s", key)
+		}
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+
+	updated, err := s.updateMissionState(ctx, issueID, updates)
+	if err != nil {
+		return fmt.Errorf("failed to update mission state: %w", err)
+	}
+	if !updated {
+		return fmt.Errorf("issue %s has no mission state", issueID)
+	}
+	return nil
+}
+
+// updateMissionState writes validated mission state columns and reports
+// whether the issue had a mission state row
+func (s *VCStorage) updateMissionState(ctx context.Context, issueID string, updates map[string]interface{}) (bool, error) {
+	// Sorted so the statement is the same for the same set of fields
+	keys := make([]string, 0, len(updates))
+	for key := range updates {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	setClauses := make([]string, 0, len(keys)+1)
+	args := make([]interface{}, 0, len(keys)+2)
+	for _, key := range keys {
+		setClauses = append(setClauses, fmt.Sprintf("%s = ?", key))
+		args = append(args, updates[key])
+	}
+	setClauses = append(setClauses, "updated_at = ?")
+	args = append(args, time.Now(), issueID)
+
+	query := fmt.Sprintf(`
+		UPDATE vc_mission_state
+		SET %s
+		WHERE issue_id = ?
+	`, strings.Join(setClauses, ", "))
+
+	result, err := s.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// SetMissionGatesStatus records the quality gates status of a mission or phase
+func (s *VCStorage) SetMissionGatesStatus(ctx context.Context, issueID string, status string) error {
+	if !types.IsValidGatesStatus(status) {
+		return fmt.Errorf("invalid gates status: %s", status)
+	}
+	updates := map[string]interface{}{"gates_status": status}
+	if status != types.GatesStatusPending {
+		updates["last_gates_run"] = time.Now()
+	}
+	return s.UpdateMissionState(ctx, issueID, updates)
+}
+
+// AdvanceMissionPhase moves a mission on to its next phase. The check and
+// the increment are one statement, so concurrent callers can't skip past
+// phase_count.
+func (s *VCStorage) AdvanceMissionPhase(ctx context.Context, issueID string) (int, error) {
+	var phase int
+	err := s.db.QueryRowContext(ctx, `
+		UPDATE vc_mission_state
+		SET current_phase = current_phase + 1, updated_at = ?
+		WHERE issue_id = ? AND current_phase < phase_count
+		RETURNING current_phase
+	`, time.Now(), issueID).Scan(&phase)
+	if err == nil {
+		return phase, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("failed to advance mission phase: %w", err)
+	}
+
+	// Nothing updated: either there's no mission state or no phase left
+	var current, count int
+	err = s.db.QueryRowContext(ctx, `
+		SELECT current_phase, phase_count FROM vc_mission_state WHERE issue_id = ?
+	`, issueID).Scan(&current, &count)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("issue %s has no mission state", issueID)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get mission phase: %w", err)
+	}
+	return 0, fmt.Errorf("mission %s is already at its last phase (%d of %d)", issueID, current, count)
+}
+
+// GetMissionsByStatus returns the non-archived missions with the given
+// issue status
+func (s *VCStorage) GetMissionsByStatus(ctx context.Context, status types.Status) ([]*types.Mission, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT i.id
+		FROM issues i
+		JOIN vc_mission_state m ON i.id = m.issue_id
+		WHERE i.status = ?
+		  AND i.issue_type = ?
+		  AND m.subtype = ?
+		  AND NOT EXISTS (SELECT 1 FROM vc_archived_issues a WHERE a.issue_id = i.id)
+		ORDER BY i.priority ASC, i.created_at ASC, i.id ASC
+	`, status, types.TypeEpic, types.SubtypeMission)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query missions by status: %w", err)
+	}
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan mission id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating mission rows: %w", err)
+	}
+
+	// Rows are closed first: GetMission needs a connection of its own
+	missions := make([]*types.Mission, 0, len(ids))
+	for _, id := range ids {
+		mission, err := s.GetMission(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get mission %s: %w", id, err)
+		}
+		missions = append(missions, mission)
+	}
+	return missions, nil
+}
+
+// ======================================================================
+// MISSION PHASES
+// ======================================================================
+
+// GetMissionPhases returns a mission's non-archived phases in execution order
+func (s *VCStorage) GetMissionPhases(ctx context.Context, missionID string) ([]*types.Issue, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT d.issue_id
+		FROM dependencies d
+		JOIN vc_mission_state m ON m.issue_id = d.issue_id
+		WHERE d.depends_on_id = ?
+		  AND d.type = ?
+		  AND m.subtype = ?
+		  AND NOT EXISTS (SELECT 1 FROM vc_archived_issues a WHERE a.issue_id = d.issue_id)
+	`, missionID, types.DepParentChild, types.SubtypePhase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query mission phases: %w", err)
+	}
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan phase id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating phase rows: %w", err)
+	}
+
+	phases := make([]*types.Issue, 0, len(ids))
+	var deps []*types.Dependency
+	for _, id := range ids {
+		phase, err := s.GetIssue(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get phase %s: %w", id, err)
+		}
+		if phase == nil {
+			continue
+		}
+		records, err := s.GetDependencyRecords(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get dependencies of phase %s: %w", id, err)
+		}
+		phases = append(phases, phase)
+		deps = append(deps, records...)
+	}
+	return types.OrderPhases(phases, deps), nil
+}
+
+// taskPhase returns the phase a task of the mission belongs to (the nearest
+// phase above it through parent-child links, "" if none) and whether the
+// task has to wait: phases run one at a time, the first phase in order
+// that isn't closed, and none runs while the mission is blocked
+func (s *VCStorage) taskPhase(ctx context.Context, taskID, missionID string, missionBlocked bool) (string, bool, error) {
+	var phaseID string
+	err := s.db.QueryRowContext(ctx, `
+		WITH RECURSIVE chain(id, depth) AS (
+		  SELECT ?, 0
+		  UNION ALL
+		  SELECT d.depends_on_id, c.depth + 1
+		  FROM dependencies d
+		  JOIN chain c ON d.issue_id = c.id
+		  WHERE d.type = ? AND c.depth < 10
+		)
+		SELECT m.issue_id
+		FROM chain c
+		JOIN vc_mission_state m ON m.issue_id = c.id
+		WHERE m.subtype = ? AND c.depth > 0
+		ORDER BY c.depth ASC
+		LIMIT 1
+	`, taskID, types.DepParentChild, types.SubtypePhase).Scan(&phaseID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to find phase of %s: %w", taskID, err)
+	}
+	if missionBlocked {
+		return phaseID, true, nil
+	}
+
+	phases, err := s.GetMissionPhases(ctx, missionID)
+	if err != nil {
+		return "", false, err
+	}
+	return phaseID, phaseID != executingPhase(phases), nil
+}
+
+// executingPhase returns the ID of the first phase in order that isn't
+// closed, "" once all are
+func executingPhase(phases []*types.Issue) string {
+	for _, phase := range phases {
+		if phase.Status != types.StatusClosed {
+			return phase.ID
+		}
+	}
+	return ""
+}
+
+// ======================================================================
+// MISSION PLAN APPROVAL
+// ======================================================================
+
+// ApproveMission approves a mission's plan
+func (s *VCStorage) ApproveMission(ctx context.Context, missionID, actor, comment string) error {
+	mission, err := s.reviewableMission(ctx, missionID)
+	if err != nil {
+		return err
+	}
+	if mission.ApprovedAt != nil {
+		return fmt.Errorf("mission %s was already approved by %s", missionID, mission.ApprovedBy)
+	}
+
+	if err := s.UpdateMissionState(ctx, missionID, map[string]interface{}{
+		"approved_at": time.Now(),
+		"approved_by": actor,
+	}); err != nil {
+		return err
+	}
+	if mission.Status == types.StatusBlocked {
+		if err := s.UpdateIssue(ctx, missionID, map[string]interface{}{"status": string(types.StatusOpen)}, actor); err != nil {
+			return fmt.Errorf("failed to reopen mission: %w", err)
+		}
+	}
+
+	s.recordMissionReview(ctx, events.MissionApprovalData{MissionID: missionID, Approved: true, Comment: comment, Actor: actor})
+	return nil
+}
+
+// RejectMission rejects a mission's plan and blocks the mission
+func (s *VCStorage) RejectMission(ctx context.Context, missionID, actor, comment string) error {
+	if strings.TrimSpace(comment) == "" {
+		return fmt.Errorf("rejecting mission %s requires a comment", missionID)
+	}
+	mission, err := s.reviewableMission(ctx, missionID)
+	if err != nil {
+		return err
+	}
+
+	if mission.Status != types.StatusBlocked {
+		if err := s.UpdateIssue(ctx, missionID, map[string]interface{}{"status": string(types.StatusBlocked)}, actor); err != nil {
+			return fmt.Errorf("failed to block mission: %w", err)
+		}
+	}
+	if err := s.UpdateMissionState(ctx, missionID, map[string]interface{}{
+		"approved_at": nil,
+		"approved_by": nil,
+	}); err != nil {
+		return err
+	}
+
+	s.recordMissionReview(ctx, events.MissionApprovalData{MissionID: missionID, Approved: false, Comment: comment, Actor: actor})
+	return nil
+}
+
+// reviewableMission returns the mission whose plan is being reviewed
+func (s *VCStorage) reviewableMission(ctx context.Context, missionID string) (*types.Mission, error) {
+	mission, err := s.GetMission(ctx, missionID)
+	if err != nil {
+		return nil, err
+	}
+	if mission.IssueSubtype != types.SubtypeMission {
+		return nil, fmt.Errorf("issue %s is a %s, only missions have plans to review", missionID, mission.IssueSubtype)
+	}
+	return mission, nil
+}
+
+// recordMissionReview adds the review comment and agent event. Both are
+// best-effort: the review itself is already stored.
+func (s *VCStorage) recordMissionReview(ctx context.Context, data events.MissionApprovalData) {
+	message, severity := "Plan approved", events.SeverityInfo
+	if !data.Approved {
+		message, severity = "Plan rejected", events.SeverityWarning
+	}
+
+	comment := fmt.Sprintf("**%s** by %s", message, data.Actor)
+	if data.Comment != "" {
+		comment += "\n\n" + data.Comment
+	}
+	if err := s.AddComment(ctx, data.MissionID, data.Actor, comment); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to add review comment to %s: %v\n", data.MissionID, err)
+	}
+
+	event, err := events.NewMissionApprovalEvent(data.MissionID, "", "", severity,
+		fmt.Sprintf("%s for mission %s by %s", message, data.MissionID, data.Actor), data)
+	if err == nil {
+		err = s.StoreAgentEvent(ctx, event)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to store %s event for %s: %v\n", message, data.MissionID, err)
+	}
+}
+
+// checkMissionApproved returns a *types.AwaitingApprovalError if the issue
+// is a mission, or work in one (the nearest mission above it through
+// parent-child links), whose plan still awaits approval
+func checkMissionApproved(ctx context.Context, conn *sql.Conn, issueID string) error {
+	var missionID string
+	var awaiting bool
+	err := conn.QueryRowContext(ctx, `
+		WITH RECURSIVE chain(id, depth) AS (
+		  SELECT ?, 0
+		  UNION ALL
+		  SELECT d.depends_on_id, c.depth + 1
+		  FROM dependencies d
+		  JOIN chain c ON d.issue_id = c.id
+		  WHERE d.type = ? AND c.depth < 10
+		)
+		SELECT m.issue_id, m.approval_required = 1 AND m.approved_at IS NULL
+		FROM chain c
+		JOIN vc_mission_state m ON m.issue_id = c.id
+		WHERE m.subtype = ?
+		ORDER BY c.depth ASC
+		LIMIT 1
+	`, issueID, types.DepParentChild, types.SubtypeMission).Scan(&missionID, &awaiting)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil // Not part of a mission
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check mission approval: %w", err)
+	}
+	if awaiting {
+		return &types.AwaitingApprovalError{IssueID: issueID, MissionID: missionID}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package beads
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// READY WORK ORDERING
+// ======================================================================
+
+// Beads orders ready work without a tiebreaker, so equal issues could come
+// back in any order. GetReadyWork orders the work in SQL instead, so the
+// limit can go into the query too, and every order ends with the issue ID.
+
+// readyWorkOrder returns the order filter asks for: OrderBy if set, else
+// the one matching SortPolicy, else the canonical priority order. The
+// second result is true for SortPolicyHybrid, which has no WorkOrder.
+func readyWorkOrder(filter types.WorkFilter) (types.WorkOrder, bool) {
+	if filter.OrderBy != "" {
+		return filter.OrderBy, false
+	}
+	switch filter.SortPolicy {
+	case types.SortPolicyOldest:
+		return types.WorkOrderAge, false
+	case types.SortPolicyHybrid:
+		return types.WorkOrderPriority, true
+	}
+	return types.WorkOrderPriority, false
+}
+
+// byPrioritySQL is the canonical ready-work order: priority ASC,
+// created_at ASC, id ASC
+const byPrioritySQL = "%[1]s.priority ASC, %[1]s.created_at ASC, %[1]s.id ASC"
+
+// readyWorkOrderSQL returns the ORDER BY terms for the order filter asks
+// for on an issues row aliased alias, with their arguments
+func readyWorkOrderSQL(alias string, filter types.WorkFilter) (string, []interface{}) {
+	order, hybrid := readyWorkOrder(filter)
+	if hybrid {
+		// Issues created in the last 48 hours first, by priority, then the
+		// older ones, oldest first
+		recent := time.Now().Add(-48 * time.Hour)
+		return fmt.Sprintf(`%[1]s.created_at <= ? ASC,
+			CASE WHEN %[1]s.created_at > ? THEN %[1]s.priority END ASC,
+			%[1]s.created_at ASC, %[1]s.priority ASC, %[1]s.id ASC`, alias), []interface{}{recent, recent}
+	}
+	switch order {
+	case types.WorkOrderAge:
+		return fmt.Sprintf("%[1]s.created_at ASC, "+byPrioritySQL, alias), nil
+	case types.WorkOrderEstimate:
+		// Issues without an estimate go last
+		return fmt.Sprintf("%[1]s.estimated_minutes IS NULL ASC, %[1]s.estimated_minutes ASC, "+byPrioritySQL, alias), nil
+	}
+	return fmt.Sprintf(byPrioritySQL, alias), nil
+}
@@ -0,0 +1,140 @@
+package beads
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestGetReadyWorkOrder(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewVCStorage(ctx, filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create VC storage: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	now := time.Now().Add(-time.Hour)
+	old := now.Add(-72 * time.Hour)
+	minutes := func(n int) *int { return &n }
+	// Same created_at for several issues, so only the ID separates them
+	fixtures := []struct {
+		name      string
+		priority  int
+		createdAt time.Time
+		estimate  *int
+	}{
+		{"a", 2, old, minutes(60)},
+		{"b", 1, now, minutes(30)},
+		{"c", 1, old, nil},
+		{"d", 2, old, minutes(15)},
+		{"e", 0, now, minutes(30)},
+		{"f", 1, now, nil},
+	}
+	names := make(map[string]string)
+	for _, f := range fixtures {
+		issue := &types.Issue{Title: f.name, Status: types.StatusOpen, Priority: f.priority, IssueType: types.TypeTask, EstimatedMinutes: f.estimate}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		if _, err := store.db.ExecContext(ctx, `UPDATE issues SET created_at = ? WHERE id = ?`, f.createdAt, issue.ID); err != nil {
+			t.Fatalf("Failed to set created_at: %v", err)
+		}
+		names[issue.ID] = f.name
+	}
+	// IDs are assigned in creation order, so equal issues keep it
+	sorted := slices.Sorted(func(yield func(string) bool) {
+		for id := range names {
+			if !yield(id) {
+				return
+			}
+		}
+	})
+	for i, id := range sorted {
+		if names[id] != fixtures[i].name {
+			t.Fatalf("IDs out of creation order: %v", sorted)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter types.WorkFilter
+		want   string
+	}{
+		{"default", types.WorkFilter{}, "ecbfad"},
+		{"priority policy", types.WorkFilter{SortPolicy: types.SortPolicyPriority}, "ecbfad"},
+		{"oldest policy", types.WorkFilter{SortPolicy: types.SortPolicyOldest}, "cadebf"},
+		{"hybrid policy", types.WorkFilter{SortPolicy: types.SortPolicyHybrid}, "ebfcad"},
+		{"age", types.WorkFilter{OrderBy: types.WorkOrderAge}, "cadebf"},
+		{"estimate", types.WorkFilter{OrderBy: types.WorkOrderEstimate}, "debacf"},
+		{"order over policy", types.WorkFilter{OrderBy: types.WorkOrderPriority, SortPolicy: types.SortPolicyOldest}, "ecbfad"},
+		{"limit", types.WorkFilter{OrderBy: types.WorkOrderAge, Limit: 2}, "ca"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ready, err := store.GetReadyWork(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("GetReadyWork failed: %v", err)
+			}
+			got := ""
+			for _, issue := range ready {
+				got += names[issue.ID]
+			}
+			if got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := store.GetReadyWork(ctx, types.WorkFilter{OrderBy: "size"}); err == nil {
+		t.Error("Expected an error for an unknown order")
+	}
+}
+
+func TestGetReadyBlockersOrder(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newCreateTestStore(t)
+
+	now := time.Now()
+	// Created in this order, but a and b share a priority and c is older
+	fixtures := []struct {
+		name      string
+		priority  int
+		createdAt time.Time
+	}{
+		{"a", 1, now},
+		{"b", 1, now},
+		{"c", 1, now.Add(-time.Hour)},
+		{"d", 0, now},
+	}
+	names := make(map[string]string)
+	for _, f := range fixtures {
+		issue := &types.Issue{Title: f.name, Status: types.StatusOpen, Priority: f.priority, IssueType: types.TypeBug}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		if err := store.AddLabel(ctx, issue.ID, "discovered:blocker", "test"); err != nil {
+			t.Fatalf("AddLabel failed: %v", err)
+		}
+		if _, err := store.db.ExecContext(ctx, `UPDATE issues SET created_at = ? WHERE id = ?`, f.createdAt, issue.ID); err != nil {
+			t.Fatalf("Failed to set created_at: %v", err)
+		}
+		names[issue.ID] = f.name
+	}
+
+	blockers, err := store.GetReadyBlockers(ctx, types.WorkFilter{Limit: 3})
+	if err != nil {
+		t.Fatalf("GetReadyBlockers failed: %v", err)
+	}
+	got := ""
+	for _, issue := range blockers {
+		got += names[issue.ID]
+	}
+	if got != "dca" {
+		t.Errorf("got %s, want dca", got)
+	}
+}
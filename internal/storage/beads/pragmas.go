@@ -0,0 +1,147 @@
+package beads
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"modernc.org/sqlite"
+)
+
+// ======================================================================
+// SQLITE CONNECTION SETTINGS
+// ======================================================================
+
+// DefaultBusyTimeout is how long a connection waits for a lock held by
+// another connection or process before failing with "database is locked".
+// Override it with VC_DB_BUSY_TIMEOUT (a Go duration such as "10s").
+const DefaultBusyTimeout = 5 * time.Second
+
+// busyTimeout returns the configured busy timeout
+func busyTimeout() time.Duration {
+	if value := os.Getenv("VC_DB_BUSY_TIMEOUT"); value != "" {
+		if timeout, err := time.ParseDuration(value); err == nil && timeout >= 0 {
+			return timeout
+		}
+		fmt.Fprintf(os.Stderr, "warning: ignoring invalid VC_DB_BUSY_TIMEOUT %q (using %s)\n", value, DefaultBusyTimeout)
+	}
+	return DefaultBusyTimeout
+}
+
+var registerPragmasOnce sync.Once
+
+// registerConnectionPragmas makes every new SQLite connection use VC's
+// settings. Beads owns the connection pool and opens connections on demand,
+// and busy_timeout, synchronous and foreign_keys are per connection, so they
+// are applied from a driver hook rather than once at startup:
+//   - journal_mode=WAL: readers don't block the writer and vice versa
+//   - busy_timeout: wait for locks instead of failing immediately
+//   - synchronous=NORMAL: with WAL, durable at checkpoints and much cheaper
+//     than FULL for the executor's stream of small writes
+//   - foreign_keys=ON: VC extension tables rely on cascades
+//   - query_only=ON: only for databases opened read-only (see compat.go)
+//
+// Files opened with a plainDSN get none of them.
+func registerConnectionPragmas() {
+	registerPragmasOnce.Do(func() {
+		sqlite.RegisterConnectionHook(func(conn sqlite.ExecQuerierContext, dsn string) error {
+			if isPlainDSN(dsn) {
+				return nil
+			}
+			pragmas := []string{
+				fmt.Sprintf("PRAGMA busy_timeout = %d", busyTimeout().Milliseconds()),
+				"PRAGMA synchronous = NORMAL",
+				"PRAGMA foreign_keys = ON",
+			}
+			// WAL needs a file; shared in-memory databases stay in memory mode
+			if !isInMemoryPath(dsn) {
+				pragmas = append([]string{"PRAGMA journal_mode = WAL"}, pragmas...)
+			}
+			// Read-only databases (VC_BEADS_COMPAT=warn, SetReadOnly)
+			if isReadOnlyDSN(dsn) {
+				pragmas = append(pragmas, "PRAGMA query_only = ON")
+			}
+			for _, pragma := range pragmas {
+				if _, err := conn.ExecContext(context.Background(), pragma, []driver.NamedValue{}); err != nil {
+					return fmt.Errorf("%s: %w", pragma, err)
+				}
+			}
+			return nil
+		})
+	})
+}
+
+// plainDSN returns a DSN that opens path with SQLite's access mode ("ro"
+// or "rwc") and skips VC's connection pragmas. Backups are written and
+// inspected through it: switching them to WAL would leave -wal and -shm
+// files next to a file that is only being read.
+func plainDSN(path, mode string) string {
+	return (&url.URL{Scheme: "file", OmitHost: true, Path: path, RawQuery: "mode=" + mode}).String()
+}
+
+// isPlainDSN reports whether dsn came from plainDSN
+func isPlainDSN(dsn string) bool {
+	return strings.HasPrefix(dsn, "file:") && (strings.HasSuffix(dsn, "?mode=ro") || strings.HasSuffix(dsn, "?mode=rwc"))
+}
+
+// connectionSettings are the pragma values a connection ended up with
+type connectionSettings struct {
+	JournalMode   string
+	BusyTimeoutMs int64
+	Synchronous   int // 0 OFF, 1 NORMAL, 2 FULL, 3 EXTRA
+	ForeignKeys   bool
+}
+
+// readConnectionSettings reads the pragmas back from one pooled connection
+func readConnectionSettings(ctx context.Context, conn *sql.Conn) (*connectionSettings, error) {
+	var settings connectionSettings
+	for _, p := range []struct {
+		pragma string
+		dest   interface{}
+	}{
+		{"journal_mode", &settings.JournalMode},
+		{"busy_timeout", &settings.BusyTimeoutMs},
+		{"synchronous", &settings.Synchronous},
+		{"foreign_keys", &settings.ForeignKeys},
+	} {
+		if err := conn.QueryRowContext(ctx, "PRAGMA "+p.pragma).Scan(p.dest); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", p.pragma, err)
+		}
+	}
+	return &settings, nil
+}
+
+// verifyConnectionSettings checks that the connection pragmas took effect.
+// SQLite silently keeps the old journal mode when the filesystem can't do WAL
+// (common on network mounts), so that only warns; the per-connection
+// settings are errors, since the hook should always have set them.
+func verifyConnectionSettings(ctx context.Context, conn *sql.Conn, dbPath string) error {
+	settings, err := readConnectionSettings(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	if want := busyTimeout().Milliseconds(); settings.BusyTimeoutMs != want {
+		return fmt.Errorf("busy_timeout is %dms, expected %dms", settings.BusyTimeoutMs, want)
+	}
+	if settings.Synchronous != 1 {
+		return fmt.Errorf("synchronous is %d, expected 1 (NORMAL)", settings.Synchronous)
+	}
+	if !settings.ForeignKeys {
+		return fmt.Errorf("foreign_keys is off")
+	}
+
+	if !isInMemoryPath(dbPath) && !strings.EqualFold(settings.JournalMode, "wal") {
+		fmt.Fprintf(os.Stderr, "warning: SQLite WAL mode is unavailable for %s (journal_mode=%s); "+
+			"readers and writers will block each other and may hit \"database is locked\". "+
+			"Network filesystems often can't support WAL; keep the database on a local disk.\n",
+			dbPath, settings.JournalMode)
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package beads
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestConnectionPragmas(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newCreateTestStore(t)
+
+	// Hold several connections at once so the pool has to open new ones:
+	// every one of them must carry the settings, not just the first
+	var conns []interface{ Close() error }
+	defer func() {
+		for _, conn := range conns {
+			_ = conn.Close()
+		}
+	}()
+	for i := 0; i < 4; i++ {
+		conn, err := store.db.Conn(ctx)
+		if err != nil {
+			t.Fatalf("Failed to get connection %d: %v", i, err)
+		}
+		conns = append(conns, conn)
+
+		settings, err := readConnectionSettings(ctx, conn)
+		if err != nil {
+			t.Fatalf("readConnectionSettings failed: %v", err)
+		}
+		want := connectionSettings{JournalMode: "wal", BusyTimeoutMs: DefaultBusyTimeout.Milliseconds(), Synchronous: 1, ForeignKeys: true}
+		if *settings != want {
+			t.Errorf("Connection %d: settings = %+v, want %+v", i, *settings, want)
+		}
+	}
+}
+
+func TestBusyTimeoutFromEnvironment(t *testing.T) {
+	t.Setenv("VC_DB_BUSY_TIMEOUT", "1500ms")
+	ctx := context.Background()
+	store, err := NewVCStorage(ctx, filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create VC storage: %v", err)
+	}
+	defer store.Close()
+
+	conn, err := store.db.Conn(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get connection: %v", err)
+	}
+	defer conn.Close()
+	settings, err := readConnectionSettings(ctx, conn)
+	if err != nil {
+		t.Fatalf("readConnectionSettings failed: %v", err)
+	}
+	if settings.BusyTimeoutMs != 1500 {
+		t.Errorf("Expected a busy timeout of 1500ms, got %d", settings.BusyTimeoutMs)
+	}
+
+	t.Setenv("VC_DB_BUSY_TIMEOUT", "soon")
+	if got := busyTimeout(); got != DefaultBusyTimeout {
+		t.Errorf("Expected an invalid value to fall back to %s, got %s", DefaultBusyTimeout, got)
+	}
+}
+
+// TestConcurrentReadersAndWriters mirrors an executor streaming events while
+// a human edits issues and runs 'vc list': two writers and several readers,
+// each on its own storage as separate processes would be. Without the busy
+// timeout the writers fail with "database is locked" (run with
+// VC_DB_BUSY_TIMEOUT=0 to see it).
+func TestConcurrentReadersAndWriters(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping concurrency stress test in short mode")
+	}
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "stress.db")
+
+	open := func() *VCStorage {
+		t.Helper()
+		store, err := NewVCStorage(ctx, dbPath)
+		if err != nil {
+			t.Fatalf("Failed to open VC storage: %v", err)
+		}
+		t.Cleanup(func() { _ = store.Close() })
+		return store
+	}
+
+	writer := open()
+	for i := 0; i < 50; i++ {
+		issue := &types.Issue{Title: fmt.Sprintf("Issue %d", i), Status: types.StatusOpen, Priority: i % 4, IssueType: types.TypeTask}
+		if err := writer.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+	}
+
+	const readers = 6
+	editor := open()
+	readerStores := make([]*VCStorage, readers)
+	for r := range readerStores {
+		readerStores[r] = open()
+	}
+
+	deadline := time.Now().Add(1500 * time.Millisecond)
+	var writes, reads atomic.Int64
+	errs := make(chan error, readers+2)
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; time.Now().Before(deadline); i++ {
+			updates := map[string]interface{}{"notes": fmt.Sprintf("edit %d", i)}
+			if err := editor.UpdateIssue(ctx, "vc-1", updates, "human"); err != nil {
+				errs <- fmt.Errorf("editor: %w", err)
+				return
+			}
+			writes.Add(1)
+		}
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for time.Now().Before(deadline) {
+			event := &events.AgentEvent{
+				Type: events.EventTypeProgress, Timestamp: time.Now(), IssueID: "vc-1",
+				Severity: events.SeverityInfo, Message: "still working",
+			}
+			if err := writer.StoreAgentEvent(ctx, event); err != nil {
+				errs <- fmt.Errorf("writer: %w", err)
+				return
+			}
+			writes.Add(1)
+		}
+	}()
+
+	for r, reader := range readerStores {
+		wg.Add(1)
+		go func(r int, reader *VCStorage) {
+			defer wg.Done()
+			for time.Now().Before(deadline) {
+				if _, err := reader.SearchIssues(ctx, "", types.IssueFilter{Limit: 20}); err != nil {
+					errs <- fmt.Errorf("reader %d: %w", r, err)
+					return
+				}
+				reads.Add(1)
+			}
+		}(r, reader)
+	}
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+	if writes.Load() == 0 || reads.Load() == 0 {
+		t.Errorf("Expected progress on both sides, got %d writes and %d reads", writes.Load(), reads.Load())
+	}
+}
@@ -0,0 +1,192 @@
+package beads
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// PROJECTS (VC extension tables)
+// ======================================================================
+
+// Issues of the default project have no vc_issue_projects row, so databases
+// from before projects existed, and issues created by bd, are in it without
+// a migration. Its prefix is the issue_prefix config, as before.
+
+// issueProjectSQL is the project of the issues row aliased %s
+const issueProjectSQL = `COALESCE((SELECT p.project FROM vc_issue_projects p WHERE p.issue_id = %s.id), '` + types.DefaultProject + `')`
+
+// CreateProject adds a project with its own ID prefix
+func (s *VCStorage) CreateProject(ctx context.Context, project *types.Project) error {
+	if err := project.Validate(); err != nil {
+		return fmt.Errorf("invalid project: %w", err)
+	}
+	if project.Name == types.DefaultProject {
+		return fmt.Errorf("project %s already exists", project.Name)
+	}
+	defaultPrefix, err := s.GetConfig(ctx, "issue_prefix")
+	if err != nil {
+		return fmt.Errorf("failed to get issue_prefix config: %w", err)
+	}
+	if project.Prefix == defaultPrefix {
+		return fmt.Errorf("prefix %s is used by project %s", project.Prefix, types.DefaultProject)
+	}
+
+	var taken string
+	err = s.db.QueryRowContext(ctx, `
+		SELECT CASE WHEN name = ? THEN 'name' ELSE 'prefix' END
+		FROM vc_projects WHERE name = ? OR prefix = ?
+	`, project.Name, project.Name, project.Prefix).Scan(&taken)
+	switch {
+	case err == nil && taken == "name":
+		return fmt.Errorf("project %s already exists", project.Name)
+	case err == nil:
+		return fmt.Errorf("prefix %s is used by another project", project.Prefix)
+	case err != sql.ErrNoRows:
+		return fmt.Errorf("failed to check project %s: %w", project.Name, err)
+	}
+
+	createdAt := time.Now()
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO vc_projects (name, prefix, root, description, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, project.Name, project.Prefix, project.Root, project.Description, createdAt)
+	if err != nil {
+		return fmt.Errorf("failed to create project %s: %w", project.Name, err)
+	}
+	project.CreatedAt = createdAt
+	return nil
+}
+
+// ListProjects returns the default project first, then the others by name
+func (s *VCStorage) ListProjects(ctx context.Context) ([]*types.Project, error) {
+	defaultPrefix, err := s.GetConfig(ctx, "issue_prefix")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue_prefix config: %w", err)
+	}
+	projects := []*types.Project{{Name: types.DefaultProject, Prefix: defaultPrefix}}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT name, prefix, root, description, created_at
+		FROM vc_projects
+		ORDER BY name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list projects: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var p types.Project
+		if err := rows.Scan(&p.Name, &p.Prefix, &p.Root, &p.Description, &p.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan project: %w", err)
+		}
+		projects = append(projects, &p)
+	}
+	return projects, rows.Err()
+}
+
+// projectPrefix returns the ID prefix of a project ("" for the default one)
+func projectPrefix(ctx context.Context, conn *sql.Conn, project string) (string, error) {
+	if project == "" || project == types.DefaultProject {
+		var prefix string
+		err := conn.QueryRowContext(ctx, `SELECT value FROM config WHERE key = ?`, "issue_prefix").Scan(&prefix)
+		if err == sql.ErrNoRows || (err == nil && prefix == "") {
+			return "", fmt.Errorf("database not initialized: issue_prefix config is missing")
+		} else if err != nil {
+			return "", fmt.Errorf("failed to get config: %w", err)
+		}
+		return prefix, nil
+	}
+
+	var prefix string
+	err := conn.QueryRowContext(ctx, `SELECT prefix FROM vc_projects WHERE name = ?`, project).Scan(&prefix)
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("unknown project %s", project)
+	} else if err != nil {
+		return "", fmt.Errorf("failed to get project %s: %w", project, err)
+	}
+	return prefix, nil
+}
+
+// assignProject puts a new issue in its project. The caller owns the
+// transaction.
+func assignProject(ctx context.Context, conn *sql.Conn, issueID, project string) error {
+	if project == "" || project == types.DefaultProject {
+		return nil
+	}
+	if _, err := projectPrefix(ctx, conn, project); err != nil {
+		return err
+	}
+	if _, err := conn.ExecContext(ctx, `
+		INSERT INTO vc_issue_projects (issue_id, project) VALUES (?, ?)
+	`, issueID, project); err != nil {
+		return fmt.Errorf("failed to assign %s to project %s: %w", issueID, project, err)
+	}
+	return nil
+}
+
+// projectClause is the condition on an issues row aliased alias that
+// keeps only the project's issues
+func projectClause(alias, project string) (string, []interface{}) {
+	if project == types.DefaultProject {
+		return fmt.Sprintf("%s.id NOT IN (SELECT issue_id FROM vc_issue_projects)", alias), nil
+	}
+	return fmt.Sprintf("%s.id IN (SELECT issue_id FROM vc_issue_projects WHERE project = ?)", alias), []interface{}{project}
+}
+
+// issueProjects returns the project of each issue
+func (s *VCStorage) issueProjects(ctx context.Context, ids []string) (map[string]string, error) {
+	projects := make(map[string]string, len(ids))
+	for _, id := range ids {
+		projects[id] = types.DefaultProject
+	}
+	if len(ids) == 0 {
+		return projects, nil
+	}
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	// #nosec G201 - only placeholders are interpolated
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT issue_id, project FROM vc_issue_projects WHERE issue_id IN (%s)
+	`, strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue projects: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, project string
+		if err := rows.Scan(&id, &project); err != nil {
+			return nil, fmt.Errorf("failed to scan issue project: %w", err)
+		}
+		projects[id] = project
+	}
+	return projects, rows.Err()
+}
+
+// withProjects sets the project of each issue and, unless project is "",
+// keeps only the project's issues
+func (s *VCStorage) withProjects(ctx context.Context, issues []*types.Issue, project string) ([]*types.Issue, error) {
+	ids := make([]string, len(issues))
+	for i, issue := range issues {
+		ids[i] = issue.ID
+	}
+	projects, err := s.issueProjects(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	kept := issues[:0]
+	for _, issue := range issues {
+		issue.Project = projects[issue.ID]
+		if project == "" || issue.Project == project {
+			kept = append(kept, issue)
+		}
+	}
+	return kept, nil
+}
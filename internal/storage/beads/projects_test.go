@@ -0,0 +1,54 @@
+package beads
+
+import (
+	"context"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// TestProjectIDSequences checks that each project numbers its issues from
+// its own prefix, and that issues from before the project existed stay in
+// the default project
+func TestProjectIDSequences(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewVCStorage(ctx, ":memory:")
+	if err != nil {
+		t.Fatalf("NewVCStorage failed: %v", err)
+	}
+	defer store.Close()
+
+	create := func(project string) *types.Issue {
+		t.Helper()
+		issue := &types.Issue{Title: "Task", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, Project: project}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue(project=%q) failed: %v", project, err)
+		}
+		return issue
+	}
+
+	existing := create("")
+	if err := store.CreateProject(ctx, &types.Project{Name: "api", Prefix: "api"}); err != nil {
+		t.Fatalf("CreateProject failed: %v", err)
+	}
+	if err := store.CreateProject(ctx, &types.Project{Name: "clash", Prefix: "vc"}); err == nil {
+		t.Error("Expected the default project's prefix to be refused")
+	}
+
+	got := []string{create("api").ID, create("").ID, create("api").ID, create(types.DefaultProject).ID}
+	want := []string{"api-1", "vc-2", "api-2", "vc-3"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Issue %d: got ID %s, want %s", i, got[i], want[i])
+		}
+	}
+
+	issue, err := store.GetIssue(ctx, existing.ID)
+	if err != nil || issue.Project != types.DefaultProject {
+		t.Errorf("Expected %s in the default project, got %+v, %v", existing.ID, issue, err)
+	}
+	n, err := store.CountIssues(ctx, "", types.IssueFilter{Project: "api"})
+	if err != nil || n != 2 {
+		t.Errorf("Expected 2 api issues, got %d, %v", n, err)
+	}
+}
@@ -0,0 +1,272 @@
+package beads
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// ISSUE QUERIES (filtering, ordering, pagination)
+// ======================================================================
+
+// sortableIssueColumns whitelists IssueFilter.OrderBy. OrderBy is spliced
+// into the SQL, so only these exact column names are accepted.
+var sortableIssueColumns = func() map[string]bool {
+	columns := make(map[string]bool, len(types.SortableIssueColumns))
+	for _, column := range types.SortableIssueColumns {
+		columns[column] = true
+	}
+	return columns
+}()
+
+// SearchIssues searches issues by text (title, description or ID) and filter.
+// Archived issues are left out unless filter.IncludeArchived is set, and the
+// SYSTEM pseudo-issue unless filter.IncludeSystem is. Results
+// follow filter.OrderBy (default: priority, then newest first) and can be
+// paged with Limit and Offset.
+//
+// The filters match Beads' SearchIssues; the query runs here so ordering,
+// paging and the archive check happen in SQL.
+func (s *VCStorage) SearchIssues(ctx context.Context, query string, filter types.IssueFilter) ([]*types.Issue, error) {
+	querySQL, args, err := buildIssueSearchQuery(query, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, querySQL, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search issues: %w", err)
+	}
+	defer rows.Close()
+
+	issues := []*types.Issue{}
+	for rows.Next() {
+		var issue types.Issue
+		var closedAt sql.NullTime
+		var assignee sql.NullString
+		var estimatedMinutes sql.NullInt64
+		if err := rows.Scan(
+			&issue.ID, &issue.Title, &issue.Description, &issue.Design,
+			&issue.AcceptanceCriteria, &issue.Notes, &issue.Status,
+			&issue.Priority, &issue.IssueType, &assignee, &estimatedMinutes,
+			&issue.CreatedAt, &issue.UpdatedAt, &closedAt, &issue.Archived,
+			&issue.Project,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan issue: %w", err)
+		}
+
+		if closedAt.Valid {
+			issue.ClosedAt = &closedAt.Time
+		}
+		if assignee.Valid {
+			issue.Assignee = assignee.String
+		}
+		if estimatedMinutes.Valid {
+			minutes := int(estimatedMinutes.Int64)
+			issue.EstimatedMinutes = &minutes
+		}
+		issues = append(issues, &issue)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating issues: %w", err)
+	}
+	return issues, nil
+}
+
+// buildIssueSearchQuery builds the SQL and arguments for SearchIssues
+func buildIssueSearchQuery(query string, filter types.IssueFilter) (string, []interface{}, error) {
+	orderSQL, err := issueOrderClause(filter)
+	if err != nil {
+		return "", nil, err
+	}
+	whereSQL, args := issueSearchWhere(query, filter)
+
+	pageSQL := ""
+	if filter.Limit > 0 || filter.Offset > 0 {
+		limit := filter.Limit
+		if limit <= 0 {
+			limit = -1 // SQLite needs a LIMIT for OFFSET; -1 means none
+		}
+		pageSQL = "LIMIT ? OFFSET ?"
+		args = append(args, limit, filter.Offset)
+	}
+
+	// #nosec G201 - where clauses use placeholders, order columns are whitelisted
+	querySQL := fmt.Sprintf(`
+		SELECT id, title, description, design, acceptance_criteria, notes,
+		       status, priority, issue_type, assignee, estimated_minutes,
+		       created_at, updated_at, closed_at,
+		       EXISTS (SELECT 1 FROM vc_archived_issues a WHERE a.issue_id = issues.id),
+		       %s
+		FROM issues
+		%s
+		%s
+		%s
+	`, fmt.Sprintf(issueProjectSQL, "issues"), whereSQL, orderSQL, pageSQL)
+	return querySQL, args, nil
+}
+
+// CountIssues counts the issues SearchIssues would return for query and
+// filter, ignoring Limit and Offset (the total for pagination)
+func (s *VCStorage) CountIssues(ctx context.Context, query string, filter types.IssueFilter) (int, error) {
+	whereSQL, args := issueSearchWhere(query, filter)
+
+	var count int
+	// #nosec G201 - where clauses use placeholders
+	err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM issues "+whereSQL, args...).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count issues: %w", err)
+	}
+	return count, nil
+}
+
+// issueGroupColumns maps the groupings of CountIssuesByGroup to their SQL
+// expression. The expressions are spliced into the SQL, so only these are
+// accepted.
+var issueGroupColumns = map[string]string{
+	types.GroupByStatus:      "status",
+	types.GroupByPriority:    "CAST(priority AS TEXT)",
+	types.GroupByType:        "issue_type",
+	types.GroupByAssignee:    "COALESCE(assignee, '')",
+	types.GroupByLabelPrefix: "COALESCE(prefixes.prefix, '')",
+}
+
+// labelPrefixJoin joins each issue to the distinct prefixes of its labels
+// (the part before the first colon), or to NULL if it has none
+const labelPrefixJoin = `
+	LEFT JOIN (
+		SELECT DISTINCT issue_id, substr(label, 1, instr(label, ':') - 1) AS prefix
+		FROM labels WHERE instr(label, ':') > 1
+	) prefixes ON prefixes.issue_id = issues.id`
+
+// CountIssuesByGroup counts the issues SearchIssues would return for query
+// and filter per value of groupBy, in a single GROUP BY
+func (s *VCStorage) CountIssuesByGroup(ctx context.Context, query string, filter types.IssueFilter, groupBy string) (map[string]int, error) {
+	column, ok := issueGroupColumns[groupBy]
+	if !ok {
+		return nil, fmt.Errorf("invalid grouping %q (valid: %s)", groupBy, strings.Join(types.IssueGroupings, ", "))
+	}
+	join := ""
+	if groupBy == types.GroupByLabelPrefix {
+		join = labelPrefixJoin
+	}
+	whereSQL, args := issueSearchWhere(query, filter)
+
+	// #nosec G201 - where clauses use placeholders, group columns are whitelisted
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT %s, COUNT(*) FROM issues %s %s GROUP BY 1
+	`, column, join, whereSQL), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count issues by %s: %w", groupBy, err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var key string
+		var count int
+		if err := rows.Scan(&key, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan issue count: %w", err)
+		}
+		counts[key] = count
+	}
+	return counts, rows.Err()
+}
+
+// issueSearchWhere builds the parameterized WHERE clause shared by
+// SearchIssues and CountIssues
+func issueSearchWhere(query string, filter types.IssueFilter) (string, []interface{}) {
+	var clauses []string
+	var args []interface{}
+
+	if query != "" {
+		pattern := "%" + query + "%"
+		clauses = append(clauses, "(title LIKE ? OR description LIKE ? OR id LIKE ?)")
+		args = append(args, pattern, pattern, pattern)
+	}
+	if filter.Status != nil {
+		clauses = append(clauses, "status = ?")
+		args = append(args, string(*filter.Status))
+	}
+	if filter.Priority != nil {
+		clauses = append(clauses, "priority = ?")
+		args = append(args, *filter.Priority)
+	}
+	issueType := filter.Type
+	if issueType == nil {
+		issueType = filter.IssueType
+	}
+	if issueType != nil {
+		clauses = append(clauses, "issue_type = ?")
+		args = append(args, string(*issueType))
+	}
+	if filter.Assignee != nil {
+		clauses = append(clauses, "assignee = ?")
+		args = append(args, *filter.Assignee)
+	}
+	// Issues must have ALL the labels
+	for _, label := range filter.Labels {
+		clauses = append(clauses, "id IN (SELECT issue_id FROM labels WHERE label = ?)")
+		args = append(args, label)
+	}
+	// Issues must have ALL the metadata values. Keys are sorted so equal
+	// filters build equal SQL.
+	for _, key := range sortedKeys(filter.MetaEquals) {
+		value, err := types.ParseMetaValue(filter.MetaEquals[key])
+		if err != nil {
+			value = nil // Matches nothing
+		}
+		clauses = append(clauses, "id IN (SELECT issue_id FROM vc_issue_metadata WHERE key = ? AND value = ?)")
+		args = append(args, key, string(value))
+	}
+	if !filter.IncludeArchived {
+		clauses = append(clauses, "id NOT IN (SELECT issue_id FROM vc_archived_issues)")
+	}
+	if !filter.IncludeSystem {
+		clauses = append(clauses, "id != ?")
+		args = append(args, types.SystemIssueID)
+	}
+	if filter.Project != "" {
+		clause, projectArgs := projectClause("issues", filter.Project)
+		clauses = append(clauses, clause)
+		args = append(args, projectArgs...)
+	}
+
+	if len(clauses) == 0 {
+		return "", args
+	}
+	return "WHERE " + strings.Join(clauses, " AND "), args
+}
+
+// issueOrderClause returns the ORDER BY for filter, rejecting columns that
+// aren't whitelisted. The ID tiebreaker keeps pages stable.
+func issueOrderClause(filter types.IssueFilter) (string, error) {
+	if filter.OrderBy == "" {
+		return "ORDER BY priority ASC, created_at DESC, id ASC", nil
+	}
+	if !sortableIssueColumns[filter.OrderBy] {
+		return "", fmt.Errorf("invalid order by column %q (sortable: %s)",
+			filter.OrderBy, strings.Join(types.SortableIssueColumns, ", "))
+	}
+
+	direction := "ASC"
+	if filter.Descending {
+		direction = "DESC"
+	}
+	return fmt.Sprintf("ORDER BY %s %s, id %s", filter.OrderBy, direction, direction), nil
+}
+
+// sortedKeys returns the map's keys in order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,213 @@
+package beads
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestSearchIssuesOrderingAndPaging(t *testing.T) {
+	ctx := context.Background()
+	store, parent := newCreateTestStore(t)
+
+	titles := []string{"delta", "alpha", "echo", "charlie", "bravo"}
+	for i, title := range titles {
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: i % 3, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+	}
+	open := types.StatusOpen
+
+	// Zero values keep the default order: priority first
+	issues, err := store.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		t.Fatalf("SearchIssues failed: %v", err)
+	}
+	if len(issues) != 6 {
+		t.Fatalf("Expected 6 issues, got %d", len(issues))
+	}
+	for i := 1; i < len(issues); i++ {
+		if issues[i].Priority < issues[i-1].Priority {
+			t.Errorf("Default order is not by priority: %d after %d", issues[i].Priority, issues[i-1].Priority)
+		}
+	}
+
+	titleOrder := func(filter types.IssueFilter) string {
+		t.Helper()
+		issues, err := store.SearchIssues(ctx, "", filter)
+		if err != nil {
+			t.Fatalf("SearchIssues(%+v) failed: %v", filter, err)
+		}
+		var got []string
+		for _, issue := range issues {
+			got = append(got, issue.Title)
+		}
+		return strings.Join(got, ",")
+	}
+	if got := titleOrder(types.IssueFilter{OrderBy: "title"}); got != "Parent,alpha,bravo,charlie,delta,echo" {
+		t.Errorf("Unexpected ascending order: %s", got)
+	}
+	if got := titleOrder(types.IssueFilter{OrderBy: "title", Descending: true}); got != "echo,delta,charlie,bravo,alpha,Parent" {
+		t.Errorf("Unexpected descending order: %s", got)
+	}
+
+	// Pages are disjoint and together cover every match
+	var pages []string
+	for offset := 0; offset < 6; offset += 4 {
+		pages = append(pages, titleOrder(types.IssueFilter{OrderBy: "title", Limit: 4, Offset: offset}))
+	}
+	if strings.Join(pages, "|") != "Parent,alpha,bravo,charlie|delta,echo" {
+		t.Errorf("Unexpected pages: %v", pages)
+	}
+	if got := titleOrder(types.IssueFilter{OrderBy: "title", Offset: 4}); got != "delta,echo" {
+		t.Errorf("Offset without limit: got %s", got)
+	}
+
+	// Counts ignore paging but honor filters and archiving
+	count, err := store.CountIssues(ctx, "", types.IssueFilter{Limit: 2, Offset: 1})
+	if err != nil || count != 6 {
+		t.Errorf("Expected 6 issues, got %d (%v)", count, err)
+	}
+	priority := 0
+	count, err = store.CountIssues(ctx, "", types.IssueFilter{Status: &open, Priority: &priority})
+	if err != nil || count != 2 {
+		t.Errorf("Expected 2 open P0 issues, got %d (%v)", count, err)
+	}
+	count, err = store.CountIssues(ctx, "ar", types.IssueFilter{})
+	if err != nil || count != 2 {
+		t.Errorf("Expected 2 issues matching \"ar\", got %d (%v)", count, err)
+	}
+	if err := store.ArchiveIssue(ctx, parent.ID, "test"); err != nil {
+		t.Fatalf("ArchiveIssue failed: %v", err)
+	}
+	count, err = store.CountIssues(ctx, "", types.IssueFilter{})
+	if err != nil || count != 5 {
+		t.Errorf("Expected 5 visible issues, got %d (%v)", count, err)
+	}
+	count, err = store.CountIssues(ctx, "", types.IssueFilter{IncludeArchived: true})
+	if err != nil || count != 6 {
+		t.Errorf("Expected 6 issues including archived, got %d (%v)", count, err)
+	}
+}
+
+func TestSearchIssuesRejectsUnknownOrderBy(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newCreateTestStore(t)
+
+	for _, orderBy := range []string{"bogus", "priority; DROP TABLE issues", "priority DESC", "(SELECT 1)"} {
+		if _, err := store.SearchIssues(ctx, "", types.IssueFilter{OrderBy: orderBy}); err == nil {
+			t.Errorf("Expected OrderBy %q to be rejected", orderBy)
+		}
+	}
+	if n := countRows(t, store, `SELECT COUNT(*) FROM issues WHERE id != ?`, types.SystemIssueID); n != 1 {
+		t.Errorf("Expected the issues table to be intact, got %d rows", n)
+	}
+}
+
+// TestSearchIssuesUsesIndex checks the query plan of the common "open issues
+// by priority" page on a 100k-issue database: it must be served from an index
+// instead of scanning the whole issues table
+func TestSearchIssuesUsesIndex(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping 100k-issue fixture in short mode")
+	}
+	ctx := context.Background()
+	store, err := NewVCStorage(ctx, filepath.Join(t.TempDir(), "big.db"))
+	if err != nil {
+		t.Fatalf("Failed to create VC storage: %v", err)
+	}
+	defer store.Close()
+
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("BeginTx failed: %v", err)
+	}
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO issues (id, title, status, priority, issue_type, created_at, updated_at, closed_at)
+		VALUES (?, ?, ?, ?, 'task', ?, ?, ?)
+	`)
+	if err != nil {
+		t.Fatalf("Prepare failed: %v", err)
+	}
+	statuses := []types.Status{types.StatusOpen, types.StatusInProgress, types.StatusBlocked, types.StatusClosed}
+	base := time.Now().Add(-100000 * time.Minute)
+	for i := 0; i < 100000; i++ {
+		status := statuses[i%len(statuses)]
+		created := base.Add(time.Duration(i) * time.Minute)
+		var closedAt interface{}
+		if status == types.StatusClosed {
+			closedAt = created
+		}
+		if _, err := stmt.ExecContext(ctx, fmt.Sprintf("vc-%d", i+1), fmt.Sprintf("Issue %d", i), status, i%5, created, created, closedAt); err != nil {
+			t.Fatalf("Insert %d failed: %v", i, err)
+		}
+	}
+	_ = stmt.Close()
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+	if _, err := store.db.ExecContext(ctx, "ANALYZE"); err != nil {
+		t.Fatalf("ANALYZE failed: %v", err)
+	}
+
+	open := types.StatusOpen
+	filters := map[string]types.IssueFilter{
+		"default order":      {Status: &open, Limit: 50},
+		"by priority, paged": {Status: &open, OrderBy: "priority", Limit: 50, Offset: 100},
+	}
+	for name, filter := range filters {
+		t.Run(name, func(t *testing.T) {
+			query, args, err := buildIssueSearchQuery("", filter)
+			if err != nil {
+				t.Fatalf("buildIssueSearchQuery failed: %v", err)
+			}
+			rows, err := store.db.QueryContext(ctx, "EXPLAIN QUERY PLAN "+query, args...)
+			if err != nil {
+				t.Fatalf("EXPLAIN failed: %v", err)
+			}
+			defer rows.Close()
+
+			var plan []string
+			for rows.Next() {
+				var id, parent, unused int
+				var detail string
+				if err := rows.Scan(&id, &parent, &unused, &detail); err != nil {
+					t.Fatalf("Scan failed: %v", err)
+				}
+				plan = append(plan, detail)
+			}
+
+			usesIndex := false
+			for _, step := range plan {
+				if strings.HasPrefix(step, "SCAN issues") && !strings.Contains(step, "INDEX") {
+					t.Errorf("Full table scan of issues: %q (plan: %v)", step, plan)
+				}
+				if strings.HasPrefix(step, "SEARCH issues USING") && strings.Contains(step, "INDEX") {
+					usesIndex = true
+				}
+			}
+			if !usesIndex {
+				t.Errorf("Expected an index search on issues, plan: %v", plan)
+			}
+		})
+	}
+
+	// And the page itself is right
+	issues, err := store.SearchIssues(ctx, "", filters["default order"])
+	if err != nil {
+		t.Fatalf("SearchIssues failed: %v", err)
+	}
+	if len(issues) != 50 || issues[0].Status != types.StatusOpen || issues[0].Priority != 0 {
+		t.Errorf("Unexpected first page: %d issues, first %+v", len(issues), issues[0])
+	}
+	total, err := store.CountIssues(ctx, "", types.IssueFilter{Status: &open})
+	if err != nil || total != 25000 {
+		t.Errorf("Expected 25000 open issues, got %d (%v)", total, err)
+	}
+}
@@ -0,0 +1,276 @@
+package beads
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	beadsLib "github.com/steveyegge/beads"
+	"github.com/steveyegge/vc/internal/events"
+)
+
+// ======================================================================
+// SLOW QUERY LOGGING
+// ======================================================================
+
+// SlowQueryConfigKey is the database config key of the slow query
+// threshold in milliseconds. VC_SLOW_QUERY_MS overrides it. Zero, the
+// default, leaves queries untimed.
+const SlowQueryConfigKey = "slow_query_ms"
+
+// maxStatementLen is how much of a statement is logged and counted
+const maxStatementLen = 160
+
+// slowQueryThreshold returns the threshold from VC_SLOW_QUERY_MS, else from
+// the config value; zero turns timing off
+func slowQueryThreshold(configValue string) time.Duration {
+	value, source := os.Getenv("VC_SLOW_QUERY_MS"), "VC_SLOW_QUERY_MS"
+	if value == "" {
+		value, source = configValue, SlowQueryConfigKey
+	}
+	if value == "" {
+		return 0
+	}
+	ms, err := strconv.Atoi(value)
+	if err != nil || ms < 0 {
+		fmt.Fprintf(os.Stderr, "warning: ignoring invalid %s %q (slow query logging off)\n", source, value)
+		return 0
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+// queryTimer times the queries VCStorage issues, logs those taking at least
+// threshold and counts them per statement. Only the statement text is ever
+// logged or kept, never its parameters, which hold issue text.
+type queryTimer struct {
+	threshold time.Duration
+	log       io.Writer
+
+	mu         sync.Mutex
+	queries    int64
+	statements map[string]*events.SlowStatement
+}
+
+// newQueryTimer returns a timer logging to stderr, or nil for a zero
+// threshold
+func newQueryTimer(threshold time.Duration) *queryTimer {
+	if threshold <= 0 {
+		return nil
+	}
+	return &queryTimer{threshold: threshold, log: os.Stderr, statements: make(map[string]*events.SlowStatement)}
+}
+
+// observe records a statement that started at start; call it deferred
+func (t *queryTimer) observe(statement string, start time.Time) {
+	elapsed := time.Since(start)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.queries++
+	if elapsed < t.threshold {
+		return
+	}
+
+	statement = normalizeStatement(statement)
+	fmt.Fprintf(t.log, "slow query (%s): %s\n", elapsed.Round(time.Millisecond), statement)
+	stat := t.statements[statement]
+	if stat == nil {
+		stat = &events.SlowStatement{Statement: statement}
+		t.statements[statement] = stat
+	}
+	ms := elapsed.Milliseconds()
+	stat.Slow++
+	stat.TotalMs += ms
+	stat.MaxMs = max(stat.MaxMs, ms)
+}
+
+// drain returns the counts so far, slowest statements first, and starts
+// counting afresh
+func (t *queryTimer) drain() events.StorageSlowQueriesData {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	data := events.StorageSlowQueriesData{ThresholdMs: t.threshold.Milliseconds(), Queries: t.queries}
+	for _, stat := range t.statements {
+		data.Slow += stat.Slow
+		data.Statements = append(data.Statements, *stat)
+	}
+	sortSlowStatements(data.Statements)
+	t.queries, t.statements = 0, make(map[string]*events.SlowStatement)
+	return data
+}
+
+// sortSlowStatements orders statements by total time, then text
+func sortSlowStatements(statements []events.SlowStatement) {
+	sort.Slice(statements, func(i, j int) bool {
+		if statements[i].TotalMs != statements[j].TotalMs {
+			return statements[i].TotalMs > statements[j].TotalMs
+		}
+		return statements[i].Statement < statements[j].Statement
+	})
+}
+
+// normalizeStatement masks the string and number literals of a statement,
+// collapses its whitespace and truncates it. Parameters are bound
+// separately, but a literal built into the SQL could still carry issue text.
+func normalizeStatement(statement string) string {
+	var b strings.Builder
+	runes := []rune(statement)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\'':
+			// Up to the closing quote, skipping escaped ('') quotes
+			for i++; i < len(runes); i++ {
+				if runes[i] != '\'' {
+					continue
+				}
+				if i+1 < len(runes) && runes[i+1] == '\'' {
+					i++
+					continue
+				}
+				break
+			}
+			b.WriteByte('?')
+		case unicode.IsSpace(r):
+			for i+1 < len(runes) && unicode.IsSpace(runes[i+1]) {
+				i++
+			}
+			if b.Len() > 0 && i+1 < len(runes) {
+				b.WriteByte(' ')
+			}
+		case unicode.IsDigit(r) && (i == 0 || !isIdentRune(runes[i-1])):
+			// A number, not the digits of a name like vc_issues2
+			for i+1 < len(runes) && (unicode.IsDigit(runes[i+1]) || runes[i+1] == '.') {
+				i++
+			}
+			b.WriteByte('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	normalized := []rune(b.String())
+	if len(normalized) > maxStatementLen {
+		return string(normalized[:maxStatementLen]) + "..."
+	}
+	return string(normalized)
+}
+
+// isIdentRune reports whether r can be part of an SQL name
+func isIdentRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// recordSlowQueries stores the process's slow queries as a SYSTEM
+// storage_slow_queries event, if there were any. Close calls it.
+func (s *VCStorage) recordSlowQueries(ctx context.Context) error {
+	if s.db.timer == nil || s.readOnly {
+		return nil
+	}
+	data := s.db.timer.drain()
+	if data.Slow == 0 {
+		return nil
+	}
+	event, err := events.NewStorageSlowQueriesEvent(
+		fmt.Sprintf("%d of %d queries took %dms or more", data.Slow, data.Queries, data.ThresholdMs), data)
+	if err != nil {
+		return err
+	}
+	return s.StoreAgentEvent(ctx, event)
+}
+
+// timedDB is the connection pool VCStorage queries the extension tables
+// through. With a timer, ExecContext, QueryContext and QueryRowContext are
+// timed; without one they go straight to the pool. Transactions and
+// scoped connections aren't timed.
+type timedDB struct {
+	*sql.DB
+	timer *queryTimer
+}
+
+// ExecContext runs a statement, timing it
+func (db *timedDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if db.timer != nil {
+		defer db.timer.observe(query, time.Now())
+	}
+	return db.DB.ExecContext(ctx, query, args...)
+}
+
+// QueryContext runs a query, timing it up to its first row
+func (db *timedDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if db.timer != nil {
+		defer db.timer.observe(query, time.Now())
+	}
+	return db.DB.QueryContext(ctx, query, args...)
+}
+
+// QueryRowContext runs a single-row query, timing it
+func (db *timedDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	if db.timer != nil {
+		defer db.timer.observe(query, time.Now())
+	}
+	return db.DB.QueryRowContext(ctx, query, args...)
+}
+
+// timedBeads times the Beads calls on VC's hot paths (issue reads and
+// writes, ready work, dependencies) as statements named beads.<Method>.
+// The rest of the Beads API passes through untimed.
+type timedBeads struct {
+	beadsLib.Storage
+	timer *queryTimer
+}
+
+// timeBeads wraps store in timedBeads, or returns it as is without a timer
+func timeBeads(store beadsLib.Storage, timer *queryTimer) beadsLib.Storage {
+	if timer == nil {
+		return store
+	}
+	return &timedBeads{Storage: store, timer: timer}
+}
+
+func (b *timedBeads) CreateIssue(ctx context.Context, issue *beadsLib.Issue, actor string) error {
+	defer b.timer.observe("beads.CreateIssue", time.Now())
+	return b.Storage.CreateIssue(ctx, issue, actor)
+}
+
+func (b *timedBeads) GetIssue(ctx context.Context, id string) (*beadsLib.Issue, error) {
+	defer b.timer.observe("beads.GetIssue", time.Now())
+	return b.Storage.GetIssue(ctx, id)
+}
+
+func (b *timedBeads) UpdateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string) error {
+	defer b.timer.observe("beads.UpdateIssue", time.Now())
+	return b.Storage.UpdateIssue(ctx, id, updates, actor)
+}
+
+func (b *timedBeads) CloseIssue(ctx context.Context, id string, reason string, actor string) error {
+	defer b.timer.observe("beads.CloseIssue", time.Now())
+	return b.Storage.CloseIssue(ctx, id, reason, actor)
+}
+
+func (b *timedBeads) SearchIssues(ctx context.Context, query string, filter beadsLib.IssueFilter) ([]*beadsLib.Issue, error) {
+	defer b.timer.observe("beads.SearchIssues", time.Now())
+	return b.Storage.SearchIssues(ctx, query, filter)
+}
+
+func (b *timedBeads) GetReadyWork(ctx context.Context, filter beadsLib.WorkFilter) ([]*beadsLib.Issue, error) {
+	defer b.timer.observe("beads.GetReadyWork", time.Now())
+	return b.Storage.GetReadyWork(ctx, filter)
+}
+
+func (b *timedBeads) GetDependencies(ctx context.Context, issueID string) ([]*beadsLib.Issue, error) {
+	defer b.timer.observe("beads.GetDependencies", time.Now())
+	return b.Storage.GetDependencies(ctx, issueID)
+}
+
+func (b *timedBeads) GetDependents(ctx context.Context, issueID string) ([]*beadsLib.Issue, error) {
+	defer b.timer.observe("beads.GetDependents", time.Now())
+	return b.Storage.GetDependents(ctx, issueID)
+}
@@ -0,0 +1,188 @@
+package beads
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestSlowQueryThreshold(t *testing.T) {
+	if got := slowQueryThreshold(""); got != 0 {
+		t.Errorf("Expected logging off by default, got %s", got)
+	}
+	if got := slowQueryThreshold("250"); got != 250*time.Millisecond {
+		t.Errorf("Expected the config value, got %s", got)
+	}
+	t.Setenv("VC_SLOW_QUERY_MS", "40")
+	if got := slowQueryThreshold("250"); got != 40*time.Millisecond {
+		t.Errorf("Expected VC_SLOW_QUERY_MS to override the config, got %s", got)
+	}
+	t.Setenv("VC_SLOW_QUERY_MS", "soon")
+	if got := slowQueryThreshold("250"); got != 0 {
+		t.Errorf("Expected an invalid value to turn logging off, got %s", got)
+	}
+	if newQueryTimer(0) != nil {
+		t.Error("Expected no timer for a zero threshold")
+	}
+}
+
+func TestQueryTimerThreshold(t *testing.T) {
+	var log bytes.Buffer
+	timer := newQueryTimer(50 * time.Millisecond)
+	timer.log = &log
+
+	timer.observe("SELECT 1", time.Now())
+	timer.observe("SELECT *\n\t\tFROM vc_mission_state WHERE issue_id = ?", time.Now().Add(-60*time.Millisecond))
+	timer.observe("SELECT * FROM vc_mission_state WHERE issue_id = ?", time.Now().Add(-90*time.Millisecond))
+	timer.observe("beads.GetIssue", time.Now().Add(-200*time.Millisecond))
+
+	lines := strings.Split(strings.TrimSpace(log.String()), "\n")
+	if len(lines) != 3 || !strings.HasSuffix(lines[0], "): SELECT * FROM vc_mission_state WHERE issue_id = ?") {
+		t.Fatalf("Expected the 3 slow queries logged, got:\n%s", log.String())
+	}
+
+	data := timer.drain()
+	if data.ThresholdMs != 50 || data.Queries != 4 || data.Slow != 3 || len(data.Statements) != 2 {
+		t.Fatalf("Unexpected summary: %+v", data)
+	}
+	if s := data.Statements[1]; s.Statement != "SELECT * FROM vc_mission_state WHERE issue_id = ?" || s.Slow != 2 || s.MaxMs < 90 || s.TotalMs < 150 {
+		t.Errorf("Expected both executions counted under one statement, got %+v", s)
+	}
+	if data := timer.drain(); data.Queries != 0 || data.Slow != 0 {
+		t.Errorf("Expected drain to start counting afresh, got %+v", data)
+	}
+}
+
+func TestNormalizeStatement(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"  SELECT id\n\t FROM issues  ", "SELECT id FROM issues"},
+		{"SELECT * FROM issues WHERE title = 'Fix the ''login'' bug'", "SELECT * FROM issues WHERE title = ?"},
+		{"SELECT * FROM vc_issues2 LIMIT 100 OFFSET 2.5", "SELECT * FROM vc_issues2 LIMIT ? OFFSET ?"},
+		{"SELECT 'unterminated", "SELECT ?"},
+		{strings.Repeat("x", maxStatementLen+10), strings.Repeat("x", maxStatementLen) + "..."},
+	}
+	for _, tt := range tests {
+		if got := normalizeStatement(tt.in); got != tt.want {
+			t.Errorf("normalizeStatement(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+// TestTimedDBNeverLogsParameters runs real queries through a pool timed
+// with a threshold every query exceeds
+func TestTimedDBNeverLogsParameters(t *testing.T) {
+	ctx := context.Background()
+	pool, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	pool.SetMaxOpenConns(1)
+
+	var log bytes.Buffer
+	timer := newQueryTimer(time.Nanosecond)
+	timer.log = &log
+	db := &timedDB{DB: pool, timer: timer}
+
+	const secret = "password hunter2 in the issue description"
+	if _, err := db.ExecContext(ctx, `CREATE TABLE notes (body TEXT)`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.ExecContext(ctx, `INSERT INTO notes (body) VALUES (?)`, secret); err != nil {
+		t.Fatal(err)
+	}
+	var n int
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM notes WHERE body = ? OR body = 'hunter2'`, secret).Scan(&n); err != nil || n != 1 {
+		t.Fatalf("Expected 1 row, got %d (%v)", n, err)
+	}
+	rows, err := db.QueryContext(ctx, `SELECT body FROM notes`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows.Close()
+
+	data := timer.drain()
+	if data.Queries != 4 || data.Slow != 4 {
+		t.Errorf("Expected 4 slow queries, got %+v", data)
+	}
+	if strings.Contains(log.String(), "hunter2") {
+		t.Errorf("Expected parameters and literals kept out of the log, got:\n%s", log.String())
+	}
+	for _, s := range data.Statements {
+		if strings.Contains(s.Statement, "hunter2") {
+			t.Errorf("Expected parameters and literals kept out of the counts, got %q", s.Statement)
+		}
+	}
+
+	// Without a timer the pool is used as is
+	if _, err := (&timedDB{DB: pool}).ExecContext(ctx, `DELETE FROM notes`); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSlowQueriesRecordedOnClose(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+	t.Setenv("VC_SLOW_QUERY_MS", "1")
+	store, err := NewVCStorage(ctx, dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create VC storage: %v", err)
+	}
+	// Every query is slow from here on
+	store.db.timer.threshold = time.Nanosecond
+	store.db.timer.log = &bytes.Buffer{}
+
+	issue := &types.Issue{Title: "Secret project codename", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("Failed to create issue: %v", err)
+	}
+	if _, err := store.GetIssue(ctx, issue.ID); err != nil {
+		t.Fatalf("Failed to get issue: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Failed to close: %v", err)
+	}
+
+	t.Setenv("VC_SLOW_QUERY_MS", "")
+	store, err = NewVCStorage(ctx, dbPath)
+	if err != nil {
+		t.Fatalf("Failed to reopen VC storage: %v", err)
+	}
+	defer store.Close()
+	if store.db.timer != nil {
+		t.Error("Expected queries untimed with slow query logging off")
+	}
+
+	recorded, err := store.GetAgentEvents(ctx, events.EventFilter{Type: events.EventTypeStorageSlowQueries})
+	if err != nil || len(recorded) != 1 {
+		t.Fatalf("Expected 1 storage_slow_queries event, got %d (%v)", len(recorded), err)
+	}
+	data, err := recorded[0].GetStorageSlowQueriesData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Slow == 0 || data.Slow != data.Queries {
+		t.Errorf("Expected every query counted as slow, got %+v", data)
+	}
+	var beadsCalls int
+	for _, s := range data.Statements {
+		if strings.Contains(s.Statement, "Secret project") {
+			t.Errorf("Expected no issue text in statements, got %q", s.Statement)
+		}
+		if strings.HasPrefix(s.Statement, "beads.") {
+			beadsCalls++
+		}
+	}
+	if beadsCalls == 0 {
+		t.Errorf("Expected the Beads calls timed too, got %+v", data.Statements)
+	}
+}
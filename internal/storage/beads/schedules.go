@@ -0,0 +1,157 @@
+package beads
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// SCHEDULES (VC extension table)
+// ======================================================================
+
+// CreateSchedule stores a new schedule, setting its ID and CreatedAt
+func (s *VCStorage) CreateSchedule(ctx context.Context, schedule *types.Schedule) error {
+	if err := schedule.Validate(); err != nil {
+		return fmt.Errorf("invalid schedule: %w", err)
+	}
+	labels := schedule.Labels
+	if labels == nil {
+		labels = []string{}
+	}
+	labelsJSON, err := json.Marshal(labels)
+	if err != nil {
+		return fmt.Errorf("failed to marshal labels: %w", err)
+	}
+	createdAt := time.Now()
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO vc_schedules (title, description, issue_type, priority, labels, project,
+			cron, interval_seconds, policy, enabled, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, schedule.Title, schedule.Description, string(schedule.IssueType), schedule.Priority, string(labelsJSON),
+		schedule.Project, schedule.Cron, int64(schedule.Interval/time.Second), string(schedule.Policy),
+		schedule.Enabled, createdAt)
+	if err != nil {
+		return fmt.Errorf("failed to create schedule: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get schedule ID: %w", err)
+	}
+	schedule.ID = id
+	schedule.CreatedAt = createdAt
+	return nil
+}
+
+// GetSchedule returns the schedule, or (nil, nil) if there is none
+func (s *VCStorage) GetSchedule(ctx context.Context, id int64) (*types.Schedule, error) {
+	row := s.db.QueryRowContext(ctx, scheduleSelect+` WHERE id = ?`, id)
+	schedule, err := scanSchedule(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schedule %d: %w", id, err)
+	}
+	return schedule, nil
+}
+
+// ListSchedules returns every schedule by ID
+func (s *VCStorage) ListSchedules(ctx context.Context) ([]*types.Schedule, error) {
+	rows, err := s.db.QueryContext(ctx, scheduleSelect+` ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schedules: %w", err)
+	}
+	defer rows.Close()
+
+	var schedules []*types.Schedule
+	for rows.Next() {
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan schedule: %w", err)
+		}
+		schedules = append(schedules, schedule)
+	}
+	return schedules, rows.Err()
+}
+
+// DeleteSchedule removes a schedule; the issues it filed stay
+func (s *VCStorage) DeleteSchedule(ctx context.Context, id int64) error {
+	return s.updateSchedule(ctx, id, `DELETE FROM vc_schedules WHERE id = ?`, id)
+}
+
+// SetScheduleEnabled turns a schedule on or off
+func (s *VCStorage) SetScheduleEnabled(ctx context.Context, id int64, enabled bool) error {
+	return s.updateSchedule(ctx, id, `UPDATE vc_schedules SET enabled = ? WHERE id = ?`, enabled, id)
+}
+
+// ClaimScheduleRun records a window handled at runAt if the schedule has
+// still handled runs windows
+func (s *VCStorage) ClaimScheduleRun(ctx context.Context, id int64, runs int, runAt time.Time) (bool, error) {
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE vc_schedules SET runs = runs + 1, last_run_at = ? WHERE id = ? AND runs = ?
+	`, runAt, id, runs)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim run of schedule %d: %w", id, err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim run of schedule %d: %w", id, err)
+	}
+	return n == 1, nil
+}
+
+// SetScheduleIssue records the issue filed for the schedule's last window
+func (s *VCStorage) SetScheduleIssue(ctx context.Context, id int64, issueID string) error {
+	return s.updateSchedule(ctx, id, `UPDATE vc_schedules SET last_issue_id = ? WHERE id = ?`, issueID, id)
+}
+
+// updateSchedule runs a statement on one schedule, failing if it doesn't
+// exist
+func (s *VCStorage) updateSchedule(ctx context.Context, id int64, query string, args ...interface{}) error {
+	result, err := s.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update schedule %d: %w", id, err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update schedule %d: %w", id, err)
+	}
+	if n == 0 {
+		return fmt.Errorf("schedule %d not found", id)
+	}
+	return nil
+}
+
+const scheduleSelect = `
+	SELECT id, title, description, issue_type, priority, labels, project, cron, interval_seconds,
+		policy, enabled, runs, last_run_at, last_issue_id, created_at
+	FROM vc_schedules`
+
+// scanSchedule reads a row selected with scheduleSelect
+func scanSchedule(row rowScanner) (*types.Schedule, error) {
+	var schedule types.Schedule
+	var issueType, labels, policy string
+	var intervalSeconds int64
+	var lastRunAt sql.NullTime
+	if err := row.Scan(&schedule.ID, &schedule.Title, &schedule.Description, &issueType, &schedule.Priority,
+		&labels, &schedule.Project, &schedule.Cron, &intervalSeconds, &policy, &schedule.Enabled,
+		&schedule.Runs, &lastRunAt, &schedule.LastIssueID, &schedule.CreatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(labels), &schedule.Labels); err != nil {
+		return nil, fmt.Errorf("invalid labels of schedule %d: %w", schedule.ID, err)
+	}
+	schedule.IssueType = types.IssueType(issueType)
+	schedule.Interval = time.Duration(intervalSeconds) * time.Second
+	schedule.Policy = types.SchedulePolicy(policy)
+	if lastRunAt.Valid {
+		schedule.LastRunAt = &lastRunAt.Time
+	}
+	return &schedule, nil
+}
@@ -0,0 +1,120 @@
+package beads
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ======================================================================
+// AGENT EVENT SEARCH (full-text index over vc_agent_events)
+// ======================================================================
+
+// EventSearchIndexConfigKey is the database config key that turns the agent
+// event search index on or off. Any value other than "false", "off", "no" or
+// "0" (including unset) keeps it on. Set it with `bd config set`; the change
+// takes effect the next time the database is opened.
+const EventSearchIndexConfigKey = "event_search_index"
+
+// minIndexedSearchLength is the shortest search text the trigram index can
+// answer; shorter text falls back to a LIKE scan
+const minIndexedSearchLength = 3
+
+// vcAgentEventsSearchSchema creates the search index: an external-content
+// FTS5 table (the text lives only in vc_agent_events) with the trigram
+// tokenizer, so MATCH does case-insensitive substring search like LIKE does.
+// The triggers keep it in sync for every writer, including the retention
+// cleanup deletes.
+const vcAgentEventsSearchSchema = `
+CREATE VIRTUAL TABLE IF NOT EXISTS vc_agent_events_fts USING fts5(
+	message,
+	data,
+	content='vc_agent_events',
+	content_rowid='id',
+	tokenize='trigram'
+);
+
+CREATE TRIGGER IF NOT EXISTS vc_agent_events_fts_insert AFTER INSERT ON vc_agent_events BEGIN
+	INSERT INTO vc_agent_events_fts(rowid, message, data) VALUES (new.id, new.message, new.data);
+END;
+
+CREATE TRIGGER IF NOT EXISTS vc_agent_events_fts_delete AFTER DELETE ON vc_agent_events BEGIN
+	INSERT INTO vc_agent_events_fts(vc_agent_events_fts, rowid, message, data) VALUES ('delete', old.id, old.message, old.data);
+END;
+
+CREATE TRIGGER IF NOT EXISTS vc_agent_events_fts_update AFTER UPDATE ON vc_agent_events BEGIN
+	INSERT INTO vc_agent_events_fts(vc_agent_events_fts, rowid, message, data) VALUES ('delete', old.id, old.message, old.data);
+	INSERT INTO vc_agent_events_fts(rowid, message, data) VALUES (new.id, new.message, new.data);
+END;
+`
+
+// dropAgentEventsSearchSchema removes the search index and its triggers
+const dropAgentEventsSearchSchema = `
+DROP TRIGGER IF EXISTS vc_agent_events_fts_insert;
+DROP TRIGGER IF EXISTS vc_agent_events_fts_delete;
+DROP TRIGGER IF EXISTS vc_agent_events_fts_update;
+DROP TABLE IF EXISTS vc_agent_events_fts;
+`
+
+// eventSearchIndexEnabled interprets the event_search_index config value
+func eventSearchIndexEnabled(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "false", "off", "no", "0":
+		return false
+	}
+	return true
+}
+
+// configureEventSearchIndex creates or drops the search index to match the
+// config. An index created over existing events is filled from them.
+// Uses a scoped connection (*sql.Conn) for DDL operations as recommended by Beads
+func configureEventSearchIndex(ctx context.Context, conn *sql.Conn, enabled bool) error {
+	if !enabled {
+		if _, err := conn.ExecContext(ctx, dropAgentEventsSearchSchema); err != nil {
+			return fmt.Errorf("failed to drop event search index: %w", err)
+		}
+		return nil
+	}
+
+	var exists bool
+	err := conn.QueryRowContext(ctx, `
+		SELECT COUNT(*) > 0
+		FROM sqlite_master
+		WHERE type = 'table' AND name = 'vc_agent_events_fts'
+	`).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to check for event search index: %w", err)
+	}
+
+	if _, err := conn.ExecContext(ctx, vcAgentEventsSearchSchema); err != nil {
+		return fmt.Errorf("failed to create event search index: %w", err)
+	}
+	if !exists {
+		if _, err := conn.ExecContext(ctx, `INSERT INTO vc_agent_events_fts(vc_agent_events_fts) VALUES ('rebuild')`); err != nil {
+			return fmt.Errorf("failed to build event search index: %w", err)
+		}
+	}
+	return nil
+}
+
+// messageContainsClause returns the WHERE condition for
+// EventFilter.MessageContains. The search index answers it when available;
+// otherwise (index disabled, or text too short for trigrams) message and
+// data are scanned with LIKE, which is slower but gives the same results.
+func (s *VCStorage) messageContainsClause(text string) (string, []interface{}) {
+	if s.eventSearchIndex && utf8.RuneCountInString(text) >= minIndexedSearchLength {
+		// Quote as an FTS phrase so the text is matched literally
+		phrase := `"` + strings.ReplaceAll(text, `"`, `""`) + `"`
+		return "id IN (SELECT rowid FROM vc_agent_events_fts WHERE vc_agent_events_fts MATCH ?)", []interface{}{phrase}
+	}
+
+	pattern := "%" + escapeLike(text) + "%"
+	return `(message LIKE ? ESCAPE '\' OR data LIKE ? ESCAPE '\')`, []interface{}{pattern, pattern}
+}
+
+// escapeLike escapes LIKE wildcards so text is matched literally
+func escapeLike(text string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(text)
+}
@@ -0,0 +1,253 @@
+package beads
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+)
+
+// searchMessages returns the messages of events matching text, sorted
+func searchMessages(t *testing.T, store *VCStorage, text string) []string {
+	t.Helper()
+	found, err := store.GetAgentEvents(context.Background(), events.EventFilter{MessageContains: text})
+	if err != nil {
+		t.Fatalf("GetAgentEvents(%q) failed: %v", text, err)
+	}
+	var messages []string
+	for _, e := range found {
+		messages = append(messages, e.Message)
+	}
+	sort.Strings(messages)
+	return messages
+}
+
+// storeSearchEvents stores events whose text exercises substring, case,
+// data and wildcard matching
+func storeSearchEvents(t *testing.T, store *VCStorage, age time.Duration) {
+	t.Helper()
+	now := time.Now()
+	for i, e := range []*events.AgentEvent{
+		{Type: events.EventTypeError, Severity: events.SeverityInfo, Message: "dial tcp: Connection refused"},
+		{Type: events.EventTypeProgress, Severity: events.SeverityInfo, Message: "retrying request",
+			Data: map[string]interface{}{"error": "connection refused by upstream"}},
+		{Type: events.EventTypeProgress, Severity: events.SeverityInfo, Message: "coverage at 100% of lines"},
+		{Type: events.EventTypeProgress, Severity: events.SeverityInfo, Message: "build_output ok"},
+	} {
+		e.Timestamp = now.Add(-age).Add(time.Duration(i) * time.Second)
+		if err := store.StoreAgentEvent(context.Background(), e); err != nil {
+			t.Fatalf("Failed to store event %d: %v", i, err)
+		}
+	}
+}
+
+func TestAgentEventsMessageContains(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewVCStorage(ctx, filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create VC storage: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	if !store.eventSearchIndex {
+		t.Fatal("Expected the event search index to be enabled by default")
+	}
+	storeSearchEvents(t, store, 0)
+
+	tests := []struct {
+		text string
+		want string
+	}{
+		// Message and data, ignoring case
+		{"connection REFUSED", "[dial tcp: Connection refused retrying request]"},
+		// Substrings, not just whole words
+		{"upstr", "[retrying request]"},
+		// LIKE wildcards are literal
+		{"100%", "[coverage at 100% of lines]"},
+		{"d_o", "[build_output ok]"},
+		// FTS syntax is literal too
+		{`"ok" OR tcp`, "[]"},
+		// Too short for the index
+		{"ok", "[build_output ok]"},
+	}
+	for _, tt := range tests {
+		if got := fmt.Sprint(searchMessages(t, store, tt.text)); got != tt.want {
+			t.Errorf("MessageContains %q: got %s, want %s", tt.text, got, tt.want)
+		}
+	}
+
+	// Combines with the other filters
+	found, err := store.GetAgentEvents(ctx, events.EventFilter{MessageContains: "refused", Type: events.EventTypeError})
+	if err != nil {
+		t.Fatalf("GetAgentEvents failed: %v", err)
+	}
+	if len(found) != 1 || found[0].Message != "dial tcp: Connection refused" {
+		t.Errorf("Expected only the error event, got %v", found)
+	}
+
+	// The database search agrees with EventFilter.Matches
+	all, err := store.GetAgentEvents(ctx, events.EventFilter{})
+	if err != nil {
+		t.Fatalf("GetAgentEvents failed: %v", err)
+	}
+	for _, tt := range tests {
+		var matched []string
+		for _, e := range all {
+			if (events.EventFilter{MessageContains: tt.text}).Matches(e) {
+				matched = append(matched, e.Message)
+			}
+		}
+		sort.Strings(matched)
+		if got := fmt.Sprint(matched); got != tt.want {
+			t.Errorf("Matches %q: got %s, want %s", tt.text, got, tt.want)
+		}
+	}
+}
+
+// TestEventSearchIndexFollowsCleanup verifies retention cleanup removes
+// deleted events from the search index
+func TestEventSearchIndexFollowsCleanup(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewVCStorage(ctx, filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create VC storage: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	storeSearchEvents(t, store, 10*24*time.Hour)
+	if err := store.StoreAgentEvent(ctx, &events.AgentEvent{
+		Timestamp: time.Now(), Type: events.EventTypeProgress, Severity: events.SeverityInfo, Message: "fresh connection refused",
+	}); err != nil {
+		t.Fatalf("Failed to store event: %v", err)
+	}
+
+	deleted, err := store.CleanupEventsByAge(ctx, 7, 7, 2)
+	if err != nil {
+		t.Fatalf("CleanupEventsByAge failed: %v", err)
+	}
+	if deleted != 4 {
+		t.Fatalf("Expected 4 old events deleted, got %d", deleted)
+	}
+
+	if got := fmt.Sprint(searchMessages(t, store, "refused")); got != "[fresh connection refused]" {
+		t.Errorf("Expected only the fresh event to match, got %s", got)
+	}
+	var indexed int
+	if err := store.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM vc_agent_events_fts WHERE vc_agent_events_fts MATCH '"refused"'`).Scan(&indexed); err != nil {
+		t.Fatalf("Failed to query search index: %v", err)
+	}
+	if indexed != 1 {
+		t.Errorf("Expected 1 indexed match after cleanup, got %d", indexed)
+	}
+	if _, err := store.db.ExecContext(ctx, `INSERT INTO vc_agent_events_fts(vc_agent_events_fts, rank) VALUES ('integrity-check', 1)`); err != nil {
+		t.Errorf("Search index out of sync with vc_agent_events: %v", err)
+	}
+}
+
+// TestEventSearchIndexConfig verifies the index can be turned off, with
+// searches falling back to scanning, and rebuilt when turned back on
+func TestEventSearchIndexConfig(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	reopen := func(store *VCStorage, value string) *VCStorage {
+		t.Helper()
+		if err := store.SetConfig(ctx, EventSearchIndexConfigKey, value); err != nil {
+			t.Fatalf("Failed to set config: %v", err)
+		}
+		if err := store.Close(); err != nil {
+			t.Fatalf("Failed to close storage: %v", err)
+		}
+		store, err := NewVCStorage(ctx, dbPath)
+		if err != nil {
+			t.Fatalf("Failed to reopen VC storage: %v", err)
+		}
+		return store
+	}
+	hasIndex := func(store *VCStorage) bool {
+		t.Helper()
+		var count int
+		if err := store.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM sqlite_master WHERE name LIKE 'vc_agent_events_fts%'`).Scan(&count); err != nil {
+			t.Fatalf("Failed to inspect schema: %v", err)
+		}
+		return count > 0
+	}
+
+	store, err := NewVCStorage(ctx, dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create VC storage: %v", err)
+	}
+	storeSearchEvents(t, store, 0)
+
+	store = reopen(store, "false")
+	if store.eventSearchIndex || hasIndex(store) {
+		t.Fatal("Expected the search index and its triggers to be dropped")
+	}
+	storeSearchEvents(t, store, 0)
+	if got := len(searchMessages(t, store, "connection refused")); got != 4 {
+		t.Errorf("Expected 4 matches without the index, got %d", got)
+	}
+
+	// Events stored while the index was off are picked up by the rebuild
+	store = reopen(store, "true")
+	defer func() { _ = store.Close() }()
+	if !store.eventSearchIndex || !hasIndex(store) {
+		t.Fatal("Expected the search index to be recreated")
+	}
+	if got := len(searchMessages(t, store, "connection refused")); got != 4 {
+		t.Errorf("Expected 4 matches after rebuilding the index, got %d", got)
+	}
+}
+
+func TestEventSearchIndexEnabled(t *testing.T) {
+	for value, want := range map[string]bool{
+		"": true, "true": true, "on": true, "1": true,
+		"false": false, "OFF": false, " no ": false, "0": false,
+	} {
+		if got := eventSearchIndexEnabled(value); got != want {
+			t.Errorf("eventSearchIndexEnabled(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
+
+// BenchmarkStoreAgentEvent measures the write overhead of the search index.
+// Run with: go test ./internal/storage/beads -run '^$' -bench StoreAgentEvent
+func BenchmarkStoreAgentEvent(b *testing.B) {
+	for _, index := range []string{"true", "false"} {
+		b.Run("search_index="+index, func(b *testing.B) {
+			ctx := context.Background()
+			dbPath := filepath.Join(b.TempDir(), "bench.db")
+			store, err := NewVCStorage(ctx, dbPath)
+			if err != nil {
+				b.Fatalf("Failed to create VC storage: %v", err)
+			}
+			if err := store.SetConfig(ctx, EventSearchIndexConfigKey, index); err != nil {
+				b.Fatalf("Failed to set config: %v", err)
+			}
+			_ = store.Close()
+			store, err = NewVCStorage(ctx, dbPath)
+			if err != nil {
+				b.Fatalf("Failed to reopen VC storage: %v", err)
+			}
+			defer func() { _ = store.Close() }()
+
+			event := &events.AgentEvent{
+				Timestamp: time.Now(),
+				Type:      events.EventTypeAgentToolUse,
+				Severity:  events.SeverityInfo,
+				Message:   "Agent used Edit on internal/executor/executor.go",
+				Data:      map[string]interface{}{"tool_name": "Edit", "target_file": "internal/executor/executor.go", "tool_description": "update the watchdog loop"},
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := store.StoreAgentEvent(ctx, event); err != nil {
+					b.Fatalf("StoreAgentEvent failed: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package beads
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestStatusTransitionEnforcement(t *testing.T) {
+	ctx := context.Background()
+	store, issue := newCreateTestStore(t)
+
+	// Closing needs a reason
+	err := store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"status": string(types.StatusClosed)}, "test")
+	if !errors.Is(err, types.ErrIllegalTransition) {
+		t.Errorf("Expected closing via UpdateIssue to be refused, got %v", err)
+	}
+	if err := store.CloseIssue(ctx, issue.ID, " ", "test"); err == nil {
+		t.Error("Expected CloseIssue without a reason to fail")
+	}
+	if err := store.CloseIssue(ctx, issue.ID, "Done", "test"); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+
+	// closed → in_progress skips the reopen
+	err = store.UpdateIssue(ctx, issue.ID, map[string]interface{}{"status": string(types.StatusInProgress)}, "test")
+	var transitionErr *types.StatusTransitionError
+	if !errors.As(err, &transitionErr) {
+		t.Fatalf("Expected a StatusTransitionError, got %v", err)
+	}
+	if transitionErr.From != types.StatusClosed || transitionErr.To != types.StatusInProgress {
+		t.Errorf("Error names the wrong transition: %v", err)
+	}
+	if current, _ := store.GetIssue(ctx, issue.ID); current.Status != types.StatusClosed {
+		t.Errorf("Illegal transition was written: %s", current.Status)
+	}
+
+	// An admin override skips the graph and leaves an audit comment
+	if err := store.OverrideIssueStatus(ctx, issue.ID, types.StatusInProgress, "executor crashed mid-run", "admin"); err != nil {
+		t.Fatalf("OverrideIssueStatus failed: %v", err)
+	}
+	current, _ := store.GetIssue(ctx, issue.ID)
+	if current.Status != types.StatusInProgress || current.ClosedAt != nil {
+		t.Errorf("Expected in_progress without closed_at, got %+v", current)
+	}
+	events, err := store.GetEvents(ctx, issue.ID, 0)
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	audited := false
+	for _, event := range events {
+		if event.Comment != nil && event.Actor == "admin" &&
+			strings.Contains(*event.Comment, "closed → in_progress") && strings.Contains(*event.Comment, "executor crashed mid-run") {
+			audited = true
+		}
+	}
+	if !audited {
+		t.Errorf("Expected an audit comment for the override, got %d events", len(events))
+	}
+}
+
+// TestExecutorStatusTransitions walks every status change the executor makes
+// through the real storage paths, so tightening the status graph can't
+// strand an issue mid-execution
+func TestExecutorStatusTransitions(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newCreateTestStore(t)
+
+	instance := &types.ExecutorInstance{
+		InstanceID: "exec-1",
+		Version:    "test",
+		StartedAt:  time.Now(),
+		Hostname:   "test-host",
+		Status:     "running",
+	}
+	if err := store.RegisterInstance(ctx, instance); err != nil {
+		t.Fatalf("Failed to register instance: %v", err)
+	}
+
+	newIssue := func() string {
+		issue := &types.Issue{Title: "Work", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatalf("CreateIssue failed: %v", err)
+		}
+		return issue.ID
+	}
+	claim := func(id string) error { return store.ClaimIssue(ctx, id, instance.InstanceID) }
+	setStatus := func(status types.Status) func(string) error {
+		return func(id string) error {
+			return store.UpdateIssue(ctx, id, map[string]interface{}{"status": string(status)}, "executor")
+		}
+	}
+	closeIssue := func(id string) error { return store.CloseIssue(ctx, id, "Completed", "executor") }
+
+	tests := []struct {
+		name  string
+		steps []func(string) error
+		want  types.Status
+	}{
+		{"claim (open → in_progress)", []func(string) error{claim}, types.StatusInProgress},
+		{"reopen after failure (in_progress → open)", []func(string) error{claim, func(id string) error {
+			return store.ReleaseIssueAndReopen(ctx, id, "executor", "agent failed")
+		}}, types.StatusOpen},
+		{"QA gates pass (in_progress → open)", []func(string) error{claim, setStatus(types.StatusOpen)}, types.StatusOpen},
+		{"agent or gates block (in_progress → blocked)", []func(string) error{claim, setStatus(types.StatusBlocked)}, types.StatusBlocked},
+		{"completion (in_progress → closed)", []func(string) error{claim, closeIssue}, types.StatusClosed},
+		{"epic completion (open → closed)", []func(string) error{closeIssue}, types.StatusClosed},
+		{"epic completion (blocked → closed)", []func(string) error{setStatus(types.StatusBlocked), closeIssue}, types.StatusClosed},
+		{"sandbox merge (blocked → open)", []func(string) error{setStatus(types.StatusBlocked), setStatus(types.StatusOpen)}, types.StatusOpen},
+		{"sandbox merge, unchanged (open → open)", []func(string) error{setStatus(types.StatusOpen)}, types.StatusOpen},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := newIssue()
+			for i, step := range tt.steps {
+				if err := step(id); err != nil {
+					t.Fatalf("Step %d failed: %v", i+1, err)
+				}
+			}
+			issue, err := store.GetIssue(ctx, id)
+			if err != nil {
+				t.Fatalf("GetIssue failed: %v", err)
+			}
+			if issue.Status != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, issue.Status)
+			}
+		})
+	}
+}
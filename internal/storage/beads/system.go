@@ -0,0 +1,49 @@
+package beads
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// SYSTEM PSEUDO-ISSUE
+// ======================================================================
+
+// execer is what ensureSystemIssue needs from a *sql.DB or *sql.Conn
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// EnsureSystemIssue creates the SYSTEM pseudo-issue unless it exists, e.g.
+// because someone deleted it with bd. It is safe to race with itself and
+// with event writes: the insert does nothing once the row exists, and agent
+// events don't reference issues by foreign key.
+func (s *VCStorage) EnsureSystemIssue(ctx context.Context) (bool, error) {
+	return ensureSystemIssue(ctx, s.db)
+}
+
+// ensureSystemIssue inserts the SYSTEM pseudo-issue if it is missing and
+// reports whether it did. The row is written directly: Beads only creates
+// issues with IDs of the project prefix.
+func ensureSystemIssue(ctx context.Context, db execer) (bool, error) {
+	issue := types.NewSystemIssue(time.Now())
+	result, err := db.ExecContext(ctx, `
+		INSERT OR IGNORE INTO issues (
+			id, title, description, status, priority, issue_type,
+			created_at, updated_at, closed_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, issue.ID, issue.Title, issue.Description, issue.Status, issue.Priority, issue.IssueType,
+		issue.CreatedAt, issue.UpdatedAt, issue.ClosedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to create %s pseudo-issue: %w", types.SystemIssueID, err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
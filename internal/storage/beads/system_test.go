@@ -0,0 +1,92 @@
+package beads
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestSystemIssueRecreatedOnOpen(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "system.db")
+	store, err := NewVCStorage(ctx, path)
+	if err != nil {
+		t.Fatalf("Failed to create VC storage: %v", err)
+	}
+	system, err := store.GetIssue(ctx, types.SystemIssueID)
+	if err != nil || system == nil || system.Status != types.StatusClosed {
+		t.Fatalf("Expected a closed SYSTEM issue in a fresh database, got %+v (%v)", system, err)
+	}
+
+	// Someone deletes it behind vc's back (e.g. with bd)
+	if _, err := store.db.ExecContext(ctx, `DELETE FROM issues WHERE id = ?`, types.SystemIssueID); err != nil {
+		t.Fatalf("Failed to delete SYSTEM issue: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err = NewVCStorage(ctx, path)
+	if err != nil {
+		t.Fatalf("Failed to reopen VC storage: %v", err)
+	}
+	defer store.Close()
+	if n := countRows(t, store, `SELECT COUNT(*) FROM issues WHERE id = ?`, types.SystemIssueID); n != 1 {
+		t.Errorf("Expected SYSTEM recreated on open, got %d rows", n)
+	}
+}
+
+func TestEnsureSystemIssueRacesEventWrites(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newCreateTestStore(t)
+	if _, err := store.db.ExecContext(ctx, `DELETE FROM issues WHERE id = ?`, types.SystemIssueID); err != nil {
+		t.Fatalf("Failed to delete SYSTEM issue: %v", err)
+	}
+
+	const workers = 8
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	created := 0
+	for i := 0; i < workers; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			ok, err := store.EnsureSystemIssue(ctx)
+			if err != nil {
+				t.Errorf("EnsureSystemIssue failed: %v", err)
+			}
+			if ok {
+				mu.Lock()
+				created++
+				mu.Unlock()
+			}
+		}()
+		go func(i int) {
+			defer wg.Done()
+			event := &events.AgentEvent{
+				ID: fmt.Sprintf("e%d", i), Type: events.EventTypeEventCleanupCompleted, Timestamp: time.Now(),
+				IssueID: types.SystemIssueID, Severity: events.SeverityInfo, Message: "cleanup",
+			}
+			if err := store.StoreAgentEvent(ctx, event); err != nil {
+				t.Errorf("StoreAgentEvent failed: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if created != 1 {
+		t.Errorf("Expected exactly one call to recreate SYSTEM, got %d", created)
+	}
+	if n := countRows(t, store, `SELECT COUNT(*) FROM issues WHERE id = ?`, types.SystemIssueID); n != 1 {
+		t.Errorf("Expected one SYSTEM issue, got %d", n)
+	}
+	if n := countRows(t, store, `SELECT COUNT(*) FROM vc_agent_events WHERE issue_id = ?`, types.SystemIssueID); n != workers {
+		t.Errorf("Expected %d SYSTEM events, got %d", workers, n)
+	}
+}
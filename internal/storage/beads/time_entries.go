@@ -0,0 +1,132 @@
+package beads
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// TIME ENTRIES (VC extension table)
+// ======================================================================
+
+// AddTimeEntry stores working time logged on an issue, setting the entry's
+// ID, CreatedAt and UpdatedAt
+func (s *VCStorage) AddTimeEntry(ctx context.Context, entry *types.TimeEntry) error {
+	if err := entry.Validate(); err != nil {
+		return fmt.Errorf("invalid time entry: %w", err)
+	}
+	var exists int
+	err := s.db.QueryRowContext(ctx, `SELECT 1 FROM issues WHERE id = ?`, entry.IssueID).Scan(&exists)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("issue %s not found", entry.IssueID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get issue %s: %w", entry.IssueID, err)
+	}
+	now := time.Now()
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO vc_time_entries (issue_id, minutes, note, actor, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, entry.IssueID, entry.Minutes, entry.Note, entry.Actor, now, now)
+	if err != nil {
+		return fmt.Errorf("failed to add time entry: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get time entry ID: %w", err)
+	}
+	entry.ID = id
+	entry.CreatedAt = now
+	entry.UpdatedAt = now
+	return nil
+}
+
+// GetTimeEntry returns the entry, or (nil, nil) if there is none
+func (s *VCStorage) GetTimeEntry(ctx context.Context, id int64) (*types.TimeEntry, error) {
+	row := s.db.QueryRowContext(ctx, timeEntrySelect+` WHERE id = ?`, id)
+	entry, err := scanTimeEntry(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get time entry %d: %w", id, err)
+	}
+	return entry, nil
+}
+
+// ListTimeEntries returns the issue's entries ("" for every issue's),
+// oldest first
+func (s *VCStorage) ListTimeEntries(ctx context.Context, issueID string) ([]*types.TimeEntry, error) {
+	query := timeEntrySelect
+	var args []interface{}
+	if issueID != "" {
+		query += ` WHERE issue_id = ?`
+		args = append(args, issueID)
+	}
+	rows, err := s.db.QueryContext(ctx, query+` ORDER BY created_at, id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query time entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*types.TimeEntry
+	for rows.Next() {
+		entry, err := scanTimeEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan time entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// UpdateTimeEntry replaces the entry's minutes and note
+func (s *VCStorage) UpdateTimeEntry(ctx context.Context, id int64, minutes int, note, actor string) error {
+	if err := types.ValidateTimeEntryUpdate(minutes, actor); err != nil {
+		return fmt.Errorf("invalid time entry: %w", err)
+	}
+	return s.updateTimeEntry(ctx, id, `
+		UPDATE vc_time_entries SET minutes = ?, note = ?, actor = ?, updated_at = ? WHERE id = ?
+	`, minutes, note, actor, time.Now(), id)
+}
+
+// DeleteTimeEntry removes an entry
+func (s *VCStorage) DeleteTimeEntry(ctx context.Context, id int64) error {
+	return s.updateTimeEntry(ctx, id, `DELETE FROM vc_time_entries WHERE id = ?`, id)
+}
+
+// updateTimeEntry runs a statement on one entry, failing if it doesn't
+// exist
+func (s *VCStorage) updateTimeEntry(ctx context.Context, id int64, query string, args ...interface{}) error {
+	result, err := s.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update time entry %d: %w", id, err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update time entry %d: %w", id, err)
+	}
+	if n == 0 {
+		return fmt.Errorf("time entry %d not found", id)
+	}
+	return nil
+}
+
+const timeEntrySelect = `
+	SELECT id, issue_id, minutes, note, actor, created_at, updated_at
+	FROM vc_time_entries`
+
+// scanTimeEntry reads a row selected with timeEntrySelect
+func scanTimeEntry(row rowScanner) (*types.TimeEntry, error) {
+	var entry types.TimeEntry
+	if err := row.Scan(&entry.ID, &entry.IssueID, &entry.Minutes, &entry.Note, &entry.Actor,
+		&entry.CreatedAt, &entry.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
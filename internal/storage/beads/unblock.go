@@ -0,0 +1,151 @@
+package beads
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// UNBLOCKING DEPENDENTS (when their last blocker closes)
+// ======================================================================
+
+// UnblockDependentsConfigKey is the database config key of the policy for
+// blocked issues whose last open blocker is closed: types.UnblockReopen
+// (the default), types.UnblockLabel or types.UnblockOff
+const UnblockDependentsConfigKey = "unblock_dependents"
+
+// unblockDependents handles the dependents of a just closed issue: each one
+// that is blocked, is blocked by closedID ('blocks' dependency) and has no
+// other open blocker is reopened (UnblockIssue) or labeled, per the
+// unblock_dependents policy, with a comment naming closedID. Archived issues
+// neither count as blockers nor are unblocked, and neither are issues held
+// for another reason (see heldForReview): closing a blocker doesn't resolve
+// a hold.
+//
+// Reopening closes nothing, so nothing cascades: in a chain A blocks B
+// blocks C, closing A unblocks B, and C waits for B to close.
+func (s *VCStorage) unblockDependents(ctx context.Context, closedID, actor string) error {
+	policy, err := s.GetConfig(ctx, UnblockDependentsConfigKey)
+	if err != nil {
+		return fmt.Errorf("failed to read %s config: %w", UnblockDependentsConfigKey, err)
+	}
+	if policy == types.UnblockOff {
+		return nil
+	}
+
+	dependents, err := s.GetDependents(ctx, closedID)
+	if err != nil {
+		return fmt.Errorf("failed to get dependents of %s: %w", closedID, err)
+	}
+	for _, dependent := range dependents {
+		if dependent.Status != types.StatusBlocked || dependent.Archived {
+			continue
+		}
+		ready, err := s.blockersClosed(ctx, dependent.ID, closedID)
+		if err != nil {
+			return err
+		}
+		if !ready {
+			continue
+		}
+		held, err := s.heldForReview(ctx, dependent.ID)
+		if err != nil {
+			return err
+		}
+		if held {
+			continue
+		}
+		if policy == types.UnblockLabel {
+			err = s.labelReadyToUnblock(ctx, dependent.ID, closedID, actor)
+		} else {
+			err = s.unblockDependent(ctx, dependent.ID, closedID, actor)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// blockersClosed reports whether issueID is blocked by closedID and every
+// other issue blocking it is closed or archived
+func (s *VCStorage) blockersClosed(ctx context.Context, issueID, closedID string) (bool, error) {
+	records, err := s.GetDependencyRecords(ctx, issueID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get dependencies of %s: %w", issueID, err)
+	}
+	blockedByClosed := false
+	for _, dep := range records {
+		if dep.Type != types.DepBlocks {
+			continue
+		}
+		if dep.DependsOnID == closedID {
+			blockedByClosed = true
+			continue
+		}
+		blocker, err := s.GetIssue(ctx, dep.DependsOnID)
+		if err != nil {
+			return false, fmt.Errorf("failed to get %s: %w", dep.DependsOnID, err)
+		}
+		if blocker != nil && !blocker.Archived && blocker.Status != types.StatusClosed {
+			return false, nil
+		}
+	}
+	return blockedByClosed, nil
+}
+
+// heldForReview reports whether a blocked issue waits for a person rather
+// than (only) for its blockers: the executor auto-blocked it after repeated
+// failures or held it for triage, or a watchdog escalation for it is open
+func (s *VCStorage) heldForReview(ctx context.Context, issueID string) (bool, error) {
+	labels, err := s.GetLabels(ctx, issueID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get labels of %s: %w", issueID, err)
+	}
+	if slices.Contains(labels, types.AutoBlockedLabel) || slices.Contains(labels, types.NeedsTriageLabel) {
+		return true, nil
+	}
+	escalations, err := s.GetIssuesByLabel(ctx, types.AffectedIssueLabel(issueID))
+	if err != nil {
+		return false, fmt.Errorf("failed to get escalations of %s: %w", issueID, err)
+	}
+	for _, escalation := range escalations {
+		if escalation.Status != types.StatusClosed {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// unblockDependent reopens an issue whose last blocker was closed
+func (s *VCStorage) unblockDependent(ctx context.Context, id, closedID, actor string) error {
+	if err := s.UnblockIssue(ctx, id, actor); err != nil {
+		return err
+	}
+	if err := s.AddComment(ctx, id, actor, fmt.Sprintf("Unblocked by closing %s", closedID)); err != nil {
+		return fmt.Errorf("unblocked %s but failed to comment: %w", id, err)
+	}
+	return nil
+}
+
+// labelReadyToUnblock labels an issue whose last blocker was closed, once
+func (s *VCStorage) labelReadyToUnblock(ctx context.Context, id, closedID, actor string) error {
+	labels, err := s.GetLabels(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get labels of %s: %w", id, err)
+	}
+	if slices.Contains(labels, types.ReadyToUnblockLabel) {
+		return nil
+	}
+	if err := s.AddLabel(ctx, id, types.ReadyToUnblockLabel, actor); err != nil {
+		return fmt.Errorf("failed to label %s %s: %w", id, types.ReadyToUnblockLabel, err)
+	}
+	comment := fmt.Sprintf("Ready to unblock: closing %s closed its last blocker", closedID)
+	if err := s.AddComment(ctx, id, actor, comment); err != nil {
+		return fmt.Errorf("labeled %s but failed to comment: %w", id, err)
+	}
+	return nil
+}
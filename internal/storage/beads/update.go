@@ -0,0 +1,154 @@
+package beads
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	beadsLib "github.com/steveyegge/beads"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// CONDITIONAL ISSUE UPDATES (optimistic concurrency control)
+// ======================================================================
+
+// updatableIssueFields are the columns UpdateIssue accepts, as in Beads
+var updatableIssueFields = map[string]bool{
+	"status":              true,
+	"priority":            true,
+	"title":               true,
+	"assignee":            true,
+	"description":         true,
+	"design":              true,
+	"acceptance_criteria": true,
+	"notes":               true,
+	"issue_type":          true,
+	"estimated_minutes":   true,
+	"external_ref":        true,
+}
+
+// UpdateIssueIfUnchanged applies updates only if the issue's updated_at still
+// equals expectedUpdatedAt, i.e. nobody wrote the issue since the caller read
+// it. On a mismatch nothing is written and a *types.ConflictError is returned.
+//
+// The check and the write share one BEGIN IMMEDIATE transaction, so no other
+// writer can slip in between. Beads' UpdateIssue opens its own transaction,
+// so the update is written directly with the same validation, closed_at
+// handling, audit event and dirty marker.
+func (s *VCStorage) UpdateIssueIfUnchanged(ctx context.Context, id string, updates map[string]interface{}, expectedUpdatedAt time.Time, actor string) error {
+	setClauses := []string{"updated_at = ?"}
+	args := []interface{}{time.Now()}
+	for key, value := range updates {
+		if !updatableIssueFields[key] {
+			return fmt.Errorf("invalid field for update: %s", key)
+		}
+		setClauses = append(setClauses, key+" = ?")
+		args = append(args, value)
+	}
+	if err := types.ValidateUpdates(updates); err != nil {
+		return err
+	}
+
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	if _, err := conn.ExecContext(ctx, "BEGIN IMMEDIATE"); err != nil {
+		return fmt.Errorf("failed to begin immediate transaction: %w", err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			_, _ = conn.ExecContext(context.Background(), "ROLLBACK")
+		}
+	}()
+
+	// We hold the write lock, so this read (on another connection, fine in
+	// WAL mode) sees the latest committed state until we commit
+	oldIssue, err := s.Storage.GetIssue(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get issue: %w", err)
+	}
+	if oldIssue == nil {
+		return fmt.Errorf("issue %s not found", id)
+	}
+	if !oldIssue.UpdatedAt.Equal(expectedUpdatedAt) {
+		return &types.ConflictError{
+			IssueID:           id,
+			ExpectedUpdatedAt: expectedUpdatedAt,
+			ActualUpdatedAt:   oldIssue.UpdatedAt,
+		}
+	}
+
+	var newStatus string
+	statusValue, hasStatus := updates["status"]
+	switch v := statusValue.(type) {
+	case string:
+		newStatus = v
+	case types.Status:
+		newStatus = string(v)
+	default:
+		hasStatus = false
+	}
+	if hasStatus {
+		if err := validateStatusUpdate(id, types.Status(oldIssue.Status), types.Status(newStatus)); err != nil {
+			return err
+		}
+
+		// Keep closed_at consistent with the status, as Beads does
+		if newStatus == string(types.StatusClosed) {
+			setClauses = append(setClauses, "closed_at = ?")
+			args = append(args, time.Now())
+		} else if oldIssue.Status == beadsLib.StatusClosed {
+			setClauses = append(setClauses, "closed_at = ?")
+			args = append(args, nil)
+		}
+	}
+
+	args = append(args, id)
+	query := fmt.Sprintf("UPDATE issues SET %s WHERE id = ?", strings.Join(setClauses, ", ")) // #nosec G201 - column names are whitelisted
+	if _, err := conn.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to update issue: %w", err)
+	}
+
+	eventType := beadsLib.EventUpdated
+	if hasStatus {
+		switch {
+		case newStatus == string(types.StatusClosed):
+			eventType = beadsLib.EventClosed
+		case oldIssue.Status == beadsLib.StatusClosed:
+			eventType = beadsLib.EventReopened
+		default:
+			eventType = beadsLib.EventStatusChanged
+		}
+	}
+	oldData, err := json.Marshal(oldIssue)
+	if err != nil {
+		oldData = []byte(fmt.Sprintf(`{"id":%q}`, id))
+	}
+	newData, err := json.Marshal(updates)
+	if err != nil {
+		newData = []byte(`{}`)
+	}
+	if _, err := conn.ExecContext(ctx, `
+		INSERT INTO events (issue_id, event_type, actor, old_value, new_value)
+		VALUES (?, ?, ?, ?, ?)
+	`, id, eventType, actor, string(oldData), string(newData)); err != nil {
+		return fmt.Errorf("failed to record event: %w", err)
+	}
+
+	if err := markIssuesDirty(ctx, conn, id); err != nil {
+		return err
+	}
+
+	if _, err := conn.ExecContext(ctx, "COMMIT"); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	committed = true
+	return nil
+}
@@ -0,0 +1,139 @@
+package beads
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestUpdateIssueIfUnchanged(t *testing.T) {
+	ctx := context.Background()
+	store, issue := newCreateTestStore(t)
+
+	read, err := store.GetIssue(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+
+	if err := store.UpdateIssueIfUnchanged(ctx, issue.ID, map[string]interface{}{"title": "Renamed"}, read.UpdatedAt, "test"); err != nil {
+		t.Fatalf("UpdateIssueIfUnchanged failed: %v", err)
+	}
+	updated, err := store.GetIssue(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if updated.Title != "Renamed" || !updated.UpdatedAt.After(read.UpdatedAt) {
+		t.Errorf("Expected title and updated_at to change, got %+v", updated)
+	}
+
+	// The version read before the first update is now stale
+	err = store.UpdateIssueIfUnchanged(ctx, issue.ID, map[string]interface{}{"title": "Lost"}, read.UpdatedAt, "test")
+	var conflict *types.ConflictError
+	if !errors.As(err, &conflict) || !errors.Is(err, types.ErrConflict) {
+		t.Fatalf("Expected a conflict, got %v", err)
+	}
+	if !conflict.ActualUpdatedAt.Equal(updated.UpdatedAt) {
+		t.Errorf("Expected actual updated_at %v, got %v", updated.UpdatedAt, conflict.ActualUpdatedAt)
+	}
+	if current, _ := store.GetIssue(ctx, issue.ID); current.Title != "Renamed" {
+		t.Errorf("Conflicting update was written: %q", current.Title)
+	}
+
+	// Closing needs a reason, so it has to go through CloseIssue
+	err = store.UpdateIssueIfUnchanged(ctx, issue.ID, map[string]interface{}{"status": string(types.StatusClosed)}, updated.UpdatedAt, "test")
+	if !errors.Is(err, types.ErrIllegalTransition) {
+		t.Fatalf("Expected closing to be refused, got %v", err)
+	}
+	if err := store.CloseIssue(ctx, issue.ID, "Done", "test"); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+
+	// Reopening clears closed_at and records a reopened event, like Beads
+	closed, _ := store.GetIssue(ctx, issue.ID)
+	if err := store.UpdateIssueIfUnchanged(ctx, issue.ID, map[string]interface{}{"status": string(types.StatusOpen)}, closed.UpdatedAt, "test"); err != nil {
+		t.Fatalf("Reopening failed: %v", err)
+	}
+	reopened, _ := store.GetIssue(ctx, issue.ID)
+	if reopened.Status != types.StatusOpen || reopened.ClosedAt != nil {
+		t.Errorf("Expected open issue without closed_at, got %+v", reopened)
+	}
+	if n := countRows(t, store, `SELECT COUNT(*) FROM events WHERE issue_id = ? AND event_type = 'reopened'`, issue.ID); n != 1 {
+		t.Errorf("Expected a reopened event, got %d", n)
+	}
+
+	if err := store.UpdateIssueIfUnchanged(ctx, issue.ID, map[string]interface{}{"priority": 7}, reopened.UpdatedAt, "test"); err == nil {
+		t.Error("Expected invalid priority to be rejected")
+	}
+	if err := store.UpdateIssueIfUnchanged(ctx, issue.ID, map[string]interface{}{"id": "vc-1"}, reopened.UpdatedAt, "test"); err == nil {
+		t.Error("Expected unknown field to be rejected")
+	}
+	if err := store.UpdateIssueIfUnchanged(ctx, "vc-9999", map[string]interface{}{"title": "x"}, reopened.UpdatedAt, "test"); err == nil {
+		t.Error("Expected missing issue to fail")
+	}
+}
+
+// TestUpdateIssueIfUnchangedRace has two writers, each with its own
+// connection pool on a shared database, update the same version of an issue.
+// Exactly one may win; the other must see a conflict instead of silently
+// overwriting.
+func TestUpdateIssueIfUnchangedRace(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "shared.db")
+
+	first, err := NewVCStorage(ctx, dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create VC storage: %v", err)
+	}
+	defer first.Close()
+	second, err := NewVCStorage(ctx, dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open second VC storage: %v", err)
+	}
+	defer second.Close()
+
+	issue := &types.Issue{Title: "Shared", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := first.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue failed: %v", err)
+	}
+
+	for round := 0; round < 10; round++ {
+		read, err := first.GetIssue(ctx, issue.ID)
+		if err != nil {
+			t.Fatalf("GetIssue failed: %v", err)
+		}
+
+		var wg sync.WaitGroup
+		errs := make([]error, 2)
+		for i, store := range []*VCStorage{first, second} {
+			wg.Add(1)
+			go func(i int, store *VCStorage) {
+				defer wg.Done()
+				updates := map[string]interface{}{"notes": read.Notes + "x"}
+				errs[i] = store.UpdateIssueIfUnchanged(ctx, issue.ID, updates, read.UpdatedAt, "writer")
+			}(i, store)
+		}
+		wg.Wait()
+
+		wins := 0
+		for _, err := range errs {
+			switch {
+			case err == nil:
+				wins++
+			case !errors.Is(err, types.ErrConflict):
+				t.Fatalf("Round %d: unexpected error: %v", round, err)
+			}
+		}
+		if wins != 1 {
+			t.Fatalf("Round %d: expected exactly one writer to win, got %d (%v)", round, wins, errs)
+		}
+	}
+
+	final, _ := first.GetIssue(ctx, issue.ID)
+	if final.Notes != "xxxxxxxxxx" {
+		t.Errorf("Expected one note per round, got %q", final.Notes)
+	}
+}
@@ -0,0 +1,72 @@
+package beads
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// TestGetDatabaseStats verifies page and free-page accounting, and that
+// VACUUM releases the free pages left behind by deletes
+func TestGetDatabaseStats(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newCreateTestStore(t)
+
+	// Fill a scratch table, then delete it all to leave free pages behind
+	if _, err := store.db.ExecContext(ctx, `CREATE TABLE vacuum_scratch (data TEXT)`); err != nil {
+		t.Fatalf("Failed to create scratch table: %v", err)
+	}
+	payload := strings.Repeat("x", 4000)
+	for i := 0; i < 200; i++ {
+		if _, err := store.db.ExecContext(ctx, `INSERT INTO vacuum_scratch (data) VALUES (?)`, payload); err != nil {
+			t.Fatalf("Failed to insert scratch row: %v", err)
+		}
+	}
+
+	full, err := store.GetDatabaseStats(ctx)
+	if err != nil {
+		t.Fatalf("GetDatabaseStats failed: %v", err)
+	}
+	if full.PageSize <= 0 || full.PageCount <= 0 {
+		t.Fatalf("Expected positive page size and count, got %+v", full)
+	}
+	if full.SizeBytes != full.PageSize*full.PageCount {
+		t.Errorf("Expected size %d, got %d", full.PageSize*full.PageCount, full.SizeBytes)
+	}
+	if full.AutoVacuum != "none" {
+		t.Errorf("Expected auto_vacuum none, got %q", full.AutoVacuum)
+	}
+
+	if _, err := store.db.ExecContext(ctx, `DELETE FROM vacuum_scratch`); err != nil {
+		t.Fatalf("Failed to delete scratch rows: %v", err)
+	}
+	deleted, err := store.GetDatabaseStats(ctx)
+	if err != nil {
+		t.Fatalf("GetDatabaseStats failed: %v", err)
+	}
+	if deleted.FreelistCount < 100 {
+		t.Errorf("Expected at least 100 free pages after delete, got %d", deleted.FreelistCount)
+	}
+	if deleted.FreeFraction() <= 0.25 {
+		t.Errorf("Expected free fraction above 0.25, got %.2f", deleted.FreeFraction())
+	}
+
+	// Incremental vacuum is a no-op without auto_vacuum=INCREMENTAL
+	if err := store.IncrementalVacuum(ctx); err != nil {
+		t.Fatalf("IncrementalVacuum failed: %v", err)
+	}
+
+	if err := store.VacuumDatabase(ctx); err != nil {
+		t.Fatalf("VacuumDatabase failed: %v", err)
+	}
+	vacuumed, err := store.GetDatabaseStats(ctx)
+	if err != nil {
+		t.Fatalf("GetDatabaseStats failed: %v", err)
+	}
+	if vacuumed.FreelistCount != 0 {
+		t.Errorf("Expected no free pages after VACUUM, got %d", vacuumed.FreelistCount)
+	}
+	if vacuumed.SizeBytes >= deleted.SizeBytes {
+		t.Errorf("Expected VACUUM to shrink the database: %d -> %d bytes", deleted.SizeBytes, vacuumed.SizeBytes)
+	}
+}
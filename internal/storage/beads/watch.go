@@ -0,0 +1,300 @@
+package beads
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+)
+
+// ======================================================================
+// AGENT EVENT WATCHING (fan-out over vc_agent_events)
+// ======================================================================
+
+const (
+	// watchBufferSize is how many undelivered events a subscriber may queue
+	// before the oldest are dropped
+	watchBufferSize = 256
+
+	// defaultWatchPollInterval is how often the shared poller looks for events
+	// written by other processes
+	defaultWatchPollInterval = time.Second
+
+	// watchPollBatch caps how many rows a single poll reads
+	watchPollBatch = 500
+)
+
+// eventHub fans stored agent events out to WatchAgentEvents subscribers.
+//
+// Events stored through this VCStorage are published as soon as they are
+// written. A single poller, running while anyone is subscribed, reads rows
+// past its cursor to pick up events written by other processes sharing the
+// database. seen holds IDs published in-process that the poller has not
+// passed yet, so neither path delivers an event twice.
+type eventHub struct {
+	mu           sync.Mutex
+	subscribers  map[*eventSubscriber]struct{}
+	seen         map[int64]struct{}
+	pollCursor   int64
+	pollInterval time.Duration
+	stopPoll     context.CancelFunc
+	closed       bool
+}
+
+func newEventHub() *eventHub {
+	return &eventHub{
+		subscribers:  make(map[*eventSubscriber]struct{}),
+		seen:         make(map[int64]struct{}),
+		pollInterval: defaultWatchPollInterval,
+	}
+}
+
+// eventSubscriber queues events for one WatchAgentEvents channel. Pushing
+// never blocks: when the queue is full the oldest event is dropped and
+// counted, and the count is delivered as a watch_events_dropped event.
+type eventSubscriber struct {
+	filter events.EventFilter
+	out    chan *events.AgentEvent
+	notify chan struct{}
+	done   chan struct{}
+
+	mu      sync.Mutex
+	queue   []*events.AgentEvent
+	dropped int
+}
+
+// WatchAgentEvents streams agent events matching filter as they are stored,
+// including events written by other processes sharing the database. Only
+// events stored after the call are delivered; Limit and the ID cursors in the
+// filter are ignored. The channel is closed when ctx is canceled or the
+// storage is closed. Delivered events are shared between subscribers and must
+// not be modified.
+//
+// A subscriber that falls behind loses its oldest undelivered events rather
+// than slowing down writers; it then receives a watch_events_dropped event
+// whose "dropped" data field holds the number of events lost.
+func (s *VCStorage) WatchAgentEvents(ctx context.Context, filter events.EventFilter) (<-chan *events.AgentEvent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	sub := &eventSubscriber{
+		filter: filter,
+		out:    make(chan *events.AgentEvent),
+		notify: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+
+	hub := s.watch
+	hub.mu.Lock()
+	if hub.closed {
+		hub.mu.Unlock()
+		return nil, fmt.Errorf("storage is closed")
+	}
+	if len(hub.subscribers) == 0 {
+		// Start the poller at the newest row so only new events are delivered
+		var maxID int64
+		if err := s.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(id), 0) FROM vc_agent_events`).Scan(&maxID); err != nil {
+			hub.mu.Unlock()
+			return nil, fmt.Errorf("failed to read agent event cursor: %w", err)
+		}
+		hub.pollCursor = maxID
+		hub.seen = make(map[int64]struct{})
+
+		pollCtx, cancel := context.WithCancel(context.Background())
+		hub.stopPoll = cancel
+		go s.pollAgentEvents(pollCtx, hub.pollInterval)
+	}
+	hub.subscribers[sub] = struct{}{}
+	hub.mu.Unlock()
+
+	go func() {
+		sub.run(ctx)
+		s.unsubscribe(sub)
+	}()
+
+	return sub.out, nil
+}
+
+// publishAgentEvent delivers an event stored by this process to subscribers.
+func (s *VCStorage) publishAgentEvent(event *events.AgentEvent, id int64) {
+	hub := s.watch
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	if len(hub.subscribers) == 0 || id <= hub.pollCursor {
+		// Nobody is listening, or the poller already delivered it
+		return
+	}
+	if _, ok := hub.seen[id]; ok {
+		return
+	}
+	hub.seen[id] = struct{}{}
+
+	published := *event
+	published.ID = strconv.FormatInt(id, 10)
+	hub.fanOut(&published)
+}
+
+// pollAgentEvents picks up events written by other processes until ctx is canceled.
+func (s *VCStorage) pollAgentEvents(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	hub := s.watch
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		hub.mu.Lock()
+		cursor := hub.pollCursor
+		hub.mu.Unlock()
+
+		newEvents, err := s.GetAgentEvents(ctx, events.EventFilter{AfterID: cursor, Limit: watchPollBatch})
+		if err != nil {
+			if ctx.Err() == nil {
+				fmt.Fprintf(os.Stderr, "warning: failed to poll agent events: %v\n", err)
+			}
+			continue
+		}
+
+		hub.mu.Lock()
+		if ctx.Err() != nil {
+			// Stopped while querying; a newer poller may own the cursor now
+			hub.mu.Unlock()
+			return
+		}
+		for _, event := range newEvents {
+			id := event.Cursor()
+			if id > hub.pollCursor {
+				hub.pollCursor = id
+			}
+			if _, ok := hub.seen[id]; ok {
+				continue
+			}
+			hub.fanOut(event)
+		}
+		for id := range hub.seen {
+			if id <= hub.pollCursor {
+				delete(hub.seen, id)
+			}
+		}
+		hub.mu.Unlock()
+	}
+}
+
+// fanOut queues an event for every matching subscriber. Caller holds hub.mu.
+func (h *eventHub) fanOut(event *events.AgentEvent) {
+	for sub := range h.subscribers {
+		if sub.filter.Matches(event) {
+			sub.push(event)
+		}
+	}
+}
+
+// unsubscribe removes a subscriber and stops the poller when it was the last one.
+func (s *VCStorage) unsubscribe(sub *eventSubscriber) {
+	hub := s.watch
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	delete(hub.subscribers, sub)
+	if len(hub.subscribers) == 0 && hub.stopPoll != nil {
+		hub.stopPoll()
+		hub.stopPoll = nil
+	}
+}
+
+// closeWatchers ends every subscription and stops the poller.
+func (s *VCStorage) closeWatchers() {
+	hub := s.watch
+	hub.mu.Lock()
+	defer hub.mu.Unlock()
+
+	if hub.closed {
+		return
+	}
+	hub.closed = true
+	for sub := range hub.subscribers {
+		close(sub.done)
+	}
+	if hub.stopPoll != nil {
+		hub.stopPoll()
+		hub.stopPoll = nil
+	}
+}
+
+// push queues an event without blocking, dropping the oldest when full.
+func (sub *eventSubscriber) push(event *events.AgentEvent) {
+	sub.mu.Lock()
+	if len(sub.queue) >= watchBufferSize {
+		sub.queue = sub.queue[1:]
+		sub.dropped++
+	}
+	sub.queue = append(sub.queue, event)
+	sub.mu.Unlock()
+
+	select {
+	case sub.notify <- struct{}{}:
+	default:
+	}
+}
+
+// next returns the next event to deliver: a drop notice if events were lost,
+// otherwise the oldest queued event (nil if the queue is empty).
+func (sub *eventSubscriber) next() *events.AgentEvent {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+
+	if sub.dropped > 0 {
+		dropped := sub.dropped
+		sub.dropped = 0
+		return &events.AgentEvent{
+			Type:      events.EventTypeWatchEventsDropped,
+			Timestamp: time.Now(),
+			Severity:  events.SeverityWarning,
+			Message:   fmt.Sprintf("%d agent events dropped: subscriber fell behind", dropped),
+			Data:      map[string]interface{}{"dropped": dropped},
+		}
+	}
+	if len(sub.queue) == 0 {
+		return nil
+	}
+	event := sub.queue[0]
+	sub.queue = sub.queue[1:]
+	return event
+}
+
+// run delivers queued events until ctx is canceled or the storage is closed.
+func (sub *eventSubscriber) run(ctx context.Context) {
+	defer close(sub.out)
+
+	for {
+		event := sub.next()
+		if event == nil {
+			select {
+			case <-sub.notify:
+				continue
+			case <-ctx.Done():
+				return
+			case <-sub.done:
+				return
+			}
+		}
+
+		select {
+		case sub.out <- event:
+		case <-ctx.Done():
+			return
+		case <-sub.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package beads
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+)
+
+// receive reads one event or fails after a timeout
+func receive(t *testing.T, ch <-chan *events.AgentEvent) *events.AgentEvent {
+	t.Helper()
+	select {
+	case event, ok := <-ch:
+		if !ok {
+			t.Fatal("Watch channel closed unexpectedly")
+		}
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for event")
+		return nil
+	}
+}
+
+func TestWatchAgentEvents(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	store, err := NewVCStorage(ctx, dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create VC storage: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+	store.watch.pollInterval = 10 * time.Millisecond
+
+	// Events stored before subscribing are not replayed
+	old := &events.AgentEvent{Timestamp: time.Now(), Type: events.EventTypeProgress, Severity: events.SeverityInfo, Message: "before"}
+	if err := store.StoreAgentEvent(ctx, old); err != nil {
+		t.Fatalf("Failed to store event: %v", err)
+	}
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	ch, err := store.WatchAgentEvents(watchCtx, events.EventFilter{Type: events.EventTypeError})
+	if err != nil {
+		t.Fatalf("WatchAgentEvents failed: %v", err)
+	}
+
+	// In-process writes are published immediately; non-matching ones are filtered out
+	for _, e := range []*events.AgentEvent{
+		{Timestamp: time.Now(), Type: events.EventTypeProgress, Severity: events.SeverityInfo, Message: "filtered"},
+		{Timestamp: time.Now(), Type: events.EventTypeError, Severity: events.SeverityError, Message: "local"},
+	} {
+		if err := store.StoreAgentEvent(ctx, e); err != nil {
+			t.Fatalf("Failed to store event: %v", err)
+		}
+	}
+	local := receive(t, ch)
+	if local.Message != "local" || local.Cursor() == 0 {
+		t.Errorf("Expected the local error event with a storage ID, got %+v", local)
+	}
+
+	// Writes from another process sharing the database arrive via the poller
+	other, err := NewVCStorage(ctx, dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open second storage: %v", err)
+	}
+	defer func() { _ = other.Close() }()
+	if err := other.StoreAgentEvent(ctx, &events.AgentEvent{Timestamp: time.Now(), Type: events.EventTypeError, Severity: events.SeverityError, Message: "remote"}); err != nil {
+		t.Fatalf("Failed to store event: %v", err)
+	}
+	if remote := receive(t, ch); remote.Message != "remote" {
+		t.Errorf("Expected the remote event next (local event must not repeat), got %q", remote.Message)
+	}
+
+	// Canceling the context closes the channel
+	cancel()
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Error("Expected no further events after cancel")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Channel was not closed after cancel")
+	}
+}
+
+func TestWatchAgentEvents_ClosedByStorage(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewVCStorage(ctx, filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create VC storage: %v", err)
+	}
+
+	ch, err := store.WatchAgentEvents(ctx, events.EventFilter{})
+	if err != nil {
+		t.Fatalf("WatchAgentEvents failed: %v", err)
+	}
+	_ = store.Close()
+
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Error("Expected the channel to be closed")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Channel was not closed by Close")
+	}
+	if _, err := store.WatchAgentEvents(ctx, events.EventFilter{}); err == nil {
+		t.Error("Expected an error watching a closed storage")
+	}
+}
+
+func TestEventSubscriberDropsOldest(t *testing.T) {
+	sub := &eventSubscriber{notify: make(chan struct{}, 1)}
+	for i := 0; i < watchBufferSize+3; i++ {
+		sub.push(&events.AgentEvent{Message: fmt.Sprintf("event %d", i)})
+	}
+
+	notice := sub.next()
+	if notice.Type != events.EventTypeWatchEventsDropped || notice.Data["dropped"] != 3 {
+		t.Fatalf("Expected a drop notice for 3 events, got %+v", notice)
+	}
+	if first := sub.next(); first.Message != "event 3" {
+		t.Errorf("Expected the oldest kept event to be event 3, got %q", first.Message)
+	}
+}
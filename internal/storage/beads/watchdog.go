@@ -0,0 +1,96 @@
+package beads
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// WATCHDOG INTERVENTIONS (VC extension table: vc_watchdog_interventions)
+// ======================================================================
+
+// RecordIntervention persists a watchdog intervention to the audit trail
+func (s *VCStorage) RecordIntervention(ctx context.Context, record *types.InterventionRecord) error {
+	if record.CreatedAt.IsZero() {
+		return fmt.Errorf("created_at is required")
+	}
+
+	result, err := s.db.ExecContext(ctx, `
+		INSERT INTO vc_watchdog_interventions (issue_id, executor_instance_id, anomaly_type, severity, confidence, action, escalation_issue_id, success, outcome, policy_entry, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, nullIfEmpty(record.IssueID), record.ExecutorInstanceID, record.AnomalyType, record.Severity, record.Confidence,
+		record.Action, nullIfEmpty(record.EscalationIssueID), record.Success, record.Outcome, nullIfEmpty(record.PolicyEntry), record.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record intervention: %w", err)
+	}
+
+	if id, err := result.LastInsertId(); err == nil {
+		record.ID = id
+	}
+	return nil
+}
+
+// GetInterventions retrieves watchdog interventions, newest first
+func (s *VCStorage) GetInterventions(ctx context.Context, filter types.InterventionFilter) ([]*types.InterventionRecord, error) {
+	query := `
+		SELECT id, issue_id, executor_instance_id, anomaly_type, severity, confidence, action, escalation_issue_id, success, outcome, policy_entry, created_at
+		FROM vc_watchdog_interventions
+	`
+	var conditions []string
+	var args []interface{}
+
+	if filter.IssueID != "" {
+		conditions = append(conditions, "issue_id = ?")
+		args = append(args, filter.IssueID)
+	}
+	if !filter.Since.IsZero() {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, filter.Since)
+	}
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY created_at DESC, id DESC"
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query interventions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var records []*types.InterventionRecord
+	for rows.Next() {
+		var r types.InterventionRecord
+		var issueID, executorID, escalationID, outcome, policyEntry sql.NullString
+
+		if err := rows.Scan(&r.ID, &issueID, &executorID, &r.AnomalyType, &r.Severity, &r.Confidence,
+			&r.Action, &escalationID, &r.Success, &outcome, &policyEntry, &r.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan intervention: %w", err)
+		}
+
+		r.IssueID = issueID.String
+		r.ExecutorInstanceID = executorID.String
+		r.EscalationIssueID = escalationID.String
+		r.Outcome = outcome.String
+		r.PolicyEntry = policyEntry.String
+		records = append(records, &r)
+	}
+
+	return records, rows.Err()
+}
+
+// nullIfEmpty maps an empty string to SQL NULL
+func nullIfEmpty(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
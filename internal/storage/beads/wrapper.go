@@ -17,6 +17,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"os"
 	"strings"
 
 	beadsLib "github.com/steveyegge/beads"
@@ -26,62 +27,243 @@ import (
 
 // VCStorage wraps Beads storage and adds VC-specific extensions
 type VCStorage struct {
-	beadsLib.Storage       // Embedded - all Beads operations available
-	db               *sql.DB  // Direct DB access for VC extension tables
-	dbPath           string   // Path to database file
+	beadsLib.Storage           // Embedded - all Beads operations available
+	db               *timedDB  // Direct DB access for VC extension tables (see querylog.go)
+	dbPath           string    // Path to database file
+	watch            *eventHub // WatchAgentEvents subscribers
+	eventSearchIndex bool      // vc_agent_events_fts exists (see search.go)
+	memoryConn       *sql.Conn // Keeps a private in-memory database alive (see memory.go)
+	readOnly         bool      // Query-only: incompatible with this Beads (see compat.go), or SetReadOnly
+	snapshotDir      string    // Holds the copy a read-only database was opened from
 }
 
 // NewVCStorage creates a VC storage instance using Beads as the underlying storage
 func NewVCStorage(ctx context.Context, dbPath string) (*VCStorage, error) {
+	// 0. Refuse databases laid out by an incompatible Beads before Beads
+	// migrates them (see compat.go), or open them read-only if asked to
+	registerConnectionPragmas()
+	dsn := inMemoryDSN(dbPath)
+	compat, err := CheckBeadsCompat(ctx, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check Beads compatibility: %w", err)
+	}
+	readOnly := false
+	if !compat.Compatible() {
+		compatErr := &BeadsCompatError{Path: dbPath, Problems: compat.Problems}
+		if beadsCompatMode() != "warn" {
+			return nil, compatErr
+		}
+		warnIncompatible(compatErr)
+		readOnly = true
+	}
+
 	// 1. Open Beads storage (creates core tables: issues, dependencies, labels, etc.)
-	beadsStore, err := beadsLib.NewSQLiteStorage(dbPath)
+	// Connections it opens get VC's pragmas (see pragmas.go)
+	openPath, snapshotDir := dsn, ""
+	if readOnly {
+		// Beads writes when it opens a database, so it opens a copy
+		if snapshotDir, openPath, err = snapshotDatabase(ctx, dsn); err != nil {
+			return nil, err
+		}
+	}
+	beadsStore, err := beadsLib.NewSQLiteStorage(openPath)
 	if err != nil {
+		removeSnapshot(snapshotDir)
 		return nil, fmt.Errorf("failed to open Beads storage: %w", err)
 	}
+	// Everything else VC needs from Beads goes through this (see compat.go)
+	var backend beadsBackend = beadsStore
+	if readOnly {
+		makeReadOnly(backend.UnderlyingDB(), openPath)
+	}
+
+	// An in-memory database lives only as long as one of its connections
+	var memoryConn *sql.Conn
+	if isInMemoryPath(dsn) {
+		memoryConn, err = backend.UnderlyingConn(ctx)
+		if err != nil {
+			beadsStore.Close()
+			return nil, fmt.Errorf("failed to pin in-memory database connection: %w", err)
+		}
+	}
 
 	// 1.5. Initialize issue_prefix config if not already set (required by Beads for ID generation)
-	if prefix, err := beadsStore.GetConfig(ctx, "issue_prefix"); err != nil || prefix == "" {
+	if prefix, err := backend.GetConfig(ctx, "issue_prefix"); !readOnly && (err != nil || prefix == "") {
 		// Set default prefix "vc" for VC project
-		if err := beadsStore.SetConfig(ctx, "issue_prefix", "vc"); err != nil {
+		if err := backend.SetConfig(ctx, "issue_prefix", "vc"); err != nil {
 			beadsStore.Close()
 			return nil, fmt.Errorf("failed to set issue_prefix config: %w", err)
 		}
 	}
 
-	// 2. Get underlying DB connection pool for regular queries (cached)
-	db := beadsStore.UnderlyingDB()
-	if db == nil {
+	// 2. Get underlying DB connection pool for regular queries (cached),
+	// timed if slow query logging is on (see querylog.go)
+	pool := backend.UnderlyingDB()
+	if pool == nil {
 		return nil, fmt.Errorf("beads storage did not provide underlying DB")
 	}
+	slowQueryConfig, err := backend.GetConfig(ctx, SlowQueryConfigKey)
+	if err != nil {
+		beadsStore.Close()
+		return nil, fmt.Errorf("failed to read %s config: %w", SlowQueryConfigKey, err)
+	}
+	timer := newQueryTimer(slowQueryThreshold(slowQueryConfig))
+	db := &timedDB{DB: pool, timer: timer}
 
 	// 3. Create VC extension tables using scoped connection for DDL
 	// Use UnderlyingConn(ctx) for DDL operations as recommended by Beads
-	conn, err := beadsStore.UnderlyingConn(ctx)
+	conn, err := backend.UnderlyingConn(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get connection for DDL: %w", err)
 	}
 	defer conn.Close()
 
+	if err := verifyConnectionSettings(ctx, conn, dsn); err != nil {
+		return nil, fmt.Errorf("failed to configure SQLite connection: %w", err)
+	}
+
+	if readOnly {
+		// Leave the schema as it is; searches use the index if it is there
+		var eventSearchIndex bool
+		err := conn.QueryRowContext(ctx, `
+			SELECT COUNT(*) > 0 FROM sqlite_master
+			WHERE type = 'table' AND name = 'vc_agent_events_fts'
+		`).Scan(&eventSearchIndex)
+		if err != nil {
+			beadsStore.Close()
+			return nil, fmt.Errorf("failed to check for event search index: %w", err)
+		}
+		return &VCStorage{
+			Storage:          timeBeads(beadsStore, timer),
+			db:               db,
+			dbPath:           dbPath,
+			watch:            newEventHub(),
+			eventSearchIndex: eventSearchIndex,
+			memoryConn:       memoryConn,
+			readOnly:         true,
+			snapshotDir:      snapshotDir,
+		}, nil
+	}
+
+	// Refuse databases written by a newer vc before touching the schema
+	if err := checkSchemaVersion(ctx, conn); err != nil {
+		beadsStore.Close()
+		return nil, err
+	}
+
 	if err := createVCExtensionTables(ctx, conn); err != nil {
 		return nil, fmt.Errorf("failed to create VC extension tables: %w", err)
 	}
+	// System-level events are filed under the SYSTEM pseudo-issue (see system.go)
+	if _, err := ensureSystemIssue(ctx, conn); err != nil {
+		return nil, err
+	}
+
+	// 4. Create or drop the agent event search index as configured
+	searchConfig, err := backend.GetConfig(ctx, EventSearchIndexConfigKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s config: %w", EventSearchIndexConfigKey, err)
+	}
+	eventSearchIndex := eventSearchIndexEnabled(searchConfig)
+	if err := configureEventSearchIndex(ctx, conn, eventSearchIndex); err != nil {
+		return nil, err
+	}
+
+	// 5. Record the Beads compatibility level and library version
+	if err := recordBeadsCompat(ctx, backend); err != nil {
+		return nil, err
+	}
 
 	return &VCStorage{
-		Storage: beadsStore,
-		db:      db,
-		dbPath:  dbPath,
+		Storage:          timeBeads(beadsStore, timer),
+		db:               db,
+		dbPath:           dbPath,
+		watch:            newEventHub(),
+		eventSearchIndex: eventSearchIndex,
+		memoryConn:       memoryConn,
 	}, nil
 }
 
+// ReadOnly reports whether the store is read-only: the database is
+// incompatible with this vc's Beads library (see compat.go), or SetReadOnly
+// was called
+func (s *VCStorage) ReadOnly() bool {
+	return s.readOnly
+}
+
+// SetReadOnly makes the store query-only for the rest of the process: every
+// write fails, while reads keep seeing the live database, which WAL lets
+// executors write meanwhile. vc serve opens the database this way. It does
+// nothing for in-memory databases. Call it before using the store from
+// other goroutines.
+func (s *VCStorage) SetReadOnly() {
+	if s.readOnly || isInMemoryPath(s.dbPath) {
+		return
+	}
+	makeReadOnly(s.db.DB, s.dbPath)
+	s.readOnly = true
+}
+
 // Close closes the storage connection and releases resources.
 // This delegates to the embedded Beads storage which owns the database connection.
 // After Close() is called, all subsequent operations will fail.
+// With slow query logging on, the process's slow queries are recorded first
+// (see querylog.go).
 func (s *VCStorage) Close() error {
+	if err := s.recordSlowQueries(context.Background()); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to record slow queries: %v\n", err)
+	}
+	s.closeWatchers()
+	if s.memoryConn != nil {
+		_ = s.memoryConn.Close()
+	}
+	defer removeSnapshot(s.snapshotDir)
+
 	// Beads owns the DB connection (s.db is the same underlying connection)
 	// so we just delegate to Beads.Storage.Close() which closes the DB
 	return s.Storage.Close()
 }
 
+// Reopen drops the pooled connections, so the next ones open the database
+// file afresh. A long-running executor calls it when its storage calls keep
+// failing: after the file was replaced (vc restore) or came back on a flaky
+// mount, it carries on without a restart. A missing file is an error rather
+// than a new, empty database. The VC schema is brought up to date and the
+// SYSTEM issue recreated, as on open. In-memory databases and read-only
+// snapshots have no file to go back to, so it does nothing for them.
+func (s *VCStorage) Reopen(ctx context.Context) error {
+	if isInMemoryPath(s.dbPath) || s.snapshotDir != "" {
+		return nil
+	}
+	if _, err := os.Stat(s.dbPath); err != nil {
+		return fmt.Errorf("database file unavailable: %w", err)
+	}
+
+	// Connections checked out right now go back to the pool and are
+	// dropped with the next reopen at worst
+	s.db.SetMaxIdleConns(0)
+	s.db.SetMaxIdleConns(2) // database/sql's default
+
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to reopen database: %w", err)
+	}
+	defer conn.Close()
+	if s.readOnly {
+		return conn.PingContext(ctx)
+	}
+	if err := checkSchemaVersion(ctx, conn); err != nil {
+		return err
+	}
+	if err := createVCExtensionTables(ctx, conn); err != nil {
+		return fmt.Errorf("failed to create VC extension tables: %w", err)
+	}
+	if _, err := ensureSystemIssue(ctx, conn); err != nil {
+		return err
+	}
+	return nil
+}
+
 // createVCExtensionTables creates VC-specific tables in the Beads database
 // These tables extend Beads with mission workflow metadata
 // Uses a scoped connection (*sql.Conn) for DDL operations as recommended by Beads
@@ -92,10 +274,10 @@ func createVCExtensionTables(ctx context.Context, conn *sql.Conn) error {
 		return fmt.Errorf("failed to create VC extension tables: %w", err)
 	}
 
-	// Step 2: Run migrations to add missing columns to existing tables
-	// This must run BEFORE creating indexes that depend on those columns
-	if err := migrateAgentEventsTable(ctx, conn); err != nil {
-		return fmt.Errorf("failed to migrate agent_events table: %w", err)
+	// Step 2: Apply pending schema migrations (see migrations.go)
+	// This must run BEFORE creating indexes that depend on migrated columns
+	if err := runSchemaMigrations(ctx, conn); err != nil {
+		return err
 	}
 
 	// Step 3: Create indexes (now that all columns exist)
@@ -107,83 +289,6 @@ func createVCExtensionTables(ctx context.Context, conn *sql.Conn) error {
 	return nil
 }
 
-// migrateAgentEventsTable adds missing columns to existing vc_agent_events tables
-// Uses a scoped connection (*sql.Conn) for DDL operations as recommended by Beads
-func migrateAgentEventsTable(ctx context.Context, conn *sql.Conn) error {
-	// Check if executor_id column exists
-	var hasExecutorID bool
-	err := conn.QueryRowContext(ctx, `
-		SELECT COUNT(*) > 0
-		FROM pragma_table_info('vc_agent_events')
-		WHERE name = 'executor_id'
-	`).Scan(&hasExecutorID)
-	if err != nil {
-		return fmt.Errorf("failed to check for executor_id column: %w", err)
-	}
-
-	if !hasExecutorID {
-		// Add executor_id column
-		_, err = conn.ExecContext(ctx, `
-			ALTER TABLE vc_agent_events ADD COLUMN executor_id TEXT
-		`)
-		if err != nil {
-			return fmt.Errorf("failed to add executor_id column: %w", err)
-		}
-
-		// Create index
-		_, err = conn.ExecContext(ctx, `
-			CREATE INDEX IF NOT EXISTS idx_vc_agent_events_executor ON vc_agent_events(executor_id)
-		`)
-		if err != nil {
-			return fmt.Errorf("failed to create executor_id index: %w", err)
-		}
-	}
-
-	// Check if agent_id column exists
-	var hasAgentID bool
-	err = conn.QueryRowContext(ctx, `
-		SELECT COUNT(*) > 0
-		FROM pragma_table_info('vc_agent_events')
-		WHERE name = 'agent_id'
-	`).Scan(&hasAgentID)
-	if err != nil {
-		return fmt.Errorf("failed to check for agent_id column: %w", err)
-	}
-
-	if !hasAgentID {
-		// Add agent_id column
-		_, err = conn.ExecContext(ctx, `
-			ALTER TABLE vc_agent_events ADD COLUMN agent_id TEXT
-		`)
-		if err != nil {
-			return fmt.Errorf("failed to add agent_id column: %w", err)
-		}
-	}
-
-	// Check if source_line column exists
-	var hasSourceLine bool
-	err = conn.QueryRowContext(ctx, `
-		SELECT COUNT(*) > 0
-		FROM pragma_table_info('vc_agent_events')
-		WHERE name = 'source_line'
-	`).Scan(&hasSourceLine)
-	if err != nil {
-		return fmt.Errorf("failed to check for source_line column: %w", err)
-	}
-
-	if !hasSourceLine {
-		// Add source_line column
-		_, err = conn.ExecContext(ctx, `
-			ALTER TABLE vc_agent_events ADD COLUMN source_line INTEGER DEFAULT 0
-		`)
-		if err != nil {
-			return fmt.Errorf("failed to add source_line column: %w", err)
-		}
-	}
-
-	return nil
-}
-
 // VC-specific extension schema - TABLE DEFINITIONS ONLY
 // These tables coexist with Beads core tables in the same database
 // Following the IntelliJ/Android Studio extensibility model
@@ -237,7 +342,8 @@ CREATE TABLE IF NOT EXISTS vc_executor_instances (
     version TEXT NOT NULL,
     started_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
     last_heartbeat DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
-    status TEXT NOT NULL DEFAULT 'running' CHECK(status IN ('running', 'stopped', 'crashed'))
+    status TEXT NOT NULL DEFAULT 'running' CHECK(status IN ('running', 'stopped', 'crashed')),
+    metadata TEXT NOT NULL DEFAULT '{}'
 );
 
 -- Issue execution state (checkpoint/resume for long-running tasks)
@@ -249,6 +355,7 @@ CREATE TABLE IF NOT EXISTS vc_issue_execution_state (
     checkpoint_data TEXT,  -- JSON blob for agent state
     error_message TEXT,
     updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    previous_assignee TEXT,  -- Restored when the claim is released
     FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE,
     FOREIGN KEY (executor_instance_id) REFERENCES vc_executor_instances(id) ON DELETE SET NULL
 );
@@ -266,10 +373,28 @@ CREATE TABLE IF NOT EXISTS vc_execution_history (
     summary TEXT,
     output_sample TEXT,
     error_sample TEXT,
+    artifacts TEXT,                -- JSON array of artifact paths
+    phases TEXT,                   -- JSON array of {phase, duration_ms}
+    commit_hash TEXT,              -- Commit the attempt made
+    branch TEXT,                   -- Branch the commit was made on
+    merge_commit TEXT,             -- Merge of that branch into main
+    files TEXT,                    -- JSON array of the paths the attempt changed
     FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE,
     FOREIGN KEY (executor_instance_id) REFERENCES vc_executor_instances(id) ON DELETE SET NULL
 );
 
+-- Assessments (the AI supervisor's plan per execution attempt)
+CREATE TABLE IF NOT EXISTS vc_assessments (
+    issue_id TEXT NOT NULL,
+    attempt INTEGER NOT NULL,
+    assessment TEXT NOT NULL,     -- JSON types.Assessment
+    completed_steps TEXT,         -- JSON array of step indexes reported done
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (issue_id, attempt),
+    FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE
+);
+
 -- Gate baselines (cache of preflight gate results by commit hash)
 -- vc-198: Pre-flight quality gates to prevent work on broken baseline
 CREATE TABLE IF NOT EXISTS vc_gate_baselines (
@@ -280,6 +405,123 @@ CREATE TABLE IF NOT EXISTS vc_gate_baselines (
     results_json TEXT NOT NULL,  -- JSON map of gate results: {"test": {"passed": true, "output": "..."}, ...}
     sandbox_path TEXT            -- Optional: for future Phase 3 sandbox reuse
 );
+
+-- Watchdog interventions (durable audit trail of watchdog actions)
+CREATE TABLE IF NOT EXISTS vc_watchdog_interventions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    issue_id TEXT,               -- No FK: executor-level interventions have no issue
+    executor_instance_id TEXT,
+    anomaly_type TEXT NOT NULL,
+    severity TEXT NOT NULL,
+    confidence REAL NOT NULL DEFAULT 0,
+    action TEXT NOT NULL,         -- Intervention type (pause_agent, kill_agent, ...)
+    escalation_issue_id TEXT,
+    success BOOLEAN NOT NULL,
+    outcome TEXT,
+    policy_entry TEXT,            -- Intervention policy entry that selected the action
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Archived issues (soft delete: hidden from queries, history kept)
+CREATE TABLE IF NOT EXISTS vc_archived_issues (
+    issue_id TEXT PRIMARY KEY,
+    archived_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    archived_by TEXT NOT NULL,
+    FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE
+);
+
+-- Close resolutions (vc close --resolution): why an issue was closed. Issues
+-- closed without one, e.g. by bd, count as fixed.
+CREATE TABLE IF NOT EXISTS vc_issue_resolutions (
+    issue_id TEXT PRIMARY KEY,
+    resolution TEXT NOT NULL CHECK(resolution IN ('fixed', 'wontfix', 'duplicate', 'obsolete')),
+    resolved_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    resolved_by TEXT NOT NULL,
+    FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE
+);
+
+-- GitHub links (vc import/sync github): the GitHub issue a vc issue mirrors
+-- and the title and state both sides agreed on at the last sync
+CREATE TABLE IF NOT EXISTS vc_github_links (
+    issue_id TEXT PRIMARY KEY,
+    repo TEXT NOT NULL,
+    number INTEGER NOT NULL,
+    state TEXT NOT NULL DEFAULT 'active' CHECK(state IN ('active', 'deleted', 'transferred')),
+    synced_title TEXT NOT NULL DEFAULT '',
+    synced_closed BOOLEAN NOT NULL DEFAULT 0,
+    synced_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    moved_to TEXT,
+    UNIQUE (repo, number),
+    FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE
+);
+
+-- Issue metadata: JSON values under namespaced keys, for integrations
+CREATE TABLE IF NOT EXISTS vc_issue_metadata (
+    issue_id TEXT NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (issue_id, key),
+    FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE
+);
+
+-- GitHub sync cursors: upstream changes up to the cursor have been synced
+CREATE TABLE IF NOT EXISTS vc_github_sync (
+    repo TEXT PRIMARY KEY,
+    cursor DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Schedules (vc schedule): issue templates filed on a cron schedule or at a
+-- fixed interval. runs guards the window handling against two executors.
+CREATE TABLE IF NOT EXISTS vc_schedules (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    title TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    issue_type TEXT NOT NULL,
+    priority INTEGER NOT NULL,
+    labels TEXT NOT NULL DEFAULT '[]',
+    project TEXT NOT NULL DEFAULT '',
+    cron TEXT NOT NULL DEFAULT '',
+    interval_seconds INTEGER NOT NULL DEFAULT 0,
+    policy TEXT NOT NULL DEFAULT 'skip' CHECK(policy IN ('skip', 'file')),
+    enabled BOOLEAN NOT NULL DEFAULT 1,
+    runs INTEGER NOT NULL DEFAULT 0,
+    last_run_at DATETIME,
+    last_issue_id TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Working time logged on issues by hand (vc time), on top of the time their
+-- execution attempts took. actor is who logged or last edited the entry.
+CREATE TABLE IF NOT EXISTS vc_time_entries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    issue_id TEXT NOT NULL,
+    minutes INTEGER NOT NULL CHECK(minutes > 0),
+    note TEXT NOT NULL DEFAULT '',
+    actor TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE
+);
+
+-- Projects besides the default one (see projects.go), each with its own
+-- issue ID prefix
+CREATE TABLE IF NOT EXISTS vc_projects (
+    name TEXT PRIMARY KEY,
+    prefix TEXT NOT NULL UNIQUE,
+    root TEXT NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- The project of each issue not in the default project
+CREATE TABLE IF NOT EXISTS vc_issue_projects (
+    issue_id TEXT PRIMARY KEY,
+    project TEXT NOT NULL,
+    FOREIGN KEY (issue_id) REFERENCES issues(id) ON DELETE CASCADE,
+    FOREIGN KEY (project) REFERENCES vc_projects(name)
+);
 `
 
 // VC-specific extension schema - INDEX DEFINITIONS
@@ -294,6 +536,7 @@ CREATE INDEX IF NOT EXISTS idx_vc_mission_gates ON vc_mission_state(gates_status
 -- Agent events indexes
 CREATE INDEX IF NOT EXISTS idx_vc_agent_events_issue ON vc_agent_events(issue_id);
 CREATE INDEX IF NOT EXISTS idx_vc_agent_events_executor ON vc_agent_events(executor_id);
+CREATE INDEX IF NOT EXISTS idx_vc_agent_events_agent ON vc_agent_events(agent_id);
 CREATE INDEX IF NOT EXISTS idx_vc_agent_events_timestamp ON vc_agent_events(timestamp);
 CREATE INDEX IF NOT EXISTS idx_vc_agent_events_type ON vc_agent_events(type);
 
@@ -309,9 +552,26 @@ CREATE INDEX IF NOT EXISTS idx_vc_execution_executor ON vc_issue_execution_state
 CREATE INDEX IF NOT EXISTS idx_vc_history_issue ON vc_execution_history(issue_id);
 CREATE INDEX IF NOT EXISTS idx_vc_history_started ON vc_execution_history(started_at);
 
+-- Issue metadata indexes (IssueFilter.MetaEquals)
+CREATE INDEX IF NOT EXISTS idx_vc_issue_metadata_key ON vc_issue_metadata(key, value);
+
+-- Time entries indexes
+CREATE INDEX IF NOT EXISTS idx_vc_time_entries_issue ON vc_time_entries(issue_id);
+
 -- Gate baselines indexes
 CREATE INDEX IF NOT EXISTS idx_vc_gate_baselines_timestamp ON vc_gate_baselines(timestamp);
 CREATE INDEX IF NOT EXISTS idx_vc_gate_baselines_branch ON vc_gate_baselines(branch_name);
+
+-- Watchdog interventions indexes
+CREATE INDEX IF NOT EXISTS idx_vc_interventions_issue ON vc_watchdog_interventions(issue_id);
+CREATE INDEX IF NOT EXISTS idx_vc_interventions_created ON vc_watchdog_interventions(created_at);
+
+-- Issue query index (on the Beads issues table): serves the common
+-- "filter by status, sort by priority and age" search without a sort step
+CREATE INDEX IF NOT EXISTS idx_vc_issues_status_priority_created ON issues(status, priority, created_at DESC);
+
+-- Project scoping (IssueFilter.Project, WorkFilter.Project)
+CREATE INDEX IF NOT EXISTS idx_vc_issue_projects_project ON vc_issue_projects(project);
 `
 
 // ======================================================================
@@ -353,7 +613,7 @@ func (s *VCStorage) StoreAgentEvent(ctx context.Context, event *events.AgentEven
 		agentID = event.AgentID
 	}
 
-	_, err := s.db.ExecContext(ctx, `
+	result, err := s.db.ExecContext(ctx, `
 		INSERT INTO vc_agent_events (timestamp, issue_id, executor_id, agent_id, type, severity, message, data, source_line)
 		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`, event.Timestamp, issueID, executorID, agentID, event.Type, event.Severity, event.Message, dataJSON, event.SourceLine)
@@ -361,66 +621,58 @@ func (s *VCStorage) StoreAgentEvent(ctx context.Context, event *events.AgentEven
 	if err != nil {
 		return fmt.Errorf("failed to store agent event: %w", err)
 	}
+
+	// Notify in-process watchers right away instead of waiting for the poller
+	if id, err := result.LastInsertId(); err == nil {
+		s.publishAgentEvent(event, id)
+	}
 	return nil
 }
 
 // GetAgentEvents retrieves agent events matching the filter
 func (s *VCStorage) GetAgentEvents(ctx context.Context, filter events.EventFilter) ([]*events.AgentEvent, error) {
-	// Build WHERE clause dynamically based on filter
-	var whereClauses []string
-	var args []interface{}
+	query, args := s.buildAgentEventsQuery(filter)
 
-	if filter.IssueID != "" {
-		whereClauses = append(whereClauses, "issue_id = ?")
-		args = append(args, filter.IssueID)
-	}
-
-	if filter.Type != "" {
-		whereClauses = append(whereClauses, "type = ?")
-		args = append(args, filter.Type)
-	}
-
-	if filter.Severity != "" {
-		whereClauses = append(whereClauses, "severity = ?")
-		args = append(args, filter.Severity)
-	}
-
-	if !filter.AfterTime.IsZero() {
-		whereClauses = append(whereClauses, "timestamp >= ?")
-		args = append(args, filter.AfterTime)
-	}
-
-	if !filter.BeforeTime.IsZero() {
-		whereClauses = append(whereClauses, "timestamp <= ?")
-		args = append(args, filter.BeforeTime)
-	}
-
-	// Build the query
-	query := `SELECT id, timestamp, issue_id, executor_id, agent_id, type, severity, message, data, source_line FROM vc_agent_events`
-	if len(whereClauses) > 0 {
-		query += " WHERE " + strings.Join(whereClauses, " AND ")
+	var result []*events.AgentEvent
+	err := s.scanAgentEvents(ctx, query, args, func(e *events.AgentEvent) error {
+		result = append(result, e)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	query += " ORDER BY timestamp DESC"
+	return result, nil
+}
 
+// StreamAgentEvents calls fn for each agent event matching the filter, oldest
+// (lowest ID) first, straight from the rows cursor so exports of any size run
+// in constant memory. Limit caps the number of events. An error from fn stops
+// the scan and is returned.
+func (s *VCStorage) StreamAgentEvents(ctx context.Context, filter events.EventFilter, fn func(*events.AgentEvent) error) error {
+	where, args := s.agentEventsWhere(filter)
+	query := agentEventsSelect + where + " ORDER BY id ASC"
 	if filter.Limit > 0 {
 		query += " LIMIT ?"
 		args = append(args, filter.Limit)
 	}
+	return s.scanAgentEvents(ctx, query, args, fn)
+}
 
+// scanAgentEvents runs an agent event query and passes each row to fn.
+func (s *VCStorage) scanAgentEvents(ctx context.Context, query string, args []interface{}, fn func(*events.AgentEvent) error) error {
 	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query agent events: %w", err)
+		return fmt.Errorf("failed to query agent events: %w", err)
 	}
 	defer func() { _ = rows.Close() }()
 
-	var result []*events.AgentEvent
 	for rows.Next() {
 		var e events.AgentEvent
 		var issueID, executorID, agentID, severity sql.NullString
 		var dataJSON sql.NullString
 		var sourceLine sql.NullInt64
 		if err := rows.Scan(&e.ID, &e.Timestamp, &issueID, &executorID, &agentID, &e.Type, &severity, &e.Message, &dataJSON, &sourceLine); err != nil {
-			return nil, fmt.Errorf("failed to scan agent event: %w", err)
+			return fmt.Errorf("failed to scan agent event: %w", err)
 		}
 		if issueID.Valid {
 			e.IssueID = issueID.String
@@ -439,13 +691,108 @@ func (s *VCStorage) GetAgentEvents(ctx context.Context, filter events.EventFilte
 		}
 		if dataJSON.Valid && dataJSON.String != "" {
 			if err := json.Unmarshal([]byte(dataJSON.String), &e.Data); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal event data: %w", err)
+				return fmt.Errorf("failed to unmarshal event data: %w", err)
 			}
 		}
-		result = append(result, &e)
+		if err := fn(&e); err != nil {
+			return err
+		}
 	}
 
-	return result, rows.Err()
+	return rows.Err()
+}
+
+// agentEventsSelect selects the columns read by scanAgentEvents
+const agentEventsSelect = `SELECT id, timestamp, issue_id, executor_id, agent_id, type, severity, message, data, source_line FROM vc_agent_events`
+
+// buildAgentEventsQuery builds the SELECT for GetAgentEvents.
+func (s *VCStorage) buildAgentEventsQuery(filter events.EventFilter) (string, []interface{}) {
+	where, args := s.agentEventsWhere(filter)
+	query := agentEventsSelect + where
+
+	// Cursors page in id order: timestamps can tie or arrive out of order,
+	// ids can't. Reading forward is oldest first, paging back newest first.
+	switch {
+	case filter.BeforeID > 0:
+		query += " ORDER BY id DESC"
+	case filter.AfterID > 0:
+		query += " ORDER BY id ASC"
+	default:
+		query += " ORDER BY timestamp DESC"
+	}
+
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	return query, args
+}
+
+// agentEventsWhere builds the WHERE clause (with a leading space, or empty)
+// for an event filter. The issue, executor, agent, type and timestamp columns
+// are indexed (see vcExtensionIndexSchema); message text uses the search
+// index when it is enabled (see messageContainsClause).
+func (s *VCStorage) agentEventsWhere(filter events.EventFilter) (string, []interface{}) {
+	// Build WHERE clause dynamically based on filter
+	var whereClauses []string
+	var args []interface{}
+
+	if filter.IssueID != "" {
+		whereClauses = append(whereClauses, "issue_id = ?")
+		args = append(args, filter.IssueID)
+	}
+
+	if filter.ExecutorID != "" {
+		whereClauses = append(whereClauses, "executor_id = ?")
+		args = append(args, filter.ExecutorID)
+	}
+
+	if filter.AgentID != "" {
+		whereClauses = append(whereClauses, "agent_id = ?")
+		args = append(args, filter.AgentID)
+	}
+
+	if filter.Type != "" {
+		whereClauses = append(whereClauses, "type = ?")
+		args = append(args, filter.Type)
+	}
+
+	if filter.Severity != "" {
+		whereClauses = append(whereClauses, "severity = ?")
+		args = append(args, filter.Severity)
+	}
+
+	if !filter.AfterTime.IsZero() {
+		whereClauses = append(whereClauses, "timestamp >= ?")
+		args = append(args, filter.AfterTime)
+	}
+
+	if !filter.BeforeTime.IsZero() {
+		whereClauses = append(whereClauses, "timestamp <= ?")
+		args = append(args, filter.BeforeTime)
+	}
+
+	if filter.MessageContains != "" {
+		clause, clauseArgs := s.messageContainsClause(filter.MessageContains)
+		whereClauses = append(whereClauses, clause)
+		args = append(args, clauseArgs...)
+	}
+
+	if filter.AfterID > 0 {
+		whereClauses = append(whereClauses, "id > ?")
+		args = append(args, filter.AfterID)
+	}
+
+	if filter.BeforeID > 0 {
+		whereClauses = append(whereClauses, "id < ?")
+		args = append(args, filter.BeforeID)
+	}
+
+	if len(whereClauses) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(whereClauses, " AND "), args
 }
 
 // GetAgentEventsByIssue retrieves all agent events for a specific issue
@@ -38,6 +38,72 @@ func DiscoverDatabase() (string, error) {
 	return discoverDatabaseInDir(dir)
 }
 
+// DatabaseSetting is a database path set explicitly and where it was set,
+// e.g. {Path: "/work/.beads/vc.db", Source: "--db"}. An empty Path means
+// it wasn't set.
+type DatabaseSetting struct {
+	Path   string
+	Source string
+}
+
+// DatabaseChoice is the database ResolveDatabase picked and why
+type DatabaseChoice struct {
+	Path   string // Absolute, or the special value ":memory:"
+	Source string // The DatabaseSetting's Source, "" if discovered
+}
+
+// Reason tells why the database was picked, e.g. "set by VC_DB_PATH" or
+// "found in /work/.beads"
+func (c *DatabaseChoice) Reason() string {
+	if c.Source == "" {
+		return "found in " + filepath.Dir(c.Path)
+	}
+	return "set by " + c.Source
+}
+
+// ResolveDatabase picks the database the CLI and the executor open, the
+// first of:
+//
+//  1. flag, the --db flag
+//  2. the VC_DB_PATH environment variable
+//  3. config, the db setting of a config file
+//  4. .beads/*.db in the current directory (never a parent directory)
+//
+// With noDiscover, it returns an error instead of looking in the current
+// directory, so nothing but an explicit setting selects a database.
+func ResolveDatabase(flag, config DatabaseSetting, noDiscover bool) (*DatabaseChoice, error) {
+	env := DatabaseSetting{Path: os.Getenv("VC_DB_PATH"), Source: "VC_DB_PATH"}
+	for _, setting := range []DatabaseSetting{flag, env, config} {
+		if setting.Path == "" {
+			continue
+		}
+		path := setting.Path
+		if path != ":memory:" {
+			abs, err := filepath.Abs(path)
+			if err != nil {
+				return nil, fmt.Errorf("invalid database path %s (from %s): %w", path, setting.Source, err)
+			}
+			path = abs
+		}
+		return &DatabaseChoice{Path: path, Source: setting.Source}, nil
+	}
+
+	if noDiscover {
+		return nil, fmt.Errorf(
+			"no database given and discovery is disabled (--no-discover)\n" +
+				"  Use --db, VC_DB_PATH or the db config setting to name the database")
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current directory: %w", err)
+	}
+	path, err := discoverDatabaseInDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &DatabaseChoice{Path: path}, nil
+}
+
 // discoverDatabaseInDir checks for .beads/*.db in the specified directory only.
 // Does NOT walk up the directory tree (vc-240).
 func discoverDatabaseInDir(dir string) (string, error) {
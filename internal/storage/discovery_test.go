@@ -3,6 +3,7 @@ package storage
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -123,6 +124,130 @@ func TestDiscoverDatabase_WithEnvVar(t *testing.T) {
 	}
 }
 
+// makeBeadsDB creates dir/.beads/name and returns its path
+func makeBeadsDB(t *testing.T, dir, name string) string {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(dir, ".beads"), 0755); err != nil {
+		t.Fatalf("failed to create .beads dir: %v", err)
+	}
+	path := filepath.Join(dir, ".beads", name)
+	if err := os.WriteFile(path, []byte(""), 0644); err != nil {
+		t.Fatalf("failed to create database: %v", err)
+	}
+	return path
+}
+
+// TestResolveDatabase_Order verifies --db, then VC_DB_PATH, then the config
+// file, then discovery pick the database
+func TestResolveDatabase_Order(t *testing.T) {
+	t.Chdir(t.TempDir())
+	dir, err := os.Getwd() // The temporary directory may be behind a symlink
+	if err != nil {
+		t.Fatal(err)
+	}
+	discovered := makeBeadsDB(t, dir, "vc.db")
+	t.Setenv("VC_DB_PATH", "")
+
+	flag := DatabaseSetting{Path: "flag.db", Source: "--db"}
+	config := DatabaseSetting{Path: "/srv/config.db", Source: ".vc/config.yaml"}
+	check := func(flag, config DatabaseSetting, wantPath, wantReason string) {
+		t.Helper()
+		choice, err := ResolveDatabase(flag, config, false)
+		if err != nil {
+			t.Fatalf("ResolveDatabase: %v", err)
+		}
+		if choice.Path != wantPath || choice.Reason() != wantReason {
+			t.Errorf("ResolveDatabase() = %s (%s), want %s (%s)", choice.Path, choice.Reason(), wantPath, wantReason)
+		}
+	}
+
+	check(DatabaseSetting{}, DatabaseSetting{}, discovered, "found in "+filepath.Join(dir, ".beads"))
+	check(DatabaseSetting{}, config, "/srv/config.db", "set by .vc/config.yaml")
+	t.Setenv("VC_DB_PATH", ":memory:")
+	check(DatabaseSetting{}, config, ":memory:", "set by VC_DB_PATH")
+	check(flag, config, filepath.Join(dir, "flag.db"), "set by --db")
+}
+
+// TestResolveDatabase_NoDiscover verifies --no-discover fails instead of
+// using the database in the current directory, but not over explicit settings
+func TestResolveDatabase_NoDiscover(t *testing.T) {
+	dir := t.TempDir()
+	makeBeadsDB(t, dir, "vc.db")
+	t.Chdir(dir)
+	t.Setenv("VC_DB_PATH", "")
+
+	if _, err := ResolveDatabase(DatabaseSetting{}, DatabaseSetting{}, true); err == nil || !strings.Contains(err.Error(), "--no-discover") {
+		t.Errorf("Expected --no-discover to fail without a database given, got %v", err)
+	}
+	t.Setenv("VC_DB_PATH", "/tmp/env.db")
+	if choice, err := ResolveDatabase(DatabaseSetting{}, DatabaseSetting{}, true); err != nil || choice.Path != "/tmp/env.db" {
+		t.Errorf("ResolveDatabase() = %+v, %v, want VC_DB_PATH", choice, err)
+	}
+}
+
+// TestResolveDatabase_Nested verifies a nested project uses its own
+// database, and a directory without one never falls back to the enclosing
+// project's
+func TestResolveDatabase_Nested(t *testing.T) {
+	root := t.TempDir()
+	makeBeadsDB(t, root, "outer.db")
+	nested := filepath.Join(root, "vendor", "inner")
+	inner := makeBeadsDB(t, nested, "inner.db")
+	t.Setenv("VC_DB_PATH", "")
+
+	t.Chdir(nested)
+	if choice, err := ResolveDatabase(DatabaseSetting{}, DatabaseSetting{}, false); err != nil || choice.Path != inner {
+		t.Errorf("ResolveDatabase() in the nested project = %+v, %v, want %s", choice, err, inner)
+	}
+	t.Chdir(filepath.Join(root, "vendor"))
+	if choice, err := ResolveDatabase(DatabaseSetting{}, DatabaseSetting{}, false); err == nil {
+		t.Errorf("ResolveDatabase() below the outer project = %+v, want an error", choice)
+	}
+}
+
+// TestResolveDatabase_Symlinks verifies a project reached through a symlink,
+// or whose .beads is a symlink, resolves to the path as given, so its
+// project root is the directory vc runs in
+func TestResolveDatabase_Symlinks(t *testing.T) {
+	target := t.TempDir()
+	makeBeadsDB(t, target, "vc.db")
+	links := t.TempDir()
+	t.Setenv("VC_DB_PATH", "")
+
+	linked := filepath.Join(links, "project")
+	if err := os.Symlink(target, linked); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	choice, err := ResolveDatabase(DatabaseSetting{Path: filepath.Join(linked, ".beads", "vc.db"), Source: "--db"}, DatabaseSetting{}, false)
+	if err != nil {
+		t.Fatalf("ResolveDatabase: %v", err)
+	}
+	if root, err := GetProjectRoot(choice.Path); err != nil || root != linked {
+		t.Errorf("GetProjectRoot(%s) = %s, %v, want %s", choice.Path, root, err, linked)
+	}
+
+	// A checkout sharing another directory's .beads
+	shared := filepath.Join(links, "checkout")
+	if err := os.MkdirAll(shared, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(target, ".beads"), filepath.Join(shared, ".beads")); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(shared)
+	choice, err = ResolveDatabase(DatabaseSetting{}, DatabaseSetting{}, false)
+	if err != nil {
+		t.Fatalf("ResolveDatabase: %v", err)
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(cwd, ".beads", "vc.db"); choice.Path != want {
+		t.Errorf("ResolveDatabase() = %s, want %s", choice.Path, want)
+	}
+}
+
 // TestValidateDatabaseFreshness_FreshDatabase verifies no error when database is up to date (vc-173)
 func TestValidateDatabaseFreshness_FreshDatabase(t *testing.T) {
 	// Create test directory structure
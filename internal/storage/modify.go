@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// MaxModifyAttempts bounds how often ModifyIssue re-reads an issue after
+// losing a race with another writer
+const MaxModifyAttempts = 5
+
+// ModifyIssue performs a read-modify-write on an issue without losing
+// concurrent changes. It reads the issue, asks modify for the updates to
+// apply to that version, and writes them with UpdateIssueIfUnchanged. If
+// another writer got there first, it re-reads and calls modify again with the
+// fresh issue, up to MaxModifyAttempts times.
+//
+// modify may return an error to abort (e.g. when the concurrent change makes
+// the update invalid) or nil updates to skip the write. When every attempt
+// conflicts, the returned error matches types.ErrConflict.
+func ModifyIssue(ctx context.Context, store IssueStore, id string, actor string, modify func(issue *types.Issue) (map[string]interface{}, error)) error {
+	var err error
+	for attempt := 0; attempt < MaxModifyAttempts; attempt++ {
+		issue, getErr := store.GetIssue(ctx, id)
+		if getErr != nil {
+			return fmt.Errorf("failed to get issue %s: %w", id, getErr)
+		}
+		if issue == nil {
+			return fmt.Errorf("issue %s not found", id)
+		}
+
+		updates, modifyErr := modify(issue)
+		if modifyErr != nil {
+			return modifyErr
+		}
+		if len(updates) == 0 {
+			return nil
+		}
+
+		err = store.UpdateIssueIfUnchanged(ctx, id, updates, issue.UpdatedAt, actor)
+		if !errors.Is(err, types.ErrConflict) {
+			return err
+		}
+	}
+	return fmt.Errorf("giving up after %d attempts: %w", MaxModifyAttempts, err)
+}
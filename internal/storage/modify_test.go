@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// TestModifyIssueConcurrentAppends has two writers append notes to the same
+// issue at the same time. Every append must survive: the loser of each race
+// re-reads the notes and retries.
+func TestModifyIssueConcurrentAppends(t *testing.T) {
+	ctx := context.Background()
+	store := setupStorage(t, "sqlite")
+	defer func() { _ = store.Close() }()
+	issue := createTestIssues(t, ctx, store, 1)[0]
+
+	const appendsPerWriter = 10
+	var mu sync.Mutex
+	var applied []string
+	var wg sync.WaitGroup
+	for _, writer := range []string{"alice", "bob"} {
+		wg.Add(1)
+		go func(writer string) {
+			defer wg.Done()
+			for i := 0; i < appendsPerWriter; i++ {
+				note := fmt.Sprintf("%s-%d;", writer, i)
+				err := ModifyIssue(ctx, store, issue.ID, writer, func(current *types.Issue) (map[string]interface{}, error) {
+					return map[string]interface{}{"notes": current.Notes + note}, nil
+				})
+				// A writer may lose MaxModifyAttempts races in a row; that is
+				// reported as a conflict, never as a lost update
+				if err != nil && !errors.Is(err, types.ErrConflict) {
+					t.Errorf("ModifyIssue failed: %v", err)
+				}
+				if err == nil {
+					mu.Lock()
+					applied = append(applied, note)
+					mu.Unlock()
+				}
+			}
+		}(writer)
+	}
+	wg.Wait()
+
+	final, err := store.GetIssue(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if got := strings.Count(final.Notes, ";"); got != len(applied) {
+		t.Errorf("Expected %d notes, got %d: %q", len(applied), got, final.Notes)
+	}
+	for _, note := range applied {
+		if strings.Count(final.Notes, note) != 1 {
+			t.Errorf("Expected note %s exactly once in %q", note, final.Notes)
+		}
+	}
+}
+
+func TestModifyIssueAbort(t *testing.T) {
+	ctx := context.Background()
+	store := setupStorage(t, "sqlite")
+	defer func() { _ = store.Close() }()
+	issue := createTestIssues(t, ctx, store, 1)[0]
+
+	abort := errors.New("changed underneath")
+	err := ModifyIssue(ctx, store, issue.ID, "test", func(current *types.Issue) (map[string]interface{}, error) {
+		return nil, abort
+	})
+	if !errors.Is(err, abort) {
+		t.Errorf("Expected modify's error, got %v", err)
+	}
+
+	if err := ModifyIssue(ctx, store, "vc-9999", "test", func(*types.Issue) (map[string]interface{}, error) {
+		t.Error("modify called for a missing issue")
+		return nil, nil
+	}); err == nil {
+		t.Error("Expected missing issue to fail")
+	}
+}
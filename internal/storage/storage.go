@@ -2,45 +2,121 @@ package storage
 
 import (
 	"context"
+	"encoding/json"
 	"os"
+	"time"
 
 	"github.com/steveyegge/vc/internal/events"
 	"github.com/steveyegge/vc/internal/storage/beads"
 	"github.com/steveyegge/vc/internal/types"
 )
 
-// Storage defines the interface for issue storage backends
+// Storage defines the interface for issue storage backends. It is the union
+// of the focused interfaces below; the CLI and the executor use all of it,
+// while components that only touch part of the store (the AI supervisor,
+// the watchdog analyzer, deduplication) depend on the narrowest one they need.
 //
-// IMPORTANT: When adding methods to this interface, you MUST update ALL mock implementations.
-// Run ./scripts/find-storage-mocks.sh to find all files that need updates.
-// The following test files contain mockStorage implementations:
-//   - internal/ai/supervisor_test.go
-//   - internal/repl/conversation_test.go
-//   - internal/repl/conversation_integration_test.go
-//   - internal/watchdog/analyzer_test.go
+// IMPORTANT: When adding methods, put them on the sub-interface they belong
+// to and implement them on storagetest.FakeStorage, the in-memory test double
+// every package's tests share. Run ./scripts/find-storage-mocks.sh to check
+// that no other implementations have crept in.
+//
+// Cover new methods in the conformance suite (internal/storage/storagetest),
+// which every backend, including the fake, must pass.
 type Storage interface {
-	// Agent Events - structured events extracted from agent output
-	StoreAgentEvent(ctx context.Context, event *events.AgentEvent) error
-	GetAgentEvents(ctx context.Context, filter events.EventFilter) ([]*events.AgentEvent, error)
-	GetAgentEventsByIssue(ctx context.Context, issueID string) ([]*events.AgentEvent, error)
-	GetRecentAgentEvents(ctx context.Context, limit int) ([]*events.AgentEvent, error)
+	IssueStore
+	EventStore
+	InstanceStore
+	ExecutionStateStore
+	MaintenanceStore
+	SyncStore
+	ScheduleStore
+	TimeStore
 
-	// Event Cleanup - retention policy enforcement (vc-194)
-	CleanupEventsByAge(ctx context.Context, retentionDays, criticalRetentionDays, batchSize int) (int, error)
-	CleanupEventsByIssueLimit(ctx context.Context, perIssueLimit, batchSize int) (int, error)
-	CleanupEventsByGlobalLimit(ctx context.Context, globalLimit, batchSize int) (int, error)
-	GetEventCounts(ctx context.Context) (*types.EventCounts, error)
-	VacuumDatabase(ctx context.Context) error
+	// Config: a key-value table; config.Settings lists the known keys.
+	// GetConfig returns "" for an unset key. SetConfig rejects invalid
+	// values of known settings, and changing a setting the executor reads
+	// (config.IsExecutorSetting) stores a SYSTEM config_changed event.
+	GetConfig(ctx context.Context, key string) (string, error)
+	SetConfig(ctx context.Context, key, value string) error
+	// ListConfig returns the stored values whose key starts with prefix
+	// ("" for all)
+	ListConfig(ctx context.Context, prefix string) (map[string]string, error)
 
+	// Lifecycle
+	Close() error
+}
+
+// IssueStore covers the issue tracker itself: issues and missions, their
+// dependencies, labels and comments, and the queries built on them
+type IssueStore interface {
 	// Issues
 	CreateIssue(ctx context.Context, issue *types.Issue, actor string) error
+	// CreateIssueWithMetadata creates an issue with its labels and dependencies
+	// atomically: on error nothing is written. An empty IssueID or DependsOnID
+	// in a dependency refers to the new issue.
+	CreateIssueWithMetadata(ctx context.Context, issue *types.Issue, labels []string, deps []*types.Dependency, actor string) error
+	// CreateIssues creates a batch of issues (with optional labels and
+	// dependencies) in one transaction. Failed issues are reported in a
+	// *types.BatchCreateError keyed by batch index.
+	CreateIssues(ctx context.Context, issues []*types.Issue, actor string, opts types.CreateIssuesOptions) error
 	GetIssue(ctx context.Context, id string) (*types.Issue, error)
 	CreateMission(ctx context.Context, mission *types.Mission, actor string) error
 	GetMission(ctx context.Context, id string) (*types.Mission, error)
 	UpdateMission(ctx context.Context, id string, updates map[string]interface{}, actor string) error
 	UpdateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string) error
+	// UpdateIssueIfUnchanged applies updates only if the issue's updated_at
+	// still equals expectedUpdatedAt (the value the caller read). Otherwise
+	// nothing is written and a *types.ConflictError (errors.Is ErrConflict)
+	// is returned. Use ModifyIssue for read-modify-write with retries.
+	UpdateIssueIfUnchanged(ctx context.Context, id string, updates map[string]interface{}, expectedUpdatedAt time.Time, actor string) error
+	// UpdateIssue, UpdateIssueIfUnchanged and UpdateMission reject status
+	// changes outside the legal status graph with a *types.StatusTransitionError;
+	// closing needs CloseIssue and a non-empty reason.
 	CloseIssue(ctx context.Context, id string, reason string, actor string) error
+	// CloseIssueWithResolution closes an issue and records why (CloseIssue
+	// records types.ResolutionFixed); GetIssue reports it while the issue
+	// stays closed. Both then handle the blocked issues left with no open
+	// blocker ('blocks' dependencies) per the unblock_dependents setting:
+	// reopened with UnblockIssue (the default) or labeled
+	// types.ReadyToUnblockLabel, each with a comment naming the closed issue.
+	CloseIssueWithResolution(ctx context.Context, id string, reason string, resolution types.Resolution, actor string) error
+	// OverrideIssueStatus sets a status without the graph check (admin
+	// repair) and records the override in an audit comment.
+	OverrideIssueStatus(ctx context.Context, id string, status types.Status, reason string, actor string) error
 	SearchIssues(ctx context.Context, query string, filter types.IssueFilter) ([]*types.Issue, error)
+	// CountIssues counts SearchIssues matches, ignoring Limit and Offset
+	CountIssues(ctx context.Context, query string, filter types.IssueFilter) (int, error)
+	// CountIssuesByGroup counts CountIssues' matches per value of groupBy
+	// (see types.IssueGroupings) in one query: the status, priority, type or
+	// assignee ("" for none), or with types.GroupByLabelPrefix each prefix
+	// of the issue's labels (see types.LabelPrefix). An issue counts once
+	// under each of its prefixes, and under "" if it has none.
+	CountIssuesByGroup(ctx context.Context, query string, filter types.IssueFilter, groupBy string) (map[string]int, error)
+
+	// Archiving (soft delete): archived issues keep their history but are
+	// left out of SearchIssues (unless IncludeArchived), GetReadyWork,
+	// GetStatistics and the dependency queries
+	ArchiveIssue(ctx context.Context, id string, actor string) error
+	UnarchiveIssue(ctx context.Context, id string, actor string) error
+
+	// UnblockIssue reopens a blocked issue, removes types.AutoBlockedLabel
+	// and types.ReadyToUnblockLabel, and stores an issue_unblocked event saying whether the executor had
+	// blocked it. Issues that aren't blocked are an error.
+	UnblockIssue(ctx context.Context, id string, actor string) error
+
+	// EnsureSystemIssue creates the SYSTEM pseudo-issue (types.SystemIssueID)
+	// unless it exists, and reports whether it had to. Stores call it when
+	// they open a database; the executor calls it again on start.
+	EnsureSystemIssue(ctx context.Context) (bool, error)
+
+	// Projects: each has its own ID prefix, and issues created with
+	// Issue.Project get IDs from its sequence. Issues of no other project
+	// are in types.DefaultProject, whose prefix is the issue_prefix config.
+	// CreateProject rejects names and prefixes already taken.
+	CreateProject(ctx context.Context, project *types.Project) error
+	// ListProjects returns the default project first, then the others by name
+	ListProjects(ctx context.Context) ([]*types.Project, error)
 
 	// Dependencies
 	AddDependency(ctx context.Context, dep *types.Dependency, actor string) error
@@ -57,61 +133,223 @@ type Storage interface {
 	GetLabels(ctx context.Context, issueID string) ([]string, error)
 	GetIssuesByLabel(ctx context.Context, label string) ([]*types.Issue, error)
 
+	// Metadata: JSON values under namespaced keys (see types.ValidateMetaKey),
+	// deleted with their issue. SetIssueMeta validates the key, normalizes
+	// the value (types.NormalizeMetaValue) and replaces any previous value.
+	SetIssueMeta(ctx context.Context, issueID, key string, value json.RawMessage) error
+	// GetIssueMeta returns the issue's metadata (empty, not nil, if none)
+	GetIssueMeta(ctx context.Context, issueID string) (map[string]json.RawMessage, error)
+	// DeleteIssueMeta fails if the issue has no value under key
+	DeleteIssueMeta(ctx context.Context, issueID, key string) error
+
 	// Ready Work & Blocking
 	GetReadyWork(ctx context.Context, filter types.WorkFilter) ([]*types.Issue, error)
 	GetBlockedIssues(ctx context.Context) ([]*types.BlockedIssue, error)
-	GetReadyBlockers(ctx context.Context, limit int) ([]*types.Issue, error)
+	// GetReadyBlockers applies only the filter's Limit, Project and AgentsOnly
+	GetReadyBlockers(ctx context.Context, filter types.WorkFilter) ([]*types.Issue, error)
 
 	// Epic Completion (vc-232)
 	IsEpicComplete(ctx context.Context, epicID string) (bool, error)
 
-	// Mission Context (vc-233)
+	// Mission Context (vc-233). For a task in a phase the context names
+	// the phase and whether it waits: a mission runs its phases one at a
+	// time, in order, and none while it is blocked. GetReadyWork leaves
+	// waiting tasks out.
 	GetMissionForTask(ctx context.Context, taskID string) (*types.MissionContext, error)
 
 	// Quality Gate Workers (vc-252)
 	GetMissionsNeedingGates(ctx context.Context) ([]*types.Issue, error)
 
+	// Mission State: the vc_mission_state row of a mission or phase. Unlike
+	// UpdateMission these write no audit event; they are the executor's
+	// bookkeeping. All fail if the issue has no mission state.
+	// UpdateMissionState updates mission state columns only (sandbox_path,
+	// branch_name, iteration_count, ...); any other key is rejected.
+	UpdateMissionState(ctx context.Context, issueID string, updates map[string]interface{}) error
+	// SetMissionGatesStatus records a types.GatesStatus* value; any status
+	// but pending also stamps last_gates_run
+	SetMissionGatesStatus(ctx context.Context, issueID string, status string) error
+	// AdvanceMissionPhase moves current_phase on by one and returns the new
+	// value. It fails once current_phase has reached phase_count.
+	AdvanceMissionPhase(ctx context.Context, issueID string) (int, error)
+	// GetMissionsByStatus returns the missions (subtype mission) whose issue
+	// has the given status, by priority then age
+	GetMissionsByStatus(ctx context.Context, status types.Status) ([]*types.Mission, error)
+	// GetMissionPhases returns the non-archived phases (subtype phase
+	// children) of a mission in execution order (see types.OrderPhases).
+	// current_phase indexes this list.
+	GetMissionPhases(ctx context.Context, missionID string) ([]*types.Issue, error)
+
+	// Plan approval: until a mission that requires approval is approved,
+	// GetReadyWork leaves out its work and ClaimIssue refuses the mission
+	// and its work with a *types.AwaitingApprovalError (errors.Is
+	// ErrAwaitingApproval). Both record a comment and an agent event.
+	// ApproveMission records the actor and time, and reopens a mission
+	// blocked by an earlier rejection.
+	ApproveMission(ctx context.Context, missionID, actor, comment string) error
+	// RejectMission clears any approval and blocks the mission; the
+	// reviewer's comment is required
+	RejectMission(ctx context.Context, missionID, actor, comment string) error
+
 	// Events
 	AddComment(ctx context.Context, issueID, actor, comment string) error
 	GetEvents(ctx context.Context, issueID string, limit int) ([]*types.Event, error)
 
 	// Statistics
 	GetStatistics(ctx context.Context) (*types.Statistics, error)
+	// GetActorStatistics summarizes activity per actor (humans, the AI
+	// supervisor and each executor instance) since the given time
+	GetActorStatistics(ctx context.Context, since time.Time) ([]*types.ActorStatistics, error)
+}
+
+// EventStore holds the structured agent events extracted from agent output
+type EventStore interface {
+	StoreAgentEvent(ctx context.Context, event *events.AgentEvent) error
+	GetAgentEvents(ctx context.Context, filter events.EventFilter) ([]*events.AgentEvent, error)
+	GetAgentEventsByIssue(ctx context.Context, issueID string) ([]*events.AgentEvent, error)
+	GetRecentAgentEvents(ctx context.Context, limit int) ([]*events.AgentEvent, error)
+	StreamAgentEvents(ctx context.Context, filter events.EventFilter, fn func(*events.AgentEvent) error) error
+	WatchAgentEvents(ctx context.Context, filter events.EventFilter) (<-chan *events.AgentEvent, error)
+}
 
-	// Executor Instances
+// InstanceStore tracks executor instances and their heartbeats
+type InstanceStore interface {
 	RegisterInstance(ctx context.Context, instance *types.ExecutorInstance) error
 	MarkInstanceStopped(ctx context.Context, instanceID string) error
 	UpdateHeartbeat(ctx context.Context, instanceID string) error
 	GetActiveInstances(ctx context.Context) ([]*types.ExecutorInstance, error)
 	CleanupStaleInstances(ctx context.Context, staleThreshold int) (int, error)
 	DeleteOldStoppedInstances(ctx context.Context, olderThanSeconds int, maxToKeep int) (int, error)
+}
 
+// ExecutionStateStore records what executors do with the issues they claim:
+// claims, checkpoints, attempts, assessments and watchdog interventions
+type ExecutionStateStore interface {
 	// Issue Execution State (Checkpoint/Resume)
+	// ClaimIssue makes the executor's short ID (types.ExecutorShortID) the
+	// assignee; the releases, and stale instance cleanup, restore the
+	// previous one unless the issue was reassigned meanwhile
 	ClaimIssue(ctx context.Context, issueID, executorInstanceID string) error
 	GetExecutionState(ctx context.Context, issueID string) (*types.IssueExecutionState, error)
 	UpdateExecutionState(ctx context.Context, issueID string, state types.ExecutionState) error
-	SaveCheckpoint(ctx context.Context, issueID string, checkpointData interface{}) error
-	GetCheckpoint(ctx context.Context, issueID string) (string, error)
+	// SaveCheckpoint stores the issue's checkpoint in the current format
+	// version; a nil checkpoint clears it
+	SaveCheckpoint(ctx context.Context, issueID string, checkpoint *types.CheckpointV1) error
+	// GetCheckpoint returns (nil, nil) if the issue has no checkpoint, and an
+	// error wrapping types.ErrCheckpointVersionMismatch if the stored one
+	// can't be interpreted (discard it and start fresh)
+	GetCheckpoint(ctx context.Context, issueID string) (*types.CheckpointV1, error)
 	ReleaseIssue(ctx context.Context, issueID string) error
 	ReleaseIssueAndReopen(ctx context.Context, issueID, actor, errorComment string) error
 
 	// Execution History
 	GetExecutionHistory(ctx context.Context, issueID string) ([]*types.ExecutionAttempt, error)
+	// RecordExecutionAttempt inserts an attempt and sets its ID
 	RecordExecutionAttempt(ctx context.Context, attempt *types.ExecutionAttempt) error
+	// UpdateExecutionAttempt writes the outcome (completed_at, success, exit
+	// code, summary, samples, artifacts, phases, commit, merge commit and
+	// changed files) of the attempt with attempt.ID
+	UpdateExecutionAttempt(ctx context.Context, attempt *types.ExecutionAttempt) error
+	// GetExecutionPhases returns the attempts started at or after since that
+	// recorded phase timings, oldest first, with only their ID, issue,
+	// attempt number, start, success and phases set
+	GetExecutionPhases(ctx context.Context, since time.Time) ([]*types.ExecutionAttempt, error)
 
-	// Config
-	GetConfig(ctx context.Context, key string) (string, error)
-	SetConfig(ctx context.Context, key, value string) error
+	// Assessments (the supervisor's plan per execution attempt)
+	// SaveAssessment stores the record, replacing the one for the same
+	// issue and attempt, and sets its timestamps
+	SaveAssessment(ctx context.Context, record *types.AssessmentRecord) error
+	// GetLatestAssessment returns the record with the highest attempt, or
+	// (nil, nil) if the issue was never assessed
+	GetLatestAssessment(ctx context.Context, issueID string) (*types.AssessmentRecord, error)
 
-	// Lifecycle
-	Close() error
+	// Watchdog Interventions (durable audit trail)
+	RecordIntervention(ctx context.Context, record *types.InterventionRecord) error
+	GetInterventions(ctx context.Context, filter types.InterventionFilter) ([]*types.InterventionRecord, error)
+}
+
+// MaintenanceStore enforces event retention and keeps the database file in
+// shape
+type MaintenanceStore interface {
+	// Event Cleanup - retention policy enforcement (vc-194)
+	CleanupEventsByAge(ctx context.Context, retentionDays, criticalRetentionDays, batchSize int) (int, error)
+	CleanupEventsByIssueLimit(ctx context.Context, perIssueLimit, batchSize int) (int, error)
+	CleanupEventsByGlobalLimit(ctx context.Context, globalLimit, batchSize int) (int, error)
+	GetEventCounts(ctx context.Context) (*types.EventCounts, error)
+	// CleanupExecutionHistory deletes attempts beyond the newest
+	// keepPerIssue of their issue that are also older than retentionDays,
+	// keeping those of blocked and escalated issues, and returns them so
+	// their artifacts can be removed
+	CleanupExecutionHistory(ctx context.Context, keepPerIssue, retentionDays, batchSize int) ([]*types.ExecutionAttempt, error)
+	VacuumDatabase(ctx context.Context) error
+	// IncrementalVacuum returns the freelist pages to the filesystem when
+	// auto_vacuum is incremental (a no-op otherwise); much cheaper than VACUUM
+	IncrementalVacuum(ctx context.Context) error
+	// GetDatabaseStats reports page counts, free pages and file size
+	GetDatabaseStats(ctx context.Context) (*types.DatabaseStats, error)
+	// Reopen drops open connections so the next ones open the database
+	// file afresh (after it was replaced or its mount came back). It fails
+	// rather than create a new database when the file is missing.
+	Reopen(ctx context.Context) error
+}
+
+// SyncStore links issues to the GitHub issues they mirror and remembers how
+// far each repository has been synced (vc import github, vc sync github)
+type SyncStore interface {
+	// SaveGitHubLink creates or replaces the link of link.IssueID
+	SaveGitHubLink(ctx context.Context, link *types.GitHubLink) error
+	// GetGitHubLink returns (nil, nil) if the issue isn't linked
+	GetGitHubLink(ctx context.Context, issueID string) (*types.GitHubLink, error)
+	// GetGitHubLinks returns the repository's links by number
+	GetGitHubLinks(ctx context.Context, repo string) ([]*types.GitHubLink, error)
+	// GetGitHubSyncCursor returns the zero time if the repository was never
+	// synced
+	GetGitHubSyncCursor(ctx context.Context, repo string) (time.Time, error)
+	SetGitHubSyncCursor(ctx context.Context, repo string, cursor time.Time) error
+}
+
+// ScheduleStore keeps the schedules that file recurring issues (vc
+// schedule) and records the windows they handled
+type ScheduleStore interface {
+	// CreateSchedule validates the schedule and sets its ID and CreatedAt
+	CreateSchedule(ctx context.Context, schedule *types.Schedule) error
+	// GetSchedule returns (nil, nil) if there is no such schedule
+	GetSchedule(ctx context.Context, id int64) (*types.Schedule, error)
+	// ListSchedules returns every schedule by ID
+	ListSchedules(ctx context.Context) ([]*types.Schedule, error)
+	DeleteSchedule(ctx context.Context, id int64) error
+	SetScheduleEnabled(ctx context.Context, id int64, enabled bool) error
+	// ClaimScheduleRun records a window handled at runAt if the schedule
+	// has still handled runs windows, incrementing Runs. It reports false
+	// if another executor got there first.
+	ClaimScheduleRun(ctx context.Context, id int64, runs int, runAt time.Time) (bool, error)
+	// SetScheduleIssue records the issue filed for the last window
+	SetScheduleIssue(ctx context.Context, id int64, issueID string) error
+}
+
+// TimeStore keeps the working time logged on issues by hand (vc time). The
+// time taken by execution attempts comes from the execution history.
+type TimeStore interface {
+	// AddTimeEntry validates the entry, fails if its issue doesn't exist,
+	// and sets its ID, CreatedAt and UpdatedAt
+	AddTimeEntry(ctx context.Context, entry *types.TimeEntry) error
+	// GetTimeEntry returns (nil, nil) if there is no such entry
+	GetTimeEntry(ctx context.Context, id int64) (*types.TimeEntry, error)
+	// ListTimeEntries returns the issue's entries ("" for every issue's),
+	// oldest first
+	ListTimeEntries(ctx context.Context, issueID string) ([]*types.TimeEntry, error)
+	// UpdateTimeEntry replaces the entry's minutes and note, recording actor
+	// as its last editor
+	UpdateTimeEntry(ctx context.Context, id int64, minutes int, note, actor string) error
+	DeleteTimeEntry(ctx context.Context, id int64) error
 }
 
 // Config holds database configuration
 type Config struct {
 	// Path is the SQLite database file path
 	// Default: ".beads/vc.db"
-	// Special value ":memory:" creates an in-memory database (useful for tests)
+	// Special value ":memory:" creates a private in-memory database (useful
+	// for tests and demos); each store opened on it starts empty
 	Path string
 }
 
@@ -147,3 +385,10 @@ func NewStorage(ctx context.Context, cfg *Config) (Storage, error) {
 
 	return beads.NewVCStorage(ctx, cfg.Path)
 }
+
+// NewInMemoryStorage opens a new, empty in-memory SQLite store. Nothing is
+// written to disk and the data is gone once the store is closed; stores
+// opened this way don't share data with each other.
+func NewInMemoryStorage(ctx context.Context) (Storage, error) {
+	return NewStorage(ctx, &Config{Path: ":memory:"})
+}
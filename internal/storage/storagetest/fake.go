@@ -0,0 +1,294 @@
+package storagetest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// FakeStorage is an in-memory storage.Storage for unit tests that don't want
+// SQLite. It keeps everything in maps but behaves like the real store (it
+// passes RunStorageTests): blocked and claimed issues drop out of
+// GetReadyWork, closing needs a reason, illegal status changes fail, and so
+// on. Tests that only care about the calls made can ignore all of that.
+//
+// On top of the contract, FakeStorage records every call (Calls, CallCount),
+// can make methods fail (FailOn, FailWhen) and can be seeded without
+// validation or audit events (AddIssue). It is safe for concurrent use.
+//
+// Keep it in sync with storage.Storage: the compile-time check below fails
+// when the interface grows.
+type FakeStorage struct {
+	mu sync.Mutex
+
+	issues   map[string]*types.Issue
+	missions map[string]*types.Mission // Mission state for issues with a subtype
+	labels   map[string][]string       // Sorted
+	deps     []*types.Dependency
+	events   []*types.Event
+	nextID   int // Issue ID counter
+	eventID  int64
+
+	projects   map[string]*types.Project // Without the default project
+	projectIDs map[string]int            // Issue ID counters by project
+
+	agentEvents  []*events.AgentEvent
+	agentEventID int64
+	watchers     map[*fakeWatcher]struct{}
+
+	instances     map[string]*types.ExecutorInstance
+	execStates    map[string]*types.IssueExecutionState
+	attempts      []*types.ExecutionAttempt
+	attemptID     int64
+	assessments   map[string][]*types.AssessmentRecord // By issue, in attempt order
+	interventions []*types.InterventionRecord
+	interventID   int64
+	config        map[string]string
+
+	githubLinks   map[string]*types.GitHubLink // By issue
+	githubCursors map[string]time.Time         // By repository
+
+	meta map[string]map[string]json.RawMessage // By issue, then key
+
+	schedules  map[int64]*types.Schedule
+	scheduleID int64
+
+	timeEntries map[int64]*types.TimeEntry
+	timeEntryID int64
+
+	closed bool
+
+	// hooks guards the call log and failure hooks, separately from mu so a
+	// hook may call back into the store
+	hooks    sync.Mutex
+	calls    []Call
+	failures map[string]func(args []interface{}) error
+}
+
+var _ storage.Storage = (*FakeStorage)(nil)
+
+// errClosed is returned by every method after Close
+var errClosed = errors.New("storage is closed")
+
+// Call is one recorded FakeStorage method call. Args are the method's
+// arguments without the context, in order.
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// NewFakeStorage returns an empty FakeStorage
+func NewFakeStorage() *FakeStorage {
+	return &FakeStorage{
+		issues:        map[string]*types.Issue{types.SystemIssueID: types.NewSystemIssue(time.Now())},
+		missions:      make(map[string]*types.Mission),
+		labels:        make(map[string][]string),
+		watchers:      make(map[*fakeWatcher]struct{}),
+		instances:     make(map[string]*types.ExecutorInstance),
+		execStates:    make(map[string]*types.IssueExecutionState),
+		assessments:   make(map[string][]*types.AssessmentRecord),
+		config:        make(map[string]string),
+		projects:      make(map[string]*types.Project),
+		projectIDs:    make(map[string]int),
+		githubLinks:   make(map[string]*types.GitHubLink),
+		githubCursors: make(map[string]time.Time),
+		meta:          make(map[string]map[string]json.RawMessage),
+		schedules:     make(map[int64]*types.Schedule),
+		timeEntries:   make(map[int64]*types.TimeEntry),
+		failures:      make(map[string]func(args []interface{}) error),
+	}
+}
+
+// FailOn makes every later call to method (e.g. "GetReadyWork") return err.
+// A nil err removes the failure.
+func (f *FakeStorage) FailOn(method string, err error) {
+	if err == nil {
+		f.FailWhen(method, nil)
+		return
+	}
+	f.FailWhen(method, func([]interface{}) error { return err })
+}
+
+// FailWhen makes calls to method return whatever fn returns for the call's
+// arguments (as in Call.Args); a nil result lets the call through. A nil fn
+// removes the failure. CreateIssues and CreateIssueWithMetadata also consult
+// the "CreateIssue" hook once per issue, with (issue, actor), so a test can
+// fail single issues of a batch.
+func (f *FakeStorage) FailWhen(method string, fn func(args []interface{}) error) {
+	f.hooks.Lock()
+	defer f.hooks.Unlock()
+	if fn == nil {
+		delete(f.failures, method)
+		return
+	}
+	f.failures[method] = fn
+}
+
+// Calls returns the recorded calls to method, oldest first. An empty method
+// returns every call.
+func (f *FakeStorage) Calls(method string) []Call {
+	f.hooks.Lock()
+	defer f.hooks.Unlock()
+	var result []Call
+	for _, call := range f.calls {
+		if method == "" || call.Method == method {
+			result = append(result, call)
+		}
+	}
+	return result
+}
+
+// CallCount returns how many times method was called
+func (f *FakeStorage) CallCount(method string) int {
+	return len(f.Calls(method))
+}
+
+// ResetCalls forgets the recorded calls
+func (f *FakeStorage) ResetCalls() {
+	f.hooks.Lock()
+	defer f.hooks.Unlock()
+	f.calls = nil
+}
+
+// AddIssue seeds issues as they are: no validation, no audit events, no
+// recorded call. Missing IDs and timestamps are filled in, and issues with a
+// subtype get an empty mission state. Use CreateIssue to go through the
+// contract instead.
+func (f *FakeStorage) AddIssue(issues ...*types.Issue) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	now := time.Now()
+	for _, issue := range issues {
+		if issue.Project == "" {
+			issue.Project = types.DefaultProject
+		}
+		if issue.ID == "" {
+			issue.ID = f.newIssueID(issue.Project)
+		}
+		if issue.CreatedAt.IsZero() {
+			issue.CreatedAt = now
+		}
+		if issue.UpdatedAt.IsZero() {
+			issue.UpdatedAt = issue.CreatedAt
+		}
+		f.issues[issue.ID] = copyIssue(issue)
+		if issue.IssueSubtype != "" && f.missions[issue.ID] == nil {
+			f.missions[issue.ID] = &types.Mission{}
+		}
+	}
+}
+
+// begin records a call and applies the failure hooks and the closed check.
+// Every Storage method starts with it.
+func (f *FakeStorage) begin(method string, args ...interface{}) error {
+	f.hooks.Lock()
+	f.calls = append(f.calls, Call{Method: method, Args: args})
+	f.hooks.Unlock()
+
+	if err := f.injected(method, args...); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return errClosed
+	}
+	return nil
+}
+
+// injected returns the error the failure hook for method gives for args
+func (f *FakeStorage) injected(method string, args ...interface{}) error {
+	f.hooks.Lock()
+	fn := f.failures[method]
+	f.hooks.Unlock()
+	if fn == nil {
+		return nil
+	}
+	return fn(args)
+}
+
+// Close stops the watchers; every later call fails
+func (f *FakeStorage) Close() error {
+	f.hooks.Lock()
+	f.calls = append(f.calls, Call{Method: "Close"})
+	f.hooks.Unlock()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed = true
+	for w := range f.watchers {
+		f.stopWatcher(w)
+	}
+	return nil
+}
+
+// newIssueID returns the next free ID of the project: vc-N for the default
+// project, <prefix>-N for the others. Caller holds mu.
+func (f *FakeStorage) newIssueID(project string) string {
+	for {
+		var id string
+		if p := f.projects[project]; p != nil {
+			f.projectIDs[project]++
+			id = fmt.Sprintf("%s-%d", p.Prefix, f.projectIDs[project])
+		} else {
+			f.nextID++
+			id = fmt.Sprintf("vc-%d", f.nextID)
+		}
+		if _, taken := f.issues[id]; !taken {
+			return id
+		}
+	}
+}
+
+// touch sets the issue's updated_at to now, strictly after its last update so
+// conditional updates always see a change. Caller holds mu.
+func touch(issue *types.Issue) time.Time {
+	now := time.Now()
+	if !now.After(issue.UpdatedAt) {
+		now = issue.UpdatedAt.Add(time.Nanosecond)
+	}
+	issue.UpdatedAt = now
+	return now
+}
+
+// recordEvent appends an audit event. Caller holds mu.
+func (f *FakeStorage) recordEvent(issueID string, eventType types.EventType, actor string, oldValue, newValue, comment *string) {
+	f.eventID++
+	f.events = append(f.events, &types.Event{
+		ID:        f.eventID,
+		IssueID:   issueID,
+		EventType: eventType,
+		Actor:     actor,
+		OldValue:  oldValue,
+		NewValue:  newValue,
+		Comment:   comment,
+		CreatedAt: time.Now(),
+	})
+}
+
+// copyIssue returns a copy callers can't use to change the stored issue
+func copyIssue(issue *types.Issue) *types.Issue {
+	c := *issue
+	if issue.ClosedAt != nil {
+		closedAt := *issue.ClosedAt
+		c.ClosedAt = &closedAt
+	}
+	if issue.EstimatedMinutes != nil {
+		minutes := *issue.EstimatedMinutes
+		c.EstimatedMinutes = &minutes
+	}
+	c.MissionContext = nil
+	return &c
+}
+
+// strPtr returns a pointer to s
+func strPtr(s string) *string {
+	return &s
+}
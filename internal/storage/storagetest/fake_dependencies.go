@@ -0,0 +1,713 @@
+package storagetest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// DEPENDENCIES
+// ======================================================================
+
+// maxFakeDependencyDepth bounds the cycle check, as in Beads
+const maxFakeDependencyDepth = 100
+
+// AddDependency links two existing issues, refusing self-dependencies,
+// backwards parent-child links and cycles
+func (f *FakeStorage) AddDependency(ctx context.Context, dep *types.Dependency, actor string) error {
+	if err := f.begin("AddDependency", dep, actor); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.checkDependency(dep, nil); err != nil {
+		return err
+	}
+	d := *dep
+	f.addDependency(&d, actor)
+	dep.CreatedAt, dep.CreatedBy = d.CreatedAt, d.CreatedBy
+	return nil
+}
+
+// checkDependency validates a new dependency. pending is an issue being
+// created in the same step, if any. Caller holds mu.
+func (f *FakeStorage) checkDependency(dep *types.Dependency, pending *types.Issue) error {
+	lookup := func(id string) *types.Issue {
+		if pending != nil && pending.ID == id {
+			return pending
+		}
+		return f.issues[id]
+	}
+
+	if !dep.Type.IsValid() {
+		return fmt.Errorf("invalid dependency type: %s (must be blocks, related, parent-child, or discovered-from)", dep.Type)
+	}
+	from := lookup(dep.IssueID)
+	if from == nil {
+		return fmt.Errorf("issue %s not found", dep.IssueID)
+	}
+	to := lookup(dep.DependsOnID)
+	if to == nil {
+		return fmt.Errorf("dependency target %s not found", dep.DependsOnID)
+	}
+	if dep.IssueID == dep.DependsOnID {
+		return fmt.Errorf("issue cannot depend on itself")
+	}
+	if dep.Type == types.DepParentChild && from.IssueType == types.TypeEpic && to.IssueType != types.TypeEpic {
+		return fmt.Errorf("invalid parent-child dependency: parent (%s) cannot depend on child (%s)", dep.IssueID, dep.DependsOnID)
+	}
+	for _, existing := range f.deps {
+		if existing.IssueID == dep.IssueID && existing.DependsOnID == dep.DependsOnID {
+			return fmt.Errorf("dependency from %s to %s already exists", dep.IssueID, dep.DependsOnID)
+		}
+	}
+	if f.reaches(dep.DependsOnID, dep.IssueID) {
+		return fmt.Errorf("cannot add dependency: would create a cycle (%s → %s → ... → %s)",
+			dep.IssueID, dep.DependsOnID, dep.IssueID)
+	}
+	return nil
+}
+
+// reaches reports whether target is reachable from start along dependencies
+// of any type. Caller holds mu.
+func (f *FakeStorage) reaches(start, target string) bool {
+	seen := map[string]bool{start: true}
+	level := []string{start}
+	for depth := 0; depth < maxFakeDependencyDepth && len(level) > 0; depth++ {
+		var next []string
+		for _, id := range level {
+			for _, dep := range f.deps {
+				if dep.IssueID != id {
+					continue
+				}
+				if dep.DependsOnID == target {
+					return true
+				}
+				if !seen[dep.DependsOnID] {
+					seen[dep.DependsOnID] = true
+					next = append(next, dep.DependsOnID)
+				}
+			}
+		}
+		level = next
+	}
+	return false
+}
+
+// addDependency stores a checked dependency and its event. Caller holds mu.
+func (f *FakeStorage) addDependency(dep *types.Dependency, actor string) {
+	if dep.CreatedAt.IsZero() {
+		dep.CreatedAt = time.Now()
+	}
+	if dep.CreatedBy == "" {
+		dep.CreatedBy = actor
+	}
+	f.deps = append(f.deps, dep)
+	f.recordEvent(dep.IssueID, types.EventDependencyAdded, actor, nil, nil,
+		strPtr(fmt.Sprintf("Added dependency: %s %s %s", dep.IssueID, dep.Type, dep.DependsOnID)))
+}
+
+// RemoveDependency removes the link from issueID to dependsOnID
+func (f *FakeStorage) RemoveDependency(ctx context.Context, issueID, dependsOnID string, actor string) error {
+	if err := f.begin("RemoveDependency", issueID, dependsOnID, actor); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, dep := range f.deps {
+		if dep.IssueID == issueID && dep.DependsOnID == dependsOnID {
+			f.deps = append(f.deps[:i:i], f.deps[i+1:]...)
+			f.recordEvent(issueID, types.EventDependencyRemoved, actor, nil, nil,
+				strPtr(fmt.Sprintf("Removed dependency on %s", dependsOnID)))
+			return nil
+		}
+	}
+	return fmt.Errorf("dependency from %s to %s does not exist", issueID, dependsOnID)
+}
+
+// GetDependencies returns the non-archived issues issueID depends on
+func (f *FakeStorage) GetDependencies(ctx context.Context, issueID string) ([]*types.Issue, error) {
+	if err := f.begin("GetDependencies", issueID); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var result []*types.Issue
+	for _, dep := range f.deps {
+		if dep.IssueID == issueID {
+			if issue := f.issues[dep.DependsOnID]; issue != nil && !issue.Archived {
+				result = append(result, copyIssue(issue))
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return defaultIssueLess(result[i], result[j]) })
+	return result, nil
+}
+
+// GetDependents returns the non-archived issues that depend on issueID
+func (f *FakeStorage) GetDependents(ctx context.Context, issueID string) ([]*types.Issue, error) {
+	if err := f.begin("GetDependents", issueID); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var result []*types.Issue
+	for _, dep := range f.deps {
+		if dep.DependsOnID == issueID {
+			if issue := f.issues[dep.IssueID]; issue != nil && !issue.Archived {
+				result = append(result, copyIssue(issue))
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return defaultIssueLess(result[i], result[j]) })
+	return result, nil
+}
+
+// GetDependencyRecords returns the dependency records of issueID
+func (f *FakeStorage) GetDependencyRecords(ctx context.Context, issueID string) ([]*types.Dependency, error) {
+	if err := f.begin("GetDependencyRecords", issueID); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var result []*types.Dependency
+	for _, dep := range f.deps {
+		if dep.IssueID == issueID {
+			d := *dep
+			result = append(result, &d)
+		}
+	}
+	return result, nil
+}
+
+// GetDependencyTree returns everything issueID depends on, each issue once
+// at its shallowest depth
+func (f *FakeStorage) GetDependencyTree(ctx context.Context, issueID string, maxDepth int) ([]*types.TreeNode, error) {
+	if err := f.begin("GetDependencyTree", issueID, maxDepth); err != nil {
+		return nil, err
+	}
+	if maxDepth <= 0 {
+		maxDepth = 50
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	root := f.issues[issueID]
+	if root == nil {
+		return nil, nil
+	}
+
+	nodes := []*types.TreeNode{{Issue: *copyIssue(root)}}
+	seen := map[string]*types.TreeNode{issueID: nodes[0]}
+	level := []*types.TreeNode{nodes[0]}
+	for depth := 1; depth <= maxDepth && len(level) > 0; depth++ {
+		var next []*types.TreeNode
+		for _, parent := range level {
+			for _, dep := range f.deps {
+				if dep.IssueID != parent.ID {
+					continue
+				}
+				issue := f.issues[dep.DependsOnID]
+				if issue == nil || issue.Archived {
+					continue
+				}
+				if node, ok := seen[issue.ID]; ok {
+					// Same depth through another parent: keep the best link
+					if node.Depth == depth && betterLink(dep.Type, parent.ID, node.DependencyType, node.ParentID) {
+						node.ParentID, node.DependencyType = parent.ID, dep.Type
+					}
+					continue
+				}
+				node := &types.TreeNode{
+					Issue:          *copyIssue(issue),
+					Depth:          depth,
+					Truncated:      depth == maxDepth,
+					ParentID:       parent.ID,
+					DependencyType: dep.Type,
+				}
+				seen[issue.ID] = node
+				nodes = append(nodes, node)
+				next = append(next, node)
+			}
+		}
+		level = next
+	}
+
+	sort.SliceStable(nodes, func(i, j int) bool {
+		a, b := nodes[i], nodes[j]
+		if a.Depth != b.Depth {
+			return a.Depth < b.Depth
+		}
+		if a.Priority != b.Priority {
+			return a.Priority < b.Priority
+		}
+		return a.ID < b.ID
+	})
+	return nodes, nil
+}
+
+// betterLink reports whether a link of type t from parent beats the current
+// one: blocking links win, then the lower parent ID
+func betterLink(t types.DependencyType, parent string, currentType types.DependencyType, currentParent string) bool {
+	if t.IsBlocking() != currentType.IsBlocking() {
+		return t.IsBlocking()
+	}
+	return parent < currentParent
+}
+
+// DetectCycles returns every dependency cycle, each as the issues on it
+func (f *FakeStorage) DetectCycles(ctx context.Context) ([][]*types.Issue, error) {
+	if err := f.begin("DetectCycles"); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var cycles [][]*types.Issue
+	found := make(map[string]bool)
+	var path []string
+	onPath := make(map[string]int)
+	var visit func(id string)
+	visit = func(id string) {
+		if at, ok := onPath[id]; ok {
+			cycle := append([]string(nil), path[at:]...)
+			// Rotate to start at the smallest ID so each cycle is reported once
+			start := 0
+			for i, cid := range cycle {
+				if cid < cycle[start] {
+					start = i
+				}
+			}
+			cycle = append(cycle[start:], cycle[:start]...)
+			key := strings.Join(cycle, ",")
+			if !found[key] {
+				found[key] = true
+				issues := make([]*types.Issue, 0, len(cycle))
+				for _, cid := range cycle {
+					if issue := f.issues[cid]; issue != nil {
+						issues = append(issues, copyIssue(issue))
+					}
+				}
+				cycles = append(cycles, issues)
+			}
+			return
+		}
+		if len(path) >= maxFakeDependencyDepth {
+			return
+		}
+		onPath[id] = len(path)
+		path = append(path, id)
+		for _, dep := range f.deps {
+			if dep.IssueID == id {
+				visit(dep.DependsOnID)
+			}
+		}
+		path = path[:len(path)-1]
+		delete(onPath, id)
+	}
+
+	starts := make([]string, 0, len(f.issues))
+	for id := range f.issues {
+		starts = append(starts, id)
+	}
+	sort.Strings(starts)
+	for _, id := range starts {
+		visit(id)
+	}
+	return cycles, nil
+}
+
+// ======================================================================
+// READY WORK & BLOCKING
+// ======================================================================
+
+// isOpenStatus reports whether an issue in this status still blocks
+func isOpenStatus(status types.Status) bool {
+	return status == types.StatusOpen || status == types.StatusInProgress || status == types.StatusBlocked
+}
+
+// openBlockers returns the open issues id has a blocks dependency on, sorted.
+// Caller holds mu.
+func (f *FakeStorage) openBlockers(id string) []string {
+	var blockers []string
+	for _, dep := range f.deps {
+		if dep.IssueID == id && dep.Type.IsBlocking() {
+			if blocker := f.issues[dep.DependsOnID]; blocker != nil && isOpenStatus(blocker.Status) {
+				blockers = append(blockers, blocker.ID)
+			}
+		}
+	}
+	sort.Strings(blockers)
+	return blockers
+}
+
+// isBlocked reports whether id has open blockers, itself or through its
+// parent-child ancestors. Caller holds mu.
+func (f *FakeStorage) isBlocked(id string) bool {
+	seen := map[string]bool{}
+	for level := []string{id}; len(level) > 0 && len(seen) < maxFakeDependencyDepth; {
+		var parents []string
+		for _, current := range level {
+			if seen[current] {
+				continue
+			}
+			seen[current] = true
+			if len(f.openBlockers(current)) > 0 {
+				return true
+			}
+			for _, dep := range f.deps {
+				if dep.IssueID == current && dep.Type == types.DepParentChild {
+					parents = append(parents, dep.DependsOnID)
+				}
+			}
+		}
+		level = parents
+	}
+	return false
+}
+
+// GetReadyWork returns unblocked, non-epic, non-archived issues other than
+// SYSTEM (open or in progress unless filter.Status says otherwise), ordered
+// by filter.OrderBy or the sort policy and enriched with their mission. Tasks of missions
+// waiting for quality gates or plan approval, and tasks whose phase waits,
+// are left out, and so is work assigned to a human for filter.AgentsOnly.
+func (f *FakeStorage) GetReadyWork(ctx context.Context, filter types.WorkFilter) ([]*types.Issue, error) {
+	if err := f.begin("GetReadyWork", filter); err != nil {
+		return nil, err
+	}
+	if filter.OrderBy != "" && !filter.OrderBy.IsValid() {
+		return nil, fmt.Errorf("invalid work order %q (valid: %v)", filter.OrderBy, types.WorkOrders)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var ready []*types.Issue
+	for id, issue := range f.issues {
+		switch {
+		case filter.Status == "" && issue.Status != types.StatusOpen && issue.Status != types.StatusInProgress:
+			continue
+		case filter.Status != "" && issue.Status != filter.Status:
+			continue
+		case issue.Archived || issue.IssueType == types.TypeEpic || id == types.SystemIssueID:
+			continue
+		case filter.Priority != nil && issue.Priority != *filter.Priority:
+			continue
+		case filter.Assignee != nil && issue.Assignee != *filter.Assignee:
+			continue
+		case filter.Project != "" && issue.Project != filter.Project:
+			continue
+		case filter.AgentsOnly && f.humanAssigned(issue):
+			continue
+		case f.isBlocked(id):
+			continue
+		}
+		ready = append(ready, copyIssue(issue))
+	}
+	sortReadyWork(ready, filter)
+
+	result := make([]*types.Issue, 0, len(ready))
+	for _, issue := range ready {
+		if missionCtx, err := f.missionForTask(issue.ID); err == nil {
+			if missionCtx.AwaitingApproval || missionCtx.PhaseWaiting || f.hasLabel(missionCtx.MissionID, "needs-quality-gates") {
+				continue
+			}
+			issue.MissionContext = missionCtx
+		}
+		result = append(result, issue)
+		if filter.Limit > 0 && len(result) == filter.Limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+// sortReadyWork orders ready work like the beads wrapper: by
+// filter.OrderBy, else by SortPolicy, else by priority. Hybrid puts issues
+// created in the last 48 hours first, by priority, and older ones after
+// them, oldest first. Every order ends with the ID.
+func sortReadyWork(issues []*types.Issue, filter types.WorkFilter) {
+	byPriority := func(a, b *types.Issue) bool {
+		if a.Priority != b.Priority {
+			return a.Priority < b.Priority
+		}
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID < b.ID
+	}
+	byAge := func(a, b *types.Issue) bool {
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return byPriority(a, b)
+	}
+	byEstimate := func(a, b *types.Issue) bool {
+		switch {
+		case a.EstimatedMinutes == nil && b.EstimatedMinutes == nil:
+		case a.EstimatedMinutes == nil || b.EstimatedMinutes == nil:
+			return b.EstimatedMinutes == nil
+		case *a.EstimatedMinutes != *b.EstimatedMinutes:
+			return *a.EstimatedMinutes < *b.EstimatedMinutes
+		}
+		return byPriority(a, b)
+	}
+
+	less := byPriority
+	switch {
+	case filter.OrderBy == types.WorkOrderAge:
+		less = byAge
+	case filter.OrderBy == types.WorkOrderEstimate:
+		less = byEstimate
+	case filter.OrderBy != "":
+	case filter.SortPolicy == types.SortPolicyOldest:
+		less = byAge
+	case filter.SortPolicy == types.SortPolicyHybrid:
+		recent := time.Now().Add(-48 * time.Hour)
+		less = func(a, b *types.Issue) bool {
+			aRecent, bRecent := a.CreatedAt.After(recent), b.CreatedAt.After(recent)
+			switch {
+			case aRecent != bRecent:
+				return aRecent
+			case aRecent:
+				return byPriority(a, b)
+			}
+			return byAge(a, b)
+		}
+	}
+	sort.Slice(issues, func(i, j int) bool { return less(issues[i], issues[j]) })
+}
+
+// GetBlockedIssues returns open issues with open blockers, with the root
+// blockers at the end of their blocking chains
+func (f *FakeStorage) GetBlockedIssues(ctx context.Context) ([]*types.BlockedIssue, error) {
+	if err := f.begin("GetBlockedIssues"); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var result []*types.BlockedIssue
+	for id, issue := range f.issues {
+		if issue.Archived || !isOpenStatus(issue.Status) {
+			continue
+		}
+		blockers := f.openBlockers(id)
+		if len(blockers) == 0 {
+			continue
+		}
+		roots, truncated := f.rootBlockers(blockers)
+		result = append(result, &types.BlockedIssue{
+			Issue:          *copyIssue(issue),
+			BlockedByCount: len(blockers),
+			BlockedBy:      blockers,
+			RootBlockers:   roots,
+			RootsTruncated: truncated,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return defaultIssueLess(&result[i].Issue, &result[j].Issue) })
+	return result, nil
+}
+
+// rootBlockers follows blocking chains from blockers to the open issues
+// without open blockers of their own. Caller holds mu.
+func (f *FakeStorage) rootBlockers(blockers []string) (roots []string, truncated bool) {
+	const maxDepth = 32
+	rootSet := make(map[string]bool)
+	seen := make(map[string]bool)
+	level := blockers
+	for depth := 1; len(level) > 0; depth++ {
+		if depth > maxDepth {
+			return sortedKeys(rootSet), true
+		}
+		var next []string
+		for _, id := range level {
+			upstream := f.openBlockers(id)
+			if len(upstream) == 0 {
+				rootSet[id] = true
+				continue
+			}
+			for _, up := range upstream {
+				if !seen[up] {
+					seen[up] = true
+					next = append(next, up)
+				}
+			}
+		}
+		level = next
+	}
+	return sortedKeys(rootSet), false
+}
+
+// sortedKeys returns the keys of set, sorted
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// GetReadyBlockers returns open discovered:blocker issues without open
+// blockers, by priority, applying the filter's Limit, Project and AgentsOnly
+func (f *FakeStorage) GetReadyBlockers(ctx context.Context, filter types.WorkFilter) ([]*types.Issue, error) {
+	if err := f.begin("GetReadyBlockers", filter); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var result []*types.Issue
+	for id, issue := range f.issues {
+		switch {
+		case issue.Status != types.StatusOpen || issue.Archived || issue.IssueType == types.TypeEpic:
+			continue
+		case !f.hasLabel(id, "discovered:blocker") || len(f.openBlockers(id)) > 0:
+			continue
+		case filter.Project != "" && issue.Project != filter.Project:
+			continue
+		case filter.AgentsOnly && f.humanAssigned(issue):
+			continue
+		}
+		result = append(result, copyIssue(issue))
+	}
+	sortReadyWork(result, types.WorkFilter{})
+	if filter.Limit > 0 && len(result) > filter.Limit {
+		result = result[:filter.Limit]
+	}
+	return result, nil
+}
+
+// IsEpicComplete reports whether every child of the epic is closed and the
+// epic has no open blockers
+func (f *FakeStorage) IsEpicComplete(ctx context.Context, epicID string) (bool, error) {
+	if err := f.begin("IsEpicComplete", epicID); err != nil {
+		return false, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, dep := range f.deps {
+		if dep.DependsOnID == epicID && dep.Type == types.DepParentChild {
+			if child := f.issues[dep.IssueID]; child != nil && child.Status != types.StatusClosed {
+				return false, nil
+			}
+		}
+	}
+	return len(f.openBlockers(epicID)) == 0, nil
+}
+
+// ======================================================================
+// STATISTICS
+// ======================================================================
+
+// GetStatistics counts the issues, leaving out archived ones and the SYSTEM
+// pseudo-issue
+func (f *FakeStorage) GetStatistics(ctx context.Context) (*types.Statistics, error) {
+	if err := f.begin("GetStatistics"); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	stats := &types.Statistics{}
+	var leadTime time.Duration
+	for id, issue := range f.issues {
+		if issue.Archived || id == types.SystemIssueID {
+			continue
+		}
+		stats.TotalIssues++
+		switch issue.Status {
+		case types.StatusOpen:
+			stats.OpenIssues++
+		case types.StatusInProgress:
+			stats.InProgressIssues++
+		case types.StatusClosed:
+			stats.ClosedIssues++
+			if issue.ClosedAt != nil {
+				leadTime += issue.ClosedAt.Sub(issue.CreatedAt)
+			}
+		}
+		if issue.Status == types.StatusBlocked && f.hasLabel(id, types.AutoBlockedLabel) {
+			stats.AutoBlockedIssues++
+		}
+		if isOpenStatus(issue.Status) && len(f.openBlockers(id)) > 0 {
+			stats.BlockedIssues++
+		} else if issue.Status == types.StatusOpen {
+			stats.ReadyIssues++
+		}
+	}
+	if stats.ClosedIssues > 0 {
+		stats.AverageLeadTime = leadTime.Hours() / float64(stats.ClosedIssues)
+	}
+	return stats, nil
+}
+
+// GetActorStatistics summarizes audit events and execution attempts per
+// actor since the given time
+func (f *FakeStorage) GetActorStatistics(ctx context.Context, since time.Time) ([]*types.ActorStatistics, error) {
+	if err := f.begin("GetActorStatistics", since); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	byActor := make(map[string]*types.ActorStatistics)
+	get := func(actor string) *types.ActorStatistics {
+		stats := byActor[actor]
+		if stats == nil {
+			stats = &types.ActorStatistics{Actor: actor}
+			if instance := f.instances[actor]; instance != nil {
+				stats.Executor = true
+				stats.Hostname = instance.Hostname
+			}
+			byActor[actor] = stats
+		}
+		return stats
+	}
+
+	for _, event := range f.events {
+		if event.CreatedAt.Before(since) {
+			continue
+		}
+		switch event.EventType {
+		case types.EventCreated:
+			get(event.Actor).IssuesCreated++
+		case types.EventClosed:
+			get(event.Actor).IssuesClosed++
+		case types.EventCommented:
+			get(event.Actor).Comments++
+		}
+	}
+
+	durations := make(map[string]float64)
+	finished := make(map[string]int)
+	for _, attempt := range f.attempts {
+		if attempt.StartedAt.Before(since) {
+			continue
+		}
+		stats := get(attempt.ExecutorInstanceID)
+		stats.Executor = true
+		stats.ExecutionAttempts++
+		if attempt.CompletedAt != nil && attempt.Success != nil {
+			finished[stats.Actor]++
+			durations[stats.Actor] += attempt.CompletedAt.Sub(attempt.StartedAt).Seconds()
+			if *attempt.Success {
+				stats.SuccessfulAttempts++
+			}
+		}
+	}
+
+	result := make([]*types.ActorStatistics, 0, len(byActor))
+	for actor, stats := range byActor {
+		if n := finished[actor]; n > 0 {
+			stats.SuccessRate = float64(stats.SuccessfulAttempts) / float64(n)
+			stats.AverageDurationSeconds = durations[actor] / float64(n)
+		}
+		result = append(result, stats)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Actor < result[j].Actor })
+	return result, nil
+}
@@ -0,0 +1,382 @@
+package storagetest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// AGENT EVENTS
+// ======================================================================
+
+// fakeWatchBuffer is how many undelivered events a watcher queues before new
+// ones are dropped
+const fakeWatchBuffer = 256
+
+// fakeWatcher is one WatchAgentEvents subscription
+type fakeWatcher struct {
+	filter events.EventFilter
+	out    chan *events.AgentEvent
+}
+
+// StoreAgentEvent stores a copy of the event under the next ID and delivers
+// it to matching watchers. Data goes through JSON, as in the real store.
+func (f *FakeStorage) StoreAgentEvent(ctx context.Context, event *events.AgentEvent) error {
+	if err := f.begin("StoreAgentEvent", event); err != nil {
+		return err
+	}
+	stored := *event
+	if event.Data != nil {
+		raw, err := json.Marshal(event.Data)
+		if err != nil {
+			return fmt.Errorf("failed to marshal event data: %w", err)
+		}
+		stored.Data = nil
+		if err := json.Unmarshal(raw, &stored.Data); err != nil {
+			return fmt.Errorf("failed to unmarshal event data: %w", err)
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.agentEventID++
+	stored.ID = strconv.FormatInt(f.agentEventID, 10)
+	f.agentEvents = append(f.agentEvents, &stored)
+
+	for w := range f.watchers {
+		if !w.filter.Matches(&stored) {
+			continue
+		}
+		published := stored
+		select {
+		case w.out <- &published:
+		default:
+			// The watcher fell behind; drop rather than block the writer
+		}
+	}
+	return nil
+}
+
+// matchingAgentEvents returns copies of the events matching the filter and
+// its ID cursors, in storage order. Caller holds mu.
+func (f *FakeStorage) matchingAgentEvents(filter events.EventFilter) []*events.AgentEvent {
+	var result []*events.AgentEvent
+	for _, event := range f.agentEvents {
+		id := event.Cursor()
+		if filter.AfterID > 0 && id <= filter.AfterID {
+			continue
+		}
+		if filter.BeforeID > 0 && id >= filter.BeforeID {
+			continue
+		}
+		if filter.Matches(event) {
+			e := *event
+			result = append(result, &e)
+		}
+	}
+	return result
+}
+
+// GetAgentEvents returns matching events: newest ID first with BeforeID,
+// oldest ID first with AfterID, otherwise newest first by timestamp
+func (f *FakeStorage) GetAgentEvents(ctx context.Context, filter events.EventFilter) ([]*events.AgentEvent, error) {
+	if err := f.begin("GetAgentEvents", filter); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	result := f.matchingAgentEvents(filter)
+	switch {
+	case filter.BeforeID > 0:
+		reverse(result)
+	case filter.AfterID > 0:
+	default:
+		reverse(result)
+		sort.SliceStable(result, func(i, j int) bool { return result[i].Timestamp.After(result[j].Timestamp) })
+	}
+	if filter.Limit > 0 && len(result) > filter.Limit {
+		result = result[:filter.Limit]
+	}
+	return result, nil
+}
+
+// GetAgentEventsByIssue returns the issue's events, oldest first
+func (f *FakeStorage) GetAgentEventsByIssue(ctx context.Context, issueID string) ([]*events.AgentEvent, error) {
+	if err := f.begin("GetAgentEventsByIssue", issueID); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	result := f.matchingAgentEvents(events.EventFilter{IssueID: issueID})
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Timestamp.Before(result[j].Timestamp) })
+	return result, nil
+}
+
+// GetRecentAgentEvents returns the newest events
+func (f *FakeStorage) GetRecentAgentEvents(ctx context.Context, limit int) ([]*events.AgentEvent, error) {
+	if err := f.begin("GetRecentAgentEvents", limit); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	result := f.matchingAgentEvents(events.EventFilter{})
+	reverse(result)
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Timestamp.After(result[j].Timestamp) })
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+// StreamAgentEvents calls fn for each matching event, oldest ID first,
+// stopping at the first error from fn
+func (f *FakeStorage) StreamAgentEvents(ctx context.Context, filter events.EventFilter, fn func(*events.AgentEvent) error) error {
+	if err := f.begin("StreamAgentEvents", filter, fn); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	result := f.matchingAgentEvents(filter)
+	f.mu.Unlock()
+	if filter.Limit > 0 && len(result) > filter.Limit {
+		result = result[:filter.Limit]
+	}
+	for _, event := range result {
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WatchAgentEvents delivers matching events stored after the call until ctx
+// is canceled or the store is closed, then closes the channel
+func (f *FakeStorage) WatchAgentEvents(ctx context.Context, filter events.EventFilter) (<-chan *events.AgentEvent, error) {
+	if err := f.begin("WatchAgentEvents", filter); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	w := &fakeWatcher{filter: filter, out: make(chan *events.AgentEvent, fakeWatchBuffer)}
+	f.mu.Lock()
+	f.watchers[w] = struct{}{}
+	f.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.stopWatcher(w)
+	}()
+	return w.out, nil
+}
+
+// stopWatcher unsubscribes w and closes its channel, once. Caller holds mu.
+func (f *FakeStorage) stopWatcher(w *fakeWatcher) {
+	if _, ok := f.watchers[w]; !ok {
+		return
+	}
+	delete(f.watchers, w)
+	close(w.out)
+}
+
+// reverse reverses events in place
+func reverse(list []*events.AgentEvent) {
+	for i, j := 0, len(list)-1; i < j; i, j = i+1, j-1 {
+		list[i], list[j] = list[j], list[i]
+	}
+}
+
+// ======================================================================
+// EVENT CLEANUP
+// ======================================================================
+
+// isCriticalSeverity reports whether retention treats the severity as critical
+func isCriticalSeverity(severity events.EventSeverity) bool {
+	return severity == events.SeverityError || severity == events.SeverityCritical
+}
+
+// deleteAgentEvents removes the events for which drop returns true, oldest
+// first, at most max of them (no limit if max < 0). Caller holds mu.
+func (f *FakeStorage) deleteAgentEvents(max int, drop func(*events.AgentEvent) bool) int {
+	candidates := make([]*events.AgentEvent, 0)
+	for _, event := range f.agentEvents {
+		if drop(event) {
+			candidates = append(candidates, event)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Timestamp.Before(candidates[j].Timestamp) })
+	if max >= 0 && len(candidates) > max {
+		candidates = candidates[:max]
+	}
+
+	doomed := make(map[*events.AgentEvent]bool, len(candidates))
+	for _, event := range candidates {
+		doomed[event] = true
+	}
+	kept := f.agentEvents[:0]
+	for _, event := range f.agentEvents {
+		if !doomed[event] {
+			kept = append(kept, event)
+		}
+	}
+	f.agentEvents = kept
+	return len(candidates)
+}
+
+// CleanupEventsByAge deletes info and warning events older than
+// retentionDays, and error and critical events older than
+// criticalRetentionDays if that differs
+func (f *FakeStorage) CleanupEventsByAge(ctx context.Context, retentionDays, criticalRetentionDays, batchSize int) (int, error) {
+	if err := f.begin("CleanupEventsByAge", retentionDays, criticalRetentionDays, batchSize); err != nil {
+		return 0, err
+	}
+	if retentionDays < 0 || criticalRetentionDays < 0 {
+		return 0, fmt.Errorf("retention days cannot be negative")
+	}
+	if batchSize < 1 {
+		return 0, fmt.Errorf("batch size must be at least 1")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	regularCutoff := time.Now().AddDate(0, 0, -retentionDays)
+	deleted := f.deleteAgentEvents(-1, func(e *events.AgentEvent) bool {
+		return !isCriticalSeverity(e.Severity) && e.Timestamp.Before(regularCutoff)
+	})
+	if criticalRetentionDays != retentionDays {
+		criticalCutoff := time.Now().AddDate(0, 0, -criticalRetentionDays)
+		deleted += f.deleteAgentEvents(-1, func(e *events.AgentEvent) bool {
+			return isCriticalSeverity(e.Severity) && e.Timestamp.Before(criticalCutoff)
+		})
+	}
+	return deleted, nil
+}
+
+// CleanupEventsByIssueLimit deletes each issue's oldest non-critical events
+// beyond perIssueLimit (0 means no limit)
+func (f *FakeStorage) CleanupEventsByIssueLimit(ctx context.Context, perIssueLimit, batchSize int) (int, error) {
+	if err := f.begin("CleanupEventsByIssueLimit", perIssueLimit, batchSize); err != nil {
+		return 0, err
+	}
+	if perIssueLimit < 0 {
+		return 0, fmt.Errorf("per-issue limit cannot be negative")
+	}
+	if perIssueLimit == 0 {
+		return 0, nil
+	}
+	if batchSize < 1 {
+		return 0, fmt.Errorf("batch size must be at least 1")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	counts := make(map[string]int)
+	for _, event := range f.agentEvents {
+		if event.IssueID != "" {
+			counts[event.IssueID]++
+		}
+	}
+	deleted := 0
+	for issueID, count := range counts {
+		if count <= perIssueLimit {
+			continue
+		}
+		deleted += f.deleteAgentEvents(count-perIssueLimit, func(e *events.AgentEvent) bool {
+			return e.IssueID == issueID && !isCriticalSeverity(e.Severity)
+		})
+	}
+	return deleted, nil
+}
+
+// CleanupEventsByGlobalLimit deletes the oldest non-critical events beyond
+// globalLimit
+func (f *FakeStorage) CleanupEventsByGlobalLimit(ctx context.Context, globalLimit, batchSize int) (int, error) {
+	if err := f.begin("CleanupEventsByGlobalLimit", globalLimit, batchSize); err != nil {
+		return 0, err
+	}
+	if globalLimit < 1 {
+		return 0, fmt.Errorf("global limit must be at least 1")
+	}
+	if batchSize < 1 {
+		return 0, fmt.Errorf("batch size must be at least 1")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.agentEvents) <= globalLimit {
+		return 0, nil
+	}
+	return f.deleteAgentEvents(len(f.agentEvents)-globalLimit, func(e *events.AgentEvent) bool {
+		return !isCriticalSeverity(e.Severity)
+	}), nil
+}
+
+// GetEventCounts counts the stored agent events by issue ("" for system
+// events), severity and type
+func (f *FakeStorage) GetEventCounts(ctx context.Context) (*types.EventCounts, error) {
+	if err := f.begin("GetEventCounts"); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	counts := &types.EventCounts{
+		TotalEvents:      len(f.agentEvents),
+		EventsByIssue:    make(map[string]int),
+		EventsBySeverity: make(map[string]int),
+		EventsByType:     make(map[string]int),
+	}
+	for _, event := range f.agentEvents {
+		counts.EventsByIssue[event.IssueID]++
+		counts.EventsBySeverity[string(event.Severity)]++
+		counts.EventsByType[string(event.Type)]++
+	}
+	return counts, nil
+}
+
+// ======================================================================
+// DATABASE MAINTENANCE
+// ======================================================================
+
+// fakePageSize is the page size GetDatabaseStats reports
+const fakePageSize = 4096
+
+// VacuumDatabase is a no-op: there is no file to compact
+func (f *FakeStorage) VacuumDatabase(ctx context.Context) error {
+	return f.begin("VacuumDatabase")
+}
+
+// IncrementalVacuum is a no-op: there is no file to compact
+func (f *FakeStorage) IncrementalVacuum(ctx context.Context) error {
+	return f.begin("IncrementalVacuum")
+}
+
+// GetDatabaseStats reports a made-up size that grows with the stored rows
+// and never has free pages
+func (f *FakeStorage) GetDatabaseStats(ctx context.Context) (*types.DatabaseStats, error) {
+	if err := f.begin("GetDatabaseStats"); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	rows := len(f.issues) + len(f.deps) + len(f.events) + len(f.agentEvents) + len(f.attempts) + len(f.interventions)
+	pages := int64(1 + rows/16)
+	return &types.DatabaseStats{
+		PageSize:   fakePageSize,
+		PageCount:  pages,
+		AutoVacuum: "none",
+		SizeBytes:  pages * fakePageSize,
+	}, nil
+}
+
+// Reopen has no file to go back to; it only records the call and applies
+// the failure hooks, so tests can fail it
+func (f *FakeStorage) Reopen(ctx context.Context) error {
+	return f.begin("Reopen")
+}
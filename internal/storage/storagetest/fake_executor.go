@@ -0,0 +1,677 @@
+package storagetest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/config"
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// EXECUTOR INSTANCES
+// ======================================================================
+
+// executorStatusCrashed is the status CleanupStaleInstances gives instances
+// that stopped sending heartbeats
+const executorStatusCrashed types.ExecutorStatus = "crashed"
+
+// activeExecutionStates are the states in which an issue is claimed
+var activeExecutionStates = map[types.ExecutionState]bool{
+	types.ExecutionStateClaimed:    true,
+	types.ExecutionStateAssessing:  true,
+	types.ExecutionStateExecuting:  true,
+	types.ExecutionStateAnalyzing:  true,
+	types.ExecutionStateGates:      true,
+	types.ExecutionStateCommitting: true,
+}
+
+// RegisterInstance registers or replaces an executor instance
+func (f *FakeStorage) RegisterInstance(ctx context.Context, instance *types.ExecutorInstance) error {
+	if err := f.begin("RegisterInstance", instance); err != nil {
+		return err
+	}
+	if err := instance.Validate(); err != nil {
+		return fmt.Errorf("invalid executor instance: %w", err)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	i := *instance
+	f.instances[instance.InstanceID] = &i
+	return nil
+}
+
+// MarkInstanceStopped marks the instance stopped
+func (f *FakeStorage) MarkInstanceStopped(ctx context.Context, instanceID string) error {
+	if err := f.begin("MarkInstanceStopped", instanceID); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	instance := f.instances[instanceID]
+	if instance == nil {
+		return fmt.Errorf("executor instance %s not found", instanceID)
+	}
+	instance.Status = types.ExecutorStatusStopped
+	return nil
+}
+
+// UpdateHeartbeat sets the instance's last heartbeat to now
+func (f *FakeStorage) UpdateHeartbeat(ctx context.Context, instanceID string) error {
+	if err := f.begin("UpdateHeartbeat", instanceID); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	instance := f.instances[instanceID]
+	if instance == nil {
+		return fmt.Errorf("executor instance %s not found", instanceID)
+	}
+	instance.LastHeartbeat = time.Now()
+	return nil
+}
+
+// GetActiveInstances returns the running instances, oldest first
+func (f *FakeStorage) GetActiveInstances(ctx context.Context) ([]*types.ExecutorInstance, error) {
+	if err := f.begin("GetActiveInstances"); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var result []*types.ExecutorInstance
+	for _, instance := range f.instances {
+		if instance.Status == types.ExecutorStatusRunning {
+			i := *instance
+			result = append(result, &i)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].StartedAt.Before(result[j].StartedAt) })
+	return result, nil
+}
+
+// CleanupStaleInstances marks running instances without a recent heartbeat
+// crashed and releases their claims, and those of stopped instances, back to
+// open. It returns the number of instances cleaned up.
+func (f *FakeStorage) CleanupStaleInstances(ctx context.Context, staleThreshold int) (int, error) {
+	if err := f.begin("CleanupStaleInstances", staleThreshold); err != nil {
+		return 0, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	staleTime := time.Now().Add(-time.Duration(staleThreshold) * time.Second)
+	cleanup := make(map[string]bool)
+	for id, instance := range f.instances {
+		if instance.Status == types.ExecutorStatusRunning && instance.LastHeartbeat.Before(staleTime) {
+			cleanup[id] = true
+			instance.Status = executorStatusCrashed
+		}
+	}
+	for _, state := range f.execStates {
+		if instance := f.instances[state.ExecutorInstanceID]; instance != nil && instance.Status == types.ExecutorStatusStopped {
+			cleanup[instance.InstanceID] = true
+		}
+	}
+
+	for issueID, state := range f.execStates {
+		if !cleanup[state.ExecutorInstanceID] {
+			continue
+		}
+		f.restoreAssignee(issueID)
+		state.ExecutorInstanceID = ""
+		state.State = types.ExecutionStatePending
+		state.UpdatedAt = time.Now()
+		if issue := f.issues[issueID]; issue != nil {
+			issue.Status = types.StatusOpen
+			issue.ClosedAt = nil
+			touch(issue)
+		}
+	}
+	return len(cleanup), nil
+}
+
+// DeleteOldStoppedInstances deletes stopped or crashed instances started
+// before the cutoff, keeping the newest maxToKeep of them
+func (f *FakeStorage) DeleteOldStoppedInstances(ctx context.Context, olderThanSeconds int, maxToKeep int) (int, error) {
+	if err := f.begin("DeleteOldStoppedInstances", olderThanSeconds, maxToKeep); err != nil {
+		return 0, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var stopped []*types.ExecutorInstance
+	for _, instance := range f.instances {
+		if instance.Status == types.ExecutorStatusStopped || instance.Status == executorStatusCrashed {
+			stopped = append(stopped, instance)
+		}
+	}
+	sort.Slice(stopped, func(i, j int) bool { return stopped[i].StartedAt.After(stopped[j].StartedAt) })
+
+	cutoff := time.Now().Add(-time.Duration(olderThanSeconds) * time.Second)
+	deleted := 0
+	for i, instance := range stopped {
+		if i >= maxToKeep && instance.StartedAt.Before(cutoff) {
+			delete(f.instances, instance.InstanceID)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// ======================================================================
+// ISSUE EXECUTION STATE
+// ======================================================================
+
+// ClaimIssue claims an open issue for the executor and moves it to
+// in_progress; claimed issues drop out of GetReadyWork for open issues.
+// Losing to another claimant returns a *types.ClaimConflictError. The
+// executor's short ID becomes the assignee until the issue is released.
+func (f *FakeStorage) ClaimIssue(ctx context.Context, issueID, executorInstanceID string) error {
+	if err := f.begin("ClaimIssue", issueID, executorInstanceID); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.awaitingApproval(issueID); err != nil {
+		return err
+	}
+	if state := f.execStates[issueID]; state != nil && activeExecutionStates[state.State] {
+		return &types.ClaimConflictError{IssueID: issueID, ClaimedBy: state.ExecutorInstanceID}
+	}
+	issue := f.issues[issueID]
+	if issue == nil {
+		return fmt.Errorf("cannot claim issue %s: issue not found", issueID)
+	}
+	switch issue.Status {
+	case types.StatusOpen:
+	case types.StatusInProgress:
+		return &types.ClaimConflictError{IssueID: issueID}
+	default:
+		return fmt.Errorf("cannot claim issue %s: %w (status %s)", issueID, types.ErrNotClaimable, issue.Status)
+	}
+
+	now := time.Now()
+	f.execStates[issueID] = &types.IssueExecutionState{
+		IssueID:            issueID,
+		ExecutorInstanceID: executorInstanceID,
+		State:              types.ExecutionStateClaimed,
+		ClaimedAt:          now,
+		StartedAt:          now,
+		UpdatedAt:          now,
+		PreviousAssignee:   issue.Assignee,
+	}
+	issue.Status = types.StatusInProgress
+	issue.Assignee = types.ExecutorShortID(executorInstanceID)
+	touch(issue)
+	return nil
+}
+
+// GetExecutionState returns the issue's execution state, or nil if it has none
+func (f *FakeStorage) GetExecutionState(ctx context.Context, issueID string) (*types.IssueExecutionState, error) {
+	if err := f.begin("GetExecutionState", issueID); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	state := f.execStates[issueID]
+	if state == nil {
+		return nil, nil
+	}
+	s := *state
+	return &s, nil
+}
+
+// UpdateExecutionState moves the execution state along the state machine
+func (f *FakeStorage) UpdateExecutionState(ctx context.Context, issueID string, state types.ExecutionState) error {
+	if err := f.begin("UpdateExecutionState", issueID, state); err != nil {
+		return err
+	}
+	if !state.IsValid() {
+		return fmt.Errorf("invalid execution state: %s", state)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	current := f.execStates[issueID]
+	if current == nil {
+		if state != types.ExecutionStatePending && state != types.ExecutionStateClaimed {
+			return fmt.Errorf("cannot transition to %s without existing execution state", state)
+		}
+		f.execStates[issueID] = &types.IssueExecutionState{IssueID: issueID, State: state, UpdatedAt: time.Now()}
+		return nil
+	}
+	if !current.State.CanTransitionTo(state) {
+		return fmt.Errorf("invalid state transition: cannot transition from %s to %s (valid transitions: %v)",
+			current.State, state, current.State.ValidTransitions())
+	}
+	current.State = state
+	current.UpdatedAt = time.Now()
+	return nil
+}
+
+// SaveCheckpoint stores the encoded checkpoint (nil clears it)
+func (f *FakeStorage) SaveCheckpoint(ctx context.Context, issueID string, checkpoint *types.CheckpointV1) error {
+	if err := f.begin("SaveCheckpoint", issueID, checkpoint); err != nil {
+		return err
+	}
+	data := ""
+	if checkpoint != nil {
+		var err error
+		if data, err = types.MarshalCheckpoint(checkpoint); err != nil {
+			return err
+		}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if state := f.execStates[issueID]; state != nil {
+		state.CheckpointData = data
+		state.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+// GetCheckpoint decodes the stored checkpoint, or returns nil if there is none
+func (f *FakeStorage) GetCheckpoint(ctx context.Context, issueID string) (*types.CheckpointV1, error) {
+	if err := f.begin("GetCheckpoint", issueID); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if state := f.execStates[issueID]; state != nil && state.CheckpointData != "" {
+		return types.UnmarshalCheckpoint(state.CheckpointData)
+	}
+	return nil, nil
+}
+
+// ReleaseIssue deletes the issue's execution state and restores its
+// assignee
+func (f *FakeStorage) ReleaseIssue(ctx context.Context, issueID string) error {
+	if err := f.begin("ReleaseIssue", issueID); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.execStates[issueID] == nil {
+		return fmt.Errorf("execution state not found for issue %s", issueID)
+	}
+	f.restoreAssignee(issueID)
+	delete(f.execStates, issueID)
+	return nil
+}
+
+// ReleaseIssueAndReopen marks the execution failed, restores the assignee,
+// reopens the issue and adds the error as a comment
+func (f *FakeStorage) ReleaseIssueAndReopen(ctx context.Context, issueID, actor, errorComment string) error {
+	if err := f.begin("ReleaseIssueAndReopen", issueID, actor, errorComment); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.restoreAssignee(issueID)
+	if state := f.execStates[issueID]; state != nil {
+		state.State = types.ExecutionStateFailed
+		state.ErrorMessage = errorComment
+		state.UpdatedAt = time.Now()
+	}
+	issue := f.issues[issueID]
+	if issue == nil {
+		return fmt.Errorf("failed to reopen issue: issue %s not found", issueID)
+	}
+	if err := f.applyUpdates(issue, map[string]interface{}{"status": string(types.StatusOpen)}, actor); err != nil {
+		return fmt.Errorf("failed to reopen issue: %w", err)
+	}
+	if errorComment != "" {
+		f.addComment(issueID, actor, errorComment)
+	}
+	return nil
+}
+
+// restoreAssignee gives a claimed issue back its assignee from before the
+// claim, unless it was reassigned since. Call with f.mu held, before the
+// claim is cleared.
+func (f *FakeStorage) restoreAssignee(issueID string) {
+	state, issue := f.execStates[issueID], f.issues[issueID]
+	if state == nil || issue == nil || state.ExecutorInstanceID == "" {
+		return
+	}
+	if issue.Assignee == types.ExecutorShortID(state.ExecutorInstanceID) {
+		issue.Assignee = state.PreviousAssignee
+		touch(issue)
+	}
+}
+
+// humanAssigned reports whether the issue is assigned to a human and not
+// labeled agent-ok. Call with f.mu held.
+func (f *FakeStorage) humanAssigned(issue *types.Issue) bool {
+	if types.AgentAssignable(issue.Assignee) || f.hasLabel(issue.ID, types.AgentOKLabel) {
+		return false
+	}
+	for id := range f.instances {
+		if types.ExecutorShortID(id) == issue.Assignee {
+			return false
+		}
+	}
+	return true
+}
+
+// ======================================================================
+// EXECUTION HISTORY AND INTERVENTIONS
+// ======================================================================
+
+// GetExecutionHistory returns the issue's attempts, oldest first
+func (f *FakeStorage) GetExecutionHistory(ctx context.Context, issueID string) ([]*types.ExecutionAttempt, error) {
+	if err := f.begin("GetExecutionHistory", issueID); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var result []*types.ExecutionAttempt
+	for _, attempt := range f.attempts {
+		if attempt.IssueID == issueID {
+			result = append(result, copyAttempt(attempt))
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].StartedAt.Before(result[j].StartedAt) })
+	return result, nil
+}
+
+// GetExecutionPhases returns the attempts started since the given time that
+// recorded phase timings, oldest first, with only the fields the beads
+// storage reads set
+func (f *FakeStorage) GetExecutionPhases(ctx context.Context, since time.Time) ([]*types.ExecutionAttempt, error) {
+	if err := f.begin("GetExecutionPhases", since); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var result []*types.ExecutionAttempt
+	for _, attempt := range f.attempts {
+		if len(attempt.Phases) > 0 && !attempt.StartedAt.Before(since) {
+			result = append(result, &types.ExecutionAttempt{
+				ID:            attempt.ID,
+				IssueID:       attempt.IssueID,
+				AttemptNumber: attempt.AttemptNumber,
+				StartedAt:     attempt.StartedAt,
+				Success:       attempt.Success,
+				Phases:        append([]types.PhaseTiming(nil), attempt.Phases...),
+			})
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].StartedAt.Before(result[j].StartedAt) })
+	return result, nil
+}
+
+// RecordExecutionAttempt stores an attempt and assigns its ID
+func (f *FakeStorage) RecordExecutionAttempt(ctx context.Context, attempt *types.ExecutionAttempt) error {
+	if err := f.begin("RecordExecutionAttempt", attempt); err != nil {
+		return err
+	}
+	if err := attempt.Validate(); err != nil {
+		return fmt.Errorf("invalid execution attempt: %w", err)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.attemptID++
+	attempt.ID = f.attemptID
+	f.attempts = append(f.attempts, copyAttempt(attempt))
+	return nil
+}
+
+// UpdateExecutionAttempt overwrites the outcome fields of a recorded attempt
+func (f *FakeStorage) UpdateExecutionAttempt(ctx context.Context, attempt *types.ExecutionAttempt) error {
+	if err := f.begin("UpdateExecutionAttempt", attempt); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, stored := range f.attempts {
+		if stored.ID == attempt.ID {
+			stored.CompletedAt = attempt.CompletedAt
+			stored.Success = attempt.Success
+			stored.ExitCode = attempt.ExitCode
+			stored.Summary = attempt.Summary
+			stored.OutputSample = attempt.OutputSample
+			stored.ErrorSample = attempt.ErrorSample
+			stored.Artifacts = append([]string(nil), attempt.Artifacts...)
+			stored.Phases = append([]types.PhaseTiming(nil), attempt.Phases...)
+			stored.CommitHash = attempt.CommitHash
+			stored.Branch = attempt.Branch
+			stored.MergeCommit = attempt.MergeCommit
+			stored.Files = append([]string(nil), attempt.Files...)
+			return nil
+		}
+	}
+	return fmt.Errorf("execution attempt %d not found", attempt.ID)
+}
+
+// copyAttempt copies an attempt along with its artifacts and phases
+func copyAttempt(attempt *types.ExecutionAttempt) *types.ExecutionAttempt {
+	a := *attempt
+	a.Artifacts = append([]string(nil), attempt.Artifacts...)
+	a.Phases = append([]types.PhaseTiming(nil), attempt.Phases...)
+	a.Files = append([]string(nil), attempt.Files...)
+	return &a
+}
+
+// CleanupExecutionHistory deletes attempts beyond the newest keepPerIssue
+// of their issue that are also older than retentionDays, keeping those of
+// blocked issues, unclosed escalated issues and issues whose watchdog
+// escalation is still open, and returns the deleted attempts
+func (f *FakeStorage) CleanupExecutionHistory(ctx context.Context, keepPerIssue, retentionDays, batchSize int) ([]*types.ExecutionAttempt, error) {
+	if err := f.begin("CleanupExecutionHistory", keepPerIssue, retentionDays, batchSize); err != nil {
+		return nil, err
+	}
+	if keepPerIssue < 0 || retentionDays < 0 {
+		return nil, fmt.Errorf("history retention cannot be negative")
+	}
+	if batchSize < 1 {
+		return nil, fmt.Errorf("batch size must be at least 1")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	exempt := make(map[string]bool)
+	for id, issue := range f.issues {
+		if issue.Status == types.StatusBlocked || (issue.Status != types.StatusClosed && f.hasLabel(id, "escalated")) {
+			exempt[id] = true
+		}
+	}
+	for _, record := range f.interventions {
+		if escalation := f.issues[record.EscalationIssueID]; escalation != nil && escalation.Status != types.StatusClosed {
+			exempt[record.IssueID] = true
+		}
+	}
+
+	// Ranks each attempt within its issue, newest first
+	newest := append([]*types.ExecutionAttempt(nil), f.attempts...)
+	sort.SliceStable(newest, func(i, j int) bool {
+		if !newest[i].StartedAt.Equal(newest[j].StartedAt) {
+			return newest[i].StartedAt.After(newest[j].StartedAt)
+		}
+		return newest[i].ID > newest[j].ID
+	})
+	cutoff := time.Now().AddDate(0, 0, -retentionDays)
+	rank := make(map[string]int)
+	doomed := make(map[*types.ExecutionAttempt]bool)
+	var deleted []*types.ExecutionAttempt
+	for _, attempt := range newest {
+		rank[attempt.IssueID]++
+		if rank[attempt.IssueID] > keepPerIssue && attempt.StartedAt.Before(cutoff) && !exempt[attempt.IssueID] {
+			doomed[attempt] = true
+			deleted = append(deleted, copyAttempt(attempt))
+		}
+	}
+	kept := f.attempts[:0]
+	for _, attempt := range f.attempts {
+		if !doomed[attempt] {
+			kept = append(kept, attempt)
+		}
+	}
+	f.attempts = kept
+	sort.SliceStable(deleted, func(i, j int) bool { return deleted[i].StartedAt.Before(deleted[j].StartedAt) })
+	return deleted, nil
+}
+
+// SaveAssessment stores a copy of the record, replacing the one for the
+// same issue and attempt
+func (f *FakeStorage) SaveAssessment(ctx context.Context, record *types.AssessmentRecord) error {
+	if err := f.begin("SaveAssessment", record); err != nil {
+		return err
+	}
+	if err := record.Validate(); err != nil {
+		return fmt.Errorf("invalid assessment: %w", err)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.issues[record.IssueID] == nil {
+		return fmt.Errorf("failed to save assessment: issue %s not found", record.IssueID)
+	}
+
+	now := time.Now()
+	if record.CreatedAt.IsZero() {
+		record.CreatedAt = now
+	}
+	record.UpdatedAt = now
+	stored := copyAssessmentRecord(record)
+	records := f.assessments[record.IssueID]
+	for i, existing := range records {
+		if existing.Attempt == record.Attempt {
+			stored.CreatedAt = existing.CreatedAt
+			records[i] = stored
+			return nil
+		}
+	}
+	records = append(records, stored)
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Attempt < records[j].Attempt })
+	f.assessments[record.IssueID] = records
+	return nil
+}
+
+// GetLatestAssessment returns a copy of the issue's highest-attempt record
+func (f *FakeStorage) GetLatestAssessment(ctx context.Context, issueID string) (*types.AssessmentRecord, error) {
+	if err := f.begin("GetLatestAssessment", issueID); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	records := f.assessments[issueID]
+	if len(records) == 0 {
+		return nil, nil
+	}
+	return copyAssessmentRecord(records[len(records)-1]), nil
+}
+
+// copyAssessmentRecord deep-copies the record's slices
+func copyAssessmentRecord(record *types.AssessmentRecord) *types.AssessmentRecord {
+	r := *record
+	r.Assessment.Steps = append([]string(nil), record.Assessment.Steps...)
+	r.Assessment.Risks = append([]string(nil), record.Assessment.Risks...)
+	r.CompletedSteps = append([]int(nil), record.CompletedSteps...)
+	return &r
+}
+
+// RecordIntervention stores a watchdog intervention and assigns its ID
+func (f *FakeStorage) RecordIntervention(ctx context.Context, record *types.InterventionRecord) error {
+	if err := f.begin("RecordIntervention", record); err != nil {
+		return err
+	}
+	if record.CreatedAt.IsZero() {
+		return fmt.Errorf("created_at is required")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.interventID++
+	record.ID = f.interventID
+	r := *record
+	f.interventions = append(f.interventions, &r)
+	return nil
+}
+
+// GetInterventions returns matching interventions, newest first
+func (f *FakeStorage) GetInterventions(ctx context.Context, filter types.InterventionFilter) ([]*types.InterventionRecord, error) {
+	if err := f.begin("GetInterventions", filter); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var result []*types.InterventionRecord
+	for _, record := range f.interventions {
+		if filter.IssueID != "" && record.IssueID != filter.IssueID {
+			continue
+		}
+		if !filter.Since.IsZero() && record.CreatedAt.Before(filter.Since) {
+			continue
+		}
+		r := *record
+		result = append(result, &r)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.After(result[j].CreatedAt)
+		}
+		return result[i].ID > result[j].ID
+	})
+	if filter.Limit > 0 && len(result) > filter.Limit {
+		result = result[:filter.Limit]
+	}
+	return result, nil
+}
+
+// ======================================================================
+// CONFIG
+// ======================================================================
+
+// GetConfig returns the value for key, or "" if it isn't set
+func (f *FakeStorage) GetConfig(ctx context.Context, key string) (string, error) {
+	if err := f.begin("GetConfig", key); err != nil {
+		return "", err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.config[key], nil
+}
+
+// SetConfig sets the value for key, validating known settings. Changing an
+// executor setting stores a SYSTEM config_changed event.
+func (f *FakeStorage) SetConfig(ctx context.Context, key, value string) error {
+	if err := f.begin("SetConfig", key, value); err != nil {
+		return err
+	}
+	if err := config.CheckSetting(key, value); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	old := f.config[key]
+	f.config[key] = value
+	f.mu.Unlock()
+
+	if old != value && config.IsExecutorSetting(key) {
+		data := events.ConfigChangedData{Key: key, OldValue: old, NewValue: value}
+		event, err := events.NewConfigChangedEvent("SYSTEM", "", "", events.SeverityInfo,
+			fmt.Sprintf("Config %s changed from %q to %q", key, old, value), data)
+		if err == nil {
+			_ = f.StoreAgentEvent(ctx, event)
+		}
+	}
+	return nil
+}
+
+// ListConfig returns the values whose key starts with prefix
+func (f *FakeStorage) ListConfig(ctx context.Context, prefix string) (map[string]string, error) {
+	if err := f.begin("ListConfig", prefix); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	result := make(map[string]string)
+	for key, value := range f.config {
+		if strings.HasPrefix(key, prefix) {
+			result[key] = value
+		}
+	}
+	return result, nil
+}
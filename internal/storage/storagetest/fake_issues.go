@@ -0,0 +1,1470 @@
+package storagetest
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// ISSUES
+// ======================================================================
+
+// fakeSnapshot is the issue state an all-or-nothing batch rolls back to
+type fakeSnapshot struct {
+	issues     map[string]*types.Issue
+	missions   map[string]*types.Mission
+	labels     map[string][]string
+	deps       []*types.Dependency
+	events     []*types.Event
+	nextID     int
+	projectIDs map[string]int
+	eventID    int64
+}
+
+// snapshot saves the issue state. Caller holds mu.
+func (f *FakeStorage) snapshot() fakeSnapshot {
+	s := fakeSnapshot{
+		issues:     make(map[string]*types.Issue, len(f.issues)),
+		missions:   make(map[string]*types.Mission, len(f.missions)),
+		labels:     make(map[string][]string, len(f.labels)),
+		deps:       append([]*types.Dependency(nil), f.deps...),
+		events:     append([]*types.Event(nil), f.events...),
+		nextID:     f.nextID,
+		projectIDs: make(map[string]int, len(f.projectIDs)),
+		eventID:    f.eventID,
+	}
+	for project, n := range f.projectIDs {
+		s.projectIDs[project] = n
+	}
+	for id, issue := range f.issues {
+		s.issues[id] = copyIssue(issue)
+	}
+	for id, mission := range f.missions {
+		m := *mission
+		s.missions[id] = &m
+	}
+	for id, labels := range f.labels {
+		s.labels[id] = append([]string(nil), labels...)
+	}
+	return s
+}
+
+// restore rolls the issue state back to s. Caller holds mu.
+func (f *FakeStorage) restore(s fakeSnapshot) {
+	f.issues, f.missions, f.labels = s.issues, s.missions, s.labels
+	f.deps, f.events, f.nextID, f.eventID = s.deps, s.events, s.nextID, s.eventID
+	f.projectIDs = s.projectIDs
+}
+
+// CreateIssue creates an issue, assigning an ID if it has none
+func (f *FakeStorage) CreateIssue(ctx context.Context, issue *types.Issue, actor string) error {
+	if err := f.begin("CreateIssue", issue, actor); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.createIssue(issue, nil, nil, actor)
+}
+
+// CreateIssueWithMetadata creates an issue with its labels and dependencies;
+// on error nothing is written
+func (f *FakeStorage) CreateIssueWithMetadata(ctx context.Context, issue *types.Issue, labels []string, deps []*types.Dependency, actor string) error {
+	if err := f.begin("CreateIssueWithMetadata", issue, labels, deps, actor); err != nil {
+		return err
+	}
+	opts := types.CreateIssuesOptions{
+		Labels:       map[int][]string{0: labels},
+		Dependencies: map[int][]*types.Dependency{0: deps},
+		AllOrNothing: true,
+	}
+	if err := f.createIssues([]*types.Issue{issue}, actor, opts); err != nil {
+		if batchErr, ok := err.(*types.BatchCreateError); ok {
+			return batchErr.Errors[0]
+		}
+		return err
+	}
+	return nil
+}
+
+// CreateIssues creates a batch of issues, reporting failures in a
+// *types.BatchCreateError keyed by batch index
+func (f *FakeStorage) CreateIssues(ctx context.Context, issues []*types.Issue, actor string, opts types.CreateIssuesOptions) error {
+	if err := f.begin("CreateIssues", issues, actor, opts); err != nil {
+		return err
+	}
+	return f.createIssues(issues, actor, opts)
+}
+
+// createIssues creates a batch, consulting the CreateIssue failure hook for
+// every issue before taking the lock
+func (f *FakeStorage) createIssues(issues []*types.Issue, actor string, opts types.CreateIssuesOptions) error {
+	failed := make(map[int]error)
+	for i, issue := range issues {
+		if err := f.injected("CreateIssue", issue, actor); err != nil {
+			failed[i] = err
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var saved fakeSnapshot
+	originalIDs := make([]string, len(issues))
+	if opts.AllOrNothing {
+		saved = f.snapshot()
+		for i, issue := range issues {
+			originalIDs[i] = issue.ID
+		}
+	}
+
+	for i, issue := range issues {
+		if _, ok := failed[i]; ok {
+			continue
+		}
+		if err := f.createIssue(issue, opts.Labels[i], opts.Dependencies[i], actor); err != nil {
+			failed[i] = err
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+
+	if opts.AllOrNothing {
+		f.restore(saved)
+		for i, issue := range issues {
+			issue.ID = originalIDs[i]
+		}
+		return &types.BatchCreateError{Errors: failed, Total: len(issues), RolledBack: true}
+	}
+	return &types.BatchCreateError{Errors: failed, Total: len(issues)}
+}
+
+// createIssue validates and stores one issue with its labels and
+// dependencies. Nothing is written on error. Caller holds mu.
+func (f *FakeStorage) createIssue(issue *types.Issue, labels []string, deps []*types.Dependency, actor string) error {
+	if err := issue.Validate(); err != nil {
+		return fmt.Errorf("validation failed: %w", err)
+	}
+	project := issue.Project
+	if project == "" {
+		project = types.DefaultProject
+	}
+	if project != types.DefaultProject && f.projects[project] == nil {
+		return fmt.Errorf("unknown project %s", project)
+	}
+	id := issue.ID
+	if id == "" {
+		id = f.newIssueID(project)
+	} else if _, exists := f.issues[id]; exists {
+		return fmt.Errorf("issue %s already exists", id)
+	}
+
+	now := time.Now()
+	pending := copyIssue(issue)
+	pending.ID = id
+	pending.Project = project
+	pending.CreatedAt = now
+	pending.UpdatedAt = now
+	pending.Archived = false
+
+	resolved := make([]*types.Dependency, 0, len(deps))
+	for _, dep := range deps {
+		d := *dep
+		if d.IssueID == "" {
+			d.IssueID = id
+		}
+		if d.DependsOnID == "" {
+			d.DependsOnID = id
+		}
+		if err := f.checkDependency(&d, pending); err != nil {
+			return err
+		}
+		resolved = append(resolved, &d)
+	}
+
+	issue.ID, issue.CreatedAt, issue.UpdatedAt = id, now, now
+	f.issues[id] = pending
+	if pending.IssueSubtype != "" {
+		f.missions[id] = &types.Mission{}
+	}
+	f.recordEvent(id, types.EventCreated, actor, nil, nil, nil)
+	for _, label := range labels {
+		f.addLabel(id, label, actor)
+	}
+	for _, dep := range resolved {
+		f.addDependency(dep, actor)
+	}
+	return nil
+}
+
+// GetIssue returns the issue, or nil if there is none with that ID
+func (f *FakeStorage) GetIssue(ctx context.Context, id string) (*types.Issue, error) {
+	if err := f.begin("GetIssue", id); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	issue := f.issues[id]
+	if issue == nil {
+		return nil, nil
+	}
+	got := copyIssue(issue)
+	if got.Status == types.StatusClosed && got.Resolution == "" {
+		got.Resolution = types.ResolutionFixed // Closed without CloseIssue
+	}
+	return got, nil
+}
+
+// UpdateIssue updates the fields Beads allows to change
+func (f *FakeStorage) UpdateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string) error {
+	if err := f.begin("UpdateIssue", id, updates, actor); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.updateIssue(id, updates, actor)
+}
+
+// UpdateIssueIfUnchanged updates the issue only if its updated_at still
+// equals expectedUpdatedAt
+func (f *FakeStorage) UpdateIssueIfUnchanged(ctx context.Context, id string, updates map[string]interface{}, expectedUpdatedAt time.Time, actor string) error {
+	if err := f.begin("UpdateIssueIfUnchanged", id, updates, expectedUpdatedAt, actor); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	issue := f.issues[id]
+	if issue == nil {
+		return fmt.Errorf("issue %s not found", id)
+	}
+	if !issue.UpdatedAt.Equal(expectedUpdatedAt) {
+		return &types.ConflictError{IssueID: id, ExpectedUpdatedAt: expectedUpdatedAt, ActualUpdatedAt: issue.UpdatedAt}
+	}
+	return f.updateIssue(id, updates, actor)
+}
+
+// updateIssue checks a status change against the status graph and applies
+// the updates. Caller holds mu.
+func (f *FakeStorage) updateIssue(id string, updates map[string]interface{}, actor string) error {
+	issue := f.issues[id]
+	if issue == nil {
+		return fmt.Errorf("issue %s not found", id)
+	}
+	if err := types.ValidateUpdates(updates); err != nil {
+		return err
+	}
+	if value, ok := updates["status"]; ok {
+		if err := validateStatusUpdate(id, issue.Status, types.Status(fmt.Sprint(value))); err != nil {
+			return err
+		}
+	}
+	return f.applyUpdates(issue, updates, actor)
+}
+
+// applyUpdates writes updates to the issue, or nothing if one is invalid.
+// Caller holds mu.
+func (f *FakeStorage) applyUpdates(issue *types.Issue, updates map[string]interface{}, actor string) error {
+	updated := copyIssue(issue)
+	for key, value := range updates {
+		var err error
+		switch key {
+		case "title":
+			updated.Title = stringValue(value)
+		case "description":
+			updated.Description = stringValue(value)
+		case "design":
+			updated.Design = stringValue(value)
+		case "acceptance_criteria":
+			updated.AcceptanceCriteria = stringValue(value)
+		case "notes":
+			updated.Notes = stringValue(value)
+		case "assignee":
+			updated.Assignee = stringValue(value)
+		case "status":
+			updated.Status = types.Status(stringValue(value))
+		case "issue_type":
+			updated.IssueType = types.IssueType(stringValue(value))
+		case "priority":
+			updated.Priority, err = intValue(key, value)
+		case "estimated_minutes":
+			if value == nil {
+				updated.EstimatedMinutes = nil
+				break
+			}
+			var minutes int
+			minutes, err = intValue(key, value)
+			updated.EstimatedMinutes = &minutes
+		case "external_ref":
+		default:
+			err = fmt.Errorf("invalid field for update: %s", key)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := updated.Validate(); err != nil {
+		return err
+	}
+
+	statusChanged := updated.Status != issue.Status
+	if statusChanged {
+		if updated.Status == types.StatusClosed {
+			now := time.Now()
+			updated.ClosedAt = &now
+		} else {
+			updated.ClosedAt = nil
+			updated.Resolution = ""
+		}
+	}
+	touch(updated)
+	f.issues[issue.ID] = updated
+
+	if statusChanged {
+		f.recordEvent(issue.ID, types.EventStatusChanged, actor, strPtr(string(issue.Status)), strPtr(string(updated.Status)), nil)
+	} else {
+		f.recordEvent(issue.ID, types.EventUpdated, actor, nil, nil, nil)
+	}
+	return nil
+}
+
+// validateStatusUpdate mirrors the status graph check of the real store:
+// closing goes through CloseIssue, everything else through the graph
+func validateStatusUpdate(id string, from, to types.Status) error {
+	if to == types.StatusClosed && from != types.StatusClosed {
+		return &types.StatusTransitionError{IssueID: id, From: from, To: to, Reason: "closing requires a reason, use CloseIssue"}
+	}
+	return types.ValidateStatusTransition(id, from, to)
+}
+
+// CloseIssue closes the issue as fixed; a reason is required
+func (f *FakeStorage) CloseIssue(ctx context.Context, id string, reason string, actor string) error {
+	if err := f.begin("CloseIssue", id, reason, actor); err != nil {
+		return err
+	}
+	if err := f.closeIssue(id, reason, types.ResolutionFixed, actor); err != nil {
+		return err
+	}
+	_ = f.unblockDependents(ctx, id, actor) // The close stands either way
+	return nil
+}
+
+// CloseIssueWithResolution closes the issue and records its resolution
+func (f *FakeStorage) CloseIssueWithResolution(ctx context.Context, id string, reason string, resolution types.Resolution, actor string) error {
+	if err := f.begin("CloseIssueWithResolution", id, reason, resolution, actor); err != nil {
+		return err
+	}
+	if !resolution.IsValid() {
+		return fmt.Errorf("invalid resolution %q (valid: %v)", resolution, types.Resolutions)
+	}
+	if err := f.closeIssue(id, reason, resolution, actor); err != nil {
+		return err
+	}
+	_ = f.unblockDependents(ctx, id, actor) // The close stands either way
+	return nil
+}
+
+// closeIssue closes the issue with the resolution; a reason is required
+func (f *FakeStorage) closeIssue(id string, reason string, resolution types.Resolution, actor string) error {
+	if strings.TrimSpace(reason) == "" {
+		return fmt.Errorf("closing %s requires a reason", id)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	issue := f.issues[id]
+	if issue == nil {
+		return fmt.Errorf("issue %s not found", id)
+	}
+	now := touch(issue)
+	issue.Status = types.StatusClosed
+	issue.ClosedAt = &now
+	issue.Resolution = resolution
+	f.recordEvent(id, types.EventClosed, actor, nil, nil, strPtr(reason))
+	return nil
+}
+
+// unblockDependents mirrors the real store: the blocked issues closedID was
+// the last open blocker of are reopened or labeled per unblock_dependents
+func (f *FakeStorage) unblockDependents(ctx context.Context, closedID, actor string) error {
+	f.mu.Lock()
+	policy := f.config["unblock_dependents"]
+	var ready []string
+	for _, dep := range f.deps {
+		if dep.DependsOnID != closedID || dep.Type != types.DepBlocks {
+			continue
+		}
+		issue := f.issues[dep.IssueID]
+		if issue == nil || issue.Archived || issue.Status != types.StatusBlocked || !f.blockersClosed(issue.ID) || f.heldForReview(issue.ID) {
+			continue
+		}
+		if policy == types.UnblockLabel && f.hasLabel(issue.ID, types.ReadyToUnblockLabel) {
+			continue
+		}
+		ready = append(ready, issue.ID)
+	}
+	f.mu.Unlock()
+	if policy == types.UnblockOff {
+		return nil
+	}
+	sort.Strings(ready)
+
+	for _, id := range ready {
+		comment := fmt.Sprintf("Unblocked by closing %s", closedID)
+		if policy == types.UnblockLabel {
+			if err := f.AddLabel(ctx, id, types.ReadyToUnblockLabel, actor); err != nil {
+				return err
+			}
+			comment = fmt.Sprintf("Ready to unblock: closing %s closed its last blocker", closedID)
+		} else if err := f.UnblockIssue(ctx, id, actor); err != nil {
+			return err
+		}
+		if err := f.AddComment(ctx, id, actor, comment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// blockersClosed reports whether every issue blocking issueID is closed or
+// archived; the caller holds f.mu
+func (f *FakeStorage) blockersClosed(issueID string) bool {
+	for _, dep := range f.deps {
+		if dep.IssueID != issueID || dep.Type != types.DepBlocks {
+			continue
+		}
+		if blocker := f.issues[dep.DependsOnID]; blocker != nil && !blocker.Archived && blocker.Status != types.StatusClosed {
+			return false
+		}
+	}
+	return true
+}
+
+// heldForReview reports whether a blocked issue waits for a person: it is
+// auto-blocked or needs triage, or an escalation for it is open. Caller
+// holds mu.
+func (f *FakeStorage) heldForReview(issueID string) bool {
+	if f.hasLabel(issueID, types.AutoBlockedLabel) || f.hasLabel(issueID, types.NeedsTriageLabel) {
+		return true
+	}
+	for id, issue := range f.issues {
+		if !issue.Archived && issue.Status != types.StatusClosed && f.hasLabel(id, types.AffectedIssueLabel(issueID)) {
+			return true
+		}
+	}
+	return false
+}
+
+// OverrideIssueStatus sets the status without the graph check and records
+// the override in a comment
+func (f *FakeStorage) OverrideIssueStatus(ctx context.Context, id string, status types.Status, reason string, actor string) error {
+	if err := f.begin("OverrideIssueStatus", id, status, reason, actor); err != nil {
+		return err
+	}
+	if !status.IsValid() {
+		return fmt.Errorf("invalid status: %s", status)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	issue := f.issues[id]
+	if issue == nil {
+		return fmt.Errorf("issue %s not found", id)
+	}
+	from := issue.Status
+	if err := f.applyUpdates(issue, map[string]interface{}{"status": string(status)}, actor); err != nil {
+		return err
+	}
+
+	comment := fmt.Sprintf("Status override by %s: %s → %s (status graph check skipped)", actor, from, status)
+	if reason != "" {
+		comment += "\n\nReason: " + reason
+	}
+	f.addComment(id, actor, comment)
+	return nil
+}
+
+// stringValue converts an update value to a string
+func stringValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case *string:
+		if v == nil {
+			return ""
+		}
+		return *v
+	}
+	return fmt.Sprint(value)
+}
+
+// intValue converts an update value to an int
+func intValue(key string, value interface{}) (int, error) {
+	switch v := value.(type) {
+	case int:
+		return v, nil
+	case int64:
+		return int(v), nil
+	case float64:
+		return int(v), nil
+	case *int:
+		if v != nil {
+			return *v, nil
+		}
+	}
+	return 0, fmt.Errorf("%s must be an integer (got %T)", key, value)
+}
+
+// ======================================================================
+// MISSIONS
+// ======================================================================
+
+// CreateMission creates the mission's issue and its mission state
+func (f *FakeStorage) CreateMission(ctx context.Context, mission *types.Mission, actor string) error {
+	if err := f.begin("CreateMission", mission, actor); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.createIssue(&mission.Issue, nil, nil, actor); err != nil {
+		return err
+	}
+	state := *mission
+	state.Issue = types.Issue{}
+	f.missions[mission.ID] = &state
+	return nil
+}
+
+// GetMission returns the issue with its mission state
+func (f *FakeStorage) GetMission(ctx context.Context, id string) (*types.Mission, error) {
+	if err := f.begin("GetMission", id); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.mission(id)
+}
+
+// mission joins an issue and its mission state. Caller holds mu.
+func (f *FakeStorage) mission(id string) (*types.Mission, error) {
+	issue := f.issues[id]
+	if issue == nil {
+		return nil, fmt.Errorf("issue %s not found", id)
+	}
+	state := f.missions[id]
+	if state == nil {
+		return nil, fmt.Errorf("issue %s is not a mission", id)
+	}
+	mission := *state
+	mission.Issue = *copyIssue(issue)
+	return &mission, nil
+}
+
+// UpdateMission updates base issue fields and mission fields
+func (f *FakeStorage) UpdateMission(ctx context.Context, id string, updates map[string]interface{}, actor string) error {
+	if err := f.begin("UpdateMission", id, updates, actor); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, err := f.mission(id); err != nil {
+		return fmt.Errorf("failed to get current mission state: %w", err)
+	}
+
+	state := *f.missions[id]
+	baseUpdates := make(map[string]interface{})
+	for key, value := range updates {
+		ok, err := setMissionField(&state, key, value)
+		if err != nil {
+			return fmt.Errorf("failed to update mission metadata: %w", err)
+		}
+		if !ok {
+			baseUpdates[key] = value
+		}
+	}
+
+	if len(baseUpdates) > 0 {
+		if err := f.updateIssue(id, baseUpdates, actor); err != nil {
+			return fmt.Errorf("failed to update base issue fields: %w", err)
+		}
+	}
+	f.missions[id] = &state
+	return nil
+}
+
+// setMissionField sets one mission state field, reporting false if key
+// isn't a mission state field
+func setMissionField(state *types.Mission, key string, value interface{}) (bool, error) {
+	var err error
+	switch key {
+	case "goal":
+		state.Goal = stringValue(value)
+	case "context":
+		state.Context = stringValue(value)
+	case "sandbox_path":
+		state.SandboxPath = stringValue(value)
+	case "branch_name":
+		state.BranchName = stringValue(value)
+	case "approved_by":
+		state.ApprovedBy = stringValue(value)
+	case "gates_status":
+		state.GatesStatus = stringValue(value)
+	case "phase_count":
+		state.PhaseCount, err = intValue(key, value)
+	case "current_phase":
+		state.CurrentPhase, err = intValue(key, value)
+	case "iteration_count":
+		state.IterationCount, err = intValue(key, value)
+	case "approval_required":
+		required, ok := value.(bool)
+		if !ok {
+			err = fmt.Errorf("approval_required must be a bool (got %T)", value)
+		}
+		state.ApprovalRequired = required
+	case "approved_at":
+		state.ApprovedAt, err = timeValue(key, value)
+	case "last_gates_run":
+		state.LastGatesRun, err = timeValue(key, value)
+	default:
+		return false, nil
+	}
+	return true, err
+}
+
+// timeValue converts a nullable time update value
+func timeValue(key string, value interface{}) (*time.Time, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case time.Time:
+		return &v, nil
+	case *time.Time:
+		return v, nil
+	default:
+		return nil, fmt.Errorf("%s must be a time (got %T)", key, value)
+	}
+}
+
+// UpdateMissionState updates mission state fields only
+func (f *FakeStorage) UpdateMissionState(ctx context.Context, issueID string, updates map[string]interface{}) error {
+	if err := f.begin("UpdateMissionState", issueID, updates); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.updateMissionState(issueID, updates)
+}
+
+// updateMissionState applies updates to a copy, so a bad field leaves the
+// state untouched. Caller holds mu.
+func (f *FakeStorage) updateMissionState(issueID string, updates map[string]interface{}) error {
+	current := f.missions[issueID]
+	if f.issues[issueID] == nil || current == nil {
+		return fmt.Errorf("issue %s has no mission state", issueID)
+	}
+	state := *current
+	for key, value := range updates {
+		ok, err := setMissionField(&state, key, value)
+		if err != nil {
+			return fmt.Errorf("failed to update mission state: %w", err)
+		}
+		if !ok {
+			return fmt.Errorf("invalid mission state field: %s", key)
+		}
+	}
+	f.missions[issueID] = &state
+	return nil
+}
+
+// SetMissionGatesStatus records the gates status, stamping last_gates_run
+// for any status but pending
+func (f *FakeStorage) SetMissionGatesStatus(ctx context.Context, issueID string, status string) error {
+	if err := f.begin("SetMissionGatesStatus", issueID, status); err != nil {
+		return err
+	}
+	if !types.IsValidGatesStatus(status) {
+		return fmt.Errorf("invalid gates status: %s", status)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	updates := map[string]interface{}{"gates_status": status}
+	if status != types.GatesStatusPending {
+		updates["last_gates_run"] = time.Now()
+	}
+	return f.updateMissionState(issueID, updates)
+}
+
+// AdvanceMissionPhase moves current_phase on by one, up to phase_count
+func (f *FakeStorage) AdvanceMissionPhase(ctx context.Context, issueID string) (int, error) {
+	if err := f.begin("AdvanceMissionPhase", issueID); err != nil {
+		return 0, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	state := f.missions[issueID]
+	if f.issues[issueID] == nil || state == nil {
+		return 0, fmt.Errorf("issue %s has no mission state", issueID)
+	}
+	if state.CurrentPhase >= state.PhaseCount {
+		return 0, fmt.Errorf("mission %s is already at its last phase (%d of %d)", issueID, state.CurrentPhase, state.PhaseCount)
+	}
+	advanced := *state
+	advanced.CurrentPhase++
+	f.missions[issueID] = &advanced
+	return advanced.CurrentPhase, nil
+}
+
+// GetMissionsByStatus returns the non-archived missions with the status,
+// by priority then age
+func (f *FakeStorage) GetMissionsByStatus(ctx context.Context, status types.Status) ([]*types.Mission, error) {
+	if err := f.begin("GetMissionsByStatus", status); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var result []*types.Mission
+	for id, issue := range f.issues {
+		if f.isMission(id) && issue.Status == status && !issue.Archived {
+			mission, err := f.mission(id)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, mission)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Priority != result[j].Priority {
+			return result[i].Priority < result[j].Priority
+		}
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.Before(result[j].CreatedAt)
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result, nil
+}
+
+// ApproveMission approves a mission's plan, reopening it if an earlier
+// rejection blocked it
+func (f *FakeStorage) ApproveMission(ctx context.Context, missionID, actor, comment string) error {
+	if err := f.begin("ApproveMission", missionID, actor, comment); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	err := f.approveMission(missionID, actor)
+	if err == nil {
+		f.addComment(missionID, actor, reviewComment("Plan approved", actor, comment))
+	}
+	f.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	f.storeReviewEvent(ctx, events.MissionApprovalData{MissionID: missionID, Approved: true, Comment: comment, Actor: actor})
+	return nil
+}
+
+// approveMission records the approval. Caller holds mu.
+func (f *FakeStorage) approveMission(missionID, actor string) error {
+	mission, err := f.reviewableMission(missionID)
+	if err != nil {
+		return err
+	}
+	if mission.ApprovedAt != nil {
+		return fmt.Errorf("mission %s was already approved by %s", missionID, mission.ApprovedBy)
+	}
+	if err := f.updateMissionState(missionID, map[string]interface{}{"approved_at": time.Now(), "approved_by": actor}); err != nil {
+		return err
+	}
+	if mission.Status == types.StatusBlocked {
+		if err := f.updateIssue(missionID, map[string]interface{}{"status": string(types.StatusOpen)}, actor); err != nil {
+			return fmt.Errorf("failed to reopen mission: %w", err)
+		}
+	}
+	return nil
+}
+
+// RejectMission clears any approval and blocks the mission
+func (f *FakeStorage) RejectMission(ctx context.Context, missionID, actor, comment string) error {
+	if err := f.begin("RejectMission", missionID, actor, comment); err != nil {
+		return err
+	}
+	if strings.TrimSpace(comment) == "" {
+		return fmt.Errorf("rejecting mission %s requires a comment", missionID)
+	}
+	f.mu.Lock()
+	err := f.rejectMission(missionID, actor)
+	if err == nil {
+		f.addComment(missionID, actor, reviewComment("Plan rejected", actor, comment))
+	}
+	f.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	f.storeReviewEvent(ctx, events.MissionApprovalData{MissionID: missionID, Approved: false, Comment: comment, Actor: actor})
+	return nil
+}
+
+// rejectMission blocks the mission and clears its approval. Caller holds mu.
+func (f *FakeStorage) rejectMission(missionID, actor string) error {
+	mission, err := f.reviewableMission(missionID)
+	if err != nil {
+		return err
+	}
+	if mission.Status != types.StatusBlocked {
+		if err := f.updateIssue(missionID, map[string]interface{}{"status": string(types.StatusBlocked)}, actor); err != nil {
+			return fmt.Errorf("failed to block mission: %w", err)
+		}
+	}
+	return f.updateMissionState(missionID, map[string]interface{}{"approved_at": nil, "approved_by": nil})
+}
+
+// reviewableMission returns the mission whose plan is reviewed. Caller holds mu.
+func (f *FakeStorage) reviewableMission(missionID string) (*types.Mission, error) {
+	mission, err := f.mission(missionID)
+	if err != nil {
+		return nil, err
+	}
+	if mission.IssueSubtype != types.SubtypeMission {
+		return nil, fmt.Errorf("issue %s is a %s, only missions have plans to review", missionID, mission.IssueSubtype)
+	}
+	return mission, nil
+}
+
+// reviewComment formats the comment a plan review leaves on the mission
+func reviewComment(verdict, actor, comment string) string {
+	text := fmt.Sprintf("**%s** by %s", verdict, actor)
+	if comment != "" {
+		text += "\n\n" + comment
+	}
+	return text
+}
+
+// storeReviewEvent records the review's agent event, best-effort as in the
+// real store
+func (f *FakeStorage) storeReviewEvent(ctx context.Context, data events.MissionApprovalData) {
+	message, severity := "Plan approved", events.SeverityInfo
+	if !data.Approved {
+		message, severity = "Plan rejected", events.SeverityWarning
+	}
+	event, err := events.NewMissionApprovalEvent(data.MissionID, "", "", severity,
+		fmt.Sprintf("%s for mission %s by %s", message, data.MissionID, data.Actor), data)
+	if err == nil {
+		_ = f.StoreAgentEvent(ctx, event)
+	}
+}
+
+// awaitingApproval returns a *types.AwaitingApprovalError if the issue is a
+// mission, or work in one, whose plan awaits approval. Caller holds mu.
+func (f *FakeStorage) awaitingApproval(issueID string) error {
+	missionID := issueID
+	if !f.isMission(issueID) {
+		missionCtx, err := f.missionForTask(issueID)
+		if err != nil {
+			return nil // Not part of a mission
+		}
+		missionID = missionCtx.MissionID
+	}
+	if !f.missions[missionID].IsApproved() {
+		return &types.AwaitingApprovalError{IssueID: issueID, MissionID: missionID}
+	}
+	return nil
+}
+
+// GetMissionForTask finds the nearest mission epic above the task through
+// parent-child links
+func (f *FakeStorage) GetMissionForTask(ctx context.Context, taskID string) (*types.MissionContext, error) {
+	if err := f.begin("GetMissionForTask", taskID); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.missionForTask(taskID)
+}
+
+// missionForTask walks up to 10 parent-child levels, noting the nearest
+// phase on the way. Caller holds mu.
+func (f *FakeStorage) missionForTask(taskID string) (*types.MissionContext, error) {
+	level := []string{taskID}
+	var phaseID string
+	for depth := 0; depth < 10 && len(level) > 0; depth++ {
+		var parents []string
+		for _, id := range level {
+			for _, dep := range f.deps {
+				if dep.IssueID == id && dep.Type == types.DepParentChild {
+					parents = append(parents, dep.DependsOnID)
+				}
+			}
+		}
+		sort.Strings(parents)
+		for _, id := range parents {
+			if f.isMission(id) {
+				state := f.missions[id]
+				missionCtx := &types.MissionContext{
+					MissionID:        id,
+					SandboxPath:      state.SandboxPath,
+					BranchName:       state.BranchName,
+					AwaitingApproval: !state.IsApproved(),
+					PhaseID:          phaseID,
+				}
+				if phaseID != "" {
+					missionCtx.PhaseWaiting = f.issues[id].Status == types.StatusBlocked ||
+						phaseID != executingPhase(f.missionPhases(id))
+				}
+				return missionCtx, nil
+			}
+		}
+		for _, id := range parents {
+			if phaseID == "" && f.isPhase(id) {
+				phaseID = id
+			}
+		}
+		level = parents
+	}
+	return nil, fmt.Errorf("task %s is not part of a mission (no parent-child dependency to mission epic)", taskID)
+}
+
+// isPhase reports whether id is a phase. Caller holds mu.
+func (f *FakeStorage) isPhase(id string) bool {
+	issue := f.issues[id]
+	return issue != nil && issue.IssueSubtype == types.SubtypePhase && f.missions[id] != nil
+}
+
+// GetMissionPhases returns the mission's non-archived phases in execution
+// order
+func (f *FakeStorage) GetMissionPhases(ctx context.Context, missionID string) ([]*types.Issue, error) {
+	if err := f.begin("GetMissionPhases", missionID); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	phases := f.missionPhases(missionID)
+	for i, phase := range phases {
+		phases[i] = copyIssue(phase)
+	}
+	return phases, nil
+}
+
+// missionPhases returns the mission's phases in execution order, uncopied.
+// Caller holds mu.
+func (f *FakeStorage) missionPhases(missionID string) []*types.Issue {
+	var phases []*types.Issue
+	for _, dep := range f.deps {
+		if dep.DependsOnID == missionID && dep.Type == types.DepParentChild &&
+			f.isPhase(dep.IssueID) && !f.issues[dep.IssueID].Archived {
+			phases = append(phases, f.issues[dep.IssueID])
+		}
+	}
+	return types.OrderPhases(phases, f.deps)
+}
+
+// executingPhase returns the ID of the first phase that isn't closed, ""
+// once all are
+func executingPhase(phases []*types.Issue) string {
+	for _, phase := range phases {
+		if phase.Status != types.StatusClosed {
+			return phase.ID
+		}
+	}
+	return ""
+}
+
+// isMission reports whether id is a mission epic. Caller holds mu.
+func (f *FakeStorage) isMission(id string) bool {
+	issue := f.issues[id]
+	return issue != nil && issue.IssueType == types.TypeEpic &&
+		issue.IssueSubtype == types.SubtypeMission && f.missions[id] != nil
+}
+
+// GetMissionsNeedingGates returns up to 10 missions labeled
+// needs-quality-gates that aren't running gates yet
+func (f *FakeStorage) GetMissionsNeedingGates(ctx context.Context) ([]*types.Issue, error) {
+	if err := f.begin("GetMissionsNeedingGates"); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var result []*types.Issue
+	for id, issue := range f.issues {
+		if f.isMission(id) && f.hasLabel(id, "needs-quality-gates") && !f.hasLabel(id, "gates-running") {
+			result = append(result, copyIssue(issue))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Priority != result[j].Priority {
+			return result[i].Priority < result[j].Priority
+		}
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+	if len(result) > 10 {
+		result = result[:10]
+	}
+	return result, nil
+}
+
+// ======================================================================
+// SEARCH AND ARCHIVING
+// ======================================================================
+
+// SearchIssues matches query against titles, descriptions and IDs
+func (f *FakeStorage) SearchIssues(ctx context.Context, query string, filter types.IssueFilter) ([]*types.Issue, error) {
+	if err := f.begin("SearchIssues", query, filter); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	result, err := f.search(query, filter)
+	if err != nil {
+		return nil, err
+	}
+	if filter.Offset > 0 {
+		if filter.Offset >= len(result) {
+			return nil, nil
+		}
+		result = result[filter.Offset:]
+	}
+	if filter.Limit > 0 && len(result) > filter.Limit {
+		result = result[:filter.Limit]
+	}
+	return result, nil
+}
+
+// CountIssues counts SearchIssues matches, ignoring Limit and Offset
+func (f *FakeStorage) CountIssues(ctx context.Context, query string, filter types.IssueFilter) (int, error) {
+	if err := f.begin("CountIssues", query, filter); err != nil {
+		return 0, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	result, err := f.search(query, filter)
+	return len(result), err
+}
+
+// CountIssuesByGroup counts CountIssues matches per value of groupBy
+func (f *FakeStorage) CountIssuesByGroup(ctx context.Context, query string, filter types.IssueFilter, groupBy string) (map[string]int, error) {
+	if err := f.begin("CountIssuesByGroup", query, filter, groupBy); err != nil {
+		return nil, err
+	}
+	if !slices.Contains(types.IssueGroupings, groupBy) {
+		return nil, fmt.Errorf("invalid grouping %q (valid: %s)", groupBy, strings.Join(types.IssueGroupings, ", "))
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	result, err := f.search(query, filter)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int)
+	for _, issue := range result {
+		switch groupBy {
+		case types.GroupByStatus:
+			counts[string(issue.Status)]++
+		case types.GroupByPriority:
+			counts[strconv.Itoa(issue.Priority)]++
+		case types.GroupByType:
+			counts[string(issue.IssueType)]++
+		case types.GroupByAssignee:
+			counts[issue.Assignee]++
+		case types.GroupByLabelPrefix:
+			prefixes := make(map[string]bool)
+			for _, label := range f.labels[issue.ID] {
+				if prefix := types.LabelPrefix(label); prefix != "" {
+					prefixes[prefix] = true
+				}
+			}
+			if len(prefixes) == 0 {
+				counts[""]++
+			}
+			for prefix := range prefixes {
+				counts[prefix]++
+			}
+		}
+	}
+	return counts, nil
+}
+
+// search returns every match in filter order. Caller holds mu.
+func (f *FakeStorage) search(query string, filter types.IssueFilter) ([]*types.Issue, error) {
+	less, err := issueOrder(filter.OrderBy, filter.Descending)
+	if err != nil {
+		return nil, err
+	}
+	issueType := filter.IssueType
+	if issueType == nil {
+		issueType = filter.Type
+	}
+	query = strings.ToLower(query)
+
+	var result []*types.Issue
+	for _, issue := range f.issues {
+		switch {
+		case issue.Archived && !filter.IncludeArchived:
+			continue
+		case issue.ID == types.SystemIssueID && !filter.IncludeSystem:
+			continue
+		case query != "" && !strings.Contains(strings.ToLower(issue.Title), query) &&
+			!strings.Contains(strings.ToLower(issue.Description), query) &&
+			!strings.Contains(strings.ToLower(issue.ID), query):
+			continue
+		case filter.Status != nil && issue.Status != *filter.Status:
+			continue
+		case filter.Priority != nil && issue.Priority != *filter.Priority:
+			continue
+		case issueType != nil && issue.IssueType != *issueType:
+			continue
+		case filter.Assignee != nil && issue.Assignee != *filter.Assignee:
+			continue
+		case filter.Project != "" && issue.Project != filter.Project:
+			continue
+		}
+		matchesLabels := true
+		for _, label := range filter.Labels {
+			if !f.hasLabel(issue.ID, label) {
+				matchesLabels = false
+				break
+			}
+		}
+		if matchesLabels && f.hasMeta(issue.ID, filter.MetaEquals) {
+			result = append(result, copyIssue(issue))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return less(result[i], result[j]) })
+	return result, nil
+}
+
+// issueOrder returns the comparison for an IssueFilter.OrderBy column, with
+// the ID as tiebreaker. Empty means priority, then newest first.
+func issueOrder(column string, descending bool) (func(a, b *types.Issue) bool, error) {
+	if column == "" {
+		return defaultIssueLess, nil
+	}
+	valid := false
+	for _, sortable := range types.SortableIssueColumns {
+		if column == sortable {
+			valid = true
+		}
+	}
+	if !valid {
+		return nil, fmt.Errorf("invalid order by column %q (valid: %s)", column, strings.Join(types.SortableIssueColumns, ", "))
+	}
+
+	compare := func(a, b *types.Issue) int {
+		switch column {
+		case "title":
+			return strings.Compare(a.Title, b.Title)
+		case "status":
+			return strings.Compare(string(a.Status), string(b.Status))
+		case "priority":
+			return a.Priority - b.Priority
+		case "issue_type":
+			return strings.Compare(string(a.IssueType), string(b.IssueType))
+		case "assignee":
+			return strings.Compare(a.Assignee, b.Assignee)
+		case "created_at":
+			return a.CreatedAt.Compare(b.CreatedAt)
+		case "updated_at":
+			return a.UpdatedAt.Compare(b.UpdatedAt)
+		case "closed_at":
+			// NULLs sort first, as in SQLite
+			switch {
+			case a.ClosedAt == nil && b.ClosedAt == nil:
+				return 0
+			case a.ClosedAt == nil:
+				return -1
+			case b.ClosedAt == nil:
+				return 1
+			}
+			return a.ClosedAt.Compare(*b.ClosedAt)
+		}
+		return 0
+	}
+	return func(a, b *types.Issue) bool {
+		c := compare(a, b)
+		if c == 0 {
+			c = strings.Compare(a.ID, b.ID)
+		}
+		if descending {
+			return c > 0
+		}
+		return c < 0
+	}, nil
+}
+
+// defaultIssueLess orders by priority, then newest first, then ID
+func defaultIssueLess(a, b *types.Issue) bool {
+	if a.Priority != b.Priority {
+		return a.Priority < b.Priority
+	}
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.After(b.CreatedAt)
+	}
+	return a.ID < b.ID
+}
+
+// ArchiveIssue hides the issue from queries; archiving twice is fine
+func (f *FakeStorage) ArchiveIssue(ctx context.Context, id string, actor string) error {
+	if err := f.begin("ArchiveIssue", id, actor); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	issue := f.issues[id]
+	if issue == nil {
+		return fmt.Errorf("issue %s not found", id)
+	}
+	if !issue.Archived {
+		issue.Archived = true
+		f.addComment(id, actor, "Archived by "+actor)
+	}
+	return nil
+}
+
+// UnarchiveIssue brings an archived issue back
+func (f *FakeStorage) UnarchiveIssue(ctx context.Context, id string, actor string) error {
+	if err := f.begin("UnarchiveIssue", id, actor); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	issue := f.issues[id]
+	if issue == nil || !issue.Archived {
+		return fmt.Errorf("issue %s is not archived", id)
+	}
+	issue.Archived = false
+	f.addComment(id, actor, "Unarchived by "+actor)
+	return nil
+}
+
+// UnblockIssue reopens a blocked issue and removes the auto-blocked and
+// ready-to-unblock labels
+func (f *FakeStorage) UnblockIssue(ctx context.Context, id string, actor string) error {
+	if err := f.begin("UnblockIssue", id, actor); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	issue := f.issues[id]
+	if issue == nil {
+		f.mu.Unlock()
+		return fmt.Errorf("issue %s not found", id)
+	}
+	if issue.Status != types.StatusBlocked {
+		f.mu.Unlock()
+		return fmt.Errorf("issue %s is not blocked (status %s)", id, issue.Status)
+	}
+	if err := f.updateIssue(id, map[string]interface{}{"status": string(types.StatusOpen)}, actor); err != nil {
+		f.mu.Unlock()
+		return fmt.Errorf("failed to reopen issue %s: %w", id, err)
+	}
+	autoBlocked := f.hasLabel(id, types.AutoBlockedLabel)
+	readyToUnblock := f.hasLabel(id, types.ReadyToUnblockLabel)
+	f.mu.Unlock()
+	if autoBlocked {
+		if err := f.RemoveLabel(ctx, id, types.AutoBlockedLabel, actor); err != nil {
+			return err
+		}
+	}
+	if readyToUnblock {
+		if err := f.RemoveLabel(ctx, id, types.ReadyToUnblockLabel, actor); err != nil {
+			return err
+		}
+	}
+
+	event, err := events.NewIssueUnblockedEvent(id, "", "", events.SeverityInfo,
+		fmt.Sprintf("Issue %s unblocked by %s", id, actor),
+		events.IssueUnblockedData{AutoBlocked: autoBlocked, Actor: actor})
+	if err == nil {
+		_ = f.StoreAgentEvent(ctx, event)
+	}
+	return nil
+}
+
+// EnsureSystemIssue creates the SYSTEM pseudo-issue unless it exists
+func (f *FakeStorage) EnsureSystemIssue(ctx context.Context) (bool, error) {
+	if err := f.begin("EnsureSystemIssue"); err != nil {
+		return false, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.issues[types.SystemIssueID] != nil {
+		return false, nil
+	}
+	f.issues[types.SystemIssueID] = types.NewSystemIssue(time.Now())
+	return true, nil
+}
+
+// ======================================================================
+// LABELS AND COMMENTS
+// ======================================================================
+
+// AddLabel labels the issue; adding a label twice is a no-op
+func (f *FakeStorage) AddLabel(ctx context.Context, issueID, label, actor string) error {
+	if err := f.begin("AddLabel", issueID, label, actor); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.issues[issueID] == nil {
+		return fmt.Errorf("issue %s not found", issueID)
+	}
+	f.addLabel(issueID, label, actor)
+	return nil
+}
+
+// addLabel adds a label and its event. Caller holds mu.
+func (f *FakeStorage) addLabel(issueID, label, actor string) {
+	if f.hasLabel(issueID, label) {
+		return
+	}
+	labels := append(f.labels[issueID], label)
+	sort.Strings(labels)
+	f.labels[issueID] = labels
+	f.recordEvent(issueID, types.EventLabelAdded, actor, nil, nil, strPtr(fmt.Sprintf("Added label: %s", label)))
+}
+
+// RemoveLabel removes the label from the issue
+func (f *FakeStorage) RemoveLabel(ctx context.Context, issueID, label, actor string) error {
+	if err := f.begin("RemoveLabel", issueID, label, actor); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	labels := f.labels[issueID]
+	for i, l := range labels {
+		if l == label {
+			f.labels[issueID] = append(labels[:i:i], labels[i+1:]...)
+			f.recordEvent(issueID, types.EventLabelRemoved, actor, nil, nil, strPtr(fmt.Sprintf("Removed label: %s", label)))
+			break
+		}
+	}
+	return nil
+}
+
+// GetLabels returns the issue's labels, sorted
+func (f *FakeStorage) GetLabels(ctx context.Context, issueID string) ([]string, error) {
+	if err := f.begin("GetLabels", issueID); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.labels[issueID]...), nil
+}
+
+// GetIssuesByLabel returns the non-archived issues with the label
+func (f *FakeStorage) GetIssuesByLabel(ctx context.Context, label string) ([]*types.Issue, error) {
+	if err := f.begin("GetIssuesByLabel", label); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var result []*types.Issue
+	for id, issue := range f.issues {
+		if !issue.Archived && f.hasLabel(id, label) {
+			result = append(result, copyIssue(issue))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return defaultIssueLess(result[i], result[j]) })
+	return result, nil
+}
+
+// hasLabel reports whether the issue has the label. Caller holds mu.
+func (f *FakeStorage) hasLabel(issueID, label string) bool {
+	for _, l := range f.labels[issueID] {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+// AddComment adds a comment to the issue's audit trail
+func (f *FakeStorage) AddComment(ctx context.Context, issueID, actor, comment string) error {
+	if err := f.begin("AddComment", issueID, actor, comment); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.issues[issueID] == nil {
+		return fmt.Errorf("issue %s not found", issueID)
+	}
+	f.addComment(issueID, actor, comment)
+	return nil
+}
+
+// addComment records a comment and bumps updated_at. Caller holds mu.
+func (f *FakeStorage) addComment(issueID, actor, comment string) {
+	touch(f.issues[issueID])
+	f.recordEvent(issueID, types.EventCommented, actor, nil, nil, strPtr(comment))
+}
+
+// GetEvents returns the issue's audit events, newest first
+func (f *FakeStorage) GetEvents(ctx context.Context, issueID string, limit int) ([]*types.Event, error) {
+	if err := f.begin("GetEvents", issueID, limit); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var result []*types.Event
+	for i := len(f.events) - 1; i >= 0; i-- {
+		if f.events[i].IssueID != issueID {
+			continue
+		}
+		event := *f.events[i]
+		result = append(result, &event)
+		if limit > 0 && len(result) == limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+// ======================================================================
+// PROJECTS
+// ======================================================================
+
+// CreateProject adds a project with its own ID prefix
+func (f *FakeStorage) CreateProject(ctx context.Context, project *types.Project) error {
+	if err := f.begin("CreateProject", project); err != nil {
+		return err
+	}
+	if err := project.Validate(); err != nil {
+		return fmt.Errorf("invalid project: %w", err)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if project.Name == types.DefaultProject || f.projects[project.Name] != nil {
+		return fmt.Errorf("project %s already exists", project.Name)
+	}
+	if project.Prefix == "vc" {
+		return fmt.Errorf("prefix %s is used by project %s", project.Prefix, types.DefaultProject)
+	}
+	for _, p := range f.projects {
+		if p.Prefix == project.Prefix {
+			return fmt.Errorf("prefix %s is used by another project", project.Prefix)
+		}
+	}
+	project.CreatedAt = time.Now()
+	p := *project
+	f.projects[p.Name] = &p
+	return nil
+}
+
+// ListProjects returns the default project (prefix vc) first, then the
+// others by name
+func (f *FakeStorage) ListProjects(ctx context.Context) ([]*types.Project, error) {
+	if err := f.begin("ListProjects"); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	result := make([]*types.Project, 0, len(f.projects)+1)
+	for _, project := range f.projects {
+		p := *project
+		result = append(result, &p)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return append([]*types.Project{{Name: types.DefaultProject, Prefix: "vc"}}, result...), nil
+}
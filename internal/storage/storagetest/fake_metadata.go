@@ -0,0 +1,78 @@
+package storagetest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// ISSUE METADATA
+// ======================================================================
+
+// SetIssueMeta stores value under key on the issue, replacing any previous
+// value
+func (f *FakeStorage) SetIssueMeta(ctx context.Context, issueID, key string, value json.RawMessage) error {
+	if err := f.begin("SetIssueMeta", issueID, key, value); err != nil {
+		return err
+	}
+	if err := types.ValidateMetaKey(key); err != nil {
+		return err
+	}
+	value, err := types.NormalizeMetaValue(value)
+	if err != nil {
+		return fmt.Errorf("invalid value of %s: %w", key, err)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.issues[issueID] == nil {
+		return fmt.Errorf("issue %s not found", issueID)
+	}
+	if f.meta[issueID] == nil {
+		f.meta[issueID] = make(map[string]json.RawMessage)
+	}
+	f.meta[issueID][key] = value
+	return nil
+}
+
+// GetIssueMeta returns the issue's metadata by key
+func (f *FakeStorage) GetIssueMeta(ctx context.Context, issueID string) (map[string]json.RawMessage, error) {
+	if err := f.begin("GetIssueMeta", issueID); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	meta := make(map[string]json.RawMessage, len(f.meta[issueID]))
+	for key, value := range f.meta[issueID] {
+		meta[key] = append(json.RawMessage(nil), value...)
+	}
+	return meta, nil
+}
+
+// DeleteIssueMeta removes key from the issue's metadata
+func (f *FakeStorage) DeleteIssueMeta(ctx context.Context, issueID, key string) error {
+	if err := f.begin("DeleteIssueMeta", issueID, key); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.meta[issueID][key]; !ok {
+		return fmt.Errorf("issue %s has no metadata %s", issueID, key)
+	}
+	delete(f.meta[issueID], key)
+	return nil
+}
+
+// hasMeta reports whether the issue's metadata has every value of
+// IssueFilter.MetaEquals. Caller holds mu.
+func (f *FakeStorage) hasMeta(issueID string, want map[string]string) bool {
+	for key, text := range want {
+		value, err := types.ParseMetaValue(text)
+		if err != nil || string(f.meta[issueID][key]) != string(value) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,136 @@
+package storagetest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// SCHEDULES
+// ======================================================================
+
+// CreateSchedule stores a new schedule, setting its ID and CreatedAt
+func (f *FakeStorage) CreateSchedule(ctx context.Context, schedule *types.Schedule) error {
+	if err := f.begin("CreateSchedule", schedule); err != nil {
+		return err
+	}
+	if err := schedule.Validate(); err != nil {
+		return fmt.Errorf("invalid schedule: %w", err)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.scheduleID++
+	schedule.ID = f.scheduleID
+	schedule.CreatedAt = time.Now()
+	s := copySchedule(schedule)
+	s.Runs = 0
+	s.LastRunAt = nil
+	s.LastIssueID = ""
+	f.schedules[s.ID] = s
+	return nil
+}
+
+// GetSchedule returns the schedule, or (nil, nil) if there is none
+func (f *FakeStorage) GetSchedule(ctx context.Context, id int64) (*types.Schedule, error) {
+	if err := f.begin("GetSchedule", id); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	schedule := f.schedules[id]
+	if schedule == nil {
+		return nil, nil
+	}
+	return copySchedule(schedule), nil
+}
+
+// ListSchedules returns every schedule by ID
+func (f *FakeStorage) ListSchedules(ctx context.Context) ([]*types.Schedule, error) {
+	if err := f.begin("ListSchedules"); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var schedules []*types.Schedule
+	for _, schedule := range f.schedules {
+		schedules = append(schedules, copySchedule(schedule))
+	}
+	sort.Slice(schedules, func(i, j int) bool { return schedules[i].ID < schedules[j].ID })
+	return schedules, nil
+}
+
+// DeleteSchedule removes a schedule; the issues it filed stay
+func (f *FakeStorage) DeleteSchedule(ctx context.Context, id int64) error {
+	if err := f.begin("DeleteSchedule", id); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.schedules[id] == nil {
+		return fmt.Errorf("schedule %d not found", id)
+	}
+	delete(f.schedules, id)
+	return nil
+}
+
+// SetScheduleEnabled turns a schedule on or off
+func (f *FakeStorage) SetScheduleEnabled(ctx context.Context, id int64, enabled bool) error {
+	if err := f.begin("SetScheduleEnabled", id, enabled); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	schedule := f.schedules[id]
+	if schedule == nil {
+		return fmt.Errorf("schedule %d not found", id)
+	}
+	schedule.Enabled = enabled
+	return nil
+}
+
+// ClaimScheduleRun records a window handled at runAt if the schedule has
+// still handled runs windows
+func (f *FakeStorage) ClaimScheduleRun(ctx context.Context, id int64, runs int, runAt time.Time) (bool, error) {
+	if err := f.begin("ClaimScheduleRun", id, runs, runAt); err != nil {
+		return false, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	schedule := f.schedules[id]
+	if schedule == nil || schedule.Runs != runs {
+		return false, nil
+	}
+	schedule.Runs++
+	schedule.LastRunAt = &runAt
+	return true, nil
+}
+
+// SetScheduleIssue records the issue filed for the schedule's last window
+func (f *FakeStorage) SetScheduleIssue(ctx context.Context, id int64, issueID string) error {
+	if err := f.begin("SetScheduleIssue", id, issueID); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	schedule := f.schedules[id]
+	if schedule == nil {
+		return fmt.Errorf("schedule %d not found", id)
+	}
+	schedule.LastIssueID = issueID
+	return nil
+}
+
+// copySchedule returns a copy sharing nothing with s
+func copySchedule(s *types.Schedule) *types.Schedule {
+	c := *s
+	c.Labels = append([]string(nil), s.Labels...)
+	if s.LastRunAt != nil {
+		t := *s.LastRunAt
+		c.LastRunAt = &t
+	}
+	return &c
+}
@@ -0,0 +1,95 @@
+package storagetest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// GITHUB LINKS AND SYNC CURSORS
+// ======================================================================
+
+// SaveGitHubLink creates or replaces the link of link.IssueID
+func (f *FakeStorage) SaveGitHubLink(ctx context.Context, link *types.GitHubLink) error {
+	if err := f.begin("SaveGitHubLink", link); err != nil {
+		return err
+	}
+	if err := link.Validate(); err != nil {
+		return fmt.Errorf("invalid GitHub link: %w", err)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.issues[link.IssueID] == nil {
+		return fmt.Errorf("failed to save GitHub link of %s: issue not found", link.IssueID)
+	}
+	for _, other := range f.githubLinks {
+		if other.IssueID != link.IssueID && other.Repo == link.Repo && other.Number == link.Number {
+			return fmt.Errorf("failed to save GitHub link of %s: %s#%d is linked to %s", link.IssueID, link.Repo, link.Number, other.IssueID)
+		}
+	}
+	if link.SyncedAt.IsZero() {
+		link.SyncedAt = time.Now()
+	}
+	l := *link
+	f.githubLinks[link.IssueID] = &l
+	return nil
+}
+
+// GetGitHubLink returns the issue's link, or (nil, nil) if it has none
+func (f *FakeStorage) GetGitHubLink(ctx context.Context, issueID string) (*types.GitHubLink, error) {
+	if err := f.begin("GetGitHubLink", issueID); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	link := f.githubLinks[issueID]
+	if link == nil {
+		return nil, nil
+	}
+	l := *link
+	return &l, nil
+}
+
+// GetGitHubLinks returns the repository's links by number
+func (f *FakeStorage) GetGitHubLinks(ctx context.Context, repo string) ([]*types.GitHubLink, error) {
+	if err := f.begin("GetGitHubLinks", repo); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var links []*types.GitHubLink
+	for _, link := range f.githubLinks {
+		if link.Repo == repo {
+			l := *link
+			links = append(links, &l)
+		}
+	}
+	sort.Slice(links, func(i, j int) bool { return links[i].Number < links[j].Number })
+	return links, nil
+}
+
+// GetGitHubSyncCursor returns how far the repository has been synced, or the
+// zero time if it never was
+func (f *FakeStorage) GetGitHubSyncCursor(ctx context.Context, repo string) (time.Time, error) {
+	if err := f.begin("GetGitHubSyncCursor", repo); err != nil {
+		return time.Time{}, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.githubCursors[repo], nil
+}
+
+// SetGitHubSyncCursor records how far the repository has been synced
+func (f *FakeStorage) SetGitHubSyncCursor(ctx context.Context, repo string, cursor time.Time) error {
+	if err := f.begin("SetGitHubSyncCursor", repo, cursor); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.githubCursors[repo] = cursor
+	return nil
+}
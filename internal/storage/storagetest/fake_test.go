@@ -0,0 +1,104 @@
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestFakeStorageFailOn(t *testing.T) {
+	ctx := context.Background()
+	store := NewFakeStorage()
+	boom := errors.New("boom")
+
+	store.FailOn("GetReadyWork", boom)
+	if _, err := store.GetReadyWork(ctx, types.WorkFilter{}); !errors.Is(err, boom) {
+		t.Fatalf("GetReadyWork: expected the injected error, got %v", err)
+	}
+	store.FailOn("GetReadyWork", nil)
+	if _, err := store.GetReadyWork(ctx, types.WorkFilter{}); err != nil {
+		t.Fatalf("GetReadyWork: expected no error once the failure is removed, got %v", err)
+	}
+}
+
+func TestFakeStorageFailWhenInBatch(t *testing.T) {
+	ctx := context.Background()
+	store := NewFakeStorage()
+	store.FailWhen("CreateIssue", func(args []interface{}) error {
+		if args[0].(*types.Issue).Title == "Second" {
+			return errors.New("rejected")
+		}
+		return nil
+	})
+
+	batch := []*types.Issue{
+		{Title: "First", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask},
+		{Title: "Second", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask},
+	}
+	err := store.CreateIssues(ctx, batch, "test", types.CreateIssuesOptions{})
+	var batchErr *types.BatchCreateError
+	if !errors.As(err, &batchErr) || len(batchErr.Errors) != 1 || batchErr.Errors[1] == nil {
+		t.Fatalf("CreateIssues: expected the second issue to fail, got %v", err)
+	}
+	if batch[0].ID == "" || batch[1].ID != "" {
+		t.Errorf("CreateIssues: expected only the first issue created, got IDs %q and %q", batch[0].ID, batch[1].ID)
+	}
+	if n := store.CallCount("CreateIssues"); n != 1 {
+		t.Errorf("CallCount(CreateIssues) = %d, want 1", n)
+	}
+	if n := store.CallCount("CreateIssue"); n != 0 {
+		t.Errorf("CallCount(CreateIssue) = %d, want 0 (the hook is consulted, not called)", n)
+	}
+}
+
+func TestFakeStorageCalls(t *testing.T) {
+	ctx := context.Background()
+	store := NewFakeStorage()
+	store.AddIssue(&types.Issue{ID: "vc-7", Title: "Seeded", Status: types.StatusOpen, IssueType: types.TypeTask})
+
+	if err := store.AddComment(ctx, "vc-7", "alice", "hello"); err != nil {
+		t.Fatalf("AddComment: %v", err)
+	}
+	calls := store.Calls("AddComment")
+	if len(calls) != 1 || calls[0].Args[0] != "vc-7" || calls[0].Args[1] != "alice" || calls[0].Args[2] != "hello" {
+		t.Errorf("Calls(AddComment) = %+v", calls)
+	}
+	if n := len(store.Calls("")); n != 1 {
+		t.Errorf("Calls(\"\") returned %d calls, want 1 (AddIssue is not recorded)", n)
+	}
+	store.ResetCalls()
+	if n := store.CallCount("AddComment"); n != 0 {
+		t.Errorf("CallCount after ResetCalls = %d, want 0", n)
+	}
+}
+
+// TestFakeStorageClaimsLeaveReadyWork verifies the fake behaves like the
+// executor expects: a claimed issue is no longer ready, a released and
+// reopened one is ready again
+func TestFakeStorageClaimsLeaveReadyWork(t *testing.T) {
+	ctx := context.Background()
+	store := NewFakeStorage()
+	issue := &types.Issue{Title: "Work", Status: types.StatusOpen, Priority: 1, IssueType: types.TypeTask}
+	if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+
+	open := types.WorkFilter{Status: types.StatusOpen}
+	if ready, _ := store.GetReadyWork(ctx, open); !containsID(ready, issue.ID) {
+		t.Fatalf("GetReadyWork: expected %s ready before the claim, got %v", issue.ID, ids(ready))
+	}
+	if err := store.ClaimIssue(ctx, issue.ID, "executor-1"); err != nil {
+		t.Fatalf("ClaimIssue: %v", err)
+	}
+	if ready, _ := store.GetReadyWork(ctx, open); containsID(ready, issue.ID) {
+		t.Errorf("GetReadyWork: claimed issue %s still ready", issue.ID)
+	}
+	if err := store.ReleaseIssueAndReopen(ctx, issue.ID, "executor-1", "failed"); err != nil {
+		t.Fatalf("ReleaseIssueAndReopen: %v", err)
+	}
+	if ready, _ := store.GetReadyWork(ctx, open); !containsID(ready, issue.ID) {
+		t.Errorf("GetReadyWork: expected %s ready again after reopening, got %v", issue.ID, ids(ready))
+	}
+}
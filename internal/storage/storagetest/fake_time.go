@@ -0,0 +1,107 @@
+package storagetest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// ======================================================================
+// TIME ENTRIES
+// ======================================================================
+
+// AddTimeEntry stores working time logged on an issue, setting the entry's
+// ID, CreatedAt and UpdatedAt
+func (f *FakeStorage) AddTimeEntry(ctx context.Context, entry *types.TimeEntry) error {
+	if err := f.begin("AddTimeEntry", entry); err != nil {
+		return err
+	}
+	if err := entry.Validate(); err != nil {
+		return fmt.Errorf("invalid time entry: %w", err)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.issues[entry.IssueID] == nil {
+		return fmt.Errorf("issue %s not found", entry.IssueID)
+	}
+	f.timeEntryID++
+	entry.ID = f.timeEntryID
+	entry.CreatedAt = time.Now()
+	entry.UpdatedAt = entry.CreatedAt
+	c := *entry
+	f.timeEntries[c.ID] = &c
+	return nil
+}
+
+// GetTimeEntry returns the entry, or (nil, nil) if there is none
+func (f *FakeStorage) GetTimeEntry(ctx context.Context, id int64) (*types.TimeEntry, error) {
+	if err := f.begin("GetTimeEntry", id); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	entry := f.timeEntries[id]
+	if entry == nil {
+		return nil, nil
+	}
+	c := *entry
+	return &c, nil
+}
+
+// ListTimeEntries returns the issue's entries ("" for every issue's),
+// oldest first
+func (f *FakeStorage) ListTimeEntries(ctx context.Context, issueID string) ([]*types.TimeEntry, error) {
+	if err := f.begin("ListTimeEntries", issueID); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var entries []*types.TimeEntry
+	for _, entry := range f.timeEntries {
+		if issueID == "" || entry.IssueID == issueID {
+			c := *entry
+			entries = append(entries, &c)
+		}
+	}
+	// IDs grow with creation, so they order entries created in the same instant
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID < entries[j].ID })
+	return entries, nil
+}
+
+// UpdateTimeEntry replaces the entry's minutes and note
+func (f *FakeStorage) UpdateTimeEntry(ctx context.Context, id int64, minutes int, note, actor string) error {
+	if err := f.begin("UpdateTimeEntry", id, minutes, note, actor); err != nil {
+		return err
+	}
+	if err := types.ValidateTimeEntryUpdate(minutes, actor); err != nil {
+		return fmt.Errorf("invalid time entry: %w", err)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	entry := f.timeEntries[id]
+	if entry == nil {
+		return fmt.Errorf("time entry %d not found", id)
+	}
+	entry.Minutes = minutes
+	entry.Note = note
+	entry.Actor = actor
+	entry.UpdatedAt = time.Now()
+	return nil
+}
+
+// DeleteTimeEntry removes an entry
+func (f *FakeStorage) DeleteTimeEntry(ctx context.Context, id int64) error {
+	if err := f.begin("DeleteTimeEntry", id); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.timeEntries[id] == nil {
+		return fmt.Errorf("time entry %d not found", id)
+	}
+	delete(f.timeEntries, id)
+	return nil
+}
@@ -0,0 +1,58 @@
+// Package storagetest provides test stores and a conformance suite for
+// storage.Storage implementations.
+//
+// Tests that need a throwaway store use New:
+//
+//	store := storagetest.New(t)
+//
+// A backend proves it implements the Storage contract by running the suite
+// against a factory that opens fresh, empty stores:
+//
+//	func TestConformance(t *testing.T) {
+//	    storagetest.RunStorageTests(t, func(t *testing.T) storage.Storage { ... })
+//	}
+package storagetest
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/storage"
+)
+
+// Factory opens a new, empty store for one test. RunStorageTests closes it
+// when the test ends.
+type Factory func(t *testing.T) storage.Storage
+
+// New returns an empty in-memory store that is closed when the test ends
+func New(t testing.TB) storage.Storage {
+	t.Helper()
+	store, err := storage.NewInMemoryStorage(context.Background())
+	if err != nil {
+		t.Fatalf("failed to open in-memory storage: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+// InMemory is a Factory for in-memory SQLite stores
+func InMemory(t *testing.T) storage.Storage {
+	t.Helper()
+	store, err := storage.NewInMemoryStorage(context.Background())
+	if err != nil {
+		t.Fatalf("failed to open in-memory storage: %v", err)
+	}
+	return store
+}
+
+// TempFile is a Factory for SQLite stores in a temporary file
+func TempFile(t *testing.T) storage.Storage {
+	t.Helper()
+	cfg := &storage.Config{Path: filepath.Join(t.TempDir(), "vc.db")}
+	store, err := storage.NewStorage(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("failed to open storage in %s: %v", cfg.Path, err)
+	}
+	return store
+}
@@ -0,0 +1,45 @@
+package storagetest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestSQLiteInMemory(t *testing.T) {
+	RunStorageTests(t, InMemory)
+}
+
+func TestSQLiteTempFile(t *testing.T) {
+	RunStorageTests(t, TempFile)
+}
+
+func TestFakeStorage(t *testing.T) {
+	RunStorageTests(t, func(t *testing.T) storage.Storage { return NewFakeStorage() })
+}
+
+// TestInMemoryStoresAreIsolated verifies in-memory stores don't share a
+// database, so tests using them can't see each other's data
+func TestInMemoryStoresAreIsolated(t *testing.T) {
+	ctx := context.Background()
+	first := New(t)
+	second := New(t)
+
+	issue := &types.Issue{Title: "Only in the first store", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	if err := first.CreateIssue(ctx, issue, "test"); err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+
+	stats, err := second.GetStatistics(ctx)
+	if err != nil {
+		t.Fatalf("GetStatistics: %v", err)
+	}
+	if stats.TotalIssues != 0 {
+		t.Errorf("expected the second store to be empty, got %d issues", stats.TotalIssues)
+	}
+	if got, err := second.GetIssue(ctx, issue.ID); err == nil && got != nil {
+		t.Errorf("expected %s to be missing from the second store", issue.ID)
+	}
+}
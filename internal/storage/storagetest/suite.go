@@ -0,0 +1,2900 @@
+package storagetest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// RunStorageTests runs the Storage conformance suite: every method of the
+// interface is exercised against fresh stores from newStore. Failures name
+// the method group (t.Run) and the method that broke the contract.
+func RunStorageTests(t *testing.T, newStore Factory) {
+	groups := []struct {
+		name string
+		fn   func(t *testing.T, s storage.Storage)
+	}{
+		{"Issues", testIssues},
+		{"StatusTransitions", testStatusTransitions},
+		{"Validation", testValidation},
+		{"BatchCreate", testBatchCreate},
+		{"Search", testSearch},
+		{"CountIssuesByGroup", testCountIssuesByGroup},
+		{"Archive", testArchive},
+		{"Unblock", testUnblock},
+		{"UnblockDependents", testUnblockDependents},
+		{"SystemIssue", testSystemIssue},
+		{"Projects", testProjects},
+		{"Missions", testMissions},
+		{"MissionState", testMissionState},
+		{"MissionApproval", testMissionApproval},
+		{"MissionPhases", testMissionPhases},
+		{"Dependencies", testDependencies},
+		{"Labels", testLabels},
+		{"Metadata", testMetadata},
+		{"ReadyWork", testReadyWork},
+		{"ReadyWorkOrder", testReadyWorkOrder},
+		{"EpicCompletion", testEpicCompletion},
+		{"Comments", testComments},
+		{"Statistics", testStatistics},
+		{"ExecutorInstances", testExecutorInstances},
+		{"ExecutionState", testExecutionState},
+		{"Assignees", testAssignees},
+		{"ExecutionHistory", testExecutionHistory},
+		{"HistoryCleanup", testHistoryCleanup},
+		{"Assessments", testAssessments},
+		{"Interventions", testInterventions},
+		{"AgentEvents", testAgentEvents},
+		{"WatchAgentEvents", testWatchAgentEvents},
+		{"EventCleanup", testEventCleanup},
+		{"DatabaseMaintenance", testDatabaseMaintenance},
+		{"Config", testConfig},
+		{"GitHubSync", testGitHubSync},
+		{"Schedules", testSchedules},
+		{"TimeEntries", testTimeEntries},
+	}
+	for _, group := range groups {
+		t.Run(group.name, func(t *testing.T) {
+			s := newStore(t)
+			t.Cleanup(func() { _ = s.Close() })
+			group.fn(t, s)
+		})
+	}
+
+	t.Run("Close", func(t *testing.T) {
+		s := newStore(t)
+		if err := s.Close(); err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+		if _, err := s.GetStatistics(context.Background()); err == nil {
+			t.Error("GetStatistics: expected an error after Close")
+		}
+	})
+}
+
+// testActor is the actor the suite creates and updates issues as
+const testActor = "storagetest"
+
+// createIssue creates an open issue with the given title and type
+func createIssue(t *testing.T, s storage.Storage, title string, issueType types.IssueType) *types.Issue {
+	t.Helper()
+	issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 2, IssueType: issueType}
+	if err := s.CreateIssue(context.Background(), issue, testActor); err != nil {
+		t.Fatalf("CreateIssue(%q): %v", title, err)
+	}
+	return issue
+}
+
+// addDependency links issueID to dependsOnID
+func addDependency(t *testing.T, s storage.Storage, issueID, dependsOnID string, depType types.DependencyType) {
+	t.Helper()
+	dep := &types.Dependency{IssueID: issueID, DependsOnID: dependsOnID, Type: depType}
+	if err := s.AddDependency(context.Background(), dep, testActor); err != nil {
+		t.Fatalf("AddDependency(%s -> %s, %s): %v", issueID, dependsOnID, depType, err)
+	}
+}
+
+// registerInstance registers a running executor instance
+func registerInstance(t *testing.T, s storage.Storage, id string) *types.ExecutorInstance {
+	t.Helper()
+	now := time.Now()
+	instance := &types.ExecutorInstance{
+		InstanceID:    id,
+		Hostname:      "storagetest-host",
+		PID:           os.Getpid(),
+		Status:        types.ExecutorStatusRunning,
+		StartedAt:     now,
+		LastHeartbeat: now,
+		Version:       "test",
+		Metadata:      "{}",
+	}
+	if err := s.RegisterInstance(context.Background(), instance); err != nil {
+		t.Fatalf("RegisterInstance(%s): %v", id, err)
+	}
+	return instance
+}
+
+// ids returns the IDs of issues, in order
+func ids(issues []*types.Issue) []string {
+	result := make([]string, len(issues))
+	for i, issue := range issues {
+		result[i] = issue.ID
+	}
+	return result
+}
+
+// containsID reports whether issues include id
+func containsID(issues []*types.Issue, id string) bool {
+	for _, issue := range issues {
+		if issue.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func testIssues(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	issue := &types.Issue{
+		Title:              "Round trip",
+		Description:        "description",
+		Design:             "design",
+		AcceptanceCriteria: "criteria",
+		Notes:              "notes",
+		Status:             types.StatusOpen,
+		Priority:           1,
+		IssueType:          types.TypeFeature,
+	}
+	if err := s.CreateIssue(ctx, issue, testActor); err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+	if issue.ID == "" {
+		t.Fatal("CreateIssue: expected an ID to be assigned")
+	}
+
+	got, err := s.GetIssue(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetIssue: %v", err)
+	}
+	if got == nil {
+		t.Fatal("GetIssue: issue not found")
+	}
+	if got.Title != issue.Title || got.Description != issue.Description || got.Design != issue.Design ||
+		got.AcceptanceCriteria != issue.AcceptanceCriteria || got.Notes != issue.Notes ||
+		got.Priority != issue.Priority || got.IssueType != issue.IssueType || got.Status != types.StatusOpen {
+		t.Errorf("GetIssue: got %+v, want the fields of %+v", got, issue)
+	}
+
+	if missing, err := s.GetIssue(ctx, "vc-does-not-exist"); err == nil && missing != nil {
+		t.Errorf("GetIssue: expected no issue for an unknown ID, got %+v", missing)
+	}
+
+	if err := s.UpdateIssue(ctx, issue.ID, map[string]interface{}{"title": "Renamed", "priority": 3}, testActor); err != nil {
+		t.Fatalf("UpdateIssue: %v", err)
+	}
+	got, err = s.GetIssue(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetIssue: %v", err)
+	}
+	if got.Title != "Renamed" || got.Priority != 3 {
+		t.Errorf("UpdateIssue: got title %q priority %d, want \"Renamed\" 3", got.Title, got.Priority)
+	}
+
+	// A conditional update with the current updated_at wins; a stale one conflicts
+	if err := s.UpdateIssueIfUnchanged(ctx, issue.ID, map[string]interface{}{"notes": "fresh"}, got.UpdatedAt, testActor); err != nil {
+		t.Fatalf("UpdateIssueIfUnchanged: %v", err)
+	}
+	err = s.UpdateIssueIfUnchanged(ctx, issue.ID, map[string]interface{}{"notes": "stale"}, got.UpdatedAt, testActor)
+	if !errors.Is(err, types.ErrConflict) {
+		t.Errorf("UpdateIssueIfUnchanged: expected ErrConflict for a stale read, got %v", err)
+	}
+	if got, _ := s.GetIssue(ctx, issue.ID); got == nil || got.Notes != "fresh" {
+		t.Errorf("UpdateIssueIfUnchanged: a conflicting update must not be written (got %+v)", got)
+	}
+
+	if err := s.CloseIssue(ctx, issue.ID, "done", testActor); err != nil {
+		t.Fatalf("CloseIssue: %v", err)
+	}
+	got, err = s.GetIssue(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetIssue: %v", err)
+	}
+	if got.Status != types.StatusClosed || got.ClosedAt == nil {
+		t.Errorf("CloseIssue: got status %s closed_at %v, want closed with a time", got.Status, got.ClosedAt)
+	}
+	if got.Resolution != types.ResolutionFixed {
+		t.Errorf("CloseIssue: got resolution %q, want fixed", got.Resolution)
+	}
+
+	// A resolution holds while the issue stays closed
+	wontfix := createIssue(t, s, "Won't fix", types.TypeTask)
+	if err := s.CloseIssueWithResolution(ctx, wontfix.ID, "not worth it", "later", testActor); err == nil {
+		t.Error("CloseIssueWithResolution: expected an error for an invalid resolution")
+	}
+	if err := s.CloseIssueWithResolution(ctx, wontfix.ID, "not worth it", types.ResolutionWontfix, testActor); err != nil {
+		t.Fatalf("CloseIssueWithResolution: %v", err)
+	}
+	if got, err := s.GetIssue(ctx, wontfix.ID); err != nil || got.Status != types.StatusClosed || got.Resolution != types.ResolutionWontfix {
+		t.Errorf("CloseIssueWithResolution: got (%+v, %v), want closed as wontfix", got, err)
+	}
+	if err := s.UpdateIssue(ctx, wontfix.ID, map[string]interface{}{"status": string(types.StatusOpen)}, testActor); err != nil {
+		t.Fatalf("UpdateIssue(reopen): %v", err)
+	}
+	if got, err := s.GetIssue(ctx, wontfix.ID); err != nil || got.Resolution != "" {
+		t.Errorf("GetIssue: got (%+v, %v), want no resolution after reopening", got, err)
+	}
+}
+
+func testStatusTransitions(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	issue := createIssue(t, s, "Transitions", types.TypeTask)
+
+	if err := s.UpdateIssue(ctx, issue.ID, map[string]interface{}{"status": string(types.StatusBlocked)}, testActor); err != nil {
+		t.Fatalf("UpdateIssue(open -> blocked): %v", err)
+	}
+	err := s.UpdateIssue(ctx, issue.ID, map[string]interface{}{"status": string(types.StatusInProgress)}, testActor)
+	if !errors.Is(err, types.ErrIllegalTransition) {
+		t.Errorf("UpdateIssue(blocked -> in_progress): expected ErrIllegalTransition, got %v", err)
+	}
+	err = s.UpdateIssue(ctx, issue.ID, map[string]interface{}{"status": string(types.StatusClosed)}, testActor)
+	if !errors.Is(err, types.ErrIllegalTransition) {
+		t.Errorf("UpdateIssue(closed): expected ErrIllegalTransition (closing needs CloseIssue), got %v", err)
+	}
+
+	// OverrideIssueStatus skips the graph and leaves an audit comment
+	if err := s.OverrideIssueStatus(ctx, issue.ID, types.StatusInProgress, "repair", testActor); err != nil {
+		t.Fatalf("OverrideIssueStatus: %v", err)
+	}
+	got, err := s.GetIssue(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetIssue: %v", err)
+	}
+	if got.Status != types.StatusInProgress {
+		t.Errorf("OverrideIssueStatus: got status %s, want in_progress", got.Status)
+	}
+	auditEvents, err := s.GetEvents(ctx, issue.ID, 0)
+	if err != nil {
+		t.Fatalf("GetEvents: %v", err)
+	}
+	commented := false
+	for _, event := range auditEvents {
+		if event.EventType == types.EventCommented {
+			commented = true
+		}
+	}
+	if !commented {
+		t.Error("OverrideIssueStatus: expected an audit comment")
+	}
+}
+
+func testValidation(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	tooLong := types.MaxEstimatedMinutes + 1
+	bad := &types.Issue{Title: "Bad", Status: types.StatusOpen, Priority: 9, IssueType: "banana", EstimatedMinutes: &tooLong}
+	var invalid *types.ValidationError
+	if err := s.CreateIssue(ctx, bad, testActor); !errors.As(err, &invalid) || len(invalid.Fields) != 3 {
+		t.Errorf("CreateIssue(priority 9, type banana, long estimate): expected 3 invalid fields, got %v", err)
+	}
+	if err := s.CreateIssueWithMetadata(ctx, bad, nil, nil, testActor); !errors.As(err, &invalid) {
+		t.Errorf("CreateIssueWithMetadata: expected a ValidationError, got %v", err)
+	}
+
+	issue := createIssue(t, s, "Validated", types.TypeTask)
+	err := s.UpdateIssue(ctx, issue.ID, map[string]interface{}{"priority": 9, "issue_type": "banana"}, testActor)
+	if !errors.As(err, &invalid) || len(invalid.Fields) != 2 || invalid.Fields[0].Field != "issue_type" {
+		t.Errorf("UpdateIssue(priority 9, type banana): expected 2 invalid fields, got %v", err)
+	}
+	got, err := s.GetIssue(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetIssue: %v", err)
+	}
+	err = s.UpdateIssueIfUnchanged(ctx, issue.ID, map[string]interface{}{"title": ""}, got.UpdatedAt, testActor)
+	if !errors.As(err, &invalid) {
+		t.Errorf("UpdateIssueIfUnchanged(empty title): expected a ValidationError, got %v", err)
+	}
+	if got, err = s.GetIssue(ctx, issue.ID); err != nil {
+		t.Fatalf("GetIssue: %v", err)
+	}
+	if got.Priority == 9 || got.IssueType != types.TypeTask || got.Title != "Validated" {
+		t.Errorf("Rejected updates were applied: %+v", got)
+	}
+}
+
+func testBatchCreate(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	parent := createIssue(t, s, "Parent", types.TypeEpic)
+
+	child := &types.Issue{Title: "With metadata", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask}
+	deps := []*types.Dependency{{DependsOnID: parent.ID, Type: types.DepParentChild}}
+	if err := s.CreateIssueWithMetadata(ctx, child, []string{"alpha", "beta"}, deps, testActor); err != nil {
+		t.Fatalf("CreateIssueWithMetadata: %v", err)
+	}
+	labels, err := s.GetLabels(ctx, child.ID)
+	if err != nil {
+		t.Fatalf("GetLabels: %v", err)
+	}
+	if len(labels) != 2 {
+		t.Errorf("CreateIssueWithMetadata: got labels %v, want alpha and beta", labels)
+	}
+	records, err := s.GetDependencyRecords(ctx, child.ID)
+	if err != nil {
+		t.Fatalf("GetDependencyRecords: %v", err)
+	}
+	if len(records) != 1 || records[0].DependsOnID != parent.ID || records[0].Type != types.DepParentChild {
+		t.Errorf("CreateIssueWithMetadata: got dependencies %+v, want parent-child on %s", records, parent.ID)
+	}
+
+	// All-or-nothing batches roll back entirely when one issue is invalid
+	batch := []*types.Issue{
+		{Title: "Batch one", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask},
+		{Title: "", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask},
+	}
+	err = s.CreateIssues(ctx, batch, testActor, types.CreateIssuesOptions{AllOrNothing: true})
+	var batchErr *types.BatchCreateError
+	if !errors.As(err, &batchErr) || !batchErr.RolledBack {
+		t.Fatalf("CreateIssues(all or nothing): expected a rolled back BatchCreateError, got %v", err)
+	}
+	if _, ok := batchErr.Errors[1]; !ok {
+		t.Errorf("CreateIssues: expected the error keyed by index 1, got %v", batchErr.Errors)
+	}
+	if n, err := s.CountIssues(ctx, "Batch one", types.IssueFilter{}); err != nil || n != 0 {
+		t.Errorf("CreateIssues: expected nothing created after rollback, got %d (err %v)", n, err)
+	}
+
+	batch = []*types.Issue{
+		{Title: "Batch one", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask},
+		{Title: "Batch two", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask},
+	}
+	opts := types.CreateIssuesOptions{
+		Labels:       map[int][]string{0: {"batch"}},
+		Dependencies: map[int][]*types.Dependency{1: {{DependsOnID: parent.ID, Type: types.DepBlocks}}},
+	}
+	if err := s.CreateIssues(ctx, batch, testActor, opts); err != nil {
+		t.Fatalf("CreateIssues: %v", err)
+	}
+	for _, issue := range batch {
+		if issue.ID == "" {
+			t.Errorf("CreateIssues: expected an ID for %q", issue.Title)
+		}
+	}
+	if labels, err := s.GetLabels(ctx, batch[0].ID); err != nil || len(labels) != 1 || labels[0] != "batch" {
+		t.Errorf("CreateIssues: got labels %v (err %v), want [batch]", labels, err)
+	}
+	if deps, err := s.GetDependencies(ctx, batch[1].ID); err != nil || !containsID(deps, parent.ID) {
+		t.Errorf("CreateIssues: expected %s to depend on %s (err %v)", batch[1].ID, parent.ID, err)
+	}
+}
+
+func testSearch(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	for i := 0; i < 5; i++ {
+		issue := createIssue(t, s, fmt.Sprintf("Searchable %d", i), types.TypeTask)
+		if i%2 == 0 {
+			if err := s.AddLabel(ctx, issue.ID, "even", testActor); err != nil {
+				t.Fatalf("AddLabel: %v", err)
+			}
+		}
+	}
+	createIssue(t, s, "Unrelated bug", types.TypeBug)
+
+	found, err := s.SearchIssues(ctx, "Searchable", types.IssueFilter{})
+	if err != nil {
+		t.Fatalf("SearchIssues: %v", err)
+	}
+	if len(found) != 5 {
+		t.Errorf("SearchIssues: got %d issues, want 5", len(found))
+	}
+
+	bug := types.TypeBug
+	found, err = s.SearchIssues(ctx, "", types.IssueFilter{IssueType: &bug})
+	if err != nil {
+		t.Fatalf("SearchIssues: %v", err)
+	}
+	if len(found) != 1 || found[0].Title != "Unrelated bug" {
+		t.Errorf("SearchIssues(type=bug): got %v", ids(found))
+	}
+
+	found, err = s.SearchIssues(ctx, "", types.IssueFilter{Labels: []string{"even"}})
+	if err != nil {
+		t.Fatalf("SearchIssues: %v", err)
+	}
+	if len(found) != 3 {
+		t.Errorf("SearchIssues(label=even): got %d issues, want 3", len(found))
+	}
+
+	page, err := s.SearchIssues(ctx, "Searchable", types.IssueFilter{OrderBy: "title", Limit: 2, Offset: 2})
+	if err != nil {
+		t.Fatalf("SearchIssues: %v", err)
+	}
+	if len(page) != 2 || page[0].Title != "Searchable 2" || page[1].Title != "Searchable 3" {
+		t.Errorf("SearchIssues(order by title, limit 2, offset 2): got %v", ids(page))
+	}
+
+	count, err := s.CountIssues(ctx, "Searchable", types.IssueFilter{Limit: 1})
+	if err != nil {
+		t.Fatalf("CountIssues: %v", err)
+	}
+	if count != 5 {
+		t.Errorf("CountIssues: got %d, want 5 (Limit must be ignored)", count)
+	}
+}
+
+func testCountIssuesByGroup(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	ui := createIssue(t, s, "Grouped UI", types.TypeTask)
+	both := createIssue(t, s, "Grouped both", types.TypeBug)
+	plain := createIssue(t, s, "Grouped plain", types.TypeTask)
+	for issueID, labels := range map[string][]string{
+		ui.ID:    {"area:ui", "area:forms", "urgent"},
+		both.ID:  {"area:api", "component:db"},
+		plain.ID: {"urgent"},
+	} {
+		for _, label := range labels {
+			if err := s.AddLabel(ctx, issueID, label, testActor); err != nil {
+				t.Fatalf("AddLabel: %v", err)
+			}
+		}
+	}
+	if err := s.UpdateIssue(ctx, both.ID, map[string]interface{}{"assignee": "alice", "priority": 0}, testActor); err != nil {
+		t.Fatalf("UpdateIssue: %v", err)
+	}
+
+	for groupBy, want := range map[string]map[string]int{
+		types.GroupByStatus:      {"open": 3},
+		types.GroupByPriority:    {"0": 1, "2": 2},
+		types.GroupByType:        {"bug": 1, "task": 2},
+		types.GroupByAssignee:    {"": 2, "alice": 1},
+		types.GroupByLabelPrefix: {"": 1, "area": 2, "component": 1}, // Each prefix once per issue
+	} {
+		counts, err := s.CountIssuesByGroup(ctx, "Grouped", types.IssueFilter{Limit: 1}, groupBy)
+		if err != nil {
+			t.Fatalf("CountIssuesByGroup(%s): %v", groupBy, err)
+		}
+		if fmt.Sprint(counts) != fmt.Sprint(want) {
+			t.Errorf("CountIssuesByGroup(%s) = %v, want %v (Limit must be ignored)", groupBy, counts, want)
+		}
+	}
+
+	counts, err := s.CountIssuesByGroup(ctx, "Grouped", types.IssueFilter{Labels: []string{"urgent"}}, types.GroupByLabelPrefix)
+	if err != nil {
+		t.Fatalf("CountIssuesByGroup: %v", err)
+	}
+	if fmt.Sprint(counts) != fmt.Sprint(map[string]int{"": 1, "area": 1}) {
+		t.Errorf("CountIssuesByGroup(label=urgent) = %v, want the filter applied", counts)
+	}
+	if _, err := s.CountIssuesByGroup(ctx, "", types.IssueFilter{}, "title"); err == nil {
+		t.Error("CountIssuesByGroup: expected an error for an unknown grouping")
+	}
+}
+
+func testArchive(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	issue := createIssue(t, s, "Archivable", types.TypeTask)
+
+	if err := s.ArchiveIssue(ctx, issue.ID, testActor); err != nil {
+		t.Fatalf("ArchiveIssue: %v", err)
+	}
+	found, err := s.SearchIssues(ctx, "Archivable", types.IssueFilter{})
+	if err != nil {
+		t.Fatalf("SearchIssues: %v", err)
+	}
+	if len(found) != 0 {
+		t.Errorf("ArchiveIssue: expected the issue hidden from SearchIssues, got %v", ids(found))
+	}
+	found, err = s.SearchIssues(ctx, "Archivable", types.IssueFilter{IncludeArchived: true})
+	if err != nil {
+		t.Fatalf("SearchIssues: %v", err)
+	}
+	if len(found) != 1 || !found[0].Archived {
+		t.Errorf("SearchIssues(IncludeArchived): expected the archived issue, got %+v", found)
+	}
+	ready, err := s.GetReadyWork(ctx, types.WorkFilter{Status: types.StatusOpen})
+	if err != nil {
+		t.Fatalf("GetReadyWork: %v", err)
+	}
+	if containsID(ready, issue.ID) {
+		t.Error("ArchiveIssue: archived issues must not be ready work")
+	}
+
+	if err := s.UnarchiveIssue(ctx, issue.ID, testActor); err != nil {
+		t.Fatalf("UnarchiveIssue: %v", err)
+	}
+	found, err = s.SearchIssues(ctx, "Archivable", types.IssueFilter{})
+	if err != nil {
+		t.Fatalf("SearchIssues: %v", err)
+	}
+	if len(found) != 1 {
+		t.Errorf("UnarchiveIssue: expected the issue back in SearchIssues, got %v", ids(found))
+	}
+
+	// Both record who did it
+	history, err := s.GetEvents(ctx, issue.ID, 0)
+	if err != nil {
+		t.Fatalf("GetEvents: %v", err)
+	}
+	audited := map[string]bool{}
+	for _, event := range history {
+		if event.EventType == types.EventCommented && event.Comment != nil && event.Actor == testActor {
+			audited[*event.Comment] = true
+		}
+	}
+	for _, want := range []string{"Archived by " + testActor, "Unarchived by " + testActor} {
+		if !audited[want] {
+			t.Errorf("expected a %q comment by %s, got %v", want, testActor, audited)
+		}
+	}
+}
+
+func testUnblock(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	autoBlocked := createIssue(t, s, "Failed three times", types.TypeTask)
+	blocked := createIssue(t, s, "Blocked by hand", types.TypeTask)
+	for _, issue := range []*types.Issue{autoBlocked, blocked} {
+		if err := s.UpdateIssue(ctx, issue.ID, map[string]interface{}{"status": string(types.StatusBlocked)}, testActor); err != nil {
+			t.Fatalf("UpdateIssue(%s): %v", issue.ID, err)
+		}
+	}
+	if err := s.AddLabel(ctx, autoBlocked.ID, types.AutoBlockedLabel, testActor); err != nil {
+		t.Fatalf("AddLabel: %v", err)
+	}
+
+	for _, issue := range []*types.Issue{autoBlocked, blocked} {
+		if err := s.UnblockIssue(ctx, issue.ID, testActor); err != nil {
+			t.Fatalf("UnblockIssue(%s): %v", issue.ID, err)
+		}
+		got, err := s.GetIssue(ctx, issue.ID)
+		if err != nil {
+			t.Fatalf("GetIssue(%s): %v", issue.ID, err)
+		}
+		if got.Status != types.StatusOpen {
+			t.Errorf("UnblockIssue(%s): status %s, want open", issue.ID, got.Status)
+		}
+	}
+	labels, err := s.GetLabels(ctx, autoBlocked.ID)
+	if err != nil {
+		t.Fatalf("GetLabels: %v", err)
+	}
+	if len(labels) != 0 {
+		t.Errorf("UnblockIssue: expected the %s label removed, got %v", types.AutoBlockedLabel, labels)
+	}
+
+	unblocked, err := s.GetAgentEvents(ctx, events.EventFilter{Type: events.EventTypeIssueUnblocked})
+	if err != nil {
+		t.Fatalf("GetAgentEvents: %v", err)
+	}
+	if len(unblocked) != 2 {
+		t.Fatalf("UnblockIssue: expected 2 issue_unblocked events, got %d", len(unblocked))
+	}
+	for _, event := range unblocked {
+		data, err := event.GetIssueUnblockedData()
+		if err != nil {
+			t.Fatalf("GetIssueUnblockedData: %v", err)
+		}
+		if want := event.IssueID == autoBlocked.ID; data.AutoBlocked != want || data.Actor != testActor {
+			t.Errorf("UnblockIssue(%s): got %+v, want auto_blocked=%v by %s", event.IssueID, data, want, testActor)
+		}
+	}
+
+	if err := s.UnblockIssue(ctx, blocked.ID, testActor); err == nil {
+		t.Error("UnblockIssue: expected an error for an issue that isn't blocked")
+	}
+	if err := s.UnblockIssue(ctx, "nonexistent-1", testActor); err == nil {
+		t.Error("UnblockIssue: expected an error for a missing issue")
+	}
+}
+
+func testUnblockDependents(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	block := func(title string) *types.Issue {
+		t.Helper()
+		issue := createIssue(t, s, title, types.TypeTask)
+		if err := s.UpdateIssue(ctx, issue.ID, map[string]interface{}{"status": string(types.StatusBlocked)}, testActor); err != nil {
+			t.Fatalf("UpdateIssue(%s): %v", issue.ID, err)
+		}
+		return issue
+	}
+	closeIssue := func(issue *types.Issue) {
+		t.Helper()
+		if err := s.CloseIssue(ctx, issue.ID, "done", testActor); err != nil {
+			t.Fatalf("CloseIssue(%s): %v", issue.ID, err)
+		}
+	}
+	wantStatus := func(when string, want types.Status, issues ...*types.Issue) {
+		t.Helper()
+		for _, issue := range issues {
+			got, err := s.GetIssue(ctx, issue.ID)
+			if err != nil {
+				t.Fatalf("GetIssue(%s): %v", issue.ID, err)
+			}
+			if got.Status != want {
+				t.Errorf("%s: %s (%s) is %s, want %s", when, issue.ID, issue.Title, got.Status, want)
+			}
+		}
+	}
+	commented := func(issue *types.Issue, want string) bool {
+		t.Helper()
+		history, err := s.GetEvents(ctx, issue.ID, 0)
+		if err != nil {
+			t.Fatalf("GetEvents(%s): %v", issue.ID, err)
+		}
+		for _, event := range history {
+			if event.EventType == types.EventCommented && event.Comment != nil && *event.Comment == want {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Chain: a blocks b blocks c. Closing a unblocks b only; c waits for b.
+	a := createIssue(t, s, "Chain root", types.TypeTask)
+	b, c := block("Chain middle"), block("Chain end")
+	addDependency(t, s, b.ID, a.ID, types.DepBlocks)
+	addDependency(t, s, c.ID, b.ID, types.DepBlocks)
+	closeIssue(a)
+	wantStatus("CloseIssue(chain root)", types.StatusOpen, b)
+	wantStatus("CloseIssue(chain root)", types.StatusBlocked, c)
+	if !commented(b, "Unblocked by closing "+a.ID) {
+		t.Errorf("CloseIssue: expected a comment on %s naming %s", b.ID, a.ID)
+	}
+	closeIssue(b)
+	wantStatus("CloseIssue(chain middle)", types.StatusOpen, c)
+
+	// Diamond: top blocks left and right, which both block bottom
+	top := createIssue(t, s, "Diamond top", types.TypeTask)
+	left, right, bottom := block("Diamond left"), block("Diamond right"), block("Diamond bottom")
+	addDependency(t, s, left.ID, top.ID, types.DepBlocks)
+	addDependency(t, s, right.ID, top.ID, types.DepBlocks)
+	addDependency(t, s, bottom.ID, left.ID, types.DepBlocks)
+	addDependency(t, s, bottom.ID, right.ID, types.DepBlocks)
+	closeIssue(top)
+	wantStatus("CloseIssue(diamond top)", types.StatusOpen, left, right)
+	wantStatus("CloseIssue(diamond top)", types.StatusBlocked, bottom)
+	closeIssue(left)
+	wantStatus("CloseIssue(diamond left)", types.StatusBlocked, bottom)
+	closeIssue(right)
+	wantStatus("CloseIssue(diamond right)", types.StatusOpen, bottom)
+
+	// Other dependency types and issues that aren't blocked are left alone
+	other := createIssue(t, s, "Other", types.TypeTask)
+	related, open := block("Related"), createIssue(t, s, "Open", types.TypeTask)
+	addDependency(t, s, related.ID, other.ID, types.DepRelated)
+	addDependency(t, s, open.ID, other.ID, types.DepBlocks)
+	closeIssue(other)
+	wantStatus("CloseIssue(related)", types.StatusBlocked, related)
+	wantStatus("CloseIssue(related)", types.StatusOpen, open)
+
+	// Issues held for review stay blocked: held for triage, auto-blocked, or
+	// with an open escalation. A closed escalation no longer holds.
+	holder := createIssue(t, s, "Holding blocker", types.TypeTask)
+	triaged, autoBlocked, escalated, resolved := block("Held for triage"), block("Auto-blocked"), block("Escalated"), block("Escalation resolved")
+	for _, held := range []*types.Issue{triaged, autoBlocked, escalated, resolved} {
+		addDependency(t, s, held.ID, holder.ID, types.DepBlocks)
+	}
+	addLabel := func(issue *types.Issue, label string) {
+		t.Helper()
+		if err := s.AddLabel(ctx, issue.ID, label, testActor); err != nil {
+			t.Fatalf("AddLabel(%s, %s): %v", issue.ID, label, err)
+		}
+	}
+	addLabel(triaged, types.NeedsTriageLabel)
+	addLabel(autoBlocked, types.AutoBlockedLabel)
+	escalation, closedEscalation := createIssue(t, s, "Escalation", types.TypeTask), createIssue(t, s, "Closed escalation", types.TypeTask)
+	addLabel(escalation, types.AffectedIssueLabel(escalated.ID))
+	addLabel(closedEscalation, types.AffectedIssueLabel(resolved.ID))
+	closeIssue(closedEscalation)
+	closeIssue(holder)
+	wantStatus("CloseIssue(held dependents)", types.StatusBlocked, triaged, autoBlocked, escalated)
+	wantStatus("CloseIssue(held dependents)", types.StatusOpen, resolved)
+	if commented(triaged, "Unblocked by closing "+holder.ID) {
+		t.Errorf("CloseIssue: expected no unblock comment on %s, which is held for triage", triaged.ID)
+	}
+
+	// label tags the dependent and leaves it blocked; UnblockIssue untags it
+	if err := s.SetConfig(ctx, "unblock_dependents", types.UnblockLabel); err != nil {
+		t.Fatalf("SetConfig: %v", err)
+	}
+	blocker, labeled := createIssue(t, s, "Labeling blocker", types.TypeTask), block("Labeled")
+	addDependency(t, s, labeled.ID, blocker.ID, types.DepBlocks)
+	closeIssue(blocker)
+	wantStatus("CloseIssue(label policy)", types.StatusBlocked, labeled)
+	labels, err := s.GetLabels(ctx, labeled.ID)
+	if err != nil {
+		t.Fatalf("GetLabels: %v", err)
+	}
+	if !slices.Contains(labels, types.ReadyToUnblockLabel) {
+		t.Errorf("CloseIssue(label policy): expected %s labeled %s, got %v", labeled.ID, types.ReadyToUnblockLabel, labels)
+	}
+	if !commented(labeled, "Ready to unblock: closing "+blocker.ID+" closed its last blocker") {
+		t.Errorf("CloseIssue(label policy): expected a comment on %s naming %s", labeled.ID, blocker.ID)
+	}
+	if err := s.UnblockIssue(ctx, labeled.ID, testActor); err != nil {
+		t.Fatalf("UnblockIssue: %v", err)
+	}
+	if labels, _ := s.GetLabels(ctx, labeled.ID); slices.Contains(labels, types.ReadyToUnblockLabel) {
+		t.Errorf("UnblockIssue: expected the %s label removed, got %v", types.ReadyToUnblockLabel, labels)
+	}
+
+	// off leaves it blocked
+	if err := s.SetConfig(ctx, "unblock_dependents", types.UnblockOff); err != nil {
+		t.Fatalf("SetConfig: %v", err)
+	}
+	blocker, left = createIssue(t, s, "Ignored blocker", types.TypeTask), block("Left blocked")
+	addDependency(t, s, left.ID, blocker.ID, types.DepBlocks)
+	closeIssue(blocker)
+	wantStatus("CloseIssue(off policy)", types.StatusBlocked, left)
+}
+
+func testSystemIssue(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	// A fresh store has it, closed and described as internal
+	system, err := s.GetIssue(ctx, types.SystemIssueID)
+	if err != nil || system == nil {
+		t.Fatalf("GetIssue(%s): expected the pseudo-issue, got %v (%v)", types.SystemIssueID, system, err)
+	}
+	if system.Status != types.StatusClosed || system.IssueType != types.TypeChore || !strings.Contains(system.Description, "Internal") {
+		t.Errorf("GetIssue(%s): got %+v", types.SystemIssueID, system)
+	}
+	if created, err := s.EnsureSystemIssue(ctx); err != nil || created {
+		t.Errorf("EnsureSystemIssue: expected nothing to create, got %t (%v)", created, err)
+	}
+
+	issue := createIssue(t, s, "Real work", types.TypeTask)
+	found, err := s.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		t.Fatalf("SearchIssues: %v", err)
+	}
+	if len(found) != 1 || found[0].ID != issue.ID {
+		t.Errorf("SearchIssues: expected only %s, got %v", issue.ID, ids(found))
+	}
+	found, err = s.SearchIssues(ctx, "", types.IssueFilter{IncludeSystem: true})
+	if err != nil {
+		t.Fatalf("SearchIssues: %v", err)
+	}
+	if len(found) != 2 {
+		t.Errorf("SearchIssues(IncludeSystem): expected the pseudo-issue too, got %v", ids(found))
+	}
+	if n, err := s.CountIssues(ctx, "", types.IssueFilter{}); err != nil || n != 1 {
+		t.Errorf("CountIssues: got %d (%v), want 1", n, err)
+	}
+
+	// Even reopened, it is never work and never counted
+	if err := s.OverrideIssueStatus(ctx, types.SystemIssueID, types.StatusOpen, "testing", testActor); err != nil {
+		t.Fatalf("OverrideIssueStatus: %v", err)
+	}
+	ready, err := s.GetReadyWork(ctx, types.WorkFilter{Limit: 1})
+	if err != nil {
+		t.Fatalf("GetReadyWork: %v", err)
+	}
+	if len(ready) != 1 || ready[0].ID != issue.ID {
+		t.Errorf("GetReadyWork: expected only %s, got %v", issue.ID, ids(ready))
+	}
+	stats, err := s.GetStatistics(ctx)
+	if err != nil {
+		t.Fatalf("GetStatistics: %v", err)
+	}
+	if stats.TotalIssues != 1 || stats.OpenIssues != 1 || stats.ReadyIssues != 1 {
+		t.Errorf("GetStatistics: got %+v, want only the real issue counted", stats)
+	}
+
+	// Event writes race ensuring it without errors or duplicates
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			_, err := s.EnsureSystemIssue(ctx)
+			errs <- err
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			errs <- s.StoreAgentEvent(ctx, &events.AgentEvent{
+				ID: fmt.Sprintf("system-%d", i), Type: events.EventTypeEventCleanupCompleted, Timestamp: time.Now(),
+				IssueID: types.SystemIssueID, Severity: events.SeverityInfo, Message: "cleanup",
+			})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("EnsureSystemIssue/StoreAgentEvent: %v", err)
+		}
+	}
+	stored, err := s.GetAgentEventsByIssue(ctx, types.SystemIssueID)
+	if err != nil {
+		t.Fatalf("GetAgentEventsByIssue: %v", err)
+	}
+	if len(stored) != 10 {
+		t.Errorf("GetAgentEventsByIssue(%s): got %d events, want 10", types.SystemIssueID, len(stored))
+	}
+	found, err = s.SearchIssues(ctx, types.SystemIssueID, types.IssueFilter{IncludeSystem: true})
+	if err != nil {
+		t.Fatalf("SearchIssues: %v", err)
+	}
+	if len(found) != 1 {
+		t.Errorf("SearchIssues(%s): expected one pseudo-issue, got %v", types.SystemIssueID, ids(found))
+	}
+}
+
+func testProjects(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	if err := s.CreateProject(ctx, &types.Project{Name: "web", Prefix: "web"}); err != nil {
+		t.Fatalf("CreateProject: %v", err)
+	}
+	if err := s.CreateProject(ctx, &types.Project{Name: "web", Prefix: "www"}); err == nil {
+		t.Error("CreateProject: expected an error for a duplicate name")
+	}
+	if err := s.CreateProject(ctx, &types.Project{Name: "site", Prefix: "web"}); err == nil {
+		t.Error("CreateProject: expected an error for a duplicate prefix")
+	}
+	if err := s.CreateProject(ctx, &types.Project{Name: types.DefaultProject, Prefix: "dflt"}); err == nil {
+		t.Error("CreateProject: expected an error for the default project")
+	}
+	projects, err := s.ListProjects(ctx)
+	if err != nil {
+		t.Fatalf("ListProjects: %v", err)
+	}
+	if len(projects) != 2 || projects[0].Name != types.DefaultProject || projects[1].Name != "web" || projects[1].Prefix != "web" {
+		t.Errorf("ListProjects: got %+v", projects)
+	}
+
+	core := createIssue(t, s, "Core task", types.TypeTask)
+	web := &types.Issue{Title: "Web task", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, Project: "web"}
+	if err := s.CreateIssue(ctx, web, testActor); err != nil {
+		t.Fatalf("CreateIssue(project=web): %v", err)
+	}
+	if !strings.HasPrefix(web.ID, "web-") {
+		t.Errorf("CreateIssue(project=web): got ID %s, want a web- prefix", web.ID)
+	}
+	unknown := &types.Issue{Title: "Lost", Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, Project: "nowhere"}
+	if err := s.CreateIssue(ctx, unknown, testActor); err == nil {
+		t.Error("CreateIssue: expected an error for an unknown project")
+	}
+
+	got, err := s.GetIssue(ctx, web.ID)
+	if err != nil || got == nil || got.Project != "web" {
+		t.Errorf("GetIssue: got %+v, %v; want project web", got, err)
+	}
+	got, err = s.GetIssue(ctx, core.ID)
+	if err != nil || got == nil || got.Project != types.DefaultProject {
+		t.Errorf("GetIssue: got %+v, %v; want project %s", got, err, types.DefaultProject)
+	}
+
+	found, err := s.SearchIssues(ctx, "", types.IssueFilter{Project: "web"})
+	if err != nil {
+		t.Fatalf("SearchIssues: %v", err)
+	}
+	if len(found) != 1 || found[0].ID != web.ID || found[0].Project != "web" {
+		t.Errorf("SearchIssues(project=web): got %v", ids(found))
+	}
+	count, err := s.CountIssues(ctx, "", types.IssueFilter{Project: types.DefaultProject})
+	if err != nil {
+		t.Fatalf("CountIssues: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("CountIssues(project=%s): got %d, want 1", types.DefaultProject, count)
+	}
+
+	ready, err := s.GetReadyWork(ctx, types.WorkFilter{Project: "web", Limit: 1})
+	if err != nil {
+		t.Fatalf("GetReadyWork: %v", err)
+	}
+	if len(ready) != 1 || ready[0].ID != web.ID {
+		t.Errorf("GetReadyWork(project=web): got %v", ids(ready))
+	}
+	ready, err = s.GetReadyWork(ctx, types.WorkFilter{})
+	if err != nil {
+		t.Fatalf("GetReadyWork: %v", err)
+	}
+	if len(ready) != 2 {
+		t.Errorf("GetReadyWork: got %v, want both projects", ids(ready))
+	}
+
+	// Dependencies may cross projects
+	if err := s.AddDependency(ctx, &types.Dependency{IssueID: core.ID, DependsOnID: web.ID, Type: types.DepBlocks}, testActor); err != nil {
+		t.Fatalf("AddDependency across projects: %v", err)
+	}
+	tree, err := s.GetDependencyTree(ctx, core.ID, 5)
+	if err != nil {
+		t.Fatalf("GetDependencyTree: %v", err)
+	}
+	if len(tree) != 2 || tree[0].Project != types.DefaultProject || tree[1].Project != "web" {
+		t.Errorf("GetDependencyTree: got %+v", tree)
+	}
+}
+
+func testMissions(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	mission := &types.Mission{
+		Issue: types.Issue{
+			Title:        "Mission",
+			Status:       types.StatusOpen,
+			Priority:     1,
+			IssueType:    types.TypeEpic,
+			IssueSubtype: types.SubtypeMission,
+		},
+		Goal:        "Ship it",
+		PhaseCount:  2,
+		SandboxPath: "/tmp/sandbox",
+		BranchName:  "mission/ship-it",
+	}
+	if err := s.CreateMission(ctx, mission, testActor); err != nil {
+		t.Fatalf("CreateMission: %v", err)
+	}
+
+	got, err := s.GetMission(ctx, mission.ID)
+	if err != nil {
+		t.Fatalf("GetMission: %v", err)
+	}
+	if got.Goal != "Ship it" || got.PhaseCount != 2 || got.BranchName != "mission/ship-it" {
+		t.Errorf("GetMission: got %+v", got)
+	}
+
+	if err := s.UpdateMission(ctx, mission.ID, map[string]interface{}{"current_phase": 1, "goal": "Ship it faster"}, testActor); err != nil {
+		t.Fatalf("UpdateMission: %v", err)
+	}
+	got, err = s.GetMission(ctx, mission.ID)
+	if err != nil {
+		t.Fatalf("GetMission: %v", err)
+	}
+	if got.CurrentPhase != 1 || got.Goal != "Ship it faster" {
+		t.Errorf("UpdateMission: got current_phase %d goal %q", got.CurrentPhase, got.Goal)
+	}
+
+	// Tasks find their mission through parent-child links, however deep
+	phase := createIssue(t, s, "Phase", types.TypeEpic)
+	addDependency(t, s, phase.ID, mission.ID, types.DepParentChild)
+	task := createIssue(t, s, "Task", types.TypeTask)
+	addDependency(t, s, task.ID, phase.ID, types.DepParentChild)
+
+	missionCtx, err := s.GetMissionForTask(ctx, task.ID)
+	if err != nil {
+		t.Fatalf("GetMissionForTask: %v", err)
+	}
+	if missionCtx.MissionID != mission.ID || missionCtx.SandboxPath != "/tmp/sandbox" {
+		t.Errorf("GetMissionForTask: got %+v, want mission %s", missionCtx, mission.ID)
+	}
+	orphan := createIssue(t, s, "Orphan", types.TypeTask)
+	if _, err := s.GetMissionForTask(ctx, orphan.ID); err == nil {
+		t.Error("GetMissionForTask: expected an error for a task outside any mission")
+	}
+
+	needing, err := s.GetMissionsNeedingGates(ctx)
+	if err != nil {
+		t.Fatalf("GetMissionsNeedingGates: %v", err)
+	}
+	if len(needing) != 0 {
+		t.Errorf("GetMissionsNeedingGates: got %v before labeling", ids(needing))
+	}
+	if err := s.AddLabel(ctx, mission.ID, "needs-quality-gates", testActor); err != nil {
+		t.Fatalf("AddLabel: %v", err)
+	}
+	needing, err = s.GetMissionsNeedingGates(ctx)
+	if err != nil {
+		t.Fatalf("GetMissionsNeedingGates: %v", err)
+	}
+	if len(needing) != 1 || needing[0].ID != mission.ID {
+		t.Errorf("GetMissionsNeedingGates: got %v, want [%s]", ids(needing), mission.ID)
+	}
+	if err := s.AddLabel(ctx, mission.ID, "gates-running", testActor); err != nil {
+		t.Fatalf("AddLabel: %v", err)
+	}
+	needing, err = s.GetMissionsNeedingGates(ctx)
+	if err != nil {
+		t.Fatalf("GetMissionsNeedingGates: %v", err)
+	}
+	if len(needing) != 0 {
+		t.Errorf("GetMissionsNeedingGates: missions already running gates must be skipped, got %v", ids(needing))
+	}
+}
+
+func testMissionState(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	newMission := func(title string, priority int) *types.Mission {
+		t.Helper()
+		mission := &types.Mission{
+			Issue: types.Issue{
+				Title:        title,
+				Status:       types.StatusOpen,
+				Priority:     priority,
+				IssueType:    types.TypeEpic,
+				IssueSubtype: types.SubtypeMission,
+			},
+			Goal:       "Goal of " + title,
+			PhaseCount: 2,
+		}
+		if err := s.CreateMission(ctx, mission, testActor); err != nil {
+			t.Fatalf("CreateMission: %v", err)
+		}
+		return mission
+	}
+	mission := newMission("Mission", 2)
+
+	if err := s.UpdateMissionState(ctx, mission.ID, map[string]interface{}{
+		"sandbox_path":    "/tmp/sandbox",
+		"branch_name":     "mission/branch",
+		"iteration_count": 3,
+	}); err != nil {
+		t.Fatalf("UpdateMissionState: %v", err)
+	}
+	got, err := s.GetMission(ctx, mission.ID)
+	if err != nil {
+		t.Fatalf("GetMission: %v", err)
+	}
+	if got.SandboxPath != "/tmp/sandbox" || got.BranchName != "mission/branch" || got.IterationCount != 3 {
+		t.Errorf("UpdateMissionState: got sandbox %q branch %q iterations %d", got.SandboxPath, got.BranchName, got.IterationCount)
+	}
+	if err := s.UpdateMissionState(ctx, mission.ID, map[string]interface{}{"title": "Renamed"}); err == nil {
+		t.Error("UpdateMissionState: expected an error for a base issue field")
+	}
+	plain := createIssue(t, s, "Plain", types.TypeTask)
+	if err := s.UpdateMissionState(ctx, plain.ID, map[string]interface{}{"sandbox_path": "/tmp/x"}); err == nil {
+		t.Error("UpdateMissionState: expected an error for an issue without mission state")
+	}
+
+	// Gates status: pending leaves last_gates_run alone, the others stamp it
+	if err := s.SetMissionGatesStatus(ctx, mission.ID, types.GatesStatusPending); err != nil {
+		t.Fatalf("SetMissionGatesStatus: %v", err)
+	}
+	got, _ = s.GetMission(ctx, mission.ID)
+	if got.GatesStatus != types.GatesStatusPending || got.LastGatesRun != nil {
+		t.Errorf("SetMissionGatesStatus(pending): got status %q last run %v", got.GatesStatus, got.LastGatesRun)
+	}
+	if err := s.SetMissionGatesStatus(ctx, mission.ID, types.GatesStatusPassed); err != nil {
+		t.Fatalf("SetMissionGatesStatus: %v", err)
+	}
+	got, _ = s.GetMission(ctx, mission.ID)
+	if got.GatesStatus != types.GatesStatusPassed || got.LastGatesRun == nil {
+		t.Errorf("SetMissionGatesStatus(passed): got status %q last run %v", got.GatesStatus, got.LastGatesRun)
+	}
+	if err := s.SetMissionGatesStatus(ctx, mission.ID, "green"); err == nil {
+		t.Error("SetMissionGatesStatus: expected an error for an invalid status")
+	}
+
+	// Phases advance up to phase_count
+	for want := 1; want <= 2; want++ {
+		phase, err := s.AdvanceMissionPhase(ctx, mission.ID)
+		if err != nil {
+			t.Fatalf("AdvanceMissionPhase: %v", err)
+		}
+		if phase != want {
+			t.Errorf("AdvanceMissionPhase: got phase %d, want %d", phase, want)
+		}
+	}
+	if _, err := s.AdvanceMissionPhase(ctx, mission.ID); err == nil {
+		t.Error("AdvanceMissionPhase: expected an error past the last phase")
+	}
+	if _, err := s.AdvanceMissionPhase(ctx, plain.ID); err == nil {
+		t.Error("AdvanceMissionPhase: expected an error for an issue without mission state")
+	}
+
+	urgent := newMission("Urgent", 0)
+	done := newMission("Done", 1)
+	if err := s.CloseIssue(ctx, done.ID, "shipped", testActor); err != nil {
+		t.Fatalf("CloseIssue: %v", err)
+	}
+	archived := newMission("Archived", 1)
+	if err := s.ArchiveIssue(ctx, archived.ID, testActor); err != nil {
+		t.Fatalf("ArchiveIssue: %v", err)
+	}
+	open, err := s.GetMissionsByStatus(ctx, types.StatusOpen)
+	if err != nil {
+		t.Fatalf("GetMissionsByStatus: %v", err)
+	}
+	if len(open) != 2 || open[0].ID != urgent.ID || open[1].ID != mission.ID {
+		t.Errorf("GetMissionsByStatus(open): got %d missions, want [%s %s]", len(open), urgent.ID, mission.ID)
+	} else if open[1].SandboxPath != "/tmp/sandbox" || open[1].CurrentPhase != 2 {
+		t.Errorf("GetMissionsByStatus: missions must carry their state, got %+v", open[1])
+	}
+	closed, err := s.GetMissionsByStatus(ctx, types.StatusClosed)
+	if err != nil {
+		t.Fatalf("GetMissionsByStatus: %v", err)
+	}
+	if len(closed) != 1 || closed[0].ID != done.ID {
+		t.Errorf("GetMissionsByStatus(closed): got %d missions, want [%s]", len(closed), done.ID)
+	}
+}
+
+func testMissionApproval(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	registerInstance(t, s, "exec-1")
+	mission := &types.Mission{
+		Issue: types.Issue{
+			Title:        "Mission",
+			Status:       types.StatusOpen,
+			Priority:     1,
+			IssueType:    types.TypeEpic,
+			IssueSubtype: types.SubtypeMission,
+		},
+		Goal:             "Ship it",
+		ApprovalRequired: true,
+	}
+	if err := s.CreateMission(ctx, mission, testActor); err != nil {
+		t.Fatalf("CreateMission: %v", err)
+	}
+	phase := createIssue(t, s, "Phase", types.TypeEpic)
+	addDependency(t, s, phase.ID, mission.ID, types.DepParentChild)
+	task := createIssue(t, s, "Task", types.TypeTask)
+	addDependency(t, s, task.ID, phase.ID, types.DepParentChild)
+	loose := createIssue(t, s, "Loose", types.TypeTask)
+
+	readyIDs := func() []string {
+		t.Helper()
+		ready, err := s.GetReadyWork(ctx, types.WorkFilter{})
+		if err != nil {
+			t.Fatalf("GetReadyWork: %v", err)
+		}
+		return ids(ready)
+	}
+
+	// Until approval, the mission's work is held back
+	if got := readyIDs(); len(got) != 1 || got[0] != loose.ID {
+		t.Errorf("GetReadyWork: got %v, want only [%s] before approval", got, loose.ID)
+	}
+	// even when it comes first and only one issue is asked for
+	if err := s.UpdateIssue(ctx, task.ID, map[string]interface{}{"priority": 0}, testActor); err != nil {
+		t.Fatalf("UpdateIssue: %v", err)
+	}
+	first, err := s.GetReadyWork(ctx, types.WorkFilter{Limit: 1})
+	if err != nil {
+		t.Fatalf("GetReadyWork: %v", err)
+	}
+	if got := ids(first); len(got) != 1 || got[0] != loose.ID {
+		t.Errorf("GetReadyWork(Limit 1): got %v, want [%s] past the held task", got, loose.ID)
+	}
+	for _, id := range []string{task.ID, mission.ID} {
+		err := s.ClaimIssue(ctx, id, "exec-1")
+		var approvalErr *types.AwaitingApprovalError
+		if !errors.As(err, &approvalErr) || !errors.Is(err, types.ErrAwaitingApproval) {
+			t.Fatalf("ClaimIssue(%s): got %v, want an AwaitingApprovalError", id, err)
+		}
+		if approvalErr.MissionID != mission.ID || approvalErr.IssueID != id {
+			t.Errorf("ClaimIssue(%s): got %+v", id, approvalErr)
+		}
+	}
+
+	// Rejection needs a comment and blocks the mission
+	if err := s.RejectMission(ctx, mission.ID, "reviewer", " "); err == nil {
+		t.Error("RejectMission: expected an error without a comment")
+	}
+	if err := s.RejectMission(ctx, mission.ID, "reviewer", "Phase 2 is too big"); err != nil {
+		t.Fatalf("RejectMission: %v", err)
+	}
+	got, err := s.GetMission(ctx, mission.ID)
+	if err != nil {
+		t.Fatalf("GetMission: %v", err)
+	}
+	if got.Status != types.StatusBlocked || got.ApprovedAt != nil {
+		t.Errorf("RejectMission: got status %s approved at %v, want blocked and unapproved", got.Status, got.ApprovedAt)
+	}
+
+	// Approval reopens it and releases its work
+	if err := s.ApproveMission(ctx, mission.ID, "reviewer", "Split looks good"); err != nil {
+		t.Fatalf("ApproveMission: %v", err)
+	}
+	got, err = s.GetMission(ctx, mission.ID)
+	if err != nil {
+		t.Fatalf("GetMission: %v", err)
+	}
+	if got.Status != types.StatusOpen || got.ApprovedAt == nil || got.ApprovedBy != "reviewer" {
+		t.Errorf("ApproveMission: got status %s approved at %v by %q", got.Status, got.ApprovedAt, got.ApprovedBy)
+	}
+	if err := s.ApproveMission(ctx, mission.ID, "reviewer", ""); err == nil {
+		t.Error("ApproveMission: expected an error for an approved mission")
+	}
+	if got := readyIDs(); len(got) != 2 {
+		t.Errorf("GetReadyWork: got %v after approval, want the task and %s", got, loose.ID)
+	}
+	if err := s.ClaimIssue(ctx, task.ID, "exec-1"); err != nil {
+		t.Errorf("ClaimIssue: %v after approval", err)
+	}
+
+	// Both reviews left a comment and an agent event
+	comments := 0
+	history, err := s.GetEvents(ctx, mission.ID, 0)
+	if err != nil {
+		t.Fatalf("GetEvents: %v", err)
+	}
+	for _, event := range history {
+		if event.EventType == types.EventCommented && event.Comment != nil && strings.HasPrefix(*event.Comment, "**Plan ") {
+			comments++
+		}
+	}
+	if comments != 2 {
+		t.Errorf("Expected 2 review comments, got %d", comments)
+	}
+	agentEvents, err := s.GetAgentEventsByIssue(ctx, mission.ID)
+	if err != nil {
+		t.Fatalf("GetAgentEventsByIssue: %v", err)
+	}
+	var reviews []events.EventType
+	for _, event := range agentEvents {
+		if event.Type == events.EventTypeMissionApproved || event.Type == events.EventTypeMissionRejected {
+			reviews = append(reviews, event.Type)
+		}
+	}
+	if len(reviews) != 2 || reviews[0] != events.EventTypeMissionRejected || reviews[1] != events.EventTypeMissionApproved {
+		t.Errorf("Expected rejected then approved events, got %v", reviews)
+	}
+
+	// Only missions have plans to review
+	if err := s.ApproveMission(ctx, phase.ID, "reviewer", ""); err == nil {
+		t.Error("ApproveMission: expected an error for a non-mission")
+	}
+}
+
+func testMissionPhases(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	mission := &types.Mission{
+		Issue: types.Issue{
+			Title:        "Mission",
+			Status:       types.StatusOpen,
+			Priority:     1,
+			IssueType:    types.TypeEpic,
+			IssueSubtype: types.SubtypeMission,
+		},
+		Goal: "Ship it",
+	}
+	if err := s.CreateMission(ctx, mission, testActor); err != nil {
+		t.Fatalf("CreateMission: %v", err)
+	}
+	createPhase := func(title string) *types.Issue {
+		t.Helper()
+		phase := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 1, IssueType: types.TypeEpic, IssueSubtype: types.SubtypePhase}
+		if err := s.CreateIssue(ctx, phase, testActor); err != nil {
+			t.Fatalf("CreateIssue(%q): %v", title, err)
+		}
+		addDependency(t, s, phase.ID, mission.ID, types.DepParentChild)
+		return phase
+	}
+	design := createPhase("Design")
+	build := createPhase("Build")
+	designTask := createIssue(t, s, "Write design", types.TypeTask)
+	addDependency(t, s, designTask.ID, design.ID, types.DepParentChild)
+	buildTask := createIssue(t, s, "Build it", types.TypeTask)
+	addDependency(t, s, buildTask.ID, build.ID, types.DepParentChild)
+	// Created last, but Build depends on it
+	prototype := createPhase("Prototype")
+	addDependency(t, s, build.ID, prototype.ID, types.DepBlocks)
+	// Not phases: a plain child epic and an archived phase
+	createIssue(t, s, "Side work", types.TypeEpic)
+	archived := createPhase("Dropped")
+	if err := s.ArchiveIssue(ctx, archived.ID, testActor); err != nil {
+		t.Fatalf("ArchiveIssue: %v", err)
+	}
+
+	phases, err := s.GetMissionPhases(ctx, mission.ID)
+	if err != nil {
+		t.Fatalf("GetMissionPhases: %v", err)
+	}
+	want := []string{design.ID, prototype.ID, build.ID}
+	if got := ids(phases); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("GetMissionPhases: got %v, want %v", got, want)
+	}
+	if len(phases) > 0 && phases[0].IssueSubtype != types.SubtypePhase {
+		t.Errorf("GetMissionPhases: got subtype %q, want phase", phases[0].IssueSubtype)
+	}
+
+	phaseOf := func(taskID string) *types.MissionContext {
+		t.Helper()
+		missionCtx, err := s.GetMissionForTask(ctx, taskID)
+		if err != nil {
+			t.Fatalf("GetMissionForTask(%s): %v", taskID, err)
+		}
+		return missionCtx
+	}
+	readyIDs := func() []string {
+		t.Helper()
+		ready, err := s.GetReadyWork(ctx, types.WorkFilter{})
+		if err != nil {
+			t.Fatalf("GetReadyWork: %v", err)
+		}
+		return ids(ready)
+	}
+
+	// Only the first phase runs
+	if got := phaseOf(designTask.ID); got.MissionID != mission.ID || got.PhaseID != design.ID || got.PhaseWaiting {
+		t.Errorf("GetMissionForTask(%s): got %+v, want phase %s running", designTask.ID, got, design.ID)
+	}
+	if got := phaseOf(buildTask.ID); got.PhaseID != build.ID || !got.PhaseWaiting {
+		t.Errorf("GetMissionForTask(%s): got %+v, want phase %s waiting", buildTask.ID, got, build.ID)
+	}
+	if got := readyIDs(); len(got) != 1 || got[0] != designTask.ID {
+		t.Errorf("GetReadyWork: got %v, want only [%s]", got, designTask.ID)
+	}
+	// A waiting task that comes first doesn't hide the running phase's work
+	prototypeTask := &types.Issue{Title: "Try it", Status: types.StatusOpen, Priority: 0, IssueType: types.TypeTask}
+	if err := s.CreateIssue(ctx, prototypeTask, testActor); err != nil {
+		t.Fatalf("CreateIssue: %v", err)
+	}
+	addDependency(t, s, prototypeTask.ID, prototype.ID, types.DepParentChild)
+	first, err := s.GetReadyWork(ctx, types.WorkFilter{Limit: 1})
+	if err != nil {
+		t.Fatalf("GetReadyWork: %v", err)
+	}
+	if got := ids(first); len(got) != 1 || got[0] != designTask.ID {
+		t.Errorf("GetReadyWork(Limit 1): got %v, want [%s] past the waiting task", got, designTask.ID)
+	}
+
+	// Closing phases moves execution along
+	for _, id := range []string{designTask.ID, design.ID, prototypeTask.ID, prototype.ID} {
+		if err := s.CloseIssue(ctx, id, "done", testActor); err != nil {
+			t.Fatalf("CloseIssue(%s): %v", id, err)
+		}
+	}
+	if got := phaseOf(buildTask.ID); got.PhaseWaiting {
+		t.Errorf("GetMissionForTask(%s): got %+v, want its phase running", buildTask.ID, got)
+	}
+	if got := readyIDs(); len(got) != 1 || got[0] != buildTask.ID {
+		t.Errorf("GetReadyWork: got %v, want only [%s]", got, buildTask.ID)
+	}
+
+	// A blocked mission runs no phase
+	if err := s.UpdateIssue(ctx, mission.ID, map[string]interface{}{"status": string(types.StatusBlocked)}, testActor); err != nil {
+		t.Fatalf("UpdateIssue: %v", err)
+	}
+	if got := phaseOf(buildTask.ID); !got.PhaseWaiting {
+		t.Errorf("GetMissionForTask(%s): got %+v, want it waiting on the blocked mission", buildTask.ID, got)
+	}
+	if got := readyIDs(); len(got) != 0 {
+		t.Errorf("GetReadyWork: got %v for a blocked mission, want none", got)
+	}
+	if err := s.UpdateIssue(ctx, mission.ID, map[string]interface{}{"status": string(types.StatusOpen)}, testActor); err != nil {
+		t.Fatalf("UpdateIssue: %v", err)
+	}
+	if got := readyIDs(); len(got) != 1 || got[0] != buildTask.ID {
+		t.Errorf("GetReadyWork: got %v after reopening, want only [%s]", got, buildTask.ID)
+	}
+}
+
+func testDependencies(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	a := createIssue(t, s, "A", types.TypeTask)
+	b := createIssue(t, s, "B", types.TypeTask)
+	c := createIssue(t, s, "C", types.TypeTask)
+	addDependency(t, s, a.ID, b.ID, types.DepBlocks)
+	addDependency(t, s, b.ID, c.ID, types.DepRelated)
+
+	deps, err := s.GetDependencies(ctx, a.ID)
+	if err != nil {
+		t.Fatalf("GetDependencies: %v", err)
+	}
+	if len(deps) != 1 || deps[0].ID != b.ID {
+		t.Errorf("GetDependencies: got %v, want [%s]", ids(deps), b.ID)
+	}
+	dependents, err := s.GetDependents(ctx, b.ID)
+	if err != nil {
+		t.Fatalf("GetDependents: %v", err)
+	}
+	if len(dependents) != 1 || dependents[0].ID != a.ID {
+		t.Errorf("GetDependents: got %v, want [%s]", ids(dependents), a.ID)
+	}
+	records, err := s.GetDependencyRecords(ctx, b.ID)
+	if err != nil {
+		t.Fatalf("GetDependencyRecords: %v", err)
+	}
+	if len(records) != 1 || records[0].DependsOnID != c.ID || records[0].Type != types.DepRelated {
+		t.Errorf("GetDependencyRecords: got %+v", records)
+	}
+
+	tree, err := s.GetDependencyTree(ctx, a.ID, 10)
+	if err != nil {
+		t.Fatalf("GetDependencyTree: %v", err)
+	}
+	depths := make(map[string]int)
+	for _, node := range tree {
+		depths[node.ID] = node.Depth
+	}
+	if depths[a.ID] != 0 || depths[b.ID] != 1 || depths[c.ID] != 2 || len(tree) != 3 {
+		t.Errorf("GetDependencyTree: got depths %v, want %s:0 %s:1 %s:2", depths, a.ID, b.ID, c.ID)
+	}
+
+	cycles, err := s.DetectCycles(ctx)
+	if err != nil {
+		t.Fatalf("DetectCycles: %v", err)
+	}
+	if len(cycles) != 0 {
+		t.Errorf("DetectCycles: got %d cycles in an acyclic graph", len(cycles))
+	}
+
+	if err := s.RemoveDependency(ctx, a.ID, b.ID, testActor); err != nil {
+		t.Fatalf("RemoveDependency: %v", err)
+	}
+	deps, err = s.GetDependencies(ctx, a.ID)
+	if err != nil {
+		t.Fatalf("GetDependencies: %v", err)
+	}
+	if len(deps) != 0 {
+		t.Errorf("RemoveDependency: still depends on %v", ids(deps))
+	}
+}
+
+func testLabels(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	issue := createIssue(t, s, "Labeled", types.TypeTask)
+	other := createIssue(t, s, "Other", types.TypeTask)
+
+	for _, label := range []string{"backend", "urgent"} {
+		if err := s.AddLabel(ctx, issue.ID, label, testActor); err != nil {
+			t.Fatalf("AddLabel(%s): %v", label, err)
+		}
+	}
+	if err := s.AddLabel(ctx, other.ID, "backend", testActor); err != nil {
+		t.Fatalf("AddLabel: %v", err)
+	}
+
+	labels, err := s.GetLabels(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetLabels: %v", err)
+	}
+	if len(labels) != 2 {
+		t.Errorf("GetLabels: got %v, want backend and urgent", labels)
+	}
+	tagged, err := s.GetIssuesByLabel(ctx, "backend")
+	if err != nil {
+		t.Fatalf("GetIssuesByLabel: %v", err)
+	}
+	if len(tagged) != 2 || !containsID(tagged, issue.ID) || !containsID(tagged, other.ID) {
+		t.Errorf("GetIssuesByLabel: got %v", ids(tagged))
+	}
+
+	if err := s.RemoveLabel(ctx, issue.ID, "urgent", testActor); err != nil {
+		t.Fatalf("RemoveLabel: %v", err)
+	}
+	labels, err = s.GetLabels(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetLabels: %v", err)
+	}
+	if len(labels) != 1 || labels[0] != "backend" {
+		t.Errorf("RemoveLabel: got labels %v, want [backend]", labels)
+	}
+}
+
+func testReadyWork(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	blocker := createIssue(t, s, "Blocker", types.TypeTask)
+	blocked := createIssue(t, s, "Blocked", types.TypeTask)
+	related := createIssue(t, s, "Related", types.TypeTask)
+	addDependency(t, s, blocked.ID, blocker.ID, types.DepBlocks)
+	addDependency(t, s, related.ID, blocker.ID, types.DepRelated)
+
+	ready, err := s.GetReadyWork(ctx, types.WorkFilter{Status: types.StatusOpen})
+	if err != nil {
+		t.Fatalf("GetReadyWork: %v", err)
+	}
+	if !containsID(ready, blocker.ID) || !containsID(ready, related.ID) || containsID(ready, blocked.ID) {
+		t.Errorf("GetReadyWork: got %v, want %s and %s but not %s", ids(ready), blocker.ID, related.ID, blocked.ID)
+	}
+
+	blockedIssues, err := s.GetBlockedIssues(ctx)
+	if err != nil {
+		t.Fatalf("GetBlockedIssues: %v", err)
+	}
+	if len(blockedIssues) != 1 || blockedIssues[0].ID != blocked.ID || blockedIssues[0].BlockedByCount != 1 {
+		t.Errorf("GetBlockedIssues: got %+v, want %s blocked by 1", blockedIssues, blocked.ID)
+	}
+
+	// Discovered blockers without open blockers of their own are ready blockers
+	if err := s.AddLabel(ctx, blocker.ID, "discovered:blocker", testActor); err != nil {
+		t.Fatalf("AddLabel: %v", err)
+	}
+	if err := s.AddLabel(ctx, blocked.ID, "discovered:blocker", testActor); err != nil {
+		t.Fatalf("AddLabel: %v", err)
+	}
+	blockers, err := s.GetReadyBlockers(ctx, types.WorkFilter{Limit: 10})
+	if err != nil {
+		t.Fatalf("GetReadyBlockers: %v", err)
+	}
+	if len(blockers) != 1 || blockers[0].ID != blocker.ID {
+		t.Errorf("GetReadyBlockers: got %v, want [%s]", ids(blockers), blocker.ID)
+	}
+
+	if err := s.CloseIssue(ctx, blocker.ID, "done", testActor); err != nil {
+		t.Fatalf("CloseIssue: %v", err)
+	}
+	ready, err = s.GetReadyWork(ctx, types.WorkFilter{Status: types.StatusOpen})
+	if err != nil {
+		t.Fatalf("GetReadyWork: %v", err)
+	}
+	if !containsID(ready, blocked.ID) {
+		t.Errorf("GetReadyWork: expected %s ready once its blocker closed, got %v", blocked.ID, ids(ready))
+	}
+}
+
+func testReadyWorkOrder(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	create := func(title string, priority int, estimate *int) *types.Issue {
+		t.Helper()
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: priority,
+			IssueType: types.TypeTask, EstimatedMinutes: estimate}
+		if err := s.CreateIssue(ctx, issue, testActor); err != nil {
+			t.Fatalf("CreateIssue(%q): %v", title, err)
+		}
+		return issue
+	}
+	minutes := func(n int) *int { return &n }
+	// Created in this order; issues created within the same timestamp
+	// tick are ordered by ID, which follows creation order too
+	a := create("P2, 60 minutes", 2, minutes(60))
+	b := create("P1, no estimate", 1, nil)
+	c := create("P2, 15 minutes", 2, minutes(15))
+	d := create("P1, 30 minutes", 1, minutes(30))
+
+	tests := []struct {
+		name   string
+		filter types.WorkFilter
+		want   []*types.Issue
+	}{
+		{"default", types.WorkFilter{}, []*types.Issue{b, d, a, c}},
+		{"priority", types.WorkFilter{OrderBy: types.WorkOrderPriority}, []*types.Issue{b, d, a, c}},
+		{"oldest policy", types.WorkFilter{SortPolicy: types.SortPolicyOldest}, []*types.Issue{a, b, c, d}},
+		{"age", types.WorkFilter{OrderBy: types.WorkOrderAge, SortPolicy: types.SortPolicyPriority}, []*types.Issue{a, b, c, d}},
+		{"estimate", types.WorkFilter{OrderBy: types.WorkOrderEstimate}, []*types.Issue{c, d, a, b}},
+		{"estimate with limit", types.WorkFilter{OrderBy: types.WorkOrderEstimate, Limit: 2}, []*types.Issue{c, d}},
+	}
+	for _, tt := range tests {
+		ready, err := s.GetReadyWork(ctx, tt.filter)
+		if err != nil {
+			t.Fatalf("GetReadyWork(%s): %v", tt.name, err)
+		}
+		if got, want := ids(ready), ids(tt.want); !slices.Equal(got, want) {
+			t.Errorf("GetReadyWork(%s): got %v, want %v", tt.name, got, want)
+		}
+	}
+
+	if _, err := s.GetReadyWork(ctx, types.WorkFilter{OrderBy: "random"}); err == nil {
+		t.Error("GetReadyWork: expected an error for an invalid order")
+	}
+}
+
+func testEpicCompletion(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	epic := createIssue(t, s, "Epic", types.TypeEpic)
+	child := createIssue(t, s, "Child", types.TypeTask)
+	addDependency(t, s, child.ID, epic.ID, types.DepParentChild)
+
+	complete, err := s.IsEpicComplete(ctx, epic.ID)
+	if err != nil {
+		t.Fatalf("IsEpicComplete: %v", err)
+	}
+	if complete {
+		t.Error("IsEpicComplete: expected false with an open child")
+	}
+
+	if err := s.CloseIssue(ctx, child.ID, "done", testActor); err != nil {
+		t.Fatalf("CloseIssue: %v", err)
+	}
+	complete, err = s.IsEpicComplete(ctx, epic.ID)
+	if err != nil {
+		t.Fatalf("IsEpicComplete: %v", err)
+	}
+	if !complete {
+		t.Error("IsEpicComplete: expected true once every child is closed")
+	}
+}
+
+func testComments(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	issue := createIssue(t, s, "Commented", types.TypeTask)
+
+	if err := s.AddComment(ctx, issue.ID, "reviewer", "looks good"); err != nil {
+		t.Fatalf("AddComment: %v", err)
+	}
+	history, err := s.GetEvents(ctx, issue.ID, 0)
+	if err != nil {
+		t.Fatalf("GetEvents: %v", err)
+	}
+	var created, commented bool
+	for _, event := range history {
+		switch event.EventType {
+		case types.EventCreated:
+			created = true
+		case types.EventCommented:
+			commented = event.Actor == "reviewer" && event.Comment != nil && *event.Comment == "looks good"
+		}
+	}
+	if !created || !commented {
+		t.Errorf("GetEvents: expected created and commented events, got %+v", history)
+	}
+
+	limited, err := s.GetEvents(ctx, issue.ID, 1)
+	if err != nil {
+		t.Fatalf("GetEvents: %v", err)
+	}
+	if len(limited) != 1 {
+		t.Errorf("GetEvents(limit 1): got %d events", len(limited))
+	}
+}
+
+func testStatistics(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	open := createIssue(t, s, "Open", types.TypeTask)
+	closed := createIssue(t, s, "Closed", types.TypeTask)
+	blocked := createIssue(t, s, "Blocked", types.TypeTask)
+	addDependency(t, s, blocked.ID, open.ID, types.DepBlocks)
+	if err := s.CloseIssue(ctx, closed.ID, "done", testActor); err != nil {
+		t.Fatalf("CloseIssue: %v", err)
+	}
+
+	stats, err := s.GetStatistics(ctx)
+	if err != nil {
+		t.Fatalf("GetStatistics: %v", err)
+	}
+	if stats.TotalIssues != 3 || stats.ClosedIssues != 1 || stats.OpenIssues != 2 ||
+		stats.BlockedIssues != 1 || stats.ReadyIssues != 1 {
+		t.Errorf("GetStatistics: got %+v, want 3 total, 2 open, 1 closed, 1 blocked, 1 ready", stats)
+	}
+
+	actors, err := s.GetActorStatistics(ctx, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("GetActorStatistics: %v", err)
+	}
+	var found *types.ActorStatistics
+	for _, actor := range actors {
+		if actor.Actor == testActor {
+			found = actor
+		}
+	}
+	if found == nil || found.IssuesCreated != 3 || found.IssuesClosed != 1 {
+		t.Errorf("GetActorStatistics: got %+v for %s, want 3 created and 1 closed", found, testActor)
+	}
+
+	if stats.AutoBlockedIssues != 0 {
+		t.Errorf("GetStatistics: got %d auto-blocked issues, want 0", stats.AutoBlockedIssues)
+	}
+
+	// Only issues still blocked with the auto-blocked label count
+	for i, title := range []string{"Auto-blocked", "Blocked by hand", "Unblocked"} {
+		issue := createIssue(t, s, title, types.TypeTask)
+		if err := s.UpdateIssue(ctx, issue.ID, map[string]interface{}{"status": string(types.StatusBlocked)}, testActor); err != nil {
+			t.Fatalf("UpdateIssue: %v", err)
+		}
+		if i == 1 {
+			continue
+		}
+		if err := s.AddLabel(ctx, issue.ID, types.AutoBlockedLabel, testActor); err != nil {
+			t.Fatalf("AddLabel: %v", err)
+		}
+		if i == 2 {
+			if err := s.UpdateIssue(ctx, issue.ID, map[string]interface{}{"status": string(types.StatusOpen)}, testActor); err != nil {
+				t.Fatalf("UpdateIssue: %v", err)
+			}
+		}
+	}
+	if stats, err = s.GetStatistics(ctx); err != nil {
+		t.Fatalf("GetStatistics: %v", err)
+	}
+	if stats.AutoBlockedIssues != 1 {
+		t.Errorf("GetStatistics: got %d auto-blocked issues, want 1", stats.AutoBlockedIssues)
+	}
+}
+
+func testExecutorInstances(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	running := registerInstance(t, s, "instance-running")
+	stale := registerInstance(t, s, "instance-stale")
+	stale.LastHeartbeat = time.Now().Add(-time.Hour)
+	stale.Metadata = `{"offline":true}`
+	if err := s.RegisterInstance(ctx, stale); err != nil {
+		t.Fatalf("RegisterInstance (re-register): %v", err)
+	}
+
+	active, err := s.GetActiveInstances(ctx)
+	if err != nil {
+		t.Fatalf("GetActiveInstances: %v", err)
+	}
+	if len(active) != 2 {
+		t.Errorf("GetActiveInstances: got %d instances, want 2", len(active))
+	}
+	for _, instance := range active {
+		if instance.InstanceID == stale.InstanceID && !instance.ParseMetadata().Offline {
+			t.Errorf("GetActiveInstances: got metadata %q, want the re-registered offline metadata", instance.Metadata)
+		}
+	}
+
+	if err := s.UpdateHeartbeat(ctx, running.InstanceID); err != nil {
+		t.Fatalf("UpdateHeartbeat: %v", err)
+	}
+	cleaned, err := s.CleanupStaleInstances(ctx, 300)
+	if err != nil {
+		t.Fatalf("CleanupStaleInstances: %v", err)
+	}
+	if cleaned != 1 {
+		t.Errorf("CleanupStaleInstances: cleaned %d instances, want 1", cleaned)
+	}
+
+	if err := s.MarkInstanceStopped(ctx, running.InstanceID); err != nil {
+		t.Fatalf("MarkInstanceStopped: %v", err)
+	}
+	active, err = s.GetActiveInstances(ctx)
+	if err != nil {
+		t.Fatalf("GetActiveInstances: %v", err)
+	}
+	if len(active) != 0 {
+		t.Errorf("GetActiveInstances: expected none after stop and cleanup, got %d", len(active))
+	}
+
+	// Both are stopped or crashed now; keep one of them
+	time.Sleep(10 * time.Millisecond)
+	deleted, err := s.DeleteOldStoppedInstances(ctx, 0, 1)
+	if err != nil {
+		t.Fatalf("DeleteOldStoppedInstances: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("DeleteOldStoppedInstances: deleted %d instances, want 1", deleted)
+	}
+}
+
+func testExecutionState(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	instance := registerInstance(t, s, "instance-exec")
+	issue := createIssue(t, s, "Claimable", types.TypeTask)
+
+	if state, err := s.GetExecutionState(ctx, issue.ID); err != nil || state != nil {
+		t.Errorf("GetExecutionState: expected (nil, nil) before a claim, got (%+v, %v)", state, err)
+	}
+
+	if err := s.ClaimIssue(ctx, issue.ID, instance.InstanceID); err != nil {
+		t.Fatalf("ClaimIssue: %v", err)
+	}
+	err := s.ClaimIssue(ctx, issue.ID, "someone-else")
+	var conflict *types.ClaimConflictError
+	if !errors.Is(err, types.ErrAlreadyClaimed) || !errors.As(err, &conflict) || conflict.ClaimedBy != instance.InstanceID {
+		t.Errorf("ClaimIssue: expected ErrAlreadyClaimed naming %s for an already claimed issue, got %v", instance.InstanceID, err)
+	}
+	got, err := s.GetIssue(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetIssue: %v", err)
+	}
+	if got.Status != types.StatusInProgress {
+		t.Errorf("ClaimIssue: got status %s, want in_progress", got.Status)
+	}
+	state, err := s.GetExecutionState(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetExecutionState: %v", err)
+	}
+	if state == nil || state.State != types.ExecutionStateClaimed || state.ExecutorInstanceID != instance.InstanceID {
+		t.Fatalf("GetExecutionState: got %+v, want claimed by %s", state, instance.InstanceID)
+	}
+
+	if err := s.UpdateExecutionState(ctx, issue.ID, types.ExecutionStateAssessing); err != nil {
+		t.Fatalf("UpdateExecutionState: %v", err)
+	}
+	if err := s.UpdateExecutionState(ctx, issue.ID, types.ExecutionStateCompleted); err == nil {
+		t.Error("UpdateExecutionState: expected an error for assessing -> completed")
+	}
+
+	if checkpoint, err := s.GetCheckpoint(ctx, issue.ID); err != nil || checkpoint != nil {
+		t.Errorf("GetCheckpoint: expected (nil, nil) before a checkpoint is saved, got (%+v, %v)", checkpoint, err)
+	}
+	saved := &types.CheckpointV1{
+		Phase:       types.ExecutionStateAssessing,
+		SandboxPath: "/tmp/sandbox",
+		Branch:      "mission/vc-1",
+		AgentID:     "agent-1",
+		CreatedAt:   time.Now().UTC().Truncate(time.Second),
+		UpdatedAt:   time.Now().UTC().Truncate(time.Second),
+		Extras:      map[string]interface{}{"step": float64(3)},
+	}
+	if err := s.SaveCheckpoint(ctx, issue.ID, saved); err != nil {
+		t.Fatalf("SaveCheckpoint: %v", err)
+	}
+	checkpoint, err := s.GetCheckpoint(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetCheckpoint: %v", err)
+	}
+	if checkpoint == nil || checkpoint.Phase != saved.Phase || checkpoint.SandboxPath != saved.SandboxPath ||
+		checkpoint.Branch != saved.Branch || checkpoint.AgentID != saved.AgentID ||
+		!checkpoint.CreatedAt.Equal(saved.CreatedAt) || checkpoint.Extras["step"] != float64(3) {
+		t.Errorf("GetCheckpoint: got %+v, want %+v", checkpoint, saved)
+	}
+	if err := s.SaveCheckpoint(ctx, issue.ID, nil); err != nil {
+		t.Fatalf("SaveCheckpoint(nil): %v", err)
+	}
+	if checkpoint, err := s.GetCheckpoint(ctx, issue.ID); err != nil || checkpoint != nil {
+		t.Errorf("SaveCheckpoint(nil): expected the checkpoint cleared, got (%+v, %v)", checkpoint, err)
+	}
+
+	if err := s.ReleaseIssue(ctx, issue.ID); err != nil {
+		t.Fatalf("ReleaseIssue: %v", err)
+	}
+	if state, err := s.GetExecutionState(ctx, issue.ID); err != nil || state != nil {
+		t.Errorf("ReleaseIssue: expected no execution state, got (%+v, %v)", state, err)
+	}
+
+	// A failed attempt reopens the issue with the error as a comment
+	other := createIssue(t, s, "Fails", types.TypeTask)
+	if err := s.ClaimIssue(ctx, other.ID, instance.InstanceID); err != nil {
+		t.Fatalf("ClaimIssue: %v", err)
+	}
+	if err := s.ReleaseIssueAndReopen(ctx, other.ID, instance.InstanceID, "agent crashed"); err != nil {
+		t.Fatalf("ReleaseIssueAndReopen: %v", err)
+	}
+	got, err = s.GetIssue(ctx, other.ID)
+	if err != nil {
+		t.Fatalf("GetIssue: %v", err)
+	}
+	if got.Status != types.StatusOpen {
+		t.Errorf("ReleaseIssueAndReopen: got status %s, want open", got.Status)
+	}
+	state, err = s.GetExecutionState(ctx, other.ID)
+	if err != nil {
+		t.Fatalf("GetExecutionState: %v", err)
+	}
+	if state == nil || state.State != types.ExecutionStateFailed || state.ErrorMessage != "agent crashed" {
+		t.Errorf("ReleaseIssueAndReopen: got execution state %+v, want failed with the error", state)
+	}
+	// The failed claim doesn't block the next executor
+	if err := s.ClaimIssue(ctx, other.ID, instance.InstanceID); err != nil {
+		t.Errorf("ClaimIssue: expected a reopened issue to be claimable, got %v", err)
+	}
+
+	// A closed issue can't be claimed, but that isn't contention
+	closed := createIssue(t, s, "Done", types.TypeTask)
+	if err := s.CloseIssue(ctx, closed.ID, "done", "test"); err != nil {
+		t.Fatalf("CloseIssue: %v", err)
+	}
+	if err := s.ClaimIssue(ctx, closed.ID, instance.InstanceID); !errors.Is(err, types.ErrNotClaimable) || errors.Is(err, types.ErrAlreadyClaimed) {
+		t.Errorf("ClaimIssue: expected ErrNotClaimable for a closed issue, got %v", err)
+	}
+}
+
+func testAssignees(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	instance := registerInstance(t, s, "instance-assignees")
+	shortID := types.ExecutorShortID(instance.InstanceID)
+
+	assign := func(title, assignee string) *types.Issue {
+		t.Helper()
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: 2, IssueType: types.TypeTask, Assignee: assignee}
+		if err := s.CreateIssue(ctx, issue, testActor); err != nil {
+			t.Fatalf("CreateIssue(%q): %v", title, err)
+		}
+		return issue
+	}
+	unassigned := assign("Unassigned", "")
+	colony := assign("Colony", types.AgentAssignee)
+	human := assign("Human", "alice")
+	agentOK := assign("Human, agent ok", "bob")
+	if err := s.AddLabel(ctx, agentOK.ID, types.AgentOKLabel, testActor); err != nil {
+		t.Fatalf("AddLabel: %v", err)
+	}
+
+	readyIDs := func(filter types.WorkFilter) map[string]bool {
+		t.Helper()
+		ready, err := s.GetReadyWork(ctx, filter)
+		if err != nil {
+			t.Fatalf("GetReadyWork: %v", err)
+		}
+		ids := make(map[string]bool)
+		for _, issue := range ready {
+			ids[issue.ID] = true
+		}
+		return ids
+	}
+	ready := readyIDs(types.WorkFilter{Status: types.StatusOpen, AgentsOnly: true})
+	if !ready[unassigned.ID] || !ready[colony.ID] || !ready[agentOK.ID] || ready[human.ID] || len(ready) != 3 {
+		t.Errorf("GetReadyWork(AgentsOnly): got %v, want %s, %s and %s", ready, unassigned.ID, colony.ID, agentOK.ID)
+	}
+	if ready := readyIDs(types.WorkFilter{Status: types.StatusOpen}); !ready[human.ID] {
+		t.Errorf("GetReadyWork: got %v, want it to include %s without AgentsOnly", ready, human.ID)
+	}
+
+	assignee := func(id string) string {
+		t.Helper()
+		issue, err := s.GetIssue(ctx, id)
+		if err != nil {
+			t.Fatalf("GetIssue: %v", err)
+		}
+		return issue.Assignee
+	}
+
+	// The claim assigns the executor; the release restores the assignee
+	if err := s.ClaimIssue(ctx, colony.ID, instance.InstanceID); err != nil {
+		t.Fatalf("ClaimIssue: %v", err)
+	}
+	if got := assignee(colony.ID); got != shortID {
+		t.Errorf("ClaimIssue: got assignee %q, want %q", got, shortID)
+	}
+	state, err := s.GetExecutionState(ctx, colony.ID)
+	if err != nil {
+		t.Fatalf("GetExecutionState: %v", err)
+	}
+	if state == nil || state.PreviousAssignee != types.AgentAssignee {
+		t.Errorf("GetExecutionState: got %+v, want previous assignee %q", state, types.AgentAssignee)
+	}
+	if err := s.ReleaseIssue(ctx, colony.ID); err != nil {
+		t.Fatalf("ReleaseIssue: %v", err)
+	}
+	if got := assignee(colony.ID); got != types.AgentAssignee {
+		t.Errorf("ReleaseIssue: got assignee %q, want %q", got, types.AgentAssignee)
+	}
+
+	// Reassigning a claimed issue sticks
+	if err := s.ClaimIssue(ctx, unassigned.ID, instance.InstanceID); err != nil {
+		t.Fatalf("ClaimIssue: %v", err)
+	}
+	if err := s.UpdateIssue(ctx, unassigned.ID, map[string]interface{}{"assignee": "carol"}, testActor); err != nil {
+		t.Fatalf("UpdateIssue: %v", err)
+	}
+	if err := s.ReleaseIssueAndReopen(ctx, unassigned.ID, instance.InstanceID, "failed"); err != nil {
+		t.Fatalf("ReleaseIssueAndReopen: %v", err)
+	}
+	if got := assignee(unassigned.ID); got != "carol" {
+		t.Errorf("ReleaseIssueAndReopen: got assignee %q, want the new assignee carol", got)
+	}
+
+	// So does releasing the claims of a stopped executor
+	if err := s.ClaimIssue(ctx, agentOK.ID, instance.InstanceID); err != nil {
+		t.Fatalf("ClaimIssue: %v", err)
+	}
+	if err := s.MarkInstanceStopped(ctx, instance.InstanceID); err != nil {
+		t.Fatalf("MarkInstanceStopped: %v", err)
+	}
+	if _, err := s.CleanupStaleInstances(ctx, 300); err != nil {
+		t.Fatalf("CleanupStaleInstances: %v", err)
+	}
+	if got := assignee(agentOK.ID); got != "bob" {
+		t.Errorf("CleanupStaleInstances: got assignee %q, want bob back", got)
+	}
+
+	// Human-assigned blockers don't use up the limit of agent blockers
+	blocker := func(title, assignee string, priority int) *types.Issue {
+		t.Helper()
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: priority, IssueType: types.TypeTask, Assignee: assignee}
+		if err := s.CreateIssue(ctx, issue, testActor); err != nil {
+			t.Fatalf("CreateIssue(%q): %v", title, err)
+		}
+		if err := s.AddLabel(ctx, issue.ID, "discovered:blocker", testActor); err != nil {
+			t.Fatalf("AddLabel: %v", err)
+		}
+		return issue
+	}
+	for i := 0; i < 3; i++ {
+		blocker(fmt.Sprintf("Human blocker %d", i), "alice", 0)
+	}
+	agentBlocker := blocker("Agent blocker", "", 1)
+	blockers, err := s.GetReadyBlockers(ctx, types.WorkFilter{Limit: 1, AgentsOnly: true})
+	if err != nil {
+		t.Fatalf("GetReadyBlockers: %v", err)
+	}
+	if len(blockers) != 1 || blockers[0].ID != agentBlocker.ID {
+		t.Errorf("GetReadyBlockers(AgentsOnly): got %v, want [%s]", ids(blockers), agentBlocker.ID)
+	}
+	if blockers, err := s.GetReadyBlockers(ctx, types.WorkFilter{Limit: 1}); err != nil || len(blockers) != 1 || blockers[0].Assignee != "alice" {
+		t.Errorf("GetReadyBlockers: got %v (%v), want a human blocker without AgentsOnly", ids(blockers), err)
+	}
+}
+
+func testExecutionHistory(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	instance := registerInstance(t, s, "instance-history")
+	issue := createIssue(t, s, "Attempted", types.TypeTask)
+
+	start := time.Now().Add(-time.Hour)
+	for n := 1; n <= 2; n++ {
+		completed := start.Add(time.Duration(n) * time.Minute)
+		success := n == 2
+		exitCode := 1
+		if success {
+			exitCode = 0
+		}
+		attempt := &types.ExecutionAttempt{
+			IssueID:            issue.ID,
+			ExecutorInstanceID: instance.InstanceID,
+			AttemptNumber:      n,
+			StartedAt:          start.Add(time.Duration(n-1) * time.Minute),
+			CompletedAt:        &completed,
+			Success:            &success,
+			ExitCode:           &exitCode,
+			Summary:            fmt.Sprintf("attempt %d", n),
+		}
+		if err := s.RecordExecutionAttempt(ctx, attempt); err != nil {
+			t.Fatalf("RecordExecutionAttempt(%d): %v", n, err)
+		}
+	}
+
+	history, err := s.GetExecutionHistory(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetExecutionHistory: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("GetExecutionHistory: got %d attempts, want 2", len(history))
+	}
+	if history[0].AttemptNumber != 1 || history[1].AttemptNumber != 2 {
+		t.Errorf("GetExecutionHistory: expected oldest attempt first, got %d, %d", history[0].AttemptNumber, history[1].AttemptNumber)
+	}
+	last := history[1]
+	if last.Success == nil || !*last.Success || last.ExitCode == nil || *last.ExitCode != 0 || last.Summary != "attempt 2" {
+		t.Errorf("GetExecutionHistory: got %+v for the second attempt", last)
+	}
+
+	// An attempt is recorded when it starts and finalized when it ends
+	running := &types.ExecutionAttempt{
+		IssueID:            issue.ID,
+		ExecutorInstanceID: instance.InstanceID,
+		AttemptNumber:      3,
+		StartedAt:          time.Now(),
+	}
+	if err := s.RecordExecutionAttempt(ctx, running); err != nil {
+		t.Fatalf("RecordExecutionAttempt(3): %v", err)
+	}
+	if running.ID == 0 || running.ID == history[1].ID {
+		t.Fatalf("RecordExecutionAttempt: expected a new ID, got %d", running.ID)
+	}
+	completed := time.Now()
+	failed := false
+	exitCode := 2
+	running.CompletedAt = &completed
+	running.Success = &failed
+	running.ExitCode = &exitCode
+	running.Summary = "gates failed"
+	running.ErrorSample = "FAIL"
+	running.Artifacts = []string{"artifacts/diff.patch", "artifacts/gate-test.log"}
+	running.Phases = []types.PhaseTiming{
+		{Phase: types.ExecutionPhaseSandbox, DurationMs: 1500},
+		{Phase: types.ExecutionPhaseAgent, DurationMs: 60000},
+		{Phase: types.ExecutionPhaseGates, DurationMs: 30000},
+	}
+	running.CommitHash = "0123456789abcdef0123456789abcdef01234567"
+	running.Branch = "mission/vc-1"
+	running.MergeCommit = "fedcba9876543210fedcba9876543210fedcba98"
+	running.Files = []string{"cmd/vc/main.go", "internal/executor/agent.go"}
+	if err := s.UpdateExecutionAttempt(ctx, running); err != nil {
+		t.Fatalf("UpdateExecutionAttempt: %v", err)
+	}
+	history, err = s.GetExecutionHistory(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetExecutionHistory: %v", err)
+	}
+	if len(history) != 3 {
+		t.Fatalf("GetExecutionHistory: got %d attempts, want 3", len(history))
+	}
+	last = history[2]
+	if last.CompletedAt == nil || last.Success == nil || *last.Success || last.ExitCode == nil || *last.ExitCode != 2 ||
+		last.Summary != "gates failed" || last.ErrorSample != "FAIL" {
+		t.Errorf("UpdateExecutionAttempt: got %+v for the third attempt", last)
+	}
+	if !reflect.DeepEqual(last.Artifacts, running.Artifacts) {
+		t.Errorf("UpdateExecutionAttempt: expected artifacts %v, got %v", running.Artifacts, last.Artifacts)
+	}
+	if history[0].Artifacts != nil {
+		t.Errorf("GetExecutionHistory: expected no artifacts for the first attempt, got %v", history[0].Artifacts)
+	}
+	if !reflect.DeepEqual(last.Phases, running.Phases) {
+		t.Errorf("UpdateExecutionAttempt: expected phases %v, got %v", running.Phases, last.Phases)
+	}
+	if last.CommitHash != running.CommitHash || last.Branch != running.Branch || last.MergeCommit != running.MergeCommit {
+		t.Errorf("UpdateExecutionAttempt: expected commit %s on %s merged as %s, got %q, %q, %q", running.CommitHash,
+			running.Branch, running.MergeCommit, last.CommitHash, last.Branch, last.MergeCommit)
+	}
+	if !reflect.DeepEqual(last.Files, running.Files) {
+		t.Errorf("UpdateExecutionAttempt: expected files %v, got %v", running.Files, last.Files)
+	}
+	if history[0].CommitHash != "" || history[0].MergeCommit != "" || history[0].Files != nil {
+		t.Errorf("GetExecutionHistory: expected no commit for the first attempt, got %+v", history[0])
+	}
+
+	// Only attempts with phase timings count, and only recent ones
+	phased, err := s.GetExecutionPhases(ctx, start)
+	if err != nil {
+		t.Fatalf("GetExecutionPhases: %v", err)
+	}
+	if len(phased) != 1 || phased[0].ID != running.ID || phased[0].IssueID != issue.ID ||
+		!reflect.DeepEqual(phased[0].Phases, running.Phases) || phased[0].Success == nil || *phased[0].Success {
+		t.Errorf("GetExecutionPhases: got %+v, want attempt %d with its phases", phased, running.ID)
+	}
+	if phased, err := s.GetExecutionPhases(ctx, time.Now().Add(time.Hour)); err != nil || len(phased) != 0 {
+		t.Errorf("GetExecutionPhases(future): got (%v, %v), want none", phased, err)
+	}
+
+	if err := s.UpdateExecutionAttempt(ctx, &types.ExecutionAttempt{ID: 9999}); err == nil {
+		t.Error("UpdateExecutionAttempt: expected an error for an unknown attempt")
+	}
+	if err := s.RecordExecutionAttempt(ctx, &types.ExecutionAttempt{IssueID: issue.ID, StartedAt: time.Now()}); err == nil {
+		t.Error("RecordExecutionAttempt: expected an error for an attempt without executor or number")
+	}
+}
+
+func testHistoryCleanup(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	instance := registerInstance(t, s, "instance-cleanup")
+	record := func(issueID string, ages ...time.Duration) {
+		t.Helper()
+		for n, age := range ages {
+			attempt := &types.ExecutionAttempt{
+				IssueID:            issueID,
+				ExecutorInstanceID: instance.InstanceID,
+				AttemptNumber:      n + 1,
+				StartedAt:          time.Now().Add(-age),
+				Artifacts:          []string{fmt.Sprintf("%s/%d/diff.patch", issueID, n+1)},
+			}
+			if err := s.RecordExecutionAttempt(ctx, attempt); err != nil {
+				t.Fatalf("RecordExecutionAttempt: %v", err)
+			}
+		}
+	}
+	day := 24 * time.Hour
+	old := createIssue(t, s, "Old", types.TypeTask)
+	record(old.ID, 100*day, 90*day, 80*day, 70*day, time.Hour)
+	recent := createIssue(t, s, "Recent", types.TypeTask)
+	record(recent.ID, 3*day, 2*day, day)
+
+	blocked := createIssue(t, s, "Blocked", types.TypeTask)
+	record(blocked.ID, 100*day, 90*day, 80*day)
+	if err := s.UpdateIssue(ctx, blocked.ID, map[string]interface{}{"status": string(types.StatusBlocked)}, testActor); err != nil {
+		t.Fatalf("UpdateIssue: %v", err)
+	}
+	escalated := createIssue(t, s, "Escalated", types.TypeTask)
+	record(escalated.ID, 100*day, 90*day, 80*day)
+	if err := s.AddLabel(ctx, escalated.ID, "escalated", testActor); err != nil {
+		t.Fatalf("AddLabel: %v", err)
+	}
+	watched := createIssue(t, s, "Watched", types.TypeTask)
+	record(watched.ID, 100*day, 90*day, 80*day)
+	escalation := createIssue(t, s, "Escalation", types.TypeTask)
+	if err := s.RecordIntervention(ctx, &types.InterventionRecord{IssueID: watched.ID, ExecutorInstanceID: instance.InstanceID,
+		AnomalyType: "stuck_state", Severity: "high", Confidence: 0.9, Action: "escalate", Success: true,
+		EscalationIssueID: escalation.ID, CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("RecordIntervention: %v", err)
+	}
+
+	// Batches of one make the deletes loop
+	deleted, err := s.CleanupExecutionHistory(ctx, 2, 30, 1)
+	if err != nil {
+		t.Fatalf("CleanupExecutionHistory: %v", err)
+	}
+	var paths []string
+	for _, attempt := range deleted {
+		paths = append(paths, attempt.Artifacts...)
+	}
+	want := []string{old.ID + "/1/diff.patch", old.ID + "/2/diff.patch", old.ID + "/3/diff.patch"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("CleanupExecutionHistory: deleted attempts with artifacts %v, want %v", paths, want)
+	}
+	for issueID, remaining := range map[string]int{old.ID: 2, recent.ID: 3, blocked.ID: 3, escalated.ID: 3, watched.ID: 3} {
+		history, err := s.GetExecutionHistory(ctx, issueID)
+		if err != nil {
+			t.Fatalf("GetExecutionHistory: %v", err)
+		}
+		if len(history) != remaining {
+			t.Errorf("CleanupExecutionHistory: %s has %d attempts left, want %d", issueID, len(history), remaining)
+		}
+	}
+
+	// Closing the escalation releases the watched issue's history
+	if err := s.CloseIssue(ctx, escalation.ID, "handled", testActor); err != nil {
+		t.Fatalf("CloseIssue: %v", err)
+	}
+	deleted, err = s.CleanupExecutionHistory(ctx, 2, 30, 100)
+	if err != nil {
+		t.Fatalf("CleanupExecutionHistory: %v", err)
+	}
+	if len(deleted) != 1 || deleted[0].IssueID != watched.ID || deleted[0].AttemptNumber != 1 {
+		t.Errorf("CleanupExecutionHistory: expected the watched issue's first attempt deleted, got %+v", deleted)
+	}
+
+	if _, err := s.CleanupExecutionHistory(ctx, -1, 30, 100); err == nil {
+		t.Error("CleanupExecutionHistory: expected an error for a negative keep count")
+	}
+	if _, err := s.CleanupExecutionHistory(ctx, 2, 30, 0); err == nil {
+		t.Error("CleanupExecutionHistory: expected an error for a zero batch size")
+	}
+}
+
+func testAssessments(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	issue := createIssue(t, s, "Assessed", types.TypeTask)
+
+	latest, err := s.GetLatestAssessment(ctx, issue.ID)
+	if err != nil || latest != nil {
+		t.Fatalf("GetLatestAssessment before any: got %+v, %v; want nil, nil", latest, err)
+	}
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		record := &types.AssessmentRecord{
+			IssueID: issue.ID,
+			Attempt: attempt,
+			Assessment: types.Assessment{
+				Strategy:   fmt.Sprintf("strategy %d", attempt),
+				Steps:      []string{"Write the test", "Fix the bug"},
+				Risks:      []string{"Flaky CI"},
+				Confidence: 0.8,
+				Reasoning:  "Small change",
+			},
+		}
+		if err := s.SaveAssessment(ctx, record); err != nil {
+			t.Fatalf("SaveAssessment(%d): %v", attempt, err)
+		}
+		if record.CreatedAt.IsZero() || record.UpdatedAt.IsZero() {
+			t.Errorf("SaveAssessment(%d): timestamps not set", attempt)
+		}
+	}
+
+	latest, err = s.GetLatestAssessment(ctx, issue.ID)
+	if err != nil || latest == nil {
+		t.Fatalf("GetLatestAssessment: got %+v, %v", latest, err)
+	}
+	if latest.Attempt != 2 || latest.Assessment.Strategy != "strategy 2" || len(latest.Assessment.Steps) != 2 ||
+		latest.Assessment.Steps[1] != "Fix the bug" || latest.Assessment.Risks[0] != "Flaky CI" || latest.Assessment.Confidence != 0.8 {
+		t.Errorf("GetLatestAssessment: got %+v", latest)
+	}
+	if len(latest.CompletedSteps) != 0 {
+		t.Errorf("GetLatestAssessment: expected no completed steps, got %v", latest.CompletedSteps)
+	}
+
+	// Saving the same attempt again replaces it
+	latest.CompletedSteps = []int{0}
+	if err := s.SaveAssessment(ctx, latest); err != nil {
+		t.Fatalf("SaveAssessment(update): %v", err)
+	}
+	updated, err := s.GetLatestAssessment(ctx, issue.ID)
+	if err != nil || updated == nil {
+		t.Fatalf("GetLatestAssessment after update: got %+v, %v", updated, err)
+	}
+	if updated.Attempt != 2 || len(updated.CompletedSteps) != 1 || updated.CompletedSteps[0] != 0 {
+		t.Errorf("GetLatestAssessment after update: got %+v", updated)
+	}
+
+	bad := &types.AssessmentRecord{IssueID: issue.ID, Attempt: 3, CompletedSteps: []int{5}}
+	if err := s.SaveAssessment(ctx, bad); err == nil {
+		t.Error("SaveAssessment: expected an error for an out-of-range completed step")
+	}
+}
+
+func testInterventions(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	issue := createIssue(t, s, "Stuck", types.TypeTask)
+
+	now := time.Now()
+	records := []*types.InterventionRecord{
+		{IssueID: issue.ID, ExecutorInstanceID: "instance-w", AnomalyType: "stuck_state", Severity: "high",
+			Confidence: 0.9, Action: "kill_agent", Success: true, Outcome: "killed", CreatedAt: now.Add(-2 * time.Hour)},
+		{ExecutorInstanceID: "instance-w", AnomalyType: "thrashing", Severity: "medium",
+			Confidence: 0.7, Action: "pause_executor", Success: true, Outcome: "paused", CreatedAt: now.Add(-time.Minute)},
+	}
+	for _, record := range records {
+		if err := s.RecordIntervention(ctx, record); err != nil {
+			t.Fatalf("RecordIntervention: %v", err)
+		}
+	}
+
+	all, err := s.GetInterventions(ctx, types.InterventionFilter{})
+	if err != nil {
+		t.Fatalf("GetInterventions: %v", err)
+	}
+	if len(all) != 2 || all[0].AnomalyType != "thrashing" {
+		t.Errorf("GetInterventions: expected 2 records, newest first, got %+v", all)
+	}
+	forIssue, err := s.GetInterventions(ctx, types.InterventionFilter{IssueID: issue.ID})
+	if err != nil {
+		t.Fatalf("GetInterventions: %v", err)
+	}
+	if len(forIssue) != 1 || forIssue[0].Action != "kill_agent" {
+		t.Errorf("GetInterventions(issue): got %+v", forIssue)
+	}
+	recent, err := s.GetInterventions(ctx, types.InterventionFilter{Since: now.Add(-time.Hour)})
+	if err != nil {
+		t.Fatalf("GetInterventions: %v", err)
+	}
+	if len(recent) != 1 || recent[0].AnomalyType != "thrashing" {
+		t.Errorf("GetInterventions(since): got %+v", recent)
+	}
+}
+
+// storeEvent stores an agent event for issueID
+func storeEvent(t *testing.T, s storage.Storage, issueID string, eventType events.EventType, severity events.EventSeverity, at time.Time) *events.AgentEvent {
+	t.Helper()
+	event := &events.AgentEvent{
+		Type:      eventType,
+		Timestamp: at,
+		IssueID:   issueID,
+		Severity:  severity,
+		Message:   fmt.Sprintf("%s at %s", eventType, at.Format(time.RFC3339Nano)),
+		Data:      map[string]interface{}{"n": 1},
+	}
+	if err := s.StoreAgentEvent(context.Background(), event); err != nil {
+		t.Fatalf("StoreAgentEvent: %v", err)
+	}
+	return event
+}
+
+func testAgentEvents(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	issue := createIssue(t, s, "Eventful", types.TypeTask)
+	other := createIssue(t, s, "Quiet", types.TypeTask)
+
+	base := time.Now().Add(-time.Hour)
+	for i := 0; i < 3; i++ {
+		storeEvent(t, s, issue.ID, events.EventTypeProgress, events.SeverityInfo, base.Add(time.Duration(i)*time.Minute))
+	}
+	storeEvent(t, s, issue.ID, events.EventTypeError, events.SeverityError, base.Add(10*time.Minute))
+	storeEvent(t, s, other.ID, events.EventTypeProgress, events.SeverityInfo, base.Add(20*time.Minute))
+
+	byIssue, err := s.GetAgentEventsByIssue(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("GetAgentEventsByIssue: %v", err)
+	}
+	if len(byIssue) != 4 {
+		t.Errorf("GetAgentEventsByIssue: got %d events, want 4", len(byIssue))
+	}
+
+	errorsOnly, err := s.GetAgentEvents(ctx, events.EventFilter{IssueID: issue.ID, Type: events.EventTypeError})
+	if err != nil {
+		t.Fatalf("GetAgentEvents: %v", err)
+	}
+	if len(errorsOnly) != 1 || errorsOnly[0].Severity != events.SeverityError || errorsOnly[0].Data["n"] == nil {
+		t.Errorf("GetAgentEvents(type=error): got %+v", errorsOnly)
+	}
+
+	recent, err := s.GetRecentAgentEvents(ctx, 2)
+	if err != nil {
+		t.Fatalf("GetRecentAgentEvents: %v", err)
+	}
+	if len(recent) != 2 {
+		t.Errorf("GetRecentAgentEvents(2): got %d events", len(recent))
+	}
+
+	var streamed []*events.AgentEvent
+	err = s.StreamAgentEvents(ctx, events.EventFilter{IssueID: issue.ID}, func(event *events.AgentEvent) error {
+		streamed = append(streamed, event)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamAgentEvents: %v", err)
+	}
+	if len(streamed) != 4 {
+		t.Fatalf("StreamAgentEvents: got %d events, want 4", len(streamed))
+	}
+	for i := 1; i < len(streamed); i++ {
+		if streamed[i].Timestamp.Before(streamed[i-1].Timestamp) {
+			t.Errorf("StreamAgentEvents: expected oldest first, got %v before %v", streamed[i-1].Timestamp, streamed[i].Timestamp)
+		}
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+	err = s.StreamAgentEvents(ctx, events.EventFilter{}, func(*events.AgentEvent) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("StreamAgentEvents: expected the callback error after 1 call, got %v after %d", err, calls)
+	}
+}
+
+func testWatchAgentEvents(t *testing.T, s storage.Storage) {
+	issue := createIssue(t, s, "Watched", types.TypeTask)
+	other := createIssue(t, s, "Unwatched", types.TypeTask)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	watched, err := s.WatchAgentEvents(ctx, events.EventFilter{IssueID: issue.ID})
+	if err != nil {
+		t.Fatalf("WatchAgentEvents: %v", err)
+	}
+
+	storeEvent(t, s, other.ID, events.EventTypeProgress, events.SeverityInfo, time.Now())
+	want := storeEvent(t, s, issue.ID, events.EventTypeProgress, events.SeverityInfo, time.Now())
+
+	select {
+	case got := <-watched:
+		if got == nil || got.IssueID != issue.ID || got.Message != want.Message {
+			t.Errorf("WatchAgentEvents: got %+v, want the event for %s", got, issue.ID)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("WatchAgentEvents: no event delivered within 5s")
+	}
+
+	cancel()
+	deadline := time.After(5 * time.Second)
+	for {
+		select {
+		case _, ok := <-watched:
+			if !ok {
+				return
+			}
+		case <-deadline:
+			t.Fatal("WatchAgentEvents: channel not closed after the context was canceled")
+		}
+	}
+}
+
+func testEventCleanup(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	issue := createIssue(t, s, "Noisy", types.TypeTask)
+	other := createIssue(t, s, "Less noisy", types.TypeTask)
+
+	old := time.Now().Add(-10 * 24 * time.Hour)
+	storeEvent(t, s, issue.ID, events.EventTypeProgress, events.SeverityInfo, old)
+	storeEvent(t, s, issue.ID, events.EventTypeError, events.SeverityError, old)
+	for i := 0; i < 4; i++ {
+		storeEvent(t, s, issue.ID, events.EventTypeProgress, events.SeverityInfo, time.Now().Add(-time.Duration(i)*time.Minute))
+	}
+	for i := 0; i < 2; i++ {
+		storeEvent(t, s, other.ID, events.EventTypeProgress, events.SeverityInfo, time.Now().Add(-time.Duration(i)*time.Minute))
+	}
+
+	counts, err := s.GetEventCounts(ctx)
+	if err != nil {
+		t.Fatalf("GetEventCounts: %v", err)
+	}
+	if counts.TotalEvents != 8 || counts.EventsByIssue[issue.ID] != 6 || counts.EventsBySeverity[string(events.SeverityError)] != 1 {
+		t.Errorf("GetEventCounts: got %+v, want 8 total, 6 for %s, 1 error", counts, issue.ID)
+	}
+
+	// Old info events go after 7 days; errors count as critical and are kept for 30
+	deleted, err := s.CleanupEventsByAge(ctx, 7, 30, 100)
+	if err != nil {
+		t.Fatalf("CleanupEventsByAge: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("CleanupEventsByAge: deleted %d events, want 1", deleted)
+	}
+
+	deleted, err = s.CleanupEventsByIssueLimit(ctx, 3, 100)
+	if err != nil {
+		t.Fatalf("CleanupEventsByIssueLimit: %v", err)
+	}
+	if deleted != 2 {
+		t.Errorf("CleanupEventsByIssueLimit(3): deleted %d events, want 2", deleted)
+	}
+
+	deleted, err = s.CleanupEventsByGlobalLimit(ctx, 4, 100)
+	if err != nil {
+		t.Fatalf("CleanupEventsByGlobalLimit: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("CleanupEventsByGlobalLimit(4): deleted %d events, want 1", deleted)
+	}
+
+	counts, err = s.GetEventCounts(ctx)
+	if err != nil {
+		t.Fatalf("GetEventCounts: %v", err)
+	}
+	if counts.TotalEvents != 4 {
+		t.Errorf("GetEventCounts: got %d events after cleanup, want 4", counts.TotalEvents)
+	}
+}
+
+func testDatabaseMaintenance(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	createIssue(t, s, "Something to store", types.TypeTask)
+
+	stats, err := s.GetDatabaseStats(ctx)
+	if err != nil {
+		t.Fatalf("GetDatabaseStats: %v", err)
+	}
+	if stats.PageSize <= 0 || stats.PageCount <= 0 || stats.SizeBytes <= 0 {
+		t.Errorf("GetDatabaseStats: expected positive sizes, got %+v", stats)
+	}
+	if stats.FreelistCount < 0 || stats.FreelistCount > stats.PageCount {
+		t.Errorf("GetDatabaseStats: free pages %d out of range for %d pages", stats.FreelistCount, stats.PageCount)
+	}
+
+	if err := s.IncrementalVacuum(ctx); err != nil {
+		t.Errorf("IncrementalVacuum: %v", err)
+	}
+	if err := s.VacuumDatabase(ctx); err != nil {
+		t.Errorf("VacuumDatabase: %v", err)
+	}
+	if _, err := s.GetStatistics(ctx); err != nil {
+		t.Errorf("GetStatistics after VACUUM: %v", err)
+	}
+
+	// Reopening a healthy database changes nothing
+	before, err := s.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		t.Fatalf("SearchIssues: %v", err)
+	}
+	if err := s.Reopen(ctx); err != nil {
+		t.Fatalf("Reopen: %v", err)
+	}
+	after, err := s.SearchIssues(ctx, "", types.IssueFilter{})
+	if err != nil {
+		t.Fatalf("SearchIssues after Reopen: %v", err)
+	}
+	if len(after) != len(before) {
+		t.Errorf("Reopen: %d issues before, %d after", len(before), len(after))
+	}
+	createIssue(t, s, "Stored after reopening", types.TypeTask)
+}
+
+func testConfig(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+
+	if err := s.SetConfig(ctx, "storagetest.key", "one"); err != nil {
+		t.Fatalf("SetConfig: %v", err)
+	}
+	if err := s.SetConfig(ctx, "storagetest.key", "two"); err != nil {
+		t.Fatalf("SetConfig (overwrite): %v", err)
+	}
+	value, err := s.GetConfig(ctx, "storagetest.key")
+	if err != nil {
+		t.Fatalf("GetConfig: %v", err)
+	}
+	if value != "two" {
+		t.Errorf("GetConfig: got %q, want \"two\"", value)
+	}
+	if value, err := s.GetConfig(ctx, "storagetest.missing"); err != nil || value != "" {
+		t.Errorf("GetConfig: expected (\"\", nil) for a missing key, got (%q, %v)", value, err)
+	}
+
+	// ListConfig filters by prefix
+	if err := s.SetConfig(ctx, "storagetest.other", "three"); err != nil {
+		t.Fatalf("SetConfig: %v", err)
+	}
+	if err := s.SetConfig(ctx, "storagetestx", "four"); err != nil {
+		t.Fatalf("SetConfig: %v", err)
+	}
+	listed, err := s.ListConfig(ctx, "storagetest.")
+	if err != nil {
+		t.Fatalf("ListConfig: %v", err)
+	}
+	want := map[string]string{"storagetest.key": "two", "storagetest.other": "three"}
+	if len(listed) != len(want) {
+		t.Errorf("ListConfig: got %v, want %v", listed, want)
+	}
+	for key, value := range want {
+		if listed[key] != value {
+			t.Errorf("ListConfig: got %s=%q, want %q", key, listed[key], value)
+		}
+	}
+	if all, err := s.ListConfig(ctx, ""); err != nil || all["storagetestx"] != "four" {
+		t.Errorf("ListConfig(\"\"): expected all keys, got (%v, %v)", all, err)
+	}
+
+	// Known settings are validated
+	if err := s.SetConfig(ctx, "executor.poll_interval", "soon"); err == nil {
+		t.Error("SetConfig: expected an error for an invalid duration")
+	}
+	if value, _ := s.GetConfig(ctx, "executor.poll_interval"); value != "" {
+		t.Errorf("SetConfig: invalid value was stored: %q", value)
+	}
+
+	// Changing an executor setting is audited; re-setting the same value isn't
+	configEvents := func() []*events.AgentEvent {
+		t.Helper()
+		all, err := s.GetAgentEventsByIssue(ctx, "SYSTEM")
+		if err != nil {
+			t.Fatalf("GetAgentEventsByIssue: %v", err)
+		}
+		var result []*events.AgentEvent
+		for _, event := range all {
+			if event.Type == events.EventTypeConfigChanged {
+				result = append(result, event)
+			}
+		}
+		return result
+	}
+	for _, value := range []string{"10s", "10s"} {
+		if err := s.SetConfig(ctx, "executor.poll_interval", value); err != nil {
+			t.Fatalf("SetConfig: %v", err)
+		}
+	}
+	changed := configEvents()
+	if len(changed) != 1 {
+		t.Fatalf("Expected 1 config_changed event, got %d", len(changed))
+	}
+	data, err := changed[0].GetConfigChangedData()
+	if err != nil {
+		t.Fatalf("GetConfigChangedData: %v", err)
+	}
+	if data.Key != "executor.poll_interval" || data.OldValue != "" || data.NewValue != "10s" {
+		t.Errorf("Unexpected config_changed data: %+v", data)
+	}
+}
+
+func testGitHubSync(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	first := createIssue(t, s, "Linked first", types.TypeTask)
+	second := createIssue(t, s, "Linked second", types.TypeBug)
+
+	if link, err := s.GetGitHubLink(ctx, first.ID); err != nil || link != nil {
+		t.Fatalf("GetGitHubLink: expected (nil, nil) before linking, got (%+v, %v)", link, err)
+	}
+	synced := time.Now().Add(-time.Hour).Truncate(time.Second)
+	links := []*types.GitHubLink{
+		{IssueID: second.ID, Repo: "acme/widgets", Number: 7, State: types.GitHubLinkActive, SyncedTitle: "Linked second", SyncedAt: synced},
+		{IssueID: first.ID, Repo: "acme/widgets", Number: 3, State: types.GitHubLinkActive, SyncedTitle: "Linked first", SyncedAt: synced},
+	}
+	for _, link := range links {
+		if err := s.SaveGitHubLink(ctx, link); err != nil {
+			t.Fatalf("SaveGitHubLink(%s): %v", link.IssueID, err)
+		}
+	}
+	if err := s.SaveGitHubLink(ctx, &types.GitHubLink{IssueID: first.ID, Repo: "acme/widgets", Number: 0, State: types.GitHubLinkActive}); err == nil {
+		t.Error("SaveGitHubLink: expected an error for an invalid link")
+	}
+	if err := s.SaveGitHubLink(ctx, &types.GitHubLink{IssueID: first.ID, Repo: "acme/widgets", Number: 7, State: types.GitHubLinkActive}); err == nil {
+		t.Error("SaveGitHubLink: expected an error linking two issues to one GitHub issue")
+	}
+
+	// Saving again replaces the link
+	moved := &types.GitHubLink{IssueID: first.ID, Repo: "acme/widgets", Number: 3, State: types.GitHubLinkTransferred,
+		SyncedTitle: "Linked first", SyncedClosed: true, SyncedAt: synced, MovedTo: "https://github.com/acme/gadgets/issues/1"}
+	if err := s.SaveGitHubLink(ctx, moved); err != nil {
+		t.Fatalf("SaveGitHubLink (replace): %v", err)
+	}
+	link, err := s.GetGitHubLink(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("GetGitHubLink: %v", err)
+	}
+	if link == nil || link.State != types.GitHubLinkTransferred || !link.SyncedClosed || link.MovedTo != moved.MovedTo || !link.SyncedAt.Equal(synced) {
+		t.Errorf("GetGitHubLink: got %+v, want %+v", link, moved)
+	}
+
+	listed, err := s.GetGitHubLinks(ctx, "acme/widgets")
+	if err != nil {
+		t.Fatalf("GetGitHubLinks: %v", err)
+	}
+	if len(listed) != 2 || listed[0].Number != 3 || listed[1].Number != 7 {
+		t.Errorf("GetGitHubLinks: expected #3 and #7 by number, got %+v", listed)
+	}
+	if other, err := s.GetGitHubLinks(ctx, "acme/other"); err != nil || len(other) != 0 {
+		t.Errorf("GetGitHubLinks: expected no links of another repository, got (%+v, %v)", other, err)
+	}
+
+	if cursor, err := s.GetGitHubSyncCursor(ctx, "acme/widgets"); err != nil || !cursor.IsZero() {
+		t.Errorf("GetGitHubSyncCursor: expected the zero time before a sync, got (%v, %v)", cursor, err)
+	}
+	for _, cursor := range []time.Time{synced, synced.Add(time.Minute)} {
+		if err := s.SetGitHubSyncCursor(ctx, "acme/widgets", cursor); err != nil {
+			t.Fatalf("SetGitHubSyncCursor: %v", err)
+		}
+	}
+	if cursor, err := s.GetGitHubSyncCursor(ctx, "acme/widgets"); err != nil || !cursor.Equal(synced.Add(time.Minute)) {
+		t.Errorf("GetGitHubSyncCursor: got (%v, %v), want %v", cursor, err, synced.Add(time.Minute))
+	}
+}
+
+func testSchedules(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	if schedule, err := s.GetSchedule(ctx, 1); err != nil || schedule != nil {
+		t.Fatalf("GetSchedule: expected (nil, nil) before creating, got (%+v, %v)", schedule, err)
+	}
+	weekly := &types.Schedule{Title: "Dependency review", IssueType: types.TypeChore, Priority: 2,
+		Labels: []string{"deps"}, Cron: "@weekly", Policy: types.ScheduleSkipOpen, Enabled: true}
+	hourly := &types.Schedule{Title: "Check the queue", IssueType: types.TypeTask, Priority: 1,
+		Interval: time.Hour, Policy: types.ScheduleFileAnyway}
+	for _, schedule := range []*types.Schedule{weekly, hourly} {
+		if err := s.CreateSchedule(ctx, schedule); err != nil {
+			t.Fatalf("CreateSchedule(%s): %v", schedule.Title, err)
+		}
+		if schedule.ID == 0 || schedule.CreatedAt.IsZero() {
+			t.Errorf("CreateSchedule: expected ID and CreatedAt set, got %+v", schedule)
+		}
+	}
+	if err := s.CreateSchedule(ctx, &types.Schedule{Title: "Both", IssueType: types.TypeTask, Cron: "@daily",
+		Interval: time.Hour, Policy: types.ScheduleSkipOpen}); err == nil {
+		t.Error("CreateSchedule: expected an error for a schedule with both a cron spec and an interval")
+	}
+
+	got, err := s.GetSchedule(ctx, weekly.ID)
+	if err != nil {
+		t.Fatalf("GetSchedule: %v", err)
+	}
+	if got == nil || got.Title != weekly.Title || got.Cron != "@weekly" || got.Policy != types.ScheduleSkipOpen ||
+		!got.Enabled || len(got.Labels) != 1 || got.Labels[0] != "deps" || got.Runs != 0 || got.LastRunAt != nil {
+		t.Errorf("GetSchedule: got %+v, want %+v", got, weekly)
+	}
+	listed, err := s.ListSchedules(ctx)
+	if err != nil {
+		t.Fatalf("ListSchedules: %v", err)
+	}
+	if len(listed) != 2 || listed[0].ID != weekly.ID || listed[1].Interval != time.Hour || listed[1].Enabled {
+		t.Errorf("ListSchedules: expected both schedules by ID, got %+v", listed)
+	}
+
+	// Claiming is a compare-and-set on Runs
+	runAt := time.Now().Truncate(time.Second)
+	if ok, err := s.ClaimScheduleRun(ctx, weekly.ID, 0, runAt); err != nil || !ok {
+		t.Fatalf("ClaimScheduleRun: expected the first claim to win, got (%v, %v)", ok, err)
+	}
+	if ok, err := s.ClaimScheduleRun(ctx, weekly.ID, 0, runAt); err != nil || ok {
+		t.Errorf("ClaimScheduleRun: expected a second claim of the same window to lose, got (%v, %v)", ok, err)
+	}
+	if err := s.SetScheduleIssue(ctx, weekly.ID, "vc-42"); err != nil {
+		t.Fatalf("SetScheduleIssue: %v", err)
+	}
+	if err := s.SetScheduleEnabled(ctx, weekly.ID, false); err != nil {
+		t.Fatalf("SetScheduleEnabled: %v", err)
+	}
+	got, err = s.GetSchedule(ctx, weekly.ID)
+	if err != nil {
+		t.Fatalf("GetSchedule: %v", err)
+	}
+	if got.Runs != 1 || got.LastRunAt == nil || !got.LastRunAt.Equal(runAt) || got.LastIssueID != "vc-42" || got.Enabled {
+		t.Errorf("GetSchedule after a run: got %+v", got)
+	}
+
+	if err := s.DeleteSchedule(ctx, weekly.ID); err != nil {
+		t.Fatalf("DeleteSchedule: %v", err)
+	}
+	if err := s.DeleteSchedule(ctx, weekly.ID); err == nil {
+		t.Error("DeleteSchedule: expected an error for a missing schedule")
+	}
+	if err := s.SetScheduleEnabled(ctx, weekly.ID, true); err == nil {
+		t.Error("SetScheduleEnabled: expected an error for a missing schedule")
+	}
+	if listed, err := s.ListSchedules(ctx); err != nil || len(listed) != 1 || listed[0].ID != hourly.ID {
+		t.Errorf("ListSchedules after delete: got (%+v, %v)", listed, err)
+	}
+}
+
+func testTimeEntries(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	issue := createIssue(t, s, "Tracked", types.TypeTask)
+	other := createIssue(t, s, "Other", types.TypeTask)
+	if entry, err := s.GetTimeEntry(ctx, 1); err != nil || entry != nil {
+		t.Fatalf("GetTimeEntry: expected (nil, nil) before adding, got (%+v, %v)", entry, err)
+	}
+
+	first := &types.TimeEntry{IssueID: issue.ID, Minutes: 45, Note: "pairing", Actor: testActor}
+	second := &types.TimeEntry{IssueID: issue.ID, Minutes: 30, Actor: testActor}
+	third := &types.TimeEntry{IssueID: other.ID, Minutes: 10, Actor: testActor}
+	for _, entry := range []*types.TimeEntry{first, second, third} {
+		if err := s.AddTimeEntry(ctx, entry); err != nil {
+			t.Fatalf("AddTimeEntry: %v", err)
+		}
+		if entry.ID == 0 || entry.CreatedAt.IsZero() || entry.UpdatedAt.IsZero() {
+			t.Errorf("AddTimeEntry: expected ID, CreatedAt and UpdatedAt set, got %+v", entry)
+		}
+	}
+	for _, bad := range []*types.TimeEntry{
+		{IssueID: issue.ID, Minutes: 0, Actor: testActor},
+		{IssueID: issue.ID, Minutes: 5},
+		{IssueID: "vc-missing", Minutes: 5, Actor: testActor},
+	} {
+		if err := s.AddTimeEntry(ctx, bad); err == nil {
+			t.Errorf("AddTimeEntry(%+v): expected an error", bad)
+		}
+	}
+
+	entries, err := s.ListTimeEntries(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("ListTimeEntries: %v", err)
+	}
+	if len(entries) != 2 || entries[0].ID != first.ID || entries[0].Minutes != 45 || entries[0].Note != "pairing" ||
+		entries[0].Actor != testActor || entries[1].ID != second.ID {
+		t.Errorf("ListTimeEntries: expected the issue's two entries oldest first, got %+v", entries)
+	}
+	if all, err := s.ListTimeEntries(ctx, ""); err != nil || len(all) != 3 {
+		t.Errorf("ListTimeEntries(\"\"): expected every entry, got (%+v, %v)", all, err)
+	}
+
+	if err := s.UpdateTimeEntry(ctx, first.ID, 60, "pairing and review", "reviewer"); err != nil {
+		t.Fatalf("UpdateTimeEntry: %v", err)
+	}
+	got, err := s.GetTimeEntry(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("GetTimeEntry: %v", err)
+	}
+	if got == nil || got.Minutes != 60 || got.Note != "pairing and review" || got.Actor != "reviewer" || got.IssueID != issue.ID {
+		t.Errorf("GetTimeEntry after update: got %+v", got)
+	}
+	if err := s.UpdateTimeEntry(ctx, first.ID, 0, "", testActor); err == nil {
+		t.Error("UpdateTimeEntry: expected an error for zero minutes")
+	}
+	if err := s.UpdateTimeEntry(ctx, 9999, 5, "", testActor); err == nil {
+		t.Error("UpdateTimeEntry: expected an error for a missing entry")
+	}
+
+	if err := s.DeleteTimeEntry(ctx, second.ID); err != nil {
+		t.Fatalf("DeleteTimeEntry: %v", err)
+	}
+	if err := s.DeleteTimeEntry(ctx, second.ID); err == nil {
+		t.Error("DeleteTimeEntry: expected an error for a missing entry")
+	}
+	if entries, err := s.ListTimeEntries(ctx, issue.ID); err != nil || len(entries) != 1 || entries[0].ID != first.ID {
+		t.Errorf("ListTimeEntries after delete: got (%+v, %v)", entries, err)
+	}
+}
+
+func testMetadata(t *testing.T, s storage.Storage) {
+	ctx := context.Background()
+	linked := createIssue(t, s, "Linked", types.TypeTask)
+	other := createIssue(t, s, "Other", types.TypeTask)
+
+	if meta, err := s.GetIssueMeta(ctx, linked.ID); err != nil || meta == nil || len(meta) != 0 {
+		t.Fatalf("GetIssueMeta: expected empty metadata, got (%v, %v)", meta, err)
+	}
+	values := map[string]string{
+		"jira.key":       `"PROJ-7"`,
+		"github.issue":   `{"repo": "acme/widgets", "number": 7}`,
+		"review.urls":    `["https://example.com/r/1"]`,
+		"service.weight": `3`,
+	}
+	for key, value := range values {
+		if err := s.SetIssueMeta(ctx, linked.ID, key, json.RawMessage(value)); err != nil {
+			t.Fatalf("SetIssueMeta(%s): %v", key, err)
+		}
+	}
+	if err := s.SetIssueMeta(ctx, other.ID, "jira.key", json.RawMessage(`"PROJ-8"`)); err != nil {
+		t.Fatalf("SetIssueMeta: %v", err)
+	}
+	// Replacing a value
+	if err := s.SetIssueMeta(ctx, linked.ID, "service.weight", json.RawMessage(`5`)); err != nil {
+		t.Fatalf("SetIssueMeta (replace): %v", err)
+	}
+
+	for _, bad := range []struct {
+		issueID, key, value string
+	}{
+		{linked.ID, "flat", `1`},
+		{linked.ID, "Upper.case", `1`},
+		{linked.ID, "jira..key", `1`},
+		{linked.ID, "jira.key", `not json`},
+		{linked.ID, "jira.key", `"` + strings.Repeat("x", types.MaxMetaValueSize) + `"`},
+		{"vc-9999", "jira.key", `1`},
+	} {
+		if err := s.SetIssueMeta(ctx, bad.issueID, bad.key, json.RawMessage(bad.value)); err == nil {
+			t.Errorf("SetIssueMeta(%s, %s, %.20s): expected an error", bad.issueID, bad.key, bad.value)
+		}
+	}
+
+	meta, err := s.GetIssueMeta(ctx, linked.ID)
+	if err != nil {
+		t.Fatalf("GetIssueMeta: %v", err)
+	}
+	if len(meta) != 4 || string(meta["service.weight"]) != `5` || string(meta["jira.key"]) != `"PROJ-7"` {
+		t.Errorf("GetIssueMeta: got %v", meta)
+	}
+	// Values come back normalized: compact, object keys sorted
+	if got := string(meta["github.issue"]); got != `{"number":7,"repo":"acme/widgets"}` {
+		t.Errorf("GetIssueMeta: expected a normalized object, got %s", got)
+	}
+
+	search := func(metaEquals map[string]string) []string {
+		t.Helper()
+		issues, err := s.SearchIssues(ctx, "", types.IssueFilter{MetaEquals: metaEquals})
+		if err != nil {
+			t.Fatalf("SearchIssues(%v): %v", metaEquals, err)
+		}
+		var ids []string
+		for _, issue := range issues {
+			ids = append(ids, issue.ID)
+		}
+		return ids
+	}
+	if ids := search(map[string]string{"jira.key": "PROJ-7"}); len(ids) != 1 || ids[0] != linked.ID {
+		t.Errorf("MetaEquals jira.key=PROJ-7: got %v", ids)
+	}
+	if ids := search(map[string]string{"jira.key": "PROJ-8", "service.weight": "5"}); len(ids) != 0 {
+		t.Errorf("MetaEquals with values of two issues: expected none, got %v", ids)
+	}
+	if ids := search(map[string]string{"github.issue": `{"repo":"acme/widgets","number":7}`, "service.weight": "5"}); len(ids) != 1 {
+		t.Errorf("MetaEquals on an object and a number: got %v", ids)
+	}
+	if ids := search(map[string]string{"service.weight": `"5"`}); len(ids) != 0 {
+		t.Errorf("MetaEquals: the string \"5\" matched the number 5: %v", ids)
+	}
+	if n, err := s.CountIssues(ctx, "", types.IssueFilter{MetaEquals: map[string]string{"jira.key": "PROJ-8"}}); err != nil || n != 1 {
+		t.Errorf("CountIssues with MetaEquals: got (%d, %v), want 1", n, err)
+	}
+
+	if err := s.DeleteIssueMeta(ctx, linked.ID, "review.urls"); err != nil {
+		t.Fatalf("DeleteIssueMeta: %v", err)
+	}
+	if err := s.DeleteIssueMeta(ctx, linked.ID, "review.urls"); err == nil {
+		t.Error("DeleteIssueMeta: expected an error for a missing key")
+	}
+	if meta, err := s.GetIssueMeta(ctx, linked.ID); err != nil || len(meta) != 3 {
+		t.Errorf("GetIssueMeta after delete: got (%v, %v)", meta, err)
+	}
+}
@@ -0,0 +1,253 @@
+// Package templates loads per-type issue templates.
+//
+// A template is a Markdown file at .vc/templates/<type>.md under the project
+// root. Level-1 headings split it into the issue's sections:
+//
+//	# Description
+//	# Design
+//	# Acceptance Criteria
+//
+// Text before the first heading belongs to the description, and deeper
+// headings (## Steps to reproduce) are part of the section they are in. Any
+// other level-1 heading is an error, so a typo can't silently drop a
+// section. Bugs and features have built-in templates that a file replaces.
+package templates
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// Dir is the template directory, relative to the project root
+const Dir = ".vc/templates"
+
+// Section headings, as written in template files
+const (
+	SectionDescription = "Description"
+	SectionDesign      = "Design"
+	SectionAcceptance  = "Acceptance Criteria"
+)
+
+// sections maps lowercased headings to the sections they start
+var sections = map[string]string{
+	"description":         SectionDescription,
+	"design":              SectionDesign,
+	"acceptance criteria": SectionAcceptance,
+	"acceptance":          SectionAcceptance,
+}
+
+// Template pre-fills the sections of new issues of one type
+type Template struct {
+	Type types.IssueType
+	// Path is the template file, or "" for a built-in template
+	Path               string
+	Description        string
+	Design             string
+	AcceptanceCriteria string
+}
+
+// builtin are the templates used when .vc/templates has no file for the type
+var builtin = map[types.IssueType]string{
+	types.TypeBug: `## Steps to reproduce
+
+1.
+
+## Expected
+
+## Actual
+`,
+	types.TypeFeature: `## Motivation
+
+# Design
+
+## Approach
+
+## Alternatives considered
+
+# Acceptance Criteria
+
+- [ ]
+`,
+}
+
+// Parse reads a template. A level-1 heading that isn't a known section is
+// an error naming its line.
+func Parse(r io.Reader) (*Template, error) {
+	var t Template
+	current := &t.Description
+	seen := map[string]int{}
+	scanner := bufio.NewScanner(r)
+	var b strings.Builder
+	flush := func() {
+		if text := strings.TrimSpace(b.String()); text != "" {
+			if *current != "" {
+				text = *current + "\n\n" + text
+			}
+			*current = text
+		}
+		b.Reset()
+	}
+	inFence := false
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if strings.HasPrefix(strings.TrimSpace(text), "```") {
+			inFence = !inFence
+		}
+		if !inFence && strings.HasPrefix(text, "# ") {
+			name := strings.TrimSpace(strings.TrimPrefix(text, "# "))
+			section, ok := sections[strings.ToLower(name)]
+			if !ok {
+				return nil, fmt.Errorf("line %d: unknown section %q (want %s, %s or %s)",
+					line, name, SectionDescription, SectionDesign, SectionAcceptance)
+			}
+			if prev, dup := seen[section]; dup {
+				return nil, fmt.Errorf("line %d: section %q repeats line %d", line, name, prev)
+			}
+			seen[section] = line
+			// Text before a # Description heading stays with it
+			if section == SectionDescription && current == &t.Description {
+				continue
+			}
+			flush()
+			current = t.field(section)
+			continue
+		}
+		b.WriteString(text)
+		b.WriteString("\n")
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+	return &t, nil
+}
+
+// field returns the field a section fills
+func (t *Template) field(section string) *string {
+	switch section {
+	case SectionDesign:
+		return &t.Design
+	case SectionAcceptance:
+		return &t.AcceptanceCriteria
+	default:
+		return &t.Description
+	}
+}
+
+// Load returns the template of the issue type: root's template file if
+// there is one, else the built-in template. It returns (nil, nil) if the
+// type has neither.
+func Load(root string, issueType types.IssueType) (*Template, error) {
+	path := filepath.Join(root, Dir, string(issueType)+".md")
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Builtin(issueType), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	t, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template %s: %w", path, err)
+	}
+	t.Type, t.Path = issueType, path
+	return t, nil
+}
+
+// Builtin returns the built-in template of the issue type, or nil
+func Builtin(issueType types.IssueType) *Template {
+	text, ok := builtin[issueType]
+	if !ok {
+		return nil
+	}
+	t, err := Parse(strings.NewReader(text))
+	if err != nil {
+		panic(fmt.Sprintf("invalid built-in %s template: %v", issueType, err))
+	}
+	t.Type = issueType
+	return t
+}
+
+// Entry is one template found by List
+type Entry struct {
+	Type types.IssueType
+	// Path is the template file, or "" for a built-in template
+	Path string
+	// Err is why the file can't be used, if it can't
+	Err error
+}
+
+// List returns the templates under root and the built-in ones they don't
+// replace, by type. A file that isn't named for an issue type or doesn't
+// parse is listed with its error.
+func List(root string) ([]Entry, error) {
+	dir := filepath.Join(root, Dir)
+	files, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	byType := map[types.IssueType]Entry{}
+	for issueType := range builtin {
+		byType[issueType] = Entry{Type: issueType}
+	}
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || filepath.Ext(name) != ".md" {
+			continue
+		}
+		issueType := types.IssueType(strings.TrimSuffix(name, ".md"))
+		entry := Entry{Type: issueType, Path: filepath.Join(dir, name)}
+		if !issueType.IsValid() {
+			entry.Err = fmt.Errorf("%s is not an issue type (bug|feature|task|epic|chore)", issueType)
+		} else if _, err := Load(root, issueType); err != nil {
+			entry.Err = err
+		}
+		byType[issueType] = entry
+	}
+	entries := make([]Entry, 0, len(byType))
+	for _, entry := range byType {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Type < entries[j].Type })
+	return entries, nil
+}
+
+// Apply fills the issue's empty sections from the template
+func (t *Template) Apply(issue *types.Issue) {
+	if issue.Description == "" {
+		issue.Description = t.Description
+	}
+	if issue.Design == "" {
+		issue.Design = t.Design
+	}
+	if issue.AcceptanceCriteria == "" {
+		issue.AcceptanceCriteria = t.AcceptanceCriteria
+	}
+}
+
+// Render returns the issue's sections as a template for filling in; Parse
+// reads it back
+func Render(issue *types.Issue) string {
+	var b strings.Builder
+	for _, s := range []struct{ heading, text string }{
+		{SectionDescription, issue.Description},
+		{SectionDesign, issue.Design},
+		{SectionAcceptance, issue.AcceptanceCriteria},
+	} {
+		fmt.Fprintf(&b, "# %s\n\n", s.heading)
+		if s.text != "" {
+			b.WriteString(s.text)
+			b.WriteString("\n\n")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,119 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/vc/internal/types"
+)
+
+func TestParse(t *testing.T) {
+	tmpl, err := Parse(strings.NewReader(`Intro line
+
+## Steps to reproduce
+
+# Design
+
+Sketch it.
+
+` + "```" + `
+# not a heading inside a fence
+` + "```" + `
+
+# acceptance criteria
+
+- [ ] Works
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tmpl.Description != "Intro line\n\n## Steps to reproduce" {
+		t.Errorf("Description = %q", tmpl.Description)
+	}
+	if !strings.HasPrefix(tmpl.Design, "Sketch it.") || !strings.Contains(tmpl.Design, "# not a heading") {
+		t.Errorf("Design = %q", tmpl.Design)
+	}
+	if tmpl.AcceptanceCriteria != "- [ ] Works" {
+		t.Errorf("AcceptanceCriteria = %q", tmpl.AcceptanceCriteria)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, text := range []string{
+		"# Description\n\n# Notes\n",
+		"# Design\nA\n# Design\nB\n",
+	} {
+		if _, err := Parse(strings.NewReader(text)); err == nil {
+			t.Errorf("Parse(%q): expected an error", text)
+		}
+	}
+}
+
+func TestRenderRoundTrip(t *testing.T) {
+	issue := &types.Issue{Description: "What broke", Design: "## Approach\nFix it", AcceptanceCriteria: "- [ ] Fixed"}
+	tmpl, err := Parse(strings.NewReader(Render(issue)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tmpl.Description != issue.Description || tmpl.Design != issue.Design || tmpl.AcceptanceCriteria != issue.AcceptanceCriteria {
+		t.Errorf("Round trip changed the sections: %+v", tmpl)
+	}
+}
+
+func TestLoadAndList(t *testing.T) {
+	root := t.TempDir()
+	if tmpl, err := Load(root, types.TypeBug); err != nil || tmpl == nil || !strings.Contains(tmpl.Description, "Steps to reproduce") || tmpl.Path != "" {
+		t.Fatalf("Expected the built-in bug template, got %+v, %v", tmpl, err)
+	}
+	if tmpl, err := Load(root, types.TypeTask); err != nil || tmpl != nil {
+		t.Fatalf("Expected no task template, got %+v, %v", tmpl, err)
+	}
+
+	dir := filepath.Join(root, Dir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"bug.md":   "## Impact\n\n# Acceptance Criteria\n\n- [ ] Regression test\n",
+		"chore.md": "# Notes\n",
+		"story.md": "As a user\n",
+	}
+	for name, text := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tmpl, err := Load(root, types.TypeBug)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tmpl.Description != "## Impact" || tmpl.AcceptanceCriteria != "- [ ] Regression test" || tmpl.Path == "" {
+		t.Errorf("Expected the file to replace the built-in template, got %+v", tmpl)
+	}
+	if _, err := Load(root, types.TypeChore); err == nil || !strings.Contains(err.Error(), "Notes") {
+		t.Errorf("Expected the unknown section named, got %v", err)
+	}
+
+	entries, err := List(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[types.IssueType]Entry{}
+	for _, entry := range entries {
+		got[entry.Type] = entry
+	}
+	if len(entries) != 4 || got["bug"].Path == "" || got["bug"].Err != nil || got["feature"].Path != "" ||
+		got["chore"].Err == nil || got["story"].Err == nil {
+		t.Errorf("Unexpected entries: %+v", entries)
+	}
+}
+
+func TestApply(t *testing.T) {
+	issue := &types.Issue{Description: "Given"}
+	Builtin(types.TypeFeature).Apply(issue)
+	if issue.Description != "Given" || !strings.Contains(issue.Design, "## Approach") || issue.AcceptanceCriteria == "" {
+		t.Errorf("Apply should only fill empty sections: %+v", issue)
+	}
+}
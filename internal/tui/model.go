@@ -0,0 +1,443 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// pane is one of the dashboard's lists
+type pane int
+
+const (
+	paneReady pane = iota
+	paneRunning
+	paneFeed
+	paneCount
+)
+
+// promptKind is the action a prompt line collects input for
+type promptKind int
+
+const (
+	promptNone promptKind = iota
+	promptClose
+	promptPriority
+	promptComment
+)
+
+// Labels of the prompts
+var promptLabels = map[promptKind]string{
+	promptClose:    "Close reason",
+	promptPriority: "Priority (0-4)",
+	promptComment:  "Comment",
+}
+
+// execution is an issue in progress and what its executor is doing
+type execution struct {
+	Issue *types.Issue
+	// State is nil if no executor claimed the issue (set in progress by hand)
+	State *types.IssueExecutionState
+}
+
+// detail is the issue detail view
+type detail struct {
+	Issue  *types.Issue
+	Labels []string
+	State  *types.IssueExecutionState
+	// Events are the issue's audit events, newest first
+	Events []*types.Event
+	// Scroll is the first line shown
+	Scroll int
+}
+
+// model is the dashboard state. Everything it shows comes from the last
+// successful read: a failed read (the executor holding a write lock, say)
+// only sets the status line.
+type model struct {
+	store Store
+	opts  Options
+
+	ready   []*types.Issue
+	running []*execution
+	// feed holds the latest agent events, oldest first, at most
+	// opts.FeedSize
+	feed   []*events.AgentEvent
+	paused bool
+
+	focus    pane
+	selected [paneCount]int
+
+	detail *detail
+
+	prompt      promptKind
+	promptIssue string
+	input       []rune
+
+	status     string
+	statusErr  bool
+	quit       bool
+	lastReload time.Time
+	// stale marks the queues for re-reading: an event arrived since the
+	// last reload, or an action changed an issue
+	stale bool
+	// detailStale marks the open detail view for re-reading
+	detailStale bool
+}
+
+// newModel returns a model over store; call reload to fill it
+func newModel(store Store, opts Options) *model {
+	return &model{store: store, opts: opts.withDefaults(), stale: true}
+}
+
+// reload re-reads the ready queue, the executions in progress and the
+// pause setting
+func (m *model) reload(ctx context.Context, now time.Time) {
+	m.lastReload = now
+	ready, err := m.store.GetReadyWork(ctx, types.WorkFilter{
+		Status:  types.StatusOpen,
+		Project: m.opts.Project,
+		Limit:   m.opts.QueueSize,
+	})
+	if err != nil {
+		m.setError("reading ready work", err)
+		return
+	}
+	running, err := m.loadRunning(ctx)
+	if err != nil {
+		m.setError("reading executions", err)
+		return
+	}
+	paused, err := m.store.GetConfig(ctx, pausedSetting)
+	if err != nil {
+		m.setError("reading "+pausedSetting, err)
+		return
+	}
+
+	m.ready, m.running = ready, running
+	m.paused, _ = strconv.ParseBool(paused) // Unset (or invalid) is not paused
+	m.stale = false
+	if m.statusErr {
+		m.status, m.statusErr = "", false
+	}
+	m.clampSelection()
+}
+
+// loadRunning reads the issues in progress with their execution state
+func (m *model) loadRunning(ctx context.Context) ([]*execution, error) {
+	status := types.StatusInProgress
+	issues, err := m.store.SearchIssues(ctx, "", types.IssueFilter{
+		Status:  &status,
+		Project: m.opts.Project,
+		Limit:   m.opts.QueueSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	running := make([]*execution, 0, len(issues))
+	for _, issue := range issues {
+		state, err := m.store.GetExecutionState(ctx, issue.ID)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", issue.ID, err)
+		}
+		running = append(running, &execution{Issue: issue, State: state})
+	}
+	return running, nil
+}
+
+// loadFeed fills the event feed with the most recent events
+func (m *model) loadFeed(ctx context.Context) {
+	recent, err := m.store.GetRecentAgentEvents(ctx, m.opts.FeedSize)
+	if err != nil {
+		m.setError("reading events", err)
+		return
+	}
+	m.feed = m.feed[:0]
+	for i := len(recent) - 1; i >= 0; i-- {
+		m.feed = append(m.feed, recent[i])
+	}
+}
+
+// openDetail loads the detail view of an issue
+func (m *model) openDetail(ctx context.Context, id string) {
+	d, err := m.loadDetail(ctx, id)
+	if err != nil {
+		m.setError("reading "+id, err)
+		return
+	}
+	m.detail = d
+	m.detailStale = false
+}
+
+// reloadDetail re-reads the open detail view, keeping its scroll position
+func (m *model) reloadDetail(ctx context.Context) {
+	if m.detail == nil {
+		return
+	}
+	d, err := m.loadDetail(ctx, m.detail.Issue.ID)
+	if err != nil {
+		m.setError("reading "+m.detail.Issue.ID, err)
+		return
+	}
+	d.Scroll = m.detail.Scroll
+	m.detail = d
+	m.detailStale = false
+}
+
+// loadDetail reads an issue with its labels, execution state and events
+func (m *model) loadDetail(ctx context.Context, id string) (*detail, error) {
+	issue, err := m.store.GetIssue(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if issue == nil {
+		return nil, fmt.Errorf("issue %s not found", id)
+	}
+	d := &detail{Issue: issue}
+	if d.Labels, err = m.store.GetLabels(ctx, id); err != nil {
+		return nil, err
+	}
+	if d.State, err = m.store.GetExecutionState(ctx, id); err != nil {
+		return nil, err
+	}
+	if d.Events, err = m.store.GetEvents(ctx, id, detailEventLimit); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// addEvent appends a live event to the feed. Events change what the queues
+// show (claims, completions, config changes), so the queues and the open
+// detail view are marked for re-reading rather than re-read per event.
+func (m *model) addEvent(event *events.AgentEvent) {
+	// The feed was loaded after subscribing, so it may already hold it
+	if n := len(m.feed); n > 0 && event.Cursor() != 0 && event.Cursor() <= m.feed[n-1].Cursor() {
+		return
+	}
+	atEnd := m.selected[paneFeed] >= len(m.feed)-1
+	m.feed = append(m.feed, event)
+	if over := len(m.feed) - m.opts.FeedSize; over > 0 {
+		m.feed = append(m.feed[:0], m.feed[over:]...)
+		m.selected[paneFeed] -= over
+	}
+	// Follow the feed unless the user scrolled back
+	if atEnd {
+		m.selected[paneFeed] = len(m.feed) - 1
+	}
+	m.clampSelection()
+	m.stale = true
+	if m.detail != nil && event.IssueID == m.detail.Issue.ID {
+		m.detailStale = true
+	}
+}
+
+// selectedIssueID is the issue of the selected row ("" if none)
+func (m *model) selectedIssueID() string {
+	i := m.selected[m.focus]
+	switch m.focus {
+	case paneReady:
+		if i < len(m.ready) {
+			return m.ready[i].ID
+		}
+	case paneRunning:
+		if i < len(m.running) {
+			return m.running[i].Issue.ID
+		}
+	case paneFeed:
+		if i < len(m.feed) && m.feed[i].IssueID != "SYSTEM" {
+			return m.feed[i].IssueID
+		}
+	}
+	return ""
+}
+
+// targetIssueID is the issue actions apply to: the detail view's, else the
+// selected row's
+func (m *model) targetIssueID() string {
+	if m.detail != nil {
+		return m.detail.Issue.ID
+	}
+	return m.selectedIssueID()
+}
+
+// paneLen is the number of rows of a pane
+func (m *model) paneLen(p pane) int {
+	switch p {
+	case paneReady:
+		return len(m.ready)
+	case paneRunning:
+		return len(m.running)
+	default:
+		return len(m.feed)
+	}
+}
+
+// clampSelection keeps every pane's selection on one of its rows
+func (m *model) clampSelection() {
+	for p := pane(0); p < paneCount; p++ {
+		n := m.paneLen(p)
+		if m.selected[p] >= n {
+			m.selected[p] = n - 1
+		}
+		if m.selected[p] < 0 {
+			m.selected[p] = 0
+		}
+	}
+}
+
+// handleKey applies a key press
+func (m *model) handleKey(ctx context.Context, k key) {
+	if k.code == keyCtrlC {
+		m.quit = true
+		return
+	}
+	if m.prompt != promptNone {
+		m.handlePromptKey(ctx, k)
+		return
+	}
+
+	switch {
+	case k.code == keyUp || k.r == 'k':
+		m.move(-1)
+	case k.code == keyDown || k.r == 'j':
+		m.move(1)
+	case k.code == keyPageUp:
+		m.move(-10)
+	case k.code == keyPageDown:
+		m.move(10)
+	case k.code == keyTab && m.detail == nil:
+		m.focus = (m.focus + 1) % paneCount
+	case k.code == keyBackTab && m.detail == nil:
+		m.focus = (m.focus + paneCount - 1) % paneCount
+	case k.code == keyEnter && m.detail == nil:
+		if id := m.selectedIssueID(); id != "" {
+			m.openDetail(ctx, id)
+		}
+	case k.code == keyEsc || k.r == 'q':
+		if m.detail != nil {
+			m.detail = nil
+		} else if k.r == 'q' {
+			m.quit = true
+		}
+	case k.r == 'c':
+		m.startPrompt(promptClose)
+	case k.r == 'p':
+		m.startPrompt(promptPriority)
+	case k.r == 'm':
+		m.startPrompt(promptComment)
+	case k.r == 'P':
+		m.togglePause(ctx)
+	case k.r == 'r':
+		m.stale = true
+		m.detailStale = m.detail != nil
+	}
+}
+
+// move moves the selection (or scrolls the detail view) by delta rows
+func (m *model) move(delta int) {
+	if m.detail != nil {
+		m.detail.Scroll = max(0, m.detail.Scroll+delta)
+		return
+	}
+	m.selected[m.focus] += delta
+	m.clampSelection()
+}
+
+// startPrompt asks for the input of an action on the target issue
+func (m *model) startPrompt(kind promptKind) {
+	id := m.targetIssueID()
+	if id == "" {
+		m.setStatus("No issue selected")
+		return
+	}
+	m.prompt, m.promptIssue, m.input = kind, id, nil
+}
+
+// handlePromptKey edits the prompt line, running its action on Enter
+func (m *model) handlePromptKey(ctx context.Context, k key) {
+	switch k.code {
+	case keyEsc:
+		m.prompt = promptNone
+	case keyBackspace:
+		if len(m.input) > 0 {
+			m.input = m.input[:len(m.input)-1]
+		}
+	case keyEnter:
+		kind, text := m.prompt, strings.TrimSpace(string(m.input))
+		m.prompt = promptNone
+		m.runPrompt(ctx, kind, text)
+	case keyNone:
+		if k.r != 0 {
+			m.input = append(m.input, k.r)
+		}
+	}
+}
+
+// runPrompt runs the action a prompt collected input for
+func (m *model) runPrompt(ctx context.Context, kind promptKind, text string) {
+	id := m.promptIssue
+	if text == "" {
+		m.setStatus("Canceled: empty input")
+		return
+	}
+
+	var err error
+	var done string
+	switch kind {
+	case promptClose:
+		err = m.store.CloseIssue(ctx, id, text, m.opts.Actor)
+		done = "Closed " + id
+	case promptPriority:
+		priority, convErr := strconv.Atoi(text)
+		if convErr != nil || priority < 0 || priority > 4 {
+			m.setStatus(fmt.Sprintf("Invalid priority %q: want 0-4", text))
+			return
+		}
+		err = m.store.UpdateIssue(ctx, id, map[string]interface{}{"priority": priority}, m.opts.Actor)
+		done = fmt.Sprintf("Set %s to P%d", id, priority)
+	case promptComment:
+		err = m.store.AddComment(ctx, id, m.opts.Actor, text)
+		done = "Commented on " + id
+	}
+	if err != nil {
+		m.setError(id, err)
+		return
+	}
+	m.setStatus(done)
+	m.stale = true
+	m.detailStale = m.detail != nil
+}
+
+// togglePause flips executor.paused. Executors read it every poll: paused
+// ones finish what they are running and claim nothing new.
+func (m *model) togglePause(ctx context.Context) {
+	value := "true"
+	if m.paused {
+		value = "false"
+	}
+	if err := m.store.SetConfig(ctx, pausedSetting, value); err != nil {
+		m.setError("setting "+pausedSetting, err)
+		return
+	}
+	m.paused = !m.paused
+	if m.paused {
+		m.setStatus("Executor paused: running work finishes, nothing new is claimed")
+	} else {
+		m.setStatus("Executor resumed")
+	}
+}
+
+// setStatus shows a message on the status line
+func (m *model) setStatus(message string) {
+	m.status, m.statusErr = message, false
+}
+
+// setError shows a failed read or action on the status line
+func (m *model) setError(what string, err error) {
+	m.status, m.statusErr = fmt.Sprintf("Error %s: %v", what, err), true
+}
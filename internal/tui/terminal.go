@@ -0,0 +1,181 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/chzyer/readline"
+)
+
+// Terminal is the dashboard's input, such as os.Stdin
+type Terminal interface {
+	io.Reader
+	Fd() uintptr
+}
+
+// ANSI escapes the dashboard writes
+const (
+	altScreenOn  = "\x1b[?1049h"
+	altScreenOff = "\x1b[?1049l"
+	cursorHide   = "\x1b[?25l"
+	cursorShow   = "\x1b[?25h"
+	cursorHome   = "\x1b[H"
+	clearLine    = "\x1b[K"
+	clearBelow   = "\x1b[J"
+	styleReset   = "\x1b[0m"
+	styleBold    = "\x1b[1m"
+	styleDim     = "\x1b[2m"
+	styleReverse = "\x1b[7m"
+	styleRed     = "\x1b[31m"
+	styleGreen   = "\x1b[32m"
+	styleYellow  = "\x1b[33m"
+	styleCyan    = "\x1b[36m"
+)
+
+// enterRaw puts the terminal in raw mode and returns the function that
+// restores it
+func enterRaw(t Terminal) (func(), error) {
+	fd := int(t.Fd())
+	if !readline.IsTerminal(fd) {
+		return nil, errors.New("input is not a terminal")
+	}
+	state, err := readline.MakeRaw(fd)
+	if err != nil {
+		return nil, err
+	}
+	return func() { _ = readline.Restore(fd, state) }, nil
+}
+
+// termSize returns the terminal's width and height, 80x24 if unknown
+func termSize(t Terminal) (int, int) {
+	width, height, err := readline.GetSize(int(t.Fd()))
+	if err != nil || width <= 0 || height <= 0 {
+		return 80, 24
+	}
+	return width, height
+}
+
+// draw writes a frame over the previous one
+func draw(out io.Writer, lines []string) {
+	var b strings.Builder
+	b.WriteString(cursorHome)
+	for i, line := range lines {
+		if i > 0 {
+			b.WriteString("\r\n")
+		}
+		b.WriteString(line)
+		b.WriteString(clearLine)
+	}
+	b.WriteString(clearBelow)
+	_, _ = io.WriteString(out, b.String())
+}
+
+// keyCode names the keys that aren't runes
+type keyCode int
+
+const (
+	keyNone keyCode = iota
+	keyUp
+	keyDown
+	keyPageUp
+	keyPageDown
+	keyTab
+	keyBackTab
+	keyEnter
+	keyEsc
+	keyBackspace
+	keyCtrlC
+)
+
+// key is a key press: a rune (code keyNone) or a special key
+type key struct {
+	r    rune
+	code keyCode
+}
+
+// escapeKeys maps the escape sequences of special keys
+var escapeKeys = map[string]keyCode{
+	"\x1b[A":  keyUp,
+	"\x1bOA":  keyUp,
+	"\x1b[B":  keyDown,
+	"\x1bOB":  keyDown,
+	"\x1b[5~": keyPageUp,
+	"\x1b[6~": keyPageDown,
+	"\x1b[Z":  keyBackTab,
+}
+
+// parseKeys splits what one read returned into key presses. A lone ESC is
+// the Esc key; unknown escape sequences are dropped.
+func parseKeys(buf []byte) []key {
+	var keys []key
+	for len(buf) > 0 {
+		switch b := buf[0]; {
+		case b == 0x1b:
+			n := escapeLen(buf)
+			if n == 1 {
+				keys = append(keys, key{code: keyEsc})
+			} else if code, ok := escapeKeys[string(buf[:n])]; ok {
+				keys = append(keys, key{code: code})
+			}
+			buf = buf[n:]
+			continue
+		case b == '\t':
+			keys = append(keys, key{code: keyTab})
+		case b == '\r' || b == '\n':
+			keys = append(keys, key{code: keyEnter})
+		case b == 0x7f || b == 0x08:
+			keys = append(keys, key{code: keyBackspace})
+		case b == 0x03:
+			keys = append(keys, key{code: keyCtrlC})
+		case b < 0x20:
+			// Other control keys do nothing
+		default:
+			r, size := utf8.DecodeRune(buf)
+			keys = append(keys, key{r: r})
+			buf = buf[size:]
+			continue
+		}
+		buf = buf[1:]
+	}
+	return keys
+}
+
+// escapeLen is the length of the escape sequence buf starts with: ESC [
+// or ESC O, parameters, and a final byte
+func escapeLen(buf []byte) int {
+	if len(buf) < 2 || (buf[1] != '[' && buf[1] != 'O') {
+		return 1
+	}
+	for i := 2; i < len(buf); i++ {
+		if buf[i] >= 0x40 && buf[i] <= 0x7e {
+			return i + 1
+		}
+	}
+	return len(buf)
+}
+
+// readKeys reads key presses until ctx is done or the input fails
+func readKeys(ctx context.Context, in io.Reader) <-chan key {
+	keys := make(chan key)
+	go func() {
+		defer close(keys)
+		buf := make([]byte, 256)
+		for {
+			n, err := in.Read(buf)
+			for _, k := range parseKeys(buf[:n]) {
+				select {
+				case keys <- k:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return keys
+}
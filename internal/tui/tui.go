@@ -0,0 +1,148 @@
+// Package tui is the terminal dashboard (vc tui): the ready queue, the
+// executions in progress with their state and elapsed time, a live feed of
+// agent events and an issue detail view, with keys to close, reprioritize
+// and comment on issues and to pause the executor.
+//
+// The executor writes the database while the dashboard reads it. Frames are
+// drawn from the last successful reads; new events arrive through
+// WatchAgentEvents and only mark the queues stale, which are then re-read at
+// most once per MinReload. A failed read keeps the previous data and shows
+// the error on the status line.
+package tui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// pausedSetting is the config key the executor re-reads every poll; while
+// it is true no new work is claimed
+const pausedSetting = "executor.paused"
+
+// detailEventLimit is how many audit events the detail view shows
+const detailEventLimit = 50
+
+// Store is the part of the storage the dashboard uses
+type Store interface {
+	GetReadyWork(ctx context.Context, filter types.WorkFilter) ([]*types.Issue, error)
+	SearchIssues(ctx context.Context, query string, filter types.IssueFilter) ([]*types.Issue, error)
+	GetIssue(ctx context.Context, id string) (*types.Issue, error)
+	GetLabels(ctx context.Context, issueID string) ([]string, error)
+	GetExecutionState(ctx context.Context, issueID string) (*types.IssueExecutionState, error)
+	GetEvents(ctx context.Context, issueID string, limit int) ([]*types.Event, error)
+	GetRecentAgentEvents(ctx context.Context, limit int) ([]*events.AgentEvent, error)
+	WatchAgentEvents(ctx context.Context, filter events.EventFilter) (<-chan *events.AgentEvent, error)
+	UpdateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string) error
+	CloseIssue(ctx context.Context, id string, reason string, actor string) error
+	AddComment(ctx context.Context, issueID, actor, comment string) error
+	GetConfig(ctx context.Context, key string) (string, error)
+	SetConfig(ctx context.Context, key, value string) error
+}
+
+// Options configure the dashboard
+type Options struct {
+	// Actor is recorded on closes, priority changes and comments
+	Actor string
+	// Project limits the queues to one project ("" for every project)
+	Project string
+	// QueueSize is the most issues each queue shows (default: 100)
+	QueueSize int
+	// FeedSize is the most events the feed keeps (default: 500)
+	FeedSize int
+	// MinReload is the shortest time between two reads of the queues
+	// (default: 1s)
+	MinReload time.Duration
+	// MaxReload re-reads the queues even when no event arrived, to pick up
+	// changes that record none, such as issues created by another process
+	// (default: 15s)
+	MaxReload time.Duration
+}
+
+// withDefaults fills in the unset options
+func (o Options) withDefaults() Options {
+	if o.QueueSize <= 0 {
+		o.QueueSize = 100
+	}
+	if o.FeedSize <= 0 {
+		o.FeedSize = 500
+	}
+	if o.MinReload <= 0 {
+		o.MinReload = time.Second
+	}
+	if o.MaxReload <= 0 {
+		o.MaxReload = 15 * time.Second
+	}
+	return o
+}
+
+// frameInterval is how often the clock and elapsed times are redrawn
+const frameInterval = time.Second
+
+// Run shows the dashboard on the terminal until the user quits or ctx is
+// done. in must be a terminal; it is put in raw mode and restored on return.
+func Run(ctx context.Context, store Store, in Terminal, out io.Writer, opts Options) error {
+	restore, err := enterRaw(in)
+	if err != nil {
+		return fmt.Errorf("tui needs a terminal: %w", err)
+	}
+	defer restore()
+	fmt.Fprint(out, altScreenOn+cursorHide)
+	defer fmt.Fprint(out, cursorShow+altScreenOff)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Subscribe before loading the feed so no event falls in between;
+	// addEvent drops the ones loaded twice
+	feed, err := store.WatchAgentEvents(ctx, events.EventFilter{})
+	if err != nil {
+		return fmt.Errorf("failed to watch agent events: %w", err)
+	}
+	keys := readKeys(ctx, in)
+
+	m := newModel(store, opts)
+	m.loadFeed(ctx)
+	m.reload(ctx, time.Now())
+
+	frames := time.NewTicker(frameInterval)
+	defer frames.Stop()
+
+	for {
+		now := time.Now()
+		if m.detailStale {
+			m.reloadDetail(ctx)
+		}
+		if (m.stale && now.Sub(m.lastReload) >= m.opts.MinReload) || now.Sub(m.lastReload) >= m.opts.MaxReload {
+			m.reload(ctx, now)
+		}
+		width, height := termSize(in)
+		draw(out, m.render(width, height, now))
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-feed:
+			if !ok {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return fmt.Errorf("agent event feed closed")
+			}
+			m.addEvent(event)
+		case k, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			m.handleKey(ctx, k)
+			if m.quit {
+				return nil
+			}
+		case <-frames.C:
+		}
+	}
+}
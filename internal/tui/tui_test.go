@@ -0,0 +1,267 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage/storagetest"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// seed creates two open issues and a third claimed by an executor
+func seed(t *testing.T) (*storagetest.FakeStorage, *model) {
+	t.Helper()
+	ctx := context.Background()
+	store := storagetest.NewFakeStorage()
+	for i, title := range []string{"Parser crash", "Lexer cleanup", "Flaky test"} {
+		issue := &types.Issue{Title: title, Status: types.StatusOpen, Priority: i, IssueType: types.TypeBug}
+		if err := store.CreateIssue(ctx, issue, "test"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.ClaimIssue(ctx, "vc-3", "exec-1"); err != nil {
+		t.Fatal(err)
+	}
+	m := newModel(store, Options{Actor: "tester"})
+	m.reload(ctx, time.Now())
+	return store, m
+}
+
+// press sends the keys typed as s to the model
+func press(m *model, s string) {
+	for _, k := range parseKeys([]byte(s)) {
+		m.handleKey(context.Background(), k)
+	}
+}
+
+func TestReloadQueues(t *testing.T) {
+	_, m := seed(t)
+	if m.statusErr {
+		t.Fatalf("Unexpected error: %s", m.status)
+	}
+	if len(m.ready) != 2 || m.ready[0].ID != "vc-1" {
+		t.Errorf("Expected vc-1 and vc-2 ready, got %v", m.ready)
+	}
+	if len(m.running) != 1 || m.running[0].Issue.ID != "vc-3" {
+		t.Fatalf("Expected vc-3 in progress, got %v", m.running)
+	}
+	if m.running[0].State == nil || m.running[0].State.State != types.ExecutionStateClaimed {
+		t.Errorf("Expected vc-3's claimed execution state, got %+v", m.running[0].State)
+	}
+	if m.stale {
+		t.Error("Reload should clear stale")
+	}
+}
+
+// TestReloadErrorKeepsData verifies a failed read (as when the executor
+// holds a write lock) keeps the last data and shows the error
+func TestReloadErrorKeepsData(t *testing.T) {
+	store, m := seed(t)
+	store.FailOn("SearchIssues", errors.New("database is locked"))
+	m.stale = true
+	m.reload(context.Background(), time.Now())
+
+	if !m.statusErr || !strings.Contains(m.status, "database is locked") {
+		t.Errorf("Expected the error on the status line, got %q", m.status)
+	}
+	if len(m.ready) != 2 || len(m.running) != 1 {
+		t.Errorf("Failed reload replaced the queues: %d ready, %d running", len(m.ready), len(m.running))
+	}
+	if !m.stale {
+		t.Error("Failed reload should stay stale so it is retried")
+	}
+
+	store.FailOn("SearchIssues", nil)
+	m.reload(context.Background(), time.Now())
+	if m.statusErr || m.status != "" {
+		t.Errorf("Successful reload should clear the error, got %q", m.status)
+	}
+}
+
+func TestNavigationAndDetail(t *testing.T) {
+	_, m := seed(t)
+	press(m, "j")
+	if id := m.selectedIssueID(); id != "vc-2" {
+		t.Errorf("Expected vc-2 selected after j, got %q", id)
+	}
+	press(m, "jjj")
+	if id := m.selectedIssueID(); id != "vc-2" {
+		t.Errorf("Selection should stop at the last row, got %q", id)
+	}
+
+	press(m, "\t")
+	if m.focus != paneRunning || m.selectedIssueID() != "vc-3" {
+		t.Fatalf("Expected vc-3 selected in the executions pane, got pane %d %q", m.focus, m.selectedIssueID())
+	}
+	press(m, "\r")
+	if m.detail == nil || m.detail.Issue.ID != "vc-3" {
+		t.Fatalf("Expected vc-3's detail view, got %+v", m.detail)
+	}
+	if m.detail.State == nil || m.detail.State.ExecutorInstanceID != "exec-1" {
+		t.Errorf("Detail view lacks the execution state: %+v", m.detail.State)
+	}
+
+	press(m, "\x1b")
+	if m.detail != nil || m.quit {
+		t.Error("Esc should close the detail view without quitting")
+	}
+	press(m, "q")
+	if !m.quit {
+		t.Error("q should quit")
+	}
+}
+
+func TestActions(t *testing.T) {
+	store, m := seed(t)
+	ctx := context.Background()
+
+	press(m, "p1\r")
+	if issue, _ := store.GetIssue(ctx, "vc-1"); issue.Priority != 1 {
+		t.Errorf("Expected vc-1 at P1, got P%d (status %q)", issue.Priority, m.status)
+	}
+	press(m, "p9\r")
+	if !strings.Contains(m.status, "Invalid priority") {
+		t.Errorf("Expected an invalid priority message, got %q", m.status)
+	}
+
+	press(m, "mLooks good\r")
+	evts, err := store.GetEvents(ctx, "vc-1", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, event := range evts {
+		if event.EventType == types.EventCommented && event.Actor == "tester" && event.Comment != nil && *event.Comment == "Looks good" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Comment not recorded: %+v", evts)
+	}
+
+	press(m, "cDone\x7f\x7f\x7f\x7fduplicate\r")
+	if issue, _ := store.GetIssue(ctx, "vc-1"); issue.Status != types.StatusClosed {
+		t.Errorf("Expected vc-1 closed, got %s (status %q)", issue.Status, m.status)
+	}
+	if !m.stale {
+		t.Error("Actions should mark the queues stale")
+	}
+
+	// Esc cancels a prompt without acting
+	press(m, "j")
+	press(m, "cnope\x1b")
+	if issue, _ := store.GetIssue(ctx, "vc-2"); issue.Status != types.StatusOpen {
+		t.Errorf("Canceled prompt closed vc-2")
+	}
+}
+
+func TestTogglePause(t *testing.T) {
+	store, m := seed(t)
+	ctx := context.Background()
+
+	press(m, "P")
+	if value, _ := store.GetConfig(ctx, pausedSetting); value != "true" || !m.paused {
+		t.Fatalf("Expected %s=true, got %q (paused %v)", pausedSetting, value, m.paused)
+	}
+	press(m, "P")
+	if value, _ := store.GetConfig(ctx, pausedSetting); value != "false" || m.paused {
+		t.Errorf("Expected %s=false, got %q (paused %v)", pausedSetting, value, m.paused)
+	}
+
+	// A pause set elsewhere shows after the next reload
+	if err := store.SetConfig(ctx, pausedSetting, "true"); err != nil {
+		t.Fatal(err)
+	}
+	m.reload(ctx, time.Now())
+	if !m.paused {
+		t.Error("Reload should pick up the stored pause")
+	}
+}
+
+func TestAddEvent(t *testing.T) {
+	_, m := seed(t)
+	m.opts.FeedSize = 3
+	for i := 1; i <= 4; i++ {
+		m.addEvent(&events.AgentEvent{ID: strconv.Itoa(i), IssueID: "vc-1", Type: events.EventTypeProgress, Timestamp: time.Now()})
+	}
+	if len(m.feed) != 3 || m.feed[0].ID != "2" || m.feed[2].ID != "4" {
+		t.Fatalf("Expected events 2-4 in the feed, got %d", len(m.feed))
+	}
+	if m.selected[paneFeed] != 2 {
+		t.Errorf("Feed should follow the newest event, selected %d", m.selected[paneFeed])
+	}
+	if !m.stale {
+		t.Error("Events should mark the queues stale")
+	}
+
+	// Events loaded with the feed and delivered again are dropped
+	m.addEvent(&events.AgentEvent{ID: "3", IssueID: "vc-1"})
+	if len(m.feed) != 3 || m.feed[2].ID != "4" {
+		t.Error("Duplicate event added to the feed")
+	}
+
+	m.openDetail(context.Background(), "vc-2")
+	m.addEvent(&events.AgentEvent{ID: "5", IssueID: "vc-2"})
+	if !m.detailStale {
+		t.Error("An event for the open issue should mark the detail view stale")
+	}
+}
+
+func TestRender(t *testing.T) {
+	_, m := seed(t)
+	now := time.Now().Add(3*time.Minute + 12*time.Second)
+	lines := m.render(60, 20, now)
+	if len(lines) != 20 {
+		t.Fatalf("Expected 20 lines, got %d", len(lines))
+	}
+	screen := strings.Join(lines, "\n")
+	for _, want := range []string{"ready: 2", "in progress: 1", "Parser crash", "vc-3", "claimed", "3m1"} {
+		if !strings.Contains(screen, want) {
+			t.Errorf("Screen lacks %q:\n%s", want, screen)
+		}
+	}
+	for i, line := range lines {
+		if n := visibleLen(line); n > 60 {
+			t.Errorf("Line %d is %d columns wide: %q", i, n, line)
+		}
+	}
+
+	press(m, "P")
+	if screen := strings.Join(m.render(60, 20, now), "\n"); !strings.Contains(screen, "PAUSED") {
+		t.Error("Header should show the pause")
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	got := parseKeys([]byte("a\x1b[A\x1b[B\x1b\t\r\x7f\x03é\x1b[Z\x1b[5~"))
+	want := []key{
+		{r: 'a'}, {code: keyUp}, {code: keyDown}, {code: keyEsc}, {code: keyTab}, {code: keyEnter},
+		{code: keyBackspace}, {code: keyCtrlC}, {r: 'é'}, {code: keyBackTab}, {code: keyPageUp},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d keys, got %v", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Key %d: expected %+v, got %+v", i, want[i], got[i])
+		}
+	}
+}
+
+func TestFormatElapsed(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		-time.Second:                   "0s",
+		45 * time.Second:               "45s",
+		3*time.Minute + 12*time.Second: "3m12s",
+		2*time.Hour + 5*time.Minute:    "2h05m",
+	} {
+		if got := formatElapsed(d); got != want {
+			t.Errorf("formatElapsed(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -0,0 +1,326 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/types"
+)
+
+// Key help shown on the last line
+const (
+	helpPanes  = "tab pane  ↑↓/jk move  enter details  c close  p priority  m comment  P pause/resume  r refresh  q quit"
+	helpDetail = "↑↓/jk scroll  c close  p priority  m comment  P pause/resume  r refresh  esc back"
+	helpPrompt = "enter confirm  esc cancel"
+)
+
+// render draws the dashboard as height lines of at most width columns
+func (m *model) render(width, height int, now time.Time) []string {
+	lines := []string{m.renderHeader(width, now)}
+	body := height - 3 // header, status and help lines
+	if m.detail != nil {
+		lines = append(lines, m.renderDetail(width, body, now)...)
+	} else {
+		lines = append(lines, m.renderPanes(width, body, now)...)
+	}
+	for len(lines) < height-2 {
+		lines = append(lines, "")
+	}
+	lines = append(lines, m.renderStatus(width))
+
+	help := helpPanes
+	switch {
+	case m.prompt != promptNone:
+		help = helpPrompt
+	case m.detail != nil:
+		help = helpDetail
+	}
+	return append(lines, styleDim+truncate(help, width)+styleReset)
+}
+
+// renderHeader is the top line: the executor's state, the queue sizes and
+// the time
+func (m *model) renderHeader(width int, now time.Time) string {
+	executor := styleGreen + "running" + styleReset
+	if m.paused {
+		executor = styleYellow + "PAUSED" + styleReset
+	}
+	left := fmt.Sprintf("%svc tui%s  executor: %s  ready: %d  in progress: %d",
+		styleBold, styleReset, executor, len(m.ready), len(m.running))
+	clock := now.Format("15:04:05")
+	if pad := width - visibleLen(left) - len(clock); pad > 0 {
+		return left + strings.Repeat(" ", pad) + clock
+	}
+	return left
+}
+
+// renderStatus is the prompt line while a prompt is open, else the last
+// message
+func (m *model) renderStatus(width int) string {
+	if m.prompt != promptNone {
+		return truncateLeft(fmt.Sprintf("%s %s: %s_", m.promptIssue, promptLabels[m.prompt], string(m.input)), width)
+	}
+	if m.statusErr {
+		return styleRed + truncate(m.status, width) + styleReset
+	}
+	return truncate(m.status, width)
+}
+
+// renderPanes stacks the ready queue, the executions and the event feed in
+// rows lines
+func (m *model) renderPanes(width, rows int, now time.Time) []string {
+	running := min(max(len(m.running), 1)+1, rows/4)
+	ready := (rows - running) / 2
+	feed := rows - running - ready
+
+	var lines []string
+	lines = append(lines, m.renderPane(paneReady, "Ready", width, ready, func(i int) string {
+		issue := m.ready[i]
+		return fmt.Sprintf("P%d %-10s %-8s %s", issue.Priority, issue.ID, issue.IssueType, issue.Title)
+	})...)
+	lines = append(lines, m.renderPane(paneRunning, "In progress", width, running, func(i int) string {
+		return formatExecution(m.running[i], now)
+	})...)
+	lines = append(lines, m.renderPane(paneFeed, "Events", width, feed, func(i int) string {
+		return formatEvent(m.feed[i])
+	})...)
+	return lines
+}
+
+// renderPane draws a titled list in rows lines, scrolled so the selected
+// row shows
+func (m *model) renderPane(p pane, title string, width, rows int, row func(int) string) []string {
+	if rows <= 0 {
+		return nil
+	}
+	n := m.paneLen(p)
+	heading := fmt.Sprintf("%s (%d)", title, n)
+	if p == m.focus {
+		heading = styleBold + styleCyan + "▸ " + heading + styleReset
+	} else {
+		heading = styleBold + "  " + heading + styleReset
+	}
+	lines := []string{heading}
+
+	visible := rows - 1
+	first := 0
+	if sel := m.selected[p]; sel >= visible {
+		first = sel - visible + 1
+	}
+	for i := first; i < n && i < first+visible; i++ {
+		text := "  " + truncate(row(i), width-2)
+		if p == m.focus && i == m.selected[p] {
+			text = styleReverse + text + styleReset
+		}
+		lines = append(lines, text)
+	}
+	for len(lines) < rows {
+		lines = append(lines, "")
+	}
+	return lines
+}
+
+// formatExecution is a row of the executions pane: the issue, what its
+// executor is doing and for how long
+func formatExecution(e *execution, now time.Time) string {
+	state, elapsed := executionStatus(e.State, now)
+	return fmt.Sprintf("%-10s %-10s %8s  %s", e.Issue.ID, state, elapsed, e.Issue.Title)
+}
+
+// executionStatus is an execution's state and the time since it started
+// (or was claimed); "unclaimed" and "" without one
+func executionStatus(state *types.IssueExecutionState, now time.Time) (string, string) {
+	if state == nil {
+		return "unclaimed", ""
+	}
+	since := state.StartedAt
+	if since.IsZero() {
+		since = state.ClaimedAt
+	}
+	if since.IsZero() {
+		return string(state.State), ""
+	}
+	return string(state.State), formatElapsed(now.Sub(since))
+}
+
+// formatEvent is a row of the event feed
+func formatEvent(event *events.AgentEvent) string {
+	marker := " "
+	switch event.Severity {
+	case events.SeverityWarning:
+		marker = "!"
+	case events.SeverityError, events.SeverityCritical:
+		marker = "✗"
+	}
+	message := strings.Join(strings.Fields(event.Message), " ")
+	return fmt.Sprintf("%s %s %-10s %s: %s", marker, event.Timestamp.Format("15:04:05"), event.IssueID, event.Type, message)
+}
+
+// formatElapsed renders a duration as 45s, 3m12s or 2h05m
+func formatElapsed(d time.Duration) string {
+	d = max(d, 0).Truncate(time.Second)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+	default:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+}
+
+// renderDetail draws the detail view in rows lines from its scroll position
+func (m *model) renderDetail(width, rows int, now time.Time) []string {
+	if rows <= 0 {
+		return nil
+	}
+	d := m.detail
+	issue := d.Issue
+	text := []string{
+		styleBold + truncate(issue.ID+"  "+issue.Title, width) + styleReset,
+		fmt.Sprintf("Status: %s  Priority: P%d  Type: %s  Assignee: %s", issue.Status, issue.Priority, issue.IssueType, orNone(issue.Assignee)),
+		"Labels: " + orNone(strings.Join(d.Labels, ", ")),
+	}
+	running := "none"
+	if d.State != nil {
+		state, elapsed := executionStatus(d.State, now)
+		running = fmt.Sprintf("%s by %s", state, d.State.ExecutorInstanceID)
+		if elapsed != "" {
+			running += " for " + elapsed
+		}
+	}
+	text = append(text, "Execution: "+running, "")
+	for _, section := range []struct{ name, body string }{
+		{"Description", issue.Description},
+		{"Design", issue.Design},
+		{"Acceptance criteria", issue.AcceptanceCriteria},
+		{"Notes", issue.Notes},
+	} {
+		if section.body == "" {
+			continue
+		}
+		text = append(text, styleBold+section.name+styleReset)
+		text = append(text, wrap(section.body, width)...)
+		text = append(text, "")
+	}
+	text = append(text, styleBold+"History"+styleReset)
+	for _, event := range d.Events {
+		text = append(text, truncate(formatIssueEvent(event), width))
+	}
+
+	d.Scroll = min(d.Scroll, max(len(text)-rows, 0))
+	end := min(d.Scroll+rows, len(text))
+	lines := make([]string, 0, rows)
+	for _, line := range text[d.Scroll:end] {
+		lines = append(lines, truncateStyled(line, width))
+	}
+	return lines
+}
+
+// formatIssueEvent is a line of the detail view's history
+func formatIssueEvent(event *types.Event) string {
+	line := fmt.Sprintf("%s %s %s", event.CreatedAt.Format("2006-01-02 15:04"), event.Actor, event.EventType)
+	switch {
+	case event.Comment != nil:
+		line += ": " + strings.Join(strings.Fields(*event.Comment), " ")
+	case event.NewValue != nil && event.OldValue != nil:
+		line += fmt.Sprintf(": %s → %s", *event.OldValue, *event.NewValue)
+	}
+	return line
+}
+
+// orNone returns s, or "-" if it's empty
+func orNone(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// wrap breaks text into lines of at most width columns at spaces
+func wrap(text string, width int) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			switch {
+			case line == "":
+				line = word
+			case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= width:
+				line += " " + word
+			default:
+				lines = append(lines, line)
+				line = word
+			}
+		}
+		lines = append(lines, truncate(line, width))
+	}
+	return lines
+}
+
+// truncate cuts plain text to width columns, ending in … when cut
+func truncate(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:width-1]) + "…"
+}
+
+// truncateLeft keeps the last width columns of plain text, so a long prompt
+// shows what is being typed
+func truncateLeft(s string, width int) string {
+	runes := []rune(s)
+	if width <= 0 || len(runes) <= width {
+		return s
+	}
+	return "…" + string(runes[len(runes)-width+1:])
+}
+
+// truncateStyled cuts a line that may hold style escapes to width visible
+// columns
+func truncateStyled(s string, width int) string {
+	if visibleLen(s) <= width {
+		return s
+	}
+	var b strings.Builder
+	n := 0
+	for i := 0; i < len(s); {
+		if s[i] == 0x1b {
+			j := i + escapeLen([]byte(s[i:]))
+			b.WriteString(s[i:j])
+			i = j
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if n == width-1 {
+			b.WriteString("…" + styleReset)
+			break
+		}
+		b.WriteRune(r)
+		n++
+		i += size
+	}
+	return b.String()
+}
+
+// visibleLen counts the columns of s, skipping style escapes
+func visibleLen(s string) int {
+	n := 0
+	for i := 0; i < len(s); {
+		if s[i] == 0x1b {
+			i += escapeLen([]byte(s[i:]))
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(s[i:])
+		n++
+		i += size
+	}
+	return n
+}
@@ -0,0 +1,94 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Assessment is the AI supervisor's plan for an issue, made before an
+// execution attempt
+type Assessment struct {
+	Strategy   string   `json:"strategy"`   // High-level strategy for completing the issue
+	Steps      []string `json:"steps"`      // Specific steps to take
+	Risks      []string `json:"risks"`      // Potential risks or challenges
+	Confidence float64  `json:"confidence"` // Confidence score (0.0-1.0)
+	Reasoning  string   `json:"reasoning"`  // Detailed reasoning
+}
+
+// AssessmentRecord is an Assessment stored for one execution attempt,
+// with the steps agents have reported done
+type AssessmentRecord struct {
+	IssueID    string     `json:"issue_id"`
+	Attempt    int        `json:"attempt"` // Execution attempt number (ExecutionAttempt.AttemptNumber)
+	Assessment Assessment `json:"assessment"`
+	// CompletedSteps are indexes into Assessment.Steps, ascending
+	CompletedSteps []int     `json:"completed_steps,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// Validate checks the record can be stored
+func (r *AssessmentRecord) Validate() error {
+	if r.IssueID == "" {
+		return fmt.Errorf("issue_id is required")
+	}
+	if r.Attempt < 0 {
+		return fmt.Errorf("attempt must not be negative (got %d)", r.Attempt)
+	}
+	for _, i := range r.CompletedSteps {
+		if i < 0 || i >= len(r.Assessment.Steps) {
+			return fmt.Errorf("completed step %d out of range (%d steps)", i, len(r.Assessment.Steps))
+		}
+	}
+	return nil
+}
+
+// StepCompleted reports whether step i has been reported done
+func (r *AssessmentRecord) StepCompleted(i int) bool {
+	for _, done := range r.CompletedSteps {
+		if done == i {
+			return true
+		}
+	}
+	return false
+}
+
+// MarkCompleted marks the steps matching any of the items an agent
+// reported done, ignoring case and surrounding whitespace; an item matches
+// a step that contains it or that it contains. Returns how many steps were
+// newly marked.
+func (r *AssessmentRecord) MarkCompleted(items []string) int {
+	marked := 0
+	for i, step := range r.Assessment.Steps {
+		if r.StepCompleted(i) {
+			continue
+		}
+		step = strings.ToLower(strings.TrimSpace(step))
+		if step == "" {
+			continue
+		}
+		for _, item := range items {
+			item = strings.ToLower(strings.TrimSpace(item))
+			if item != "" && (strings.Contains(step, item) || strings.Contains(item, step)) {
+				r.CompletedSteps = append(r.CompletedSteps, i)
+				marked++
+				break
+			}
+		}
+	}
+	sort.Ints(r.CompletedSteps)
+	return marked
+}
+
+// CompletedStepTexts returns the text of the completed steps
+func (r *AssessmentRecord) CompletedStepTexts() []string {
+	var texts []string
+	for _, i := range r.CompletedSteps {
+		if i >= 0 && i < len(r.Assessment.Steps) {
+			texts = append(texts, r.Assessment.Steps[i])
+		}
+	}
+	return texts
+}
@@ -0,0 +1,53 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAssessmentRecordMarkCompleted(t *testing.T) {
+	r := &AssessmentRecord{
+		IssueID: "vc-1",
+		Assessment: Assessment{
+			Steps: []string{"Add a failing test", "Fix the parser", "Update docs"},
+		},
+	}
+	if n := r.MarkCompleted([]string{"  fix the PARSER ", "unrelated"}); n != 1 {
+		t.Errorf("Expected 1 step marked, got %d", n)
+	}
+	// An item containing the step text matches too; marked steps aren't counted twice
+	if n := r.MarkCompleted([]string{"Add a failing test, then fix the parser"}); n != 1 {
+		t.Errorf("Expected 1 newly marked step, got %d", n)
+	}
+	if n := r.MarkCompleted([]string{"Add a failing test"}); n != 0 {
+		t.Errorf("Expected no newly marked steps, got %d", n)
+	}
+	if !reflect.DeepEqual(r.CompletedSteps, []int{0, 1}) {
+		t.Errorf("Unexpected completed steps: %v", r.CompletedSteps)
+	}
+	if !r.StepCompleted(0) || r.StepCompleted(2) {
+		t.Errorf("StepCompleted disagrees with %v", r.CompletedSteps)
+	}
+	if got := r.CompletedStepTexts(); !reflect.DeepEqual(got, []string{"Add a failing test", "Fix the parser"}) {
+		t.Errorf("Unexpected completed step texts: %v", got)
+	}
+	if err := r.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+}
+
+func TestAssessmentRecordValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		record AssessmentRecord
+	}{
+		{"missing issue", AssessmentRecord{}},
+		{"negative attempt", AssessmentRecord{IssueID: "vc-1", Attempt: -1}},
+		{"step out of range", AssessmentRecord{IssueID: "vc-1", CompletedSteps: []int{0}}},
+	}
+	for _, tt := range tests {
+		if err := tt.record.Validate(); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// CheckpointVersion is the checkpoint format MarshalCheckpoint writes. Bump
+// it (and add a CheckpointV<n>) whenever the shape changes; older blobs are
+// then reported as ErrCheckpointVersionMismatch instead of misread.
+const CheckpointVersion = 1
+
+// ErrCheckpointVersionMismatch is returned when stored checkpoint data can't
+// be interpreted as the current version: written by another release, from
+// before checkpoints were versioned (v0), or not valid JSON. The checkpoint
+// should be discarded and the execution started fresh.
+var ErrCheckpointVersionMismatch = errors.New("checkpoint version mismatch")
+
+// CheckpointV1 is the resumable state an executor saves for the issue it is
+// working on (IssueExecutionState.CheckpointData holds it encoded)
+type CheckpointV1 struct {
+	// Phase is the execution state the checkpoint was taken in
+	Phase       ExecutionState `json:"phase"`
+	SandboxPath string         `json:"sandbox_path,omitempty"`
+	Branch      string         `json:"branch,omitempty"`
+	AgentID     string         `json:"agent_id,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	// Extras holds component-specific state (e.g. a git.RebaseCheckpoint)
+	Extras map[string]interface{} `json:"extras,omitempty"`
+}
+
+// versionedCheckpointV1 is the stored form of a CheckpointV1
+type versionedCheckpointV1 struct {
+	Version int `json:"version"`
+	CheckpointV1
+}
+
+// MarshalCheckpoint encodes a checkpoint with its format version
+func MarshalCheckpoint(cp *CheckpointV1) (string, error) {
+	data, err := json.Marshal(versionedCheckpointV1{Version: CheckpointVersion, CheckpointV1: *cp})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal checkpoint: %w", err)
+	}
+	return string(data), nil
+}
+
+// UnmarshalCheckpoint decodes checkpoint data written by MarshalCheckpoint.
+// Data it can't interpret, including unversioned JSON from before
+// checkpoints had a version (treated as v0), yields an error wrapping
+// ErrCheckpointVersionMismatch.
+func UnmarshalCheckpoint(data string) (*CheckpointV1, error) {
+	var stored struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal([]byte(data), &stored); err != nil {
+		return nil, fmt.Errorf("%w: unreadable checkpoint: %v", ErrCheckpointVersionMismatch, err)
+	}
+	if stored.Version != CheckpointVersion {
+		return nil, fmt.Errorf("%w: got version %d, want %d", ErrCheckpointVersionMismatch, stored.Version, CheckpointVersion)
+	}
+
+	var cp versionedCheckpointV1
+	if err := json.Unmarshal([]byte(data), &cp); err != nil {
+		return nil, fmt.Errorf("%w: malformed version %d checkpoint: %v", ErrCheckpointVersionMismatch, CheckpointVersion, err)
+	}
+	return &cp.CheckpointV1, nil
+}
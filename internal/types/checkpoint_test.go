@@ -0,0 +1,58 @@
+package types
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCheckpointRoundTrip(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	cp := &CheckpointV1{
+		Phase:       ExecutionStateExecuting,
+		SandboxPath: "/tmp/sandbox",
+		Branch:      "mission/vc-1",
+		AgentID:     "agent-1",
+		CreatedAt:   now.Add(-time.Minute),
+		UpdatedAt:   now,
+		Extras:      map[string]interface{}{"step": float64(2)},
+	}
+
+	data, err := MarshalCheckpoint(cp)
+	if err != nil {
+		t.Fatalf("MarshalCheckpoint() error = %v", err)
+	}
+	got, err := UnmarshalCheckpoint(data)
+	if err != nil {
+		t.Fatalf("UnmarshalCheckpoint(%s) error = %v", data, err)
+	}
+	if got.Phase != cp.Phase || got.SandboxPath != cp.SandboxPath || got.Branch != cp.Branch || got.AgentID != cp.AgentID ||
+		!got.CreatedAt.Equal(cp.CreatedAt) || !got.UpdatedAt.Equal(cp.UpdatedAt) || got.Extras["step"] != float64(2) {
+		t.Errorf("UnmarshalCheckpoint() = %+v, want %+v", got, cp)
+	}
+}
+
+func TestUnmarshalCheckpointVersionMismatch(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"unversioned (v0)", `{"step":2,"completed":["setup"]}`},
+		{"newer version", `{"version":2,"phase":"executing"}`},
+		{"explicit v0", `{"version":0,"phase":"executing"}`},
+		{"not JSON", `step 2`},
+		{"not an object", `[1,2]`},
+		{"wrong field type", `{"version":1,"created_at":"yesterday"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cp, err := UnmarshalCheckpoint(tt.data)
+			if !errors.Is(err, ErrCheckpointVersionMismatch) {
+				t.Errorf("UnmarshalCheckpoint(%s) error = %v, want ErrCheckpointVersionMismatch", tt.data, err)
+			}
+			if cp != nil {
+				t.Errorf("UnmarshalCheckpoint(%s) = %+v, want nil", tt.data, cp)
+			}
+		})
+	}
+}
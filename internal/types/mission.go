@@ -2,7 +2,9 @@ package types
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"time"
 )
 
@@ -23,7 +25,25 @@ type Mission struct {
 	SandboxPath string `json:"sandbox_path,omitempty"`    // Path to sandbox directory for this mission
 	BranchName  string `json:"branch_name,omitempty"`     // Git branch for this mission's work
 	IterationCount int `json:"iteration_count"`           // Number of execution iterations
-	GatesStatus string `json:"gates_status,omitempty"`    // Quality gates status (passed/failed)
+	GatesStatus string `json:"gates_status,omitempty"`    // Quality gates status (see GatesStatus* constants)
+	LastGatesRun *time.Time `json:"last_gates_run,omitempty"` // When quality gates last ran for this mission
+}
+
+// Quality gates status of a mission (vc_mission_state.gates_status)
+const (
+	GatesStatusPending = "pending" // Gates are waiting for a QA worker
+	GatesStatusRunning = "running"
+	GatesStatusPassed  = "passed"
+	GatesStatusFailed  = "failed"
+)
+
+// IsValidGatesStatus reports whether s is one of the GatesStatus* values
+func IsValidGatesStatus(s string) bool {
+	switch s {
+	case GatesStatusPending, GatesStatusRunning, GatesStatusPassed, GatesStatusFailed:
+		return true
+	}
+	return false
 }
 
 // Validate checks if the mission has valid field values
@@ -64,6 +84,31 @@ func (m *Mission) IsApproved() bool {
 	return m.ApprovedAt != nil
 }
 
+// ErrAwaitingApproval is returned by ClaimIssue for a mission, or work in a
+// mission, whose plan still needs human approval. Match it with errors.Is;
+// the concrete error is an *AwaitingApprovalError.
+var ErrAwaitingApproval = errors.New("mission awaiting plan approval")
+
+// AwaitingApprovalError reports work held back until a mission's plan is
+// approved
+type AwaitingApprovalError struct {
+	IssueID   string
+	MissionID string // Same as IssueID when the mission itself was claimed
+}
+
+// Error implements the error interface.
+func (e *AwaitingApprovalError) Error() string {
+	if e.IssueID == e.MissionID {
+		return fmt.Sprintf("%v: %s", ErrAwaitingApproval, e.MissionID)
+	}
+	return fmt.Sprintf("%v: %s (for %s)", ErrAwaitingApproval, e.MissionID, e.IssueID)
+}
+
+// Unwrap lets errors.Is(err, ErrAwaitingApproval) match.
+func (e *AwaitingApprovalError) Unwrap() error {
+	return ErrAwaitingApproval
+}
+
 // Phase represents a major stage in a mission implementation
 // Each phase is created as a child epic of the mission with its own tasks
 type Phase struct {
@@ -220,6 +265,74 @@ type MissionContext struct {
 	MissionID   string `json:"mission_id"`    // ID of the parent mission epic
 	SandboxPath string `json:"sandbox_path"`  // Path to sandbox directory (future - vc-217)
 	BranchName  string `json:"branch_name"`   // Git branch for this mission (future - vc-217)
+	AwaitingApproval bool `json:"awaiting_approval,omitempty"` // Plan needs approval before work may start
+	PhaseID     string `json:"phase_id,omitempty"`      // Phase epic the task belongs to, if any
+	PhaseWaiting bool  `json:"phase_waiting,omitempty"` // The task's phase isn't the one the mission is executing
+}
+
+// OrderPhases returns a mission's phases in execution order: every phase
+// comes after the phases it depends on through blocks dependencies (deps
+// between phases outside the list are ignored), and phases are otherwise
+// taken by creation time, then ID. Phases caught in a dependency cycle go
+// last, in the same tie-break order.
+func OrderPhases(phases []*Issue, deps []*Dependency) []*Issue {
+	byAge := make([]*Issue, len(phases))
+	copy(byAge, phases)
+	sort.SliceStable(byAge, func(i, j int) bool {
+		if !byAge[i].CreatedAt.Equal(byAge[j].CreatedAt) {
+			return byAge[i].CreatedAt.Before(byAge[j].CreatedAt)
+		}
+		return byAge[i].ID < byAge[j].ID
+	})
+
+	inList := make(map[string]bool, len(phases))
+	for _, phase := range phases {
+		inList[phase.ID] = true
+	}
+	waitingOn := make(map[string]map[string]bool)
+	for _, dep := range deps {
+		if dep.Type != DepBlocks || !inList[dep.IssueID] || !inList[dep.DependsOnID] || dep.IssueID == dep.DependsOnID {
+			continue
+		}
+		if waitingOn[dep.IssueID] == nil {
+			waitingOn[dep.IssueID] = make(map[string]bool)
+		}
+		waitingOn[dep.IssueID][dep.DependsOnID] = true
+	}
+
+	ordered := make([]*Issue, 0, len(phases))
+	placed := make(map[string]bool, len(phases))
+	for len(ordered) < len(byAge) {
+		progress := false
+		for _, phase := range byAge {
+			if placed[phase.ID] {
+				continue
+			}
+			ready := true
+			for blocker := range waitingOn[phase.ID] {
+				if !placed[blocker] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				ordered = append(ordered, phase)
+				placed[phase.ID] = true
+				progress = true
+				break // Restart so earlier phases unblocked by this one go first
+			}
+		}
+		if !progress {
+			// Cycle: append what's left as is
+			for _, phase := range byAge {
+				if !placed[phase.ID] {
+					ordered = append(ordered, phase)
+					placed[phase.ID] = true
+				}
+			}
+		}
+	}
+	return ordered
 }
 
 // MissionPlanner is the interface for AI-driven mission planning
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"strings"
 	"testing"
 	"time"
 )
@@ -666,3 +667,70 @@ func TestPlannedTask_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestOrderPhases(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	phase := func(id string, minute int) *Issue {
+		return &Issue{ID: id, CreatedAt: base.Add(time.Duration(minute) * time.Minute)}
+	}
+	blocks := func(issueID, dependsOnID string) *Dependency {
+		return &Dependency{IssueID: issueID, DependsOnID: dependsOnID, Type: DepBlocks}
+	}
+
+	tests := []struct {
+		name   string
+		phases []*Issue
+		deps   []*Dependency
+		want   string
+	}{
+		{
+			name:   "creation order without dependencies",
+			phases: []*Issue{phase("vc-3", 2), phase("vc-1", 0), phase("vc-2", 1)},
+			want:   "vc-1 vc-2 vc-3",
+		},
+		{
+			name:   "ties broken by ID",
+			phases: []*Issue{phase("vc-2", 0), phase("vc-1", 0)},
+			want:   "vc-1 vc-2",
+		},
+		{
+			name:   "dependencies override creation order",
+			phases: []*Issue{phase("vc-1", 0), phase("vc-2", 1), phase("vc-3", 2)},
+			deps:   []*Dependency{blocks("vc-1", "vc-3")},
+			want:   "vc-2 vc-3 vc-1",
+		},
+		{
+			name:   "unblocked older phase goes first",
+			phases: []*Issue{phase("vc-1", 0), phase("vc-2", 1), phase("vc-3", 2)},
+			deps:   []*Dependency{blocks("vc-1", "vc-3"), blocks("vc-2", "vc-3")},
+			want:   "vc-3 vc-1 vc-2",
+		},
+		{
+			name:   "other dependencies ignored",
+			phases: []*Issue{phase("vc-1", 0), phase("vc-2", 1)},
+			deps: []*Dependency{
+				blocks("vc-1", "vc-9"),
+				{IssueID: "vc-1", DependsOnID: "vc-2", Type: DepRelated},
+			},
+			want: "vc-1 vc-2",
+		},
+		{
+			name:   "cycle appended in creation order",
+			phases: []*Issue{phase("vc-1", 0), phase("vc-2", 1), phase("vc-3", 2)},
+			deps:   []*Dependency{blocks("vc-1", "vc-2"), blocks("vc-2", "vc-1")},
+			want:   "vc-3 vc-1 vc-2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ids []string
+			for _, p := range OrderPhases(tt.phases, tt.deps) {
+				ids = append(ids, p.ID)
+			}
+			if got := strings.Join(ids, " "); got != tt.want {
+				t.Errorf("OrderPhases() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
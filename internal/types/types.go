@@ -2,7 +2,11 @@ package types
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -24,34 +28,180 @@ type Issue struct {
 	UpdatedAt          time.Time        `json:"updated_at"`
 	ClosedAt           *time.Time       `json:"closed_at,omitempty"`
 	MissionContext     *MissionContext  `json:"mission_context,omitempty"` // vc-234: Populated by GetReadyWork
+	Archived           bool             `json:"archived,omitempty"`        // Hidden from queries unless IssueFilter.IncludeArchived
+	Resolution         Resolution       `json:"resolution,omitempty"`      // Why a closed issue was closed, set by GetIssue (closed without one = fixed)
+	// Project picks the ID prefix on create (DefaultProject if empty); set
+	// by GetIssue, SearchIssues, GetReadyWork, GetReadyBlockers and
+	// GetDependencyTree
+	Project string `json:"project,omitempty"`
 }
 
-// Validate checks if the issue has valid field values
-func (i *Issue) Validate() error {
-	if len(i.Title) == 0 {
-		return fmt.Errorf("title is required")
+// MaxTitleLength caps issue titles, in bytes
+const MaxTitleLength = 500
+
+// MaxEstimatedMinutes caps an issue's estimate at 30 days of work; larger
+// work is an epic, not one issue
+const MaxEstimatedMinutes = 30 * 24 * 60
+
+// FieldError is an invalid value of one issue field
+type FieldError struct {
+	Field   string // The field's column name, e.g. priority or issue_type
+	Message string // What is wrong, e.g. "must be between 0 and 4 (got 9)"
+}
+
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// ValidationError lists every invalid field of an issue or an update, so a
+// caller can report them all at once. Storage returns it from CreateIssue,
+// UpdateIssue and their variants.
+type ValidationError struct {
+	Fields []*FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		messages[i] = f.Error()
 	}
-	if len(i.Title) > 500 {
-		return fmt.Errorf("title must be 500 characters or less (got %d)", len(i.Title))
+	return "invalid issue: " + strings.Join(messages, "; ")
+}
+
+// validationResult returns a *ValidationError of fields, or nil for none
+func validationResult(fields []*FieldError) error {
+	if len(fields) == 0 {
+		return nil
 	}
-	if i.Priority < 0 || i.Priority > 4 {
-		return fmt.Errorf("priority must be between 0 and 4 (got %d)", i.Priority)
+	return &ValidationError{Fields: fields}
+}
+
+// Validate checks if the issue has valid field values. It returns a
+// *ValidationError naming each invalid field.
+func (i *Issue) Validate() error {
+	var fields []*FieldError
+	if err := validateTitle(i.Title); err != nil {
+		fields = append(fields, err)
+	}
+	if err := validatePriority(i.Priority); err != nil {
+		fields = append(fields, err)
 	}
 	if !i.Status.IsValid() {
-		return fmt.Errorf("invalid status: %s", i.Status)
+		fields = append(fields, invalidStatus(i.Status))
 	}
 	if !i.IssueType.IsValid() {
-		return fmt.Errorf("invalid issue type: %s", i.IssueType)
+		fields = append(fields, invalidIssueType(i.IssueType))
 	}
 	if !i.IssueSubtype.IsValid() {
-		return fmt.Errorf("invalid issue subtype: %s", i.IssueSubtype)
+		fields = append(fields, &FieldError{Field: "issue_subtype", Message: fmt.Sprintf("unknown subtype %q (valid: mission, phase or none)", i.IssueSubtype)})
 	}
-	if i.EstimatedMinutes != nil && *i.EstimatedMinutes < 0 {
-		return fmt.Errorf("estimated_minutes cannot be negative")
+	if i.EstimatedMinutes != nil {
+		if err := validateEstimate(*i.EstimatedMinutes); err != nil {
+			fields = append(fields, err)
+		}
+	}
+	return validationResult(fields)
+}
+
+// ValidateUpdates checks the values of an update map as passed to
+// Storage.UpdateIssue: title, priority, status, issue_type and
+// estimated_minutes (nil clears it). Other fields aren't checked here. It
+// returns a *ValidationError naming each invalid field.
+func ValidateUpdates(updates map[string]interface{}) error {
+	keys := make([]string, 0, len(updates))
+	for key := range updates {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var fields []*FieldError
+	for _, key := range keys {
+		value := updates[key]
+		var err *FieldError
+		switch key {
+		case "title":
+			err = validateTitle(fmt.Sprint(value))
+		case "priority":
+			if priority, ok := updateInt(value); !ok {
+				err = &FieldError{Field: key, Message: fmt.Sprintf("must be an integer (got %T)", value)}
+			} else {
+				err = validatePriority(priority)
+			}
+		case "status":
+			if status := Status(fmt.Sprint(value)); !status.IsValid() {
+				err = invalidStatus(status)
+			}
+		case "issue_type":
+			if issueType := IssueType(fmt.Sprint(value)); !issueType.IsValid() {
+				err = invalidIssueType(issueType)
+			}
+		case "estimated_minutes":
+			if cleared, ok := value.(*int); value == nil || ok && cleared == nil {
+				break
+			}
+			if minutes, ok := updateInt(value); !ok {
+				err = &FieldError{Field: key, Message: fmt.Sprintf("must be an integer (got %T)", value)}
+			} else {
+				err = validateEstimate(minutes)
+			}
+		}
+		if err != nil {
+			fields = append(fields, err)
+		}
+	}
+	return validationResult(fields)
+}
+
+// updateInt reads an integer update value: an int, an int64, a whole
+// float64 (decoded JSON) or a non-nil *int
+func updateInt(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	case float64:
+		return int(v), v == float64(int(v))
+	case *int:
+		if v != nil {
+			return *v, true
+		}
+	}
+	return 0, false
+}
+
+func validateTitle(title string) *FieldError {
+	if strings.TrimSpace(title) == "" {
+		return &FieldError{Field: "title", Message: "is required"}
+	}
+	if len(title) > MaxTitleLength {
+		return &FieldError{Field: "title", Message: fmt.Sprintf("must be %d characters or less (got %d)", MaxTitleLength, len(title))}
 	}
 	return nil
 }
 
+func validatePriority(priority int) *FieldError {
+	if priority < 0 || priority > 4 {
+		return &FieldError{Field: "priority", Message: fmt.Sprintf("must be between 0 and 4 (got %d)", priority)}
+	}
+	return nil
+}
+
+func validateEstimate(minutes int) *FieldError {
+	if minutes < 0 || minutes > MaxEstimatedMinutes {
+		return &FieldError{Field: "estimated_minutes", Message: fmt.Sprintf("must be between 0 and %d (got %d)", MaxEstimatedMinutes, minutes)}
+	}
+	return nil
+}
+
+func invalidStatus(status Status) *FieldError {
+	return &FieldError{Field: "status", Message: fmt.Sprintf("unknown status %q (valid: open, in_progress, blocked, closed)", status)}
+}
+
+func invalidIssueType(issueType IssueType) *FieldError {
+	return &FieldError{Field: "issue_type", Message: fmt.Sprintf("unknown type %q (valid: bug, feature, task, epic, chore)", issueType)}
+}
+
 // Status represents the current state of an issue
 type Status string
 
@@ -71,6 +221,116 @@ func (s Status) IsValid() bool {
 	return false
 }
 
+// statusTransitions is the legal status graph. Any status may also move to
+// closed (with a reason, see CloseIssue) and stay where it is.
+var statusTransitions = map[Status][]Status{
+	StatusOpen:       {StatusInProgress, StatusBlocked},
+	StatusInProgress: {StatusOpen, StatusBlocked},
+	StatusBlocked:    {StatusOpen}, // unblocked
+	StatusClosed:     {StatusOpen}, // reopened
+}
+
+// CanTransitionTo reports whether an issue may move from s to next
+func (s Status) CanTransitionTo(next Status) bool {
+	if s == next || next == StatusClosed {
+		return true
+	}
+	for _, allowed := range statusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolution records why a closed issue was closed
+type Resolution string
+
+const (
+	ResolutionFixed     Resolution = "fixed"     // Done; the default
+	ResolutionWontfix   Resolution = "wontfix"   // Decided against
+	ResolutionDuplicate Resolution = "duplicate" // Covered by another issue
+	ResolutionObsolete  Resolution = "obsolete"  // No longer relevant
+)
+
+// Resolutions lists the valid resolutions
+var Resolutions = []Resolution{ResolutionFixed, ResolutionWontfix, ResolutionDuplicate, ResolutionObsolete}
+
+// IsValid checks if the resolution value is valid
+func (r Resolution) IsValid() bool {
+	return slices.Contains(Resolutions, r)
+}
+
+// VoidsPremise reports whether work that depends on an issue closed with
+// this resolution has lost its reason to exist: the prerequisite will never
+// be done. A duplicate's work is done elsewhere, so it doesn't.
+func (r Resolution) VoidsPremise() bool {
+	return r == ResolutionWontfix || r == ResolutionObsolete
+}
+
+// ErrIllegalTransition is matched (via errors.Is) by every *StatusTransitionError
+var ErrIllegalTransition = errors.New("illegal status transition")
+
+// StatusTransitionError reports a status change outside the legal status graph
+type StatusTransitionError struct {
+	IssueID string
+	From    Status
+	To      Status
+	// Reason explains why the transition is refused, if not just the graph
+	Reason string
+}
+
+// Error implements the error interface.
+func (e *StatusTransitionError) Error() string {
+	msg := fmt.Sprintf("%v for %s: %s → %s", ErrIllegalTransition, e.IssueID, e.From, e.To)
+	if e.Reason != "" {
+		msg += " (" + e.Reason + ")"
+	}
+	return msg
+}
+
+// Unwrap lets errors.Is(err, ErrIllegalTransition) match.
+func (e *StatusTransitionError) Unwrap() error {
+	return ErrIllegalTransition
+}
+
+// ValidateStatusTransition returns a *StatusTransitionError if issueID may not
+// move from one status to the other
+func ValidateStatusTransition(issueID string, from, to Status) error {
+	if !to.IsValid() {
+		return fmt.Errorf("invalid status: %s", to)
+	}
+	if !from.CanTransitionTo(to) {
+		return &StatusTransitionError{IssueID: issueID, From: from, To: to}
+	}
+	return nil
+}
+
+// SystemIssueID is the ID of the SYSTEM pseudo-issue, which system-level
+// agent events (event cleanup, vacuums, AI outages) are filed under. Storage
+// creates it when it opens a database and recreates it if it was deleted.
+// SearchIssues, GetReadyWork and GetStatistics leave it out unless
+// IssueFilter.IncludeSystem is set.
+const SystemIssueID = "SYSTEM"
+
+// NewSystemIssue returns the SYSTEM pseudo-issue as storage creates it: a
+// closed chore saying what it is for
+func NewSystemIssue(now time.Time) *Issue {
+	return &Issue{
+		ID:    SystemIssueID,
+		Title: "System-level events (internal)",
+		Description: "Internal pseudo-issue that vc files system-level agent events under " +
+			"(event cleanup, database maintenance, AI outages). It is not work: leave it closed. " +
+			"vc recreates it if it is deleted.",
+		Status:    StatusClosed,
+		Priority:  4,
+		IssueType: TypeChore,
+		CreatedAt: now,
+		UpdatedAt: now,
+		ClosedAt:  &now,
+	}
+}
+
 // IssueType categorizes the kind of work
 type IssueType string
 
@@ -133,6 +393,14 @@ const (
 	DepDiscoveredFrom DependencyType = "discovered-from"
 )
 
+// IsBlocking reports whether the dependency blocks on its own: the dependent
+// issue isn't ready while the issue it depends on is open. Parent-child links
+// don't block, but GetReadyWork passes a blocked parent's blockage on to its
+// children; related and discovered-from links only record a relationship.
+func (d DependencyType) IsBlocking() bool {
+	return d == DepBlocks
+}
+
 // IsValid checks if the dependency type value is valid
 func (d DependencyType) IsValid() bool {
 	switch d {
@@ -160,6 +428,24 @@ type Event struct {
 	CreatedAt time.Time  `json:"created_at"`
 }
 
+// DatabaseStats describes the size and free space of the database file
+type DatabaseStats struct {
+	PageSize      int64  `json:"page_size"`
+	PageCount     int64  `json:"page_count"`
+	FreelistCount int64  `json:"freelist_count"` // Unused pages VACUUM would reclaim
+	AutoVacuum    string `json:"auto_vacuum"`    // "none", "full" or "incremental"
+	SizeBytes     int64  `json:"size_bytes"`     // PageCount * PageSize
+	WALSizeBytes  int64  `json:"wal_size_bytes"` // Size of the -wal file, if any
+}
+
+// FreeFraction is the fraction of pages on the freelist (0 for an empty database)
+func (s *DatabaseStats) FreeFraction() float64 {
+	if s.PageCount == 0 {
+		return 0
+	}
+	return float64(s.FreelistCount) / float64(s.PageCount)
+}
+
 // EventCounts holds event count statistics for monitoring
 type EventCounts struct {
 	TotalEvents      int
@@ -190,6 +476,14 @@ type BlockedIssue struct {
 	Issue
 	BlockedByCount int      `json:"blocked_by_count"`
 	BlockedBy      []string `json:"blocked_by"`
+	// RootBlockers are the open issues at the end of the blocking chains:
+	// issues this one transitively depends on that have no open blockers
+	// themselves. Closing them is what eventually unblocks this issue.
+	RootBlockers []string `json:"root_blockers,omitempty"`
+	// RootsTruncated is set when a chain doesn't end within the depth limit,
+	// because it is very deep or runs into a dependency cycle, so
+	// RootBlockers may be incomplete
+	RootsTruncated bool `json:"roots_truncated,omitempty"`
 }
 
 // TreeNode represents a node in a dependency tree
@@ -197,17 +491,89 @@ type TreeNode struct {
 	Issue
 	Depth     int  `json:"depth"`
 	Truncated bool `json:"truncated"`
+	// ParentID is the node this one hangs off (the issue that depends on
+	// it), and DependencyType the type of that link; both are empty for
+	// the root
+	ParentID       string         `json:"parent_id,omitempty"`
+	DependencyType DependencyType `json:"dependency_type,omitempty"`
 }
 
 // Statistics provides aggregate metrics
 type Statistics struct {
-	TotalIssues      int     `json:"total_issues"`
-	OpenIssues       int     `json:"open_issues"`
-	InProgressIssues int     `json:"in_progress_issues"`
-	ClosedIssues     int     `json:"closed_issues"`
-	BlockedIssues    int     `json:"blocked_issues"`
-	ReadyIssues      int     `json:"ready_issues"`
-	AverageLeadTime  float64 `json:"average_lead_time_hours"`
+	TotalIssues       int     `json:"total_issues"`
+	OpenIssues        int     `json:"open_issues"`
+	InProgressIssues  int     `json:"in_progress_issues"`
+	ClosedIssues      int     `json:"closed_issues"`
+	BlockedIssues     int     `json:"blocked_issues"`
+	ReadyIssues       int     `json:"ready_issues"`
+	AutoBlockedIssues int     `json:"auto_blocked_issues"` // Still blocked with AutoBlockedLabel: these need a human, not a blocker
+	AverageLeadTime   float64 `json:"average_lead_time_hours"`
+	// Flow holds lead time, cycle time and throughput over the trailing
+	// FlowWeeks weeks
+	Flow *FlowMetrics `json:"flow,omitempty"`
+}
+
+// FlowWeeks is the number of weeks (including the current one) that
+// Statistics.Flow covers
+const FlowWeeks = 8
+
+// FlowMetrics describes how work moves through the tracker.
+//
+// All times are UTC. Weeks start on Monday 00:00 UTC; the window starts at
+// the beginning of the week Weeks-1 weeks before the current one, so the last
+// week in Throughput is still in progress. Lead and cycle times cover the
+// issues closed inside the window.
+type FlowMetrics struct {
+	Since time.Time `json:"since"`
+	Weeks int       `json:"weeks"`
+	// LeadTime runs from creation to close
+	LeadTime DurationStats `json:"lead_time"`
+	// CycleTime runs from the first execution attempt (the first claim) to
+	// close; closed issues that were never executed are not counted
+	CycleTime DurationStats `json:"cycle_time"`
+	// Throughput is the number of issues closed per week, oldest first
+	Throughput []WeeklyThroughput `json:"throughput"`
+	// WIP is the number of issues currently in progress
+	WIP int `json:"wip"`
+}
+
+// DurationStats summarizes a set of durations, in hours. Percentiles use the
+// nearest-rank method.
+type DurationStats struct {
+	Count   int     `json:"count"`
+	Average float64 `json:"average_hours"`
+	P50     float64 `json:"p50_hours"`
+	P90     float64 `json:"p90_hours"`
+}
+
+// WeeklyThroughput counts the issues closed in the week starting WeekStart
+type WeeklyThroughput struct {
+	WeekStart time.Time `json:"week_start"`
+	Closed    int       `json:"closed"`
+}
+
+// ActorStatistics summarizes one actor's activity in a time window.
+// Executors act under their instance ID, so every executor instance shows up
+// as an actor with Executor set.
+type ActorStatistics struct {
+	Actor    string `json:"actor"`
+	Executor bool   `json:"executor"`
+	// Hostname is the executor instance's host, when still registered
+	Hostname      string `json:"hostname,omitempty"`
+	IssuesCreated int    `json:"issues_created"`
+	IssuesClosed  int    `json:"issues_closed"`
+	Comments      int    `json:"comments"`
+	// ExecutionAttempts counts the attempts started in the window;
+	// SuccessRate and AverageDurationSeconds cover the finished ones
+	ExecutionAttempts      int     `json:"execution_attempts"`
+	SuccessfulAttempts     int     `json:"successful_attempts"`
+	SuccessRate            float64 `json:"success_rate"`
+	AverageDurationSeconds float64 `json:"average_duration_seconds"`
+	// GateRuns counts quality gate evaluations; GatesPassed those where
+	// every gate passed
+	GateRuns     int     `json:"gate_runs"`
+	GatesPassed  int     `json:"gates_passed"`
+	GatePassRate float64 `json:"gate_pass_rate"`
 }
 
 // IssueFilter is used to filter issue queries
@@ -219,6 +585,152 @@ type IssueFilter struct {
 	Assignee  *string
 	Labels    []string
 	Limit     int
+	// Offset skips the first Offset matches (for pagination)
+	Offset int
+	// OrderBy is a sortable column (see SortableIssueColumns). Empty keeps
+	// the default order: priority, then newest first.
+	OrderBy string
+	// Descending reverses OrderBy
+	Descending bool
+	// IncludeArchived also returns archived issues (excluded by default)
+	IncludeArchived bool
+	// IncludeSystem also returns the SYSTEM pseudo-issue (excluded by
+	// default, see SystemIssueID)
+	IncludeSystem bool
+	// Project only returns the project's issues ("" for every project)
+	Project string
+	// MetaEquals only returns issues whose metadata has every key with the
+	// value, read as by ParseMetaValue
+	MetaEquals map[string]string
+}
+
+// SortableIssueColumns are the values accepted by IssueFilter.OrderBy
+var SortableIssueColumns = []string{
+	"id", "title", "status", "priority", "issue_type", "assignee",
+	"created_at", "updated_at", "closed_at",
+}
+
+// Issue groupings accepted by Storage.CountIssuesByGroup (vc list --group-by)
+const (
+	GroupByStatus      = "status"
+	GroupByPriority    = "priority"
+	GroupByType        = "type"
+	GroupByAssignee    = "assignee"
+	GroupByLabelPrefix = "label-prefix" // The part of each label before its first colon
+)
+
+// IssueGroupings lists the issue groupings
+var IssueGroupings = []string{GroupByStatus, GroupByPriority, GroupByType, GroupByAssignee, GroupByLabelPrefix}
+
+// LabelPrefix returns the part of label before its first colon ("area" for
+// "area:ui"), or "" if it has none
+func LabelPrefix(label string) string {
+	prefix, _, found := strings.Cut(label, ":")
+	if !found {
+		return ""
+	}
+	return prefix
+}
+
+// CreateIssuesOptions configures a batch issue create
+type CreateIssuesOptions struct {
+	// Labels to add, keyed by the index of the issue in the batch
+	Labels map[int][]string
+	// Dependencies to add, keyed by the index of the issue in the batch.
+	// An empty IssueID or DependsOnID refers to that issue.
+	Dependencies map[int][]*Dependency
+	// AllOrNothing rolls back the whole batch if any issue fails. By default
+	// the other issues are still created.
+	AllOrNothing bool
+}
+
+// BatchCreateError reports the issues of a batch that could not be created,
+// keyed by their index in the batch
+type BatchCreateError struct {
+	Errors map[int]error
+	// Total is the size of the batch
+	Total int
+	// RolledBack is true when nothing was created (all-or-nothing batches)
+	RolledBack bool
+}
+
+// Error implements the error interface.
+func (e *BatchCreateError) Error() string {
+	indexes := make([]int, 0, len(e.Errors))
+	for i := range e.Errors {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+
+	msg := fmt.Sprintf("failed to create %d of %d issues", len(e.Errors), e.Total)
+	if e.RolledBack {
+		msg += " (batch rolled back)"
+	}
+	for n, i := range indexes {
+		if n == 0 {
+			msg += ": "
+		} else {
+			msg += "; "
+		}
+		msg += fmt.Sprintf("#%d: %v", i, e.Errors[i])
+	}
+	return msg
+}
+
+// ErrConflict is returned by conditional updates when the issue was modified
+// after the caller read it. Match it with errors.Is; the concrete error is a
+// *ConflictError.
+var ErrConflict = errors.New("issue was modified concurrently")
+
+// ConflictError reports a conditional update that lost a race with another writer
+type ConflictError struct {
+	IssueID string
+	// ExpectedUpdatedAt is the updated_at the caller read
+	ExpectedUpdatedAt time.Time
+	// ActualUpdatedAt is the updated_at found in the database
+	ActualUpdatedAt time.Time
+}
+
+// Error implements the error interface.
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%v: %s was updated at %s, expected %s", ErrConflict, e.IssueID,
+		e.ActualUpdatedAt.Format(time.RFC3339Nano), e.ExpectedUpdatedAt.Format(time.RFC3339Nano))
+}
+
+// Unwrap lets errors.Is(err, ErrConflict) match.
+func (e *ConflictError) Unwrap() error {
+	return ErrConflict
+}
+
+// ErrAlreadyClaimed is returned by ClaimIssue when another executor got to
+// the issue first. Match it with errors.Is; the concrete error is a
+// *ClaimConflictError.
+var ErrAlreadyClaimed = errors.New("issue already claimed")
+
+// ErrNotClaimable is returned by ClaimIssue when the issue is no longer
+// open, e.g. it was closed or blocked between the ready-work query and the
+// claim. Match it with errors.Is.
+var ErrNotClaimable = errors.New("issue is not open")
+
+// ClaimConflictError reports a claim that lost to another claimant
+type ClaimConflictError struct {
+	IssueID string
+	// ClaimedBy is the executor instance holding the claim, empty if the
+	// issue was moved to in_progress without one (e.g. by hand)
+	ClaimedBy string
+}
+
+// Error implements the error interface.
+func (e *ClaimConflictError) Error() string {
+	if e.ClaimedBy == "" {
+		return fmt.Sprintf("%v: %s is in progress", ErrAlreadyClaimed, e.IssueID)
+	}
+	return fmt.Sprintf("%v: %s by %s", ErrAlreadyClaimed, e.IssueID, e.ClaimedBy)
+}
+
+// Unwrap lets errors.Is(err, ErrAlreadyClaimed) match.
+func (e *ClaimConflictError) Unwrap() error {
+	return ErrAlreadyClaimed
 }
 
 // WorkFilter is used to filter ready work queries
@@ -238,12 +750,45 @@ const (
 	SortPolicyOldest SortPolicy = "oldest"
 )
 
+// WorkOrder orders ready work. Every order ends with the issue ID, so equal
+// issues always come back in the same order.
+type WorkOrder string
+
+const (
+	// WorkOrderPriority is the canonical order: most urgent first (P0
+	// before P2), then oldest created_at, then ID
+	WorkOrderPriority WorkOrder = "priority"
+
+	// WorkOrderAge puts the oldest issues first, then orders by priority
+	WorkOrderAge WorkOrder = "age"
+
+	// WorkOrderEstimate is shortest job first: the smallest estimate first,
+	// issues without one last, then the canonical order
+	WorkOrderEstimate WorkOrder = "estimate"
+)
+
+// WorkOrders lists the valid work orders
+var WorkOrders = []WorkOrder{WorkOrderPriority, WorkOrderAge, WorkOrderEstimate}
+
+// IsValid checks if the work order value is valid
+func (o WorkOrder) IsValid() bool {
+	return slices.Contains(WorkOrders, o)
+}
+
 type WorkFilter struct {
 	Status     Status
 	Priority   *int
 	Assignee   *string
 	Limit      int
 	SortPolicy SortPolicy
+	// OrderBy orders the work, overriding SortPolicy. Without either the
+	// order is WorkOrderPriority; SortPolicyOldest is WorkOrderAge.
+	OrderBy WorkOrder
+	// Project only returns the project's work ("" for every project)
+	Project string
+	// AgentsOnly leaves out work assigned to a human, unless it is labeled
+	// AgentOKLabel (see AgentAssignable)
+	AgentsOnly bool
 }
 
 // ExecutorStatus represents the state of an executor instance
@@ -271,6 +816,22 @@ type ExecutorInstance struct {
 	Metadata      string         `json:"metadata"` // JSON string (must be valid JSON)
 }
 
+// InstanceMetadata is the part of ExecutorInstance.Metadata vc reads back
+type InstanceMetadata struct {
+	Offline       bool   `json:"offline,omitempty"`        // Running without AI supervision or anything needing it
+	OfflineReason string `json:"offline_reason,omitempty"` // Why: configured, or the AI provider was unreachable
+}
+
+// ParseMetadata decodes the instance's metadata. Invalid or missing metadata
+// decodes to the zero value.
+func (e *ExecutorInstance) ParseMetadata() InstanceMetadata {
+	var metadata InstanceMetadata
+	if e.Metadata != "" {
+		_ = json.Unmarshal([]byte(e.Metadata), &metadata)
+	}
+	return metadata
+}
+
 // Validate checks if the executor instance has valid field values
 func (e *ExecutorInstance) Validate() error {
 	if e.InstanceID == "" {
@@ -385,6 +946,9 @@ type IssueExecutionState struct {
 	StartedAt          time.Time      `json:"started_at"`
 	UpdatedAt          time.Time      `json:"updated_at"`
 	ErrorMessage       string         `json:"error_message,omitempty"`
+	// PreviousAssignee is the issue's assignee before the claim, which
+	// releasing the issue restores
+	PreviousAssignee string `json:"previous_assignee,omitempty"`
 }
 
 // Validate checks if the issue execution state has valid field values
@@ -412,17 +976,55 @@ func (s *IssueExecutionState) Validate() error {
 // Multiple attempts may occur due to retries, resumption after failures,
 // or iterative refinement.
 type ExecutionAttempt struct {
-	ID                 int64      `json:"id"`
-	IssueID            string     `json:"issue_id"`
-	ExecutorInstanceID string     `json:"executor_instance_id"`
-	AttemptNumber      int        `json:"attempt_number"`
-	StartedAt          time.Time  `json:"started_at"`
-	CompletedAt        *time.Time `json:"completed_at,omitempty"`
-	Success            *bool      `json:"success,omitempty"` // nil if not completed yet
-	ExitCode           *int       `json:"exit_code,omitempty"`
-	Summary            string     `json:"summary"`
-	OutputSample       string     `json:"output_sample"` // Truncated output (last 1000 lines)
-	ErrorSample        string     `json:"error_sample"`  // Truncated errors (last 1000 lines)
+	ID                 int64         `json:"id"`
+	IssueID            string        `json:"issue_id"`
+	ExecutorInstanceID string        `json:"executor_instance_id"`
+	AttemptNumber      int           `json:"attempt_number"`
+	StartedAt          time.Time     `json:"started_at"`
+	CompletedAt        *time.Time    `json:"completed_at,omitempty"`
+	Success            *bool         `json:"success,omitempty"` // nil if not completed yet
+	ExitCode           *int          `json:"exit_code,omitempty"`
+	Summary            string        `json:"summary"`
+	OutputSample       string        `json:"output_sample"`          // Truncated output (last 1000 lines)
+	ErrorSample        string        `json:"error_sample"`           // Truncated errors (last 1000 lines)
+	Artifacts          []string      `json:"artifacts,omitempty"`    // Saved diff, gate logs and agent summary (see internal/artifacts)
+	Phases             []PhaseTiming `json:"phases,omitempty"`       // Time spent in each execution phase, in the order they ran
+	CommitHash         string        `json:"commit_hash,omitempty"`  // Commit the attempt made (auto-commit)
+	Branch             string        `json:"branch,omitempty"`       // Sandbox branch the commit was made on
+	MergeCommit        string        `json:"merge_commit,omitempty"` // Merge of the branch into main, when the attempt's cleanup merged it
+	Files              []string      `json:"files,omitempty"`        // Paths the attempt changed, relative to the repository root
+}
+
+// ExecutionPhase is a step of an execution attempt that is timed on its own.
+// Not to be confused with the phases of a phased mission.
+type ExecutionPhase string
+
+// Execution phases, in the order they run. An attempt that ends early
+// only has the phases it reached.
+const (
+	ExecutionPhaseAssessment ExecutionPhase = "assessment" // AI assessment of the issue
+	ExecutionPhaseSandbox    ExecutionPhase = "sandbox"    // Getting or creating the sandbox
+	ExecutionPhaseAgent      ExecutionPhase = "agent"      // Building the prompt and running the agent
+	ExecutionPhaseAnalysis   ExecutionPhase = "analysis"   // Reading the agent's report or AI analysis of its output
+	ExecutionPhaseGates      ExecutionPhase = "gates"      // Quality gates
+	ExecutionPhaseMerge      ExecutionPhase = "merge"      // Committing and merging the work
+)
+
+// ExecutionPhases lists the execution phases in the order they run
+var ExecutionPhases = []ExecutionPhase{
+	ExecutionPhaseAssessment, ExecutionPhaseSandbox, ExecutionPhaseAgent,
+	ExecutionPhaseAnalysis, ExecutionPhaseGates, ExecutionPhaseMerge,
+}
+
+// PhaseTiming is the time an attempt spent in one execution phase
+type PhaseTiming struct {
+	Phase      ExecutionPhase `json:"phase"`
+	DurationMs int64          `json:"duration_ms"`
+}
+
+// Duration returns the time spent in the phase
+func (p PhaseTiming) Duration() time.Duration {
+	return time.Duration(p.DurationMs) * time.Millisecond
 }
 
 // Validate checks if the execution attempt has valid field values
@@ -439,6 +1041,29 @@ func (a *ExecutionAttempt) Validate() error {
 	return nil
 }
 
+// InterventionRecord is a persisted watchdog intervention (vc_watchdog_interventions)
+type InterventionRecord struct {
+	ID                 int64     `json:"id"`
+	IssueID            string    `json:"issue_id"` // Empty for executor-level interventions
+	ExecutorInstanceID string    `json:"executor_instance_id"`
+	AnomalyType        string    `json:"anomaly_type"`
+	Severity           string    `json:"severity"`
+	Confidence         float64   `json:"confidence"`
+	Action             string    `json:"action"` // Intervention type taken (e.g. kill_agent)
+	EscalationIssueID  string    `json:"escalation_issue_id,omitempty"`
+	Success            bool      `json:"success"`
+	Outcome            string    `json:"outcome"`                // Human-readable result message
+	PolicyEntry        string    `json:"policy_entry,omitempty"` // Intervention policy entry that selected the action
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+// InterventionFilter narrows watchdog intervention history queries
+type InterventionFilter struct {
+	IssueID string
+	Since   time.Time // Zero means no lower bound
+	Limit   int       // 0 means no limit
+}
+
 // GateResult represents the result of a quality gate check
 // vc-198: Used in preflight quality gates cache
 type GateResult struct {
@@ -447,3 +1072,354 @@ type GateResult struct {
 	Output string `json:"output"`
 	Error  string `json:"error,omitempty"`
 }
+
+// GitHubLinkState is whether a GitHub link still syncs
+type GitHubLinkState string
+
+// GitHub link states
+const (
+	GitHubLinkActive      GitHubLinkState = "active"
+	GitHubLinkDeleted     GitHubLinkState = "deleted"     // The GitHub issue was deleted
+	GitHubLinkTransferred GitHubLinkState = "transferred" // The GitHub issue moved to another repository
+)
+
+// IsValid checks if the link state value is valid
+func (s GitHubLinkState) IsValid() bool {
+	switch s {
+	case GitHubLinkActive, GitHubLinkDeleted, GitHubLinkTransferred:
+		return true
+	}
+	return false
+}
+
+// GitHubLink ties a vc issue to the GitHub issue it mirrors (vc_github_links).
+// SyncedTitle and SyncedClosed are what both sides agreed on at the last
+// sync, so a side whose value differs from them has changed since.
+type GitHubLink struct {
+	IssueID      string          `json:"issue_id"`
+	Repo         string          `json:"repo"` // owner/name
+	Number       int             `json:"number"`
+	State        GitHubLinkState `json:"state"`
+	SyncedTitle  string          `json:"synced_title"`
+	SyncedClosed bool            `json:"synced_closed"`
+	SyncedAt     time.Time       `json:"synced_at"`
+	MovedTo      string          `json:"moved_to,omitempty"` // URL of a transferred issue
+}
+
+// Validate checks if the link has valid field values
+func (l *GitHubLink) Validate() error {
+	if l.IssueID == "" {
+		return fmt.Errorf("issue_id is required")
+	}
+	if l.Repo == "" || !strings.Contains(l.Repo, "/") {
+		return fmt.Errorf("repo must be owner/name (got %q)", l.Repo)
+	}
+	if l.Number <= 0 {
+		return fmt.Errorf("number must be positive (got %d)", l.Number)
+	}
+	if !l.State.IsValid() {
+		return fmt.Errorf("invalid link state: %s", l.State)
+	}
+	return nil
+}
+
+// DefaultProject is the project of every issue not created in another one,
+// including all issues of databases from before projects existed. Its ID
+// prefix is the issue_prefix config.
+const DefaultProject = "default"
+
+// Project is a set of issues with their own ID prefix, sharing the database
+// with the other projects (vc_projects)
+type Project struct {
+	Name   string `json:"name"`
+	Prefix string `json:"prefix"` // Issue IDs are <prefix>-N
+	// Root is a directory in which vc commands default to this project
+	Root        string    `json:"root,omitempty"`
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Validate checks if the project has valid field values
+func (p *Project) Validate() error {
+	if p.Name == "" || strings.ContainsAny(p.Name, " \t") {
+		return fmt.Errorf("project name must be non-empty without spaces (got %q)", p.Name)
+	}
+	if p.Prefix == "" || strings.ContainsAny(p.Prefix, " \t") {
+		return fmt.Errorf("prefix must be non-empty without spaces (got %q)", p.Prefix)
+	}
+	return nil
+}
+
+// SchedulePolicy is what a schedule does when the issue it filed last is
+// still open
+type SchedulePolicy string
+
+// Schedule policies
+const (
+	ScheduleSkipOpen   SchedulePolicy = "skip" // Skip the window
+	ScheduleFileAnyway SchedulePolicy = "file" // File another issue
+)
+
+// IsValid checks if the schedule policy value is valid
+func (p SchedulePolicy) IsValid() bool {
+	switch p {
+	case ScheduleSkipOpen, ScheduleFileAnyway:
+		return true
+	}
+	return false
+}
+
+// ScheduledLabel is on every issue a schedule files, with schedule:<id>
+const ScheduledLabel = "scheduled"
+
+// AutoBlockedLabel is on issues the executor blocked after repeated
+// execution failures; UnblockIssue removes it
+const AutoBlockedLabel = "auto-blocked"
+
+// ReadyToUnblockLabel is on blocked issues whose blockers are all closed,
+// under UnblockLabel; UnblockIssue removes it
+const ReadyToUnblockLabel = "ready-to-unblock"
+
+// NeedsTriageLabel is on issues a person should review before they are
+// worked on: discovered issues with an inferred priority, and issues the
+// executor held back because their prerequisites were dropped
+const NeedsTriageLabel = "needs-triage"
+
+// EscalationLabel is on the issues the watchdog files for human review
+const EscalationLabel = "watchdog-escalation"
+
+// AffectedIssueLabel is the label a watchdog escalation for issueID carries
+func AffectedIssueLabel(issueID string) string {
+	return "affected-issue:" + issueID
+}
+
+// Policies (the unblock_dependents setting) for a blocked issue once
+// closing an issue that blocks it leaves it no open blocker
+const (
+	UnblockReopen = "reopen" // Reopen it with a comment (default)
+	UnblockLabel  = "label"  // Label it ReadyToUnblockLabel with a comment
+	UnblockOff    = "off"    // Leave it blocked
+)
+
+// AgentOKLabel lets agents take an issue even though it is assigned to a human
+const AgentOKLabel = "agent-ok"
+
+// AgentAssignee hands an issue to the executors explicitly; unassigned
+// issues are theirs too
+const AgentAssignee = "colony"
+
+// agentAssignees are the assignees that aren't humans: unassigned, the
+// executors, and the AI supervisor, which files discovered work
+var agentAssignees = []string{"", AgentAssignee, "ai-supervisor"}
+
+// AgentAssignees returns the assignees that aren't humans
+func AgentAssignees() []string {
+	return slices.Clone(agentAssignees)
+}
+
+// AgentAssignable reports whether agents may take work assigned to assignee
+// without the AgentOKLabel. An executor short ID (ExecutorShortID) counts
+// too, but only storage can tell one apart from a human's name.
+func AgentAssignable(assignee string) bool {
+	return slices.Contains(agentAssignees, assignee)
+}
+
+// ExecutorShortID is the short form of an executor instance ID, which the
+// executor sets as the assignee of the issues it claims
+func ExecutorShortID(instanceID string) string {
+	if len(instanceID) > 8 {
+		return instanceID[:8]
+	}
+	return instanceID
+}
+
+// MinScheduleInterval is the shortest Schedule.Interval
+const MinScheduleInterval = time.Minute
+
+// Schedule files an issue from a template on a cron schedule or at a fixed
+// interval (vc_schedules, vc schedule)
+type Schedule struct {
+	ID int64 `json:"id"`
+
+	// Template of the issues filed
+	Title       string    `json:"title"`
+	Description string    `json:"description,omitempty"`
+	IssueType   IssueType `json:"issue_type"`
+	Priority    int       `json:"priority"`
+	Labels      []string  `json:"labels,omitempty"`
+	Project     string    `json:"project,omitempty"`
+
+	// Exactly one of Cron (five fields or @hourly, @daily, @weekly,
+	// @monthly) and Interval is set
+	Cron     string         `json:"cron,omitempty"`
+	Interval time.Duration  `json:"interval,omitempty"`
+	Policy   SchedulePolicy `json:"policy"`
+	Enabled  bool           `json:"enabled"`
+
+	// Runs counts the windows handled, filed or skipped. Handling a window
+	// is conditional on it, so two executors can't both file one.
+	Runs int `json:"runs"`
+	// LastRunAt is when the last window was handled (nil before the first)
+	LastRunAt   *time.Time `json:"last_run_at,omitempty"`
+	LastIssueID string     `json:"last_issue_id,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// Validate checks if the schedule has valid field values. The cron
+// expression's syntax is checked by the schedule package.
+func (s *Schedule) Validate() error {
+	if s.Title == "" {
+		return fmt.Errorf("title is required")
+	}
+	if !s.IssueType.IsValid() {
+		return fmt.Errorf("invalid issue type: %s", s.IssueType)
+	}
+	if s.Priority < 0 || s.Priority > 4 {
+		return fmt.Errorf("priority must be between 0 and 4 (got %d)", s.Priority)
+	}
+	if (s.Cron == "") == (s.Interval == 0) {
+		return fmt.Errorf("exactly one of cron and interval is required")
+	}
+	if s.Cron == "" && s.Interval < MinScheduleInterval {
+		return fmt.Errorf("interval must be at least %v (got %v)", MinScheduleInterval, s.Interval)
+	}
+	if !s.Policy.IsValid() {
+		return fmt.Errorf("invalid schedule policy: %s (want %s or %s)", s.Policy, ScheduleSkipOpen, ScheduleFileAnyway)
+	}
+	return nil
+}
+
+// TimeEntry is working time logged on an issue by hand (vc_time_entries,
+// vc time), on top of the time its execution attempts took
+type TimeEntry struct {
+	ID        int64     `json:"id"`
+	IssueID   string    `json:"issue_id"`
+	Minutes   int       `json:"minutes"`
+	Note      string    `json:"note,omitempty"`
+	Actor     string    `json:"actor"` // Who logged or last edited the entry
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Validate checks if the time entry has valid field values
+func (e *TimeEntry) Validate() error {
+	if e.IssueID == "" {
+		return fmt.Errorf("issue ID is required")
+	}
+	return ValidateTimeEntryUpdate(e.Minutes, e.Actor)
+}
+
+// ValidateTimeEntryUpdate checks the fields an edit of a time entry sets
+func ValidateTimeEntryUpdate(minutes int, actor string) error {
+	if minutes < 1 || minutes > MaxEstimatedMinutes {
+		return fmt.Errorf("minutes must be between 1 and %d (got %d)", MaxEstimatedMinutes, minutes)
+	}
+	if actor == "" {
+		return fmt.Errorf("actor is required")
+	}
+	return nil
+}
+
+// Issue metadata: JSON values stored under namespaced keys (github.issue,
+// jira.key), for integrations to keep structured data on issues
+const (
+	// MaxMetaKeyLength bounds metadata keys
+	MaxMetaKeyLength = 128
+	// MaxMetaValueSize bounds the JSON encoding of a metadata value
+	MaxMetaValueSize = 4096
+)
+
+// ValidateMetaKey checks that key is namespaced: dot-separated segments of
+// lowercase letters, digits, '_' and '-', at least two of them
+func ValidateMetaKey(key string) error {
+	if len(key) > MaxMetaKeyLength {
+		return fmt.Errorf("metadata key is longer than %d bytes", MaxMetaKeyLength)
+	}
+	segments := strings.Split(key, ".")
+	if len(segments) < 2 {
+		return fmt.Errorf("metadata key %q must be namespaced (e.g. github.issue)", key)
+	}
+	for _, segment := range segments {
+		if segment == "" {
+			return fmt.Errorf("metadata key %q has an empty segment", key)
+		}
+		for _, r := range segment {
+			if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '_' && r != '-' {
+				return fmt.Errorf("metadata key %q may only use a-z, 0-9, '_', '-' and '.'", key)
+			}
+		}
+	}
+	return nil
+}
+
+// NormalizeMetaValue checks that value is JSON of at most MaxMetaValueSize
+// bytes and returns its canonical encoding (compact, object keys sorted),
+// so equal values compare equal in queries
+func NormalizeMetaValue(value json.RawMessage) (json.RawMessage, error) {
+	var v interface{}
+	if err := json.Unmarshal(value, &v); err != nil {
+		return nil, fmt.Errorf("metadata value is not JSON: %w", err)
+	}
+	canonical, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if len(canonical) > MaxMetaValueSize {
+		return nil, fmt.Errorf("metadata value is %d bytes (limit %d)", len(canonical), MaxMetaValueSize)
+	}
+	return canonical, nil
+}
+
+// ParseMetaValue reads a metadata value typed by a person: valid JSON is
+// taken as is, anything else is a string. So 42 and true are a number and
+// a boolean, and "42" (quoted) or PROJ-7 are strings.
+func ParseMetaValue(text string) (json.RawMessage, error) {
+	if !json.Valid([]byte(text)) {
+		quoted, err := json.Marshal(text)
+		if err != nil {
+			return nil, err
+		}
+		return NormalizeMetaValue(quoted)
+	}
+	return NormalizeMetaValue(json.RawMessage(text))
+}
+
+// Metadata keys recording the commit that closed an issue, set by the
+// executor (commit and branch) and by sandbox cleanup (merge commit). The
+// values are JSON strings.
+const (
+	MetaClosingCommit = "vc.commit"
+	MetaClosingBranch = "vc.branch"
+	MetaMergeCommit   = "vc.merge_commit"
+)
+
+// ClosingCommit is the commit that closed an issue, read from its metadata
+type ClosingCommit struct {
+	Commit      string `json:"commit"`
+	Branch      string `json:"branch,omitempty"`
+	MergeCommit string `json:"merge_commit,omitempty"` // Only if the branch was merged with a merge commit
+}
+
+// ClosingCommitFromMeta reads the closing commit from issue metadata, nil if
+// none was recorded
+func ClosingCommitFromMeta(meta map[string]json.RawMessage) *ClosingCommit {
+	var c ClosingCommit
+	_ = json.Unmarshal(meta[MetaClosingCommit], &c.Commit)
+	if c.Commit == "" {
+		return nil
+	}
+	_ = json.Unmarshal(meta[MetaClosingBranch], &c.Branch)
+	_ = json.Unmarshal(meta[MetaMergeCommit], &c.MergeCommit)
+	if c.MergeCommit == c.Commit {
+		c.MergeCommit = ""
+	}
+	return &c
+}
+
+// MetaString encodes s as a metadata value (a JSON string), for SetIssueMeta
+// and for IssueFilter.MetaEquals, where an unquoted hash of digits would be
+// read as a number
+func MetaString(s string) json.RawMessage {
+	encoded, _ := json.Marshal(s)
+	return encoded
+}
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"errors"
 	"testing"
 	"time"
 )
@@ -145,3 +146,106 @@ func TestAllDependencyTypesValid(t *testing.T) {
 		})
 	}
 }
+
+// TestDependencyTypeIsBlocking checks that only blocks dependencies block
+func TestDependencyTypeIsBlocking(t *testing.T) {
+	for _, depType := range []DependencyType{DepBlocks, DepRelated, DepParentChild, DepDiscoveredFrom} {
+		if got, want := depType.IsBlocking(), depType == DepBlocks; got != want {
+			t.Errorf("%q.IsBlocking() = %v, want %v", depType, got, want)
+		}
+	}
+}
+
+// TestStatusTransitions enumerates the whole status graph
+func TestStatusTransitions(t *testing.T) {
+	legal := map[Status][]Status{
+		StatusOpen:       {StatusOpen, StatusInProgress, StatusBlocked, StatusClosed},
+		StatusInProgress: {StatusInProgress, StatusOpen, StatusBlocked, StatusClosed},
+		StatusBlocked:    {StatusBlocked, StatusOpen, StatusClosed},
+		StatusClosed:     {StatusClosed, StatusOpen},
+	}
+	all := []Status{StatusOpen, StatusInProgress, StatusBlocked, StatusClosed}
+
+	for _, from := range all {
+		for _, to := range all {
+			want := false
+			for _, allowed := range legal[from] {
+				if allowed == to {
+					want = true
+				}
+			}
+			if got := from.CanTransitionTo(to); got != want {
+				t.Errorf("%s → %s: CanTransitionTo = %v, want %v", from, to, got, want)
+			}
+
+			err := ValidateStatusTransition("vc-1", from, to)
+			if want && err != nil {
+				t.Errorf("%s → %s: unexpected error %v", from, to, err)
+			}
+			if !want {
+				var transitionErr *StatusTransitionError
+				if !errors.As(err, &transitionErr) || !errors.Is(err, ErrIllegalTransition) {
+					t.Errorf("%s → %s: expected a StatusTransitionError, got %v", from, to, err)
+				} else if transitionErr.From != from || transitionErr.To != to {
+					t.Errorf("%s → %s: error names the wrong transition: %v", from, to, err)
+				}
+			}
+		}
+	}
+
+	if err := ValidateStatusTransition("vc-1", StatusOpen, "done"); err == nil {
+		t.Error("Expected unknown status to be rejected")
+	}
+}
+
+func TestIssueValidateFields(t *testing.T) {
+	valid := &Issue{Title: "Fix login", Status: StatusOpen, Priority: 2, IssueType: TypeBug}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("Expected a valid issue, got %v", err)
+	}
+
+	tooLong := 30*24*60 + 1
+	issue := &Issue{Title: "  ", Status: "done", Priority: 9, IssueType: "banana", EstimatedMinutes: &tooLong}
+	var invalid *ValidationError
+	if err := issue.Validate(); !errors.As(err, &invalid) {
+		t.Fatalf("Expected a ValidationError, got %v", err)
+	}
+	var got []string
+	for _, f := range invalid.Fields {
+		got = append(got, f.Field)
+	}
+	want := []string{"title", "priority", "status", "issue_type", "estimated_minutes"}
+	if len(got) != len(want) {
+		t.Fatalf("Expected fields %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Expected fields %v, got %v", want, got)
+			break
+		}
+	}
+	if msg := invalid.Fields[1].Error(); msg != "priority: must be between 0 and 4 (got 9)" {
+		t.Errorf("Unexpected priority message: %q", msg)
+	}
+}
+
+func TestValidateUpdates(t *testing.T) {
+	var none *int
+	valid := map[string]interface{}{
+		"title": "Fix login", "priority": float64(1), "status": "in_progress",
+		"issue_type": TypeTask, "estimated_minutes": none, "notes": "anything",
+	}
+	if err := ValidateUpdates(valid); err != nil {
+		t.Fatalf("Expected valid updates, got %v", err)
+	}
+
+	var invalid *ValidationError
+	err := ValidateUpdates(map[string]interface{}{"priority": "high", "title": "", "estimated_minutes": -5})
+	if !errors.As(err, &invalid) || len(invalid.Fields) != 3 {
+		t.Fatalf("Expected 3 invalid fields, got %v", err)
+	}
+	want := "invalid issue: estimated_minutes: must be between 0 and 43200 (got -5); priority: must be an integer (got string); title: is required"
+	if err.Error() != want {
+		t.Errorf("Expected %q, got %q", want, err.Error())
+	}
+}
@@ -3,6 +3,7 @@ package watchdog
 import (
 	"context"
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
@@ -20,6 +21,8 @@ const (
 	AnomalyRegression        AnomalyType = "regression"         // Pattern of failures after previous successes
 	AnomalyResourceSpike     AnomalyType = "resource_spike"     // Unusual resource usage pattern
 	AnomalyContextExhaustion AnomalyType = "context_exhaustion" // Context usage approaching limit
+	AnomalyStalled           AnomalyType = "stalled"            // Agent produced no output past the silence threshold
+	AnomalyOutputLoop        AnomalyType = "loop"               // Agent output repeating the same lines
 	AnomalyOther             AnomalyType = "other"              // Other anomalous behavior
 )
 
@@ -83,14 +86,20 @@ type Analyzer struct {
 	supervisor *ai.Supervisor
 	// TODO(vc-170): store will be used to query historical events for richer context
 	// Currently unused but required for future event-based anomaly correlation
-	store      storage.Storage
+	store      storage.EventStore
+	// config supplies the warm-up period (nil disables warm-up)
+	config     *WatchdogConfig
+	// callAI sends the prompt to the AI (replaced in tests to count invocations)
+	callAI     func(ctx context.Context, prompt string) (string, error)
 }
 
 // AnalyzerConfig holds configuration for the analyzer
 type AnalyzerConfig struct {
 	Monitor    *Monitor
 	Supervisor *ai.Supervisor
-	Store      storage.Storage
+	Store      storage.EventStore
+	// Config is optional; when set its AIConfig.WarmupPeriod is honored
+	Config     *WatchdogConfig
 }
 
 // NewAnalyzer creates a new behavioral analyzer
@@ -105,11 +114,21 @@ func NewAnalyzer(cfg *AnalyzerConfig) (*Analyzer, error) {
 		return nil, fmt.Errorf("store is required")
 	}
 
-	return &Analyzer{
+	a := &Analyzer{
 		monitor:    cfg.Monitor,
 		supervisor: cfg.Supervisor,
 		store:      cfg.Store,
-	}, nil
+		config:     cfg.Config,
+	}
+	a.callAI = a.callAIWithRetry
+	return a, nil
+}
+
+// debugf prints watchdog debug output when VC_DEBUG_WATCHDOG is set
+func debugf(format string, args ...interface{}) {
+	if os.Getenv("VC_DEBUG_WATCHDOG") != "" {
+		fmt.Fprintf(os.Stderr, "[DEBUG] Watchdog: "+format+"\n", args...)
+	}
 }
 
 // DetectAnomalies analyzes telemetry and event history to detect anomalous behavior
@@ -123,13 +142,38 @@ func (a *Analyzer) DetectAnomalies(ctx context.Context) (*AnomalyReport, error)
 	// Get current execution if any
 	currentExecution := a.monitor.GetCurrentExecution()
 
-	// If no data, nothing to analyze
-	if len(telemetry) == 0 && currentExecution == nil {
+	// Nothing executing: history alone isn't worth a model call every tick
+	if currentExecution == nil {
+		debugf("skipping anomaly detection, no active execution")
+		return &AnomalyReport{
+			Detected:    false,
+			Description: "No active execution to analyze",
+			Reasoning:   "Anomaly detection only runs while an issue is executing",
+			Confidence:  1.0,
+		}, nil
+	}
+
+	// Early telemetry is meaningless, so don't spend a model call on it
+	if warmup := a.config.GetWarmupPeriod(); warmup > 0 {
+		if age := time.Since(currentExecution.StartTime); age < warmup {
+			debugf("skipping anomaly detection for %s, execution age %v is within warm-up period %v",
+				currentExecution.IssueID, age.Round(time.Second), warmup)
+			return &AnomalyReport{
+				Detected:    false,
+				Description: fmt.Sprintf("Anomaly detection skipped for %s during warm-up", currentExecution.IssueID),
+				Reasoning:   fmt.Sprintf("Execution is younger than the %v warm-up period", warmup),
+				Confidence:  1.0,
+			}, nil
+		}
+	}
+
+	// Issue opted out of anomaly checks via the watchdog:off label
+	if currentExecution != nil && currentExecution.ProtectionMode == ProtectionOff {
 		return &AnomalyReport{
-			Detected:   false,
-			Description: "No telemetry data available for analysis",
-			Reasoning:  "Cannot detect anomalies without execution history",
-			Confidence: 1.0,
+			Detected:    false,
+			Description: fmt.Sprintf("Anomaly detection skipped for %s (labeled %s)", currentExecution.IssueID, LabelWatchdogOff),
+			Reasoning:   "Watchdog protection was deliberately disabled for this issue",
+			Confidence:  1.0,
 		}, nil
 	}
 
@@ -226,6 +270,9 @@ IMPORTANT: Base your analysis on the DATA provided, not on hardcoded thresholds.
 		prompt.WriteString(fmt.Sprintf("  Started: %s\n", current.StartTime.Format(time.RFC3339)))
 		prompt.WriteString(fmt.Sprintf("  Current time: %s\n", now.Format(time.RFC3339)))
 		prompt.WriteString(fmt.Sprintf("  Running for: %v\n", duration))
+		if current.ProtectionMode == ProtectionRelaxed {
+			prompt.WriteString(fmt.Sprintf("  Watchdog mode: relaxed (issue labeled %s; long, repetitive output is expected)\n", LabelWatchdogRelaxed))
+		}
 
 		if len(current.StateTransitions) > 0 {
 			prompt.WriteString(fmt.Sprintf("  State Transitions (%d):\n", len(current.StateTransitions)))
@@ -311,45 +358,50 @@ IMPORTANT: Respond with ONLY raw JSON. Do NOT wrap it in markdown code fences. J
 	return prompt.String(), nil
 }
 
-// callAISupervisor sends the prompt to the AI supervisor and parses the response
+// callAISupervisor sends the prompt to the AI supervisor and parses the response.
+// An unparseable response is asked for once more with the expected schema.
 func (a *Analyzer) callAISupervisor(ctx context.Context, prompt string) (*AnomalyReport, error) {
-	responseText, err := a.callAIWithRetry(ctx, prompt)
+	responseText, err := a.callAI(ctx, prompt)
 	if err != nil {
 		return nil, err
 	}
 
-	// Strip markdown code fences if present (Claude sometimes adds them despite instructions)
-	responseText = strings.TrimSpace(responseText)
-	if strings.HasPrefix(responseText, "```json") {
-		responseText = strings.TrimPrefix(responseText, "```json")
-		responseText = strings.TrimPrefix(responseText, "```")
-		responseText = strings.TrimSuffix(responseText, "```")
-		responseText = strings.TrimSpace(responseText)
-	} else if strings.HasPrefix(responseText, "```") {
-		responseText = strings.TrimPrefix(responseText, "```")
-		responseText = strings.TrimSuffix(responseText, "```")
-		responseText = strings.TrimSpace(responseText)
-	}
-
 	// Parse the response using AI's resilient JSON parser
-	parseResult := ai.Parse[AnomalyReport](responseText, ai.ParseOptions{
+	opts := ai.ParseOptions{
 		Context:   "anomaly detection response",
 		LogErrors: ai.BoolPtr(true),
-	})
-	if !parseResult.Success {
+	}
+	parseResult := ai.Parse[AnomalyReport](responseText, opts)
+	if parseResult.Success {
+		a.supervisor.RecordResponseRepair(ctx, anomalyOperation, "", parseResult.Repair)
+		return &parseResult.Data, nil
+	}
+
+	retryText, err := a.callAI(ctx, ai.StructuredRetryPrompt(prompt, responseText, parseResult.Error, ai.SchemaHint[AnomalyReport]()))
+	if err != nil {
+		a.supervisor.RecordResponseRetry(ctx, anomalyOperation, "", parseResult.Error, false)
+		return nil, fmt.Errorf("failed to parse anomaly detection response: %s (response: %s); retry failed: %w",
+			parseResult.Error, responseText, err)
+	}
+	retryResult := ai.Parse[AnomalyReport](retryText, opts)
+	a.supervisor.RecordResponseRetry(ctx, anomalyOperation, "", parseResult.Error, retryResult.Success)
+	if !retryResult.Success {
 		return nil, fmt.Errorf("failed to parse anomaly detection response: %s (response: %s)",
-			parseResult.Error, responseText)
+			retryResult.Error, retryText)
 	}
 
-	return &parseResult.Data, nil
+	return &retryResult.Data, nil
 }
 
+// anomalyOperation names anomaly detection calls in AI usage and repair events
+const anomalyOperation = "anomaly-detection"
+
 // callAIWithRetry calls the AI API with the prompt using the supervisor's generic CallAI method
 // This leverages the supervisor's retry logic and circuit breaker without code duplication
 func (a *Analyzer) callAIWithRetry(ctx context.Context, prompt string) (string, error) {
 	// Use supervisor's generic CallAI method
 	// This provides retry logic, circuit breaker, and proper error handling
-	responseText, err := a.supervisor.CallAI(ctx, prompt, "anomaly-detection", "claude-sonnet-4-5-20250929", 4096)
+	responseText, err := a.supervisor.CallAI(ctx, prompt, anomalyOperation, "", 4096)
 	if err != nil {
 		return "", fmt.Errorf("AI anomaly detection API call failed: %w", err)
 	}
@@ -7,79 +7,15 @@ import (
 	"time"
 
 	"github.com/steveyegge/vc/internal/ai"
-	"github.com/steveyegge/vc/internal/events"
+	"github.com/steveyegge/vc/internal/storage/storagetest"
 	"github.com/steveyegge/vc/internal/types"
 )
 
-// mockStorage provides a mock storage implementation for testing
-type mockStorage struct{}
-
-func (m *mockStorage) Close() error { return nil }
-func (m *mockStorage) StoreAgentEvent(ctx context.Context, event *events.AgentEvent) error { return nil }
-func (m *mockStorage) GetAgentEvents(ctx context.Context, filter events.EventFilter) ([]*events.AgentEvent, error) { return nil, nil }
-func (m *mockStorage) GetAgentEventsByIssue(ctx context.Context, issueID string) ([]*events.AgentEvent, error) { return nil, nil }
-func (m *mockStorage) GetRecentAgentEvents(ctx context.Context, limit int) ([]*events.AgentEvent, error) { return nil, nil }
-func (m *mockStorage) CreateIssue(ctx context.Context, issue *types.Issue, actor string) error { return nil }
-func (m *mockStorage) CreateMission(ctx context.Context, mission *types.Mission, actor string) error { return nil }
-func (m *mockStorage) GetIssue(ctx context.Context, id string) (*types.Issue, error) { return nil, nil }
-func (m *mockStorage) GetMission(ctx context.Context, id string) (*types.Mission, error) { return nil, nil }
-
-func (m *mockStorage) UpdateMission(ctx context.Context, id string, updates map[string]interface{}, actor string) error {
-	return nil
-}
-func (m *mockStorage) UpdateIssue(ctx context.Context, id string, updates map[string]interface{}, actor string) error { return nil }
-func (m *mockStorage) CloseIssue(ctx context.Context, id string, reason string, actor string) error { return nil }
-func (m *mockStorage) SearchIssues(ctx context.Context, query string, filter types.IssueFilter) ([]*types.Issue, error) { return nil, nil }
-func (m *mockStorage) AddDependency(ctx context.Context, dep *types.Dependency, actor string) error { return nil }
-func (m *mockStorage) RemoveDependency(ctx context.Context, issueID, dependsOnID string, actor string) error { return nil }
-func (m *mockStorage) GetDependencies(ctx context.Context, issueID string) ([]*types.Issue, error) { return nil, nil }
-func (m *mockStorage) GetDependencyRecords(ctx context.Context, issueID string) ([]*types.Dependency, error) {
-	return nil, nil
-}
-func (m *mockStorage) GetDependents(ctx context.Context, issueID string) ([]*types.Issue, error) { return nil, nil }
-func (m *mockStorage) GetDependencyTree(ctx context.Context, issueID string, maxDepth int) ([]*types.TreeNode, error) { return nil, nil }
-func (m *mockStorage) DetectCycles(ctx context.Context) ([][]*types.Issue, error) { return nil, nil }
-func (m *mockStorage) AddLabel(ctx context.Context, issueID, label, actor string) error { return nil }
-func (m *mockStorage) RemoveLabel(ctx context.Context, issueID, label, actor string) error { return nil }
-func (m *mockStorage) GetLabels(ctx context.Context, issueID string) ([]string, error) { return nil, nil }
-func (m *mockStorage) GetIssuesByLabel(ctx context.Context, label string) ([]*types.Issue, error) { return nil, nil }
-func (m *mockStorage) GetReadyWork(ctx context.Context, filter types.WorkFilter) ([]*types.Issue, error) { return nil, nil }
-func (m *mockStorage) GetBlockedIssues(ctx context.Context) ([]*types.BlockedIssue, error) { return nil, nil }
-func (m *mockStorage) GetReadyBlockers(ctx context.Context, limit int) ([]*types.Issue, error) { return nil, nil }
-func (m *mockStorage) IsEpicComplete(ctx context.Context, epicID string) (bool, error) { return false, nil }
-func (m *mockStorage) GetMissionForTask(ctx context.Context, taskID string) (*types.MissionContext, error) { return nil, nil }
-func (m *mockStorage) GetMissionsNeedingGates(ctx context.Context) ([]*types.Issue, error) { return nil, nil }
-func (m *mockStorage) AddComment(ctx context.Context, issueID, actor, comment string) error { return nil }
-func (m *mockStorage) GetEvents(ctx context.Context, issueID string, limit int) ([]*types.Event, error) { return nil, nil }
-func (m *mockStorage) GetStatistics(ctx context.Context) (*types.Statistics, error) { return nil, nil }
-func (m *mockStorage) RegisterInstance(ctx context.Context, instance *types.ExecutorInstance) error { return nil }
-func (m *mockStorage) MarkInstanceStopped(ctx context.Context, instanceID string) error { return nil }
-func (m *mockStorage) UpdateHeartbeat(ctx context.Context, instanceID string) error { return nil }
-func (m *mockStorage) GetActiveInstances(ctx context.Context) ([]*types.ExecutorInstance, error) { return nil, nil }
-func (m *mockStorage) CleanupStaleInstances(ctx context.Context, staleThreshold int) (int, error) { return 0, nil }
-func (m *mockStorage) DeleteOldStoppedInstances(ctx context.Context, olderThanSeconds int, maxToKeep int) (int, error) { return 0, nil }
-func (m *mockStorage) ClaimIssue(ctx context.Context, issueID, executorInstanceID string) error { return nil }
-func (m *mockStorage) GetExecutionState(ctx context.Context, issueID string) (*types.IssueExecutionState, error) { return nil, nil }
-func (m *mockStorage) UpdateExecutionState(ctx context.Context, issueID string, state types.ExecutionState) error { return nil }
-func (m *mockStorage) SaveCheckpoint(ctx context.Context, issueID string, checkpointData interface{}) error { return nil }
-func (m *mockStorage) GetCheckpoint(ctx context.Context, issueID string) (string, error) { return "", nil }
-func (m *mockStorage) ReleaseIssue(ctx context.Context, issueID string) error { return nil }
-func (m *mockStorage) ReleaseIssueAndReopen(ctx context.Context, issueID, actor, errorComment string) error { return nil }
-func (m *mockStorage) GetExecutionHistory(ctx context.Context, issueID string) ([]*types.ExecutionAttempt, error) { return nil, nil }
-func (m *mockStorage) RecordExecutionAttempt(ctx context.Context, attempt *types.ExecutionAttempt) error { return nil }
-func (m *mockStorage) GetConfig(ctx context.Context, key string) (string, error) { return "", nil }
-func (m *mockStorage) SetConfig(ctx context.Context, key, value string) error { return nil }
-func (m *mockStorage) CleanupEventsByAge(ctx context.Context, retentionDays, criticalRetentionDays, batchSize int) (int, error) { return 0, nil }
-func (m *mockStorage) CleanupEventsByIssueLimit(ctx context.Context, perIssueLimit, batchSize int) (int, error) { return 0, nil }
-func (m *mockStorage) CleanupEventsByGlobalLimit(ctx context.Context, globalLimit, batchSize int) (int, error) { return 0, nil }
-func (m *mockStorage) GetEventCounts(ctx context.Context) (*types.EventCounts, error) { return &types.EventCounts{}, nil }
-func (m *mockStorage) VacuumDatabase(ctx context.Context) error { return nil }
-
 // createTestSupervisor creates a supervisor for testing
 // If ANTHROPIC_API_KEY is set, uses real AI calls; otherwise uses a test key (which will fail API calls)
 func createTestSupervisor(t *testing.T) *ai.Supervisor {
 	t.Helper()
-	store := &mockStorage{}
+	store := storagetest.NewFakeStorage()
 
 	// Use real API key from environment if available, otherwise use test key
 	// When test key is used, tests that call the AI will skip or fail gracefully
@@ -101,7 +37,7 @@ func createTestSupervisor(t *testing.T) *ai.Supervisor {
 func TestNewAnalyzer(t *testing.T) {
 	monitor := NewMonitor(nil)
 	supervisor := createTestSupervisor(t)
-	store := &mockStorage{}
+	store := storagetest.NewFakeStorage()
 
 	tests := []struct {
 		name      string
@@ -168,7 +104,7 @@ func TestNewAnalyzer(t *testing.T) {
 func TestDetectAnomalies_NoTelemetry(t *testing.T) {
 	monitor := NewMonitor(nil)
 	supervisor := createTestSupervisor(t)
-	store := &mockStorage{}
+	store := storagetest.NewFakeStorage()
 
 	analyzer, err := NewAnalyzer(&AnalyzerConfig{
 		Monitor:    monitor,
@@ -201,7 +137,7 @@ func TestDetectAnomalies_NoTelemetry(t *testing.T) {
 func TestDetectAnomalies_WithTelemetry(t *testing.T) {
 	monitor := NewMonitor(nil)
 	supervisor := createTestSupervisor(t)
-	store := &mockStorage{}
+	store := storagetest.NewFakeStorage()
 
 	// Add some mock telemetry
 	monitor.StartExecution("vc-test-1", "executor-1")
@@ -254,7 +190,7 @@ func TestDetectAnomalies_WithTelemetry(t *testing.T) {
 func TestDetectAnomalies_WithCurrentExecution(t *testing.T) {
 	monitor := NewMonitor(nil)
 	supervisor := createTestSupervisor(t)
-	store := &mockStorage{}
+	store := storagetest.NewFakeStorage()
 
 	// Add completed telemetry
 	monitor.StartExecution("vc-test-1", "executor-1")
@@ -296,7 +232,7 @@ func TestDetectAnomalies_WithCurrentExecution(t *testing.T) {
 func TestGetTelemetrySummary(t *testing.T) {
 	monitor := NewMonitor(nil)
 	supervisor := createTestSupervisor(t)
-	store := &mockStorage{}
+	store := storagetest.NewFakeStorage()
 
 	// Add various telemetry
 	// Success 1
@@ -359,7 +295,7 @@ func TestGetTelemetrySummary(t *testing.T) {
 func TestGetTelemetrySummary_WithCurrentExecution(t *testing.T) {
 	monitor := NewMonitor(nil)
 	supervisor := createTestSupervisor(t)
-	store := &mockStorage{}
+	store := storagetest.NewFakeStorage()
 
 	// Add one completed
 	monitor.StartExecution("vc-test-1", "executor-1")
@@ -392,7 +328,7 @@ func TestGetTelemetrySummary_WithCurrentExecution(t *testing.T) {
 func TestBuildAnomalyDetectionPrompt(t *testing.T) {
 	monitor := NewMonitor(nil)
 	supervisor := createTestSupervisor(t)
-	store := &mockStorage{}
+	store := storagetest.NewFakeStorage()
 
 	// Add some telemetry
 	monitor.StartExecution("vc-test-1", "executor-1")
@@ -444,7 +380,7 @@ func TestBuildAnomalyDetectionPrompt(t *testing.T) {
 func TestBuildAnomalyDetectionPrompt_WithCurrentExecution(t *testing.T) {
 	monitor := NewMonitor(nil)
 	supervisor := createTestSupervisor(t)
-	store := &mockStorage{}
+	store := storagetest.NewFakeStorage()
 
 	// Start current execution
 	monitor.StartExecution("vc-current", "executor-1")
@@ -480,7 +416,7 @@ func TestBuildAnomalyDetectionPrompt_WithCurrentExecution(t *testing.T) {
 func TestBuildAnomalyDetectionPrompt_TemporalContext(t *testing.T) {
 	monitor := NewMonitor(nil)
 	supervisor := createTestSupervisor(t)
-	store := &mockStorage{}
+	store := storagetest.NewFakeStorage()
 
 	// Add historical execution
 	monitor.StartExecution("vc-historical", "executor-1")
@@ -546,7 +482,7 @@ func TestBuildAnomalyDetectionPrompt_TemporalContext(t *testing.T) {
 func TestBuildAnomalyDetectionPrompt_AgentProgressIndicators(t *testing.T) {
 	monitor := NewMonitor(nil)
 	supervisor := createTestSupervisor(t)
-	store := &mockStorage{}
+	store := storagetest.NewFakeStorage()
 
 	// Start current execution with agent tool usage
 	monitor.StartExecution("vc-active", "executor-1")
@@ -620,7 +556,7 @@ func TestAnomalyReport_ZFCCompliance(t *testing.T) {
 
 	monitor := NewMonitor(nil)
 	supervisor := createTestSupervisor(t)
-	store := &mockStorage{}
+	store := storagetest.NewFakeStorage()
 
 	// Create analyzer
 	analyzer, err := NewAnalyzer(&AnalyzerConfig{
@@ -637,6 +573,8 @@ func TestAnomalyReport_ZFCCompliance(t *testing.T) {
 		monitor.StartExecution("vc-repeated", "executor-1")
 		monitor.EndExecution(false, false) // All failures
 	}
+	// Detection only runs while something executes: the 11th attempt
+	monitor.StartExecution("vc-repeated", "executor-1")
 
 	ctx := context.Background()
 	report, err := analyzer.DetectAnomalies(ctx)
@@ -708,3 +646,70 @@ func containsIgnoreCaseHelper(s, substr string) bool {
 	}
 	return false
 }
+
+// newCountingAnalyzer returns an analyzer whose AI calls are counted instead of sent
+func newCountingAnalyzer(t *testing.T, monitor *Monitor, cfg *WatchdogConfig) (*Analyzer, *int) {
+	t.Helper()
+	analyzer, err := NewAnalyzer(&AnalyzerConfig{
+		Monitor:    monitor,
+		Supervisor: createTestSupervisor(t),
+		Store:      storagetest.NewFakeStorage(),
+		Config:     cfg,
+	})
+	if err != nil {
+		t.Fatalf("failed to create analyzer: %v", err)
+	}
+
+	calls := 0
+	analyzer.callAI = func(ctx context.Context, prompt string) (string, error) {
+		calls++
+		return `{"detected": false, "description": "ok", "reasoning": "looks fine", "confidence": 0.9}`, nil
+	}
+	return analyzer, &calls
+}
+
+func TestDetectAnomalies_SkipsAIWithoutActiveExecution(t *testing.T) {
+	monitor := NewMonitor(nil)
+
+	// Completed history only - nothing currently executing
+	monitor.StartExecution("vc-test-1", "executor-1")
+	monitor.RecordEvent("test_run")
+	monitor.EndExecution(true, true)
+
+	analyzer, calls := newCountingAnalyzer(t, monitor, nil)
+	report, err := analyzer.DetectAnomalies(context.Background())
+	if err != nil {
+		t.Fatalf("DetectAnomalies failed: %v", err)
+	}
+	if report.Detected {
+		t.Error("expected no anomaly without an active execution")
+	}
+	if *calls != 0 {
+		t.Errorf("expected 0 AI calls without an active execution, got %d", *calls)
+	}
+}
+
+func TestDetectAnomalies_WarmupPeriod(t *testing.T) {
+	monitor := NewMonitor(nil)
+	monitor.StartExecution("vc-test-1", "executor-1")
+
+	cfg := DefaultWatchdogConfig()
+	analyzer, calls := newCountingAnalyzer(t, monitor, cfg)
+
+	// Fresh execution is within the default 2m warm-up
+	if _, err := analyzer.DetectAnomalies(context.Background()); err != nil {
+		t.Fatalf("DetectAnomalies failed: %v", err)
+	}
+	if *calls != 0 {
+		t.Errorf("expected 0 AI calls during warm-up, got %d", *calls)
+	}
+
+	// Disabling warm-up lets the same execution be analyzed
+	cfg.AIConfig.WarmupPeriod = 0
+	if _, err := analyzer.DetectAnomalies(context.Background()); err != nil {
+		t.Fatalf("DetectAnomalies failed: %v", err)
+	}
+	if *calls != 1 {
+		t.Errorf("expected 1 AI call after warm-up, got %d", *calls)
+	}
+}
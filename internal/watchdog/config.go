@@ -42,6 +42,15 @@ type WatchdogConfig struct {
 	// Default: 100
 	MaxHistorySize int `json:"max_history_size"`
 
+	// RelaxedMode holds thresholds for issues labeled watchdog:relaxed
+	RelaxedMode RelaxedModeConfig `json:"relaxed_mode"`
+
+	// StallDetection holds settings for the heuristic stalled-agent check
+	StallDetection StallDetectionConfig `json:"stall_detection"`
+
+	// LoopDetection holds settings for the heuristic repeated-output check
+	LoopDetection LoopDetectionConfig `json:"loop_detection"`
+
 	// detectionStates tracks consecutive detections per anomaly type
 	// This supports accumulation-based intervention logic (vc-227)
 	detectionStates map[AnomalyType]*DetectionState
@@ -66,6 +75,61 @@ type AIConfig struct {
 	// are logged for debugging and analysis
 	// Default: true
 	EnableAnomalyLogging bool `json:"enable_anomaly_logging"`
+
+	// WarmupPeriod skips AI anomaly detection for executions younger than this,
+	// since early telemetry is meaningless (0 disables warm-up)
+	// Default: 2 minutes
+	WarmupPeriod time.Duration `json:"warmup_period"`
+}
+
+// RelaxedModeConfig holds the thresholds applied to issues labeled watchdog:relaxed
+type RelaxedModeConfig struct {
+	// ThresholdMultiplier scales accumulation thresholds (e.g. how many consecutive
+	// stuck_state detections and how long stuck before intervening)
+	// Default: 3.0
+	ThresholdMultiplier float64 `json:"threshold_multiplier"`
+
+	// MinSeverityLevel is the minimum severity that triggers intervention for relaxed
+	// issues; the stricter of this and ai_config.min_severity_level applies
+	// Default: "critical"
+	MinSeverityLevel AnomalySeverity `json:"min_severity_level"`
+}
+
+// StallDetectionConfig holds settings for detecting agents that stop producing output.
+// Unlike AI anomaly detection this is a cheap timestamp comparison run every check.
+type StallDetectionConfig struct {
+	// Enabled controls whether silent agents are canceled
+	// Default: true
+	Enabled bool `json:"enabled"`
+
+	// SilenceThreshold is how long an agent may produce no events before it is stalled
+	// Default: 10 minutes
+	SilenceThreshold time.Duration `json:"silence_threshold"`
+
+	// EstimateSilenceRatio raises the threshold for issues with an estimate, since
+	// long builds are legitimately quiet: max(SilenceThreshold, estimate * ratio)
+	// Default: 0.25
+	EstimateSilenceRatio float64 `json:"estimate_silence_ratio"`
+}
+
+// LoopDetectionConfig holds settings for detecting agents that repeat the same
+// output (e.g. retrying one failing command hundreds of times).
+type LoopDetectionConfig struct {
+	// Enabled controls whether looping agents are canceled
+	// Default: true
+	Enabled bool `json:"enabled"`
+
+	// WindowSize is the number of recent agent event messages compared
+	// Default: 50
+	WindowSize int `json:"window_size"`
+
+	// RepetitionRatio is the fraction of the window that must be repeats (0.0-1.0)
+	// Default: 0.8
+	RepetitionRatio float64 `json:"repetition_ratio"`
+
+	// SustainedDuration is how long the ratio must stay above the threshold
+	// Default: 2 minutes
+	SustainedDuration time.Duration `json:"sustained_duration"`
 }
 
 // InterventionConfig holds intervention policy settings
@@ -88,6 +152,10 @@ type InterventionConfig struct {
 	// EscalationPriority maps anomaly severity to escalation issue priority
 	// Default: critical=P0, high=P1, medium=P2, low=P3
 	EscalationPriority map[AnomalySeverity]int `json:"escalation_priority"`
+
+	// Policy maps anomaly types and severities to intervention actions
+	// Default: defer to the AI's recommended action for every anomaly
+	Policy InterventionPolicy `json:"policy"`
 }
 
 // DefaultWatchdogConfig returns a watchdog configuration with safe, conservative defaults
@@ -105,6 +173,7 @@ func DefaultWatchdogConfig() *WatchdogConfig {
 			MinConfidenceThreshold: 0.75,
 			MinSeverityLevel:       SeverityHigh,
 			EnableAnomalyLogging:   true,
+			WarmupPeriod:           2 * time.Minute,
 		},
 		InterventionConfig: InterventionConfig{
 			AutoKillEnabled:    true,
@@ -116,8 +185,19 @@ func DefaultWatchdogConfig() *WatchdogConfig {
 				SeverityMedium:   2, // P2
 				SeverityLow:      3, // P3
 			},
+			Policy: DefaultInterventionPolicy(),
+		},
+		MaxHistorySize: 100,
+		RelaxedMode: RelaxedModeConfig{
+			ThresholdMultiplier: 3.0,
+			MinSeverityLevel:    SeverityCritical,
 		},
-		MaxHistorySize:  100,
+		StallDetection: StallDetectionConfig{
+			Enabled:              true,
+			SilenceThreshold:     10 * time.Minute,
+			EstimateSilenceRatio: 0.25,
+		},
+		LoopDetection: DefaultLoopDetectionConfig(),
 		detectionStates: make(map[AnomalyType]*DetectionState),
 	}
 }
@@ -173,6 +253,26 @@ func LoadFromEnv() *WatchdogConfig {
 		}
 	}
 
+	if val := os.Getenv("VC_WATCHDOG_STALL_DETECTION"); val != "" {
+		cfg.StallDetection.Enabled = parseBool(val)
+	}
+
+	if val := os.Getenv("VC_WATCHDOG_SILENCE_THRESHOLD"); val != "" {
+		if duration, err := time.ParseDuration(val); err == nil {
+			cfg.StallDetection.SilenceThreshold = duration
+		}
+	}
+
+	if val := os.Getenv("VC_WATCHDOG_WARMUP_PERIOD"); val != "" {
+		if duration, err := time.ParseDuration(val); err == nil {
+			cfg.AIConfig.WarmupPeriod = duration
+		}
+	}
+
+	if val := os.Getenv("VC_WATCHDOG_LOOP_DETECTION"); val != "" {
+		cfg.LoopDetection.Enabled = parseBool(val)
+	}
+
 	if val := os.Getenv("VC_WATCHDOG_MAX_HISTORY"); val != "" {
 		if size, err := strconv.Atoi(val); err == nil && size > 0 {
 			cfg.MaxHistorySize = size
@@ -209,6 +309,10 @@ func LoadFromEnv() *WatchdogConfig {
 		cfg.InterventionConfig.EscalateOnCritical = parseBool(val)
 	}
 
+	if val := os.Getenv("VC_WATCHDOG_DEFAULT_ACTION"); val != "" {
+		cfg.InterventionConfig.Policy.DefaultAction = PolicyAction(val)
+	}
+
 	// Validate after loading from env
 	if err := cfg.validate(); err != nil {
 		fmt.Printf("Warning: invalid watchdog config from environment: %v\n", err)
@@ -263,6 +367,9 @@ func (c *WatchdogConfig) validate() error {
 	if c.AIConfig.MinConfidenceThreshold < 0.0 || c.AIConfig.MinConfidenceThreshold > 1.0 {
 		return fmt.Errorf("min_confidence_threshold must be between 0.0 and 1.0, got %f", c.AIConfig.MinConfidenceThreshold)
 	}
+	if c.AIConfig.WarmupPeriod < 0 {
+		return fmt.Errorf("warmup_period must be non-negative, got %v", c.AIConfig.WarmupPeriod)
+	}
 
 	// Validate severity level
 	validSeverities := map[AnomalySeverity]bool{
@@ -288,6 +395,11 @@ func (c *WatchdogConfig) validate() error {
 		c.InterventionConfig.EscalationPriority = DefaultWatchdogConfig().InterventionConfig.EscalationPriority
 	}
 
+	// Validate intervention policy
+	if err := c.InterventionConfig.Policy.Validate(); err != nil {
+		return fmt.Errorf("invalid intervention policy: %w", err)
+	}
+
 	// History size validation
 	if c.MaxHistorySize <= 0 {
 		return fmt.Errorf("max_history_size must be positive, got %d", c.MaxHistorySize)
@@ -296,6 +408,55 @@ func (c *WatchdogConfig) validate() error {
 		return fmt.Errorf("max_history_size too large (maximum 10000), got %d", c.MaxHistorySize)
 	}
 
+	// Relaxed mode validation (zero values fall back to defaults)
+	if c.RelaxedMode.ThresholdMultiplier == 0 {
+		c.RelaxedMode.ThresholdMultiplier = DefaultWatchdogConfig().RelaxedMode.ThresholdMultiplier
+	}
+	if c.RelaxedMode.ThresholdMultiplier < 1.0 || c.RelaxedMode.ThresholdMultiplier > 100.0 {
+		return fmt.Errorf("relaxed_mode.threshold_multiplier must be between 1.0 and 100.0, got %f", c.RelaxedMode.ThresholdMultiplier)
+	}
+	if c.RelaxedMode.MinSeverityLevel == "" {
+		c.RelaxedMode.MinSeverityLevel = DefaultWatchdogConfig().RelaxedMode.MinSeverityLevel
+	}
+	if !isValidSeverity(c.RelaxedMode.MinSeverityLevel) {
+		return fmt.Errorf("invalid relaxed_mode.min_severity_level: %s (must be low, medium, high, or critical)", c.RelaxedMode.MinSeverityLevel)
+	}
+
+	// Stall detection validation (zero values fall back to defaults)
+	if c.StallDetection.SilenceThreshold == 0 {
+		c.StallDetection.SilenceThreshold = DefaultWatchdogConfig().StallDetection.SilenceThreshold
+	}
+	if c.StallDetection.SilenceThreshold < time.Minute {
+		return fmt.Errorf("stall_detection.silence_threshold too short (minimum 1m), got %v", c.StallDetection.SilenceThreshold)
+	}
+	if c.StallDetection.EstimateSilenceRatio == 0 {
+		c.StallDetection.EstimateSilenceRatio = DefaultWatchdogConfig().StallDetection.EstimateSilenceRatio
+	}
+	if c.StallDetection.EstimateSilenceRatio < 0 || c.StallDetection.EstimateSilenceRatio > 1.0 {
+		return fmt.Errorf("stall_detection.estimate_silence_ratio must be between 0.0 and 1.0, got %f", c.StallDetection.EstimateSilenceRatio)
+	}
+
+	// Loop detection validation (zero values fall back to defaults)
+	defaultLoop := DefaultLoopDetectionConfig()
+	if c.LoopDetection.WindowSize == 0 {
+		c.LoopDetection.WindowSize = defaultLoop.WindowSize
+	}
+	if c.LoopDetection.WindowSize < 10 || c.LoopDetection.WindowSize > 10000 {
+		return fmt.Errorf("loop_detection.window_size must be between 10 and 10000, got %d", c.LoopDetection.WindowSize)
+	}
+	if c.LoopDetection.RepetitionRatio == 0 {
+		c.LoopDetection.RepetitionRatio = defaultLoop.RepetitionRatio
+	}
+	if c.LoopDetection.RepetitionRatio < 0 || c.LoopDetection.RepetitionRatio > 1.0 {
+		return fmt.Errorf("loop_detection.repetition_ratio must be between 0.0 and 1.0, got %f", c.LoopDetection.RepetitionRatio)
+	}
+	if c.LoopDetection.SustainedDuration == 0 {
+		c.LoopDetection.SustainedDuration = defaultLoop.SustainedDuration
+	}
+	if c.LoopDetection.SustainedDuration < 0 {
+		return fmt.Errorf("loop_detection.sustained_duration must be non-negative, got %v", c.LoopDetection.SustainedDuration)
+	}
+
 	return nil
 }
 
@@ -328,14 +489,19 @@ func (c *WatchdogConfig) Clone() *WatchdogConfig {
 			MinConfidenceThreshold: c.AIConfig.MinConfidenceThreshold,
 			MinSeverityLevel:       c.AIConfig.MinSeverityLevel,
 			EnableAnomalyLogging:   c.AIConfig.EnableAnomalyLogging,
+			WarmupPeriod:           c.AIConfig.WarmupPeriod,
 		},
 		InterventionConfig: InterventionConfig{
 			AutoKillEnabled:    c.InterventionConfig.AutoKillEnabled,
 			MaxRetries:         c.InterventionConfig.MaxRetries,
 			EscalateOnCritical: c.InterventionConfig.EscalateOnCritical,
 			EscalationPriority: escPriority,
+			Policy:             c.InterventionConfig.Policy.Clone(),
 		},
 		MaxHistorySize:  c.MaxHistorySize,
+		RelaxedMode:     c.RelaxedMode,
+		StallDetection:  c.StallDetection,
+		LoopDetection:   c.LoopDetection,
 		detectionStates: detectionStates,
 	}
 }
@@ -392,6 +558,16 @@ func (c *WatchdogConfig) GetCheckInterval() time.Duration {
 	return c.CheckInterval
 }
 
+// GetWarmupPeriod returns the AI detection warm-up period (thread-safe, nil-safe)
+func (c *WatchdogConfig) GetWarmupPeriod() time.Duration {
+	if c == nil {
+		return 0
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.AIConfig.WarmupPeriod
+}
+
 // SetCheckInterval updates the check interval at runtime
 func (c *WatchdogConfig) SetCheckInterval(interval time.Duration) error {
 	// Validate the new interval
@@ -414,13 +590,25 @@ func (c *WatchdogConfig) SetCheckInterval(interval time.Duration) error {
 //
 // Other anomaly types use standard thresholds (confidence + severity)
 func (c *WatchdogConfig) ShouldIntervene(report *AnomalyReport) bool {
+	return c.ShouldInterveneForMode(report, ProtectionNormal)
+}
+
+// ShouldInterveneForMode is ShouldIntervene for an execution whose protection was
+// reduced via labels: ProtectionOff never intervenes, ProtectionRelaxed multiplies
+// accumulation thresholds and raises the minimum severity (see RelaxedModeConfig)
+func (c *WatchdogConfig) ShouldInterveneForMode(report *AnomalyReport, mode ProtectionMode) bool {
 	c.mu.Lock() // Need write lock to update detection states
 	defer c.mu.Unlock()
 
-	if !c.Enabled {
+	if !c.Enabled || mode == ProtectionOff {
 		return false
 	}
 
+	multiplier := 1.0
+	if mode == ProtectionRelaxed && c.RelaxedMode.ThresholdMultiplier > 1.0 {
+		multiplier = c.RelaxedMode.ThresholdMultiplier
+	}
+
 	if !report.Detected {
 		// No anomaly detected - just return false
 		// Don't clear states; they'll naturally expire or reset on next detection
@@ -455,14 +643,14 @@ func (c *WatchdogConfig) ShouldIntervene(report *AnomalyReport) bool {
 
 	// Special accumulation logic for stuck_state (vc-227)
 	if report.AnomalyType == AnomalyStuckState {
-		// Condition 1: 10 consecutive detections
-		if state.ConsecutiveCount >= 10 {
+		// Condition 1: 10 consecutive detections (scaled in relaxed mode)
+		if float64(state.ConsecutiveCount) >= 10*multiplier {
 			return true
 		}
 
-		// Condition 2: Stuck for 3+ minutes
+		// Condition 2: Stuck for 3+ minutes (scaled in relaxed mode)
 		stuckDuration := now.Sub(state.FirstDetectedAt)
-		if stuckDuration >= 3*time.Minute {
+		if stuckDuration >= time.Duration(float64(3*time.Minute)*multiplier) {
 			return true
 		}
 
@@ -477,14 +665,36 @@ func (c *WatchdogConfig) ShouldIntervene(report *AnomalyReport) bool {
 	}
 
 	// Check severity threshold
+	if mode == ProtectionRelaxed && !meetsSeverity(report.Severity, c.RelaxedMode.MinSeverityLevel) {
+		return false
+	}
 	return c.meetsMinSeverity(report.Severity)
 }
 
+// ResolvePolicy returns the intervention action for an anomaly report and
+// the policy entry that selected it (thread-safe)
+func (c *WatchdogConfig) ResolvePolicy(report *AnomalyReport) (PolicyAction, string) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.InterventionConfig.Policy.Resolve(report.AnomalyType, report.Severity)
+}
+
+// PolicySafetyWarning returns a loud warning if the intervention policy
+// effectively disables protection, or "" if it is safe (thread-safe)
+func (c *WatchdogConfig) PolicySafetyWarning() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.InterventionConfig.Policy.SafetyWarning()
+}
+
 // meetsMinSeverity checks if a severity level meets the minimum threshold
 // MUST be called with c.mu held (read or write lock)
 func (c *WatchdogConfig) meetsMinSeverity(severity AnomalySeverity) bool {
-	minSev := c.AIConfig.MinSeverityLevel
+	return meetsSeverity(severity, c.AIConfig.MinSeverityLevel)
+}
 
+// meetsSeverity checks if severity is at least minSev
+func meetsSeverity(severity, minSev AnomalySeverity) bool {
 	// Severity ordering: low < medium < high < critical
 	severityOrder := map[AnomalySeverity]int{
 		SeverityLow:      1,
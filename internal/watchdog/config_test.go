@@ -612,3 +612,21 @@ func TestSaveToFile(t *testing.T) {
 		t.Error("Check interval not saved correctly")
 	}
 }
+
+func TestWatchdogConfig_ValidatePolicy(t *testing.T) {
+	cfg := DefaultWatchdogConfig()
+	cfg.InterventionConfig.Policy = InterventionPolicy{
+		Rules: []PolicyRule{{AnomalyType: AnomalyStuckState, Action: "reboot"}},
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected validation error for unknown policy action")
+	}
+
+	clone := DefaultWatchdogConfig()
+	clone.InterventionConfig.Policy.Rules = []PolicyRule{{AnomalyType: AnomalyStuckState, Action: PolicyComment}}
+	copied := clone.Clone()
+	copied.InterventionConfig.Policy.Rules[0].Action = PolicyLogOnly
+	if clone.InterventionConfig.Policy.Rules[0].Action != PolicyComment {
+		t.Error("Clone should deep-copy policy rules")
+	}
+}
@@ -6,11 +6,11 @@ import (
 	"testing"
 
 	"github.com/steveyegge/vc/internal/ai"
+	"github.com/steveyegge/vc/internal/storage/storagetest"
 )
 
 func TestNewGitSafetyMonitor(t *testing.T) {
-	// Use mockStorage from analyzer_test.go
-	mockStore := &mockStorage{}
+	store := storagetest.NewFakeStorage()
 
 	tests := []struct {
 		name    string
@@ -22,14 +22,14 @@ func TestNewGitSafetyMonitor(t *testing.T) {
 			name: "valid config",
 			cfg: &GitSafetyMonitorConfig{
 				Supervisor: &ai.Supervisor{},
-				Store:      mockStore,
+				Store:      store,
 			},
 			wantErr: false,
 		},
 		{
 			name: "missing supervisor",
 			cfg: &GitSafetyMonitorConfig{
-				Store: mockStore,
+				Store: store,
 			},
 			wantErr: true,
 			errMsg:  "supervisor is required",
@@ -46,7 +46,7 @@ func TestNewGitSafetyMonitor(t *testing.T) {
 			name: "with custom config",
 			cfg: &GitSafetyMonitorConfig{
 				Supervisor: &ai.Supervisor{},
-				Store:      mockStore,
+				Store:      store,
 				Config:     DefaultWatchdogConfig(),
 			},
 			wantErr: false,
@@ -250,8 +250,8 @@ func TestParseCommand(t *testing.T) {
 }
 
 func TestEvaluateCommand_Mock(t *testing.T) {
-	// Create a mock store
-	mockStore := &mockStorage{}
+	// Create a fake store
+	store := storagetest.NewFakeStorage()
 	ctx := context.Background()
 
 	// Note: We can't fully test EvaluateCommand without a real supervisor
@@ -260,7 +260,7 @@ func TestEvaluateCommand_Mock(t *testing.T) {
 
 	monitor := &GitSafetyMonitor{
 		supervisor: nil, // We'll use the mock in callAISupervisor
-		store:      mockStore,
+		store:      store,
 		config:     DefaultWatchdogConfig(),
 	}
 
@@ -285,12 +285,12 @@ func TestEvaluateCommand_Mock(t *testing.T) {
 }
 
 func TestCheckDangerousOperation_SafeCommand(t *testing.T) {
-	mockStore := &mockStorage{}
+	store := storagetest.NewFakeStorage()
 	ctx := context.Background()
 
 	monitor := &GitSafetyMonitor{
 		supervisor: nil, // Will use mock
-		store:      mockStore,
+		store:      store,
 		config:     DefaultWatchdogConfig(),
 	}
 
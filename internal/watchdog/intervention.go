@@ -6,10 +6,15 @@ import (
 	"sync"
 	"time"
 
+	"github.com/steveyegge/vc/internal/artifacts"
+	"github.com/steveyegge/vc/internal/deduplication"
 	"github.com/steveyegge/vc/internal/storage"
 	"github.com/steveyegge/vc/internal/types"
 )
 
+// escalationLabel marks issues filed by the watchdog for human review
+const escalationLabel = types.EscalationLabel
+
 // InterventionType categorizes the type of intervention taken
 type InterventionType string
 
@@ -18,6 +23,9 @@ const (
 	InterventionKillAgent        InterventionType = "kill_agent"
 	InterventionPauseExecutor    InterventionType = "pause_executor"
 	InterventionRequestCheckpoint InterventionType = "request_checkpoint"
+	InterventionLogOnly          InterventionType = "log_only"
+	InterventionComment          InterventionType = "comment"
+	InterventionCancelAndBlock   InterventionType = "cancel_and_block"
 )
 
 // InterventionResult represents the outcome of an intervention
@@ -34,6 +42,9 @@ type InterventionResult struct {
 	Message string
 	// Timestamp is when the intervention occurred
 	Timestamp time.Time
+	// PolicyEntry identifies the intervention policy entry that selected this action
+	// (empty when the intervention was invoked directly rather than via Intervene)
+	PolicyEntry string
 }
 
 // InterventionController manages watchdog interventions when anomalies are detected
@@ -52,6 +63,16 @@ type InterventionController struct {
 	// executorInstanceID identifies this executor instance
 	executorInstanceID string
 
+	// config supplies the intervention policy consulted by Intervene
+	config *WatchdogConfig
+
+	// deduplicator finds semantically duplicate escalations (nil without AI)
+	deduplicator deduplication.Deduplicator
+
+	// commentLimit caps the intervention comments, which quote the AI's
+	// description of the anomaly
+	commentLimit artifacts.CommentLimit
+
 	// interventionHistory tracks recent interventions for reporting
 	interventionHistory []InterventionResult
 	maxHistorySize      int
@@ -61,7 +82,10 @@ type InterventionController struct {
 type InterventionControllerConfig struct {
 	Store              storage.Storage
 	ExecutorInstanceID string
-	MaxHistorySize     int // Maximum number of interventions to keep in memory (default: 100)
+	MaxHistorySize     int             // Maximum number of interventions to keep in memory (default: 100)
+	Config             *WatchdogConfig // Intervention policy source (default: DefaultWatchdogConfig)
+	Deduplicator       deduplication.Deduplicator // Optional AI fallback for finding existing escalations
+	CommentLimit       artifacts.CommentLimit     // Size cap of intervention comments (zero = no cap)
 }
 
 // NewInterventionController creates a new intervention controller
@@ -78,9 +102,17 @@ func NewInterventionController(cfg *InterventionControllerConfig) (*Intervention
 		maxHistorySize = 100
 	}
 
+	config := cfg.Config
+	if config == nil {
+		config = DefaultWatchdogConfig()
+	}
+
 	return &InterventionController{
 		store:               cfg.Store,
 		executorInstanceID:  cfg.ExecutorInstanceID,
+		config:              config,
+		deduplicator:        cfg.Deduplicator,
+		commentLimit:        cfg.CommentLimit,
 		interventionHistory: make([]InterventionResult, 0, maxHistorySize),
 		maxHistorySize:      maxHistorySize,
 	}, nil
@@ -110,10 +142,13 @@ func (ic *InterventionController) ClearAgentContext() {
 // PauseAgent pauses the currently executing agent by canceling its context
 // This triggers a graceful shutdown where the agent should clean up and stop
 func (ic *InterventionController) PauseAgent(ctx context.Context, report *AnomalyReport) (*InterventionResult, error) {
-	ic.mu.Lock()
-	defer ic.mu.Unlock()
+	return ic.pauseAgent(ctx, report, "")
+}
 
-	if ic.cancelFunc == nil {
+// pauseAgent implements PauseAgent, recording which policy entry (if any) selected it
+func (ic *InterventionController) pauseAgent(ctx context.Context, report *AnomalyReport, policyEntry string) (*InterventionResult, error) {
+	currentIssueID, cancel := ic.agentContext()
+	if cancel == nil {
 		return nil, fmt.Errorf("no active agent to pause")
 	}
 
@@ -121,16 +156,16 @@ func (ic *InterventionController) PauseAgent(ctx context.Context, report *Anomal
 		Success:          true,
 		InterventionType: InterventionPauseAgent,
 		AnomalyReport:    report,
-		Message:          fmt.Sprintf("Paused agent executing %s due to %s anomaly", ic.currentIssueID, report.AnomalyType),
+		Message:          fmt.Sprintf("Paused agent executing %s due to %s anomaly", currentIssueID, report.AnomalyType),
 		Timestamp:        time.Now(),
+		PolicyEntry:      policyEntry,
 	}
 
 	// Cancel the agent's context to trigger graceful shutdown
-	ic.cancelFunc()
+	cancel()
 
 	// Create escalation issue for human review
-	// Pass currentIssueID to avoid reading without lock
-	escalationID, err := ic.createEscalationIssue(ctx, report, InterventionPauseAgent, ic.currentIssueID)
+	escalationID, err := ic.createEscalationIssue(ctx, report, InterventionPauseAgent, currentIssueID)
 	if err != nil {
 		result.Message += fmt.Sprintf(" (warning: failed to create escalation issue: %v)", err)
 	} else {
@@ -138,14 +173,14 @@ func (ic *InterventionController) PauseAgent(ctx context.Context, report *Anomal
 	}
 
 	// Emit watchdog event
-	if err := ic.emitWatchdogEvent(ctx, result, ic.currentIssueID); err != nil {
+	if err := ic.emitWatchdogEvent(ctx, result, currentIssueID); err != nil {
 		result.Message += fmt.Sprintf(" (warning: failed to emit event: %v)", err)
 	}
 
 	// Add to history
-	ic.addToHistoryLocked(result)
+	ic.addToHistory(ctx, result, currentIssueID)
 
-	fmt.Printf("Watchdog: Paused agent for issue %s (escalation: %s)\n", ic.currentIssueID, escalationID)
+	fmt.Printf("Watchdog: Paused agent for issue %s (escalation: %s)\n", currentIssueID, escalationID)
 
 	return result, nil
 }
@@ -153,10 +188,13 @@ func (ic *InterventionController) PauseAgent(ctx context.Context, report *Anomal
 // KillAgent immediately kills the currently executing agent by canceling its context
 // This is a more aggressive intervention than PauseAgent
 func (ic *InterventionController) KillAgent(ctx context.Context, report *AnomalyReport) (*InterventionResult, error) {
-	ic.mu.Lock()
-	defer ic.mu.Unlock()
+	return ic.killAgent(ctx, report, "")
+}
 
-	if ic.cancelFunc == nil {
+// killAgent implements KillAgent, recording which policy entry (if any) selected it
+func (ic *InterventionController) killAgent(ctx context.Context, report *AnomalyReport, policyEntry string) (*InterventionResult, error) {
+	currentIssueID, cancel := ic.agentContext()
+	if cancel == nil {
 		return nil, fmt.Errorf("no active agent to kill")
 	}
 
@@ -164,16 +202,16 @@ func (ic *InterventionController) KillAgent(ctx context.Context, report *Anomaly
 		Success:          true,
 		InterventionType: InterventionKillAgent,
 		AnomalyReport:    report,
-		Message:          fmt.Sprintf("Killed agent executing %s due to %s anomaly (severity: %s)", ic.currentIssueID, report.AnomalyType, report.Severity),
+		Message:          fmt.Sprintf("Killed agent executing %s due to %s anomaly (severity: %s)", currentIssueID, report.AnomalyType, report.Severity),
 		Timestamp:        time.Now(),
+		PolicyEntry:      policyEntry,
 	}
 
 	// Cancel the agent's context immediately
-	ic.cancelFunc()
+	cancel()
 
 	// Create escalation issue for human review
-	// Pass currentIssueID to avoid reading without lock
-	escalationID, err := ic.createEscalationIssue(ctx, report, InterventionKillAgent, ic.currentIssueID)
+	escalationID, err := ic.createEscalationIssue(ctx, report, InterventionKillAgent, currentIssueID)
 	if err != nil {
 		result.Message += fmt.Sprintf(" (warning: failed to create escalation issue: %v)", err)
 	} else {
@@ -181,14 +219,14 @@ func (ic *InterventionController) KillAgent(ctx context.Context, report *Anomaly
 	}
 
 	// Emit watchdog event
-	if err := ic.emitWatchdogEvent(ctx, result, ic.currentIssueID); err != nil {
+	if err := ic.emitWatchdogEvent(ctx, result, currentIssueID); err != nil {
 		result.Message += fmt.Sprintf(" (warning: failed to emit event: %v)", err)
 	}
 
 	// Add to history
-	ic.addToHistoryLocked(result)
+	ic.addToHistory(ctx, result, currentIssueID)
 
-	fmt.Printf("Watchdog: Killed agent for issue %s (escalation: %s)\n", ic.currentIssueID, escalationID)
+	fmt.Printf("Watchdog: Killed agent for issue %s (escalation: %s)\n", currentIssueID, escalationID)
 
 	return result, nil
 }
@@ -205,8 +243,12 @@ func (ic *InterventionController) KillAgent(ctx context.Context, report *Anomaly
 // TODO(vc-executor): Implement actual executor pause mechanism
 // For now, this creates a high-priority escalation issue that requires human intervention.
 func (ic *InterventionController) PauseExecutor(ctx context.Context, report *AnomalyReport) (*InterventionResult, error) {
-	ic.mu.Lock()
-	defer ic.mu.Unlock()
+	return ic.pauseExecutor(ctx, report, "")
+}
+
+// pauseExecutor implements PauseExecutor, recording which policy entry (if any) selected it
+func (ic *InterventionController) pauseExecutor(ctx context.Context, report *AnomalyReport, policyEntry string) (*InterventionResult, error) {
+	currentIssueID, _ := ic.agentContext()
 
 	result := &InterventionResult{
 		Success:          true,
@@ -214,11 +256,11 @@ func (ic *InterventionController) PauseExecutor(ctx context.Context, report *Ano
 		AnomalyReport:    report,
 		Message:          fmt.Sprintf("Created escalation for executor %s pause due to %s anomaly (severity: %s)", ic.executorInstanceID, report.AnomalyType, report.Severity),
 		Timestamp:        time.Now(),
+		PolicyEntry:      policyEntry,
 	}
 
 	// Create escalation issue for human review
-	// Pass currentIssueID to avoid reading without lock
-	escalationID, err := ic.createEscalationIssue(ctx, report, InterventionPauseExecutor, ic.currentIssueID)
+	escalationID, err := ic.createEscalationIssue(ctx, report, InterventionPauseExecutor, currentIssueID)
 	if err != nil {
 		result.Message += fmt.Sprintf(" (warning: failed to create escalation issue: %v)", err)
 	} else {
@@ -226,12 +268,12 @@ func (ic *InterventionController) PauseExecutor(ctx context.Context, report *Ano
 	}
 
 	// Emit watchdog event
-	if err := ic.emitWatchdogEvent(ctx, result, ic.currentIssueID); err != nil {
+	if err := ic.emitWatchdogEvent(ctx, result, currentIssueID); err != nil {
 		result.Message += fmt.Sprintf(" (warning: failed to emit event: %v)", err)
 	}
 
 	// Add to history
-	ic.addToHistoryLocked(result)
+	ic.addToHistory(ctx, result, currentIssueID)
 
 	// NOTE: This does not actually pause the executor - just creates an escalation issue.
 	// The executor implementation needs to check for pause signals/escalations in its main loop.
@@ -250,10 +292,13 @@ func (ic *InterventionController) PauseExecutor(ctx context.Context, report *Ano
 //  3. Gracefully terminate
 // The next worker can then pick up the checkpoint and continue
 func (ic *InterventionController) RequestCheckpoint(ctx context.Context, report *AnomalyReport) (*InterventionResult, error) {
-	ic.mu.Lock()
-	defer ic.mu.Unlock()
+	return ic.requestCheckpoint(ctx, report, "")
+}
 
-	if ic.cancelFunc == nil {
+// requestCheckpoint implements RequestCheckpoint, recording which policy entry (if any) selected it
+func (ic *InterventionController) requestCheckpoint(ctx context.Context, report *AnomalyReport, policyEntry string) (*InterventionResult, error) {
+	currentIssueID, cancel := ic.agentContext()
+	if cancel == nil {
 		return nil, fmt.Errorf("no active agent to checkpoint")
 	}
 
@@ -261,17 +306,17 @@ func (ic *InterventionController) RequestCheckpoint(ctx context.Context, report
 		Success:          true,
 		InterventionType: InterventionRequestCheckpoint,
 		AnomalyReport:    report,
-		Message:          fmt.Sprintf("Requested checkpoint for issue %s due to context exhaustion (%.1f%% usage)", ic.currentIssueID, report.Confidence*100),
+		Message:          fmt.Sprintf("Requested checkpoint for issue %s due to context exhaustion (%.1f%% usage)", currentIssueID, report.Confidence*100),
 		Timestamp:        time.Now(),
+		PolicyEntry:      policyEntry,
 	}
 
 	// Cancel the agent's context to trigger graceful shutdown
 	// The agent should detect this and checkpoint its state
-	ic.cancelFunc()
+	cancel()
 
 	// Create escalation issue documenting the checkpoint request
-	// Pass currentIssueID to avoid reading without lock
-	escalationID, err := ic.createEscalationIssue(ctx, report, InterventionRequestCheckpoint, ic.currentIssueID)
+	escalationID, err := ic.createEscalationIssue(ctx, report, InterventionRequestCheckpoint, currentIssueID)
 	if err != nil {
 		result.Message += fmt.Sprintf(" (warning: failed to create escalation issue: %v)", err)
 	} else {
@@ -279,37 +324,37 @@ func (ic *InterventionController) RequestCheckpoint(ctx context.Context, report
 	}
 
 	// Emit watchdog event
-	if err := ic.emitWatchdogEvent(ctx, result, ic.currentIssueID); err != nil {
+	if err := ic.emitWatchdogEvent(ctx, result, currentIssueID); err != nil {
 		result.Message += fmt.Sprintf(" (warning: failed to emit event: %v)", err)
 	}
 
 	// Add to history
-	ic.addToHistoryLocked(result)
+	ic.addToHistory(ctx, result, currentIssueID)
 
-	fmt.Printf("Watchdog: Requested checkpoint for issue %s (escalation: %s)\n", ic.currentIssueID, escalationID)
+	fmt.Printf("Watchdog: Requested checkpoint for issue %s (escalation: %s)\n", currentIssueID, escalationID)
 
 	return result, nil
 }
 
 // notifyHuman creates an escalation issue without stopping execution
 // This is used for anomalies that need human attention but aren't critical
-func (ic *InterventionController) notifyHuman(ctx context.Context, report *AnomalyReport) (*InterventionResult, error) {
-	ic.mu.Lock()
-	defer ic.mu.Unlock()
+func (ic *InterventionController) notifyHuman(ctx context.Context, report *AnomalyReport, policyEntry string) (*InterventionResult, error) {
+	currentIssueID, _ := ic.agentContext()
 
 	result := &InterventionResult{
 		Success:          true,
 		InterventionType: InterventionPauseAgent, // Use pause as the type for notification
 		AnomalyReport:    report,
-		Message:          fmt.Sprintf("Notified human about %s anomaly in %s", report.AnomalyType, ic.currentIssueID),
+		Message:          fmt.Sprintf("Notified human about %s anomaly in %s", report.AnomalyType, currentIssueID),
 		Timestamp:        time.Now(),
+		PolicyEntry:      policyEntry,
 	}
 
-	currentIssueID := ic.currentIssueID
 	escalationID, err := ic.createEscalationIssue(ctx, report, InterventionPauseAgent, currentIssueID)
 	if err != nil {
 		result.Success = false
 		result.Message = fmt.Sprintf("Failed to create escalation issue: %v", err)
+		ic.addToHistory(ctx, result, currentIssueID)
 		return result, err
 	}
 	result.EscalationIssueID = escalationID
@@ -318,15 +363,14 @@ func (ic *InterventionController) notifyHuman(ctx context.Context, report *Anoma
 		result.Message += fmt.Sprintf(" (warning: failed to emit event: %v)", err)
 	}
 
-	ic.addToHistoryLocked(result)
+	ic.addToHistory(ctx, result, currentIssueID)
 	return result, nil
 }
 
 // flagForInvestigation creates an escalation issue for lower-priority anomalies
 // These are logged for investigation but don't require immediate intervention
-func (ic *InterventionController) flagForInvestigation(ctx context.Context, report *AnomalyReport) (*InterventionResult, error) {
-	ic.mu.Lock()
-	defer ic.mu.Unlock()
+func (ic *InterventionController) flagForInvestigation(ctx context.Context, report *AnomalyReport, policyEntry string) (*InterventionResult, error) {
+	currentIssueID, _ := ic.agentContext()
 
 	result := &InterventionResult{
 		Success:          true,
@@ -334,13 +378,14 @@ func (ic *InterventionController) flagForInvestigation(ctx context.Context, repo
 		AnomalyReport:    report,
 		Message:          fmt.Sprintf("Flagged %s anomaly for investigation", report.AnomalyType),
 		Timestamp:        time.Now(),
+		PolicyEntry:      policyEntry,
 	}
 
-	currentIssueID := ic.currentIssueID
 	escalationID, err := ic.createEscalationIssue(ctx, report, InterventionPauseAgent, currentIssueID)
 	if err != nil {
 		result.Success = false
 		result.Message = fmt.Sprintf("Failed to create escalation issue: %v", err)
+		ic.addToHistory(ctx, result, currentIssueID)
 		return result, err
 	}
 	result.EscalationIssueID = escalationID
@@ -349,75 +394,201 @@ func (ic *InterventionController) flagForInvestigation(ctx context.Context, repo
 		result.Message += fmt.Sprintf(" (warning: failed to emit event: %v)", err)
 	}
 
-	ic.addToHistoryLocked(result)
+	ic.addToHistory(ctx, result, currentIssueID)
 	return result, nil
 }
 
-// Intervene analyzes an anomaly report and decides what intervention to take
-// This delegates the intervention decision to AI (ZFC compliant)
+// Intervene consults the configured intervention policy and takes the action
+// it selects for the anomaly. With the default policy the AI's recommended
+// action is followed (ZFC compliant).
 func (ic *InterventionController) Intervene(ctx context.Context, report *AnomalyReport) (*InterventionResult, error) {
 	if !report.Detected {
 		return nil, fmt.Errorf("no anomaly detected, intervention not needed")
 	}
 
-	// The AnomalyReport already contains a RecommendedAction from the AI
-	// We follow that recommendation
+	action, policyEntry := ic.config.ResolvePolicy(report)
+	fmt.Printf("Watchdog: Policy %s selected for %s anomaly (severity: %s)\n", policyEntry, report.AnomalyType, report.Severity)
+
+	switch action {
+	case PolicyAIRecommended:
+		return ic.followRecommendation(ctx, report, policyEntry)
+	case PolicyLogOnly:
+		return ic.logOnly(ctx, report, policyEntry)
+	case PolicyComment:
+		return ic.commentOnly(ctx, report, policyEntry)
+	case PolicyCancelAgent:
+		return ic.cancelAgent(ctx, report, policyEntry)
+	case PolicyCancelAndBlock:
+		return ic.cancelAndBlock(ctx, report, policyEntry)
+	case PolicyEscalateIssue:
+		return ic.notifyHuman(ctx, report, policyEntry)
+	default:
+		return nil, fmt.Errorf("unknown policy action: %s", action)
+	}
+}
+
+// followRecommendation takes the action the AI recommended in the anomaly report
+func (ic *InterventionController) followRecommendation(ctx context.Context, report *AnomalyReport, policyEntry string) (*InterventionResult, error) {
 	switch report.RecommendedAction {
 	case ActionStopExecution:
 		// Stop execution = kill the agent
-		return ic.KillAgent(ctx, report)
+		return ic.killAgent(ctx, report, policyEntry)
 	case ActionRestartAgent:
 		// Restart = pause (kill) the agent, it will be restarted by the executor
-		return ic.PauseAgent(ctx, report)
+		return ic.pauseAgent(ctx, report, policyEntry)
 	case ActionMarkAsBlocked:
 		// Mark as blocked = pause agent and create escalation issue
 		// The escalation issue will be marked as blocked for human review
-		return ic.PauseAgent(ctx, report)
+		return ic.pauseAgent(ctx, report, policyEntry)
 	case ActionCheckpoint:
 		// Request checkpoint for context exhaustion
-		return ic.RequestCheckpoint(ctx, report)
+		return ic.requestCheckpoint(ctx, report, policyEntry)
 	case ActionNotifyHuman:
 		// Just create the escalation issue without stopping execution
-		return ic.notifyHuman(ctx, report)
+		return ic.notifyHuman(ctx, report, policyEntry)
 
 	case ActionInvestigate, ActionMonitor:
 		// These are lower-priority actions - just log and create escalation
-		return ic.flagForInvestigation(ctx, report)
+		return ic.flagForInvestigation(ctx, report, policyEntry)
 
 	default:
 		return nil, fmt.Errorf("unknown recommended action: %s", report.RecommendedAction)
 	}
 }
 
-// createEscalationIssue creates or updates an escalation issue for human review
-// Implements deduplication to prevent spam (vc-243)
-// currentIssueID is passed as parameter to avoid reading ic.currentIssueID without lock
-func (ic *InterventionController) createEscalationIssue(ctx context.Context, report *AnomalyReport, interventionType InterventionType, currentIssueID string) (string, error) {
-	// Check for existing open escalation for this (issue, anomaly type) combination
-	anomalyLabel := fmt.Sprintf("anomaly:%s", report.AnomalyType)
-	affectedLabel := fmt.Sprintf("affected-issue:%s", currentIssueID)
+// logOnly records the anomaly in the intervention history without acting on it
+func (ic *InterventionController) logOnly(ctx context.Context, report *AnomalyReport, policyEntry string) (*InterventionResult, error) {
+	currentIssueID, _ := ic.agentContext()
 
-	openStatus := types.StatusOpen
-	filter := types.IssueFilter{
-		Status: &openStatus,
-		Labels: []string{"watchdog-escalation", anomalyLabel, affectedLabel},
-		Limit:  1,
+	result := &InterventionResult{
+		Success:          true,
+		InterventionType: InterventionLogOnly,
+		AnomalyReport:    report,
+		Message:          fmt.Sprintf("Logged %s anomaly (severity: %s) without intervening", report.AnomalyType, report.Severity),
+		Timestamp:        time.Now(),
+		PolicyEntry:      policyEntry,
 	}
 
-	existing, err := ic.store.SearchIssues(ctx, "", filter)
+	ic.addToHistory(ctx, result, currentIssueID)
+	return result, nil
+}
+
+// commentOnly comments on the executing issue and lets the agent continue
+func (ic *InterventionController) commentOnly(ctx context.Context, report *AnomalyReport, policyEntry string) (*InterventionResult, error) {
+	currentIssueID, _ := ic.agentContext()
+
+	result := &InterventionResult{
+		Success:          true,
+		InterventionType: InterventionComment,
+		AnomalyReport:    report,
+		Message:          fmt.Sprintf("Commented on %s about %s anomaly: %s", currentIssueID, report.AnomalyType, report.Description),
+		Timestamp:        time.Now(),
+		PolicyEntry:      policyEntry,
+	}
+
+	if err := ic.emitWatchdogEvent(ctx, result, currentIssueID); err != nil {
+		result.Success = false
+		result.Message = fmt.Sprintf("Failed to comment on %s: %v", currentIssueID, err)
+		ic.addToHistory(ctx, result, currentIssueID)
+		return result, err
+	}
+
+	ic.addToHistory(ctx, result, currentIssueID)
+	return result, nil
+}
+
+// cancelAgent cancels the executing agent and comments on its issue without
+// filing an escalation issue
+func (ic *InterventionController) cancelAgent(ctx context.Context, report *AnomalyReport, policyEntry string) (*InterventionResult, error) {
+	currentIssueID, cancel := ic.agentContext()
+	if cancel == nil {
+		return nil, fmt.Errorf("no active agent to cancel")
+	}
+
+	result := &InterventionResult{
+		Success:          true,
+		InterventionType: InterventionKillAgent,
+		AnomalyReport:    report,
+		Message:          fmt.Sprintf("Canceled agent executing %s due to %s anomaly (severity: %s)", currentIssueID, report.AnomalyType, report.Severity),
+		Timestamp:        time.Now(),
+		PolicyEntry:      policyEntry,
+	}
+
+	cancel()
+
+	if err := ic.emitWatchdogEvent(ctx, result, currentIssueID); err != nil {
+		result.Message += fmt.Sprintf(" (warning: failed to emit event: %v)", err)
+	}
+
+	ic.addToHistory(ctx, result, currentIssueID)
+
+	fmt.Printf("Watchdog: Canceled agent for issue %s\n", currentIssueID)
+
+	return result, nil
+}
+
+// cancelAndBlock cancels the executing agent, files an escalation issue and
+// marks the issue blocked, so no executor picks it up again until a human
+// resolves the escalation and unblocks it (vc unblock). The executor leaves
+// an issue that is blocked when its agent fails blocked instead of reopening it.
+func (ic *InterventionController) cancelAndBlock(ctx context.Context, report *AnomalyReport, policyEntry string) (*InterventionResult, error) {
+	currentIssueID, cancel := ic.agentContext()
+	if cancel == nil {
+		return nil, fmt.Errorf("no active agent to cancel")
+	}
+
+	result := &InterventionResult{
+		Success:          true,
+		InterventionType: InterventionCancelAndBlock,
+		AnomalyReport:    report,
+		Message:          fmt.Sprintf("Canceled agent executing %s due to %s anomaly (severity: %s)", currentIssueID, report.AnomalyType, report.Severity),
+		Timestamp:        time.Now(),
+		PolicyEntry:      policyEntry,
+	}
+
+	cancel()
+
+	escalationID, err := ic.createEscalationIssue(ctx, report, InterventionCancelAndBlock, currentIssueID)
 	if err != nil {
-		// Log but continue - we'll create new if search fails
-		fmt.Printf("Warning: failed to search for existing escalation: %v\n", err)
+		result.Message += fmt.Sprintf(" (warning: failed to create escalation issue: %v)", err)
+	} else {
+		result.EscalationIssueID = escalationID
+	}
+
+	if err := ic.store.UpdateIssue(ctx, currentIssueID, map[string]interface{}{
+		"status": string(types.StatusBlocked),
+	}, "watchdog"); err != nil {
+		result.Success = false
+		result.Message += fmt.Sprintf(" (failed to block issue: %v)", err)
+	} else if escalationID != "" {
+		result.Message += fmt.Sprintf("; blocked %s until escalation %s is resolved", currentIssueID, escalationID)
+	} else {
+		result.Message += fmt.Sprintf("; blocked %s", currentIssueID)
 	}
 
-	// If existing escalation found, update it instead of creating new
-	if len(existing) > 0 {
-		return ic.updateEscalationIssue(ctx, existing[0], report, interventionType)
+	if err := ic.emitWatchdogEvent(ctx, result, currentIssueID); err != nil {
+		result.Message += fmt.Sprintf(" (warning: failed to emit event: %v)", err)
 	}
 
-	// No existing escalation - create new one
+	ic.addToHistory(ctx, result, currentIssueID)
+
+	fmt.Printf("Watchdog: Canceled agent and blocked issue %s (escalation: %s)\n", currentIssueID, escalationID)
+
+	return result, nil
+}
+
+// createEscalationIssue creates or updates an escalation issue for human review
+// Implements deduplication to prevent spam (vc-243)
+func (ic *InterventionController) createEscalationIssue(ctx context.Context, report *AnomalyReport, interventionType InterventionType, currentIssueID string) (string, error) {
+	anomalyLabel := fmt.Sprintf("anomaly:%s", report.AnomalyType)
+	affectedLabel := types.AffectedIssueLabel(currentIssueID)
 	title := fmt.Sprintf("Watchdog: %s anomaly detected in %s", report.AnomalyType, currentIssueID)
 
+	// If an unresolved escalation exists, update it instead of creating a new one
+	if existing := ic.findOpenEscalation(ctx, report, currentIssueID, title); existing != nil {
+		return ic.updateEscalationIssue(ctx, existing, report, interventionType)
+	}
+
 	description := fmt.Sprintf(`Watchdog detected anomalous behavior and intervened.
 
 **Anomaly Type**: %s
@@ -482,7 +653,7 @@ func (ic *InterventionController) createEscalationIssue(ctx context.Context, rep
 	}
 
 	// Add labels for deduplication
-	labels := []string{"watchdog-escalation", anomalyLabel, affectedLabel}
+	labels := []string{escalationLabel, anomalyLabel, affectedLabel}
 	for _, label := range labels {
 		if err := ic.store.AddLabel(ctx, issue.ID, label, "watchdog"); err != nil {
 			// Log but don't fail - labels are for optimization
@@ -490,13 +661,101 @@ func (ic *InterventionController) createEscalationIssue(ctx context.Context, rep
 		}
 	}
 
-	// Note: We do NOT create a dependency on the parent issue (vc-244)
-	// Escalation issues are monitoring artifacts that should not block their parent
-	// Parent issue ID is already in the title and labels for reference
+	// Link the escalation to the issue it was filed for so `vc dep tree` shows it.
+	// discovered-from is non-blocking: escalations are monitoring artifacts that
+	// must not block their parent (vc-244)
+	if currentIssueID != "" {
+		dep := &types.Dependency{
+			IssueID:     issue.ID,
+			DependsOnID: currentIssueID,
+			Type:        types.DepDiscoveredFrom,
+			CreatedAt:   time.Now(),
+			CreatedBy:   "watchdog",
+		}
+		if err := ic.store.AddDependency(ctx, dep, "watchdog"); err != nil {
+			// Log but don't fail - the escalation itself was filed
+			fmt.Printf("Warning: failed to link escalation %s to %s: %v\n", issue.ID, currentIssueID, err)
+		}
+	}
 
 	return issue.ID, nil
 }
 
+// findOpenEscalation returns an unresolved escalation for the same issue and
+// anomaly type, or nil. Matches, in order:
+//  1. The deterministic escalation labels
+//  2. A dependent of the issue with the same title (labels may have failed to apply)
+//  3. A semantic duplicate found by the deduplicator, when AI is available
+//
+// Lookup failures are logged and treated as "not found" so escalation still happens.
+func (ic *InterventionController) findOpenEscalation(ctx context.Context, report *AnomalyReport, currentIssueID, title string) *types.Issue {
+	filter := types.IssueFilter{
+		Labels: []string{escalationLabel, fmt.Sprintf("anomaly:%s", report.AnomalyType), types.AffectedIssueLabel(currentIssueID)},
+	}
+	existing, err := ic.store.SearchIssues(ctx, "", filter)
+	if err != nil {
+		fmt.Printf("Warning: failed to search for existing escalation: %v\n", err)
+	}
+	for _, issue := range existing {
+		if issue.Status != types.StatusClosed {
+			return issue
+		}
+	}
+
+	if currentIssueID != "" {
+		dependents, err := ic.store.GetDependents(ctx, currentIssueID)
+		if err != nil {
+			fmt.Printf("Warning: failed to get dependents of %s: %v\n", currentIssueID, err)
+		}
+		for _, issue := range dependents {
+			if issue.Status != types.StatusClosed && issue.Title == title {
+				return issue
+			}
+		}
+	}
+
+	if ic.deduplicator == nil {
+		return nil
+	}
+
+	candidate := &types.Issue{
+		Title:       title,
+		Description: fmt.Sprintf("%s\n\n%s", report.Description, report.Reasoning),
+		Status:      types.StatusOpen,
+		Priority:    1,
+		IssueType:   types.TypeTask,
+	}
+	decision, err := ic.deduplicator.CheckDuplicate(ctx, candidate)
+	if err != nil {
+		fmt.Printf("Warning: escalation deduplication failed: %v\n", err)
+		return nil
+	}
+	if !decision.IsDuplicate || decision.DuplicateOf == "" {
+		return nil
+	}
+
+	// Only fold into another escalation, never into regular work
+	labels, err := ic.store.GetLabels(ctx, decision.DuplicateOf)
+	if err != nil || !containsLabel(labels, escalationLabel) {
+		return nil
+	}
+	issue, err := ic.store.GetIssue(ctx, decision.DuplicateOf)
+	if err != nil || issue == nil || issue.Status == types.StatusClosed {
+		return nil
+	}
+	return issue
+}
+
+// containsLabel reports whether labels includes label
+func containsLabel(labels []string, label string) bool {
+	for _, l := range labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
 // updateEscalationIssue updates an existing escalation with new observation
 func (ic *InterventionController) updateEscalationIssue(ctx context.Context, issue *types.Issue, report *AnomalyReport, interventionType InterventionType) (string, error) {
 	// Append new detection to history
@@ -547,7 +806,6 @@ func (ic *InterventionController) updateEscalationIssue(ctx context.Context, iss
 }
 
 // emitWatchdogEvent emits a watchdog event through the event system
-// currentIssueID is passed as parameter to avoid reading ic.currentIssueID without lock
 func (ic *InterventionController) emitWatchdogEvent(ctx context.Context, result *InterventionResult, currentIssueID string) error {
 	if currentIssueID == "" {
 		// No current issue to attach event to
@@ -557,22 +815,59 @@ func (ic *InterventionController) emitWatchdogEvent(ctx context.Context, result
 	comment := fmt.Sprintf("Watchdog intervention: %s - %s", result.InterventionType, result.Message)
 	actor := fmt.Sprintf("watchdog-%s", ic.executorInstanceID)
 
-	if err := ic.store.AddComment(ctx, currentIssueID, actor, comment); err != nil {
+	if err := ic.store.AddComment(ctx, currentIssueID, actor, ic.commentLimit.Cap(currentIssueID, comment)); err != nil {
 		return fmt.Errorf("failed to create watchdog event: %w", err)
 	}
 
 	return nil
 }
 
-// addToHistoryLocked adds an intervention result to the history
-// MUST be called with ic.mu held (lock requirement enforced by naming convention)
-func (ic *InterventionController) addToHistoryLocked(result *InterventionResult) {
+// agentContext returns the executing issue and its agent's cancel function
+// (nil when no agent is running). Interventions copy them here and act
+// without holding ic.mu, so a slow database never blocks other callers.
+func (ic *InterventionController) agentContext() (string, context.CancelFunc) {
+	ic.mu.RLock()
+	defer ic.mu.RUnlock()
+	return ic.currentIssueID, ic.cancelFunc
+}
+
+// addToHistory adds an intervention result to the in-memory history and
+// persists it to vc_watchdog_interventions so it survives restarts. Only
+// the in-memory append holds ic.mu; the write happens after it is released.
+func (ic *InterventionController) addToHistory(ctx context.Context, result *InterventionResult, currentIssueID string) {
+	ic.mu.Lock()
 	ic.interventionHistory = append(ic.interventionHistory, *result)
 
 	// Enforce max history size - keep only the last maxHistorySize entries
 	if len(ic.interventionHistory) > ic.maxHistorySize {
 		ic.interventionHistory = ic.interventionHistory[len(ic.interventionHistory)-ic.maxHistorySize:]
 	}
+	ic.mu.Unlock()
+
+	if err := ic.store.RecordIntervention(ctx, ic.interventionRecord(result, currentIssueID)); err != nil {
+		// Log but don't fail - the intervention itself already happened
+		fmt.Printf("Warning: failed to persist watchdog intervention: %v\n", err)
+	}
+}
+
+// interventionRecord converts an intervention result into its persisted form
+func (ic *InterventionController) interventionRecord(result *InterventionResult, currentIssueID string) *types.InterventionRecord {
+	record := &types.InterventionRecord{
+		IssueID:            currentIssueID,
+		ExecutorInstanceID: ic.executorInstanceID,
+		Action:             string(result.InterventionType),
+		EscalationIssueID:  result.EscalationIssueID,
+		Success:            result.Success,
+		Outcome:            result.Message,
+		PolicyEntry:        result.PolicyEntry,
+		CreatedAt:          result.Timestamp,
+	}
+	if report := result.AnomalyReport; report != nil {
+		record.AnomalyType = string(report.AnomalyType)
+		record.Severity = string(report.Severity)
+		record.Confidence = report.Confidence
+	}
+	return record
 }
 
 // GetInterventionHistory returns a copy of recent intervention results
@@ -3,10 +3,13 @@ package watchdog
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/steveyegge/vc/internal/deduplication"
 	"github.com/steveyegge/vc/internal/storage"
+	"github.com/steveyegge/vc/internal/storage/storagetest"
 	"github.com/steveyegge/vc/internal/types"
 )
 
@@ -511,4 +514,404 @@ func TestInterventionController_InterventionHistory(t *testing.T) {
 			t.Error("Expected most recent intervention to be last in history")
 		}
 	}
+
+	// Persisted history is not pruned and survives beyond the in-memory window
+	records, err := store.GetInterventions(ctx, types.InterventionFilter{})
+	if err != nil {
+		t.Fatalf("GetInterventions failed: %v", err)
+	}
+	if len(records) != 5 {
+		t.Fatalf("Expected 5 persisted interventions, got %d", len(records))
+	}
+	newest := records[0]
+	if newest.IssueID != "vc-test-5" {
+		t.Errorf("Expected newest persisted intervention for vc-test-5, got %s", newest.IssueID)
+	}
+	if newest.Action != string(InterventionPauseAgent) || newest.AnomalyType != string(AnomalyInfiniteLoop) {
+		t.Errorf("Unexpected persisted action/anomaly: %s/%s", newest.Action, newest.AnomalyType)
+	}
+	if newest.ExecutorInstanceID != "test-executor" || newest.Confidence != 0.9 || !newest.Success {
+		t.Errorf("Unexpected persisted record: %+v", newest)
+	}
+	if newest.EscalationIssueID == "" {
+		t.Error("Expected escalation issue ID to be persisted")
+	}
+
+	// Issue filter
+	records, err = store.GetInterventions(ctx, types.InterventionFilter{IssueID: "vc-test-2"})
+	if err != nil {
+		t.Fatalf("GetInterventions with issue filter failed: %v", err)
+	}
+	if len(records) != 1 || records[0].IssueID != "vc-test-2" {
+		t.Errorf("Expected 1 intervention for vc-test-2, got %d", len(records))
+	}
+
+	// Since filter excludes everything in the past
+	records, err = store.GetInterventions(ctx, types.InterventionFilter{Since: time.Now().Add(time.Hour)})
+	if err != nil {
+		t.Fatalf("GetInterventions with since filter failed: %v", err)
+	}
+	if len(records) != 0 {
+		t.Errorf("Expected no interventions after future cutoff, got %d", len(records))
+	}
+}
+
+func TestInterventionController_PersistsWithoutHoldingLock(t *testing.T) {
+	ctx := context.Background()
+
+	// A stalled database: recording the intervention blocks until released
+	store := storagetest.NewFakeStorage()
+	release := make(chan struct{})
+	recording := make(chan struct{})
+	store.FailWhen("RecordIntervention", func([]interface{}) error {
+		close(recording)
+		<-release
+		return nil
+	})
+
+	ic, err := NewInterventionController(&InterventionControllerConfig{
+		Store:              store,
+		ExecutorInstanceID: "test-executor",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create intervention controller: %v", err)
+	}
+
+	report := &AnomalyReport{
+		Detected:    true,
+		AnomalyType: AnomalyInfiniteLoop,
+		Severity:    SeverityLow,
+		Description: "Test anomaly",
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if _, err := ic.logOnly(ctx, report, "test"); err != nil {
+			t.Errorf("logOnly failed: %v", err)
+		}
+	}()
+	<-recording
+
+	// Other callers go on while the write is stuck
+	unblocked := make(chan struct{})
+	go func() {
+		defer close(unblocked)
+		_, cancel := context.WithCancel(ctx)
+		ic.SetAgentContext("vc-other", cancel)
+		_ = ic.GetInterventionHistory()
+	}()
+	select {
+	case <-unblocked:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Controller lock held while persisting the intervention")
+	}
+
+	close(release)
+	<-done
+	if history := ic.GetInterventionHistory(); len(history) != 1 {
+		t.Errorf("Expected 1 intervention in history, got %d", len(history))
+	}
+}
+
+func TestInterventionController_PolicyOverridesRecommendation(t *testing.T) {
+	ctx := context.Background()
+
+	store, err := storage.NewStorage(ctx, &storage.Config{
+		Path: ":memory:",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	cfg := DefaultWatchdogConfig()
+	cfg.InterventionConfig.Policy = InterventionPolicy{
+		Rules: []PolicyRule{
+			{AnomalyType: AnomalyInfiniteLoop, Severity: SeverityHigh, Action: PolicyLogOnly},
+			{AnomalyType: AnomalyThrashing, Action: PolicyCancelAgent},
+		},
+		DefaultAction: PolicyAIRecommended,
+	}
+
+	ic, err := NewInterventionController(&InterventionControllerConfig{
+		Store:              store,
+		ExecutorInstanceID: "test-executor",
+		Config:             cfg,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create intervention controller: %v", err)
+	}
+
+	testIssue := &types.Issue{
+		ID:          "vc-policy-1",
+		Title:       "Policy test",
+		Description: "Test",
+		Status:      types.StatusInProgress,
+		Priority:    2,
+		IssueType:   types.TypeTask,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	if err := store.CreateIssue(ctx, testIssue, "test"); err != nil {
+		t.Fatalf("Failed to create test issue: %v", err)
+	}
+
+	cancelCalled := false
+	ic.SetAgentContext("vc-policy-1", func() { cancelCalled = true })
+
+	// log_only: AI says stop, policy says just log
+	result, err := ic.Intervene(ctx, &AnomalyReport{
+		Detected:          true,
+		AnomalyType:       AnomalyInfiniteLoop,
+		Severity:          SeverityHigh,
+		RecommendedAction: ActionStopExecution,
+		Confidence:        0.9,
+	})
+	if err != nil {
+		t.Fatalf("Intervene failed: %v", err)
+	}
+	if cancelCalled {
+		t.Error("log_only policy should not cancel the agent")
+	}
+	if result.InterventionType != InterventionLogOnly || result.EscalationIssueID != "" {
+		t.Errorf("Expected log_only without escalation, got %s (escalation %q)", result.InterventionType, result.EscalationIssueID)
+	}
+	if result.PolicyEntry != "infinite_loop/high=log_only" {
+		t.Errorf("Unexpected policy entry: %s", result.PolicyEntry)
+	}
+
+	// cancel_agent: AI says investigate, policy says cancel
+	result, err = ic.Intervene(ctx, &AnomalyReport{
+		Detected:          true,
+		AnomalyType:       AnomalyThrashing,
+		Severity:          SeverityMedium,
+		RecommendedAction: ActionInvestigate,
+		Confidence:        0.8,
+	})
+	if err != nil {
+		t.Fatalf("Intervene failed: %v", err)
+	}
+	if !cancelCalled {
+		t.Error("cancel_agent policy should cancel the agent")
+	}
+	if result.EscalationIssueID != "" {
+		t.Errorf("cancel_agent should not escalate, got %s", result.EscalationIssueID)
+	}
+
+	// The policy entry is persisted alongside the intervention
+	records, err := store.GetInterventions(ctx, types.InterventionFilter{IssueID: "vc-policy-1"})
+	if err != nil {
+		t.Fatalf("GetInterventions failed: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 persisted interventions, got %d", len(records))
+	}
+	if records[0].PolicyEntry != "thrashing/*=cancel_agent" || records[1].PolicyEntry != "infinite_loop/high=log_only" {
+		t.Errorf("Unexpected persisted policy entries: %q, %q", records[0].PolicyEntry, records[1].PolicyEntry)
+	}
+}
+
+func TestInterventionController_CancelAndBlock(t *testing.T) {
+	ctx := context.Background()
+
+	store, err := storage.NewStorage(ctx, &storage.Config{Path: ":memory:"})
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	cfg := DefaultWatchdogConfig()
+	cfg.InterventionConfig.Policy = InterventionPolicy{
+		Rules:         []PolicyRule{{AnomalyType: AnomalyInfiniteLoop, Action: PolicyCancelAndBlock}},
+		DefaultAction: PolicyAIRecommended,
+	}
+	ic, err := NewInterventionController(&InterventionControllerConfig{
+		Store:              store,
+		ExecutorInstanceID: "test-executor",
+		Config:             cfg,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create intervention controller: %v", err)
+	}
+
+	testIssue := &types.Issue{
+		ID:          "vc-block-1",
+		Title:       "Block test",
+		Description: "Test",
+		Status:      types.StatusInProgress,
+		Priority:    2,
+		IssueType:   types.TypeTask,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	if err := store.CreateIssue(ctx, testIssue, "test"); err != nil {
+		t.Fatalf("Failed to create test issue: %v", err)
+	}
+
+	cancelCalled := false
+	ic.SetAgentContext(testIssue.ID, func() { cancelCalled = true })
+
+	result, err := ic.Intervene(ctx, &AnomalyReport{
+		Detected:          true,
+		AnomalyType:       AnomalyInfiniteLoop,
+		Severity:          SeverityCritical,
+		RecommendedAction: ActionMonitor,
+		Confidence:        0.95,
+	})
+	if err != nil {
+		t.Fatalf("Intervene failed: %v", err)
+	}
+	if !cancelCalled {
+		t.Error("cancel_and_block policy should cancel the agent")
+	}
+	if !result.Success || result.InterventionType != InterventionCancelAndBlock || result.EscalationIssueID == "" {
+		t.Errorf("Expected a successful cancel_and_block with an escalation, got %+v", result)
+	}
+
+	issue, err := store.GetIssue(ctx, testIssue.ID)
+	if err != nil {
+		t.Fatalf("GetIssue failed: %v", err)
+	}
+	if issue.Status != types.StatusBlocked {
+		t.Errorf("Expected %s to be blocked, got status %s", testIssue.ID, issue.Status)
+	}
+
+	// The block is recorded in the intervention history
+	records, err := store.GetInterventions(ctx, types.InterventionFilter{IssueID: testIssue.ID})
+	if err != nil {
+		t.Fatalf("GetInterventions failed: %v", err)
+	}
+	if len(records) != 1 || records[0].Action != string(InterventionCancelAndBlock) {
+		t.Fatalf("Expected 1 cancel_and_block intervention, got %+v", records)
+	}
+	if records[0].EscalationIssueID != result.EscalationIssueID || !strings.Contains(records[0].Outcome, "blocked "+testIssue.ID) {
+		t.Errorf("Expected the record to name the escalation and the block, got %+v", records[0])
+	}
+}
+
+// stubDeduplicator reports every candidate as a duplicate of a fixed issue
+type stubDeduplicator struct {
+	duplicateOf string
+	calls       int
+}
+
+func (d *stubDeduplicator) CheckDuplicate(ctx context.Context, candidate *types.Issue) (*deduplication.DuplicateDecision, error) {
+	d.calls++
+	return &deduplication.DuplicateDecision{IsDuplicate: true, DuplicateOf: d.duplicateOf, Confidence: 0.95}, nil
+}
+
+func (d *stubDeduplicator) DeduplicateBatch(ctx context.Context, candidates []*types.Issue) (*deduplication.DeduplicationResult, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func TestInterventionController_EscalationDeduplication(t *testing.T) {
+	ctx := context.Background()
+
+	store, err := storage.NewStorage(ctx, &storage.Config{Path: ":memory:"})
+	if err != nil {
+		t.Fatalf("Failed to create storage: %v", err)
+	}
+	defer func() { _ = store.Close() }()
+
+	testIssue := &types.Issue{
+		ID:          "vc-dedup-1",
+		Title:       "Dedup test issue",
+		Description: "Issue whose anomalies recur",
+		Status:      types.StatusInProgress,
+		Priority:    2,
+		IssueType:   types.TypeTask,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	if err := store.CreateIssue(ctx, testIssue, "test"); err != nil {
+		t.Fatalf("Failed to create test issue: %v", err)
+	}
+
+	report := &AnomalyReport{
+		Detected:          true,
+		AnomalyType:       AnomalyInfiniteLoop,
+		Severity:          SeverityHigh,
+		Description:       "Same command retried repeatedly",
+		RecommendedAction: ActionNotifyHuman,
+		Confidence:        0.9,
+	}
+
+	// Each escalation uses a fresh controller to simulate executor restarts
+	escalate := func(dedup *stubDeduplicator) string {
+		t.Helper()
+		cfg := &InterventionControllerConfig{Store: store, ExecutorInstanceID: "test-executor"}
+		if dedup != nil {
+			cfg.Deduplicator = dedup
+		}
+		ic, err := NewInterventionController(cfg)
+		if err != nil {
+			t.Fatalf("Failed to create intervention controller: %v", err)
+		}
+		ic.SetAgentContext(testIssue.ID, func() {})
+		result, err := ic.Intervene(ctx, report)
+		if err != nil {
+			t.Fatalf("Intervene failed: %v", err)
+		}
+		if result.EscalationIssueID == "" {
+			t.Fatal("Expected an escalation issue")
+		}
+		return result.EscalationIssueID
+	}
+
+	first := escalate(nil)
+
+	// The escalation is linked to the original issue with a non-blocking dependency
+	deps, err := store.GetDependencies(ctx, first)
+	if err != nil {
+		t.Fatalf("GetDependencies failed: %v", err)
+	}
+	if len(deps) != 1 || deps[0].ID != testIssue.ID {
+		t.Errorf("Expected escalation %s to depend on %s, got %v", first, testIssue.ID, deps)
+	}
+
+	// Recurrence after a restart updates the same escalation
+	if second := escalate(nil); second != first {
+		t.Errorf("Expected recurrence to reuse escalation %s, got %s", first, second)
+	}
+
+	// Escalations in progress still count as unresolved
+	if err := store.UpdateIssue(ctx, first, map[string]interface{}{"status": types.StatusInProgress}, "test"); err != nil {
+		t.Fatalf("UpdateIssue failed: %v", err)
+	}
+	if again := escalate(nil); again != first {
+		t.Errorf("Expected in-progress escalation %s to be reused, got %s", first, again)
+	}
+
+	// Without labels the dependency link and title still identify the escalation
+	if err := store.RemoveLabel(ctx, first, "anomaly:infinite_loop", "test"); err != nil {
+		t.Fatalf("RemoveLabel failed: %v", err)
+	}
+	if linked := escalate(nil); linked != first {
+		t.Errorf("Expected linked escalation %s to be reused, got %s", first, linked)
+	}
+
+	// Once resolved, a recurrence files a new escalation
+	if err := store.CloseIssue(ctx, first, "fixed", "test"); err != nil {
+		t.Fatalf("CloseIssue failed: %v", err)
+	}
+	fresh := escalate(nil)
+	if fresh == first {
+		t.Error("Expected a new escalation after the previous one was closed")
+	}
+
+	// The AI deduplicator can fold a differently-titled anomaly into an open escalation
+	report.AnomalyType = AnomalyThrashing
+	dedup := &stubDeduplicator{duplicateOf: fresh}
+	if folded := escalate(dedup); folded != fresh {
+		t.Errorf("Expected deduplicator match %s to be reused, got %s", fresh, folded)
+	}
+	if dedup.calls != 1 {
+		t.Errorf("Expected 1 deduplicator call, got %d", dedup.calls)
+	}
+
+	// ...but never into regular work
+	report.AnomalyType = AnomalyRegression
+	if other := escalate(&stubDeduplicator{duplicateOf: testIssue.ID}); other == testIssue.ID {
+		t.Error("Deduplicator must not fold escalations into non-escalation issues")
+	}
 }
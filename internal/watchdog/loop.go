@@ -0,0 +1,251 @@
+package watchdog
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// DefaultLoopDetectionConfig returns the default loop detection thresholds
+func DefaultLoopDetectionConfig() LoopDetectionConfig {
+	return LoopDetectionConfig{
+		Enabled:           true,
+		WindowSize:        50,
+		RepetitionRatio:   0.8,
+		SustainedDuration: 2 * time.Minute,
+	}
+}
+
+var (
+	// Volatile tokens that change between otherwise identical retries are
+	// masked before hashing; every other number is kept, so lines that
+	// differ in a count ("downloaded 3/200 files") stay distinct
+	uuidPattern      = regexp.MustCompile(`[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`)
+	timestampPattern = regexp.MustCompile(`\d{4}-\d{2}-\d{2}([t ]\d{2}:\d{2}(:\d{2}(\.\d+)?)?(z|[+-]\d{2}:?\d{2})?)?|\b\d{1,2}:\d{2}:\d{2}(\.\d+)?\b`)
+	pidPattern       = regexp.MustCompile(`\bpid[:=#]?\s*\d+|\b(\w+)\[\d+\]`)
+	hexPattern       = regexp.MustCompile(`\b0x[0-9a-f]+\b|\b[0-9a-f]{7,}\b`)
+
+	// barPattern matches progress bars drawn with repeated glyphs
+	barPattern = regexp.MustCompile(`[=#█▓▒░■>]{4,}`)
+	// counterPattern matches progress counters: percentages and n/m counts
+	counterPattern = regexp.MustCompile(`\d+(\.\d+)?\s*%|\d+\s*/\s*\d+`)
+)
+
+// LoopStatus summarizes the repetition in recent agent output
+type LoopStatus struct {
+	// Samples is the number of messages currently in the window
+	Samples int
+	// RepetitionRatio is the fraction of messages in the window that repeat an earlier one
+	RepetitionRatio float64
+	// RepeatingFor is how long the ratio has stayed at or above the threshold
+	RepeatingFor time.Duration
+	// TopLine is the most repeated normalized message
+	TopLine string
+	// TopCount is how many times TopLine appears in the window
+	TopCount int
+}
+
+// loopEntry is one hashed message in the rolling window
+type loopEntry struct {
+	hash uint64
+	line string
+}
+
+// LoopDetector keeps a rolling window of hashed agent event messages and
+// tracks how long the repetition ratio has stayed above the threshold.
+// It is not safe for concurrent use; the Monitor serializes access.
+type LoopDetector struct {
+	config         LoopDetectionConfig
+	window         []loopEntry
+	next           int
+	counts         map[uint64]int
+	repeatingSince time.Time
+}
+
+// NewLoopDetector creates a loop detector with the given thresholds
+func NewLoopDetector(cfg LoopDetectionConfig) *LoopDetector {
+	if cfg.WindowSize <= 0 {
+		cfg.WindowSize = DefaultLoopDetectionConfig().WindowSize
+	}
+	return &LoopDetector{
+		config: cfg,
+		window: make([]loopEntry, 0, cfg.WindowSize),
+		counts: make(map[uint64]int),
+	}
+}
+
+// normalizeLoopLine normalizes a message for hashing: whitespace and case
+// are folded and timestamps, PIDs and hex IDs masked. It returns "" for lines
+// that should be ignored: blank lines and progress bars (see isProgressLine).
+func normalizeLoopLine(line string) string {
+	line = strings.TrimSpace(line)
+	if line == "" || isProgressLine(line) {
+		return ""
+	}
+	line = strings.ToLower(strings.Join(strings.Fields(line), " "))
+	line = uuidPattern.ReplaceAllString(line, "<hex>")
+	line = timestampPattern.ReplaceAllString(line, "<time>")
+	line = pidPattern.ReplaceAllStringFunc(line, func(m string) string {
+		if sub := pidPattern.FindStringSubmatch(m); sub[1] != "" {
+			return sub[1] + "[<pid>]"
+		}
+		return "pid <pid>"
+	})
+	return hexPattern.ReplaceAllStringFunc(line, func(m string) string {
+		// A hex ID mixes digits and letters; all-digit runs are numbers
+		// and all-letter runs are words
+		if strings.HasPrefix(m, "0x") || (strings.ContainsAny(m, "0123456789") && strings.ContainsAny(m, "abcdef")) {
+			return "<hex>"
+		}
+		return m
+	})
+}
+
+// isProgressLine reports whether a line is a progress indicator: a bar of
+// repeated glyphs, or nothing but counters ("45%", "[12/40]") and punctuation.
+// A line with words around a number ("coverage 45% below threshold") is not.
+func isProgressLine(line string) bool {
+	if barPattern.MatchString(line) {
+		return true
+	}
+	rest := counterPattern.ReplaceAllString(line, "")
+	if rest == line {
+		return false
+	}
+	return !strings.ContainsFunc(rest, func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsDigit(r)
+	})
+}
+
+// Record adds a message to the window
+func (d *LoopDetector) Record(line string, now time.Time) {
+	normalized := normalizeLoopLine(line)
+	if normalized == "" {
+		return
+	}
+
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(normalized))
+	entry := loopEntry{hash: h.Sum64(), line: normalized}
+
+	if len(d.window) < d.config.WindowSize {
+		d.window = append(d.window, entry)
+	} else {
+		evicted := d.window[d.next]
+		d.counts[evicted.hash]--
+		if d.counts[evicted.hash] == 0 {
+			delete(d.counts, evicted.hash)
+		}
+		d.window[d.next] = entry
+		d.next = (d.next + 1) % d.config.WindowSize
+	}
+	d.counts[entry.hash]++
+
+	if d.repetitive() {
+		if d.repeatingSince.IsZero() {
+			d.repeatingSince = now
+		}
+	} else {
+		d.repeatingSince = time.Time{}
+	}
+}
+
+// repetitive reports whether a full window is at or above the repetition ratio.
+// Partial windows never count so a handful of early duplicates can't trigger.
+func (d *LoopDetector) repetitive() bool {
+	if len(d.window) < d.config.WindowSize {
+		return false
+	}
+	return d.ratio() >= d.config.RepetitionRatio
+}
+
+// ratio returns the fraction of the window that repeats an earlier message
+func (d *LoopDetector) ratio() float64 {
+	if len(d.window) == 0 {
+		return 0
+	}
+	return 1 - float64(len(d.counts))/float64(len(d.window))
+}
+
+// Status summarizes the current window
+func (d *LoopDetector) Status(now time.Time) LoopStatus {
+	status := LoopStatus{
+		Samples:         len(d.window),
+		RepetitionRatio: d.ratio(),
+	}
+	if !d.repeatingSince.IsZero() {
+		status.RepeatingFor = now.Sub(d.repeatingSince)
+	}
+
+	var topHash uint64
+	for hash, count := range d.counts {
+		if count > status.TopCount {
+			status.TopCount = count
+			topHash = hash
+		}
+	}
+	for _, entry := range d.window {
+		if entry.hash == topHash {
+			status.TopLine = entry.line
+			break
+		}
+	}
+	return status
+}
+
+// Reset clears the window, e.g. after an intervention
+func (d *LoopDetector) Reset() {
+	d.window = d.window[:0]
+	d.next = 0
+	d.counts = make(map[uint64]int)
+	d.repeatingSince = time.Time{}
+}
+
+// CheckLoop returns a "loop" anomaly report if the current execution's output
+// has been repetitive for longer than the sustained duration, or nil.
+// Like CheckStall this needs no AI call.
+func (c *WatchdogConfig) CheckLoop(current *ExecutionTelemetry, status LoopStatus) *AnomalyReport {
+	if current == nil {
+		return nil
+	}
+
+	c.mu.RLock()
+	enabled := c.Enabled && c.LoopDetection.Enabled
+	threshold := c.LoopDetection.RepetitionRatio
+	sustained := c.LoopDetection.SustainedDuration
+	multiplier := c.RelaxedMode.ThresholdMultiplier
+	c.mu.RUnlock()
+
+	if !enabled || current.ProtectionMode == ProtectionOff {
+		return nil
+	}
+	if current.ProtectionMode == ProtectionRelaxed && multiplier > 1.0 {
+		sustained = time.Duration(float64(sustained) * multiplier)
+	}
+
+	if status.RepeatingFor == 0 || status.RepeatingFor < sustained {
+		return nil
+	}
+
+	return &AnomalyReport{
+		Detected:          true,
+		AnomalyType:       AnomalyOutputLoop,
+		Severity:          SeverityHigh,
+		Description:       fmt.Sprintf("Agent for %s is repeating its output (%.0f%% of the last %d messages are repeats)", current.IssueID, status.RepetitionRatio*100, status.Samples),
+		RecommendedAction: ActionStopExecution,
+		Reasoning: fmt.Sprintf("Output repetition has stayed at or above %.0f%% for %v (threshold: %v). "+
+			"Most repeated message (%d times): %q", threshold*100, status.RepeatingFor.Round(time.Second), sustained, status.TopCount, status.TopLine),
+		Confidence:     status.RepetitionRatio,
+		AffectedIssues: []string{current.IssueID},
+		Metrics: map[string]interface{}{
+			"repetition_ratio":  status.RepetitionRatio,
+			"window_samples":    status.Samples,
+			"repeating_seconds": int64(status.RepeatingFor.Seconds()),
+			"top_line":          status.TopLine,
+			"top_count":         status.TopCount,
+		},
+	}
+}
@@ -0,0 +1,218 @@
+package watchdog
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testLoopConfig() LoopDetectionConfig {
+	return LoopDetectionConfig{
+		Enabled:           true,
+		WindowSize:        10,
+		RepetitionRatio:   0.8,
+		SustainedDuration: time.Minute,
+	}
+}
+
+func TestNormalizeLoopLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{"blank", "   ", ""},
+		{"whitespace and case", "  Error:   Connection   REFUSED ", "error: connection refused"},
+		{"numbers kept", "downloaded 3/200 files", "downloaded 3/200 files"},
+		{"percentage in a message kept", "coverage 45% below threshold", "coverage 45% below threshold"},
+		{"timestamps masked", "2026-10-17T09:15:02Z build failed at 09:15:02.123", "<time> build failed at <time>"},
+		{"pids masked", "worker pid=4182 exited; sshd[977]: closed", "worker pid <pid> exited; sshd[<pid>]: closed"},
+		{"hex ids masked", "request 3f9a2c7e1b failed at 0xc000123abc", "request <hex> failed at <hex>"},
+		{"uuids masked", "run 123e4567-e89b-12d3-a456-426614174000 failed", "run <hex> failed"},
+		{"hex-letter words kept", "deadbeef 1234567", "deadbeef 1234567"},
+		{"percentage progress ignored", "  45% ", ""},
+		{"counter progress ignored", "[12/40] (30%)", ""},
+		{"bar progress ignored", "[=======>      ] 12/40", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeLoopLine(tt.line); got != tt.want {
+				t.Errorf("normalizeLoopLine(%q) = %q, want %q", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoopDetector_RepeatedOutput(t *testing.T) {
+	d := NewLoopDetector(testLoopConfig())
+	start := time.Now()
+
+	for i := 0; i < 10; i++ {
+		d.Record(fmt.Sprintf("12:00:%02d Error: build failed (pid %d)", i, 4000+i), start)
+	}
+
+	status := d.Status(start)
+	if status.RepetitionRatio != 0.9 {
+		t.Errorf("RepetitionRatio = %v, want 0.9", status.RepetitionRatio)
+	}
+	if status.TopCount != 10 || status.TopLine != "<time> error: build failed (pid <pid>)" {
+		t.Errorf("Top = %q x%d, want build failure x10", status.TopLine, status.TopCount)
+	}
+
+	d.Record("12:01:30 Error: build failed (pid 4011)", start.Add(90*time.Second))
+	if got := d.Status(start.Add(90 * time.Second)).RepeatingFor; got != 90*time.Second {
+		t.Errorf("RepeatingFor = %v, want 90s", got)
+	}
+
+	d.Reset()
+	if status := d.Status(start); status.Samples != 0 || status.RepeatingFor != 0 {
+		t.Errorf("Expected empty status after reset, got %+v", status)
+	}
+}
+
+func TestLoopDetector_VariedOutputResetsClock(t *testing.T) {
+	d := NewLoopDetector(testLoopConfig())
+	now := time.Now()
+
+	for i := 0; i < 10; i++ {
+		d.Record("same line", now)
+	}
+	if d.repeatingSince.IsZero() {
+		t.Fatal("Expected repetition to be tracked")
+	}
+
+	// Distinct lines push the ratio below the threshold
+	for _, line := range []string{"alpha", "beta", "gamma"} {
+		d.Record(line, now.Add(time.Minute))
+	}
+	if got := d.Status(now.Add(2 * time.Minute)).RepeatingFor; got != 0 {
+		t.Errorf("RepeatingFor = %v, want 0 after varied output", got)
+	}
+}
+
+func TestLoopDetector_PartialWindowNeverRepetitive(t *testing.T) {
+	d := NewLoopDetector(testLoopConfig())
+	now := time.Now()
+
+	for i := 0; i < 9; i++ {
+		d.Record("same line", now)
+	}
+	if got := d.Status(now.Add(time.Hour)).RepeatingFor; got != 0 {
+		t.Errorf("RepeatingFor = %v, want 0 for a partial window", got)
+	}
+}
+
+func TestLoopDetector_IgnoresProgressBars(t *testing.T) {
+	d := NewLoopDetector(testLoopConfig())
+	now := time.Now()
+
+	for i := 0; i < 50; i++ {
+		d.Record(fmt.Sprintf("[%-20s] %d/50", strings.Repeat("=", i/3)+">", i), now)
+		d.Record(fmt.Sprintf("%d%%", i*2), now)
+	}
+	if status := d.Status(now); status.Samples != 0 {
+		t.Errorf("Samples = %d, want progress lines ignored", status.Samples)
+	}
+}
+
+func TestLoopDetector_NumbersKeepLinesDistinct(t *testing.T) {
+	d := NewLoopDetector(testLoopConfig())
+	now := time.Now()
+
+	// Lines that differ only in a count are progress, not repetition
+	for i := 0; i < 50; i++ {
+		d.Record(fmt.Sprintf("downloaded %d/200 files", i), now.Add(time.Duration(i)*time.Second))
+	}
+	if status := d.Status(now.Add(time.Hour)); status.RepeatingFor != 0 || status.RepetitionRatio != 0 {
+		t.Errorf("Expected no repetition for changing counts, got %+v", status)
+	}
+}
+
+func TestLoopDetector_DetectsRepeatedPercentage(t *testing.T) {
+	d := NewLoopDetector(testLoopConfig())
+	now := time.Now()
+
+	// A percentage inside a message doesn't make it a progress bar
+	for i := 0; i < 200; i++ {
+		d.Record("coverage 45% below threshold", now.Add(time.Duration(i)*time.Second))
+	}
+	status := d.Status(now.Add(200 * time.Second))
+	if status.Samples != 10 || status.TopLine != "coverage 45% below threshold" {
+		t.Errorf("Expected the repeated line to fill the window, got %+v", status)
+	}
+	if status.RepeatingFor < time.Minute {
+		t.Errorf("RepeatingFor = %v, want the loop to be sustained", status.RepeatingFor)
+	}
+}
+
+func TestCheckLoop(t *testing.T) {
+	cfg := DefaultWatchdogConfig()
+	current := &ExecutionTelemetry{IssueID: "vc-1", ProtectionMode: ProtectionNormal}
+	looping := LoopStatus{Samples: 50, RepetitionRatio: 0.9, RepeatingFor: 3 * time.Minute, TopLine: "error", TopCount: 45}
+
+	report := cfg.CheckLoop(current, looping)
+	if report == nil {
+		t.Fatal("Expected loop report past the sustained duration")
+	}
+	if report.AnomalyType != AnomalyOutputLoop {
+		t.Errorf("AnomalyType = %s, want %s", report.AnomalyType, AnomalyOutputLoop)
+	}
+	if report.Confidence != 0.9 {
+		t.Errorf("Confidence = %v, want repetition ratio 0.9", report.Confidence)
+	}
+
+	brief := looping
+	brief.RepeatingFor = 30 * time.Second
+	if cfg.CheckLoop(current, brief) != nil {
+		t.Error("Expected no report below the sustained duration")
+	}
+
+	// Relaxed mode: 2m * 3 = 6m sustained duration required
+	relaxed := *current
+	relaxed.ProtectionMode = ProtectionRelaxed
+	if cfg.CheckLoop(&relaxed, looping) != nil {
+		t.Error("Expected relaxed mode to tolerate 3m of repetition")
+	}
+
+	off := *current
+	off.ProtectionMode = ProtectionOff
+	if cfg.CheckLoop(&off, looping) != nil {
+		t.Error("Expected no report when watchdog protection is off")
+	}
+
+	cfg.LoopDetection.Enabled = false
+	if cfg.CheckLoop(current, looping) != nil {
+		t.Error("Expected no report when loop detection is disabled")
+	}
+}
+
+func TestMonitorLoopDetection(t *testing.T) {
+	m := NewMonitor(DefaultConfig())
+	m.SetLoopDetection(testLoopConfig())
+
+	// Output outside an execution is dropped
+	m.RecordOutput("ignored")
+	if status := m.LoopStatus(); status.Samples != 0 {
+		t.Errorf("Samples = %d, want 0 while idle", status.Samples)
+	}
+
+	m.StartExecution("vc-1", "exec-1")
+	for i := 0; i < 10; i++ {
+		m.RecordOutput("go test ./... FAIL")
+	}
+	if status := m.LoopStatus(); status.Samples != 10 || status.TopCount != 10 {
+		t.Errorf("Expected 10 identical samples, got %+v", status)
+	}
+
+	m.ResetLoopDetection()
+	if status := m.LoopStatus(); status.Samples != 0 {
+		t.Errorf("Samples = %d, want 0 after reset", status.Samples)
+	}
+
+	m.EndExecution(false, false)
+	if status := m.LoopStatus(); status.Samples != 0 {
+		t.Errorf("Samples = %d, want 0 after execution ends", status.Samples)
+	}
+}
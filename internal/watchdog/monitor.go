@@ -4,6 +4,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/steveyegge/vc/internal/events"
 	"github.com/steveyegge/vc/internal/types"
 )
 
@@ -25,6 +26,14 @@ type ExecutionTelemetry struct {
 	GatesPassed bool
 	// ExecutorID is the executor instance that processed this issue
 	ExecutorID string
+	// ProtectionMode records whether watchdog protection was deliberately
+	// reduced for this execution via issue labels (normal if unset)
+	ProtectionMode ProtectionMode
+	// LastActivity is when the most recent event was recorded (starts at StartTime)
+	LastActivity time.Time
+	// SilenceThreshold overrides the stall detection threshold for this
+	// execution (zero uses the configured default)
+	SilenceThreshold time.Duration
 }
 
 // StateTransition represents a state change during issue execution
@@ -37,6 +46,48 @@ type StateTransition struct {
 	Timestamp time.Time
 }
 
+// CurrentState returns the execution state after the last transition and when
+// it was entered. An execution with no transitions is still in the claimed state.
+func (t *ExecutionTelemetry) CurrentState() (types.ExecutionState, time.Time) {
+	if len(t.StateTransitions) == 0 {
+		return types.ExecutionStateClaimed, t.StartTime
+	}
+	last := t.StateTransitions[len(t.StateTransitions)-1]
+	return last.To, last.Timestamp
+}
+
+// Snapshot converts the telemetry into execution_telemetry event data as of now,
+// so it can outlive the process and be read by `vc telemetry`.
+func (t *ExecutionTelemetry) Snapshot(now time.Time) events.ExecutionTelemetryData {
+	state, since := t.CurrentState()
+
+	data := events.ExecutionTelemetryData{
+		State:              string(state),
+		StateSince:         since,
+		StartedAt:          t.StartTime,
+		LastActivity:       t.LastActivity,
+		EventCounts:        make(map[string]int, len(t.EventCounts)),
+		Transitions:        make([]events.TelemetryTransition, 0, len(t.StateTransitions)),
+		ProtectionMode:     string(t.ProtectionMode),
+		SilenceThresholdMs: t.SilenceThreshold.Milliseconds(),
+	}
+	for eventType, count := range t.EventCounts {
+		data.EventCounts[eventType] = count
+		data.TotalEvents += count
+	}
+	if elapsed := now.Sub(t.StartTime); elapsed > 0 {
+		data.EventsPerMinute = float64(data.TotalEvents) / elapsed.Minutes()
+	}
+	for _, tr := range t.StateTransitions {
+		data.Transitions = append(data.Transitions, events.TelemetryTransition{
+			From:      string(tr.From),
+			To:        string(tr.To),
+			Timestamp: tr.Timestamp,
+		})
+	}
+	return data
+}
+
 // Monitor collects telemetry data from executor executions for analysis
 // It maintains a sliding window of recent execution history
 type Monitor struct {
@@ -49,6 +100,11 @@ type Monitor struct {
 
 	// currentExecution tracks the currently executing issue (if any)
 	currentExecution *ExecutionTelemetry
+
+	// loopConfig configures the loop detector created for each execution
+	loopConfig LoopDetectionConfig
+	// loopDetector tracks repeated output for the current execution (nil if idle)
+	loopDetector *LoopDetector
 }
 
 // Config holds monitor configuration
@@ -77,6 +133,7 @@ func NewMonitor(cfg *Config) *Monitor {
 	return &Monitor{
 		telemetry:  make([]*ExecutionTelemetry, 0, cfg.WindowSize),
 		windowSize: cfg.WindowSize,
+		loopConfig: DefaultLoopDetectionConfig(),
 	}
 }
 
@@ -86,13 +143,95 @@ func (m *Monitor) StartExecution(issueID, executorID string) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	now := time.Now()
 	m.currentExecution = &ExecutionTelemetry{
 		IssueID:          issueID,
 		ExecutorID:       executorID,
-		StartTime:        time.Now(),
+		StartTime:        now,
 		StateTransitions: []StateTransition{},
 		EventCounts:      make(map[string]int),
+		ProtectionMode:   ProtectionNormal,
+		LastActivity:     now,
+	}
+	m.loopDetector = NewLoopDetector(m.loopConfig)
+}
+
+// SetLoopDetection sets the loop detection thresholds used for subsequent executions
+func (m *Monitor) SetLoopDetection(cfg LoopDetectionConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.loopConfig = cfg
+}
+
+// RecordOutput adds an agent event message to the current execution's loop detector
+func (m *Monitor) RecordOutput(message string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.loopDetector == nil {
+		return
+	}
+
+	m.loopDetector.Record(message, time.Now())
+}
+
+// LoopStatus summarizes output repetition for the current execution
+func (m *Monitor) LoopStatus() LoopStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.loopDetector == nil {
+		return LoopStatus{}
+	}
+	return m.loopDetector.Status(time.Now())
+}
+
+// ResetLoopDetection clears the current execution's output window so a loop
+// isn't reported again while an intervention takes effect
+func (m *Monitor) ResetLoopDetection() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.loopDetector != nil {
+		m.loopDetector.Reset()
+	}
+}
+
+// SetSilenceThreshold overrides the stall detection threshold for the current execution
+func (m *Monitor) SetSilenceThreshold(threshold time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.currentExecution == nil {
+		return
+	}
+
+	m.currentExecution.SilenceThreshold = threshold
+}
+
+// SetProtectionMode records the watchdog protection mode for the current execution
+func (m *Monitor) SetProtectionMode(mode ProtectionMode) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.currentExecution == nil {
+		return
+	}
+
+	m.currentExecution.ProtectionMode = mode
+}
+
+// CurrentProtectionMode returns the protection mode of the current execution
+// (normal if nothing is executing)
+func (m *Monitor) CurrentProtectionMode() ProtectionMode {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.currentExecution == nil || m.currentExecution.ProtectionMode == "" {
+		return ProtectionNormal
 	}
+	return m.currentExecution.ProtectionMode
 }
 
 // RecordStateTransition records a state change during execution
@@ -122,6 +261,7 @@ func (m *Monitor) RecordEvent(eventType string) {
 	}
 
 	m.currentExecution.EventCounts[eventType]++
+	m.currentExecution.LastActivity = time.Now()
 }
 
 // EndExecution completes tracking for the current execution
@@ -150,6 +290,7 @@ func (m *Monitor) EndExecution(success, gatesPassed bool) {
 
 	// Clear current execution
 	m.currentExecution = nil
+	m.loopDetector = nil
 }
 
 // GetTelemetry returns a deep copy of the telemetry history
@@ -173,6 +314,14 @@ func (m *Monitor) GetTelemetry() []*ExecutionTelemetry {
 	return result
 }
 
+// HasActiveExecution reports whether an issue is currently executing
+func (m *Monitor) HasActiveExecution() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.currentExecution != nil
+}
+
 // GetCurrentExecution returns the currently executing issue (if any)
 func (m *Monitor) GetCurrentExecution() *ExecutionTelemetry {
 	m.mu.RLock()
@@ -239,4 +388,5 @@ func (m *Monitor) Clear() {
 
 	m.telemetry = make([]*ExecutionTelemetry, 0, m.windowSize)
 	m.currentExecution = nil
+	m.loopDetector = nil
 }
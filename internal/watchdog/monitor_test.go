@@ -58,6 +58,9 @@ func TestMonitor_StartEndExecution(t *testing.T) {
 	if curr := m.GetCurrentExecution(); curr != nil {
 		t.Errorf("expected no current execution, got %+v", curr)
 	}
+	if m.HasActiveExecution() {
+		t.Error("expected HasActiveExecution = false before start")
+	}
 
 	// Start execution
 	issueID := "vc-168"
@@ -78,6 +81,9 @@ func TestMonitor_StartEndExecution(t *testing.T) {
 	if curr.EndTime != (time.Time{}) {
 		t.Errorf("expected zero end time, got %v", curr.EndTime)
 	}
+	if !m.HasActiveExecution() {
+		t.Error("expected HasActiveExecution = true after start")
+	}
 
 	// End execution
 	m.EndExecution(true, true)
@@ -86,6 +92,9 @@ func TestMonitor_StartEndExecution(t *testing.T) {
 	if curr := m.GetCurrentExecution(); curr != nil {
 		t.Errorf("expected no current execution after end, got %+v", curr)
 	}
+	if m.HasActiveExecution() {
+		t.Error("expected HasActiveExecution = false after end")
+	}
 
 	// Should have one telemetry entry
 	telemetry := m.GetTelemetry()
@@ -424,3 +433,40 @@ func TestMonitor_GetTelemetryDeepCopy(t *testing.T) {
 			len(telemetry2[0].StateTransitions))
 	}
 }
+
+func TestExecutionTelemetrySnapshot(t *testing.T) {
+	start := time.Now().Add(-10 * time.Minute)
+	telemetry := &ExecutionTelemetry{
+		IssueID:   "vc-1",
+		StartTime: start,
+		StateTransitions: []StateTransition{
+			{From: types.ExecutionStateClaimed, To: types.ExecutionStateAssessing, Timestamp: start.Add(time.Minute)},
+			{From: types.ExecutionStateAssessing, To: types.ExecutionStateExecuting, Timestamp: start.Add(2 * time.Minute)},
+		},
+		EventCounts:      map[string]int{"agent_tool_use": 15, "progress": 5},
+		ProtectionMode:   ProtectionRelaxed,
+		LastActivity:     start.Add(9 * time.Minute),
+		SilenceThreshold: 30 * time.Minute,
+	}
+
+	data := telemetry.Snapshot(start.Add(10 * time.Minute))
+
+	if data.State != "executing" || !data.StateSince.Equal(start.Add(2*time.Minute)) {
+		t.Errorf("Expected executing since the second transition, got %s since %v", data.State, data.StateSince)
+	}
+	if data.TotalEvents != 20 || data.EventsPerMinute != 2 {
+		t.Errorf("Expected 20 events at 2/min, got %d at %v", data.TotalEvents, data.EventsPerMinute)
+	}
+	if len(data.Transitions) != 2 || data.Transitions[1].To != "executing" {
+		t.Errorf("Unexpected transitions: %+v", data.Transitions)
+	}
+	if data.ProtectionMode != "relaxed" || data.SilenceThresholdMs != (30*time.Minute).Milliseconds() {
+		t.Errorf("Unexpected protection: %s, %dms", data.ProtectionMode, data.SilenceThresholdMs)
+	}
+
+	// Without transitions the execution is still in the claimed state
+	idle := &ExecutionTelemetry{StartTime: start}
+	if state, since := idle.CurrentState(); state != types.ExecutionStateClaimed || !since.Equal(start) {
+		t.Errorf("Expected claimed since start, got %s since %v", state, since)
+	}
+}
@@ -0,0 +1,188 @@
+package watchdog
+
+import "fmt"
+
+// PolicyAction is the action the intervention controller takes for an anomaly
+type PolicyAction string
+
+const (
+	// PolicyAIRecommended defers to the AI's RecommendedAction (ZFC default)
+	PolicyAIRecommended PolicyAction = "ai_recommended"
+	// PolicyLogOnly records the anomaly without touching the agent or filing issues
+	PolicyLogOnly PolicyAction = "log_only"
+	// PolicyComment adds a comment to the executing issue and lets the agent continue
+	PolicyComment PolicyAction = "comment"
+	// PolicyCancelAgent cancels the agent and comments on the issue, without escalation
+	PolicyCancelAgent PolicyAction = "cancel_agent"
+	// PolicyCancelAndBlock cancels the agent, files an escalation issue for human
+	// review and marks the issue blocked until someone unblocks it
+	PolicyCancelAndBlock PolicyAction = "cancel_and_block"
+	// PolicyEscalateIssue files an escalation issue but lets the agent continue
+	PolicyEscalateIssue PolicyAction = "escalate_issue"
+)
+
+// AnyAnomalyType matches every anomaly type in a PolicyRule
+const AnyAnomalyType AnomalyType = "*"
+
+// validPolicyActions lists the actions accepted in configuration
+var validPolicyActions = map[PolicyAction]bool{
+	PolicyAIRecommended:  true,
+	PolicyLogOnly:        true,
+	PolicyComment:        true,
+	PolicyCancelAgent:    true,
+	PolicyCancelAndBlock: true,
+	PolicyEscalateIssue:  true,
+}
+
+// PolicyRule maps an anomaly type (and optionally a severity) to an action
+type PolicyRule struct {
+	// AnomalyType this rule applies to ("*" matches any type)
+	AnomalyType AnomalyType `json:"anomaly_type"`
+
+	// Severity this rule applies to (empty matches any severity)
+	Severity AnomalySeverity `json:"severity,omitempty"`
+
+	// Action to take when the rule matches
+	Action PolicyAction `json:"action"`
+}
+
+// String identifies the rule in logs and intervention history
+func (r PolicyRule) String() string {
+	severity := string(r.Severity)
+	if severity == "" {
+		severity = "*"
+	}
+	return fmt.Sprintf("%s/%s=%s", r.AnomalyType, severity, r.Action)
+}
+
+// InterventionPolicy decides which action to take for a detected anomaly.
+//
+// Resolution order, most specific first:
+//  1. Rule matching both anomaly type and severity
+//  2. Rule matching anomaly type with no severity
+//  3. Wildcard ("*") rule matching severity
+//  4. Wildcard ("*") rule with no severity
+//  5. DefaultAction
+type InterventionPolicy struct {
+	// Rules mapping anomaly types/severities to actions
+	Rules []PolicyRule `json:"rules,omitempty"`
+
+	// DefaultAction applies when no rule matches (e.g. unknown anomaly types)
+	// Default: ai_recommended
+	DefaultAction PolicyAction `json:"default_action"`
+}
+
+// DefaultInterventionPolicy returns a policy that defers every decision to the AI
+func DefaultInterventionPolicy() InterventionPolicy {
+	return InterventionPolicy{DefaultAction: PolicyAIRecommended}
+}
+
+// Resolve returns the action for an anomaly and a description of the policy
+// entry that produced it (e.g. "infinite_loop/high=cancel_agent" or "default=log_only")
+func (p InterventionPolicy) Resolve(anomalyType AnomalyType, severity AnomalySeverity) (PolicyAction, string) {
+	candidates := []struct {
+		anomalyType AnomalyType
+		severity    AnomalySeverity
+	}{
+		{anomalyType, severity},
+		{anomalyType, ""},
+		{AnyAnomalyType, severity},
+		{AnyAnomalyType, ""},
+	}
+
+	for _, c := range candidates {
+		for _, rule := range p.Rules {
+			if rule.AnomalyType == c.anomalyType && rule.Severity == c.severity {
+				return rule.Action, rule.String()
+			}
+		}
+	}
+
+	action := p.DefaultAction
+	if action == "" {
+		action = PolicyAIRecommended
+	}
+	return action, fmt.Sprintf("default=%s", action)
+}
+
+// Validate checks that every rule is well-formed and unambiguous
+func (p InterventionPolicy) Validate() error {
+	if p.DefaultAction != "" && !validPolicyActions[p.DefaultAction] {
+		return fmt.Errorf("invalid default_action: %s", p.DefaultAction)
+	}
+
+	seen := make(map[string]bool)
+	for i, rule := range p.Rules {
+		if rule.AnomalyType == "" {
+			return fmt.Errorf("rule %d: anomaly_type is required", i)
+		}
+		if rule.Severity != "" && !isValidSeverity(rule.Severity) {
+			return fmt.Errorf("rule %d: invalid severity %s (must be low, medium, high, or critical)", i, rule.Severity)
+		}
+		if !validPolicyActions[rule.Action] {
+			return fmt.Errorf("rule %d: invalid action %q", i, rule.Action)
+		}
+		key := fmt.Sprintf("%s/%s", rule.AnomalyType, rule.Severity)
+		if seen[key] {
+			return fmt.Errorf("rule %d: duplicate rule for %s", i, rule.String())
+		}
+		seen[key] = true
+	}
+
+	return nil
+}
+
+// DisablesProtection reports whether every known anomaly type resolves to
+// log_only at high and critical severity, which effectively turns the
+// watchdog into a passive logger.
+func (p InterventionPolicy) DisablesProtection() bool {
+	for _, anomalyType := range knownAnomalyTypes {
+		for _, severity := range []AnomalySeverity{SeverityHigh, SeverityCritical} {
+			if action, _ := p.Resolve(anomalyType, severity); action != PolicyLogOnly {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// SafetyWarning returns a loud warning if the policy disables protection, or "" otherwise
+func (p InterventionPolicy) SafetyWarning() string {
+	if !p.DisablesProtection() {
+		return ""
+	}
+	return "!!! WARNING: watchdog intervention policy maps every high/critical anomaly to log_only. " +
+		"The watchdog will NOT stop runaway agents. Review intervention_config.policy !!!"
+}
+
+// Clone returns a deep copy of the policy
+func (p InterventionPolicy) Clone() InterventionPolicy {
+	clone := InterventionPolicy{DefaultAction: p.DefaultAction}
+	if p.Rules != nil {
+		clone.Rules = make([]PolicyRule, len(p.Rules))
+		copy(clone.Rules, p.Rules)
+	}
+	return clone
+}
+
+// knownAnomalyTypes lists the anomaly types the analyzer can report
+var knownAnomalyTypes = []AnomalyType{
+	AnomalyInfiniteLoop,
+	AnomalyThrashing,
+	AnomalyStuckState,
+	AnomalyRegression,
+	AnomalyResourceSpike,
+	AnomalyContextExhaustion,
+	AnomalyStalled,
+	AnomalyOutputLoop,
+	AnomalyOther,
+}
+
+// isValidSeverity reports whether severity is one of the known levels
+func isValidSeverity(severity AnomalySeverity) bool {
+	switch severity {
+	case SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package watchdog
+
+import "testing"
+
+func TestInterventionPolicy_Resolve(t *testing.T) {
+	policy := InterventionPolicy{
+		Rules: []PolicyRule{
+			{AnomalyType: AnomalyInfiniteLoop, Severity: SeverityCritical, Action: PolicyCancelAndBlock},
+			{AnomalyType: AnomalyInfiniteLoop, Action: PolicyCancelAgent},
+			{AnomalyType: AnyAnomalyType, Severity: SeverityLow, Action: PolicyLogOnly},
+			{AnomalyType: AnyAnomalyType, Action: PolicyComment},
+		},
+		DefaultAction: PolicyEscalateIssue,
+	}
+
+	tests := []struct {
+		name        string
+		anomalyType AnomalyType
+		severity    AnomalySeverity
+		wantAction  PolicyAction
+		wantEntry   string
+	}{
+		{"exact match", AnomalyInfiniteLoop, SeverityCritical, PolicyCancelAndBlock, "infinite_loop/critical=cancel_and_block"},
+		{"type without severity", AnomalyInfiniteLoop, SeverityHigh, PolicyCancelAgent, "infinite_loop/*=cancel_agent"},
+		{"type beats wildcard severity", AnomalyInfiniteLoop, SeverityLow, PolicyCancelAgent, "infinite_loop/*=cancel_agent"},
+		{"wildcard with severity", AnomalyThrashing, SeverityLow, PolicyLogOnly, "*/low=log_only"},
+		{"wildcard catch-all", AnomalyThrashing, SeverityHigh, PolicyComment, "*/*=comment"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			action, entry := policy.Resolve(tt.anomalyType, tt.severity)
+			if action != tt.wantAction || entry != tt.wantEntry {
+				t.Errorf("Resolve(%s, %s) = (%s, %s), want (%s, %s)",
+					tt.anomalyType, tt.severity, action, entry, tt.wantAction, tt.wantEntry)
+			}
+		})
+	}
+}
+
+func TestInterventionPolicy_ResolveDefault(t *testing.T) {
+	policy := InterventionPolicy{
+		Rules:         []PolicyRule{{AnomalyType: AnomalyInfiniteLoop, Action: PolicyCancelAgent}},
+		DefaultAction: PolicyEscalateIssue,
+	}
+
+	action, entry := policy.Resolve(AnomalyType("brand_new_anomaly"), SeverityHigh)
+	if action != PolicyEscalateIssue || entry != "default=escalate_issue" {
+		t.Errorf("Unknown anomaly type resolved to (%s, %s), want default escalate_issue", action, entry)
+	}
+
+	// Empty default falls back to the AI recommendation
+	action, _ = InterventionPolicy{}.Resolve(AnomalyThrashing, SeverityHigh)
+	if action != PolicyAIRecommended {
+		t.Errorf("Empty policy resolved to %s, want %s", action, PolicyAIRecommended)
+	}
+}
+
+func TestInterventionPolicy_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  InterventionPolicy
+		wantErr bool
+	}{
+		{"default policy", DefaultInterventionPolicy(), false},
+		{"valid rules", InterventionPolicy{Rules: []PolicyRule{
+			{AnomalyType: AnomalyStuckState, Severity: SeverityHigh, Action: PolicyCancelAgent},
+			{AnomalyType: AnomalyStuckState, Action: PolicyComment},
+		}}, false},
+		{"invalid default action", InterventionPolicy{DefaultAction: "explode"}, true},
+		{"invalid rule action", InterventionPolicy{Rules: []PolicyRule{{AnomalyType: AnomalyOther, Action: "explode"}}}, true},
+		{"missing anomaly type", InterventionPolicy{Rules: []PolicyRule{{Action: PolicyLogOnly}}}, true},
+		{"invalid severity", InterventionPolicy{Rules: []PolicyRule{{AnomalyType: AnomalyOther, Severity: "extreme", Action: PolicyLogOnly}}}, true},
+		{"duplicate rule", InterventionPolicy{Rules: []PolicyRule{
+			{AnomalyType: AnomalyOther, Action: PolicyLogOnly},
+			{AnomalyType: AnomalyOther, Action: PolicyComment},
+		}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestInterventionPolicy_DisablesProtection(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy InterventionPolicy
+		want   bool
+	}{
+		{"default policy", DefaultInterventionPolicy(), false},
+		{"log_only default", InterventionPolicy{DefaultAction: PolicyLogOnly}, true},
+		{"wildcard log_only", InterventionPolicy{Rules: []PolicyRule{{AnomalyType: AnyAnomalyType, Action: PolicyLogOnly}}}, true},
+		{"log_only except critical loops", InterventionPolicy{
+			Rules:         []PolicyRule{{AnomalyType: AnomalyInfiniteLoop, Severity: SeverityCritical, Action: PolicyCancelAgent}},
+			DefaultAction: PolicyLogOnly,
+		}, false},
+		{"log_only only for low severity", InterventionPolicy{
+			Rules:         []PolicyRule{{AnomalyType: AnyAnomalyType, Severity: SeverityLow, Action: PolicyLogOnly}},
+			DefaultAction: PolicyCancelAgent,
+		}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.DisablesProtection(); got != tt.want {
+				t.Errorf("DisablesProtection() = %v, want %v", got, tt.want)
+			}
+			if hasWarning := tt.policy.SafetyWarning() != ""; hasWarning != tt.want {
+				t.Errorf("SafetyWarning() present = %v, want %v", hasWarning, tt.want)
+			}
+		})
+	}
+}
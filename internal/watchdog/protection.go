@@ -0,0 +1,48 @@
+package watchdog
+
+// ProtectionMode describes how much watchdog protection applies to an execution.
+// Issues opt out via labels because some legitimately look anomalous, e.g.
+// huge mechanical refactors that produce hours of repetitive output.
+type ProtectionMode string
+
+const (
+	// ProtectionNormal applies the standard thresholds
+	ProtectionNormal ProtectionMode = "normal"
+	// ProtectionRelaxed applies the multiplied thresholds from RelaxedModeConfig
+	ProtectionRelaxed ProtectionMode = "relaxed"
+	// ProtectionOff skips AI anomaly checks for the execution entirely
+	ProtectionOff ProtectionMode = "off"
+)
+
+// Labels that reduce watchdog protection for an issue
+const (
+	LabelWatchdogOff     = "watchdog:off"
+	LabelWatchdogRelaxed = "watchdog:relaxed"
+)
+
+// ProtectionModeFromLabels determines the protection mode for an issue's labels.
+// watchdog:off wins over watchdog:relaxed when both are present.
+func ProtectionModeFromLabels(labels []string) ProtectionMode {
+	mode := ProtectionNormal
+	for _, label := range labels {
+		switch label {
+		case LabelWatchdogOff:
+			return ProtectionOff
+		case LabelWatchdogRelaxed:
+			mode = ProtectionRelaxed
+		}
+	}
+	return mode
+}
+
+// ProtectionLabel returns the label that selects mode ("" for normal)
+func ProtectionLabel(mode ProtectionMode) string {
+	switch mode {
+	case ProtectionOff:
+		return LabelWatchdogOff
+	case ProtectionRelaxed:
+		return LabelWatchdogRelaxed
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,114 @@
+package watchdog
+
+import "testing"
+
+func TestProtectionModeFromLabels(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels []string
+		want   ProtectionMode
+	}{
+		{"no labels", nil, ProtectionNormal},
+		{"unrelated labels", []string{"task-ready", "refactor"}, ProtectionNormal},
+		{"off", []string{LabelWatchdogOff}, ProtectionOff},
+		{"relaxed", []string{"refactor", LabelWatchdogRelaxed}, ProtectionRelaxed},
+		{"off wins over relaxed", []string{LabelWatchdogRelaxed, LabelWatchdogOff}, ProtectionOff},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ProtectionModeFromLabels(tt.labels); got != tt.want {
+				t.Errorf("ProtectionModeFromLabels(%v) = %s, want %s", tt.labels, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShouldInterveneForMode(t *testing.T) {
+	highReport := func() *AnomalyReport {
+		return &AnomalyReport{
+			Detected:    true,
+			AnomalyType: AnomalyInfiniteLoop,
+			Severity:    SeverityHigh,
+			Confidence:  0.9,
+		}
+	}
+
+	cfg := DefaultWatchdogConfig()
+	if !cfg.ShouldInterveneForMode(highReport(), ProtectionNormal) {
+		t.Error("Expected intervention for high severity in normal mode")
+	}
+	if cfg.ShouldInterveneForMode(highReport(), ProtectionOff) {
+		t.Error("Expected no intervention when protection is off")
+	}
+	if cfg.ShouldInterveneForMode(highReport(), ProtectionRelaxed) {
+		t.Error("Expected relaxed mode to require critical severity")
+	}
+
+	critical := highReport()
+	critical.Severity = SeverityCritical
+	if !cfg.ShouldInterveneForMode(critical, ProtectionRelaxed) {
+		t.Error("Expected intervention for critical severity in relaxed mode")
+	}
+}
+
+func TestShouldInterveneForMode_RelaxedStuckStateMultiplier(t *testing.T) {
+	stuck := &AnomalyReport{
+		Detected:    true,
+		AnomalyType: AnomalyStuckState,
+		Severity:    SeverityLow,
+		Confidence:  0.5,
+	}
+
+	cfg := DefaultWatchdogConfig()
+	cfg.RelaxedMode.ThresholdMultiplier = 2.0
+
+	// Normal mode intervenes on the 10th consecutive detection; relaxed needs 20
+	for i := 1; i < 20; i++ {
+		if cfg.ShouldInterveneForMode(stuck, ProtectionRelaxed) {
+			t.Fatalf("Relaxed mode intervened after only %d detections", i)
+		}
+	}
+	if !cfg.ShouldInterveneForMode(stuck, ProtectionRelaxed) {
+		t.Error("Expected relaxed mode to intervene after 20 consecutive detections")
+	}
+}
+
+func TestValidate_RelaxedMode(t *testing.T) {
+	cfg := DefaultWatchdogConfig()
+	cfg.RelaxedMode = RelaxedModeConfig{}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Expected zero relaxed mode to fall back to defaults, got %v", err)
+	}
+	if cfg.RelaxedMode.ThresholdMultiplier != 3.0 || cfg.RelaxedMode.MinSeverityLevel != SeverityCritical {
+		t.Errorf("Unexpected relaxed defaults: %+v", cfg.RelaxedMode)
+	}
+
+	cfg.RelaxedMode.ThresholdMultiplier = 0.5
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for multiplier below 1.0")
+	}
+}
+
+func TestMonitor_ProtectionMode(t *testing.T) {
+	m := NewMonitor(nil)
+	if m.CurrentProtectionMode() != ProtectionNormal {
+		t.Error("Expected normal protection with no execution")
+	}
+
+	m.StartExecution("vc-1", "exec-1")
+	m.SetProtectionMode(ProtectionRelaxed)
+	if m.CurrentProtectionMode() != ProtectionRelaxed {
+		t.Errorf("Expected relaxed protection, got %s", m.CurrentProtectionMode())
+	}
+	m.EndExecution(true, true)
+
+	// Recorded in the execution's telemetry for post-mortems
+	telemetry := m.GetExecutionsByIssue("vc-1")
+	if len(telemetry) != 1 || telemetry[0].ProtectionMode != ProtectionRelaxed {
+		t.Error("Expected protection mode to be recorded in telemetry")
+	}
+	if m.CurrentProtectionMode() != ProtectionNormal {
+		t.Error("Expected normal protection after execution ended")
+	}
+}
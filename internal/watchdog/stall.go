@@ -0,0 +1,71 @@
+package watchdog
+
+import (
+	"fmt"
+	"time"
+)
+
+// SilenceThresholdFor returns the stall detection threshold for an issue.
+// Issues with an estimate get proportionally more quiet time, never less than
+// the configured SilenceThreshold.
+func (c *WatchdogConfig) SilenceThresholdFor(estimatedMinutes *int) time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	threshold := c.StallDetection.SilenceThreshold
+	if estimatedMinutes != nil && *estimatedMinutes > 0 {
+		fromEstimate := time.Duration(float64(*estimatedMinutes)*c.StallDetection.EstimateSilenceRatio) * time.Minute
+		if fromEstimate > threshold {
+			threshold = fromEstimate
+		}
+	}
+	return threshold
+}
+
+// CheckStall compares the current execution's last activity against its silence
+// threshold and returns a "stalled" anomaly report if it was exceeded, or nil.
+// This is a cheap heuristic that needs no AI call: a hung agent process is the
+// most common failure and simply produces no output until the execution timeout.
+func (c *WatchdogConfig) CheckStall(current *ExecutionTelemetry, now time.Time) *AnomalyReport {
+	if current == nil || current.LastActivity.IsZero() {
+		return nil
+	}
+
+	c.mu.RLock()
+	enabled := c.Enabled && c.StallDetection.Enabled
+	threshold := c.StallDetection.SilenceThreshold
+	multiplier := c.RelaxedMode.ThresholdMultiplier
+	c.mu.RUnlock()
+
+	if !enabled || current.ProtectionMode == ProtectionOff {
+		return nil
+	}
+	if current.SilenceThreshold > 0 {
+		threshold = current.SilenceThreshold
+	}
+	if current.ProtectionMode == ProtectionRelaxed && multiplier > 1.0 {
+		threshold = time.Duration(float64(threshold) * multiplier)
+	}
+
+	silence := now.Sub(current.LastActivity)
+	if silence < threshold {
+		return nil
+	}
+
+	return &AnomalyReport{
+		Detected:          true,
+		AnomalyType:       AnomalyStalled,
+		Severity:          SeverityHigh,
+		Description:       fmt.Sprintf("Agent for %s produced no output for %v", current.IssueID, silence.Round(time.Second)),
+		RecommendedAction: ActionStopExecution,
+		Reasoning: fmt.Sprintf("No agent events since %s, exceeding the silence threshold of %v. "+
+			"The agent process appears hung.", current.LastActivity.Format(time.RFC3339), threshold),
+		Confidence:     1.0,
+		AffectedIssues: []string{current.IssueID},
+		Metrics: map[string]interface{}{
+			"silent_seconds":    int64(silence.Seconds()),
+			"threshold_seconds": int64(threshold.Seconds()),
+			"last_activity":     current.LastActivity.Format(time.RFC3339),
+		},
+	}
+}
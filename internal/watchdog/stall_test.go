@@ -0,0 +1,114 @@
+package watchdog
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSilenceThresholdFor(t *testing.T) {
+	cfg := DefaultWatchdogConfig()
+	intPtr := func(v int) *int { return &v }
+
+	tests := []struct {
+		name     string
+		estimate *int
+		want     time.Duration
+	}{
+		{"no estimate", nil, 10 * time.Minute},
+		{"zero estimate", intPtr(0), 10 * time.Minute},
+		{"small estimate keeps base", intPtr(30), 10 * time.Minute},
+		{"large estimate raises threshold", intPtr(240), 60 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cfg.SilenceThresholdFor(tt.estimate); got != tt.want {
+				t.Errorf("SilenceThresholdFor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckStall(t *testing.T) {
+	now := time.Now()
+	execution := func(silent time.Duration) *ExecutionTelemetry {
+		return &ExecutionTelemetry{
+			IssueID:        "vc-1",
+			ProtectionMode: ProtectionNormal,
+			LastActivity:   now.Add(-silent),
+		}
+	}
+
+	cfg := DefaultWatchdogConfig()
+
+	if report := cfg.CheckStall(nil, now); report != nil {
+		t.Error("Expected no report without a current execution")
+	}
+	if report := cfg.CheckStall(execution(5*time.Minute), now); report != nil {
+		t.Error("Expected no report below the silence threshold")
+	}
+
+	report := cfg.CheckStall(execution(12*time.Minute), now)
+	if report == nil {
+		t.Fatal("Expected stall report past the silence threshold")
+	}
+	if report.AnomalyType != AnomalyStalled {
+		t.Errorf("AnomalyType = %s, want %s", report.AnomalyType, AnomalyStalled)
+	}
+	if report.RecommendedAction != ActionStopExecution {
+		t.Errorf("RecommendedAction = %s, want %s", report.RecommendedAction, ActionStopExecution)
+	}
+	if got := report.Metrics["silent_seconds"]; got != int64(720) {
+		t.Errorf("silent_seconds = %v, want 720", got)
+	}
+
+	// Per-execution threshold from the issue estimate
+	long := execution(12 * time.Minute)
+	long.SilenceThreshold = 30 * time.Minute
+	if report := cfg.CheckStall(long, now); report != nil {
+		t.Error("Expected per-execution threshold to override the default")
+	}
+
+	// Relaxed mode multiplies the threshold (3x default)
+	relaxed := execution(20 * time.Minute)
+	relaxed.ProtectionMode = ProtectionRelaxed
+	if report := cfg.CheckStall(relaxed, now); report != nil {
+		t.Error("Expected relaxed mode to tolerate 20m of silence")
+	}
+	relaxed.LastActivity = now.Add(-31 * time.Minute)
+	if report := cfg.CheckStall(relaxed, now); report == nil {
+		t.Error("Expected stall report past the relaxed threshold")
+	}
+
+	off := execution(time.Hour)
+	off.ProtectionMode = ProtectionOff
+	if report := cfg.CheckStall(off, now); report != nil {
+		t.Error("Expected no report when watchdog protection is off")
+	}
+
+	cfg.StallDetection.Enabled = false
+	if report := cfg.CheckStall(execution(time.Hour), now); report != nil {
+		t.Error("Expected no report when stall detection is disabled")
+	}
+}
+
+func TestMonitorTracksLastActivity(t *testing.T) {
+	m := NewMonitor(DefaultConfig())
+	m.StartExecution("vc-1", "exec-1")
+
+	started := m.GetCurrentExecution().LastActivity
+	if started.IsZero() {
+		t.Fatal("Expected LastActivity to be set at start")
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	m.RecordEvent("agent_tool_use")
+	if !m.GetCurrentExecution().LastActivity.After(started) {
+		t.Error("Expected RecordEvent to advance LastActivity")
+	}
+
+	m.SetSilenceThreshold(45 * time.Minute)
+	if got := m.GetCurrentExecution().SilenceThreshold; got != 45*time.Minute {
+		t.Errorf("SilenceThreshold = %v, want 45m", got)
+	}
+}
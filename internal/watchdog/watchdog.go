@@ -3,10 +3,12 @@ package watchdog
 import (
 	"context"
 	"fmt"
+	"os"
 	"sync"
 	"time"
 
 	"github.com/steveyegge/vc/internal/ai"
+	"github.com/steveyegge/vc/internal/events"
 	"github.com/steveyegge/vc/internal/storage"
 )
 
@@ -63,12 +65,14 @@ func NewWatchdog(deps *WatchdogDeps) (*Watchdog, error) {
 	monitor := NewMonitor(&Config{
 		WindowSize: config.TelemetryWindowSize,
 	})
+	monitor.SetLoopDetection(config.LoopDetection)
 
 	// Create analyzer
 	analyzer, err := NewAnalyzer(&AnalyzerConfig{
 		Monitor:    monitor,
 		Supervisor: deps.Supervisor,
 		Store:      deps.Store,
+		Config:     config,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create analyzer: %w", err)
@@ -82,10 +86,14 @@ func NewWatchdog(deps *WatchdogDeps) (*Watchdog, error) {
 		Store:              deps.Store,
 		ExecutorInstanceID: deps.ExecutorInstanceID,
 		MaxHistorySize:     config.MaxHistorySize,
+		Config:             config,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create intervention controller: %w", err)
 	}
+	if warning := config.PolicySafetyWarning(); warning != "" {
+		fmt.Fprintln(os.Stderr, warning)
+	}
 
 	return &Watchdog{
 		monitor:                monitor,
@@ -157,6 +165,15 @@ func (w *Watchdog) monitoringLoop() {
 				fmt.Printf("Watchdog: context exhaustion check failed: %v\n", err)
 			}
 
+			// Cheap heuristics; a stalled or looping agent skips AI analysis this tick
+			detected, err := w.checkHeuristicAnomalies()
+			if err != nil {
+				fmt.Printf("Watchdog: heuristic check failed: %v\n", err)
+			}
+			if detected {
+				continue
+			}
+
 			// Run general anomaly detection
 			if err := w.checkAnomalies(); err != nil {
 				fmt.Printf("Watchdog: anomaly detection failed: %v\n", err)
@@ -218,6 +235,34 @@ func (w *Watchdog) checkContextExhaustion() error {
 	return nil
 }
 
+// checkHeuristicAnomalies cancels the agent if it has been silent past the
+// silence threshold or keeps repeating its output. Returns true if detected.
+func (w *Watchdog) checkHeuristicAnomalies() (bool, error) {
+	if !w.interventionController.HasActiveAgent() {
+		return false, nil
+	}
+
+	current := w.monitor.GetCurrentExecution()
+	report := w.config.CheckStall(current, time.Now())
+	if report != nil {
+		// Reset the silence clock so the stall isn't re-reported while cancellation propagates
+		w.monitor.RecordEvent(string(events.EventTypeWatchdog))
+	} else if report = w.config.CheckLoop(current, w.monitor.LoopStatus()); report != nil {
+		w.monitor.ResetLoopDetection()
+	}
+	if report == nil {
+		return false, nil
+	}
+
+	result, err := w.interventionController.Intervene(w.ctx, report)
+	if err != nil {
+		return true, fmt.Errorf("intervention failed: %w", err)
+	}
+
+	fmt.Printf("Watchdog: %s intervention completed: %s\n", report.AnomalyType, result.Message)
+	return true, nil
+}
+
 // checkAnomalies runs general anomaly detection on telemetry
 func (w *Watchdog) checkAnomalies() error {
 	// Run AI-driven anomaly detection
@@ -231,8 +276,8 @@ func (w *Watchdog) checkAnomalies() error {
 		return nil
 	}
 
-	// Check if we should intervene based on config
-	if !w.config.ShouldIntervene(report) {
+	// Check if we should intervene based on config and the issue's protection mode
+	if !w.config.ShouldInterveneForMode(report, w.monitor.CurrentProtectionMode()) {
 		// Log but don't intervene
 		if w.config.AIConfig.EnableAnomalyLogging {
 			fmt.Printf("Watchdog: Anomaly detected (%s) but below intervention threshold\n", report.AnomalyType)
@@ -1,31 +1,33 @@
 #!/bin/bash
 # find-storage-mocks.sh
-# Finds all mockStorage implementations in test files
+# Finds all storage.Storage implementations other than the SQLite backend
 # Usage: ./scripts/find-storage-mocks.sh
 
 set -euo pipefail
 
-echo "Finding all mockStorage implementations in test files..."
+echo "Finding all storage.Storage test doubles..."
 echo ""
 
-# Find all test files with mockStorage struct definitions
-mock_files=$(find . -name "*_test.go" -type f -exec grep -l "type.*mockStorage.*struct" {} \; | sort)
+# A full implementation defines every interface method; ReleaseIssueAndReopen
+# is only ever implemented by Storage types, so it identifies them
+impl_files=$(grep -rl --include="*.go" ") ReleaseIssueAndReopen(" . | grep -v "^./internal/storage/beads/" | sort || true)
 
-if [ -z "$mock_files" ]; then
-    echo "No mockStorage implementations found."
+if [ -z "$impl_files" ]; then
+    echo "No storage.Storage test doubles found."
     exit 0
 fi
 
-echo "Found mockStorage implementations in the following files:"
+echo "Found storage.Storage implementations in the following files:"
 echo ""
-for file in $mock_files; do
+for file in $impl_files; do
     echo "  $file"
 done
 
 echo ""
-echo "Total: $(echo "$mock_files" | wc -l | tr -d ' ') files"
+echo "Total: $(echo "$impl_files" | wc -l | tr -d ' ') files"
 echo ""
-echo "When updating the storage.Storage interface, ALL of these files must be updated."
-echo "Use the following command to check if they compile:"
+echo "When updating the storage.Storage interface, update FakeStorage in"
+echo "internal/storage/storagetest and cover the new method in the conformance suite."
+echo "Tests should use storagetest.NewFakeStorage() instead of adding new mocks."
 echo ""
-echo "  go test -c \$(echo "$mock_files" | tr '\n' ' ')"
+echo "  go test ./internal/storage/..."